@@ -183,6 +183,30 @@ func (e *executor) BeaconSources(ctx context.Context) ([]addr.IA, error) {
 	return ias, nil
 }
 
+func (e *executor) BeaconCounts(ctx context.Context) (map[addr.IA]int, error) {
+	e.RLock()
+	defer e.RUnlock()
+	query := `SELECT StartIsd, StartAs, COUNT(*) FROM Beacons GROUP BY StartIsd, StartAs`
+	rows, err := e.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, db.NewReadError("Error counting beacons", err)
+	}
+	defer rows.Close()
+	counts := make(map[addr.IA]int)
+	for rows.Next() {
+		var ia addr.IA
+		var count int
+		if err := rows.Scan(&ia.I, &ia.A, &count); err != nil {
+			return nil, db.NewReadError(beacon.ErrReadingRows, err)
+		}
+		counts[ia] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, db.NewReadError(beacon.ErrReadingRows, err)
+	}
+	return counts, nil
+}
+
 func (e *executor) CandidateBeacons(ctx context.Context, setSize int, usage beacon.Usage,
 	src addr.IA) (<-chan beacon.BeaconOrErr, error) {
 
@@ -463,6 +487,36 @@ func (e *executor) DeleteRevokedBeacons(ctx context.Context, now time.Time) (int
 	})
 }
 
+// EvictBeacons deletes beacons until at most maxBeacons beacons are left,
+// choosing the beacons to delete according to the strategy.
+func (e *executor) EvictBeacons(ctx context.Context, maxBeacons int,
+	strategy beacon.EvictionStrategy) (int, error) {
+
+	var order string
+	switch strategy {
+	case beacon.EvictOldest:
+		order = "LastUpdated ASC"
+	case beacon.EvictLongest:
+		order = "HopsLength DESC, LastUpdated ASC"
+	default:
+		return 0, db.NewInputDataError("eviction strategy", strategy.Validate())
+	}
+	return e.deleteInTx(ctx, func(tx *sql.Tx) (sql.Result, error) {
+		var count int
+		err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM Beacons`).Scan(&count)
+		if err != nil {
+			return nil, err
+		}
+		delStmt := fmt.Sprintf(`
+		DELETE FROM Beacons
+		WHERE RowID IN (
+			SELECT RowID FROM Beacons ORDER BY %s LIMIT ?
+		)
+		`, order)
+		return tx.ExecContext(ctx, delStmt, max(0, count-maxBeacons))
+	})
+}
+
 func (e *executor) InsertRevocation(ctx context.Context,
 	revocation *path_mgmt.SignedRevInfo) error {
 
@@ -530,3 +584,10 @@ func (e *executor) DeleteExpiredRevocations(ctx context.Context, now time.Time)
 		return tx.ExecContext(ctx, query, now.Unix())
 	})
 }
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -115,6 +115,8 @@ func Test(t *testing.T, db Testable) {
 	}
 	t.Run("BeaconSources should report all sources",
 		testWrapper(testBeaconSources))
+	t.Run("BeaconCounts should report the beacons per source",
+		testWrapper(testBeaconCounts))
 	t.Run("InsertBeacon should correctly insert a new beacon",
 		testWrapper(testInsertBeacon))
 	t.Run("InsertBeacon should correctly update a new beacon",
@@ -125,6 +127,8 @@ func Test(t *testing.T, db Testable) {
 		tableWrapper(false, testCandidateBeacons))
 	t.Run("DeleteExpired should delete expired segments",
 		testWrapper(testDeleteExpiredBeacons))
+	t.Run("EvictBeacons should delete beacons according to the strategy",
+		testWrapper(testEvictBeacons))
 	t.Run("DeleteRevokedBeacons",
 		tableWrapper(false, testDeleteRevokedBeacons))
 	t.Run("AllRevocations",
@@ -154,6 +158,8 @@ func Test(t *testing.T, db Testable) {
 	t.Run("WithTransaction", func(t *testing.T) {
 		t.Run("BeaconSources should report all sources",
 			txTestWrapper(testBeaconSources))
+		t.Run("BeaconCounts should report the beacons per source",
+			txTestWrapper(testBeaconCounts))
 		t.Run("InsertBeacon should correctly insert a new beacon",
 			txTestWrapper(testInsertBeacon))
 		t.Run("InsertBeacon should correctly update a new beacon",
@@ -164,6 +170,8 @@ func Test(t *testing.T, db Testable) {
 			tableWrapper(true, testCandidateBeacons))
 		t.Run("DeleteExpired should delete expired segments",
 			txTestWrapper(testDeleteExpiredBeacons))
+		t.Run("EvictBeacons should delete beacons according to the strategy",
+			txTestWrapper(testEvictBeacons))
 		t.Run("DeleteRevokedBeacons",
 			tableWrapper(true, testDeleteRevokedBeacons))
 		t.Run("AllRevocations",
@@ -196,6 +204,17 @@ func testBeaconSources(t *testing.T, ctrl *gomock.Controller, db beacon.DBReadWr
 	assert.ElementsMatch(t, []addr.IA{ia311, ia330}, ias)
 }
 
+func testBeaconCounts(t *testing.T, ctrl *gomock.Controller, db beacon.DBReadWrite) {
+	for i, info := range [][]IfInfo{Info3, Info2, Info1} {
+		InsertBeacon(t, ctrl, db, info, 12, uint32(i), beacon.UsageProp)
+	}
+	ctx, cancelF := context.WithTimeout(context.Background(), timeout)
+	defer cancelF()
+	counts, err := db.BeaconCounts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[addr.IA]int{ia311: 1, ia330: 2}, counts)
+}
+
 func testInsertBeacon(t *testing.T, ctrl *gomock.Controller, db beacon.DBReadWrite) {
 	TS := uint32(10)
 	b, _ := AllocBeacon(t, ctrl, Info3, 12, TS)
@@ -365,6 +384,35 @@ func testDeleteExpiredBeacons(t *testing.T, ctrl *gomock.Controller, db beacon.D
 	assert.Equal(t, 1, deleted, "Deleted")
 }
 
+func testEvictBeacons(t *testing.T, ctrl *gomock.Controller, db beacon.DBReadWrite) {
+	ctx, cancelF := context.WithTimeout(context.Background(), timeout)
+	defer cancelF()
+	InsertBeacon(t, ctrl, db, Info3, 12, 10, beacon.UsageProp)
+	b2 := InsertBeacon(t, ctrl, db, Info2, 13, 10, beacon.UsageProp)
+	b1 := InsertBeacon(t, ctrl, db, Info1, 14, 10, beacon.UsageProp)
+	// Below the limit nothing is evicted.
+	deleted, err := db.EvictBeacons(ctx, 3, beacon.EvictOldest)
+	require.NoError(t, err)
+	assert.Equal(t, 0, deleted, "Deleted")
+	// The longest beacon is evicted first.
+	deleted, err = db.EvictBeacons(ctx, 2, beacon.EvictLongest)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted, "Deleted")
+	results, err := db.CandidateBeacons(ctx, 10, beacon.UsageProp, addr.IA{})
+	require.NoError(t, err)
+	CheckResults(t, results, []beacon.Beacon{b1, b2})
+	// The least recently updated beacon is evicted first.
+	deleted, err = db.EvictBeacons(ctx, 1, beacon.EvictOldest)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted, "Deleted")
+	results, err = db.CandidateBeacons(ctx, 10, beacon.UsageProp, addr.IA{})
+	require.NoError(t, err)
+	CheckResults(t, results, []beacon.Beacon{b1})
+	// Unknown strategies are rejected.
+	_, err = db.EvictBeacons(ctx, 0, beacon.EvictionStrategy("random"))
+	assert.Error(t, err)
+}
+
 func testDeleteRevokedBeacons(t *testing.T, db Testable, inTx bool) {
 	rootCtrl := gomock.NewController(t)
 	defer rootCtrl.Finish()
@@ -47,6 +47,9 @@ type DBRead interface {
 		<-chan BeaconOrErr, error)
 	// BeaconSources returns all source ISD-AS of the beacons in the database.
 	BeaconSources(ctx context.Context) ([]addr.IA, error)
+	// BeaconCounts returns the number of beacons in the database per source
+	// ISD-AS.
+	BeaconCounts(ctx context.Context) (map[addr.IA]int, error)
 	// AllRevocations returns all revocations in the database as a channel. The
 	// result channel either carries revocations or errors. The error can
 	// either be ErrReadingRows or ErrParse. After a ErrReadingRows occurs the
@@ -66,6 +69,10 @@ type DBWrite interface {
 	InsertBeacon(ctx context.Context, beacon Beacon, usage Usage) (InsertStats, error)
 	DeleteExpiredBeacons(ctx context.Context, now time.Time) (int, error)
 	DeleteRevokedBeacons(ctx context.Context, now time.Time) (int, error)
+	// EvictBeacons deletes beacons until at most maxBeacons beacons are left
+	// in the database. The strategy determines which beacons are deleted
+	// first. It returns the number of deleted beacons.
+	EvictBeacons(ctx context.Context, maxBeacons int, strategy EvictionStrategy) (int, error)
 	InsertRevocation(ctx context.Context, revocation *path_mgmt.SignedRevInfo) error
 	DeleteRevocation(ctx context.Context, ia addr.IA, ifid common.IFIDType) error
 	DeleteExpiredRevocations(ctx context.Context, now time.Time) (int, error)
@@ -92,6 +99,28 @@ type DB interface {
 	io.Closer
 }
 
+const (
+	// EvictOldest evicts the least recently updated beacons first.
+	EvictOldest EvictionStrategy = "oldest"
+	// EvictLongest evicts the beacons with the most hops first. Among beacons
+	// of the same length, the least recently updated are evicted first.
+	EvictLongest EvictionStrategy = "longest"
+)
+
+// EvictionStrategy determines which beacons are evicted first, if the beacon
+// store exceeds its size limit.
+type EvictionStrategy string
+
+// Validate checks that the eviction strategy is known.
+func (s EvictionStrategy) Validate() error {
+	switch s {
+	case EvictOldest, EvictLongest:
+		return nil
+	default:
+		return common.NewBasicError("Unknown eviction strategy", nil, "strategy", s)
+	}
+}
+
 const (
 	// UsageUpReg indicates the beacon is allowed to be registered as an up segment.
 	UsageUpReg Usage = 0x01
@@ -183,6 +183,16 @@ func (e *executor) BeaconSources(ctx context.Context) ([]addr.IA, error) {
 	return ret, err
 }
 
+func (e *executor) BeaconCounts(ctx context.Context) (map[addr.IA]int, error) {
+	var ret map[addr.IA]int
+	var err error
+	e.metrics.Observe(ctx, "beacon_counts", func(ctx context.Context) error {
+		ret, err = e.db.BeaconCounts(ctx)
+		return err
+	})
+	return ret, err
+}
+
 func (e *executor) AllRevocations(ctx context.Context) (<-chan RevocationOrErr, error) {
 	var ret <-chan RevocationOrErr
 	var err error
@@ -224,6 +234,18 @@ func (e *executor) DeleteRevokedBeacons(ctx context.Context, now time.Time) (int
 	return ret, err
 }
 
+func (e *executor) EvictBeacons(ctx context.Context, maxBeacons int,
+	strategy EvictionStrategy) (int, error) {
+
+	var ret int
+	var err error
+	e.metrics.Observe(ctx, "evict_beacons", func(ctx context.Context) error {
+		ret, err = e.db.EvictBeacons(ctx, maxBeacons, strategy)
+		return err
+	})
+	return ret, err
+}
+
 func (e *executor) InsertRevocation(ctx context.Context,
 	revocation *path_mgmt.SignedRevInfo) error {
 
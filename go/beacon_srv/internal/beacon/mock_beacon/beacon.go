@@ -54,6 +54,21 @@ func (mr *MockDBMockRecorder) AllRevocations(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllRevocations", reflect.TypeOf((*MockDB)(nil).AllRevocations), arg0)
 }
 
+// BeaconCounts mocks base method
+func (m *MockDB) BeaconCounts(arg0 context.Context) (map[addr.IA]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeaconCounts", arg0)
+	ret0, _ := ret[0].(map[addr.IA]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeaconCounts indicates an expected call of BeaconCounts
+func (mr *MockDBMockRecorder) BeaconCounts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeaconCounts", reflect.TypeOf((*MockDB)(nil).BeaconCounts), arg0)
+}
+
 // BeaconSources mocks base method
 func (m *MockDB) BeaconSources(arg0 context.Context) ([]addr.IA, error) {
 	m.ctrl.T.Helper()
@@ -172,6 +187,21 @@ func (mr *MockDBMockRecorder) DeleteRevokedBeacons(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRevokedBeacons", reflect.TypeOf((*MockDB)(nil).DeleteRevokedBeacons), arg0, arg1)
 }
 
+// EvictBeacons mocks base method
+func (m *MockDB) EvictBeacons(arg0 context.Context, arg1 int, arg2 beacon.EvictionStrategy) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvictBeacons", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EvictBeacons indicates an expected call of EvictBeacons
+func (mr *MockDBMockRecorder) EvictBeacons(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvictBeacons", reflect.TypeOf((*MockDB)(nil).EvictBeacons), arg0, arg1, arg2)
+}
+
 // InsertBeacon mocks base method
 func (m *MockDB) InsertBeacon(arg0 context.Context, arg1 beacon.Beacon, arg2 beacon.Usage) (beacon.InsertStats, error) {
 	m.ctrl.T.Helper()
@@ -263,6 +293,21 @@ func (mr *MockTransactionMockRecorder) AllRevocations(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllRevocations", reflect.TypeOf((*MockTransaction)(nil).AllRevocations), arg0)
 }
 
+// BeaconCounts mocks base method
+func (m *MockTransaction) BeaconCounts(arg0 context.Context) (map[addr.IA]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeaconCounts", arg0)
+	ret0, _ := ret[0].(map[addr.IA]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeaconCounts indicates an expected call of BeaconCounts
+func (mr *MockTransactionMockRecorder) BeaconCounts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeaconCounts", reflect.TypeOf((*MockTransaction)(nil).BeaconCounts), arg0)
+}
+
 // BeaconSources mocks base method
 func (m *MockTransaction) BeaconSources(arg0 context.Context) ([]addr.IA, error) {
 	m.ctrl.T.Helper()
@@ -366,6 +411,21 @@ func (mr *MockTransactionMockRecorder) DeleteRevokedBeacons(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRevokedBeacons", reflect.TypeOf((*MockTransaction)(nil).DeleteRevokedBeacons), arg0, arg1)
 }
 
+// EvictBeacons mocks base method
+func (m *MockTransaction) EvictBeacons(arg0 context.Context, arg1 int, arg2 beacon.EvictionStrategy) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvictBeacons", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EvictBeacons indicates an expected call of EvictBeacons
+func (mr *MockTransactionMockRecorder) EvictBeacons(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvictBeacons", reflect.TypeOf((*MockTransaction)(nil).EvictBeacons), arg0, arg1, arg2)
+}
+
 // InsertBeacon mocks base method
 func (m *MockTransaction) InsertBeacon(arg0 context.Context, arg1 beacon.Beacon, arg2 beacon.Usage) (beacon.InsertStats, error) {
 	m.ctrl.T.Helper()
@@ -188,6 +188,11 @@ type Policy struct {
 	Filter Filter `yaml:"Filter"`
 	// Type is the policy type.
 	Type PolicyType `yaml:"Type"`
+	// Selection is the algorithm used to select the best beacons from the
+	// candidate beacons.
+	Selection SelectionType `yaml:"Selection"`
+	// Weights are the weights used by the weighted selection algorithm.
+	Weights SelectionWeights `yaml:"Weights"`
 }
 
 // InitDefaults initializes the default values for unset fields.
@@ -202,6 +207,9 @@ func (p *Policy) InitDefaults() {
 		m := DefaultMaxExpTime
 		p.MaxExpTime = &m
 	}
+	if p.Selection == "" {
+		p.Selection = DefaultSelection
+	}
 	p.Filter.InitDefaults()
 }
 
@@ -212,9 +220,27 @@ func (p *Policy) initDefaults(t PolicyType) error {
 			"expected", t, "actual", p.Type)
 	}
 	p.Type = t
+	if _, err := p.selectionAlgorithm(); err != nil {
+		return err
+	}
 	return nil
 }
 
+// selectionAlgorithm returns the selection algorithm configured in the policy.
+func (p *Policy) selectionAlgorithm() (SelectionAlgorithm, error) {
+	switch p.Selection {
+	case DiversitySelection:
+		return baseAlgo{}, nil
+	case ShortestSelection:
+		return shortestAlgo{}, nil
+	case WeightedSelection:
+		return weightedAlgo{weights: p.Weights}, nil
+	default:
+		return nil, common.NewBasicError("Unknown selection algorithm", nil,
+			"policy", p.Type, "selection", p.Selection)
+	}
+}
+
 // ParsePolicyYaml parses the policy in yaml format and initializes the default values.
 func ParsePolicyYaml(b common.RawBytes, t PolicyType) (*Policy, error) {
 	p := &Policy{}
@@ -237,6 +263,53 @@ func LoadPolicyFromYaml(path string, t PolicyType) (*Policy, error) {
 	return ParsePolicyYaml(b, t)
 }
 
+// SelectionType is the type of the beacon selection algorithm.
+type SelectionType string
+
+const (
+	// DiversitySelection selects the shortest beacons and replaces the last
+	// one with the most diverse beacon, if it increases the path diversity.
+	DiversitySelection SelectionType = "Diversity"
+	// ShortestSelection selects the shortest beacons.
+	ShortestSelection SelectionType = "Shortest"
+	// WeightedSelection selects the beacons with the lowest score according to
+	// the configured selection weights.
+	WeightedSelection SelectionType = "Weighted"
+	// DefaultSelection is the default Selection value.
+	DefaultSelection = DiversitySelection
+)
+
+// SelectionWeights are the weights for the weighted selection algorithm. The
+// score of a beacon is the weighted sum of its properties. Beacons with a lower
+// score are preferred. Properties with zero weight are ignored.
+type SelectionWeights struct {
+	// Hops is the cost of each AS entry in the beacon.
+	Hops float64 `yaml:"Hops"`
+	// Diversity is the reward for each link that is not shared with the
+	// shortest candidate beacon.
+	Diversity float64 `yaml:"Diversity"`
+	// ASes is the cost of each appearance of the AS in the beacon.
+	ASes map[addr.AS]float64 `yaml:"ASes"`
+	// ISDs is the cost of each AS entry in the beacon that is part of the ISD.
+	ISDs map[addr.ISD]float64 `yaml:"ISDs"`
+}
+
+// score computes the score of the beacon. The diversity is computed relative
+// to the best beacon.
+func (w SelectionWeights) score(best, beacon Beacon) float64 {
+	score := w.Hops * float64(len(beacon.Segment.ASEntries))
+	if w.Diversity != 0 {
+		score -= w.Diversity * float64(best.Diversity(beacon))
+	}
+	if len(w.ASes) == 0 && len(w.ISDs) == 0 {
+		return score
+	}
+	for _, ia := range buildHops(beacon) {
+		score += w.ASes[ia.A] + w.ISDs[ia.I]
+	}
+	return score
+}
+
 // Filter filters beacons.
 type Filter struct {
 	// MaxHopsLength is the maximum number of hops a segment can have.
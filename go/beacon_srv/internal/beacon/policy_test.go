@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
 
 	"github.com/scionproto/scion/go/beacon_srv/internal/beacon"
 	"github.com/scionproto/scion/go/lib/addr"
@@ -78,6 +79,59 @@ func TestLoadPolicyFromYaml(t *testing.T) {
 	})
 }
 
+func TestParsePolicyYamlSelection(t *testing.T) {
+	tests := map[string]struct {
+		yaml      string
+		selection beacon.SelectionType
+		weights   beacon.SelectionWeights
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"default": {
+			yaml:      "BestSetSize: 6",
+			selection: beacon.DiversitySelection,
+			assertErr: assert.NoError,
+		},
+		"shortest": {
+			yaml:      "Selection: Shortest",
+			selection: beacon.ShortestSelection,
+			assertErr: assert.NoError,
+		},
+		"weighted": {
+			yaml: `
+Selection: Weighted
+Weights:
+  Hops: 1.5
+  Diversity: 2
+  ASes: {"ff00:0:110": 10}
+  ISDs: {2: 5}
+`,
+			selection: beacon.WeightedSelection,
+			weights: beacon.SelectionWeights{
+				Hops:      1.5,
+				Diversity: 2,
+				ASes:      map[addr.AS]float64{ia110.A: 10},
+				ISDs:      map[addr.ISD]float64{2: 5},
+			},
+			assertErr: assert.NoError,
+		},
+		"unknown": {
+			yaml:      "Selection: Random",
+			assertErr: assert.Error,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := beacon.ParsePolicyYaml([]byte(test.yaml), beacon.PropPolicy)
+			test.assertErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, test.selection, p.Selection)
+			assert.Equal(t, test.weights, p.Weights)
+		})
+	}
+}
+
 func TestFilterApply(t *testing.T) {
 	Convey("Given a filter", t, func() {
 		f := beacon.Filter{
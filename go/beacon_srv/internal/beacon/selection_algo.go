@@ -14,11 +14,17 @@
 
 package beacon
 
-import "math"
+import (
+	"math"
+	"sort"
+)
 
-type selectionAlgorithm interface {
+// SelectionAlgorithm selects the beacons to propagate or register from the
+// candidate beacons.
+type SelectionAlgorithm interface {
 	// SelectAndServe selects the n best beacons from the beacons channel and
-	// serves them on the results channel.
+	// serves them on the results channel. The beacons channel is sorted by
+	// segment length and must be drained completely.
 	SelectAndServe(beacons <-chan BeaconOrErr, results chan<- BeaconOrErr, resultSize int)
 }
 
@@ -110,6 +116,67 @@ func (baseAlgo) serveMostDiverse(beacons <-chan BeaconOrErr, results chan<- Beac
 	results <- BeaconOrErr{Beacon: first}
 }
 
+// shortestAlgo implements a selection algorithm that serves the shortest
+// beacons without taking path diversity into account.
+type shortestAlgo struct{}
+
+// SelectAndServe serves the resultSize first beacons on the result channel.
+// Errors are served until resultSize beacons have been served.
+func (shortestAlgo) SelectAndServe(beacons <-chan BeaconOrErr, results chan<- BeaconOrErr,
+	resultSize int) {
+
+	i := 0
+	for res := range beacons {
+		if i == resultSize {
+			// Drain the remaining beacons.
+			continue
+		}
+		if res.Err == nil {
+			i++
+		}
+		results <- res
+	}
+}
+
+// weightedAlgo implements a selection algorithm that scores the beacons based
+// on the configured weights and serves the beacons with the lowest score.
+type weightedAlgo struct {
+	weights SelectionWeights
+}
+
+// SelectAndServe serves the resultSize beacons with the lowest score on the
+// result channel. Beacons with the same score are served in the order they are
+// received. Errors are served immediately.
+func (a weightedAlgo) SelectAndServe(beacons <-chan BeaconOrErr, results chan<- BeaconOrErr,
+	resultSize int) {
+
+	type scored struct {
+		beacon Beacon
+		score  float64
+	}
+	var candidates []scored
+	var best Beacon
+	for res := range beacons {
+		if res.Err != nil {
+			results <- res
+			continue
+		}
+		if (best == Beacon{}) {
+			best = res.Beacon
+		}
+		candidates = append(candidates, scored{
+			beacon: res.Beacon,
+			score:  a.weights.score(best, res.Beacon),
+		})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score < candidates[j].score
+	})
+	for i := 0; i < len(candidates) && i < resultSize; i++ {
+		results <- BeaconOrErr{Beacon: candidates[i].beacon}
+	}
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
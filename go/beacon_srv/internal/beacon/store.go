@@ -50,10 +50,14 @@ func NewBeaconStore(policies Policies, db DB) (*Store, error) {
 	if err := policies.Validate(); err != nil {
 		return nil, err
 	}
+	algos, err := selectionAlgorithms(&policies.Prop, &policies.UpReg, &policies.DownReg)
+	if err != nil {
+		return nil, err
+	}
 	s := &Store{
 		baseStore: baseStore{
-			db:   db,
-			algo: baseAlgo{},
+			db:    db,
+			algos: algos,
 		},
 		policies: policies,
 	}
@@ -92,11 +96,12 @@ func (s *Store) getBeacons(ctx context.Context, policy *Policy) (<-chan BeaconOr
 	if err != nil {
 		return nil, err
 	}
+	algo := s.algos[policy.Type]
 	results := make(chan BeaconOrErr, min(maxResultChanSize, policy.BestSetSize))
 	go func() {
 		defer log.LogPanicAndExit()
 		defer close(results)
-		s.algo.SelectAndServe(beacons, results, policy.BestSetSize)
+		algo.SelectAndServe(beacons, results, policy.BestSetSize)
 	}()
 	return results, nil
 }
@@ -129,10 +134,14 @@ func NewCoreBeaconStore(policies CorePolicies, db DB) (*CoreStore, error) {
 	if err := policies.Validate(); err != nil {
 		return nil, err
 	}
+	algos, err := selectionAlgorithms(&policies.Prop, &policies.CoreReg)
+	if err != nil {
+		return nil, err
+	}
 	s := &CoreStore{
 		baseStore: baseStore{
-			db:   db,
-			algo: baseAlgo{},
+			db:    db,
+			algos: algos,
 		},
 		policies: policies,
 	}
@@ -166,6 +175,7 @@ func (s *CoreStore) getBeacons(ctx context.Context, policy *Policy) (<-chan Beac
 	if err != nil {
 		return nil, err
 	}
+	algo := s.algos[policy.Type]
 	results := make(chan BeaconOrErr, min(maxResultChanSize, len(srcs)*policy.BestSetSize))
 	wg := sync.WaitGroup{}
 	var errs []addr.IA
@@ -183,7 +193,7 @@ func (s *CoreStore) getBeacons(ctx context.Context, policy *Policy) (<-chan Beac
 		go func() {
 			defer log.LogPanicAndExit()
 			defer wg.Done()
-			s.algo.SelectAndServe(beacons, results, policy.BestSetSize)
+			algo.SelectAndServe(beacons, results, policy.BestSetSize)
 		}()
 	}
 	go func() {
@@ -210,11 +220,52 @@ func (s *CoreStore) MaxExpTime(policyType PolicyType) spath.ExpTimeType {
 	return DefaultMaxExpTime
 }
 
+// GCConfig configures the garbage collection of the beacon store.
+type GCConfig struct {
+	// MaxBeacons is the maximum number of beacons kept in the store. If it is
+	// zero, the number of beacons is not limited.
+	MaxBeacons int
+	// EvictionStrategy determines which beacons are evicted first, if the
+	// store holds more than MaxBeacons beacons.
+	EvictionStrategy EvictionStrategy
+}
+
 // baseStore is the basis for the beacon store.
 type baseStore struct {
 	db     DB
 	usager usager
-	algo   selectionAlgorithm
+	algos  map[PolicyType]SelectionAlgorithm
+	gc     GCConfig
+}
+
+func selectionAlgorithms(policies ...*Policy) (map[PolicyType]SelectionAlgorithm, error) {
+	algos := make(map[PolicyType]SelectionAlgorithm, len(policies))
+	for _, policy := range policies {
+		algo, err := policy.selectionAlgorithm()
+		if err != nil {
+			return nil, err
+		}
+		algos[policy.Type] = algo
+	}
+	return algos, nil
+}
+
+// SetSelectionAlgorithm replaces the selection algorithm used for the given
+// policy type. This allows plugging in custom selection algorithms. It must be
+// called before the store is used.
+func (s *baseStore) SetSelectionAlgorithm(policyType PolicyType, algo SelectionAlgorithm) {
+	s.algos[policyType] = algo
+}
+
+// SetGCConfig sets the garbage collection configuration. It must be called
+// before the store is used.
+func (s *baseStore) SetGCConfig(cfg GCConfig) {
+	s.gc = cfg
+}
+
+// GCConfig returns the garbage collection configuration.
+func (s *baseStore) GCConfig() GCConfig {
+	return s.gc
 }
 
 // PreFilter indicates whether the beacon will be filtered on insert by
@@ -266,6 +317,21 @@ func (s *baseStore) DeleteExpiredBeacons(ctx context.Context) (int, error) {
 	return s.db.DeleteExpiredBeacons(ctx, time.Now())
 }
 
+// EvictBeacons evicts beacons according to the configured eviction strategy,
+// if the store holds more beacons than the configured limit. It returns the
+// number of evicted beacons.
+func (s *baseStore) EvictBeacons(ctx context.Context) (int, error) {
+	if s.gc.MaxBeacons == 0 {
+		return 0, nil
+	}
+	return s.db.EvictBeacons(ctx, s.gc.MaxBeacons, s.gc.EvictionStrategy)
+}
+
+// BeaconCounts returns the number of beacons in the store per origin AS.
+func (s *baseStore) BeaconCounts(ctx context.Context) (map[addr.IA]int, error) {
+	return s.db.BeaconCounts(ctx)
+}
+
 // DeleteExpiredRevocations deletes expired Revocations from the store.
 func (s *baseStore) DeleteExpiredRevocations(ctx context.Context) (int, error) {
 	return s.db.DeleteExpiredRevocations(ctx, time.Now())
@@ -20,6 +20,8 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/scionproto/scion/go/beacon_srv/internal/beacon"
 	"github.com/scionproto/scion/go/beacon_srv/internal/beacon/mock_beacon"
@@ -194,6 +196,135 @@ func testStoreSelection(t *testing.T,
 	}
 }
 
+func TestStoreSelectionAlgorithms(t *testing.T) {
+	mctrl := gomock.NewController(t)
+	defer mctrl.Finish()
+	g := graph.NewDefaultGraph(mctrl)
+
+	stub := graph.If_210_X_220_X
+	beacons := []beacon.BeaconOrErr{
+		testBeaconOrErr(g, graph.If_130_A_110_X, graph.If_110_X_210_X, stub),
+		testBeaconOrErr(g, graph.If_130_A_110_X, graph.If_110_X_210_X, stub),
+		testBeaconOrErr(g, graph.If_130_B_120_A, graph.If_120_A_110_X, graph.If_110_X_210_X, stub),
+		testBeaconOrErr(g, graph.If_130_B_120_A, graph.If_120_B_220_X, graph.If_220_X_210_X, stub),
+		testBeaconOrErr(g, graph.If_130_B_111_A, graph.If_111_B_120_X, graph.If_120_B_220_X,
+			graph.If_220_X_210_X, stub),
+	}
+	beaconErr := beacon.BeaconOrErr{Err: errors.New("Fail")}
+	results := append(append([]beacon.BeaconOrErr{}, beacons...), beaconErr)
+
+	var tests = map[string]struct {
+		policy   beacon.Policy
+		algo     beacon.SelectionAlgorithm
+		expected []beacon.BeaconOrErr
+	}{
+		"default": {
+			policy:   beacon.Policy{BestSetSize: 2},
+			expected: []beacon.BeaconOrErr{beacons[0], beacons[3]},
+		},
+		"diversity": {
+			policy: beacon.Policy{
+				BestSetSize: 2,
+				Selection:   beacon.DiversitySelection,
+			},
+			expected: []beacon.BeaconOrErr{beacons[0], beacons[3]},
+		},
+		"shortest": {
+			policy: beacon.Policy{
+				BestSetSize: 2,
+				Selection:   beacon.ShortestSelection,
+			},
+			expected: []beacon.BeaconOrErr{beacons[0], beacons[1]},
+		},
+		"weighted avoid AS": {
+			policy: beacon.Policy{
+				BestSetSize: 2,
+				Selection:   beacon.WeightedSelection,
+				Weights: beacon.SelectionWeights{
+					Hops: 1,
+					ASes: map[addr.AS]float64{xtest.MustParseAS("ff00:0:110"): 10},
+				},
+			},
+			expected: []beacon.BeaconOrErr{beacons[3], beacons[4]},
+		},
+		"weighted avoid ISD": {
+			policy: beacon.Policy{
+				BestSetSize: 3,
+				Selection:   beacon.WeightedSelection,
+				Weights: beacon.SelectionWeights{
+					Hops: 1,
+					ISDs: map[addr.ISD]float64{2: 10},
+				},
+			},
+			expected: []beacon.BeaconOrErr{beacons[0], beacons[1], beacons[2]},
+		},
+		"custom": {
+			policy:   beacon.Policy{BestSetSize: 2},
+			algo:     lastBeaconAlgo{},
+			expected: []beacon.BeaconOrErr{beacons[4]},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mctrl := gomock.NewController(t)
+			defer mctrl.Finish()
+			db := mock_beacon.NewMockDB(mctrl)
+			policies := beacon.Policies{
+				Prop:    test.policy,
+				UpReg:   beacon.Policy{},
+				DownReg: beacon.Policy{},
+			}
+			store, err := beacon.NewBeaconStore(policies, db)
+			require.NoError(t, err)
+			if test.algo != nil {
+				store.SetSelectionAlgorithm(beacon.PropPolicy, test.algo)
+			}
+			db.EXPECT().CandidateBeacons(gomock.Any(), gomock.Any(), gomock.Any(),
+				addr.IA{}).DoAndReturn(
+				func(_ ...interface{}) (<-chan beacon.BeaconOrErr, error) {
+					ch := make(chan beacon.BeaconOrErr, len(results))
+					defer close(ch)
+					for _, res := range results {
+						ch <- res
+					}
+					return ch, nil
+				},
+			)
+			res, err := store.BeaconsToPropagate(context.Background())
+			require.NoError(t, err)
+			var served []beacon.BeaconOrErr
+			for bOrErr := range res {
+				if bOrErr.Err == nil {
+					served = append(served, bOrErr)
+				}
+			}
+			assert.ElementsMatch(t, test.expected, served)
+		})
+	}
+	t.Run("unknown selection", func(t *testing.T) {
+		policies := beacon.Policies{
+			Prop: beacon.Policy{Selection: "unknown"},
+		}
+		_, err := beacon.NewBeaconStore(policies, nil)
+		assert.Error(t, err)
+	})
+}
+
+// lastBeaconAlgo is a custom selection algorithm that serves the last beacon.
+type lastBeaconAlgo struct{}
+
+func (lastBeaconAlgo) SelectAndServe(beacons <-chan beacon.BeaconOrErr,
+	results chan<- beacon.BeaconOrErr, _ int) {
+
+	var last beacon.BeaconOrErr
+	for res := range beacons {
+		if res.Err == nil {
+			last = res
+		}
+	}
+	results <- last
+}
+
 func TestCoreStoreSegmentsToRegister(t *testing.T) {
 	testCoreStoreSelection(t, func(store *beacon.CoreStore) (<-chan beacon.BeaconOrErr, error) {
 		return store.SegmentsToRegister(context.Background(), proto.PathSegType_core)
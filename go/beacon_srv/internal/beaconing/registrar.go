@@ -52,6 +52,10 @@ type RegistrarConf struct {
 	Msgr         infra.Messenger
 	Period       time.Duration
 	SegType      proto.PathSegType
+	// RetryBackoff is the backoff before retrying a failed registration. It
+	// doubles with every consecutive failure with the same remote, up to the
+	// registration period. If zero, DefaultRetryBackoff is used.
+	RetryBackoff time.Duration
 }
 
 // Registrar is used to periodically register path segments with the appropriate
@@ -63,6 +67,7 @@ type Registrar struct {
 	segProvider  SegmentProvider
 	topoProvider topology.Provider
 	segType      proto.PathSegType
+	remotes      *remoteTracker
 
 	// mutable fields
 	lastSucc time.Time
@@ -83,6 +88,7 @@ func (cfg RegistrarConf) New() (*Registrar, error) {
 		msgr:         cfg.Msgr,
 		tick:         tick{period: cfg.Period},
 		segExtender:  extender,
+		remotes:      newRemoteTracker(cfg.SegType, cfg.RetryBackoff, cfg.Period),
 	}
 	return r, nil
 }
@@ -103,10 +109,19 @@ func (r *Registrar) Run(ctx context.Context) {
 	r.tick.updateLast()
 }
 
+// Remotes returns the registration status with the path servers of all remote
+// ASes that segments have been registered with.
+func (r *Registrar) Remotes() []RemoteStatus {
+	return r.remotes.statuses()
+}
+
 func (r *Registrar) run(ctx context.Context) error {
 	if r.tick.now.Sub(r.lastSucc) < r.tick.period && !r.tick.passed() {
+		r.retry(ctx)
 		return nil
 	}
+	// All segments are registered anew, pending retries are obsolete.
+	r.remotes.clearPending()
 	logger := log.FromCtx(ctx)
 	segments, err := r.segProvider.SegmentsToRegister(ctx, r.segType)
 	if err != nil {
@@ -152,6 +167,35 @@ func (r *Registrar) run(ctx context.Context) error {
 	return nil
 }
 
+// retry resends the failed registrations whose backoff has expired.
+func (r *Registrar) retry(ctx context.Context) {
+	regs := r.remotes.due(r.tick.now)
+	if len(regs) == 0 {
+		return
+	}
+	logger := log.FromCtx(ctx)
+	logger.Debug("[beaconing.Registrar] Retrying failed registrations", "type", r.segType,
+		"count", len(regs))
+	s := newSummary()
+	var wg sync.WaitGroup
+	for _, reg := range regs {
+		sr := segmentRegistrar{
+			Registrar: r,
+			beacon:    reg.beacon,
+			summary:   s,
+			logger:    logger,
+			reg:       reg.reg,
+			addr:      reg.addr,
+		}
+		sr.startSendSegReg(ctx, &wg)
+	}
+	wg.Wait()
+	if s.count > 0 {
+		logger.Info("[beaconing.Registrar] Registered beacons on retry", "type", r.segType,
+			"count", s.count, "failed", len(regs)-s.count)
+	}
+}
+
 func (r *Registrar) logSummary(logger log.Logger, s *summary) {
 	if r.tick.passed() {
 		logger.Info("[beaconing.Registrar] Registered beacons", "type", r.segType, "count", s.count,
@@ -224,6 +268,11 @@ func (r *segmentRegistrar) startSendSegReg(ctx context.Context, wg *sync.WaitGro
 			r.logger.Error("[beaconing.Registrar] Unable to register segment",
 				"addr", r.addr, "err", err)
 			metrics.Registrar.InternalErrorsWithType(r.segType.String()).Inc()
+			r.remotes.failure(remoteIA(r.addr), time.Now(), pendingReg{
+				reg:    r.reg,
+				addr:   r.addr,
+				beacon: r.beacon,
+			}, err)
 			return
 		}
 		r.onSuccess()
@@ -233,6 +282,7 @@ func (r *segmentRegistrar) startSendSegReg(ctx context.Context, wg *sync.WaitGro
 }
 
 func (r *segmentRegistrar) onSuccess() {
+	r.remotes.success(remoteIA(r.addr), time.Now(), r.addr)
 	r.summary.AddSrc(r.beacon.Segment.FirstIA())
 	r.summary.Inc()
 	l := metrics.RegistrarLabels{
@@ -182,6 +182,73 @@ func TestRegistrarRun(t *testing.T) {
 			r.Run(context.Background())
 		})
 	}
+	Convey("Failed registrations are retried", t, func() {
+		mctrl := gomock.NewController(t)
+		defer mctrl.Finish()
+		topoProvider := xtest.TopoProviderFromFile(t, topoCore)
+		segProvider := mock_beaconing.NewMockSegmentProvider(mctrl)
+		msgr := mock_infra.NewMockMessenger(mctrl)
+		cfg := RegistrarConf{
+			Config: ExtenderConf{
+				Signer: testSigner(t, priv, topoProvider.Get().ISD_AS),
+				Mac:    mac,
+				Intfs: ifstate.NewInterfaces(topoProvider.Get().IFInfoMap,
+					ifstate.Config{}),
+				MTU:           uint16(topoProvider.Get().MTU),
+				GetMaxExpTime: maxExpTimeFactory(beacon.DefaultMaxExpTime),
+			},
+			Period:       time.Hour,
+			RetryBackoff: time.Nanosecond,
+			Msgr:         msgr,
+			SegProvider:  segProvider,
+			TopoProvider: topoProvider,
+			SegType:      proto.PathSegType_core,
+		}
+		r, err := cfg.New()
+		SoMsg("err", err, ShouldBeNil)
+		g := graph.NewDefaultGraph(mctrl)
+		beacons := [][]common.IFIDType{
+			{graph.If_120_A_110_X},
+			{graph.If_130_B_120_A, graph.If_120_A_110_X},
+		}
+		segProvider.EXPECT().SegmentsToRegister(gomock.Any(), proto.PathSegType_core).DoAndReturn(
+			func(_, _ interface{}) (<-chan beacon.BeaconOrErr, error) {
+				res := make(chan beacon.BeaconOrErr, len(beacons))
+				for _, desc := range beacons {
+					res <- testBeaconOrErr(g, desc)
+				}
+				close(res)
+				return res, nil
+			})
+		var mu sync.Mutex
+		var calls int
+		msgr.EXPECT().SendSegReg(gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any()).Times(len(beacons) + 1).DoAndReturn(
+			func(_, _, _, _ interface{}) error {
+				mu.Lock()
+				defer mu.Unlock()
+				calls++
+				if calls == 1 {
+					return errors.New("no ack")
+				}
+				return nil
+			},
+		)
+		for _, intf := range cfg.Config.Intfs.All() {
+			intf.Activate(42)
+		}
+		r.Run(context.Background())
+		remotes := r.Remotes()
+		SoMsg("Remotes", len(remotes), ShouldEqual, 1)
+		SoMsg("IA", remotes[0].IA, ShouldResemble, topoProvider.Get().ISD_AS)
+		SoMsg("Pending", remotes[0].Pending, ShouldEqual, 1)
+		// The second run retries the failed registration only.
+		r.Run(context.Background())
+		remotes = r.Remotes()
+		SoMsg("Pending", remotes[0].Pending, ShouldEqual, 0)
+		SoMsg("Failures", remotes[0].ConsecutiveFailures, ShouldEqual, 0)
+		SoMsg("LastSuccess", remotes[0].LastSuccess, ShouldNotBeZeroValue)
+	})
 	Convey("Run drains the channel", t, func() {
 		mctrl := gomock.NewController(t)
 		defer mctrl.Finish()
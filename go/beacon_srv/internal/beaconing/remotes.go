@@ -0,0 +1,262 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package beaconing
+
+import (
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/beacon_srv/internal/beacon"
+	"github.com/scionproto/scion/go/beacon_srv/internal/metrics"
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/prom"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/proto"
+)
+
+// DefaultRetryBackoff is the default backoff before the first retry of a
+// failed registration.
+const DefaultRetryBackoff = time.Second
+
+// RemoteStatus is the registration status with the path servers of a remote
+// AS.
+type RemoteStatus struct {
+	// IA is the ISD-AS of the remote path servers.
+	IA addr.IA
+	// LastAttempt is the time of the last registration attempt.
+	LastAttempt time.Time
+	// LastSuccess is the time of the last acknowledged registration.
+	LastSuccess time.Time
+	// ConsecutiveFailures is the number of failed registrations since the
+	// last acknowledged one.
+	ConsecutiveFailures int
+	// Pending is the number of failed registrations awaiting a retry.
+	Pending int
+}
+
+// pendingReg is a failed registration that is retried.
+type pendingReg struct {
+	reg    *path_mgmt.SegReg
+	addr   net.Addr
+	beacon beacon.Beacon
+}
+
+type remoteState struct {
+	status    RemoteStatus
+	pending   []pendingReg
+	nextRetry time.Time
+	// paths are the distinct paths registrations with the remote have been
+	// sent over since the last clearPending, in order of first use.
+	paths []*remotePath
+}
+
+// remotePath is a path to a remote, and whether the last registration sent
+// over it failed.
+type remotePath struct {
+	addr   *snet.Addr
+	failed bool
+}
+
+// remoteTracker tracks the registration status per remote and keeps the
+// failed registrations until they are retried. The backoff between retries
+// doubles with every consecutive failure, up to maxBackoff. Retries are sent
+// over a path to the remote that has not failed, if one is known.
+type remoteTracker struct {
+	segType    proto.PathSegType
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	mtx     sync.Mutex
+	remotes map[addr.IA]*remoteState
+}
+
+func newRemoteTracker(segType proto.PathSegType, minBackoff,
+	maxBackoff time.Duration) *remoteTracker {
+
+	if minBackoff <= 0 {
+		minBackoff = DefaultRetryBackoff
+	}
+	if maxBackoff < minBackoff {
+		maxBackoff = minBackoff
+	}
+	return &remoteTracker{
+		segType:    segType,
+		minBackoff: minBackoff,
+		maxBackoff: maxBackoff,
+		remotes:    make(map[addr.IA]*remoteState),
+	}
+}
+
+// success records an acknowledged registration with the remote at address a.
+func (t *remoteTracker) success(ia addr.IA, now time.Time, a net.Addr) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	s := t.get(ia)
+	s.record(a, false)
+	s.status.LastAttempt = now
+	s.status.LastSuccess = now
+	s.status.ConsecutiveFailures = 0
+	t.report(ia, metrics.Success, 0)
+}
+
+// failure records a failed registration with the remote and queues it for a
+// retry.
+func (t *remoteTracker) failure(ia addr.IA, now time.Time, reg pendingReg, err error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	s := t.get(ia)
+	s.record(reg.addr, true)
+	s.status.LastAttempt = now
+	s.status.ConsecutiveFailures++
+	s.pending = append(s.pending, reg)
+	s.status.Pending = len(s.pending)
+	s.nextRetry = now.Add(t.backoff(s.status.ConsecutiveFailures))
+	result := prom.ErrNotClassified
+	if common.IsTimeoutErr(err) {
+		result = prom.ErrTimeout
+	}
+	t.report(ia, result, s.status.ConsecutiveFailures)
+}
+
+// due removes and returns the pending registrations of all remotes whose
+// backoff has expired. The address of a returned registration is replaced by
+// one with a path to the remote that has not failed, if there is one.
+func (t *remoteTracker) due(now time.Time) []pendingReg {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	var regs []pendingReg
+	for _, s := range t.remotes {
+		if len(s.pending) == 0 || now.Before(s.nextRetry) {
+			continue
+		}
+		for _, reg := range s.pending {
+			reg.addr = s.alternative(reg.addr)
+			regs = append(regs, reg)
+		}
+		s.pending = nil
+		s.status.Pending = 0
+	}
+	return regs
+}
+
+// clearPending drops all pending registrations and forgets the paths used so
+// far. It is called when all segments are registered anew.
+func (t *remoteTracker) clearPending() {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	for _, s := range t.remotes {
+		s.pending = nil
+		s.status.Pending = 0
+		s.paths = nil
+	}
+}
+
+// statuses returns the status of all remotes, sorted by ISD-AS.
+func (t *remoteTracker) statuses() []RemoteStatus {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	res := make([]RemoteStatus, 0, len(t.remotes))
+	for _, s := range t.remotes {
+		res = append(res, s.status)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].IA.IAInt() < res[j].IA.IAInt() })
+	return res
+}
+
+func (t *remoteTracker) get(ia addr.IA) *remoteState {
+	s, ok := t.remotes[ia]
+	if !ok {
+		s = &remoteState{status: RemoteStatus{IA: ia}}
+		t.remotes[ia] = s
+	}
+	return s
+}
+
+// record records whether the last registration sent over the path of a
+// failed.
+func (s *remoteState) record(a net.Addr, failed bool) {
+	sa, ok := a.(*snet.Addr)
+	if !ok {
+		return
+	}
+	key := pathKey(sa)
+	for _, p := range s.paths {
+		if pathKey(p.addr) == key {
+			p.failed = failed
+			return
+		}
+	}
+	s.paths = append(s.paths, &remotePath{addr: sa.Copy(), failed: failed})
+}
+
+// alternative returns an address with a path to the remote that differs from
+// the path of a and has not failed. If there is none, a is returned.
+func (s *remoteState) alternative(a net.Addr) net.Addr {
+	sa, ok := a.(*snet.Addr)
+	if !ok {
+		return a
+	}
+	key := pathKey(sa)
+	for _, p := range s.paths {
+		if !p.failed && pathKey(p.addr) != key {
+			return p.addr.Copy()
+		}
+	}
+	return a
+}
+
+// pathKey identifies the path of a by its raw forwarding path and next hop.
+func pathKey(a *snet.Addr) string {
+	var key string
+	if a.Path != nil {
+		key = string(a.Path.Raw)
+	}
+	if a.NextHop != nil {
+		key += a.NextHop.String()
+	}
+	return key
+}
+
+func (t *remoteTracker) backoff(failures int) time.Duration {
+	backoff := t.minBackoff
+	for i := 1; i < failures && backoff < t.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > t.maxBackoff {
+		return t.maxBackoff
+	}
+	return backoff
+}
+
+func (t *remoteTracker) report(ia addr.IA, result string, failures int) {
+	l := metrics.RemoteLabels{RemoteIA: ia, SegType: t.segType.String()}
+	metrics.Registrar.RemoteRegistrations(metrics.RemoteResultLabels{
+		RemoteLabels: l,
+		Result:       result,
+	}).Inc()
+	metrics.Registrar.RemoteFailures(l).Set(float64(failures))
+}
+
+// remoteIA returns the ISD-AS of the registration destination.
+func remoteIA(a net.Addr) addr.IA {
+	if sa, ok := a.(*snet.Addr); ok {
+		return sa.IA
+	}
+	return addr.IA{}
+}
@@ -0,0 +1,89 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package beaconing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/proto"
+)
+
+func TestRemoteTracker(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	other := xtest.MustParseIA("1-ff00:0:120")
+	now := time.Now()
+	t.Run("Backoff doubles up to max", func(t *testing.T) {
+		tr := newRemoteTracker(proto.PathSegType_down, time.Second, 5*time.Second)
+		assert.Equal(t, time.Second, tr.backoff(1))
+		assert.Equal(t, 2*time.Second, tr.backoff(2))
+		assert.Equal(t, 4*time.Second, tr.backoff(3))
+		assert.Equal(t, 5*time.Second, tr.backoff(4))
+		assert.Equal(t, 5*time.Second, tr.backoff(100))
+	})
+	t.Run("Failed registrations are due after backoff", func(t *testing.T) {
+		tr := newRemoteTracker(proto.PathSegType_down, time.Second, time.Minute)
+		tr.failure(ia, now, pendingReg{}, errors.New("fail"))
+		tr.failure(ia, now, pendingReg{}, errors.New("fail"))
+		tr.success(other, now, nil)
+		assert.Empty(t, tr.due(now.Add(time.Second)))
+		assert.Len(t, tr.due(now.Add(2*time.Second)), 2)
+		assert.Empty(t, tr.due(now.Add(time.Hour)))
+		assert.Equal(t, []RemoteStatus{
+			{IA: ia, LastAttempt: now, ConsecutiveFailures: 2},
+			{IA: other, LastAttempt: now, LastSuccess: now},
+		}, tr.statuses())
+	})
+	t.Run("Success resets failures", func(t *testing.T) {
+		tr := newRemoteTracker(proto.PathSegType_down, 0, 0)
+		tr.failure(ia, now, pendingReg{}, common.NewBasicError("fail", nil))
+		later := now.Add(time.Second)
+		tr.success(ia, later, nil)
+		assert.Equal(t, []RemoteStatus{
+			{IA: ia, LastAttempt: later, LastSuccess: later, Pending: 1},
+		}, tr.statuses())
+	})
+	t.Run("Retries use a different path", func(t *testing.T) {
+		ps := addr.NewSVCUDPAppAddr(addr.SvcPS)
+		pathA := &snet.Addr{IA: ia, Host: ps, Path: spath.New(common.RawBytes{1})}
+		pathB := &snet.Addr{IA: ia, Host: ps, Path: spath.New(common.RawBytes{2})}
+		tr := newRemoteTracker(proto.PathSegType_down, time.Second, time.Minute)
+		tr.success(ia, now, pathB)
+		tr.failure(ia, now, pendingReg{addr: pathA}, errors.New("fail"))
+		regs := tr.due(now.Add(time.Second))
+		assert.Len(t, regs, 1)
+		assert.Equal(t, pathB, regs[0].addr)
+		// If all paths failed, the failed path is kept.
+		tr.failure(ia, now, pendingReg{addr: pathB}, errors.New("fail"))
+		regs = tr.due(now.Add(time.Hour))
+		assert.Len(t, regs, 1)
+		assert.Equal(t, pathB, regs[0].addr)
+	})
+	t.Run("Clear pending", func(t *testing.T) {
+		tr := newRemoteTracker(proto.PathSegType_down, time.Second, time.Minute)
+		tr.failure(ia, now, pendingReg{}, errors.New("fail"))
+		tr.clearPending()
+		assert.Empty(t, tr.due(now.Add(time.Hour)))
+		assert.Equal(t, 0, tr.statuses()[0].Pending)
+	})
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/scionproto/scion/go/beacon_srv/internal/beacon"
 	"github.com/scionproto/scion/go/beacon_srv/internal/beaconstorage"
 	"github.com/scionproto/scion/go/lib/infra/modules/db"
 	"github.com/scionproto/scion/go/lib/util"
@@ -33,6 +34,8 @@ func InitTestBeaconDBConf(cfg *beaconstorage.BeaconDBConf) {
 	}
 	(*cfg)[db.MaxOpenConnsKey] = "maxOpenConns"
 	(*cfg)[db.MaxIdleConnsKey] = "maxIdleConns"
+	(*cfg)[beaconstorage.MaxBeaconsKey] = "maxBeacons"
+	(*cfg)[beaconstorage.EvictionStrategyKey] = "evictionStrategy"
 }
 
 // CheckTestBeaconDBConf checks that the values are as expected from the sample.
@@ -40,6 +43,10 @@ func CheckTestBeaconDBConf(t *testing.T, cfg *beaconstorage.BeaconDBConf, id str
 	util.LowerKeys(*cfg)
 	assert.False(t, isSet(cfg.MaxOpenConns()))
 	assert.False(t, isSet(cfg.MaxIdleConns()))
+	maxBeacons, err := cfg.MaxBeacons()
+	assert.NoError(t, err)
+	assert.Zero(t, maxBeacons)
+	assert.Equal(t, beacon.EvictOldest, cfg.EvictionStrategy())
 	assert.Equal(t, beaconstorage.BackendSqlite, cfg.Backend())
 	assert.Equal(t, fmt.Sprintf("/var/lib/scion/beacondb/%s.beacon.db", id), cfg.Connection())
 }
@@ -20,6 +20,7 @@ package beaconstorage
 import (
 	"fmt"
 	"io"
+	"strconv"
 
 	"github.com/scionproto/scion/go/beacon_srv/internal/beacon"
 	"github.com/scionproto/scion/go/beacon_srv/internal/beacon/beacondbsqlite"
@@ -47,6 +48,12 @@ const (
 	BackendKey = "backend"
 	// ConnectionKey is the connection key in the config mapping.
 	ConnectionKey = "connection"
+	// MaxBeaconsKey is the key for the beacon store size limit in the config
+	// mapping.
+	MaxBeaconsKey = "maxbeacons"
+	// EvictionStrategyKey is the key for the eviction strategy in the config
+	// mapping.
+	EvictionStrategyKey = "evictionstrategy"
 )
 
 var _ (config.Config) = (*BeaconDBConf)(nil)
@@ -65,6 +72,9 @@ func (cfg *BeaconDBConf) InitDefaults() {
 	if cfg.Backend() == backendNone {
 		m[BackendKey] = string(BackendSqlite)
 	}
+	if m[EvictionStrategyKey] == "" {
+		m[EvictionStrategyKey] = string(beacon.EvictOldest)
+	}
 }
 
 // Backend returns the database backend type.
@@ -87,6 +97,40 @@ func (cfg *BeaconDBConf) MaxIdleConns() (int, bool) {
 	return db.ConfiguredMaxIdleConns(*cfg)
 }
 
+// MaxBeacons returns the maximum number of beacons in the store. Zero
+// indicates that the number of beacons is not limited.
+func (cfg *BeaconDBConf) MaxBeacons() (int, error) {
+	val := (*cfg)[MaxBeaconsKey]
+	if val == "" {
+		return 0, nil
+	}
+	max, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, common.NewBasicError("Invalid MaxBeacons", err, "value", val)
+	}
+	if max < 0 {
+		return 0, common.NewBasicError("MaxBeacons must not be negative", nil, "value", val)
+	}
+	return max, nil
+}
+
+// EvictionStrategy returns the configured eviction strategy.
+func (cfg *BeaconDBConf) EvictionStrategy() beacon.EvictionStrategy {
+	return beacon.EvictionStrategy((*cfg)[EvictionStrategyKey])
+}
+
+// GCConfig returns the garbage collection configuration of the beacon store.
+func (cfg *BeaconDBConf) GCConfig() (beacon.GCConfig, error) {
+	max, err := cfg.MaxBeacons()
+	if err != nil {
+		return beacon.GCConfig{}, err
+	}
+	return beacon.GCConfig{
+		MaxBeacons:       max,
+		EvictionStrategy: cfg.EvictionStrategy(),
+	}, nil
+}
+
 // Validate validates that all values are parsable, and the backend is set.
 func (cfg *BeaconDBConf) Validate() error {
 	if err := db.ValidateConfigLimits(*cfg); err != nil {
@@ -95,6 +139,12 @@ func (cfg *BeaconDBConf) Validate() error {
 	if err := cfg.validateBackend(); err != nil {
 		return err
 	}
+	if _, err := cfg.MaxBeacons(); err != nil {
+		return err
+	}
+	if err := cfg.EvictionStrategy().Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -140,18 +190,36 @@ func (cfg *BeaconDBConf) New(ia addr.IA) (beacon.DB, error) {
 
 // NewStore creates a new beacon store backed by the configured database.
 func (cfg *BeaconDBConf) NewStore(ia addr.IA, policies beacon.Policies) (Store, error) {
+	gc, err := cfg.GCConfig()
+	if err != nil {
+		return nil, err
+	}
 	db, err := cfg.New(ia)
 	if err != nil {
 		return nil, err
 	}
-	return beacon.NewBeaconStore(policies, db)
+	s, err := beacon.NewBeaconStore(policies, db)
+	if err != nil {
+		return nil, err
+	}
+	s.SetGCConfig(gc)
+	return s, nil
 }
 
 // NewCoreStore creates a new core beacon store backed by the configured database.
 func (cfg *BeaconDBConf) NewCoreStore(ia addr.IA, policies beacon.CorePolicies) (Store, error) {
+	gc, err := cfg.GCConfig()
+	if err != nil {
+		return nil, err
+	}
 	db, err := cfg.New(ia)
 	if err != nil {
 		return nil, err
 	}
-	return beacon.NewCoreBeaconStore(policies, db)
+	s, err := beacon.NewCoreBeaconStore(policies, db)
+	if err != nil {
+		return nil, err
+	}
+	s.SetGCConfig(gc)
+	return s, nil
 }
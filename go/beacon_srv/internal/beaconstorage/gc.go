@@ -0,0 +1,68 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package beaconstorage
+
+import (
+	"context"
+
+	"github.com/scionproto/scion/go/beacon_srv/internal/metrics"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/periodic"
+)
+
+var _ periodic.Task = (*GC)(nil)
+
+// GC is a periodic task that evicts beacons if the store exceeds its size
+// limit, and exports the beacon store occupancy metrics. Expired beacons are
+// deleted by the beacon cleaner.
+type GC struct {
+	Store Store
+}
+
+// NewGC creates a new garbage collection task for the store.
+func NewGC(s Store) *GC {
+	return &GC{Store: s}
+}
+
+// Name returns the tasks name.
+func (g *GC) Name() string {
+	return "beaconstorage.GC"
+}
+
+// Run evicts beacons if necessary and updates the beacon store metrics.
+func (g *GC) Run(ctx context.Context) {
+	logger := log.FromCtx(ctx)
+	cfg := g.Store.GCConfig()
+	metrics.Store.MaxBeacons().Set(float64(cfg.MaxBeacons))
+	evicted, err := g.Store.EvictBeacons(ctx)
+	if err != nil {
+		logger.Error("[beaconstorage.GC] Failed to evict beacons", "err", err)
+		metrics.Store.Runs(metrics.StoreGCLabels{Result: metrics.ErrDB}).Inc()
+		return
+	}
+	if evicted > 0 {
+		logger.Info("[beaconstorage.GC] Evicted beacons", "count", evicted,
+			"max", cfg.MaxBeacons, "strategy", cfg.EvictionStrategy)
+		metrics.Store.Evicted().Add(float64(evicted))
+	}
+	counts, err := g.Store.BeaconCounts(ctx)
+	if err != nil {
+		logger.Error("[beaconstorage.GC] Failed to count beacons", "err", err)
+		metrics.Store.Runs(metrics.StoreGCLabels{Result: metrics.ErrDB}).Inc()
+		return
+	}
+	metrics.Store.SetBeaconCounts(counts)
+	metrics.Store.Runs(metrics.StoreGCLabels{Result: metrics.Success}).Inc()
+}
@@ -0,0 +1,76 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package beaconstorage_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/beacon_srv/internal/beacon"
+	"github.com/scionproto/scion/go/beacon_srv/internal/beacon/mock_beacon"
+	"github.com/scionproto/scion/go/beacon_srv/internal/beaconstorage"
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestGCRun(t *testing.T) {
+	tests := map[string]struct {
+		GC           beacon.GCConfig
+		PrepareMocks func(db *mock_beacon.MockDB)
+	}{
+		"unlimited store does not evict": {
+			PrepareMocks: func(db *mock_beacon.MockDB) {
+				db.EXPECT().BeaconCounts(gomock.Any()).Return(
+					map[addr.IA]int{xtest.MustParseIA("1-ff00:0:110"): 3}, nil)
+			},
+		},
+		"limited store evicts": {
+			GC: beacon.GCConfig{MaxBeacons: 10, EvictionStrategy: beacon.EvictLongest},
+			PrepareMocks: func(db *mock_beacon.MockDB) {
+				db.EXPECT().EvictBeacons(gomock.Any(), 10, beacon.EvictLongest).Return(2, nil)
+				db.EXPECT().BeaconCounts(gomock.Any()).Return(
+					map[addr.IA]int{xtest.MustParseIA("1-ff00:0:110"): 10}, nil)
+			},
+		},
+		"eviction error skips counting": {
+			GC: beacon.GCConfig{MaxBeacons: 10, EvictionStrategy: beacon.EvictOldest},
+			PrepareMocks: func(db *mock_beacon.MockDB) {
+				db.EXPECT().EvictBeacons(gomock.Any(), 10, beacon.EvictOldest).Return(
+					0, errors.New("test err"))
+			},
+		},
+		"count error": {
+			PrepareMocks: func(db *mock_beacon.MockDB) {
+				db.EXPECT().BeaconCounts(gomock.Any()).Return(nil, errors.New("test err"))
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			db := mock_beacon.NewMockDB(ctrl)
+			store, err := beacon.NewBeaconStore(beacon.Policies{}, db)
+			require.NoError(t, err)
+			store.SetGCConfig(test.GC)
+			test.PrepareMocks(db)
+			beaconstorage.NewGC(store).Run(context.Background())
+		})
+	}
+}
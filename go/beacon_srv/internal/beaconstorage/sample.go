@@ -28,4 +28,14 @@ MaxOpenConns = ""
 # The maximum number of idle connections to the database. In case of the
 # empty string, the limit is not set and uses the go default. (default "")
 MaxIdleConns = ""
+
+# The maximum number of beacons in the beacon store. If the store holds more
+# beacons, beacons are evicted according to the eviction strategy. In case of
+# the empty string, the number of beacons is not limited. (default "")
+MaxBeacons = ""
+
+# The strategy to select the beacons to evict if the store exceeds MaxBeacons.
+# "oldest" evicts the least recently updated beacons first, "longest" evicts
+# the beacons with the most hops first. (default "oldest")
+EvictionStrategy = "oldest"
 `
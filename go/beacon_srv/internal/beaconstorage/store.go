@@ -57,6 +57,15 @@ type Store interface {
 	DeleteExpiredBeacons(ctx context.Context) (int, error)
 	// DeleteExpiredRevocations deletes expired Revocations from the store.
 	DeleteExpiredRevocations(ctx context.Context) (int, error)
+	// EvictBeacons evicts beacons according to the configured eviction
+	// strategy, if the store exceeds its size limit.
+	EvictBeacons(ctx context.Context) (int, error)
+	// BeaconCounts returns the number of beacons in the store per origin AS.
+	BeaconCounts(ctx context.Context) (map[addr.IA]int, error)
+	// SetGCConfig sets the garbage collection configuration.
+	SetGCConfig(cfg beacon.GCConfig)
+	// GCConfig returns the garbage collection configuration.
+	GCConfig() beacon.GCConfig
 	// Close closes the store.
 	Close() error
 }
@@ -17,6 +17,7 @@ package config
 
 import (
 	"io"
+	"path/filepath"
 	"time"
 
 	"github.com/scionproto/scion/go/beacon_srv/internal/beaconstorage"
@@ -25,6 +26,7 @@ import (
 	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
 	"github.com/scionproto/scion/go/lib/env"
 	"github.com/scionproto/scion/go/lib/infra/modules/idiscovery"
+	"github.com/scionproto/scion/go/lib/keyconf"
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/truststorage"
 	"github.com/scionproto/scion/go/lib/util"
@@ -63,7 +65,8 @@ type Config struct {
 	Logging        env.Logging
 	Metrics        env.Metrics
 	Tracing        env.Tracing
-	QUIC           env.QUIC `toml:"quic"`
+	QUIC           env.QUIC           `toml:"quic"`
+	CircuitBreaker env.CircuitBreaker `toml:"circuit_breaker"`
 	TrustDB        truststorage.TrustDBConf
 	BeaconDB       beaconstorage.BeaconDBConf
 	Discovery      idiscovery.Config
@@ -93,6 +96,8 @@ func (cfg *Config) Validate() error {
 		&cfg.Features,
 		&cfg.Logging,
 		&cfg.Metrics,
+		&cfg.QUIC,
+		&cfg.CircuitBreaker,
 		&cfg.TrustDB,
 		&cfg.BeaconDB,
 		&cfg.Discovery,
@@ -109,6 +114,7 @@ func (cfg *Config) Sample(dst io.Writer, path config.Path, _ config.CtxMap) {
 		&cfg.Metrics,
 		&cfg.Tracing,
 		&cfg.QUIC,
+		&cfg.CircuitBreaker,
 		&cfg.TrustDB,
 		&cfg.BeaconDB,
 		&cfg.Discovery,
@@ -121,6 +127,22 @@ func (cfg *Config) ConfigName() string {
 	return "bs_config"
 }
 
+// CheckFiles checks that the topology and the key files referenced by the
+// config can be loaded.
+func (cfg *Config) CheckFiles() error {
+	if err := env.CheckFilesAll(&cfg.General); err != nil {
+		return err
+	}
+	dir := filepath.Join(cfg.General.ConfigDir, "keys")
+	if _, err := keyconf.Load(dir, false, false, false, false); err != nil {
+		return common.NewBasicError("Unable to load key config", err, "dir", dir)
+	}
+	if _, err := keyconf.LoadMaster(dir); err != nil {
+		return common.NewBasicError("Unable to load master keys", err, "dir", dir)
+	}
+	return nil
+}
+
 var _ config.Config = (*BSConfig)(nil)
 
 // BSConfig holds the configuration specific to the beacon server.
@@ -77,6 +77,7 @@ func InitTestPolicies(cfg *Policies) {
 
 func CheckTestConfig(t *testing.T, cfg *Config, id string) {
 	envtest.CheckTest(t, &cfg.General, &cfg.Logging, &cfg.Metrics, &cfg.Tracing, nil, id)
+	envtest.CheckTestCircuitBreaker(t, &cfg.CircuitBreaker)
 	truststoragetest.CheckTestConfig(t, &cfg.TrustDB, id)
 	beaconstoragetest.CheckTestBeaconDBConf(t, &cfg.BeaconDB, id)
 	idiscoverytest.CheckTestConfig(t, &cfg.Discovery)
@@ -27,6 +27,13 @@
 // and renews revocations of already revoked interfaces. Create it with the
 // NewRevoker costructor.
 //
+// If multiple beacon server instances run in the same AS, only the instance
+// chosen by the Elector issues and renews revocations. The HeartbeatElector
+// elects the live instance with the smallest ID in the topology, where
+// liveness is tracked with periodic heartbeats. The elected instance
+// pushes its revocations to the other instances, such that a newly elected
+// instance can renew them.
+//
 // Handler
 //
 // The handler handles interface state requests. It can be instantiated with
@@ -0,0 +1,161 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ifstate
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/ctrl/cert_mgmt"
+	"github.com/scionproto/scion/go/lib/infra"
+	"github.com/scionproto/scion/go/lib/infra/messenger"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/periodic"
+	"github.com/scionproto/scion/go/lib/scrypto"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/topology"
+)
+
+// Elector decides whether the beacon server instance is responsible for
+// issuing the revocations of the local AS. In an AS with multiple beacon
+// server instances, exactly one of them must be elected, such that no
+// duplicate or conflicting revocations are issued.
+type Elector interface {
+	// Leader indicates whether this instance issues revocations.
+	Leader() bool
+	// Peers returns the other beacon server instances of the local AS. The
+	// leader shares the issued revocations with them, such that a new leader
+	// can renew them.
+	Peers() topology.IDAddrMap
+}
+
+var _ Elector = (*HeartbeatElector)(nil)
+var _ periodic.Task = (*HeartbeatElector)(nil)
+
+// HeartbeatElector elects the beacon server instance with the
+// lexicographically smallest ID among the live instances in the current
+// topology. Liveness is tracked with heartbeats: Run probes every instance
+// with a smaller ID by requesting the local TRC from it. An instance is
+// considered live, if it answered a probe within the lease timeout. Thus, if
+// the leader stops responding, the next instance takes over after the lease
+// timeout has passed.
+//
+// Instances that have not been probed yet are considered live for one lease
+// timeout after the elector is created. This prevents a restarting instance
+// from claiming leadership before it knows about the current leader.
+type HeartbeatElector struct {
+	id           string
+	topoProvider topology.Provider
+	msgr         infra.Messenger
+	leaseTimeout time.Duration
+	start        time.Time
+
+	mu       sync.Mutex
+	lastSeen map[string]time.Time
+}
+
+// NewHeartbeatElector creates a new elector for the beacon server instance
+// with the given id. An instance is considered live, if it answered a
+// heartbeat within the lease timeout.
+func NewHeartbeatElector(id string, topoProvider topology.Provider,
+	msgr infra.Messenger, leaseTimeout time.Duration) *HeartbeatElector {
+
+	return &HeartbeatElector{
+		id:           id,
+		topoProvider: topoProvider,
+		msgr:         msgr,
+		leaseTimeout: leaseTimeout,
+		start:        time.Now(),
+		lastSeen:     make(map[string]time.Time),
+	}
+}
+
+// Name returns the tasks name.
+func (e *HeartbeatElector) Name() string {
+	return "ifstate.HeartbeatElector"
+}
+
+// Run sends a heartbeat to every beacon server instance with a smaller ID and
+// records the instances that answered.
+func (e *HeartbeatElector) Run(ctx context.Context) {
+	topo := e.topoProvider.Get()
+	wg := sync.WaitGroup{}
+	for id, bs := range topo.BS {
+		if id >= e.id {
+			continue
+		}
+		a := &snet.Addr{
+			IA:      topo.ISD_AS,
+			Host:    bs.PublicAddr(topo.Overlay),
+			NextHop: bs.OverlayAddr(topo.Overlay),
+		}
+		wg.Add(1)
+		go func(id string) {
+			defer log.LogPanicAndExit()
+			defer wg.Done()
+			req := &cert_mgmt.TRCReq{
+				ISD:       topo.ISD_AS.I,
+				Version:   scrypto.LatestVer,
+				CacheOnly: true,
+			}
+			if _, err := e.msgr.GetTRC(ctx, req, a, messenger.NextId()); err != nil {
+				log.FromCtx(ctx).Trace("[ifstate.HeartbeatElector] No heartbeat from BS",
+					"bs", id, "err", err)
+				return
+			}
+			e.mu.Lock()
+			defer e.mu.Unlock()
+			e.lastSeen[id] = time.Now()
+		}(id)
+	}
+	wg.Wait()
+}
+
+// Leader indicates whether this instance is the elected leader, i.e., no
+// instance with a smaller ID is live. An instance that is not part of the
+// topology is never the leader.
+func (e *HeartbeatElector) Leader() bool {
+	bs := e.topoProvider.Get().BS
+	if _, ok := bs[e.id]; !ok {
+		return false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for id := range bs {
+		if id >= e.id {
+			continue
+		}
+		seen, ok := e.lastSeen[id]
+		if !ok {
+			seen = e.start
+		}
+		if time.Since(seen) < e.leaseTimeout {
+			return false
+		}
+	}
+	return true
+}
+
+// Peers returns the other beacon server instances in the current topology.
+func (e *HeartbeatElector) Peers() topology.IDAddrMap {
+	peers := make(topology.IDAddrMap)
+	for id, a := range e.topoProvider.Get().BS {
+		if id != e.id {
+			peers[id] = a
+		}
+	}
+	return peers
+}
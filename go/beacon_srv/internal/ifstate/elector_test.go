@@ -0,0 +1,86 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ifstate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/ctrl/cert_mgmt"
+	"github.com/scionproto/scion/go/lib/infra/mock_infra"
+	"github.com/scionproto/scion/go/lib/topology"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestHeartbeatElector(t *testing.T) {
+	Convey("Given a topology with multiple beacon servers", t, func() {
+		mctrl := gomock.NewController(t)
+		defer mctrl.Finish()
+		msgr := mock_infra.NewMockMessenger(mctrl)
+		topo := xtest.TopoProviderFromFile(t, "testdata/topology.json").Get()
+		// Reuse the path server address for the beacon server instances.
+		a := topo.PS.GetById("ps1-ff00_0_111-1")
+		topo.BS = topology.IDAddrMap{"bs-2": *a, "bs-1": *a, "bs-3": *a}
+		provider := &xtest.TestTopoProvider{Topo: topo}
+		lease := time.Hour
+		ctx, cancelF := context.WithTimeout(context.Background(), timeout)
+		defer cancelF()
+		Convey("The instance with the smallest ID is the leader", func() {
+			e := NewHeartbeatElector("bs-1", provider, msgr, lease)
+			e.Run(ctx)
+			SoMsg("leader", e.Leader(), ShouldBeTrue)
+		})
+		Convey("An instance that is not in the topology is never the leader", func() {
+			e := NewHeartbeatElector("bs-0", provider, msgr, lease)
+			SoMsg("leader", e.Leader(), ShouldBeFalse)
+		})
+		Convey("An instance does not take over right after it started", func() {
+			e := NewHeartbeatElector("bs-2", provider, msgr, lease)
+			SoMsg("leader", e.Leader(), ShouldBeFalse)
+		})
+		Convey("An instance does not take over while the leader is live", func() {
+			e := NewHeartbeatElector("bs-2", provider, msgr, lease)
+			e.start = time.Now().Add(-2 * lease)
+			msgr.EXPECT().GetTRC(gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any()).Return(&cert_mgmt.TRC{}, nil)
+			e.Run(ctx)
+			SoMsg("leader", e.Leader(), ShouldBeFalse)
+		})
+		Convey("An instance takes over if the leader misses its heartbeats", func() {
+			e := NewHeartbeatElector("bs-2", provider, msgr, lease)
+			e.lastSeen["bs-1"] = time.Now().Add(-2 * lease)
+			msgr.EXPECT().GetTRC(gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any()).Return(nil, errors.New("timeout"))
+			e.Run(ctx)
+			SoMsg("leader", e.Leader(), ShouldBeTrue)
+		})
+		Convey("The next instance takes over if the leader is removed", func() {
+			e := NewHeartbeatElector("bs-2", provider, msgr, lease)
+			delete(topo.BS, "bs-1")
+			SoMsg("leader", e.Leader(), ShouldBeTrue)
+		})
+		Convey("The peers are all other instances", func() {
+			peers := NewHeartbeatElector("bs-2", provider, msgr, lease).Peers()
+			SoMsg("len", len(peers), ShouldEqual, 2)
+			SoMsg("bs-1", peers, ShouldContainKey, "bs-1")
+			SoMsg("bs-3", peers, ShouldContainKey, "bs-3")
+		})
+	})
+}
@@ -52,12 +52,18 @@ type RevokerConf struct {
 	TopoProvider topology.Provider
 	RevInserter  RevInserter
 	RevConfig    RevConfig
+	// Elector decides whether this instance issues revocations. If it is
+	// nil, this instance is the only beacon server in the AS and always
+	// issues revocations.
+	Elector Elector
 }
 
 var _ periodic.Task = (*Revoker)(nil)
 
 // Revoker issues revocations for interfaces that have timed out.
-// Revocations for already revoked interfaces are renewed periodically.
+// Revocations for already revoked interfaces are renewed periodically. If an
+// elector is configured, only the elected instance issues revocations and
+// pushes them to the other beacon server instances.
 type Revoker struct {
 	cfg    RevokerConf
 	pusher brPusher
@@ -83,6 +89,10 @@ func (r *Revoker) Name() string {
 // and renews revocations for revoked interfaces.
 func (r *Revoker) Run(ctx context.Context) {
 	logger := log.FromCtx(ctx)
+	if r.cfg.Elector != nil && !r.cfg.Elector.Leader() {
+		logger.Trace("[ifstate.Revoker] Not elected, skipping revocation issuance")
+		return
+	}
 	revs := make(map[common.IFIDType]*path_mgmt.SignedRevInfo)
 	for ifid, intf := range r.cfg.Intfs.All() {
 		labelsIssued := metrics.IssuedLabels{
@@ -125,6 +135,7 @@ func (r *Revoker) Run(ctx context.Context) {
 		}
 		r.pushRevocationsToBRs(ctx, revs, wg)
 		r.pushRevocationsToPS(ctx, revs)
+		r.pushRevocationsToBSs(ctx, revs, wg)
 		wg.Wait()
 	}
 }
@@ -179,6 +190,35 @@ func (r *Revoker) pushRevocationsToPS(ctx context.Context,
 	}
 }
 
+// pushRevocationsToBSs pushes the revocations to the other beacon server
+// instances, such that they know the revocations in case they are elected.
+func (r *Revoker) pushRevocationsToBSs(ctx context.Context,
+	revs map[common.IFIDType]*path_mgmt.SignedRevInfo, wg *sync.WaitGroup) {
+
+	if r.cfg.Elector == nil {
+		return
+	}
+	topo := r.cfg.TopoProvider.Get()
+	for id, bs := range r.cfg.Elector.Peers() {
+		a := &snet.Addr{
+			IA:      topo.ISD_AS,
+			Host:    bs.PublicAddr(topo.Overlay),
+			NextHop: bs.OverlayAddr(topo.Overlay),
+		}
+		wg.Add(1)
+		go func(id string) {
+			defer log.LogPanicAndExit()
+			defer wg.Done()
+			for ifid, srev := range revs {
+				if err := r.cfg.Msgr.SendRev(ctx, srev, a, messenger.NextId()); err != nil {
+					log.FromCtx(ctx).Error("[ifstate.Revoker] Failed to send revocation to BS",
+						"bs", id, "ifid", ifid, "err", err)
+				}
+			}
+		}(id)
+	}
+}
+
 type brPusher struct {
 	msgr infra.Messenger
 	mode string
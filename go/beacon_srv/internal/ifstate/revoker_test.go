@@ -135,6 +135,42 @@ func TestRevokeInterface(t *testing.T) {
 	})
 }
 
+// TestRevokerNotElected tests that a revoker that is not elected does not
+// issue revocations.
+func TestRevokerNotElected(t *testing.T) {
+	topoProvider := xtest.TopoProviderFromFile(t, "testdata/topology.json")
+	_, priv, err := scrypto.GenKeyPair(scrypto.Ed25519)
+	xtest.FailOnErr(t, err)
+	signer := createTestSigner(t, priv)
+	Convey("TestRevokerNotElected", t, func() {
+		mctrl := gomock.NewController(t)
+		defer mctrl.Finish()
+		// No calls are expected on the messenger and the inserter.
+		msgr := mock_infra.NewMockMessenger(mctrl)
+		revInserter := mock_ifstate.NewMockRevInserter(mctrl)
+		intfs := NewInterfaces(topoProvider.Get().IFInfoMap, Config{})
+		activateAll(intfs)
+		intfs.Get(101).lastActivate = time.Now().Add(-expireTime)
+		cfg := RevokerConf{
+			Intfs:        intfs,
+			Msgr:         msgr,
+			Signer:       signer,
+			TopoProvider: topoProvider,
+			RevInserter:  revInserter,
+			RevConfig: RevConfig{
+				RevTTL:     ttl,
+				RevOverlap: overlapTime,
+			},
+			Elector: testElector{leader: false},
+		}
+		revoker := cfg.New()
+		ctx, cancelF := context.WithTimeout(context.Background(), timeout)
+		defer cancelF()
+		revoker.Run(ctx)
+		SoMsg("No revocation", intfs.Get(101).Revocation(), ShouldBeNil)
+	})
+}
+
 // TestRevokedInterfaceNotRevokedImmediately tests that if an interface was revoked recently it
 // shouldn't be revoked again.
 func TestRevokedInterfaceNotRevokedImmediately(t *testing.T) {
@@ -364,3 +400,16 @@ func (v revVerifier) Verify(_ context.Context, msg common.RawBytes, sign *proto.
 	return scrypto.Verify(sign.SigInput(msg, false), sign.Signature,
 		common.RawBytes(v), scrypto.Ed25519)
 }
+
+type testElector struct {
+	leader bool
+	peers  topology.IDAddrMap
+}
+
+func (e testElector) Leader() bool {
+	return e.leader
+}
+
+func (e testElector) Peers() topology.IDAddrMap {
+	return e.peers
+}
@@ -79,4 +79,6 @@ var (
 	Revocation = newRevocation()
 	// Registrar is the single-instance struct to get prometheus metrics or counters.
 	Registrar = newRegistrar()
+	// Store is the single-instance struct to get beacon store prometheus metrics.
+	Store = newStore()
 )
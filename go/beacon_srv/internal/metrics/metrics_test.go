@@ -32,8 +32,12 @@ func TestLabels(t *testing.T) {
 		metrics.BeaconingLabels{},
 		metrics.PropagatorLabels{},
 		metrics.RegistrarLabels{},
+		metrics.RemoteLabels{},
+		metrics.RemoteResultLabels{},
 		metrics.TypeOnlyLabel{},
 		metrics.OriginatorLabels{},
+		metrics.StoreOriginLabels{},
+		metrics.StoreGCLabels{},
 	}
 	for _, test := range tests {
 		promtest.CheckLabelsStruct(t, test)
@@ -55,8 +55,43 @@ func (l TypeOnlyLabel) Values() []string {
 	return []string{l.SegType}
 }
 
+// RemoteLabels define the labels attached to per-remote registration metrics.
+type RemoteLabels struct {
+	RemoteIA addr.IA
+	SegType  string
+}
+
+// Labels returns the name of the labels in correct order.
+func (l RemoteLabels) Labels() []string {
+	return []string{"remote_ia", "seg_type"}
+}
+
+// Values returns the values of the label in correct order.
+func (l RemoteLabels) Values() []string {
+	return []string{l.RemoteIA.String(), l.SegType}
+}
+
+// RemoteResultLabels define the labels attached to per-remote registration
+// results.
+type RemoteResultLabels struct {
+	RemoteLabels
+	Result string
+}
+
+// Labels returns the name of the labels in correct order.
+func (l RemoteResultLabels) Labels() []string {
+	return append(l.RemoteLabels.Labels(), prom.LabelResult)
+}
+
+// Values returns the values of the label in correct order.
+func (l RemoteResultLabels) Values() []string {
+	return append(l.RemoteLabels.Values(), l.Result)
+}
+
 type registrar struct {
 	registeredBeacons, runtime, internalErrors *prometheus.CounterVec
+	remoteRegistrations                        *prometheus.CounterVec
+	remoteFailures                             *prometheus.GaugeVec
 }
 
 func newRegistrar() registrar {
@@ -69,6 +104,12 @@ func newRegistrar() registrar {
 			"Registrar total time spent on every periodic run", TypeOnlyLabel{}.Labels()),
 		internalErrors: prom.NewCounterVec(ns, sub, "registrar_errors_total",
 			"Registrar total internal errors", TypeOnlyLabel{}.Labels()),
+		remoteRegistrations: prom.NewCounterVec(ns, sub, "registrar_remote_registrations_total",
+			"Number of segment registrations sent to remote path servers",
+			RemoteResultLabels{}.Labels()),
+		remoteFailures: prom.NewGaugeVec(ns, sub, "registrar_remote_consecutive_failures",
+			"Number of consecutive failed registrations with the remote path servers",
+			RemoteLabels{}.Labels()),
 	}
 }
 
@@ -85,3 +126,14 @@ func (e *registrar) InternalErrorsWithType(s string) prometheus.Counter {
 	l := TypeOnlyLabel{SegType: s}
 	return e.internalErrors.WithLabelValues(l.Values()...)
 }
+
+// RemoteRegistrations returns the counter of registrations sent to a remote.
+func (e *registrar) RemoteRegistrations(l RemoteResultLabels) prometheus.Counter {
+	return e.remoteRegistrations.WithLabelValues(l.Values()...)
+}
+
+// RemoteFailures returns the gauge of consecutive failed registrations with a
+// remote.
+func (e *registrar) RemoteFailures(l RemoteLabels) prometheus.Gauge {
+	return e.remoteFailures.WithLabelValues(l.Values()...)
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/prom"
+)
+
+// StoreOriginLabels define the labels attached to the per-origin beacon
+// store metrics.
+type StoreOriginLabels struct {
+	OriginIA addr.IA
+}
+
+// Labels returns the list of labels.
+func (l StoreOriginLabels) Labels() []string {
+	return []string{"origin_ia"}
+}
+
+// Values returns the label values in the order defined by Labels.
+func (l StoreOriginLabels) Values() []string {
+	return []string{l.OriginIA.String()}
+}
+
+// StoreGCLabels define the labels attached to the beacon store garbage
+// collection metrics.
+type StoreGCLabels struct {
+	Result string
+}
+
+// Labels returns the list of labels.
+func (l StoreGCLabels) Labels() []string {
+	return []string{prom.LabelResult}
+}
+
+// Values returns the label values in the order defined by Labels.
+func (l StoreGCLabels) Values() []string {
+	return []string{l.Result}
+}
+
+type store struct {
+	beacons, maxBeacons prometheus.Gauge
+	originBeacons       prometheus.GaugeVec
+	evicted             prometheus.Counter
+	runs                prometheus.CounterVec
+}
+
+func newStore() store {
+	sub := "beaconstore"
+	return store{
+		beacons: prom.NewGauge(Namespace, sub, "beacons",
+			"Number of beacons in the beacon store."),
+		maxBeacons: prom.NewGauge(Namespace, sub, "max_beacons",
+			"Maximum number of beacons in the beacon store, 0 if unlimited."),
+		originBeacons: *prom.NewGaugeVec(Namespace, sub, "origin_beacons",
+			"Number of beacons in the beacon store per origin AS.",
+			StoreOriginLabels{}.Labels()),
+		evicted: prom.NewCounter(Namespace, sub, "evicted_beacons_total",
+			"Total number of beacons evicted because the store exceeded its size limit."),
+		runs: *prom.NewCounterVec(Namespace, sub, "gc_runs_total",
+			"Total number of beacon store garbage collection runs.",
+			StoreGCLabels{}.Labels()),
+	}
+}
+
+// SetBeaconCounts sets the beacon occupancy gauges from the per-origin beacon
+// counts. Origins that are not present in counts are removed.
+func (e *store) SetBeaconCounts(counts map[addr.IA]int) {
+	total := 0
+	e.originBeacons.Reset()
+	for origin, count := range counts {
+		e.originBeacons.WithLabelValues(StoreOriginLabels{OriginIA: origin}.Values()...).
+			Set(float64(count))
+		total += count
+	}
+	e.beacons.Set(float64(total))
+}
+
+// MaxBeacons returns the gauge for the configured beacon store size limit.
+func (e *store) MaxBeacons() prometheus.Gauge {
+	return e.maxBeacons
+}
+
+// Evicted returns the counter for evicted beacons.
+func (e *store) Evicted() prometheus.Counter {
+	return e.evicted
+}
+
+// Runs returns the counter for garbage collection runs.
+func (e *store) Runs(l StoreGCLabels) prometheus.Counter {
+	return e.runs.WithLabelValues(l.Values()...)
+}
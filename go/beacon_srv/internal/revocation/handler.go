@@ -19,7 +19,9 @@ import (
 	"context"
 	"time"
 
+	"github.com/scionproto/scion/go/beacon_srv/internal/ifstate"
 	"github.com/scionproto/scion/go/beacon_srv/internal/metrics"
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
 	"github.com/scionproto/scion/go/lib/infra"
@@ -34,14 +36,24 @@ type Store interface {
 }
 
 type handler struct {
+	ia       addr.IA
+	intfs    *ifstate.Interfaces
 	verifier infra.Verifier
 	revStore Store
 	timeout  time.Duration
 }
 
-// NewHandler returns an infra.Handler for revocation.
-func NewHandler(revStore Store, verifier infra.Verifier, timeout time.Duration) infra.Handler {
+// NewHandler returns an infra.Handler for revocation. Revocations of local
+// interfaces, i.e., revocations issued by another beacon server instance of
+// the local AS ia, are applied to intfs. This allows the instance to renew
+// them, in case it is elected to issue revocations. If intfs is nil, they are
+// only stored.
+func NewHandler(ia addr.IA, intfs *ifstate.Interfaces, revStore Store,
+	verifier infra.Verifier, timeout time.Duration) infra.Handler {
+
 	return &handler{
+		ia:       ia,
+		intfs:    intfs,
 		verifier: verifier,
 		revStore: revStore,
 		timeout:  timeout,
@@ -89,8 +101,32 @@ func (h *handler) Handle(request *infra.Request) *infra.HandlerResult {
 		return ErrBeaconStore(err)
 
 	}
+	if h.intfs != nil && revInfo.IA().Equal(h.ia) {
+		h.applyLocal(subCtx, revInfo, revocation)
+	}
 	sendAck(proto.Ack_ErrCode_ok, "")
 	labels.Result = metrics.Success
 	metrics.Revocation.Receives(labels).Inc()
 	return infra.MetricsResultOk
 }
+
+// applyLocal applies a revocation of a local interface to the interface
+// state, unless the interface already has a more recent revocation.
+func (h *handler) applyLocal(ctx context.Context, revInfo *path_mgmt.RevInfo,
+	revocation *path_mgmt.SignedRevInfo) {
+
+	intf := h.intfs.Get(revInfo.IfID)
+	if intf == nil {
+		return
+	}
+	if cur := intf.Revocation(); cur != nil {
+		if curInfo, err := cur.RevInfo(); err == nil &&
+			curInfo.RawTimestamp >= revInfo.RawTimestamp {
+			return
+		}
+	}
+	if err := intf.Revoke(revocation); err != nil {
+		log.FromCtx(ctx).Debug("[RevHandler] Not applying revocation of local interface",
+			"ifid", revInfo.IfID, "err", err)
+	}
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 
+	"github.com/scionproto/scion/go/beacon_srv/internal/ifstate"
 	"github.com/scionproto/scion/go/beacon_srv/internal/revocation/mock_revocation"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/ctrl"
@@ -33,6 +34,7 @@ import (
 	"github.com/scionproto/scion/go/lib/infra/modules/trust"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/scrypto"
+	"github.com/scionproto/scion/go/lib/topology"
 	"github.com/scionproto/scion/go/lib/util"
 	"github.com/scionproto/scion/go/lib/xtest"
 	"github.com/scionproto/scion/go/lib/xtest/matchers"
@@ -125,11 +127,20 @@ func TestHandler(t *testing.T) {
 
 			serveCtx := infra.NewContextWithResponseWriter(context.Background(), rw)
 			req := infra.NewRequest(serveCtx, test.Rev, nil, nil, 0)
-			h := NewHandler(revStore, verifier, time.Second)
+			intfs := ifstate.NewInterfaces(topology.IfInfoMap{101: {}}, ifstate.Config{})
+			h := NewHandler(ia, intfs, revStore, verifier, time.Second)
 			res := h.Handle(req)
 			if res != test.Result {
 				t.Fatalf("Expected %v but was: %v", test.Result, res)
 			}
+			// Verified revocations of local interfaces are applied.
+			var expectedRev *path_mgmt.SignedRevInfo
+			if test.Result == infra.MetricsResultOk {
+				expectedRev = test.Rev
+			}
+			if rev := intfs.Get(101).Revocation(); rev != expectedRev {
+				t.Fatalf("Expected interface revocation %v but was: %v", expectedRev, rev)
+			}
 		})
 	}
 }
@@ -138,13 +138,16 @@ func realMain() int {
 		SVC:                   addr.SvcBS,
 		ReconnectToDispatcher: cfg.General.ReconnectToDispatcher,
 		QUIC: infraenv.QUIC{
-			Address:  cfg.QUIC.Address,
-			CertFile: cfg.QUIC.CertFile,
-			KeyFile:  cfg.QUIC.KeyFile,
+			Address:      cfg.QUIC.Address,
+			CertFile:     cfg.QUIC.CertFile,
+			KeyFile:      cfg.QUIC.KeyFile,
+			ClientCAFile: cfg.QUIC.ClientCAFile,
+			AuthTypes:    cfg.QUIC.AuthTypes,
 		},
 		SVCResolutionFraction: cfg.QUIC.ResolutionFraction,
 		TrustStore:            trustStore,
 		SVCRouter:             messenger.NewSVCRouter(itopo.Provider()),
+		CircuitBreaker:        infraenv.BreakerConfig(cfg.CircuitBreaker),
 	}
 	msgr, err := nc.Messenger()
 	if err != nil {
@@ -163,7 +166,7 @@ func realMain() int {
 	msgr.AddHandler(infra.ChainRequest, trustStore.NewChainReqHandler(false))
 	msgr.AddHandler(infra.TRCRequest, trustStore.NewTRCReqHandler(false))
 	msgr.AddHandler(infra.IfStateReq, ifstate.NewHandler(intfs))
-	msgr.AddHandler(infra.SignedRev, revocation.NewHandler(store,
+	msgr.AddHandler(infra.SignedRev, revocation.NewHandler(topo.ISD_AS, intfs, store,
 		trustStore.NewVerifier(), 5*time.Second))
 	msgr.AddHandler(infra.Seg, beaconing.NewHandler(topo.ISD_AS, intfs, store,
 		trustStore.NewVerifier()))
@@ -178,6 +181,14 @@ func realMain() int {
 		}),
 	)
 
+	env.AddHealthCheck("trustdb", func(ctx context.Context) error {
+		return trustdb.Ping(ctx, trustDB)
+	})
+	env.AddReadinessCheck("topology", env.TopologyCheck)
+	env.AddReadinessCheck("dispatcher", env.UnixSocketCheck(reliable.DefaultDispPath))
+	env.AddReadinessCheck("messenger", func(ctx context.Context) error {
+		return messenger.CheckServing(ctx, msgr)
+	})
 	cfg.Metrics.StartPrometheus()
 	go func() {
 		defer log.LogPanicAndExit()
@@ -273,9 +284,11 @@ type periodicTasks struct {
 	originator *periodic.Runner
 	propagator *periodic.Runner
 	revoker    *periodic.Runner
+	elector    *periodic.Runner
 	registrars segRegRunners
 
 	beaconCleaner *periodic.Runner
+	beaconGC      *periodic.Runner
 	revCleaner    *periodic.Runner
 
 	mtx     sync.Mutex
@@ -311,13 +324,22 @@ func (t *periodicTasks) Start() error {
 	if t.propagator, err = t.startPropagator(topoAddress); err != nil {
 		return err
 	}
-	t.beaconCleaner = periodic.StartPeriodicTask(
+	t.beaconCleaner = periodic.StartPeriodicTaskWithOptions(
 		beaconstorage.NewBeaconCleaner(t.store),
 		periodic.NewTicker(30*time.Second), 30*time.Second,
+		periodic.Options{Jitter: 3 * time.Second, MinBackoff: 30 * time.Second,
+			MaxBackoff: 5 * time.Minute},
 	)
-	t.revCleaner = periodic.StartPeriodicTask(
+	t.beaconGC = periodic.StartPeriodicTaskWithOptions(
+		beaconstorage.NewGC(t.store),
+		periodic.NewTicker(30*time.Second), 30*time.Second,
+		periodic.Options{Jitter: 3 * time.Second},
+	)
+	t.revCleaner = periodic.StartPeriodicTaskWithOptions(
 		beaconstorage.NewRevocationCleaner(t.store),
 		periodic.NewTicker(5*time.Second), 5*time.Second,
+		periodic.Options{Jitter: 500 * time.Millisecond, MinBackoff: 5 * time.Second,
+			MaxBackoff: 5 * time.Minute},
 	)
 	return nil
 }
@@ -328,6 +350,12 @@ func (t *periodicTasks) startRevoker() (*periodic.Runner, error) {
 	if err != nil {
 		return nil, err
 	}
+	// The elector sends heartbeats at the keepalive interval, and considers
+	// instances that miss them for the keepalive timeout as dead.
+	elector := ifstate.NewHeartbeatElector(cfg.General.ID, t.topoProvider, t.msgr,
+		cfg.BS.KeepaliveTimeout.Duration)
+	t.elector = periodic.StartPeriodicTask(elector,
+		periodic.NewTicker(cfg.BS.KeepaliveInterval.Duration), cfg.BS.KeepaliveInterval.Duration)
 	r := ifstate.RevokerConf{
 		Intfs:        t.intfs,
 		Msgr:         t.msgr,
@@ -338,6 +366,7 @@ func (t *periodicTasks) startRevoker() (*periodic.Runner, error) {
 			RevTTL:     cfg.BS.RevTTL.Duration,
 			RevOverlap: cfg.BS.RevOverlap.Duration,
 		},
+		Elector: elector,
 	}.New()
 	return periodic.StartPeriodicTask(r, periodic.NewTicker(cfg.BS.ExpiredCheckInterval.Duration),
 		cfg.BS.ExpiredCheckInterval.Duration), nil
@@ -492,13 +521,39 @@ func (t *periodicTasks) createSigner(topo *topology.Topo) (infra.Signer, error)
 	if err != nil {
 		return nil, common.NewBasicError("Unable to create sign meta", err)
 	}
-	signer, err := trust.NewBasicSigner(cfg.SignKey, meta)
+	signKey, err := signingKey(ctx, dir, topo.ISD_AS, meta, t.trustDB, cfg.SignKey)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := trust.NewBasicSigner(signKey, meta)
 	if err != nil {
 		return nil, common.NewBasicError("Unable to create signer", err)
 	}
 	return signer, nil
 }
 
+// signingKey returns the key in the signing key ring that belongs to the
+// certificate chain described by meta. During a key rollover, this is not
+// necessarily the newest key. If no key in the ring matches, the fallback is
+// returned.
+func signingKey(ctx context.Context, dir string, ia addr.IA, meta infra.SignerMeta,
+	db trustdb.TrustDB, fallback common.RawBytes) (common.RawBytes, error) {
+
+	ring, err := keyconf.NewRing(dir, keyconf.PrivateKey, keyconf.ASSigningKey, ia)
+	if err != nil {
+		return nil, common.NewBasicError("Unable to load signing keys", err)
+	}
+	chain, err := db.GetChainVersion(ctx, ia, meta.Src.ChainVer)
+	if err != nil {
+		return nil, common.NewBasicError("Unable to get certificate chain", err)
+	}
+	key, err := ring.Matching(time.Now(), chain.Leaf.SubjectSignKey)
+	if err != nil {
+		return fallback, nil
+	}
+	return key.SigningKey()
+}
+
 func (t *periodicTasks) Kill() {
 	t.mtx.Lock()
 	defer t.mtx.Unlock()
@@ -508,10 +563,12 @@ func (t *periodicTasks) Kill() {
 	}
 	t.registrars.Kill()
 	t.revoker.Kill()
+	t.elector.Kill()
 	t.keepalive.Kill()
 	t.originator.Kill()
 	t.propagator.Kill()
 	t.beaconCleaner.Kill()
+	t.beaconGC.Kill()
 	t.revCleaner.Kill()
 	t.running = false
 }
@@ -552,7 +609,8 @@ func setup() error {
 	}
 	clbks := itopo.Callbacks{UpdateStatic: handleTopoUpdate}
 	itopo.Init(cfg.General.ID, proto.ServiceType_bs, clbks)
-	topo, err := topology.LoadFromFile(cfg.General.Topology)
+	topo, err := idiscovery.LoadTopology(cfg.General.Topology, cfg.Discovery,
+		discovery.Full, nil)
 	if err != nil {
 		return common.NewBasicError("Unable to load topology", err)
 	}
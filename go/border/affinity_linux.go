@@ -0,0 +1,41 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/scionproto/scion/go/lib/common"
+)
+
+// cpuSetWords is the number of words in the CPU mask passed to the kernel. It
+// covers the default maximum of 1024 CPUs.
+const cpuSetWords = 1024 / 64
+
+// setAffinity pins the calling OS thread to the CPU core.
+func setAffinity(cpu int) error {
+	if cpu >= cpuSetWords*64 {
+		return common.NewBasicError("CPU out of range", nil, "cpu", cpu, "max", cpuSetWords*64-1)
+	}
+	var mask [cpuSetWords]uint64
+	mask[cpu/64] |= 1 << uint(cpu%64)
+	_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_SETAFFINITY, 0,
+		uintptr(unsafe.Sizeof(mask)), uintptr(unsafe.Pointer(&mask)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
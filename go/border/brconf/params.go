@@ -16,11 +16,13 @@ package brconf
 
 import (
 	"io"
+	"path/filepath"
 
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/config"
 	"github.com/scionproto/scion/go/lib/env"
 	"github.com/scionproto/scion/go/lib/infra/modules/idiscovery"
+	"github.com/scionproto/scion/go/lib/keyconf"
 )
 
 var _ config.Config = (*Config)(nil)
@@ -72,6 +74,23 @@ func (cfg *Config) ConfigName() string {
 	return "br_config"
 }
 
+// CheckFiles checks that the topology and the master keys referenced by the
+// config can be loaded.
+func (cfg *Config) CheckFiles() error {
+	if err := env.CheckFilesAll(&cfg.General); err != nil {
+		return err
+	}
+	dir := filepath.Join(cfg.General.ConfigDir, "keys")
+	if _, err := keyconf.LoadMaster(dir); err != nil {
+		return common.NewBasicError("Unable to load master keys", err, "dir", dir)
+	}
+	return nil
+}
+
+// DefaultWorkers is the default number of packet processing goroutines per
+// socket.
+const DefaultWorkers = 1
+
 var _ config.Config = (*BR)(nil)
 
 // BR contains the border router specific parts of the configuration.
@@ -81,16 +100,50 @@ type BR struct {
 	// RollbackFailAction indicates the action that should be taken
 	// if the rollback fails.
 	RollbackFailAction FailAction
+	// Workers is the number of goroutines processing the packets read from
+	// each socket. Packets are steered to the workers by a hash over their
+	// address header, such that packets of the same flow stay in order.
+	Workers int
+	// CPUs is the list of CPU cores the packet processing goroutines are
+	// pinned to. The cores are assigned round-robin. If empty, no pinning is
+	// done.
+	CPUs []int
+	// CaptureDir is the directory in which the packet captures started via
+	// the /capture admin endpoint are created. If empty, packet capture is
+	// disabled.
+	CaptureDir string
+	// DisableTraceRoute is the list of interfaces for which SCMP traceroute
+	// requests are not answered.
+	DisableTraceRoute []common.IFIDType
 }
 
 func (cfg *BR) InitDefaults() {
 	if cfg.RollbackFailAction != FailActionContinue {
 		cfg.RollbackFailAction = FailActionFatal
 	}
+	if cfg.Workers == 0 {
+		cfg.Workers = DefaultWorkers
+	}
 }
 
 func (cfg *BR) Validate() error {
-	return cfg.RollbackFailAction.Validate()
+	if err := cfg.RollbackFailAction.Validate(); err != nil {
+		return err
+	}
+	if cfg.Workers < 1 {
+		return common.NewBasicError("Workers must be positive", nil, "workers", cfg.Workers)
+	}
+	seen := make(map[int]struct{}, len(cfg.CPUs))
+	for _, cpu := range cfg.CPUs {
+		if cpu < 0 {
+			return common.NewBasicError("CPU must not be negative", nil, "cpu", cpu)
+		}
+		if _, ok := seen[cpu]; ok {
+			return common.NewBasicError("Duplicate CPU", nil, "cpu", cpu)
+		}
+		seen[cpu] = struct{}{}
+	}
+	return nil
 }
 
 func (cfg *BR) Sample(dst io.Writer, path config.Path, _ config.CtxMap) {
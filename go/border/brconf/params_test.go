@@ -21,6 +21,7 @@ import (
 	"github.com/BurntSushi/toml"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/env/envtest"
 	"github.com/scionproto/scion/go/lib/infra/modules/idiscovery/idiscoverytest"
 )
@@ -49,6 +50,10 @@ func InitTestDiscoveryConfig(cfg *Discovery) {
 
 func InitTestBRConfig(cfg *BR) {
 	cfg.Profile = true
+	cfg.Workers = 8
+	cfg.CPUs = []int{1, 2}
+	cfg.CaptureDir = "/tmp"
+	cfg.DisableTraceRoute = []common.IFIDType{1}
 }
 
 func CheckTestConfig(t *testing.T, cfg *Config, id string) {
@@ -65,4 +70,47 @@ func CheckTestDiscoveryConfig(t *testing.T, cfg *Discovery) {
 func CheckTestBRConfig(t *testing.T, cfg *BR) {
 	assert.False(t, cfg.Profile)
 	assert.Equal(t, FailActionFatal, cfg.RollbackFailAction)
+	assert.Equal(t, DefaultWorkers, cfg.Workers)
+	assert.Empty(t, cfg.CPUs)
+	assert.Empty(t, cfg.CaptureDir)
+	assert.Empty(t, cfg.DisableTraceRoute)
+}
+
+func TestBRValidate(t *testing.T) {
+	tests := map[string]struct {
+		Workers   int
+		CPUs      []int
+		Assertion assert.ErrorAssertionFunc
+	}{
+		"default": {
+			Workers:   DefaultWorkers,
+			Assertion: assert.NoError,
+		},
+		"pinned workers": {
+			Workers:   4,
+			CPUs:      []int{0, 2, 3},
+			Assertion: assert.NoError,
+		},
+		"no workers": {
+			Workers:   -1,
+			Assertion: assert.Error,
+		},
+		"negative cpu": {
+			Workers:   1,
+			CPUs:      []int{-1},
+			Assertion: assert.Error,
+		},
+		"duplicate cpu": {
+			Workers:   2,
+			CPUs:      []int{1, 1},
+			Assertion: assert.Error,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := BR{Workers: test.Workers, CPUs: test.CPUs}
+			cfg.InitDefaults()
+			test.Assertion(t, cfg.Validate())
+		})
+	}
 }
@@ -23,6 +23,25 @@ Profile = false
 # Action that should be taken when an error occurs during a context rollback.
 # (Fatal | Continue) (default Fatal)
 RollbackFailAction = "Fatal"
+
+# Number of goroutines processing the packets read from each socket. Packets
+# are steered to the workers based on a hash over their addresses, such that
+# the packets of a flow are processed in order. (default 1)
+Workers = 1
+
+# CPU cores the packet processing goroutines are pinned to. The cores are
+# assigned round-robin. Only supported on Linux. (default [], no pinning)
+CPUs = []
+
+# Directory in which the packet captures started via the /capture admin endpoint
+# (see metrics.Admin) are created. If empty, packet capture is disabled.
+# (default "")
+CaptureDir = ""
+
+# Interfaces for which SCMP traceroute requests are not answered. The requests
+# are forwarded instead, such that the interfaces are not revealed.
+# (default [])
+DisableTraceRoute = []
 `
 
 const discoverySample = `
@@ -95,6 +95,11 @@ func (r *Router) doPktError(rp *rpkt.RtrPkt, perr error) {
 			}
 		}
 	}
+	// Identical revocations are not sent to the same source at line rate.
+	if serr.CT.Class == scmp.C_Path && serr.CT.Type == scmp.T_P_RevokedIF &&
+		r.suppressSCMPRevocation(rp, serr.Info) {
+		return
+	}
 	reply, err := r.createSCMPErrorReply(rp, serr.CT, serr.Info)
 	if err != nil {
 		rp.Error("Error creating SCMP response", "err", err)
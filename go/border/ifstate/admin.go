@@ -0,0 +1,78 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ifstate
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/scionproto/scion/go/border/internal/metrics"
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/log"
+)
+
+var (
+	// adminMtx serializes changes to adminDown.
+	adminMtx sync.Mutex
+	// adminDown holds the set of administratively disabled interfaces as a
+	// map[common.IFIDType]struct{}. The map is replaced on every change, such
+	// that the packet processing path can read it without locking.
+	adminDown atomic.Value
+)
+
+func init() {
+	adminDown.Store(map[common.IFIDType]struct{}{})
+}
+
+// SetAdminDown administratively disables (down is true) or enables the
+// interface. The router drops all packets received from or sent to a disabled
+// interface, including the IFID keepalives. The beacon services on both sides
+// of the link thus see the interface time out and issue revocations for it.
+// The return value indicates whether the state changed.
+func SetAdminDown(ifID common.IFIDType, ia addr.IA, down bool) bool {
+	adminMtx.Lock()
+	defer adminMtx.Unlock()
+	old := adminDown.Load().(map[common.IFIDType]struct{})
+	if _, ok := old[ifID]; ok == down {
+		return false
+	}
+	m := make(map[common.IFIDType]struct{}, len(old)+1)
+	for id := range old {
+		m[id] = struct{}{}
+	}
+	var val float64
+	if down {
+		m[ifID] = struct{}{}
+		val = 1
+	} else {
+		delete(m, ifID)
+	}
+	adminDown.Store(m)
+	label := metrics.IntfLabels{Intf: metrics.IntfToLabel(ifID), NeighIA: ia.String()}
+	metrics.Control.IFAdminDown(label).Set(val)
+	log.Info("IFState: intf admin state changed", "ifid", ifID, "down", down)
+	return true
+}
+
+// AdminDown returns whether the interface is administratively disabled.
+func AdminDown(ifID common.IFIDType) bool {
+	m := adminDown.Load().(map[common.IFIDType]struct{})
+	if len(m) == 0 {
+		return false
+	}
+	_, ok := m[ifID]
+	return ok
+}
@@ -0,0 +1,33 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ifstate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestSetAdminDown(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	assert.True(t, SetAdminDown(5, ia, true))
+	assert.False(t, SetAdminDown(5, ia, true))
+	assert.True(t, AdminDown(5))
+	assert.True(t, SetAdminDown(5, ia, false))
+	assert.False(t, SetAdminDown(5, ia, false))
+	assert.False(t, AdminDown(5))
+}
@@ -0,0 +1,105 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ifstate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/scionproto/scion/go/border/rctx"
+	"github.com/scionproto/scion/go/lib/common"
+)
+
+// Status is the state of an interface as reported by the admin handler.
+type Status struct {
+	IfID common.IFIDType
+	// NeighIA is the ISD-AS of the neighbor.
+	NeighIA string
+	// Active is the state reported by the beacon service.
+	Active bool
+	// AdminDown indicates that the interface is administratively disabled.
+	AdminDown bool
+}
+
+// NewAdminHandler returns an HTTP handler to administratively disable and
+// enable the interfaces of the router.
+//
+// GET returns the status of all interfaces as JSON.
+//
+// POST sets the admin state of the interface given by the ifid query parameter
+// to the value of the state query parameter, either up or down, and returns
+// the status of the interface.
+func NewAdminHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := rctx.Get()
+		switch r.Method {
+		case http.MethodGet:
+			var statuses []Status
+			for _, ifID := range ctx.Conf.BR.IFIDs {
+				statuses = append(statuses, status(ctx, ifID))
+			}
+			writeJSON(w, statuses)
+		case http.MethodPost:
+			q := r.URL.Query()
+			id, err := strconv.ParseUint(q.Get("ifid"), 10, 64)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid ifid: %s", err), http.StatusBadRequest)
+				return
+			}
+			ifID := common.IFIDType(id)
+			intf, ok := ctx.Conf.BR.IFs[ifID]
+			if !ok {
+				http.Error(w, "unknown interface", http.StatusNotFound)
+				return
+			}
+			var down bool
+			switch q.Get("state") {
+			case "up":
+			case "down":
+				down = true
+			default:
+				http.Error(w, "state must be up or down", http.StatusBadRequest)
+				return
+			}
+			SetAdminDown(ifID, intf.ISD_AS, down)
+			writeJSON(w, status(ctx, ifID))
+		default:
+			http.Error(w, "only GET and POST are supported", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+func status(ctx *rctx.Ctx, ifID common.IFIDType) Status {
+	s := Status{
+		IfID:      ifID,
+		NeighIA:   ctx.Conf.BR.IFs[ifID].ISD_AS.String(),
+		AdminDown: AdminDown(ifID),
+	}
+	// Interfaces without state info are considered active by the router.
+	s.Active = true
+	if info, ok := LoadState(ifID); ok {
+		s.Active = info.Active
+	}
+	return s
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	enc.Encode(v)
+}
@@ -0,0 +1,78 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ifstate
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/border/brconf"
+	"github.com/scionproto/scion/go/border/rctx"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/topology"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestAdminHandler(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	rctx.Set(rctx.New(&brconf.BRConf{
+		BR: &topology.BRInfo{
+			IFIDs: []common.IFIDType{1, 2},
+			IFs: map[common.IFIDType]*topology.IFInfo{
+				1: {Id: 1, ISD_AS: ia},
+				2: {Id: 2, ISD_AS: ia},
+			},
+		},
+	}))
+	h := NewAdminHandler()
+	do := func(method, query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, "/interfaces?"+query, nil))
+		return w
+	}
+	t.Run("Disable and enable", func(t *testing.T) {
+		w := do(http.MethodPost, "ifid=2&state=down")
+		require.Equal(t, http.StatusOK, w.Code)
+		var st Status
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &st))
+		assert.Equal(t, Status{IfID: 2, NeighIA: ia.String(), Active: true, AdminDown: true}, st)
+		assert.True(t, AdminDown(2))
+		assert.False(t, AdminDown(1))
+
+		w = do(http.MethodGet, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var sts []Status
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &sts))
+		require.Len(t, sts, 2)
+		assert.False(t, sts[0].AdminDown)
+		assert.True(t, sts[1].AdminDown)
+
+		w = do(http.MethodPost, "ifid=2&state=up")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.False(t, AdminDown(2))
+	})
+	t.Run("Invalid requests", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "ifid=x&state=down").Code)
+		assert.Equal(t, http.StatusNotFound, do(http.MethodPost, "ifid=3&state=down").Code)
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "ifid=1&state=off").Code)
+		assert.Equal(t, http.StatusMethodNotAllowed, do(http.MethodDelete, "").Code)
+		assert.False(t, AdminDown(1))
+	})
+}
@@ -0,0 +1,367 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package capture implements a packet capture tap for the border router.
+//
+// A capture session mirrors the packets received by the router that match a
+// filter to an output, e.g., a file or a local socket, in the pcap format.
+// The SCION packets are encapsulated in synthesized IP/UDP headers that
+// correspond to the overlay addresses the packets were received with. This
+// allows inspecting the captures with the SCION wireshark plugin.
+//
+// Sessions are bounded in duration and optionally in the number of captured
+// packets. At most one session is active at a time.
+package capture
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/scmp"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+const (
+	// DefaultDuration is the default duration of a capture session.
+	DefaultDuration = 10 * time.Second
+	// MaxDuration is the maximum duration of a capture session.
+	MaxDuration = 10 * time.Minute
+	// queueLen is the number of captured packets that can be queued for
+	// writing. Packets captured while the queue is full are dropped.
+	queueLen = 1024
+)
+
+var (
+	// ErrSessionActive indicates that a capture session is already active.
+	ErrSessionActive = serrors.New("capture session already active")
+	// ErrInvalidDuration indicates that the session duration is out of range.
+	ErrInvalidDuration = serrors.New("invalid capture duration")
+)
+
+// Packet contains the information about a received packet that is used for
+// filtering and capturing.
+type Packet struct {
+	// Time is the time the packet was received.
+	Time time.Time
+	// IfID is the interface the packet was received on. It is zero for the
+	// local socket.
+	IfID common.IFIDType
+	// Src and Dst are the overlay addresses the packet was received with.
+	Src, Dst *net.UDPAddr
+	// SrcIA and DstIA are the source and destination ISD-AS of the packet.
+	SrcIA, DstIA addr.IA
+	// SCMP indicates whether the packet is an SCMP packet. SCMPClass is only
+	// valid if set.
+	SCMP      bool
+	SCMPClass scmp.Class
+	// Raw is the raw SCION packet. It is copied when the packet is captured.
+	Raw common.RawBytes
+}
+
+// Filter selects the captured packets. Unset fields match all packets.
+type Filter struct {
+	// IfID is the interface the packet was received on.
+	IfID *common.IFIDType
+	// SrcIA is the source ISD-AS of the packet.
+	SrcIA addr.IA
+	// DstIA is the destination ISD-AS of the packet.
+	DstIA addr.IA
+	// SCMPClass restricts the capture to SCMP packets of the class.
+	SCMPClass *scmp.Class
+}
+
+// Match returns whether the packet matches the filter.
+func (f Filter) Match(pkt *Packet) bool {
+	if f.IfID != nil && *f.IfID != pkt.IfID {
+		return false
+	}
+	if !f.SrcIA.IsZero() && !f.SrcIA.Equal(pkt.SrcIA) {
+		return false
+	}
+	if !f.DstIA.IsZero() && !f.DstIA.Equal(pkt.DstIA) {
+		return false
+	}
+	if f.SCMPClass != nil && (!pkt.SCMP || *f.SCMPClass != pkt.SCMPClass) {
+		return false
+	}
+	return true
+}
+
+func (f Filter) String() string {
+	var parts []string
+	if f.IfID != nil {
+		parts = append(parts, fmt.Sprintf("ifid=%d", *f.IfID))
+	}
+	if !f.SrcIA.IsZero() {
+		parts = append(parts, fmt.Sprintf("src=%s", f.SrcIA))
+	}
+	if !f.DstIA.IsZero() {
+		parts = append(parts, fmt.Sprintf("dst=%s", f.DstIA))
+	}
+	if f.SCMPClass != nil {
+		parts = append(parts, fmt.Sprintf("scmp_class=%s", f.SCMPClass))
+	}
+	if len(parts) == 0 {
+		return "any"
+	}
+	return strings.Join(parts, " ")
+}
+
+// Params are the parameters of a capture session.
+type Params struct {
+	// Filter selects the captured packets.
+	Filter Filter
+	// Duration is the duration of the session. If zero, DefaultDuration is
+	// used.
+	Duration time.Duration
+	// MaxPkts is the number of packets after which the session is stopped.
+	// Zero means unbounded.
+	MaxPkts uint64
+}
+
+// Status describes a capture session.
+type Status struct {
+	Active   bool
+	Output   string
+	Filter   string
+	Started  time.Time
+	Deadline time.Time
+	Captured uint64
+	Dropped  uint64
+}
+
+// Tap mirrors packets to the active capture session. The zero value is ready
+// to use.
+type Tap struct {
+	mtx     sync.Mutex
+	session *Session
+	// last is the most recently started session.
+	last *Session
+	// active holds the active *Session, and allows the packet processing to
+	// check for it without locking.
+	active atomic.Value
+}
+
+// Active returns the active capture session, or nil if there is none.
+func (t *Tap) Active() *Session {
+	s, _ := t.active.Load().(*Session)
+	return s
+}
+
+// Start starts a capture session that writes to out. The output name is only
+// used for reporting. The output is closed when the session stops.
+func (t *Tap) Start(p Params, out io.WriteCloser, name string) (*Session, error) {
+	if p.Duration == 0 {
+		p.Duration = DefaultDuration
+	}
+	if p.Duration < 0 || p.Duration > MaxDuration {
+		return nil, serrors.WithCtx(ErrInvalidDuration, "duration", p.Duration,
+			"max", MaxDuration)
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.session != nil {
+		return nil, ErrSessionActive
+	}
+	s := &Session{
+		Params:  p,
+		output:  name,
+		started: time.Now(),
+		tap:     t,
+		out:     out,
+		recs:    make(chan record, queueLen),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	s.deadline = s.started.Add(p.Duration)
+	s.timer = time.AfterFunc(p.Duration, func() { t.stop(s) })
+	go func() {
+		defer log.LogPanicAndExit()
+		s.run()
+	}()
+	t.session, t.last = s, s
+	t.active.Store(s)
+	log.Info("Packet capture started", "output", name, "filter", p.Filter,
+		"duration", p.Duration, "max_pkts", p.MaxPkts)
+	return s, nil
+}
+
+// Stop stops the active capture session, if any, and waits until all queued
+// packets are written.
+func (t *Tap) Stop() {
+	t.mtx.Lock()
+	s := t.session
+	t.mtx.Unlock()
+	if s != nil {
+		t.stop(s)
+		<-s.done
+	}
+}
+
+// Status returns the status of the active session, or of the most recently
+// stopped one if no session is active.
+func (t *Tap) Status() Status {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.last == nil {
+		return Status{}
+	}
+	st := t.last.status()
+	st.Active = t.session == t.last
+	return st
+}
+
+func (t *Tap) stop(s *Session) {
+	t.mtx.Lock()
+	if t.session == s {
+		t.session = nil
+		t.active.Store((*Session)(nil))
+	}
+	t.mtx.Unlock()
+	s.close()
+}
+
+// Session is a capture session.
+type Session struct {
+	Params
+	output   string
+	started  time.Time
+	deadline time.Time
+	tap      *Tap
+	timer    *time.Timer
+	out      io.WriteCloser
+	recs     chan record
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+	// matched, captured and dropped must be accessed atomically.
+	matched  uint64
+	captured uint64
+	dropped  uint64
+}
+
+type record struct {
+	time     time.Time
+	src, dst *net.UDPAddr
+	raw      common.RawBytes
+}
+
+// Capture queues the packet for writing, if it matches the session filter.
+// It never blocks; if the queue is full, the packet is dropped.
+func (s *Session) Capture(pkt *Packet) {
+	if !s.Filter.Match(pkt) {
+		return
+	}
+	if n := atomic.AddUint64(&s.matched, 1); s.MaxPkts > 0 && n >= s.MaxPkts {
+		if n > s.MaxPkts {
+			return
+		}
+		defer s.tap.stop(s)
+	}
+	rec := record{
+		time: pkt.Time,
+		src:  pkt.Src,
+		dst:  pkt.Dst,
+		raw:  append(common.RawBytes(nil), pkt.Raw...),
+	}
+	select {
+	case s.recs <- rec:
+		atomic.AddUint64(&s.captured, 1)
+	default:
+		atomic.AddUint64(&s.dropped, 1)
+	}
+}
+
+// Done returns a channel that is closed once the session is stopped and all
+// queued packets are written.
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Session) status() Status {
+	return Status{
+		Output:   s.output,
+		Filter:   s.Filter.String(),
+		Started:  s.started,
+		Deadline: s.deadline,
+		Captured: atomic.LoadUint64(&s.captured),
+		Dropped:  atomic.LoadUint64(&s.dropped),
+	}
+}
+
+func (s *Session) close() {
+	s.stopOnce.Do(func() {
+		s.timer.Stop()
+		close(s.stop)
+	})
+}
+
+func (s *Session) run() {
+	defer close(s.done)
+	defer func() {
+		st := s.status()
+		log.Info("Packet capture stopped", "output", s.output,
+			"captured", st.Captured, "dropped", st.Dropped)
+	}()
+	defer s.out.Close()
+	bw := bufio.NewWriter(s.out)
+	w, err := newPcapWriter(bw)
+	if err == nil {
+		err = s.write(w, bw)
+	}
+	if err == nil {
+		err = bw.Flush()
+	}
+	if err != nil {
+		log.Error("Packet capture failed", "output", s.output, "err", err)
+		s.tap.stop(s)
+	}
+}
+
+func (s *Session) write(w *pcapWriter, bw *bufio.Writer) error {
+	for {
+		select {
+		case rec := <-s.recs:
+			if err := w.WritePacket(rec.time, rec.src, rec.dst, rec.raw); err != nil {
+				return err
+			}
+			// Flush when idle, such that live consumers see the packets.
+			if len(s.recs) == 0 {
+				if err := bw.Flush(); err != nil {
+					return err
+				}
+			}
+		case <-s.stop:
+			for {
+				select {
+				case rec := <-s.recs:
+					if err := w.WritePacket(rec.time, rec.src, rec.dst, rec.raw); err != nil {
+						return err
+					}
+				default:
+					return nil
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package capture
+
+import (
+	"bytes"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/scmp"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestFilterMatch(t *testing.T) {
+	ia110 := xtest.MustParseIA("1-ff00:0:110")
+	ia111 := xtest.MustParseIA("1-ff00:0:111")
+	ifid := common.IFIDType(1)
+	local := common.IFIDType(0)
+	routing := scmp.C_Routing
+	pkt := &Packet{IfID: 1, SrcIA: ia110, DstIA: ia111, SCMP: true, SCMPClass: scmp.C_Routing}
+	tests := map[string]struct {
+		Filter Filter
+		Packet *Packet
+		Match  bool
+	}{
+		"empty filter": {
+			Packet: pkt,
+			Match:  true,
+		},
+		"all fields": {
+			Filter: Filter{IfID: &ifid, SrcIA: ia110, DstIA: ia111, SCMPClass: &routing},
+			Packet: pkt,
+			Match:  true,
+		},
+		"interface mismatch": {
+			Filter: Filter{IfID: &local},
+			Packet: pkt,
+		},
+		"src mismatch": {
+			Filter: Filter{SrcIA: ia111},
+			Packet: pkt,
+		},
+		"dst mismatch": {
+			Filter: Filter{DstIA: ia110},
+			Packet: pkt,
+		},
+		"scmp class mismatch": {
+			Filter: Filter{SCMPClass: &routing},
+			Packet: &Packet{SCMP: true, SCMPClass: scmp.C_Path},
+		},
+		"not scmp": {
+			Filter: Filter{SCMPClass: new(scmp.Class)},
+			Packet: &Packet{},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.Match, test.Filter.Match(test.Packet))
+		})
+	}
+}
+
+func TestTap(t *testing.T) {
+	ia110 := xtest.MustParseIA("1-ff00:0:110")
+	ia111 := xtest.MustParseIA("1-ff00:0:111")
+	match := &Packet{Time: time.Now(), SrcIA: ia110, Raw: common.RawBytes{1, 2, 3}}
+	other := &Packet{Time: time.Now(), SrcIA: ia111, Raw: common.RawBytes{4, 5, 6}}
+
+	t.Run("Session stops after count packets", func(t *testing.T) {
+		var tap Tap
+		out := &testOutput{}
+		s, err := tap.Start(Params{Filter: Filter{SrcIA: ia110}, MaxPkts: 2}, out, "test")
+		require.NoError(t, err)
+		assert.Equal(t, s, tap.Active())
+		s.Capture(other)
+		s.Capture(match)
+		s.Capture(match)
+		s.Capture(match)
+		<-s.Done()
+		assert.Nil(t, tap.Active())
+		st := tap.Status()
+		assert.False(t, st.Active)
+		assert.Equal(t, uint64(2), st.Captured)
+		assert.Equal(t, "src=1-ff00:0:110", st.Filter)
+		assert.True(t, out.Closed())
+		// Header and two records of 16+28+3 bytes each.
+		assert.Equal(t, 24+2*47, out.Len())
+	})
+	t.Run("Session stops after duration", func(t *testing.T) {
+		var tap Tap
+		s, err := tap.Start(Params{Duration: 10 * time.Millisecond}, &testOutput{}, "test")
+		require.NoError(t, err)
+		select {
+		case <-s.Done():
+		case <-time.After(time.Second):
+			t.Fatal("session not stopped")
+		}
+		assert.Nil(t, tap.Active())
+	})
+	t.Run("Only one session at a time", func(t *testing.T) {
+		var tap Tap
+		_, err := tap.Start(Params{}, &testOutput{}, "first")
+		require.NoError(t, err)
+		_, err = tap.Start(Params{}, &testOutput{}, "second")
+		assert.Equal(t, ErrSessionActive, err)
+		tap.Stop()
+		assert.Nil(t, tap.Active())
+		_, err = tap.Start(Params{}, &testOutput{}, "third")
+		assert.NoError(t, err)
+		tap.Stop()
+	})
+	t.Run("Invalid duration", func(t *testing.T) {
+		var tap Tap
+		_, err := tap.Start(Params{Duration: MaxDuration + 1}, &testOutput{}, "test")
+		assert.Error(t, err)
+		assert.Nil(t, tap.Active())
+	})
+}
+
+func TestPcapWriter(t *testing.T) {
+	tests := map[string]struct {
+		Src, Dst *net.UDPAddr
+		IPLen    int
+	}{
+		"IPv4": {
+			Src:   &net.UDPAddr{IP: net.IP{192, 0, 2, 1}, Port: 50000},
+			Dst:   &net.UDPAddr{IP: net.IP{192, 0, 2, 2}, Port: 50001},
+			IPLen: ipv4HdrLen,
+		},
+		"IPv6": {
+			Src:   &net.UDPAddr{IP: net.ParseIP("2001:db8::1"), Port: 50000},
+			Dst:   &net.UDPAddr{IP: net.ParseIP("2001:db8::2"), Port: 50001},
+			IPLen: ipv6HdrLen,
+		},
+		"no addresses": {
+			IPLen: ipv4HdrLen,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := newPcapWriter(&buf)
+			require.NoError(t, err)
+			pld := []byte{1, 2, 3, 4, 5}
+			ts := time.Unix(10, 2000)
+			require.NoError(t, w.WritePacket(ts, test.Src, test.Dst, pld))
+			b := buf.Bytes()
+			require.Len(t, b, 24+16+test.IPLen+udpHdrLen+len(pld))
+			assert.Equal(t, []byte{0xd4, 0xc3, 0xb2, 0xa1}, b[:4])
+			assert.Equal(t, []byte{linkTypeRaw, 0, 0, 0}, b[20:24])
+			rec := b[24:]
+			assert.Equal(t, []byte{10, 0, 0, 0, 2, 0, 0, 0}, rec[:8])
+			ip, udp := rec[16:16+test.IPLen], rec[16+test.IPLen:]
+			if test.IPLen == ipv4HdrLen {
+				assert.Equal(t, uint16(0xffff), fold(checksum(0, ip)), "IPv4 checksum")
+			}
+			// The one's complement sum over the pseudo header and the datagram,
+			// including the checksum, must be all ones.
+			addrs := ip[12:20]
+			if test.IPLen == ipv6HdrLen {
+				addrs = ip[8:40]
+			}
+			sum := checksum(0, addrs)
+			sum += protoUDP + uint32(len(udp))
+			assert.Equal(t, uint16(0xffff), fold(checksum(sum, udp)), "UDP checksum")
+			assert.Equal(t, pld, []byte(udp[udpHdrLen:]))
+		})
+	}
+}
+
+type testOutput struct {
+	mtx    sync.Mutex
+	buf    bytes.Buffer
+	closed bool
+}
+
+func (o *testOutput) Write(b []byte) (int, error) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	return o.buf.Write(b)
+}
+
+func (o *testOutput) Close() error {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.closed = true
+	return nil
+}
+
+func (o *testOutput) Len() int {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	return o.buf.Len()
+}
+
+func (o *testOutput) Closed() bool {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	return o.closed
+}
@@ -0,0 +1,189 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package capture
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/scmp"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+const dialTimeout = time.Second
+
+// NewHTTPHandler returns an HTTP handler to control the capture sessions of
+// the tap. The outputs are created in dir. If dir is empty, capture sessions
+// cannot be started.
+//
+// GET returns the status of the active or most recent session as JSON.
+//
+// POST starts a session. The output is either a new pcap file, given by the
+// file query parameter, or an existing unix socket that the pcap stream is
+// written to, given by the socket query parameter. Both are names relative to
+// dir. The optional ifid (0 for the local socket), src and dst (ISD-AS), and
+// scmp_class (name or number) parameters restrict the captured packets. The
+// optional duration (default 10s, max 10m) and count parameters bound the
+// session.
+//
+// DELETE stops the active session.
+func (t *Tap) NewHTTPHandler(dir string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeStatus(w, t.Status())
+		case http.MethodPost:
+			if dir == "" {
+				http.Error(w, "packet capture disabled", http.StatusForbidden)
+				return
+			}
+			q := r.URL.Query()
+			p, err := parseParams(q)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			out, name, err := openOutput(dir, q)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if _, err := t.Start(p, out, name); err != nil {
+				out.Close()
+				if q.Get("file") != "" {
+					os.Remove(name)
+				}
+				code := http.StatusBadRequest
+				if err == ErrSessionActive {
+					code = http.StatusConflict
+				}
+				http.Error(w, err.Error(), code)
+				return
+			}
+			writeStatus(w, t.Status())
+		case http.MethodDelete:
+			t.Stop()
+			writeStatus(w, t.Status())
+		default:
+			http.Error(w, "only GET, POST and DELETE are supported",
+				http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+func writeStatus(w http.ResponseWriter, st Status) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	enc.Encode(st)
+}
+
+func parseParams(q url.Values) (Params, error) {
+	var p Params
+	var err error
+	if v := q.Get("ifid"); v != "" {
+		ifid, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return p, serrors.WrapStr("invalid ifid", err)
+		}
+		p.Filter.IfID = new(common.IFIDType)
+		*p.Filter.IfID = common.IFIDType(ifid)
+	}
+	if v := q.Get("src"); v != "" {
+		if p.Filter.SrcIA, err = addr.IAFromString(v); err != nil {
+			return p, serrors.WrapStr("invalid src", err)
+		}
+	}
+	if v := q.Get("dst"); v != "" {
+		if p.Filter.DstIA, err = addr.IAFromString(v); err != nil {
+			return p, serrors.WrapStr("invalid dst", err)
+		}
+	}
+	if v := q.Get("scmp_class"); v != "" {
+		class, err := parseSCMPClass(v)
+		if err != nil {
+			return p, err
+		}
+		p.Filter.SCMPClass = &class
+	}
+	if v := q.Get("duration"); v != "" {
+		if p.Duration, err = time.ParseDuration(v); err != nil {
+			return p, serrors.WrapStr("invalid duration", err)
+		}
+		if p.Duration <= 0 {
+			return p, serrors.WithCtx(ErrInvalidDuration, "duration", p.Duration)
+		}
+	}
+	if v := q.Get("count"); v != "" {
+		if p.MaxPkts, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return p, serrors.WrapStr("invalid count", err)
+		}
+	}
+	return p, nil
+}
+
+// parseSCMPClass parses the SCMP class from its name, e.g. "ROUTING", or its
+// number.
+func parseSCMPClass(s string) (scmp.Class, error) {
+	if n, err := strconv.ParseUint(s, 10, 16); err == nil {
+		return scmp.Class(n), nil
+	}
+	for c := scmp.C_General; c <= scmp.C_Sibra; c++ {
+		name := c.String()
+		if strings.EqualFold(name[:strings.IndexByte(name, '(')], s) {
+			return c, nil
+		}
+	}
+	return 0, serrors.New("invalid scmp_class", "input", s)
+}
+
+// openOutput opens the output specified by the file or socket query
+// parameter.
+func openOutput(dir string, q url.Values) (io.WriteCloser, string, error) {
+	file, socket := q.Get("file"), q.Get("socket")
+	if (file == "") == (socket == "") {
+		return nil, "", serrors.New("exactly one of file and socket must be set")
+	}
+	name := file
+	if socket != "" {
+		name = socket
+	}
+	if filepath.Base(name) != name || name == "." || name == ".." {
+		return nil, "", serrors.New("output must be a plain name", "name", name)
+	}
+	path := filepath.Join(dir, name)
+	if socket != "" {
+		c, err := net.DialTimeout("unix", path, dialTimeout)
+		if err != nil {
+			return nil, "", serrors.WrapStr("unable to connect to socket", err)
+		}
+		return c, path, nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0640)
+	if err != nil {
+		return nil, "", serrors.WrapStr("unable to create file", err)
+	}
+	return f, path, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package capture
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPHandler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "capture")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	do := func(h http.Handler, method, query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, "/capture?"+query, nil))
+		return rec
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		var tap Tap
+		h := tap.NewHTTPHandler("")
+		assert.Equal(t, http.StatusForbidden, do(h, http.MethodPost, "file=a.pcap").Code)
+		assert.Equal(t, http.StatusOK, do(h, http.MethodGet, "").Code)
+	})
+	t.Run("Invalid parameters", func(t *testing.T) {
+		var tap Tap
+		h := tap.NewHTTPHandler(dir)
+		queries := []string{
+			"",
+			"file=a.pcap&socket=a.sock",
+			"file=../a.pcap",
+			"file=a.pcap&src=1-ff00",
+			"file=a.pcap&scmp_class=FOO",
+			"file=a.pcap&duration=-1s",
+			"file=a.pcap&duration=1h",
+		}
+		for _, q := range queries {
+			assert.Equal(t, http.StatusBadRequest, do(h, http.MethodPost, q).Code, q)
+		}
+		assert.Nil(t, tap.Active())
+		_, err := os.Stat(filepath.Join(dir, "a.pcap"))
+		assert.True(t, os.IsNotExist(err))
+	})
+	t.Run("Start and stop", func(t *testing.T) {
+		var tap Tap
+		h := tap.NewHTTPHandler(dir)
+		rec := do(h, http.MethodPost, "file=b.pcap&ifid=1&scmp_class=routing&duration=1m")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		s := tap.Active()
+		require.NotNil(t, s)
+		assert.Equal(t, "ifid=1 scmp_class=ROUTING(1)", s.Filter.String())
+		rec = do(h, http.MethodPost, "file=c.pcap")
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, http.StatusOK, do(h, http.MethodDelete, "").Code)
+		assert.Nil(t, tap.Active())
+		raw, err := ioutil.ReadFile(filepath.Join(dir, "b.pcap"))
+		require.NoError(t, err)
+		assert.Len(t, raw, 24)
+	})
+	t.Run("Existing file", func(t *testing.T) {
+		var tap Tap
+		h := tap.NewHTTPHandler(dir)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "d.pcap"), nil, 0640))
+		assert.Equal(t, http.StatusBadRequest, do(h, http.MethodPost, "file=d.pcap").Code)
+	})
+}
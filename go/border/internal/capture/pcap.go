@@ -0,0 +1,157 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package capture
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"time"
+)
+
+const (
+	pcapMagic        = 0xa1b2c3d4
+	pcapVersionMajor = 2
+	pcapVersionMinor = 4
+	pcapSnapLen      = 65535
+	// linkTypeRaw indicates that the records start with an IPv4 or IPv6 header.
+	linkTypeRaw = 101
+
+	ipv4HdrLen = 20
+	ipv6HdrLen = 40
+	udpHdrLen  = 8
+	protoUDP   = 17
+	defaultTTL = 64
+)
+
+// pcapWriter writes packets in the pcap format. The packets are encapsulated
+// in IP/UDP headers that are synthesized from the overlay addresses.
+type pcapWriter struct {
+	w   io.Writer
+	buf []byte
+}
+
+// newPcapWriter writes the pcap file header to w, and returns a writer for
+// the packet records.
+func newPcapWriter(w io.Writer) (*pcapWriter, error) {
+	hdr := make([]byte, 24)
+	binary.LittleEndian.PutUint32(hdr[0:], pcapMagic)
+	binary.LittleEndian.PutUint16(hdr[4:], pcapVersionMajor)
+	binary.LittleEndian.PutUint16(hdr[6:], pcapVersionMinor)
+	binary.LittleEndian.PutUint32(hdr[16:], pcapSnapLen)
+	binary.LittleEndian.PutUint32(hdr[20:], linkTypeRaw)
+	if _, err := w.Write(hdr); err != nil {
+		return nil, err
+	}
+	return &pcapWriter{w: w, buf: make([]byte, 16+ipv6HdrLen+udpHdrLen)}, nil
+}
+
+// WritePacket writes a record containing pld, sent from src to dst over UDP.
+// Missing addresses are replaced by the unspecified IPv4 address.
+func (pw *pcapWriter) WritePacket(ts time.Time, src, dst *net.UDPAddr, pld []byte) error {
+	srcIP, srcPort := udpAddrParts(src)
+	dstIP, dstPort := udpAddrParts(dst)
+	v4 := srcIP.To4() != nil && dstIP.To4() != nil
+	ipLen := ipv6HdrLen
+	if v4 {
+		ipLen = ipv4HdrLen
+	}
+	hdrLen := ipLen + udpHdrLen
+	origLen := hdrLen + len(pld)
+	if origLen > pcapSnapLen {
+		pld = pld[:pcapSnapLen-hdrLen]
+	}
+	rec := pw.buf[:16+hdrLen]
+	for i := range rec {
+		rec[i] = 0
+	}
+	binary.LittleEndian.PutUint32(rec[0:], uint32(ts.Unix()))
+	binary.LittleEndian.PutUint32(rec[4:], uint32(ts.Nanosecond()/1000))
+	binary.LittleEndian.PutUint32(rec[8:], uint32(hdrLen+len(pld)))
+	binary.LittleEndian.PutUint32(rec[12:], uint32(origLen))
+	ip, udp := rec[16:16+ipLen], rec[16+ipLen:]
+	udpLen := udpHdrLen + len(pld)
+	if v4 {
+		ip[0] = 0x45
+		binary.BigEndian.PutUint16(ip[2:], uint16(origLen))
+		ip[8] = defaultTTL
+		ip[9] = protoUDP
+		copy(ip[12:16], srcIP.To4())
+		copy(ip[16:20], dstIP.To4())
+		binary.BigEndian.PutUint16(ip[10:], ^fold(checksum(0, ip)))
+	} else {
+		ip[0] = 0x60
+		binary.BigEndian.PutUint16(ip[4:], uint16(udpLen))
+		ip[6] = protoUDP
+		ip[7] = defaultTTL
+		copy(ip[8:24], srcIP.To16())
+		copy(ip[24:40], dstIP.To16())
+	}
+	binary.BigEndian.PutUint16(udp[0:], srcPort)
+	binary.BigEndian.PutUint16(udp[2:], dstPort)
+	binary.BigEndian.PutUint16(udp[4:], uint16(udpLen))
+	binary.BigEndian.PutUint16(udp[6:], udpChecksum(ip, v4, udp, pld, udpLen))
+	if _, err := pw.w.Write(rec); err != nil {
+		return err
+	}
+	_, err := pw.w.Write(pld)
+	return err
+}
+
+func udpAddrParts(a *net.UDPAddr) (net.IP, uint16) {
+	if a == nil || a.IP == nil {
+		return net.IPv4zero, 0
+	}
+	return a.IP, uint16(a.Port)
+}
+
+// udpChecksum computes the UDP checksum including the pseudo header derived
+// from the IP header.
+func udpChecksum(ip []byte, v4 bool, udp, pld []byte, udpLen int) uint16 {
+	var sum uint32
+	if v4 {
+		sum = checksum(sum, ip[12:20])
+	} else {
+		sum = checksum(sum, ip[8:40])
+	}
+	sum += protoUDP + uint32(udpLen)
+	sum = checksum(sum, udp)
+	sum = checksum(sum, pld)
+	csum := ^fold(sum)
+	if csum == 0 {
+		// A zero checksum indicates that no checksum was computed.
+		csum = 0xffff
+	}
+	return csum
+}
+
+// checksum adds b to the one's complement sum, and returns the folded sum.
+// The buffer b must be of even length, except for the last call.
+func checksum(sum uint32, b []byte) uint32 {
+	for ; len(b) >= 2; b = b[2:] {
+		sum += uint32(b[0])<<8 | uint32(b[1])
+	}
+	if len(b) == 1 {
+		sum += uint32(b[0]) << 8
+	}
+	return uint32(fold(sum))
+}
+
+func fold(sum uint32) uint16 {
+	for sum > 0xffff {
+		sum = (sum >> 16) + (sum & 0xffff)
+	}
+	return uint16(sum)
+}
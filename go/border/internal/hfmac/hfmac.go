@@ -0,0 +1,135 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hfmac verifies hop field MACs on the forwarding fast path.
+//
+// The MAC of a hop field is the truncated AES-CMAC of a single input block,
+// see spath.HopField.CalcMac. For a single complete block, CMAC reduces to
+// one AES encryption of the block XORed with the first CMAC subkey. Verifier
+// exploits this to compute MACs without the hash.Hash overhead and without
+// allocations, and can verify the MACs of a batch of packets at once.
+//
+// A Verifier holds its own cipher context and scratch buffers and is not safe
+// for concurrent use. Each packet processing worker is expected to create its
+// own Verifier and reuse it for all packets.
+package hfmac
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/spath"
+)
+
+// Request is the input of a hop field MAC verification.
+type Request struct {
+	// HopF is the hop field whose MAC is verified.
+	HopF *spath.HopField
+	// TsInt is the timestamp of the info field of the hop field.
+	TsInt uint32
+	// Prev is the previous hop field, without the flags byte, or nil, see
+	// spath.HopField.CalcMac.
+	Prev common.RawBytes
+}
+
+// Verifier computes and verifies hop field MACs.
+type Verifier struct {
+	block cipher.Block
+	// k1 is the first CMAC subkey.
+	k1 [aes.BlockSize]byte
+	// buf holds a single MAC computation.
+	buf [aes.BlockSize]byte
+	// batch holds the blocks of a batch computation.
+	batch []byte
+}
+
+// New creates a verifier for the given hop field MAC key, see
+// scrypto.HFMacKey.
+func New(key []byte) (*Verifier, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, serrors.WrapStr("unable to initialize AES cipher", err)
+	}
+	v := &Verifier{block: block}
+	// See RFC 4493, section 2.3.
+	var l [aes.BlockSize]byte
+	block.Encrypt(l[:], l[:])
+	msb := l[0] >> 7
+	for i := 0; i < aes.BlockSize-1; i++ {
+		v.k1[i] = l[i]<<1 | l[i+1]>>7
+	}
+	v.k1[aes.BlockSize-1] = l[aes.BlockSize-1]<<1 ^ (0x87 * msb)
+	return v, nil
+}
+
+// Compute computes the MAC of the request and writes it to mac, which must be
+// at least spath.MacLen bytes long.
+func (v *Verifier) Compute(req Request, mac []byte) {
+	v.encrypt(v.buf[:], req)
+	copy(mac, v.buf[:spath.MacLen])
+}
+
+// Verify verifies the MAC of the request. It returns an error wrapping
+// spath.ErrorHopFBadMac if the MAC does not match.
+func (v *Verifier) Verify(req Request) error {
+	v.encrypt(v.buf[:], req)
+	return check(req.HopF, v.buf[:spath.MacLen])
+}
+
+// VerifyBatch verifies the MACs of all requests and stores the result of
+// reqs[i] in errs[i]. errs must be at least as long as reqs. The input blocks
+// of all requests are computed first, and then encrypted back to back, which
+// keeps the cipher context hot and amortizes the per-request overhead.
+func (v *Verifier) VerifyBatch(reqs []Request, errs []error) {
+	size := len(reqs) * aes.BlockSize
+	if cap(v.batch) < size {
+		v.batch = make([]byte, size)
+	}
+	blocks := v.batch[:size]
+	for i, req := range reqs {
+		v.input(blocks[i*aes.BlockSize:(i+1)*aes.BlockSize], req)
+	}
+	for i := 0; i < size; i += aes.BlockSize {
+		v.block.Encrypt(blocks[i:i+aes.BlockSize], blocks[i:i+aes.BlockSize])
+	}
+	for i, req := range reqs {
+		errs[i] = check(req.HopF, blocks[i*aes.BlockSize:i*aes.BlockSize+spath.MacLen])
+	}
+}
+
+// encrypt computes the full CMAC block of the request into b.
+func (v *Verifier) encrypt(b []byte, req Request) {
+	v.input(b, req)
+	v.block.Encrypt(b, b)
+}
+
+// input writes the MAC input block of the request, XORed with the first
+// subkey, to b.
+func (v *Verifier) input(b []byte, req Request) {
+	req.HopF.WriteMacInput(b, req.TsInt, req.Prev)
+	for i := range v.k1 {
+		b[i] ^= v.k1[i]
+	}
+}
+
+func check(hopF *spath.HopField, mac []byte) error {
+	if subtle.ConstantTimeCompare(hopF.Mac, mac) != 1 {
+		return serrors.WithCtx(spath.ErrorHopFBadMac, "expected",
+			append(common.RawBytes(nil), mac...), "actual", hopF.Mac)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hfmac
+
+import (
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/scrypto"
+	"github.com/scionproto/scion/go/lib/spath"
+)
+
+func TestNewSubkey(t *testing.T) {
+	// Test vector from RFC 4493, section 4.
+	key, err := hex.DecodeString("2b7e151628aed2a6abf7158809cf4f3c")
+	require.NoError(t, err)
+	v, err := New(key)
+	require.NoError(t, err)
+	assert.Equal(t, "fbeed618357133667c85e08f7236a8de", hex.EncodeToString(v.k1[:]))
+}
+
+func TestVerifierMatchesCalcMac(t *testing.T) {
+	key := scrypto.HFMacKey([]byte("master key"))
+	v, err := New(key)
+	require.NoError(t, err)
+	macH, err := scrypto.InitMac(key)
+	require.NoError(t, err)
+
+	for i, req := range testRequests(macH) {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			mac := make([]byte, spath.MacLen)
+			v.Compute(req, mac)
+			assert.Equal(t, []byte(req.HopF.Mac), mac)
+			assert.NoError(t, v.Verify(req))
+		})
+	}
+}
+
+func TestVerifyBatch(t *testing.T) {
+	key := scrypto.HFMacKey([]byte("master key"))
+	v, err := New(key)
+	require.NoError(t, err)
+	macH, err := scrypto.InitMac(key)
+	require.NoError(t, err)
+
+	reqs := testRequests(macH)
+	// Corrupt the MAC of every other request.
+	for i := 1; i < len(reqs); i += 2 {
+		reqs[i].HopF.Mac = common.RawBytes{0, 0, 0}
+	}
+	errs := make([]error, len(reqs))
+	v.VerifyBatch(reqs, errs)
+	for i, err := range errs {
+		if i%2 == 0 {
+			assert.NoError(t, err, "request %d", i)
+		} else {
+			assert.True(t, xerrors.Is(err, spath.ErrorHopFBadMac), "request %d: %v", i, err)
+		}
+	}
+	// Smaller batches reuse the buffer.
+	v.VerifyBatch(reqs[:1], errs)
+	assert.NoError(t, errs[0])
+}
+
+// testRequests returns requests with correct MACs, computed with macH.
+func testRequests(macH hash.Hash) []Request {
+	prev := common.RawBytes{1, 2, 3, 4, 5, 6, 7}
+	var reqs []Request
+	for i := 0; i < 8; i++ {
+		req := Request{
+			HopF: &spath.HopField{
+				ExpTime:     spath.ExpTimeType(i),
+				ConsIngress: common.IFIDType(i),
+				ConsEgress:  common.IFIDType(i + 1),
+			},
+			TsInt: uint32(1e9 + i),
+		}
+		if i%4 < 2 {
+			req.Prev = prev
+		}
+		req.HopF.Mac = req.HopF.CalcMac(macH, req.TsInt, req.Prev)
+		reqs = append(reqs, req)
+	}
+	return reqs
+}
+
+func BenchmarkCalcMac(b *testing.B) {
+	key := scrypto.HFMacKey([]byte("master key"))
+	macH, err := scrypto.InitMac(key)
+	require.NoError(b, err)
+	reqs := testRequests(macH)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req := reqs[i%len(reqs)]
+		if err := req.HopF.Verify(macH, req.TsInt, req.Prev); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	key := scrypto.HFMacKey([]byte("master key"))
+	macH, err := scrypto.InitMac(key)
+	require.NoError(b, err)
+	v, err := New(key)
+	require.NoError(b, err)
+	reqs := testRequests(macH)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := v.Verify(reqs[i%len(reqs)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkVerifyBatch(b *testing.B) {
+	key := scrypto.HFMacKey([]byte("master key"))
+	macH, err := scrypto.InitMac(key)
+	require.NoError(b, err)
+	v, err := New(key)
+	require.NoError(b, err)
+	for _, size := range []int{1, 8, 32, 64} {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			var reqs []Request
+			for len(reqs) < size {
+				reqs = append(reqs, testRequests(macH)...)
+			}
+			reqs = reqs[:size]
+			errs := make([]error, size)
+			b.ReportAllocs()
+			b.ResetTimer()
+			// Each iteration verifies a single packet, such that the results
+			// are comparable with the other benchmarks.
+			for i := 0; i < b.N; i += size {
+				v.VerifyBatch(reqs, errs)
+			}
+		})
+	}
+}
@@ -65,6 +65,7 @@ type control struct {
 	receivedIFStateInfo *prometheus.CounterVec
 	sentIFStateReq      *prometheus.CounterVec
 	ifstate             *prometheus.GaugeVec
+	ifAdminDown         *prometheus.GaugeVec
 	ifstateTick         prometheus.Counter
 	readRevInfos        *prometheus.CounterVec
 	sentRevInfos        *prometheus.CounterVec
@@ -85,6 +86,9 @@ func newControl() control {
 			ControlLabels{}.Labels()),
 		ifstate: prom.NewGaugeVec(Namespace, sub,
 			"interface_active", "Interface is active.", IntfLabels{}.Labels()),
+		ifAdminDown: prom.NewGaugeVec(Namespace, sub,
+			"interface_admin_down", "Interface is administratively disabled.",
+			IntfLabels{}.Labels()),
 		ifstateTick: prom.NewCounter(Namespace, sub,
 			"ifstate_ticks_total", "Total number of IFState requests ticks."),
 		readRevInfos: prom.NewCounterVec(Namespace, sub,
@@ -119,6 +123,11 @@ func (c *control) IFState(l IntfLabels) prometheus.Gauge {
 	return c.ifstate.WithLabelValues(l.Values()...)
 }
 
+// IFAdminDown returns the gauge for the given label set.
+func (c *control) IFAdminDown(l IntfLabels) prometheus.Gauge {
+	return c.ifAdminDown.WithLabelValues(l.Values()...)
+}
+
 // IFStateTick returns the counter for the given label set.
 func (c *control) IFStateTick() prometheus.Counter {
 	return c.ifstateTick
@@ -41,14 +41,19 @@ const (
 	ErrParsePayload = "err_parse_payload"
 	// ErrResolveSVC is an error resolving a SVC address.
 	ErrResolveSVC = "err_resolve_svc"
+	// ErrAdminDown is a packet dropped because its interface is
+	// administratively disabled.
+	ErrAdminDown = "err_admin_down"
 )
 
 // Metrics initialization.
 var (
-	Input   = newInput()
-	Output  = newOutput()
-	Process = newProcess()
-	Control = newControl()
+	Input       = newInput()
+	Output      = newOutput()
+	Process     = newProcess()
+	Control     = newControl()
+	SCMP        = newSCMP()
+	Revocations = newRevocation()
 )
 
 type IntfLabels struct {
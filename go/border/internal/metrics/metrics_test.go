@@ -26,4 +26,6 @@ func TestRegistrationsLabels(t *testing.T) {
 	promtest.CheckLabelsStruct(t, metrics.ControlLabels{})
 	promtest.CheckLabelsStruct(t, metrics.SentRevInfoLabels{})
 	promtest.CheckLabelsStruct(t, metrics.ProcessLabels{})
+	promtest.CheckLabelsStruct(t, metrics.SCMPLabels{})
+	promtest.CheckLabelsStruct(t, metrics.RevocationLabels{})
 }
@@ -0,0 +1,65 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/scionproto/scion/go/lib/prom"
+)
+
+// Revocation target values.
+const (
+	// RevTargetHost is the target of SCMP revocations that the router sends to
+	// end hosts.
+	RevTargetHost = "host"
+	// RevTargetCtrl is the target of revocations that the router forwards to
+	// the local control services.
+	RevTargetCtrl = "ctrl"
+)
+
+type RevocationLabels struct {
+	// Target is the target of the revocation, RevTargetHost or RevTargetCtrl.
+	Target string
+}
+
+// Labels returns the list of labels.
+func (l RevocationLabels) Labels() []string {
+	return []string{"target"}
+}
+
+// Values returns the label values in the order defined by Labels.
+func (l RevocationLabels) Values() []string {
+	return []string{l.Target}
+}
+
+type revocation struct {
+	suppressed *prometheus.CounterVec
+}
+
+func newRevocation() revocation {
+	sub := "revocation"
+	return revocation{
+		suppressed: prom.NewCounterVec(Namespace, sub,
+			"suppressed_total",
+			"Total number of duplicate revocations that were not sent again.",
+			RevocationLabels{}.Labels()),
+	}
+}
+
+// Suppressed returns the counter for the given label set.
+func (r *revocation) Suppressed(l RevocationLabels) prometheus.Counter {
+	return r.suppressed.WithLabelValues(l.Values()...)
+}
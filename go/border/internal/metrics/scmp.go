@@ -0,0 +1,86 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/scionproto/scion/go/lib/prom"
+)
+
+// SCMP message type values
+const (
+	SCMPTraceRoute  = "traceroute"
+	SCMPRecordPath  = "recordpath"
+	SCMPEchoRequest = "echo_request"
+	SCMPEchoReply   = "echo_reply"
+)
+
+// SCMP result values
+const (
+	// SCMPDisabled is the result for SCMP requests that are not answered,
+	// because answering them is disabled.
+	SCMPDisabled = "disabled"
+	// SCMPForwarded is the result for SCMP messages that are forwarded by the
+	// router without local processing, e.g., echo requests and replies.
+	SCMPForwarded = "forwarded"
+)
+
+type SCMPLabels struct {
+	// Type is the type of the SCMP message.
+	Type string
+	// Intf is the SCION interface the message was handled for.
+	Intf string
+	// Result is the outcome of handling the message.
+	Result string
+}
+
+// Labels returns the list of labels.
+func (l SCMPLabels) Labels() []string {
+	return []string{"type", "intf", "result"}
+}
+
+// Values returns the label values in the order defined by Labels.
+func (l SCMPLabels) Values() []string {
+	return []string{l.Type, l.Intf, l.Result}
+}
+
+type scmp struct {
+	msgs     *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func newSCMP() scmp {
+	sub := "scmp"
+	return scmp{
+		msgs: prom.NewCounterVec(Namespace, sub,
+			"msgs_total", "Total number of SCMP messages handled by the router.",
+			SCMPLabels{}.Labels()),
+		duration: prom.NewHistogramVec(Namespace, sub,
+			"duration_seconds",
+			"Time from receiving an SCMP message until it is handled.",
+			SCMPLabels{}.Labels(), prometheus.ExponentialBuckets(0.00001, 2, 14)),
+	}
+}
+
+// Msgs returns the counter for the given label set.
+func (s *scmp) Msgs(l SCMPLabels) prometheus.Counter {
+	return s.msgs.WithLabelValues(l.Values()...)
+}
+
+// Duration returns the histogram for the given label set.
+func (s *scmp) Duration(l SCMPLabels) prometheus.Observer {
+	return s.duration.WithLabelValues(l.Values()...)
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package revdedup suppresses duplicate revocations in the border router.
+//
+// While an interface is revoked, every packet routed over it triggers an
+// SCMP revocation to its source, and every revocation the router forwards
+// would be sent to the local control services again. With flapping
+// interfaces, this generates identical revocations at line rate. A Cache
+// remembers the revocations that were recently sent to a target, such that
+// the router sends each revocation at most once per interval to the same
+// target.
+package revdedup
+
+import (
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+)
+
+const (
+	// DefaultInterval is the default interval in which a revocation is sent
+	// at most once to the same target.
+	DefaultInterval = time.Second
+	// DefaultMaxEntries is the default maximum number of remembered
+	// revocations.
+	DefaultMaxEntries = 4096
+)
+
+// Key identifies a revocation sent to a target.
+type Key struct {
+	// IA is the ISD-AS of the revoked interface.
+	IA addr.IA
+	// IfID is the revoked interface.
+	IfID common.IFIDType
+	// Timestamp is the timestamp of the revocation. Renewed revocations are
+	// thus not suppressed.
+	Timestamp uint32
+	// Target identifies the receiver of the revocation, e.g., the end host an
+	// SCMP revocation is sent to.
+	Target string
+}
+
+// NewKey returns the key of the revocation sent to target.
+func NewKey(revInfo *path_mgmt.RevInfo, target string) Key {
+	return Key{
+		IA:        revInfo.IA(),
+		IfID:      revInfo.IfID,
+		Timestamp: revInfo.RawTimestamp,
+		Target:    target,
+	}
+}
+
+// Cache remembers recently sent revocations. It is safe for concurrent use.
+type Cache struct {
+	interval   time.Duration
+	maxEntries int
+
+	mu sync.Mutex
+	// expiries maps the remembered revocations to the time until which they
+	// are suppressed.
+	expiries map[Key]time.Time
+}
+
+// New creates a cache that suppresses a revocation for the given interval
+// after it was sent, and remembers at most maxEntries revocations. If the
+// cache is full, revocations that are not remembered are not suppressed.
+func New(interval time.Duration, maxEntries int) *Cache {
+	return &Cache{
+		interval:   interval,
+		maxEntries: maxEntries,
+		expiries:   make(map[Key]time.Time),
+	}
+}
+
+// Suppress returns true if the revocation was sent within the interval before
+// now, in which case it should not be sent again. Otherwise, the revocation
+// is remembered as sent at now, and false is returned.
+func (c *Cache) Suppress(k Key, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if expiry, ok := c.expiries[k]; ok && now.Before(expiry) {
+		return true
+	}
+	if len(c.expiries) >= c.maxEntries {
+		c.deleteExpired(now)
+	}
+	if len(c.expiries) < c.maxEntries {
+		c.expiries[k] = now.Add(c.interval)
+	}
+	return false
+}
+
+// deleteExpired removes the entries that are no longer suppressed. Must be
+// called with the lock held.
+func (c *Cache) deleteExpired(now time.Time) {
+	for k, expiry := range c.expiries {
+		if !now.Before(expiry) {
+			delete(c.expiries, k)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revdedup_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/border/internal/revdedup"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestCacheSuppress(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	key := revdedup.Key{IA: ia, IfID: 1, Timestamp: 10, Target: "1-ff00:0:111,[10.0.0.1]"}
+	now := time.Now()
+
+	t.Run("duplicates are suppressed within the interval", func(t *testing.T) {
+		c := revdedup.New(time.Second, 10)
+		assert.False(t, c.Suppress(key, now))
+		assert.True(t, c.Suppress(key, now.Add(500*time.Millisecond)))
+		assert.False(t, c.Suppress(key, now.Add(time.Second)))
+		assert.True(t, c.Suppress(key, now.Add(1500*time.Millisecond)))
+	})
+	t.Run("other revocations and targets are not suppressed", func(t *testing.T) {
+		c := revdedup.New(time.Second, 10)
+		assert.False(t, c.Suppress(key, now))
+		renewed := key
+		renewed.Timestamp++
+		assert.False(t, c.Suppress(renewed, now))
+		otherTarget := key
+		otherTarget.Target = "1-ff00:0:111,[10.0.0.2]"
+		assert.False(t, c.Suppress(otherTarget, now))
+		otherIf := key
+		otherIf.IfID = 2
+		assert.False(t, c.Suppress(otherIf, now))
+	})
+	t.Run("full cache does not suppress new revocations", func(t *testing.T) {
+		c := revdedup.New(time.Second, 1)
+		other := key
+		other.IfID = 2
+		assert.False(t, c.Suppress(key, now))
+		assert.False(t, c.Suppress(other, now))
+		assert.False(t, c.Suppress(other, now))
+		assert.True(t, c.Suppress(key, now))
+		// Expired entries make room.
+		assert.False(t, c.Suppress(other, now.Add(time.Second)))
+		assert.True(t, c.Suppress(other, now.Add(time.Second)))
+	})
+}
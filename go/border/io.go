@@ -18,6 +18,7 @@
 package main
 
 import (
+	"bytes"
 	"net"
 	"os"
 	"syscall"
@@ -46,11 +47,24 @@ const (
 	outputBufCnt = 32
 	// Number of packets to write in a single WriteBatch call.
 	outputBatchCnt = 32 // Must be <= outputBufCnt
+	// Time a socket is avoided by its bond after a failed write.
+	bondLinkDownTime = 1 * time.Second
+	// Interval at which keepalives are sent over each link of a bond.
+	bondKeepaliveInterval = 200 * time.Millisecond
+	// Time after which a link of a bond is considered down if nothing was
+	// received over it.
+	bondLinkTimeout = 1 * time.Second
 )
 
+// bondKeepalive is the payload of the keepalives sent over the links of a
+// bond. Its first nibble is not a valid SCION version, so it cannot be
+// confused with a SCION packet.
+var bondKeepalive = common.RawBytes("BRBONDKA")
+
 func (r *Router) posixInput(s *rctx.Sock, stop, stopped chan struct{}) {
 	defer log.LogPanicAndExit()
 	defer close(stopped)
+	pinThread()
 	dst := s.Conn.LocalAddr()
 	log.Info("posixInput starting", "addr", dst)
 	defer log.Info("posixInput stopping", "addr", dst)
@@ -132,18 +146,54 @@ Top:
 			inputBytes.Add(float64(msg.N))
 			inputPktSize.Observe(float64(msg.N))
 		}
-		for written := 0; written < pktsRead; {
-			wn, _ := s.Ring.Write(pkts[written:pktsRead], true)
+		toWrite := pkts[:pktsRead]
+		if s.Liveness != nil {
+			s.Liveness.Received()
+			toWrite = filterBondKeepalives(toWrite)
+		}
+		for written := 0; written < len(toWrite); {
+			wn, _ := s.Ring.Write(toWrite[written:], true)
 			written += wn
 		}
 		// Move unused pkts to the start.
-		copied := copy(pkts, pkts[pktsRead:])
+		copied := copy(pkts, pkts[len(toWrite):])
 		pkts = pkts[:copied]
 	}
 	// Return any unused buffers.
 	r.freePkts.Write(pkts, true)
 }
 
+// filterBondKeepalives moves the bond keepalives in pkts to the end, resets
+// them, and returns the remaining packets.
+func filterBondKeepalives(pkts ringbuf.EntryList) ringbuf.EntryList {
+	n := 0
+	for i := range pkts {
+		rp := pkts[i].(*rpkt.RtrPkt)
+		if bytes.Equal(rp.Raw, bondKeepalive) {
+			rp.Reset()
+			continue
+		}
+		pkts[n], pkts[i] = pkts[i], pkts[n]
+		n++
+	}
+	return pkts[:n]
+}
+
+// bondKeepalives periodically sends keepalives over all links of the bonded
+// interfaces, such that the peer router can detect failed links.
+func (r *Router) bondKeepalives() {
+	for range time.Tick(bondKeepaliveInterval) {
+		for ifid, bond := range rctx.Get().ExtBondOut {
+			for _, s := range bond.Socks {
+				if _, err := s.Conn.Write(bondKeepalive); err != nil {
+					log.Debug("Unable to send bond keepalive", "ifid", ifid,
+						"addr", s.Conn.LocalAddr(), "err", err)
+				}
+			}
+		}
+	}
+}
+
 // posixPrepInput refills pkts if it's below inputLowBufCnt, and sets the msgs
 // Buffers references to point to the corresponding buffers in pkts.
 func (r *Router) posixPrepInput(pkts ringbuf.EntryList,
@@ -235,6 +285,8 @@ func (r *Router) posixOutput(s *rctx.Sock, _, stopped chan struct{}) {
 				if common.IsTemporaryErr(err) {
 					continue
 				}
+				// Let bonded interfaces fail over to their other links.
+				s.MarkDown(bondLinkDownTime)
 				// Before dropping the packets, we have to return them to the freelist.
 				releasePkts(epkts[:toWrite])
 				// Drop packets from the failed write attempt.
@@ -246,6 +298,7 @@ func (r *Router) posixOutput(s *rctx.Sock, _, stopped chan struct{}) {
 				break
 			}
 		}
+		s.MarkUp()
 		t = time.Since(start).Seconds()
 		bytes = 0
 		for i := 0; i < pktsWritten; i++ {
@@ -27,6 +27,7 @@ import (
 	"github.com/scionproto/scion/go/border/rctx"
 	"github.com/scionproto/scion/go/border/rpkt"
 	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/overlay"
 	"github.com/scionproto/scion/go/lib/overlay/conn"
@@ -131,6 +132,27 @@ func newTestConn(mctrl *gomock.Controller) *mock_conn.MockConn {
 	return mconn
 }
 
+func TestFilterBondKeepalives(t *testing.T) {
+	pkts := make(ringbuf.EntryList, 4)
+	for i := range pkts {
+		rp := rpkt.NewRtrPkt()
+		rp.Raw = rp.Raw[:copy(rp.Raw, []byte{byte(i)})]
+		pkts[i] = rp
+	}
+	ka := []*rpkt.RtrPkt{pkts[1].(*rpkt.RtrPkt), pkts[3].(*rpkt.RtrPkt)}
+	for _, rp := range ka {
+		rp.Raw = rp.Raw[:copy(rp.Raw, bondKeepalive)]
+	}
+	filtered := filterBondKeepalives(pkts)
+	require.Len(t, filtered, 2)
+	require.Equal(t, common.RawBytes{0}, filtered[0].(*rpkt.RtrPkt).Raw)
+	require.Equal(t, common.RawBytes{2}, filtered[1].(*rpkt.RtrPkt).Raw)
+	require.ElementsMatch(t, ka, []*rpkt.RtrPkt{pkts[2].(*rpkt.RtrPkt), pkts[3].(*rpkt.RtrPkt)})
+	for _, rp := range ka {
+		require.Equal(t, cap(rp.Raw), len(rp.Raw), "Keepalive not reset")
+	}
+}
+
 func newTestPktList(t *testing.T, length int) (ringbuf.EntryList, func(expected int)) {
 	var freeCtr int
 	entries := make(ringbuf.EntryList, length)
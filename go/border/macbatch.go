@@ -0,0 +1,97 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/scionproto/scion/go/border/internal/hfmac"
+	"github.com/scionproto/scion/go/border/rctx"
+	"github.com/scionproto/scion/go/border/rpkt"
+	"github.com/scionproto/scion/go/lib/log"
+)
+
+// macBatch collects the hop field MAC verification requests of a batch of
+// parsed packets, and verifies them at once with the verifier of the
+// processing worker, see hfmac.Verifier.VerifyBatch. The results are recorded
+// in the packets, such that validation does not verify the MACs again.
+type macBatch struct {
+	// ctx is the router context the verifier was created for. The verifier
+	// is replaced when the context changes, because the master key might have
+	// changed with the new configuration.
+	ctx      *rctx.Ctx
+	verifier *hfmac.Verifier
+	pkts     []*rpkt.RtrPkt
+	reqs     []hfmac.Request
+	errs     []error
+}
+
+func newMacBatch(size int) *macBatch {
+	return &macBatch{
+		pkts: make([]*rpkt.RtrPkt, 0, size),
+		reqs: make([]hfmac.Request, 0, size),
+		errs: make([]error, size),
+	}
+}
+
+// add adds the request of the packet to the batch, if the packet has a hop
+// field MAC to verify.
+func (b *macBatch) add(rp *rpkt.RtrPkt) {
+	req, ok := rp.HopFMacRequest()
+	if !ok {
+		return
+	}
+	b.pkts = append(b.pkts, rp)
+	b.reqs = append(b.reqs, req)
+}
+
+// verify verifies the MACs of all requests in the batch, records the results
+// in the packets, and empties the batch.
+func (b *macBatch) verify() {
+	if len(b.errs) < len(b.reqs) {
+		b.errs = make([]error, len(b.reqs))
+	}
+	for start := 0; start < len(b.pkts); {
+		// All packets of a batch share the context, except around reloads.
+		ctx := b.pkts[start].Ctx
+		end := start + 1
+		for end < len(b.pkts) && b.pkts[end].Ctx == ctx {
+			end++
+		}
+		if v := b.verifierFor(ctx); v != nil {
+			v.VerifyBatch(b.reqs[start:end], b.errs[start:end])
+			for i := start; i < end; i++ {
+				b.pkts[i].SetHopFMacResult(b.errs[i])
+			}
+		}
+		start = end
+	}
+	for i := range b.pkts {
+		b.pkts[i], b.reqs[i], b.errs[i] = nil, hfmac.Request{}, nil
+	}
+	b.pkts, b.reqs = b.pkts[:0], b.reqs[:0]
+}
+
+// verifierFor returns the verifier for the context. If the verifier cannot be
+// created, nil is returned, and the MACs are verified during validation.
+func (b *macBatch) verifierFor(ctx *rctx.Ctx) *hfmac.Verifier {
+	if ctx == b.ctx {
+		return b.verifier
+	}
+	v, err := ctx.NewHFMacVerifier()
+	if err != nil {
+		log.Error("Unable to create hop field MAC verifier", "err", err)
+	}
+	b.ctx, b.verifier = ctx, v
+	return b.verifier
+}
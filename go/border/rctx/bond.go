@@ -0,0 +1,80 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rctx
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Bond distributes packets over the output Socks of the parallel underlay
+// links of a bonded interface. Packets of the same flow are sent over the same
+// link as long as it is up. If that link is down, the flow fails over to the
+// next link that is up. A link is down if a write to it failed recently, or
+// if its Liveness reports that nothing was received over it for too long.
+type Bond struct {
+	// Socks are the member Socks. The first one belongs to the primary link.
+	Socks []*Sock
+}
+
+// NewBond creates a Bond over the given Socks. At least one Sock is required.
+func NewBond(socks ...*Sock) *Bond {
+	return &Bond{Socks: socks}
+}
+
+// Pick returns the Sock for the given flow hash. If all members are down, the
+// Sock that the flow maps to is returned regardless.
+func (b *Bond) Pick(flow uint32) *Sock {
+	n := uint32(len(b.Socks))
+	start := flow % n
+	for i := uint32(0); i < n; i++ {
+		if s := b.Socks[(start+i)%n]; s.Up() {
+			return s
+		}
+	}
+	return b.Socks[start]
+}
+
+// Liveness tracks whether the peer router is reachable over an underlay link
+// of a bonded interface. It is shared by the input and output Sock of the
+// link; the input Sock records when packets are received. The routers on both
+// sides send keepalives over all links of a bond, such that a link over which
+// nothing was received for the timeout can be considered down.
+type Liveness struct {
+	// lastRecv is the unix time in nanoseconds of the last reception. It is
+	// accessed atomically and must stay the first field to guarantee 64-bit
+	// alignment.
+	lastRecv int64
+	timeout  time.Duration
+}
+
+// NewLiveness creates a Liveness that considers the link alive for the
+// timeout after the last reception. The link is initially alive.
+func NewLiveness(timeout time.Duration) *Liveness {
+	l := &Liveness{timeout: timeout}
+	l.Received()
+	return l
+}
+
+// Received records that packets were received over the link.
+func (l *Liveness) Received() {
+	atomic.StoreInt64(&l.lastRecv, time.Now().UnixNano())
+}
+
+// Alive returns whether packets were received over the link within the
+// timeout.
+func (l *Liveness) Alive() bool {
+	return time.Now().UnixNano()-atomic.LoadInt64(&l.lastRecv) < int64(l.timeout)
+}
@@ -0,0 +1,79 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rctx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/border/rcmn"
+)
+
+func TestBondPick(t *testing.T) {
+	newSocks := func() []*Sock {
+		socks := make([]*Sock, 3)
+		for i := range socks {
+			socks[i] = NewSock(nil, nil, rcmn.DirExternal, 1, "", nil, nil, "")
+		}
+		return socks
+	}
+	t.Run("Flows map to members", func(t *testing.T) {
+		socks := newSocks()
+		bond := NewBond(socks...)
+		assert.Equal(t, socks[0], bond.Pick(0))
+		assert.Equal(t, socks[1], bond.Pick(1))
+		assert.Equal(t, socks[2], bond.Pick(5))
+		assert.Equal(t, bond.Pick(42), bond.Pick(42))
+	})
+	t.Run("Down member fails over", func(t *testing.T) {
+		socks := newSocks()
+		bond := NewBond(socks...)
+		socks[1].MarkDown(time.Hour)
+		assert.Equal(t, socks[2], bond.Pick(1))
+		socks[2].MarkDown(time.Hour)
+		assert.Equal(t, socks[0], bond.Pick(1))
+		socks[1].MarkUp()
+		assert.Equal(t, socks[1], bond.Pick(1))
+	})
+	t.Run("All down uses flow member", func(t *testing.T) {
+		socks := newSocks()
+		bond := NewBond(socks...)
+		for _, s := range socks {
+			s.MarkDown(time.Hour)
+		}
+		assert.Equal(t, socks[2], bond.Pick(2))
+	})
+	t.Run("Down period expires", func(t *testing.T) {
+		socks := newSocks()
+		bond := NewBond(socks...)
+		socks[0].MarkDown(-time.Second)
+		assert.True(t, socks[0].Up())
+		assert.Equal(t, socks[0], bond.Pick(0))
+	})
+	t.Run("Dead member fails over", func(t *testing.T) {
+		socks := newSocks()
+		bond := NewBond(socks...)
+		for _, s := range socks {
+			s.Liveness = NewLiveness(time.Hour)
+		}
+		socks[1].Liveness.lastRecv = 0
+		assert.False(t, socks[1].Up())
+		assert.Equal(t, socks[2], bond.Pick(1))
+		socks[1].Liveness.Received()
+		assert.Equal(t, socks[1], bond.Pick(1))
+	})
+}
@@ -15,6 +15,7 @@
 package rctx
 
 import (
+	"sync/atomic"
 	"time"
 
 	"github.com/scionproto/scion/go/border/brconf"
@@ -35,6 +36,10 @@ type SockFunc func(sock *Sock, stop, stopped chan struct{})
 // Each connection will have 2 Sock's associated, one for reading data from the
 // network, the other for writing data to the network.
 type Sock struct {
+	// downUntil is the unix time in nanoseconds until which the Sock is
+	// considered down. It is accessed atomically and must stay the first field
+	// to guarantee 64-bit alignment.
+	downUntil int64
 	// Ring is a ring-buffer that's written to by writers, and read from by readers.
 	Ring *ringbuf.Ring
 	// Conn is the underlying connection that this Sock represents.
@@ -54,7 +59,11 @@ type Sock struct {
 	// in a go routine when Sock.Start() is called.
 	Writer SockFunc
 	// Type is the type of the socket.
-	Type          brconf.SockType
+	Type brconf.SockType
+	// Liveness, if set, tracks whether the peer is reachable over the link of
+	// a bonded interface. It is shared by the input and output Sock of the
+	// link.
+	Liveness      *Liveness
 	stop          chan struct{}
 	readerStopped chan struct{}
 	writerStopped chan struct{}
@@ -152,6 +161,30 @@ func (s *Sock) Stop() {
 	}
 }
 
+// MarkDown marks the Sock as down for the duration d. Bonds avoid sending
+// packets over Socks that are down.
+func (s *Sock) MarkDown(d time.Duration) {
+	atomic.StoreInt64(&s.downUntil, time.Now().Add(d).UnixNano())
+}
+
+// MarkUp clears a previous MarkDown.
+func (s *Sock) MarkUp() {
+	if atomic.LoadInt64(&s.downUntil) != 0 {
+		atomic.StoreInt64(&s.downUntil, 0)
+	}
+}
+
+// Up returns whether the Sock is usable, i.e., it is not marked as down or
+// the down period has expired, and the peer is alive according to the
+// Liveness, if set.
+func (s *Sock) Up() bool {
+	until := atomic.LoadInt64(&s.downUntil)
+	if until != 0 && time.Now().UnixNano() < until {
+		return false
+	}
+	return s.Liveness == nil || s.Liveness.Alive()
+}
+
 func (s *Sock) Running() bool {
 	return s.running
 }
@@ -23,6 +23,7 @@ import (
 	"sync/atomic"
 
 	"github.com/scionproto/scion/go/border/brconf"
+	"github.com/scionproto/scion/go/border/internal/hfmac"
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/overlay"
@@ -47,6 +48,15 @@ type Ctx struct {
 	// ExtSockOut is a map of Sock's for sending packets to neighbouring ASes,
 	// keyed by the interface ID of the relevant link.
 	ExtSockOut map[common.IFIDType]*Sock
+	// ExtBondIn is a map of the Socks for receiving packets on the additional
+	// links of bonded interfaces, keyed by the interface ID.
+	ExtBondIn map[common.IFIDType][]*Sock
+	// ExtBondOut is a map of Bonds for sending packets over bonded interfaces,
+	// keyed by the interface ID. The first member of each Bond is the
+	// corresponding entry in ExtSockOut.
+	ExtBondOut map[common.IFIDType]*Bond
+	// hfMacKey is the key for the Hop Field MAC generation.
+	hfMacKey []byte
 }
 
 // ctx is the current router context object.
@@ -58,16 +68,28 @@ func New(conf *brconf.BRConf) *Ctx {
 		Conf:       conf,
 		ExtSockOut: make(map[common.IFIDType]*Sock),
 		ExtSockIn:  make(map[common.IFIDType]*Sock),
+		ExtBondIn:  make(map[common.IFIDType][]*Sock),
+		ExtBondOut: make(map[common.IFIDType]*Bond),
 	}
 	return ctx
 }
 
+// ExtEgress returns the Sock to send a packet of the given flow over the
+// interface ifid. For bonded interfaces, the Sock is chosen by the Bond.
+func (ctx *Ctx) ExtEgress(ifid common.IFIDType, flow uint32) *Sock {
+	if bond, ok := ctx.ExtBondOut[ifid]; ok {
+		return bond.Pick(flow)
+	}
+	return ctx.ExtSockOut[ifid]
+}
+
 // initMacPool initializes the hop field mac pool.
 func (ctx *Ctx) InitMacPool() error {
 	hfMacFactory, err := scrypto.HFMacFactory(ctx.Conf.MasterKeys.Key0)
 	if err != nil {
 		return err
 	}
+	ctx.hfMacKey = scrypto.HFMacKey(ctx.Conf.MasterKeys.Key0)
 	// Create a pool of MAC instances.
 	ctx.HFMacPool = &sync.Pool{
 		New: func() interface{} {
@@ -77,6 +99,12 @@ func (ctx *Ctx) InitMacPool() error {
 	return nil
 }
 
+// NewHFMacVerifier creates a Hop Field MAC verifier for a packet processing
+// worker. InitMacPool must have been called before.
+func (ctx *Ctx) NewHFMacVerifier() (*hfmac.Verifier, error) {
+	return hfmac.New(ctx.hfMacKey)
+}
+
 func (ctx *Ctx) ResolveSVC(svc addr.HostSVC) ([]*overlay.OverlayAddr, error) {
 	if svc.IsMulticast() {
 		return ctx.ResolveSVCMulti(svc)
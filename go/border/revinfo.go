@@ -12,22 +12,87 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// This file handles Revocation Info (RevInfo) packets.
+// This file handles Revocation Info (RevInfo) packets, and suppresses
+// duplicate revocations.
 
 package main
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/scionproto/scion/go/border/internal/metrics"
+	"github.com/scionproto/scion/go/border/internal/revdedup"
 	"github.com/scionproto/scion/go/border/rpkt"
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
 	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/scmp"
 )
 
 // RawSRevCallback is called to enqueue RevInfos for handling by the
-// RevInfoFwd goroutine.
+// RevInfoFwd goroutine. Revocations that were recently forwarded to the same
+// service are not forwarded again.
 func (r *Router) RawSRevCallback(args rpkt.RawSRevCallbackArgs) {
-	// TODO #1867 filter revocations to avoid sending the same one to Control-plane multiple times.
+	args.Addrs = r.revTargets(args)
+	if len(args.Addrs) == 0 {
+		return
+	}
 	select {
 	case r.sRevInfoQ <- args:
 	default:
 		log.Debug("Dropping rev token")
 	}
 }
+
+// revTargets returns the services the revocation has not recently been
+// forwarded to.
+func (r *Router) revTargets(args rpkt.RawSRevCallbackArgs) []addr.HostSVC {
+	revInfo, err := args.SignedRevInfo.RevInfo()
+	if err != nil {
+		// Let the RevInfoFwd goroutine deal with the error.
+		return args.Addrs
+	}
+	now := time.Now()
+	targets := args.Addrs[:0]
+	for _, svc := range args.Addrs {
+		if r.revDedup.Suppress(revdedup.NewKey(revInfo, svc.BaseString()), now) {
+			metrics.Revocations.Suppressed(
+				metrics.RevocationLabels{Target: metrics.RevTargetCtrl}).Inc()
+			continue
+		}
+		targets = append(targets, svc)
+	}
+	return targets
+}
+
+// suppressSCMPRevocation returns true if the SCMP revocation was recently
+// sent to the source of rp, in which case it is not sent again.
+func (r *Router) suppressSCMPRevocation(rp *rpkt.RtrPkt, info scmp.Info) bool {
+	infoRev, ok := info.(*scmp.InfoRevocation)
+	if !ok {
+		return false
+	}
+	sRevInfo, err := path_mgmt.NewSignedRevInfoFromRaw(infoRev.RawSRev)
+	if err != nil {
+		return false
+	}
+	revInfo, err := sRevInfo.RevInfo()
+	if err != nil {
+		return false
+	}
+	srcIA, err := rp.SrcIA()
+	if err != nil {
+		return false
+	}
+	srcHost, err := rp.SrcHost()
+	if err != nil {
+		return false
+	}
+	target := fmt.Sprintf("%s,[%s]", srcIA, srcHost)
+	if !r.revDedup.Suppress(revdedup.NewKey(revInfo, target), time.Now()) {
+		return false
+	}
+	metrics.Revocations.Suppressed(metrics.RevocationLabels{Target: metrics.RevTargetHost}).Inc()
+	return true
+}
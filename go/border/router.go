@@ -21,7 +21,10 @@ import (
 	"sync"
 
 	"github.com/scionproto/scion/go/border/brconf"
+	"github.com/scionproto/scion/go/border/ifstate"
+	"github.com/scionproto/scion/go/border/internal/capture"
 	"github.com/scionproto/scion/go/border/internal/metrics"
+	"github.com/scionproto/scion/go/border/internal/revdedup"
 	"github.com/scionproto/scion/go/border/rcmn"
 	"github.com/scionproto/scion/go/border/rctrl"
 	"github.com/scionproto/scion/go/border/rctx"
@@ -30,7 +33,9 @@ import (
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/fatal"
 	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/overlay/natprobe"
 	"github.com/scionproto/scion/go/lib/ringbuf"
+	"github.com/scionproto/scion/go/lib/scmp"
 	_ "github.com/scionproto/scion/go/lib/scrypto" // Make sure math/rand is seeded
 )
 
@@ -52,6 +57,11 @@ type Router struct {
 	// static topology from the discovery service, or from dropping an expired
 	// dynamic topology.
 	setCtxMtx sync.Mutex
+	// tap mirrors received packets to the active capture session, if any.
+	tap capture.Tap
+	// revDedup suppresses revocations that were recently sent to the same
+	// target, i.e., end host or control service.
+	revDedup *revdedup.Cache
 }
 
 func NewRouter(id, confDir string) (*Router, error) {
@@ -73,6 +83,10 @@ func (r *Router) Start() {
 		defer log.LogPanicAndExit()
 		rctrl.Control(r.sRevInfoQ, cfg.General.ReconnectToDispatcher)
 	}()
+	go func() {
+		defer log.LogPanicAndExit()
+		r.bondKeepalives()
+	}()
 	if err := r.startDiscovery(); err != nil {
 		fatal.Fatal(common.NewBasicError("Unable to start discovery", err))
 	}
@@ -94,27 +108,47 @@ func (r *Router) ReloadConfig() error {
 func (r *Router) handleSock(s *rctx.Sock, stop, stopped chan struct{}) {
 	defer log.LogPanicAndExit()
 	defer close(stopped)
-	pkts := make(ringbuf.EntryList, processBufCnt)
+	if cfg.BR.Workers > 1 {
+		r.steerSock(s, cfg.BR.Workers)
+		return
+	}
+	pinThread()
 	dst := s.Conn.LocalAddr()
 	log.Debug("handleSock starting", "addr", dst)
-	for {
-		n, _ := s.Ring.Read(pkts, true)
-		if n < 0 {
-			log.Debug("handleSock stopping", "addr", dst)
-			return
-		}
-		for i := 0; i < n; i++ {
-			rp := pkts[i].(*rpkt.RtrPkt)
-			r.processPacket(rp)
-			rp.Release()
-			pkts[i] = nil
+	r.processRing(s.Ring)
+	log.Debug("handleSock stopping", "addr", dst)
+}
+
+// capturePkt hands the packet to the capture session.
+func capturePkt(s *capture.Session, rp *rpkt.RtrPkt) {
+	pkt := capture.Packet{
+		Time: rp.TimeIn,
+		IfID: rp.Ingress.IfID,
+		Src:  rp.Ingress.Src.ToUDPAddr(),
+		Dst:  rp.Ingress.Dst.ToUDPAddr(),
+		Raw:  rp.Raw,
+	}
+	pkt.SrcIA, _ = rp.SrcIA()
+	pkt.DstIA, _ = rp.DstIA()
+	if s.Filter.SCMPClass != nil {
+		if hdr, err := rp.L4Hdr(false); err == nil {
+			if scmpHdr, ok := hdr.(*scmp.Hdr); ok {
+				pkt.SCMP, pkt.SCMPClass = true, scmpHdr.Class
+			}
 		}
 	}
+	s.Capture(&pkt)
 }
 
-// processPacket is the heart of the router's packet handling. It delegates
-// everything from parsing the incoming packet, to routing the outgoing packet.
-func (r *Router) processPacket(rp *rpkt.RtrPkt) {
+// parsePacket and forwardPacket are the heart of the router's packet
+// handling. They delegate everything from parsing the incoming packet, to
+// routing the outgoing packet. Processing is split in two stages, such that
+// the hop field MACs of a batch of parsed packets can be verified together,
+// see processRing.
+//
+// parsePacket parses the packet, and returns false if the packet was already
+// handled, e.g., dropped because of an error.
+func (r *Router) parsePacket(rp *rpkt.RtrPkt) (metrics.ProcessLabels, bool) {
 	if assert.On {
 		assert.Must(rp.DirFrom != rcmn.DirUnset, "DirFrom must be set")
 		assert.Must(rp.Ingress.Dst != nil, "Ingress.Dst must be set")
@@ -130,17 +164,37 @@ func (r *Router) processPacket(rp *rpkt.RtrPkt) {
 		IntfIn:  metrics.IntfToLabel(rp.Ingress.IfID),
 		IntfOut: metrics.Drop,
 	}
+	// Silently drop packets received on administratively disabled interfaces.
+	if rp.DirFrom == rcmn.DirExternal && ifstate.AdminDown(rp.Ingress.IfID) {
+		l.Result = metrics.ErrAdminDown
+		metrics.Process.Pkts(l).Inc()
+		return l, false
+	}
+	// Answer NAT probes of end hosts in the local AS, see natprobe.
+	if rp.DirFrom == rcmn.DirLocal && natprobe.IsRequest(rp.Raw) {
+		r.handleNATProbe(rp)
+		return l, false
+	}
 	// Assign a pseudorandom ID to the packet, for correlating log entries.
 	rp.Id = log.RandId(4)
 	rp.Logger = log.New("rpkt", rp.Id)
 	// XXX(kormat): uncomment for debugging:
-	//rp.Debug("processPacket", "raw", rp.Raw)
+	//rp.Debug("parsePacket", "raw", rp.Raw)
 	if err := rp.Parse(); err != nil {
 		r.handlePktError(rp, err, "Error parsing packet")
 		l.Result = metrics.ErrParse
 		metrics.Process.Pkts(l).Inc()
-		return
+		return l, false
+	}
+	// Mirror the packet as received, if a capture session is active.
+	if s := r.tap.Active(); s != nil {
+		capturePkt(s, rp)
 	}
+	return l, true
+}
+
+// forwardPacket validates, processes and routes the parsed packet.
+func (r *Router) forwardPacket(rp *rpkt.RtrPkt, l metrics.ProcessLabels) {
 	// Validation looks for errors in the packet that didn't break basic
 	// parsing.
 	valid, err := rp.Validate()
@@ -181,3 +235,17 @@ func (r *Router) processPacket(rp *rpkt.RtrPkt) {
 		metrics.Process.Pkts(l).Inc()
 	}
 }
+
+// handleNATProbe replies to the NAT probe in rp with the overlay address the
+// probe was received from. The reply reuses the packet buffer.
+func (r *Router) handleNATProbe(rp *rpkt.RtrPkt) {
+	src := rp.Ingress.Src.ToUDPAddr()
+	if src == nil {
+		return
+	}
+	rp.Raw = natprobe.AppendReply(rp.Raw[:0], rp.Raw, src)
+	rp.Egress = append(rp.Egress, rpkt.EgressPair{S: rp.Ctx.LocSockOut, Dst: rp.Ingress.Src})
+	if err := rp.Route(); err != nil {
+		log.Error("Unable to reply to NAT probe", "src", src, "err", err)
+	}
+}
@@ -239,7 +239,7 @@ func (rp *RtrPkt) replyEgress(dir rcmn.Dir, dst *overlay.OverlayAddr, ifid commo
 	}
 	// Destination AS is not local
 	if dir == rcmn.DirExternal {
-		rp.Egress = append(rp.Egress, EgressPair{S: rp.Ctx.ExtSockOut[ifid]})
+		rp.Egress = append(rp.Egress, EgressPair{S: rp.Ctx.ExtEgress(ifid, rp.flowHash())})
 		return nil
 	}
 	if assert.On {
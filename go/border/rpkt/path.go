@@ -24,6 +24,7 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/scionproto/scion/go/border/ifstate"
+	"github.com/scionproto/scion/go/border/internal/hfmac"
 	"github.com/scionproto/scion/go/border/rcmn"
 	"github.com/scionproto/scion/go/lib/assert"
 	"github.com/scionproto/scion/go/lib/common"
@@ -67,10 +68,13 @@ func (rp *RtrPkt) validatePath(dirFrom rcmn.Dir) error {
 			"expiry", hopfExpiry,
 		)
 	}
-	// Verify the Hop Field MAC.
-	hfmac := rp.Ctx.HFMacPool.Get().(hash.Hash)
-	err := rp.hopF.Verify(hfmac, rp.infoF.TsInt, rp.getHopFVer(dirFrom))
-	rp.Ctx.HFMacPool.Put(hfmac)
+	// Verify the Hop Field MAC, unless it was verified ahead of validation.
+	err := rp.hopFMacErr
+	if !rp.hopFMacChecked {
+		hfmac := rp.Ctx.HFMacPool.Get().(hash.Hash)
+		err = rp.hopF.Verify(hfmac, rp.infoF.TsInt, rp.getHopFVer(dirFrom))
+		rp.Ctx.HFMacPool.Put(hfmac)
+	}
 	if err != nil && xerrors.Is(err, spath.ErrorHopFBadMac) {
 		err = scmp.NewError(scmp.C_Path, scmp.T_P_BadMac,
 			rp.mkInfoPathOffsets(rp.CmnHdr.CurrInfoF, rp.CmnHdr.CurrHopF), err)
@@ -78,6 +82,30 @@ func (rp *RtrPkt) validatePath(dirFrom rcmn.Dir) error {
 	return err
 }
 
+// HopFMacRequest returns the MAC verification request for the current Hop
+// Field, such that the MAC can be verified ahead of Validate, e.g., together
+// with the MACs of other packets. The packet must have been parsed. The bool
+// return value is false if the packet has no path, or if the Hop Field is
+// rejected without verifying its MAC.
+func (rp *RtrPkt) HopFMacRequest() (hfmac.Request, bool) {
+	if rp.infoF == nil || rp.hopF == nil || rp.hopF.VerifyOnly {
+		return hfmac.Request{}, false
+	}
+	return hfmac.Request{
+		HopF:  rp.hopF,
+		TsInt: rp.infoF.TsInt,
+		Prev:  rp.getHopFVer(rp.DirFrom),
+	}, true
+}
+
+// SetHopFMacResult records the result of verifying the request returned by
+// HopFMacRequest. Validate then uses the result instead of verifying the MAC
+// again.
+func (rp *RtrPkt) SetHopFMacResult(err error) {
+	rp.hopFMacChecked = true
+	rp.hopFMacErr = err
+}
+
 // validateLocalIF makes sure a given interface ID exists in the local AS, and
 // that it isn't revoked. Note that revocations are ignored if the packet's
 // destination is this router.
@@ -374,6 +402,10 @@ func (rp *RtrPkt) IncPath() (bool, error) {
 	rp.CmnHdr.UpdatePathOffsets(rp.Raw, currInfoF, currHopF)
 	rp.infoF = infoF
 	rp.hopF = hopF
+	// A MAC verification result recorded by SetHopFMacResult belongs to the
+	// previous Hop Field.
+	rp.hopFMacChecked = false
+	rp.hopFMacErr = nil
 	rp.IncrementedPath = true
 	rp.consDirFlag = &infoF.ConsDir
 	// Extract the next interface ID.
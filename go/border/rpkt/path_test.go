@@ -0,0 +1,122 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpkt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/border/brconf"
+	"github.com/scionproto/scion/go/border/rcmn"
+	"github.com/scionproto/scion/go/border/rctx"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/keyconf"
+	"github.com/scionproto/scion/go/lib/scmp"
+	"github.com/scionproto/scion/go/lib/scrypto"
+	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/lib/topology"
+)
+
+// prepareXoverPkt creates a packet on the last Hop Field of an up-segment,
+// followed by a down-segment. If badMac is set, the MAC of the first Hop Field
+// of the down-segment is invalid.
+func prepareXoverPkt(t *testing.T, badMac bool) *RtrPkt {
+	key := []byte("0123456789abcdef")
+	mac, err := scrypto.HFMacFactory(key)
+	require.NoError(t, err)
+	topo := topology.NewTopo()
+	topo.IFInfoMap[1] = topology.IFInfo{}
+	topo.IFInfoMap[2] = topology.IFInfo{}
+	conf := &brconf.BRConf{
+		Topo: topo,
+		BR: &topology.BRInfo{
+			IFs: map[common.IFIDType]*topology.IFInfo{1: {}, 2: {}},
+		},
+		MasterKeys: keyconf.Master{Key0: key},
+	}
+	ctx := rctx.New(conf)
+	require.NoError(t, ctx.InitMacPool())
+
+	ts := uint32(time.Now().Unix())
+	up := &spath.InfoField{TsInt: ts, Hops: 2}
+	down := &spath.InfoField{ConsDir: true, TsInt: ts, Hops: 2}
+	hops := []*spath.HopField{
+		{ExpTime: spath.DefaultHopFExpiry, ConsEgress: 3},
+		{ExpTime: spath.DefaultHopFExpiry, Xover: true, ConsIngress: 3, ConsEgress: 1},
+		{ExpTime: spath.DefaultHopFExpiry, Xover: true, ConsEgress: 2},
+		{ExpTime: spath.DefaultHopFExpiry, ConsIngress: 4},
+	}
+	for _, h := range hops {
+		h.Mac = h.CalcMac(mac(), ts, make(common.RawBytes, common.LineLen-1))
+	}
+	if badMac {
+		hops[2].Mac = common.RawBytes{0, 0, 0}
+	}
+
+	rp := NewRtrPkt()
+	rp.Ctx = ctx
+	rp.DirFrom = rcmn.DirExternal
+	rp.CmnHdr.HdrLen = 7
+	rp.CmnHdr.CurrInfoF = 1
+	rp.CmnHdr.CurrHopF = 3
+	up.Write(rp.Raw[1*common.LineLen:])
+	hops[0].Write(rp.Raw[2*common.LineLen:])
+	hops[1].Write(rp.Raw[3*common.LineLen:])
+	down.Write(rp.Raw[4*common.LineLen:])
+	hops[2].Write(rp.Raw[5*common.LineLen:])
+	hops[3].Write(rp.Raw[6*common.LineLen:])
+	rp.infoF = up
+	rp.hopF = hops[1]
+	rp.consDirFlag = &up.ConsDir
+	rp.ifCurr = &hops[1].ConsEgress
+	return rp
+}
+
+func TestXoverRevalidatesHopFMac(t *testing.T) {
+	tests := map[string]struct {
+		BadMac    bool
+		Assertion func(t *testing.T, err error)
+	}{
+		"valid MAC": {
+			Assertion: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		"bad MAC": {
+			BadMac: true,
+			Assertion: func(t *testing.T, err error) {
+				require.Error(t, err)
+				scmpErr, ok := err.(*scmp.Error)
+				require.True(t, ok, "expected SCMP error, got %v", err)
+				assert.Equal(t, scmp.T_P_BadMac, scmpErr.CT.Type)
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rp := prepareXoverPkt(t, test.BadMac)
+			// The MAC of the up-segment Hop Field was verified ahead of
+			// validation.
+			rp.SetHopFMacResult(nil)
+			segChgd, err := rp.IncPath()
+			require.NoError(t, err)
+			require.True(t, segChgd)
+			test.Assertion(t, rp.validatePath(rcmn.DirLocal))
+		})
+	}
+}
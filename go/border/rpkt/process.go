@@ -19,6 +19,7 @@ package rpkt
 import (
 	"time"
 
+	"github.com/scionproto/scion/go/border/internal/metrics"
 	"github.com/scionproto/scion/go/border/rcmn"
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
@@ -91,10 +92,9 @@ func (rp *RtrPkt) processSCMPTraceRoute() error {
 		return common.NewBasicError("Invalid payload type in SCMP packet", nil,
 			"expected", "*scmp.Payload", "actual", common.TypeOf(rp.pld))
 	}
-	infoTrace, ok := pld.Info.(*scmp.InfoTraceRoute)
-	if !ok {
-		return common.NewBasicError("Invalid SCMP Info type in SCMP packet", nil,
-			"expected", "*scmp.InfoTraceRoute", "actual", common.TypeOf(pld.Info))
+	infoTrace, err := pld.TraceRoute()
+	if err != nil {
+		return err
 	}
 	if infoTrace.HopOff != rp.CmnHdr.CurrHopF {
 		return nil
@@ -105,6 +105,19 @@ func (rp *RtrPkt) processSCMPTraceRoute() error {
 	if infoTrace.In != (rp.DirFrom == rcmn.DirExternal) {
 		return nil
 	}
+	if _, ok := traceRouteDisabled[*rp.ifCurr]; ok {
+		// Forward the request without revealing the interface.
+		rp.observeSCMP(metrics.SCMPTraceRoute, metrics.SCMPDisabled)
+		return nil
+	}
+	err = rp.replySCMPTraceRoute(infoTrace)
+	rp.observeSCMP(metrics.SCMPTraceRoute, scmpResult(err))
+	return err
+}
+
+// replySCMPTraceRoute replies to the traceroute request, and drops the
+// original packet.
+func (rp *RtrPkt) replySCMPTraceRoute(infoTrace *scmp.InfoTraceRoute) error {
 	infoTrace.IA = rp.Ctx.Conf.IA
 	infoTrace.IfID = *rp.ifCurr
 	// Create generic ScnPkt reply
@@ -134,11 +147,18 @@ func (rp *RtrPkt) processSCMPRecordPath() error {
 		return common.NewBasicError("Invalid payload type in SCMP packet", nil,
 			"expected", "*scmp.Payload", "actual", common.TypeOf(rp.pld))
 	}
-	infoRec, ok := pld.Info.(*scmp.InfoRecordPath)
-	if !ok {
-		return common.NewBasicError("Invalid SCMP Info type in SCMP packet", nil,
-			"expected", "*scmp.InfoRecordPath", "actual", common.TypeOf(pld.Info))
+	infoRec, err := pld.RecordPath()
+	if err != nil {
+		return err
 	}
+	err = rp.addSCMPRecordPathEntry(infoRec)
+	rp.observeSCMP(metrics.SCMPRecordPath, scmpResult(err))
+	return err
+}
+
+// addSCMPRecordPathEntry adds the entry of this router to the record path
+// request.
+func (rp *RtrPkt) addSCMPRecordPathEntry(infoRec *scmp.InfoRecordPath) error {
 	// Calculate time in microseconds since scmp packet was created
 	hdr := rp.l4.(*scmp.Hdr)
 	ts := uint32(time.Since(hdr.Time()) / time.Microsecond)
@@ -157,6 +177,48 @@ func (rp *RtrPkt) processSCMPRecordPath() error {
 	return nil
 }
 
+// observeSCMP updates the metrics for an SCMP request handled for the current
+// interface.
+func (rp *RtrPkt) observeSCMP(scmpType, result string) {
+	l := metrics.SCMPLabels{
+		Type:   scmpType,
+		Intf:   metrics.IntfToLabel(*rp.ifCurr),
+		Result: result,
+	}
+	metrics.SCMP.Msgs(l).Inc()
+	metrics.SCMP.Duration(l).Observe(time.Since(rp.TimeIn).Seconds())
+}
+
+// observeSCMPEcho updates the metrics if the packet is an SCMP echo request or
+// reply. The router does not answer echo requests itself, they are forwarded
+// like any other packet, including the ones destined to the local dispatcher.
+func (rp *RtrPkt) observeSCMPEcho() {
+	if rp.idxs.nextHdrIdx.Type != common.L4SCMP || rp.ifCurr == nil {
+		return
+	}
+	l4h, err := rp.L4Hdr(false)
+	if err != nil {
+		return
+	}
+	hdr, ok := l4h.(*scmp.Hdr)
+	if !ok || hdr.Class != scmp.C_General {
+		return
+	}
+	switch hdr.Type {
+	case scmp.T_G_EchoRequest:
+		rp.observeSCMP(metrics.SCMPEchoRequest, metrics.SCMPForwarded)
+	case scmp.T_G_EchoReply:
+		rp.observeSCMP(metrics.SCMPEchoReply, metrics.SCMPForwarded)
+	}
+}
+
+func scmpResult(err error) string {
+	if err != nil {
+		return metrics.ErrProcess
+	}
+	return metrics.Success
+}
+
 // processSCMPRevocation handles SCMP revocations.
 // There are 3 cases where the router does more than just forward an SCMP revocation message.
 // 1. The revocation was received on a core interface, and the destination is in this ISD. In this
@@ -176,10 +238,9 @@ func (rp *RtrPkt) processSCMPRevocation() error {
 		return common.NewBasicError("Invalid payload type in SCMP packet", nil,
 			"expected", "*scmp.Payload", "actual", common.TypeOf(rp.pld))
 	}
-	infoRev, ok := pld.Info.(*scmp.InfoRevocation)
-	if !ok {
-		return common.NewBasicError("Invalid SCMP Info type in SCMP packet", nil,
-			"expected", "*scmp.InfoRevocation", "actual", common.TypeOf(pld.Info))
+	infoRev, err := pld.Revocation()
+	if err != nil {
+		return err
 	}
 	if args.SignedRevInfo, err = path_mgmt.NewSignedRevInfoFromRaw(infoRev.RawSRev); err != nil {
 		return common.NewBasicError(
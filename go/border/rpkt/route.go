@@ -19,6 +19,7 @@ package rpkt
 import (
 	"fmt"
 
+	"github.com/scionproto/scion/go/border/ifstate"
 	"github.com/scionproto/scion/go/border/internal/metrics"
 	"github.com/scionproto/scion/go/border/rcmn"
 	"github.com/scionproto/scion/go/lib/addr"
@@ -59,6 +60,7 @@ func (rp *RtrPkt) Route() error {
 		Result: metrics.Success,
 		IntfIn: rp.Ingress.IfLabel,
 	}
+	rp.dropAdminDown()
 	rp.RefInc(len(rp.Egress))
 	// Call all egress functions.
 	for _, epair := range rp.Egress {
@@ -66,9 +68,29 @@ func (rp *RtrPkt) Route() error {
 		l.IntfOut = epair.S.Label
 		metrics.Process.Pkts(l).Inc()
 	}
+	rp.observeSCMPEcho()
 	return nil
 }
 
+// dropAdminDown removes the egress entries of administratively disabled
+// interfaces.
+func (rp *RtrPkt) dropAdminDown() {
+	egress := rp.Egress[:0]
+	for _, epair := range rp.Egress {
+		if epair.S.Dir == rcmn.DirExternal && ifstate.AdminDown(epair.S.Ifid) {
+			l := metrics.ProcessLabels{
+				Result:  metrics.ErrAdminDown,
+				IntfIn:  rp.Ingress.IfLabel,
+				IntfOut: epair.S.Label,
+			}
+			metrics.Process.Pkts(l).Inc()
+			continue
+		}
+		egress = append(egress, epair)
+	}
+	rp.Egress = egress
+}
+
 // RouteResolveSVC is a hook to resolve SVC addresses for routing packets to the local ISD-AS.
 func (rp *RtrPkt) RouteResolveSVC() (HookResult, error) {
 	svc, ok := rp.dstHost.(addr.HostSVC)
@@ -234,10 +256,29 @@ func (rp *RtrPkt) forwardFromLocal() (HookResult, error) {
 			return HookError, err
 		}
 	}
-	rp.Egress = append(rp.Egress, EgressPair{S: rp.Ctx.ExtSockOut[*rp.ifCurr]})
+	rp.Egress = append(rp.Egress, EgressPair{S: rp.Ctx.ExtEgress(*rp.ifCurr, rp.flowHash())})
 	return HookContinue, nil
 }
 
+// flowHash returns an FNV-1a hash over the address header of the packet. It
+// keeps the packets of a flow on the same link of a bonded interface, which
+// avoids reordering.
+func (rp *RtrPkt) flowHash() uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+	if rp.idxs.path <= rp.idxs.dstIA || rp.idxs.path > len(rp.Raw) {
+		return 0
+	}
+	h := uint32(offset32)
+	for _, b := range rp.Raw[rp.idxs.dstIA:rp.idxs.path] {
+		h ^= uint32(b)
+		h *= prime32
+	}
+	return h
+}
+
 func (rp *RtrPkt) reprocess() (HookResult, error) {
 	// save
 	ctx := rp.Ctx
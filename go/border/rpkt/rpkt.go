@@ -56,6 +56,20 @@ func Init(rawSRevF func(RawSRevCallbackArgs)) {
 	callbacks.rawSRevF = rawSRevF
 }
 
+// traceRouteDisabled is the set of interfaces for which SCMP traceroute
+// requests are not answered.
+var traceRouteDisabled map[common.IFIDType]struct{}
+
+// DisableTraceRoute disables answering SCMP traceroute requests for the given
+// interfaces. Such requests are forwarded as if they were destined to another
+// hop. It must be called before packets are processed.
+func DisableTraceRoute(ifids ...common.IFIDType) {
+	traceRouteDisabled = make(map[common.IFIDType]struct{}, len(ifids))
+	for _, ifid := range ifids {
+		traceRouteDisabled[ifid] = struct{}{}
+	}
+}
+
 // Router representation of SCION packet, including metadata.  The comments for the members have
 // tags to specify if the member is set during receiving (RECV), parsing (PARSE), processing
 // (PROCESS) or routing (ROUTE). A number of the non-exported fields are pointers, as they are
@@ -96,6 +110,11 @@ type RtrPkt struct {
 	infoF *spath.InfoField
 	// hopF is the current Hop Field, if any. (PARSE)
 	hopF *spath.HopField
+	// hopFMacChecked is set if the MAC of hopF was verified ahead of
+	// validation, see SetHopFMacResult. (PARSE, only if batched)
+	hopFMacChecked bool
+	// hopFMacErr is the result of the MAC verification ahead of validation.
+	hopFMacErr error
 	// ifCurr is the current interface ID. (PARSE)
 	ifCurr *common.IFIDType
 	// ifNext is the next interface ID, if any. (PARSE)
@@ -229,6 +248,8 @@ func (rp *RtrPkt) Reset() {
 	rp.srcHost = nil
 	rp.infoF = nil
 	rp.hopF = nil
+	rp.hopFMacChecked = false
+	rp.hopFMacErr = nil
 	rp.ifCurr = nil
 	rp.ifNext = nil
 	rp.consDirFlag = nil
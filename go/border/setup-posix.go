@@ -113,13 +113,11 @@ func (p posixExt) Setup(r *Router, ctx *rctx.Ctx, intf *topology.IFInfo, oldCtx
 		if !interfaceChanged(intf, oldIntf) {
 			log.Trace("No change detected for external socket.", "conn",
 				intf.Local.BindOrPublicOverlay(ctx.Conf.Topo.Overlay))
-			ctx.ExtSockIn[intf.Id] = oldCtx.ExtSockIn[intf.Id]
-			ctx.ExtSockOut[intf.Id] = oldCtx.ExtSockOut[intf.Id]
+			copyIntfSocks(ctx, oldCtx, intf.Id)
 			return nil
 		}
 		log.Debug("Closing existing external socket", "old", oldIntf, "new", intf)
-		oldCtx.ExtSockIn[intf.Id].Stop()
-		oldCtx.ExtSockOut[intf.Id].Stop()
+		stopIntfSocks(oldCtx, intf.Id)
 	}
 	return p.addIntf(r, ctx, intf)
 }
@@ -138,8 +136,7 @@ func (p posixExt) Rollback(r *Router, ctx *rctx.Ctx, intf *topology.IFInfo,
 	// Stop new socket if it exists. It might not exist if the Setup failed.
 	if _, ok := ctx.ExtSockIn[intf.Id]; ok {
 		log.Debug("Rolling back external socket", "intf", intf)
-		ctx.ExtSockIn[intf.Id].Stop()
-		ctx.ExtSockOut[intf.Id].Stop()
+		stopIntfSocks(ctx, intf.Id)
 	}
 	// No need to start socket if it is not present in old context or still running.
 	// The socket is still running if setupNet failed before iterating over this socket.
@@ -153,19 +150,44 @@ func (p posixExt) addIntf(r *Router, ctx *rctx.Ctx, intf *topology.IFInfo) error
 
 	// Connect to remote address.
 	log.Debug("Setting up new external socket.", "intf", intf)
-	bind := intf.Local.BindOrPublicOverlay(intf.Local.Overlay)
-	c, err := conn.New(bind, intf.Remote, nil)
-	if err != nil {
-		return common.NewBasicError("Unable to listen on external socket", err)
+	links := intf.Links()
+	ins := make([]*rctx.Sock, 0, len(links))
+	outs := make([]*rctx.Sock, 0, len(links))
+	for i, link := range links {
+		bind := link.Local.BindOrPublicOverlay(link.Local.Overlay)
+		c, err := conn.New(bind, link.Remote, nil)
+		if err != nil {
+			// Close the sockets of the links that have already been set up.
+			for j := range ins {
+				ins[j].Stop()
+				outs[j].Stop()
+			}
+			return common.NewBasicError("Unable to listen on external socket", err,
+				"link", i)
+		}
+		// Setup input goroutine.
+		ins = append(ins, rctx.NewSock(
+			ringbuf.New(64, nil, linkRingName("ext_in", intf.Id, i)),
+			c, rcmn.DirExternal, intf.Id, intf.ISD_AS.String(), r.posixInput, r.handleSock,
+			PosixSock))
+		outs = append(outs, rctx.NewSock(
+			ringbuf.New(64, nil, linkRingName("ext_out", intf.Id, i)),
+			c, rcmn.DirExternal, intf.Id, intf.ISD_AS.String(), nil, r.posixOutput, PosixSock))
 	}
-	// Setup input goroutine.
-	ctx.ExtSockIn[intf.Id] = rctx.NewSock(
-		ringbuf.New(64, nil, fmt.Sprintf("ext_in_%s", intf.Id)),
-		c, rcmn.DirExternal, intf.Id, intf.ISD_AS.String(), r.posixInput, r.handleSock, PosixSock)
-	ctx.ExtSockOut[intf.Id] = rctx.NewSock(
-		ringbuf.New(64, nil, fmt.Sprintf("ext_out_%s", intf.Id)),
-		c, rcmn.DirExternal, intf.Id, intf.ISD_AS.String(), nil, r.posixOutput, PosixSock)
-	log.Debug("Done setting up new external socket.", "intf", intf)
+	ctx.ExtSockIn[intf.Id] = ins[0]
+	ctx.ExtSockOut[intf.Id] = outs[0]
+	if len(links) > 1 {
+		// Track the liveness of each link, such that the bond avoids links
+		// over which the peer is not reachable anymore.
+		for i := range ins {
+			live := rctx.NewLiveness(bondLinkTimeout)
+			ins[i].Liveness = live
+			outs[i].Liveness = live
+		}
+		ctx.ExtBondIn[intf.Id] = ins[1:]
+		ctx.ExtBondOut[intf.Id] = rctx.NewBond(outs...)
+	}
+	log.Debug("Done setting up new external socket.", "intf", intf, "links", len(links))
 	return nil
 }
 
@@ -175,15 +197,57 @@ func (p posixExt) Teardown(r *Router, ctx *rctx.Ctx, intf *topology.IFInfo, oldC
 	}
 	if _, ok := ctx.ExtSockIn[intf.Id]; !ok {
 		log.Debug("Tearing down socket from removed external interface", "intf", intf)
-		oldCtx.ExtSockIn[intf.Id].Stop()
-		oldCtx.ExtSockOut[intf.Id].Stop()
+		stopIntfSocks(oldCtx, intf.Id)
+	}
+}
+
+// linkRingName returns the ring buffer name for the socket of the link with
+// the given index. The primary link keeps the name of unbonded interfaces.
+func linkRingName(prefix string, ifid common.IFIDType, link int) string {
+	if link == 0 {
+		return fmt.Sprintf("%s_%s", prefix, ifid)
+	}
+	return fmt.Sprintf("%s_%s_%d", prefix, ifid, link)
+}
+
+// copyIntfSocks copies the sockets of all links of interface ifid from oldCtx
+// to ctx.
+func copyIntfSocks(ctx, oldCtx *rctx.Ctx, ifid common.IFIDType) {
+	ctx.ExtSockIn[ifid] = oldCtx.ExtSockIn[ifid]
+	ctx.ExtSockOut[ifid] = oldCtx.ExtSockOut[ifid]
+	if bondIn, ok := oldCtx.ExtBondIn[ifid]; ok {
+		ctx.ExtBondIn[ifid] = bondIn
+		ctx.ExtBondOut[ifid] = oldCtx.ExtBondOut[ifid]
+	}
+}
+
+// stopIntfSocks stops the sockets of all links of interface ifid.
+func stopIntfSocks(ctx *rctx.Ctx, ifid common.IFIDType) {
+	ctx.ExtSockIn[ifid].Stop()
+	ctx.ExtSockOut[ifid].Stop()
+	for _, s := range ctx.ExtBondIn[ifid] {
+		s.Stop()
+	}
+	if bond, ok := ctx.ExtBondOut[ifid]; ok {
+		// The first member is the primary output socket, which is already stopped.
+		for _, s := range bond.Socks[1:] {
+			s.Stop()
+		}
 	}
 }
 
 // interfaceChanged returns true if a new input goroutine is needed for the
 // corresponding interface.
 func interfaceChanged(newIntf *topology.IFInfo, oldIntf *topology.IFInfo) bool {
-	return (newIntf.Id != oldIntf.Id ||
-		!newIntf.Local.Equal(oldIntf.Local) ||
-		!newIntf.Remote.Equal(oldIntf.Remote))
+	if newIntf.Id != oldIntf.Id || len(newIntf.Bond) != len(oldIntf.Bond) {
+		return true
+	}
+	newLinks, oldLinks := newIntf.Links(), oldIntf.Links()
+	for i := range newLinks {
+		if !newLinks[i].Local.Equal(oldLinks[i].Local) ||
+			!newLinks[i].Remote.Equal(oldLinks[i].Remote) {
+			return true
+		}
+	}
+	return false
 }
@@ -28,10 +28,13 @@ import (
 	"github.com/syndtr/gocapability/capability"
 
 	"github.com/scionproto/scion/go/border/brconf"
+	"github.com/scionproto/scion/go/border/ifstate"
+	"github.com/scionproto/scion/go/border/internal/revdedup"
 	"github.com/scionproto/scion/go/border/rctx"
 	"github.com/scionproto/scion/go/border/rpkt"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/discovery"
+	"github.com/scionproto/scion/go/lib/env"
 	"github.com/scionproto/scion/go/lib/fatal"
 	"github.com/scionproto/scion/go/lib/infra/modules/idiscovery"
 	"github.com/scionproto/scion/go/lib/infra/modules/itopo"
@@ -65,9 +68,11 @@ func (r *Router) setup() error {
 	}, "free_pkts")
 	r.sRevInfoQ = make(chan rpkt.RawSRevCallbackArgs, 16)
 	r.pktErrorQ = make(chan pktErrorArgs, 16)
+	r.revDedup = revdedup.New(revdedup.DefaultInterval, revdedup.DefaultMaxEntries)
 
 	// Configure the rpkt package with the callbacks it needs.
 	rpkt.Init(r.RawSRevCallback)
+	rpkt.DisableTraceRoute(cfg.BR.DisableTraceRoute...)
 
 	// Load config.
 	var err error
@@ -88,6 +93,8 @@ func (r *Router) setup() error {
 	if err = r.clearCapabilities(); err != nil {
 		return err
 	}
+	env.HandleAdmin("/capture", r.tap.NewHTTPHandler(cfg.BR.CaptureDir))
+	env.HandleAdmin("/interfaces", ifstate.NewAdminHandler())
 	cfg.Metrics.StartPrometheus()
 	return nil
 }
@@ -343,6 +350,17 @@ func startSocks(ctx *rctx.Ctx) {
 	for _, s := range ctx.ExtSockOut {
 		s.Start()
 	}
+	// Start the additional links of bonded interfaces.
+	for _, socks := range ctx.ExtBondIn {
+		for _, s := range socks {
+			s.Start()
+		}
+	}
+	for _, bond := range ctx.ExtBondOut {
+		for _, s := range bond.Socks[1:] {
+			s.Start()
+		}
+	}
 }
 
 func handleRollbackErr(err error) {
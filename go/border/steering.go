@@ -0,0 +1,165 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file contains the steering of packets to processing workers, as well as
+// the pinning of the IO and processing goroutines to CPU cores.
+
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/scionproto/scion/go/border/internal/metrics"
+	"github.com/scionproto/scion/go/border/rctx"
+	"github.com/scionproto/scion/go/border/rpkt"
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/ringbuf"
+	"github.com/scionproto/scion/go/lib/spkt"
+)
+
+// nextCPU is the index of the next entry in the configured CPU list that a
+// goroutine is pinned to.
+var nextCPU uint32
+
+// pinThread locks the calling goroutine to its OS thread, and pins the thread
+// to the next configured CPU core. It does nothing if no CPU cores are
+// configured. The goroutine must not unlock the thread, such that the pinned
+// thread is terminated when the goroutine exits.
+func pinThread() {
+	cpus := cfg.BR.CPUs
+	if len(cpus) == 0 {
+		return
+	}
+	runtime.LockOSThread()
+	cpu := cpus[(atomic.AddUint32(&nextCPU, 1)-1)%uint32(len(cpus))]
+	if err := setAffinity(cpu); err != nil {
+		log.Warn("Unable to pin thread to CPU", "cpu", cpu, "err", err)
+	}
+}
+
+// steerSock distributes the packets read from the socket ring to workers
+// processing packets concurrently. The worker is selected by the flow hash of
+// the packet, such that packets of the same flow are processed in order.
+func (r *Router) steerSock(s *rctx.Sock, workers int) {
+	dst := s.Conn.LocalAddr()
+	log.Debug("handleSock starting", "addr", dst, "workers", workers)
+	rings := make([]*ringbuf.Ring, workers)
+	batches := make([]ringbuf.EntryList, workers)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := range rings {
+		rings[i] = ringbuf.New(processBufCnt, nil, fmt.Sprintf("worker_%s_%d", s.Label, i))
+		batches[i] = make(ringbuf.EntryList, 0, processBufCnt)
+		go func(ring *ringbuf.Ring) {
+			defer log.LogPanicAndExit()
+			defer wg.Done()
+			pinThread()
+			r.processRing(ring)
+		}(rings[i])
+	}
+	pkts := make(ringbuf.EntryList, processBufCnt)
+	for {
+		n, _ := s.Ring.Read(pkts, true)
+		if n < 0 {
+			break
+		}
+		for i := 0; i < n; i++ {
+			w := flowWorker(pkts[i].(*rpkt.RtrPkt).Raw, workers)
+			batches[w] = append(batches[w], pkts[i])
+			pkts[i] = nil
+		}
+		for i, batch := range batches {
+			for written := 0; written < len(batch); {
+				wn, _ := rings[i].Write(batch[written:], true)
+				written += wn
+			}
+			for j := range batch {
+				batch[j] = nil
+			}
+			batches[i] = batch[:0]
+		}
+	}
+	for _, ring := range rings {
+		ring.Close()
+	}
+	wg.Wait()
+	log.Debug("handleSock stopping", "addr", dst)
+}
+
+// processRing processes the packets read from ring, until ring is closed. The
+// packets read at once are processed as a batch: all packets are parsed
+// first, such that the hop field MACs of the batch can be verified together,
+// and then forwarded in order.
+func (r *Router) processRing(ring *ringbuf.Ring) {
+	pkts := make(ringbuf.EntryList, processBufCnt)
+	labels := make([]metrics.ProcessLabels, processBufCnt)
+	parsed := make([]bool, processBufCnt)
+	macs := newMacBatch(processBufCnt)
+	for {
+		n, _ := ring.Read(pkts, true)
+		if n < 0 {
+			return
+		}
+		for i := 0; i < n; i++ {
+			rp := pkts[i].(*rpkt.RtrPkt)
+			if labels[i], parsed[i] = r.parsePacket(rp); parsed[i] {
+				macs.add(rp)
+			}
+		}
+		macs.verify()
+		for i := 0; i < n; i++ {
+			rp := pkts[i].(*rpkt.RtrPkt)
+			if parsed[i] {
+				r.forwardPacket(rp, labels[i])
+			}
+			rp.Release()
+			pkts[i] = nil
+		}
+	}
+}
+
+// flowWorker returns the index of the worker that processes the raw packet.
+// The index is derived from a hash over the address header, i.e., the source
+// and destination ISD-AS and host addresses. Packets whose address header can
+// not be determined are steered to the first worker.
+func flowWorker(raw common.RawBytes, workers int) int {
+	var cmn spkt.CmnHdr
+	if err := cmn.Parse(raw); err != nil {
+		return 0
+	}
+	dstLen, err := addr.HostLen(cmn.DstType)
+	if err != nil {
+		return 0
+	}
+	srcLen, err := addr.HostLen(cmn.SrcType)
+	if err != nil {
+		return 0
+	}
+	end := spkt.CmnHdrLen + 2*addr.IABytes + int(dstLen) + int(srcLen)
+	if len(raw) < end {
+		return 0
+	}
+	// FNV-1a, computed inline to avoid allocations on the fast path.
+	h := uint32(2166136261)
+	for _, b := range raw[spkt.CmnHdrLen:end] {
+		h ^= uint32(b)
+		h *= 16777619
+	}
+	return int(h % uint32(workers))
+}
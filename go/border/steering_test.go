@@ -0,0 +1,74 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/spkt"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestFlowWorker(t *testing.T) {
+	ia1 := xtest.MustParseIA("1-ff00:0:110")
+	ia2 := xtest.MustParseIA("1-ff00:0:111")
+	h1 := addr.HostFromIP(net.IP{192, 0, 2, 1})
+	h2 := addr.HostFromIP(net.IP{192, 0, 2, 2})
+	const workers = 4
+
+	t.Run("Same flow is steered to the same worker", func(t *testing.T) {
+		a := newSteeringPkt(ia1, ia2, h1, h2, []byte{1, 2, 3})
+		b := newSteeringPkt(ia1, ia2, h1, h2, []byte{4, 5, 6, 7})
+		assert.Equal(t, flowWorker(a, workers), flowWorker(b, workers))
+	})
+	t.Run("Flows are spread over workers", func(t *testing.T) {
+		seen := make(map[int]struct{})
+		for i := 1; i < 64; i++ {
+			src := addr.HostFromIP(net.IP{192, 0, 2, byte(i)})
+			raw := newSteeringPkt(ia1, ia2, h1, src, nil)
+			w := flowWorker(raw, workers)
+			assert.True(t, w >= 0 && w < workers)
+			seen[w] = struct{}{}
+		}
+		assert.Len(t, seen, workers)
+	})
+	t.Run("Truncated packet is steered to the first worker", func(t *testing.T) {
+		raw := newSteeringPkt(ia1, ia2, h1, h2, nil)
+		assert.Equal(t, 0, flowWorker(raw[:spkt.CmnHdrLen+addr.IABytes], workers))
+		assert.Equal(t, 0, flowWorker(raw[:spkt.CmnHdrLen-1], workers))
+	})
+	t.Run("Single worker", func(t *testing.T) {
+		raw := newSteeringPkt(ia1, ia2, h1, h2, nil)
+		assert.Equal(t, 0, flowWorker(raw, 1))
+	})
+}
+
+func newSteeringPkt(dstIA, srcIA addr.IA, dst, src addr.HostAddr,
+	payload []byte) common.RawBytes {
+
+	cmn := spkt.CmnHdr{DstType: dst.Type(), SrcType: src.Type()}
+	raw := make(common.RawBytes, spkt.CmnHdrLen+2*addr.IABytes)
+	cmn.Write(raw)
+	dstIA.Write(raw[spkt.CmnHdrLen:])
+	srcIA.Write(raw[spkt.CmnHdrLen+addr.IABytes:])
+	raw = append(raw, dst.Pack()...)
+	raw = append(raw, src.Pack()...)
+	return append(raw, payload...)
+}
@@ -16,11 +16,14 @@ package config
 
 import (
 	"io"
+	"path/filepath"
 	"time"
 
+	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/config"
 	"github.com/scionproto/scion/go/lib/env"
 	"github.com/scionproto/scion/go/lib/infra/modules/idiscovery"
+	"github.com/scionproto/scion/go/lib/keyconf"
 	"github.com/scionproto/scion/go/lib/scrypto/cert"
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/truststorage"
@@ -38,6 +41,12 @@ const (
 	ReissReqRate = 10 * time.Second
 	// ReissueReqTimeout is the default timeout of a reissue request.
 	ReissueReqTimeout = 5 * time.Second
+	// ReissMaxBackoff is the default upper bound of the backoff between two
+	// consecutive reissue requests after failed attempts.
+	ReissMaxBackoff = 5 * time.Minute
+	// ExpiryCritTime is the default time before leaf cert expiration at which
+	// the health check starts failing.
+	ExpiryCritTime = 1 * time.Hour
 
 	ErrorKeyConf   = "Unable to load KeyConf"
 	ErrorCustomers = "Unable to load Customers"
@@ -46,16 +55,17 @@ const (
 var _ config.Config = (*Config)(nil)
 
 type Config struct {
-	General   env.General
-	Features  env.Features
-	Logging   env.Logging
-	Metrics   env.Metrics
-	Tracing   env.Tracing
-	QUIC      env.QUIC         `toml:"quic"`
-	Sciond    env.SciondClient `toml:"sd_client"`
-	TrustDB   truststorage.TrustDBConf
-	Discovery idiscovery.Config
-	CS        CSConfig
+	General        env.General
+	Features       env.Features
+	Logging        env.Logging
+	Metrics        env.Metrics
+	Tracing        env.Tracing
+	QUIC           env.QUIC           `toml:"quic"`
+	CircuitBreaker env.CircuitBreaker `toml:"circuit_breaker"`
+	Sciond         env.SciondClient   `toml:"sd_client"`
+	TrustDB        truststorage.TrustDBConf
+	Discovery      idiscovery.Config
+	CS             CSConfig
 }
 
 func (cfg *Config) InitDefaults() {
@@ -78,6 +88,8 @@ func (cfg *Config) Validate() error {
 		&cfg.Features,
 		&cfg.Logging,
 		&cfg.Metrics,
+		&cfg.QUIC,
+		&cfg.CircuitBreaker,
 		&cfg.Sciond,
 		&cfg.TrustDB,
 		&cfg.Discovery,
@@ -94,6 +106,7 @@ func (cfg *Config) Sample(dst io.Writer, path config.Path, _ config.CtxMap) {
 		&cfg.Metrics,
 		&cfg.Tracing,
 		&cfg.QUIC,
+		&cfg.CircuitBreaker,
 		&cfg.TrustDB,
 		&cfg.Discovery,
 		&cfg.CS,
@@ -104,6 +117,21 @@ func (cfg *Config) ConfigName() string {
 	return "cs_config"
 }
 
+// CheckFiles checks that the topology and the key files referenced by the
+// config can be loaded. Core ASes additionally require the issuer and online
+// keys.
+func (cfg *Config) CheckFiles() error {
+	topo, err := cfg.General.LoadTopology()
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(cfg.General.ConfigDir, "keys")
+	if _, err := keyconf.Load(dir, topo.Core, topo.Core, false, true); err != nil {
+		return common.NewBasicError(ErrorKeyConf, err, "dir", dir)
+	}
+	return nil
+}
+
 var _ config.Config = (*CSConfig)(nil)
 
 type CSConfig struct {
@@ -117,6 +145,12 @@ type CSConfig struct {
 	ReissueRate util.DurWrap
 	// ReissueTimeout is the timeout for resissue request.
 	ReissueTimeout util.DurWrap
+	// ReissueMaxBackoff is the upper bound of the exponential backoff between
+	// two consecutive reissue requests after failed attempts.
+	ReissueMaxBackoff util.DurWrap
+	// ExpiryCriticalTime indicates how long in advance of leaf cert expiration
+	// the health check reports the certificate server as unhealthy.
+	ExpiryCriticalTime util.DurWrap
 	// AutomaticRenewal whether automatic reissuing is enabled.
 	AutomaticRenewal bool
 	// DisableCorePush disables the core pusher task.
@@ -136,6 +170,12 @@ func (cfg *CSConfig) InitDefaults() {
 	if cfg.ReissueTimeout.Duration == 0 {
 		cfg.ReissueTimeout.Duration = ReissueReqTimeout
 	}
+	if cfg.ReissueMaxBackoff.Duration == 0 {
+		cfg.ReissueMaxBackoff.Duration = ReissMaxBackoff
+	}
+	if cfg.ExpiryCriticalTime.Duration == 0 {
+		cfg.ExpiryCriticalTime.Duration = ExpiryCritTime
+	}
 }
 
 func (cfg *CSConfig) Validate() error {
@@ -151,6 +191,15 @@ func (cfg *CSConfig) Validate() error {
 	if cfg.ReissueTimeout.Duration == 0 {
 		return serrors.New("ReissueTimeout must not be zero")
 	}
+	if cfg.ReissueMaxBackoff.Duration < cfg.ReissueRate.Duration {
+		return serrors.New("ReissueMaxBackoff must not be smaller than ReissueRate")
+	}
+	if cfg.ExpiryCriticalTime.Duration == 0 {
+		return serrors.New("ExpiryCriticalTime must not be zero")
+	}
+	if cfg.ExpiryCriticalTime.Duration >= cfg.LeafReissueLeadTime.Duration {
+		return serrors.New("ExpiryCriticalTime must be smaller than LeafReissueLeadTime")
+	}
 	return nil
 }
 
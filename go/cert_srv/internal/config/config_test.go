@@ -51,6 +51,8 @@ func TestConfig_InitDefaults(t *testing.T) {
 		assert.Equal(t, 48*time.Hour, cfg.CS.IssuerReissueLeadTime.Duration)
 		assert.Equal(t, 12*time.Second, cfg.CS.ReissueRate.Duration)
 		assert.Equal(t, 6*time.Second, cfg.CS.ReissueTimeout.Duration)
+		assert.Equal(t, 10*time.Minute, cfg.CS.ReissueMaxBackoff.Duration)
+		assert.Equal(t, 2*time.Hour, cfg.CS.ExpiryCriticalTime.Duration)
 		assert.True(t, cfg.CS.AutomaticRenewal)
 		assert.True(t, cfg.CS.DisableCorePush)
 	})
@@ -65,6 +67,8 @@ func TestConfig_InitDefaults(t *testing.T) {
 		assert.Equal(t, IssuerReissTime, cfg.CS.IssuerReissueLeadTime.Duration)
 		assert.Equal(t, ReissReqRate, cfg.CS.ReissueRate.Duration)
 		assert.Equal(t, ReissueReqTimeout, cfg.CS.ReissueTimeout.Duration)
+		assert.Equal(t, ReissMaxBackoff, cfg.CS.ReissueMaxBackoff.Duration)
+		assert.Equal(t, ExpiryCritTime, cfg.CS.ExpiryCriticalTime.Duration)
 		assert.False(t, cfg.CS.AutomaticRenewal)
 		assert.False(t, cfg.CS.DisableCorePush)
 	})
@@ -84,6 +88,7 @@ func InitTestCSConfig(cfg *CSConfig) {
 
 func CheckTestConfig(t *testing.T, cfg *Config, id string) {
 	envtest.CheckTest(t, &cfg.General, &cfg.Logging, &cfg.Metrics, &cfg.Tracing, &cfg.Sciond, id)
+	envtest.CheckTestCircuitBreaker(t, &cfg.CircuitBreaker)
 	truststoragetest.CheckTestConfig(t, &cfg.TrustDB, id)
 	idiscoverytest.CheckTestConfig(t, &cfg.Discovery)
 	CheckTestCSConfig(t, &cfg.CS)
@@ -92,6 +97,8 @@ func CheckTestConfig(t *testing.T, cfg *Config, id string) {
 func CheckTestCSConfig(t *testing.T, cfg *CSConfig) {
 	assert.Equal(t, ReissReqRate, cfg.ReissueRate.Duration)
 	assert.Equal(t, ReissueReqTimeout, cfg.ReissueTimeout.Duration)
+	assert.Equal(t, ReissMaxBackoff, cfg.ReissueMaxBackoff.Duration)
+	assert.Equal(t, ExpiryCritTime, cfg.ExpiryCriticalTime.Duration)
 	assert.False(t, cfg.AutomaticRenewal)
 	assert.Equal(t, LeafReissTime, cfg.LeafReissueLeadTime.Duration)
 	assert.Equal(t, IssuerReissTime, cfg.IssuerReissueLeadTime.Duration)
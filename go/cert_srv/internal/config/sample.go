@@ -32,6 +32,14 @@ ReissueRate = "10s"
 # Timeout for resissue request. (default 5s)
 ReissueTimeout = "5s"
 
+# Upper bound of the exponential backoff between two consecutive reissue
+# requests after failed attempts. (default 5m)
+ReissueMaxBackoff = "5m"
+
+# Time before leaf cert expiration at which the health check starts failing.
+# Must be smaller than LeafReissueLeadTime. (default 1h)
+ExpiryCriticalTime = "1h"
+
 # Whether automatic reissuing is enabled. (default false)
 AutomaticRenewal = false
 
@@ -17,7 +17,9 @@ package config
 import (
 	"path/filepath"
 	"sync"
+	"time"
 
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/infra"
 	"github.com/scionproto/scion/go/lib/infra/modules/trust"
@@ -30,6 +32,9 @@ type State struct {
 	Store *trust.Store
 	// TrustDB is the trust DB.
 	TrustDB trustdb.TrustDB
+	// SignKeys holds the versioned AS signing keys. Reloading it picks up
+	// new keys without a restart.
+	SignKeys *keyconf.Ring
 	// keyConf contains the AS level keys.
 	keyConf *keyconf.Conf
 	// keyConfLock guards KeyConf.
@@ -44,31 +49,60 @@ type State struct {
 	verifierLock sync.RWMutex
 }
 
-func LoadState(confDir string, isCore bool, trustDB trustdb.TrustDB,
+func LoadState(confDir string, ia addr.IA, isCore bool, trustDB trustdb.TrustDB,
 	trustStore *trust.Store) (*State, error) {
 
 	s := &State{
 		Store:   trustStore,
 		TrustDB: trustDB,
 	}
-	if err := s.loadKeyConf(confDir, isCore); err != nil {
+	if err := s.loadKeyConf(confDir, ia, isCore); err != nil {
 		return nil, err
 	}
 	return s, nil
 }
 
 // loadKeyConf loads the key configuration.
-func (s *State) loadKeyConf(confDir string, isCore bool) error {
+func (s *State) loadKeyConf(confDir string, ia addr.IA, isCore bool) error {
 	var err error
-	s.keyConf, err = keyconf.Load(filepath.Join(confDir, "keys"), isCore, isCore, false, true)
+	dir := filepath.Join(confDir, "keys")
+	s.keyConf, err = keyconf.Load(dir, isCore, isCore, false, true)
+	if err != nil {
+		return common.NewBasicError(ErrorKeyConf, err)
+	}
+	s.SignKeys, err = keyconf.NewRing(dir, keyconf.PrivateKey, keyconf.ASSigningKey, ia)
 	if err != nil {
 		return common.NewBasicError(ErrorKeyConf, err)
 	}
 	return nil
 }
 
-// GetSigningKey returns the signing key of the current key configuration.
+// GetSigningKey returns the signing key that should be used for new
+// certificates. This is the active key of the signing key ring, if there is
+// one, and the signing key of the current key configuration otherwise.
 func (s *State) GetSigningKey() common.RawBytes {
+	if key, err := s.SignKeys.Active(time.Now()); err == nil {
+		if raw, err := key.SigningKey(); err == nil {
+			return raw
+		}
+	}
+	s.keyConfLock.RLock()
+	defer s.keyConfLock.RUnlock()
+	return s.keyConf.SignKey
+}
+
+// GetSigningKeyFor returns the signing key that belongs to the public key
+// pub, e.g., the subject signing key of a certificate. Keys in the signing
+// key ring that are still valid are considered, such that certificates
+// issued for the previous key can be used during a key rollover. If no key
+// in the ring matches, the signing key of the current key configuration is
+// returned.
+func (s *State) GetSigningKeyFor(pub common.RawBytes) common.RawBytes {
+	if key, err := s.SignKeys.Matching(time.Now(), pub); err == nil {
+		if raw, err := key.SigningKey(); err == nil {
+			return raw
+		}
+	}
 	s.keyConfLock.RLock()
 	defer s.keyConfLock.RUnlock()
 	return s.keyConf.SignKey
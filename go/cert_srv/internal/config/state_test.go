@@ -21,6 +21,7 @@ import (
 
 	"github.com/scionproto/scion/go/lib/keyconf"
 	"github.com/scionproto/scion/go/lib/scrypto"
+	"github.com/scionproto/scion/go/lib/xtest"
 )
 
 func TestLoadState(t *testing.T) {
@@ -31,8 +32,9 @@ func TestLoadState(t *testing.T) {
 	asSig, _ := keyconf.LoadKey("testdata/keys/as-sig.seed", scrypto.Ed25519)
 	issSig, _ := keyconf.LoadKey("testdata/keys/core-sig.seed", scrypto.Ed25519)
 	online, _ := keyconf.LoadKey("testdata/keys/online-root.seed", scrypto.Ed25519)
+	ia := xtest.MustParseIA("1-ff00:0:110")
 	Convey("Load core state", t, func() {
-		state, err := LoadState("testdata", true, nil, nil)
+		state, err := LoadState("testdata", ia, true, nil, nil)
 		SoMsg("err", err, ShouldBeNil)
 		SoMsg("Master0", state.keyConf.Master.Key0, ShouldResemble, mstr0)
 		SoMsg("Master1", state.keyConf.Master.Key1, ShouldResemble, mstr1)
@@ -44,7 +46,7 @@ func TestLoadState(t *testing.T) {
 	})
 
 	Convey("Load non-core state", t, func() {
-		state, err := LoadState("testdata", false, nil, nil)
+		state, err := LoadState("testdata", ia, false, nil, nil)
 		SoMsg("err", err, ShouldBeNil)
 		SoMsg("Master0", state.keyConf.Master.Key0, ShouldResemble, mstr0)
 		SoMsg("Master1", state.keyConf.Master.Key1, ShouldResemble, mstr1)
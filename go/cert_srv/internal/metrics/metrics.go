@@ -16,3 +16,8 @@ package metrics
 
 // Namespace is the metrics namespace for the certificate server.
 const Namespace = "cs"
+
+var (
+	// Reiss is the single-instance struct to get reissuance metrics.
+	Reiss = newReiss()
+)
@@ -0,0 +1,32 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/scionproto/scion/go/cert_srv/internal/metrics"
+	"github.com/scionproto/scion/go/lib/prom/promtest"
+)
+
+func TestLabels(t *testing.T) {
+	tests := []interface{}{
+		metrics.CertLabels{},
+		metrics.ReissLabels{},
+	}
+	for _, test := range tests {
+		promtest.CheckLabelsStruct(t, test)
+	}
+}
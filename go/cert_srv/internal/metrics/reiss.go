@@ -0,0 +1,109 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/scionproto/scion/go/lib/prom"
+)
+
+// Certificate types.
+const (
+	// CertLeaf indicates the leaf certificate of the local AS.
+	CertLeaf = "leaf"
+	// CertIssuer indicates the issuer certificate of the local AS.
+	CertIssuer = "issuer"
+)
+
+// Reissue results.
+const (
+	// Success indicates a successful reissuance.
+	Success = prom.Success
+	// ErrCrypto indicates an error while signing or verifying.
+	ErrCrypto = prom.ErrCrypto
+	// ErrDB indicates an error while reading or writing the trust database.
+	ErrDB = prom.ErrDB
+	// ErrReply indicates an invalid reissue reply.
+	ErrReply = prom.ErrReply
+	// ErrInternal indicates an internal error.
+	ErrInternal = prom.ErrInternal
+	// ErrRequest indicates that the reissue request failed.
+	ErrRequest = "err_request"
+)
+
+// CertLabels contains the labels for the certificate expiration metrics.
+type CertLabels struct {
+	Cert string
+}
+
+// Labels returns the name of the labels in correct order.
+func (l CertLabels) Labels() []string {
+	return []string{"cert"}
+}
+
+// Values returns the values of the label in correct order.
+func (l CertLabels) Values() []string {
+	return []string{l.Cert}
+}
+
+// ReissLabels contains the labels for the reissuance result metrics.
+type ReissLabels struct {
+	CertLabels
+	Result string
+}
+
+// Labels returns the name of the labels in correct order.
+func (l ReissLabels) Labels() []string {
+	return append(l.CertLabels.Labels(), prom.LabelResult)
+}
+
+// Values returns the values of the label in correct order.
+func (l ReissLabels) Values() []string {
+	return append(l.CertLabels.Values(), l.Result)
+}
+
+type reiss struct {
+	timeToExpiry *prometheus.GaugeVec
+	attempts     *prometheus.CounterVec
+	backoff      prometheus.Gauge
+}
+
+func newReiss() reiss {
+	sub := "reiss"
+	return reiss{
+		timeToExpiry: prom.NewGaugeVec(Namespace, sub, "time_to_expiry_seconds",
+			"Time until the active certificate expires.", CertLabels{}.Labels()),
+		attempts: prom.NewCounterVec(Namespace, sub, "attempts_total",
+			"Total number of reissue attempts.", ReissLabels{}.Labels()),
+		backoff: prom.NewGauge(Namespace, sub, "backoff_seconds",
+			"Current backoff before the next reissue request."),
+	}
+}
+
+// TimeToExpiry returns the gauge for the time to expiry of the certificate.
+func (r *reiss) TimeToExpiry(l CertLabels) prometheus.Gauge {
+	return r.timeToExpiry.WithLabelValues(l.Values()...)
+}
+
+// Attempts returns the counter for reissue attempts.
+func (r *reiss) Attempts(l ReissLabels) prometheus.Counter {
+	return r.attempts.WithLabelValues(l.Values()...)
+}
+
+// Backoff returns the gauge for the current reissue request backoff.
+func (r *reiss) Backoff() prometheus.Gauge {
+	return r.backoff
+}
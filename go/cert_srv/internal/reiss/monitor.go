@@ -0,0 +1,142 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reiss
+
+import (
+	"context"
+	"time"
+
+	"github.com/scionproto/scion/go/cert_srv/internal/metrics"
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/infra/modules/trust/trustdb"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/periodic"
+	"github.com/scionproto/scion/go/lib/util"
+)
+
+var _ periodic.Task = (*ExpiryMonitor)(nil)
+
+// ExpiryMonitor monitors the expiration time of the local certificates. It
+// exports the time to expiry as metrics and provides a health check that fails
+// if the certificate chain is about to expire without being reissued.
+type ExpiryMonitor struct {
+	TrustDB trustdb.TrustDB
+	IA      addr.IA
+	// CritTime indicates how long in advance of the leaf certificate
+	// expiration the health check starts failing.
+	CritTime time.Duration
+}
+
+// Name returns the tasks name.
+func (m *ExpiryMonitor) Name() string {
+	return "reiss.ExpiryMonitor"
+}
+
+// Run updates the expiration metrics and logs if the certificate chain is
+// about to expire.
+func (m *ExpiryMonitor) Run(ctx context.Context) {
+	if err := m.Check(ctx); err != nil {
+		log.FromCtx(ctx).Crit("[reiss.ExpiryMonitor] Certificate chain check failed",
+			"err", err)
+	}
+}
+
+// Check updates the expiration metrics. It returns an error if the local
+// certificate chain is missing, expired, or expires within the critical time.
+// Check can be registered as a health check.
+func (m *ExpiryMonitor) Check(ctx context.Context) error {
+	now := time.Now()
+	issCrt, err := m.TrustDB.GetIssCertMaxVersion(ctx, m.IA)
+	if err != nil {
+		return common.NewBasicError("Unable to get issuer certificate", err)
+	}
+	if issCrt != nil {
+		setTimeToExpiry(metrics.CertIssuer, issCrt.ExpirationTime, now)
+	}
+	chain, err := m.TrustDB.GetChainMaxVersion(ctx, m.IA)
+	if err != nil {
+		return common.NewBasicError("Unable to get certificate chain", err)
+	}
+	if chain == nil {
+		return common.NewBasicError("Certificate chain not found", nil, "ia", m.IA)
+	}
+	setTimeToExpiry(metrics.CertLeaf, chain.Leaf.ExpirationTime, now)
+	exp := util.SecsToTime(chain.Leaf.ExpirationTime)
+	if now.After(exp) {
+		return common.NewBasicError("Certificate chain expired", nil,
+			"version", chain.Leaf.Version, "expTime", util.TimeToCompact(exp))
+	}
+	if now.Add(m.CritTime).After(exp) {
+		return common.NewBasicError("Certificate chain about to expire", nil,
+			"version", chain.Leaf.Version, "expTime", util.TimeToCompact(exp),
+			"critTime", m.CritTime)
+	}
+	return nil
+}
+
+// backoff tracks failed reissue requests and computes the exponential backoff
+// until the next attempt. The backoff is counted in runs of the periodic task
+// instead of wall-clock time, such that it is not affected by ticker jitter.
+type backoff struct {
+	failures uint
+	skip     uint
+}
+
+// ready indicates whether the current run is allowed to attempt a request. A
+// run that is not allowed counts towards the backoff.
+func (b *backoff) ready() bool {
+	if b.skip > 0 {
+		b.skip--
+		return false
+	}
+	return true
+}
+
+// fail registers a failed attempt and returns the number of runs until the
+// next attempt is allowed. The backoff starts at 2 runs and is doubled after
+// each consecutive failure, bounded by max. A max smaller than 2 disables the
+// backoff.
+func (b *backoff) fail(max uint) uint {
+	if max < 2 {
+		return 1
+	}
+	n := uint(2)
+	for i := uint(0); i < b.failures && n < max; i++ {
+		n *= 2
+	}
+	if n > max {
+		n = max
+	}
+	b.failures++
+	b.skip = n - 1
+	return n
+}
+
+// reset clears the failed attempts.
+func (b *backoff) reset() {
+	b.failures = 0
+	b.skip = 0
+}
+
+func setTimeToExpiry(crt string, exp uint32, now time.Time) {
+	l := metrics.CertLabels{Cert: crt}
+	metrics.Reiss.TimeToExpiry(l).Set(util.SecsToTime(exp).Sub(now).Seconds())
+}
+
+func incAttempts(crt, result string) {
+	l := metrics.ReissLabels{CertLabels: metrics.CertLabels{Cert: crt}, Result: result}
+	metrics.Reiss.Attempts(l).Inc()
+}
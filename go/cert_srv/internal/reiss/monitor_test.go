@@ -0,0 +1,97 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reiss
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/infra/modules/trust/trustdb/mock_trustdb"
+	"github.com/scionproto/scion/go/lib/scrypto/cert"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/util"
+)
+
+func TestExpiryMonitorCheck(t *testing.T) {
+	now := time.Now()
+	chainExp := func(d time.Duration) *cert.Chain {
+		return &cert.Chain{
+			Leaf: &cert.Certificate{ExpirationTime: util.TimeToSecs(now.Add(d))},
+		}
+	}
+	tests := map[string]struct {
+		Chain     *cert.Chain
+		ChainErr  error
+		ExpectErr bool
+	}{
+		"valid chain": {
+			Chain: chainExp(3 * time.Hour),
+		},
+		"about to expire": {
+			Chain:     chainExp(30 * time.Minute),
+			ExpectErr: true,
+		},
+		"expired": {
+			Chain:     chainExp(-time.Minute),
+			ExpectErr: true,
+		},
+		"missing chain": {
+			ExpectErr: true,
+		},
+		"db error": {
+			ChainErr:  serrors.New("test error"),
+			ExpectErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			db := mock_trustdb.NewMockTrustDB(ctrl)
+			db.EXPECT().GetIssCertMaxVersion(gomock.Any(), localIA).Return(nil, nil)
+			db.EXPECT().GetChainMaxVersion(gomock.Any(), localIA).Return(
+				test.Chain, test.ChainErr)
+			m := &ExpiryMonitor{TrustDB: db, IA: localIA, CritTime: time.Hour}
+			err := m.Check(context.Background())
+			if test.ExpectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	var b backoff
+	assert.True(t, b.ready())
+	for _, exp := range []uint{2, 4, 5, 5} {
+		assert.Equal(t, exp, b.fail(5))
+		for i := uint(1); i < exp; i++ {
+			assert.False(t, b.ready())
+		}
+		assert.True(t, b.ready())
+	}
+	b.reset()
+	assert.True(t, b.ready())
+	assert.Equal(t, uint(2), b.fail(5))
+	var disabled backoff
+	assert.Equal(t, uint(1), disabled.fail(1))
+	assert.True(t, disabled.ready())
+}
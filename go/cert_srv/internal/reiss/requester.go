@@ -22,6 +22,7 @@ import (
 	"golang.org/x/crypto/ed25519"
 
 	"github.com/scionproto/scion/go/cert_srv/internal/config"
+	"github.com/scionproto/scion/go/cert_srv/internal/metrics"
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/ctrl/cert_mgmt"
@@ -40,12 +41,20 @@ var _ periodic.Task = (*Requester)(nil)
 
 // Requester requests reissued certificate chains before
 // expiration of the currently active certificate chain.
+// After a failed request, further requests are delayed by an exponential
+// backoff starting at twice the Interval and bounded by MaxBackoff.
 type Requester struct {
-	Msgr       infra.Messenger
-	State      *config.State
-	IA         addr.IA
-	LeafTime   time.Duration
+	Msgr     infra.Messenger
+	State    *config.State
+	IA       addr.IA
+	LeafTime time.Duration
+	// Interval is the interval in which the task is run. The backoff is a
+	// multiple of it.
+	Interval   time.Duration
+	MaxBackoff time.Duration
 	CorePusher *periodic.Runner
+
+	backoff backoff
 }
 
 // Name returns the tasks name.
@@ -73,6 +82,7 @@ func (r *Requester) run(ctx context.Context) (bool, error) {
 	}
 	exp := util.SecsToTime(chain.Leaf.ExpirationTime)
 	now := time.Now()
+	setTimeToExpiry(metrics.CertLeaf, chain.Leaf.ExpirationTime, now)
 	if now.After(exp) {
 		return true, common.NewBasicError("Certificate expired without being reissued", nil,
 			"chain", chain, "expTime", util.TimeToCompact(exp), "now", util.TimeToString(now))
@@ -80,7 +90,27 @@ func (r *Requester) run(ctx context.Context) (bool, error) {
 	if now.Add(r.LeafTime).Before(exp) {
 		return false, nil
 	}
-	return r.sendReq(ctx, chain)
+	if !r.backoff.ready() {
+		log.FromCtx(ctx).Trace("[reiss.Requester] Backing off", "skip", r.backoff.skip)
+		return false, nil
+	}
+	crit, err := r.sendReq(ctx, chain)
+	if err != nil {
+		runs := r.backoff.fail(r.maxBackoffRuns())
+		metrics.Reiss.Backoff().Set((time.Duration(runs) * r.Interval).Seconds())
+		return crit, err
+	}
+	r.backoff.reset()
+	metrics.Reiss.Backoff().Set(0)
+	return false, nil
+}
+
+// maxBackoffRuns returns the upper bound of the backoff in runs of the task.
+func (r *Requester) maxBackoffRuns() uint {
+	if r.Interval <= 0 {
+		return 0
+	}
+	return uint(r.MaxBackoff / r.Interval)
 }
 
 // sendReq creates and sends a certificate chain reissue request based on the newest
@@ -91,17 +121,29 @@ func (r *Requester) sendReq(ctx context.Context, chain *cert.Chain) (bool, error
 	c.IssuingTime = util.TimeToSecs(time.Now())
 	c.ExpirationTime = c.IssuingTime + (chain.Leaf.ExpirationTime - chain.Leaf.IssuingTime)
 	c.Version++
-	if err := c.Sign(r.State.GetSigningKey(), chain.Leaf.SignAlgorithm); err != nil {
+	// Request the certificate for the active signing key. This rolls over to
+	// a new key once it becomes active in the signing key ring.
+	signKey := r.State.GetSigningKey()
+	pub, err := scrypto.GetPubKey(signKey, chain.Leaf.SignAlgorithm)
+	if err != nil {
+		incAttempts(metrics.CertLeaf, metrics.ErrCrypto)
+		return true, common.NewBasicError("Unable to derive public key", err)
+	}
+	c.SubjectSignKey = pub
+	if err := c.Sign(signKey, chain.Leaf.SignAlgorithm); err != nil {
+		incAttempts(metrics.CertLeaf, metrics.ErrCrypto)
 		return true, common.NewBasicError("Unable to sign certificate", err)
 	}
 	raw, err := c.JSON(false)
 	if err != nil {
+		incAttempts(metrics.CertLeaf, metrics.ErrInternal)
 		return false, common.NewBasicError("Unable to pack certificate", err)
 	}
 	req := &cert_mgmt.ChainIssReq{RawCert: raw}
 	a := &snet.Addr{IA: c.Issuer, Host: addr.NewSVCUDPAppAddr(addr.SvcCS)}
 	rep, err := r.Msgr.RequestChainIssue(ctx, req, a, messenger.NextId())
 	if err != nil {
+		incAttempts(metrics.CertLeaf, metrics.ErrRequest)
 		return false, common.NewBasicError("Unable to request reissued certificate chain", err)
 	}
 	logger.Trace("[reiss.Requester] Received certificate reissue reply", "addr", a, "rep", rep)
@@ -115,26 +157,34 @@ func (r *Requester) handleRep(ctx context.Context, rep *cert_mgmt.ChainIssRep) (
 	logger := log.FromCtx(ctx)
 	chain, err := rep.Chain()
 	if err != nil {
+		incAttempts(metrics.CertLeaf, metrics.ErrReply)
 		return false, common.NewBasicError("Unable to parse chain", err)
 	}
 	if err = r.validateRep(ctx, chain); err != nil {
+		incAttempts(metrics.CertLeaf, metrics.ErrReply)
 		return true, common.NewBasicError("Unable to validate chain", err, "chain", chain)
 	}
 	if _, err = r.State.TrustDB.InsertChain(ctx, chain); err != nil {
+		incAttempts(metrics.CertLeaf, metrics.ErrDB)
 		return true, common.NewBasicError("Unable to insert reissued certificate chain in TrustDB",
 			err, "chain", chain)
 	}
 	meta, err := trust.CreateSignMeta(ctx, r.IA, r.State.TrustDB)
 	if err != nil {
+		incAttempts(metrics.CertLeaf, metrics.ErrDB)
 		return true, common.NewBasicError("Unable create sign meta", err)
 	}
-	signer, err := trust.NewBasicSigner(r.State.GetSigningKey(), meta)
+	signer, err := trust.NewBasicSigner(
+		r.State.GetSigningKeyFor(chain.Leaf.SubjectSignKey), meta)
 	if err != nil {
+		incAttempts(metrics.CertLeaf, metrics.ErrCrypto)
 		return true, common.NewBasicError("Unable to create new signer", err)
 	}
 	r.State.SetSigner(signer)
 	r.Msgr.UpdateSigner(signer, []infra.MessageType{infra.ChainIssueRequest})
 	logger.Info("[reiss.Requester] Updated certificate chain", "chain", chain)
+	incAttempts(metrics.CertLeaf, metrics.Success)
+	setTimeToExpiry(metrics.CertLeaf, chain.Leaf.ExpirationTime, time.Now())
 	if r.CorePusher != nil {
 		r.CorePusher.TriggerRun()
 	}
@@ -144,7 +194,7 @@ func (r *Requester) handleRep(ctx context.Context, rep *cert_mgmt.ChainIssRep) (
 // validateRep validates that the received certificate chain can be added to the trust store.
 func (r *Requester) validateRep(ctx context.Context, chain *cert.Chain) error {
 	verKey := common.RawBytes(ed25519.PrivateKey(
-		r.State.GetSigningKey()).Public().(ed25519.PublicKey))
+		r.State.GetSigningKeyFor(chain.Leaf.SubjectSignKey)).Public().(ed25519.PublicKey))
 	if !bytes.Equal(chain.Leaf.SubjectSignKey, verKey) {
 		return common.NewBasicError("Invalid SubjectSignKey", nil, "expected",
 			verKey, "actual", chain.Leaf.SubjectSignKey)
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/scionproto/scion/go/cert_srv/internal/config"
+	"github.com/scionproto/scion/go/cert_srv/internal/metrics"
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/infra"
@@ -68,6 +69,8 @@ func (s *Self) run(ctx context.Context) error {
 		return common.NewBasicError("Unable to get certificate chain", err)
 	}
 	now := time.Now()
+	setTimeToExpiry(metrics.CertIssuer, issCrt.ExpirationTime, now)
+	setTimeToExpiry(metrics.CertLeaf, chain.Leaf.ExpirationTime, now)
 	iSleep := time.Unix(int64(issCrt.ExpirationTime), 0).Sub(now) - s.IssTime
 	lSleep := time.Unix(int64(chain.Leaf.ExpirationTime), 0).Sub(now) - s.LeafTime
 	if lSleep > 0 && iSleep > 0 {
@@ -94,6 +97,7 @@ func (s *Self) run(ctx context.Context) error {
 func (s *Self) createLeafCert(ctx context.Context, leaf *cert.Certificate) error {
 	issCrt, err := s.getIssuerCert(ctx)
 	if err != nil {
+		incAttempts(metrics.CertLeaf, metrics.ErrDB)
 		return common.NewBasicError("Unable to get issuer certificate", err)
 	}
 	chain := &cert.Chain{Leaf: leaf.Copy(), Issuer: issCrt}
@@ -105,23 +109,31 @@ func (s *Self) createLeafCert(ctx context.Context, leaf *cert.Certificate) error
 		chain.Leaf.ExpirationTime = chain.Issuer.ExpirationTime
 	}
 	if err := chain.Leaf.Sign(s.State.GetIssSigningKey(), issCrt.SignAlgorithm); err != nil {
+		incAttempts(metrics.CertLeaf, metrics.ErrCrypto)
 		return common.NewBasicError("Unable to sign leaf certificate", err, "chain", chain)
 	}
 	if err := trust.VerifyChain(ctx, s.IA, chain, s.State.Store); err != nil {
+		incAttempts(metrics.CertLeaf, metrics.ErrCrypto)
 		return common.NewBasicError("Unable to verify chain", err, "chain", chain)
 	}
 	if _, err := s.State.TrustDB.InsertChain(ctx, chain); err != nil {
+		incAttempts(metrics.CertLeaf, metrics.ErrDB)
 		return common.NewBasicError("Unable to write certificate chain", err, "chain", chain)
 	}
 	log.FromCtx(ctx).Info("[reiss.Self] Created certificate chain", "chain", chain)
 	meta, err := trust.CreateSignMeta(ctx, s.IA, s.State.TrustDB)
 	if err != nil {
+		incAttempts(metrics.CertLeaf, metrics.ErrDB)
 		return common.NewBasicError("Unable to create sign meta", err)
 	}
-	signer, err := trust.NewBasicSigner(s.State.GetSigningKey(), meta)
+	signer, err := trust.NewBasicSigner(
+		s.State.GetSigningKeyFor(chain.Leaf.SubjectSignKey), meta)
 	if err != nil {
+		incAttempts(metrics.CertLeaf, metrics.ErrCrypto)
 		return common.NewBasicError("Unable to create new signer", err)
 	}
+	incAttempts(metrics.CertLeaf, metrics.Success)
+	setTimeToExpiry(metrics.CertLeaf, chain.Leaf.ExpirationTime, time.Now())
 	s.State.SetSigner(signer)
 	s.Msgr.UpdateSigner(signer, []infra.MessageType{infra.ChainIssueReply})
 	return nil
@@ -147,18 +159,24 @@ func (s *Self) createIssuerCert(ctx context.Context, crt *cert.Certificate) erro
 	crt.ExpirationTime = crt.IssuingTime + cert.DefaultIssuerCertValidity
 	coreAS, err := s.getCoreASEntry(ctx)
 	if err != nil {
+		incAttempts(metrics.CertIssuer, metrics.ErrDB)
 		return common.NewBasicError("Unable to get core AS entry", err, "cert", crt)
 	}
 	if err = crt.Sign(s.State.GetOnRootKey(), coreAS.OnlineKeyAlg); err != nil {
+		incAttempts(metrics.CertIssuer, metrics.ErrCrypto)
 		return common.NewBasicError("Unable to sign issuer certificate", err, "cert", crt)
 	}
 	if err = crt.Verify(crt.Issuer, coreAS.OnlineKey, coreAS.OnlineKeyAlg); err != nil {
+		incAttempts(metrics.CertIssuer, metrics.ErrCrypto)
 		return common.NewBasicError("Invalid issuer certificate signature", err, "cert", crt)
 	}
 	if err = s.setIssuerCert(ctx, crt); err != nil {
+		incAttempts(metrics.CertIssuer, metrics.ErrDB)
 		return common.NewBasicError("Unable to store issuer certificate", err, "cert", crt)
 	}
 	log.FromCtx(ctx).Info("[reiss.Self] Created issuer certificate", "cert", crt)
+	incAttempts(metrics.CertIssuer, metrics.Success)
+	setTimeToExpiry(metrics.CertIssuer, crt.ExpirationTime, time.Now())
 	return nil
 }
 
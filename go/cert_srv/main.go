@@ -16,6 +16,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	_ "net/http/pprof"
@@ -31,21 +32,27 @@ import (
 	"github.com/scionproto/scion/go/lib/env"
 	"github.com/scionproto/scion/go/lib/fatal"
 	"github.com/scionproto/scion/go/lib/infra"
+	"github.com/scionproto/scion/go/lib/infra/messenger"
 	"github.com/scionproto/scion/go/lib/infra/modules/idiscovery"
 	"github.com/scionproto/scion/go/lib/infra/modules/itopo"
+	"github.com/scionproto/scion/go/lib/infra/modules/trust"
 	"github.com/scionproto/scion/go/lib/infra/modules/trust/trustdb"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/periodic"
+	"github.com/scionproto/scion/go/lib/sock/reliable"
 )
 
 var (
-	cfg         config.Config
-	state       *config.State
-	reissRunner *periodic.Runner
-	discRunners idiscovery.Runners
-	corePusher  *periodic.Runner
-	msgr        infra.Messenger
-	trustDB     trustdb.TrustDB
+	cfg          config.Config
+	state        *config.State
+	reissRunner  *periodic.Runner
+	expiryRunner *periodic.Runner
+	discRunners  idiscovery.Runners
+	corePusher   *periodic.Runner
+	trustMetrics *periodic.Runner
+	keyReloader  *periodic.Runner
+	msgr         infra.Messenger
+	trustDB      trustdb.TrustDB
 )
 
 func init() {
@@ -85,8 +92,20 @@ func realMain() int {
 	opentracing.SetGlobalTracer(tracer)
 	// Start the periodic reissuance task.
 	startReissRunner()
+	// Start monitoring the certificate expiration.
+	expiryMonitor := startExpiryMonitor()
 	// Start the periodic fetching from discovery service.
 	startDiscovery()
+	// Expose the cached crypto material on the admin endpoint, such that
+	// trust store refreshes cannot be triggered remotely.
+	env.HandleAdmin("/trust/crypto", state.Store.NewInfoHTTPHandler())
+	env.HandleAdmin("/trust/refresh_trc", state.Store.NewRefreshTRCHTTPHandler())
+	trustMetrics = periodic.StartPeriodicTask(&trust.MetricsUpdater{Store: state.Store},
+		periodic.NewTicker(time.Minute), 30*time.Second)
+	// Reload the signing keys, such that new keys are picked up without a
+	// restart.
+	keyReloader = periodic.StartPeriodicTask(state.SignKeys, periodic.NewTicker(time.Minute),
+		30*time.Second)
 	// Start the messenger.
 	go func() {
 		defer log.LogPanicAndExit()
@@ -94,6 +113,15 @@ func realMain() int {
 	}()
 	// Cleanup when the CS exits.
 	defer stop()
+	env.AddHealthCheck("certificate_expiry", expiryMonitor.Check)
+	env.AddHealthCheck("trustdb", func(ctx context.Context) error {
+		return trustdb.Ping(ctx, trustDB)
+	})
+	env.AddReadinessCheck("topology", env.TopologyCheck)
+	env.AddReadinessCheck("dispatcher", env.UnixSocketCheck(reliable.DefaultDispPath))
+	env.AddReadinessCheck("messenger", func(ctx context.Context) error {
+		return messenger.CheckServing(ctx, msgr)
+	})
 	cfg.Metrics.StartPrometheus()
 	select {
 	case <-fatal.ShutdownChan():
@@ -146,6 +174,8 @@ func startReissRunner() {
 			State:      state,
 			IA:         itopo.Get().ISD_AS,
 			LeafTime:   cfg.CS.LeafReissueLeadTime.Duration,
+			Interval:   cfg.CS.ReissueRate.Duration,
+			MaxBackoff: cfg.CS.ReissueMaxBackoff.Duration,
 			CorePusher: corePusher,
 		},
 		periodic.NewTicker(cfg.CS.ReissueRate.Duration),
@@ -153,6 +183,19 @@ func startReissRunner() {
 	)
 }
 
+// startExpiryMonitor starts a periodic task that exports the certificate
+// expiration metrics.
+func startExpiryMonitor() *reiss.ExpiryMonitor {
+	m := &reiss.ExpiryMonitor{
+		TrustDB:  state.TrustDB,
+		IA:       itopo.Get().ISD_AS,
+		CritTime: cfg.CS.ExpiryCriticalTime.Duration,
+	}
+	expiryRunner = periodic.StartPeriodicTask(m, periodic.NewTicker(time.Minute),
+		30*time.Second)
+	return m
+}
+
 func startDiscovery() {
 	var err error
 	discRunners, err = idiscovery.StartRunners(cfg.Discovery, discovery.Full,
@@ -173,6 +216,9 @@ func stopReissRunner() {
 
 func stop() {
 	stopReissRunner()
+	expiryRunner.Kill()
+	trustMetrics.Kill()
+	keyReloader.Kill()
 	discRunners.Kill()
 	msgr.CloseServer()
 	trustDB.Close()
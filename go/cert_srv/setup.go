@@ -25,10 +25,12 @@ import (
 	"github.com/scionproto/scion/go/cert_srv/internal/reiss"
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/discovery"
 	"github.com/scionproto/scion/go/lib/env"
 	"github.com/scionproto/scion/go/lib/infra"
 	"github.com/scionproto/scion/go/lib/infra/infraenv"
 	"github.com/scionproto/scion/go/lib/infra/messenger"
+	"github.com/scionproto/scion/go/lib/infra/modules/idiscovery"
 	"github.com/scionproto/scion/go/lib/infra/modules/itopo"
 	"github.com/scionproto/scion/go/lib/infra/modules/trust"
 	"github.com/scionproto/scion/go/lib/infra/modules/trust/trustdb"
@@ -36,7 +38,6 @@ import (
 	"github.com/scionproto/scion/go/lib/prom"
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/snet"
-	"github.com/scionproto/scion/go/lib/topology"
 	"github.com/scionproto/scion/go/proto"
 )
 
@@ -68,7 +69,8 @@ func setup() error {
 		return common.NewBasicError("Unable to validate config", err)
 	}
 	itopo.Init(cfg.General.ID, proto.ServiceType_cs, itopo.Callbacks{})
-	topo, err := topology.LoadFromFile(cfg.General.Topology)
+	topo, err := idiscovery.LoadTopology(cfg.General.Topology, cfg.Discovery,
+		discovery.Full, nil)
 	if err != nil {
 		return common.NewBasicError("Unable to load topology", err)
 	}
@@ -134,7 +136,8 @@ func initState(cfg *config.Config, router snet.Router) error {
 	if err != nil {
 		return common.NewBasicError("Unable to load local crypto", err)
 	}
-	state, err = config.LoadState(cfg.General.ConfigDir, topo.Core, trustDB, trustStore)
+	state, err = config.LoadState(cfg.General.ConfigDir, topo.ISD_AS, topo.Core, trustDB,
+		trustStore)
 	if err != nil {
 		return common.NewBasicError("Unable to load CS state", err)
 	}
@@ -145,7 +148,7 @@ func initState(cfg *config.Config, router snet.Router) error {
 }
 
 // setDefaultSignerVerifier sets the signer and verifier. The newest certificate chain version
-// in the store is used.
+// in the store is used, together with the signing key that belongs to it.
 func setDefaultSignerVerifier(c *config.State, pubIA addr.IA) error {
 	ctx, cancelF := context.WithTimeout(context.Background(), time.Second)
 	defer cancelF()
@@ -153,7 +156,11 @@ func setDefaultSignerVerifier(c *config.State, pubIA addr.IA) error {
 	if err != nil {
 		return err
 	}
-	signer, err := trust.NewBasicSigner(c.GetSigningKey(), meta)
+	chain, err := c.TrustDB.GetChainVersion(ctx, pubIA, meta.Src.ChainVer)
+	if err != nil {
+		return err
+	}
+	signer, err := trust.NewBasicSigner(c.GetSigningKeyFor(chain.Leaf.SubjectSignKey), meta)
 	if err != nil {
 		return err
 	}
@@ -177,14 +184,17 @@ func setMessenger(cfg *config.Config, router snet.Router) error {
 		SVC:                   addr.SvcCS,
 		ReconnectToDispatcher: cfg.General.ReconnectToDispatcher,
 		QUIC: infraenv.QUIC{
-			Address:  cfg.QUIC.Address,
-			CertFile: cfg.QUIC.CertFile,
-			KeyFile:  cfg.QUIC.KeyFile,
+			Address:      cfg.QUIC.Address,
+			CertFile:     cfg.QUIC.CertFile,
+			KeyFile:      cfg.QUIC.KeyFile,
+			ClientCAFile: cfg.QUIC.ClientCAFile,
+			AuthTypes:    cfg.QUIC.AuthTypes,
 		},
 		SVCResolutionFraction: cfg.QUIC.ResolutionFraction,
 		TrustStore:            state.Store,
 		Router:                router,
 		SVCRouter:             messenger.NewSVCRouter(itopo.Provider()),
+		CircuitBreaker:        infraenv.BreakerConfig(cfg.CircuitBreaker),
 	}
 	var err error
 	msgr, err = nc.Messenger()
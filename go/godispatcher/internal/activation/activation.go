@@ -0,0 +1,76 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package activation implements the socket activation protocol of systemd.
+//
+// The service manager passes the listening sockets as file descriptors
+// starting at 3. The number of descriptors is set in the LISTEN_FDS
+// environment variable, and LISTEN_PID must be set to the PID of the
+// receiving process. See sd_listen_fds(3) for details.
+package activation
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+
+	"github.com/scionproto/scion/go/lib/common"
+)
+
+const (
+	// listenFDsStart is the first file descriptor passed by the service
+	// manager.
+	listenFDsStart = 3
+
+	envPID     = "LISTEN_PID"
+	envFDs     = "LISTEN_FDS"
+	envFDNames = "LISTEN_FDNAMES"
+)
+
+// Files returns the files passed by the service manager, or nil if no files
+// were passed to this process. The environment variables are unset, such that
+// the files are not inherited by child processes. The returned files are
+// marked close-on-exec.
+func Files() ([]*os.File, error) {
+	defer func() {
+		os.Unsetenv(envPID)
+		os.Unsetenv(envFDs)
+		os.Unsetenv(envFDNames)
+	}()
+	return files(os.Getpid(), os.Getenv(envPID), os.Getenv(envFDs))
+}
+
+func files(pid int, envPID, envFDs string) ([]*os.File, error) {
+	if envPID == "" || envFDs == "" {
+		return nil, nil
+	}
+	listenPID, err := strconv.Atoi(envPID)
+	if err != nil {
+		return nil, common.NewBasicError("Invalid LISTEN_PID", err, "value", envPID)
+	}
+	if listenPID != pid {
+		// The files were meant for another process.
+		return nil, nil
+	}
+	n, err := strconv.Atoi(envFDs)
+	if err != nil || n < 0 {
+		return nil, common.NewBasicError("Invalid LISTEN_FDS", err, "value", envFDs)
+	}
+	files := make([]*os.File, 0, n)
+	for fd := listenFDsStart; fd < listenFDsStart+n; fd++ {
+		syscall.CloseOnExec(fd)
+		files = append(files, os.NewFile(uintptr(fd), "LISTEN_FD_"+strconv.Itoa(fd)))
+	}
+	return files, nil
+}
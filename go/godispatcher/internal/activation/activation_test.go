@@ -0,0 +1,68 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package activation
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFiles(t *testing.T) {
+	pid := os.Getpid()
+	tests := map[string]struct {
+		PID       string
+		FDs       string
+		Count     int
+		Assertion assert.ErrorAssertionFunc
+	}{
+		"not activated": {
+			Assertion: assert.NoError,
+		},
+		"other process": {
+			PID:       strconv.Itoa(pid + 1),
+			FDs:       "2",
+			Assertion: assert.NoError,
+		},
+		"activated": {
+			PID:       strconv.Itoa(pid),
+			FDs:       "2",
+			Count:     2,
+			Assertion: assert.NoError,
+		},
+		"invalid pid": {
+			PID:       "abc",
+			FDs:       "1",
+			Assertion: assert.Error,
+		},
+		"invalid fds": {
+			PID:       strconv.Itoa(pid),
+			FDs:       "-1",
+			Assertion: assert.Error,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			files, err := files(pid, test.PID, test.FDs)
+			test.Assertion(t, err)
+			assert.Len(t, files, test.Count)
+			for i, f := range files {
+				assert.Equal(t, uintptr(listenFDsStart+i), f.Fd())
+			}
+		})
+	}
+}
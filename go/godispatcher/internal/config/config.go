@@ -48,6 +48,10 @@ type Config struct {
 		// DeleteSocket specifies whether the dispatcher should delete the
 		// socket file prior to attempting to create a new one.
 		DeleteSocket bool
+		// HandoverSocket is the path of the socket used to hand over the
+		// sockets and application connections to a new dispatcher process on
+		// restart. If not set, sockets are not handed over.
+		HandoverSocket string
 	}
 }
 
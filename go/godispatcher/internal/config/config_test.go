@@ -43,6 +43,7 @@ func InitTestConfig(cfg *Config) {
 	envtest.InitTest(nil, &cfg.Logging, &cfg.Metrics, nil, nil)
 	cfg.Dispatcher.DeleteSocket = true
 	cfg.Dispatcher.PerfData = "Invalid"
+	cfg.Dispatcher.HandoverSocket = "Invalid"
 }
 
 func CheckTestConfig(t *testing.T, cfg *Config, id string) {
@@ -53,4 +54,5 @@ func CheckTestConfig(t *testing.T, cfg *Config, id string) {
 	assert.Equal(t, overlay.EndhostPort, cfg.Dispatcher.OverlayPort)
 	assert.Empty(t, cfg.Dispatcher.PerfData)
 	assert.False(t, cfg.Dispatcher.DeleteSocket)
+	assert.Empty(t, cfg.Dispatcher.HandoverSocket)
 }
@@ -36,4 +36,10 @@ PerfData = ""
 # Set DeleteSock to true to have the Dispatcher remove the socket file (if it
 # exists) on start. (default false)
 DeleteSocket = false
+
+# HandoverSocket is the path of the socket used to hand over the sockets and
+# the application connections to a new dispatcher process on restart. On start,
+# the dispatcher takes over the sockets of the dispatcher listening on this
+# socket, if any. If not set, sockets are not handed over.
+HandoverSocket = ""
 `
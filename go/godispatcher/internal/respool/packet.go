@@ -17,6 +17,7 @@ package respool
 import (
 	"net"
 	"sync"
+	"time"
 
 	"github.com/scionproto/scion/go/godispatcher/internal/metrics"
 	"github.com/scionproto/scion/go/lib/common"
@@ -44,6 +45,9 @@ func GetPacket() *Packet {
 type Packet struct {
 	Info          spkt.ScnPkt
 	OverlayRemote *net.UDPAddr
+	// Timestamp is the time at which the packet was received from the
+	// network. It is zero for packets received from applications.
+	Timestamp time.Time
 
 	// buffer contains the raw slice that other fields reference
 	buffer common.RawBytes
@@ -93,12 +97,28 @@ func (pkt *Packet) Free() {
 	}
 }
 
+// timestampReader is implemented by overlay connections that report the time at
+// which a packet was received by the host.
+type timestampReader interface {
+	ReadFromWithTimestamp(b []byte) (int, net.Addr, time.Time, error)
+}
+
+// TimestampWriter is implemented by application connections that can convey
+// the time at which a packet was received, see reliable.CapReceiveTimestamps.
+type TimestampWriter interface {
+	WriteToWithTimestamp(b []byte, address net.Addr, ts time.Time) (int, error)
+}
+
+// DecodeFromConn reads a packet from the overlay connection conn. If conn does
+// not implement timestampReader, the time at which the read returned is used
+// as the timestamp of the packet.
 func (pkt *Packet) DecodeFromConn(conn net.PacketConn) error {
-	n, readExtra, err := conn.ReadFrom(pkt.buffer)
+	n, readExtra, timestamp, err := readFromWithTimestamp(conn, pkt.buffer)
 	if err != nil {
 		return err
 	}
 	pkt.buffer = pkt.buffer[:n]
+	pkt.Timestamp = timestamp
 	metrics.IncomingBytesTotal.Add(float64(n))
 
 	pkt.OverlayRemote = readExtra.(*net.UDPAddr)
@@ -134,8 +154,25 @@ func (pkt *Packet) SendOnConn(conn net.PacketConn, address net.Addr) (int, error
 	return conn.WriteTo(pkt.buffer, address)
 }
 
+// SendOnConnWithTimestamp is like SendOnConn, but the packet is sent together
+// with its timestamp.
+func (pkt *Packet) SendOnConnWithTimestamp(conn TimestampWriter,
+	address net.Addr) (int, error) {
+
+	return conn.WriteToWithTimestamp(pkt.buffer, address, pkt.Timestamp)
+}
+
 func (pkt *Packet) reset() {
 	pkt.buffer = pkt.buffer[:cap(pkt.buffer)]
 	pkt.Info = spkt.ScnPkt{}
 	pkt.OverlayRemote = nil
+	pkt.Timestamp = time.Time{}
+}
+
+func readFromWithTimestamp(conn net.PacketConn, b []byte) (int, net.Addr, time.Time, error) {
+	if tr, ok := conn.(timestampReader); ok {
+		return tr.ReadFromWithTimestamp(b)
+	}
+	n, address, err := conn.ReadFrom(b)
+	return n, address, time.Now(), err
 }
@@ -22,9 +22,11 @@ import (
 	_ "net/http/pprof"
 	"os"
 	"os/user"
+	"sync/atomic"
 
 	"github.com/BurntSushi/toml"
 
+	"github.com/scionproto/scion/go/godispatcher/internal/activation"
 	"github.com/scionproto/scion/go/godispatcher/internal/config"
 	"github.com/scionproto/scion/go/godispatcher/network"
 	"github.com/scionproto/scion/go/lib/common"
@@ -73,6 +75,15 @@ func realMain() int {
 		return 1
 	}
 
+	sockets, err := inheritSockets(cfg.Dispatcher.HandoverSocket)
+	if err != nil {
+		log.Crit("Unable to inherit sockets", "err", err)
+		return 1
+	}
+	// The application socket file is owned by whoever passed the listening
+	// socket to us, or by the dispatcher we hand the sockets over to.
+	keepSocket := sockets != nil && sockets.App != nil
+	var handedOver int32
 	go func() {
 		defer log.LogPanicAndExit()
 		err := RunDispatcher(
@@ -80,8 +91,14 @@ func realMain() int {
 			cfg.Dispatcher.ApplicationSocket,
 			os.FileMode(cfg.Dispatcher.SocketFileMode),
 			cfg.Dispatcher.OverlayPort,
+			sockets,
+			cfg.Dispatcher.HandoverSocket,
 		)
-		if err != nil {
+		switch {
+		case err == network.ErrHandedOver:
+			atomic.StoreInt32(&handedOver, 1)
+			fatal.Shutdown(env.ShutdownGraceInterval)
+		case err != nil:
 			fatal.Fatal(err)
 		}
 	}()
@@ -96,6 +113,8 @@ func realMain() int {
 	}
 
 	env.SetupEnv(nil)
+	env.AddHealthCheck("application_socket",
+		env.UnixSocketCheck(cfg.Dispatcher.ApplicationSocket))
 	cfg.Metrics.StartPrometheus()
 
 	returnCode := waitForTeardown()
@@ -103,9 +122,12 @@ func realMain() int {
 	// done together with the whole stack on top the dispatcher. Cleaning
 	// up gracefully does not give us anything in this case. We just clean
 	// up the sockets and let the application close.
-	errDelete := deleteSocket(cfg.Dispatcher.ApplicationSocket)
-	if errDelete != nil {
-		log.Warn("Unable to delete socket when shutting down", errDelete)
+	var errDelete error
+	if !keepSocket && atomic.LoadInt32(&handedOver) == 0 {
+		errDelete = deleteSocket(cfg.Dispatcher.ApplicationSocket)
+		if errDelete != nil {
+			log.Warn("Unable to delete socket when shutting down", errDelete)
+		}
 	}
 	switch {
 	case returnCode != 0:
@@ -129,10 +151,39 @@ func setupBasic() error {
 	return env.LogAppStarted("Dispatcher", cfg.Dispatcher.ID)
 }
 
+// inheritSockets takes over the sockets of a running dispatcher, if one is
+// listening on the handover socket. Otherwise, the sockets passed by the
+// service manager are returned. If no sockets are inherited, nil is returned.
+func inheritSockets(handoverSocket string) (*network.Sockets, error) {
+	files, err := activation.Files()
+	if err != nil {
+		return nil, err
+	}
+	if handoverSocket != "" {
+		sockets, err := network.RequestHandover(handoverSocket)
+		if err != nil {
+			return nil, err
+		}
+		if sockets != nil {
+			log.Info("Took over sockets from running dispatcher", "apps", len(sockets.Apps))
+			// The service manager passes the same sockets again on restart.
+			for _, f := range files {
+				f.Close()
+			}
+			return sockets, nil
+		}
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+	log.Info("Using sockets passed by the service manager", "count", len(files))
+	return network.SocketsFromFiles(files)
+}
+
 func RunDispatcher(deleteSocketFlag bool, applicationSocket string, socketFileMode os.FileMode,
-	overlayPort int) error {
+	overlayPort int, sockets *network.Sockets, handoverSocket string) error {
 
-	if deleteSocketFlag {
+	if deleteSocketFlag && (sockets == nil || sockets.App == nil) {
 		if err := deleteSocket(cfg.Dispatcher.ApplicationSocket); err != nil {
 			return err
 		}
@@ -142,6 +193,8 @@ func RunDispatcher(deleteSocketFlag bool, applicationSocket string, socketFileMo
 		OverlaySocket:     fmt.Sprintf(":%d", overlayPort),
 		ApplicationSocket: applicationSocket,
 		SocketFileMode:    socketFileMode,
+		Inherited:         sockets,
+		HandoverSocket:    handoverSocket,
 	}
 	log.Debug("Dispatcher starting", "appSocket", applicationSocket, "overlayPort", overlayPort)
 	return dispatcher.ListenAndServe()
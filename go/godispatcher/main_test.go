@@ -423,7 +423,7 @@ func TestDataplaneIntegration(t *testing.T) {
 
 	go func() {
 		err := RunDispatcher(false, settings.ApplicationSocket, reliable.DefaultDispSocketFileMode,
-			settings.OverlayPort)
+			settings.OverlayPort, nil, "")
 		xtest.FailOnErr(t, err, "dispatcher error")
 	}()
 	time.Sleep(defaultWaitDuration)
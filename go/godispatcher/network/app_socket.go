@@ -18,6 +18,9 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/scionproto/scion/go/godispatcher/internal/metrics"
 	"github.com/scionproto/scion/go/godispatcher/internal/registration"
@@ -37,12 +40,17 @@ import (
 type AppSocketServer struct {
 	Listener    *reliable.Listener
 	ConnManager *AppConnManager
+
+	stopped int32
 }
 
 func (s *AppSocketServer) Serve() error {
 	for {
 		conn, err := s.Listener.Accept()
 		if err != nil {
+			if atomic.LoadInt32(&s.stopped) == 1 {
+				return nil
+			}
 			return err
 		}
 		pconn := conn.(net.PacketConn)
@@ -50,6 +58,12 @@ func (s *AppSocketServer) Serve() error {
 	}
 }
 
+// Stop makes Serve return without closing the listener.
+func (s *AppSocketServer) Stop() {
+	atomic.StoreInt32(&s.stopped, 1)
+	s.Listener.SetDeadline(time.Now())
+}
+
 // AppConnManager handles new connections coming from SCION applications.
 type AppConnManager struct {
 	RoutingTable *IATable
@@ -59,21 +73,86 @@ type AppConnManager struct {
 	// IPv6OverlayConn is the network connection to which IPv6 egress traffic
 	// is sent.
 	IPv6OverlayConn net.PacketConn
+
+	mu        sync.Mutex
+	handlers  map[*AppConnHandler]struct{}
+	detaching bool
 }
 
 // Handle passes conn off to a per-connection state handler.
 func (h *AppConnManager) Handle(conn net.PacketConn) {
-	ch := &AppConnHandler{
+	ch := h.newHandler(conn)
+	go func() {
+		defer log.LogPanicAndExit()
+		ch.Handle()
+	}()
+}
+
+// Resume passes the connection of an application that was registered by a
+// previous dispatcher process off to a per-connection state handler.
+func (h *AppConnManager) Resume(app AppState) {
+	ch := h.newHandler(reliable.NewConn(app.Conn, app.Buffered))
+	go func() {
+		defer log.LogPanicAndExit()
+		ch.Resume(app.Registration)
+	}()
+}
+
+func (h *AppConnManager) newHandler(conn net.PacketConn) *AppConnHandler {
+	return &AppConnHandler{
 		Conn:            conn,
 		RoutingTable:    h.RoutingTable,
 		IPv4OverlayConn: h.IPv4OverlayConn,
 		IPv6OverlayConn: h.IPv6OverlayConn,
 		Logger:          log.Root().New("clientID", fmt.Sprintf("%p", conn)),
+		manager:         h,
+		detached:        make(chan *AppState, 1),
 	}
-	go func() {
-		defer log.LogPanicAndExit()
-		ch.Handle()
-	}()
+}
+
+// Detach stops all registered application handlers without closing their
+// connections, and returns the state needed to resume them in another
+// dispatcher process. Connections that are accepted afterwards are closed.
+func (h *AppConnManager) Detach() []AppState {
+	h.mu.Lock()
+	h.detaching = true
+	handlers := make([]*AppConnHandler, 0, len(h.handlers))
+	for handler := range h.handlers {
+		handlers = append(handlers, handler)
+	}
+	h.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler.detach()
+	}
+	var apps []AppState
+	for _, handler := range handlers {
+		if state := <-handler.detached; state != nil {
+			apps = append(apps, *state)
+		}
+	}
+	return apps
+}
+
+// add tracks a registered handler. It returns false if the manager is
+// detaching, in which case the handler must not serve the application.
+func (h *AppConnManager) add(handler *AppConnHandler) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.detaching {
+		return false
+	}
+	if h.handlers == nil {
+		h.handlers = make(map[*AppConnHandler]struct{})
+	}
+	h.handlers[handler] = struct{}{}
+	return true
+}
+
+func (h *AppConnManager) remove(handler *AppConnHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.handlers, handler)
 }
 
 // AppConnHandler handles a single SCION application connection.
@@ -88,47 +167,115 @@ type AppConnHandler struct {
 	// is sent.
 	IPv6OverlayConn net.PacketConn
 	Logger          log.Logger
+
+	manager *AppConnManager
+	// timestamps is set if the application negotiated receive timestamps.
+	timestamps bool
+	// detaching is set to 1 if the handler is being detached.
+	detaching int32
+	// detached receives the state of the application once the handler
+	// stopped. It receives nil if the connection was closed instead.
+	detached chan *AppState
 }
 
 func (h *AppConnHandler) Handle() {
 	h.Logger.Info("Accepted new client")
-	defer h.Logger.Info("Closed client socket")
-	defer h.Conn.Close()
+	regInfo, err := h.recvRegistrationMsg()
+	if err != nil {
+		h.Logger.Warn("registration error", "err", err)
+		h.Conn.Close()
+		h.Logger.Info("Closed client socket")
+		return
+	}
+	h.serve(regInfo, true)
+}
+
+// Resume serves an application that was registered by a previous dispatcher
+// process. No registration messages are exchanged with the application.
+func (h *AppConnHandler) Resume(regInfo *reliable.Registration) {
+	h.Logger.Info("Resumed client")
+	h.serve(regInfo, false)
+}
+
+func (h *AppConnHandler) serve(regInfo *reliable.Registration, confirm bool) {
+	if !h.manager.add(h) {
+		h.Conn.Close()
+		h.Logger.Info("Closed client socket, dispatcher is handing over")
+		return
+	}
+	defer h.manager.remove(h)
 
-	ref, tableEntry, useIPv6, err := h.doRegExchange()
+	ref, tableEntry, err := h.register(regInfo, confirm)
 	if err != nil {
 		h.Logger.Warn("registration error", "err", err)
+		h.close()
 		return
 	}
 	defer ref.Free()
 	metrics.OpenSockets.WithLabelValues(metrics.GetOpenConnectionLabel(ref.SVCAddr())).Inc()
 	defer metrics.OpenSockets.WithLabelValues(metrics.GetOpenConnectionLabel(ref.SVCAddr())).Dec()
 
-	defer tableEntry.appIngressRing.Close()
+	ringDone := make(chan struct{})
 	go func() {
 		defer log.LogPanicAndExit()
+		defer close(ringDone)
 		h.RunRingToAppDataplane(tableEntry.appIngressRing)
 	}()
 
-	conn := h.IPv4OverlayConn
-	if useIPv6 {
-		conn = h.IPv6OverlayConn
+	h.RunAppToNetDataplane(ref)
+	tableEntry.appIngressRing.Close()
+	if !h.isDetaching() {
+		h.close()
+		return
+	}
+	// Deliver the packets that are still enqueued before handing over the
+	// connection.
+	<-ringDone
+	rconn := h.Conn.(*reliable.Conn)
+	h.detached <- &AppState{
+		Conn:         rconn.UnixConn,
+		Registration: regInfo,
+		Buffered:     rconn.Buffered(),
 	}
-	h.RunAppToNetDataplane(ref, conn)
+	h.Logger.Info("Detached client")
 }
 
-// doRegExchange manages an application's registration request, and returns a
-// reference to registered data that should be freed at the end of the
-// registration, information about allocated ring buffers, a boolean specifying
-// whether to use IPv6 egress instead of IPv4, and whether an error occurred.
-func (h *AppConnHandler) doRegExchange() (registration.RegReference, *TableEntry, bool, error) {
+func (h *AppConnHandler) close() {
+	h.Conn.Close()
+	h.detached <- nil
+	h.Logger.Info("Closed client socket")
+}
+
+// detach makes the handler stop reading from the application.
+func (h *AppConnHandler) detach() {
+	atomic.StoreInt32(&h.detaching, 1)
+	h.Conn.SetReadDeadline(time.Now())
+}
+
+func (h *AppConnHandler) isDetaching() bool {
+	return atomic.LoadInt32(&h.detaching) == 1
+}
+
+// recvRegistrationMsg reads an application's registration request.
+func (h *AppConnHandler) recvRegistrationMsg() (*reliable.Registration, error) {
 	b := respool.GetBuffer()
 	defer respool.PutBuffer(b)
 
 	regInfo, err := h.recvRegistration(b)
 	if err != nil {
-		return nil, nil, false, common.NewBasicError("registration message error", nil, "err", err)
+		return nil, common.NewBasicError("registration message error", nil, "err", err)
 	}
+	return regInfo, nil
+}
+
+// register registers the application in the routing table, and returns a
+// reference to registered data that should be freed at the end of the
+// registration, information about allocated ring buffers and whether an error
+// occurred. If confirm is set, the registration is confirmed to the
+// application. The public address of regInfo is updated to contain the
+// allocated port.
+func (h *AppConnHandler) register(regInfo *reliable.Registration,
+	confirm bool) (registration.RegReference, *TableEntry, error) {
 
 	tableEntry := newTableEntry(h.Conn)
 	ref, err := h.RoutingTable.Register(
@@ -139,24 +286,37 @@ func (h *AppConnHandler) doRegExchange() (registration.RegReference, *TableEntry
 		tableEntry,
 	)
 	if err != nil {
-		return nil, nil, false, common.NewBasicError("registration table error", nil, "err", err)
+		return nil, nil, common.NewBasicError("registration table error", nil, "err", err)
 	}
 
 	udpRef := ref.(registration.RegReference)
 	port := uint16(udpRef.UDPAddr().Port)
-	if err := h.sendConfirmation(b, &reliable.Confirmation{Port: port}); err != nil {
-		// Need to release stale state from the table
-		ref.Free()
-		return nil, nil, false, common.NewBasicError("confirmation message error", nil, "err", err)
+	confirmation := &reliable.Confirmation{Port: port}
+	if regInfo.Negotiation != nil {
+		negotiation := regInfo.Negotiation.Negotiate()
+		confirmation.Negotiation = &negotiation
+		h.timestamps = negotiation.Capabilities&reliable.CapReceiveTimestamps != 0
+	}
+	if confirm {
+		b := respool.GetBuffer()
+		defer respool.PutBuffer(b)
+		if err := h.sendConfirmation(b, confirmation); err != nil {
+			// Need to release stale state from the table
+			ref.Free()
+			return nil, nil, common.NewBasicError("confirmation message error", nil,
+				"err", err)
+		}
 	}
+	public := *regInfo.PublicAddress
+	public.Port = int(port)
+	regInfo.PublicAddress = &public
 	h.logRegistration(regInfo.IA, udpRef.UDPAddr(), getBindIP(regInfo.BindAddress),
-		regInfo.SVCAddress)
-	isIPv6 := regInfo.PublicAddress.IP.To4() == nil
-	return udpRef, tableEntry, isIPv6, nil
+		regInfo.SVCAddress, confirmation.Negotiation)
+	return udpRef, tableEntry, nil
 }
 
 func (h *AppConnHandler) logRegistration(ia addr.IA, public *net.UDPAddr, bind net.IP,
-	svc addr.HostSVC) {
+	svc addr.HostSVC, negotiation *reliable.Negotiation) {
 
 	items := []interface{}{"ia", ia, "public", public}
 	if bind != nil {
@@ -165,6 +325,10 @@ func (h *AppConnHandler) logRegistration(ia addr.IA, public *net.UDPAddr, bind n
 	if svc != addr.SvcNone {
 		items = append(items, "svc", svc)
 	}
+	if negotiation != nil {
+		items = append(items, "version", negotiation.Version,
+			"capabilities", negotiation.Capabilities)
+	}
 	h.Logger.Info("Client registered address", items...)
 }
 
@@ -196,10 +360,10 @@ func (h *AppConnHandler) sendConfirmation(b common.RawBytes, c *reliable.Confirm
 }
 
 // RunAppToNetDataplane moves packets from the application's socket to the
-// overlay socket.
-func (h *AppConnHandler) RunAppToNetDataplane(ref registration.RegReference,
-	ovConn net.PacketConn) {
-
+// overlay socket. The overlay socket is chosen per packet based on the address
+// family of the next hop, such that applications registered on an IPv6 address
+// can still reach IPv4 next hops and vice versa.
+func (h *AppConnHandler) RunAppToNetDataplane(ref registration.RegReference) {
 	for {
 		pkt := respool.GetPacket()
 		// XXX(scrye): we don't release the reference on error conditions, and
@@ -207,6 +371,9 @@ func (h *AppConnHandler) RunAppToNetDataplane(ref registration.RegReference,
 		// rare.
 
 		if err := pkt.DecodeFromReliableConn(h.Conn); err != nil {
+			if h.isDetaching() {
+				return
+			}
 			if err == io.EOF {
 				h.Logger.Info("[app->network] EOF received from client")
 			} else {
@@ -219,6 +386,13 @@ func (h *AppConnHandler) RunAppToNetDataplane(ref registration.RegReference,
 			log.Warn("SCMP Request ID error, packet still sent", "err", err)
 		}
 
+		ovConn := h.overlayConn(pkt.OverlayRemote.IP)
+		if ovConn == nil {
+			h.Logger.Error("[app->network] No overlay socket for next hop address family",
+				"nextHop", pkt.OverlayRemote)
+			pkt.Free()
+			continue
+		}
 		n, err := pkt.SendOnConn(ovConn, pkt.OverlayRemote)
 		if err != nil {
 			h.Logger.Error("[app->network] Overlay socket error", "err", err)
@@ -230,6 +404,16 @@ func (h *AppConnHandler) RunAppToNetDataplane(ref registration.RegReference,
 	}
 }
 
+// overlayConn returns the overlay socket matching the address family of ip.
+// The returned value is nil if the dispatcher is not listening on that
+// address family.
+func (h *AppConnHandler) overlayConn(ip net.IP) net.PacketConn {
+	if ip.To4() != nil {
+		return h.IPv4OverlayConn
+	}
+	return h.IPv6OverlayConn
+}
+
 func registerIfSCMPRequest(ref registration.RegReference, packet *spkt.ScnPkt) error {
 	if scmpHdr, ok := packet.L4.(*scmp.Hdr); ok {
 		if !isSCMPGeneralRequest(scmpHdr) {
@@ -262,7 +446,7 @@ func (h *AppConnHandler) RunRingToAppDataplane(r *ringbuf.Ring) {
 				h.Logger.Warn("[network->app] Unable to encode overlay address.", "err", err)
 				continue
 			}
-			if _, err := pkt.SendOnConn(h.Conn, overlayAddr); err != nil {
+			if _, err := h.sendToApp(pkt, overlayAddr); err != nil {
 				h.Logger.Error("[network->app] App connection error.", "err", err)
 				h.Conn.Close()
 				return
@@ -271,3 +455,12 @@ func (h *AppConnHandler) RunRingToAppDataplane(r *ringbuf.Ring) {
 		}
 	}
 }
+
+// sendToApp sends pkt to the application. The timestamp of the packet is
+// included if the application negotiated receive timestamps.
+func (h *AppConnHandler) sendToApp(pkt *respool.Packet, address net.Addr) (int, error) {
+	if tw, ok := h.Conn.(respool.TimestampWriter); ok && h.timestamps {
+		return pkt.SendOnConnWithTimestamp(tw, address)
+	}
+	return pkt.SendOnConn(h.Conn, address)
+}
@@ -0,0 +1,56 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppConnHandlerOverlayConn(t *testing.T) {
+	ipv4Conn, ipv6Conn := &net.UDPConn{}, &net.UDPConn{}
+	tests := map[string]struct {
+		Handler  *AppConnHandler
+		NextHop  net.IP
+		Expected net.PacketConn
+	}{
+		"IPv4 next hop": {
+			Handler:  &AppConnHandler{IPv4OverlayConn: ipv4Conn, IPv6OverlayConn: ipv6Conn},
+			NextHop:  net.IP{127, 0, 0, 1},
+			Expected: ipv4Conn,
+		},
+		"IPv6 next hop": {
+			Handler:  &AppConnHandler{IPv4OverlayConn: ipv4Conn, IPv6OverlayConn: ipv6Conn},
+			NextHop:  net.ParseIP("2001:db8::1"),
+			Expected: ipv6Conn,
+		},
+		"IPv4-mapped IPv6 next hop": {
+			Handler:  &AppConnHandler{IPv4OverlayConn: ipv4Conn, IPv6OverlayConn: ipv6Conn},
+			NextHop:  net.ParseIP("::ffff:127.0.0.1"),
+			Expected: ipv4Conn,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.Expected, test.Handler.overlayConn(test.NextHop))
+		})
+	}
+	t.Run("no IPv6 overlay socket", func(t *testing.T) {
+		h := &AppConnHandler{IPv4OverlayConn: ipv4Conn}
+		assert.Nil(t, h.overlayConn(net.ParseIP("2001:db8::1")))
+	})
+}
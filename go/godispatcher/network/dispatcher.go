@@ -15,8 +15,10 @@
 package network
 
 import (
+	"io/ioutil"
 	"net"
 	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -40,74 +42,196 @@ type Dispatcher struct {
 	OverlaySocket     string
 	ApplicationSocket string
 	SocketFileMode    os.FileMode
+	// Inherited contains the sockets that were passed by the service manager
+	// or by a previous dispatcher process. If nil, all sockets are opened by
+	// the dispatcher.
+	Inherited *Sockets
+	// HandoverSocket is the path of the socket on which a new dispatcher
+	// process can take over the sockets of this dispatcher. If it is empty,
+	// the sockets are not handed over.
+	HandoverSocket string
 }
 
+// ListenAndServe runs the dispatcher. If the sockets are handed over to a new
+// dispatcher process, ErrHandedOver is returned.
 func (d *Dispatcher) ListenAndServe() error {
+	inherited := d.Inherited
+	if inherited == nil {
+		inherited = &Sockets{}
+	}
 	metaLogger := &throttledMetaLogger{
 		Logger:      log.Root(),
 		MinInterval: OverflowLoggingInterval,
 	}
-	ipv4Conn, err := openConn("udp4", d.OverlaySocket, metaLogger)
+	ipv4UDP, err := listenUDP("udp4", d.OverlaySocket, inherited.IPv4)
+	if err != nil {
+		return err
+	}
+	ipv4Conn, err := openConn(ipv4UDP, metaLogger)
 	if err != nil {
 		return err
 	}
 	defer ipv4Conn.Close()
 
-	ipv6Conn, err := openConn("udp6", d.OverlaySocket, metaLogger)
+	ipv6UDP, err := listenUDP("udp6", d.OverlaySocket, inherited.IPv6)
 	if err != nil {
 		return err
 	}
-	defer ipv6Conn.Close()
-
-	appServerConn, err := reliable.Listen(d.ApplicationSocket)
+	ipv6Conn, err := openConn(ipv6UDP, metaLogger)
 	if err != nil {
 		return err
 	}
+	defer ipv6Conn.Close()
+
+	appListener := inherited.App
+	if appListener == nil {
+		l, err := reliable.Listen(d.ApplicationSocket)
+		if err != nil {
+			return err
+		}
+		if err := os.Chmod(d.ApplicationSocket, d.SocketFileMode); err != nil {
+			l.Close()
+			return common.NewBasicError("chmod failed", err, "socket file", d.ApplicationSocket)
+		}
+		appListener = l.UnixListener
+	}
+	appServerConn := &reliable.Listener{UnixListener: appListener}
 	defer appServerConn.Close()
-	if err := os.Chmod(d.ApplicationSocket, d.SocketFileMode); err != nil {
-		return common.NewBasicError("chmod failed", err, "socket file", d.ApplicationSocket)
+
+	var handoverListener *net.UnixListener
+	if d.HandoverSocket != "" {
+		if handoverListener, err = listenHandover(d.HandoverSocket); err != nil {
+			return err
+		}
+		defer handoverListener.Close()
 	}
 
-	errChan := make(chan error)
+	ipv4Dataplane := &NetToRingDataplane{
+		OverlayConn:  ipv4Conn,
+		RoutingTable: d.RoutingTable,
+	}
+	ipv6Dataplane := &NetToRingDataplane{
+		OverlayConn:  ipv6Conn,
+		RoutingTable: d.RoutingTable,
+	}
+	connManager := &AppConnManager{
+		RoutingTable:    d.RoutingTable,
+		IPv4OverlayConn: ipv4Conn,
+		IPv6OverlayConn: ipv6Conn,
+	}
+	for _, app := range inherited.Apps {
+		connManager.Resume(app)
+	}
+	appServer := &AppSocketServer{
+		Listener:    appServerConn,
+		ConnManager: connManager,
+	}
+
+	// Goroutines that are stopped because of a handover return a nil error.
+	errChan := make(chan error, 4)
+	var dataplanes sync.WaitGroup
+	dataplanes.Add(2)
 	go func() {
 		defer log.LogPanicAndExit()
-		netToRingDataplane := &NetToRingDataplane{
-			OverlayConn:  ipv4Conn,
-			RoutingTable: d.RoutingTable,
-		}
-		errChan <- netToRingDataplane.Run()
+		defer dataplanes.Done()
+		errChan <- ipv4Dataplane.Run()
 	}()
 	go func() {
 		defer log.LogPanicAndExit()
-		netToRingDataplane := &NetToRingDataplane{
-			OverlayConn:  ipv6Conn,
-			RoutingTable: d.RoutingTable,
-		}
-		errChan <- netToRingDataplane.Run()
+		defer dataplanes.Done()
+		errChan <- ipv6Dataplane.Run()
 	}()
-
 	go func() {
 		defer log.LogPanicAndExit()
-		appServer := &AppSocketServer{
-			Listener: appServerConn,
-			ConnManager: &AppConnManager{
-				RoutingTable:    d.RoutingTable,
-				IPv4OverlayConn: ipv4Conn,
-				IPv6OverlayConn: ipv6Conn,
-			},
-		}
 		errChan <- appServer.Serve()
 	}()
+	if handoverListener != nil {
+		go func() {
+			defer log.LogPanicAndExit()
+			errChan <- serveHandover(handoverListener, func(c *net.UnixConn) error {
+				// Stop all readers before handing over the sockets, such
+				// that the new dispatcher is the only process reading from
+				// them.
+				appServer.Stop()
+				ipv4Dataplane.Stop()
+				ipv6Dataplane.Stop()
+				dataplanes.Wait()
+				apps := connManager.Detach()
+				// The socket file must outlive this process.
+				appListener.SetUnlinkOnClose(false)
+				log.Info("Handing over sockets to new dispatcher", "apps", len(apps))
+				return SendSockets(c, &Sockets{
+					IPv4: ipv4UDP,
+					IPv6: ipv6UDP,
+					App:  appListener,
+					Apps: apps,
+				})
+			})
+		}()
+	}
+	for {
+		if err := <-errChan; err != nil {
+			return err
+		}
+	}
+}
+
+// listenHandover listens on the handover socket. Only processes running as
+// the same user can connect to it. The socket is created in a private
+// directory, and only moved to path once its permissions are restricted, such
+// that other users cannot connect in between.
+func listenHandover(path string) (*net.UnixListener, error) {
+	dir, err := ioutil.TempDir(filepath.Dir(path), ".handover")
+	if err != nil {
+		return nil, common.NewBasicError("Unable to create private handover directory", err,
+			"path", path)
+	}
+	defer os.RemoveAll(dir)
+	tmpPath := filepath.Join(dir, "sock")
+	l, err := net.ListenUnix("unixpacket", &net.UnixAddr{Name: tmpPath, Net: "unixpacket"})
+	if err != nil {
+		return nil, common.NewBasicError("Unable to listen on handover socket", err,
+			"path", tmpPath)
+	}
+	// The socket file is moved, so it cannot be unlinked by its original name.
+	l.SetUnlinkOnClose(false)
+	if err := os.Chmod(tmpPath, 0600); err != nil {
+		l.Close()
+		return nil, common.NewBasicError("chmod failed", err, "socket file", tmpPath)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		l.Close()
+		return nil, common.NewBasicError("Unable to move handover socket into place", err,
+			"path", path)
+	}
+	return l, nil
+}
 
-	return <-errChan
+// serveHandover waits for a single handover request. The listener is closed
+// before the sockets are handed over, such that the new dispatcher can
+// create its own handover socket.
+func serveHandover(l *net.UnixListener, handover func(*net.UnixConn) error) error {
+	c, err := l.AcceptUnix()
+	if err != nil {
+		return common.NewBasicError("Unable to accept handover request", err)
+	}
+	defer c.Close()
+	l.Close()
+	if err := handover(c); err != nil {
+		return common.NewBasicError("Unable to hand over sockets", err)
+	}
+	return ErrHandedOver
 }
 
-// openConn opens an overlay socket that tracks additional socket information
-// such as packets dropped due to buffer full.
+// listenUDP returns the inherited socket if it is not nil. Otherwise, it
+// opens a new UDP socket.
 //
 // Note that Go-style dual-stacked IPv4/IPv6 connections are not supported. If
 // network is udp, it will be treated as udp4.
-func openConn(network, address string, p SocketMetaHandler) (net.PacketConn, error) {
+func listenUDP(network, address string, inherited *net.UDPConn) (*net.UDPConn, error) {
+	if inherited != nil {
+		return inherited, nil
+	}
 	// We cannot allow the Go standard library to open both types of sockets
 	// because the socket options are specific to only one socket type, so we
 	// degrade udp to only udp4.
@@ -118,30 +242,31 @@ func openConn(network, address string, p SocketMetaHandler) (net.PacketConn, err
 	if err != nil {
 		return nil, common.NewBasicError("unable to construct UDP addr", err)
 	}
-
-	var hostIP addr.HostAddr
-	switch network {
-	case "udp4":
-		hostIP = addr.HostIPv4(listeningAddress.IP)
-	case "udp6":
-		hostIP = addr.HostIPv6(listeningAddress.IP)
-	default:
-		return nil, common.NewBasicError("unsupported network", nil, "network", network)
+	c, err := net.ListenUDP(network, listeningAddress)
+	if err != nil {
+		return nil, common.NewBasicError("unable to listen on UDP addr", err,
+			"network", network, "addr", listeningAddress)
 	}
+	return c, nil
+}
 
+// openConn wraps c in an overlay socket that tracks additional socket
+// information such as packets dropped due to buffer full.
+func openConn(c *net.UDPConn, p SocketMetaHandler) (net.PacketConn, error) {
+	listeningAddress := c.LocalAddr().(*net.UDPAddr)
 	ov, err := overlay.NewOverlayAddr(
-		hostIP,
+		addr.HostFromIP(listeningAddress.IP),
 		addr.NewL4UDPInfo(uint16(listeningAddress.Port)),
 	)
 	if err != nil {
 		return nil, common.NewBasicError("unable to construct overlay address", err)
 	}
-	c, err := conn.New(ov, nil, &conn.Config{ReceiveBufferSize: ReceiveBufferSize})
+	oc, err := conn.New(ov, nil, &conn.Config{ReceiveBufferSize: ReceiveBufferSize, Conn: c})
 	if err != nil {
 		return nil, common.NewBasicError("unable to open conn", err)
 	}
 
-	return &overlayConnWrapper{Conn: c, Handler: p}, nil
+	return &overlayConnWrapper{Conn: oc, Handler: p}, nil
 }
 
 // SocketMetaHandler processes OS socket metadata during reads.
@@ -186,12 +311,24 @@ type overlayConnWrapper struct {
 }
 
 func (o *overlayConnWrapper) ReadFrom(p []byte) (int, net.Addr, error) {
+	n, address, _, err := o.ReadFromWithTimestamp(p)
+	return n, address, err
+}
+
+// ReadFromWithTimestamp works like ReadFrom, but also returns the time at
+// which the kernel received the packet. If the kernel did not report it, the
+// time of the read is returned instead.
+func (o *overlayConnWrapper) ReadFromWithTimestamp(p []byte) (int, net.Addr, time.Time, error) {
 	n, meta, err := o.Conn.Read(common.RawBytes(p))
 	if meta == nil {
-		return n, nil, err
+		return n, nil, time.Time{}, err
 	}
 	o.Handler.Handle(meta)
-	return n, meta.Src.ToUDPAddr(), err
+	timestamp := meta.Recvd
+	if timestamp.UnixNano() <= 0 {
+		timestamp = time.Now()
+	}
+	return n, meta.Src.ToUDPAddr(), timestamp, err
 }
 
 func (o *overlayConnWrapper) WriteTo(p []byte, a net.Addr) (int, error) {
@@ -0,0 +1,80 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/overlay/conn"
+	"github.com/scionproto/scion/go/lib/overlay/conn/mock_conn"
+)
+
+type nopMetaHandler struct{}
+
+func (nopMetaHandler) Handle(*conn.ReadMeta) {}
+
+func TestOverlayConnWrapperReadFromWithTimestamp(t *testing.T) {
+	src, err := overlay.NewOverlayAddr(addr.HostFromIPStr("192.0.2.1"),
+		addr.NewL4UDPInfo(30041))
+	require.NoError(t, err)
+	kernelTime := time.Unix(1500000000, 42)
+	tests := map[string]struct {
+		Recvd     time.Time
+		Assertion func(t *testing.T, before, ts time.Time)
+	}{
+		"kernel timestamp": {
+			Recvd: kernelTime,
+			Assertion: func(t *testing.T, _, ts time.Time) {
+				assert.Equal(t, kernelTime, ts)
+			},
+		},
+		"no kernel timestamp": {
+			Recvd: time.Unix(0, 0),
+			Assertion: func(t *testing.T, before, ts time.Time) {
+				assert.False(t, ts.Before(before), "timestamp %v before read %v", ts, before)
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockConn := mock_conn.NewMockConn(ctrl)
+			mockConn.EXPECT().Read(gomock.Any()).DoAndReturn(
+				func(b common.RawBytes) (int, *conn.ReadMeta, error) {
+					n := copy(b, []byte{1, 2, 3})
+					return n, &conn.ReadMeta{Src: src, Recvd: test.Recvd}, nil
+				},
+			)
+			o := &overlayConnWrapper{Conn: mockConn, Handler: nopMetaHandler{}}
+			before := time.Now()
+			n, address, ts, err := o.ReadFromWithTimestamp(make([]byte, 10))
+			require.NoError(t, err)
+			assert.Equal(t, 3, n)
+			assert.Equal(t, &net.UDPAddr{IP: net.ParseIP("192.0.2.1").To4(), Port: 30041},
+				address)
+			test.Assertion(t, before, ts)
+		})
+	}
+}
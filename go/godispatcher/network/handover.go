@@ -0,0 +1,295 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"syscall"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/sock/reliable"
+)
+
+// ErrHandedOver is returned by the dispatcher after its sockets were handed
+// over to another dispatcher process.
+var ErrHandedOver = serrors.New("sockets handed over to new dispatcher")
+
+// Sockets contains sockets that the dispatcher inherits from the service
+// manager or from a previous dispatcher process. Nil sockets are opened by
+// the dispatcher itself.
+type Sockets struct {
+	IPv4 *net.UDPConn
+	IPv6 *net.UDPConn
+	App  *net.UnixListener
+	// Apps are the application connections of a previous dispatcher process.
+	Apps []AppState
+}
+
+// AppState describes an application connection that is resumed by the
+// dispatcher without a new registration exchange.
+type AppState struct {
+	Conn *net.UnixConn
+	// Registration is the registration of the application. The public
+	// address contains the port that was allocated to the application.
+	Registration *reliable.Registration
+	// Buffered is the data that was read from the connection, but that was
+	// not processed yet.
+	Buffered []byte
+}
+
+// SocketsFromFiles classifies the files passed by the service manager. The
+// files are closed.
+func SocketsFromFiles(files []*os.File) (*Sockets, error) {
+	s := &Sockets{}
+	for _, f := range files {
+		err := s.add(f)
+		f.Close()
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (s *Sockets) add(f *os.File) error {
+	if l, err := net.FileListener(f); err == nil {
+		ul, ok := l.(*net.UnixListener)
+		if !ok || s.App != nil {
+			l.Close()
+			return common.NewBasicError("Unexpected stream socket", nil, "addr", l.Addr())
+		}
+		s.App = ul
+		return nil
+	}
+	c, err := net.FilePacketConn(f)
+	if err != nil {
+		return common.NewBasicError("Unsupported socket", err, "name", f.Name())
+	}
+	uc, ok := c.(*net.UDPConn)
+	if !ok {
+		c.Close()
+		return common.NewBasicError("Unexpected packet socket", nil, "addr", c.LocalAddr())
+	}
+	target := &s.IPv6
+	if uc.LocalAddr().(*net.UDPAddr).IP.To4() != nil {
+		target = &s.IPv4
+	}
+	if *target != nil {
+		uc.Close()
+		return common.NewBasicError("Duplicate UDP socket", nil, "addr", uc.LocalAddr())
+	}
+	*target = uc
+	return nil
+}
+
+// Close closes all sockets.
+func (s *Sockets) Close() {
+	if s.IPv4 != nil {
+		s.IPv4.Close()
+	}
+	if s.IPv6 != nil {
+		s.IPv6.Close()
+	}
+	if s.App != nil {
+		s.App.Close()
+	}
+	for _, app := range s.Apps {
+		app.Conn.Close()
+	}
+}
+
+const (
+	handoverUDP4        = "udp4"
+	handoverUDP6        = "udp6"
+	handoverAppListener = "app_listener"
+	handoverAppConn     = "app_conn"
+	handoverDone        = "done"
+
+	// maxHandoverMsgSize is the maximum size of a handover message. It fits
+	// a registration and a full read buffer of an application connection.
+	maxHandoverMsgSize = 1 << 18
+)
+
+// handoverMsg is sent for every socket that is handed over. The file
+// descriptor of the socket is attached to the message.
+type handoverMsg struct {
+	Kind         string
+	Registration []byte `json:",omitempty"`
+	Buffered     []byte `json:",omitempty"`
+}
+
+// RequestHandover connects to the handover socket of a running dispatcher and
+// takes over its sockets. If no dispatcher is listening on the handover
+// socket, nil is returned.
+func RequestHandover(handoverSocket string) (*Sockets, error) {
+	c, err := net.Dial("unixpacket", handoverSocket)
+	if err != nil {
+		return nil, nil
+	}
+	defer c.Close()
+	return ReceiveSockets(c.(*net.UnixConn))
+}
+
+// SendSockets sends the sockets over c. The sockets are not closed, it is up
+// to the caller to stop using them.
+func SendSockets(c *net.UnixConn, s *Sockets) error {
+	send := func(msg handoverMsg, sock syscall.Conn) error {
+		if sock == nil {
+			return writeHandoverMsg(c, msg, -1)
+		}
+		raw, err := sock.SyscallConn()
+		if err != nil {
+			return err
+		}
+		var werr error
+		err = raw.Control(func(fd uintptr) {
+			werr = writeHandoverMsg(c, msg, int(fd))
+		})
+		if err != nil {
+			return err
+		}
+		return werr
+	}
+	if s.IPv4 != nil {
+		if err := send(handoverMsg{Kind: handoverUDP4}, s.IPv4); err != nil {
+			return common.NewBasicError("Unable to hand over IPv4 socket", err)
+		}
+	}
+	if s.IPv6 != nil {
+		if err := send(handoverMsg{Kind: handoverUDP6}, s.IPv6); err != nil {
+			return common.NewBasicError("Unable to hand over IPv6 socket", err)
+		}
+	}
+	if s.App != nil {
+		if err := send(handoverMsg{Kind: handoverAppListener}, s.App); err != nil {
+			return common.NewBasicError("Unable to hand over application socket", err)
+		}
+	}
+	b := make([]byte, common.MaxMTU)
+	for _, app := range s.Apps {
+		n, err := app.Registration.SerializeTo(b)
+		if err != nil {
+			return common.NewBasicError("Unable to serialize registration", err)
+		}
+		msg := handoverMsg{Kind: handoverAppConn, Registration: b[:n], Buffered: app.Buffered}
+		if err := send(msg, app.Conn); err != nil {
+			return common.NewBasicError("Unable to hand over application connection", err,
+				"public", app.Registration.PublicAddress)
+		}
+	}
+	return send(handoverMsg{Kind: handoverDone}, nil)
+}
+
+// ReceiveSockets receives the sockets sent by SendSockets.
+func ReceiveSockets(c *net.UnixConn) (*Sockets, error) {
+	s := &Sockets{}
+	for {
+		msg, f, err := readHandoverMsg(c)
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+		if msg.Kind == handoverDone {
+			return s, nil
+		}
+		err = s.addHandedOver(msg, f)
+		f.Close()
+		if err != nil {
+			s.Close()
+			return nil, common.NewBasicError("Unable to take over socket", err, "kind", msg.Kind)
+		}
+	}
+}
+
+func (s *Sockets) addHandedOver(msg *handoverMsg, f *os.File) error {
+	switch msg.Kind {
+	case handoverUDP4, handoverUDP6:
+		c, err := net.FilePacketConn(f)
+		if err != nil {
+			return err
+		}
+		if msg.Kind == handoverUDP4 {
+			s.IPv4 = c.(*net.UDPConn)
+		} else {
+			s.IPv6 = c.(*net.UDPConn)
+		}
+	case handoverAppListener:
+		l, err := net.FileListener(f)
+		if err != nil {
+			return err
+		}
+		s.App = l.(*net.UnixListener)
+	case handoverAppConn:
+		var reg reliable.Registration
+		if err := reg.DecodeFromBytes(msg.Registration); err != nil {
+			return err
+		}
+		c, err := net.FileConn(f)
+		if err != nil {
+			return err
+		}
+		s.Apps = append(s.Apps, AppState{
+			Conn:         c.(*net.UnixConn),
+			Registration: &reg,
+			Buffered:     msg.Buffered,
+		})
+	default:
+		return serrors.New("unknown socket kind")
+	}
+	return nil
+}
+
+func writeHandoverMsg(c *net.UnixConn, msg handoverMsg, fd int) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	var oob []byte
+	if fd >= 0 {
+		oob = syscall.UnixRights(fd)
+	}
+	_, _, err = c.WriteMsgUnix(b, oob, nil)
+	return err
+}
+
+func readHandoverMsg(c *net.UnixConn) (*handoverMsg, *os.File, error) {
+	b := make([]byte, maxHandoverMsgSize)
+	oob := make([]byte, syscall.CmsgSpace(4))
+	n, oobn, _, _, err := c.ReadMsgUnix(b, oob)
+	if err != nil {
+		return nil, nil, err
+	}
+	var msg handoverMsg
+	if err := json.Unmarshal(b[:n], &msg); err != nil {
+		return nil, nil, common.NewBasicError("Unable to parse handover message", err)
+	}
+	if msg.Kind == handoverDone {
+		return &msg, nil, nil
+	}
+	cmsgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if err != nil || len(cmsgs) != 1 {
+		return nil, nil, common.NewBasicError("Missing file descriptor", err, "kind", msg.Kind)
+	}
+	fds, err := syscall.ParseUnixRights(&cmsgs[0])
+	if err != nil || len(fds) != 1 {
+		return nil, nil, common.NewBasicError("Missing file descriptor", err, "kind", msg.Kind)
+	}
+	syscall.CloseOnExec(fds[0])
+	return &msg, os.NewFile(uintptr(fds[0]), msg.Kind), nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/sock/reliable"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestSendReceiveSockets(t *testing.T) {
+	dir, cleanup := xtest.MustTempDir("", "dispatcher_handover")
+	defer cleanup()
+
+	udp, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	defer udp.Close()
+	app, err := net.ListenUnix("unix", &net.UnixAddr{Name: filepath.Join(dir, "app.sock")})
+	require.NoError(t, err)
+	defer app.Close()
+	appConn, peer := unixPair(t, syscall.SOCK_STREAM)
+	defer appConn.Close()
+	defer peer.Close()
+
+	reg := &reliable.Registration{
+		IA:            xtest.MustParseIA("1-ff00:0:110"),
+		PublicAddress: &net.UDPAddr{IP: net.IP{127, 0, 0, 1}, Port: 40000},
+		SVCAddress:    addr.SvcNone,
+	}
+	sent := &Sockets{
+		IPv4: udp,
+		App:  app,
+		Apps: []AppState{{Conn: appConn, Registration: reg, Buffered: []byte{1, 2, 3}}},
+	}
+	sender, receiver := unixPair(t, syscall.SOCK_SEQPACKET)
+	defer sender.Close()
+	defer receiver.Close()
+	errC := make(chan error, 1)
+	go func() { errC <- SendSockets(sender, sent) }()
+
+	received, err := ReceiveSockets(receiver)
+	require.NoError(t, err)
+	require.NoError(t, <-errC)
+	defer received.Close()
+
+	require.NotNil(t, received.IPv4)
+	assert.Equal(t, udp.LocalAddr(), received.IPv4.LocalAddr())
+	assert.Nil(t, received.IPv6)
+	require.NotNil(t, received.App)
+	assert.Equal(t, app.Addr(), received.App.Addr())
+	require.Len(t, received.Apps, 1)
+	assert.Equal(t, reg, received.Apps[0].Registration)
+	assert.Equal(t, []byte{1, 2, 3}, received.Apps[0].Buffered)
+
+	// The received connection must be usable by the new process.
+	_, err = received.Apps[0].Conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	b := make([]byte, 4)
+	_, err = peer.Read(b)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ping"), b)
+}
+
+func TestListenHandover(t *testing.T) {
+	dir, cleanup := xtest.MustTempDir("", "dispatcher_handover")
+	defer cleanup()
+	path := filepath.Join(dir, "handover.sock")
+	// A stale socket file of a previous dispatcher is replaced.
+	require.NoError(t, ioutil.WriteFile(path, nil, 0666))
+
+	l, err := listenHandover(path)
+	require.NoError(t, err)
+	defer l.Close()
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.ModeSocket, info.Mode()&os.ModeType)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	entries, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "private directory must be removed")
+
+	c, err := net.Dial("unixpacket", path)
+	require.NoError(t, err)
+	c.Close()
+}
+
+func TestSocketsFromFiles(t *testing.T) {
+	udp, err := net.ListenUDP("udp6", &net.UDPAddr{IP: net.IPv6loopback})
+	if err != nil {
+		t.Skip("IPv6 not available:", err)
+	}
+	defer udp.Close()
+	f, err := udp.File()
+	require.NoError(t, err)
+
+	sockets, err := SocketsFromFiles([]*os.File{f})
+	require.NoError(t, err)
+	defer sockets.Close()
+	assert.Nil(t, sockets.IPv4)
+	require.NotNil(t, sockets.IPv6)
+	assert.Equal(t, udp.LocalAddr(), sockets.IPv6.LocalAddr())
+}
+
+func unixPair(t *testing.T, typ int) (*net.UnixConn, *net.UnixConn) {
+	fds, err := syscall.Socketpair(syscall.AF_UNIX, typ, 0)
+	require.NoError(t, err)
+	conns := make([]*net.UnixConn, 2)
+	for i, fd := range fds {
+		f := os.NewFile(uintptr(fd), "socketpair")
+		c, err := net.FileConn(f)
+		f.Close()
+		require.NoError(t, err)
+		conns[i] = c.(*net.UnixConn)
+	}
+	return conns[0], conns[1]
+}
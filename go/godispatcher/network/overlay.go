@@ -16,6 +16,8 @@ package network
 
 import (
 	"net"
+	"sync/atomic"
+	"time"
 
 	"github.com/scionproto/scion/go/godispatcher/internal/metrics"
 	"github.com/scionproto/scion/go/godispatcher/internal/respool"
@@ -46,6 +48,8 @@ const (
 type NetToRingDataplane struct {
 	OverlayConn  net.PacketConn
 	RoutingTable *IATable
+
+	stopped int32
 }
 
 func (dp *NetToRingDataplane) Run() error {
@@ -56,6 +60,9 @@ func (dp *NetToRingDataplane) Run() error {
 		// rare.
 
 		if err := pkt.DecodeFromConn(dp.OverlayConn); err != nil {
+			if atomic.LoadInt32(&dp.stopped) == 1 {
+				return nil
+			}
 			log.Warn("error receiving next packet from overlay conn", "err", err)
 			continue
 		}
@@ -73,6 +80,12 @@ func (dp *NetToRingDataplane) Run() error {
 	}
 }
 
+// Stop makes Run return without closing the overlay socket.
+func (dp *NetToRingDataplane) Stop() {
+	atomic.StoreInt32(&dp.stopped, 1)
+	dp.OverlayConn.SetReadDeadline(time.Now())
+}
+
 func ComputeDestination(packet *spkt.ScnPkt) (Destination, error) {
 	switch header := packet.L4.(type) {
 	case *l4.UDP:
@@ -128,7 +141,7 @@ func ComputeSCMPErrorDestination(packet *spkt.ScnPkt, header *scmp.Hdr) (Destina
 	scmpPayload := packet.Pld.(*scmp.Payload)
 	switch scmpPayload.Meta.L4Proto {
 	case common.L4UDP:
-		quotedUDPHeader, err := l4.UDPFromRaw(scmpPayload.L4Hdr)
+		quotedUDPHeader, err := scmpPayload.QuotedUDP()
 		if err != nil {
 			return nil, common.NewBasicError(ErrMalformedL4Quote, nil, "err", err)
 		}
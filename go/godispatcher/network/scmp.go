@@ -54,26 +54,7 @@ func getSCMPGeneralID(pktInfo *spkt.ScnPkt) uint64 {
 
 // getSCMPQuoteID returns the 8-byte ID of a quoted SCMP General class packet.
 func getQuotedSCMPGeneralID(scmpPayload *scmp.Payload) (uint64, error) {
-	// FIXME(scrye): In the case of an SCMP quote, the L4 header quote
-	// contains both the SCMP Meta and SCMP Info Payload fields of the
-	// offending packet. This, however, is not defined (or parsed) as an
-	// SCMP header. We skip past the canonical 16 bytes of the header here,
-	// to extract the Info field of the offending packet.
-	quotedSCMPHeader, err := scmp.HdrFromRaw(scmpPayload.L4Hdr)
-	if err != nil {
-		return 0, err
-	}
-	meta, err := scmp.MetaFromRaw(scmpPayload.L4Hdr[quotedSCMPHeader.L4Len():])
-	if err != nil {
-		return 0, err
-	}
-	quotedInfoStart := quotedSCMPHeader.L4Len() + common.LineLen
-	quotedInfoEnd := quotedInfoStart + int(meta.InfoLen)*common.LineLen
-	if len(scmpPayload.L4Hdr) < quotedInfoEnd {
-		return 0, serrors.New("incomplete post-quoted SCMP header meta+info quote")
-	}
-	info, err := scmp.ParseInfo(scmpPayload.L4Hdr[quotedInfoStart:quotedInfoEnd],
-		scmp.ClassType{Class: quotedSCMPHeader.Class, Type: quotedSCMPHeader.Type})
+	quotedSCMPHeader, info, err := scmpPayload.QuotedSCMP()
 	if err != nil {
 		return 0, err
 	}
@@ -91,15 +72,11 @@ func extractID(t scmp.Type, info scmp.Info) uint64 {
 		return 0
 	}
 	switch t {
-	case scmp.T_G_EchoRequest, scmp.T_G_EchoReply:
-		infoEcho := info.(*scmp.InfoEcho)
-		return infoEcho.Id
-	case scmp.T_G_RecordPathRequest, scmp.T_G_RecordPathReply:
-		infoRecordPath := info.(*scmp.InfoRecordPath)
-		return infoRecordPath.Id
-	case scmp.T_G_TraceRouteRequest, scmp.T_G_TraceRouteReply:
-		infoTraceRoute := info.(*scmp.InfoTraceRoute)
-		return infoTraceRoute.Id
+	case scmp.T_G_EchoRequest, scmp.T_G_EchoReply,
+		scmp.T_G_RecordPathRequest, scmp.T_G_RecordPathReply,
+		scmp.T_G_TraceRouteRequest, scmp.T_G_TraceRouteReply:
+		id, _ := scmp.GeneralID(info)
+		return id
 	}
 	return 0
 }
@@ -33,10 +33,8 @@ import (
 	"github.com/scionproto/scion/go/lib/overlay"
 	"github.com/scionproto/scion/go/lib/pathmgr"
 	"github.com/scionproto/scion/go/lib/sciond"
-	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/snet"
 	"github.com/scionproto/scion/go/lib/sock/reliable"
-	"github.com/scionproto/scion/go/lib/spath"
 	"github.com/scionproto/scion/go/lib/util"
 )
 
@@ -236,25 +234,13 @@ func (c client) getRemote(ctx context.Context, n int) error {
 		return nil
 	}
 	// Get paths from sciond
-	paths, err := c.sdConn.Paths(ctx, remote.IA, integration.Local.IA, 1,
+	paths, err := sciond.GetPaths(ctx, c.sdConn, remote.IA, integration.Local.IA, 1,
 		sciond.PathReqFlags{Refresh: n != 0})
 	if err != nil {
 		return common.NewBasicError("Error requesting paths", err)
 	}
-	if len(paths.Entries) == 0 {
-		return serrors.New("No path entries found")
-	}
-	pathEntry := paths.Entries[0]
-	path := spath.New(pathEntry.Path.FwdPath)
-	if err = path.InitOffsets(); err != nil {
-		return common.NewBasicError("Unable to initialize path", err)
-	}
-	// Extract forwarding path from sciond response
-	remote.Path = path
-	remote.NextHop, err = pathEntry.HostInfo.Overlay()
-	if err != nil {
-		return common.NewBasicError("Error getting overlay", err)
-	}
+	remote.Path = paths[0].Path
+	remote.NextHop = paths[0].NextHop
 	return nil
 }
 
@@ -179,6 +179,7 @@ func (as *AS) UnmarshalText(text []byte) error {
 }
 
 var _ fmt.Stringer = IA{}
+var _ encoding.TextMarshaler = IA{}
 var _ encoding.TextUnmarshaler = (*IA)(nil)
 var _ flag.Value = (*IA)(nil)
 
@@ -228,6 +229,8 @@ func IAFromFileFmt(s string, prefixes bool) (IA, error) {
 	return IA{I: isd, A: as}, nil
 }
 
+// MarshalText implements encoding.TextMarshaler. The IA is encoded in the
+// format accepted by IAFromString.
 func (ia IA) MarshalText() ([]byte, error) {
 	return []byte(ia.String()), nil
 }
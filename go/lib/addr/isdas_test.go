@@ -15,6 +15,7 @@
 package addr
 
 import (
+	"encoding/json"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -319,3 +320,25 @@ func Test_IA_String(t *testing.T) {
 		}
 	})
 }
+
+func Test_IA_Text(t *testing.T) {
+	Convey("IA should round-trip through text and JSON", t, func() {
+		for _, src := range []string{"1-ff00:0:110", "65535-1", "1-1:0:0"} {
+			Convey(src, func() {
+				ia, err := IAFromString(src)
+				SoMsg("parse err", err, ShouldBeNil)
+				text, err := ia.MarshalText()
+				SoMsg("marshal err", err, ShouldBeNil)
+				SoMsg("text", string(text), ShouldEqual, src)
+				var parsed IA
+				SoMsg("unmarshal err", parsed.UnmarshalText(text), ShouldBeNil)
+				SoMsg("text ia", parsed, ShouldResemble, ia)
+				raw, err := json.Marshal(map[IA]IA{ia: ia})
+				SoMsg("json marshal err", err, ShouldBeNil)
+				var m map[IA]IA
+				SoMsg("json unmarshal err", json.Unmarshal(raw, &m), ShouldBeNil)
+				SoMsg("json ia", m[ia], ShouldResemble, ia)
+			})
+		}
+	})
+}
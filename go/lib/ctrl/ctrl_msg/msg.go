@@ -61,6 +61,50 @@ func (r *Requester) Request(ctx context.Context, pld *ctrl.Pld,
 	return rpld, rspld.Sign, nil
 }
 
+// RequestStream sends pld to a, and returns all replies until more returns
+// false. more is called with the unverified payloads received so far; the
+// signatures of all replies are verified before they are returned.
+func (r *Requester) RequestStream(ctx context.Context, pld *ctrl.Pld, a net.Addr,
+	more func([]*ctrl.Pld) bool) ([]*ctrl.Pld, error) {
+
+	spld, err := pld.SignedPld(r.signer)
+	if err != nil {
+		return nil, err
+	}
+	var plds []*ctrl.Pld
+	streamMore := func(replies []proto.Cerealizable) bool {
+		rspld, ok := replies[len(replies)-1].(*ctrl.SignedPld)
+		if !ok {
+			// Stop the stream, the type is checked below.
+			return false
+		}
+		rpld, err := rspld.UnsafePld()
+		if err != nil {
+			return false
+		}
+		plds = append(plds, rpld)
+		return more(plds)
+	}
+	replies, err := r.d.RequestStream(ctx, spld, a, streamMore)
+	if err != nil {
+		return nil, err
+	}
+	rplds := make([]*ctrl.Pld, 0, len(replies))
+	for _, reply := range replies {
+		rspld, ok := reply.(*ctrl.SignedPld)
+		if !ok {
+			return nil, common.NewBasicError("ctrl_msg: reply is not a ctrl.SignedPld", nil,
+				"type", common.TypeOf(reply), "reply", reply)
+		}
+		rpld, err := rspld.GetVerifiedPld(ctx, r.sigv)
+		if err != nil {
+			return nil, err
+		}
+		rplds = append(rplds, rpld)
+	}
+	return rplds, nil
+}
+
 func (r *Requester) Notify(ctx context.Context, pld *ctrl.Pld, a net.Addr) error {
 	return r.notify(ctx, pld, a, r.d.Notify)
 }
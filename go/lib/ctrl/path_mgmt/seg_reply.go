@@ -30,6 +30,14 @@ var _ proto.Cerealizable = (*SegReply)(nil)
 type SegReply struct {
 	Req  *SegReq
 	Recs *SegRecs
+	// Chunk is the index of this reply in a chunked reply.
+	Chunk uint16
+	// Chunks is the total number of chunks of a chunked reply. It is 0 for
+	// unchunked replies.
+	Chunks uint16
+	// Truncated indicates that the responder dropped segments because the
+	// reply exceeded its size limit.
+	Truncated bool
 }
 
 func NewSegReplyFromRaw(b common.RawBytes) (*SegReply, error) {
@@ -46,6 +54,10 @@ func (s *SegReply) Write(b common.RawBytes) (int, error) {
 }
 
 func (s *SegReply) String() string {
+	if s.Chunks > 0 || s.Truncated {
+		return fmt.Sprintf("Req: %s Chunk: %d/%d Truncated: %t Reply:\n%s",
+			s.Req, s.Chunk, s.Chunks, s.Truncated, s.Recs)
+	}
 	return fmt.Sprintf("Req: %s Reply:\n%s", s.Req, s.Recs)
 }
 
@@ -67,8 +79,9 @@ func (s *SegReply) ParseRaw() error {
 // is silently sanitized.
 func (s *SegReply) Sanitize(logger log.Logger) *SegReply {
 	newReply := &SegReply{
-		Req:  s.Req,
-		Recs: &SegRecs{},
+		Req:       s.Req,
+		Recs:      &SegRecs{},
+		Truncated: s.Truncated,
 	}
 	if s.Recs == nil {
 		return newReply
@@ -0,0 +1,154 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package path_mgmt
+
+import (
+	"math"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/proto"
+)
+
+// segChunkOverhead is added to the packed size of every segment to account
+// for the list encoding when estimating the size of a chunk.
+const segChunkOverhead = 16
+
+// Split splits s into at most maxChunks replies, each of which has a packed
+// size of at most maxSize bytes. All chunks carry the request, the
+// revocations are carried in the first chunk. Segments that do not fit into
+// a chunk on their own, or that do not fit into maxChunks chunks, are
+// dropped and all chunks are marked as truncated.
+//
+// If s fits into maxSize bytes, it is returned unchanged as the only chunk.
+func (s *SegReply) Split(maxSize, maxChunks int) ([]*SegReply, error) {
+	raw, err := proto.PackRoot(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) <= maxSize || s.Recs == nil {
+		return []*SegReply{s}, nil
+	}
+	if maxChunks > math.MaxUint16 {
+		maxChunks = math.MaxUint16
+	}
+	sizes := make([]int, len(s.Recs.Recs))
+	for i, meta := range s.Recs.Recs {
+		raw, err := meta.Segment.Pack()
+		if err != nil {
+			return nil, common.NewBasicError("Unable to pack segment", err, "seg_index", i)
+		}
+		sizes[i] = len(raw) + segChunkOverhead
+	}
+	truncated := s.Truncated
+	var chunks []*SegReply
+	next := 0
+	for len(chunks) < maxChunks && (len(chunks) == 0 || next < len(sizes)) {
+		chunk := &SegReply{Req: s.Req, Recs: &SegRecs{}}
+		if len(chunks) == 0 {
+			chunk.Recs.SRevInfos = s.Recs.SRevInfos
+		}
+		raw, err := proto.PackRoot(chunk)
+		if err != nil {
+			return nil, err
+		}
+		size := len(raw)
+		for ; next < len(sizes); next++ {
+			if size+sizes[next] <= maxSize {
+				size += sizes[next]
+				chunk.Recs.Recs = append(chunk.Recs.Recs, s.Recs.Recs[next])
+				continue
+			}
+			if len(chunk.Recs.Recs) > 0 {
+				break
+			}
+			// The segment does not even fit into an empty chunk.
+			truncated = true
+		}
+		if len(chunks) > 0 && len(chunk.Recs.Recs) == 0 {
+			break
+		}
+		chunks = append(chunks, chunk)
+	}
+	if next < len(sizes) {
+		truncated = true
+	}
+	for i, chunk := range chunks {
+		if len(chunks) > 1 {
+			chunk.Chunk = uint16(i)
+			chunk.Chunks = uint16(len(chunks))
+		}
+		chunk.Truncated = truncated
+	}
+	return chunks, nil
+}
+
+// SegRepliesComplete returns whether replies contains all chunks of a chunked reply.
+// An unchunked reply is always complete.
+func SegRepliesComplete(replies []*SegReply) bool {
+	if len(replies) == 0 {
+		return false
+	}
+	total := int(replies[0].Chunks)
+	if total <= 1 {
+		return true
+	}
+	seen := make(map[uint16]struct{}, total)
+	for _, r := range replies {
+		seen[r.Chunk] = struct{}{}
+	}
+	return len(seen) >= total
+}
+
+// MergeSegReplies reassembles the chunks of a chunked reply. The chunks can
+// be in any order, and duplicate chunks are ignored, but every chunk must be
+// present. An unchunked reply is returned unchanged.
+func MergeSegReplies(replies []*SegReply) (*SegReply, error) {
+	if len(replies) == 0 {
+		return nil, serrors.New("No replies to merge")
+	}
+	total := int(replies[0].Chunks)
+	for _, r := range replies {
+		if int(r.Chunks) != total || (total > 0 && int(r.Chunk) >= total) {
+			return nil, common.NewBasicError("Invalid chunk", nil,
+				"chunk", r.Chunk, "chunks", r.Chunks, "expected_chunks", total)
+		}
+	}
+	if total <= 1 {
+		return replies[0], nil
+	}
+	ordered := make([]*SegReply, total)
+	for _, r := range replies {
+		if ordered[r.Chunk] == nil {
+			ordered[r.Chunk] = r
+		}
+	}
+	for i, r := range ordered {
+		if r == nil {
+			return nil, common.NewBasicError("Missing chunk", nil,
+				"chunk", i, "chunks", total)
+		}
+	}
+	merged := &SegReply{Req: ordered[0].Req, Recs: &SegRecs{}}
+	for _, r := range ordered {
+		merged.Truncated = merged.Truncated || r.Truncated
+		if r.Recs == nil {
+			continue
+		}
+		merged.Recs.Recs = append(merged.Recs.Recs, r.Recs.Recs...)
+		merged.Recs.SRevInfos = append(merged.Recs.SRevInfos, r.Recs.SRevInfos...)
+	}
+	return merged, nil
+}
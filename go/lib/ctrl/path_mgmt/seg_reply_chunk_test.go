@@ -0,0 +1,100 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package path_mgmt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/proto"
+)
+
+func TestSegReplySplit(t *testing.T) {
+	reply := &SegReply{
+		Req:  &SegReq{RawSrcIA: 1, RawDstIA: 2},
+		Recs: &SegRecs{},
+	}
+	for i := 0; i < 10; i++ {
+		s, err := seg.NewSeg(&spath.InfoField{ISD: 1, TsInt: uint32(i)})
+		require.NoError(t, err)
+		reply.Recs.Recs = append(reply.Recs.Recs, seg.NewMeta(s, proto.PathSegType_down))
+	}
+	raw, err := proto.PackRoot(reply)
+	require.NoError(t, err)
+	segRaw, err := reply.Recs.Recs[0].Segment.Pack()
+	require.NoError(t, err)
+	// Leave room for roughly three segments per chunk.
+	maxSize := len(raw) - 7*(len(segRaw)+segChunkOverhead)
+
+	t.Run("small reply is not split", func(t *testing.T) {
+		chunks, err := reply.Split(len(raw), 10)
+		require.NoError(t, err)
+		assert.Equal(t, []*SegReply{reply}, chunks)
+	})
+	t.Run("large reply is split and merged", func(t *testing.T) {
+		chunks, err := reply.Split(maxSize, 10)
+		require.NoError(t, err)
+		require.True(t, len(chunks) > 1)
+		for i, chunk := range chunks {
+			raw, err := proto.PackRoot(chunk)
+			require.NoError(t, err)
+			assert.True(t, len(raw) <= maxSize, "chunk %d too large: %d", i, len(raw))
+			assert.Equal(t, uint16(i), chunk.Chunk)
+			assert.Equal(t, uint16(len(chunks)), chunk.Chunks)
+			assert.False(t, chunk.Truncated)
+		}
+		assert.False(t, SegRepliesComplete(chunks[1:]))
+		assert.True(t, SegRepliesComplete(chunks))
+		// Reverse the order to check that merging sorts the chunks.
+		reversed := make([]*SegReply, 0, len(chunks))
+		for i := len(chunks) - 1; i >= 0; i-- {
+			reversed = append(reversed, chunks[i])
+		}
+		merged, err := MergeSegReplies(reversed)
+		require.NoError(t, err)
+		assert.Equal(t, reply.Req, merged.Req)
+		assert.Equal(t, reply.Recs.Recs, merged.Recs.Recs)
+		assert.False(t, merged.Truncated)
+	})
+	t.Run("reply exceeding the chunk limit is truncated", func(t *testing.T) {
+		chunks, err := reply.Split(maxSize, 2)
+		require.NoError(t, err)
+		require.Len(t, chunks, 2)
+		merged, err := MergeSegReplies(chunks)
+		require.NoError(t, err)
+		assert.True(t, merged.Truncated)
+		assert.True(t, len(merged.Recs.Recs) < len(reply.Recs.Recs))
+		assert.Equal(t, reply.Recs.Recs[:len(merged.Recs.Recs)], merged.Recs.Recs)
+	})
+	t.Run("incomplete replies are not merged", func(t *testing.T) {
+		chunks, err := reply.Split(maxSize, 10)
+		require.NoError(t, err)
+		_, err = MergeSegReplies(chunks[1:])
+		assert.Error(t, err)
+		_, err = MergeSegReplies(append(chunks[1:], chunks[1]))
+		assert.Error(t, err)
+	})
+	t.Run("duplicate chunks are ignored", func(t *testing.T) {
+		chunks, err := reply.Split(maxSize, 10)
+		require.NoError(t, err)
+		merged, err := MergeSegReplies(append(chunks, chunks[0], chunks[len(chunks)-1]))
+		require.NoError(t, err)
+		assert.Equal(t, reply.Recs.Recs, merged.Recs.Recs)
+	})
+}
@@ -36,6 +36,36 @@ type SegReq struct {
 type SegReqFlags struct {
 	Sibra     bool
 	CacheOnly bool
+	// RevFilter determines how the PS handles segments that are affected by
+	// active revocations.
+	RevFilter RevFilter
+}
+
+// RevFilter determines how a path server handles segments that are affected
+// by active revocations when replying to a segment request.
+type RevFilter uint8
+
+const (
+	// RevFilterDefault leaves the choice to the path server configuration.
+	RevFilterDefault RevFilter = iota
+	// RevFilterDrop removes affected segments from the reply.
+	RevFilterDrop
+	// RevFilterAnnotate keeps affected segments in the reply. The revocations
+	// affecting them are part of the reply.
+	RevFilterAnnotate
+)
+
+func (f RevFilter) String() string {
+	switch f {
+	case RevFilterDefault:
+		return "default"
+	case RevFilterDrop:
+		return "drop"
+	case RevFilterAnnotate:
+		return "annotate"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(f))
+	}
 }
 
 func NewSegReqFromRaw(b common.RawBytes) (*SegReq, error) {
@@ -29,6 +29,9 @@ type InstancePool interface {
 	// Choose returns the info for the best discovery service instance
 	// according to the pool.
 	Choose() (InstanceInfo, error)
+	// Instances returns the infos for all instances, ordered from best to
+	// worst according to the pool.
+	Instances() []InstanceInfo
 }
 
 // InstanceInfo provides the information for a single discovery service instance.
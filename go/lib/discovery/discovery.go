@@ -60,6 +60,23 @@
 //  dynamic && default:  /discovery/v1/dynamic/default.json
 //  dynamic && endhost:  /discovery/v1/dynamic/endhost.json
 //  dynamic && full:     /discovery/v1/dynamic/full.json
+//
+// Incremental updates
+//
+// Clients that already have a topology can request the changes since its
+// version, see FetchTopoUpdate. The version is assigned by the server and sent
+// in the VersionHeader. The server responds with status 304 if the topology
+// has not changed, with a JSON merge patch (RFC 7396) against the client's
+// version if it can compute one, or with the full topology otherwise. Servers
+// can use UpdateHandler to serve topologies this way.
+//
+// Signatures
+//
+// The discovery service can sign the topology files it serves. The
+// signature over the response body is sent base64 encoded in the
+// SignatureHeader response header. Clients that are configured with a
+// Verifier reject topology files without a valid signature, see
+// FetchVerifiedTopoRaw.
 package discovery
 
 import (
@@ -130,15 +147,44 @@ func FetchTopo(ctx context.Context, params FetchParams, ds *addr.AppAddr,
 func FetchTopoRaw(ctx context.Context, params FetchParams, ds *addr.AppAddr,
 	client *http.Client) (*topology.Topo, common.RawBytes, error) {
 
+	return FetchVerifiedTopoRaw(ctx, params, ds, client, nil)
+}
+
+// FetchVerifiedTopoRaw fetches the topology like FetchTopoRaw. If verifier is
+// not nil, the signature of the topology is verified with it before the
+// topology is parsed.
+func FetchVerifiedTopoRaw(ctx context.Context, params FetchParams, ds *addr.AppAddr,
+	client *http.Client, verifier Verifier) (*topology.Topo, common.RawBytes, error) {
+
 	url, err := createURL(params, ds)
 	if err != nil {
 		return nil, nil, common.NewBasicError("Unable to create URL", err)
 	}
+	_, raw, err := fetchBody(ctx, url, client, verifier)
+	if err != nil {
+		return nil, nil, err
+	}
+	topo, err := topology.Load(raw)
+	if err != nil {
+		return nil, nil, common.NewBasicError("Unable to parse topo", err)
+	}
+	return topo, raw, nil
+}
+
+// fetchBody fetches url and returns the response header and body. If verifier
+// is not nil, the signature of the body is verified with it. ErrNotModified is
+// returned if the server responds with status 304.
+func fetchBody(ctx context.Context, url string, client *http.Client,
+	verifier Verifier) (http.Header, common.RawBytes, error) {
+
 	rep, err := ctxhttp.Get(ctx, client, url)
 	if err != nil {
 		return nil, nil, common.NewBasicError("HTTP request failed", err)
 	}
 	defer rep.Body.Close()
+	if rep.StatusCode == http.StatusNotModified {
+		return nil, nil, ErrNotModified
+	}
 	if rep.StatusCode != http.StatusOK {
 		return nil, nil, common.NewBasicError("Status not OK", nil, "status", rep.Status)
 	}
@@ -146,11 +192,12 @@ func FetchTopoRaw(ctx context.Context, params FetchParams, ds *addr.AppAddr,
 	if err != nil {
 		return nil, nil, common.NewBasicError("Unable to read body", err)
 	}
-	topo, err := topology.Load(raw)
-	if err != nil {
-		return nil, nil, common.NewBasicError("Unable to parse topo", err)
+	if verifier != nil {
+		if err := verifySignature(ctx, verifier, raw, rep.Header); err != nil {
+			return nil, nil, err
+		}
 	}
-	return topo, raw, nil
+	return rep.Header, raw, nil
 }
 
 // createURL builds the url to the topology file.
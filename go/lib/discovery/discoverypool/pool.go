@@ -16,8 +16,10 @@ package discoverypool
 
 import (
 	"math"
+	"sort"
 	"sync"
 
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/discovery"
 	"github.com/scionproto/scion/go/lib/discovery/discoveryinfo"
@@ -31,6 +33,9 @@ var _ discovery.InstancePool = (*Pool)(nil)
 type Pool struct {
 	mu sync.Mutex
 	m  map[string]discovery.InstanceInfo
+	// static contains the keys of the instances that are not removed on
+	// updates.
+	static map[string]struct{}
 }
 
 // New populates the pool with discovery service instances from the map
@@ -41,12 +46,33 @@ func New(svcInfo topology.IDAddrMap) (*Pool, error) {
 			"SvcInfo must contain at least one discovery service instance", nil)
 	}
 	p := &Pool{
-		m: make(map[string]discovery.InstanceInfo, len(svcInfo)),
+		m:      make(map[string]discovery.InstanceInfo, len(svcInfo)),
+		static: make(map[string]struct{}),
 	}
 	p.Update(svcInfo)
 	return p, nil
 }
 
+// NewStatic populates the pool with the discovery service instances in
+// instances, e.g., configured bootstrap instances. At least one instance must
+// be present. Unlike the instances from a topology, these instances are kept
+// on updates, such that the pool does not depend on the discovery service
+// entries of the fetched topologies.
+func NewStatic(instances map[string]*addr.AppAddr) (*Pool, error) {
+	if len(instances) <= 0 {
+		return nil, serrors.New("At least one discovery service instance required")
+	}
+	p := &Pool{
+		m:      make(map[string]discovery.InstanceInfo, len(instances)),
+		static: make(map[string]struct{}, len(instances)),
+	}
+	for k, v := range instances {
+		p.m[k] = discoveryinfo.New(k, v)
+		p.static[k] = struct{}{}
+	}
+	return p, nil
+}
+
 // Update adds missing instances and removes instances which are no longer in the topology.
 func (p *Pool) Update(svcInfo topology.IDAddrMap) error {
 	p.mu.Lock()
@@ -62,6 +88,9 @@ func (p *Pool) Update(svcInfo topology.IDAddrMap) error {
 	// Get list of outdated DS servers.
 	var del []string
 	for k := range p.m {
+		if _, ok := p.static[k]; ok {
+			continue
+		}
 		if _, ok := svcInfo[k]; !ok {
 			del = append(del, k)
 		}
@@ -95,3 +124,29 @@ func (p *Pool) Choose() (discovery.InstanceInfo, error) {
 	}
 	return best, nil
 }
+
+// Instances returns the infos for all discovery service instances in the
+// pool, ordered by increasing fail count.
+func (p *Pool) Instances() []discovery.InstanceInfo {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	type entry struct {
+		info      discovery.InstanceInfo
+		failCount int
+	}
+	entries := make([]entry, 0, len(p.m))
+	for _, ds := range p.m {
+		entries = append(entries, entry{info: ds, failCount: ds.FailCount()})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].failCount != entries[j].failCount {
+			return entries[i].failCount < entries[j].failCount
+		}
+		return entries[i].info.Key() < entries[j].info.Key()
+	})
+	infos := make([]discovery.InstanceInfo, 0, len(entries))
+	for _, e := range entries {
+		infos = append(infos, e.info)
+	}
+	return infos
+}
@@ -131,6 +131,34 @@ func TestPoolUpdate(t *testing.T) {
 	})
 }
 
+func TestNewStatic(t *testing.T) {
+	Convey("Given configured discovery service instances", t, func() {
+		instances := map[string]*addr.AppAddr{ds[0].key: ds[0].addr}
+		pool, err := NewStatic(instances)
+		So(err, ShouldBeNil)
+		contains(pool, ds[0])
+		Convey("And a topology that does not contain them", func() {
+			svcInfo := mustLoadSvcInfo(t)
+			delete(svcInfo, ds[0].key)
+			err := pool.Update(svcInfo)
+			So(err, ShouldBeNil)
+			Convey("The pool should keep the configured instances", func() {
+				contains(pool, ds[0])
+				contains(pool, ds[1])
+			})
+		})
+		Convey("And a topology with no discovery service", func() {
+			err := pool.Update(nil)
+			So(err, ShouldBeNil)
+			contains(pool, ds[0])
+		})
+	})
+	Convey("Without instances, the pool should not initialize", t, func() {
+		_, err := NewStatic(nil)
+		So(err, ShouldNotBeNil)
+	})
+}
+
 func mustLoadPool(t *testing.T) *Pool {
 	pool, err := New(mustLoadSvcInfo(t))
 	xtest.FailOnErr(t, err)
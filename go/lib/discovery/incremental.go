@@ -0,0 +1,262 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/topology"
+)
+
+const (
+	// SinceParam is the query parameter that carries the version of the
+	// client's topology when requesting an update.
+	SinceParam = "since"
+	// VersionHeader is the response header that carries the version of the
+	// served topology.
+	VersionHeader = "Scion-Topology-Version"
+	// BaseHeader is the response header that carries the version of the
+	// topology that a merge patch applies to.
+	BaseHeader = "Scion-Topology-Base"
+	// MergePatchContentType is the content type of responses that contain a
+	// JSON merge patch instead of a full topology.
+	MergePatchContentType = "application/merge-patch+json"
+	// maxPatchVersions is the number of previous topology versions that the
+	// UpdateHandler keeps to compute merge patches against.
+	maxPatchVersions = 8
+)
+
+// ErrNotModified indicates that the topology has not changed since the
+// requested version.
+var ErrNotModified = serrors.New("topology not modified")
+
+// IncrementalBase is a previously fetched topology, against which updates are
+// requested.
+type IncrementalBase struct {
+	// Raw is the raw topology.
+	Raw common.RawBytes
+	// Version is the version of the topology as assigned by the server. A
+	// version of 0 means unknown, in which case the full topology is
+	// requested.
+	Version int64
+}
+
+// FetchTopoUpdate fetches the changes of the topology since base, and returns
+// the updated topology together with the base for the next update. If the
+// server sends a merge patch, it is applied to base. ErrNotModified is
+// returned if the topology has not changed. If verifier is not nil, the
+// signature of the response is verified with it.
+//
+// The version of the topology is taken from the VersionHeader. If the server
+// does not send one, the version is unknown and the next update requests the
+// full topology.
+func FetchTopoUpdate(ctx context.Context, params FetchParams, ds *addr.AppAddr,
+	client *http.Client, verifier Verifier,
+	base IncrementalBase) (*topology.Topo, IncrementalBase, error) {
+
+	url, err := createURL(params, ds)
+	if err != nil {
+		return nil, IncrementalBase{}, common.NewBasicError("Unable to create URL", err)
+	}
+	if base.Version != 0 {
+		url = fmt.Sprintf("%s?%s=%d", url, SinceParam, base.Version)
+	}
+	header, raw, err := fetchBody(ctx, url, client, verifier)
+	if err != nil {
+		return nil, IncrementalBase{}, err
+	}
+	if strings.HasPrefix(header.Get("Content-Type"), MergePatchContentType) {
+		patchBase := header.Get(BaseHeader)
+		if base.Version == 0 || patchBase != strconv.FormatInt(base.Version, 10) {
+			return nil, IncrementalBase{}, common.NewBasicError("Patch for unexpected version",
+				nil, "expected", base.Version, "actual", patchBase)
+		}
+		if raw, err = ApplyMergePatch(base.Raw, raw); err != nil {
+			return nil, IncrementalBase{}, common.NewBasicError("Unable to apply patch", err)
+		}
+	}
+	topo, err := topology.Load(raw)
+	if err != nil {
+		return nil, IncrementalBase{}, common.NewBasicError("Unable to parse topo", err)
+	}
+	// An invalid or missing version is treated as unknown.
+	version, _ := strconv.ParseInt(header.Get(VersionHeader), 10, 64)
+	return topo, IncrementalBase{Raw: raw, Version: version}, nil
+}
+
+// UpdateHandler serves a topology with support for incremental updates, see
+// FetchTopoUpdate. Each distinct topology that is set gets a new version. The
+// versions start at the creation time of the handler in nanoseconds, such that
+// they are not reused after a restart. Clients that request the current
+// version get status 304, clients with one of the previous maxPatchVersions
+// versions get a JSON merge patch, and all other clients get the full
+// topology. The responses are not signed.
+type UpdateHandler struct {
+	mtx      sync.RWMutex
+	version  int64
+	raw      common.RawBytes
+	previous map[int64]common.RawBytes
+}
+
+// NewUpdateHandler creates a handler that does not serve a topology until
+// one is set.
+func NewUpdateHandler() *UpdateHandler {
+	return &UpdateHandler{
+		version:  time.Now().UnixNano(),
+		previous: make(map[int64]common.RawBytes),
+	}
+}
+
+// Set sets the served topology. If raw is equal to the current topology, the
+// version is not changed.
+func (h *UpdateHandler) Set(raw common.RawBytes) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if h.raw != nil && bytes.Equal(h.raw, raw) {
+		return
+	}
+	if h.raw != nil {
+		h.previous[h.version] = h.raw
+		delete(h.previous, h.version-maxPatchVersions)
+	}
+	h.version++
+	h.raw = append(common.RawBytes(nil), raw...)
+}
+
+func (h *UpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mtx.RLock()
+	version, raw := h.version, h.raw
+	since, err := strconv.ParseInt(r.URL.Query().Get(SinceParam), 10, 64)
+	prev, known := h.previous[since]
+	h.mtx.RUnlock()
+	if raw == nil {
+		http.Error(w, "topology not available", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set(VersionHeader, strconv.FormatInt(version, 10))
+	switch {
+	case err != nil:
+	case since == version:
+		w.WriteHeader(http.StatusNotModified)
+		return
+	case known:
+		if patch, err := CreateMergePatch(prev, raw); err == nil {
+			w.Header().Set("Content-Type", MergePatchContentType)
+			w.Header().Set(BaseHeader, strconv.FormatInt(since, 10))
+			w.Write(patch)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(raw)
+}
+
+// CreateMergePatch creates the JSON merge patch (RFC 7396) that turns doc
+// into target. An error is returned if target cannot be expressed as merge
+// patch against doc, e.g., because it contains null values.
+func CreateMergePatch(doc, target common.RawBytes) (common.RawBytes, error) {
+	var d, t interface{}
+	if err := decodeJSON(doc, &d); err != nil {
+		return nil, common.NewBasicError("Unable to parse document", err)
+	}
+	if err := decodeJSON(target, &t); err != nil {
+		return nil, common.NewBasicError("Unable to parse target", err)
+	}
+	patch := diffPatch(d, t)
+	// Merge patches cannot express everything, e.g., null values. Verify that
+	// the patch results in the target.
+	if err := decodeJSON(doc, &d); err != nil {
+		return nil, common.NewBasicError("Unable to parse document", err)
+	}
+	if !reflect.DeepEqual(mergePatch(d, patch), t) {
+		return nil, serrors.New("target not expressible as merge patch")
+	}
+	return json.Marshal(patch)
+}
+
+// ApplyMergePatch applies the JSON merge patch (RFC 7396) to doc, and returns
+// the resulting document.
+func ApplyMergePatch(doc, patch common.RawBytes) (common.RawBytes, error) {
+	var target, p interface{}
+	if err := decodeJSON(doc, &target); err != nil {
+		return nil, common.NewBasicError("Unable to parse document", err)
+	}
+	if err := decodeJSON(patch, &p); err != nil {
+		return nil, common.NewBasicError("Unable to parse patch", err)
+	}
+	return json.MarshalIndent(mergePatch(target, p), "", "  ")
+}
+
+// decodeJSON decodes raw into v. Numbers are kept as json.Number, such that
+// large integers are not truncated.
+func decodeJSON(raw common.RawBytes, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func diffPatch(doc, target interface{}) interface{} {
+	d, ok := doc.(map[string]interface{})
+	if !ok {
+		return target
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		return target
+	}
+	patch := make(map[string]interface{})
+	for k := range d {
+		if _, ok := t[k]; !ok {
+			patch[k] = nil
+		}
+	}
+	for k, v := range t {
+		if old, ok := d[k]; !ok || !reflect.DeepEqual(old, v) {
+			patch[k] = diffPatch(d[k], v)
+		}
+	}
+	return patch
+}
+
+func mergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{}, len(p))
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergePatch(t[k], v)
+	}
+	return t
+}
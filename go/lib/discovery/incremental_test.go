@@ -0,0 +1,258 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/topology"
+)
+
+const (
+	testTopo = `{"ISD_AS": "1-ff00:0:111", "Overlay": "UDP/IPv4", "Timestamp": 100,
+		"MTU": 1472}`
+	testPatch = `{"Timestamp": 200, "MTU": 1400}`
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	tests := map[string]struct {
+		Doc      string
+		Patch    string
+		Expected string
+	}{
+		"replace value": {
+			Doc:      `{"a": "b", "c": "d"}`,
+			Patch:    `{"a": "z"}`,
+			Expected: `{"a": "z", "c": "d"}`,
+		},
+		"remove value": {
+			Doc:      `{"a": {"b": "c", "d": "e"}}`,
+			Patch:    `{"a": {"b": null}}`,
+			Expected: `{"a": {"d": "e"}}`,
+		},
+		"add nested value": {
+			Doc:      `{"a": "b"}`,
+			Patch:    `{"c": {"d": 1}}`,
+			Expected: `{"a": "b", "c": {"d": 1}}`,
+		},
+		"replace array": {
+			Doc:      `{"a": [1, 2]}`,
+			Patch:    `{"a": [3]}`,
+			Expected: `{"a": [3]}`,
+		},
+		"large integers are kept": {
+			Doc:      `{"a": 1}`,
+			Patch:    `{"a": 12345678901234567}`,
+			Expected: `{"a": 12345678901234567}`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			raw, err := ApplyMergePatch(common.RawBytes(test.Doc), common.RawBytes(test.Patch))
+			require.NoError(t, err)
+			assert.JSONEq(t, test.Expected, string(raw))
+		})
+	}
+}
+
+func TestFetchTopoUpdate(t *testing.T) {
+	base := IncrementalBase{Raw: common.RawBytes(testTopo), Version: 100}
+	tests := map[string]struct {
+		Handler         http.HandlerFunc
+		Base            IncrementalBase
+		ExpectedErr     error
+		ExpectedMTU     int
+		ExpectedVersion int64
+	}{
+		"full topology without base": {
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get(SinceParam) != "" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.Header().Set(VersionHeader, "100")
+				w.Write([]byte(testTopo))
+			},
+			ExpectedMTU:     1472,
+			ExpectedVersion: 100,
+		},
+		"not modified": {
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get(SinceParam) != "100" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.WriteHeader(http.StatusNotModified)
+			},
+			Base:        base,
+			ExpectedErr: ErrNotModified,
+		},
+		"patch": {
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", MergePatchContentType)
+				w.Header().Set(BaseHeader, r.URL.Query().Get(SinceParam))
+				w.Header().Set(VersionHeader, "101")
+				w.Write([]byte(testPatch))
+			},
+			Base:            base,
+			ExpectedMTU:     1400,
+			ExpectedVersion: 101,
+		},
+		"full topology despite base": {
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(VersionHeader, "100")
+				w.Write([]byte(testTopo))
+			},
+			Base:            base,
+			ExpectedMTU:     1472,
+			ExpectedVersion: 100,
+		},
+		"missing version is unknown": {
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(testTopo))
+			},
+			Base:        base,
+			ExpectedMTU: 1472,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := httptest.NewServer(test.Handler)
+			defer s.Close()
+			topo, next, err := FetchTopoUpdate(context.Background(),
+				FetchParams{Mode: Static, File: Default}, serverAddr(t, s), nil, nil, test.Base)
+			if test.ExpectedErr != nil {
+				assert.True(t, xerrors.Is(err, test.ExpectedErr), "err: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.ExpectedMTU, topo.MTU)
+			assert.Equal(t, test.ExpectedVersion, next.Version)
+		})
+	}
+	t.Run("patch for other version", func(t *testing.T) {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", MergePatchContentType)
+			w.Header().Set(BaseHeader, "50")
+			w.Write([]byte(testPatch))
+		}))
+		defer s.Close()
+		_, _, err := FetchTopoUpdate(context.Background(),
+			FetchParams{Mode: Static, File: Default}, serverAddr(t, s), nil, nil, base)
+		assert.Error(t, err)
+	})
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	tests := map[string]struct {
+		Doc    string
+		Target string
+		Valid  bool
+	}{
+		"replace value": {
+			Doc:    `{"a": "b", "c": "d"}`,
+			Target: `{"a": "z", "c": "d"}`,
+			Valid:  true,
+		},
+		"remove value": {
+			Doc:    `{"a": {"b": "c", "d": "e"}}`,
+			Target: `{"a": {"d": "e"}}`,
+			Valid:  true,
+		},
+		"add nested value": {
+			Doc:    `{"a": "b"}`,
+			Target: `{"a": "b", "c": {"d": 1}}`,
+			Valid:  true,
+		},
+		"null value": {
+			Doc:    `{"a": "b"}`,
+			Target: `{"a": null}`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			patch, err := CreateMergePatch(common.RawBytes(test.Doc), common.RawBytes(test.Target))
+			if !test.Valid {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			raw, err := ApplyMergePatch(common.RawBytes(test.Doc), patch)
+			require.NoError(t, err)
+			assert.JSONEq(t, test.Target, string(raw))
+		})
+	}
+}
+
+func TestUpdateHandler(t *testing.T) {
+	h := NewUpdateHandler()
+	s := httptest.NewServer(h)
+	defer s.Close()
+	fetch := func(base IncrementalBase) (*topology.Topo, IncrementalBase, error) {
+		return FetchTopoUpdate(context.Background(),
+			FetchParams{Mode: Static, File: Default}, serverAddr(t, s), nil, nil, base)
+	}
+
+	_, _, err := fetch(IncrementalBase{})
+	assert.Error(t, err, "no topology set")
+
+	h.Set(common.RawBytes(testTopo))
+	topo, v1, err := fetch(IncrementalBase{})
+	require.NoError(t, err)
+	assert.Equal(t, 1472, topo.MTU)
+	assert.NotZero(t, v1.Version)
+
+	// Setting the same topology keeps the version.
+	h.Set(common.RawBytes(testTopo))
+	_, _, err = fetch(v1)
+	assert.True(t, xerrors.Is(err, ErrNotModified), "err: %v", err)
+
+	// Changes with the same timestamp are detected.
+	h.Set(common.RawBytes(`{"ISD_AS": "1-ff00:0:111", "Overlay": "UDP/IPv4", "Timestamp": 100,
+		"MTU": 1400}`))
+	topo, v2, err := fetch(v1)
+	require.NoError(t, err)
+	assert.Equal(t, 1400, topo.MTU)
+	assert.NotEqual(t, v1.Version, v2.Version)
+	_, _, err = fetch(v2)
+	assert.True(t, xerrors.Is(err, ErrNotModified), "err: %v", err)
+
+	// Unknown versions get the full topology.
+	topo, _, err = fetch(IncrementalBase{Raw: v1.Raw, Version: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 1400, topo.MTU)
+}
+
+func serverAddr(t *testing.T, s *httptest.Server) *addr.AppAddr {
+	host, port, err := net.SplitHostPort(s.Listener.Addr().String())
+	require.NoError(t, err)
+	p, err := strconv.ParseUint(port, 10, 16)
+	require.NoError(t, err)
+	return &addr.AppAddr{
+		L3: addr.HostFromIP(net.ParseIP(host)),
+		L4: addr.NewL4TCPInfo(uint16(p)),
+	}
+}
@@ -17,7 +17,11 @@ package topofetcher
 import (
 	"context"
 	"net/http"
+	"sync"
 
+	"golang.org/x/xerrors"
+
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/discovery"
 	"github.com/scionproto/scion/go/lib/discovery/discoverypool"
@@ -48,6 +52,20 @@ type Fetcher struct {
 	Callbacks Callbacks
 	// Client is the http Client. If nil, the default Client is used.
 	Client *http.Client
+	// Verifier, if set, verifies the signatures of the fetched topologies.
+	// Unsigned topologies are rejected.
+	Verifier discovery.Verifier
+	// Incremental enables incremental updates. Once a topology has been
+	// fetched, only the changes since its version are requested, see
+	// discovery.FetchTopoUpdate. If the topology has not changed, no callback
+	// is called.
+	Incremental bool
+
+	// baseMtx protects base.
+	baseMtx sync.Mutex
+	// base is the last fetched topology, against which incremental updates
+	// are requested.
+	base discovery.IncrementalBase
 }
 
 // New initializes a fetcher with the given values. Topo is provided to
@@ -81,6 +99,10 @@ func (f *Fetcher) Name() string {
 // appropriate callback functions to notify the caller. RawF and UpdateF are
 // only called if no error has occurred and the topology was parsed correctly.
 // Otherwise ErrorF is called.
+//
+// If fetching from an instance fails, the other instances in the pool are
+// tried in order of their fail count, until a fetch succeeds, every instance
+// has been tried, or ctx is done.
 func (f *Fetcher) Run(ctx context.Context) {
 	if err := f.run(ctx); err != nil && f.Callbacks.Error != nil {
 		f.Callbacks.Error(ctx, err)
@@ -91,14 +113,11 @@ func (f *Fetcher) run(ctx context.Context) error {
 	if f.Pool == nil {
 		return serrors.New("Pool not initialized")
 	}
-	// Choose a DS server.
-	ds, err := f.Pool.Choose()
-	if err != nil {
-		return err
+	topo, raw, err := f.fetch(ctx)
+	if xerrors.Is(err, discovery.ErrNotModified) {
+		return nil
 	}
-	topo, raw, err := discovery.FetchTopoRaw(ctx, f.Params, ds.Addr(), f.Client)
 	if err != nil {
-		ds.Fail()
 		return err
 	}
 	// Update DS server entries based on new topo.
@@ -115,3 +134,51 @@ func (f *Fetcher) run(ctx context.Context) error {
 	}
 	return nil
 }
+
+// fetch fetches the topology, failing over to the next instance on error.
+func (f *Fetcher) fetch(ctx context.Context) (*topology.Topo, common.RawBytes, error) {
+	instances := f.Pool.Instances()
+	if len(instances) == 0 {
+		return nil, nil, serrors.New("Unable to find any discovery service instance")
+	}
+	var errs serrors.List
+	for _, ds := range instances {
+		topo, raw, err := f.fetchFrom(ctx, ds.Addr())
+		if err == nil || xerrors.Is(err, discovery.ErrNotModified) {
+			return topo, raw, err
+		}
+		ds.Fail()
+		errs = append(errs, common.NewBasicError("Fetch failed", err, "ds", ds.Key()))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, nil, serrors.WrapStr("Unable to fetch topology", errs.ToError())
+}
+
+// fetchFrom fetches the topology from ds. For incremental fetchers, the
+// update since the last fetched topology is requested.
+func (f *Fetcher) fetchFrom(ctx context.Context,
+	ds *addr.AppAddr) (*topology.Topo, common.RawBytes, error) {
+
+	if !f.Incremental {
+		return discovery.FetchVerifiedTopoRaw(ctx, f.Params, ds, f.Client, f.Verifier)
+	}
+	f.baseMtx.Lock()
+	base := f.base
+	f.baseMtx.Unlock()
+	topo, next, err := discovery.FetchTopoUpdate(ctx, f.Params, ds, f.Client, f.Verifier, base)
+	if xerrors.Is(err, discovery.ErrNotModified) {
+		return nil, nil, err
+	}
+	f.baseMtx.Lock()
+	defer f.baseMtx.Unlock()
+	if err != nil {
+		// Request the full topology next time, in case the error is caused
+		// by a patch that does not apply to the base.
+		f.base = discovery.IncrementalBase{}
+		return nil, nil, err
+	}
+	f.base = next
+	return topo, next.Raw, nil
+}
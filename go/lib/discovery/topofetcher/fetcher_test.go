@@ -0,0 +1,172 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topofetcher
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/discovery"
+	"github.com/scionproto/scion/go/lib/discovery/discoverypool"
+	"github.com/scionproto/scion/go/lib/scrypto"
+	"github.com/scionproto/scion/go/lib/topology"
+)
+
+func TestFetcherRun(t *testing.T) {
+	raw, err := ioutil.ReadFile("testdata/topology.json")
+	require.NoError(t, err)
+	pub, priv, err := scrypto.GenKeyPair(scrypto.Ed25519)
+	require.NoError(t, err)
+	sig, err := scrypto.Sign(raw, priv, scrypto.Ed25519)
+	require.NoError(t, err)
+
+	failing := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		},
+	))
+	defer failing.Close()
+	unsigned := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.Write(raw)
+		},
+	))
+	defer unsigned.Close()
+	signed := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set(discovery.SignatureHeader, base64.StdEncoding.EncodeToString(sig))
+			w.Write(raw)
+		},
+	))
+	defer signed.Close()
+
+	tests := map[string]struct {
+		Servers  []*httptest.Server
+		Verifier discovery.Verifier
+		Success  bool
+	}{
+		"single instance": {
+			Servers: []*httptest.Server{unsigned},
+			Success: true,
+		},
+		"failover to the second instance": {
+			Servers: []*httptest.Server{failing, unsigned},
+			Success: true,
+		},
+		"all instances fail": {
+			Servers: []*httptest.Server{failing},
+		},
+		"valid signature": {
+			Servers:  []*httptest.Server{signed},
+			Verifier: discovery.KeyVerifier{Key: pub, Algo: scrypto.Ed25519},
+			Success:  true,
+		},
+		"missing signature fails over to signed instance": {
+			Servers:  []*httptest.Server{unsigned, signed},
+			Verifier: discovery.KeyVerifier{Key: pub, Algo: scrypto.Ed25519},
+			Success:  true,
+		},
+		"invalid signature": {
+			Servers:  []*httptest.Server{signed},
+			Verifier: discovery.KeyVerifier{Key: make([]byte, len(pub)), Algo: scrypto.Ed25519},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			instances := make(map[string]*addr.AppAddr)
+			for i, s := range test.Servers {
+				// The keys are ordered like the servers, such that
+				// instances with equal fail counts are tried in order.
+				instances[strconv.Itoa(i)] = serverAddr(t, s)
+			}
+			pool, err := discoverypool.NewStatic(instances)
+			require.NoError(t, err)
+			var topo *topology.Topo
+			var fetchErr error
+			f := &Fetcher{
+				Pool:   pool,
+				Params: discovery.FetchParams{Mode: discovery.Static, File: discovery.Default},
+				Callbacks: Callbacks{
+					Update: func(_ context.Context, t *topology.Topo) { topo = t },
+					Error:  func(_ context.Context, err error) { fetchErr = err },
+				},
+				Verifier: test.Verifier,
+			}
+			f.Run(context.Background())
+			if test.Success {
+				assert.NoError(t, fetchErr)
+				require.NotNil(t, topo)
+				assert.Equal(t, "1-ff00:0:111", topo.ISD_AS.String())
+			} else {
+				assert.Error(t, fetchErr)
+				assert.Nil(t, topo)
+			}
+		})
+	}
+}
+
+func TestFetcherRunIncremental(t *testing.T) {
+	raw, err := ioutil.ReadFile("testdata/topology.json")
+	require.NoError(t, err)
+	var sinces []string
+	h := discovery.NewUpdateHandler()
+	h.Set(raw)
+	s := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			sinces = append(sinces, r.URL.Query().Get(discovery.SinceParam))
+			h.ServeHTTP(w, r)
+		},
+	))
+	defer s.Close()
+	pool, err := discoverypool.NewStatic(map[string]*addr.AppAddr{"ds": serverAddr(t, s)})
+	require.NoError(t, err)
+	var updates int
+	f := &Fetcher{
+		Pool:   pool,
+		Params: discovery.FetchParams{Mode: discovery.Dynamic, File: discovery.Default},
+		Callbacks: Callbacks{
+			Update: func(context.Context, *topology.Topo) { updates++ },
+			Error:  func(_ context.Context, err error) { t.Error(err) },
+		},
+		Incremental: true,
+	}
+	f.Run(context.Background())
+	f.Run(context.Background())
+	assert.Equal(t, 1, updates)
+	require.Len(t, sinces, 2)
+	assert.Empty(t, sinces[0])
+	assert.NotEmpty(t, sinces[1])
+}
+
+func serverAddr(t *testing.T, s *httptest.Server) *addr.AppAddr {
+	host, port, err := net.SplitHostPort(s.Listener.Addr().String())
+	require.NoError(t, err)
+	p, err := strconv.ParseUint(port, 10, 16)
+	require.NoError(t, err)
+	return &addr.AppAddr{
+		L3: addr.HostFromIP(net.ParseIP(host)),
+		L4: addr.NewL4TCPInfo(uint16(p)),
+	}
+}
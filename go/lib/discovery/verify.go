@@ -0,0 +1,76 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/scrypto"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+// SignatureHeader is the response header that carries the base64 encoded
+// signature of a signed topology file.
+const SignatureHeader = "Scion-Topology-Signature"
+
+var (
+	// ErrNoSignature indicates that a topology file is not signed.
+	ErrNoSignature = serrors.New("topology not signed")
+	// ErrInvalidSignature indicates that the signature of a topology file
+	// does not verify.
+	ErrInvalidSignature = serrors.New("invalid topology signature")
+)
+
+// Verifier verifies the signatures of topology files.
+type Verifier interface {
+	// Verify returns an error if sig is not a valid signature of raw.
+	Verify(ctx context.Context, raw, sig common.RawBytes) error
+}
+
+var _ Verifier = KeyVerifier{}
+
+// KeyVerifier verifies signatures with a fixed public key.
+type KeyVerifier struct {
+	// Key is the public key of the discovery service.
+	Key common.RawBytes
+	// Algo is the signature algorithm, e.g., scrypto.Ed25519.
+	Algo string
+}
+
+func (v KeyVerifier) Verify(_ context.Context, raw, sig common.RawBytes) error {
+	if err := scrypto.Verify(raw, sig, v.Key, v.Algo); err != nil {
+		return serrors.Wrap(ErrInvalidSignature, err)
+	}
+	return nil
+}
+
+// verifySignature verifies the signature of raw, which is taken from the
+// header.
+func verifySignature(ctx context.Context, verifier Verifier, raw common.RawBytes,
+	header http.Header) error {
+
+	encoded := header.Get(SignatureHeader)
+	if encoded == "" {
+		return ErrNoSignature
+	}
+	sig, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return serrors.Wrap(ErrInvalidSignature, err)
+	}
+	return verifier.Verify(ctx, raw, sig)
+}
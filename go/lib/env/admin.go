@@ -0,0 +1,50 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+// adminMux serves the admin endpoints. Contrary to http.DefaultServeMux, which
+// serves the metrics and debug endpoints, it is only exposed on a loopback
+// address (see Metrics.Admin).
+var adminMux = http.NewServeMux()
+
+// HandleAdmin registers handler for pattern on the admin endpoint. Handlers
+// that change the state of the service must be registered here instead of on
+// http.DefaultServeMux. Handlers are only reachable if Metrics.Admin is set.
+func HandleAdmin(pattern string, handler http.Handler) {
+	adminMux.Handle(pattern, handler)
+}
+
+// validateAdminAddr checks that address is a loopback address.
+func validateAdminAddr(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return common.NewBasicError("Unable to parse admin address", err, "addr", address)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return serrors.New("Admin address must be a loopback address", "addr", address)
+	}
+	return nil
+}
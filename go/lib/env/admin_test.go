@@ -0,0 +1,42 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsValidateAdmin(t *testing.T) {
+	tests := map[string]struct {
+		Admin          string
+		ErrorAssertion assert.ErrorAssertionFunc
+	}{
+		"unset":          {Admin: "", ErrorAssertion: assert.NoError},
+		"IPv4 loopback":  {Admin: "127.0.0.1:30455", ErrorAssertion: assert.NoError},
+		"IPv6 loopback":  {Admin: "[::1]:30455", ErrorAssertion: assert.NoError},
+		"localhost":      {Admin: "localhost:30455", ErrorAssertion: assert.NoError},
+		"public address": {Admin: "192.0.2.1:30455", ErrorAssertion: assert.Error},
+		"all interfaces": {Admin: ":30455", ErrorAssertion: assert.Error},
+		"missing port":   {Admin: "127.0.0.1", ErrorAssertion: assert.Error},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := Metrics{Admin: test.Admin}
+			test.ErrorAssertion(t, cfg.Validate())
+		})
+	}
+}
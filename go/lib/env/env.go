@@ -38,6 +38,7 @@ import (
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/config"
 	"github.com/scionproto/scion/go/lib/fatal"
+	"github.com/scionproto/scion/go/lib/infra"
 	"github.com/scionproto/scion/go/lib/infra/modules/itopo"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/overlay"
@@ -135,6 +136,10 @@ type SciondClient struct {
 	// InitialConnectPeriod is the maximum amount of time spent attempting to
 	// connect to sciond on start.
 	InitialConnectPeriod util.DurWrap
+	// PrewarmDsts are the destination ISD-ASes whose paths are fetched on
+	// start, such that the first packets to them do not wait for a path
+	// lookup.
+	PrewarmDsts []addr.IA
 }
 
 func (cfg *SciondClient) InitDefaults() {
@@ -233,10 +238,22 @@ var _ config.Config = (*Metrics)(nil)
 
 type Metrics struct {
 	config.NoDefaulter
-	config.NoValidator
 	// Prometheus contains the address to export prometheus metrics on. If
-	// not set, metrics are not exported.
+	// not set, metrics are not exported. The same address serves the /health
+	// and /ready endpoints for orchestration.
 	Prometheus string
+	// Admin contains the address of the admin endpoints, which change the
+	// state of the service (see HandleAdmin), e.g., the /loglevel endpoint
+	// to change logging levels at runtime. It must be a loopback address. If
+	// not set, the admin endpoints are not exposed.
+	Admin string
+}
+
+func (cfg *Metrics) Validate() error {
+	if cfg.Admin != "" {
+		return validateAdminAddr(cfg.Admin)
+	}
+	return nil
 }
 
 func (cfg *Metrics) Sample(dst io.Writer, path config.Path, _ config.CtxMap) {
@@ -251,6 +268,8 @@ func (cfg *Metrics) StartPrometheus() {
 	fatal.Check()
 	if cfg.Prometheus != "" {
 		http.Handle("/metrics", promhttp.Handler())
+		http.Handle("/health", healthHandler(healthChecks))
+		http.Handle("/ready", healthHandler(healthChecks, readyChecks))
 		log.Info("Exporting prometheus metrics", "addr", cfg.Prometheus)
 		go func() {
 			defer log.LogPanicAndExit()
@@ -259,6 +278,16 @@ func (cfg *Metrics) StartPrometheus() {
 			}
 		}()
 	}
+	if cfg.Admin != "" {
+		HandleAdmin("/loglevel", log.LevelHTTPHandler())
+		log.Info("Exporting admin endpoints", "addr", cfg.Admin)
+		go func() {
+			defer log.LogPanicAndExit()
+			if err := http.ListenAndServe(cfg.Admin, adminMux); err != nil {
+				fatal.Fatal(common.NewBasicError("Admin HTTP ListenAndServe error", err))
+			}
+		}()
+	}
 }
 
 // Tracing contains configuration for tracing.
@@ -309,12 +338,66 @@ func (cfg *Tracing) NewTracer(id string) (opentracing.Tracer, io.Closer, error)
 		jaegercfg.Injector(opentracing.Binary, bp))
 }
 
+var _ config.Config = (*CircuitBreaker)(nil)
+
+// CircuitBreaker contains the configuration of the per-destination circuit
+// breakers for the outgoing requests of control-plane speakers.
+type CircuitBreaker struct {
+	config.NoDefaulter
+	// Enabled enables the circuit breakers.
+	Enabled bool
+	// FailureThreshold is the number of consecutive timed out requests
+	// towards a destination after which further requests fail immediately.
+	// If it is 0, the messenger default is used.
+	FailureThreshold int
+	// OpenTimeout is the time after which a probe request is let through an
+	// open circuit. If it is 0, the messenger default is used.
+	OpenTimeout util.DurWrap
+}
+
+func (cfg *CircuitBreaker) Validate() error {
+	if cfg.FailureThreshold < 0 {
+		return serrors.New("FailureThreshold must not be negative",
+			"value", cfg.FailureThreshold)
+	}
+	if cfg.OpenTimeout.Duration < 0 {
+		return serrors.New("OpenTimeout must not be negative", "value", cfg.OpenTimeout)
+	}
+	return nil
+}
+
+func (cfg *CircuitBreaker) Sample(dst io.Writer, path config.Path, _ config.CtxMap) {
+	config.WriteString(dst, circuitBreakerSample)
+}
+
+func (cfg *CircuitBreaker) ConfigName() string {
+	return "circuit_breaker"
+}
+
 // QUIC contains configuration for control-plane speakers.
 type QUIC struct {
 	ResolutionFraction float64
 	Address            string
 	CertFile           string
 	KeyFile            string
+	// ClientCAFile, if set, contains the PEM encoded certificates used to
+	// verify the client certificates of incoming QUIC connections.
+	ClientCAFile string
+	// AuthTypes lists the names of the message types, e.g., SegRequest, whose
+	// requesters must authenticate with a client certificate.
+	AuthTypes []string
+}
+
+func (cfg *QUIC) Validate() error {
+	if len(cfg.AuthTypes) > 0 && (cfg.Address == "" || cfg.ClientCAFile == "") {
+		return serrors.New("AuthTypes requires Address and ClientCAFile")
+	}
+	for _, t := range cfg.AuthTypes {
+		if _, err := infra.MessageTypeFromString(t); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (cfg *QUIC) Sample(dst io.Writer, path config.Path, _ config.CtxMap) {
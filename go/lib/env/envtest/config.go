@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/uber/jaeger-client-go"
@@ -94,11 +95,24 @@ func CheckTestLogging(t *testing.T, cfg *env.Logging, id string) {
 	assert.Equal(t, log.DefaultFileMaxAgeDays, int(cfg.File.MaxAge))
 	assert.Equal(t, log.DefaultFileMaxBackups, int(cfg.File.MaxBackups))
 	assert.Equal(t, log.DefaultFileFlushSeconds, *cfg.File.FlushInterval)
+	assert.Equal(t, log.FormatHuman, cfg.File.Format)
 	assert.Equal(t, log.DefaultConsoleLevel, cfg.Console.Level)
+	assert.Equal(t, log.FormatHuman, cfg.Console.Format)
+	assert.Zero(t, cfg.Sampling.Interval.Duration)
+	assert.Zero(t, cfg.Sampling.First)
+	assert.Zero(t, cfg.Sampling.Thereafter)
 }
 
 func CheckTestMetrics(t *testing.T, cfg *env.Metrics) {
 	assert.Empty(t, cfg.Prometheus)
+	assert.Empty(t, cfg.Admin)
+}
+
+func CheckTestCircuitBreaker(t *testing.T, cfg *env.CircuitBreaker) {
+	assert.False(t, cfg.Enabled)
+	assert.Equal(t, 5, cfg.FailureThreshold)
+	assert.Equal(t, 30*time.Second, cfg.OpenTimeout.Duration)
+	assert.NoError(t, cfg.Validate())
 }
 
 func CheckTestTracing(t *testing.T, cfg *env.Tracing) {
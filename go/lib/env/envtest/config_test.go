@@ -69,6 +69,16 @@ func TestTracingSample(t *testing.T) {
 	CheckTestTracing(t, &cfg)
 }
 
+func TestCircuitBreakerSample(t *testing.T) {
+	var sample bytes.Buffer
+	var cfg env.CircuitBreaker
+	cfg.Sample(&sample, nil, nil)
+	meta, err := toml.Decode(sample.String(), &cfg)
+	assert.NoError(t, err)
+	assert.Empty(t, meta.Undecoded())
+	CheckTestCircuitBreaker(t, &cfg)
+}
+
 func TestSciondClientSample(t *testing.T) {
 	var sample bytes.Buffer
 	var cfg env.SciondClient
@@ -23,15 +23,18 @@ import (
 )
 
 var (
-	configFile string
-	helpConfig bool
-	version    bool
+	configFile     string
+	helpConfig     bool
+	validateConfig bool
+	version        bool
 )
 
 // AddFlags adds the config and sample flags.
 func AddFlags() {
 	flag.StringVar(&configFile, "config", "", "TOML config file.")
 	flag.BoolVar(&helpConfig, "help-config", false, "Output sample commented config file.")
+	flag.BoolVar(&validateConfig, "validate-config", false,
+		"Validate the config file and the files it references, then exit.")
 	flag.BoolVar(&version, "version", false, "Output version information and exit.")
 }
 
@@ -42,15 +45,17 @@ func ConfigFile() string {
 
 // Usage outputs run-time help to stdout.
 func Usage() {
-	fmt.Printf("Usage: %s -config <FILE> \n   or: %s -help-config\n\nArguments:\n",
-		os.Args[0], os.Args[0])
+	fmt.Printf("Usage: %s -config <FILE> \n   or: %s -config <FILE> -validate-config\n"+
+		"   or: %s -help-config\n\nArguments:\n", os.Args[0], os.Args[0], os.Args[0])
 	flag.CommandLine.SetOutput(os.Stdout)
 	flag.PrintDefaults()
 }
 
 // CheckFlags checks whether the config or help-config flags have been set. In case the
 // help-config flag is set, the config flag is ignored and a commented sample config
-// is written to stdout.
+// is written to stdout. In case the validate-config flag is set, the config file
+// and the files it references are validated, the result is reported and the
+// program should exit. A non-zero return code indicates an invalid config.
 //
 // The first return value is the return code of the program. The second value
 // indicates whether the program can continue with its execution or should exit.
@@ -68,5 +73,22 @@ func CheckFlags(sampler config.Sampler) (int, bool) {
 		flag.Usage()
 		return 1, false
 	}
+	if validateConfig {
+		return checkConfig(sampler)
+	}
 	return 0, true
 }
+
+func checkConfig(sampler config.Sampler) (int, bool) {
+	cfg, ok := sampler.(config.Config)
+	if !ok {
+		fmt.Fprintln(os.Stderr, "Err: Config validation not supported")
+		return 1, false
+	}
+	if err := ValidateConfigFile(configFile, cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Err: %s\n", err)
+		return 1, false
+	}
+	fmt.Printf("Config file %s is valid\n", configFile)
+	return 0, false
+}
@@ -0,0 +1,137 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/infra/modules/itopo"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+// HealthCheckTimeout is the maximum time spent evaluating all checks of a
+// single /health or /ready request.
+const HealthCheckTimeout = 5 * time.Second
+
+// HealthCheck checks the state of a component. It returns nil if the
+// component is healthy.
+type HealthCheck func(ctx context.Context) error
+
+var (
+	healthChecks = newCheckRegistry()
+	readyChecks  = newCheckRegistry()
+)
+
+// AddHealthCheck registers a liveness check under name. The check is
+// evaluated on every request to the /health and /ready endpoints. A failing
+// health check signals that the service should be restarted.
+func AddHealthCheck(name string, check HealthCheck) {
+	healthChecks.add(name, check)
+}
+
+// AddReadinessCheck registers a readiness check under name. The check is
+// evaluated on every request to the /ready endpoint. A failing readiness check
+// signals that the service should not receive traffic yet.
+func AddReadinessCheck(name string, check HealthCheck) {
+	readyChecks.add(name, check)
+}
+
+// TopologyCheck is a readiness check that fails if no topology is loaded in
+// itopo. It must only be registered after itopo is initialized.
+func TopologyCheck(_ context.Context) error {
+	if itopo.Get() == nil {
+		return serrors.New("topology not loaded")
+	}
+	return nil
+}
+
+// UnixSocketCheck returns a health check that fails if no process accepts
+// connections on the UNIX socket at path, e.g., the dispatcher socket.
+func UnixSocketCheck(path string) HealthCheck {
+	return func(ctx context.Context) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "unix", path)
+		if err != nil {
+			return serrors.WrapStr("socket not reachable", err, "path", path)
+		}
+		return conn.Close()
+	}
+}
+
+// HealthStatus is the JSON body returned by the /health and /ready endpoints.
+type HealthStatus struct {
+	// Healthy is true if all checks passed.
+	Healthy bool `json:"healthy"`
+	// Checks maps each check name to "ok", or to the error of the check.
+	Checks map[string]string `json:"checks"`
+}
+
+type checkRegistry struct {
+	mtx    sync.Mutex
+	checks map[string]HealthCheck
+}
+
+func newCheckRegistry() *checkRegistry {
+	return &checkRegistry{checks: make(map[string]HealthCheck)}
+}
+
+func (r *checkRegistry) add(name string, check HealthCheck) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.checks[name] = check
+}
+
+// run evaluates all checks in r and records the results in status.
+func (r *checkRegistry) run(ctx context.Context, status *HealthStatus) {
+	r.mtx.Lock()
+	checks := make(map[string]HealthCheck, len(r.checks))
+	names := make([]string, 0, len(r.checks))
+	for name, check := range r.checks {
+		checks[name] = check
+		names = append(names, name)
+	}
+	r.mtx.Unlock()
+	sort.Strings(names)
+	for _, name := range names {
+		if err := checks[name](ctx); err != nil {
+			status.Healthy = false
+			status.Checks[name] = err.Error()
+			continue
+		}
+		status.Checks[name] = "ok"
+	}
+}
+
+func healthHandler(registries ...*checkRegistry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancelF := context.WithTimeout(r.Context(), HealthCheckTimeout)
+		defer cancelF()
+		status := &HealthStatus{Healthy: true, Checks: make(map[string]string)}
+		for _, registry := range registries {
+			registry.run(ctx, status)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if !status.Healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(status)
+	})
+}
@@ -0,0 +1,88 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+func TestHealthHandler(t *testing.T) {
+	health := newCheckRegistry()
+	ready := newCheckRegistry()
+	health.add("alive", func(_ context.Context) error { return nil })
+	var serving bool
+	ready.add("messenger", func(_ context.Context) error {
+		if !serving {
+			return serrors.New("not serving")
+		}
+		return nil
+	})
+
+	get := func(h http.Handler) (int, HealthStatus) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		var status HealthStatus
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&status))
+		return rec.Code, status
+	}
+
+	code, status := get(healthHandler(health))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, HealthStatus{Healthy: true, Checks: map[string]string{"alive": "ok"}},
+		status)
+
+	code, status = get(healthHandler(health, ready))
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.False(t, status.Healthy)
+	assert.Equal(t, "not serving", status.Checks["messenger"])
+
+	serving = true
+	code, status = get(healthHandler(health, ready))
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, status.Healthy)
+
+	health.add("db", func(_ context.Context) error { return serrors.New("unreachable") })
+	code, status = get(healthHandler(health, ready))
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "unreachable", status.Checks["db"])
+}
+
+func TestUnixSocketCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "health")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test.sock")
+	check := UnixSocketCheck(path)
+
+	assert.Error(t, check(context.Background()))
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	assert.NoError(t, check(context.Background()))
+	l.Close()
+	assert.Error(t, check(context.Background()))
+}
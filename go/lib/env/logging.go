@@ -35,7 +35,6 @@ var (
 var _ (config.Config) = (*Logging)(nil)
 
 type Logging struct {
-	config.NoValidator
 	File struct {
 		// Path is the location of the logging file. If unset, no file logging
 		// is performed.
@@ -51,11 +50,28 @@ type Logging struct {
 		// FlushInterval specifies how frequently to flush to the log file,
 		// in seconds (defaults to lib/log default).
 		FlushInterval *int
+		// Format of file logging, either human or json (defaults to human).
+		Format string
 	}
 
 	Console struct {
 		// Level of console logging (defaults to lib/log default).
 		Level string
+		// Format of console logging, either human or json (defaults to human).
+		Format string
+	}
+
+	Sampling struct {
+		// Interval is the duration of a sampling window. If 0, sampling is
+		// disabled.
+		Interval util.DurWrap
+		// First is the number of identical debug/info messages logged per
+		// window before sampling starts.
+		First int
+		// Thereafter is the sampling rate after the first messages, i.e.,
+		// only every Thereafter-th message is logged. If 0, all further
+		// messages in the window are dropped.
+		Thereafter int
 	}
 }
 
@@ -81,6 +97,32 @@ func (cfg *Logging) InitDefaults() {
 		s := log.DefaultFileFlushSeconds
 		cfg.File.FlushInterval = &s
 	}
+	if cfg.File.Format == "" {
+		cfg.File.Format = log.FormatHuman
+	}
+	if cfg.Console.Format == "" {
+		cfg.Console.Format = log.FormatHuman
+	}
+}
+
+// Validate checks that the logging formats and the sampling configuration are
+// valid.
+func (cfg *Logging) Validate() error {
+	if err := log.ValidateFormat(cfg.File.Format); err != nil {
+		return err
+	}
+	if err := log.ValidateFormat(cfg.Console.Format); err != nil {
+		return err
+	}
+	return cfg.samplingConfig().Validate()
+}
+
+func (cfg *Logging) samplingConfig() log.SamplingConfig {
+	return log.SamplingConfig{
+		Interval:   cfg.Sampling.Interval.Duration,
+		First:      cfg.Sampling.First,
+		Thereafter: cfg.Sampling.Thereafter,
+	}
 }
 
 func (cfg *Logging) Sample(dst io.Writer, path config.Path, ctx config.CtxMap) {
@@ -93,6 +135,10 @@ func (cfg *Logging) Sample(dst io.Writer, path config.Path, ctx config.CtxMap) {
 			Text: loggingConsoleSample,
 			Name: "console",
 		},
+		config.StringSampler{
+			Text: loggingSamplingSample,
+			Name: "sampling",
+		},
 	)
 }
 
@@ -106,10 +152,10 @@ func InitLogging(cfg *Logging) error {
 	if err := setupFileLogging(cfg); err != nil {
 		return err
 	}
-	if err := log.SetupLogConsole(cfg.Console.Level); err != nil {
+	if err := log.SetupLogConsole(cfg.Console.Level, cfg.Console.Format); err != nil {
 		return err
 	}
-	return nil
+	return log.SetupSampling(cfg.samplingConfig())
 }
 
 func setupFileLogging(cfg *Logging) error {
@@ -122,6 +168,7 @@ func setupFileLogging(cfg *Logging) error {
 			int(cfg.File.MaxAge),
 			int(cfg.File.MaxBackups),
 			*cfg.File.FlushInterval,
+			cfg.File.Format,
 		)
 	}
 	return nil
@@ -39,6 +39,10 @@ Path = "/run/shm/sciond/default.sock"
 
 # Maximum time spent attempting to connect to sciond on start. (default 20s)
 InitialConnectPeriod = "20s"
+
+# The destination ISD-ASes whose paths are fetched on start, such that the
+# first packets to them do not wait for a path lookup. (default [])
+# PrewarmDsts = ["1-ff00:0:111", "2-ff00:0:210"]
 `
 
 const loggingFileSample = `
@@ -61,17 +65,48 @@ MaxBackups = 10
 # are immediately flushed. If negative, messages are never flushed
 # automatically. (default 5)
 FlushInterval = 5
+
+# File logging format. (human|json) (default human)
+Format = "human"
 `
 
 const loggingConsoleSample = `
 # Console logging level (trace|debug|info|warn|error|crit) (default crit)
 Level = "crit"
+
+# Console logging format. (human|json) (default human)
+Format = "human"
+`
+
+const loggingSamplingSample = `
+# Duration of a sampling window. Identical debug and info messages are
+# rate-limited per window; warnings and more severe messages are never
+# dropped. If 0, sampling is disabled. (default 0s)
+Interval = "0s"
+
+# Number of identical messages logged per window before sampling starts.
+# (default 0)
+First = 0
+
+# After the first messages, only every Thereafter-th message is logged. If 0,
+# all further identical messages in the window are dropped. If sampling is
+# enabled, First or Thereafter must be set. (default 0)
+Thereafter = 0
 `
 
 const metricsSample = `
 # The address to export prometheus metrics on (host:port or ip:port or :port).
+# The same address serves the /health and /ready endpoints for orchestration.
 # If not set, metrics are not exported. (default "")
 Prometheus = ""
+
+# The address to serve the admin endpoints on (ip:port or localhost:port).
+# Admin endpoints change the state of the service, and are therefore only
+# served on a loopback address. The /loglevel endpoint can be used to change
+# logging levels at runtime (e.g., curl -X PUT -d console=debug
+# localhost:port/loglevel). If not set, the admin endpoints are not exposed.
+# (default "")
+Admin = ""
 `
 
 const tracingSample = `
@@ -84,6 +119,20 @@ Debug = false
 Agent = "localhost:6831"
 `
 
+const circuitBreakerSample = `
+# Enable per-destination circuit breakers for outgoing control-plane requests.
+# Once the circuit towards a destination is open, requests to it fail
+# immediately instead of waiting for a timeout. (default false)
+Enabled = false
+
+# Number of consecutive timed out requests towards a destination after which
+# the circuit is opened. (default 5)
+FailureThreshold = 5
+
+# Time a circuit stays open before a probe request is let through. (default 30s)
+OpenTimeout = "30s"
+`
+
 const quicSample = `
 # The address to start a QUIC server on (ip:port). If not set, a QUIC server is
 # not started. (default "")
@@ -95,6 +144,18 @@ CertFile = "/etc/scion/quic/tls.pem"
 # Key file to use for authenticating QUIC connections.
 KeyFile = "/etc/scion/quic/tls.key"
 
+# File with the PEM encoded CA certificates used to verify the client
+# certificates of incoming QUIC connections. If set, clients must present a
+# certificate issued by one of these CAs. (default "")
+ClientCAFile = ""
+
+# Names of the message types (e.g., "SegRequest") whose requesters must
+# authenticate. Such requests are only accepted over QUIC, from clients whose
+# certificate has the requester's ISD-AS (e.g., "1-ff00:0:110") as subject
+# common name. Other requests are rejected with a reject ack. Requires Address
+# and ClientCAFile. (default [], no authentication)
+AuthTypes = []
+
 # SVCResolutionFraction enables SVC resolution for traffic to SVC
 # destinations in a way that is also compatible with control plane servers
 # that do not implement the SVC Resolution Mechanism. The value represents
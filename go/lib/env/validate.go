@@ -0,0 +1,77 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"github.com/BurntSushi/toml"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/config"
+	"github.com/scionproto/scion/go/lib/topology"
+)
+
+// FileChecker is implemented by configurations that reference additional
+// files (e.g., topology or key files). CheckFiles verifies that the
+// referenced files exist and can be parsed.
+type FileChecker interface {
+	CheckFiles() error
+}
+
+// CheckFilesAll calls CheckFiles on all the arguments that implement
+// FileChecker. The first error encountered is returned.
+func CheckFilesAll(cfgs ...interface{}) error {
+	for _, cfg := range cfgs {
+		if checker, ok := cfg.(FileChecker); ok {
+			if err := checker.CheckFiles(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateConfigFile decodes the TOML file into cfg, initializes the defaults
+// and validates the result. The file is decoded in the same way the services
+// load it at startup, i.e., keys that are not known to cfg are ignored. If cfg
+// implements FileChecker, the referenced files are checked as well.
+func ValidateConfigFile(file string, cfg config.Config) error {
+	if _, err := toml.DecodeFile(file, cfg); err != nil {
+		return common.NewBasicError("Unable to parse config", err, "file", file)
+	}
+	cfg.InitDefaults()
+	if err := cfg.Validate(); err != nil {
+		return common.NewBasicError("Invalid config", err, "file", file)
+	}
+	if err := CheckFilesAll(cfg); err != nil {
+		return common.NewBasicError("Invalid referenced file", err, "file", file)
+	}
+	return nil
+}
+
+// CheckFiles checks that the topology file can be loaded.
+func (cfg *General) CheckFiles() error {
+	_, err := cfg.LoadTopology()
+	return err
+}
+
+// LoadTopology loads the topology file. Services that need the contents of the
+// topology to check further files use it instead of CheckFiles.
+func (cfg *General) LoadTopology() (*topology.Topo, error) {
+	topo, err := topology.LoadFromFile(cfg.Topology)
+	if err != nil {
+		return nil, common.NewBasicError("Unable to load topology", err, "path", cfg.Topology)
+	}
+	return topo, nil
+}
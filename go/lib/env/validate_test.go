@@ -0,0 +1,97 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/config"
+)
+
+type testConfig struct {
+	General General
+	Logging Logging
+}
+
+func (cfg *testConfig) InitDefaults() {
+	config.InitAll(&cfg.General, &cfg.Logging)
+}
+
+func (cfg *testConfig) Validate() error {
+	return config.ValidateAll(&cfg.General, &cfg.Logging)
+}
+
+func (cfg *testConfig) Sample(dst io.Writer, path config.Path, ctx config.CtxMap) {}
+
+func (cfg *testConfig) ConfigName() string {
+	return "test"
+}
+
+func (cfg *testConfig) CheckFiles() error {
+	return CheckFilesAll(&cfg.General)
+}
+
+func TestValidateConfigFile(t *testing.T) {
+	tests := map[string]struct {
+		Config    string
+		Assertion assert.ErrorAssertionFunc
+	}{
+		"valid": {
+			Config: `[general]
+ID = "cs1-ff00_0_110-1"
+Topology = "testdata/dir/topology.json"`,
+			Assertion: assert.NoError,
+		},
+		"malformed TOML": {
+			Config:    `[general`,
+			Assertion: assert.Error,
+		},
+		"unknown key is ignored": {
+			Config: `[general]
+ID = "cs1-ff00_0_110-1"
+Topology = "testdata/dir/topology.json"
+Unknown = 1`,
+			Assertion: assert.NoError,
+		},
+		"missing ID": {
+			Config: `[general]
+Topology = "testdata/dir/topology.json"`,
+			Assertion: assert.Error,
+		},
+		"missing topology": {
+			Config: `[general]
+ID = "cs1-ff00_0_110-1"
+Topology = "testdata/nonexistent.json"`,
+			Assertion: assert.Error,
+		},
+	}
+	dir, err := ioutil.TempDir("", "validate-config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			file := filepath.Join(dir, "config.toml")
+			require.NoError(t, ioutil.WriteFile(file, []byte(test.Config), 0644))
+			test.Assertion(t, ValidateConfigFile(file, &testConfig{}))
+		})
+	}
+}
@@ -18,6 +18,7 @@ package hostinfo
 import (
 	"fmt"
 	"net"
+	"strings"
 
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
@@ -26,6 +27,61 @@ import (
 	"github.com/scionproto/scion/go/lib/topology"
 )
 
+// Preference is the address family that is conveyed if a host is reachable
+// over both IPv4 and IPv6.
+type Preference uint8
+
+const (
+	// PreferAny conveys both addresses. Clients use the IPv4 address.
+	PreferAny Preference = iota
+	// PreferIPv4 conveys only the IPv4 address, if the host has one.
+	PreferIPv4
+	// PreferIPv6 conveys only the IPv6 address, if the host has one.
+	PreferIPv6
+)
+
+// ParsePreference parses the string representation of an address family
+// preference.
+func ParsePreference(s string) (Preference, error) {
+	switch strings.ToLower(s) {
+	case "", "any":
+		return PreferAny, nil
+	case "ipv4":
+		return PreferIPv4, nil
+	case "ipv6":
+		return PreferIPv6, nil
+	}
+	return 0, common.NewBasicError("Unknown address family preference", nil, "preference", s)
+}
+
+func (p Preference) String() string {
+	switch p {
+	case PreferAny:
+		return "any"
+	case PreferIPv4:
+		return "ipv4"
+	case PreferIPv6:
+		return "ipv6"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(p))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (p Preference) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (p *Preference) UnmarshalText(text []byte) error {
+	pref, err := ParsePreference(string(text))
+	if err != nil {
+		return err
+	}
+	*p = pref
+	return nil
+}
+
 // Host contains connectivity information for a host.
 type Host struct {
 	Addrs Addrs  `capnp:"addrs"`
@@ -49,23 +105,54 @@ func FromHostAddr(host addr.HostAddr, port uint16) *Host {
 }
 
 func FromTopoAddr(topoAddr topology.TopoAddr) Host {
+	return FromTopoAddrWithPreference(topoAddr, PreferAny)
+}
+
+// FromTopoAddrWithPreference returns the host info for the public address of
+// topoAddr. If topoAddr has an address of the preferred family, only that
+// address and its port are conveyed.
+func FromTopoAddrWithPreference(topoAddr topology.TopoAddr, pref Preference) Host {
 	ipv4, port4 := topoAddrToIPv4AndPort(topoAddr)
 	ipv6, port6 := topoAddrToIPv6AndPort(topoAddr)
-	return buildHostInfo(ipv4, ipv6, port4, port6)
+	return buildHostInfo(ipv4, ipv6, port4, port6, pref)
+}
+
+// FromTopoAddrPerOverlay returns separate host infos for the IPv4 and the
+// IPv6 public address of topoAddr, each with the port of the respective
+// address. The host info is nil for address types topoAddr does not have.
+func FromTopoAddrPerOverlay(topoAddr topology.TopoAddr) (*Host, *Host) {
+	var ipv4, ipv6 *Host
+	if ip, port := topoAddrToIPv4AndPort(topoAddr); ip != nil {
+		ipv4 = &Host{Addrs: Addrs{IPv4: ip}, Port: port}
+	}
+	if ip, port := topoAddrToIPv6AndPort(topoAddr); ip != nil {
+		ipv6 = &Host{Addrs: Addrs{IPv6: ip}, Port: port}
+	}
+	return ipv4, ipv6
 }
 
 func FromTopoBRAddr(topoBRAddr topology.TopoBRAddr) Host {
+	return FromTopoBRAddrWithPreference(topoBRAddr, PreferAny)
+}
+
+// FromTopoBRAddrWithPreference returns the host info for the public overlay
+// address of topoBRAddr. If topoBRAddr has an address of the preferred family,
+// only that address and its port are conveyed.
+func FromTopoBRAddrWithPreference(topoBRAddr topology.TopoBRAddr, pref Preference) Host {
 	ipv4, port4 := topoBRAddrToIPv4AndPort(topoBRAddr)
 	ipv6, port6 := topoBRAddrToIPv6AndPort(topoBRAddr)
-	return buildHostInfo(ipv4, ipv6, port4, port6)
+	return buildHostInfo(ipv4, ipv6, port4, port6, pref)
 }
 
+// Host returns the address of the host. The IPv4 address is used if both
+// addresses are set. An IPv4-mapped IPv6 address is returned as IPv4 address,
+// as the host is only reachable over IPv4.
 func (h *Host) Host() addr.HostAddr {
 	if len(h.Addrs.IPv4) > 0 {
 		return addr.HostIPv4(h.Addrs.IPv4)
 	}
 	if len(h.Addrs.IPv6) > 0 {
-		return addr.HostIPv6(h.Addrs.IPv6)
+		return addr.HostFromIP(h.Addrs.IPv6)
 	}
 	return nil
 }
@@ -127,7 +214,13 @@ func topoBRAddrToIPv6AndPort(topoBRAddr topology.TopoBRAddr) (net.IP, uint16) {
 	return nil, 0
 }
 
-func buildHostInfo(ipv4, ipv6 net.IP, port4, port6 uint16) Host {
+func buildHostInfo(ipv4, ipv6 net.IP, port4, port6 uint16, pref Preference) Host {
+	switch {
+	case pref == PreferIPv4 && ipv4 != nil:
+		return Host{Addrs: Addrs{IPv4: ipv4}, Port: port4}
+	case pref == PreferIPv6 && ipv6 != nil:
+		return Host{Addrs: Addrs{IPv6: ipv6}, Port: port6}
+	}
 	if port4 != 0 && port6 != 0 && port4 != port6 {
 		// NOTE: https://github.com/scionproto/scion/issues/1842 will change
 		// the behavior of this.
@@ -0,0 +1,97 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostinfo
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/topology"
+)
+
+func TestFromTopoBRAddrWithPreference(t *testing.T) {
+	ipv4, ipv6 := net.IP{10, 1, 0, 1}, net.ParseIP("2001:db8::1")
+	overBind := func(ip net.IP, port uint16) *topology.OverBindAddr {
+		ov, err := overlay.NewOverlayAddr(addr.HostFromIP(ip), addr.NewL4UDPInfo(port))
+		require.NoError(t, err)
+		return &topology.OverBindAddr{PublicOverlay: ov}
+	}
+	dualStack := topology.TopoBRAddr{
+		IPv4:    overBind(ipv4, 30041),
+		IPv6:    overBind(ipv6, 30042),
+		Overlay: overlay.UDPIPv46,
+	}
+	ipv4Only := topology.TopoBRAddr{
+		IPv4:    overBind(ipv4, 30041),
+		Overlay: overlay.UDPIPv4,
+	}
+	tests := map[string]struct {
+		Addr     topology.TopoBRAddr
+		Pref     Preference
+		Expected Host
+	}{
+		"any conveys both": {
+			Addr:     dualStack,
+			Pref:     PreferAny,
+			Expected: Host{Addrs: Addrs{IPv4: ipv4, IPv6: ipv6}, Port: 30041},
+		},
+		"ipv4 preferred": {
+			Addr:     dualStack,
+			Pref:     PreferIPv4,
+			Expected: Host{Addrs: Addrs{IPv4: ipv4}, Port: 30041},
+		},
+		"ipv6 preferred": {
+			Addr:     dualStack,
+			Pref:     PreferIPv6,
+			Expected: Host{Addrs: Addrs{IPv6: ipv6}, Port: 30042},
+		},
+		"ipv6 preferred but not available": {
+			Addr:     ipv4Only,
+			Pref:     PreferIPv6,
+			Expected: Host{Addrs: Addrs{IPv4: ipv4}, Port: 30041},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.Expected, FromTopoBRAddrWithPreference(test.Addr, test.Pref))
+		})
+	}
+}
+
+func TestHostMappedIPv6(t *testing.T) {
+	h := Host{Addrs: Addrs{IPv6: net.ParseIP("::ffff:10.1.0.1")}, Port: 30041}
+	assert.Equal(t, addr.HostIPv4(net.IP{10, 1, 0, 1}), h.Host())
+	ov, err := h.Overlay()
+	require.NoError(t, err)
+	assert.Equal(t, overlay.UDPIPv4, ov.Type())
+}
+
+func TestParsePreference(t *testing.T) {
+	for _, pref := range []Preference{PreferAny, PreferIPv4, PreferIPv6} {
+		parsed, err := ParsePreference(pref.String())
+		require.NoError(t, err)
+		assert.Equal(t, pref, parsed)
+	}
+	pref, err := ParsePreference("")
+	require.NoError(t, err)
+	assert.Equal(t, PreferAny, pref)
+	_, err = ParsePreference("ipx")
+	assert.Error(t, err)
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"runtime/debug"
 	"time"
 
 	"github.com/scionproto/scion/go/lib/addr"
@@ -109,6 +110,9 @@ var (
 	// responseWriterKey is a context key. It can be used in SCION infra
 	// request handlers to reply to a remote request.
 	responseWriterContextKey = &contextKey{"response-writer"}
+	// authPeerContextKey is a context key. It stores the ISD-AS of the
+	// authenticated requester.
+	authPeerContextKey = &contextKey{"authenticated-peer"}
 )
 
 type contextKey struct {
@@ -213,6 +217,17 @@ func (mt MessageType) String() string {
 	}
 }
 
+// MessageTypeFromString returns the message type whose String representation
+// is s.
+func MessageTypeFromString(s string) (MessageType, error) {
+	for mt := None; mt <= HPCfgReply; mt++ {
+		if mt.String() == s {
+			return mt, nil
+		}
+	}
+	return None, common.NewBasicError("Unknown message type", nil, "type", s)
+}
+
 // MetricLabel returns the label for metrics for a given message type.
 // The postfix for requests is always "req" and for replies and push messages it is always "push".
 func (mt MessageType) MetricLabel() string {
@@ -311,6 +326,41 @@ func NewResourceAwareHandler(handler Handler, resources ...ResourceHealth) Handl
 	})
 }
 
+// NewRecoveringHandler creates a decorated handler that recovers from panics
+// in the underlying handler. The panic is logged together with the stack
+// trace, counted in the handler panic metric, and the requester receives an
+// internal error ack. msgType is used to label the metric.
+func NewRecoveringHandler(msgType MessageType, handler Handler) Handler {
+	initPanicMetrics()
+	return HandlerFunc(func(r *Request) (result *HandlerResult) {
+		defer func() {
+			msg := recover()
+			if msg == nil {
+				return
+			}
+			handlerPanicsTotal.WithLabelValues(msgType.MetricLabel()).Inc()
+			ctx := r.Context()
+			logger := log.FromCtx(ctx)
+			logger.Error("Handler panicked", "type", msgType, "msg", msg,
+				"stack", string(debug.Stack()))
+			result = MetricsErrInternal
+			rwriter, ok := ResponseWriterFromContext(ctx)
+			if !ok {
+				logger.Error("No response writer found")
+				return
+			}
+			err := rwriter.SendAckReply(ctx, &ack.Ack{
+				Err:     proto.Ack_ErrCode_reject,
+				ErrDesc: "internal error",
+			})
+			if err != nil {
+				logger.Error("Unable to reply after panic", "err", err)
+			}
+		}()
+		return handler.Handle(r)
+	})
+}
+
 type Messenger interface {
 	SendAck(ctx context.Context, msg *ack.Ack, a net.Addr, id uint64) error
 	// GetTRC sends a cert_mgmt.TRCReq request to address a, blocks until it receives a
@@ -384,6 +434,19 @@ func ResponseWriterFromContext(ctx context.Context) (ResponseWriter, bool) {
 	return rw, ok
 }
 
+// NewContextWithAuthenticatedPeer returns a copy of ctx that carries the
+// ISD-AS of the authenticated requester.
+func NewContextWithAuthenticatedPeer(ctx context.Context, ia addr.IA) context.Context {
+	return context.WithValue(ctx, authPeerContextKey, ia)
+}
+
+// AuthenticatedPeerFromContext returns the ISD-AS of the authenticated
+// requester. The boolean is false if the request was not authenticated.
+func AuthenticatedPeerFromContext(ctx context.Context) (addr.IA, bool) {
+	ia, ok := ctx.Value(authPeerContextKey).(addr.IA)
+	return ia, ok
+}
+
 var _ error = (*Error)(nil)
 
 type Error struct {
@@ -61,3 +61,30 @@ func TestResourceHealth(t *testing.T) {
 		rHandler.Handle(req)
 	})
 }
+
+func TestRecoveringHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	Convey("Panicking handler results in internal error replied", t, func() {
+		handler := infra.HandlerFunc(func(r *infra.Request) *infra.HandlerResult {
+			panic("bad request")
+		})
+		rHandler := infra.NewRecoveringHandler(infra.ChainRequest, handler)
+		rwMock := mock_infra.NewMockResponseWriter(ctrl)
+		ctx := infra.NewContextWithResponseWriter(context.Background(), rwMock)
+		rwMock.EXPECT().SendAckReply(gomock.Eq(ctx), gomock.Eq(&ack.Ack{
+			Err:     proto.Ack_ErrCode_reject,
+			ErrDesc: "internal error",
+		}))
+		req := infra.NewRequest(ctx, nil, nil, nil, 1)
+		So(rHandler.Handle(req), ShouldEqual, infra.MetricsErrInternal)
+	})
+	Convey("Result of non-panicking handler is passed through", t, func() {
+		handler := infra.HandlerFunc(func(r *infra.Request) *infra.HandlerResult {
+			return infra.MetricsResultOk
+		})
+		rHandler := infra.NewRecoveringHandler(infra.ChainRequest, handler)
+		req := infra.NewRequest(context.Background(), nil, nil, nil, 1)
+		So(rHandler.Handle(req), ShouldEqual, infra.MetricsResultOk)
+	})
+}
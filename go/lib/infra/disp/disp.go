@@ -16,7 +16,8 @@
 // protocols.
 //
 // Supported message exchanges are Request (send a request message, block until
-// a reply message with the same key is received), Notify (send a reliable
+// a reply message with the same key is received), RequestStream (like Request,
+// but collects multiple reply messages with the same key), Notify (send a reliable
 // notification, i.e., one that is either sent via a lower-level reliable
 // transport or waits for an ACK on an unreliable transport), and
 // NotifyUnreliable (send a message, return immediately).
@@ -53,6 +54,9 @@ import (
 
 const (
 	maxReadEvents = 1 << 8
+	// maxStreamReplies is the number of replies to a streamed request that
+	// are buffered before further replies are dropped.
+	maxStreamReplies = 1 << 6
 )
 
 type Dispatcher struct {
@@ -129,6 +133,47 @@ func (d *Dispatcher) Request(ctx context.Context, msg proto.Cerealizable,
 	return reply, nil
 }
 
+// RequestStream sends msg to address, and returns all replies with the same
+// key. After each reply, more is called with all replies received so far;
+// RequestStream returns once more returns false. This method always blocks
+// while waiting for the responses.
+//
+// Replies that arrive faster than they can be processed are buffered; if
+// the buffer is full, further replies are dropped and the request
+// eventually times out.
+func (d *Dispatcher) RequestStream(ctx context.Context, msg proto.Cerealizable,
+	address net.Addr,
+	more func([]proto.Cerealizable) bool) ([]proto.Cerealizable, error) {
+
+	if err := d.waitTable.addStreamRequest(msg, maxStreamReplies); err != nil {
+		return nil, common.NewBasicError(infra.StrInternalError, err,
+			"op", "waitTable.AddStreamRequest")
+	}
+	// Delete request entry when we exit this context
+	defer d.waitTable.cancelRequest(msg)
+
+	b, err := d.adapter.MsgToRaw(msg)
+	if err != nil {
+		return nil, common.NewBasicError(infra.StrAdapterError, err, "op", "MsgToRaw")
+	}
+	if _, err := d.conn.WriteTo(b, address); err != nil {
+		return nil, common.NewBasicError(infra.StrTransportError, err, "op", "WriteTo")
+	}
+
+	var replies []proto.Cerealizable
+	for {
+		reply, err := d.waitTable.waitForReply(ctx, msg)
+		if err != nil {
+			return nil, common.NewBasicError(infra.StrInternalError, err,
+				"op", "waitTable.WaitForReply", "received", len(replies))
+		}
+		replies = append(replies, reply)
+		if !more(replies) {
+			return replies, nil
+		}
+	}
+}
+
 // Notify sends msg to address in a reliable way (i.e., either via a
 // lower-level reliable transport or by waiting for an ACK on an unreliable
 // transport).
@@ -156,7 +201,8 @@ func (d *Dispatcher) NotifyUnreliable(ctx context.Context, msg proto.Cerealizabl
 	return nil
 }
 
-// RecvFrom returns the next non-reply message.
+// RecvFrom returns the next non-reply message. Once the transport was shut
+// down, RecvFrom returns the messages that were read before and then fails.
 func (d *Dispatcher) RecvFrom(ctx context.Context) (proto.Cerealizable, net.Addr, error) {
 	select {
 	case event := <-d.readEvents:
@@ -167,6 +213,14 @@ func (d *Dispatcher) RecvFrom(ctx context.Context) (proto.Cerealizable, net.Addr
 	case <-d.closedChan:
 		// Some other goroutine closed the dispatcher
 		return nil, nil, common.NewBasicError(infra.StrClosedError, nil)
+	case <-d.stoppedChan:
+		// The transport was shut down; drain the remaining messages
+		select {
+		case event := <-d.readEvents:
+			return event.msg, event.address, nil
+		default:
+			return nil, nil, common.NewBasicError(infra.StrClosedError, nil)
+		}
 	}
 }
 
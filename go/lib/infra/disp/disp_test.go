@@ -26,6 +26,7 @@ import (
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/xtest"
 	"github.com/scionproto/scion/go/lib/xtest/p2p"
+	"github.com/scionproto/scion/go/proto"
 )
 
 const (
@@ -62,6 +63,47 @@ func TestRequestReply(t *testing.T) {
 	})
 }
 
+func TestRequestStream(t *testing.T) {
+	Convey("Setup", t, func() {
+		dispA, dispB, request, reply := Setup()
+		reply2 := &customObject{8, "reply2"}
+		Convey("Request and stream reply (Parallel)", xtest.Parallel(func(sc *xtest.SC) {
+			ctx, cancelF := context.WithTimeout(context.Background(), testCtxTimeout)
+			defer cancelF()
+			more := func(replies []proto.Cerealizable) bool { return len(replies) < 2 }
+			recvReplies, err := dispA.RequestStream(ctx, request, nil, more)
+			sc.SoMsg("a request err", err, ShouldBeNil)
+			sc.SoMsg("a request replies", recvReplies, ShouldResemble,
+				[]proto.Cerealizable{reply, reply2})
+		}, func(sc *xtest.SC) {
+			ctx, cancelF := context.WithTimeout(context.Background(), testCtxTimeout)
+			defer cancelF()
+			recvRequest, _, err := dispB.RecvFrom(ctx)
+			sc.SoMsg("b recv err", err, ShouldBeNil)
+			sc.SoMsg("b recv msg", recvRequest, ShouldResemble, request)
+			err = dispB.Notify(ctx, reply, nil)
+			sc.SoMsg("b notify #1 err", err, ShouldBeNil)
+			err = dispB.Notify(ctx, reply2, nil)
+			sc.SoMsg("b notify #2 err", err, ShouldBeNil)
+		}))
+		Convey("Incomplete stream times out (Parallel)", xtest.Parallel(func(sc *xtest.SC) {
+			ctx, cancelF := context.WithTimeout(context.Background(), testCtxTimeout)
+			defer cancelF()
+			more := func(replies []proto.Cerealizable) bool { return len(replies) < 2 }
+			recvReplies, err := dispA.RequestStream(ctx, request, nil, more)
+			sc.SoMsg("a request err timeout", common.IsTimeoutErr(err), ShouldBeTrue)
+			sc.SoMsg("a request replies", recvReplies, ShouldBeNil)
+		}, func(sc *xtest.SC) {
+			ctx, cancelF := context.WithTimeout(context.Background(), testCtxTimeout)
+			defer cancelF()
+			_, _, err := dispB.RecvFrom(ctx)
+			sc.SoMsg("b recv err", err, ShouldBeNil)
+			err = dispB.Notify(ctx, reply, nil)
+			sc.SoMsg("b notify err", err, ShouldBeNil)
+		}))
+	})
+}
+
 func TestRequestNoReceiver(t *testing.T) {
 	Convey("Setup", t, func() {
 		dispA, _, request, _ := Setup()
@@ -39,12 +39,18 @@ func newWaitTable(keyF func(proto.Cerealizable) string) *waitTable {
 }
 
 func (wt *waitTable) addRequest(object proto.Cerealizable) error {
+	return wt.addStreamRequest(object, 1)
+}
+
+// addStreamRequest adds a request that can be answered by multiple replies.
+// Up to size replies are buffered until the waiting goroutine picks them up.
+func (wt *waitTable) addStreamRequest(object proto.Cerealizable, size int) error {
 	select {
 	case <-wt.destroyChan:
 		return serrors.New("Table destroyed")
 	default:
 	}
-	replyChannel := make(chan proto.Cerealizable, 1)
+	replyChannel := make(chan proto.Cerealizable, size)
 	_, loaded := wt.replyMap.LoadOrStore(wt.keyF(object), replyChannel)
 	if loaded {
 		return common.NewBasicError("Duplicate key", nil, "key", wt.keyF(object))
@@ -18,7 +18,10 @@ package infraenv
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
 	"net"
 	"time"
 
@@ -52,6 +55,15 @@ type QUIC struct {
 	CertFile string
 	// KeyFile is the private key to use for QUIC authentication.
 	KeyFile string
+	// ClientCAFile, if set, contains the PEM encoded certificates used to
+	// verify the client certificates of incoming QUIC connections. Clients
+	// that do not present a valid certificate are rejected.
+	ClientCAFile string
+	// AuthTypes lists the names of the message types whose requesters must
+	// authenticate with their QUIC client certificate, see
+	// messenger.TLSAuthenticator. It requires ClientCAFile, and is ignored if
+	// NetworkConfig.Auth is set.
+	AuthTypes []string
 }
 
 // NetworkConfig describes the networking configuration of a SCION
@@ -85,6 +97,12 @@ type NetworkConfig struct {
 	// SVCRouter is used to discover the overlay addresses of intra-AS SVC
 	// servers.
 	SVCRouter messenger.LocalSVCRouter
+	// CircuitBreaker, if set, enables per-destination circuit breakers on the
+	// messenger.
+	CircuitBreaker *messenger.BreakerConfig
+	// Auth, if set, enables the authentication of incoming requests on the
+	// messenger.
+	Auth *messenger.AuthConfig
 }
 
 // Messenger initializes a SCION control-plane RPC endpoint using the specified
@@ -106,10 +124,17 @@ func (nc *NetworkConfig) Messenger() (infra.Messenger, error) {
 	if err != nil {
 		return nil, err
 	}
+	auth := nc.Auth
+	if auth == nil && len(nc.QUIC.AuthTypes) > 0 {
+		if auth, err = nc.tlsAuthConfig(); err != nil {
+			return nil, err
+		}
+	}
 
 	msgerCfg := &messenger.Config{
 		IA:              nc.IA,
 		AddressRewriter: nc.AddressRewriter(nil),
+		Auth:            auth,
 	}
 	msgerCfg.Dispatcher = disp.New(
 		conn,
@@ -123,7 +148,10 @@ func (nc *NetworkConfig) Messenger() (infra.Messenger, error) {
 			return nil, err
 		}
 	}
-	msger := messenger.NewMessengerWithMetrics(msgerCfg)
+	var msger infra.Messenger = messenger.NewMessengerWithMetrics(msgerCfg)
+	if nc.CircuitBreaker != nil {
+		msger = messenger.NewMessengerWithBreaker(msger, *nc.CircuitBreaker)
+	}
 	nc.TrustStore.SetMessenger(msger)
 	return msger, nil
 
@@ -146,21 +174,23 @@ func (nc *NetworkConfig) AddressRewriter(
 		}
 	}
 
+	resolver := &svc.Resolver{
+		LocalIA:     nc.IA,
+		ConnFactory: connFactory,
+		Machine:     buildLocalMachine(nc.Bind, nc.Public),
+		// Legacy control payloads have a 4-byte length prefix. A
+		// 0-value for the prefix is invalid, so SVC resolution-aware
+		// servers can use this to detect that the client is attempting
+		// SVC resolution. Legacy SVC traffic sent by legacy clients
+		// will have a non-0 value, and thus not trigger resolution
+		// logic.
+		Payload: resolutionRequestPayload,
+	}
 	return &messenger.AddressRewriter{
-		Router:    router,
-		SVCRouter: nc.SVCRouter,
-		Resolver: &svc.Resolver{
-			LocalIA:     nc.IA,
-			ConnFactory: connFactory,
-			Machine:     buildLocalMachine(nc.Bind, nc.Public),
-			// Legacy control payloads have a 4-byte length prefix. A
-			// 0-value for the prefix is invalid, so SVC resolution-aware
-			// servers can use this to detect that the client is attempting
-			// SVC resolution. Legacy SVC traffic sent by legacy clients
-			// will have a non-0 value, and thus not trigger resolution
-			// logic.
-			Payload: resolutionRequestPayload,
-		},
+		Router:                router,
+		SVCRouter:             nc.SVCRouter,
+		Resolver:              resolver,
+		InstanceResolver:      resolver,
 		SVCResolutionFraction: nc.SVCResolutionFraction,
 	}
 }
@@ -184,7 +214,7 @@ func (nc *NetworkConfig) initUDPSocket(quicAddress string) (net.PacketConn, erro
 	if nc.ReconnectToDispatcher {
 		dispatcherService = reconnect.NewDispatcherService(dispatcherService)
 	}
-	packetDispatcher := svc.NewResolverPacketDispatcher(
+	resolverDispatcher := svc.NewResolverPacketDispatcher(
 		&snet.DefaultPacketDispatcherService{
 			Dispatcher: dispatcherService,
 		},
@@ -195,7 +225,10 @@ func (nc *NetworkConfig) initUDPSocket(quicAddress string) (net.PacketConn, erro
 			ExpectedPayload: resolutionRequestPayload,
 		},
 	)
-	network, err := snet.NewCustomNetwork(nc.IA, "", packetDispatcher)
+	// Answer multicast resolution requests, such that clients can discover
+	// all instances of the service. Other packets are forwarded.
+	resolverDispatcher.HandleMulticast = true
+	network, err := snet.NewCustomNetwork(nc.IA, "", resolverDispatcher)
 	if err != nil {
 		return nil, common.NewBasicError("Unable to create network", err)
 	}
@@ -240,17 +273,51 @@ func (nc *NetworkConfig) initQUICSocket() (net.PacketConn, error) {
 	return conn, nil
 }
 
+// tlsAuthConfig returns the configuration to authenticate the requesters of
+// the message types in nc.QUIC.AuthTypes with their QUIC client certificates.
+func (nc *NetworkConfig) tlsAuthConfig() (*messenger.AuthConfig, error) {
+	if nc.QUIC.Address == "" || nc.QUIC.ClientCAFile == "" {
+		return nil, common.NewBasicError("Authentication requires QUIC with client CAs", nil)
+	}
+	types := make([]infra.MessageType, 0, len(nc.QUIC.AuthTypes))
+	for _, name := range nc.QUIC.AuthTypes {
+		msgType, err := infra.MessageTypeFromString(name)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, msgType)
+	}
+	return &messenger.AuthConfig{
+		Authenticator: messenger.TLSAuthenticator{},
+		Types:         types,
+	}, nil
+}
+
 func (nc *NetworkConfig) buildQUICConfig(conn net.PacketConn) (*messenger.QUICConfig, error) {
 	cert, err := tls.LoadX509KeyPair(nc.QUIC.CertFile, nc.QUIC.KeyFile)
 	if err != nil {
 		return nil, err
 	}
+	tlsConfig := &tls.Config{
+		Certificates:       []tls.Certificate{cert},
+		InsecureSkipVerify: true,
+	}
+	if nc.QUIC.ClientCAFile != "" {
+		raw, err := ioutil.ReadFile(nc.QUIC.ClientCAFile)
+		if err != nil {
+			return nil, common.NewBasicError("Unable to read client CA file", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(raw) {
+			return nil, common.NewBasicError("No certificates in client CA file", nil,
+				"file", nc.QUIC.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
 	return &messenger.QUICConfig{
-		Conn: conn,
-		TLSConfig: &tls.Config{
-			Certificates:       []tls.Certificate{cert},
-			InsecureSkipVerify: true,
-		},
+		Conn:      conn,
+		TLSConfig: tlsConfig,
 	}, nil
 }
 
@@ -306,15 +373,17 @@ func NewRouter(localIA addr.IA, sd env.SciondClient) (snet.Router, error) {
 	for {
 		sciondConn, err := sciond.NewService(sd.Path, true).Connect()
 		if err == nil {
+			resolver := pathmgr.New(
+				sciondConn,
+				pathmgr.Timers{
+					NormalRefire: time.Minute,
+					ErrorRefire:  3 * time.Second,
+				},
+			)
+			prewarm(resolver, localIA, sd.PrewarmDsts)
 			router = &snet.BaseRouter{
-				IA: localIA,
-				PathResolver: pathmgr.New(
-					sciondConn,
-					pathmgr.Timers{
-						NormalRefire: time.Minute,
-						ErrorRefire:  3 * time.Second,
-					},
-				),
+				IA:           localIA,
+				PathResolver: resolver,
 			}
 			break
 		}
@@ -327,6 +396,19 @@ func NewRouter(localIA addr.IA, sd env.SciondClient) (snet.Router, error) {
 	return router, nil
 }
 
+// prewarm fetches the paths to dsts in the background.
+func prewarm(resolver pathmgr.Resolver, localIA addr.IA, dsts []addr.IA) {
+	if len(dsts) == 0 {
+		return
+	}
+	go func() {
+		defer log.LogPanicAndExit()
+		ctx, cancelF := context.WithTimeout(context.Background(), pathmgr.DefaultQueryTimeout)
+		defer cancelF()
+		resolver.Prewarm(ctx, localIA, dsts)
+	}()
+}
+
 func InitInfraEnvironment(topologyPath string) {
 	InitInfraEnvironmentFunc(topologyPath, nil)
 }
@@ -355,3 +437,16 @@ func (ignoreSCMP) Handle(pkt *snet.SCIONPacket) error {
 	// Always reattempt reads from the socket.
 	return nil
 }
+
+// BreakerConfig returns the messenger circuit breaker configuration for
+// NetworkConfig.CircuitBreaker. If the circuit breakers are not enabled in cfg,
+// nil is returned.
+func BreakerConfig(cfg env.CircuitBreaker) *messenger.BreakerConfig {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &messenger.BreakerConfig{
+		FailureThreshold: cfg.FailureThreshold,
+		OpenTimeout:      cfg.OpenTimeout.Duration,
+	}
+}
@@ -62,6 +62,10 @@ type AddressRewriter struct {
 	// disabled, and data packets are never sent to SVC destinations unless the
 	// resolution step is successful.
 	SVCResolutionFraction float64
+	// InstanceResolver, if set, resolves all instances of SVC destinations in
+	// remote ASes. Requests to such destinations are then retried over the
+	// QUIC servers of all instances, see RequestInstances.
+	InstanceResolver svc.InstanceResolver
 }
 
 // RedirectToQUIC takes an address and adds a path (if one does not already
@@ -197,6 +201,91 @@ func (r AddressRewriter) resolveIfSVC(ctx context.Context, p snet.Path,
 	return reply.ReturnPath, appAddr, true, nil
 }
 
+// RequestInstances performs a request to the SVC destination a in a remote
+// AS, by calling f with the QUIC addresses of the service instances in turn,
+// until the exchange with one of them succeeds (see svc.Requester). The
+// instances are resolved like in RedirectToQUIC, respecting
+// SVCResolutionFraction.
+//
+// The returned boolean value is false if the request was not attempted,
+// because a is not a remote SVC destination, no InstanceResolver is
+// configured, or no QUIC instance was found. The caller is expected to fall
+// back to RedirectToQUIC in that case.
+func (r AddressRewriter) RequestInstances(ctx context.Context, a net.Addr,
+	f func(ctx context.Context, a net.Addr) error) (bool, error) {
+
+	if r.InstanceResolver == nil || r.SVCResolutionFraction <= 0.0 || a == nil {
+		return false, nil
+	}
+	fullAddress, err := r.buildFullAddress(ctx, a)
+	if err != nil {
+		return false, nil
+	}
+	svcAddress, ok := fullAddress.Host.L3.(addr.HostSVC)
+	if !ok || fullAddress.Path == nil {
+		// Local SVC destinations are resolved via the topology.
+		return false, nil
+	}
+	path, err := fullAddress.GetPath()
+	if err != nil {
+		return false, nil
+	}
+	instances := r.lookupQUICInstances(ctx, path, svcAddress)
+	if len(instances) == 0 {
+		return false, nil
+	}
+	requester := svc.Requester{Resolver: instances}
+	_, err = requester.Request(ctx, path, svcAddress,
+		func(ctx context.Context, instance *svc.Reply) error {
+			appAddr, err := parseReply(instance)
+			if err != nil {
+				return err
+			}
+			instanceAddress := fullAddress.Copy()
+			instanceAddress.Host = appAddr
+			if instance.ReturnPath != nil {
+				instanceAddress.Path = instance.ReturnPath.Path()
+			}
+			return f(ctx, instanceAddress)
+		},
+	)
+	return true, err
+}
+
+// lookupQUICInstances resolves the instances of the service that run a QUIC
+// server. Resolution failures are logged, and result in no instances.
+func (r AddressRewriter) lookupQUICInstances(ctx context.Context, p snet.Path,
+	svcAddress addr.HostSVC) resolvedInstances {
+
+	if r.SVCResolutionFraction < 1.0 {
+		var cancelF context.CancelFunc
+		ctx, cancelF = r.resolutionCtx(ctx)
+		defer cancelF()
+	}
+	replies, err := r.InstanceResolver.LookupSVCInstances(ctx, p, svcAddress)
+	if err != nil {
+		log.FromCtx(ctx).Trace("SVC instance resolution failed", "svc", svcAddress, "err", err)
+		return nil
+	}
+	var instances resolvedInstances
+	for _, reply := range replies {
+		if _, ok := reply.Transports[svc.QUIC]; ok {
+			instances = append(instances, reply)
+		}
+	}
+	return instances
+}
+
+// resolvedInstances is an svc.InstanceResolver that returns already resolved
+// instances.
+type resolvedInstances []*svc.Reply
+
+func (i resolvedInstances) LookupSVCInstances(context.Context, snet.Path,
+	addr.HostSVC) ([]*svc.Reply, error) {
+
+	return i, nil
+}
+
 func (r AddressRewriter) resolutionCtx(ctx context.Context) (context.Context, context.CancelFunc) {
 	deadline, ok := ctx.Deadline()
 	if !ok {
@@ -26,6 +26,7 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 
 	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/infra/messenger/mock_messenger"
 	"github.com/scionproto/scion/go/lib/overlay"
 	"github.com/scionproto/scion/go/lib/snet"
@@ -348,6 +349,70 @@ func TestResolveIfSVC(t *testing.T) {
 	})
 }
 
+func TestRequestInstances(t *testing.T) {
+	Convey("Given a remote SVC destination with two QUIC instances", t, func() {
+		raw := make(common.RawBytes, 2*common.LineLen)
+		(&spath.InfoField{ConsDir: true, Hops: 1}).Write(raw)
+		(&spath.HopField{ConsEgress: 1}).Write(raw[common.LineLen:])
+		svcAddr := &snet.Addr{
+			IA:   xtest.MustParseIA("1-ff00:0:1"),
+			Host: &addr.AppAddr{L3: addr.SvcPS, L4: addr.NewL4UDPInfo(0)},
+			Path: spath.New(raw),
+		}
+		instances := resolvedInstances{
+			{Transports: map[svc.Transport]string{svc.QUIC: "10.0.0.1:30000"}},
+			{Transports: map[svc.Transport]string{svc.QUIC: "10.0.0.2:30000"}},
+		}
+		rewriter := AddressRewriter{
+			InstanceResolver:      instances,
+			SVCResolutionFraction: 1.0,
+		}
+		ctx, cancelF := context.WithTimeout(context.Background(), time.Second)
+		defer cancelF()
+		var tried []string
+		f := func(ctx context.Context, a net.Addr) error {
+			host := a.(*snet.Addr).Host.L3.String()
+			tried = append(tried, host)
+			if host == "10.0.0.1" {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			return nil
+		}
+		Convey("The next instance is tried if one times out", func() {
+			ok, err := rewriter.RequestInstances(ctx, svcAddr, f)
+			SoMsg("ok", ok, ShouldBeTrue)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("tried", tried, ShouldResemble, []string{"10.0.0.1", "10.0.0.2"})
+		})
+		Convey("Unicast destinations are not handled", func() {
+			a := svcAddr.Copy()
+			a.Host = &addr.AppAddr{
+				L3: addr.HostFromIP(net.IP{10, 0, 0, 3}),
+				L4: addr.NewL4UDPInfo(1),
+			}
+			ok, err := rewriter.RequestInstances(ctx, a, f)
+			SoMsg("ok", ok, ShouldBeFalse)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("tried", tried, ShouldBeEmpty)
+		})
+		Convey("Without instance resolver, the request is not handled", func() {
+			rewriter.InstanceResolver = nil
+			ok, err := rewriter.RequestInstances(ctx, svcAddr, f)
+			SoMsg("ok", ok, ShouldBeFalse)
+			SoMsg("err", err, ShouldBeNil)
+		})
+		Convey("Without QUIC instances, the request is not handled", func() {
+			rewriter.InstanceResolver = resolvedInstances{
+				{Transports: map[svc.Transport]string{svc.UDP: "10.0.0.1:30000"}},
+			}
+			ok, err := rewriter.RequestInstances(ctx, svcAddr, f)
+			SoMsg("ok", ok, ShouldBeFalse)
+			SoMsg("err", err, ShouldBeNil)
+		})
+	})
+}
+
 func TestParseReply(t *testing.T) {
 	testCases := []struct {
 		Description     string
@@ -0,0 +1,193 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package messenger
+
+import (
+	"context"
+	"crypto/x509"
+	"net"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/ctrl"
+	"github.com/scionproto/scion/go/lib/infra"
+	"github.com/scionproto/scion/go/lib/prom"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/proto"
+)
+
+const (
+	authFailureUnauthenticated = "unauthenticated"
+	authFailureUnauthorized    = "unauthorized"
+)
+
+var (
+	// ErrUnauthenticated indicates that the requester could not be
+	// authenticated.
+	ErrUnauthenticated = serrors.New("requester not authenticated")
+	// ErrUnauthorized indicates that the authenticated requester is not
+	// allowed to issue the request.
+	ErrUnauthorized = serrors.New("requester not authorized")
+)
+
+// AuthInfo contains the information about an incoming request that is
+// available for authentication.
+type AuthInfo struct {
+	// Address is the source address of the request.
+	Address net.Addr
+	// PeerCertificates contains the certificates presented by the requester
+	// during the TLS handshake. It is always empty for requests received
+	// over UDP.
+	PeerCertificates []*x509.Certificate
+	// SignedPld is the received control payload. Authenticators that rely
+	// on symmetric keys, e.g., DRKey, can inspect its signature.
+	SignedPld *ctrl.SignedPld
+}
+
+// Authenticator authenticates the requester of an incoming control-plane
+// request.
+type Authenticator interface {
+	// Authenticate returns the ISD-AS of the requester. An error wrapping
+	// ErrUnauthenticated is returned if the requester cannot be
+	// authenticated.
+	Authenticate(ctx context.Context, info AuthInfo) (addr.IA, error)
+}
+
+// AuthConfig configures the authentication of incoming requests.
+type AuthConfig struct {
+	// Authenticator authenticates the requesters. It must not be nil.
+	Authenticator Authenticator
+	// Types lists the message types that require authentication. If it is
+	// empty, all message types require authentication.
+	Types []infra.MessageType
+	// Authorize, if set, decides whether the authenticated requester is
+	// allowed to issue a request of the given type. If it is nil, all
+	// authenticated requesters are allowed.
+	Authorize func(msgType infra.MessageType, peer addr.IA) bool
+}
+
+var _ Authenticator = TLSAuthenticator{}
+
+// TLSAuthenticator authenticates requesters based on the client certificate
+// presented during the QUIC handshake. The ISD-AS of the requester is read
+// from the common name of the certificate subject and must match the ISD-AS
+// of the source address. Requests without a client certificate, e.g.,
+// requests received over UDP, are rejected.
+//
+// The certificate itself is verified by the TLS stack. The server TLS
+// configuration must thus set ClientAuth to tls.RequireAndVerifyClientCert
+// and ClientCAs to the trusted roots.
+type TLSAuthenticator struct{}
+
+// Authenticate returns the ISD-AS in the client certificate.
+func (TLSAuthenticator) Authenticate(_ context.Context, info AuthInfo) (addr.IA, error) {
+	if len(info.PeerCertificates) == 0 {
+		return addr.IA{}, serrors.WithCtx(ErrUnauthenticated, "reason", "no client certificate")
+	}
+	subject := info.PeerCertificates[0].Subject.CommonName
+	ia, err := addr.IAFromString(subject)
+	if err != nil {
+		return addr.IA{}, serrors.Wrap(ErrUnauthenticated, err, "subject", subject)
+	}
+	if src, ok := info.Address.(*snet.Addr); ok && !src.IA.Equal(ia) {
+		return addr.IA{}, serrors.WithCtx(ErrUnauthenticated, "reason", "ISD-AS mismatch",
+			"subject", ia, "src", src.IA)
+	}
+	return ia, nil
+}
+
+// authenticator applies the authentication configuration to incoming
+// requests. A nil authenticator accepts all requests.
+type authenticator struct {
+	authenticator Authenticator
+	types         map[infra.MessageType]struct{}
+	authorize     func(infra.MessageType, addr.IA) bool
+}
+
+func newAuthenticator(cfg *AuthConfig) *authenticator {
+	if cfg == nil {
+		return nil
+	}
+	initMetrics()
+	a := &authenticator{
+		authenticator: cfg.Authenticator,
+		authorize:     cfg.Authorize,
+	}
+	if len(cfg.Types) > 0 {
+		a.types = make(map[infra.MessageType]struct{}, len(cfg.Types))
+		for _, t := range cfg.Types {
+			a.types[t] = struct{}{}
+		}
+	}
+	return a
+}
+
+// authenticate checks that the requester is allowed to issue a request of
+// the given type. On success, the returned context carries the ISD-AS of the
+// authenticated requester, if authentication was required.
+func (a *authenticator) authenticate(ctx context.Context, msgType infra.MessageType,
+	info AuthInfo) (context.Context, error) {
+
+	if a == nil || !a.required(msgType) {
+		return ctx, nil
+	}
+	ia, err := a.authenticator.Authenticate(ctx, info)
+	if err != nil {
+		metricAuthFailure(msgType, metricPeerValue(info.Address), authFailureUnauthenticated)
+		return ctx, err
+	}
+	if a.authorize != nil && !a.authorize(msgType, ia) {
+		metricAuthFailure(msgType, ia.String(), authFailureUnauthorized)
+		return ctx, serrors.WithCtx(ErrUnauthorized, "peer", ia, "type", msgType)
+	}
+	return infra.NewContextWithAuthenticatedPeer(ctx, ia), nil
+}
+
+// sendAuthReject answers a rejected request with a reject ack, such that the
+// requester does not wait for a reply until it times out. Acks are not
+// answered.
+func sendAuthReject(ctx context.Context, msgType infra.MessageType) {
+	if msgType == infra.Ack {
+		return
+	}
+	if rw, ok := infra.ResponseWriterFromContext(ctx); ok {
+		SendAckHelper(ctx, rw)(proto.Ack_ErrCode_reject, AckRejectUnauthorized)
+	}
+}
+
+func (a *authenticator) required(msgType infra.MessageType) bool {
+	if a.types == nil {
+		return true
+	}
+	_, ok := a.types[msgType]
+	return ok
+}
+
+func metricPeerValue(peer net.Addr) string {
+	if sAddr, ok := peer.(*snet.Addr); ok {
+		return sAddr.IA.String()
+	}
+	return infra.PromSrcUnknown
+}
+
+func metricAuthFailure(msgType infra.MessageType, peer, reason string) {
+	authFailuresTotal.With(prometheus.Labels{
+		prom.LabelOperation: msgType.MetricLabel(),
+		labelPeer:           peer,
+		labelReason:         reason,
+	}).Inc()
+}
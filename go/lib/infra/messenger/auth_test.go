@@ -0,0 +1,179 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package messenger
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/ctrl/ack"
+	"github.com/scionproto/scion/go/lib/infra"
+	"github.com/scionproto/scion/go/lib/infra/mock_infra"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/proto"
+)
+
+func TestTLSAuthenticator(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	certFor := func(cn string) []*x509.Certificate {
+		return []*x509.Certificate{{Subject: pkix.Name{CommonName: cn}}}
+	}
+	tests := map[string]struct {
+		Info          AuthInfo
+		ExpectedIA    addr.IA
+		ExpectedError error
+	}{
+		"no certificate": {
+			Info:          AuthInfo{Address: &snet.Addr{IA: ia}},
+			ExpectedError: ErrUnauthenticated,
+		},
+		"invalid subject": {
+			Info: AuthInfo{
+				Address:          &snet.Addr{IA: ia},
+				PeerCertificates: certFor("cs1-ff00:0:110-1"),
+			},
+			ExpectedError: ErrUnauthenticated,
+		},
+		"ISD-AS mismatch": {
+			Info: AuthInfo{
+				Address:          &snet.Addr{IA: xtest.MustParseIA("1-ff00:0:111")},
+				PeerCertificates: certFor(ia.String()),
+			},
+			ExpectedError: ErrUnauthenticated,
+		},
+		"valid": {
+			Info: AuthInfo{
+				Address:          &snet.Addr{IA: ia},
+				PeerCertificates: certFor(ia.String()),
+			},
+			ExpectedIA: ia,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			peer, err := TLSAuthenticator{}.Authenticate(context.Background(), test.Info)
+			assert.Equal(t, test.ExpectedIA, peer)
+			if test.ExpectedError != nil {
+				assert.True(t, xerrors.Is(err, test.ExpectedError), "err: %v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+type staticAuthenticator struct {
+	ia  addr.IA
+	err error
+}
+
+func (a staticAuthenticator) Authenticate(context.Context, AuthInfo) (addr.IA, error) {
+	return a.ia, a.err
+}
+
+func TestAuthenticatorAuthenticate(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	tests := map[string]struct {
+		Config        *AuthConfig
+		MsgType       infra.MessageType
+		ExpectedPeer  bool
+		ExpectedError error
+	}{
+		"nil config accepts all": {
+			MsgType: infra.SegRequest,
+		},
+		"unauthenticated": {
+			Config: &AuthConfig{
+				Authenticator: staticAuthenticator{err: ErrUnauthenticated},
+			},
+			MsgType:       infra.SegRequest,
+			ExpectedError: ErrUnauthenticated,
+		},
+		"type without authentication": {
+			Config: &AuthConfig{
+				Authenticator: staticAuthenticator{err: ErrUnauthenticated},
+				Types:         []infra.MessageType{infra.SegReg},
+			},
+			MsgType: infra.SegRequest,
+		},
+		"unauthorized": {
+			Config: &AuthConfig{
+				Authenticator: staticAuthenticator{ia: ia},
+				Authorize: func(infra.MessageType, addr.IA) bool {
+					return false
+				},
+			},
+			MsgType:       infra.SegRequest,
+			ExpectedError: ErrUnauthorized,
+		},
+		"authorized": {
+			Config: &AuthConfig{
+				Authenticator: staticAuthenticator{ia: ia},
+				Types:         []infra.MessageType{infra.SegRequest},
+				Authorize: func(msgType infra.MessageType, peer addr.IA) bool {
+					return msgType == infra.SegRequest && peer.Equal(ia)
+				},
+			},
+			MsgType:      infra.SegRequest,
+			ExpectedPeer: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := newAuthenticator(test.Config)
+			ctx, err := a.authenticate(context.Background(), test.MsgType,
+				AuthInfo{Address: &snet.Addr{IA: ia}})
+			if test.ExpectedError != nil {
+				assert.True(t, xerrors.Is(err, test.ExpectedError), "err: %v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+			peer, ok := infra.AuthenticatedPeerFromContext(ctx)
+			assert.Equal(t, test.ExpectedPeer, ok)
+			if test.ExpectedPeer {
+				assert.Equal(t, ia, peer)
+			}
+		})
+	}
+}
+
+func TestSendAuthReject(t *testing.T) {
+	t.Run("request is rejected", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		rw := mock_infra.NewMockResponseWriter(ctrl)
+		rw.EXPECT().SendAckReply(gomock.Any(), &ack.Ack{
+			Err:     proto.Ack_ErrCode_reject,
+			ErrDesc: AckRejectUnauthorized,
+		})
+		ctx := infra.NewContextWithResponseWriter(context.Background(), rw)
+		sendAuthReject(ctx, infra.SegRequest)
+	})
+	t.Run("ack is not answered", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		rw := mock_infra.NewMockResponseWriter(ctrl)
+		ctx := infra.NewContextWithResponseWriter(context.Background(), rw)
+		sendAuthReject(ctx, infra.Ack)
+	})
+}
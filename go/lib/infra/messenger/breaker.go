@@ -0,0 +1,240 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package messenger
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/infra"
+	"github.com/scionproto/scion/go/lib/prom"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+const (
+	// DefaultBreakerFailureThreshold is the default number of consecutive
+	// failures after which a circuit is opened.
+	DefaultBreakerFailureThreshold = 5
+	// DefaultBreakerOpenTimeout is the default time a circuit stays open
+	// before a probe request is let through.
+	DefaultBreakerOpenTimeout = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned for requests that are rejected because the
+// circuit towards the destination is open.
+var ErrCircuitOpen = serrors.New("circuit breaker open")
+
+// BreakerConfig configures the circuit breakers of a MessengerWithBreaker.
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failed requests towards a
+	// destination after which the circuit is opened, i.e., further requests
+	// fail immediately.
+	FailureThreshold int
+	// OpenTimeout is the time a circuit stays open. Afterwards, the circuit
+	// is half-open: a single probe request is let through, which closes the
+	// circuit on success and opens it again on failure.
+	OpenTimeout time.Duration
+}
+
+// InitDefaults sets the default values for unset fields.
+func (cfg *BreakerConfig) InitDefaults() {
+	if cfg.FailureThreshold == 0 {
+		cfg.FailureThreshold = DefaultBreakerFailureThreshold
+	}
+	if cfg.OpenTimeout == 0 {
+		cfg.OpenTimeout = DefaultBreakerOpenTimeout
+	}
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerClosed:
+		return "closed"
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
+type breakerOutcome int
+
+const (
+	outcomeSuccess breakerOutcome = iota
+	outcomeFailure
+	// outcomeNeutral is used for requests that were aborted by the caller, and
+	// thus say nothing about the state of the destination.
+	outcomeNeutral
+)
+
+// classifyBreakerOutcome classifies the result of a request. Only timeouts
+// are counted as failures; other errors indicate that the destination is
+// reachable.
+func classifyBreakerOutcome(err error) breakerOutcome {
+	switch {
+	case err == nil:
+		return outcomeSuccess
+	case xerrors.Is(err, context.Canceled):
+		return outcomeNeutral
+	case common.IsTimeoutErr(err) || serrors.IsTimeout(err):
+		return outcomeFailure
+	default:
+		return outcomeSuccess
+	}
+}
+
+type breaker struct {
+	state    breakerState
+	failures int
+	openedAt time.Time
+	// probing is set while the probe request of a half-open circuit is in
+	// flight.
+	probing bool
+}
+
+// breakerSet keeps track of the circuit breakers of all destinations. Only
+// destinations with failures are tracked; the circuit of all others is
+// closed.
+type breakerSet struct {
+	cfg BreakerConfig
+	now func() time.Time
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+func newBreakerSet(cfg BreakerConfig) *breakerSet {
+	cfg.InitDefaults()
+	return &breakerSet{
+		cfg:      cfg,
+		now:      time.Now,
+		breakers: make(map[string]*breaker),
+	}
+}
+
+// do runs action if the circuit towards a is not open, and records its
+// outcome.
+func (s *breakerSet) do(a net.Addr, msgType infra.MessageType, action func() error) error {
+	key := breakerKey(a)
+	if !s.allow(key) {
+		breakerRejectionsTotal.With(prometheus.Labels{
+			prom.LabelOperation: msgType.MetricLabel(),
+		}).Inc()
+		return serrors.WithCtx(ErrCircuitOpen, "addr", a, "op", msgType)
+	}
+	err := action()
+	s.report(key, classifyBreakerOutcome(err))
+	return err
+}
+
+func (s *breakerSet) allow(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.breakers[key]
+	if !ok {
+		return true
+	}
+	switch b.state {
+	case breakerOpen:
+		if s.now().Sub(b.openedAt) < s.cfg.OpenTimeout {
+			return false
+		}
+		s.setState(b, breakerHalfOpen)
+		b.probing = true
+		return true
+	case breakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (s *breakerSet) report(key string, outcome breakerOutcome) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.breakers[key]
+	switch outcome {
+	case outcomeNeutral:
+		if ok && b.state == breakerHalfOpen {
+			b.probing = false
+		}
+	case outcomeSuccess:
+		if ok {
+			s.setState(b, breakerClosed)
+			delete(s.breakers, key)
+		}
+	case outcomeFailure:
+		if !ok {
+			b = &breaker{}
+			s.breakers[key] = b
+		}
+		b.failures++
+		switch {
+		case b.state == breakerHalfOpen:
+			s.open(b)
+		case b.state == breakerClosed && b.failures >= s.cfg.FailureThreshold:
+			s.open(b)
+		}
+	}
+}
+
+func (s *breakerSet) open(b *breaker) {
+	s.setState(b, breakerOpen)
+	b.openedAt = s.now()
+	b.probing = false
+}
+
+// setState transitions b to state and updates the metrics. It must be called
+// with the lock held.
+func (s *breakerSet) setState(b *breaker, state breakerState) {
+	if b.state == state {
+		return
+	}
+	if b.state != breakerClosed {
+		breakerCircuits.With(prometheus.Labels{labelState: b.state.String()}).Dec()
+	}
+	if state != breakerClosed {
+		breakerCircuits.With(prometheus.Labels{labelState: state.String()}).Inc()
+	}
+	breakerTransitionsTotal.With(prometheus.Labels{labelState: state.String()}).Inc()
+	b.state = state
+}
+
+func breakerKey(a net.Addr) string {
+	if a == nil {
+		return ""
+	}
+	return a.Network() + "/" + a.String()
+}
@@ -0,0 +1,210 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package messenger
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/infra"
+	"github.com/scionproto/scion/go/lib/infra/mock_infra"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+)
+
+func mustParseAddr(t *testing.T, s string) *snet.Addr {
+	t.Helper()
+	a, err := snet.AddrFromString(s)
+	require.NoError(t, err)
+	return a
+}
+
+// TestMessengerWithBreakerCallsUnderlyingMessenger tests that the messenger
+// with breaker calls the underlying messenger function while the circuit is
+// closed.
+func TestMessengerWithBreakerCallsUnderlyingMessenger(t *testing.T) {
+	initMetrics()
+	msgerType := reflect.TypeOf((*MessengerWithBreaker)(nil))
+	for i := 0; i < msgerType.NumMethod(); i++ {
+		method := msgerType.Method(i)
+		t.Run(fmt.Sprintf("Testing method %s", method.Name), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockMsger := mock_infra.NewMockMessenger(ctrl)
+			expectedCall := reflect.ValueOf(mockMsger.EXPECT()).MethodByName(method.Name)
+			msger := NewMessengerWithBreaker(mockMsger, BreakerConfig{})
+			var args []reflect.Value
+			var argMatchers []reflect.Value
+			methodType := method.Type
+			for a := 1; a < methodType.NumIn(); a++ {
+				if methodType.In(a).Name() == "Context" {
+					args = append(args, reflect.ValueOf(context.Background()))
+				} else {
+					args = append(args, reflect.Zero(methodType.In(a)))
+				}
+				argMatchers = append(argMatchers, reflect.ValueOf(gomock.Any()))
+			}
+			expectedCall.Call(argMatchers)
+			reflect.ValueOf(msger).MethodByName(method.Name).Call(args)
+		})
+	}
+}
+
+func TestMessengerWithBreakerOpensCircuit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockMsger := mock_infra.NewMockMessenger(ctrl)
+	msger := NewMessengerWithBreaker(mockMsger, BreakerConfig{FailureThreshold: 2})
+	dead := mustParseAddr(t, "1-ff00:0:111,[127.0.0.1]:80")
+	alive := mustParseAddr(t, "1-ff00:0:112,[127.0.0.1]:80")
+
+	mockMsger.EXPECT().SendRev(gomock.Any(), gomock.Any(), dead, gomock.Any()).
+		Return(context.DeadlineExceeded).Times(2)
+	mockMsger.EXPECT().SendRev(gomock.Any(), gomock.Any(), alive, gomock.Any()).
+		Return(nil).Times(1)
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		err := msger.SendRev(ctx, nil, dead, 0)
+		assert.True(t, common.IsTimeoutErr(err))
+	}
+	err := msger.SendRev(ctx, nil, dead, 0)
+	assert.True(t, xerrors.Is(err, ErrCircuitOpen))
+	assert.NoError(t, msger.SendRev(ctx, nil, alive, 0))
+}
+
+func TestMessengerWithBreakerIgnoresReplies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockMsger := mock_infra.NewMockMessenger(ctrl)
+	msger := NewMessengerWithBreaker(mockMsger, BreakerConfig{FailureThreshold: 1})
+	slow := mustParseAddr(t, "1-ff00:0:111,[127.0.0.1]:80")
+
+	mockMsger.EXPECT().SendAck(gomock.Any(), gomock.Any(), slow, gomock.Any()).
+		Return(context.DeadlineExceeded).Times(2)
+	mockMsger.EXPECT().SendRev(gomock.Any(), gomock.Any(), slow, gomock.Any()).
+		Return(nil).Times(1)
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		err := msger.SendAck(ctx, nil, slow, 0)
+		assert.True(t, common.IsTimeoutErr(err))
+	}
+	assert.NoError(t, msger.SendRev(ctx, nil, slow, 0))
+}
+
+func TestBreakerSet(t *testing.T) {
+	timeoutErr := serrors.WrapStr("request failed", context.DeadlineExceeded)
+	otherErr := serrors.New("bad reply")
+	type step struct {
+		Advance       time.Duration
+		Err           error
+		ExpectAllowed bool
+		ExpectState   breakerState
+	}
+	tests := map[string][]step{
+		"opens after threshold": {
+			{Err: timeoutErr, ExpectAllowed: true, ExpectState: breakerClosed},
+			{Err: timeoutErr, ExpectAllowed: true, ExpectState: breakerClosed},
+			{Err: timeoutErr, ExpectAllowed: true, ExpectState: breakerOpen},
+			{ExpectAllowed: false, ExpectState: breakerOpen},
+		},
+		"success resets failures": {
+			{Err: timeoutErr, ExpectAllowed: true, ExpectState: breakerClosed},
+			{Err: timeoutErr, ExpectAllowed: true, ExpectState: breakerClosed},
+			{Err: nil, ExpectAllowed: true, ExpectState: breakerClosed},
+			{Err: timeoutErr, ExpectAllowed: true, ExpectState: breakerClosed},
+			{Err: timeoutErr, ExpectAllowed: true, ExpectState: breakerClosed},
+		},
+		"non-timeout errors are not failures": {
+			{Err: otherErr, ExpectAllowed: true, ExpectState: breakerClosed},
+			{Err: otherErr, ExpectAllowed: true, ExpectState: breakerClosed},
+			{Err: otherErr, ExpectAllowed: true, ExpectState: breakerClosed},
+			{Err: otherErr, ExpectAllowed: true, ExpectState: breakerClosed},
+		},
+		"successful probe closes circuit": {
+			{Err: timeoutErr, ExpectAllowed: true, ExpectState: breakerClosed},
+			{Err: timeoutErr, ExpectAllowed: true, ExpectState: breakerClosed},
+			{Err: timeoutErr, ExpectAllowed: true, ExpectState: breakerOpen},
+			{Advance: 9 * time.Second, ExpectAllowed: false, ExpectState: breakerOpen},
+			{Advance: time.Second, Err: nil, ExpectAllowed: true, ExpectState: breakerClosed},
+			{Err: nil, ExpectAllowed: true, ExpectState: breakerClosed},
+		},
+		"failed probe opens circuit": {
+			{Err: timeoutErr, ExpectAllowed: true, ExpectState: breakerClosed},
+			{Err: timeoutErr, ExpectAllowed: true, ExpectState: breakerClosed},
+			{Err: timeoutErr, ExpectAllowed: true, ExpectState: breakerOpen},
+			{Advance: 10 * time.Second, Err: timeoutErr, ExpectAllowed: true,
+				ExpectState: breakerOpen},
+			{Advance: 9 * time.Second, ExpectAllowed: false, ExpectState: breakerOpen},
+		},
+		"canceled probe allows new probe": {
+			{Err: timeoutErr, ExpectAllowed: true, ExpectState: breakerClosed},
+			{Err: timeoutErr, ExpectAllowed: true, ExpectState: breakerClosed},
+			{Err: timeoutErr, ExpectAllowed: true, ExpectState: breakerOpen},
+			{Advance: 10 * time.Second, Err: context.Canceled, ExpectAllowed: true,
+				ExpectState: breakerHalfOpen},
+			{Err: nil, ExpectAllowed: true, ExpectState: breakerClosed},
+		},
+	}
+	for name, steps := range tests {
+		t.Run(name, func(t *testing.T) {
+			initMetrics()
+			now := time.Now()
+			s := newBreakerSet(BreakerConfig{FailureThreshold: 3, OpenTimeout: 10 * time.Second})
+			s.now = func() time.Time { return now }
+			dst := mustParseAddr(t, "1-ff00:0:111,[127.0.0.1]:80")
+			for i, st := range steps {
+				now = now.Add(st.Advance)
+				called := false
+				err := s.do(dst, infra.SegRequest, func() error {
+					called = true
+					return st.Err
+				})
+				require.Equal(t, st.ExpectAllowed, called, "step %d", i)
+				if !st.ExpectAllowed {
+					assert.True(t, xerrors.Is(err, ErrCircuitOpen), "step %d", i)
+				}
+				state := breakerClosed
+				if b, ok := s.breakers[breakerKey(dst)]; ok {
+					state = b.state
+				}
+				assert.Equal(t, st.ExpectState, state, "step %d", i)
+			}
+		})
+	}
+}
+
+func TestBreakerSetHalfOpenSingleProbe(t *testing.T) {
+	initMetrics()
+	now := time.Now()
+	s := newBreakerSet(BreakerConfig{FailureThreshold: 1, OpenTimeout: time.Second})
+	s.now = func() time.Time { return now }
+	key := breakerKey(mustParseAddr(t, "1-ff00:0:111,[127.0.0.1]:80"))
+	s.report(key, outcomeFailure)
+	assert.False(t, s.allow(key))
+	now = now.Add(time.Second)
+	assert.True(t, s.allow(key), "probe must be allowed")
+	assert.False(t, s.allow(key), "only a single probe must be in flight")
+	s.report(key, outcomeSuccess)
+	assert.True(t, s.allow(key))
+}
@@ -15,6 +15,8 @@
 package messenger
 
 import (
+	"context"
+	"fmt"
 	"sync/atomic"
 
 	"github.com/scionproto/scion/go/lib/scrypto"
@@ -28,3 +30,41 @@ var (
 func NextId() uint64 {
 	return atomic.AddUint64(&counter, 1)
 }
+
+// RequestID identifies a request handled by a service, such that all log
+// entries and outgoing messages caused by the request can be correlated.
+type RequestID uint32
+
+// NewRequestID returns a random request ID.
+func NewRequestID() RequestID {
+	return RequestID(scrypto.RandUint64())
+}
+
+func (id RequestID) String() string {
+	return fmt.Sprintf("%08x", uint32(id))
+}
+
+type requestIDContextKey struct{}
+
+// NewContextWithRequestID returns a copy of ctx that carries the request ID.
+func NewContextWithRequestID(ctx context.Context, id RequestID) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, if any.
+func RequestIDFromContext(ctx context.Context) (RequestID, bool) {
+	id, ok := ctx.Value(requestIDContextKey{}).(RequestID)
+	return id, ok
+}
+
+// NextIdFromCtx is like NextId, but if ctx carries a request ID, the upper 32
+// bits of the returned ID are set to the request ID. Messages sent on behalf
+// of a request can thus be correlated with it in the logs of the receivers,
+// which log the ID of every received message.
+func NextIdFromCtx(ctx context.Context) uint64 {
+	id := NextId()
+	if reqID, ok := RequestIDFromContext(ctx); ok {
+		id = uint64(reqID)<<32 | id&0xffffffff
+	}
+	return id
+}
@@ -0,0 +1,42 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package messenger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextIdFromCtx(t *testing.T) {
+	t.Run("without request ID", func(t *testing.T) {
+		_, ok := RequestIDFromContext(context.Background())
+		assert.False(t, ok)
+		id1, id2 := NextIdFromCtx(context.Background()), NextIdFromCtx(context.Background())
+		assert.NotEqual(t, id1, id2)
+	})
+	t.Run("with request ID", func(t *testing.T) {
+		ctx := NewContextWithRequestID(context.Background(), 0xdeadbeef)
+		reqID, ok := RequestIDFromContext(ctx)
+		require.True(t, ok)
+		assert.Equal(t, "deadbeef", reqID.String())
+		id1, id2 := NextIdFromCtx(ctx), NextIdFromCtx(ctx)
+		assert.Equal(t, uint64(0xdeadbeef), id1>>32)
+		assert.Equal(t, uint64(0xdeadbeef), id2>>32)
+		assert.NotEqual(t, id1, id2)
+	})
+}
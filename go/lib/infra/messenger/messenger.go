@@ -71,6 +71,11 @@
 //
 // CloseServer() does not do graceful shutdown of the handlers and does not
 // close the Messenger itself.
+//
+// If Config.Auth is set, the requesters of incoming messages are authenticated
+// before the handler is called. Rejected messages are dropped, and the ISD-AS
+// of authenticated requesters is available to handlers via
+// infra.AuthenticatedPeerFromContext.
 package messenger
 
 import (
@@ -80,6 +85,7 @@ import (
 	"fmt"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	quic "github.com/lucas-clemente/quic-go"
@@ -99,6 +105,7 @@ import (
 	"github.com/scionproto/scion/go/lib/infra/disp"
 	"github.com/scionproto/scion/go/lib/infra/rpc"
 	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/snet"
 	"github.com/scionproto/scion/go/lib/util"
 	"github.com/scionproto/scion/go/proto"
@@ -106,6 +113,13 @@ import (
 
 const (
 	DefaultHandlerTimeout = 10 * time.Second
+	// DefaultMaxSegReplySize is the default maximum size of a single segment
+	// reply message sent over UDP. It leaves room for the ctrl payload, the
+	// signature and the SCION header within a 64KiB datagram.
+	DefaultMaxSegReplySize = 32 * 1024
+	// DefaultMaxSegReplyChunks is the default maximum number of chunks a
+	// segment reply is split into.
+	DefaultMaxSegReplyChunks = 64
 )
 
 // Config can be used to customize the behavior of the Messenger.
@@ -132,6 +146,17 @@ type Config struct {
 	// QUIC defines whether the Messenger should also operate on top of QUIC
 	// instead of only on UDP.
 	QUIC *QUICConfig
+	// MaxSegReplySize is the maximum size in bytes of a single segment reply
+	// message sent over UDP. Larger replies are split into chunks that are
+	// reassembled by the requester. If it is 0, the default is used.
+	MaxSegReplySize int
+	// MaxSegReplyChunks is the maximum number of chunks a segment reply is
+	// split into. Segments that do not fit are dropped, and the reply is
+	// marked as truncated. If it is 0, the default is used.
+	MaxSegReplyChunks int
+	// Auth, if set, enables the authentication of incoming requests. Requests
+	// that fail authentication or authorization are dropped.
+	Auth *AuthConfig
 }
 
 type QUICConfig struct {
@@ -144,6 +169,12 @@ func (c *Config) InitDefaults() {
 	if c.HandlerTimeout == 0 {
 		c.HandlerTimeout = DefaultHandlerTimeout
 	}
+	if c.MaxSegReplySize == 0 {
+		c.MaxSegReplySize = DefaultMaxSegReplySize
+	}
+	if c.MaxSegReplyChunks == 0 {
+		c.MaxSegReplyChunks = DefaultMaxSegReplyChunks
+	}
 	if c.Logger == nil {
 		c.Logger = log.Root()
 	}
@@ -177,6 +208,9 @@ type Messenger struct {
 	// Context passed to blocking receive. Canceled by Close to unblock listeners.
 	ctx     context.Context
 	cancelF context.CancelFunc
+	// serving is 1 while the UDP server loop of ListenAndServe runs. It must
+	// be accessed atomically.
+	serving int32
 
 	ia  addr.IA
 	log log.Logger
@@ -184,6 +218,10 @@ type Messenger struct {
 	quicClient  *rpc.Client
 	quicServer  *rpc.Server
 	quicHandler *QUICHandler
+
+	// auth authenticates incoming requests. If it is nil, all requests are
+	// accepted.
+	auth *authenticator
 }
 
 // New creates a new Messenger based on config.
@@ -195,6 +233,7 @@ func New(config *Config) *Messenger {
 
 	// Parent context for all handlers
 	ctx, cancelF := context.WithCancel(context.Background())
+	auth := newAuthenticator(config.Auth)
 
 	var quicServer *rpc.Server
 	var quicClient *rpc.Client
@@ -211,6 +250,7 @@ func New(config *Config) *Messenger {
 			timeout:      config.HandlerTimeout,
 			parentLogger: config.Logger,
 			parentCtx:    ctx,
+			auth:         auth,
 		}
 		quicServer = &rpc.Server{
 			Conn:       config.QUIC.Conn,
@@ -240,6 +280,7 @@ func New(config *Config) *Messenger {
 		quicServer:      quicServer,
 		quicClient:      quicClient,
 		quicHandler:     quicHandler,
+		auth:            auth,
 	}
 }
 
@@ -394,40 +435,87 @@ func (m *Messenger) GetSegs(ctx context.Context, msg *path_mgmt.SegReq,
 	}
 	logger.Trace("[Messenger] Sending request", "req_type", infra.SegRequest,
 		"msg_id", id, "request", msg, "peer", a)
-	replyCtrlPld, err := m.getFallbackRequester(infra.SegRequest).Request(ctx, pld, a, false)
+	replyCtrlPlds, err := m.getFallbackRequester(infra.SegRequest).RequestStream(ctx, pld, a,
+		segReplyMore)
 	if err != nil {
 		return nil, common.NewBasicError("[Messenger] Request error", err,
 			"req_type", infra.SegRequest)
 	}
-	_, replyMsg, err := validate(replyCtrlPld)
+	chunks := make([]*path_mgmt.SegReply, 0, len(replyCtrlPlds))
+	for _, replyCtrlPld := range replyCtrlPlds {
+		_, replyMsg, err := validate(replyCtrlPld)
+		if err != nil {
+			return nil, common.NewBasicError("[Messenger] Reply validation failed", err)
+		}
+		switch reply := replyMsg.(type) {
+		case *path_mgmt.SegReply:
+			chunks = append(chunks, reply)
+		case *ack.Ack:
+			return nil, &infra.Error{Message: reply}
+		default:
+			err := newTypeAssertErr("*path_mgmt.SegReply", replyMsg)
+			return nil, common.NewBasicError("[Messenger] Type assertion failed", err)
+		}
+	}
+	reply, err := path_mgmt.MergeSegReplies(chunks)
 	if err != nil {
-		return nil, common.NewBasicError("[Messenger] Reply validation failed", err)
+		return nil, common.NewBasicError("[Messenger] Failed to reassemble reply", err)
 	}
-	switch reply := replyMsg.(type) {
-	case *path_mgmt.SegReply:
-		if err := reply.ParseRaw(); err != nil {
-			return nil, common.NewBasicError("[Messenger] Failed to parse reply", err)
+	if err := reply.ParseRaw(); err != nil {
+		return nil, common.NewBasicError("[Messenger] Failed to parse reply", err)
+	}
+	if reply.Truncated {
+		logger.Debug("[Messenger] Received truncated reply", "req_id", id, "peer", a)
+	}
+	logger.Trace("[Messenger] Received reply", "req_id", id, "chunks", len(chunks))
+	return reply, nil
+}
+
+// segReplyMore returns whether more chunks of a segment reply are expected
+// after plds have been received.
+func segReplyMore(plds []*ctrl.Pld) bool {
+	chunks := make([]*path_mgmt.SegReply, 0, len(plds))
+	for _, pld := range plds {
+		_, msg, err := validate(pld)
+		if err != nil {
+			return false
 		}
-		logger.Trace("[Messenger] Received reply", "req_id", id)
-		return reply, nil
-	case *ack.Ack:
-		return nil, &infra.Error{Message: reply}
-	default:
-		err := newTypeAssertErr("*path_mgmt.SegReply", replyMsg)
-		return nil, common.NewBasicError("[Messenger] Type assertion failed", err)
+		reply, ok := msg.(*path_mgmt.SegReply)
+		if !ok {
+			return false
+		}
+		chunks = append(chunks, reply)
 	}
+	return !path_mgmt.SegRepliesComplete(chunks)
 }
 
+// SendSegReply sends msg to a. Replies that exceed the configured maximum
+// message size are split into multiple chunks with the same ID.
 func (m *Messenger) SendSegReply(ctx context.Context, msg *path_mgmt.SegReply,
 	a net.Addr, id uint64) error {
 
-	pld, err := ctrl.NewPathMgmtPld(msg, nil, &ctrl.Data{ReqId: id})
+	chunks, err := msg.Split(m.config.MaxSegReplySize, m.config.MaxSegReplyChunks)
 	if err != nil {
 		return err
 	}
 	logger := log.FromCtx(ctx)
-	logger.Trace("[Messenger] Sending Notify", "type", infra.SegReply, "to", a, "id", id)
-	return m.getFallbackRequester(infra.SegReply).Notify(ctx, pld, a)
+	if len(chunks) > 0 && chunks[0].Truncated {
+		logger.Info("[Messenger] Segment reply truncated", "to", a, "id", id,
+			"max_size", m.config.MaxSegReplySize, "max_chunks", m.config.MaxSegReplyChunks)
+	}
+	requester := m.getFallbackRequester(infra.SegReply)
+	for _, chunk := range chunks {
+		pld, err := ctrl.NewPathMgmtPld(chunk, nil, &ctrl.Data{ReqId: id})
+		if err != nil {
+			return err
+		}
+		logger.Trace("[Messenger] Sending Notify", "type", infra.SegReply, "to", a, "id", id,
+			"chunk", chunk.Chunk, "chunks", chunk.Chunks)
+		if err := requester.Notify(ctx, pld, a); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (m *Messenger) SendSegSync(ctx context.Context, msg *path_mgmt.SegSync,
@@ -724,8 +812,10 @@ func (m *Messenger) sendMessage(ctx context.Context, msg proto.Cerealizable, a n
 	return err
 }
 
-// AddHandler registers a handler for msgType.
+// AddHandler registers a handler for msgType. Panics in the handler are
+// recovered and answered with an internal error ack.
 func (m *Messenger) AddHandler(msgType infra.MessageType, handler infra.Handler) {
+	handler = infra.NewRecoveringHandler(msgType, handler)
 	m.handlersLock.Lock()
 	m.handlers[msgType] = handler
 	if m.quicServer != nil {
@@ -745,6 +835,8 @@ func (m *Messenger) ListenAndServe() {
 			m.listenAndServeQUIC()
 			close(done)
 		}()
+	} else {
+		close(done)
 	}
 	m.listenAndServeUDP()
 	<-done
@@ -761,6 +853,8 @@ func (m *Messenger) listenAndServeQUIC() {
 func (m *Messenger) listenAndServeUDP() {
 	m.log.Info("Started listening UDP")
 	defer m.log.Info("Stopped listening UDP")
+	atomic.StoreInt32(&m.serving, 1)
+	defer atomic.StoreInt32(&m.serving, 0)
 	for {
 		// Recv blocks until a new message is received. To close the server,
 		// CloseServer() calls the context's cancel function, thus unblocking Recv. The
@@ -831,6 +925,13 @@ func (m *Messenger) serve(ctx context.Context, cancelF context.CancelFunc, pld *
 		return
 	}
 	logger.Trace("[Messenger] Received message", "type", msgType, "from", address, "id", pld.ReqId)
+	ctx, err = m.auth.authenticate(ctx, msgType, AuthInfo{Address: address, SignedPld: signedPld})
+	if err != nil {
+		logger.Warn("Received message, but rejected requester", "from", address,
+			"msgType", msgType, "id", pld.ReqId, "err", err)
+		sendAuthReject(ctx, msgType)
+		return
+	}
 
 	m.handlersLock.RLock()
 	handler := m.handlers[msgType]
@@ -870,6 +971,29 @@ func (m *Messenger) serve(ctx context.Context, cancelF context.CancelFunc, pld *
 	}()
 }
 
+// CheckServing returns an error if m does not serve requests, i.e., if
+// ListenAndServe has not been called yet or has returned. It can be registered
+// as a readiness check, see env.AddReadinessCheck.
+func (m *Messenger) CheckServing(_ context.Context) error {
+	if atomic.LoadInt32(&m.serving) == 0 {
+		return serrors.New("messenger not serving")
+	}
+	return nil
+}
+
+// CheckServing returns an error if msgr does not serve requests. It supports
+// the messengers of this package, including the wrapping ones, and returns an
+// error for other implementations.
+func CheckServing(ctx context.Context, msgr infra.Messenger) error {
+	c, ok := msgr.(interface {
+		CheckServing(context.Context) error
+	})
+	if !ok {
+		return serrors.New("messenger does not report serving state")
+	}
+	return c.CheckServing(ctx)
+}
+
 // CloseServer stops any running ListenAndServe functions, and cancels all running
 // handlers. The server's Messenger layer is not closed.
 func (m *Messenger) CloseServer() error {
@@ -970,6 +1094,11 @@ type pathingRequester struct {
 func (pr *pathingRequester) Request(ctx context.Context, pld *ctrl.Pld,
 	a net.Addr, downgradeToNotify bool) (*ctrl.Pld, error) {
 
+	if !downgradeToNotify {
+		if reply, ok, err := pr.requestInstances(ctx, pld, a); ok {
+			return reply, err
+		}
+	}
 	newAddr, redirect, err := pr.addressRewriter.RedirectToQUIC(ctx, a)
 	if err != nil {
 		return nil, err
@@ -988,6 +1117,57 @@ func (pr *pathingRequester) Request(ctx context.Context, pld *ctrl.Pld,
 	return pld, err
 }
 
+// RequestStream is like Request, but collects all replies until more returns
+// false. Replies over QUIC are never chunked, so a single reply is returned in
+// that case.
+func (pr *pathingRequester) RequestStream(ctx context.Context, pld *ctrl.Pld,
+	a net.Addr, more func([]*ctrl.Pld) bool) ([]*ctrl.Pld, error) {
+
+	if reply, ok, err := pr.requestInstances(ctx, pld, a); ok {
+		if err != nil {
+			return nil, err
+		}
+		return []*ctrl.Pld{reply}, nil
+	}
+	newAddr, redirect, err := pr.addressRewriter.RedirectToQUIC(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	logger := log.FromCtx(ctx)
+	if redirect && pr.quicRequester != nil {
+		logger.Trace("Request upgraded to QUIC", "remote", newAddr)
+		pld, err := pr.quicRequester.Request(ctx, pld, newAddr)
+		if err != nil {
+			return nil, err
+		}
+		return []*ctrl.Pld{pld}, nil
+	}
+	logger.Trace("Request could not be upgraded to QUIC, using UDP", "remote", newAddr)
+	return pr.requester.RequestStream(ctx, pld, newAddr, more)
+}
+
+// requestInstances sends the request over QUIC to the instances of the remote
+// SVC destination a, trying the next instance if one times out. The boolean
+// return value is false if the request was not attempted, see
+// AddressRewriter.RequestInstances.
+func (pr *pathingRequester) requestInstances(ctx context.Context, pld *ctrl.Pld,
+	a net.Addr) (*ctrl.Pld, bool, error) {
+
+	if pr.quicRequester == nil {
+		return nil, false, nil
+	}
+	var reply *ctrl.Pld
+	ok, err := pr.addressRewriter.RequestInstances(ctx, a,
+		func(ctx context.Context, instance net.Addr) error {
+			log.FromCtx(ctx).Trace("Request upgraded to QUIC", "remote", instance)
+			var err error
+			reply, err = pr.quicRequester.Request(ctx, pld, instance)
+			return err
+		},
+	)
+	return reply, ok, err
+}
+
 func (pr *pathingRequester) Notify(ctx context.Context, pld *ctrl.Pld, a net.Addr) error {
 	newAddr, _, err := pr.addressRewriter.RedirectToQUIC(ctx, a)
 	if err != nil {
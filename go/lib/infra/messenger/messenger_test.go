@@ -15,13 +15,55 @@
 package messenger
 
 import (
+	"context"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/infra/disp"
+	"github.com/scionproto/scion/go/lib/infra/mock_infra"
 	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/xtest/p2p"
 )
 
 func TestMain(m *testing.M) {
 	log.Root().SetHandler(log.DiscardHandler())
 	os.Exit(m.Run())
 }
+
+func TestCheckServing(t *testing.T) {
+	ctx := context.Background()
+	t.Run("unsupported messenger", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		assert.Error(t, CheckServing(ctx, mock_infra.NewMockMessenger(ctrl)))
+	})
+	t.Run("serving state", func(t *testing.T) {
+		conn, _ := p2p.NewPacketConns()
+		msgr := New(&Config{Dispatcher: disp.New(conn, DefaultAdapter, log.Root())})
+		wrapped := NewMessengerWithBreaker(msgr, BreakerConfig{})
+		assert.Error(t, CheckServing(ctx, wrapped))
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			msgr.ListenAndServe()
+		}()
+		var err error
+		for start := time.Now(); time.Since(start) < time.Second; {
+			if err = CheckServing(ctx, wrapped); err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		assert.NoError(t, err)
+
+		require.NoError(t, msgr.CloseServer())
+		<-done
+		assert.Error(t, CheckServing(ctx, wrapped))
+	})
+}
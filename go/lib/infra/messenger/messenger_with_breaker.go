@@ -0,0 +1,288 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package messenger
+
+import (
+	"context"
+	"net"
+
+	"github.com/scionproto/scion/go/lib/ctrl/ack"
+	"github.com/scionproto/scion/go/lib/ctrl/cert_mgmt"
+	"github.com/scionproto/scion/go/lib/ctrl/ifid"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/infra"
+)
+
+var _ infra.Messenger = (*MessengerWithBreaker)(nil)
+
+// MessengerWithBreaker wraps a messenger with per-destination circuit
+// breakers. After a number of consecutive timeouts towards a destination, the
+// circuit is opened and further calls to that destination fail immediately
+// with ErrCircuitOpen, instead of tying up the caller until the timeout
+// expires. After some time, a single probe call is let through to check
+// whether the destination recovered.
+//
+// Only outgoing requests and notifications are subject to the circuit
+// breakers. Replies to requests of remote clients are always sent and their
+// outcome is not recorded, such that a slow client cannot open the circuit
+// towards itself.
+//
+// Destinations are distinguished by their address, e.g., the SVC address of
+// a remote AS is a single destination.
+type MessengerWithBreaker struct {
+	messenger infra.Messenger
+	breakers  *breakerSet
+}
+
+// NewMessengerWithBreaker wraps msger with circuit breakers configured by
+// cfg. Unset values in cfg are initialized to their defaults.
+func NewMessengerWithBreaker(msger infra.Messenger, cfg BreakerConfig) *MessengerWithBreaker {
+	initMetrics()
+	return &MessengerWithBreaker{
+		messenger: msger,
+		breakers:  newBreakerSet(cfg),
+	}
+}
+
+func (m *MessengerWithBreaker) SendAck(ctx context.Context, msg *ack.Ack, a net.Addr,
+	id uint64) error {
+
+	return m.messenger.SendAck(ctx, msg, a, id)
+}
+
+func (m *MessengerWithBreaker) GetTRC(ctx context.Context, msg *cert_mgmt.TRCReq,
+	a net.Addr, id uint64) (*cert_mgmt.TRC, error) {
+
+	var trc *cert_mgmt.TRC
+	err := m.breakers.do(a, infra.TRCRequest, func() error {
+		var err error
+		trc, err = m.messenger.GetTRC(ctx, msg, a, id)
+		return err
+	})
+	return trc, err
+}
+
+func (m *MessengerWithBreaker) SendTRC(ctx context.Context, msg *cert_mgmt.TRC, a net.Addr,
+	id uint64) error {
+
+	return m.messenger.SendTRC(ctx, msg, a, id)
+}
+
+func (m *MessengerWithBreaker) GetCertChain(ctx context.Context, msg *cert_mgmt.ChainReq,
+	a net.Addr, id uint64) (*cert_mgmt.Chain, error) {
+
+	var chain *cert_mgmt.Chain
+	err := m.breakers.do(a, infra.ChainRequest, func() error {
+		var err error
+		chain, err = m.messenger.GetCertChain(ctx, msg, a, id)
+		return err
+	})
+	return chain, err
+}
+
+func (m *MessengerWithBreaker) SendCertChain(ctx context.Context, msg *cert_mgmt.Chain, a net.Addr,
+	id uint64) error {
+
+	return m.messenger.SendCertChain(ctx, msg, a, id)
+}
+
+func (m *MessengerWithBreaker) SendIfId(ctx context.Context, msg *ifid.IFID, a net.Addr,
+	id uint64) error {
+
+	return m.breakers.do(a, infra.IfId, func() error {
+		return m.messenger.SendIfId(ctx, msg, a, id)
+	})
+}
+
+func (m *MessengerWithBreaker) SendIfStateInfos(ctx context.Context, msg *path_mgmt.IFStateInfos,
+	a net.Addr, id uint64) error {
+
+	return m.breakers.do(a, infra.IfStateInfos, func() error {
+		return m.messenger.SendIfStateInfos(ctx, msg, a, id)
+	})
+}
+
+func (m *MessengerWithBreaker) SendRev(ctx context.Context, msg *path_mgmt.SignedRevInfo,
+	a net.Addr, id uint64) error {
+
+	return m.breakers.do(a, infra.SignedRev, func() error {
+		return m.messenger.SendRev(ctx, msg, a, id)
+	})
+}
+
+func (m *MessengerWithBreaker) SendSegReg(ctx context.Context, msg *path_mgmt.SegReg,
+	a net.Addr, id uint64) error {
+
+	return m.breakers.do(a, infra.SegReg, func() error {
+		return m.messenger.SendSegReg(ctx, msg, a, id)
+	})
+}
+
+func (m *MessengerWithBreaker) GetSegs(ctx context.Context, msg *path_mgmt.SegReq,
+	a net.Addr, id uint64) (*path_mgmt.SegReply, error) {
+
+	var segs *path_mgmt.SegReply
+	err := m.breakers.do(a, infra.SegRequest, func() error {
+		var err error
+		segs, err = m.messenger.GetSegs(ctx, msg, a, id)
+		return err
+	})
+	return segs, err
+}
+
+func (m *MessengerWithBreaker) SendSegReply(ctx context.Context, msg *path_mgmt.SegReply,
+	a net.Addr, id uint64) error {
+
+	return m.messenger.SendSegReply(ctx, msg, a, id)
+}
+
+func (m *MessengerWithBreaker) SendSegSync(ctx context.Context, msg *path_mgmt.SegSync,
+	a net.Addr, id uint64) error {
+
+	return m.breakers.do(a, infra.SegSync, func() error {
+		return m.messenger.SendSegSync(ctx, msg, a, id)
+	})
+}
+
+func (m *MessengerWithBreaker) GetSegChangesIds(ctx context.Context, msg *path_mgmt.SegChangesIdReq,
+	a net.Addr, id uint64) (*path_mgmt.SegChangesIdReply, error) {
+
+	var reply *path_mgmt.SegChangesIdReply
+	err := m.breakers.do(a, infra.SegChangesIdReq, func() error {
+		var err error
+		reply, err = m.messenger.GetSegChangesIds(ctx, msg, a, id)
+		return err
+	})
+	return reply, err
+}
+
+func (m *MessengerWithBreaker) SendSegChangesIdReply(ctx context.Context,
+	msg *path_mgmt.SegChangesIdReply, a net.Addr, id uint64) error {
+
+	return m.messenger.SendSegChangesIdReply(ctx, msg, a, id)
+}
+
+func (m *MessengerWithBreaker) GetSegChanges(ctx context.Context, msg *path_mgmt.SegChangesReq,
+	a net.Addr, id uint64) (*path_mgmt.SegChangesReply, error) {
+
+	var reply *path_mgmt.SegChangesReply
+	err := m.breakers.do(a, infra.SegChangesReq, func() error {
+		var err error
+		reply, err = m.messenger.GetSegChanges(ctx, msg, a, id)
+		return err
+	})
+	return reply, err
+}
+
+func (m *MessengerWithBreaker) SendSegChangesReply(ctx context.Context,
+	msg *path_mgmt.SegChangesReply, a net.Addr, id uint64) error {
+
+	return m.messenger.SendSegChangesReply(ctx, msg, a, id)
+}
+
+func (m *MessengerWithBreaker) SendHPSegReg(ctx context.Context, msg *path_mgmt.HPSegReg,
+	a net.Addr, id uint64) error {
+
+	return m.breakers.do(a, infra.HPSegReg, func() error {
+		return m.messenger.SendHPSegReg(ctx, msg, a, id)
+	})
+}
+
+func (m *MessengerWithBreaker) GetHPSegs(ctx context.Context, msg *path_mgmt.HPSegReq,
+	a net.Addr, id uint64) (*path_mgmt.HPSegReply, error) {
+
+	var segs *path_mgmt.HPSegReply
+	err := m.breakers.do(a, infra.HPSegRequest, func() error {
+		var err error
+		segs, err = m.messenger.GetHPSegs(ctx, msg, a, id)
+		return err
+	})
+	return segs, err
+}
+
+func (m *MessengerWithBreaker) SendHPSegReply(ctx context.Context, msg *path_mgmt.HPSegReply,
+	a net.Addr, id uint64) error {
+
+	return m.messenger.SendHPSegReply(ctx, msg, a, id)
+}
+
+func (m *MessengerWithBreaker) GetHPCfgs(ctx context.Context, msg *path_mgmt.HPCfgReq,
+	a net.Addr, id uint64) (*path_mgmt.HPCfgReply, error) {
+
+	var cfgs *path_mgmt.HPCfgReply
+	err := m.breakers.do(a, infra.HPCfgRequest, func() error {
+		var err error
+		cfgs, err = m.messenger.GetHPCfgs(ctx, msg, a, id)
+		return err
+	})
+	return cfgs, err
+}
+
+func (m *MessengerWithBreaker) SendHPCfgReply(ctx context.Context, msg *path_mgmt.HPCfgReply,
+	a net.Addr, id uint64) error {
+
+	return m.messenger.SendHPCfgReply(ctx, msg, a, id)
+}
+
+func (m *MessengerWithBreaker) RequestChainIssue(ctx context.Context, msg *cert_mgmt.ChainIssReq,
+	a net.Addr, id uint64) (*cert_mgmt.ChainIssRep, error) {
+
+	var reply *cert_mgmt.ChainIssRep
+	err := m.breakers.do(a, infra.ChainIssueRequest, func() error {
+		var err error
+		reply, err = m.messenger.RequestChainIssue(ctx, msg, a, id)
+		return err
+	})
+	return reply, err
+}
+
+func (m *MessengerWithBreaker) SendChainIssueReply(ctx context.Context, msg *cert_mgmt.ChainIssRep,
+	a net.Addr, id uint64) error {
+
+	return m.messenger.SendChainIssueReply(ctx, msg, a, id)
+}
+
+func (m *MessengerWithBreaker) SendBeacon(ctx context.Context, msg *seg.Beacon, a net.Addr,
+	id uint64) error {
+
+	return m.breakers.do(a, infra.Seg, func() error {
+		return m.messenger.SendBeacon(ctx, msg, a, id)
+	})
+}
+
+func (m *MessengerWithBreaker) AddHandler(msgType infra.MessageType, handler infra.Handler) {
+	m.messenger.AddHandler(msgType, handler)
+}
+
+func (m *MessengerWithBreaker) ListenAndServe() {
+	m.messenger.ListenAndServe()
+}
+
+func (m *MessengerWithBreaker) CheckServing(ctx context.Context) error {
+	return CheckServing(ctx, m.messenger)
+}
+
+func (m *MessengerWithBreaker) CloseServer() error {
+	return m.messenger.CloseServer()
+}
+
+func (m *MessengerWithBreaker) UpdateSigner(signer infra.Signer, types []infra.MessageType) {
+	m.messenger.UpdateSigner(signer, types)
+}
+
+func (m *MessengerWithBreaker) UpdateVerifier(verifier infra.Verifier) {
+	m.messenger.UpdateVerifier(verifier)
+}
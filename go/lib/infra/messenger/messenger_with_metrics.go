@@ -288,6 +288,10 @@ func (m *MessengerWithMetrics) ListenAndServe() {
 	m.messenger.ListenAndServe()
 }
 
+func (m *MessengerWithMetrics) CheckServing(ctx context.Context) error {
+	return CheckServing(ctx, m.messenger)
+}
+
 func (m *MessengerWithMetrics) CloseServer() error {
 	return m.messenger.CloseServer()
 }
@@ -34,6 +34,10 @@ import (
 
 const (
 	promNamespace = "messenger"
+
+	labelState  = "state"
+	labelPeer   = "peer"
+	labelReason = "reason"
 )
 
 var (
@@ -45,6 +49,12 @@ var (
 	inResultsTotal *prometheus.CounterVec
 	inCallsLatency *prometheus.HistogramVec
 
+	breakerCircuits         *prometheus.GaugeVec
+	breakerTransitionsTotal *prometheus.CounterVec
+	breakerRejectionsTotal  *prometheus.CounterVec
+
+	authFailuresTotal *prometheus.CounterVec
+
 	initOnce sync.Once
 )
 
@@ -69,6 +79,23 @@ func initMetrics() {
 			"Histogram of out call latency in seconds.",
 			[]string{prom.LabelStatus, prom.LabelOperation},
 			prom.DefaultLatencyBuckets)
+
+		// Cardinality: 2 (open, half_open)
+		breakerCircuits = prom.NewGaugeVec(promNamespace, "circuit_breaker", "circuits",
+			"Number of destinations with a non-closed circuit.", []string{labelState})
+		// Cardinality: 3 (closed, open, half_open)
+		breakerTransitionsTotal = prom.NewCounterVec(promNamespace, "circuit_breaker",
+			"transitions_total", "Total circuit state transitions, by new state.",
+			[]string{labelState})
+		// Cardinality: 17 (len(allOps))
+		breakerRejectionsTotal = prom.NewCounterVec(promNamespace, "circuit_breaker",
+			"rejections_total", "Total out calls rejected because of an open circuit.",
+			[]string{prom.LabelOperation})
+
+		// Cardinality: 17 (len(allOps)) * number of peers * 2 (reasons)
+		authFailuresTotal = prom.NewCounterVec(promNamespace, "auth", "failures_total",
+			"Total in calls rejected by request authentication.",
+			[]string{prom.LabelOperation, labelPeer, labelReason})
 	})
 }
 
@@ -45,6 +45,7 @@ type QUICHandler struct {
 	timeout      time.Duration
 	parentLogger log.Logger
 	parentCtx    context.Context
+	auth         *authenticator
 }
 
 func (h *QUICHandler) ServeRPC(rw rpc.ReplyWriter, request *rpc.Request) {
@@ -81,6 +82,17 @@ func (h *QUICHandler) ServeRPC(rw rpc.ReplyWriter, request *rpc.Request) {
 	defer servceCancelF()
 	defer span.Finish()
 
+	serveCtx, err = h.auth.authenticate(serveCtx, messageType, AuthInfo{
+		Address:          request.Address,
+		PeerCertificates: request.PeerCertificates,
+		SignedPld:        signedPld,
+	})
+	if err != nil {
+		log.Warn("Received message, but rejected requester", "from", request.Address,
+			"type", messageType, "err", err)
+		sendAuthReject(serveCtx, messageType)
+		return
+	}
 	if handler == nil {
 		log.Error("Message type not handled", "type", messageType)
 	} else {
@@ -27,6 +27,7 @@ const (
 	AckRejectFailedToParse  = "Failed to parse"
 	AckRejectFailedToVerify = "Failed to verfiy"
 	AckRejectPolicyError    = "Message rejected due to policy"
+	AckRejectUnauthorized   = "Requester not authorized"
 	AckRetryDBError         = "DB Error"
 )
 
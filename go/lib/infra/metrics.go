@@ -15,6 +15,10 @@
 package infra
 
 import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/prom"
 )
@@ -55,6 +59,20 @@ var (
 	MetricsResultOk = &HandlerResult{Result: prom.Success, Status: prom.StatusOk}
 )
 
+var (
+	handlerPanicsTotal *prometheus.CounterVec
+
+	initPanicMetricsOnce sync.Once
+)
+
+func initPanicMetrics() {
+	initPanicMetricsOnce.Do(func() {
+		// Cardinality: X (len(message types))
+		handlerPanicsTotal = prom.NewCounterVec("infra", "", "handler_panics_total",
+			"Total number of panics recovered in request handlers.", []string{"type"})
+	})
+}
+
 func MetricsErrTrustDB(err error) *HandlerResult {
 	return MetricsErrWithTimeout(err, metricsErrTrustDBTimeout, metricsErrTrustDB)
 }
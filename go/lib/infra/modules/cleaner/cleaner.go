@@ -37,7 +37,7 @@ var registry = metricsRegistry{registered: make(map[string]*metric)}
 // ExpiredDeleter is used to delete expired data.
 type ExpiredDeleter func(ctx context.Context) (int, error)
 
-var _ periodic.Task = (*Cleaner)(nil)
+var _ periodic.ErrorTask = (*Cleaner)(nil)
 
 // Cleaner is a periodic.Task implementation that deletes expired data.
 type Cleaner struct {
@@ -62,18 +62,24 @@ func (c *Cleaner) Name() string {
 
 // Run deletes expired entries using the deleter func.
 func (c *Cleaner) Run(ctx context.Context) {
+	c.RunE(ctx)
+}
+
+// RunE is like Run, but additionally returns the error of the deleter func.
+func (c *Cleaner) RunE(ctx context.Context) error {
 	count, err := c.deleter(ctx)
 	logger := log.FromCtx(ctx)
 	if err != nil {
 		logger.Error("[Cleaner] Failed to delete", "subsystem", c.subsystem, "err", err)
 		c.metric.resultsTotal.WithLabelValues("err").Inc()
-		return
+		return err
 	}
 	if count > 0 {
 		logger.Info("[Cleaner] Deleted expired", "subsystem", c.subsystem, "count", count)
 		c.metric.deletedTotal.Add(float64(count))
 	}
 	c.metric.resultsTotal.WithLabelValues("ok").Inc()
+	return nil
 }
 
 type metricsRegistry struct {
@@ -16,6 +16,7 @@ package cleaner_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/scionproto/scion/go/lib/infra/modules/cleaner"
@@ -28,3 +29,16 @@ func TestDoubleNew(t *testing.T) {
 	cleaner.New(dummy, "same")
 	cleaner.New(dummy, "same")
 }
+
+// TestRunE checks that RunE returns the error of the deleter.
+func TestRunE(t *testing.T) {
+	deleteErr := errors.New("test error")
+	failing := cleaner.New(func(context.Context) (int, error) { return 0, deleteErr }, "fail")
+	if err := failing.RunE(context.Background()); err != deleteErr {
+		t.Fatalf("Expected %v but was: %v", deleteErr, err)
+	}
+	ok := cleaner.New(func(context.Context) (int, error) { return 1, nil }, "ok")
+	if err := ok.RunE(context.Background()); err != nil {
+		t.Fatalf("Expected no error but was: %v", err)
+	}
+}
@@ -22,6 +22,10 @@
 //
 // Returned paths are sorted by weight in descending order. The weight is
 // defined as the number of transited AS hops in the path.
+//
+// Every path is annotated with its type, i.e., whether it transits the core,
+// uses a shortcut, or crosses a peering link. Use FilterPathTypes and
+// PreferPathTypes to select paths by type.
 package combinator
 
 import (
@@ -71,6 +75,8 @@ type Path struct {
 	Weight     int
 	Mtu        uint16
 	Interfaces []sciond.PathInterface
+	// Type is the type of the path, see PathType.
+	Type PathType
 }
 
 func (p *Path) writeTestString(w io.Writer) {
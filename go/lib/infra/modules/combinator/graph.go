@@ -276,6 +276,7 @@ func (solution *PathSolution) GetFwdPathMetadata() *Path {
 	path := &Path{
 		Weight: solution.cost,
 		Mtu:    ^uint16(0),
+		Type:   solution.pathType(),
 	}
 	for edgeIdx, solEdge := range solution.edges {
 		currentSeg := &Segment{
@@ -364,6 +365,22 @@ func (solution *PathSolution) GetFwdPathMetadata() *Path {
 	return path
 }
 
+// pathType returns the type of the path described by the solution. A path
+// that crosses a peering link is a peering path, even though peering links are
+// also annotated as shortcuts.
+func (solution *PathSolution) pathType() PathType {
+	pathType := PathTypeCore
+	for _, solEdge := range solution.edges {
+		if solEdge.edge.Peer != 0 {
+			return PathTypePeering
+		}
+		if solEdge.edge.Shortcut != 0 {
+			pathType = PathTypeShortcut
+		}
+	}
+	return pathType
+}
+
 // PathSolutionList is a sort.Interface implementation for a slice of solutions.
 type PathSolutionList []*PathSolution
 
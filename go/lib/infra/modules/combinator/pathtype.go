@@ -0,0 +1,79 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package combinator
+
+import (
+	"sort"
+	"strings"
+)
+
+// PathType is the type of a combined path. The types are bit flags, such that
+// sets of types are expressed by combining them with a bitwise or.
+type PathType uint8
+
+const (
+	// PathTypeCore is a path that transits the core. Paths that consist of a
+	// single segment, e.g., paths to a core AS, are core paths as well.
+	PathTypeCore PathType = 1 << iota
+	// PathTypeShortcut is a path that switches from the up to the down
+	// segment at an AS that both segments have in common, without transiting
+	// the core.
+	PathTypeShortcut
+	// PathTypePeering is a path that switches from the up to the down segment
+	// via a peering link.
+	PathTypePeering
+
+	// AllPathTypes is the set of all path types.
+	AllPathTypes = PathTypeCore | PathTypeShortcut | PathTypePeering
+)
+
+func (t PathType) String() string {
+	var names []string
+	if t&PathTypeCore != 0 {
+		names = append(names, "core")
+	}
+	if t&PathTypeShortcut != 0 {
+		names = append(names, "shortcut")
+	}
+	if t&PathTypePeering != 0 {
+		names = append(names, "peering")
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, "|")
+}
+
+// FilterPathTypes returns a new slice containing only those paths whose type
+// is in types.
+func FilterPathTypes(paths []*Path, types PathType) []*Path {
+	var newPaths []*Path
+	for _, path := range paths {
+		if path.Type&types != 0 {
+			newPaths = append(newPaths, path)
+		}
+	}
+	return newPaths
+}
+
+// PreferPathTypes moves the paths whose type is in types to the front of
+// paths. Otherwise, the order of paths is kept, e.g., to prefer peering
+// shortcuts over the paths through the core, while keeping the weight order
+// within both groups.
+func PreferPathTypes(paths []*Path, types PathType) {
+	sort.SliceStable(paths, func(i, j int) bool {
+		return paths[i].Type&types != 0 && paths[j].Type&types == 0
+	})
+}
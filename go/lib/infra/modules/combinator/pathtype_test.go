@@ -0,0 +1,135 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package combinator
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/lib/xtest/graph"
+)
+
+func TestPathType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	g := graph.NewDefaultGraph(ctrl)
+
+	tests := map[string]struct {
+		SrcIA    addr.IA
+		DstIA    addr.IA
+		Ups      []*seg.PathSegment
+		Cores    []*seg.PathSegment
+		Downs    []*seg.PathSegment
+		Expected []PathType
+	}{
+		"up-core-down": {
+			SrcIA: xtest.MustParseIA("1-ff00:0:131"),
+			DstIA: xtest.MustParseIA("1-ff00:0:111"),
+			Ups: []*seg.PathSegment{
+				g.Beacon([]common.IFIDType{graph.If_130_A_131_X}),
+			},
+			Cores: []*seg.PathSegment{
+				g.Beacon([]common.IFIDType{graph.If_120_A_130_B}),
+			},
+			Downs: []*seg.PathSegment{
+				g.Beacon([]common.IFIDType{graph.If_120_X_111_B}),
+			},
+			Expected: []PathType{PathTypeCore},
+		},
+		"up only": {
+			SrcIA: xtest.MustParseIA("1-ff00:0:131"),
+			DstIA: xtest.MustParseIA("1-ff00:0:130"),
+			Ups: []*seg.PathSegment{
+				g.Beacon([]common.IFIDType{graph.If_130_A_131_X}),
+			},
+			Expected: []PathType{PathTypeCore},
+		},
+		"shortcut": {
+			SrcIA: xtest.MustParseIA("2-ff00:0:212"),
+			DstIA: xtest.MustParseIA("2-ff00:0:222"),
+			Ups: []*seg.PathSegment{
+				g.Beacon([]common.IFIDType{graph.If_210_X1_211_A, graph.If_211_A1_212_X}),
+			},
+			Downs: []*seg.PathSegment{
+				g.Beacon([]common.IFIDType{graph.If_210_X1_211_A, graph.If_211_A_222_X}),
+			},
+			Expected: []PathType{PathTypeShortcut},
+		},
+		"peering and core": {
+			SrcIA: xtest.MustParseIA("2-ff00:0:212"),
+			DstIA: xtest.MustParseIA("2-ff00:0:222"),
+			Ups: []*seg.PathSegment{
+				g.Beacon([]common.IFIDType{graph.If_210_X1_211_A, graph.If_211_A1_212_X}),
+			},
+			Cores: []*seg.PathSegment{
+				g.Beacon([]common.IFIDType{graph.If_220_X_210_X}),
+			},
+			Downs: []*seg.PathSegment{
+				g.Beacon([]common.IFIDType{graph.If_220_X_221_X, graph.If_221_X_222_X}),
+			},
+			Expected: []PathType{PathTypePeering, PathTypeCore},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			paths := Combine(test.SrcIA, test.DstIA, test.Ups, test.Cores, test.Downs)
+			var types []PathType
+			for _, path := range paths {
+				types = append(types, path.Type)
+			}
+			assert.Equal(t, test.Expected, types)
+		})
+	}
+}
+
+func TestFilterPathTypes(t *testing.T) {
+	core := &Path{Type: PathTypeCore}
+	shortcut := &Path{Type: PathTypeShortcut}
+	peering := &Path{Type: PathTypePeering}
+	paths := []*Path{core, shortcut, peering}
+
+	assert.Equal(t, paths, FilterPathTypes(paths, AllPathTypes))
+	assert.Equal(t, []*Path{core, peering},
+		FilterPathTypes(paths, PathTypeCore|PathTypePeering))
+	assert.Equal(t, []*Path{shortcut}, FilterPathTypes(paths, PathTypeShortcut))
+	assert.Empty(t, FilterPathTypes(paths, 0))
+}
+
+func TestPreferPathTypes(t *testing.T) {
+	core1 := &Path{Type: PathTypeCore, Weight: 1}
+	core2 := &Path{Type: PathTypeCore, Weight: 2}
+	shortcut := &Path{Type: PathTypeShortcut, Weight: 3}
+	peering := &Path{Type: PathTypePeering, Weight: 4}
+
+	paths := []*Path{core1, shortcut, core2, peering}
+	PreferPathTypes(paths, PathTypePeering|PathTypeShortcut)
+	assert.Equal(t, []*Path{shortcut, peering, core1, core2}, paths)
+
+	paths = []*Path{core1, shortcut, core2, peering}
+	PreferPathTypes(paths, PathTypeCore)
+	assert.Equal(t, []*Path{core1, core2, shortcut, peering}, paths)
+}
+
+func TestPathTypeString(t *testing.T) {
+	assert.Equal(t, "core", PathTypeCore.String())
+	assert.Equal(t, "shortcut|peering", (PathTypeShortcut | PathTypePeering).String())
+	assert.Equal(t, "none", PathType(0).String())
+}
@@ -15,12 +15,20 @@
 package idiscovery
 
 import (
+	"encoding/base64"
 	"io"
+	"net"
+	"strconv"
 	"strings"
 	"time"
 
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/config"
+	"github.com/scionproto/scion/go/lib/discovery"
+	"github.com/scionproto/scion/go/lib/scrypto"
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/util"
 )
@@ -40,6 +48,16 @@ var (
 var _ config.Config = (*Config)(nil)
 
 type Config struct {
+	// Instances are the addresses (ip:port) of discovery service instances
+	// that are queried in addition to the instances in the topology. They
+	// are kept even if the fetched topologies do not contain them, such that
+	// the local topology does not need to contain a discovery service, see
+	// FetchStatic.
+	Instances []string
+	// VerifyKey is the base64 encoded Ed25519 public key of the discovery
+	// service. If it is set, only topologies with a valid signature are
+	// accepted.
+	VerifyKey string
 	// Static contains the parameters for fetching the static
 	// topology from the discovery service.
 	Static StaticConfig
@@ -53,10 +71,17 @@ func (cfg *Config) InitDefaults() {
 }
 
 func (cfg *Config) Validate() error {
+	if _, err := cfg.instances(); err != nil {
+		return err
+	}
+	if _, err := cfg.verifier(); err != nil {
+		return err
+	}
 	return config.ValidateAll(&cfg.Static, &cfg.Dynamic)
 }
 
 func (cfg *Config) Sample(dst io.Writer, path config.Path, ctx config.CtxMap) {
+	config.WriteString(dst, discoverySample)
 	config.WriteSample(dst, path, ctx, &cfg.Static, &cfg.Dynamic)
 }
 
@@ -64,6 +89,48 @@ func (cfg *Config) ConfigName() string {
 	return "discovery"
 }
 
+// instances parses the configured discovery service instances. The instances
+// are keyed by their address.
+func (cfg *Config) instances() (map[string]*addr.AppAddr, error) {
+	instances := make(map[string]*addr.AppAddr, len(cfg.Instances))
+	for _, s := range cfg.Instances {
+		host, port, err := net.SplitHostPort(s)
+		if err != nil {
+			return nil, common.NewBasicError("Invalid instance", err, "addr", s)
+		}
+		ip := net.ParseIP(host)
+		if ip == nil {
+			return nil, common.NewBasicError("Invalid instance IP", nil, "addr", s)
+		}
+		p, err := strconv.ParseUint(port, 10, 16)
+		if err != nil {
+			return nil, common.NewBasicError("Invalid instance port", err, "addr", s)
+		}
+		instances[s] = &addr.AppAddr{
+			L3: addr.HostFromIP(ip),
+			L4: addr.NewL4TCPInfo(uint16(p)),
+		}
+	}
+	return instances, nil
+}
+
+// verifier returns the verifier for the configured key, or nil if no key is
+// configured.
+func (cfg *Config) verifier() (discovery.Verifier, error) {
+	if cfg.VerifyKey == "" {
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(cfg.VerifyKey)
+	if err != nil {
+		return nil, common.NewBasicError("Unable to decode VerifyKey", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, common.NewBasicError("Invalid VerifyKey size", nil,
+			"expected", ed25519.PublicKeySize, "actual", len(key))
+	}
+	return discovery.KeyVerifier{Key: key, Algo: scrypto.Ed25519}, nil
+}
+
 var _ config.Config = (*StaticConfig)(nil)
 
 type StaticConfig struct {
@@ -104,6 +171,9 @@ type FetchConfig struct {
 	Timeout util.DurWrap
 	// Https indicates whether https must be used to fetch the topology.
 	Https bool
+	// Incremental indicates whether only the changes since the last fetched
+	// topology are requested, instead of the full topology.
+	Incremental bool
 	// Connect contains the parameters for the initial connection
 	// check to the discovery service.
 	Connect ConnectParams
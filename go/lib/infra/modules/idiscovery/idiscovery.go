@@ -32,16 +32,28 @@
 //
 // A periodic.Task with a customized TopoHandler can be created with
 // NewFetcher, when the client package requires more control.
+//
+// Besides the discovery service instances in the topology, the runners query
+// the configured Instances, failing over to the next instance if a fetch
+// fails. If a VerifyKey is configured, only topologies with a valid signature
+// are accepted.
+//
+// Hosts without a topology file can load their initial topology with
+// LoadTopology, which fetches it from the configured Instances and writes it
+// to the topology file.
 package idiscovery
 
 import (
 	"context"
 	"net/http"
+	"os"
 	"sync"
 	"time"
 
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/discovery"
+	"github.com/scionproto/scion/go/lib/discovery/discoverypool"
 	"github.com/scionproto/scion/go/lib/discovery/topofetcher"
 	"github.com/scionproto/scion/go/lib/fatal"
 	"github.com/scionproto/scion/go/lib/infra/modules/idiscovery/internal/metrics"
@@ -105,7 +117,10 @@ func StartRunners(cfg Config, file discovery.File, handlers TopoHandlers,
 	client *http.Client) (Runners, error) {
 
 	cfg.InitDefaults()
-	var err error
+	opts, err := newFetcherOpts(cfg)
+	if err != nil {
+		return Runners{}, err
+	}
 	r := Runners{}
 	if cfg.Static.Enable {
 		r.Static, err = startPeriodicFetcher(
@@ -118,6 +133,7 @@ func StartRunners(cfg Config, file discovery.File, handlers TopoHandlers,
 			},
 			cfg.Static.Filename,
 			client,
+			opts,
 		)
 		if err != nil {
 			return Runners{}, err
@@ -134,6 +150,7 @@ func StartRunners(cfg Config, file discovery.File, handlers TopoHandlers,
 			},
 			"",
 			client,
+			opts,
 		)
 		if err != nil {
 			r.Kill()
@@ -190,7 +207,7 @@ func (r *Runner) Kill() {
 // If during the InitialPeriod no topology is successfully fetched, the process takes
 // the configured FailAction.
 func startPeriodicFetcher(cfg FetchConfig, handler TopoHandler, params discovery.FetchParams,
-	filename string, client *http.Client) (*Runner, error) {
+	filename string, client *http.Client, opts fetcherOpts) (*Runner, error) {
 
 	fatal.Check()
 	r := &Runner{
@@ -200,7 +217,8 @@ func startPeriodicFetcher(cfg FetchConfig, handler TopoHandler, params discovery
 			c: make(chan struct{}),
 		},
 	}
-	fetcher, err := NewFetcher(r.handler, params, filename, client)
+	opts.incremental = cfg.Incremental
+	fetcher, err := newFetcher(r.handler, params, filename, client, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -293,6 +311,12 @@ type task struct {
 func NewFetcher(handler TopoHandler, params discovery.FetchParams,
 	filename string, client *http.Client) (*task, error) {
 
+	return newFetcher(handler, params, filename, client, fetcherOpts{})
+}
+
+func newFetcher(handler TopoHandler, params discovery.FetchParams,
+	filename string, client *http.Client, opts fetcherOpts) (*task, error) {
+
 	if handler == nil {
 		return nil, serrors.New("handler must not be nil")
 	}
@@ -301,22 +325,139 @@ func NewFetcher(handler TopoHandler, params discovery.FetchParams,
 		handler:  handler,
 		filename: filename,
 	}
+	clbks := topofetcher.Callbacks{
+		Error: t.handleErr,
+		Raw:   t.handleRaw,
+	}
 	var err error
-	t.fetcher, err = topofetcher.New(
-		itopo.Get().DS,
-		params,
-		topofetcher.Callbacks{
-			Error: t.handleErr,
-			Raw:   t.handleRaw,
-		},
-		client,
-	)
+	if len(opts.instances) == 0 {
+		t.fetcher, err = topofetcher.New(itopo.Get().DS, params, clbks, client)
+	} else {
+		t.fetcher, err = newStaticFetcher(opts.instances, params, clbks, client)
+		if err == nil {
+			err = t.fetcher.UpdateInstances(itopo.Get().DS)
+		}
+	}
 	if err != nil {
 		return nil, common.NewBasicError("Unable to initialize fetcher", err)
 	}
+	t.fetcher.Verifier = opts.verifier
+	t.fetcher.Incremental = opts.incremental
 	return t, nil
 }
 
+// FetchStatic fetches the static topology from the discovery service
+// instances configured in cfg, failing over to the next instance if a fetch
+// fails. It allows hosts without a topology file to fetch their initial
+// topology, e.g., to initialize itopo with it.
+func FetchStatic(ctx context.Context, cfg Config, file discovery.File,
+	client *http.Client) (*topology.Topo, common.RawBytes, error) {
+
+	opts, err := newFetcherOpts(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	var topo *topology.Topo
+	var raw common.RawBytes
+	var fetchErr error
+	clbks := topofetcher.Callbacks{
+		Raw: func(_ context.Context, r common.RawBytes, t *topology.Topo) {
+			topo, raw = t, r
+		},
+		Error: func(_ context.Context, err error) {
+			fetchErr = err
+		},
+	}
+	params := discovery.FetchParams{
+		Mode:  discovery.Static,
+		File:  file,
+		Https: cfg.Static.Https,
+	}
+	fetcher, err := newStaticFetcher(opts.instances, params, clbks, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	fetcher.Verifier = opts.verifier
+	fetcher.Run(ctx)
+	if fetchErr != nil {
+		return nil, nil, fetchErr
+	}
+	if topo == nil {
+		return nil, nil, serrors.New("No topology fetched")
+	}
+	return topo, raw, nil
+}
+
+// LoadTopology loads the static topology from the file at path. If the file
+// does not exist and Instances are configured, the topology is fetched with
+// FetchStatic instead. The fetch is retried every second for the static
+// InitialPeriod. The fetched topology is written to path, such that topology
+// reloads and restarts find it.
+func LoadTopology(path string, cfg Config, file discovery.File,
+	client *http.Client) (*topology.Topo, error) {
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) || len(cfg.Instances) == 0 {
+		return topology.LoadFromFile(path)
+	}
+	cfg.InitDefaults()
+	log.Info("[discovery] Topology file not found, fetching topology", "path", path)
+	deadline := time.Now().Add(cfg.Static.Connect.InitialPeriod.Duration)
+	for {
+		ctx, cancelF := context.WithTimeout(context.Background(), cfg.Static.Timeout.Duration)
+		topo, raw, err := FetchStatic(ctx, cfg, file, client)
+		cancelF()
+		if err == nil {
+			if err := util.WriteFile(path, raw, 0644); err != nil {
+				return nil, common.NewBasicError("Unable to write fetched topology", err,
+					"path", path)
+			}
+			return topo, nil
+		}
+		if time.Now().Add(time.Second).After(deadline) {
+			return nil, common.NewBasicError("Unable to fetch topology", err,
+				"period", cfg.Static.Connect.InitialPeriod)
+		}
+		log.Info("[discovery] Unable to fetch topology, retrying", "err", err)
+		time.Sleep(time.Second)
+	}
+}
+
+// fetcherOpts contains the options for the fetchers that are derived from the
+// configuration.
+type fetcherOpts struct {
+	instances   map[string]*addr.AppAddr
+	verifier    discovery.Verifier
+	incremental bool
+}
+
+func newFetcherOpts(cfg Config) (fetcherOpts, error) {
+	instances, err := cfg.instances()
+	if err != nil {
+		return fetcherOpts{}, err
+	}
+	verifier, err := cfg.verifier()
+	if err != nil {
+		return fetcherOpts{}, err
+	}
+	return fetcherOpts{instances: instances, verifier: verifier}, nil
+}
+
+// newStaticFetcher creates a fetcher for the configured instances.
+func newStaticFetcher(instances map[string]*addr.AppAddr, params discovery.FetchParams,
+	clbks topofetcher.Callbacks, client *http.Client) (*topofetcher.Fetcher, error) {
+
+	pool, err := discoverypool.NewStatic(instances)
+	if err != nil {
+		return nil, err
+	}
+	return &topofetcher.Fetcher{
+		Pool:      pool,
+		Params:    params,
+		Callbacks: clbks,
+		Client:    client,
+	}, nil
+}
+
 func (t *task) Name() string {
 	return "discovery"
 }
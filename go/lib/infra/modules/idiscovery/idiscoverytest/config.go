@@ -23,14 +23,20 @@ import (
 )
 
 func InitTestConfig(cfg *idiscovery.Config) {
+	cfg.Instances = []string{"127.0.0.1:30084"}
+	cfg.VerifyKey = "key"
 	cfg.Dynamic.Enable = true
 	cfg.Dynamic.Https = true
+	cfg.Dynamic.Incremental = true
 	cfg.Static.Enable = true
 	cfg.Static.Https = true
+	cfg.Static.Incremental = true
 	cfg.Static.Filename = "topology.json"
 }
 
 func CheckTestConfig(t *testing.T, cfg *idiscovery.Config) {
+	assert.Empty(t, cfg.Instances)
+	assert.Empty(t, cfg.VerifyKey)
 	t.Run("static", func(t *testing.T) {
 		checkCommon(t, cfg.Static.FetchConfig)
 		assert.Empty(t, cfg.Static.Filename)
@@ -44,6 +50,7 @@ func checkCommon(t *testing.T, cfg idiscovery.FetchConfig) {
 	assert.False(t, cfg.Enable)
 	assert.Equal(t, idiscovery.DefaultFetchTimeout, cfg.Timeout.Duration)
 	assert.False(t, cfg.Https)
+	assert.False(t, cfg.Incremental)
 	assert.Equal(t, idiscovery.DefaultInitialConnectPeriod, cfg.Connect.InitialPeriod.Duration)
 	assert.Equal(t, idiscovery.FailActionContinue, cfg.Connect.FailAction)
 }
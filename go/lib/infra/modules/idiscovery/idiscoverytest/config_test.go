@@ -16,6 +16,7 @@ package idiscoverytest
 
 import (
 	"bytes"
+	"encoding/base64"
 	"testing"
 
 	"github.com/BurntSushi/toml"
@@ -34,3 +35,46 @@ func TestConfigSample(t *testing.T) {
 	assert.Empty(t, meta.Undecoded())
 	CheckTestConfig(t, &cfg)
 }
+
+func TestConfigValidate(t *testing.T) {
+	tests := map[string]struct {
+		Instances []string
+		VerifyKey string
+		Valid     bool
+	}{
+		"defaults": {
+			Valid: true,
+		},
+		"instances and key": {
+			Instances: []string{"127.0.0.1:30084", "[::1]:30084"},
+			VerifyKey: base64.StdEncoding.EncodeToString(make([]byte, 32)),
+			Valid:     true,
+		},
+		"instance without port": {
+			Instances: []string{"127.0.0.1"},
+		},
+		"instance with hostname": {
+			Instances: []string{"ds.example.com:30084"},
+		},
+		"key not base64": {
+			VerifyKey: "%",
+		},
+		"key with wrong size": {
+			VerifyKey: base64.StdEncoding.EncodeToString(make([]byte, 16)),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var cfg idiscovery.Config
+			cfg.InitDefaults()
+			cfg.Instances = test.Instances
+			cfg.VerifyKey = test.VerifyKey
+			err := cfg.Validate()
+			if test.Valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -14,6 +14,16 @@
 
 package idiscovery
 
+const discoverySample = `
+# The addresses (ip:port) of discovery service instances that are queried in
+# addition to the instances in the topology. (default [])
+Instances = []
+
+# The base64 encoded Ed25519 public key of the discovery service. If set, only
+# topologies with a valid signature are accepted. (default "")
+VerifyKey = ""
+`
+
 const staticSample = `
 # Enable periodic fetching of the static topology. (default false)
 Enable = false
@@ -27,6 +37,9 @@ Timeout = "1s"
 # Require https connection. (default false)
 Https = false
 
+# Request only the changes since the last fetched topology. (default false)
+Incremental = false
+
 # Filename where the updated static topologies are written. In case of the
 # empty string, the updated topologies are not written. (default "")
 Filename = ""
@@ -44,6 +57,9 @@ Timeout = "1s"
 
 # Require https connection. (default false)
 Https = false
+
+# Request only the changes since the last fetched topology. (default false)
+Incremental = false
 `
 
 const connectSample = `
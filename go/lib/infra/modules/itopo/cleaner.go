@@ -45,7 +45,9 @@ func (c cleaner) Run(ctx context.Context) {
 		log.FromCtx(ctx).Info("[itopo.cleaner] Dropping expired dynamic topology",
 			"ts", st.topo.dynamic.Timestamp, "ttl", st.topo.dynamic.TTL,
 			"expired", st.topo.dynamic.Expiry())
+		old := st.topo.dynamic
 		st.topo.dynamic = nil
+		st.subs.publish(old, st.topo.Get())
 		call(st.clbks.CleanDynamic)
 		metrics.Current.Active().Set(0)
 	}
@@ -133,5 +133,13 @@ Callbacks
 
 The client package can register callbacks to be notified about
 certain events.
+
+Subscriptions
+
+Components that need to know what changed in the active topology can
+Subscribe to typed change events. An event is delivered for every
+interface and service instance that was added, removed or changed. This
+allows, e.g., caches keyed by interface to be updated precisely instead
+of being rebuilt on every update.
 */
 package itopo
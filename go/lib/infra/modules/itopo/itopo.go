@@ -212,12 +212,14 @@ type state struct {
 	topo      topo
 	validator validator
 	clbks     Callbacks
+	subs      *subscribers
 }
 
 func newState(id string, svc proto.ServiceType, clbks Callbacks) *state {
 	s := &state{
 		validator: validatorFactory(id, svc),
 		clbks:     clbks,
+		subs:      newSubscribers(),
 	}
 	return s
 }
@@ -240,7 +242,9 @@ func (s *state) setDynamic(dynamic *topology.Topo) (*topology.Topo, bool, error)
 }
 
 func (s *state) updateDynamic(dynamic *topology.Topo) {
+	old := s.topo.Get()
 	s.topo.dynamic = dynamic
+	s.subs.publish(old, s.topo.Get())
 	cl := metrics.CurrentLabels{Type: metrics.Dynamic}
 	metrics.Current.Active().Set(1)
 	metrics.Current.Timestamp(cl).Set(metrics.Timestamp(dynamic.Timestamp))
@@ -322,6 +326,8 @@ func (s *state) beginSetStatic(static *topology.Topo, allowed bool) (Transaction
 
 // updateStatic updates the static topology, if necessary, and calls the corresponding callbacks.
 func (s *state) updateStatic(static *topology.Topo) {
+	old := s.topo.Get()
+	defer func() { s.subs.publish(old, s.topo.Get()) }()
 	// Drop dynamic topology if necessary.
 	if s.validator.MustDropDynamic(static, s.topo.static) && s.topo.dynamic != nil {
 		s.topo.dynamic = nil
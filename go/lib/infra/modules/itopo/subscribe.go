@@ -0,0 +1,298 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package itopo
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/topology"
+	"github.com/scionproto/scion/go/proto"
+)
+
+// EventType is the type of a topology change event.
+type EventType string
+
+const (
+	// InterfaceAdded indicates that an interface was added.
+	InterfaceAdded EventType = "interface_added"
+	// InterfaceRemoved indicates that an interface was removed.
+	InterfaceRemoved EventType = "interface_removed"
+	// InterfaceChanged indicates that the information of an interface, e.g.,
+	// its addresses, changed.
+	InterfaceChanged EventType = "interface_changed"
+	// ServiceAdded indicates that a service instance was added.
+	ServiceAdded EventType = "service_added"
+	// ServiceRemoved indicates that a service instance was removed.
+	ServiceRemoved EventType = "service_removed"
+	// ServiceChanged indicates that the address of a service instance changed.
+	ServiceChanged EventType = "service_changed"
+)
+
+// subscribedServices are the services whose instances are tracked.
+var subscribedServices = []proto.ServiceType{
+	proto.ServiceType_bs,
+	proto.ServiceType_ps,
+	proto.ServiceType_cs,
+	proto.ServiceType_sb,
+	proto.ServiceType_ds,
+	proto.ServiceType_sig,
+}
+
+// Event describes a change of the active topology.
+type Event struct {
+	Type EventType
+	// IFID is the interface that changed. It is only set for interface
+	// events.
+	IFID common.IFIDType
+	// Service is the service type of the instance that changed. It is only
+	// set for service events.
+	Service proto.ServiceType
+	// Instance is the ID of the instance that changed. It is only set for
+	// service events.
+	Instance string
+	// Topo is the active topology after the change.
+	Topo *topology.Topo
+}
+
+// Subscribe subscribes to the changes of the active topology. The events are
+// delivered in order on the channel returned by Events, until Unsubscribe is
+// called. Events are queued for slow subscribers, such that topology updates
+// never block. Subscribe must be called after Init. Subscribers that need the
+// current state must call Get after subscribing.
+func Subscribe() *Subscription {
+	return st.subs.subscribe()
+}
+
+// Subscription is a subscription to topology changes, see Subscribe.
+type Subscription struct {
+	parent *subscribers
+	events chan Event
+	notify chan struct{}
+	done   chan struct{}
+
+	mtx   sync.Mutex
+	queue []Event
+}
+
+// Events returns the channel on which the events are delivered. The channel
+// is closed after Unsubscribe is called.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Unsubscribe stops the delivery of events. It is safe to call Unsubscribe
+// multiple times.
+func (s *Subscription) Unsubscribe() {
+	if s.parent.remove(s) {
+		close(s.done)
+	}
+}
+
+func (s *Subscription) push(events []Event) {
+	s.mtx.Lock()
+	s.queue = append(s.queue, events...)
+	s.mtx.Unlock()
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// run delivers the queued events until the subscription is cancelled.
+func (s *Subscription) run() {
+	defer close(s.events)
+	for {
+		s.mtx.Lock()
+		events := s.queue
+		s.queue = nil
+		s.mtx.Unlock()
+		for _, e := range events {
+			select {
+			case s.events <- e:
+			case <-s.done:
+				return
+			}
+		}
+		select {
+		case <-s.notify:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// subscribers keeps track of the subscriptions.
+type subscribers struct {
+	mtx  sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+func newSubscribers() *subscribers {
+	return &subscribers{subs: make(map[*Subscription]struct{})}
+}
+
+func (s *subscribers) subscribe() *Subscription {
+	sub := &Subscription{
+		parent: s,
+		events: make(chan Event),
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	s.mtx.Lock()
+	s.subs[sub] = struct{}{}
+	s.mtx.Unlock()
+	go func() {
+		defer log.LogPanicAndExit()
+		sub.run()
+	}()
+	return sub
+}
+
+// remove removes sub and returns whether it was subscribed.
+func (s *subscribers) remove(sub *Subscription) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if _, ok := s.subs[sub]; !ok {
+		return false
+	}
+	delete(s.subs, sub)
+	return true
+}
+
+// publish delivers the changes between the old and new active topology to
+// all subscriptions.
+func (s *subscribers) publish(oldTopo, newTopo *topology.Topo) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if len(s.subs) == 0 {
+		return
+	}
+	events := diff(oldTopo, newTopo)
+	if len(events) == 0 {
+		return
+	}
+	for sub := range s.subs {
+		sub.push(events)
+	}
+}
+
+// diff returns the events that describe the changes from oldTopo to newTopo.
+// Either topology can be nil. Interface events are ordered by interface ID,
+// service events by service type and instance ID.
+func diff(oldTopo, newTopo *topology.Topo) []Event {
+	if oldTopo == newTopo || newTopo == nil {
+		return nil
+	}
+	var events []Event
+	var oldIFs topology.IfInfoMap
+	if oldTopo != nil {
+		oldIFs = oldTopo.IFInfoMap
+	}
+	for _, ifid := range sortedIFIDs(oldIFs, newTopo.IFInfoMap) {
+		oldInfo, inOld := oldIFs[ifid]
+		newInfo, inNew := newTopo.IFInfoMap[ifid]
+		if t, ok := changeType(inOld, inNew, !cmp.Equal(oldInfo, newInfo),
+			InterfaceAdded, InterfaceRemoved, InterfaceChanged); ok {
+			events = append(events, Event{Type: t, IFID: ifid, Topo: newTopo})
+		}
+	}
+	for _, svc := range subscribedServices {
+		oldAddrs, newAddrs := svcAddrs(oldTopo, svc), svcAddrs(newTopo, svc)
+		for _, id := range sortedIDs(oldAddrs, newAddrs) {
+			oldAddr, inOld := oldAddrs[id]
+			newAddr, inNew := newAddrs[id]
+			if t, ok := changeType(inOld, inNew, !cmp.Equal(oldAddr, newAddr),
+				ServiceAdded, ServiceRemoved, ServiceChanged); ok {
+				events = append(events, Event{Type: t, Service: svc, Instance: id,
+					Topo: newTopo})
+			}
+		}
+	}
+	return events
+}
+
+// changeType returns the type of the change of an entry, and false if the
+// entry did not change.
+func changeType(inOld, inNew, changed bool, added, removed,
+	modified EventType) (EventType, bool) {
+
+	switch {
+	case !inOld:
+		return added, true
+	case !inNew:
+		return removed, true
+	case changed:
+		return modified, true
+	}
+	return "", false
+}
+
+func svcAddrs(topo *topology.Topo, svc proto.ServiceType) topology.IDAddrMap {
+	if topo == nil {
+		return nil
+	}
+	switch svc {
+	case proto.ServiceType_bs:
+		return topo.BS
+	case proto.ServiceType_ps:
+		return topo.PS
+	case proto.ServiceType_cs:
+		return topo.CS
+	case proto.ServiceType_sb:
+		return topo.SB
+	case proto.ServiceType_ds:
+		return topo.DS
+	case proto.ServiceType_sig:
+		return topo.SIG
+	}
+	return nil
+}
+
+func sortedIFIDs(a, b topology.IfInfoMap) []common.IFIDType {
+	set := make(map[common.IFIDType]struct{}, len(b))
+	for ifid := range a {
+		set[ifid] = struct{}{}
+	}
+	for ifid := range b {
+		set[ifid] = struct{}{}
+	}
+	ifids := make([]common.IFIDType, 0, len(set))
+	for ifid := range set {
+		ifids = append(ifids, ifid)
+	}
+	sort.Slice(ifids, func(i, j int) bool { return ifids[i] < ifids[j] })
+	return ifids
+}
+
+func sortedIDs(a, b topology.IDAddrMap) []string {
+	set := make(map[string]struct{}, len(b))
+	for id := range a {
+		set[id] = struct{}{}
+	}
+	for id := range b {
+		set[id] = struct{}{}
+	}
+	ids := make([]string, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
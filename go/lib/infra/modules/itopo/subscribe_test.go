@@ -0,0 +1,164 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package itopo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/topology"
+	"github.com/scionproto/scion/go/proto"
+)
+
+func TestDiff(t *testing.T) {
+	type event struct {
+		Type     EventType
+		IFID     common.IFIDType
+		Service  proto.ServiceType
+		Instance string
+	}
+	// Old and New modify the loaded topology. If they are nil, the topology
+	// is used as is.
+	tests := map[string]struct {
+		Old      func(topo *topology.Topo) *topology.Topo
+		New      func(topo *topology.Topo) *topology.Topo
+		Expected []event
+	}{
+		"identical": {},
+		"initial": {
+			Old: func(*topology.Topo) *topology.Topo { return nil },
+			Expected: []event{
+				{Type: InterfaceAdded, IFID: 1},
+				{Type: InterfaceAdded, IFID: 2},
+				{Type: ServiceAdded, Service: proto.ServiceType_cs,
+					Instance: "cs1-ff00:0:311-1"},
+				{Type: ServiceAdded, Service: proto.ServiceType_cs,
+					Instance: "cs1-ff00:0:311-2"},
+			},
+		},
+		"interface removed": {
+			New: func(topo *topology.Topo) *topology.Topo {
+				delete(topo.IFInfoMap, 2)
+				return topo
+			},
+			Expected: []event{{Type: InterfaceRemoved, IFID: 2}},
+		},
+		"interface changed": {
+			New: func(topo *topology.Topo) *topology.Topo {
+				info := topo.IFInfoMap[1]
+				info.MTU = 42
+				topo.IFInfoMap[1] = info
+				return topo
+			},
+			Expected: []event{{Type: InterfaceChanged, IFID: 1}},
+		},
+		"service added and removed": {
+			New: func(topo *topology.Topo) *topology.Topo {
+				topo.CS["cs1-ff00:0:311-3"] = topo.CS["cs1-ff00:0:311-2"]
+				delete(topo.CS, "cs1-ff00:0:311-2")
+				return topo
+			},
+			Expected: []event{
+				{Type: ServiceRemoved, Service: proto.ServiceType_cs,
+					Instance: "cs1-ff00:0:311-2"},
+				{Type: ServiceAdded, Service: proto.ServiceType_cs,
+					Instance: "cs1-ff00:0:311-3"},
+			},
+		},
+		"service changed": {
+			New: func(topo *topology.Topo) *topology.Topo {
+				topo.CS["cs1-ff00:0:311-1"] = topo.CS["cs1-ff00:0:311-2"]
+				return topo
+			},
+			Expected: []event{
+				{Type: ServiceChanged, Service: proto.ServiceType_cs,
+					Instance: "cs1-ff00:0:311-1"},
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			oldTopo, newTopo := loadTopo(fn, t), loadTopo(fn, t)
+			if test.Old != nil {
+				oldTopo = test.Old(oldTopo)
+			}
+			if test.New != nil {
+				newTopo = test.New(newTopo)
+			}
+			var events []event
+			for _, e := range diff(oldTopo, newTopo) {
+				assert.Equal(t, newTopo, e.Topo)
+				events = append(events, event{Type: e.Type, IFID: e.IFID,
+					Service: e.Service, Instance: e.Instance})
+			}
+			assert.Equal(t, test.Expected, events)
+		})
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	s := newState("", proto.ServiceType_unset, Callbacks{})
+	s.topo.static = loadTopo(fn, t)
+	sub := s.subs.subscribe()
+	other := s.subs.subscribe()
+	defer other.Unsubscribe()
+
+	// Publish two updates without reading, updates must not block.
+	first := loadTopo(fn, t)
+	delete(first.IFInfoMap, 2)
+	_, updated, err := s.setStatic(first, true)
+	require.NoError(t, err)
+	require.True(t, updated)
+	second := loadTopo(fn, t)
+	_, updated, err = s.setStatic(second, true)
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	e := nextEvent(t, sub.Events())
+	assert.Equal(t, InterfaceRemoved, e.Type)
+	assert.Equal(t, common.IFIDType(2), e.IFID)
+	assert.Equal(t, first, e.Topo)
+	e = nextEvent(t, sub.Events())
+	assert.Equal(t, InterfaceAdded, e.Type)
+	assert.Equal(t, common.IFIDType(2), e.IFID)
+	assert.Equal(t, second, e.Topo)
+
+	sub.Unsubscribe()
+	sub.Unsubscribe()
+	select {
+	case _, ok := <-sub.Events():
+		assert.False(t, ok, "channel must be closed")
+	case <-time.After(time.Second):
+		t.Fatal("channel not closed after unsubscribe")
+	}
+	// The remaining subscription still receives all events.
+	assert.Equal(t, InterfaceRemoved, nextEvent(t, other.Events()).Type)
+	assert.Equal(t, InterfaceAdded, nextEvent(t, other.Events()).Type)
+}
+
+func nextEvent(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+	return Event{}
+}
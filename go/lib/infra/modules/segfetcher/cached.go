@@ -0,0 +1,125 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segfetcher
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/pathdb"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+// ErrNoCacheResolver indicates that the fetcher can not serve cached
+// segments.
+const ErrNoCacheResolver = "no cache resolver configured"
+
+// CachedSegs returns the segments for the request that are stored locally,
+// without contacting any remote server. Cache entries that should have been
+// refreshed already are used nonetheless. The returned duration is the
+// staleness of the segments, i.e., how long the refresh of the most outdated
+// cache entry is overdue; 0 means the cache is up to date.
+//
+// CachedSegs is intended as fallback if FetchSegs fails, e.g., because the
+// remote servers are unreachable. It is best effort: the returned segments
+// may be incomplete, or empty.
+func (f *Fetcher) CachedSegs(ctx context.Context, req Request) (Segments, time.Duration,
+	error) {
+
+	if f.CacheResolver == nil {
+		return Segments{}, 0, serrors.New(ErrNoCacheResolver)
+	}
+	if f.Validator != nil {
+		if err := f.Validator.Validate(ctx, req); err != nil {
+			return Segments{}, 0, err
+		}
+	}
+	reqSet, err := f.Splitter.Split(ctx, req)
+	if err != nil {
+		return Segments{}, 0, err
+	}
+	db := &staleDB{Read: f.PathDB, now: time.Now()}
+	resolver := f.CacheResolver(db)
+	var segs Segments
+	for i := 0; i < 3; i++ {
+		if segs, reqSet, err = resolver.Resolve(ctx, segs, reqSet); err != nil {
+			return Segments{}, 0, err
+		}
+		if reqSet.IsLoaded() {
+			break
+		}
+		// There is nothing to fetch, load whatever is in the cache.
+		reqSet = markFetched(reqSet)
+	}
+	if !reqSet.IsLoaded() {
+		log.FromCtx(ctx).Debug("Cached segments are incomplete", "req", reqSet)
+	}
+	return segs, db.staleness(), nil
+}
+
+// markFetched marks all requests in state Fetch as fetched, such that the
+// resolver loads them from the DB.
+func markFetched(reqSet RequestSet) RequestSet {
+	if reqSet.Up.State == Fetch {
+		reqSet.Up.State = Fetched
+	}
+	if reqSet.Down.State == Fetch {
+		reqSet.Down.State = Fetched
+	}
+	for i := range reqSet.Cores {
+		if reqSet.Cores[i].State == Fetch {
+			reqSet.Cores[i].State = Fetched
+		}
+	}
+	return reqSet
+}
+
+// staleDB reports all cache entries as up to date, such that the resolver
+// loads them from the DB. It records the earliest next query time that was
+// overdue to compute the staleness of the loaded segments.
+type staleDB struct {
+	pathdb.Read
+	now time.Time
+
+	mtx     sync.Mutex
+	overdue time.Time
+}
+
+func (db *staleDB) GetNextQuery(ctx context.Context, src, dst addr.IA,
+	policy pathdb.PolicyHash) (time.Time, error) {
+
+	nq, err := db.Read.GetNextQuery(ctx, src, dst, policy)
+	if err != nil {
+		return nq, err
+	}
+	db.mtx.Lock()
+	defer db.mtx.Unlock()
+	if !nq.IsZero() && nq.Before(db.now) && (db.overdue.IsZero() || nq.Before(db.overdue)) {
+		db.overdue = nq
+	}
+	return db.now.Add(time.Hour), nil
+}
+
+func (db *staleDB) staleness() time.Duration {
+	db.mtx.Lock()
+	defer db.mtx.Unlock()
+	if db.overdue.IsZero() {
+		return 0
+	}
+	return db.now.Sub(db.overdue)
+}
@@ -0,0 +1,87 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segfetcher_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/infra/modules/segfetcher"
+	"github.com/scionproto/scion/go/lib/pathdb"
+	"github.com/scionproto/scion/go/lib/revcache/mock_revcache"
+)
+
+func TestCachedSegs(t *testing.T) {
+	rootCtrl := gomock.NewController(t)
+	defer rootCtrl.Finish()
+	tg := newTestGraph(rootCtrl)
+	reqSet := segfetcher.RequestSet{
+		Up: segfetcher.Request{Src: non_core_111, Dst: core_130},
+	}
+
+	tests := map[string]struct {
+		NextQuery         time.Time
+		ExpectedStaleness time.Duration
+	}{
+		"up to date": {
+			NextQuery: time.Now().Add(time.Minute),
+		},
+		"stale": {
+			NextQuery:         time.Now().Add(-10 * time.Minute),
+			ExpectedStaleness: 10 * time.Minute,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := NewTestFetcher(ctrl)
+			revCache := mock_revcache.NewMockRevCache(ctrl)
+			revCache.EXPECT().Get(gomock.Any(), gomock.Any()).AnyTimes()
+			fetcher := f.Fetcher()
+			fetcher.CacheResolver = func(db pathdb.Read) segfetcher.Resolver {
+				return segfetcher.NewResolver(db, revCache, false)
+			}
+
+			f.Validator.EXPECT().Validate(gomock.Any(), gomock.Any())
+			f.Splitter.EXPECT().Split(gomock.Any(), gomock.Any()).Return(reqSet, nil)
+			f.PathDB.EXPECT().GetNextQuery(gomock.Any(), non_core_111, core_130,
+				gomock.Any()).Return(test.NextQuery, nil)
+			f.PathDB.EXPECT().Get(gomock.Any(), gomock.Any()).
+				Return(resultsFromSegs(tg.seg130_111), nil)
+			// Remote servers must not be contacted.
+			f.Requester.EXPECT().Request(gomock.Any(), gomock.Any()).Times(0)
+
+			segs, staleness, err := fetcher.CachedSegs(context.Background(),
+				segfetcher.Request{Src: non_core_111, Dst: core_130})
+			require.NoError(t, err)
+			assert.Equal(t, segfetcher.Segments{Up: seg.Segments{tg.seg130_111}}, segs)
+			assert.InDelta(t, test.ExpectedStaleness.Seconds(), staleness.Seconds(), 1)
+		})
+	}
+	t.Run("no cache resolver", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		_, _, err := NewTestFetcher(ctrl).Fetcher().CachedSegs(context.Background(),
+			segfetcher.Request{Src: non_core_111, Dst: core_130})
+		assert.Error(t, err)
+	})
+}
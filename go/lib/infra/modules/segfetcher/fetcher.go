@@ -24,6 +24,7 @@ import (
 	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
 	"github.com/scionproto/scion/go/lib/infra"
 	"github.com/scionproto/scion/go/lib/infra/modules/seghandler"
+	"github.com/scionproto/scion/go/lib/infra/modules/segverifier"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/pathdb"
 	"github.com/scionproto/scion/go/lib/revcache"
@@ -66,23 +67,43 @@ type FetcherConfig struct {
 	// SciondMode enables sciond mode, this means it uses the local CS to fetch
 	// crypto material and considers revocations in the path lookup.
 	SciondMode bool
+	// KeepRevoked makes the fetcher return segments that are affected by
+	// active revocations instead of filtering them out.
+	KeepRevoked bool
+	// VerificationCache, if set, caches the results of segment and
+	// revocation verifications across replies.
+	VerificationCache *segverifier.Cache
+	// VerificationPool, if set, verifies the segments of all replies on a
+	// bounded number of workers.
+	VerificationPool *segverifier.Pool
 }
 
 // New creates a new fetcher from the configuration.
 func (cfg FetcherConfig) New() *Fetcher {
+	resolver := NewResolver(cfg.PathDB, cfg.RevCache, !cfg.SciondMode)
+	resolver.KeepRevoked = cfg.KeepRevoked
 	return &Fetcher{
 		Validator: cfg.Validator,
 		Splitter:  cfg.Splitter,
-		Resolver:  NewResolver(cfg.PathDB, cfg.RevCache, !cfg.SciondMode),
+		Resolver:  resolver,
 		Requester: &DefaultRequester{API: cfg.RequestAPI, DstProvider: cfg.DstProvider},
 		ReplyHandler: &seghandler.Handler{
-			Verifier: &seghandler.DefaultVerifier{Verifier: cfg.VerificationFactory.NewVerifier()},
-			Storage:  &seghandler.DefaultStorage{PathDB: cfg.PathDB, RevCache: cfg.RevCache},
+			Verifier: &seghandler.DefaultVerifier{
+				Verifier: cfg.VerificationFactory.NewVerifier(),
+				Cache:    cfg.VerificationCache,
+				Pool:     cfg.VerificationPool,
+			},
+			Storage: &seghandler.DefaultStorage{PathDB: cfg.PathDB, RevCache: cfg.RevCache},
 		},
 		PathDB:                cfg.PathDB,
 		QueryInterval:         cfg.QueryInterval,
 		NextQueryCleaner:      NextQueryCleaner{PathDB: cfg.PathDB},
 		CryptoLookupAtLocalCS: cfg.SciondMode,
+		CacheResolver: func(db pathdb.Read) Resolver {
+			cacheResolver := NewResolver(db, cfg.RevCache, !cfg.SciondMode)
+			cacheResolver.KeepRevoked = cfg.KeepRevoked
+			return cacheResolver
+		},
 	}
 }
 
@@ -97,6 +118,9 @@ type Fetcher struct {
 	QueryInterval         time.Duration
 	NextQueryCleaner      NextQueryCleaner
 	CryptoLookupAtLocalCS bool
+	// CacheResolver creates the resolver that CachedSegs uses to load
+	// segments from db. If it is nil, CachedSegs returns an error.
+	CacheResolver func(db pathdb.Read) Resolver
 }
 
 // FetchSegs fetches the required segments to build a path between src and dst
@@ -80,7 +80,7 @@ func (r *DefaultRequester) fetchReqs(ctx context.Context, reqs Requests) <-chan
 		go func() {
 			defer log.LogPanicAndExit()
 			defer wg.Done()
-			reply, err := r.API.GetSegs(ctx, req.ToSegReq(), dst, messenger.NextId())
+			reply, err := r.API.GetSegs(ctx, req.ToSegReq(), dst, messenger.NextIdFromCtx(ctx))
 			replies <- ReplyOrErr{Req: req, Reply: reply, Peer: dst, Err: err}
 		}()
 	}
@@ -54,6 +54,9 @@ type DefaultResolver struct {
 	DB         pathdb.Read
 	RevCache   revcache.RevCache
 	IgnoreRevs bool
+	// KeepRevoked makes the resolver return segments that are affected by
+	// active revocations instead of filtering them out.
+	KeepRevoked bool
 }
 
 // Resolve resolves a request set. It returns the segments that are locally
@@ -102,11 +105,11 @@ func (r *DefaultResolver) Resolve(ctx context.Context, segs Segments,
 		if err != nil {
 			return segs, req, err
 		}
-		coreSegs, filtered, err := r.resultsToSegs(ctx, coreRes)
+		coreSegs, allRevoked, err := r.resultsToSegs(ctx, coreRes)
 		if err != nil {
 			return segs, req, err
 		}
-		if len(coreSegs) == 0 && filtered > 0 && coreReq.State != Fetched {
+		if allRevoked && coreReq.State != Fetched {
 			req.Cores[i].State = Fetch
 		} else {
 			req.Cores[i].State = Loaded
@@ -156,9 +159,9 @@ func (r *DefaultResolver) resolveSegment(ctx context.Context,
 	if err != nil {
 		return nil, req, err
 	}
-	segs, filtered, err := r.resultsToSegs(ctx, res)
+	segs, allRevoked, err := r.resultsToSegs(ctx, res)
 	// because of revocations our cache is empty, so refetch
-	if len(segs) == 0 && filtered > 0 {
+	if allRevoked {
 		if req.State == Unresolved {
 			req.State = Fetch
 		} else {
@@ -297,15 +300,26 @@ func (r *DefaultResolver) resolveCores(ctx context.Context,
 	return req.Cores, nil
 }
 
+// resultsToSegs returns the segments of the results. Unless KeepRevoked is
+// set, revoked segments are filtered out. The returned bool indicates whether
+// there were results and all of them are revoked.
 func (r *DefaultResolver) resultsToSegs(ctx context.Context,
-	results query.Results) (seg.Segments, int, error) {
+	results query.Results) (seg.Segments, bool, error) {
 
 	segs := results.Segs()
-	filtered, err := segs.FilterSegsErr(func(ps *seg.PathSegment) (bool, error) {
+	usable := segs
+	if r.KeepRevoked {
+		usable = append(seg.Segments(nil), segs...)
+	}
+	filtered, err := usable.FilterSegsErr(func(ps *seg.PathSegment) (bool, error) {
 		return revcache.NoRevokedHopIntf(ctx, r.RevCache, ps)
 	})
 	if err != nil {
-		return nil, 0, err
+		return nil, false, err
+	}
+	allRevoked := filtered > 0 && len(usable) == 0
+	if r.KeepRevoked {
+		return segs, allRevoked, nil
 	}
-	return segs, filtered, nil
+	return usable, allRevoked, nil
 }
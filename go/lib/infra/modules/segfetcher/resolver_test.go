@@ -80,6 +80,7 @@ type resolverTest struct {
 	ExpectRevcache   func(t *testing.T, revCache *mock_revcache.MockRevCache)
 	ExpectedSegments segfetcher.Segments
 	ExpectedReqSet   segfetcher.RequestSet
+	KeepRevoked      bool
 }
 
 func (rt resolverTest) run(t *testing.T) {
@@ -95,6 +96,7 @@ func (rt resolverTest) run(t *testing.T) {
 		revCache.EXPECT().Get(gomock.Any(), gomock.Any()).AnyTimes()
 	}
 	resolver := segfetcher.NewResolver(db, revCache, false)
+	resolver.KeepRevoked = rt.KeepRevoked
 	segs, remainingReqs, err := resolver.Resolve(context.Background(), rt.Segs, rt.Req)
 	assert.Equal(t, rt.ExpectedSegments, segs)
 	assert.Equal(t, rt.ExpectedReqSet, remainingReqs)
@@ -744,6 +746,63 @@ func TestResolverWithRevocations(t *testing.T) {
 				},
 			},
 		},
+		"Up wildcard (cached) all revoked, keep revoked": {
+			Req: segfetcher.RequestSet{
+				Up: segfetcher.Request{Src: non_core_111, Dst: isd1},
+			},
+			ExpectCalls: func(db *mock_pathdb.MockPathDB) {
+				db.EXPECT().GetNextQuery(gomock.Any(), gomock.Eq(non_core_111),
+					gomock.Eq(isd1), gomock.Any()).Return(futureT, nil)
+				db.EXPECT().Get(gomock.Any(), matchers.EqParams(&query.Params{
+					SegTypes: []proto.PathSegType{proto.PathSegType_up},
+					StartsAt: []addr.IA{isd1}, EndsAt: []addr.IA{non_core_111},
+				})).Return(resultsFromSegs(tg.seg120_111, tg.seg130_111), nil)
+			},
+			ExpectRevcache: func(t *testing.T, revCache *mock_revcache.MockRevCache) {
+				key111_120 := revcache.Key{IA: non_core_111, IfId: graph.If_111_B_120_X}
+				key111_130 := revcache.Key{IA: non_core_111, IfId: graph.If_111_A_130_B}
+				revoke(t, revCache, key111_120)
+				revoke(t, revCache, key111_130)
+				revCache.EXPECT().Get(gomock.Any(), gomock.Any()).AnyTimes()
+			},
+			KeepRevoked: true,
+			// The segments are still returned, but a refetch is triggered.
+			ExpectedSegments: segfetcher.Segments{
+				Up: seg.Segments{tg.seg120_111, tg.seg130_111},
+			},
+			ExpectedReqSet: segfetcher.RequestSet{
+				Up: segfetcher.Request{Src: non_core_111, Dst: isd1, State: segfetcher.Fetch},
+			},
+		},
+		"Core (cached) with revocations, keep revoked": {
+			Req: segfetcher.RequestSet{
+				Cores: []segfetcher.Request{
+					{Src: core_210, Dst: core_130},
+				},
+			},
+			ExpectCalls: func(db *mock_pathdb.MockPathDB) {
+				db.EXPECT().GetNextQuery(gomock.Any(), gomock.Eq(core_210),
+					gomock.Eq(core_130), gomock.Any()).Return(futureT, nil)
+				db.EXPECT().Get(gomock.Any(), matchers.EqParams(&query.Params{
+					SegTypes: []proto.PathSegType{proto.PathSegType_core},
+					StartsAt: []addr.IA{core_130}, EndsAt: []addr.IA{core_210},
+				})).Return(resultsFromSegs(tg.seg210_130, tg.seg210_130_2), nil)
+			},
+			ExpectRevcache: func(t *testing.T, revCache *mock_revcache.MockRevCache) {
+				key110 := revcache.Key{IA: core_110, IfId: graph.If_110_X_130_A}
+				revoke(t, revCache, key110)
+				revCache.EXPECT().Get(gomock.Any(), gomock.Any()).AnyTimes()
+			},
+			KeepRevoked: true,
+			ExpectedSegments: segfetcher.Segments{
+				Core: seg.Segments{tg.seg210_130, tg.seg210_130_2},
+			},
+			ExpectedReqSet: segfetcher.RequestSet{
+				Cores: []segfetcher.Request{
+					{Src: core_210, Dst: core_130, State: segfetcher.Loaded},
+				},
+			},
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, test.run)
@@ -14,7 +14,49 @@
 
 package seghandler
 
-import "github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+import (
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+)
+
+// SegResultType classifies the outcome of handling a single segment.
+type SegResultType int
+
+const (
+	// SegStored indicates that the segment was inserted or updated in the DB.
+	SegStored SegResultType = iota
+	// SegFiltered indicates that the segment was verified, but not written to
+	// the DB, because it is already stored.
+	SegFiltered
+	// SegVerifyFailed indicates that the segment failed verification.
+	SegVerifyFailed
+	// SegStoreFailed indicates that storing the segment failed.
+	SegStoreFailed
+)
+
+func (t SegResultType) String() string {
+	switch t {
+	case SegStored:
+		return "stored"
+	case SegFiltered:
+		return "filtered"
+	case SegVerifyFailed:
+		return "verify_failed"
+	case SegStoreFailed:
+		return "store_failed"
+	default:
+		return "unknown"
+	}
+}
+
+// SegResult is the result of handling a single segment.
+type SegResult struct {
+	Seg  *seg.Meta
+	Type SegResultType
+	// Err is the reason for the failure, if Type is SegVerifyFailed or
+	// SegStoreFailed.
+	Err error
+}
 
 // Stats provides statistics about handling segments.
 type Stats struct {
@@ -31,6 +73,7 @@ type Stats struct {
 func (s *Stats) addStoredSegs(segs SegStats) {
 	s.SegDB.InsertedSegs = append(s.SegDB.InsertedSegs, segs.InsertedSegs...)
 	s.SegDB.UpdatedSegs = append(s.SegDB.UpdatedSegs, segs.UpdatedSegs...)
+	s.SegDB.IgnoredDuplicates = append(s.SegDB.IgnoredDuplicates, segs.IgnoredDuplicates...)
 }
 
 // ProcessedResult is the result of handling a segment reply.
@@ -41,6 +84,7 @@ type ProcessedResult struct {
 	revs       []*path_mgmt.SignedRevInfo
 	err        error
 	verifyErrs []error
+	segResults []SegResult
 }
 
 // EarlyTriggerProcessed returns a channel that will contain the number of
@@ -70,3 +114,20 @@ func (r *ProcessedResult) Err() error {
 func (r *ProcessedResult) VerificationErrors() []error {
 	return r.verifyErrs
 }
+
+// SegResults returns the result for every handled segment. This should only be
+// accessed after FullReplyProcessed channel has been closed.
+func (r *ProcessedResult) SegResults() []SegResult {
+	return r.segResults
+}
+
+// SegResultCount returns the number of segments with the given result type.
+func (r *ProcessedResult) SegResultCount(t SegResultType) int {
+	var n int
+	for _, res := range r.segResults {
+		if res.Type == t {
+			n++
+		}
+	}
+	return n
+}
@@ -38,7 +38,9 @@ type Segments struct {
 
 // Handler is a handler that verifies and stores seg replies. The handler
 // supports an early trigger, so that a partial result can be stored early to
-// possibly reply to clients earlier.
+// possibly reply to clients earlier. The verified revocations are stored
+// before the segments, such that segments are never stored without the
+// revocations that came with them.
 type Handler struct {
 	Verifier Verifier
 	Storage  Storage
@@ -88,13 +90,15 @@ func (h *Handler) verifyAndStore(ctx context.Context,
 		case <-earlyTrigger:
 			// Reduce u since this does not process an additional unit.
 			u--
-			verifyErrs, err := h.storeResults(ctx, verifiedUnits, hpGroupID, &result.stats)
+			segResults, verifyErrs, err := h.storeResults(ctx, verifiedUnits, hpGroupID,
+				&result.stats)
 			allVerifyErrs = append(allVerifyErrs, verifyErrs...)
 			result.early <- result.stats.SegDB.Total()
 			// TODO(lukedirtwalker): log early store failure
 			if err == nil {
 				// clear already processed units
 				verifiedUnits = verifiedUnits[:0]
+				result.segResults = append(result.segResults, segResults...)
 			} else {
 				// reset stats
 				result.stats = Stats{}
@@ -103,21 +107,33 @@ func (h *Handler) verifyAndStore(ctx context.Context,
 			earlyTrigger = nil
 		}
 	}
-	verifyErrs, err := h.storeResults(ctx, verifiedUnits, hpGroupID, &result.stats)
+	segResults, verifyErrs, err := h.storeResults(ctx, verifiedUnits, hpGroupID,
+		&result.stats)
 	result.verifyErrs = append(allVerifyErrs, verifyErrs...)
+	result.segResults = append(result.segResults, segResults...)
 	result.err = err
 }
 
+// storeResults stores the verified segments and revocations of the units. It
+// returns the per-segment results, the verification errors, and the storage
+// error, if any.
 func (h *Handler) storeResults(ctx context.Context, verifiedUnits []segverifier.UnitResult,
-	hpGroupID hiddenpath.GroupId, stats *Stats) ([]error, error) {
+	hpGroupID hiddenpath.GroupId, stats *Stats) ([]SegResult, []error, error) {
 
 	var verifyErrs []error
+	segResults := make([]SegResult, 0, len(verifiedUnits))
 	segs := make([]*SegWithHP, 0, len(verifiedUnits))
 	var revs []*path_mgmt.SignedRevInfo
 	for _, unit := range verifiedUnits {
 		if err := unit.SegError(); err != nil {
-			verifyErrs = append(verifyErrs, common.NewBasicError("Failed to verify seg", err,
-				"seg", unit.Unit.SegMeta.Segment))
+			verifyErr := common.NewBasicError("Failed to verify seg", err,
+				"seg", unit.Unit.SegMeta.Segment)
+			verifyErrs = append(verifyErrs, verifyErr)
+			segResults = append(segResults, SegResult{
+				Seg:  unit.Unit.SegMeta,
+				Type: SegVerifyFailed,
+				Err:  verifyErr,
+			})
 		} else {
 			segs = append(segs, &SegWithHP{
 				Seg:     unit.Unit.SegMeta,
@@ -138,20 +154,68 @@ func (h *Handler) storeResults(ctx context.Context, verifiedUnits []segverifier.
 			}
 		}
 	}
+	// The path DB and the revocation cache are separate stores that cannot be
+	// written atomically. The revocations are stored first: If storing the
+	// segments fails, or the process crashes in between, the revocations are
+	// kept without their segments, which is safe. Segments are never stored
+	// without the revocations that came with them.
+	if len(revs) > 0 {
+		if err := h.Storage.StoreRevs(ctx, revs); err != nil {
+			segResults = append(segResults, storeFailedSegResults(segs, err)...)
+			return segResults, verifyErrs, err
+		}
+		stats.StoredRevs = append(stats.StoredRevs, revs...)
+	}
 	if len(segs) > 0 {
 		storeSegStats, err := h.Storage.StoreSegs(ctx, segs)
 		if err != nil {
-			return verifyErrs, err
+			segResults = append(segResults, storeFailedSegResults(segs, err)...)
+			return segResults, verifyErrs, err
 		}
 		stats.addStoredSegs(storeSegStats)
+		segResults = append(segResults, storedSegResults(segs, storeSegStats)...)
 	}
-	if len(revs) > 0 {
-		if err := h.Storage.StoreRevs(ctx, revs); err != nil {
-			return verifyErrs, err
+	return segResults, verifyErrs, nil
+}
+
+// storeFailedSegResults reports all segments as failed to store with err.
+func storeFailedSegResults(segs []*SegWithHP, err error) []SegResult {
+	results := make([]SegResult, 0, len(segs))
+	for _, s := range segs {
+		results = append(results, SegResult{Seg: s.Seg, Type: SegStoreFailed, Err: err})
+	}
+	return results
+}
+
+// storedSegResults classifies the segments that were successfully passed to
+// the storage. Segments that were neither inserted nor updated are reported
+// as filtered.
+func storedSegResults(segs []*SegWithHP, stats SegStats) []SegResult {
+	results := make([]SegResult, 0, len(segs))
+	if stats.Total() >= len(segs) {
+		// All segments were written, no need to match the IDs.
+		for _, s := range segs {
+			results = append(results, SegResult{Seg: s.Seg, Type: SegStored})
 		}
-		stats.StoredRevs = append(stats.StoredRevs, revs...)
+		return results
+	}
+	written := make(map[string]struct{}, stats.Total())
+	for _, id := range stats.InsertedSegs {
+		written[id] = struct{}{}
+	}
+	for _, id := range stats.UpdatedSegs {
+		written[id] = struct{}{}
+	}
+	for _, s := range segs {
+		resType := SegFiltered
+		if s.Seg.Segment != nil {
+			if _, ok := written[s.Seg.Segment.GetLoggingID()]; ok {
+				resType = SegStored
+			}
+		}
+		results = append(results, SegResult{Seg: s.Seg, Type: resType})
 	}
-	return verifyErrs, nil
+	return results
 }
 
 func convertHPGroupID(id hiddenpath.GroupId) []*query.HPCfgID {
@@ -22,6 +22,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
 	"github.com/scionproto/scion/go/lib/ctrl/seg"
 	"github.com/scionproto/scion/go/lib/infra"
@@ -31,6 +32,7 @@ import (
 	"github.com/scionproto/scion/go/lib/mocks/net/mock_net"
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/lib/xtest/graph"
 	"github.com/scionproto/scion/go/proto"
 )
 
@@ -123,6 +125,10 @@ func TestReplyHandlerErrors(t *testing.T) {
 	xtest.AssertReadReturnsBefore(t, r.FullReplyProcessed(), time.Second/2)
 	assert.NoError(t, r.Err())
 	assert.Len(t, r.VerificationErrors(), len(verifyErrs))
+	assert.Equal(t, 3, r.SegResultCount(seghandler.SegVerifyFailed))
+	for _, res := range r.SegResults() {
+		assert.Error(t, res.Err)
+	}
 	stats := r.Stats()
 	assert.Zero(t, len(stats.VerifiedSegs))
 	assert.Zero(t, stats.SegDB.Total())
@@ -195,6 +201,11 @@ func TestReplyHandlerNoErrors(t *testing.T) {
 	stats := r.Stats()
 	assert.Equal(t, 3, len(stats.VerifiedSegs))
 	assert.Equal(t, 3, stats.SegDB.Total())
+	assert.Equal(t, []seghandler.SegResult{
+		{Seg: seg1.Seg, Type: seghandler.SegStored},
+		{Seg: seg2.Seg, Type: seghandler.SegStored},
+		{Seg: seg3.Seg, Type: seghandler.SegStored},
+	}, r.SegResults())
 	expectedRevs := []*path_mgmt.SignedRevInfo{rev1}
 	assert.ElementsMatch(t, expectedRevs, stats.VerifiedRevs)
 	assert.ElementsMatch(t, expectedRevs, stats.StoredRevs)
@@ -311,6 +322,8 @@ func TestReplyHandlerEarlyTriggerStorageError(t *testing.T) {
 	stats := r.Stats()
 	assert.Equal(t, 2, len(stats.VerifiedSegs))
 	assert.Equal(t, 2, stats.SegDB.Total())
+	assert.Equal(t, 2, len(r.SegResults()))
+	assert.Equal(t, 2, r.SegResultCount(seghandler.SegStored))
 	expectedRevs := []*path_mgmt.SignedRevInfo{rev1}
 	assert.ElementsMatch(t, expectedRevs, stats.VerifiedRevs)
 	assert.ElementsMatch(t, expectedRevs, stats.StoredRevs)
@@ -360,6 +373,10 @@ func TestReplyHandlerStorageError(t *testing.T) {
 	xtest.AssertReadReturnsBefore(t, r.FullReplyProcessed(), time.Second/2)
 	assert.Error(t, r.Err())
 	assert.Nil(t, r.VerificationErrors())
+	assert.Equal(t, []seghandler.SegResult{
+		{Seg: seg1.Seg, Type: seghandler.SegStoreFailed, Err: storageErr},
+		{Seg: seg2.Seg, Type: seghandler.SegStoreFailed, Err: storageErr},
+	}, r.SegResults())
 	stats := r.Stats()
 	assert.Equal(t, 2, len(stats.VerifiedSegs))
 	assert.Zero(t, stats.SegDB.Total())
@@ -367,6 +384,109 @@ func TestReplyHandlerStorageError(t *testing.T) {
 	assert.Empty(t, stats.StoredRevs)
 }
 
+// TestReplyHandlerStorageErrorKeepsRevs tests that the revocations are stored
+// before the segments, and kept if storing the segments fails.
+func TestReplyHandlerStorageErrorKeepsRevs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx, cancelF := context.WithTimeout(context.Background(), TestTimeout)
+	defer cancelF()
+
+	seg1 := &seghandler.SegWithHP{
+		Seg: &seg.Meta{Type: proto.PathSegType_down},
+	}
+	rev1, err := path_mgmt.NewSignedRevInfo(&path_mgmt.RevInfo{}, infra.NullSigner)
+	xtest.FailOnErr(t, err)
+	segs := seghandler.Segments{}
+	earlyTrigger := make(chan struct{})
+	verified := make(chan segverifier.UnitResult)
+
+	storage := mock_seghandler.NewMockStorage(ctrl)
+	verifier := mock_seghandler.NewMockVerifier(ctrl)
+	verifier.EXPECT().Verify(ctx, segs, gomock.Any()).Return(verified, 1)
+	handler := seghandler.Handler{
+		Storage:  storage,
+		Verifier: verifier,
+	}
+	storageErr := serrors.New("Test error")
+	gomock.InOrder(
+		storage.EXPECT().StoreRevs(gomock.Any(),
+			gomock.Eq([]*path_mgmt.SignedRevInfo{rev1})),
+		storage.EXPECT().StoreSegs(gomock.Any(),
+			gomock.Eq([]*seghandler.SegWithHP{seg1})).
+			Return(seghandler.SegStats{}, storageErr),
+	)
+
+	close(earlyTrigger)
+	r := handler.Handle(ctx, segs, nil, earlyTrigger)
+	AssertRead(t, r.EarlyTriggerProcessed(), 0, time.Second/2)
+	verified <- segverifier.UnitResult{
+		Unit: &segverifier.Unit{
+			SegMeta:   seg1.Seg,
+			SRevInfos: []*path_mgmt.SignedRevInfo{rev1},
+		},
+	}
+	xtest.AssertReadReturnsBefore(t, r.FullReplyProcessed(), time.Second/2)
+	assert.Error(t, r.Err())
+	assert.Equal(t, []seghandler.SegResult{
+		{Seg: seg1.Seg, Type: seghandler.SegStoreFailed, Err: storageErr},
+	}, r.SegResults())
+	stats := r.Stats()
+	assert.Zero(t, stats.SegDB.Total())
+	expectedRevs := []*path_mgmt.SignedRevInfo{rev1}
+	assert.ElementsMatch(t, expectedRevs, stats.StoredRevs)
+}
+
+// TestReplyHandlerFilteredSegments tests that segments that are not written
+// by the storage are reported as filtered.
+func TestReplyHandlerFilteredSegments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx, cancelF := context.WithTimeout(context.Background(), TestTimeout)
+	defer cancelF()
+
+	tg := graph.NewDefaultGraph(ctrl)
+	seg1 := &seghandler.SegWithHP{
+		Seg: &seg.Meta{
+			Type:    proto.PathSegType_down,
+			Segment: tg.Beacon([]common.IFIDType{graph.If_110_X_120_A, graph.If_120_A_130_B}),
+		},
+	}
+	seg2 := &seghandler.SegWithHP{
+		Seg: &seg.Meta{
+			Type:    proto.PathSegType_down,
+			Segment: tg.Beacon([]common.IFIDType{graph.If_110_X_130_A}),
+		},
+	}
+	segs := seghandler.Segments{}
+	verified := make(chan segverifier.UnitResult, 2)
+
+	storage := mock_seghandler.NewMockStorage(ctrl)
+	verifier := mock_seghandler.NewMockVerifier(ctrl)
+	verifier.EXPECT().Verify(ctx, segs, gomock.Any()).Return(verified, 2)
+	handler := seghandler.Handler{
+		Storage:  storage,
+		Verifier: verifier,
+	}
+	storage.EXPECT().StoreSegs(gomock.Any(),
+		gomock.Eq([]*seghandler.SegWithHP{seg1, seg2})).
+		Return(seghandler.SegStats{
+			InsertedSegs:      []string{seg1.Seg.Segment.GetLoggingID()},
+			IgnoredDuplicates: []string{seg2.Seg.Segment.GetLoggingID()},
+		}, nil)
+
+	verified <- segverifier.UnitResult{Unit: &segverifier.Unit{SegMeta: seg1.Seg}}
+	verified <- segverifier.UnitResult{Unit: &segverifier.Unit{SegMeta: seg2.Seg}}
+	r := handler.Handle(ctx, segs, nil, nil)
+	xtest.AssertReadReturnsBefore(t, r.FullReplyProcessed(), time.Second/2)
+	assert.NoError(t, r.Err())
+	assert.Equal(t, []seghandler.SegResult{
+		{Seg: seg1.Seg, Type: seghandler.SegStored},
+		{Seg: seg2.Seg, Type: seghandler.SegFiltered},
+	}, r.SegResults())
+	assert.Equal(t, 1, r.SegResultCount(seghandler.SegFiltered))
+}
+
 func AssertChanEmpty(t *testing.T, ch <-chan struct{}) {
 	t.Helper()
 	select {
@@ -19,11 +19,13 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
 	"github.com/scionproto/scion/go/lib/ctrl/seg"
 	"github.com/scionproto/scion/go/lib/hiddenpath"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/pathdb"
+	"github.com/scionproto/scion/go/lib/pathdb/query"
 	"github.com/scionproto/scion/go/lib/revcache"
 )
 
@@ -43,6 +45,10 @@ type SegStats struct {
 	InsertedSegs []string
 	// UpdatedSegs are the log IDs of the updated segments.
 	UpdatedSegs []string
+	// IgnoredDuplicates are the log IDs of the segments that were not written
+	// to the DB, because they were already stored with an equal or later
+	// expiration time.
+	IgnoredDuplicates []string
 }
 
 // Total returns the total amount of updates and inserts.
@@ -58,6 +64,9 @@ func (s *SegStats) Log(logger log.Logger) {
 	if len(s.UpdatedSegs) > 0 {
 		logger.Debug("Segments updated in DB", "segments", s.UpdatedSegs)
 	}
+	if len(s.IgnoredDuplicates) > 0 {
+		logger.Debug("Segments already in DB, ignored", "segments", s.IgnoredDuplicates)
+	}
 }
 
 // Storage is used to store segments and revocations.
@@ -74,7 +83,19 @@ type DefaultStorage struct {
 }
 
 // StoreSegs stores the given segments in the pathdb in a transaction.
+// Segments that are already stored with an equal or later expiration time, with
+// the same type and hidden path group, are not written again. They are
+// reported as ignored duplicates in the stats. If all segments are
+// duplicates, no transaction is started.
 func (s *DefaultStorage) StoreSegs(ctx context.Context, segs []*SegWithHP) (SegStats, error) {
+	segStats := SegStats{}
+	if len(segs) > 0 {
+		segs, segStats.IgnoredDuplicates = s.filterDuplicates(ctx, segs)
+		if len(segs) == 0 {
+			segStats.Log(log.FromCtx(ctx))
+			return segStats, nil
+		}
+	}
 	tx, err := s.PathDB.BeginTransaction(ctx, nil)
 	if err != nil {
 		return SegStats{}, err
@@ -84,7 +105,6 @@ func (s *DefaultStorage) StoreSegs(ctx context.Context, segs []*SegWithHP) (SegS
 	sort.Slice(segs, func(i, j int) bool {
 		return segs[i].Seg.Segment.GetLoggingID() < segs[j].Seg.Segment.GetLoggingID()
 	})
-	segStats := SegStats{}
 	for _, seg := range segs {
 		stats, err := tx.InsertWithHPCfgIDs(ctx, seg.Seg, convertHPGroupID(seg.HPGroup))
 		if err != nil {
@@ -103,6 +123,65 @@ func (s *DefaultStorage) StoreSegs(ctx context.Context, segs []*SegWithHP) (SegS
 	return segStats, nil
 }
 
+// filterDuplicates removes the segments that are already stored in the DB with
+// an equal or later expiration time. It returns the remaining segments and the
+// log IDs of the removed ones. If the DB cannot be queried, all segments are
+// returned.
+func (s *DefaultStorage) filterDuplicates(ctx context.Context,
+	segs []*SegWithHP) ([]*SegWithHP, []string) {
+
+	ids := make([]common.RawBytes, 0, len(segs))
+	for _, seg := range segs {
+		id, err := seg.Seg.Segment.ID()
+		if err != nil {
+			return segs, nil
+		}
+		ids = append(ids, id)
+	}
+	results, err := s.PathDB.Get(ctx, &query.Params{SegIDs: ids})
+	if err != nil {
+		log.FromCtx(ctx).Debug("Unable to look up stored segments, storing all", "err", err)
+		return segs, nil
+	}
+	stored := make(map[string][]*query.Result, len(results))
+	for _, res := range results {
+		id, err := res.Seg.ID()
+		if err != nil {
+			continue
+		}
+		stored[string(id)] = append(stored[string(id)], res)
+	}
+	var remaining []*SegWithHP
+	var duplicates []string
+	for i, seg := range segs {
+		if isStored(seg, stored[string(ids[i])]) {
+			duplicates = append(duplicates, seg.Seg.Segment.GetLoggingID())
+			continue
+		}
+		remaining = append(remaining, seg)
+	}
+	return remaining, duplicates
+}
+
+// isStored returns whether one of the stored results already contains seg,
+// i.e., it expires no earlier than seg, and has the same type and hidden path
+// group.
+func isStored(seg *SegWithHP, stored []*query.Result) bool {
+	hpCfgID := convertHPGroupID(seg.HPGroup)[0]
+	expiry := seg.Seg.Segment.MaxExpiry()
+	for _, res := range stored {
+		if res.Type != seg.Seg.Type || res.Seg.MaxExpiry().Before(expiry) {
+			continue
+		}
+		for _, id := range res.HpCfgIDs {
+			if id.Equal(hpCfgID) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // StoreRevs stores the given revocations in the revocation cache.
 func (s *DefaultStorage) StoreRevs(ctx context.Context,
 	revs []*path_mgmt.SignedRevInfo) error {
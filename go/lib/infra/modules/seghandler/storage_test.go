@@ -27,6 +27,7 @@ import (
 	"github.com/scionproto/scion/go/lib/infra/modules/seghandler"
 	"github.com/scionproto/scion/go/lib/pathdb"
 	"github.com/scionproto/scion/go/lib/pathdb/mock_pathdb"
+	"github.com/scionproto/scion/go/lib/pathdb/query"
 	"github.com/scionproto/scion/go/lib/xtest/graph"
 	"github.com/scionproto/scion/go/proto"
 )
@@ -91,6 +92,7 @@ func TestDefaultStorageStoreSegs(t *testing.T) {
 				pathDB := mock_pathdb.NewMockPathDB(ctrl)
 				tx := mock_pathdb.NewMockTransaction(ctrl)
 				gomock.InOrder(
+					pathDB.EXPECT().Get(gomock.Any(), gomock.Any()),
 					pathDB.EXPECT().BeginTransaction(gomock.Any(), gomock.Any()).
 						Return(tx, nil),
 					tx.EXPECT().InsertWithHPCfgIDs(gomock.Any(),
@@ -110,6 +112,77 @@ func TestDefaultStorageStoreSegs(t *testing.T) {
 				UpdatedSegs:  []string{seg110To130Short.GetLoggingID()},
 			},
 		},
+		"Duplicates ignored": {
+			Segs: []*seghandler.SegWithHP{
+				{Seg: seg.NewMeta(seg110To130, proto.PathSegType_core)},
+				{Seg: seg.NewMeta(seg110To130Short, proto.PathSegType_core)},
+			},
+			PathDB: func(ctrl *gomock.Controller) pathdb.PathDB {
+				pathDB := mock_pathdb.NewMockPathDB(ctrl)
+				tx := mock_pathdb.NewMockTransaction(ctrl)
+				gomock.InOrder(
+					pathDB.EXPECT().Get(gomock.Any(), gomock.Any()).Return(query.Results{
+						storedResult(seg110To130, proto.PathSegType_core),
+						// Stored with a different type, thus not a duplicate.
+						storedResult(seg110To130Short, proto.PathSegType_up),
+					}, nil),
+					pathDB.EXPECT().BeginTransaction(gomock.Any(), gomock.Any()).
+						Return(tx, nil),
+					tx.EXPECT().InsertWithHPCfgIDs(gomock.Any(),
+						seg.NewMeta(seg110To130Short, proto.PathSegType_core), gomock.Any()).
+						Return(pathdb.InsertStats{Updated: 1}, nil),
+					tx.EXPECT().Commit(),
+					tx.EXPECT().Rollback(),
+				)
+				return pathDB
+			},
+			ErrorAssertion: assert.NoError,
+			ExpectedStats: seghandler.SegStats{
+				UpdatedSegs:       []string{seg110To130Short.GetLoggingID()},
+				IgnoredDuplicates: []string{seg110To130.GetLoggingID()},
+			},
+		},
+		"Only duplicates": {
+			Segs: []*seghandler.SegWithHP{
+				{Seg: seg.NewMeta(seg110To130, proto.PathSegType_core)},
+			},
+			PathDB: func(ctrl *gomock.Controller) pathdb.PathDB {
+				pathDB := mock_pathdb.NewMockPathDB(ctrl)
+				pathDB.EXPECT().Get(gomock.Any(), gomock.Any()).Return(query.Results{
+					storedResult(seg110To130, proto.PathSegType_core),
+				}, nil)
+				return pathDB
+			},
+			ErrorAssertion: assert.NoError,
+			ExpectedStats: seghandler.SegStats{
+				IgnoredDuplicates: []string{seg110To130.GetLoggingID()},
+			},
+		},
+		"Lookup error stores all": {
+			Segs: []*seghandler.SegWithHP{
+				{Seg: seg.NewMeta(seg110To130, proto.PathSegType_core)},
+			},
+			PathDB: func(ctrl *gomock.Controller) pathdb.PathDB {
+				pathDB := mock_pathdb.NewMockPathDB(ctrl)
+				tx := mock_pathdb.NewMockTransaction(ctrl)
+				gomock.InOrder(
+					pathDB.EXPECT().Get(gomock.Any(), gomock.Any()).
+						Return(nil, errors.New("test err")),
+					pathDB.EXPECT().BeginTransaction(gomock.Any(), gomock.Any()).
+						Return(tx, nil),
+					tx.EXPECT().InsertWithHPCfgIDs(gomock.Any(),
+						seg.NewMeta(seg110To130, proto.PathSegType_core), gomock.Any()).
+						Return(pathdb.InsertStats{Inserted: 1}, nil),
+					tx.EXPECT().Commit(),
+					tx.EXPECT().Rollback(),
+				)
+				return pathDB
+			},
+			ErrorAssertion: assert.NoError,
+			ExpectedStats: seghandler.SegStats{
+				InsertedSegs: []string{seg110To130.GetLoggingID()},
+			},
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -124,3 +197,11 @@ func TestDefaultStorageStoreSegs(t *testing.T) {
 		})
 	}
 }
+
+func storedResult(ps *seg.PathSegment, segType proto.PathSegType) *query.Result {
+	return &query.Result{
+		Seg:      ps,
+		Type:     segType,
+		HpCfgIDs: []*query.HPCfgID{&query.NullHpCfgID},
+	}
+}
@@ -31,11 +31,22 @@ type Verifier interface {
 // the Verifier interface.
 type DefaultVerifier struct {
 	Verifier infra.Verifier
+	// Cache, if set, caches successful verifications, such that segments and
+	// revocations that were verified recently are not verified again.
+	Cache *segverifier.Cache
+	// Pool, if set, verifies the segments on its bounded number of workers.
+	// Otherwise, new goroutines are spawned for every reply.
+	Pool *segverifier.Pool
 }
 
 // Verify calls segverifier for the given reply.
 func (v *DefaultVerifier) Verify(ctx context.Context, recs Segments,
 	server net.Addr) (chan segverifier.UnitResult, int) {
 
-	return segverifier.StartVerification(ctx, v.Verifier, server, recs.Segs, recs.SRevInfos)
+	if v.Pool != nil {
+		return v.Pool.StartVerification(ctx, v.Verifier, v.Cache, server,
+			recs.Segs, recs.SRevInfos)
+	}
+	return segverifier.StartCachedVerification(ctx, v.Verifier, v.Cache, server,
+		recs.Segs, recs.SRevInfos)
 }
@@ -0,0 +1,215 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segverifier
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/infra/modules/segverifier/internal/metrics"
+	"github.com/scionproto/scion/go/proto"
+)
+
+const (
+	// DefaultCacheSize is the default number of verification results a cache
+	// holds.
+	DefaultCacheSize = 10000
+	// DefaultCacheTTL is the default time a verification result is cached.
+	DefaultCacheTTL = 5 * time.Minute
+)
+
+// Cache remembers the segments and revocations that were recently verified
+// successfully, such that receiving them again, e.g., from many peers, does
+// not require verifying the signatures again. Entries are keyed by a hash
+// over the signed content including the signatures, thus a modified object
+// never matches a cached entry. Failed verifications are not cached.
+//
+// The cache holds at most a fixed number of entries, and evicts the least
+// recently used entry if it is full. Entries expire after a fixed time, such
+// that changes to the trust material, e.g., expired certificates, are taken
+// into account eventually.
+//
+// A nil cache is valid and caches nothing.
+type Cache struct {
+	size int
+	ttl  time.Duration
+
+	mu sync.Mutex
+	// order contains the keys, the most recently used key first.
+	order   *list.List
+	entries map[cacheKey]*list.Element
+}
+
+type cacheKey [sha256.Size]byte
+
+type cacheEntry struct {
+	key     cacheKey
+	expires time.Time
+}
+
+// NewCache creates a cache that holds up to size verification results for the
+// duration ttl. Non-positive values are replaced by DefaultCacheSize and
+// DefaultCacheTTL respectively.
+func NewCache(size int, ttl time.Duration) *Cache {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &Cache{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[cacheKey]*list.Element),
+	}
+}
+
+// Len returns the number of cached verification results, including expired
+// ones that were not evicted yet.
+func (c *Cache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *Cache) containsSegment(segment *seg.PathSegment) bool {
+	return c.contains(segmentKey(segment), metrics.Segment)
+}
+
+func (c *Cache) addSegment(segment *seg.PathSegment) {
+	c.add(segmentKey(segment))
+}
+
+func (c *Cache) containsRevInfo(sRevInfo *path_mgmt.SignedRevInfo) bool {
+	return c.contains(revInfoKey(sRevInfo), metrics.Revocation)
+}
+
+func (c *Cache) addRevInfo(sRevInfo *path_mgmt.SignedRevInfo) {
+	c.add(revInfoKey(sRevInfo))
+}
+
+func (c *Cache) contains(key cacheKey, objType string) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	l := metrics.LookupLabels{Type: objType, Result: metrics.Miss}
+	defer func() { metrics.Cache.Lookups(l).Inc() }()
+	elem, ok := c.entries[key]
+	if !ok {
+		return false
+	}
+	if time.Now().After(elem.Value.(*cacheEntry).expires) {
+		c.remove(elem)
+		return false
+	}
+	c.order.MoveToFront(elem)
+	l.Result = metrics.Hit
+	return true
+}
+
+func (c *Cache) add(key cacheKey) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expires := time.Now().Add(c.ttl)
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*cacheEntry).expires = expires
+		c.order.MoveToFront(elem)
+		return
+	}
+	for c.order.Len() >= c.size {
+		c.remove(c.order.Back())
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, expires: expires})
+	metrics.Cache.Entries().Inc()
+}
+
+func (c *Cache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry).key)
+	metrics.Cache.Entries().Dec()
+}
+
+// segmentKey hashes the signed data and all signed AS entries of the segment.
+func segmentKey(segment *seg.PathSegment) cacheKey {
+	h := newKeyHash(metrics.Segment)
+	h.write(segment.RawSData)
+	for _, entry := range segment.RawASEntries {
+		h.write(entry.Blob)
+		h.writeSign(entry.Sign)
+	}
+	return h.key()
+}
+
+// revInfoKey hashes the revocation and its signature.
+func revInfoKey(sRevInfo *path_mgmt.SignedRevInfo) cacheKey {
+	h := newKeyHash(metrics.Revocation)
+	h.write(sRevInfo.Blob)
+	h.writeSign(sRevInfo.Sign)
+	return h.key()
+}
+
+// keyHash computes cache keys. Every field is prefixed with its length, such
+// that moving bytes from one field to an adjacent one results in a different
+// key.
+type keyHash struct {
+	hash.Hash
+}
+
+func newKeyHash(objType string) keyHash {
+	h := keyHash{Hash: sha256.New()}
+	h.write([]byte(objType))
+	return h
+}
+
+func (h keyHash) write(b []byte) {
+	var l [4]byte
+	binary.BigEndian.PutUint32(l[:], uint32(len(b)))
+	h.Write(l[:])
+	h.Write(b)
+}
+
+func (h keyHash) writeSign(sign *proto.SignS) {
+	if sign == nil {
+		h.write(nil)
+		return
+	}
+	h.write([]byte(sign.Type.String()))
+	h.write(sign.Src)
+	h.write(sign.Signature)
+	var ts [4]byte
+	binary.BigEndian.PutUint32(ts[:], sign.Timestamp)
+	h.write(ts[:])
+}
+
+func (h keyHash) key() cacheKey {
+	var key cacheKey
+	copy(key[:], h.Sum(nil))
+	return key
+}
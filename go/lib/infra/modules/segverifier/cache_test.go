@@ -0,0 +1,141 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segverifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/infra/mock_infra"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/proto"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("nil cache caches nothing", func(t *testing.T) {
+		var cache *Cache
+		rev := testRevInfo(t, 1)
+		cache.addRevInfo(rev)
+		assert.False(t, cache.containsRevInfo(rev))
+		assert.Equal(t, 0, cache.Len())
+	})
+	t.Run("added objects are cached", func(t *testing.T) {
+		cache := NewCache(10, time.Minute)
+		rev := testRevInfo(t, 1)
+		segment := testSegment(1)
+		assert.False(t, cache.containsRevInfo(rev))
+		assert.False(t, cache.containsSegment(segment))
+		cache.addRevInfo(rev)
+		cache.addSegment(segment)
+		assert.True(t, cache.containsRevInfo(rev))
+		assert.True(t, cache.containsSegment(segment))
+		assert.False(t, cache.containsRevInfo(testRevInfo(t, 2)))
+		assert.False(t, cache.containsSegment(testSegment(2)))
+		assert.Equal(t, 2, cache.Len())
+	})
+	t.Run("signature is part of the key", func(t *testing.T) {
+		cache := NewCache(10, time.Minute)
+		rev := testRevInfo(t, 1)
+		cache.addRevInfo(rev)
+		forged := &path_mgmt.SignedRevInfo{Blob: rev.Blob, Sign: rev.Sign.Copy()}
+		forged.Sign.Signature = common.RawBytes("forged")
+		assert.False(t, cache.containsRevInfo(forged))
+		// Moving bytes between adjacent fields changes the key.
+		shifted := &path_mgmt.SignedRevInfo{Blob: rev.Blob, Sign: rev.Sign.Copy()}
+		shifted.Sign.Src = append(shifted.Sign.Src, shifted.Sign.Signature[0])
+		shifted.Sign.Signature = shifted.Sign.Signature[1:]
+		assert.False(t, cache.containsRevInfo(shifted))
+	})
+	t.Run("entries expire", func(t *testing.T) {
+		cache := NewCache(10, 10*time.Millisecond)
+		rev := testRevInfo(t, 1)
+		cache.addRevInfo(rev)
+		time.Sleep(20 * time.Millisecond)
+		assert.False(t, cache.containsRevInfo(rev))
+		assert.Equal(t, 0, cache.Len())
+	})
+	t.Run("least recently used entry is evicted", func(t *testing.T) {
+		cache := NewCache(2, time.Minute)
+		rev1, rev2, rev3 := testRevInfo(t, 1), testRevInfo(t, 2), testRevInfo(t, 3)
+		cache.addRevInfo(rev1)
+		cache.addRevInfo(rev2)
+		// Using rev1 makes rev2 the least recently used entry.
+		assert.True(t, cache.containsRevInfo(rev1))
+		cache.addRevInfo(rev3)
+		assert.Equal(t, 2, cache.Len())
+		assert.True(t, cache.containsRevInfo(rev1))
+		assert.False(t, cache.containsRevInfo(rev2))
+		assert.True(t, cache.containsRevInfo(rev3))
+	})
+}
+
+func TestVerifyRevInfoCached(t *testing.T) {
+	mctrl := gomock.NewController(t)
+	defer mctrl.Finish()
+	verifier := mock_infra.NewMockVerifier(mctrl)
+	verifier.EXPECT().WithServer(gomock.Any()).Return(verifier).AnyTimes()
+
+	cache := NewCache(10, time.Minute)
+	rev := testRevInfo(t, 1)
+	ch := make(chan ElemResult, 3)
+	// Failed verifications are not cached.
+	verifier.EXPECT().Verify(gomock.Any(), rev.Blob, rev.Sign).Return(common.NewBasicError(
+		"invalid", nil))
+	verifyRevInfo(context.Background(), verifier, cache, nil, 0, rev, ch)
+	assert.Error(t, (<-ch).Error)
+	// Successful verifications are cached, and the revocation is only
+	// verified once.
+	verifier.EXPECT().Verify(gomock.Any(), rev.Blob, rev.Sign)
+	verifyRevInfo(context.Background(), verifier, cache, nil, 0, rev, ch)
+	assert.NoError(t, (<-ch).Error)
+	verifyRevInfo(context.Background(), verifier, cache, nil, 0, rev, ch)
+	assert.NoError(t, (<-ch).Error)
+}
+
+func testRevInfo(t *testing.T, ifID common.IFIDType) *path_mgmt.SignedRevInfo {
+	raw, err := (&path_mgmt.RevInfo{
+		IfID:     ifID,
+		RawIsdas: xtest.MustParseIA("1-ff00:0:110").IAInt(),
+	}).Pack()
+	require.NoError(t, err)
+	return &path_mgmt.SignedRevInfo{
+		Blob: raw,
+		Sign: &proto.SignS{
+			Type:      proto.SignType_ed25519,
+			Src:       common.RawBytes("DEFAULT: IA: 1-ff00:0:110 CHAIN: 1 TRC: 1"),
+			Signature: common.RawBytes("signature"),
+		},
+	}
+}
+
+func testSegment(id byte) *seg.PathSegment {
+	return &seg.PathSegment{
+		RawSData: common.RawBytes{id},
+		RawASEntries: []*proto.SignedBlobS{
+			{
+				Blob: common.RawBytes{id, 1},
+				Sign: &proto.SignS{Signature: common.RawBytes("signature")},
+			},
+		},
+	}
+}
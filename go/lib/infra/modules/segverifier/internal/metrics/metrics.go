@@ -0,0 +1,116 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics provides the metrics of the segment verifier.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/scionproto/scion/go/lib/prom"
+)
+
+// Namespace is the prometheus namespace.
+const Namespace = "segverifier"
+
+// Object types.
+const (
+	Segment    = "segment"
+	Revocation = "revocation"
+)
+
+// Cache lookup results.
+const (
+	Hit  = "hit"
+	Miss = "miss"
+)
+
+// Cache exposes the verification cache metrics.
+var Cache = newCache()
+
+// Pool exposes the verification worker pool metrics.
+var Pool = newPool()
+
+// LookupLabels defines the labels of verification cache lookups.
+type LookupLabels struct {
+	// Type is the type of the verified object, Segment or Revocation.
+	Type   string
+	Result string
+}
+
+// Labels returns the list of labels.
+func (l LookupLabels) Labels() []string {
+	return []string{"type", prom.LabelResult}
+}
+
+// Values returns the label values in the order defined by Labels.
+func (l LookupLabels) Values() []string {
+	return []string{l.Type, l.Result}
+}
+
+type cache struct {
+	lookups *prometheus.CounterVec
+	entries prometheus.Gauge
+}
+
+func newCache() cache {
+	return cache{
+		lookups: prom.NewCounterVec(Namespace, "cache", "lookups_total",
+			"Total number of verification cache lookups.", LookupLabels{}.Labels()),
+		entries: prom.NewGauge(Namespace, "cache", "entries",
+			"Number of verification results in the cache."),
+	}
+}
+
+// Lookups returns the counter for cache lookups with the given labels.
+func (c cache) Lookups(l LookupLabels) prometheus.Counter {
+	return c.lookups.WithLabelValues(l.Values()...)
+}
+
+// Entries returns the gauge for the number of cached verification results.
+func (c cache) Entries() prometheus.Gauge {
+	return c.entries
+}
+
+type pool struct {
+	queued   prometheus.Gauge
+	busy     prometheus.Gauge
+	canceled prometheus.Counter
+}
+
+func newPool() pool {
+	return pool{
+		queued: prom.NewGauge(Namespace, "pool", "queued_units",
+			"Number of verification units waiting for a worker."),
+		busy: prom.NewGauge(Namespace, "pool", "busy_workers",
+			"Number of workers currently verifying a unit."),
+		canceled: prom.NewCounter(Namespace, "pool", "canceled_units_total",
+			"Total number of verification units dropped because the request was canceled."),
+	}
+}
+
+// Queued returns the gauge for the number of queued verification units.
+func (p pool) Queued() prometheus.Gauge {
+	return p.queued
+}
+
+// Busy returns the gauge for the number of busy workers.
+func (p pool) Busy() prometheus.Gauge {
+	return p.busy
+}
+
+// Canceled returns the counter for canceled verification units.
+func (p pool) Canceled() prometheus.Counter {
+	return p.canceled
+}
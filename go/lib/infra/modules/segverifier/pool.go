@@ -0,0 +1,152 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segverifier
+
+import (
+	"context"
+	"net"
+	"runtime"
+
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/infra"
+	"github.com/scionproto/scion/go/lib/infra/modules/segverifier/internal/metrics"
+	"github.com/scionproto/scion/go/lib/log"
+)
+
+// DefaultPoolQueueSize is the default number of units that can be queued for
+// verification in a pool before submitters are blocked.
+const DefaultPoolQueueSize = 256
+
+// Pool verifies units on a fixed number of worker goroutines, such that a
+// burst of replies cannot use an unbounded amount of CPU. Each worker verifies
+// the segment and the revocations of a unit sequentially.
+//
+// Units are queued until a worker is available. If the queue is full,
+// StartVerification blocks until there is space, which pushes back on the
+// callers. Units whose context is done before they are verified are not
+// verified, and all their elements fail with the context error instead.
+//
+// The workers run for the lifetime of the process.
+type Pool struct {
+	tasks chan poolTask
+}
+
+type poolTask struct {
+	ctx      context.Context
+	unit     *Unit
+	verifier infra.Verifier
+	cache    *Cache
+	server   net.Addr
+	results  chan UnitResult
+}
+
+// NewPool starts a pool with the given number of workers and queue size. If
+// workers is not positive, one worker per CPU is started. If queueSize is not
+// positive, DefaultPoolQueueSize is used.
+func NewPool(workers, queueSize int) *Pool {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if queueSize <= 0 {
+		queueSize = DefaultPoolQueueSize
+	}
+	p := &Pool{tasks: make(chan poolTask, queueSize)}
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer log.LogPanicAndExit()
+			p.work()
+		}()
+	}
+	return p
+}
+
+// StartVerification is like StartCachedVerification, but the units are
+// verified by the workers of the pool. It blocks until all units are queued,
+// or until ctx is done. The results of units that could not be queued are put
+// on the channel immediately.
+func (p *Pool) StartVerification(ctx context.Context, verifier infra.Verifier, cache *Cache,
+	server net.Addr, segMetas []*seg.Meta,
+	sRevInfos []*path_mgmt.SignedRevInfo) (chan UnitResult, int) {
+
+	units := BuildUnits(segMetas, sRevInfos)
+	results := make(chan UnitResult, len(units))
+	for i, unit := range units {
+		task := poolTask{
+			ctx:      ctx,
+			unit:     unit,
+			verifier: verifier,
+			cache:    cache,
+			server:   server,
+			results:  results,
+		}
+		metrics.Pool.Queued().Inc()
+		select {
+		case p.tasks <- task:
+		case <-ctx.Done():
+			metrics.Pool.Queued().Dec()
+			for _, unit := range units[i:] {
+				results <- canceledResult(unit, ctx.Err())
+			}
+			return results, len(units)
+		}
+	}
+	return results, len(units)
+}
+
+func (p *Pool) work() {
+	for task := range p.tasks {
+		metrics.Pool.Queued().Dec()
+		if err := task.ctx.Err(); err != nil {
+			task.results <- canceledResult(task.unit, err)
+			continue
+		}
+		metrics.Pool.Busy().Inc()
+		task.results <- task.unit.verifySequentially(task.ctx, task.verifier, task.cache,
+			task.server)
+		metrics.Pool.Busy().Dec()
+	}
+}
+
+// verifySequentially verifies the segment and the revocations of the unit one
+// after the other.
+func (u *Unit) verifySequentially(ctx context.Context, verifier infra.Verifier, cache *Cache,
+	server net.Addr) UnitResult {
+
+	responses := make(chan ElemResult, u.Len())
+	verifySegment(ctx, verifier, cache, server, u.SegMeta, responses)
+	for i, sRevInfo := range u.SRevInfos {
+		verifyRevInfo(ctx, verifier, cache, server, i, sRevInfo, responses)
+	}
+	close(responses)
+	errs := make(map[int]error)
+	for result := range responses {
+		if result.Error != nil {
+			errs[result.Index] = result.Error
+		}
+	}
+	return UnitResult{Unit: u, Errors: errs}
+}
+
+// canceledResult returns a result in which all elements of the unit failed
+// with err.
+func canceledResult(unit *Unit, err error) UnitResult {
+	metrics.Pool.Canceled().Inc()
+	errs := map[int]error{segErrIndex: err}
+	for i := range unit.SRevInfos {
+		errs[i] = err
+	}
+	return UnitResult{Unit: unit, Errors: errs}
+}
@@ -0,0 +1,105 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segverifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/infra/mock_infra"
+)
+
+func TestPoolStartVerification(t *testing.T) {
+	segMetas := []*seg.Meta{
+		{Segment: testSegment(1)},
+		{Segment: testSegment(2)},
+		{Segment: testSegment(3)},
+	}
+	t.Run("all units are verified", func(t *testing.T) {
+		mctrl := gomock.NewController(t)
+		defer mctrl.Finish()
+		pool := NewPool(2, 1)
+		results, units := pool.StartVerification(context.Background(),
+			mock_infra.NewMockVerifier(mctrl), nil, nil, segMetas, nil)
+		require.Equal(t, len(segMetas), units)
+		for i := 0; i < units; i++ {
+			result := <-results
+			assert.Empty(t, result.Errors)
+		}
+	})
+	t.Run("units of canceled requests fail", func(t *testing.T) {
+		mctrl := gomock.NewController(t)
+		defer mctrl.Finish()
+		ctx, cancelF := context.WithCancel(context.Background())
+		cancelF()
+		pool := NewPool(1, 1)
+		results, units := pool.StartVerification(ctx,
+			mock_infra.NewMockVerifier(mctrl), nil, nil, segMetas, nil)
+		require.Equal(t, len(segMetas), units)
+		for i := 0; i < units; i++ {
+			result := <-results
+			assert.Equal(t, context.Canceled, result.SegError())
+		}
+	})
+	t.Run("full queue blocks until the context is done", func(t *testing.T) {
+		mctrl := gomock.NewController(t)
+		defer mctrl.Finish()
+		// A pool without workers, such that the queue is never drained.
+		pool := &Pool{tasks: make(chan poolTask, 1)}
+		ctx, cancelF := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancelF()
+		start := time.Now()
+		results, units := pool.StartVerification(ctx,
+			mock_infra.NewMockVerifier(mctrl), nil, nil, segMetas, nil)
+		assert.True(t, time.Since(start) >= 50*time.Millisecond)
+		require.Equal(t, len(segMetas), units)
+		// The first unit is queued, the others fail.
+		require.Len(t, results, units-1)
+		for i := 1; i < units; i++ {
+			result := <-results
+			assert.Equal(t, context.DeadlineExceeded, result.SegError())
+		}
+		assert.Len(t, pool.tasks, 1)
+	})
+}
+
+func TestUnitVerifySequentially(t *testing.T) {
+	mctrl := gomock.NewController(t)
+	defer mctrl.Finish()
+	verifier := mock_infra.NewMockVerifier(mctrl)
+	verifier.EXPECT().WithServer(gomock.Any()).Return(verifier).AnyTimes()
+	rev1, rev2 := testRevInfo(t, 1), testRevInfo(t, 2)
+	verifier.EXPECT().Verify(gomock.Any(), rev1.Blob, rev1.Sign).Return(
+		common.NewBasicError("invalid", nil))
+	verifier.EXPECT().Verify(gomock.Any(), rev2.Blob, rev2.Sign)
+
+	unit := &Unit{
+		SegMeta:   &seg.Meta{Segment: testSegment(1)},
+		SRevInfos: []*path_mgmt.SignedRevInfo{rev1, rev2},
+	}
+	result := unit.verifySequentially(context.Background(), verifier, nil, nil)
+	assert.Equal(t, unit, result.Unit)
+	assert.NoError(t, result.SegError())
+	assert.Len(t, result.Errors, 1)
+	assert.Error(t, result.Errors[0])
+}
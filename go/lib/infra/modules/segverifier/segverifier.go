@@ -27,6 +27,12 @@
 //   - If a revocation verification failed, its error is contained at key x,
 //   where x is the position of the revocation in the slice of SignedRevInfos
 //   passed to BuildVerificationUnits.
+//
+// Verification results can be cached in a Cache, such that segments and
+// revocations that are received repeatedly are only verified once.
+//
+// Instead of spawning goroutines for every unit, units can be verified on the
+// bounded number of workers of a Pool.
 package segverifier
 
 import (
@@ -51,13 +57,23 @@ const (
 func StartVerification(ctx context.Context, verifier infra.Verifier, server net.Addr,
 	segMetas []*seg.Meta, sRevInfos []*path_mgmt.SignedRevInfo) (chan UnitResult, int) {
 
+	return StartCachedVerification(ctx, verifier, nil, server, segMetas, sRevInfos)
+}
+
+// StartCachedVerification is like StartVerification, but the segments and
+// revocations found in the cache are not verified again. Successfully verified
+// segments and revocations are added to the cache.
+func StartCachedVerification(ctx context.Context, verifier infra.Verifier, cache *Cache,
+	server net.Addr, segMetas []*seg.Meta,
+	sRevInfos []*path_mgmt.SignedRevInfo) (chan UnitResult, int) {
+
 	units := BuildUnits(segMetas, sRevInfos)
 	unitResultsC := make(chan UnitResult, len(units))
 	for i := range units {
 		unit := units[i]
 		go func() {
 			defer log.LogPanicAndExit()
-			unit.Verify(ctx, verifier, server, unitResultsC)
+			unit.VerifyCached(ctx, verifier, cache, server, unitResultsC)
 		}()
 	}
 	return unitResultsC, len(units)
@@ -100,16 +116,25 @@ func (u *Unit) Len() int {
 func (u *Unit) Verify(ctx context.Context, verifier infra.Verifier,
 	server net.Addr, unitResults chan UnitResult) {
 
+	u.VerifyCached(ctx, verifier, nil, server, unitResults)
+}
+
+// VerifyCached is like Verify, but skips the verification of the segment and
+// revocations found in the cache. Successfully verified ones are added to the
+// cache.
+func (u *Unit) VerifyCached(ctx context.Context, verifier infra.Verifier, cache *Cache,
+	server net.Addr, unitResults chan UnitResult) {
+
 	responses := make(chan ElemResult, u.Len())
 	go func() {
 		defer log.LogPanicAndExit()
-		verifySegment(ctx, verifier, server, u.SegMeta, responses)
+		verifySegment(ctx, verifier, cache, server, u.SegMeta, responses)
 	}()
 	for i := range u.SRevInfos {
 		index := i
 		go func() {
 			defer log.LogPanicAndExit()
-			verifyRevInfo(ctx, verifier, server, index, u.SRevInfos[index], responses)
+			verifyRevInfo(ctx, verifier, cache, server, index, u.SRevInfos[index], responses)
 		}()
 	}
 	// Response writers must guarantee that the for loop below returns before
@@ -146,10 +171,16 @@ type ElemResult struct {
 	Error error
 }
 
-func verifySegment(ctx context.Context, verifier infra.Verifier, server net.Addr, segment *seg.Meta,
-	ch chan ElemResult) {
+func verifySegment(ctx context.Context, verifier infra.Verifier, cache *Cache, server net.Addr,
+	segment *seg.Meta, ch chan ElemResult) {
 
-	err := VerifySegment(ctx, verifier, server, segment.Segment)
+	var err error
+	if !cache.containsSegment(segment.Segment) {
+		err = VerifySegment(ctx, verifier, server, segment.Segment)
+		if err == nil {
+			cache.addSegment(segment.Segment)
+		}
+	}
 	select {
 	case ch <- ElemResult{Index: segErrIndex, Error: err}:
 	default:
@@ -176,10 +207,16 @@ func VerifySegment(ctx context.Context, verifier infra.Verifier, server net.Addr
 	return nil
 }
 
-func verifyRevInfo(ctx context.Context, verifier infra.Verifier, server net.Addr, index int,
-	signedRevInfo *path_mgmt.SignedRevInfo, ch chan ElemResult) {
+func verifyRevInfo(ctx context.Context, verifier infra.Verifier, cache *Cache, server net.Addr,
+	index int, signedRevInfo *path_mgmt.SignedRevInfo, ch chan ElemResult) {
 
-	err := VerifyRevInfo(ctx, verifier, server, signedRevInfo)
+	var err error
+	if !cache.containsRevInfo(signedRevInfo) {
+		err = VerifyRevInfo(ctx, verifier, server, signedRevInfo)
+		if err == nil {
+			cache.addRevInfo(signedRevInfo)
+		}
+	}
 	select {
 	case ch <- ElemResult{Index: index, Error: err}:
 	default:
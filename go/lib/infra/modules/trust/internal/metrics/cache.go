@@ -0,0 +1,114 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/scionproto/scion/go/lib/prom"
+)
+
+// TRCLabels defines the labels of the cached TRC metrics.
+type TRCLabels struct {
+	ISD string
+}
+
+// Labels returns the list of labels.
+func (l TRCLabels) Labels() []string {
+	return []string{"isd"}
+}
+
+// Values returns the label values in the order defined by Labels.
+func (l TRCLabels) Values() []string {
+	return []string{l.ISD}
+}
+
+// ChainLabels defines the labels of the cached certificate chain metrics.
+type ChainLabels struct {
+	IA string
+}
+
+// Labels returns the list of labels.
+func (l ChainLabels) Labels() []string {
+	return []string{"ia"}
+}
+
+// Values returns the label values in the order defined by Labels.
+func (l ChainLabels) Values() []string {
+	return []string{l.IA}
+}
+
+type cache struct {
+	trcs            prometheus.GaugeVec
+	trcVersion      prometheus.GaugeVec
+	trcExpiration   prometheus.GaugeVec
+	chains          prometheus.GaugeVec
+	chainVersion    prometheus.GaugeVec
+	chainExpiration prometheus.GaugeVec
+}
+
+func newCache() cache {
+	return cache{
+		trcs: *prom.NewGaugeVec(Namespace, "", "cached_trcs",
+			"Number of cached TRC versions per ISD", TRCLabels{}.Labels()),
+		trcVersion: *prom.NewGaugeVec(Namespace, "", "trc_latest_version",
+			"Latest cached TRC version per ISD", TRCLabels{}.Labels()),
+		trcExpiration: *prom.NewGaugeVec(Namespace, "", "trc_expiration_seconds",
+			"Expiration of the latest cached TRC per ISD, as unix timestamp",
+			TRCLabels{}.Labels()),
+		chains: *prom.NewGaugeVec(Namespace, "", "cached_chains",
+			"Number of cached certificate chain versions per AS", ChainLabels{}.Labels()),
+		chainVersion: *prom.NewGaugeVec(Namespace, "", "chain_latest_version",
+			"Latest cached certificate chain version per AS", ChainLabels{}.Labels()),
+		chainExpiration: *prom.NewGaugeVec(Namespace, "", "chain_expiration_seconds",
+			"Expiration of the latest cached certificate chain per AS, as unix timestamp",
+			ChainLabels{}.Labels()),
+	}
+}
+
+// Reset removes all cached crypto metrics. It is used before the metrics are
+// updated from the database, such that removed material does not linger.
+func (c *cache) Reset() {
+	c.trcs.Reset()
+	c.trcVersion.Reset()
+	c.trcExpiration.Reset()
+	c.chains.Reset()
+	c.chainVersion.Reset()
+	c.chainExpiration.Reset()
+}
+
+func (c *cache) TRCs(l TRCLabels) prometheus.Gauge {
+	return c.trcs.WithLabelValues(l.Values()...)
+}
+
+func (c *cache) TRCVersion(l TRCLabels) prometheus.Gauge {
+	return c.trcVersion.WithLabelValues(l.Values()...)
+}
+
+func (c *cache) TRCExpiration(l TRCLabels) prometheus.Gauge {
+	return c.trcExpiration.WithLabelValues(l.Values()...)
+}
+
+func (c *cache) Chains(l ChainLabels) prometheus.Gauge {
+	return c.chains.WithLabelValues(l.Values()...)
+}
+
+func (c *cache) ChainVersion(l ChainLabels) prometheus.Gauge {
+	return c.chainVersion.WithLabelValues(l.Values()...)
+}
+
+func (c *cache) ChainExpiration(l ChainLabels) prometheus.Gauge {
+	return c.chainExpiration.WithLabelValues(l.Values()...)
+}
@@ -28,6 +28,8 @@ func TestQueryLabels(t *testing.T) {
 		"LookupLabels":       metrics.LookupLabels{},
 		"SentLabels":         metrics.SentLabels{},
 		"VerificationLabels": metrics.VerificationLabels{},
+		"TRCLabels":          metrics.TRCLabels{},
+		"ChainLabels":        metrics.ChainLabels{},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -54,6 +54,8 @@ const (
 )
 
 var (
+	// Cache exposes the cached crypto material metrics.
+	Cache = newCache()
 	// DB exposes the database metrics.
 	DB = newDB()
 	// Handler exposes the handler metrics.
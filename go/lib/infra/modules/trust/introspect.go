@@ -0,0 +1,279 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trust
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/infra/messenger"
+	"github.com/scionproto/scion/go/lib/infra/modules/trust/internal/metrics"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/periodic"
+	"github.com/scionproto/scion/go/lib/scrypto"
+	"github.com/scionproto/scion/go/lib/scrypto/trc"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/util"
+	"github.com/scionproto/scion/go/proto"
+)
+
+// ErrAuthoritative indicates that the trust store is authoritative for the
+// requested object, and thus cannot refresh it from the network.
+var ErrAuthoritative = serrors.New("trust store is authoritative for requested object")
+
+// TRCInfo summarizes the cached TRCs of an ISD.
+type TRCInfo struct {
+	ISD addr.ISD `json:"isd"`
+	// Versions is the number of cached TRC versions.
+	Versions int `json:"versions"`
+	// Latest is the latest cached TRC version.
+	Latest scrypto.Version `json:"latest"`
+	// Expiration is the expiration time of the latest cached TRC.
+	Expiration time.Time `json:"expiration"`
+}
+
+// ChainInfo summarizes the cached certificate chains of an AS.
+type ChainInfo struct {
+	IA addr.IA `json:"ia"`
+	// Versions is the number of cached chain versions.
+	Versions int `json:"versions"`
+	// Latest is the latest cached chain version.
+	Latest scrypto.Version `json:"latest"`
+	// Expiration is the expiration time of the leaf certificate of the latest
+	// cached chain.
+	Expiration time.Time `json:"expiration"`
+}
+
+// CryptoInfo summarizes the crypto material cached in the trust store.
+type CryptoInfo struct {
+	TRCs   []TRCInfo   `json:"trcs"`
+	Chains []ChainInfo `json:"chains"`
+}
+
+// CryptoInfo returns a summary of the TRCs and certificate chains in the
+// database, sorted by ISD and AS respectively.
+func (store *Store) CryptoInfo(ctx context.Context) (*CryptoInfo, error) {
+	trcs, err := store.trustdb.GetAllTRCs(ctx)
+	if err != nil {
+		return nil, serrors.WrapStr("unable to list TRCs", err)
+	}
+	trcInfos := make(map[addr.ISD]*TRCInfo)
+	for res := range trcs {
+		if res.Err != nil {
+			return nil, serrors.WrapStr("unable to read TRC", res.Err)
+		}
+		info, ok := trcInfos[res.TRC.ISD]
+		if !ok {
+			info = &TRCInfo{ISD: res.TRC.ISD}
+			trcInfos[res.TRC.ISD] = info
+		}
+		info.Versions++
+		if res.TRC.Version >= info.Latest {
+			info.Latest = res.TRC.Version
+			info.Expiration = util.SecsToTime(res.TRC.ExpirationTime)
+		}
+	}
+	chains, err := store.trustdb.GetAllChains(ctx)
+	if err != nil {
+		return nil, serrors.WrapStr("unable to list chains", err)
+	}
+	chainInfos := make(map[addr.IA]*ChainInfo)
+	for res := range chains {
+		if res.Err != nil {
+			return nil, serrors.WrapStr("unable to read chain", res.Err)
+		}
+		leaf := res.Chain.Leaf
+		info, ok := chainInfos[leaf.Subject]
+		if !ok {
+			info = &ChainInfo{IA: leaf.Subject}
+			chainInfos[leaf.Subject] = info
+		}
+		info.Versions++
+		if leaf.Version >= info.Latest {
+			info.Latest = leaf.Version
+			info.Expiration = util.SecsToTime(leaf.ExpirationTime)
+		}
+	}
+	info := &CryptoInfo{
+		TRCs:   make([]TRCInfo, 0, len(trcInfos)),
+		Chains: make([]ChainInfo, 0, len(chainInfos)),
+	}
+	for _, t := range trcInfos {
+		info.TRCs = append(info.TRCs, *t)
+	}
+	for _, c := range chainInfos {
+		info.Chains = append(info.Chains, *c)
+	}
+	sort.Slice(info.TRCs, func(i, j int) bool {
+		return info.TRCs[i].ISD < info.TRCs[j].ISD
+	})
+	sort.Slice(info.Chains, func(i, j int) bool {
+		return info.Chains[i].IA.IAInt() < info.Chains[j].IA.IAInt()
+	})
+	return info, nil
+}
+
+// UpdateMetrics updates the Prometheus metrics describing the crypto material
+// cached in the trust store.
+func (store *Store) UpdateMetrics(ctx context.Context) error {
+	info, err := store.CryptoInfo(ctx)
+	if err != nil {
+		return err
+	}
+	metrics.Cache.Reset()
+	for _, t := range info.TRCs {
+		l := metrics.TRCLabels{ISD: t.ISD.String()}
+		metrics.Cache.TRCs(l).Set(float64(t.Versions))
+		metrics.Cache.TRCVersion(l).Set(float64(t.Latest))
+		metrics.Cache.TRCExpiration(l).Set(float64(t.Expiration.Unix()))
+	}
+	for _, c := range info.Chains {
+		l := metrics.ChainLabels{IA: c.IA.String()}
+		metrics.Cache.Chains(l).Set(float64(c.Versions))
+		metrics.Cache.ChainVersion(l).Set(float64(c.Latest))
+		metrics.Cache.ChainExpiration(l).Set(float64(c.Expiration.Unix()))
+	}
+	return nil
+}
+
+// RefreshTRC fetches the latest TRC of isd from the network, regardless of
+// the TRCs that are already cached. A newer TRC is verified and inserted into
+// the database. A trust store that is authoritative for isd returns
+// ErrAuthoritative.
+func (store *Store) RefreshTRC(ctx context.Context, isd addr.ISD) (*trc.TRC, error) {
+	if store.config.ServiceType == proto.ServiceType_cs &&
+		store.config.TopoProvider.Get().Core && store.ia.I == isd {
+
+		return nil, serrors.WithCtx(ErrAuthoritative, "isd", isd)
+	}
+	store.mu.Lock()
+	hasMsger := store.msger != nil
+	store.mu.Unlock()
+	if !hasMsger {
+		return nil, serrors.New("trust store has no messenger", "isd", isd)
+	}
+	server, err := store.ChooseServer(ctx, addr.IA{I: isd})
+	if err != nil {
+		return nil, serrors.WrapStr("Error determining server to query", err, "isd", isd)
+	}
+	trcObj, err := store.getTRCFromNetwork(ctx, &trcRequest{
+		isd:      isd,
+		version:  scrypto.LatestVer,
+		id:       messenger.NextId(),
+		server:   server,
+		postHook: store.insertTRCHook(),
+	})
+	l := store.getTRClabels(ctx, nil, server, err)
+	metrics.Store.Sent(l).Inc()
+	return trcObj, err
+}
+
+// NewInfoHTTPHandler returns an HTTP handler that serves the summary of the
+// cached crypto material (see CryptoInfo) as JSON. Only GET requests are
+// accepted.
+func (store *Store) NewInfoHTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "only GET is supported", http.StatusMethodNotAllowed)
+			return
+		}
+		ctx, cancelF := context.WithTimeout(r.Context(), HandlerTimeout)
+		defer cancelF()
+		info, err := store.CryptoInfo(ctx)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "    ")
+		enc.Encode(info)
+	})
+}
+
+// NewRefreshTRCHTTPHandler returns an HTTP handler that forces a refresh of
+// the TRC of the ISD passed in the isd query parameter (see RefreshTRC). Only
+// POST requests are accepted. Invalid parameters, ISDs the store is
+// authoritative for and TRCs that are not found result in a client error,
+// failures to fetch the TRC from the network in a gateway error.
+func (store *Store) NewRefreshTRCHTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+			return
+		}
+		isd, err := strconv.ParseUint(r.URL.Query().Get("isd"), 10, 16)
+		if err != nil || isd == 0 {
+			http.Error(w, "invalid isd parameter", http.StatusBadRequest)
+			return
+		}
+		ctx, cancelF := context.WithTimeout(r.Context(), HandlerTimeout)
+		defer cancelF()
+		trcObj, err := store.RefreshTRC(ctx, addr.ISD(isd))
+		if err != nil {
+			http.Error(w, err.Error(), refreshTRCStatus(ctx, err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(TRCInfo{
+			ISD:        trcObj.ISD,
+			Versions:   1,
+			Latest:     trcObj.Version,
+			Expiration: util.SecsToTime(trcObj.ExpirationTime),
+		})
+	})
+}
+
+// refreshTRCStatus returns the HTTP status code for an error returned by
+// RefreshTRC.
+func refreshTRCStatus(ctx context.Context, err error) int {
+	switch {
+	case xerrors.Is(err, ErrAuthoritative):
+		return http.StatusConflict
+	case xerrors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case ctx.Err() != nil:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+var _ periodic.Task = (*MetricsUpdater)(nil)
+
+// MetricsUpdater is a periodic task that updates the metrics describing the
+// crypto material cached in the trust store.
+type MetricsUpdater struct {
+	Store *Store
+}
+
+// Name returns the task name.
+func (u *MetricsUpdater) Name() string {
+	return "trust_metrics_updater"
+}
+
+// Run updates the metrics.
+func (u *MetricsUpdater) Run(ctx context.Context) {
+	if err := u.Store.UpdateMetrics(ctx); err != nil {
+		log.FromCtx(ctx).Error("[trust.MetricsUpdater] Unable to update metrics", "err", err)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trust
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/util"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestStoreCryptoInfo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	trcs, chains := loadCrypto(t, isds, ias)
+	store, cleanF := initStore(t, ctrl, xtest.MustParseIA("1-ff00:0:1"), nil)
+	defer cleanF()
+	insertTRC(t, store, trcs[2])
+	insertTRC(t, store, trcs[1])
+	insertChain(t, store, chains[ias[3]])
+	insertChain(t, store, chains[ias[0]])
+
+	info, err := store.CryptoInfo(context.Background())
+	require.NoError(t, err)
+	expected := &CryptoInfo{
+		TRCs: []TRCInfo{
+			{
+				ISD:        1,
+				Versions:   1,
+				Latest:     trcs[1].Version,
+				Expiration: util.SecsToTime(trcs[1].ExpirationTime),
+			},
+			{
+				ISD:        2,
+				Versions:   1,
+				Latest:     trcs[2].Version,
+				Expiration: util.SecsToTime(trcs[2].ExpirationTime),
+			},
+		},
+		Chains: []ChainInfo{
+			{
+				IA:         ias[0],
+				Versions:   1,
+				Latest:     chains[ias[0]].Leaf.Version,
+				Expiration: util.SecsToTime(chains[ias[0]].Leaf.ExpirationTime),
+			},
+			{
+				IA:         ias[3],
+				Versions:   1,
+				Latest:     chains[ias[3]].Leaf.Version,
+				Expiration: util.SecsToTime(chains[ias[3]].Leaf.ExpirationTime),
+			},
+		},
+	}
+	assert.Equal(t, expected, info)
+	assert.NoError(t, store.UpdateMetrics(context.Background()))
+
+	rec := httptest.NewRecorder()
+	store.NewInfoHTTPHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/trust/crypto", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var served CryptoInfo
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &served))
+	assert.Len(t, served.TRCs, 2)
+	assert.Len(t, served.Chains, 2)
+
+	rec = httptest.NewRecorder()
+	store.NewInfoHTTPHandler().ServeHTTP(rec, httptest.NewRequest("POST", "/trust/crypto", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestStoreRefreshTRC(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	trcs, chains := loadCrypto(t, isds, ias)
+	msger := newMessengerMock(ctrl, trcs, chains)
+	store, cleanF := initStore(t, ctrl, xtest.MustParseIA("1-ff00:0:1"), msger)
+	defer cleanF()
+
+	trcObj, err := store.RefreshTRC(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, trcs[2].Version, trcObj.Version)
+	info, err := store.CryptoInfo(context.Background())
+	require.NoError(t, err)
+	require.Len(t, info.TRCs, 1)
+	assert.Equal(t, addr.ISD(2), info.TRCs[0].ISD)
+
+	tests := map[string]struct {
+		Method       string
+		Target       string
+		ExpectedCode int
+	}{
+		"refresh": {
+			Method:       http.MethodPost,
+			Target:       "/trust/refresh_trc?isd=3",
+			ExpectedCode: http.StatusOK,
+		},
+		"wrong method": {
+			Method:       http.MethodGet,
+			Target:       "/trust/refresh_trc?isd=3",
+			ExpectedCode: http.StatusMethodNotAllowed,
+		},
+		"missing isd": {
+			Method:       http.MethodPost,
+			Target:       "/trust/refresh_trc",
+			ExpectedCode: http.StatusBadRequest,
+		},
+		"unknown isd": {
+			Method:       http.MethodPost,
+			Target:       "/trust/refresh_trc?isd=42",
+			ExpectedCode: http.StatusBadGateway,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			store.NewRefreshTRCHTTPHandler().ServeHTTP(rec,
+				httptest.NewRequest(test.Method, test.Target, nil))
+			assert.Equal(t, test.ExpectedCode, rec.Code)
+		})
+	}
+}
+
+func TestRefreshTRCStatus(t *testing.T) {
+	canceled, cancelF := context.WithCancel(context.Background())
+	cancelF()
+	tests := map[string]struct {
+		Ctx          context.Context
+		Err          error
+		ExpectedCode int
+	}{
+		"authoritative": {
+			Ctx:          context.Background(),
+			Err:          serrors.WithCtx(ErrAuthoritative, "isd", 1),
+			ExpectedCode: http.StatusConflict,
+		},
+		"not found": {
+			Ctx:          context.Background(),
+			Err:          serrors.Wrap(ErrNotFound, serrors.New("no TRC")),
+			ExpectedCode: http.StatusNotFound,
+		},
+		"timeout": {
+			Ctx:          canceled,
+			Err:          serrors.WrapStr("context done", canceled.Err()),
+			ExpectedCode: http.StatusGatewayTimeout,
+		},
+		"network error": {
+			Ctx:          context.Background(),
+			Err:          serrors.New("TRC not found"),
+			ExpectedCode: http.StatusBadGateway,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.ExpectedCode, refreshTRCStatus(test.Ctx, test.Err))
+		})
+	}
+}
@@ -159,3 +159,9 @@ type Transaction interface {
 	// Rollback rollbacks the transaction.
 	Rollback() error
 }
+
+// Ping checks that db is reachable by performing a cheap read.
+func Ping(ctx context.Context, db Read) error {
+	_, err := db.GetIssCertMaxVersion(ctx, addr.IA{})
+	return err
+}
@@ -15,6 +15,7 @@
 package rpc
 
 import (
+	"crypto/x509"
 	"net"
 
 	capnp "zombiezen.com/go/capnproto2"
@@ -25,6 +26,10 @@ type Request struct {
 	// Address records the network address that sent the request. It will
 	// usually be used for logging.
 	Address net.Addr
+	// PeerCertificates contains the certificates presented by the remote
+	// end during the TLS handshake. It is empty if the client did not
+	// present a certificate.
+	PeerCertificates []*x509.Certificate
 }
 
 type Reply struct {
@@ -115,8 +115,9 @@ func (s *Server) handleQUICSession(session quic.Session) error {
 	}
 	rw := &replyWriter{stream: stream}
 	request := &Request{
-		Message: msg,
-		Address: session.RemoteAddr(),
+		Message:          msg,
+		Address:          session.RemoteAddr(),
+		PeerCertificates: session.ConnectionState().PeerCertificates,
 	}
 	go func() {
 		defer log.LogPanicAndExit()
@@ -0,0 +1,219 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testtopo
+
+import (
+	"context"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/log"
+)
+
+const (
+	// DefaultReadyTimeout is the default time to wait for a service to
+	// become ready.
+	DefaultReadyTimeout = 10 * time.Second
+	// DefaultStopTimeout is the default time to wait for a service to exit
+	// after it has been asked to terminate, before it is killed.
+	DefaultStopTimeout = 5 * time.Second
+	// readyPollInterval is the interval in which readiness is checked.
+	readyPollInterval = 50 * time.Millisecond
+)
+
+// Runner starts the services of a topology as subprocesses.
+type Runner struct {
+	// BinDir is the directory containing the service binaries. If it is
+	// empty, "bin" is used.
+	BinDir string
+	// LogDir is the directory the output of every service is written to, in
+	// a file named after the service. If it is empty, "logs" is used.
+	LogDir string
+	// WorkDir is the working directory of the services. Configuration files
+	// created by the topology generator contain paths relative to the root of
+	// the repository. If it is empty, the current working directory is used.
+	WorkDir string
+	// ReadyTimeout bounds the time to wait for a single service to become
+	// ready. If it is zero, DefaultReadyTimeout is used.
+	ReadyTimeout time.Duration
+	// StopTimeout bounds the time to wait for a single service to exit. If it
+	// is zero, DefaultStopTimeout is used.
+	StopTimeout time.Duration
+}
+
+// Start starts all services of topo in order. A service is considered ready
+// once it accepts connections on its socket; services without socket are
+// ready once started. Socket files left behind by an earlier run therefore do
+// not make a service appear ready.
+// If a service fails to start or exits before it is ready, the services that
+// were already started are stopped and an error is returned.
+func (r *Runner) Start(ctx context.Context, topo *Topology) (*Instance, error) {
+	logDir := r.logDir()
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		return nil, common.NewBasicError("Unable to create log directory", err, "dir", logDir)
+	}
+	instance := &Instance{stopTimeout: r.StopTimeout}
+	if instance.stopTimeout == 0 {
+		instance.stopTimeout = DefaultStopTimeout
+	}
+	for _, elem := range topo.Elements {
+		p, err := r.start(elem)
+		if err != nil {
+			instance.Stop()
+			return nil, err
+		}
+		instance.procs = append(instance.procs, p)
+		if err := r.waitReady(ctx, p); err != nil {
+			instance.Stop()
+			return nil, err
+		}
+		log.Debug("[testtopo] Service started", "name", elem.Name, "pid", p.cmd.Process.Pid)
+	}
+	return instance, nil
+}
+
+func (r *Runner) start(elem Element) (*process, error) {
+	bin := filepath.Join(r.binDir(), elem.Kind.Binary())
+	cmd := exec.Command(bin, "-config", elem.Config)
+	cmd.Dir = r.WorkDir
+	logFile, err := os.Create(filepath.Join(r.logDir(), elem.Name+".log"))
+	if err != nil {
+		return nil, common.NewBasicError("Unable to create log file", err, "name", elem.Name)
+	}
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if err := cmd.Start(); err != nil {
+		logFile.Close()
+		return nil, common.NewBasicError("Unable to start service", err, "name", elem.Name,
+			"bin", bin)
+	}
+	p := &process{elem: elem, cmd: cmd, done: make(chan struct{})}
+	go func() {
+		defer log.LogPanicAndExit()
+		defer close(p.done)
+		p.err = cmd.Wait()
+		logFile.Close()
+	}()
+	return p, nil
+}
+
+func (r *Runner) waitReady(ctx context.Context, p *process) error {
+	timeout := r.ReadyTimeout
+	if timeout == 0 {
+		timeout = DefaultReadyTimeout
+	}
+	ctx, cancelF := context.WithTimeout(ctx, timeout)
+	defer cancelF()
+	ticker := time.NewTicker(readyPollInterval)
+	defer ticker.Stop()
+	for {
+		if p.elem.Socket == "" || listening(p.elem.Socket) {
+			return nil
+		}
+		select {
+		case <-p.done:
+			return common.NewBasicError("Service exited before it was ready", p.err,
+				"name", p.elem.Name)
+		case <-ctx.Done():
+			return common.NewBasicError("Service not ready", ctx.Err(), "name", p.elem.Name,
+				"socket", p.elem.Socket)
+		case <-ticker.C:
+		}
+	}
+}
+
+// listening returns whether a process accepts connections on the UNIX socket.
+func listening(socket string) bool {
+	conn, err := net.DialTimeout("unix", socket, readyPollInterval)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+func (r *Runner) binDir() string {
+	if r.BinDir == "" {
+		return "bin"
+	}
+	return r.BinDir
+}
+
+func (r *Runner) logDir() string {
+	if r.LogDir == "" {
+		return "logs"
+	}
+	return r.LogDir
+}
+
+// Instance is a running topology.
+type Instance struct {
+	procs       []*process
+	stopTimeout time.Duration
+}
+
+// Exited returns the names of the services that are no longer running.
+func (i *Instance) Exited() []string {
+	var names []string
+	for _, p := range i.procs {
+		select {
+		case <-p.done:
+			names = append(names, p.elem.Name)
+		default:
+		}
+	}
+	return names
+}
+
+// Stop stops all services in reverse start order. Services are asked to
+// terminate with SIGTERM, and are killed if they do not exit in time.
+func (i *Instance) Stop() {
+	for j := len(i.procs) - 1; j >= 0; j-- {
+		i.procs[j].stop(i.stopTimeout)
+	}
+	i.procs = nil
+}
+
+type process struct {
+	elem Element
+	cmd  *exec.Cmd
+	done chan struct{}
+	// err is the result of waiting for the process. It must only be read
+	// after done is closed.
+	err error
+}
+
+func (p *process) stop(timeout time.Duration) {
+	select {
+	case <-p.done:
+		return
+	default:
+	}
+	if err := p.cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		log.Debug("[testtopo] Unable to terminate service", "name", p.elem.Name, "err", err)
+	}
+	select {
+	case <-p.done:
+	case <-time.After(timeout):
+		log.Info("[testtopo] Killing service", "name", p.elem.Name)
+		p.cmd.Process.Kill()
+		<-p.done
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testtopo_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/integration/testtopo"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestLoad(t *testing.T) {
+	topo, err := testtopo.Load("testdata/gen")
+	require.NoError(t, err)
+	assert.Equal(t, []addr.IA{
+		xtest.MustParseIA("1-ff00:0:110"),
+		xtest.MustParseIA("1-ff00:0:111"),
+	}, topo.ASes)
+	assert.Equal(t, []string{
+		"dispatcher",
+		"br1-ff00_0_110-1",
+		"br1-ff00_0_111-1",
+		"br1-ff00_0_111-2",
+		"bs1-ff00_0_110-1",
+		"cs1-ff00_0_110-1",
+		"ps1-ff00_0_110-1",
+		"sd1-ff00_0_110",
+		"sd1-ff00_0_111",
+	}, names(topo))
+
+	sd := topo.Elements[len(topo.Elements)-1]
+	assert.Equal(t, testtopo.SCIOND, sd.Kind)
+	assert.Equal(t, xtest.MustParseIA("1-ff00:0:111"), sd.IA)
+	assert.Equal(t, "testdata/gen/ISD1/ASff00_0_111/endhost/sd.toml", sd.Config)
+	assert.Equal(t, "/run/shm/sciond/sd1-ff00_0_111.sock", sd.Socket)
+}
+
+func TestLoadEmpty(t *testing.T) {
+	dir, cleanF := xtest.MustTempDir("", "testtopo")
+	defer cleanF()
+	_, err := testtopo.Load(dir)
+	assert.Error(t, err)
+}
+
+func TestSelect(t *testing.T) {
+	topo, err := testtopo.Load("testdata/gen")
+	require.NoError(t, err)
+	selected := topo.Select(xtest.MustParseIA("1-ff00:0:111"))
+	assert.Equal(t, []addr.IA{xtest.MustParseIA("1-ff00:0:111")}, selected.ASes)
+	assert.Equal(t, []string{
+		"dispatcher",
+		"br1-ff00_0_111-1",
+		"br1-ff00_0_111-2",
+		"sd1-ff00_0_111",
+	}, names(selected))
+}
+
+func TestRunner(t *testing.T) {
+	dir, cleanF := xtest.MustTempDir("", "testtopo")
+	defer cleanF()
+	binDir := filepath.Join(dir, "bin")
+	require.NoError(t, os.Mkdir(binDir, 0755))
+	// The fake services do not open their sockets; the tests listen on them
+	// instead where a service is expected to become ready.
+	writeScript(t, binDir, "godispatcher", `exec sleep 60`)
+	writeScript(t, binDir, "border", `exec sleep 60`)
+	writeScript(t, binDir, "sciond", `exit 1`)
+	dispSocket := filepath.Join(dir, testtopo.Dispatcher.ConfigName()+".sock")
+
+	newTopo := func(kinds ...testtopo.Kind) *testtopo.Topology {
+		topo := &testtopo.Topology{}
+		for _, kind := range kinds {
+			elem := testtopo.Element{
+				Name:   string(kind),
+				Kind:   kind,
+				Config: filepath.Join(dir, kind.ConfigName()),
+			}
+			if kind == testtopo.Dispatcher || kind == testtopo.SCIOND {
+				elem.Socket = elem.Config + ".sock"
+			}
+			topo.Elements = append(topo.Elements, elem)
+		}
+		return topo
+	}
+	runner := &testtopo.Runner{
+		BinDir:       binDir,
+		LogDir:       filepath.Join(dir, "logs"),
+		ReadyTimeout: 5 * time.Second,
+		StopTimeout:  time.Second,
+	}
+
+	t.Run("services start and stop", func(t *testing.T) {
+		l, err := net.Listen("unix", dispSocket)
+		require.NoError(t, err)
+		defer l.Close()
+		instance, err := runner.Start(context.Background(),
+			newTopo(testtopo.Dispatcher, testtopo.BorderRouter))
+		require.NoError(t, err)
+		assert.Empty(t, instance.Exited())
+		instance.Stop()
+		assert.FileExists(t, filepath.Join(dir, "logs", "dispatcher.log"))
+		assert.FileExists(t, filepath.Join(dir, "logs", "br.log"))
+	})
+	t.Run("stale socket is not ready", func(t *testing.T) {
+		l, err := net.ListenUnix("unix", &net.UnixAddr{Name: dispSocket, Net: "unix"})
+		require.NoError(t, err)
+		l.SetUnlinkOnClose(false)
+		l.Close()
+		staleRunner := *runner
+		staleRunner.ReadyTimeout = 200 * time.Millisecond
+		_, err = staleRunner.Start(context.Background(), newTopo(testtopo.Dispatcher))
+		assert.Error(t, err)
+	})
+	t.Run("failing service", func(t *testing.T) {
+		_, err := runner.Start(context.Background(),
+			newTopo(testtopo.BorderRouter, testtopo.SCIOND))
+		assert.Error(t, err)
+	})
+	t.Run("missing binary", func(t *testing.T) {
+		_, err := runner.Start(context.Background(), newTopo(testtopo.PathServer))
+		assert.Error(t, err)
+	})
+}
+
+func names(topo *testtopo.Topology) []string {
+	var names []string
+	for _, elem := range topo.Elements {
+		names = append(names, elem.Name)
+	}
+	return names
+}
+
+func writeScript(t *testing.T, dir, name, body string) {
+	t.Helper()
+	err := ioutil.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+body+"\n"), 0755)
+	require.NoError(t, err)
+}
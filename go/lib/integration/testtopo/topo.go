@@ -0,0 +1,255 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package testtopo starts a local SCION topology for Go integration tests.
+//
+// The topology is described by the configuration directory created by the
+// topology generator (usually gen/). Load discovers the dispatcher and the
+// border routers, beacon servers, certificate servers, path servers and
+// SCIOND instances of every AS in the directory. A Runner then starts the
+// services as subprocesses, in the same order as supervisor does, and waits
+// until they are ready:
+//
+//	topo, err := testtopo.Load("gen")
+//	...
+//	topo = topo.Select(xtest.MustParseIA("1-ff00:0:110"), xtest.MustParseIA("1-ff00:0:111"))
+//	instance, err := (&testtopo.Runner{}).Start(ctx, topo)
+//	...
+//	defer instance.Stop()
+//
+// Go test suites can thus manage the topology they run against, instead of
+// relying on it being started by scion.sh.
+package testtopo
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/sock/reliable"
+)
+
+// Kind is the type of a service in a local topology.
+type Kind string
+
+const (
+	Dispatcher   Kind = "dispatcher"
+	BorderRouter Kind = "br"
+	BeaconServer Kind = "bs"
+	CertServer   Kind = "cs"
+	PathServer   Kind = "ps"
+	SCIOND       Kind = "sd"
+)
+
+// startOrder is the order in which services are started. It mirrors the
+// order of the supervisor configuration.
+var startOrder = []Kind{Dispatcher, BorderRouter, BeaconServer, CertServer, PathServer, SCIOND}
+
+// Binary returns the name of the binary that implements the service.
+func (k Kind) Binary() string {
+	switch k {
+	case Dispatcher:
+		return "godispatcher"
+	case BorderRouter:
+		return "border"
+	case BeaconServer:
+		return "beacon_srv"
+	case CertServer:
+		return "cert_srv"
+	case PathServer:
+		return "path_srv"
+	case SCIOND:
+		return "sciond"
+	default:
+		return ""
+	}
+}
+
+// ConfigName returns the name of the configuration file of the service.
+func (k Kind) ConfigName() string {
+	switch k {
+	case Dispatcher:
+		return "disp.toml"
+	default:
+		return string(k) + ".toml"
+	}
+}
+
+func (k Kind) order() int {
+	for i, o := range startOrder {
+		if k == o {
+			return i
+		}
+	}
+	return len(startOrder)
+}
+
+// Element is a single service of a local topology.
+type Element struct {
+	// Name is the name of the service, e.g., br1-ff00_0_110-1.
+	Name string
+	Kind Kind
+	// IA is the AS the service belongs to. It is the zero value for the
+	// dispatcher, which is shared by all ASes.
+	IA addr.IA
+	// Config is the path to the configuration file of the service.
+	Config string
+	// Socket is the path of a UNIX socket the service creates once it is
+	// ready to serve requests. It is empty if readiness can not be observed.
+	Socket string
+}
+
+// Topology is a set of services that make up a local topology.
+type Topology struct {
+	// Dir is the configuration directory the topology was loaded from.
+	Dir string
+	// ASes contains the ASes of the topology, in ascending order.
+	ASes []addr.IA
+	// Elements contains the services of the topology, in start order.
+	Elements []Element
+}
+
+// Load discovers the services of the local topology configured in genDir.
+// The layout of genDir must match the output of the topology generator, i.e.,
+// genDir/dispatcher for the dispatcher and genDir/ISD<isd>/AS<as>/<service>
+// for the services of every AS. Services other than the ones listed in Kind
+// (e.g., SIGs) are ignored.
+func Load(genDir string) (*Topology, error) {
+	topo := &Topology{Dir: genDir}
+	dispConfig := filepath.Join(genDir, "dispatcher", Dispatcher.ConfigName())
+	if exists(dispConfig) {
+		topo.Elements = append(topo.Elements, Element{
+			Name:   "dispatcher",
+			Kind:   Dispatcher,
+			Config: dispConfig,
+			Socket: reliable.DefaultDispPath,
+		})
+	}
+	asDirs, err := filepath.Glob(filepath.Join(genDir, addr.ISDFmtPrefix+"*",
+		addr.ASFmtPrefix+"*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, asDir := range asDirs {
+		isdDir := filepath.Base(filepath.Dir(asDir))
+		ia, err := addr.IAFromFileFmt(isdDir+"-"+filepath.Base(asDir), true)
+		if err != nil {
+			return nil, common.NewBasicError("Unable to parse AS directory", err, "dir", asDir)
+		}
+		elems, err := loadAS(ia, asDir)
+		if err != nil {
+			return nil, err
+		}
+		topo.ASes = append(topo.ASes, ia)
+		topo.Elements = append(topo.Elements, elems...)
+	}
+	if len(topo.Elements) == 0 {
+		return nil, common.NewBasicError("No services found", nil, "dir", genDir)
+	}
+	topo.sort()
+	return topo, nil
+}
+
+func loadAS(ia addr.IA, asDir string) ([]Element, error) {
+	infos, err := ioutil.ReadDir(asDir)
+	if err != nil {
+		return nil, err
+	}
+	var elems []Element
+	for _, info := range infos {
+		if !info.IsDir() {
+			continue
+		}
+		name := info.Name()
+		if name == "endhost" {
+			config := filepath.Join(asDir, name, SCIOND.ConfigName())
+			if exists(config) {
+				elems = append(elems, Element{
+					Name:   "sd" + ia.FileFmt(false),
+					Kind:   SCIOND,
+					IA:     ia,
+					Config: config,
+					Socket: sciond.GetDefaultSCIONDPath(&ia),
+				})
+			}
+			continue
+		}
+		for _, kind := range []Kind{BorderRouter, BeaconServer, CertServer, PathServer} {
+			if !strings.HasPrefix(name, string(kind)) {
+				continue
+			}
+			config := filepath.Join(asDir, name, kind.ConfigName())
+			if exists(config) {
+				elems = append(elems, Element{
+					Name:   name,
+					Kind:   kind,
+					IA:     ia,
+					Config: config,
+				})
+			}
+		}
+	}
+	return elems, nil
+}
+
+// Select returns the topology restricted to the given ASes. The dispatcher
+// is always part of the returned topology.
+func (t *Topology) Select(ias ...addr.IA) *Topology {
+	selected := &Topology{Dir: t.Dir}
+	contains := func(ia addr.IA) bool {
+		for _, other := range ias {
+			if ia.Equal(other) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, ia := range t.ASes {
+		if contains(ia) {
+			selected.ASes = append(selected.ASes, ia)
+		}
+	}
+	for _, elem := range t.Elements {
+		if elem.Kind == Dispatcher || contains(elem.IA) {
+			selected.Elements = append(selected.Elements, elem)
+		}
+	}
+	return selected
+}
+
+func (t *Topology) sort() {
+	sort.Slice(t.ASes, func(i, j int) bool {
+		return t.ASes[i].IAInt() < t.ASes[j].IAInt()
+	})
+	sort.SliceStable(t.Elements, func(i, j int) bool {
+		a, b := t.Elements[i], t.Elements[j]
+		if a.Kind != b.Kind {
+			return a.Kind.order() < b.Kind.order()
+		}
+		if !a.IA.Equal(b.IA) {
+			return a.IA.IAInt() < b.IA.IAInt()
+		}
+		return a.Name < b.Name
+	})
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
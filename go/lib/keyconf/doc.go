@@ -18,4 +18,8 @@
 // attached metadata. The PEM files have special file names based on the type,
 // usage, and version of the key. See the encoding and filename example for more
 // information.
+//
+// Type Ring holds all versions of the keys with a given type and usage. It
+// allows rotating keys with overlapping validity periods and reloads the keys
+// from disk when run as a periodic task.
 package keyconf
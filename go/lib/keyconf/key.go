@@ -18,8 +18,11 @@ import (
 	"encoding/pem"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
+	"golang.org/x/crypto/ed25519"
+
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/scrypto"
@@ -185,6 +188,18 @@ func (k Key) File() string {
 	return PublicKeyFile(k.Usage, k.IA, k.Version)
 }
 
+// SigningKey returns the private key in the format expected by
+// scrypto.Sign. Ed25519 keys stored as seed are expanded.
+func (k Key) SigningKey() (common.RawBytes, error) {
+	if k.Type != PrivateKey {
+		return nil, serrors.WithCtx(ErrUnsupportedType, "type", k.Type)
+	}
+	if strings.ToLower(k.Algorithm) == scrypto.Ed25519 && len(k.Bytes) == ed25519.SeedSize {
+		return common.RawBytes(ed25519.NewKeyFromSeed(k.Bytes)), nil
+	}
+	return append(common.RawBytes(nil), k.Bytes...), nil
+}
+
 func (k Key) String() string {
 	key := "<redacted>"
 	if k.Type == PublicKey {
@@ -0,0 +1,231 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyconf
+
+import (
+	"bytes"
+	"context"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/scrypto"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+var (
+	// ErrNoActiveKey indicates that no key in the ring is currently valid.
+	ErrNoActiveKey = serrors.New("no active key")
+	// ErrNoMatchingKey indicates that no valid key in the ring corresponds to
+	// the requested public key.
+	ErrNoMatchingKey = serrors.New("no matching key")
+	// ErrUnexpectedKey indicates that a key file contains a key that does not
+	// belong to the ring.
+	ErrUnexpectedKey = serrors.New("unexpected key")
+)
+
+// Ring holds all versions of the keys with a given type and usage of an AS.
+// The keys are loaded from a directory using the file names defined by Key.
+//
+// Multiple keys can be valid at the same time. This allows rotating keys with
+// overlapping validity periods: The newest valid key is used for signing,
+// while signatures created with any key in the ring can still be verified
+// during the transition.
+//
+// Ring implements periodic.Task. Running it periodically reloads the keys from
+// disk, such that new keys are picked up without a restart.
+type Ring struct {
+	dir   string
+	typ   Type
+	usage Usage
+	ia    addr.IA
+
+	mtx  sync.RWMutex
+	keys map[scrypto.KeyVersion]Key
+}
+
+// NewRing creates a key ring for the keys of the AS with the given type and
+// usage, and loads the keys in the directory.
+func NewRing(dir string, typ Type, usage Usage, ia addr.IA) (*Ring, error) {
+	r := &Ring{
+		dir:   dir,
+		typ:   typ,
+		usage: usage,
+		ia:    ia,
+	}
+	if err := r.Load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Load reloads all keys from the directory. Key files that cannot be loaded
+// are skipped and logged, such that a single broken file does not prevent
+// the other keys from being used. If the directory cannot be listed, an error
+// is returned and the previously loaded keys are kept.
+func (r *Ring) Load() error {
+	files, err := filepath.Glob(filepath.Join(r.dir, r.pattern()))
+	if err != nil {
+		return serrors.WrapStr("unable to list key files", err, "dir", r.dir)
+	}
+	keys := make(map[scrypto.KeyVersion]Key, len(files))
+	for _, file := range files {
+		key, err := r.loadKey(file)
+		if err != nil {
+			log.Warn("Ignoring invalid key file", "file", file, "err", err)
+			continue
+		}
+		keys[key.Version] = key
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.keys = keys
+	return nil
+}
+
+func (r *Ring) pattern() string {
+	if r.typ == PrivateKey {
+		return fmt.Sprintf("%s-v*.key", r.usage)
+	}
+	return fmt.Sprintf("%s-%s-v*.pub", r.ia.FileFmt(true), r.usage)
+}
+
+func (r *Ring) loadKey(file string) (Key, error) {
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		return Key{}, err
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return Key{}, serrors.New("unable to decode PEM")
+	}
+	key, err := KeyFromPEM(block)
+	if err != nil {
+		return Key{}, err
+	}
+	if key.Type != r.typ || key.Usage != r.usage || !key.IA.Equal(r.ia) {
+		return Key{}, serrors.WithCtx(ErrUnexpectedKey, "type", key.Type,
+			"usage", key.Usage, "ia", key.IA)
+	}
+	if _, name := filepath.Split(file); name != key.File() {
+		return Key{}, serrors.New("unexpected file name", "actual", name,
+			"expected", key.File())
+	}
+	return key, nil
+}
+
+// Key returns the key with the given version.
+func (r *Ring) Key(version scrypto.KeyVersion) (Key, bool) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	key, ok := r.keys[version]
+	return key, ok
+}
+
+// Active returns the key with the highest version that is valid at the given
+// time. This key should be used for signing.
+func (r *Ring) Active(now time.Time) (Key, error) {
+	valid := r.Valid(now)
+	if len(valid) == 0 {
+		return Key{}, serrors.WithCtx(ErrNoActiveKey, "usage", r.usage, "time", now)
+	}
+	return valid[len(valid)-1], nil
+}
+
+// Valid returns all keys that are valid at the given time sorted by
+// ascending version. These keys can be used for verification.
+func (r *Ring) Valid(now time.Time) []Key {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	var valid []Key
+	for _, key := range r.keys {
+		if key.Validity.Contains(now) {
+			valid = append(valid, key)
+		}
+	}
+	sort.Slice(valid, func(i, j int) bool { return valid[i].Version < valid[j].Version })
+	return valid
+}
+
+// Matching returns the key with the highest version that is valid at the
+// given time and corresponds to the public key pub. For private keys, the
+// public key is derived from the private key.
+func (r *Ring) Matching(now time.Time, pub []byte) (Key, error) {
+	valid := r.Valid(now)
+	for i := len(valid) - 1; i >= 0; i-- {
+		key := valid[i]
+		keyPub := key.Bytes
+		if key.Type == PrivateKey {
+			var err error
+			if keyPub, err = scrypto.GetPubKey(key.Bytes, key.Algorithm); err != nil {
+				continue
+			}
+		}
+		if bytes.Equal(keyPub, pub) {
+			return key, nil
+		}
+	}
+	return Key{}, serrors.WithCtx(ErrNoMatchingKey, "usage", r.usage, "time", now)
+}
+
+// Versions returns the versions of all keys in the ring sorted in ascending
+// order.
+func (r *Ring) Versions() []scrypto.KeyVersion {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	versions := make([]scrypto.KeyVersion, 0, len(r.keys))
+	for version := range r.keys {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions
+}
+
+// Name returns the task name.
+func (r *Ring) Name() string {
+	return fmt.Sprintf("keyconf_ring_%s", r.usage)
+}
+
+// Run reloads the keys from disk. Errors are logged and the previously loaded
+// keys are kept.
+func (r *Ring) Run(ctx context.Context) {
+	logger := log.FromCtx(ctx)
+	before := r.Versions()
+	if err := r.Load(); err != nil {
+		logger.Error("Unable to reload keys", "usage", r.usage, "err", err)
+		return
+	}
+	if after := r.Versions(); !equalVersions(before, after) {
+		logger.Info("Reloaded keys", "usage", r.usage, "versions", after)
+	}
+}
+
+func equalVersions(a, b []scrypto.KeyVersion) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,203 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyconf_test
+
+import (
+	"context"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/keyconf"
+	"github.com/scionproto/scion/go/lib/periodic"
+	"github.com/scionproto/scion/go/lib/scrypto"
+	"github.com/scionproto/scion/go/lib/util"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+var _ periodic.Task = (*keyconf.Ring)(nil)
+
+func TestRing(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	now := time.Now().Truncate(time.Second)
+	// v1 and v2 overlap between now and now+1h.
+	v1 := ringKey(ia, 1, now.Add(-time.Hour), now.Add(time.Hour))
+	v2 := ringKey(ia, 2, now, now.Add(2*time.Hour))
+	// v3 is not valid yet.
+	v3 := ringKey(ia, 3, now.Add(3*time.Hour), now.Add(4*time.Hour))
+
+	t.Run("load", func(t *testing.T) {
+		dir, cleanF := xtest.MustTempDir("", "keyconf-ring")
+		defer cleanF()
+		writeRingKeys(t, dir, v1, v2, v3)
+		// Keys with different usage are ignored.
+		other := v1
+		other.Usage = keyconf.ASDecryptionKey
+		writeRingKeys(t, dir, other)
+
+		r, err := keyconf.NewRing(dir, keyconf.PrivateKey, keyconf.ASSigningKey, ia)
+		require.NoError(t, err)
+		assert.Equal(t, []scrypto.KeyVersion{1, 2, 3}, r.Versions())
+		k, ok := r.Key(1)
+		assert.True(t, ok)
+		assert.Equal(t, v1.Bytes, k.Bytes)
+		assert.True(t, v1.Validity.NotBefore.Equal(k.Validity.NotBefore.Time))
+		assert.True(t, v1.Validity.NotAfter.Equal(k.Validity.NotAfter.Time))
+		_, ok = r.Key(4)
+		assert.False(t, ok)
+	})
+	t.Run("active and valid", func(t *testing.T) {
+		dir, cleanF := xtest.MustTempDir("", "keyconf-ring")
+		defer cleanF()
+		writeRingKeys(t, dir, v1, v2, v3)
+		r, err := keyconf.NewRing(dir, keyconf.PrivateKey, keyconf.ASSigningKey, ia)
+		require.NoError(t, err)
+
+		tests := map[string]struct {
+			Time   time.Time
+			Active scrypto.KeyVersion
+			Valid  []scrypto.KeyVersion
+		}{
+			"only v1": {
+				Time:   now.Add(-time.Minute),
+				Active: 1,
+				Valid:  []scrypto.KeyVersion{1},
+			},
+			"overlap": {
+				Time:   now.Add(time.Minute),
+				Active: 2,
+				Valid:  []scrypto.KeyVersion{1, 2},
+			},
+			"only v2": {
+				Time:   now.Add(90 * time.Minute),
+				Active: 2,
+				Valid:  []scrypto.KeyVersion{2},
+			},
+			"none": {
+				Time: now.Add(150 * time.Minute),
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				var valid []scrypto.KeyVersion
+				for _, k := range r.Valid(test.Time) {
+					valid = append(valid, k.Version)
+				}
+				assert.Equal(t, test.Valid, valid)
+				k, err := r.Active(test.Time)
+				if test.Valid == nil {
+					assert.Error(t, err)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, test.Active, k.Version)
+			})
+		}
+	})
+	t.Run("reload", func(t *testing.T) {
+		dir, cleanF := xtest.MustTempDir("", "keyconf-ring")
+		defer cleanF()
+		writeRingKeys(t, dir, v1)
+		r, err := keyconf.NewRing(dir, keyconf.PrivateKey, keyconf.ASSigningKey, ia)
+		require.NoError(t, err)
+		assert.Equal(t, []scrypto.KeyVersion{1}, r.Versions())
+
+		// New keys are picked up.
+		writeRingKeys(t, dir, v2)
+		r.Run(context.Background())
+		assert.Equal(t, []scrypto.KeyVersion{1, 2}, r.Versions())
+
+		// Invalid keys are skipped and the other keys are kept.
+		file := filepath.Join(dir, v3.File())
+		require.NoError(t, ioutil.WriteFile(file, []byte("invalid"), 0600))
+		require.NoError(t, r.Load())
+		assert.Equal(t, []scrypto.KeyVersion{1, 2}, r.Versions())
+
+		// Removed keys are dropped.
+		require.NoError(t, os.Remove(file))
+		require.NoError(t, os.Remove(filepath.Join(dir, v1.File())))
+		require.NoError(t, r.Load())
+		assert.Equal(t, []scrypto.KeyVersion{2}, r.Versions())
+	})
+	t.Run("matching", func(t *testing.T) {
+		dir, cleanF := xtest.MustTempDir("", "keyconf-ring")
+		defer cleanF()
+		pub1, priv1, err := scrypto.GenKeyPair(scrypto.Ed25519)
+		require.NoError(t, err)
+		pub2, priv2, err := scrypto.GenKeyPair(scrypto.Ed25519)
+		require.NoError(t, err)
+		k1, k2 := v1, v2
+		k1.Bytes = ed25519.PrivateKey(priv1).Seed()
+		k2.Bytes = priv2
+		writeRingKeys(t, dir, k1, k2)
+		r, err := keyconf.NewRing(dir, keyconf.PrivateKey, keyconf.ASSigningKey, ia)
+		require.NoError(t, err)
+
+		k, err := r.Matching(now, pub1)
+		require.NoError(t, err)
+		assert.Equal(t, scrypto.KeyVersion(1), k.Version)
+		raw, err := k.SigningKey()
+		require.NoError(t, err)
+		assert.Equal(t, priv1, raw)
+		k, err = r.Matching(now, pub2)
+		require.NoError(t, err)
+		assert.Equal(t, scrypto.KeyVersion(2), k.Version)
+		// v1 has expired.
+		_, err = r.Matching(now.Add(90*time.Minute), pub1)
+		assert.Error(t, err)
+	})
+	t.Run("public keys", func(t *testing.T) {
+		dir, cleanF := xtest.MustTempDir("", "keyconf-ring")
+		defer cleanF()
+		pub1, pub2 := v1, v2
+		pub1.Type, pub2.Type = keyconf.PublicKey, keyconf.PublicKey
+		writeRingKeys(t, dir, pub1, pub2, v3)
+		r, err := keyconf.NewRing(dir, keyconf.PublicKey, keyconf.ASSigningKey, ia)
+		require.NoError(t, err)
+		assert.Equal(t, []scrypto.KeyVersion{1, 2}, r.Versions())
+	})
+}
+
+func ringKey(ia addr.IA, version scrypto.KeyVersion, notBefore, notAfter time.Time) keyconf.Key {
+	return keyconf.Key{
+		Type:      keyconf.PrivateKey,
+		Usage:     keyconf.ASSigningKey,
+		Algorithm: scrypto.Ed25519,
+		Validity: scrypto.Validity{
+			NotBefore: util.UnixTime{Time: notBefore},
+			NotAfter:  util.UnixTime{Time: notAfter},
+		},
+		Version: version,
+		IA:      ia,
+		Bytes:   []byte{byte(version)},
+	}
+}
+
+func writeRingKeys(t *testing.T, dir string, keys ...keyconf.Key) {
+	t.Helper()
+	for _, key := range keys {
+		block := key.PEM()
+		err := ioutil.WriteFile(filepath.Join(dir, key.File()), pem.EncodeToMemory(&block), 0600)
+		require.NoError(t, err)
+	}
+}
@@ -63,7 +63,7 @@ func AddLogFileFlags() {
 func SetupFromFlags(name string) error {
 	var err error
 	if logConsole != "" {
-		err = SetupLogConsole(logConsole)
+		err = SetupLogConsole(logConsole, FormatHuman)
 		if err != nil {
 			return err
 		}
@@ -73,7 +73,8 @@ func SetupFromFlags(name string) error {
 		if logDir == "" {
 			return serrors.New("Log dir flag not set")
 		}
-		err = SetupLogFile(name, logDir, logLevel, logSize, logAge, logBackups, logFlush)
+		err = SetupLogFile(name, logDir, logLevel, logSize, logAge, logBackups, logFlush,
+			FormatHuman)
 	}
 	return err
 }
@@ -0,0 +1,195 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/scionproto/scion/go/lib/common"
+)
+
+var (
+	// lvlMtx protects fileLvl and consLvl, which are set up by the logging
+	// setup functions and read by the level HTTP handler.
+	lvlMtx sync.RWMutex
+	// fileLvl and consLvl hold the levels of the file and console handlers.
+	// They can be changed at runtime.
+	fileLvl *lvlFilterHandler
+	consLvl *lvlFilterHandler
+)
+
+func setFileLvl(h *lvlFilterHandler) {
+	lvlMtx.Lock()
+	defer lvlMtx.Unlock()
+	fileLvl = h
+}
+
+func setConsLvl(h *lvlFilterHandler) {
+	lvlMtx.Lock()
+	defer lvlMtx.Unlock()
+	consLvl = h
+}
+
+func lvlHandlers() (file, cons *lvlFilterHandler) {
+	lvlMtx.RLock()
+	defer lvlMtx.RUnlock()
+	return fileLvl, consLvl
+}
+
+var _ Handler = (*lvlFilterHandler)(nil)
+
+// lvlFilterHandler filters records below a minimum level. Contrary to
+// log15.LvlFilterHandler, the level can be changed at runtime. Trace messages
+// are only passed through if the level is trace.
+type lvlFilterHandler struct {
+	log15.Handler
+
+	mtx      sync.RWMutex
+	lvlStr   string
+	lvl      log15.Lvl
+	logTrace bool
+}
+
+func newLvlFilterHandler(logLevel string, handler log15.Handler) (*lvlFilterHandler, error) {
+	h := &lvlFilterHandler{Handler: handler}
+	if err := h.setLevel(logLevel); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (h *lvlFilterHandler) setLevel(logLevel string) error {
+	logLevel = strings.ToLower(logLevel)
+	lvl, err := log15.LvlFromString(changeTraceToDebug(logLevel))
+	if err != nil {
+		return common.NewBasicError("Unable to parse log level", err, "level", logLevel)
+	}
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.lvlStr = logLevel
+	h.lvl = lvl
+	h.logTrace = logLevel == LvlTraceStr
+	return nil
+}
+
+func (h *lvlFilterHandler) level() string {
+	h.mtx.RLock()
+	defer h.mtx.RUnlock()
+	return h.lvlStr
+}
+
+func (h *lvlFilterHandler) Log(r *log15.Record) error {
+	h.mtx.RLock()
+	lvl, logTrace := h.lvl, h.logTrace
+	h.mtx.RUnlock()
+	if r.Lvl > lvl {
+		return nil
+	}
+	if !logTrace && strings.HasPrefix(r.Msg, TraceMsgPrefix) {
+		return nil
+	}
+	return h.Handler.Log(r)
+}
+
+// SetFileLevel changes the level of file logging at runtime. It returns an
+// error if file logging is not set up.
+func SetFileLevel(logLevel string) error {
+	h, _ := lvlHandlers()
+	if h == nil {
+		return common.NewBasicError("File logging not initialized", nil)
+	}
+	return h.setLevel(logLevel)
+}
+
+// SetConsoleLevel changes the level of console logging at runtime. It returns
+// an error if console logging is not set up.
+func SetConsoleLevel(logLevel string) error {
+	_, h := lvlHandlers()
+	if h == nil {
+		return common.NewBasicError("Console logging not initialized", nil)
+	}
+	return h.setLevel(logLevel)
+}
+
+// Levels contains the current logging levels. Empty levels denote loggers
+// that are not set up.
+type Levels struct {
+	File    string `json:"file,omitempty"`
+	Console string `json:"console,omitempty"`
+}
+
+// CurrentLevels returns the current logging levels.
+func CurrentLevels() Levels {
+	var levels Levels
+	file, cons := lvlHandlers()
+	if file != nil {
+		levels.File = file.level()
+	}
+	if cons != nil {
+		levels.Console = cons.level()
+	}
+	return levels
+}
+
+// LevelHTTPHandler returns an HTTP handler to inspect and change logging
+// levels at runtime. GET requests return the current levels as JSON. PUT and
+// POST requests change the levels according to the "file" and "console" form
+// values, and return the resulting levels.
+//
+// The handler changes the state of the service, so it must not be exposed on
+// a publicly reachable address.
+//
+// Example: curl -X PUT -d console=debug http://127.0.0.1:30452/loglevel
+func LevelHTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			if err := setLevelsFromRequest(r); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(CurrentLevels())
+	})
+}
+
+func setLevelsFromRequest(r *http.Request) error {
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+	if lvl := r.Form.Get("file"); lvl != "" {
+		if err := SetFileLevel(lvl); err != nil {
+			return err
+		}
+		Info("Changed file logging level", "level", lvl)
+	}
+	if lvl := r.Form.Get("console"); lvl != "" {
+		if err := SetConsoleLevel(lvl); err != nil {
+			return err
+		}
+		Info("Changed console logging level", "level", lvl)
+	}
+	return nil
+}
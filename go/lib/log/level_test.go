@@ -0,0 +1,89 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLvlFilterHandler(t *testing.T) {
+	var logged []string
+	h, err := newLvlFilterHandler("info", log15.FuncHandler(func(r *log15.Record) error {
+		logged = append(logged, r.Msg)
+		return nil
+	}))
+	require.NoError(t, err)
+	logAll := func() {
+		h.Log(&log15.Record{Lvl: log15.LvlInfo, Msg: "info"})
+		h.Log(&log15.Record{Lvl: log15.LvlDebug, Msg: "debug"})
+		h.Log(&log15.Record{Lvl: log15.LvlDebug, Msg: TraceMsgPrefix + "trace"})
+	}
+	logAll()
+	assert.Equal(t, []string{"info"}, logged)
+
+	logged = nil
+	require.NoError(t, h.setLevel("debug"))
+	logAll()
+	assert.Equal(t, []string{"info", "debug"}, logged)
+
+	logged = nil
+	require.NoError(t, h.setLevel("trace"))
+	logAll()
+	assert.Equal(t, []string{"info", "debug", TraceMsgPrefix + "trace"}, logged)
+
+	assert.Error(t, h.setLevel("verbose"))
+	assert.Equal(t, "trace", h.level())
+}
+
+func TestLevelHTTPHandler(t *testing.T) {
+	h, err := newLvlFilterHandler("crit", log15.DiscardHandler())
+	require.NoError(t, err)
+	setConsLvl(h)
+	defer setConsLvl(nil)
+	handler := LevelHTTPHandler()
+
+	t.Run("GET returns levels", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/loglevel", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var levels Levels
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&levels))
+		assert.Equal(t, Levels{Console: "crit"}, levels)
+	})
+	t.Run("PUT changes levels", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/loglevel",
+			strings.NewReader(url.Values{"console": {"debug"}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "debug", CurrentLevels().Console)
+	})
+	t.Run("PUT for uninitialized file logging fails", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/loglevel?file=debug", nil)
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -43,6 +43,35 @@ var (
 	logConsHandler Handler
 )
 
+const (
+	// FormatHuman is the human readable log format.
+	FormatHuman = "human"
+	// FormatJSON formats every log record as a single JSON object per line.
+	FormatJSON = "json"
+)
+
+// ValidateFormat returns an error if logFormat is not a supported log format.
+// The empty string is a valid format and denotes FormatHuman.
+func ValidateFormat(logFormat string) error {
+	switch logFormat {
+	case "", FormatHuman, FormatJSON:
+		return nil
+	default:
+		return common.NewBasicError("Unknown log format", nil, "format", logFormat)
+	}
+}
+
+func newFormat(logFormat string, cMap map[log15.Lvl]int) (log15.Format, error) {
+	switch logFormat {
+	case "", FormatHuman:
+		return fmt15.Fmt15Format(cMap), nil
+	case FormatJSON:
+		return log15.JsonFormat(), nil
+	default:
+		return nil, common.NewBasicError("Unknown log format", nil, "format", logFormat)
+	}
+}
+
 // SetupLogFile initializes a file for logging. The path is logDir/name.log if
 // name doesn't already contain the .log extension, or logDir/name otherwise.
 // logLevel can be one of trace, debug, info, warn, error, and crit and states
@@ -52,14 +81,18 @@ var (
 // old log files to retain. If logFlush > 0, logging output is
 // buffered, and flushed every logFlush seconds.  If logFlush < 0: logging
 // output is buffered, but must be manually flushed by calling Flush(). If
-// logFlush = 0 logging output is unbuffered and Flush() is a no-op.
+// logFlush = 0 logging output is unbuffered and Flush() is a no-op. logFormat
+// is one of FormatHuman and FormatJSON.
 func SetupLogFile(name string, logDir string, logLevel string, logSize int, logAge int,
-	logBackups int, logFlush int) error {
+	logBackups int, logFlush int, logFormat string) error {
 
-	logLvl, err := log15.LvlFromString(changeTraceToDebug(logLevel))
-	if err != nil {
+	if _, err := log15.LvlFromString(changeTraceToDebug(logLevel)); err != nil {
 		return common.NewBasicError("Unable to parse log.level flag:", err)
 	}
+	format, err := newFormat(logFormat, nil)
+	if err != nil {
+		return err
+	}
 
 	// Strip .log extension s.t. config files can contain the exact filename
 	// while not breaking existing behavior for apps that don't contain the
@@ -78,12 +111,12 @@ func SetupLogFile(name string, logDir string, logLevel string, logSize int, logA
 		fileLogger = logBuf
 	}
 
-	logFileHandler = log15.LvlFilterHandler(logLvl,
-		log15.StreamHandler(fileLogger, fmt15.Fmt15Format(nil)))
-	if logLevel != LvlTraceStr {
-		// Discard trace messages
-		logFileHandler = FilterTraceHandler(logFileHandler)
+	lvlHandler, err := newLvlFilterHandler(logLevel, log15.StreamHandler(fileLogger, format))
+	if err != nil {
+		return common.NewBasicError("Unable to parse log.level flag:", err)
 	}
+	setFileLvl(lvlHandler)
+	logFileHandler = lvlHandler
 	setHandlers()
 
 	if logFlush > 0 {
@@ -100,21 +133,21 @@ func SetupLogFile(name string, logDir string, logLevel string, logSize int, logA
 // SetupLogConsole sets up logging on default stderr. logLevel can be one of
 // trace, debug, info, warn, error, and crit, and states the minimum level of
 // logging events that gets printed to the console.
-func SetupLogConsole(logLevel string) error {
-	lvl, err := log15.LvlFromString(changeTraceToDebug(logLevel))
-	if err != nil {
-		return common.NewBasicError("Unable to parse log.console flag:", err)
-	}
+func SetupLogConsole(logLevel string, logFormat string) error {
 	var cMap map[log15.Lvl]int
 	if isatty.IsTerminal(os.Stderr.Fd()) {
 		cMap = fmt15.ColorMap
 	}
-	logConsHandler = log15.LvlFilterHandler(lvl,
-		log15.StreamHandler(os.Stderr, fmt15.Fmt15Format(cMap)))
-	if logLevel != LvlTraceStr {
-		// Discard trace messages
-		logConsHandler = FilterTraceHandler(logConsHandler)
+	format, err := newFormat(logFormat, cMap)
+	if err != nil {
+		return err
 	}
+	lvlHandler, err := newLvlFilterHandler(logLevel, log15.StreamHandler(os.Stderr, format))
+	if err != nil {
+		return common.NewBasicError("Unable to parse log.console flag:", err)
+	}
+	setConsLvl(lvlHandler)
+	logConsHandler = lvlHandler
 	setHandlers()
 	return nil
 }
@@ -136,6 +169,9 @@ func setHandlers() {
 	case logConsHandler != nil: // logFileHandler == nil
 		handler = logConsHandler
 	}
+	if handler != nil && sampling != nil {
+		handler = SamplingHandler(handler, *sampling)
+	}
 	log15.Root().SetHandler(handler)
 }
 
@@ -0,0 +1,126 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"sync"
+	"time"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/scionproto/scion/go/lib/common"
+)
+
+// sampling is the sampling configuration applied to the root handler. If nil,
+// no sampling is performed.
+var sampling *SamplingConfig
+
+// SamplingConfig describes how log records are sampled. Sampling only applies
+// to records of level info and lower; warnings and more severe records are
+// never dropped.
+type SamplingConfig struct {
+	// Interval is the duration of a sampling window. Counters are reset at
+	// the start of every window.
+	Interval time.Duration
+	// First is the number of records with the same level and message that are
+	// logged in every window before sampling starts.
+	First int
+	// Thereafter is the sampling rate after the first records have been
+	// logged, i.e., only every Thereafter-th record is logged. If 0, all
+	// records after the first are dropped.
+	Thereafter int
+}
+
+// Validate checks that the sampling configuration is consistent. If sampling
+// is enabled, at least one of First and Thereafter must be set, otherwise all
+// debug and info records would be dropped.
+func (cfg SamplingConfig) Validate() error {
+	if cfg.Interval < 0 {
+		return common.NewBasicError("Negative sampling interval", nil, "interval", cfg.Interval)
+	}
+	if cfg.First < 0 || cfg.Thereafter < 0 {
+		return common.NewBasicError("Negative sampling rate", nil,
+			"first", cfg.First, "thereafter", cfg.Thereafter)
+	}
+	if cfg.Interval > 0 && cfg.First == 0 && cfg.Thereafter == 0 {
+		return common.NewBasicError("Sampling drops all records, set first or thereafter", nil,
+			"interval", cfg.Interval)
+	}
+	return nil
+}
+
+// SetupSampling enables sampling of log records on the root handler. An
+// interval of 0 disables sampling.
+func SetupSampling(cfg SamplingConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if cfg.Interval == 0 {
+		sampling = nil
+	} else {
+		sampling = &cfg
+	}
+	setHandlers()
+	return nil
+}
+
+var _ Handler = (*samplingHandler)(nil)
+
+type samplingHandler struct {
+	log15.Handler
+	cfg SamplingConfig
+
+	mtx         sync.Mutex
+	windowStart time.Time
+	counts      map[string]int
+}
+
+// SamplingHandler returns a handler that rate-limits records with identical
+// level and message according to cfg before passing them to handler.
+func SamplingHandler(handler log15.Handler, cfg SamplingConfig) log15.Handler {
+	return &samplingHandler{
+		Handler: handler,
+		cfg:     cfg,
+		counts:  make(map[string]int),
+	}
+}
+
+func (h *samplingHandler) Log(r *log15.Record) error {
+	// Lower log15 levels are more severe; warnings and above are never sampled.
+	if r.Lvl > log15.LvlWarn && h.dropped(r) {
+		return nil
+	}
+	return h.Handler.Log(r)
+}
+
+// dropped returns true if r should not be logged.
+func (h *samplingHandler) dropped(r *log15.Record) bool {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if r.Time.Sub(h.windowStart) >= h.cfg.Interval {
+		h.windowStart = r.Time
+		h.counts = make(map[string]int)
+	}
+	key := r.Lvl.String() + r.Msg
+	h.counts[key]++
+	n := h.counts[key]
+	if n <= h.cfg.First {
+		return false
+	}
+	if h.cfg.Thereafter == 0 {
+		return true
+	}
+	return (n-h.cfg.First)%h.cfg.Thereafter != 0
+}
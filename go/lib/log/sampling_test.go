@@ -0,0 +1,116 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSamplingHandler(t *testing.T) {
+	tests := map[string]struct {
+		Cfg      SamplingConfig
+		Lvl      log15.Lvl
+		Records  int
+		Expected int
+	}{
+		"first only": {
+			Cfg:      SamplingConfig{Interval: time.Minute, First: 3},
+			Lvl:      log15.LvlDebug,
+			Records:  10,
+			Expected: 3,
+		},
+		"first and thereafter": {
+			Cfg:      SamplingConfig{Interval: time.Minute, First: 2, Thereafter: 4},
+			Lvl:      log15.LvlInfo,
+			Records:  10,
+			Expected: 4,
+		},
+		"warnings are never sampled": {
+			Cfg:      SamplingConfig{Interval: time.Minute, First: 1},
+			Lvl:      log15.LvlWarn,
+			Records:  10,
+			Expected: 10,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var logged int
+			h := SamplingHandler(log15.FuncHandler(func(r *log15.Record) error {
+				logged++
+				return nil
+			}), test.Cfg)
+			now := time.Now()
+			for i := 0; i < test.Records; i++ {
+				h.Log(&log15.Record{Time: now, Lvl: test.Lvl, Msg: "msg"})
+			}
+			assert.Equal(t, test.Expected, logged)
+		})
+	}
+}
+
+func TestSamplingHandlerResetsWindow(t *testing.T) {
+	var logged int
+	h := SamplingHandler(log15.FuncHandler(func(r *log15.Record) error {
+		logged++
+		return nil
+	}), SamplingConfig{Interval: time.Second, First: 1})
+	now := time.Now()
+	h.Log(&log15.Record{Time: now, Lvl: log15.LvlDebug, Msg: "msg"})
+	h.Log(&log15.Record{Time: now, Lvl: log15.LvlDebug, Msg: "msg"})
+	h.Log(&log15.Record{Time: now, Lvl: log15.LvlDebug, Msg: "other"})
+	h.Log(&log15.Record{Time: now.Add(time.Second), Lvl: log15.LvlDebug, Msg: "msg"})
+	assert.Equal(t, 3, logged)
+}
+
+func TestSamplingConfigValidate(t *testing.T) {
+	tests := map[string]struct {
+		Cfg            SamplingConfig
+		ErrorAssertion assert.ErrorAssertionFunc
+	}{
+		"disabled": {
+			Cfg:            SamplingConfig{},
+			ErrorAssertion: assert.NoError,
+		},
+		"first only": {
+			Cfg:            SamplingConfig{Interval: time.Minute, First: 3},
+			ErrorAssertion: assert.NoError,
+		},
+		"thereafter only": {
+			Cfg:            SamplingConfig{Interval: time.Minute, Thereafter: 10},
+			ErrorAssertion: assert.NoError,
+		},
+		"drops everything": {
+			Cfg:            SamplingConfig{Interval: time.Minute},
+			ErrorAssertion: assert.Error,
+		},
+		"negative interval": {
+			Cfg:            SamplingConfig{Interval: -time.Minute, First: 1},
+			ErrorAssertion: assert.Error,
+		},
+		"negative rate": {
+			Cfg:            SamplingConfig{Interval: time.Minute, First: -1},
+			ErrorAssertion: assert.Error,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test.ErrorAssertion(t, test.Cfg.Validate())
+		})
+	}
+}
@@ -72,6 +72,10 @@ type Config struct {
 	// ReceiveBufferSize is the size of the operating system receive buffer, in
 	// bytes. If 0, the package constant is used instead.
 	ReceiveBufferSize int
+	// Conn is an existing socket that is used instead of opening a new one,
+	// e.g., a socket inherited from the service manager. It must be bound to
+	// the listen address, and connected to the remote address if set.
+	Conn *net.UDPConn
 }
 
 func (c *Config) getReceiveBufferSize() int {
@@ -233,7 +237,9 @@ func (cc *connUDPBase) initConnUDP(network string, listen, remote *overlay.Overl
 	if laddr == nil {
 		return common.NewBasicError("Invalid listen address", nil, "addr", listen)
 	}
-	if remote == nil {
+	if cfg.Conn != nil {
+		c = cfg.Conn
+	} else if remote == nil {
 		if c, err = net.ListenUDP(network, laddr); err != nil {
 			return common.NewBasicError("Error listening on socket", err,
 				"network", network, "listen", listen)
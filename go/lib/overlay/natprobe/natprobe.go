@@ -0,0 +1,188 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package natprobe implements a STUN-like probe with which end hosts behind a
+// NAT inside an AS discover their overlay address as seen by the border
+// routers.
+//
+// The end host sends a request from its overlay socket to the internal
+// overlay address of a border router. The border router answers with a reply
+// that contains the source address of the request. The end host then uses
+// this address as its public address when registering with the dispatcher,
+// such that it is used as source address of the sent packets, e.g.:
+//
+//	ip, err := natprobe.DiscoverIP(ctx, localIP, brAddr)
+//	public := &snet.Addr{IA: ia, Host: &addr.AppAddr{L3: addr.HostFromIP(ip), L4: l4}}
+//	conn, err := snet.ListenSCION("udp4", public)
+//
+// Border routers deliver packets to the end host overlay port of the
+// destination, so the NAT must forward that port to the host.
+//
+// Probe messages start with a magic value whose first byte is not a valid
+// SCION common header, so they cannot be confused with SCION packets.
+//
+// Request format:
+//
+//	magic (8B) | type (1B) | id (8B)
+//
+// Reply format:
+//
+//	magic (8B) | type (1B) | id (8B) | port (2B) | IP (4B or 16B)
+package natprobe
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+const (
+	// RequestLen is the length of a probe request.
+	RequestLen = len(magic) + 1 + 8
+	// MaxReplyLen is the maximum length of a probe reply.
+	MaxReplyLen = RequestLen + 2 + net.IPv6len
+	// DefaultRetryInterval is the interval after which a probe request is
+	// retransmitted if no reply is received.
+	DefaultRetryInterval = 500 * time.Millisecond
+)
+
+const (
+	magic = "SCIONNAT"
+
+	typeRequest byte = 1
+	typeReply   byte = 2
+)
+
+// IsRequest returns whether b is a probe request.
+func IsRequest(b []byte) bool {
+	return len(b) == RequestLen && bytes.HasPrefix(b, []byte(magic)) && b[len(magic)] == typeRequest
+}
+
+// NewRequest returns a probe request with the given id.
+func NewRequest(id uint64) []byte {
+	b := make([]byte, RequestLen)
+	copy(b, magic)
+	b[len(magic)] = typeRequest
+	binary.BigEndian.PutUint64(b[len(magic)+1:], id)
+	return b
+}
+
+// AppendReply appends the reply to the probe request req to b, and returns the
+// extended buffer. observed is the source address of the request. req must be
+// a valid request, see IsRequest.
+func AppendReply(b, req []byte, observed *net.UDPAddr) []byte {
+	ip := observed.IP
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	b = append(b, req...)
+	b[len(b)-RequestLen+len(magic)] = typeReply
+	b = append(b, byte(observed.Port>>8), byte(observed.Port))
+	return append(b, ip...)
+}
+
+// ParseReply parses the probe reply b, and returns the id and the observed
+// address it contains.
+func ParseReply(b []byte) (uint64, *net.UDPAddr, error) {
+	if len(b) < RequestLen+2 || !bytes.HasPrefix(b, []byte(magic)) || b[len(magic)] != typeReply {
+		return 0, nil, serrors.New("Not a probe reply", "len", len(b))
+	}
+	id := binary.BigEndian.Uint64(b[len(magic)+1:])
+	port := int(binary.BigEndian.Uint16(b[RequestLen:]))
+	ip := b[RequestLen+2:]
+	if len(ip) != net.IPv4len && len(ip) != net.IPv6len {
+		return 0, nil, serrors.New("Invalid IP length in probe reply", "len", len(ip))
+	}
+	return id, &net.UDPAddr{IP: append(net.IP(nil), ip...), Port: port}, nil
+}
+
+// Probe sends probe requests over conn to the border router at br, and
+// returns the address of conn as observed by the border router. Requests are
+// retransmitted every DefaultRetryInterval until a reply is received or ctx
+// is done. Packets other than the expected reply that are read from conn in
+// the meantime are dropped, so conn should not be in use yet.
+func Probe(ctx context.Context, conn net.PacketConn, br net.Addr) (*net.UDPAddr, error) {
+	id := rand.Uint64()
+	req := NewRequest(id)
+	defer conn.SetReadDeadline(time.Time{})
+	buf := make([]byte, common.MaxMTU)
+	for {
+		if _, err := conn.WriteTo(req, br); err != nil {
+			return nil, common.NewBasicError("Unable to send probe request", err, "br", br)
+		}
+		retry := time.Now().Add(DefaultRetryInterval)
+		if deadline, ok := ctx.Deadline(); ok && deadline.Before(retry) {
+			retry = deadline
+		}
+		if err := conn.SetReadDeadline(retry); err != nil {
+			return nil, common.NewBasicError("Unable to set read deadline", err)
+		}
+		observed, err := readReply(conn, buf, id)
+		if err == nil {
+			return observed, nil
+		}
+		if !isTimeout(err) {
+			return nil, common.NewBasicError("Unable to read probe reply", err, "br", br)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, common.NewBasicError("No probe reply received", ctx.Err(), "br", br)
+		default:
+		}
+	}
+}
+
+// DiscoverIP returns the address of the host with the local IP as observed by
+// the border router at br. The probe is sent from a temporary socket bound to
+// local, so the returned IP is only meaningful for NATs that map all ports of
+// a host to the same public IP.
+func DiscoverIP(ctx context.Context, local net.IP, br *net.UDPAddr) (net.IP, error) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: local})
+	if err != nil {
+		return nil, common.NewBasicError("Unable to open probe socket", err, "local", local)
+	}
+	defer conn.Close()
+	observed, err := Probe(ctx, conn, br)
+	if err != nil {
+		return nil, err
+	}
+	return observed.IP, nil
+}
+
+// readReply reads from conn until the reply with the given id is received or
+// an error occurs.
+func readReply(conn net.PacketConn, buf []byte, id uint64) (*net.UDPAddr, error) {
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			return nil, err
+		}
+		replyID, observed, err := ParseReply(buf[:n])
+		if err != nil || replyID != id {
+			continue
+		}
+		return observed, nil
+	}
+}
+
+func isTimeout(err error) bool {
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
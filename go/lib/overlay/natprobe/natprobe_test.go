@@ -0,0 +1,110 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package natprobe
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReply(t *testing.T) {
+	tests := map[string]*net.UDPAddr{
+		"ipv4": {IP: net.IP{192, 0, 2, 1}, Port: 30041},
+		"ipv6": {IP: net.ParseIP("2001:db8::1"), Port: 30041},
+	}
+	for name, observed := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := NewRequest(42)
+			require.True(t, IsRequest(req))
+			reply := AppendReply(nil, req, observed)
+			assert.False(t, IsRequest(reply))
+			assert.True(t, len(reply) <= MaxReplyLen)
+			id, addr, err := ParseReply(reply)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(42), id)
+			assert.Equal(t, observed.String(), addr.String())
+		})
+	}
+	t.Run("request is not a reply", func(t *testing.T) {
+		_, _, err := ParseReply(NewRequest(42))
+		assert.Error(t, err)
+	})
+}
+
+func TestProbe(t *testing.T) {
+	br, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IP{127, 0, 0, 1}})
+	require.NoError(t, err)
+	defer br.Close()
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, src, err := br.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			if !IsRequest(buf[:n]) {
+				continue
+			}
+			// Send garbage first, which the client must ignore.
+			br.WriteToUDP([]byte("garbage"), src)
+			br.WriteToUDP(AppendReply(nil, buf[:n], src), src)
+		}
+	}()
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IP{127, 0, 0, 1}})
+	require.NoError(t, err)
+	defer conn.Close()
+	ctx, cancelF := context.WithTimeout(context.Background(), time.Second)
+	defer cancelF()
+	observed, err := Probe(ctx, conn, br.LocalAddr())
+	require.NoError(t, err)
+	assert.Equal(t, conn.LocalAddr().String(), observed.String())
+}
+
+func TestDiscoverIP(t *testing.T) {
+	br, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IP{127, 0, 0, 1}})
+	require.NoError(t, err)
+	defer br.Close()
+	go func() {
+		buf := make([]byte, 1500)
+		n, src, err := br.ReadFromUDP(buf)
+		if err != nil || !IsRequest(buf[:n]) {
+			return
+		}
+		br.WriteToUDP(AppendReply(nil, buf[:n], src), src)
+	}()
+	ctx, cancelF := context.WithTimeout(context.Background(), time.Second)
+	defer cancelF()
+	ip, err := DiscoverIP(ctx, net.IP{127, 0, 0, 1}, br.LocalAddr().(*net.UDPAddr))
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", ip.String())
+}
+
+func TestProbeTimeout(t *testing.T) {
+	br, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IP{127, 0, 0, 1}})
+	require.NoError(t, err)
+	defer br.Close()
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IP{127, 0, 0, 1}})
+	require.NoError(t, err)
+	defer conn.Close()
+	ctx, cancelF := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancelF()
+	_, err = Probe(ctx, conn, br.LocalAddr())
+	assert.Error(t, err)
+}
@@ -15,9 +15,11 @@
 package pathdb
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/json"
 
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/pathpol"
 )
@@ -53,3 +55,9 @@ func HashPolicy(policy *pathpol.Policy) (PolicyHash, error) {
 func (h PolicyHash) String() string {
 	return common.RawBytes(h).String()
 }
+
+// Ping checks that db is reachable by performing a cheap read.
+func Ping(ctx context.Context, db Read) error {
+	_, err := db.GetNextQuery(ctx, addr.IA{}, addr.IA{}, NoPolicy)
+	return err
+}
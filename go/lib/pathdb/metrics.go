@@ -48,6 +48,7 @@ const (
 	promOpGetAll          promOp = "get_all"
 	promOpInsertNextQuery promOp = "insert_next_query"
 	promOpGetNextQuery    promOp = "get_next_query"
+	promOpGetNextQueries  promOp = "get_next_queries"
 	promOpDeleteExpiredNQ promOp = "delete_expired_nq"
 	promOpDeleteNQ        promOp = "delete_nq"
 
@@ -266,6 +267,18 @@ func (db *metricsExecutor) GetNextQuery(ctx context.Context, src, dst addr.IA,
 	return t, err
 }
 
+func (db *metricsExecutor) GetNextQueries(ctx context.Context, src, dst addr.IA,
+	policy PolicyHash) ([]NextQueryEntry, error) {
+
+	var entries []NextQueryEntry
+	var err error
+	db.metrics.Observe(ctx, promOpGetNextQueries, func(ctx context.Context) error {
+		entries, err = db.pathDB.GetNextQueries(ctx, src, dst, policy)
+		return err
+	})
+	return entries, err
+}
+
 func (db *metricsExecutor) DeleteExpiredNQ(ctx context.Context, now time.Time) (int, error) {
 	var cnt int
 	var err error
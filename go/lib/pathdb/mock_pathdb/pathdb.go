@@ -158,6 +158,21 @@ func (mr *MockPathDBMockRecorder) GetAll(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockPathDB)(nil).GetAll), arg0)
 }
 
+// GetNextQueries mocks base method
+func (m *MockPathDB) GetNextQueries(arg0 context.Context, arg1, arg2 addr.IA, arg3 pathdb.PolicyHash) ([]pathdb.NextQueryEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNextQueries", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]pathdb.NextQueryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNextQueries indicates an expected call of GetNextQueries
+func (mr *MockPathDBMockRecorder) GetNextQueries(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNextQueries", reflect.TypeOf((*MockPathDB)(nil).GetNextQueries), arg0, arg1, arg2, arg3)
+}
+
 // GetNextQuery mocks base method
 func (m *MockPathDB) GetNextQuery(arg0 context.Context, arg1, arg2 addr.IA, arg3 pathdb.PolicyHash) (time.Time, error) {
 	m.ctrl.T.Helper()
@@ -369,6 +384,21 @@ func (mr *MockTransactionMockRecorder) GetAll(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTransaction)(nil).GetAll), arg0)
 }
 
+// GetNextQueries mocks base method
+func (m *MockTransaction) GetNextQueries(arg0 context.Context, arg1, arg2 addr.IA, arg3 pathdb.PolicyHash) ([]pathdb.NextQueryEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNextQueries", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]pathdb.NextQueryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNextQueries indicates an expected call of GetNextQueries
+func (mr *MockTransactionMockRecorder) GetNextQueries(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNextQueries", reflect.TypeOf((*MockTransaction)(nil).GetNextQueries), arg0, arg1, arg2, arg3)
+}
+
 // GetNextQuery mocks base method
 func (m *MockTransaction) GetNextQuery(arg0 context.Context, arg1, arg2 addr.IA, arg3 pathdb.PolicyHash) (time.Time, error) {
 	m.ctrl.T.Helper()
@@ -556,6 +586,21 @@ func (mr *MockReadWriteMockRecorder) GetAll(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockReadWrite)(nil).GetAll), arg0)
 }
 
+// GetNextQueries mocks base method
+func (m *MockReadWrite) GetNextQueries(arg0 context.Context, arg1, arg2 addr.IA, arg3 pathdb.PolicyHash) ([]pathdb.NextQueryEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNextQueries", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]pathdb.NextQueryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNextQueries indicates an expected call of GetNextQueries
+func (mr *MockReadWriteMockRecorder) GetNextQueries(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNextQueries", reflect.TypeOf((*MockReadWrite)(nil).GetNextQueries), arg0, arg1, arg2, arg3)
+}
+
 // GetNextQuery mocks base method
 func (m *MockReadWrite) GetNextQuery(arg0 context.Context, arg1, arg2 addr.IA, arg3 pathdb.PolicyHash) (time.Time, error) {
 	m.ctrl.T.Helper()
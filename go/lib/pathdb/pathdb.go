@@ -43,6 +43,20 @@ type Read interface {
 	// GetNextQuery returns the nextQuery timestamp for the given src-dst pair
 	// and policy , or a zero time if it hasn't been queried.
 	GetNextQuery(ctx context.Context, src, dst addr.IA, policy PolicyHash) (time.Time, error)
+	// GetNextQueries returns all next query entries that match the given
+	// parameters. Note that only parameters with non-zero value are
+	// considered, i.e. calling this function with all zero values returns all
+	// next query entries.
+	GetNextQueries(ctx context.Context, src, dst addr.IA,
+		policy PolicyHash) ([]NextQueryEntry, error)
+}
+
+// NextQueryEntry is a next query entry as stored in the path DB.
+type NextQueryEntry struct {
+	Src       addr.IA
+	Dst       addr.IA
+	Policy    PolicyHash
+	NextQuery time.Time
 }
 
 // Write defines all write operations of the path DB.
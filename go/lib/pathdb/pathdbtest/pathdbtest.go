@@ -122,6 +122,8 @@ func TestPathDB(t *testing.T, db TestablePathDB) {
 		testWrapper(testGetModifiedIDs))
 	t.Run("NextQuery",
 		testWrapper(testNextQuery))
+	t.Run("GetNextQueries",
+		testWrapper(testGetNextQueries))
 	t.Run("DeleteExpiredNQ",
 		tableWrapper(false, testNextQueryDeleteExpired))
 	t.Run("DeleteNQ",
@@ -172,6 +174,8 @@ func TestPathDB(t *testing.T, db TestablePathDB) {
 			txTestWrapper(testGetModifiedIDs))
 		t.Run("NextQuery",
 			txTestWrapper(testNextQuery))
+		t.Run("GetNextQueries",
+			txTestWrapper(testGetNextQueries))
 		t.Run("DeleteExpiredNQ",
 			tableWrapper(true, testNextQueryDeleteExpired))
 		t.Run("DeleteNQ",
@@ -589,6 +593,64 @@ func testNextQuery(t *testing.T, _ *gomock.Controller, pathDB pathdb.ReadWrite)
 	assert.Error(t, err)
 }
 
+func testGetNextQueries(t *testing.T, _ *gomock.Controller, pathDB pathdb.ReadWrite) {
+	ctx, cancelF := context.WithTimeout(context.Background(), timeout)
+	defer cancelF()
+	ia110 := xtest.MustParseIA("1-ff00:0:110")
+	ia120 := xtest.MustParseIA("1-ff00:0:120")
+	ia130 := xtest.MustParseIA("1-ff00:0:130")
+	pol := []byte("policy")
+	now := time.Now()
+	for _, nq := range []nqDescriptor{
+		{ia110, ia120, nil}, {ia110, ia130, nil}, {ia120, ia130, pol}} {
+
+		_, err := pathDB.InsertNextQuery(ctx, nq.Src, nq.Dst, nq.Policy, now)
+		require.NoError(t, err)
+	}
+	tests := map[string]struct {
+		Src      addr.IA
+		Dst      addr.IA
+		Policy   pathdb.PolicyHash
+		Expected []nqDescriptor
+	}{
+		"all": {
+			Expected: []nqDescriptor{
+				{ia110, ia120, pathdb.NoPolicy}, {ia110, ia130, pathdb.NoPolicy},
+				{ia120, ia130, pol}},
+		},
+		"src": {
+			Src:      ia120,
+			Expected: []nqDescriptor{{ia120, ia130, pol}},
+		},
+		"dst": {
+			Dst: ia130,
+			Expected: []nqDescriptor{
+				{ia110, ia130, pathdb.NoPolicy}, {ia120, ia130, pol}},
+		},
+		"src and dst": {
+			Src:      ia110,
+			Dst:      ia130,
+			Expected: []nqDescriptor{{ia110, ia130, pathdb.NoPolicy}},
+		},
+		"not matching": {
+			Src:    ia110,
+			Policy: pol,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			entries, err := pathDB.GetNextQueries(ctx, test.Src, test.Dst, test.Policy)
+			require.NoError(t, err)
+			var actual []nqDescriptor
+			for _, entry := range entries {
+				assert.Equal(t, now.Unix(), entry.NextQuery.Unix())
+				actual = append(actual, nqDescriptor{entry.Src, entry.Dst, entry.Policy})
+			}
+			assert.ElementsMatch(t, test.Expected, actual)
+		})
+	}
+}
+
 // nqDescriptor describes a next query entry.
 type nqDescriptor struct {
 	Src    addr.IA
@@ -654,6 +654,42 @@ func (e *executor) GetNextQuery(ctx context.Context, src, dst addr.IA,
 	return time.Unix(0, nanos), nil
 }
 
+func (e *executor) GetNextQueries(ctx context.Context, src, dst addr.IA,
+	policy pathdb.PolicyHash) ([]pathdb.NextQueryEntry, error) {
+
+	e.RLock()
+	defer e.RUnlock()
+	if e.db == nil {
+		return nil, serrors.New("No database open")
+	}
+	query := `
+		SELECT SrcIsdID, SrcAsID, DstIsdID, DstAsID, Policy, NextQuery FROM NextQuery`
+	whereParts, args := nqFilter(src, dst, policy)
+	if len(whereParts) > 0 {
+		query = fmt.Sprintf("%s WHERE %s", query, strings.Join(whereParts, " AND "))
+	}
+	rows, err := e.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, common.NewBasicError("Error looking up next query entries", err)
+	}
+	defer rows.Close()
+	var entries []pathdb.NextQueryEntry
+	for rows.Next() {
+		var entry pathdb.NextQueryEntry
+		var policy []byte
+		var nanos int64
+		err := rows.Scan(&entry.Src.I, &entry.Src.A, &entry.Dst.I, &entry.Dst.A,
+			&policy, &nanos)
+		if err != nil {
+			return nil, common.NewBasicError("Error reading DB response", err)
+		}
+		entry.Policy = policy
+		entry.NextQuery = time.Unix(0, nanos)
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
 func (e *executor) DeleteExpiredNQ(ctx context.Context, now time.Time) (int, error) {
 	return e.deleteInTx(ctx, func(tx *sql.Tx) (sql.Result, error) {
 		delStmt := `DELETE FROM NextQuery WHERE NextQuery < ?`
@@ -666,23 +702,30 @@ func (e *executor) DeleteNQ(ctx context.Context, src, dst addr.IA,
 
 	return e.deleteInTx(ctx, func(tx *sql.Tx) (sql.Result, error) {
 		delStmt := `DELETE FROM NextQuery`
-		var whereParts []string
-		var args []interface{}
-		if !src.IsZero() {
-			whereParts = append(whereParts, "SrcIsdID = ? AND SrcASID = ?")
-			args = append(args, src.I, src.A)
-		}
-		if !dst.IsZero() {
-			whereParts = append(whereParts, "DstIsdID = ? AND DstASID = ?")
-			args = append(args, dst.I, dst.A)
-		}
-		if policy != nil {
-			whereParts = append(whereParts, "Policy = ?")
-			args = append(args, policy)
-		}
+		whereParts, args := nqFilter(src, dst, policy)
 		if len(whereParts) > 0 {
 			delStmt = fmt.Sprintf("%s WHERE %s", delStmt, strings.Join(whereParts, " AND "))
 		}
 		return tx.ExecContext(ctx, delStmt, args...)
 	})
 }
+
+// nqFilter returns the where clauses and the corresponding arguments to
+// filter the NextQuery table. Only non-zero parameters are considered.
+func nqFilter(src, dst addr.IA, policy pathdb.PolicyHash) ([]string, []interface{}) {
+	var whereParts []string
+	var args []interface{}
+	if !src.IsZero() {
+		whereParts = append(whereParts, "SrcIsdID = ? AND SrcASID = ?")
+		args = append(args, src.I, src.A)
+	}
+	if !dst.IsZero() {
+		whereParts = append(whereParts, "DstIsdID = ? AND DstASID = ?")
+		args = append(args, dst.I, dst.A)
+	}
+	if policy != nil {
+		whereParts = append(whereParts, "Policy = ?")
+		args = append(args, policy)
+	}
+	return whereParts, args
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathmgr
+
+import (
+	"context"
+	"sync"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/spath/spathmeta"
+)
+
+// queryKey identifies identical path queries.
+type queryKey struct {
+	src   addr.IA
+	dst   addr.IA
+	flags sciond.PathReqFlags
+}
+
+type pendingQuery struct {
+	done chan struct{}
+	aps  spathmeta.AppPathSet
+}
+
+// queryGroup deduplicates concurrent path queries. While a query is pending,
+// identical queries wait for its result instead of querying SCIOND again.
+type queryGroup struct {
+	mtx     sync.Mutex
+	pending map[queryKey]*pendingQuery
+}
+
+func newQueryGroup() *queryGroup {
+	return &queryGroup{pending: make(map[queryKey]*pendingQuery)}
+}
+
+// do runs query, unless an identical query is pending, in which case its
+// result is returned. Waiting for a pending query is subject to ctx; if ctx
+// is done first, an empty set is returned. Note that the pending query itself
+// is subject to the context of the caller that started it.
+func (g *queryGroup) do(ctx context.Context, key queryKey,
+	query func() spathmeta.AppPathSet) spathmeta.AppPathSet {
+
+	g.mtx.Lock()
+	if q, ok := g.pending[key]; ok {
+		g.mtx.Unlock()
+		select {
+		case <-q.done:
+			// Every caller gets its own copy, such that callers can modify
+			// the set.
+			return q.aps.Copy()
+		case <-ctx.Done():
+			return make(spathmeta.AppPathSet)
+		}
+	}
+	q := &pendingQuery{done: make(chan struct{})}
+	g.pending[key] = q
+	g.mtx.Unlock()
+
+	defer func() {
+		g.mtx.Lock()
+		delete(g.pending, key)
+		g.mtx.Unlock()
+		close(q.done)
+	}()
+	q.aps = query()
+	return q.aps.Copy()
+}
@@ -114,6 +114,18 @@ func (m *MockResolver) EXPECT() *MockResolverMockRecorder {
 	return m.recorder
 }
 
+// Prewarm mocks base method
+func (m *MockResolver) Prewarm(arg0 context.Context, arg1 addr.IA, arg2 []addr.IA) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Prewarm", arg0, arg1, arg2)
+}
+
+// Prewarm indicates an expected call of Prewarm
+func (mr *MockResolverMockRecorder) Prewarm(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prewarm", reflect.TypeOf((*MockResolver)(nil).Prewarm), arg0, arg1, arg2)
+}
+
 // Query mocks base method
 func (m *MockResolver) Query(arg0 context.Context, arg1, arg2 addr.IA, arg3 sciond.PathReqFlags) spathmeta.AppPathSet {
 	m.ctrl.T.Helper()
@@ -180,6 +192,18 @@ func (mr *MockResolverMockRecorder) Sciond() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sciond", reflect.TypeOf((*MockResolver)(nil).Sciond))
 }
 
+// SetTimers mocks base method
+func (m *MockResolver) SetTimers(arg0 addr.IA, arg1 pathmgr.Timers) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTimers", arg0, arg1)
+}
+
+// SetTimers indicates an expected call of SetTimers
+func (mr *MockResolverMockRecorder) SetTimers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTimers", reflect.TypeOf((*MockResolver)(nil).SetTimers), arg0, arg1)
+}
+
 // Watch mocks base method
 func (m *MockResolver) Watch(arg0 context.Context, arg1, arg2 addr.IA) (*pathmgr.SyncPaths, error) {
 	m.ctrl.T.Helper()
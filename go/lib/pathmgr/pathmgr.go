@@ -27,12 +27,18 @@
 // paths, the resolver will atomically change the value within the SyncPaths
 // object. The data can be accessed by calling Load again.
 //
+// Concurrent identical queries are deduplicated, such that only one request
+// is sent to SCIOND. Destinations that are known in advance can be prewarmed
+// via 'Prewarm'; queries to them are answered from a watch without waiting
+// for SCIOND.
+//
 // An example of how this package can be used can be found in the associated
 // infra test file.
 package pathmgr
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/scionproto/scion/go/lib/addr"
@@ -61,6 +67,18 @@ func (timers *Timers) initDefaults() {
 	}
 }
 
+// withDefaults returns a copy of timers in which every timer that is left
+// uninitialized is set to the corresponding value in defaults.
+func (timers Timers) withDefaults(defaults Timers) Timers {
+	if timers.NormalRefire == 0 {
+		timers.NormalRefire = defaults.NormalRefire
+	}
+	if timers.ErrorRefire == 0 {
+		timers.ErrorRefire = defaults.ErrorRefire
+	}
+	return timers
+}
+
 func (timers *Timers) GetWait(isError bool) time.Duration {
 	if isError {
 		return timers.ErrorRefire
@@ -92,6 +110,12 @@ type Resolver interface {
 	// QueryFilter returns a set of paths between src and dst that satisfy
 	// policy. A nil policy will not delete any paths.
 	QueryFilter(ctx context.Context, src, dst addr.IA, policy Policy) spathmeta.AppPathSet
+	// Prewarm starts watches for the paths from src to each of dsts, and
+	// blocks until the first answers from SCIOND are received or ctx is done.
+	// As long as a watch has paths, Query calls without flags for its
+	// destination return its paths instead of querying SCIOND. The watches
+	// are kept for the lifetime of the resolver.
+	Prewarm(ctx context.Context, src addr.IA, dsts []addr.IA)
 	// Watch returns an object that periodically polls for paths between src
 	// and dst.
 	//
@@ -112,6 +136,13 @@ type Resolver interface {
 	WatchFilter(ctx context.Context, src, dst addr.IA, filter Policy) (*SyncPaths, error)
 	// WatchCount returns the number of active watchers.
 	WatchCount() int
+	// SetTimers overrides the timers for watches to dst. This can be used to
+	// refresh paths to latency-critical destinations more often, and paths
+	// to background destinations less often. Timers that are left
+	// uninitialized fall back to the timers of the resolver; passing an empty
+	// Timers value removes the override. Running watches to dst immediately
+	// switch to the new timers.
+	SetTimers(dst addr.IA, timers Timers)
 	// RevokeRaw informs SCIOND of a revocation.
 	RevokeRaw(ctx context.Context, rawSRevInfo common.RawBytes)
 	// Revoke informs SCIOND of a revocation.
@@ -124,6 +155,17 @@ type resolver struct {
 	sciondConn   sciond.Connector
 	timers       Timers
 	watchFactory *WatchFactory
+	queries      *queryGroup
+
+	// prewarmedMtx protects prewarmed.
+	prewarmedMtx sync.RWMutex
+	// prewarmed contains the watches of the prewarmed destinations.
+	prewarmed map[prewarmKey]*SyncPaths
+
+	// dstTimersMtx protects dstTimers.
+	dstTimersMtx sync.RWMutex
+	// dstTimers contains the per-destination timer overrides.
+	dstTimers map[addr.IA]Timers
 }
 
 // New creates a new path management context.
@@ -139,6 +181,9 @@ func New(conn sciond.Connector, timers Timers) Resolver {
 		sciondConn:   conn,
 		timers:       timers,
 		watchFactory: NewWatchFactory(timers),
+		queries:      newQueryGroup(),
+		prewarmed:    make(map[prewarmKey]*SyncPaths),
+		dstTimers:    make(map[addr.IA]Timers),
 	}
 	return r
 }
@@ -146,6 +191,27 @@ func New(conn sciond.Connector, timers Timers) Resolver {
 func (r *resolver) Query(ctx context.Context, src, dst addr.IA,
 	flags sciond.PathReqFlags) spathmeta.AppPathSet {
 
+	if flags == (sciond.PathReqFlags{}) {
+		if aps := r.prewarmedPaths(src, dst); len(aps) > 0 {
+			return aps
+		}
+	}
+	return r.query(ctx, src, dst, flags)
+}
+
+// query queries SCIOND for paths, deduplicating concurrent identical queries.
+func (r *resolver) query(ctx context.Context, src, dst addr.IA,
+	flags sciond.PathReqFlags) spathmeta.AppPathSet {
+
+	key := queryKey{src: src, dst: dst, flags: flags}
+	return r.queries.do(ctx, key, func() spathmeta.AppPathSet {
+		return r.querySciond(ctx, src, dst, flags)
+	})
+}
+
+func (r *resolver) querySciond(ctx context.Context, src, dst addr.IA,
+	flags sciond.PathReqFlags) spathmeta.AppPathSet {
+
 	reply, err := r.sciondConn.Paths(ctx, dst, src, numReqPaths, flags)
 	if err != nil {
 		r.logger(ctx).Error("SCIOND network error", "err", err)
@@ -171,7 +237,9 @@ func (r *resolver) QueryFilter(ctx context.Context, src, dst addr.IA,
 func (r *resolver) WatchFilter(ctx context.Context, src, dst addr.IA,
 	filter Policy) (*SyncPaths, error) {
 
-	aps := r.Query(ctx, src, dst, sciond.PathReqFlags{})
+	// Watches query SCIOND directly, because the prewarmed paths are
+	// themselves kept up to date by watches.
+	aps := r.query(ctx, src, dst, sciond.PathReqFlags{})
 	if filter != nil {
 		aps = psToAps(filter.Filter(apsToPs(aps)))
 	}
@@ -179,12 +247,12 @@ func (r *resolver) WatchFilter(ctx context.Context, src, dst addr.IA,
 	sp.Update(aps)
 
 	query := &queryConfig{
-		querier: Querier(r),
+		querier: querierFunc(r.query),
 		src:     src,
 		dst:     dst,
 		filter:  filter,
 	}
-	pp := NewPollingPolicy(filter != nil, r.timers)
+	pp := NewPollingPolicy(filter != nil, r.timersFor(dst))
 	w := r.watchFactory.New(sp, query, pp)
 	sp.setDestructor(w.Destroy)
 
@@ -199,10 +267,83 @@ func (r *resolver) Watch(ctx context.Context, src, dst addr.IA) (*SyncPaths, err
 	return r.WatchFilter(ctx, src, dst, nil)
 }
 
+func (r *resolver) Prewarm(ctx context.Context, src addr.IA, dsts []addr.IA) {
+	var wg sync.WaitGroup
+	for _, dst := range dsts {
+		key := prewarmKey{src: src, dst: dst}
+		r.prewarmedMtx.RLock()
+		_, ok := r.prewarmed[key]
+		r.prewarmedMtx.RUnlock()
+		if ok {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer log.LogPanicAndExit()
+			defer wg.Done()
+			r.prewarm(ctx, key)
+		}()
+	}
+	wg.Wait()
+}
+
+func (r *resolver) prewarm(ctx context.Context, key prewarmKey) {
+	sp, err := r.Watch(ctx, key.src, key.dst)
+	if err != nil {
+		r.logger(ctx).Error("Unable to prewarm paths", "src", key.src, "dst", key.dst,
+			"err", err)
+		return
+	}
+	r.prewarmedMtx.Lock()
+	defer r.prewarmedMtx.Unlock()
+	if _, ok := r.prewarmed[key]; ok {
+		// A concurrent call prewarmed the destination first.
+		sp.Destroy()
+		return
+	}
+	r.prewarmed[key] = sp
+}
+
+// prewarmedPaths returns a copy of the prewarmed paths from src to dst, or
+// nil if the destination is not prewarmed.
+func (r *resolver) prewarmedPaths(src, dst addr.IA) spathmeta.AppPathSet {
+	r.prewarmedMtx.RLock()
+	sp, ok := r.prewarmed[prewarmKey{src: src, dst: dst}]
+	r.prewarmedMtx.RUnlock()
+	if !ok {
+		return nil
+	}
+	return sp.Load().APS.Copy()
+}
+
 func (r *resolver) WatchCount() int {
 	return r.watchFactory.length()
 }
 
+func (r *resolver) SetTimers(dst addr.IA, timers Timers) {
+	r.dstTimersMtx.Lock()
+	if timers == (Timers{}) {
+		delete(r.dstTimers, dst)
+	} else {
+		r.dstTimers[dst] = timers
+	}
+	r.dstTimersMtx.Unlock()
+
+	effective := timers.withDefaults(r.timers)
+	r.watchFactory.apply(func(w *WatchRunner) {
+		if w.querier.dst.Equal(dst) {
+			w.pp.SetTimers(effective)
+		}
+	})
+}
+
+// timersFor returns the timers to use for watches to dst.
+func (r *resolver) timersFor(dst addr.IA) Timers {
+	r.dstTimersMtx.RLock()
+	defer r.dstTimersMtx.RUnlock()
+	return r.dstTimers[dst].withDefaults(r.timers)
+}
+
 func (r *resolver) RevokeRaw(ctx context.Context, rawSRevInfo common.RawBytes) {
 	sRevInfo, err := path_mgmt.NewSignedRevInfoFromRaw(rawSRevInfo)
 	if err != nil {
@@ -275,6 +416,21 @@ func matches(path *spathmeta.AppPath, predicatePI sciond.PathInterface) bool {
 	return false
 }
 
+type prewarmKey struct {
+	src addr.IA
+	dst addr.IA
+}
+
+// querierFunc is a function that implements Querier.
+type querierFunc func(ctx context.Context, src, dst addr.IA,
+	flags sciond.PathReqFlags) spathmeta.AppPathSet
+
+func (f querierFunc) Query(ctx context.Context, src, dst addr.IA,
+	flags sciond.PathReqFlags) spathmeta.AppPathSet {
+
+	return f(ctx, src, dst, flags)
+}
+
 type pathWrap struct {
 	*spathmeta.AppPath
 }
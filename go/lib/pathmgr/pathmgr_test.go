@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -27,6 +28,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
 	"github.com/scionproto/scion/go/lib/infra"
 	"github.com/scionproto/scion/go/lib/pathmgr"
@@ -136,6 +138,73 @@ func TestQueryFilter(t *testing.T) {
 	}
 }
 
+func TestQueryDeduplication(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sd := mock_sciond.NewMockConnector(ctrl)
+	pm := pathmgr.New(sd, pathmgr.Timers{})
+	srcIA, dstIA := xtest.MustParseIA("1-ff00:0:133"), xtest.MustParseIA("1-ff00:0:131")
+	path := fmt.Sprintf("%s#1019 1-ff00:0:132#1910 1-ff00:0:132#1916 %s#1619", srcIA, dstIA)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	sd.EXPECT().Paths(gomock.Any(), dstIA, srcIA, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _, _ addr.IA, _ uint16,
+			_ sciond.PathReqFlags) (*sciond.PathReply, error) {
+
+			close(started)
+			<-release
+			return buildSDAnswer(path), nil
+		},
+	)
+	var wg sync.WaitGroup
+	query := func() {
+		defer wg.Done()
+		aps := pm.Query(context.Background(), srcIA, dstIA, sciond.PathReqFlags{})
+		assert.ElementsMatch(t, getPathStrings(aps), []string{path})
+	}
+	wg.Add(2)
+	go query()
+	<-started
+	go query()
+	// Give the second query time to join the pending one.
+	time.Sleep(getDuration(2))
+	close(release)
+	wg.Wait()
+}
+
+func TestPrewarm(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sd := mock_sciond.NewMockConnector(ctrl)
+	pm := pathmgr.New(sd, pathmgr.Timers{})
+	srcIA, dstIA := xtest.MustParseIA("1-ff00:0:133"), xtest.MustParseIA("1-ff00:0:131")
+	path := fmt.Sprintf("%s#1019 1-ff00:0:132#1910 1-ff00:0:132#1916 %s#1619", srcIA, dstIA)
+
+	sd.EXPECT().Paths(gomock.Any(), dstIA, srcIA, gomock.Any(), gomock.Any()).Return(
+		buildSDAnswer(path), nil,
+	)
+	pm.Prewarm(context.Background(), srcIA, []addr.IA{dstIA})
+	assert.Equal(t, 1, pm.WatchCount())
+
+	t.Run("queries without flags are answered from the watch", func(t *testing.T) {
+		aps := pm.Query(context.Background(), srcIA, dstIA, sciond.PathReqFlags{})
+		assert.ElementsMatch(t, getPathStrings(aps), []string{path})
+	})
+	t.Run("queries with flags go to SCIOND", func(t *testing.T) {
+		sd.EXPECT().Paths(gomock.Any(), dstIA, srcIA, gomock.Any(), gomock.Any()).Return(
+			buildSDAnswer(), nil,
+		)
+		aps := pm.Query(context.Background(), srcIA, dstIA, sciond.PathReqFlags{Refresh: true})
+		assert.Empty(t, aps)
+	})
+	t.Run("prewarming again is a no-op", func(t *testing.T) {
+		pm.Prewarm(context.Background(), srcIA, []addr.IA{dstIA})
+		assert.Equal(t, 1, pm.WatchCount())
+	})
+}
+
 func TestWatchCount(t *testing.T) {
 	t.Log("Given a path manager and adding a watch")
 
@@ -236,6 +305,49 @@ func TestWatchFilter(t *testing.T) {
 	assert.Len(t, sp.Load().APS, 1, "and after waiting, we get 1 path that is not filtered")
 }
 
+func TestSetTimers(t *testing.T) {
+	src := xtest.MustParseIA("1-ff00:0:111")
+	dst := xtest.MustParseIA("1-ff00:0:110")
+	other := xtest.MustParseIA("1-ff00:0:112")
+	answer := buildSDAnswer("1-ff00:0:111#104 1-ff00:0:120#5 1-ff00:0:120#6 1-ff00:0:110#1")
+
+	t.Run("override applies to new watches of the destination only", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		sd := mock_sciond.NewMockConnector(ctrl)
+		pr := pathmgr.New(sd, pathmgr.Timers{NormalRefire: getDuration(100)})
+		pr.SetTimers(dst, pathmgr.Timers{NormalRefire: getDuration(1)})
+
+		sd.EXPECT().Paths(gomock.Any(), dst, src, gomock.Any(), gomock.Any()).
+			Return(answer, nil).MinTimes(3)
+		sd.EXPECT().Paths(gomock.Any(), other, src, gomock.Any(), gomock.Any()).
+			Return(answer, nil).Times(1)
+
+		fast, err := pr.Watch(context.Background(), src, dst)
+		require.NoError(t, err)
+		defer fast.Destroy()
+		slow, err := pr.Watch(context.Background(), src, other)
+		require.NoError(t, err)
+		defer slow.Destroy()
+		time.Sleep(getDuration(10))
+	})
+	t.Run("override applies to running watches", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		sd := mock_sciond.NewMockConnector(ctrl)
+		pr := pathmgr.New(sd, pathmgr.Timers{NormalRefire: getDuration(100)})
+
+		sd.EXPECT().Paths(gomock.Any(), dst, src, gomock.Any(), gomock.Any()).
+			Return(answer, nil).MinTimes(4)
+
+		sp, err := pr.Watch(context.Background(), src, dst)
+		require.NoError(t, err)
+		defer sp.Destroy()
+		pr.SetTimers(dst, pathmgr.Timers{NormalRefire: getDuration(1)})
+		time.Sleep(getDuration(10))
+	})
+}
+
 func TestRevokeFastRecovery(t *testing.T) {
 	t.Log("Given a path manager with a long normal timer and very small error timer")
 	t.Log("A revocation that deletes everything triggers an immediate requery")
@@ -33,6 +33,9 @@ type PollingPolicy interface {
 	UpdateState(availablePaths spathmeta.AppPathSet)
 	// PollNow triggers a new query now, irrespective of other timers.
 	PollNow()
+	// SetTimers replaces the timers of the policy. The new timers are used
+	// starting with the next poll, which is triggered immediately.
+	SetTimers(timers Timers)
 	// PollC returns the channel that is written to whenever the policy
 	// dictates a new poll. Ticks are dropped if the channel is full.
 	PollC() <-chan sciond.PathReqFlags
@@ -54,22 +57,23 @@ func NewPollingPolicy(haveFilter bool, timers Timers) PollingPolicy {
 
 type DefaultPollingPolicy struct {
 	haveFilter    bool
-	timers        Timers
 	signalingChan chan sciond.PathReqFlags
 	params        PollingParameters
 
+	// runnerMtx protects the runner and the timers, which might be changed
+	// while the policy is in use.
 	runnerMtx sync.Mutex
 	runner    *periodic.Runner
+	timers    Timers
 }
 
 func (pp *DefaultPollingPolicy) UpdateState(availablePaths spathmeta.AppPathSet) {
+	pp.runnerMtx.Lock()
+	defer pp.runnerMtx.Unlock()
 	parameters := pp.getPollingParams(availablePaths)
 	if parameters != pp.params {
 		pp.params = parameters
 		pp.runner.Stop()
-
-		pp.runnerMtx.Lock()
-		defer pp.runnerMtx.Unlock()
 		pp.runner = StartPeriodic(pp.params, pp.signalingChan)
 	}
 }
@@ -91,6 +95,15 @@ func (pp *DefaultPollingPolicy) PollNow() {
 	pp.runner.TriggerRun()
 }
 
+func (pp *DefaultPollingPolicy) SetTimers(timers Timers) {
+	pp.runnerMtx.Lock()
+	pp.timers = timers
+	pp.runnerMtx.Unlock()
+	// Poll right away, such that the new timers are applied when the state
+	// is updated after the poll.
+	pp.PollNow()
+}
+
 func (pp *DefaultPollingPolicy) PollC() <-chan sciond.PathReqFlags {
 	return pp.signalingChan
 }
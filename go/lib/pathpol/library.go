@@ -0,0 +1,304 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathpol
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"github.com/scionproto/scion/go/lib/common"
+)
+
+// CompositionOp is the operation by which a composition combines the results
+// of its policies.
+type CompositionOp int
+
+const (
+	// Union accepts the paths accepted by any of the policies.
+	Union CompositionOp = iota
+	// Intersection accepts the paths accepted by all of the policies.
+	Intersection
+	// Priority accepts the paths accepted by the first policy that accepts
+	// any path.
+	Priority
+)
+
+// Composition combines the results of several policies.
+type Composition struct {
+	Op       CompositionOp
+	Policies []*Policy
+}
+
+func (c *Composition) eval(paths PathSet, opts FilterOptions) PathSet {
+	switch c.Op {
+	case Intersection:
+		resultSet := paths
+		for _, policy := range c.Policies {
+			subPaths := policy.FilterOpt(paths, opts)
+			intersection := make(PathSet)
+			for key, path := range resultSet {
+				if _, ok := subPaths[key]; ok {
+					intersection[key] = path
+				}
+			}
+			resultSet = intersection
+		}
+		return resultSet
+	case Priority:
+		for _, policy := range c.Policies {
+			if subPaths := policy.FilterOpt(paths, opts); len(subPaths) > 0 {
+				return subPaths
+			}
+		}
+		return make(PathSet)
+	default:
+		resultSet := make(PathSet)
+		for _, policy := range c.Policies {
+			for key, path := range policy.FilterOpt(paths, opts) {
+				resultSet[key] = path
+			}
+		}
+		return resultSet
+	}
+}
+
+// LibraryFile is the JSON format of a policy library file.
+//
+// Example:
+//
+//	{
+//	  "include": ["common.json"],
+//	  "policies": {
+//	    "no_110": {"acl": ["- 1-ff00:0:110#0", "+"]},
+//	    "short": {"extends": ["no_110"], "sequence": "0* 0*"},
+//	    "fallback": {"priority": ["short", "no_110"]}
+//	  }
+//	}
+type LibraryFile struct {
+	// Include lists further library files whose policies are part of the
+	// library. Relative paths are relative to the directory of the including
+	// file.
+	Include []string `json:"include,omitempty"`
+	// Policies are the policies defined in the file, keyed by their name.
+	Policies map[string]*LibraryPolicy `json:"policies,omitempty"`
+}
+
+// LibraryPolicy is a named policy in a library. Extends refers to other
+// policies of the library by name. In addition, a library policy can be
+// composed of other policies of the library, by listing their names in at
+// most one of Union, Intersection or Priority. The paths accepted by the
+// composition are further filtered by the ACL, Sequence and Options of the
+// policy.
+type LibraryPolicy struct {
+	ExtPolicy
+	Union        []string `json:"union,omitempty"`
+	Intersection []string `json:"intersection,omitempty"`
+	Priority     []string `json:"priority,omitempty"`
+}
+
+// Library is a set of compiled named policies.
+type Library struct {
+	policies map[string]*Policy
+}
+
+// LoadLibrary loads the policy library from file and all the files it
+// includes, transitively. Policy names must be unique across all files. All
+// policies are compiled, references to unknown policies and circular
+// references result in an error. The policies of options are compiled as well,
+// so they can extend policies of the library.
+func LoadLibrary(file string) (*Library, error) {
+	l := &libraryLoader{
+		files:     make(map[string]struct{}),
+		raw:       make(map[string]*LibraryPolicy),
+		origin:    make(map[string]string),
+		compiled:  make(map[string]*Policy),
+		compiling: make(map[string]struct{}),
+	}
+	if err := l.load(file); err != nil {
+		return nil, err
+	}
+	for name := range l.raw {
+		if _, err := l.compile(name); err != nil {
+			return nil, common.NewBasicError("Unable to compile policy", err,
+				"policy", name, "file", l.origin[name])
+		}
+	}
+	return &Library{policies: l.compiled}, nil
+}
+
+// Policy returns the policy with the given name.
+func (l *Library) Policy(name string) (*Policy, error) {
+	policy, ok := l.policies[name]
+	if !ok {
+		return nil, common.NewBasicError("Policy not found in library", nil, "policy", name)
+	}
+	return policy, nil
+}
+
+// Names returns the sorted names of all policies in the library.
+func (l *Library) Names() []string {
+	names := make([]string, 0, len(l.policies))
+	for name := range l.policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type libraryLoader struct {
+	// files contains the absolute paths of the loaded files.
+	files map[string]struct{}
+	// raw contains the policies as defined in the files.
+	raw map[string]*LibraryPolicy
+	// origin contains the file that defines a policy.
+	origin    map[string]string
+	compiled  map[string]*Policy
+	compiling map[string]struct{}
+}
+
+func (l *libraryLoader) load(file string) error {
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+	// Files that are included multiple times, or circularly, are only loaded
+	// once.
+	if _, ok := l.files[file]; ok {
+		return nil
+	}
+	l.files[file] = struct{}{}
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		return common.NewBasicError("Unable to read policy library", err, "file", file)
+	}
+	var lf LibraryFile
+	if err := json.Unmarshal(raw, &lf); err != nil {
+		return common.NewBasicError("Unable to parse policy library", err, "file", file)
+	}
+	for name, policy := range lf.Policies {
+		if other, ok := l.origin[name]; ok {
+			return common.NewBasicError("Policy defined multiple times", nil,
+				"policy", name, "files", []string{other, file})
+		}
+		if policy == nil {
+			policy = &LibraryPolicy{}
+		}
+		l.raw[name] = policy
+		l.origin[name] = file
+	}
+	for _, inc := range lf.Include {
+		if !filepath.IsAbs(inc) {
+			inc = filepath.Join(filepath.Dir(file), inc)
+		}
+		if err := l.load(inc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *libraryLoader) compile(name string) (*Policy, error) {
+	if policy, ok := l.compiled[name]; ok {
+		return policy, nil
+	}
+	raw, ok := l.raw[name]
+	if !ok {
+		return nil, common.NewBasicError("Referenced policy not found", nil, "policy", name)
+	}
+	if _, ok := l.compiling[name]; ok {
+		return nil, common.NewBasicError("Circular policy reference", nil, "policy", name)
+	}
+	l.compiling[name] = struct{}{}
+	defer delete(l.compiling, name)
+
+	policy, err := l.compileExt(name, &raw.ExtPolicy)
+	if err != nil {
+		return nil, err
+	}
+	composition, err := l.compileComposition(raw)
+	if err != nil {
+		return nil, err
+	}
+	if composition != nil {
+		policy.Composition = composition
+	}
+	l.compiled[name] = policy
+	return policy, nil
+}
+
+// compileExt compiles ext without modifying it. Extended policies are looked
+// up in the library.
+func (l *libraryLoader) compileExt(name string, ext *ExtPolicy) (*Policy, error) {
+	var policy *Policy
+	if ext.Policy != nil {
+		options := make([]Option, 0, len(ext.Options))
+		for _, option := range ext.Options {
+			var optPolicy *Policy
+			if option.Policy != nil {
+				var err error
+				if optPolicy, err = l.compileExt("", option.Policy); err != nil {
+					return nil, err
+				}
+			}
+			options = append(options, Option{
+				Weight: option.Weight,
+				Policy: &ExtPolicy{Policy: optPolicy},
+			})
+		}
+		policy = NewPolicy(name, ext.ACL, ext.Sequence, options)
+	} else {
+		policy = &Policy{Name: name}
+	}
+	// Traverse in reverse s.t. the last entry of the list has precedence.
+	for i := len(ext.Extends) - 1; i >= 0; i-- {
+		extended, err := l.compile(ext.Extends[i])
+		if err != nil {
+			return nil, err
+		}
+		policy.inherit(extended)
+	}
+	return policy, nil
+}
+
+func (l *libraryLoader) compileComposition(raw *LibraryPolicy) (*Composition, error) {
+	var composition *Composition
+	for _, c := range []struct {
+		op    CompositionOp
+		names []string
+	}{
+		{op: Union, names: raw.Union},
+		{op: Intersection, names: raw.Intersection},
+		{op: Priority, names: raw.Priority},
+	} {
+		if len(c.names) == 0 {
+			continue
+		}
+		if composition != nil {
+			return nil, common.NewBasicError("Only one of union, intersection and priority "+
+				"can be set", nil)
+		}
+		composition = &Composition{Op: c.op}
+		for _, name := range c.names {
+			policy, err := l.compile(name)
+			if err != nil {
+				return nil, err
+			}
+			composition.Policies = append(composition.Policies, policy)
+		}
+	}
+	return composition, nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathpol
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+)
+
+func TestLoadLibrary(t *testing.T) {
+	lib, err := LoadLibrary("testdata/library/main.json")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"any", "both", "deny_all", "extended", "fallback", "nested",
+		"no_120", "no_130", "options"}, lib.Names())
+
+	// Paths from 1-ff00:0:110 to 1-ff00:0:111: direct, via 1-ff00:0:120 and
+	// via 1-ff00:0:130.
+	paths := libraryTestPaths(
+		[]string{"1-ff00:0:110#1", "1-ff00:0:111#1"},
+		[]string{"1-ff00:0:110#2", "1-ff00:0:120#1", "1-ff00:0:120#2", "1-ff00:0:111#2"},
+		[]string{"1-ff00:0:110#3", "1-ff00:0:130#1", "1-ff00:0:130#2", "1-ff00:0:111#3"},
+	)
+	tests := map[string][]string{
+		"no_120":   {"direct", "via 1-ff00:0:130"},
+		"any":      {"direct", "via 1-ff00:0:120", "via 1-ff00:0:130"},
+		"both":     {"direct"},
+		"fallback": {"direct", "via 1-ff00:0:120"},
+		"extended": {"direct", "via 1-ff00:0:130"},
+		"options":  {"direct", "via 1-ff00:0:120"},
+		"nested":   {"direct", "via 1-ff00:0:130"},
+		"deny_all": {},
+	}
+	for name, expected := range tests {
+		t.Run(name, func(t *testing.T) {
+			policy, err := lib.Policy(name)
+			require.NoError(t, err)
+			assert.Equal(t, expected, pathSetKeys(policy.Filter(paths)))
+		})
+	}
+	t.Run("unknown policy", func(t *testing.T) {
+		_, err := lib.Policy("unknown")
+		assert.Error(t, err)
+	})
+}
+
+func TestLoadLibraryErrors(t *testing.T) {
+	tests := map[string]string{
+		"circular reference":    "testdata/library/circular.json",
+		"unknown reference":     "testdata/library/unknown.json",
+		"duplicate policy":      "testdata/library/duplicate.json",
+		"multiple compositions": "testdata/library/multiple_ops.json",
+		"non-existing file":     "testdata/library/nonexisting.json",
+		"missing include":       "testdata/library/missing_include.json",
+	}
+	for name, file := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadLibrary(file)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// libraryTestPaths creates a path set from the interface lists. The key of
+// the direct path is "direct", the key of the other paths is "via <IA>" with
+// the IA of the second interface.
+func libraryTestPaths(ifaceLists ...[]string) PathSet {
+	paths := make(PathSet)
+	for _, ifaces := range ifaceLists {
+		path := &testPath{key: "direct"}
+		for _, str := range ifaces {
+			hp, err := HopPredicateFromString(str)
+			if err != nil {
+				panic(err)
+			}
+			path.interfaces = append(path.interfaces, testPathIntf{
+				ia:   addr.IA{I: hp.ISD, A: hp.AS},
+				ifid: hp.IfIDs[0],
+			})
+		}
+		if len(ifaces) > 2 {
+			path.key = "via " + path.interfaces[1].IA().String()
+		}
+		paths[path.key] = path
+	}
+	return paths
+}
+
+func pathSetKeys(paths PathSet) []string {
+	keys := []string{}
+	for key := range paths {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
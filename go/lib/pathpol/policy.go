@@ -48,6 +48,9 @@ type Policy struct {
 	ACL      *ACL      `json:"acl,omitempty"`
 	Sequence *Sequence `json:"sequence,omitempty"`
 	Options  []Option  `json:"options,omitempty"`
+	// Composition combines the results of other policies. It is only set for
+	// policies compiled from a library and is not marshaled.
+	Composition *Composition `json:"-"`
 }
 
 // NewPolicy creates a Policy and sorts its Options
@@ -71,6 +74,9 @@ func (p *Policy) FilterOpt(paths PathSet, opts FilterOptions) PathSet {
 	if p == nil {
 		return paths
 	}
+	if p.Composition != nil {
+		paths = p.Composition.eval(paths, opts)
+	}
 	resultSet := p.ACL.Eval(paths)
 	if p.Sequence != nil && !opts.IgnoreSequence {
 		resultSet = p.Sequence.Eval(resultSet)
@@ -115,22 +121,32 @@ func (p *Policy) applyExtended(extends []string, exPolicies []*ExtPolicy) error
 			return common.NewBasicError("Extended policy could not be found", nil,
 				"policy", extends[i])
 		}
-		// Replace ACL
-		if p.ACL == nil && policy.ACL != nil {
-			p.ACL = policy.ACL
-		}
-		// Replace Options
-		if len(p.Options) == 0 {
-			p.Options = policy.Options
-		}
-		// Replace Sequence
-		if p.Sequence == nil {
-			p.Sequence = policy.Sequence
-		}
+		p.inherit(policy)
 	}
 	return nil
 }
 
+// inherit sets the attributes of p that are not set yet to the ones of the
+// extended policy.
+func (p *Policy) inherit(policy *Policy) {
+	// Replace ACL
+	if p.ACL == nil && policy.ACL != nil {
+		p.ACL = policy.ACL
+	}
+	// Replace Options
+	if len(p.Options) == 0 {
+		p.Options = policy.Options
+	}
+	// Replace Sequence
+	if p.Sequence == nil {
+		p.Sequence = policy.Sequence
+	}
+	// Replace Composition
+	if p.Composition == nil {
+		p.Composition = policy.Composition
+	}
+}
+
 // evalOptions evaluates the options of a policy and returns the pathSet that matches the option
 // with the highest weight
 func (p *Policy) evalOptions(inputSet PathSet, opts FilterOptions) PathSet {
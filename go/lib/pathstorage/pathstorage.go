@@ -17,6 +17,7 @@ package pathstorage
 import (
 	"fmt"
 	"io"
+	"strconv"
 
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/config"
@@ -41,6 +42,9 @@ const (
 const (
 	BackendKey    = "backend"
 	ConnectionKey = "connection"
+	// MaxEntriesKey is the key for the maximum number of entries in the
+	// in-memory revocation cache.
+	MaxEntriesKey = "maxentries"
 )
 
 var _ config.Config = (*PathDBConf)(nil)
@@ -148,6 +152,20 @@ func (cfg *RevCacheConf) MaxIdleConns() (int, bool) {
 	return db.ConfiguredMaxIdleConns(*cfg)
 }
 
+// MaxEntries returns the configured maximum number of entries of the in-memory
+// revocation cache and returns true if the limit was set.
+func (cfg *RevCacheConf) MaxEntries() (int, bool) {
+	val, ok := (*cfg)[MaxEntriesKey]
+	if !ok || val == "" {
+		return 0, false
+	}
+	i, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, false
+	}
+	return i, true
+}
+
 // Validate validates the configuration, should be called after InitDefaults.
 func (cfg *RevCacheConf) Validate() error {
 	if err := db.ValidateConfigLimits(*cfg); err != nil {
@@ -159,6 +177,9 @@ func (cfg *RevCacheConf) Validate() error {
 	if err := cfg.validateConnection(); err != nil {
 		return err
 	}
+	if err := cfg.validateMaxEntries(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -187,6 +208,17 @@ func (cfg *RevCacheConf) validateConnection() error {
 	return nil
 }
 
+func (cfg *RevCacheConf) validateMaxEntries() error {
+	val, ok := (*cfg)[MaxEntriesKey]
+	if !ok || val == "" {
+		return nil
+	}
+	if i, err := strconv.Atoi(val); err != nil || i < 0 {
+		return common.NewBasicError("Invalid MaxEntries", nil, "value", val)
+	}
+	return nil
+}
+
 // NewPathStorage creates a PathStorage from the given configs. Periodic
 // cleaners for the given databases have to be manually created and started
 // (see cleaner package).
@@ -248,7 +280,8 @@ func newRevCache(conf RevCacheConf) (revcache.RevCache, error) {
 	log.Info("Connecting RevCache", "backend", conf.Backend(), "connection", conf.Connection())
 	switch conf.Backend() {
 	case BackendMem:
-		return memrevcache.New(), nil
+		maxEntries, _ := conf.MaxEntries()
+		return memrevcache.NewWithMaxEntries(maxEntries), nil
 	case BackendNone:
 		return nil, nil
 	default:
@@ -39,6 +39,7 @@ func InitTestRevCacheConf(cfg *pathstorage.RevCacheConf) {
 	}
 	(*cfg)[db.MaxOpenConnsKey] = "maxOpenConns"
 	(*cfg)[db.MaxIdleConnsKey] = "maxIdleConns"
+	(*cfg)[pathstorage.MaxEntriesKey] = "maxEntries"
 }
 
 func CheckTestPathDBConf(t *testing.T, cfg *pathstorage.PathDBConf, id string) {
@@ -53,6 +54,7 @@ func CheckTestRevCacheConf(t *testing.T, cfg *pathstorage.RevCacheConf) {
 	util.LowerKeys(*cfg)
 	assert.False(t, isSet(cfg.MaxOpenConns()))
 	assert.False(t, isSet(cfg.MaxIdleConns()))
+	assert.False(t, isSet(cfg.MaxEntries()))
 	assert.Equal(t, pathstorage.BackendMem, cfg.Backend())
 }
 
@@ -34,6 +34,11 @@ const revSample = `
 # The type of RevCache backend.
 Backend = "mem"
 
+# The maximum number of revocations kept by the in-memory backend. If the
+# cache is full, the revocations that expire the soonest are evicted. In case
+# of the empty string, the cache is unbounded. (default "")
+MaxEntries = ""
+
 # The maximum number of open connections to the database. In case of the
 # empty string, the limit is not set and uses the go default. (default "")
 MaxOpenConns = ""
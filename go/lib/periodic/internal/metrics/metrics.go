@@ -0,0 +1,91 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics provides the metrics of the periodic task runner.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/scionproto/scion/go/lib/prom"
+)
+
+// Namespace is the prometheus namespace.
+const Namespace = "periodic"
+
+// Result type strings.
+const (
+	Success = prom.Success
+	ErrRun  = "err_run"
+)
+
+// Tasks exposes the periodic task metrics.
+var Tasks = newTasks()
+
+// RunLabels defines the labels of periodic task runs.
+type RunLabels struct {
+	Task   string
+	Result string
+}
+
+// Labels returns the list of labels.
+func (l RunLabels) Labels() []string {
+	return []string{"task", prom.LabelResult}
+}
+
+// Values returns the label values in the order defined by Labels.
+func (l RunLabels) Values() []string {
+	return []string{l.Task, l.Result}
+}
+
+// TaskLabels defines the labels of periodic task durations.
+type TaskLabels struct {
+	Task string
+}
+
+// Labels returns the list of labels.
+func (l TaskLabels) Labels() []string {
+	return []string{"task"}
+}
+
+// Values returns the label values in the order defined by Labels.
+func (l TaskLabels) Values() []string {
+	return []string{l.Task}
+}
+
+type tasks struct {
+	runs     *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func newTasks() tasks {
+	return tasks{
+		runs: prom.NewCounterVec(Namespace, "", "runs_total",
+			"Total number of periodic task runs.", RunLabels{}.Labels()),
+		duration: prom.NewHistogramVec(Namespace, "", "run_duration_seconds",
+			"Duration of periodic task runs.", TaskLabels{}.Labels(),
+			prometheus.DefBuckets),
+	}
+}
+
+// Runs returns the counter for the runs with the given labels. Failed runs
+// are counted with the ErrRun result.
+func (t tasks) Runs(l RunLabels) prometheus.Counter {
+	return t.runs.WithLabelValues(l.Values()...)
+}
+
+// Duration returns the histogram for the run durations of the given task.
+func (t tasks) Duration(l TaskLabels) prometheus.Observer {
+	return t.duration.WithLabelValues(l.Values()...)
+}
@@ -16,9 +16,11 @@ package periodic
 
 import (
 	"context"
+	"math/rand"
 	"time"
 
 	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/periodic/internal/metrics"
 	"github.com/scionproto/scion/go/lib/util"
 )
 
@@ -52,16 +54,59 @@ type Task interface {
 	Name() string
 }
 
+// ErrorTask is a Task that reports whether a run failed. If the task of a
+// Runner implements ErrorTask, the runner calls RunE instead of Run, counts
+// the failures in the metrics, and backs off after failed runs.
+type ErrorTask interface {
+	Task
+	// RunE executes the task once and returns an error if the run failed. It
+	// should return within the context's timeout.
+	RunE(context.Context) error
+}
+
+// Options configures the optional behavior of a Runner. The zero value runs
+// the task on every tick.
+type Options struct {
+	// Jitter is the maximum random delay before a run that was initiated by
+	// the ticker. It spreads out the runs of tasks that are started at the
+	// same time. Triggered runs are not delayed.
+	Jitter time.Duration
+	// MinBackoff is the time after a failed run of an ErrorTask during which
+	// ticks are skipped. The backoff doubles with every consecutive failure
+	// up to MaxBackoff. If it is zero, the runner does not back off.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum backoff. If it is not larger than MinBackoff,
+	// the backoff is constant.
+	MaxBackoff time.Duration
+}
+
+// backoff returns the backoff after the given number of consecutive failures.
+func (o Options) backoff(failures int) time.Duration {
+	d := o.MinBackoff
+	for i := 1; i < failures && d < o.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > o.MaxBackoff && o.MaxBackoff > o.MinBackoff {
+		d = o.MaxBackoff
+	}
+	return d
+}
+
 // Runner runs a task periodically.
 type Runner struct {
 	task         Task
 	ticker       Ticker
 	timeout      time.Duration
+	opts         Options
 	stop         chan struct{}
 	loopFinished chan struct{}
 	ctx          context.Context
 	cancelF      context.CancelFunc
 	trigger      chan struct{}
+	pending      chan struct{}
+	// failures and notBefore are only accessed by the run loop.
+	failures  int
+	notBefore time.Time
 }
 
 // StartPeriodicTask creates and starts a new Runner to run the given task peridiocally.
@@ -69,6 +114,14 @@ type Runner struct {
 // The timeout can be larger than the periodicity of the ticker. That means if a tasks takes a long
 // time it will be immediately retriggered.
 func StartPeriodicTask(task Task, ticker Ticker, timeout time.Duration) *Runner {
+	return StartPeriodicTaskWithOptions(task, ticker, timeout, Options{})
+}
+
+// StartPeriodicTaskWithOptions is like StartPeriodicTask, but additionally
+// applies the given options.
+func StartPeriodicTaskWithOptions(task Task, ticker Ticker, timeout time.Duration,
+	opts Options) *Runner {
+
 	ctx, cancelF := context.WithCancel(context.Background())
 	logger := log.New("debug_id", util.GetDebugID())
 	ctx = log.CtxWith(ctx, logger)
@@ -76,11 +129,13 @@ func StartPeriodicTask(task Task, ticker Ticker, timeout time.Duration) *Runner
 		task:         task,
 		ticker:       ticker,
 		timeout:      timeout,
+		opts:         opts,
 		stop:         make(chan struct{}),
 		loopFinished: make(chan struct{}),
 		ctx:          ctx,
 		cancelF:      cancelF,
 		trigger:      make(chan struct{}),
+		pending:      make(chan struct{}, 1),
 	}
 	logger.Info("Starting periodic task", "task", task.Name())
 	go func() {
@@ -124,6 +179,16 @@ func (r *Runner) TriggerRun() {
 	}
 }
 
+// TriggerNow is like TriggerRun, but it does not block. If a triggered run is
+// already pending, the calls are coalesced into a single run. Like all
+// triggered runs, the run is neither delayed by jitter nor by backoff.
+func (r *Runner) TriggerNow() {
+	select {
+	case r.pending <- struct{}{}:
+	default:
+	}
+}
+
 func (r *Runner) runLoop() {
 	defer close(r.loopFinished)
 	defer r.cancelF()
@@ -132,22 +197,83 @@ func (r *Runner) runLoop() {
 		case <-r.stop:
 			return
 		case <-r.ticker.Chan():
-			r.onTick()
+			r.onTick(true)
 		case <-r.trigger:
-			r.onTick()
+			r.onTick(false)
+		case <-r.pending:
+			r.onTick(false)
 		}
 	}
 }
 
-func (r *Runner) onTick() {
+func (r *Runner) onTick(scheduled bool) {
 	select {
 	// Make sure that stop case is evaluated first,
 	// so that when we kill and both channels are ready we always go into stop first.
 	case <-r.stop:
 		return
 	default:
-		ctx, cancelF := context.WithTimeout(r.ctx, r.timeout)
+	}
+	if scheduled {
+		if time.Now().Before(r.notBefore) {
+			return
+		}
+		if !r.waitJitter() {
+			return
+		}
+	}
+	r.run()
+}
+
+// waitJitter waits for a random delay up to the configured jitter. It returns
+// false if the runner was stopped in the meantime.
+func (r *Runner) waitJitter() bool {
+	if r.opts.Jitter <= 0 {
+		return true
+	}
+	timer := time.NewTimer(time.Duration(rand.Int63n(int64(r.opts.Jitter))))
+	defer timer.Stop()
+	select {
+	case <-r.stop:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (r *Runner) run() {
+	start := time.Now()
+	ctx, cancelF := context.WithTimeout(r.ctx, r.timeout)
+	var err error
+	if t, ok := r.task.(ErrorTask); ok {
+		err = t.RunE(ctx)
+	} else {
 		r.task.Run(ctx)
-		cancelF()
 	}
+	cancelF()
+	name := r.task.Name()
+	metrics.Tasks.Duration(metrics.TaskLabels{Task: name}).Observe(
+		time.Since(start).Seconds())
+	labels := metrics.RunLabels{Task: name, Result: metrics.Success}
+	if err != nil {
+		labels.Result = metrics.ErrRun
+	}
+	metrics.Tasks.Runs(labels).Inc()
+	r.updateBackoff(err)
+}
+
+func (r *Runner) updateBackoff(err error) {
+	if err == nil {
+		r.failures = 0
+		r.notBefore = time.Time{}
+		return
+	}
+	r.failures++
+	var backoff time.Duration
+	if r.opts.MinBackoff > 0 {
+		backoff = r.opts.backoff(r.failures)
+		r.notBefore = time.Now().Add(backoff)
+	}
+	log.FromCtx(r.ctx).Info("Periodic task failed", "task", r.task.Name(),
+		"failures", r.failures, "backoff", backoff, "err", err)
 }
@@ -16,6 +16,8 @@ package periodic
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"runtime"
 	"testing"
 	"time"
@@ -35,6 +37,20 @@ func (tf taskFunc) Name() string {
 	return "Test function"
 }
 
+type errTaskFunc func(context.Context) error
+
+func (tf errTaskFunc) Run(ctx context.Context) {
+	panic("Run must not be called on an ErrorTask")
+}
+
+func (tf errTaskFunc) RunE(ctx context.Context) error {
+	return tf(ctx)
+}
+
+func (tf errTaskFunc) Name() string {
+	return "Test error function"
+}
+
 var _ (Ticker) = (*testTicker)(nil)
 
 type testTicker struct {
@@ -126,3 +142,103 @@ func TestTriggerNow(t *testing.T) {
 	r.TriggerRun()
 	assert.Equal(t, 3, cnt, "Must have executed 3 times")
 }
+
+func TestTriggerNowCoalesces(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	fn := taskFunc(func(ctx context.Context) {
+		started <- struct{}{}
+		<-release
+	})
+	r := StartPeriodicTask(fn, &testTicker{C: make(chan time.Time)}, time.Second)
+	defer r.Kill()
+	r.TriggerNow()
+	xtest.AssertReadReturnsBefore(t, started, 50*time.Millisecond)
+	// While the task is running, multiple triggers result in a single run.
+	r.TriggerNow()
+	r.TriggerNow()
+	r.TriggerNow()
+	release <- struct{}{}
+	xtest.AssertReadReturnsBefore(t, started, 50*time.Millisecond)
+	release <- struct{}{}
+	assertNoRead(t, started, 50*time.Millisecond)
+}
+
+func TestErrorTaskBackoff(t *testing.T) {
+	done := make(chan struct{})
+	var fail bool
+	fn := errTaskFunc(func(ctx context.Context) error {
+		defer func() { done <- struct{}{} }()
+		if fail {
+			return errors.New("test error")
+		}
+		return nil
+	})
+	tickC := make(chan time.Time)
+	r := StartPeriodicTaskWithOptions(fn, &testTicker{C: tickC}, time.Second,
+		Options{MinBackoff: time.Hour})
+	defer r.Kill()
+	fail = true
+	tickC <- time.Now()
+	xtest.AssertReadReturnsBefore(t, done, 50*time.Millisecond)
+	// The tick is skipped because of the backoff.
+	tickC <- time.Now()
+	assertNoRead(t, done, 50*time.Millisecond)
+	// Triggered runs ignore the backoff, and a successful run resets it.
+	fail = false
+	r.TriggerRun()
+	xtest.AssertReadReturnsBefore(t, done, 50*time.Millisecond)
+	tickC <- time.Now()
+	xtest.AssertReadReturnsBefore(t, done, 50*time.Millisecond)
+}
+
+func TestJitterDelaysScheduledRuns(t *testing.T) {
+	done := make(chan time.Time)
+	fn := taskFunc(func(ctx context.Context) {
+		done <- time.Now()
+	})
+	tickC := make(chan time.Time)
+	jitter := 20 * time.Millisecond
+	r := StartPeriodicTaskWithOptions(fn, &testTicker{C: tickC}, time.Second,
+		Options{Jitter: jitter})
+	defer r.Kill()
+	tick := time.Now()
+	tickC <- tick
+	select {
+	case ran := <-done:
+		assert.True(t, ran.Sub(tick) < jitter+50*time.Millisecond)
+	case <-time.After(time.Second):
+		t.Fatalf("time out while waiting on run")
+	}
+}
+
+func TestOptionsBackoff(t *testing.T) {
+	tests := []struct {
+		Opts     Options
+		Failures int
+		Expected time.Duration
+	}{
+		{Options{MinBackoff: time.Second}, 1, time.Second},
+		{Options{MinBackoff: time.Second}, 5, time.Second},
+		{Options{MinBackoff: time.Second, MaxBackoff: 10 * time.Second}, 1, time.Second},
+		{Options{MinBackoff: time.Second, MaxBackoff: 10 * time.Second}, 2, 2 * time.Second},
+		{Options{MinBackoff: time.Second, MaxBackoff: 10 * time.Second}, 4, 8 * time.Second},
+		{Options{MinBackoff: time.Second, MaxBackoff: 10 * time.Second}, 5, 10 * time.Second},
+		{Options{MinBackoff: time.Second, MaxBackoff: 10 * time.Second}, 100, 10 * time.Second},
+	}
+	for _, test := range tests {
+		name := fmt.Sprintf("%v, %d failures", test.Opts, test.Failures)
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.Expected, test.Opts.backoff(test.Failures))
+		})
+	}
+}
+
+func assertNoRead(t *testing.T, ch <-chan struct{}, timeout time.Duration) {
+	t.Helper()
+	select {
+	case <-ch:
+		t.Fatalf("unexpected read")
+	case <-time.After(timeout):
+	}
+}
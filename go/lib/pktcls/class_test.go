@@ -25,6 +25,7 @@ import (
 
 	. "github.com/smartystreets/goconvey/convey"
 
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/xtest"
 )
 
@@ -72,6 +73,23 @@ func TestClassMap(t *testing.T) {
 				),
 			},
 		},
+		{
+			Name:     "SCION",
+			FileName: "class_3",
+			Classes: ClassMap{
+				"scion": NewClass(
+					"scion",
+					NewCondAllOf(
+						NewCondSCION(&SCIONMatchSrcIA{xtest.MustParseIA("1-ff00:0:110")}),
+						NewCondSCION(&SCIONMatchDstIA{xtest.MustParseIA("2-0")}),
+						NewCondSCION(&SCIONMatchHopCount{Min: 2, Max: 8}),
+						NewCondSCION(&SCIONMatchSVC{addr.SvcCS}),
+						NewCondSCION(&SCIONMatchSrcPort{Min: 1024, Max: 65535}),
+						NewCondSCION(&SCIONMatchDstPort{Min: 443, Max: 443}),
+					),
+				),
+			},
+		},
 		{
 			Name:     "nil ClassMap stays nil",
 			FileName: "class_2",
@@ -222,6 +240,42 @@ func TestBadJSON(t *testing.T) {
 			},
 			"Name": "Unable to parse source operand string"
 		}
+		`, `
+		{
+			"CondSCION": {
+				"MatchSrcIA": {
+					"IA": "1-ff00:0:110:1"
+				}
+			},
+			"Name": "Unable to parse IA operand"
+		}
+		`, `
+		{
+			"CondSCION": {
+				"MatchSVC": {
+					"SVC": "FOO"
+				}
+			},
+			"Name": "Unable to parse SVC operand"
+		}
+		`, `
+		{
+			"CondSCION": {
+				"MatchDstPort": {
+					"Min": "443"
+				}
+			},
+			"Name": "No port range maximum"
+		}
+		`, `
+		{
+			"CondSCION": {
+				"MatchSource": {
+					"Net": "10.0.0.0/8"
+				}
+			},
+			"Name": "IPv4 predicate in SCION condition"
+		}
 	`}
 	Convey("Marshaling bad JSON should return errors", t, func() {
 		for i, tc := range testCases {
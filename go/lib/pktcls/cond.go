@@ -181,9 +181,9 @@ func (c *CondIPv4) Eval(v interface{}) bool {
 	if v == nil {
 		return false
 	}
-	pkt := v.(*Packet)
+	pkt, ok := v.(*Packet)
 	// Protect against typed nils
-	if pkt == nil {
+	if !ok || pkt == nil || pkt.parsedPkt == nil {
 		return false
 	}
 	parsedPkt, ok := pkt.parsedPkt.Layer(layers.LayerTypeIPv4).(*layers.IPv4)
@@ -206,3 +206,38 @@ func (c *CondIPv4) UnmarshalJSON(b []byte) error {
 	c.Predicate, err = unmarshalPredicate(b)
 	return err
 }
+
+var _ Cond = (*CondSCION)(nil)
+
+// CondSCION conditions return true if the embedded SCION predicate returns
+// true. The evaluated value must be a *Packet created by NewSCIONPacket.
+type CondSCION struct {
+	Predicate SCIONPredicate
+}
+
+func NewCondSCION(p SCIONPredicate) *CondSCION {
+	return &CondSCION{Predicate: p}
+}
+
+func (c *CondSCION) Eval(v interface{}) bool {
+	pkt, ok := v.(*Packet)
+	// Protect against typed nils
+	if !ok || pkt == nil || pkt.scnPkt == nil {
+		return false
+	}
+	return c.Predicate.Eval(pkt.scnPkt)
+}
+
+func (c *CondSCION) Type() string {
+	return TypeCondSCION
+}
+
+func (c *CondSCION) MarshalJSON() ([]byte, error) {
+	return marshalInterface(c.Predicate)
+}
+
+func (c *CondSCION) UnmarshalJSON(b []byte) error {
+	var err error
+	c.Predicate, err = unmarshalSCIONPredicate(b)
+	return err
+}
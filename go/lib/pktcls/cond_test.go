@@ -21,6 +21,13 @@ import (
 	"github.com/google/gopacket"
 	"github.com/google/gopacket/layers"
 	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/l4"
+	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/lib/spkt"
+	"github.com/scionproto/scion/go/lib/xtest"
 )
 
 func TestBasicCond(t *testing.T) {
@@ -155,6 +162,144 @@ func TestIPCond(t *testing.T) {
 	})
 }
 
+func TestSCIONCond(t *testing.T) {
+	pkt := NewSCIONPacket(&spkt.ScnPkt{
+		SrcIA:   xtest.MustParseIA("1-ff00:0:110"),
+		DstIA:   xtest.MustParseIA("2-ff00:0:220"),
+		DstHost: addr.SvcCS.Multicast(),
+		Path:    newTestPath(2, 3),
+		L4:      &l4.UDP{SrcPort: 40000, DstPort: 443},
+	})
+	ipPkt := newTestPacket(
+		&layers.IPv4{
+			SrcIP: net.IP{172, 17, 1, 1},
+			DstIP: net.IP{192, 168, 1, 2},
+		},
+		[]byte{1},
+	)
+	mixed := NewCondAnyOf(
+		NewCondIPv4(
+			&IPv4MatchSource{
+				&net.IPNet{
+					IP:   net.IP{172, 17, 1, 0},
+					Mask: net.IPv4Mask(255, 255, 255, 0),
+				},
+			},
+		),
+		NewCondSCION(&SCIONMatchSrcIA{xtest.MustParseIA("1-ff00:0:110")}),
+	)
+	testCases := []struct {
+		Name    string
+		Cond    Cond
+		Packet  *Packet
+		ExpEval bool
+	}{
+		{
+			Name:    "Match source IA",
+			Cond:    NewCondSCION(&SCIONMatchSrcIA{xtest.MustParseIA("1-ff00:0:110")}),
+			Packet:  pkt,
+			ExpEval: true,
+		},
+		{
+			Name:    "Match destination ISD wildcard",
+			Cond:    NewCondSCION(&SCIONMatchDstIA{xtest.MustParseIA("2-0")}),
+			Packet:  pkt,
+			ExpEval: true,
+		},
+		{
+			Name:    "Mismatch destination IA",
+			Cond:    NewCondSCION(&SCIONMatchDstIA{xtest.MustParseIA("1-0")}),
+			Packet:  pkt,
+			ExpEval: false,
+		},
+		{
+			Name:    "Match hop count",
+			Cond:    NewCondSCION(&SCIONMatchHopCount{Min: 5, Max: 5}),
+			Packet:  pkt,
+			ExpEval: true,
+		},
+		{
+			Name:    "Mismatch hop count",
+			Cond:    NewCondSCION(&SCIONMatchHopCount{Min: 0, Max: 4}),
+			Packet:  pkt,
+			ExpEval: false,
+		},
+		{
+			Name:    "Match multicast destination with anycast SVC",
+			Cond:    NewCondSCION(&SCIONMatchSVC{addr.SvcCS}),
+			Packet:  pkt,
+			ExpEval: true,
+		},
+		{
+			Name:    "Mismatch SVC",
+			Cond:    NewCondSCION(&SCIONMatchSVC{addr.SvcPS}),
+			Packet:  pkt,
+			ExpEval: false,
+		},
+		{
+			Name: "Match source and destination ports",
+			Cond: NewCondAllOf(
+				NewCondSCION(&SCIONMatchSrcPort{Min: 1024, Max: 65535}),
+				NewCondSCION(&SCIONMatchDstPort{Min: 443, Max: 443}),
+			),
+			Packet:  pkt,
+			ExpEval: true,
+		},
+		{
+			Name:    "Mismatch destination port",
+			Cond:    NewCondSCION(&SCIONMatchDstPort{Min: 80, Max: 80}),
+			Packet:  pkt,
+			ExpEval: false,
+		},
+		{
+			Name:    "Non-SCION packet",
+			Cond:    NewCondSCION(&SCIONMatchSrcIA{xtest.MustParseIA("1-ff00:0:110")}),
+			Packet:  ipPkt,
+			ExpEval: false,
+		},
+		{
+			Name:    "Mixed condition on SCION packet",
+			Cond:    mixed,
+			Packet:  pkt,
+			ExpEval: true,
+		},
+		{
+			Name:    "Mixed condition on IPv4 packet",
+			Cond:    mixed,
+			Packet:  ipPkt,
+			ExpEval: true,
+		},
+		{
+			Name: "IPv4 condition on SCION packet",
+			Cond: NewCondAllOf(
+				NewCondIPv4(&IPv4MatchToS{0}),
+				NewCondSCION(&SCIONMatchSrcIA{xtest.MustParseIA("1-ff00:0:110")}),
+			),
+			Packet:  pkt,
+			ExpEval: false,
+		},
+	}
+
+	Convey("TestSCIONCond", t, func() {
+		for _, tc := range testCases {
+			Convey(tc.Name, func() {
+				class := NewClass("test", tc.Cond)
+				SoMsg("eval", class.Eval(tc.Packet), ShouldEqual, tc.ExpEval)
+			})
+		}
+	})
+}
+
+func newTestPath(segHops ...uint8) *spath.Path {
+	var raw common.RawBytes
+	for _, hops := range segHops {
+		seg := make(common.RawBytes, spath.InfoFieldLength+int(hops)*spath.HopFieldLength)
+		(&spath.InfoField{Hops: hops}).Write(seg)
+		raw = append(raw, seg...)
+	}
+	return &spath.Path{Raw: raw}
+}
+
 func newTestPacket(ipv4 *layers.IPv4, pld []byte) *Packet {
 	buf := gopacket.NewSerializeBuffer()
 	gopacket.SerializeLayers(
@@ -20,7 +20,7 @@
 // true for a ClsPkt, that packet is considered to be part of that class.
 //
 // The following conditions are supported:
-// AnyOf, AllOf, Boolean true, Boolean false and IPv4. AnyOf returns true if at
+// AnyOf, AllOf, Boolean true, Boolean false, IPv4 and SCION. AnyOf returns true if at
 // least one subcondition returns true. AllOf returns true if all subconditions
 // return true.  AllOf or AnyOf without subconditions return true. Boolean
 // conditions always return their internal value. IPv4 conditions include
@@ -29,6 +29,11 @@
 // and ToS/DSCP fields match. Multiple predicates can be checked by enumerating
 // them under AllOf or AnyOf.
 //
+// SCION conditions are evaluated on SCION packets (see NewSCIONPacket) instead
+// of IPv4 packets; they never match IPv4 packets and vice versa. Supported SCION conditions include source and destination
+// ISD-AS match (with 0 acting as a wildcard for the ISD or AS), hop count
+// range, SVC destination match and UDP source and destination port ranges.
+//
 // The package contains support for JSON marshaling and unmarshaling of
 // classes. Due to the custom formatting of the JSON output, marshaling must be
 // done by first adding the classes to a ClassMap. Unmarshaling back to the Map
@@ -50,6 +50,13 @@ const (
 	TypeIPv4MatchDestination = "MatchDestination"
 	TypeIPv4MatchToS         = "MatchToS"
 	TypeIPv4MatchDSCP        = "MatchDSCP"
+	TypeCondSCION            = "CondSCION"
+	TypeSCIONMatchSrcIA      = "MatchSrcIA"
+	TypeSCIONMatchDstIA      = "MatchDstIA"
+	TypeSCIONMatchHopCount   = "MatchHopCount"
+	TypeSCIONMatchSVC        = "MatchSVC"
+	TypeSCIONMatchSrcPort    = "MatchSrcPort"
+	TypeSCIONMatchDstPort    = "MatchDstPort"
 )
 
 // generic container for marshaling custom data
@@ -112,6 +119,34 @@ func unmarshalInterface(b []byte) (Typer, error) {
 			var p IPv4MatchDSCP
 			err := json.Unmarshal(*v, &p)
 			return &p, err
+		case TypeCondSCION:
+			var c CondSCION
+			err := json.Unmarshal(*v, &c)
+			return &c, err
+		case TypeSCIONMatchSrcIA:
+			var p SCIONMatchSrcIA
+			err := json.Unmarshal(*v, &p)
+			return &p, err
+		case TypeSCIONMatchDstIA:
+			var p SCIONMatchDstIA
+			err := json.Unmarshal(*v, &p)
+			return &p, err
+		case TypeSCIONMatchHopCount:
+			var p SCIONMatchHopCount
+			err := json.Unmarshal(*v, &p)
+			return &p, err
+		case TypeSCIONMatchSVC:
+			var p SCIONMatchSVC
+			err := json.Unmarshal(*v, &p)
+			return &p, err
+		case TypeSCIONMatchSrcPort:
+			var p SCIONMatchSrcPort
+			err := json.Unmarshal(*v, &p)
+			return &p, err
+		case TypeSCIONMatchDstPort:
+			var p SCIONMatchDstPort
+			err := json.Unmarshal(*v, &p)
+			return &p, err
 		default:
 			return nil, common.NewBasicError("Unknown type", nil, "type", k)
 		}
@@ -145,6 +180,19 @@ func unmarshalPredicate(b []byte) (IPv4Predicate, error) {
 	return p, nil
 }
 
+// unmarshalSCIONPredicate extracts a SCIONPredicate from a JSON encoding
+func unmarshalSCIONPredicate(b []byte) (SCIONPredicate, error) {
+	t, err := unmarshalInterface(b)
+	if err != nil {
+		return nil, err
+	}
+	p, ok := t.(SCIONPredicate)
+	if !ok {
+		return nil, serrors.New("Unable to extract SCIONPredicate from interface")
+	}
+	return p, nil
+}
+
 // Special case slices because we only need them for Conds
 
 func marshalCondSlice(conds []Cond) ([]byte, error) {
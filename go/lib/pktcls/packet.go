@@ -19,6 +19,7 @@ import (
 	"github.com/google/gopacket/layers"
 
 	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/spkt"
 )
 
 // Packet is a wrapper around common.RawBytes which is used to store additional
@@ -26,6 +27,7 @@ import (
 type Packet struct {
 	rawPkt    common.RawBytes
 	parsedPkt gopacket.Packet
+	scnPkt    *spkt.ScnPkt
 }
 
 func NewPacket(raw common.RawBytes) *Packet {
@@ -34,3 +36,9 @@ func NewPacket(raw common.RawBytes) *Packet {
 		parsedPkt: gopacket.NewPacket(raw, layers.LayerTypeIPv4, gopacket.NoCopy),
 	}
 }
+
+// NewSCIONPacket wraps a SCION packet, such that it can be evaluated by SCION
+// conditions. IPv4 conditions never match the returned packet.
+func NewSCIONPacket(pkt *spkt.ScnPkt) *Packet {
+	return &Packet{scnPkt: pkt}
+}
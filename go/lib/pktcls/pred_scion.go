@@ -0,0 +1,296 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pktcls
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/l4"
+	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/lib/spkt"
+)
+
+// SCIONPredicate describes a single test on various SCION packet fields.
+type SCIONPredicate interface {
+	// Eval returns true if the SCION packet matched the predicate
+	Eval(*spkt.ScnPkt) bool
+	Typer
+}
+
+var _ SCIONPredicate = (*SCIONMatchSrcIA)(nil)
+
+// SCIONMatchSrcIA checks whether the source ISD-AS matches IA. A 0 ISD or AS
+// in IA matches any ISD or AS, respectively.
+type SCIONMatchSrcIA struct {
+	IA addr.IA
+}
+
+func (m *SCIONMatchSrcIA) Type() string {
+	return TypeSCIONMatchSrcIA
+}
+
+func (m *SCIONMatchSrcIA) Eval(p *spkt.ScnPkt) bool {
+	return matchIA(m.IA, p.SrcIA)
+}
+
+func (m *SCIONMatchSrcIA) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		jsonContainer{
+			"IA": m.IA.String(),
+		},
+	)
+}
+
+func (m *SCIONMatchSrcIA) UnmarshalJSON(b []byte) error {
+	ia, err := unmarshalIAField(b, TypeSCIONMatchSrcIA, "IA")
+	if err != nil {
+		return err
+	}
+	m.IA = ia
+	return nil
+}
+
+var _ SCIONPredicate = (*SCIONMatchDstIA)(nil)
+
+// SCIONMatchDstIA checks whether the destination ISD-AS matches IA. A 0 ISD or
+// AS in IA matches any ISD or AS, respectively.
+type SCIONMatchDstIA struct {
+	IA addr.IA
+}
+
+func (m *SCIONMatchDstIA) Type() string {
+	return TypeSCIONMatchDstIA
+}
+
+func (m *SCIONMatchDstIA) Eval(p *spkt.ScnPkt) bool {
+	return matchIA(m.IA, p.DstIA)
+}
+
+func (m *SCIONMatchDstIA) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		jsonContainer{
+			"IA": m.IA.String(),
+		},
+	)
+}
+
+func (m *SCIONMatchDstIA) UnmarshalJSON(b []byte) error {
+	ia, err := unmarshalIAField(b, TypeSCIONMatchDstIA, "IA")
+	if err != nil {
+		return err
+	}
+	m.IA = ia
+	return nil
+}
+
+var _ SCIONPredicate = (*SCIONMatchHopCount)(nil)
+
+// SCIONMatchHopCount checks whether the number of hop fields in the path is
+// between Min and Max, inclusive. Packets without a path have a hop count of
+// 0.
+type SCIONMatchHopCount struct {
+	Min uint8
+	Max uint8
+}
+
+func (m *SCIONMatchHopCount) Type() string {
+	return TypeSCIONMatchHopCount
+}
+
+func (m *SCIONMatchHopCount) Eval(p *spkt.ScnPkt) bool {
+	hops, ok := hopCount(p.Path)
+	return ok && hops >= int(m.Min) && hops <= int(m.Max)
+}
+
+func (m *SCIONMatchHopCount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		jsonContainer{
+			"Min": fmt.Sprintf("%d", m.Min),
+			"Max": fmt.Sprintf("%d", m.Max),
+		},
+	)
+}
+
+func (m *SCIONMatchHopCount) UnmarshalJSON(b []byte) error {
+	min, err := unmarshalUintField(b, TypeSCIONMatchHopCount, "Min", 8)
+	if err != nil {
+		return err
+	}
+	max, err := unmarshalUintField(b, TypeSCIONMatchHopCount, "Max", 8)
+	if err != nil {
+		return err
+	}
+	m.Min, m.Max = uint8(min), uint8(max)
+	return nil
+}
+
+var _ SCIONPredicate = (*SCIONMatchSVC)(nil)
+
+// SCIONMatchSVC checks whether the destination host is the SVC address SVC.
+// Anycast and multicast addresses of the same service both match.
+type SCIONMatchSVC struct {
+	SVC addr.HostSVC
+}
+
+func (m *SCIONMatchSVC) Type() string {
+	return TypeSCIONMatchSVC
+}
+
+func (m *SCIONMatchSVC) Eval(p *spkt.ScnPkt) bool {
+	svc, ok := p.DstHost.(addr.HostSVC)
+	return ok && svc.Base() == m.SVC.Base()
+}
+
+func (m *SCIONMatchSVC) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		jsonContainer{
+			"SVC": m.SVC.BaseString(),
+		},
+	)
+}
+
+func (m *SCIONMatchSVC) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalStringField(b, TypeSCIONMatchSVC, "SVC")
+	if err != nil {
+		return err
+	}
+	svc := addr.HostSVCFromString(s)
+	if svc == addr.SvcNone {
+		return common.NewBasicError("Unable to parse MatchSVC operand", nil, "svc", s)
+	}
+	m.SVC = svc.Base()
+	return nil
+}
+
+var _ SCIONPredicate = (*SCIONMatchSrcPort)(nil)
+
+// SCIONMatchSrcPort checks whether the packet is a UDP packet with a source
+// port between Min and Max, inclusive.
+type SCIONMatchSrcPort struct {
+	Min uint16
+	Max uint16
+}
+
+func (m *SCIONMatchSrcPort) Type() string {
+	return TypeSCIONMatchSrcPort
+}
+
+func (m *SCIONMatchSrcPort) Eval(p *spkt.ScnPkt) bool {
+	udp, ok := p.L4.(*l4.UDP)
+	return ok && udp.SrcPort >= m.Min && udp.SrcPort <= m.Max
+}
+
+func (m *SCIONMatchSrcPort) MarshalJSON() ([]byte, error) {
+	return marshalPortRange(m.Min, m.Max)
+}
+
+func (m *SCIONMatchSrcPort) UnmarshalJSON(b []byte) error {
+	var err error
+	m.Min, m.Max, err = unmarshalPortRange(b, TypeSCIONMatchSrcPort)
+	return err
+}
+
+var _ SCIONPredicate = (*SCIONMatchDstPort)(nil)
+
+// SCIONMatchDstPort checks whether the packet is a UDP packet with a
+// destination port between Min and Max, inclusive.
+type SCIONMatchDstPort struct {
+	Min uint16
+	Max uint16
+}
+
+func (m *SCIONMatchDstPort) Type() string {
+	return TypeSCIONMatchDstPort
+}
+
+func (m *SCIONMatchDstPort) Eval(p *spkt.ScnPkt) bool {
+	udp, ok := p.L4.(*l4.UDP)
+	return ok && udp.DstPort >= m.Min && udp.DstPort <= m.Max
+}
+
+func (m *SCIONMatchDstPort) MarshalJSON() ([]byte, error) {
+	return marshalPortRange(m.Min, m.Max)
+}
+
+func (m *SCIONMatchDstPort) UnmarshalJSON(b []byte) error {
+	var err error
+	m.Min, m.Max, err = unmarshalPortRange(b, TypeSCIONMatchDstPort)
+	return err
+}
+
+// matchIA returns true if ia matches pattern, where a 0 ISD or AS in pattern
+// matches any ISD or AS.
+func matchIA(pattern, ia addr.IA) bool {
+	return (pattern.I == 0 || pattern.I == ia.I) && (pattern.A == 0 || pattern.A == ia.A)
+}
+
+// hopCount returns the number of hop fields in path. It returns false if the
+// path is malformed.
+func hopCount(path *spath.Path) (int, bool) {
+	if path == nil {
+		return 0, true
+	}
+	hops, off := 0, 0
+	for off < len(path.Raw) {
+		info, err := spath.InfoFFromRaw(path.Raw[off:])
+		if err != nil {
+			return 0, false
+		}
+		hops += int(info.Hops)
+		off += spath.InfoFieldLength + int(info.Hops)*spath.HopFieldLength
+	}
+	if off > len(path.Raw) {
+		// The last segment is truncated.
+		return 0, false
+	}
+	return hops, true
+}
+
+func unmarshalIAField(b []byte, name, field string) (addr.IA, error) {
+	s, err := unmarshalStringField(b, name, field)
+	if err != nil {
+		return addr.IA{}, err
+	}
+	ia, err := addr.IAFromString(s)
+	if err != nil {
+		return addr.IA{}, common.NewBasicError("Unable to parse IA field", err,
+			"name", name, "field", field)
+	}
+	return ia, nil
+}
+
+func marshalPortRange(min, max uint16) ([]byte, error) {
+	return json.Marshal(
+		jsonContainer{
+			"Min": fmt.Sprintf("%d", min),
+			"Max": fmt.Sprintf("%d", max),
+		},
+	)
+}
+
+func unmarshalPortRange(b []byte, name string) (uint16, uint16, error) {
+	min, err := unmarshalUintField(b, name, "Min", 16)
+	if err != nil {
+		return 0, 0, err
+	}
+	max, err := unmarshalUintField(b, name, "Max", 16)
+	if err != nil {
+		return 0, 0, err
+	}
+	return uint16(min), uint16(max), nil
+}
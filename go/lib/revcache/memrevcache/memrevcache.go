@@ -15,6 +15,7 @@
 package memrevcache
 
 import (
+	"container/heap"
 	"context"
 	"sync"
 	"time"
@@ -31,15 +32,40 @@ type memRevCache struct {
 	// Do not embed or use type directly to reduce the cache's API surface
 	c    *cache.Cache
 	lock sync.RWMutex
+	// maxEntries is the maximum number of entries in the cache, 0 means unbounded.
+	maxEntries int
+	// expiries orders the entries by expiration, such that the entry that
+	// expires the soonest can be evicted without scanning the cache. It is
+	// only maintained if the cache is bounded.
+	expiries expiryHeap
+	// expiryEntries maps the keys to their entries in expiries.
+	expiryEntries map[string]*expiryEntry
 }
 
 // New creates a new RevCache, backed by an in memory cache.
 func New() *memRevCache {
-	return &memRevCache{
+	return NewWithMaxEntries(0)
+}
+
+// NewWithMaxEntries creates a new RevCache, backed by an in memory cache,
+// that holds at most maxEntries revocations. If the cache is full, the
+// revocation that expires the soonest is evicted to make room for a new one.
+// A value of 0 means the cache is unbounded.
+func NewWithMaxEntries(maxEntries int) *memRevCache {
+	initMetrics()
+	c := &memRevCache{
 		// We insert all the items with expiration so no need to have a default expiration.
 		// The cleaning should happen manually using the DeleteExpired method.
-		c: cache.New(cache.NoExpiration, 0),
+		c:          cache.New(cache.NoExpiration, 0),
+		maxEntries: maxEntries,
+	}
+	if maxEntries > 0 {
+		c.expiryEntries = make(map[string]*expiryEntry)
+		// Deletions happen with the write lock held, so the callback can
+		// update the expiries directly.
+		c.c.OnEvicted(func(key string, _ interface{}) { c.untrack(key) })
 	}
+	return c
 }
 
 func (c *memRevCache) Get(_ context.Context, keys revcache.KeySet) (revcache.Revocations, error) {
@@ -49,6 +75,9 @@ func (c *memRevCache) Get(_ context.Context, keys revcache.KeySet) (revcache.Rev
 	for k := range keys {
 		if revInfo, ok := c.get(k.String()); ok {
 			revs[k] = revInfo
+			lookupsTotal.WithLabelValues(resultHit).Inc()
+		} else {
+			lookupsTotal.WithLabelValues(resultMiss).Inc()
 		}
 	}
 	return revs, nil
@@ -92,7 +121,8 @@ func (c *memRevCache) Insert(_ context.Context, rev *path_mgmt.SignedRevInfo) (b
 	key := k.String()
 	val, ok := c.get(key)
 	if !ok {
-		c.c.Set(key, rev, ttl)
+		c.makeRoom()
+		c.set(key, rev, ttl)
 		return true, nil
 	}
 	existingInfo, err := val.RevInfo()
@@ -100,21 +130,90 @@ func (c *memRevCache) Insert(_ context.Context, rev *path_mgmt.SignedRevInfo) (b
 		panic(err)
 	}
 	if newInfo.Timestamp().After(existingInfo.Timestamp()) {
-		c.c.Set(key, rev, ttl)
+		c.set(key, rev, ttl)
 		return true, nil
 	}
 	return false, nil
 }
 
+// set stores the revocation and updates the metrics. Must be called with the
+// write lock held.
+func (c *memRevCache) set(key string, rev *path_mgmt.SignedRevInfo, ttl time.Duration) {
+	before := c.c.ItemCount()
+	c.c.Set(key, rev, ttl)
+	entries.Add(float64(c.c.ItemCount() - before))
+	insertionsTotal.Inc()
+	c.track(key, time.Now().Add(ttl).UnixNano())
+}
+
+// track records the expiration of the entry with the given key. Must be
+// called with the write lock held.
+func (c *memRevCache) track(key string, expiration int64) {
+	if c.expiryEntries == nil {
+		return
+	}
+	if e, ok := c.expiryEntries[key]; ok {
+		e.expiration = expiration
+		heap.Fix(&c.expiries, e.index)
+		return
+	}
+	e := &expiryEntry{key: key, expiration: expiration}
+	heap.Push(&c.expiries, e)
+	c.expiryEntries[key] = e
+}
+
+// untrack removes the entry with the given key from the expiries. Must be
+// called with the write lock held.
+func (c *memRevCache) untrack(key string) {
+	e, ok := c.expiryEntries[key]
+	if !ok {
+		return
+	}
+	heap.Remove(&c.expiries, e.index)
+	delete(c.expiryEntries, key)
+}
+
+// makeRoom ensures that there is space for one more entry in the cache. It
+// first drops expired entries and then evicts the entries that expire the
+// soonest. Must be called with the write lock held.
+func (c *memRevCache) makeRoom() {
+	if c.maxEntries <= 0 || c.c.ItemCount() < c.maxEntries {
+		return
+	}
+	c.deleteExpired()
+	for c.c.ItemCount() >= c.maxEntries {
+		key, ok := c.soonestExpiring()
+		if !ok {
+			return
+		}
+		c.c.Delete(key)
+		entries.Dec()
+		evictionsTotal.Inc()
+	}
+}
+
+// soonestExpiring returns the key of the entry with the earliest expiration.
+func (c *memRevCache) soonestExpiring() (string, bool) {
+	if len(c.expiries) == 0 {
+		return "", false
+	}
+	return c.expiries[0].key, true
+}
+
 func (c *memRevCache) DeleteExpired(_ context.Context) (int64, error) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
-	var cnt int64
-	c.c.OnEvicted(func(string, interface{}) {
-		cnt++
-	})
+	return c.deleteExpired(), nil
+}
+
+// deleteExpired removes all expired entries and returns the number of removed
+// entries. Must be called with the write lock held.
+func (c *memRevCache) deleteExpired() int64 {
+	before := c.c.ItemCount()
 	c.c.DeleteExpired()
-	return cnt, nil
+	cnt := before - c.c.ItemCount()
+	entries.Sub(float64(cnt))
+	return int64(cnt)
 }
 
 func (c *memRevCache) Close() error { return nil }
@@ -122,3 +221,39 @@ func (c *memRevCache) Close() error { return nil }
 func (c *memRevCache) SetMaxOpenConns(_ int) {}
 
 func (c *memRevCache) SetMaxIdleConns(_ int) {}
+
+// expiryEntry is an entry of the expiryHeap.
+type expiryEntry struct {
+	key        string
+	expiration int64
+	index      int
+}
+
+// expiryHeap is a min-heap of entries ordered by expiration. It implements
+// heap.Interface.
+type expiryHeap []*expiryEntry
+
+func (h expiryHeap) Len() int { return len(h) }
+
+func (h expiryHeap) Less(i, j int) bool { return h[i].expiration < h[j].expiration }
+
+func (h expiryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expiryHeap) Push(x interface{}) {
+	e := x.(*expiryEntry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *expiryHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return e
+}
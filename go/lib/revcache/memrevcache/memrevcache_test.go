@@ -20,11 +20,17 @@ import (
 	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/infra"
 	"github.com/scionproto/scion/go/lib/revcache"
 	"github.com/scionproto/scion/go/lib/revcache/revcachetest"
+	"github.com/scionproto/scion/go/lib/util"
 	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/proto"
 )
 
 var _ (revcachetest.TestableRevCache) = (*testRevCache)(nil)
@@ -44,7 +50,7 @@ func (c *testRevCache) InsertExpired(t *testing.T, _ context.Context,
 	}
 	k := revcache.NewKey(newInfo.IA(), newInfo.IfID)
 	key := k.String()
-	c.c.Set(key, rev, time.Microsecond)
+	c.set(key, rev, time.Microsecond)
 	// Unfortunately inserting with negative TTL makes entries available forever,
 	// so we use 1 micro second and sleep afterwards
 	// to simulate the insertion of an expired entry.
@@ -61,3 +67,94 @@ func TestRevCacheSuite(t *testing.T) {
 		revcachetest.TestRevCache(t, &testRevCache{memRevCache: New()})
 	})
 }
+
+func TestMaxEntries(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	newRev := func(t *testing.T, ifID common.IFIDType, ts time.Time,
+		ttl time.Duration) *path_mgmt.SignedRevInfo {
+
+		sr, err := path_mgmt.NewSignedRevInfo(&path_mgmt.RevInfo{
+			IfID:         ifID,
+			RawIsdas:     ia.IAInt(),
+			LinkType:     proto.LinkType_core,
+			RawTimestamp: util.TimeToSecs(ts),
+			RawTTL:       uint32(ttl.Seconds()),
+		}, infra.NullSigner)
+		require.NoError(t, err)
+		return sr
+	}
+	ctx, cancelF := context.WithTimeout(context.Background(), time.Second)
+	defer cancelF()
+	now := time.Now()
+
+	t.Run("soonest expiring entry is evicted", func(t *testing.T) {
+		c := NewWithMaxEntries(2)
+		for _, rev := range []*path_mgmt.SignedRevInfo{
+			newRev(t, 1, now, 20*time.Second),
+			newRev(t, 2, now, 10*time.Second),
+			newRev(t, 3, now, 30*time.Second),
+		} {
+			inserted, err := c.Insert(ctx, rev)
+			require.NoError(t, err)
+			assert.True(t, inserted)
+		}
+		revs, err := c.Get(ctx, revcache.KeySet{
+			*revcache.NewKey(ia, 1): {},
+			*revcache.NewKey(ia, 2): {},
+			*revcache.NewKey(ia, 3): {},
+		})
+		require.NoError(t, err)
+		assert.Len(t, revs, 2)
+		assert.Contains(t, revs, *revcache.NewKey(ia, 1))
+		assert.Contains(t, revs, *revcache.NewKey(ia, 3))
+	})
+	t.Run("replacing an entry does not evict", func(t *testing.T) {
+		c := NewWithMaxEntries(2)
+		for _, rev := range []*path_mgmt.SignedRevInfo{
+			newRev(t, 1, now.Add(-time.Second), 10*time.Second),
+			newRev(t, 2, now, 20*time.Second),
+			newRev(t, 1, now, 10*time.Second),
+		} {
+			inserted, err := c.Insert(ctx, rev)
+			require.NoError(t, err)
+			assert.True(t, inserted)
+		}
+		assert.Equal(t, 2, c.c.ItemCount())
+	})
+	t.Run("replacing an entry updates its expiration", func(t *testing.T) {
+		c := NewWithMaxEntries(2)
+		for _, rev := range []*path_mgmt.SignedRevInfo{
+			newRev(t, 1, now.Add(-time.Second), 10*time.Second),
+			newRev(t, 2, now, 20*time.Second),
+			// Entry 1 now expires after entry 2.
+			newRev(t, 1, now, 30*time.Second),
+			newRev(t, 3, now, 40*time.Second),
+		} {
+			inserted, err := c.Insert(ctx, rev)
+			require.NoError(t, err)
+			assert.True(t, inserted)
+		}
+		revs, err := c.Get(ctx, revcache.KeySet{
+			*revcache.NewKey(ia, 1): {},
+			*revcache.NewKey(ia, 3): {},
+		})
+		require.NoError(t, err)
+		assert.Len(t, revs, 2)
+		assert.Len(t, c.expiries, 2)
+	})
+	t.Run("expired entries are removed first", func(t *testing.T) {
+		c := &testRevCache{memRevCache: NewWithMaxEntries(2)}
+		c.InsertExpired(t, ctx, newRev(t, 1, now.Add(-2*time.Second), time.Second))
+		_, err := c.Insert(ctx, newRev(t, 2, now, 10*time.Second))
+		require.NoError(t, err)
+		_, err = c.Insert(ctx, newRev(t, 3, now, 20*time.Second))
+		require.NoError(t, err)
+		revs, err := c.Get(ctx, revcache.KeySet{
+			*revcache.NewKey(ia, 2): {},
+			*revcache.NewKey(ia, 3): {},
+		})
+		require.NoError(t, err)
+		assert.Len(t, revs, 2)
+		assert.Len(t, c.expiries, 2)
+	})
+}
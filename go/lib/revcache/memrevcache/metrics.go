@@ -0,0 +1,55 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memrevcache
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/scionproto/scion/go/lib/prom"
+)
+
+const (
+	promNamespace = "revcache"
+	promSubsystem = "mem"
+
+	resultHit  = "hit"
+	resultMiss = "miss"
+)
+
+var (
+	entries         prometheus.Gauge
+	insertionsTotal prometheus.Counter
+	lookupsTotal    *prometheus.CounterVec
+	evictionsTotal  prometheus.Counter
+
+	initMetricsOnce sync.Once
+)
+
+func initMetrics() {
+	initMetricsOnce.Do(func() {
+		entries = prom.NewGauge(promNamespace, promSubsystem, "entries",
+			"Number of revocations in the in-memory revocation cache.")
+		insertionsTotal = prom.NewCounter(promNamespace, promSubsystem, "insertions_total",
+			"Total number of revocations inserted into the in-memory revocation cache.")
+		// Cardinality: 2 (hit, miss)
+		lookupsTotal = prom.NewCounterVec(promNamespace, promSubsystem, "lookups_total",
+			"Total number of revocation lookups in the in-memory revocation cache.",
+			[]string{prom.LabelResult})
+		evictionsTotal = prom.NewCounter(promNamespace, promSubsystem, "evictions_total",
+			"Total number of revocations evicted because the cache was full.")
+	})
+}
@@ -0,0 +1,415 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fake contains an in-memory implementation of the SCIOND API for
+// hermetic unit tests of applications.
+//
+// The fake serves AS, interface and service information from a topology file.
+// Paths to the direct neighbors of the local AS are derived from the
+// interfaces in the topology; further canned paths can be added with
+// AddPath. Revocations sent to the fake remove the paths that traverse the
+// revoked interface, such that failover logic can be tested:
+//
+//	conn, err := fake.NewFromFile("testdata/topology.json")
+//	...
+//	paths, err := conn.Paths(ctx, dst, addr.IA{}, 5, sciond.PathReqFlags{})
+//
+// The forwarding paths of the served entries are not valid SCION paths unless
+// raw paths are set explicitly with AddPath, so packets sent on them are not
+// routable by a real border router.
+package fake
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/hostinfo"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/lib/topology"
+	"github.com/scionproto/scion/go/lib/util"
+	"github.com/scionproto/scion/go/proto"
+)
+
+// ServiceTTL is the TTL of the service information served by the fake.
+const ServiceTTL = 300
+
+var _ sciond.Service = Service{}
+
+// Service is a sciond.Service that returns Connector on every connect.
+type Service struct {
+	Connector *Connector
+}
+
+func (s Service) Connect() (sciond.Connector, error) {
+	return s.Connector, nil
+}
+
+func (s Service) ConnectTimeout(timeout time.Duration) (sciond.Connector, error) {
+	return s.Connector, nil
+}
+
+var _ sciond.Connector = (*Connector)(nil)
+
+// Connector is an in-memory sciond.Connector. It is safe for concurrent use.
+type Connector struct {
+	topo *topology.Topo
+
+	mtx     sync.Mutex
+	paths   map[addr.IA][]sciond.PathReplyEntry
+	revoked map[sciond.PathInterface]struct{}
+	changes []chan *sciond.TopoChangeNotification
+	closed  bool
+}
+
+// NewFromFile creates a fake connector for the AS described by the topology
+// file.
+func NewFromFile(topoFile string) (*Connector, error) {
+	topo, err := topology.LoadFromFile(topoFile)
+	if err != nil {
+		return nil, err
+	}
+	return New(topo), nil
+}
+
+// New creates a fake connector for the AS described by topo. A one-hop path
+// is served for every interface of the local AS.
+func New(topo *topology.Topo) *Connector {
+	c := &Connector{
+		topo:    topo,
+		paths:   make(map[addr.IA][]sciond.PathReplyEntry),
+		revoked: make(map[sciond.PathInterface]struct{}),
+	}
+	expiry := util.TimeToSecs(time.Now().Add(spath.MaxTTL * time.Second))
+	for _, ifid := range sortedIFIDs(topo.IFInfoMap) {
+		ifInfo := topo.IFInfoMap[ifid]
+		mtu := topo.MTU
+		if ifInfo.MTU != 0 && ifInfo.MTU < mtu {
+			mtu = ifInfo.MTU
+		}
+		c.paths[ifInfo.ISD_AS] = append(c.paths[ifInfo.ISD_AS], sciond.PathReplyEntry{
+			Path: &sciond.FwdPathMeta{
+				Mtu: uint16(mtu),
+				Interfaces: []sciond.PathInterface{
+					{RawIsdas: topo.ISD_AS.IAInt(), IfID: ifid},
+					{RawIsdas: ifInfo.ISD_AS.IAInt(), IfID: ifInfo.RemoteIFID},
+				},
+				ExpTime: expiry,
+			},
+			HostInfo: hostinfo.FromTopoBRAddr(*ifInfo.InternalAddrs),
+		})
+	}
+	return c
+}
+
+// AddPath adds a canned path to the destination of the path. If the host
+// information of the entry is not set, the internal address of the border
+// router owning the first interface is used. If the path does not have an
+// expiration time, it expires after the maximum path TTL.
+func (c *Connector) AddPath(entry sciond.PathReplyEntry) error {
+	if entry.Path == nil || len(entry.Path.Interfaces) < 2 {
+		return common.NewBasicError("Path must contain at least two interfaces", nil,
+			"path", entry.Path)
+	}
+	if src := entry.Path.SrcIA(); !src.Equal(c.topo.ISD_AS) {
+		return common.NewBasicError("Path does not start in the local AS", nil,
+			"expected", c.topo.ISD_AS, "actual", src)
+	}
+	e := entry.Copy()
+	if len(e.HostInfo.Addrs.IPv4) == 0 && len(e.HostInfo.Addrs.IPv6) == 0 {
+		ifid := e.Path.Interfaces[0].IfID
+		ifInfo, ok := c.topo.IFInfoMap[ifid]
+		if !ok {
+			return common.NewBasicError("First interface not found in topology", nil,
+				"ifid", ifid)
+		}
+		e.HostInfo = hostinfo.FromTopoBRAddr(*ifInfo.InternalAddrs)
+	}
+	if e.Path.Mtu == 0 {
+		e.Path.Mtu = uint16(c.topo.MTU)
+	}
+	if e.Path.ExpTime == 0 {
+		e.Path.ExpTime = util.TimeToSecs(time.Now().Add(spath.MaxTTL * time.Second))
+	}
+	dst := e.Path.DstIA()
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.paths[dst] = append(c.paths[dst], *e)
+	return nil
+}
+
+// ResetRevocations forgets all revocations received so far, i.e., all canned
+// paths are served again.
+func (c *Connector) ResetRevocations() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.revoked = make(map[sciond.PathInterface]struct{})
+}
+
+func (c *Connector) Paths(ctx context.Context, dst, src addr.IA, max uint16,
+	f sciond.PathReqFlags) (*sciond.PathReply, error) {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if err := c.checkClosed(); err != nil {
+		return nil, err
+	}
+	if src.IsZero() {
+		src = c.topo.ISD_AS
+	}
+	if !src.Equal(c.topo.ISD_AS) {
+		return &sciond.PathReply{ErrorCode: sciond.ErrorBadSrcIA}, nil
+	}
+	if dst.Equal(c.topo.ISD_AS) {
+		// Mirror SCIOND, which replies with an empty path for local
+		// destinations.
+		return &sciond.PathReply{
+			Entries: []sciond.PathReplyEntry{
+				{
+					Path: &sciond.FwdPathMeta{
+						Mtu:        uint16(c.topo.MTU),
+						Interfaces: []sciond.PathInterface{},
+						ExpTime: util.TimeToSecs(
+							time.Now().Add(spath.MaxTTL * time.Second)),
+					},
+				},
+			},
+		}, nil
+	}
+	reply := &sciond.PathReply{}
+	for _, entry := range c.paths[dst] {
+		if c.isRevoked(entry.Path) {
+			continue
+		}
+		reply.Entries = append(reply.Entries, *entry.Copy())
+		if max != 0 && len(reply.Entries) == int(max) {
+			break
+		}
+	}
+	if len(reply.Entries) == 0 {
+		reply.ErrorCode = sciond.ErrorNoPaths
+	}
+	return reply, nil
+}
+
+// HiddenPaths returns the same paths as Paths, the fake does not know any
+// hidden segments.
+func (c *Connector) HiddenPaths(ctx context.Context, dst, src addr.IA, max uint16,
+	f sciond.PathReqFlags, groups []*path_mgmt.HPGroupId) (*sciond.PathReply, error) {
+
+	return c.Paths(ctx, dst, src, max, f)
+}
+
+func (c *Connector) ASInfo(ctx context.Context, ia addr.IA) (*sciond.ASInfoReply, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if err := c.checkClosed(); err != nil {
+		return nil, err
+	}
+	reply := &sciond.ASInfoReply{}
+	if ia.IsZero() || ia.Equal(c.topo.ISD_AS) {
+		reply.Entries = append(reply.Entries, sciond.ASInfoReplyEntry{
+			RawIsdas: c.topo.ISD_AS.IAInt(),
+			Mtu:      uint16(c.topo.MTU),
+			IsCore:   c.topo.Core,
+		})
+	}
+	return reply, nil
+}
+
+func (c *Connector) IFInfo(ctx context.Context,
+	ifs []common.IFIDType) (*sciond.IFInfoReply, error) {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if err := c.checkClosed(); err != nil {
+		return nil, err
+	}
+	if len(ifs) == 0 {
+		ifs = sortedIFIDs(c.topo.IFInfoMap)
+	}
+	reply := &sciond.IFInfoReply{}
+	for _, ifid := range ifs {
+		ifInfo, ok := c.topo.IFInfoMap[ifid]
+		if !ok {
+			continue
+		}
+		reply.RawEntries = append(reply.RawEntries, sciond.IFInfoReplyEntry{
+			IfID:     ifid,
+			HostInfo: hostinfo.FromTopoBRAddr(*ifInfo.InternalAddrs),
+		})
+	}
+	return reply, nil
+}
+
+func (c *Connector) SVCInfo(ctx context.Context,
+	svcTypes []proto.ServiceType) (*sciond.ServiceInfoReply, error) {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if err := c.checkClosed(); err != nil {
+		return nil, err
+	}
+	reply := &sciond.ServiceInfoReply{}
+	for _, t := range svcTypes {
+		entry := sciond.ServiceInfoReplyEntry{ServiceType: t, Ttl: ServiceTTL}
+		// Unknown service types are answered with an empty entry, like
+		// SCIOND does.
+		addrs, _ := c.topo.GetAllTopoAddrs(t)
+		for _, a := range addrs {
+			entry.HostInfos = append(entry.HostInfos, hostinfo.FromTopoAddr(a))
+		}
+		reply.Entries = append(reply.Entries, entry)
+	}
+	return reply, nil
+}
+
+func (c *Connector) RevNotificationFromRaw(ctx context.Context,
+	b []byte) (*sciond.RevReply, error) {
+
+	sRevInfo, err := path_mgmt.NewSignedRevInfoFromRaw(b)
+	if err != nil {
+		return nil, err
+	}
+	return c.RevNotification(ctx, sRevInfo)
+}
+
+// RevNotification removes the paths traversing the revoked interface. The
+// signature of the revocation is not verified.
+func (c *Connector) RevNotification(ctx context.Context,
+	sRevInfo *path_mgmt.SignedRevInfo) (*sciond.RevReply, error) {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if err := c.checkClosed(); err != nil {
+		return nil, err
+	}
+	revInfo, err := sRevInfo.RevInfo()
+	if err != nil {
+		return &sciond.RevReply{Result: sciond.RevInvalid}, nil
+	}
+	if revInfo.Active() != nil {
+		return &sciond.RevReply{Result: sciond.RevStale}, nil
+	}
+	iface := sciond.PathInterface{RawIsdas: revInfo.RawIsdas, IfID: revInfo.IfID}
+	c.revoked[iface] = struct{}{}
+	return &sciond.RevReply{Result: sciond.RevValid}, nil
+}
+
+// NextQueries always returns an empty reply, the fake does not fetch
+// segments.
+func (c *Connector) NextQueries(ctx context.Context,
+	dst addr.IA) (*sciond.NextQueryReply, error) {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if err := c.checkClosed(); err != nil {
+		return nil, err
+	}
+	return &sciond.NextQueryReply{}, nil
+}
+
+// DeleteNextQueries always returns an empty reply, the fake does not fetch
+// segments.
+func (c *Connector) DeleteNextQueries(ctx context.Context,
+	dst addr.IA) (*sciond.NextQueryReply, error) {
+
+	return c.NextQueries(ctx, dst)
+}
+
+// PathFeedback accepts and discards the feedback, the fake does not rank
+// paths.
+func (c *Connector) PathFeedback(ctx context.Context, pathKey []byte, rtt time.Duration,
+	loss float64) (*sciond.PathFeedbackReply, error) {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if err := c.checkClosed(); err != nil {
+		return nil, err
+	}
+	return &sciond.PathFeedbackReply{Result: sciond.PathFeedbackDisabled}, nil
+}
+
+// TopologyChanges returns a channel that receives the notifications triggered
+// via NotifyTopologyChange.
+func (c *Connector) TopologyChanges() <-chan *sciond.TopoChangeNotification {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	ch := make(chan *sciond.TopoChangeNotification, 1)
+	if c.closed {
+		close(ch)
+		return ch
+	}
+	c.changes = append(c.changes, ch)
+	return ch
+}
+
+// NotifyTopologyChange delivers a topology change notification to all
+// channels returned by TopologyChanges, as if SCIOND loaded a new topology.
+// The topology of the fake itself does not change.
+func (c *Connector) NotifyTopologyChange() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	n := sciond.NewTopoChangeNotification(c.topo.Timestamp)
+	for _, ch := range c.changes {
+		select {
+		case ch <- n:
+		default:
+		}
+	}
+}
+
+// Close closes the connector. Subsequent calls return an error.
+func (c *Connector) Close(ctx context.Context) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if !c.closed {
+		for _, ch := range c.changes {
+			close(ch)
+		}
+		c.changes = nil
+	}
+	c.closed = true
+	return nil
+}
+
+func (c *Connector) checkClosed() error {
+	if c.closed {
+		return common.NewBasicError("Connector closed", nil)
+	}
+	return nil
+}
+
+func (c *Connector) isRevoked(path *sciond.FwdPathMeta) bool {
+	for _, iface := range path.Interfaces {
+		if _, ok := c.revoked[iface]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedIFIDs(m topology.IfInfoMap) []common.IFIDType {
+	ifids := make([]common.IFIDType, 0, len(m))
+	for ifid := range m {
+		ifids = append(ifids, ifid)
+	}
+	sort.Slice(ifids, func(i, j int) bool { return ifids[i] < ifids[j] })
+	return ifids
+}
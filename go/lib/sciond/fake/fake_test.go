@@ -0,0 +1,269 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/sciond/fake"
+	"github.com/scionproto/scion/go/lib/util"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/proto"
+)
+
+var (
+	ia110 = xtest.MustParseIA("1-ff00:0:110")
+	ia111 = xtest.MustParseIA("1-ff00:0:111")
+	ia120 = xtest.MustParseIA("1-ff00:0:120")
+	ia130 = xtest.MustParseIA("1-ff00:0:130")
+)
+
+func TestConnectorPaths(t *testing.T) {
+	testCases := map[string]struct {
+		Dst                addr.IA
+		Src                addr.IA
+		Max                uint16
+		ExpectedErrorCode  sciond.PathErrorCode
+		ExpectedInterfaces [][]string
+	}{
+		"neighbor with multiple links": {
+			Dst: ia111,
+			ExpectedInterfaces: [][]string{
+				{"1-ff00:0:110#1", "1-ff00:0:111#0"},
+				{"1-ff00:0:110#2", "1-ff00:0:111#0"},
+			},
+		},
+		"max paths": {
+			Dst: ia111,
+			Max: 1,
+			ExpectedInterfaces: [][]string{
+				{"1-ff00:0:110#1", "1-ff00:0:111#0"},
+			},
+		},
+		"explicit source": {
+			Dst: ia120,
+			Src: ia110,
+			ExpectedInterfaces: [][]string{
+				{"1-ff00:0:110#3", "1-ff00:0:120#0"},
+			},
+		},
+		"local destination": {
+			Dst:                ia110,
+			ExpectedInterfaces: [][]string{{}},
+		},
+		"unknown destination": {
+			Dst:               ia130,
+			ExpectedErrorCode: sciond.ErrorNoPaths,
+		},
+		"bad source": {
+			Dst:               ia120,
+			Src:               ia111,
+			ExpectedErrorCode: sciond.ErrorBadSrcIA,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			conn := newConnector(t)
+			reply, err := conn.Paths(context.Background(), tc.Dst, tc.Src, tc.Max,
+				sciond.PathReqFlags{})
+			require.NoError(t, err)
+			assert.Equal(t, tc.ExpectedErrorCode, reply.ErrorCode)
+			assert.Equal(t, tc.ExpectedInterfaces, interfaces(reply))
+		})
+	}
+}
+
+func TestConnectorPathsMetadata(t *testing.T) {
+	conn := newConnector(t)
+	reply, err := conn.Paths(context.Background(), ia111, addr.IA{}, 0, sciond.PathReqFlags{})
+	require.NoError(t, err)
+	require.Len(t, reply.Entries, 2)
+	assert.Equal(t, uint16(1280), reply.Entries[0].Path.Mtu)
+	assert.Equal(t, uint16(1472), reply.Entries[1].Path.Mtu)
+	assert.True(t, reply.Entries[0].Path.Expiry().After(time.Now()))
+	assert.True(t, net.IPv4(127, 0, 0, 1).Equal(reply.Entries[0].HostInfo.Addrs.IPv4))
+	assert.Equal(t, uint16(31001), reply.Entries[0].HostInfo.Port)
+}
+
+func TestConnectorAddPath(t *testing.T) {
+	conn := newConnector(t)
+	err := conn.AddPath(sciond.PathReplyEntry{
+		Path: &sciond.FwdPathMeta{
+			FwdPath: []byte{1, 2, 3, 4, 5, 6, 7, 8},
+			Interfaces: mustPathInterfaces(t, "1-ff00:0:110#3", "1-ff00:0:120#4",
+				"1-ff00:0:120#5", "1-ff00:0:130#6"),
+		},
+	})
+	require.NoError(t, err)
+
+	reply, err := conn.Paths(context.Background(), ia130, addr.IA{}, 0, sciond.PathReqFlags{})
+	require.NoError(t, err)
+	require.Len(t, reply.Entries, 1)
+	entry := reply.Entries[0]
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8}, entry.Path.FwdPath)
+	assert.Equal(t, uint16(1472), entry.Path.Mtu)
+	assert.True(t, net.IPv4(127, 0, 0, 2).Equal(entry.HostInfo.Addrs.IPv4))
+
+	t.Run("bad source", func(t *testing.T) {
+		err := conn.AddPath(sciond.PathReplyEntry{
+			Path: &sciond.FwdPathMeta{
+				Interfaces: mustPathInterfaces(t, "1-ff00:0:111#1", "1-ff00:0:130#6"),
+			},
+		})
+		assert.Error(t, err)
+	})
+	t.Run("unknown first interface", func(t *testing.T) {
+		err := conn.AddPath(sciond.PathReplyEntry{
+			Path: &sciond.FwdPathMeta{
+				Interfaces: mustPathInterfaces(t, "1-ff00:0:110#42", "1-ff00:0:130#6"),
+			},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestConnectorRevNotification(t *testing.T) {
+	conn := newConnector(t)
+	ctx := context.Background()
+
+	reply, err := conn.RevNotification(ctx, signedRevInfo(t, ia110, 1, time.Now()))
+	require.NoError(t, err)
+	assert.Equal(t, sciond.RevValid, reply.Result)
+	paths, err := conn.Paths(ctx, ia111, addr.IA{}, 0, sciond.PathReqFlags{})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"1-ff00:0:110#2", "1-ff00:0:111#0"}}, interfaces(paths))
+
+	reply, err = conn.RevNotification(ctx,
+		signedRevInfo(t, ia110, 2, time.Now().Add(-time.Hour)))
+	require.NoError(t, err)
+	assert.Equal(t, sciond.RevStale, reply.Result)
+
+	conn.ResetRevocations()
+	paths, err = conn.Paths(ctx, ia111, addr.IA{}, 0, sciond.PathReqFlags{})
+	require.NoError(t, err)
+	assert.Len(t, paths.Entries, 2)
+}
+
+func TestConnectorInfo(t *testing.T) {
+	conn := newConnector(t)
+	ctx := context.Background()
+
+	asInfo, err := conn.ASInfo(ctx, addr.IA{})
+	require.NoError(t, err)
+	assert.Equal(t, []sciond.ASInfoReplyEntry{
+		{RawIsdas: ia110.IAInt(), Mtu: 1472, IsCore: true},
+	}, asInfo.Entries)
+
+	ifInfo, err := conn.IFInfo(ctx, nil)
+	require.NoError(t, err)
+	assert.Len(t, ifInfo.Entries(), 3)
+	ifInfo, err = conn.IFInfo(ctx, []common.IFIDType{3, 42})
+	require.NoError(t, err)
+	require.Len(t, ifInfo.RawEntries, 1)
+	assert.Equal(t, uint16(31002), ifInfo.RawEntries[0].HostInfo.Port)
+
+	svcInfo, err := conn.SVCInfo(ctx, []proto.ServiceType{proto.ServiceType_ps})
+	require.NoError(t, err)
+	require.Len(t, svcInfo.Entries, 1)
+	require.Len(t, svcInfo.Entries[0].HostInfos, 1)
+	assert.Equal(t, uint16(30252), svcInfo.Entries[0].HostInfos[0].Port)
+}
+
+func TestConnectorClose(t *testing.T) {
+	conn := newConnector(t)
+	svc := fake.Service{Connector: conn}
+	c, err := svc.Connect()
+	require.NoError(t, err)
+	require.NoError(t, c.Close(context.Background()))
+	_, err = conn.Paths(context.Background(), ia111, addr.IA{}, 0, sciond.PathReqFlags{})
+	assert.Error(t, err)
+}
+
+func TestConnectorTopologyChanges(t *testing.T) {
+	conn := newConnector(t)
+	changes := conn.TopologyChanges()
+	conn.NotifyTopologyChange()
+	// A pending notification is not queued twice.
+	conn.NotifyTopologyChange()
+	n, ok := <-changes
+	require.True(t, ok)
+	assert.NotNil(t, n)
+	select {
+	case n := <-changes:
+		t.Fatalf("Unexpected notification %v", n)
+	default:
+	}
+	require.NoError(t, conn.Close(context.Background()))
+	_, ok = <-changes
+	assert.False(t, ok)
+	_, ok = <-conn.TopologyChanges()
+	assert.False(t, ok)
+}
+
+func newConnector(t *testing.T) *fake.Connector {
+	t.Helper()
+	conn, err := fake.NewFromFile("testdata/topology.json")
+	require.NoError(t, err)
+	return conn
+}
+
+func interfaces(reply *sciond.PathReply) [][]string {
+	var paths [][]string
+	for _, entry := range reply.Entries {
+		ifaces := []string{}
+		for _, iface := range entry.Path.Interfaces {
+			ifaces = append(ifaces, iface.String())
+		}
+		paths = append(paths, ifaces)
+	}
+	return paths
+}
+
+func mustPathInterfaces(t *testing.T, ifaces ...string) []sciond.PathInterface {
+	t.Helper()
+	var result []sciond.PathInterface
+	for _, s := range ifaces {
+		iface, err := sciond.NewPathInterface(s)
+		require.NoError(t, err)
+		result = append(result, iface)
+	}
+	return result
+}
+
+func signedRevInfo(t *testing.T, ia addr.IA, ifid common.IFIDType,
+	ts time.Time) *path_mgmt.SignedRevInfo {
+
+	t.Helper()
+	revInfo := &path_mgmt.RevInfo{
+		IfID:         ifid,
+		RawIsdas:     ia.IAInt(),
+		LinkType:     proto.LinkType_child,
+		RawTTL:       10,
+		RawTimestamp: util.TimeToSecs(ts),
+	}
+	rawRev, err := revInfo.Pack()
+	require.NoError(t, err)
+	return &path_mgmt.SignedRevInfo{Blob: rawRev, Sign: &proto.SignS{}}
+}
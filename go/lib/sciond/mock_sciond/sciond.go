@@ -121,6 +121,36 @@ func (mr *MockConnectorMockRecorder) Close(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockConnector)(nil).Close), arg0)
 }
 
+// DeleteNextQueries mocks base method
+func (m *MockConnector) DeleteNextQueries(arg0 context.Context, arg1 addr.IA) (*sciond.NextQueryReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNextQueries", arg0, arg1)
+	ret0, _ := ret[0].(*sciond.NextQueryReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteNextQueries indicates an expected call of DeleteNextQueries
+func (mr *MockConnectorMockRecorder) DeleteNextQueries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNextQueries", reflect.TypeOf((*MockConnector)(nil).DeleteNextQueries), arg0, arg1)
+}
+
+// HiddenPaths mocks base method
+func (m *MockConnector) HiddenPaths(arg0 context.Context, arg1, arg2 addr.IA, arg3 uint16, arg4 sciond.PathReqFlags, arg5 []*path_mgmt.HPGroupId) (*sciond.PathReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HiddenPaths", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(*sciond.PathReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HiddenPaths indicates an expected call of HiddenPaths
+func (mr *MockConnectorMockRecorder) HiddenPaths(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HiddenPaths", reflect.TypeOf((*MockConnector)(nil).HiddenPaths), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
 // IFInfo mocks base method
 func (m *MockConnector) IFInfo(arg0 context.Context, arg1 []common.IFIDType) (*sciond.IFInfoReply, error) {
 	m.ctrl.T.Helper()
@@ -136,6 +166,36 @@ func (mr *MockConnectorMockRecorder) IFInfo(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IFInfo", reflect.TypeOf((*MockConnector)(nil).IFInfo), arg0, arg1)
 }
 
+// NextQueries mocks base method
+func (m *MockConnector) NextQueries(arg0 context.Context, arg1 addr.IA) (*sciond.NextQueryReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextQueries", arg0, arg1)
+	ret0, _ := ret[0].(*sciond.NextQueryReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextQueries indicates an expected call of NextQueries
+func (mr *MockConnectorMockRecorder) NextQueries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextQueries", reflect.TypeOf((*MockConnector)(nil).NextQueries), arg0, arg1)
+}
+
+// PathFeedback mocks base method
+func (m *MockConnector) PathFeedback(arg0 context.Context, arg1 []byte, arg2 time.Duration, arg3 float64) (*sciond.PathFeedbackReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PathFeedback", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*sciond.PathFeedbackReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PathFeedback indicates an expected call of PathFeedback
+func (mr *MockConnectorMockRecorder) PathFeedback(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PathFeedback", reflect.TypeOf((*MockConnector)(nil).PathFeedback), arg0, arg1, arg2, arg3)
+}
+
 // Paths mocks base method
 func (m *MockConnector) Paths(arg0 context.Context, arg1, arg2 addr.IA, arg3 uint16, arg4 sciond.PathReqFlags) (*sciond.PathReply, error) {
 	m.ctrl.T.Helper()
@@ -195,3 +255,17 @@ func (mr *MockConnectorMockRecorder) SVCInfo(arg0, arg1 interface{}) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SVCInfo", reflect.TypeOf((*MockConnector)(nil).SVCInfo), arg0, arg1)
 }
+
+// TopologyChanges mocks base method
+func (m *MockConnector) TopologyChanges() <-chan *sciond.TopoChangeNotification {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TopologyChanges")
+	ret0, _ := ret[0].(<-chan *sciond.TopoChangeNotification)
+	return ret0
+}
+
+// TopologyChanges indicates an expected call of TopologyChanges
+func (mr *MockConnectorMockRecorder) TopologyChanges() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopologyChanges", reflect.TypeOf((*MockConnector)(nil).TopologyChanges))
+}
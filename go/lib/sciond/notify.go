@@ -0,0 +1,70 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sciond
+
+import "sync"
+
+// topoSubscribers fans out topology change notifications to the channels
+// returned by Connector.TopologyChanges.
+type topoSubscribers struct {
+	mu     sync.Mutex
+	chans  []chan *TopoChangeNotification
+	closed bool
+}
+
+// subscribe returns a new channel that receives all notifications published
+// after the call. If the subscribers were already closed, the returned
+// channel is closed as well.
+func (s *topoSubscribers) subscribe() <-chan *TopoChangeNotification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// A single buffered notification is enough, because notifications only
+	// signal that cached information is stale.
+	c := make(chan *TopoChangeNotification, 1)
+	if s.closed {
+		close(c)
+		return c
+	}
+	s.chans = append(s.chans, c)
+	return c
+}
+
+// publish delivers n to all subscribers. Subscribers that still have a
+// pending notification do not receive n.
+func (s *topoSubscribers) publish(n *TopoChangeNotification) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.chans {
+		select {
+		case c <- n:
+		default:
+		}
+	}
+}
+
+// close closes all subscriber channels. Subsequent subscriptions return closed
+// channels.
+func (s *topoSubscribers) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	for _, c := range s.chans {
+		close(c)
+	}
+	s.chans = nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sciond
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/sock/reliable"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/proto"
+)
+
+func TestConnectorTopologyChanges(t *testing.T) {
+	dir, cleanF := xtest.MustTempDir("", "sciond")
+	defer cleanF()
+	path := filepath.Join(dir, "sd.sock")
+	listener, err := reliable.Listen(path)
+	require.NoError(t, err)
+	defer listener.Close()
+	accepted := make(chan *reliable.Conn, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			accepted <- conn.(*reliable.Conn)
+		}
+	}()
+
+	c, err := connect(path)
+	require.NoError(t, err)
+	changes := c.TopologyChanges()
+	c.ifInfos.SetDefault("1", IFInfoReplyEntry{IfID: 1})
+	server := <-accepted
+	defer server.Close()
+
+	ts := time.Unix(1568000000, 0)
+	b, err := proto.PackRoot(&Pld{
+		Which:                  proto.SCIONDMsg_Which_topoChangeNotification,
+		TopoChangeNotification: NewTopoChangeNotification(ts),
+	})
+	require.NoError(t, err)
+	_, err = server.WriteTo(b, nil)
+	require.NoError(t, err)
+	select {
+	case n := <-changes:
+		assert.Equal(t, ts, n.Timestamp())
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for notification")
+	}
+	_, remaining := c.getIFEntriesFromCache([]common.IFIDType{1})
+	assert.Equal(t, []common.IFIDType{1}, remaining, "cache must be flushed")
+
+	require.NoError(t, c.Close(context.Background()))
+	select {
+	case _, ok := <-changes:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("Channel not closed")
+	}
+}
+
+func TestTopoSubscribers(t *testing.T) {
+	var s topoSubscribers
+	c := s.subscribe()
+	first := &TopoChangeNotification{RawTimestamp: 1}
+	s.publish(first)
+	// The second notification is dropped, because the first is still pending.
+	s.publish(&TopoChangeNotification{RawTimestamp: 2})
+	assert.Equal(t, first, <-c)
+	s.close()
+	_, ok := <-c
+	assert.False(t, ok)
+	_, ok = <-s.subscribe()
+	assert.False(t, ok)
+}
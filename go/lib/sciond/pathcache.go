@@ -0,0 +1,238 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sciond
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/log"
+)
+
+// DefaultPathCacheSize is the number of path replies cached by a path caching
+// connector, if no size is configured.
+const DefaultPathCacheSize = 128
+
+var _ Connector = (*pathCachingConnector)(nil)
+
+// pathCachingConnector answers path requests from an in-process LRU cache of
+// path replies, and forwards all other requests to the wrapped connector.
+type pathCachingConnector struct {
+	Connector
+	cache *pathCache
+}
+
+// NewPathCachingConnector wraps conn, such that path replies are cached in
+// process and repeated path requests for the same destination do not go to
+// SCIOND. At most size replies are cached; if size is not positive,
+// DefaultPathCacheSize is used. A cached reply is used until the first of its
+// paths expires.
+//
+// Only successful, non-stale replies are cached. Requests with the Refresh
+// flag set always go to SCIOND, and their replies replace the cached ones.
+// Revocations that are sent to SCIOND via the returned connector remove the
+// replies that contain the revoked interface, and the cache is flushed
+// whenever SCIOND reports a topology change.
+func NewPathCachingConnector(conn Connector, size int) Connector {
+	if size <= 0 {
+		size = DefaultPathCacheSize
+	}
+	c := &pathCachingConnector{
+		Connector: conn,
+		cache:     newPathCache(size),
+	}
+	topoChanges := conn.TopologyChanges()
+	go func() {
+		defer log.LogPanicAndExit()
+		for range topoChanges {
+			c.cache.flush()
+		}
+	}()
+	return c
+}
+
+func (c *pathCachingConnector) Paths(ctx context.Context, dst, src addr.IA, max uint16,
+	f PathReqFlags) (*PathReply, error) {
+
+	key := newPathCacheKey(dst, src, max, f)
+	if !f.Refresh {
+		if reply, ok := c.cache.get(key, time.Now()); ok {
+			return reply, nil
+		}
+	}
+	reply, err := c.Connector.Paths(ctx, dst, src, max, f)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.add(key, reply)
+	return reply, nil
+}
+
+func (c *pathCachingConnector) RevNotificationFromRaw(ctx context.Context,
+	b []byte) (*RevReply, error) {
+
+	if sRevInfo, err := path_mgmt.NewSignedRevInfoFromRaw(b); err == nil {
+		c.invalidate(sRevInfo)
+	}
+	return c.Connector.RevNotificationFromRaw(ctx, b)
+}
+
+func (c *pathCachingConnector) RevNotification(ctx context.Context,
+	sRevInfo *path_mgmt.SignedRevInfo) (*RevReply, error) {
+
+	c.invalidate(sRevInfo)
+	return c.Connector.RevNotification(ctx, sRevInfo)
+}
+
+// invalidate removes the replies that contain the revoked interface. Because
+// SCIOND verifies the revocation, the cache is invalidated irrespective of
+// whether SCIOND accepts it; this only costs an additional path request.
+func (c *pathCachingConnector) invalidate(sRevInfo *path_mgmt.SignedRevInfo) {
+	revInfo, err := sRevInfo.RevInfo()
+	if err != nil {
+		return
+	}
+	c.cache.removeInterface(PathInterface{RawIsdas: revInfo.RawIsdas, IfID: revInfo.IfID})
+}
+
+// pathCacheKey identifies a path request. The Refresh flag is not part of the
+// key, such that refreshed replies replace the cached ones.
+type pathCacheKey struct {
+	dst, src addr.IA
+	max      uint16
+	flags    PathReqFlags
+}
+
+func newPathCacheKey(dst, src addr.IA, max uint16, f PathReqFlags) pathCacheKey {
+	f.Refresh = false
+	return pathCacheKey{dst: dst, src: src, max: max, flags: f}
+}
+
+type pathCacheEntry struct {
+	key    pathCacheKey
+	reply  *PathReply
+	expiry time.Time
+}
+
+// pathCache is an LRU cache of path replies. It is safe for concurrent use.
+type pathCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[pathCacheKey]*list.Element
+	// lru holds the entries, the most recently used at the front.
+	lru *list.List
+}
+
+func newPathCache(size int) *pathCache {
+	return &pathCache{
+		size:    size,
+		entries: make(map[pathCacheKey]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// get returns a copy of the cached reply for key, if it has not expired at
+// now.
+func (c *pathCache) get(key pathCacheKey, now time.Time) (*PathReply, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*pathCacheEntry)
+	if !now.Before(entry.expiry) {
+		c.remove(elem)
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return copyPathReply(entry.reply), true
+}
+
+// add caches a copy of reply for key, if it is cacheable. Otherwise, the
+// cached reply for key, if any, is removed.
+func (c *pathCache) add(key pathCacheKey, reply *PathReply) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	if reply == nil || reply.ErrorCode != ErrorOk || reply.Stale || len(reply.Entries) == 0 {
+		return
+	}
+	entry := &pathCacheEntry{key: key, reply: copyPathReply(reply)}
+	for i, e := range reply.Entries {
+		if e.Path == nil {
+			return
+		}
+		if expiry := e.Path.Expiry(); i == 0 || expiry.Before(entry.expiry) {
+			entry.expiry = expiry
+		}
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+// removeInterface removes all replies that contain a path through iface.
+func (c *pathCache) removeInterface(iface PathInterface) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, elem := range c.entries {
+		if containsInterface(elem.Value.(*pathCacheEntry).reply, iface) {
+			c.remove(elem)
+		}
+	}
+}
+
+func (c *pathCache) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[pathCacheKey]*list.Element)
+	c.lru.Init()
+}
+
+// remove removes elem. Must be called with the lock held.
+func (c *pathCache) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*pathCacheEntry).key)
+}
+
+func containsInterface(reply *PathReply, iface PathInterface) bool {
+	for _, e := range reply.Entries {
+		for _, pathIface := range e.Path.Interfaces {
+			if pathIface.RawIsdas == iface.RawIsdas && pathIface.IfID == iface.IfID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// copyPathReply returns a deep copy of reply, such that callers can modify the
+// replies they get from the cache.
+func copyPathReply(reply *PathReply) *PathReply {
+	res := *reply
+	res.Entries = make([]PathReplyEntry, 0, len(reply.Entries))
+	for i := range reply.Entries {
+		res.Entries = append(res.Entries, *reply.Entries[i].Copy())
+	}
+	return &res
+}
@@ -0,0 +1,174 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sciond
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/util"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/proto"
+)
+
+// countingConnector answers path requests with a copy of reply and counts the
+// requests.
+type countingConnector struct {
+	Connector
+	reply       *PathReply
+	requests    int
+	topoChanges chan *TopoChangeNotification
+}
+
+func (c *countingConnector) Paths(_ context.Context, _, _ addr.IA, _ uint16,
+	_ PathReqFlags) (*PathReply, error) {
+
+	c.requests++
+	return copyPathReply(c.reply), nil
+}
+
+func (c *countingConnector) RevNotification(_ context.Context,
+	_ *path_mgmt.SignedRevInfo) (*RevReply, error) {
+
+	return &RevReply{}, nil
+}
+
+func (c *countingConnector) TopologyChanges() <-chan *TopoChangeNotification {
+	return c.topoChanges
+}
+
+func TestPathCachingConnector(t *testing.T) {
+	ctx := context.Background()
+	src := xtest.MustParseIA("1-ff00:0:110")
+	dst := xtest.MustParseIA("1-ff00:0:111")
+	newReply := func(expiry time.Time) *PathReply {
+		return &PathReply{
+			ErrorCode: ErrorOk,
+			Entries: []PathReplyEntry{
+				{
+					Path: &FwdPathMeta{
+						Interfaces: []PathInterface{
+							{RawIsdas: src.IAInt(), IfID: 1},
+							{RawIsdas: dst.IAInt(), IfID: 2},
+						},
+						ExpTime: util.TimeToSecs(expiry),
+					},
+				},
+			},
+		}
+	}
+	newConn := func(reply *PathReply, size int) (*countingConnector, Connector) {
+		sd := &countingConnector{
+			reply:       reply,
+			topoChanges: make(chan *TopoChangeNotification),
+		}
+		return sd, NewPathCachingConnector(sd, size)
+	}
+
+	t.Run("repeated requests are cached", func(t *testing.T) {
+		sd, conn := newConn(newReply(time.Now().Add(time.Hour)), 0)
+		defer close(sd.topoChanges)
+		for i := 0; i < 3; i++ {
+			reply, err := conn.Paths(ctx, dst, src, 5, PathReqFlags{})
+			require.NoError(t, err)
+			assert.Len(t, reply.Entries, 1)
+		}
+		assert.Equal(t, 1, sd.requests)
+		// Other parameters are cached separately.
+		_, err := conn.Paths(ctx, dst, src, 1, PathReqFlags{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, sd.requests)
+		// Refresh requests bypass the cache.
+		_, err = conn.Paths(ctx, dst, src, 5, PathReqFlags{Refresh: true})
+		require.NoError(t, err)
+		assert.Equal(t, 3, sd.requests)
+	})
+	t.Run("cached replies can be modified", func(t *testing.T) {
+		sd, conn := newConn(newReply(time.Now().Add(time.Hour)), 0)
+		defer close(sd.topoChanges)
+		reply, err := conn.Paths(ctx, dst, src, 5, PathReqFlags{})
+		require.NoError(t, err)
+		reply.Entries[0].Path.Interfaces[0].IfID = 42
+		reply, err = conn.Paths(ctx, dst, src, 5, PathReqFlags{})
+		require.NoError(t, err)
+		assert.Equal(t, common.IFIDType(1), reply.Entries[0].Path.Interfaces[0].IfID)
+	})
+	t.Run("expired and failed replies are not cached", func(t *testing.T) {
+		sd, conn := newConn(newReply(time.Now().Add(-time.Second)), 0)
+		defer close(sd.topoChanges)
+		for i := 0; i < 2; i++ {
+			_, err := conn.Paths(ctx, dst, src, 5, PathReqFlags{})
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, sd.requests)
+		sd.reply = &PathReply{ErrorCode: ErrorNoPaths}
+		for i := 0; i < 2; i++ {
+			_, err := conn.Paths(ctx, dst, src, 6, PathReqFlags{})
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 4, sd.requests)
+	})
+	t.Run("least recently used reply is evicted", func(t *testing.T) {
+		sd, conn := newConn(newReply(time.Now().Add(time.Hour)), 2)
+		defer close(sd.topoChanges)
+		for _, max := range []uint16{1, 2, 1, 3, 1, 2} {
+			_, err := conn.Paths(ctx, dst, src, max, PathReqFlags{})
+			require.NoError(t, err)
+		}
+		// 2 is evicted by 3, and requested again.
+		assert.Equal(t, 4, sd.requests)
+	})
+	t.Run("revocation invalidates affected replies", func(t *testing.T) {
+		sd, conn := newConn(newReply(time.Now().Add(time.Hour)), 0)
+		defer close(sd.topoChanges)
+		_, err := conn.Paths(ctx, dst, src, 5, PathReqFlags{})
+		require.NoError(t, err)
+		newRev := func(ifID common.IFIDType) *path_mgmt.SignedRevInfo {
+			rawRev, err := (&path_mgmt.RevInfo{IfID: ifID, RawIsdas: dst.IAInt()}).Pack()
+			require.NoError(t, err)
+			return &path_mgmt.SignedRevInfo{Blob: rawRev, Sign: &proto.SignS{}}
+		}
+		_, err = conn.RevNotification(ctx, newRev(3))
+		require.NoError(t, err)
+		_, err = conn.Paths(ctx, dst, src, 5, PathReqFlags{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, sd.requests)
+		_, err = conn.RevNotification(ctx, newRev(2))
+		require.NoError(t, err)
+		_, err = conn.Paths(ctx, dst, src, 5, PathReqFlags{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, sd.requests)
+	})
+	t.Run("topology change flushes the cache", func(t *testing.T) {
+		sd, conn := newConn(newReply(time.Now().Add(time.Hour)), 0)
+		defer close(sd.topoChanges)
+		_, err := conn.Paths(ctx, dst, src, 5, PathReqFlags{})
+		require.NoError(t, err)
+		// The unbuffered send returns once the notification is received, the
+		// second one once the first is processed.
+		sd.topoChanges <- &TopoChangeNotification{}
+		sd.topoChanges <- &TopoChangeNotification{}
+		_, err = conn.Paths(ctx, dst, src, 5, PathReqFlags{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, sd.requests)
+	})
+}
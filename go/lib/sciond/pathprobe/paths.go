@@ -15,6 +15,7 @@
 package pathprobe
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -46,6 +47,9 @@ const (
 	StatusAlive StatusName = "Alive"
 	// StatusSCMP indicates that an unexpected SCMP packet came in the reply.
 	StatusSCMP StatusName = "SCMP"
+	// StatusInvalidReply indicates that the probed application sent a reply
+	// that did not pass validation.
+	StatusInvalidReply StatusName = "InvalidReply"
 )
 
 // Status indicates the state a path is in.
@@ -75,10 +79,27 @@ func PathKey(path sciond.PathReplyEntry) string {
 }
 
 // Prober can be used to get the status of a path.
+//
+// By default, probes are sent to an invalid address in the destination AS,
+// and a path is alive if the border router of the destination AS replies with
+// an SCMP bad host error. If DstHost is set, probes carrying Payload are sent
+// to the application at DstHost and DstPort instead, and a path is only alive
+// if the application replies via the path. This validates both the path and
+// the service behind it.
 type Prober struct {
 	DstIA    addr.IA
 	Local    snet.Addr
 	DispPath string
+	// DstHost is the host of the probed application. If it is nil, the
+	// application is not probed.
+	DstHost addr.HostAddr
+	// DstPort is the UDP port of the probed application.
+	DstPort uint16
+	// Payload is the payload of the application probes.
+	Payload []byte
+	// ValidateReply reports whether a reply of the application is valid. If
+	// it is nil, the reply must echo the payload.
+	ValidateReply func(reply []byte) bool
 }
 
 // GetStatuses probes the paths and returns the statuses of the paths. The
@@ -93,7 +114,8 @@ func (p Prober) GetStatuses(ctx context.Context,
 	// Check whether paths are alive. This is done by sending a packet
 	// with invalid address via the path. The border router at the destination
 	// is going to reply with SCMP error. Receiving the error means that
-	// the path is alive.
+	// the path is alive. For application probes, the reply of the
+	// application means that the path is alive.
 	pathStatuses := make(map[string]Status, len(paths))
 	scmpH := &scmpHandler{statuses: pathStatuses}
 	network := snet.NewCustomNetworkWithPR(p.Local.IA,
@@ -120,7 +142,7 @@ func (p Prober) GetStatuses(ctx context.Context,
 	}
 	var receiveErrors common.MultiError
 	for i := len(scmpH.statuses); i > 0; i-- {
-		if err := p.receive(snetConn); err != nil {
+		if err := p.receive(snetConn, scmpH); err != nil {
 			receiveErrors = append(receiveErrors, err)
 		}
 	}
@@ -139,28 +161,38 @@ func (p Prober) send(scionConn snet.Conn, path sciond.PathReplyEntry) error {
 	if err != nil {
 		return common.NewBasicError("unable to get overlay info", err)
 	}
+	dst := &addr.AppAddr{
+		L3: addr.HostSVCFromString("NONE"),
+		L4: addr.NewL4UDPInfo(0),
+	}
+	payload := []byte{}
+	if p.DstHost != nil {
+		dst = &addr.AppAddr{L3: p.DstHost, L4: addr.NewL4UDPInfo(p.DstPort)}
+		payload = p.Payload
+	}
 	addr := &snet.Addr{
-		IA: p.DstIA,
-		Host: &addr.AppAddr{
-			L3: addr.HostSVCFromString("NONE"),
-			L4: addr.NewL4UDPInfo(0),
-		},
+		IA:      p.DstIA,
+		Host:    dst,
 		NextHop: nextHop,
 		Path:    sPath,
 	}
 	log.Debug("Sending test packet.", "path", path.Path.String())
-	_, err = scionConn.WriteTo([]byte{}, addr)
+	_, err = scionConn.WriteTo(payload, addr)
 	if err != nil {
 		return common.NewBasicError("cannot send packet", err)
 	}
 	return nil
 }
 
-func (p Prober) receive(scionConn snet.Conn) error {
+func (p Prober) receive(scionConn snet.Conn, scmpH *scmpHandler) error {
 	b := make([]byte, 1500, 1500)
-	_, _, err := scionConn.ReadFromSCION(b)
+	n, remote, err := scionConn.ReadFromSCION(b)
 	if err == nil {
-		// We've got an actual reply instead of SCMP error. This should not happen.
+		if p.DstHost == nil {
+			// We've got an actual reply instead of SCMP error. This should not happen.
+			return nil
+		}
+		p.handleReply(b[:n], remote, scmpH)
 		return nil
 	}
 	if xerrors.Is(err, errBadHost) || xerrors.Is(err, errSCMP) {
@@ -173,6 +205,36 @@ func (p Prober) receive(scionConn snet.Conn) error {
 	return common.NewBasicError("failed to read packet", err)
 }
 
+// handleReply marks the path of a valid application reply as alive. Replies
+// that do not come from the probed application or via a probed path are
+// ignored.
+func (p Prober) handleReply(reply []byte, remote *snet.Addr, scmpH *scmpHandler) {
+	if remote == nil || remote.Path == nil || !remote.IA.Equal(p.DstIA) ||
+		remote.Host == nil || !remote.Host.L3.Equal(p.DstHost) ||
+		remote.Host.L4 == nil || remote.Host.L4.Port() != p.DstPort {
+
+		log.Debug("Ignoring unexpected reply", "remote", remote)
+		return
+	}
+	key := string(remote.Path.Raw)
+	if !scmpH.hasPath(key) {
+		log.Debug("Ignoring reply via unknown path", "remote", remote)
+		return
+	}
+	if !p.validReply(reply) {
+		scmpH.setStatus(key, Status{Status: StatusInvalidReply})
+		return
+	}
+	scmpH.setStatus(key, alive)
+}
+
+func (p Prober) validReply(reply []byte) bool {
+	if p.ValidateReply != nil {
+		return p.ValidateReply(reply)
+	}
+	return bytes.Equal(reply, p.Payload)
+}
+
 var errBadHost = errors.New("scmp: bad host")
 var errSCMP = errors.New("scmp: other")
 
@@ -206,6 +268,13 @@ func (h *scmpHandler) path(pkt *snet.SCIONPacket) (string, error) {
 	return string(path.Raw), nil
 }
 
+func (h *scmpHandler) hasPath(path string) bool {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	_, ok := h.statuses[path]
+	return ok
+}
+
 func (h *scmpHandler) setStatus(path string, status Status) {
 	h.mtx.Lock()
 	defer h.mtx.Unlock()
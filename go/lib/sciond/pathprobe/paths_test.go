@@ -0,0 +1,95 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathprobe
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestProberHandleReply(t *testing.T) {
+	dstIA := xtest.MustParseIA("1-ff00:0:110")
+	dstHost := addr.HostFromIP(net.IP{127, 0, 0, 1})
+	knownPath := common.RawBytes{1, 2, 3, 4, 5, 6, 7, 8}
+	remote := func(ia addr.IA, host addr.HostAddr, port uint16, raw common.RawBytes) *snet.Addr {
+		return &snet.Addr{
+			IA:   ia,
+			Host: &addr.AppAddr{L3: host, L4: addr.NewL4UDPInfo(port)},
+			Path: spath.New(raw),
+		}
+	}
+	tests := map[string]struct {
+		Validate func([]byte) bool
+		Reply    []byte
+		Remote   *snet.Addr
+		Expected Status
+	}{
+		"echo": {
+			Reply:    []byte("ping"),
+			Remote:   remote(dstIA, dstHost, 40000, knownPath),
+			Expected: alive,
+		},
+		"wrong echo": {
+			Reply:    []byte("pong"),
+			Remote:   remote(dstIA, dstHost, 40000, knownPath),
+			Expected: Status{Status: StatusInvalidReply},
+		},
+		"custom validation": {
+			Validate: func(reply []byte) bool { return string(reply) == "pong" },
+			Reply:    []byte("pong"),
+			Remote:   remote(dstIA, dstHost, 40000, knownPath),
+			Expected: alive,
+		},
+		"wrong port": {
+			Reply:    []byte("ping"),
+			Remote:   remote(dstIA, dstHost, 40001, knownPath),
+			Expected: timeout,
+		},
+		"wrong IA": {
+			Reply:    []byte("ping"),
+			Remote:   remote(xtest.MustParseIA("1-ff00:0:111"), dstHost, 40000, knownPath),
+			Expected: timeout,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := Prober{
+				DstIA:         dstIA,
+				DstHost:       dstHost,
+				DstPort:       40000,
+				Payload:       []byte("ping"),
+				ValidateReply: test.Validate,
+			}
+			scmpH := &scmpHandler{statuses: map[string]Status{string(knownPath): timeout}}
+			p.handleReply(test.Reply, test.Remote, scmpH)
+			assert.Equal(t, test.Expected, scmpH.statuses[string(knownPath)])
+		})
+	}
+	t.Run("unknown path", func(t *testing.T) {
+		p := Prober{DstIA: dstIA, DstHost: dstHost, DstPort: 40000, Payload: []byte("ping")}
+		scmpH := &scmpHandler{statuses: map[string]Status{string(knownPath): timeout}}
+		p.handleReply([]byte("ping"), remote(dstIA, dstHost, 40000, common.RawBytes{8}), scmpH)
+		assert.Len(t, scmpH.statuses, 1)
+		assert.Equal(t, timeout, scmpH.statuses[string(knownPath)])
+	})
+}
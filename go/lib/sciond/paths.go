@@ -0,0 +1,119 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sciond
+
+import (
+	"context"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/spath"
+)
+
+// Path is a path returned by SCIOND, parsed such that it can be used to send
+// packets right away.
+type Path struct {
+	// Path is the forwarding path with initialized offsets. It is nil if the
+	// destination is in the local AS.
+	Path *spath.Path
+	// NextHop is the overlay address of the first hop. It is nil if the
+	// destination is in the local AS and SCIOND did not return a next hop.
+	NextHop *overlay.OverlayAddr
+	// Expiry is the time at which the path expires.
+	Expiry time.Time
+	// MTU is the maximum transmission unit of the path.
+	MTU uint16
+	// Interfaces is the list of interfaces traversed by the path.
+	Interfaces []PathInterface
+	// Entry is the reply entry the path was parsed from.
+	Entry *PathReplyEntry
+}
+
+// NewPath parses a path reply entry.
+func NewPath(entry *PathReplyEntry) (*Path, error) {
+	if entry == nil || entry.Path == nil {
+		return nil, common.NewBasicError("Path reply entry without path", nil)
+	}
+	p := &Path{
+		Expiry:     entry.Path.Expiry(),
+		MTU:        entry.Path.Mtu,
+		Interfaces: entry.Path.Interfaces,
+		Entry:      entry,
+	}
+	if len(entry.Path.FwdPath) > 0 {
+		p.Path = spath.New(entry.Path.FwdPath)
+		if err := p.Path.InitOffsets(); err != nil {
+			return nil, common.NewBasicError("Unable to initialize path", err,
+				"path", entry.Path)
+		}
+	}
+	if p.Path != nil || entry.HostInfo.Host() != nil {
+		nextHop, err := entry.HostInfo.Overlay()
+		if err != nil {
+			return nil, common.NewBasicError("Unable to parse next hop", err,
+				"path", entry.Path)
+		}
+		p.NextHop = nextHop
+	}
+	return p, nil
+}
+
+// SrcIA returns the source ISD-AS of the path.
+func (p *Path) SrcIA() addr.IA {
+	return p.Entry.Path.SrcIA()
+}
+
+// DstIA returns the destination ISD-AS of the path.
+func (p *Path) DstIA() addr.IA {
+	return p.Entry.Path.DstIA()
+}
+
+func (p *Path) String() string {
+	return p.Entry.Path.String()
+}
+
+// GetPaths requests at most max paths from src to dst from SCIOND and parses
+// them. Unlike Connector.Paths, an error is returned if SCIOND does not
+// return any path. The errors carry a code (see serrors.CodeOf), such that
+// callers can decide whether to retry.
+func GetPaths(ctx context.Context, conn Connector, dst, src addr.IA, max uint16,
+	f PathReqFlags) ([]*Path, error) {
+
+	reply, err := conn.Paths(ctx, dst, src, max, f)
+	if err != nil {
+		return nil, common.NewBasicError("Unable to request paths", err,
+			"src", src, "dst", dst)
+	}
+	if reply.ErrorCode != ErrorOk {
+		return nil, serrors.WithCode(common.NewBasicError("Path request failed", nil,
+			"src", src, "dst", dst, "code", reply.ErrorCode), reply.ErrorCode.ErrorCode())
+	}
+	if len(reply.Entries) == 0 {
+		return nil, serrors.WithCode(common.NewBasicError("No paths available", nil,
+			"src", src, "dst", dst), serrors.CodeNotFound)
+	}
+	paths := make([]*Path, 0, len(reply.Entries))
+	for i := range reply.Entries {
+		p, err := NewPath(&reply.Entries[i])
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, p)
+	}
+	return paths, nil
+}
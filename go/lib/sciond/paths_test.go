@@ -0,0 +1,138 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sciond
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/hostinfo"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+// pathsConnector returns a fixed path reply.
+type pathsConnector struct {
+	Connector
+	reply *PathReply
+}
+
+func (c *pathsConnector) Paths(ctx context.Context, dst, src addr.IA, max uint16,
+	f PathReqFlags) (*PathReply, error) {
+
+	return c.reply, nil
+}
+
+func testFwdPath() common.RawBytes {
+	raw := make(common.RawBytes, spath.InfoFieldLength+spath.HopFieldLength)
+	(&spath.InfoField{ConsDir: true, Hops: 1}).Write(raw)
+	(&spath.HopField{ConsEgress: 1}).Write(raw[spath.InfoFieldLength:])
+	return raw
+}
+
+func testHostInfo() hostinfo.Host {
+	hostInfo := hostinfo.Host{Port: 30041}
+	hostInfo.Addrs.IPv4 = net.IP{127, 0, 0, 1}
+	return hostInfo
+}
+
+func TestNewPath(t *testing.T) {
+	hostInfo := testHostInfo()
+	t.Run("remote AS", func(t *testing.T) {
+		entry := &PathReplyEntry{
+			Path: &FwdPathMeta{
+				FwdPath: testFwdPath(),
+				Mtu:     1472,
+				ExpTime: 1000,
+			},
+			HostInfo: hostInfo,
+		}
+		p, err := NewPath(entry)
+		require.NoError(t, err)
+		require.NotNil(t, p.Path)
+		assert.Equal(t, spath.InfoFieldLength, p.Path.HopOff)
+		require.NotNil(t, p.NextHop)
+		assert.Equal(t, "127.0.0.1", p.NextHop.L3().String())
+		assert.Equal(t, uint16(1472), p.MTU)
+		assert.Equal(t, entry.Path.Expiry(), p.Expiry)
+	})
+	t.Run("local AS", func(t *testing.T) {
+		p, err := NewPath(&PathReplyEntry{Path: &FwdPathMeta{}})
+		require.NoError(t, err)
+		assert.Nil(t, p.Path)
+		assert.Nil(t, p.NextHop)
+	})
+	t.Run("invalid path", func(t *testing.T) {
+		_, err := NewPath(&PathReplyEntry{
+			Path:     &FwdPathMeta{FwdPath: common.RawBytes{1, 2, 3}},
+			HostInfo: hostInfo,
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestGetPaths(t *testing.T) {
+	src := xtest.MustParseIA("1-ff00:0:110")
+	dst := xtest.MustParseIA("1-ff00:0:111")
+	tests := map[string]struct {
+		Reply        *PathReply
+		ExpectedLen  int
+		ExpectedErr  bool
+		ExpectedCode serrors.Code
+	}{
+		"paths": {
+			Reply: &PathReply{Entries: []PathReplyEntry{
+				{Path: &FwdPathMeta{FwdPath: testFwdPath()}, HostInfo: testHostInfo()},
+				{Path: &FwdPathMeta{FwdPath: testFwdPath()}, HostInfo: testHostInfo()},
+			}},
+			ExpectedLen: 2,
+		},
+		"no paths": {
+			Reply:        &PathReply{ErrorCode: ErrorNoPaths},
+			ExpectedErr:  true,
+			ExpectedCode: serrors.CodeNotFound,
+		},
+		"PS timeout": {
+			Reply:        &PathReply{ErrorCode: ErrorPSTimeout},
+			ExpectedErr:  true,
+			ExpectedCode: serrors.CodeTimeout,
+		},
+		"no entries": {
+			Reply:        &PathReply{},
+			ExpectedErr:  true,
+			ExpectedCode: serrors.CodeNotFound,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			conn := &pathsConnector{reply: test.Reply}
+			paths, err := GetPaths(context.Background(), conn, dst, src, 5, PathReqFlags{})
+			if test.ExpectedErr {
+				assert.Error(t, err)
+				assert.Equal(t, test.ExpectedCode, serrors.CodeOf(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, paths, test.ExpectedLen)
+		})
+	}
+}
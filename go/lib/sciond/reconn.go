@@ -16,6 +16,7 @@ package sciond
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/scionproto/scion/go/lib/addr"
@@ -27,6 +28,10 @@ import (
 
 var _ Connector = (*reconnector)(nil)
 
+// topoWatchRetryInterval is the time the reconnector waits before
+// re-establishing the connection that receives topology change notifications.
+const topoWatchRetryInterval = time.Second
+
 // reconnector is a SCIOND API implementation that is resilient to SCIOND going
 // down and up.
 //
@@ -41,10 +46,17 @@ var _ Connector = (*reconnector)(nil)
 // support.
 type reconnector struct {
 	path string
+
+	// watchOnce starts the long-lived connection that receives topology
+	// change notifications on the first call to TopologyChanges.
+	watchOnce   sync.Once
+	topoChanges topoSubscribers
+	closeOnce   sync.Once
+	closeChan   chan struct{}
 }
 
 func newReconnector(path string, initialCheckTimeout time.Duration) (*reconnector, error) {
-	c := &reconnector{path: path}
+	c := &reconnector{path: path, closeChan: make(chan struct{})}
 	// Test during initialization that SCIOND is alive; this helps catch some
 	// unfixable issues (like bad socket name) while apps are still
 	// initializing their networking.
@@ -76,6 +88,17 @@ func (c *reconnector) Paths(ctx context.Context, dst, src addr.IA, max uint16,
 	return conn.Paths(ctx, dst, src, max, f)
 }
 
+func (c *reconnector) HiddenPaths(ctx context.Context, dst, src addr.IA, max uint16,
+	f PathReqFlags, groups []*path_mgmt.HPGroupId) (*PathReply, error) {
+
+	conn, err := c.ctxAwareConnect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close(ctx)
+	return conn.HiddenPaths(ctx, dst, src, max, f, groups)
+}
+
 func (c *reconnector) ASInfo(ctx context.Context, ia addr.IA) (*ASInfoReply, error) {
 	conn, err := c.ctxAwareConnect(ctx)
 	if err != nil {
@@ -125,7 +148,101 @@ func (c *reconnector) RevNotification(ctx context.Context,
 	return conn.RevNotification(ctx, sRevInfo)
 }
 
+func (c *reconnector) NextQueries(ctx context.Context, dst addr.IA) (*NextQueryReply, error) {
+	conn, err := c.ctxAwareConnect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close(ctx)
+	return conn.NextQueries(ctx, dst)
+}
+
+func (c *reconnector) DeleteNextQueries(ctx context.Context,
+	dst addr.IA) (*NextQueryReply, error) {
+
+	conn, err := c.ctxAwareConnect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close(ctx)
+	return conn.DeleteNextQueries(ctx, dst)
+}
+
+func (c *reconnector) PathFeedback(ctx context.Context, pathKey []byte, rtt time.Duration,
+	loss float64) (*PathFeedbackReply, error) {
+
+	conn, err := c.ctxAwareConnect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close(ctx)
+	return conn.PathFeedback(ctx, pathKey, rtt, loss)
+}
+
+// TopologyChanges returns a channel that receives a notification whenever
+// SCIOND loaded a new topology. Because the reconnector does not keep a
+// connection to SCIOND for API calls, the first call establishes a dedicated
+// connection for receiving notifications. If the connection cannot be
+// established or breaks, it is retried, and a notification with an unknown
+// timestamp is delivered once it succeeds, because the topology might have
+// changed in the meantime.
+func (c *reconnector) TopologyChanges() <-chan *TopoChangeNotification {
+	ch := c.topoChanges.subscribe()
+	c.watchOnce.Do(func() {
+		go func() {
+			defer log.LogPanicAndExit()
+			c.watchTopology()
+		}()
+	})
+	return ch
+}
+
+func (c *reconnector) watchTopology() {
+	defer c.topoChanges.close()
+	for first := true; ; first = false {
+		if conn, err := connectTimeout(c.path, topoWatchRetryInterval); err == nil {
+			if !first {
+				c.topoChanges.publish(&TopoChangeNotification{})
+			}
+			closed := c.forwardTopologyChanges(conn)
+			conn.Close(context.Background())
+			if closed {
+				return
+			}
+			log.Info("[sciond-API] Lost connection for topology notifications, reconnecting")
+		}
+		select {
+		case <-c.closeChan:
+			return
+		case <-time.After(topoWatchRetryInterval):
+		}
+	}
+}
+
+// forwardTopologyChanges forwards the notifications received on conn until
+// either the connection breaks or the reconnector is closed. It returns true
+// in the latter case.
+func (c *reconnector) forwardTopologyChanges(conn *connector) bool {
+	changes := conn.TopologyChanges()
+	for {
+		select {
+		case n, ok := <-changes:
+			if !ok {
+				return false
+			}
+			c.topoChanges.publish(n)
+		case <-c.closeChan:
+			return true
+		}
+	}
+}
+
 func (c *reconnector) Close(ctx context.Context) error {
+	c.closeOnce.Do(func() {
+		close(c.closeChan)
+		// Close the subscribers right away if the watcher never started.
+		c.watchOnce.Do(c.topoChanges.close)
+	})
 	return nil
 }
 
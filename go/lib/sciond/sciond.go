@@ -22,6 +22,10 @@
 //
 // Connector method calls return the entire answer of SCIOND.
 //
+// Applications that frequently request paths to the same destinations can wrap
+// their connector with NewPathCachingConnector, which answers repeated path
+// requests from an in-process cache.
+//
 // Fields prefixed with Raw (e.g., RawErrorCode) contain data in the format
 // received from SCIOND.  These are used internally, and the accessors without
 // the prefix (e.g., ErrorCode()) should be used instead.
@@ -36,12 +40,15 @@ import (
 	"time"
 
 	"github.com/patrickmn/go-cache"
+	"golang.org/x/xerrors"
 
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/infra"
 	"github.com/scionproto/scion/go/lib/infra/disp"
 	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/sock/reliable"
 	"github.com/scionproto/scion/go/proto"
 )
@@ -112,6 +119,11 @@ type Connector interface {
 	// Paths requests from SCIOND a set of end to end paths between src and
 	// dst. max specifies the maximum number of paths returned.
 	Paths(ctx context.Context, dst, src addr.IA, max uint16, f PathReqFlags) (*PathReply, error)
+	// HiddenPaths is like Paths, but the paths additionally include the
+	// hidden segments registered for the hidden path groups. SCIOND fetches
+	// the hidden segments from its configured hidden path servers.
+	HiddenPaths(ctx context.Context, dst, src addr.IA, max uint16, f PathReqFlags,
+		groups []*path_mgmt.HPGroupId) (*PathReply, error)
 	// ASInfo requests from SCIOND information about AS ia.
 	ASInfo(ctx context.Context, ia addr.IA) (*ASInfoReply, error)
 	// IFInfo requests from SCIOND addresses and ports of interfaces.  Slice
@@ -128,6 +140,28 @@ type Connector interface {
 	RevNotificationFromRaw(ctx context.Context, b []byte) (*RevReply, error)
 	// RevNotification sends a RevocationInfo message to SCIOND.
 	RevNotification(ctx context.Context, sRevInfo *path_mgmt.SignedRevInfo) (*RevReply, error)
+	// NextQueries requests from SCIOND the next query entries for segment
+	// requests towards dst. A zero dst returns the entries for all
+	// destinations.
+	NextQueries(ctx context.Context, dst addr.IA) (*NextQueryReply, error)
+	// DeleteNextQueries deletes the next query entries for segment requests
+	// towards dst in SCIOND, such that the segments are fetched again on the
+	// next path request. A zero dst deletes the entries for all destinations.
+	// The reply contains the deleted entries.
+	DeleteNextQueries(ctx context.Context, dst addr.IA) (*NextQueryReply, error)
+	// PathFeedback reports the round trip time and the loss rate (in the range
+	// [0, 1]) that were observed on the path with fingerprint pathKey to
+	// SCIOND. SCIOND uses the feedback to rank paths in subsequent replies.
+	// A zero rtt means the round trip time is unknown.
+	PathFeedback(ctx context.Context, pathKey []byte, rtt time.Duration,
+		loss float64) (*PathFeedbackReply, error)
+	// TopologyChanges returns a channel that receives a notification whenever
+	// SCIOND loaded a new topology. Cached interface, service and AS
+	// information is discarded before the notification is delivered, such
+	// that subsequent calls return fresh information. Each call returns a new
+	// channel. A notification is dropped if the previous one was not received
+	// yet. The channel is closed once the connector is closed.
+	TopologyChanges() <-chan *TopoChangeNotification
 	// Close shuts down the connection to a SCIOND server.
 	Close(ctx context.Context) error
 }
@@ -141,6 +175,8 @@ type connector struct {
 	asInfos  *cache.Cache
 	ifInfos  *cache.Cache
 	svcInfos *cache.Cache
+
+	topoChanges topoSubscribers
 }
 
 func connect(socketName string) (*connector, error) {
@@ -150,9 +186,9 @@ func connect(socketName string) (*connector, error) {
 func connectTimeout(socketName string, timeout time.Duration) (*connector, error) {
 	conn, err := reliable.DialTimeout(socketName, timeout)
 	if err != nil {
-		return nil, err
+		return nil, serrors.WithCode(err, requestErrorCode(err))
 	}
-	return &connector{
+	c := &connector{
 		dispatcher: disp.New(
 			conn,
 			&Adapter{},
@@ -161,7 +197,62 @@ func connectTimeout(socketName string, timeout time.Duration) (*connector, error
 		asInfos:  cache.New(ASInfoTTL, time.Minute),
 		ifInfos:  cache.New(IFInfoTTL, time.Minute),
 		svcInfos: cache.New(SVCInfoTTL, time.Minute),
-	}, nil
+	}
+	go func() {
+		defer log.LogPanicAndExit()
+		c.receiveNotifications()
+	}()
+	return c, nil
+}
+
+// receiveNotifications handles the messages SCIOND pushes without a preceding
+// request, until the connection is closed.
+func (c *connector) receiveNotifications() {
+	defer c.topoChanges.close()
+	for {
+		msg, _, err := c.dispatcher.RecvFrom(context.Background())
+		if err != nil {
+			return
+		}
+		pld, ok := msg.(*Pld)
+		if !ok || pld.Which != proto.SCIONDMsg_Which_topoChangeNotification {
+			log.Debug("[sciond-API] Ignoring unexpected message", "msg", msg)
+			continue
+		}
+		c.flushCaches()
+		c.topoChanges.publish(pld.TopoChangeNotification)
+	}
+}
+
+func (c *connector) flushCaches() {
+	c.asInfos.Flush()
+	c.ifInfos.Flush()
+	c.svcInfos.Flush()
+}
+
+// requestError wraps the error of a failed request to SCIOND. The returned
+// error is classified by its cause, such that callers can tell whether the
+// request can be retried. See requestErrorCode.
+func requestError(msg common.ErrMsg, err error) error {
+	return serrors.WithCode(common.NewBasicError(msg, err), requestErrorCode(err))
+}
+
+// requestErrorCode classifies the error of a failed request to SCIOND.
+// Cancellations by the caller are not retryable and carry no code, deadline
+// expirations are timeouts, and failures to pack or parse messages are
+// internal errors. All remaining errors stem from the connection to SCIOND,
+// and are classified as unavailable.
+func requestErrorCode(err error) serrors.Code {
+	switch {
+	case xerrors.Is(err, context.Canceled):
+		return serrors.CodeUnknown
+	case serrors.IsTimeout(err), xerrors.Is(err, context.DeadlineExceeded):
+		return serrors.CodeTimeout
+	case common.GetErrorMsg(err) == infra.StrAdapterError:
+		return serrors.CodeInternal
+	default:
+		return serrors.CodeUnavailable
+	}
 }
 
 // Self incrementing atomic counter for request IDs
@@ -172,24 +263,41 @@ func (c *connector) nextID() uint64 {
 func (c *connector) Paths(ctx context.Context, dst, src addr.IA, max uint16,
 	f PathReqFlags) (*PathReply, error) {
 
+	return c.paths(ctx, &PathReq{
+		Dst:      dst.IAInt(),
+		Src:      src.IAInt(),
+		MaxPaths: max,
+		Flags:    f,
+	})
+}
+
+func (c *connector) HiddenPaths(ctx context.Context, dst, src addr.IA, max uint16,
+	f PathReqFlags, groups []*path_mgmt.HPGroupId) (*PathReply, error) {
+
+	f.Hidden = true
+	return c.paths(ctx, &PathReq{
+		Dst:      dst.IAInt(),
+		Src:      src.IAInt(),
+		MaxPaths: max,
+		HPCfgs:   groups,
+		Flags:    f,
+	})
+}
+
+func (c *connector) paths(ctx context.Context, req *PathReq) (*PathReply, error) {
 	c.Lock()
 	defer c.Unlock()
 	reply, err := c.dispatcher.Request(
 		ctx,
 		&Pld{
-			Id:    c.nextID(),
-			Which: proto.SCIONDMsg_Which_pathReq,
-			PathReq: &PathReq{
-				Dst:      dst.IAInt(),
-				Src:      src.IAInt(),
-				MaxPaths: max,
-				Flags:    f,
-			},
+			Id:      c.nextID(),
+			Which:   proto.SCIONDMsg_Which_pathReq,
+			PathReq: req,
 		},
 		nil,
 	)
 	if err != nil {
-		return nil, common.NewBasicError("[sciond-API] Failed to get Paths", err)
+		return nil, requestError("[sciond-API] Failed to get Paths", err)
 	}
 	return reply.(*Pld).PathReply, nil
 }
@@ -215,7 +323,7 @@ func (c *connector) ASInfo(ctx context.Context, ia addr.IA) (*ASInfoReply, error
 		nil,
 	)
 	if err != nil {
-		return nil, common.NewBasicError("[sciond-API] Failed to get ASInfo", err)
+		return nil, requestError("[sciond-API] Failed to get ASInfo", err)
 	}
 	asInfoReply := pld.(*Pld).AsInfoReply
 	c.asInfos.SetDefault(key, asInfoReply)
@@ -243,7 +351,7 @@ func (c *connector) IFInfo(ctx context.Context, ifs []common.IFIDType) (*IFInfoR
 		nil,
 	)
 	if err != nil {
-		return nil, common.NewBasicError("[sciond-API] Failed to get IFInfo", err)
+		return nil, requestError("[sciond-API] Failed to get IFInfo", err)
 	}
 	ifInfoReply := pld.(*Pld).IfInfoReply
 	// Add new information to cache
@@ -294,7 +402,7 @@ func (c *connector) SVCInfo(ctx context.Context,
 		nil,
 	)
 	if err != nil {
-		return nil, common.NewBasicError("[sciond-API] Failed to get SVCInfo", err)
+		return nil, requestError("[sciond-API] Failed to get SVCInfo", err)
 	}
 	serviceInfoReply := pld.(*Pld).ServiceInfoReply
 	// Add new information to cache
@@ -349,11 +457,68 @@ func (c *connector) RevNotification(ctx context.Context,
 		nil,
 	)
 	if err != nil {
-		return nil, common.NewBasicError("[sciond-API] Failed to send RevNotification", err)
+		return nil, requestError("[sciond-API] Failed to send RevNotification", err)
 	}
 	return reply.(*Pld).RevReply, nil
 }
 
+func (c *connector) NextQueries(ctx context.Context, dst addr.IA) (*NextQueryReply, error) {
+	return c.nextQueries(ctx, dst, false)
+}
+
+func (c *connector) DeleteNextQueries(ctx context.Context,
+	dst addr.IA) (*NextQueryReply, error) {
+
+	return c.nextQueries(ctx, dst, true)
+}
+
+func (c *connector) nextQueries(ctx context.Context, dst addr.IA,
+	del bool) (*NextQueryReply, error) {
+
+	c.Lock()
+	defer c.Unlock()
+	reply, err := c.dispatcher.Request(
+		ctx,
+		&Pld{
+			Id:    c.nextID(),
+			Which: proto.SCIONDMsg_Which_nextQueryReq,
+			NextQueryReq: &NextQueryReq{
+				Dst:    dst.IAInt(),
+				Delete: del,
+			},
+		},
+		nil,
+	)
+	if err != nil {
+		return nil, requestError("[sciond-API] Failed to get next queries", err)
+	}
+	return reply.(*Pld).NextQueryReply, nil
+}
+
+func (c *connector) PathFeedback(ctx context.Context, pathKey []byte, rtt time.Duration,
+	loss float64) (*PathFeedbackReply, error) {
+
+	c.Lock()
+	defer c.Unlock()
+	reply, err := c.dispatcher.Request(
+		ctx,
+		&Pld{
+			Id:              c.nextID(),
+			Which:           proto.SCIONDMsg_Which_pathFeedbackReq,
+			PathFeedbackReq: NewPathFeedbackReq(pathKey, rtt, loss),
+		},
+		nil,
+	)
+	if err != nil {
+		return nil, requestError("[sciond-API] Failed to send path feedback", err)
+	}
+	return reply.(*Pld).PathFeedbackReply, nil
+}
+
+func (c *connector) TopologyChanges() <-chan *TopoChangeNotification {
+	return c.topoChanges.subscribe()
+}
+
 func (c *connector) Close(ctx context.Context) error {
 	return c.dispatcher.Close(ctx)
 }
@@ -0,0 +1,65 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sciond
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/infra"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+func TestRequestErrorCode(t *testing.T) {
+	tests := map[string]struct {
+		Err  error
+		Code serrors.Code
+	}{
+		"canceled": {
+			Err: common.NewBasicError(infra.StrInternalError, context.Canceled,
+				"op", "waitTable.WaitForReply"),
+			Code: serrors.CodeUnknown,
+		},
+		"deadline exceeded": {
+			Err: common.NewBasicError(infra.StrInternalError, context.DeadlineExceeded,
+				"op", "waitTable.WaitForReply"),
+			Code: serrors.CodeTimeout,
+		},
+		"adapter error": {
+			Err: common.NewBasicError(infra.StrAdapterError, serrors.New("bad msg"),
+				"op", "MsgToRaw"),
+			Code: serrors.CodeInternal,
+		},
+		"transport error": {
+			Err: common.NewBasicError(infra.StrTransportError, serrors.New("broken pipe"),
+				"op", "WriteTo"),
+			Code: serrors.CodeUnavailable,
+		},
+		"closed": {
+			Err:  common.NewBasicError(infra.StrClosedError, nil),
+			Code: serrors.CodeUnavailable,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.Code, requestErrorCode(test.Err))
+			assert.Equal(t, test.Code.Retryable(),
+				serrors.IsRetryable(requestError("request failed", test.Err)))
+		})
+	}
+}
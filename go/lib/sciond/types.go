@@ -17,6 +17,7 @@ package sciond
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -25,6 +26,7 @@ import (
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
 	"github.com/scionproto/scion/go/lib/hostinfo"
+	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/util"
 	"github.com/scionproto/scion/go/proto"
 )
@@ -59,21 +61,43 @@ func (c PathErrorCode) String() string {
 	}
 }
 
+// ErrorCode classifies c, such that callers can decide whether to retry the
+// path request.
+func (c PathErrorCode) ErrorCode() serrors.Code {
+	switch c {
+	case ErrorNoPaths:
+		return serrors.CodeNotFound
+	case ErrorPSTimeout:
+		return serrors.CodeTimeout
+	case ErrorInternal:
+		return serrors.CodeInternal
+	case ErrorBadSrcIA, ErrorBadDstIA:
+		return serrors.CodeInvalidArgument
+	default:
+		return serrors.CodeUnknown
+	}
+}
+
 var _ proto.Cerealizable = (*Pld)(nil)
 
 type Pld struct {
-	Id                 uint64
-	Which              proto.SCIONDMsg_Which
-	PathReq            *PathReq
-	PathReply          *PathReply
-	AsInfoReq          *ASInfoReq
-	AsInfoReply        *ASInfoReply
-	RevNotification    *RevNotification
-	RevReply           *RevReply
-	IfInfoRequest      *IFInfoRequest
-	IfInfoReply        *IFInfoReply
-	ServiceInfoRequest *ServiceInfoRequest
-	ServiceInfoReply   *ServiceInfoReply
+	Id                     uint64
+	Which                  proto.SCIONDMsg_Which
+	PathReq                *PathReq
+	PathReply              *PathReply
+	AsInfoReq              *ASInfoReq
+	AsInfoReply            *ASInfoReply
+	RevNotification        *RevNotification
+	RevReply               *RevReply
+	IfInfoRequest          *IFInfoRequest
+	IfInfoReply            *IFInfoReply
+	ServiceInfoRequest     *ServiceInfoRequest
+	ServiceInfoReply       *ServiceInfoReply
+	NextQueryReq           *NextQueryReq
+	NextQueryReply         *NextQueryReply
+	PathFeedbackReq        *PathFeedbackReq
+	PathFeedbackReply      *PathFeedbackReply
+	TopoChangeNotification *TopoChangeNotification
 }
 
 func NewPldFromRaw(b common.RawBytes) (*Pld, error) {
@@ -118,6 +142,16 @@ func (p *Pld) union() (interface{}, error) {
 		return p.ServiceInfoRequest, nil
 	case proto.SCIONDMsg_Which_serviceInfoReply:
 		return p.ServiceInfoReply, nil
+	case proto.SCIONDMsg_Which_nextQueryReq:
+		return p.NextQueryReq, nil
+	case proto.SCIONDMsg_Which_nextQueryReply:
+		return p.NextQueryReply, nil
+	case proto.SCIONDMsg_Which_pathFeedbackReq:
+		return p.PathFeedbackReq, nil
+	case proto.SCIONDMsg_Which_pathFeedbackReply:
+		return p.PathFeedbackReply, nil
+	case proto.SCIONDMsg_Which_topoChangeNotification:
+		return p.TopoChangeNotification, nil
 	}
 	return nil, common.NewBasicError("Unsupported SCIOND union type", nil, "type", p.Which)
 }
@@ -135,6 +169,7 @@ func (pathReq *PathReq) Copy() *PathReq {
 		Dst:      pathReq.Dst,
 		Src:      pathReq.Src,
 		MaxPaths: pathReq.MaxPaths,
+		HPCfgs:   append([]*path_mgmt.HPGroupId(nil), pathReq.HPCfgs...),
 		Flags:    pathReq.Flags,
 	}
 }
@@ -147,11 +182,144 @@ func (pathReq *PathReq) String() string {
 type PathReqFlags struct {
 	Refresh bool
 	Hidden  bool
+	// Ordering is the criterion by which the paths in the reply are ordered.
+	// If it is PathOrderingDefault, the ordering configured in SCIOND is used.
+	Ordering PathOrdering
+	// MaxCandidates limits the number of non-revoked paths, in the requested
+	// ordering, from which the reply is selected. Revocations are only checked
+	// until enough candidates are found. If it is 0, the limit configured in
+	// SCIOND is used.
+	MaxCandidates uint16
+	// Disjointness selects paths that overlap as little as possible with
+	// the paths before them in the reply, such that the first paths of the
+	// reply form a maximally disjoint subset. Within that constraint, the
+	// ordering is kept.
+	Disjointness PathDisjointness
+	// AllowStale allows SCIOND to answer from cached segments if the
+	// segments cannot be fetched, e.g., because the path server is
+	// unreachable. Such replies are marked as stale.
+	AllowStale bool
+}
+
+// PathOrdering is the criterion by which paths are ordered. Paths that are
+// equal with regard to the criterion are ordered by the remaining criteria
+// (in the order hops, expiry, MTU) and finally by their interfaces, such that
+// the ordering is deterministic.
+type PathOrdering uint8
+
+const (
+	// PathOrderingDefault selects the ordering configured in SCIOND.
+	PathOrderingDefault PathOrdering = iota
+	// PathOrderingHops orders paths by ascending number of AS hops.
+	PathOrderingHops
+	// PathOrderingExpiry orders paths by descending expiration time.
+	PathOrderingExpiry
+	// PathOrderingMTU orders paths by descending MTU.
+	PathOrderingMTU
+)
+
+// ParsePathOrdering parses the string representation of a path ordering.
+func ParsePathOrdering(s string) (PathOrdering, error) {
+	switch strings.ToLower(s) {
+	case "", "default":
+		return PathOrderingDefault, nil
+	case "hops":
+		return PathOrderingHops, nil
+	case "expiry":
+		return PathOrderingExpiry, nil
+	case "mtu":
+		return PathOrderingMTU, nil
+	}
+	return 0, common.NewBasicError("Unknown path ordering", nil, "ordering", s)
+}
+
+func (o PathOrdering) String() string {
+	switch o {
+	case PathOrderingDefault:
+		return "default"
+	case PathOrderingHops:
+		return "hops"
+	case PathOrderingExpiry:
+		return "expiry"
+	case PathOrderingMTU:
+		return "mtu"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(o))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (o PathOrdering) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (o *PathOrdering) UnmarshalText(text []byte) error {
+	ordering, err := ParsePathOrdering(string(text))
+	if err != nil {
+		return err
+	}
+	*o = ordering
+	return nil
+}
+
+// PathDisjointness is the criterion by which the disjointness of paths is
+// measured.
+type PathDisjointness uint8
+
+const (
+	// DisjointNone does not consider the disjointness of paths.
+	DisjointNone PathDisjointness = iota
+	// DisjointLink minimizes the number of inter-AS links that paths share.
+	DisjointLink
+	// DisjointAS minimizes the number of transit ASes that paths share. The
+	// source and destination ASes are shared by all paths and not counted.
+	DisjointAS
+)
+
+// ParsePathDisjointness parses the string representation of a path
+// disjointness criterion.
+func ParsePathDisjointness(s string) (PathDisjointness, error) {
+	switch strings.ToLower(s) {
+	case "", "none":
+		return DisjointNone, nil
+	case "link":
+		return DisjointLink, nil
+	case "as":
+		return DisjointAS, nil
+	}
+	return 0, common.NewBasicError("Unknown path disjointness", nil, "disjointness", s)
+}
+
+func (d PathDisjointness) String() string {
+	switch d {
+	case DisjointNone:
+		return "none"
+	case DisjointLink:
+		return "link"
+	case DisjointAS:
+		return "as"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(d))
+	}
 }
 
 type PathReply struct {
 	ErrorCode PathErrorCode
 	Entries   []PathReplyEntry
+	// Stale indicates that the paths are built from cached segments, because
+	// the segments could not be fetched. It is only set if the request
+	// allowed stale answers.
+	Stale bool
+	// RawStaleness is the number of seconds the refresh of the cached
+	// segments is overdue, see Staleness.
+	RawStaleness uint32 `capnp:"staleness"`
+}
+
+// Staleness returns how long the refresh of the segments used to build the
+// paths is overdue. It is only meaningful if the reply is stale.
+func (r *PathReply) Staleness() time.Duration {
+	return time.Duration(r.RawStaleness) * time.Second
 }
 
 func (r *PathReply) String() string {
@@ -159,7 +327,12 @@ func (r *PathReply) String() string {
 	for i := range r.Entries {
 		strEntries[i] = r.Entries[i].String()
 	}
-	return fmt.Sprintf("ErrorCode=%v\n  %v", r.ErrorCode, strings.Join(strEntries, "\n  "))
+	var stale string
+	if r.Stale {
+		stale = fmt.Sprintf(" Stale=%s", r.Staleness())
+	}
+	return fmt.Sprintf("ErrorCode=%v%s\n  %v", r.ErrorCode, stale,
+		strings.Join(strEntries, "\n  "))
 }
 
 type PathReplyEntry struct {
@@ -393,4 +566,194 @@ type ServiceInfoReplyEntry struct {
 	ServiceType proto.ServiceType
 	Ttl         uint32
 	HostInfos   []hostinfo.Host
+	// Instances describes every instance of the service, with the addresses
+	// and ports for all overlays. It is empty if SCIOND does not support
+	// it.
+	Instances []ServiceInstance
+}
+
+// ServiceInstance describes a single instance of a service.
+type ServiceInstance struct {
+	// ID is the name of the instance in the topology.
+	ID string `capnp:"id"`
+	// IPv4 is the IPv4 address and port of the instance, nil if the instance
+	// has no IPv4 address.
+	IPv4 *hostinfo.Host `capnp:"ipv4"`
+	// IPv6 is the IPv6 address and port of the instance, nil if the instance
+	// has no IPv6 address.
+	IPv6 *hostinfo.Host `capnp:"ipv6"`
+}
+
+func (i ServiceInstance) String() string {
+	return fmt.Sprintf("%s ipv4=%v ipv6=%v", i.ID, i.IPv4, i.IPv6)
+}
+
+// NextQueryReq requests the next query entries of SCIOND for segment
+// requests towards Dst. If Delete is set, the matching entries are removed,
+// i.e., SCIOND fetches the segments again on the next path request.
+type NextQueryReq struct {
+	Dst    addr.IAInt
+	Delete bool
+}
+
+func (r NextQueryReq) String() string {
+	return fmt.Sprintf("dst=%s, delete=%t", r.Dst, r.Delete)
+}
+
+type NextQueryReply struct {
+	Entries   []NextQueryReplyEntry
+	ErrorCode NextQueryErrorCode
+}
+
+type NextQueryErrorCode uint16
+
+const (
+	// NextQueryOk indicates that the entries were loaded, and deleted if
+	// requested, successfully.
+	NextQueryOk NextQueryErrorCode = iota
+	// NextQueryInternal indicates that SCIOND failed to access its path
+	// database. The reply contains no entries and, for delete requests, no
+	// entries were deleted.
+	NextQueryInternal
+)
+
+func (c NextQueryErrorCode) String() string {
+	switch c {
+	case NextQueryOk:
+		return "NextQueryOk"
+	case NextQueryInternal:
+		return "NextQueryInternal"
+	default:
+		return fmt.Sprintf("Unknown next query error (%d)", c)
+	}
+}
+
+// NextQueryReplyEntry describes the time at which SCIOND queries the segments
+// for the segment request from Src to Dst again.
+type NextQueryReplyEntry struct {
+	RawSrc    addr.IAInt `capnp:"src"`
+	RawDst    addr.IAInt `capnp:"dst"`
+	NextQuery uint32
+}
+
+func (e NextQueryReplyEntry) Src() addr.IA {
+	return e.RawSrc.IA()
+}
+
+func (e NextQueryReplyEntry) Dst() addr.IA {
+	return e.RawDst.IA()
+}
+
+func (e NextQueryReplyEntry) NextQueryTime() time.Time {
+	return util.SecsToTime(e.NextQuery)
+}
+
+func (e NextQueryReplyEntry) String() string {
+	return fmt.Sprintf("%s -> %s, nextQuery=%s", e.Src(), e.Dst(),
+		util.TimeToString(e.NextQueryTime()))
+}
+
+// LossPPM is the scale of PathFeedbackReq.Loss, i.e., a loss rate of 1
+// corresponds to LossPPM.
+const LossPPM = 1000000
+
+// PathFeedbackReq reports the performance an application observed on a path
+// to SCIOND. SCIOND uses the feedback of all local applications to rank the
+// paths in subsequent path replies.
+type PathFeedbackReq struct {
+	// PathKey is the fingerprint of the path, see snet.Path.Fingerprint.
+	PathKey common.RawBytes
+	// RawRTT is the observed round trip time in microseconds, 0 if unknown.
+	RawRTT uint32 `capnp:"rtt"`
+	// Loss is the observed packet loss rate in parts per million.
+	Loss uint32
+}
+
+// NewPathFeedbackReq creates a feedback request for the path identified by
+// key. The loss rate is clamped to the range [0, 1].
+func NewPathFeedbackReq(key []byte, rtt time.Duration, loss float64) *PathFeedbackReq {
+	if rtt < 0 {
+		rtt = 0
+	}
+	if rtt > math.MaxUint32*time.Microsecond {
+		rtt = math.MaxUint32 * time.Microsecond
+	}
+	loss = math.Max(0, math.Min(1, loss))
+	return &PathFeedbackReq{
+		PathKey: key,
+		RawRTT:  uint32(rtt / time.Microsecond),
+		Loss:    uint32(math.Round(loss * LossPPM)),
+	}
+}
+
+// RTT returns the observed round trip time. Zero means the RTT is unknown.
+func (r *PathFeedbackReq) RTT() time.Duration {
+	return time.Duration(r.RawRTT) * time.Microsecond
+}
+
+// LossRate returns the observed packet loss rate in the range [0, 1].
+func (r *PathFeedbackReq) LossRate() float64 {
+	return float64(r.Loss) / LossPPM
+}
+
+func (r *PathFeedbackReq) String() string {
+	return fmt.Sprintf("path=%s, rtt=%s, loss=%.4f", r.PathKey, r.RTT(), r.LossRate())
+}
+
+type PathFeedbackReply struct {
+	Result PathFeedbackResult
+}
+
+type PathFeedbackResult uint16
+
+const (
+	// PathFeedbackOk indicates that the feedback was recorded.
+	PathFeedbackOk PathFeedbackResult = iota
+	// PathFeedbackInvalid indicates that the feedback was malformed.
+	PathFeedbackInvalid
+	// PathFeedbackDisabled indicates that SCIOND does not rank paths based on
+	// feedback.
+	PathFeedbackDisabled
+)
+
+func (c PathFeedbackResult) String() string {
+	switch c {
+	case PathFeedbackOk:
+		return "PathFeedbackOk"
+	case PathFeedbackInvalid:
+		return "PathFeedbackInvalid"
+	case PathFeedbackDisabled:
+		return "PathFeedbackDisabled"
+	default:
+		return fmt.Sprintf("Unknown path feedback result (%d)", c)
+	}
+}
+
+// TopoChangeNotification is pushed by SCIOND to all connected clients
+// whenever it loaded a new topology, e.g., because border routers or
+// interfaces were added or removed. Clients should discard cached interface
+// and service information when receiving it.
+type TopoChangeNotification struct {
+	// RawTimestamp is the timestamp of the new topology in seconds since Unix
+	// epoch, 0 if unknown.
+	RawTimestamp uint32 `capnp:"timestamp"`
+}
+
+// NewTopoChangeNotification creates a notification for a topology with
+// timestamp ts.
+func NewTopoChangeNotification(ts time.Time) *TopoChangeNotification {
+	return &TopoChangeNotification{RawTimestamp: util.TimeToSecs(ts)}
+}
+
+// Timestamp returns the timestamp of the new topology. The zero time means
+// the timestamp is unknown.
+func (n *TopoChangeNotification) Timestamp() time.Time {
+	if n.RawTimestamp == 0 {
+		return time.Time{}
+	}
+	return util.SecsToTime(n.RawTimestamp)
+}
+
+func (n *TopoChangeNotification) String() string {
+	return fmt.Sprintf("timestamp=%s", util.TimeToString(n.Timestamp()))
 }
@@ -15,10 +15,16 @@
 package sciond
 
 import (
+	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/hostinfo"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/proto"
 )
 
 func TestNewPathInterface(t *testing.T) {
@@ -92,6 +98,250 @@ func TestNewPathInterface(t *testing.T) {
 		})
 	}
 }
+func TestNextQueryPldRoundTrip(t *testing.T) {
+	tests := map[string]*Pld{
+		"request": {
+			Id:    1,
+			Which: proto.SCIONDMsg_Which_nextQueryReq,
+			NextQueryReq: &NextQueryReq{
+				Dst:    xtest.MustParseIA("1-ff00:0:110").IAInt(),
+				Delete: true,
+			},
+		},
+		"reply": {
+			Id:    2,
+			Which: proto.SCIONDMsg_Which_nextQueryReply,
+			NextQueryReply: &NextQueryReply{
+				Entries: []NextQueryReplyEntry{
+					{
+						RawSrc:    xtest.MustParseIA("1-0").IAInt(),
+						RawDst:    xtest.MustParseIA("1-ff00:0:110").IAInt(),
+						NextQuery: 1570000000,
+					},
+				},
+			},
+		},
+		"error reply": {
+			Id:    3,
+			Which: proto.SCIONDMsg_Which_nextQueryReply,
+			NextQueryReply: &NextQueryReply{
+				ErrorCode: NextQueryInternal,
+			},
+		},
+	}
+	for name, pld := range tests {
+		t.Run(name, func(t *testing.T) {
+			raw, err := proto.PackRoot(pld)
+			require.NoError(t, err)
+			parsed, err := NewPldFromRaw(raw)
+			require.NoError(t, err)
+			assert.Equal(t, pld, parsed)
+		})
+	}
+}
+
+func TestServiceInfoPldRoundTrip(t *testing.T) {
+	pld := &Pld{
+		Id:    1,
+		Which: proto.SCIONDMsg_Which_serviceInfoReply,
+		ServiceInfoReply: &ServiceInfoReply{
+			Entries: []ServiceInfoReplyEntry{
+				{
+					ServiceType: proto.ServiceType_bs,
+					Ttl:         300,
+					HostInfos: []hostinfo.Host{
+						{Addrs: hostinfo.Addrs{IPv4: []byte{127, 0, 0, 1}}, Port: 30041},
+					},
+					Instances: []ServiceInstance{
+						{
+							ID: "bs1",
+							IPv4: &hostinfo.Host{
+								Addrs: hostinfo.Addrs{IPv4: []byte{127, 0, 0, 1}},
+								Port:  30041,
+							},
+							IPv6: &hostinfo.Host{
+								Addrs: hostinfo.Addrs{IPv6: net.ParseIP("::1")},
+								Port:  30042,
+							},
+						},
+						{
+							ID: "bs2",
+							IPv4: &hostinfo.Host{
+								Addrs: hostinfo.Addrs{IPv4: []byte{127, 0, 0, 2}},
+								Port:  30041,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	raw, err := proto.PackRoot(pld)
+	require.NoError(t, err)
+	parsed, err := NewPldFromRaw(raw)
+	require.NoError(t, err)
+	assert.Equal(t, pld, parsed)
+}
+
+func TestPathFeedbackPldRoundTrip(t *testing.T) {
+	tests := map[string]*Pld{
+		"request": {
+			Id:              1,
+			Which:           proto.SCIONDMsg_Which_pathFeedbackReq,
+			PathFeedbackReq: NewPathFeedbackReq([]byte("key"), 20*time.Millisecond, 0.01),
+		},
+		"reply": {
+			Id:                2,
+			Which:             proto.SCIONDMsg_Which_pathFeedbackReply,
+			PathFeedbackReply: &PathFeedbackReply{Result: PathFeedbackInvalid},
+		},
+	}
+	for name, pld := range tests {
+		t.Run(name, func(t *testing.T) {
+			raw, err := proto.PackRoot(pld)
+			require.NoError(t, err)
+			parsed, err := NewPldFromRaw(raw)
+			require.NoError(t, err)
+			assert.Equal(t, pld, parsed)
+		})
+	}
+}
+
+func TestNewPathFeedbackReq(t *testing.T) {
+	tests := map[string]struct {
+		RTT          time.Duration
+		Loss         float64
+		ExpectedRTT  time.Duration
+		ExpectedLoss uint32
+	}{
+		"regular": {
+			RTT:          1500 * time.Microsecond,
+			Loss:         0.25,
+			ExpectedRTT:  1500 * time.Microsecond,
+			ExpectedLoss: 250000,
+		},
+		"sub microsecond rtt is truncated": {
+			RTT:         1500 * time.Nanosecond,
+			ExpectedRTT: time.Microsecond,
+		},
+		"negative values are clamped": {
+			RTT:  -time.Second,
+			Loss: -1,
+		},
+		"loss above one is clamped": {
+			RTT:          time.Second,
+			Loss:         2,
+			ExpectedRTT:  time.Second,
+			ExpectedLoss: LossPPM,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := NewPathFeedbackReq([]byte("key"), test.RTT, test.Loss)
+			assert.Equal(t, test.ExpectedRTT, req.RTT())
+			assert.Equal(t, test.ExpectedLoss, req.Loss)
+		})
+	}
+}
+
+func TestParsePathOrdering(t *testing.T) {
+	tests := map[string]struct {
+		In       string
+		Ordering PathOrdering
+		Err      assert.ErrorAssertionFunc
+	}{
+		"empty": {
+			In:       "",
+			Ordering: PathOrderingDefault,
+			Err:      assert.NoError,
+		},
+		"hops": {
+			In:       "hops",
+			Ordering: PathOrderingHops,
+			Err:      assert.NoError,
+		},
+		"expiry": {
+			In:       "expiry",
+			Ordering: PathOrderingExpiry,
+			Err:      assert.NoError,
+		},
+		"mtu upper case": {
+			In:       "MTU",
+			Ordering: PathOrderingMTU,
+			Err:      assert.NoError,
+		},
+		"unknown": {
+			In:  "latency",
+			Err: assert.Error,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ordering, err := ParsePathOrdering(test.In)
+			test.Err(t, err)
+			assert.Equal(t, test.Ordering, ordering)
+		})
+	}
+}
+
+func TestParsePathDisjointness(t *testing.T) {
+	tests := map[string]struct {
+		In           string
+		Disjointness PathDisjointness
+		Err          assert.ErrorAssertionFunc
+	}{
+		"empty": {
+			In:           "",
+			Disjointness: DisjointNone,
+			Err:          assert.NoError,
+		},
+		"link": {
+			In:           "link",
+			Disjointness: DisjointLink,
+			Err:          assert.NoError,
+		},
+		"as upper case": {
+			In:           "AS",
+			Disjointness: DisjointAS,
+			Err:          assert.NoError,
+		},
+		"unknown": {
+			In:  "node",
+			Err: assert.Error,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			disjointness, err := ParsePathDisjointness(test.In)
+			test.Err(t, err)
+			assert.Equal(t, test.Disjointness, disjointness)
+		})
+	}
+}
+
+func TestPathReqFlagsRoundTrip(t *testing.T) {
+	pld := &Pld{
+		Id:    1,
+		Which: proto.SCIONDMsg_Which_pathReq,
+		PathReq: &PathReq{
+			Dst:      xtest.MustParseIA("1-ff00:0:110").IAInt(),
+			Src:      xtest.MustParseIA("1-ff00:0:111").IAInt(),
+			MaxPaths: 5,
+			Flags: PathReqFlags{
+				Refresh:       true,
+				Ordering:      PathOrderingMTU,
+				MaxCandidates: 20,
+				Disjointness:  DisjointAS,
+			},
+		},
+	}
+	raw, err := proto.PackRoot(pld)
+	require.NoError(t, err)
+	parsed, err := NewPldFromRaw(raw)
+	require.NoError(t, err)
+	assert.Equal(t, pld.PathReq.Flags, parsed.PathReq.Flags)
+}
+
 func mustPathInterface(t *testing.T, str string) PathInterface {
 	t.Helper()
 	pi, err := NewPathInterface(str)
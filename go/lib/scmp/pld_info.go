@@ -0,0 +1,165 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scmp
+
+import (
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/l4"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+// The accessors below return the Info of the payload as the type defined for
+// the respective SCMP class/type. They return an error if the payload carries
+// a different Info type, e.g., because the SCMP header has a different
+// class/type.
+
+// Echo returns the Info of an echo request or reply.
+func (p *Payload) Echo() (*InfoEcho, error) {
+	info, ok := p.Info.(*InfoEcho)
+	if !ok {
+		return nil, infoTypeError("*scmp.InfoEcho", p.Info)
+	}
+	return info, nil
+}
+
+// TraceRoute returns the Info of a traceroute request or reply.
+func (p *Payload) TraceRoute() (*InfoTraceRoute, error) {
+	info, ok := p.Info.(*InfoTraceRoute)
+	if !ok {
+		return nil, infoTypeError("*scmp.InfoTraceRoute", p.Info)
+	}
+	return info, nil
+}
+
+// RecordPath returns the Info of a record path request or reply.
+func (p *Payload) RecordPath() (*InfoRecordPath, error) {
+	info, ok := p.Info.(*InfoRecordPath)
+	if !ok {
+		return nil, infoTypeError("*scmp.InfoRecordPath", p.Info)
+	}
+	return info, nil
+}
+
+// PktSize returns the Info of an oversize packet or bad packet length error.
+func (p *Payload) PktSize() (*InfoPktSize, error) {
+	info, ok := p.Info.(*InfoPktSize)
+	if !ok {
+		return nil, infoTypeError("*scmp.InfoPktSize", p.Info)
+	}
+	return info, nil
+}
+
+// PathProblem returns the path offsets of a path class error, i.e., the
+// location in the path at which the problem occurred. This includes
+// revocations.
+func (p *Payload) PathProblem() (*InfoPathOffsets, error) {
+	switch info := p.Info.(type) {
+	case *InfoPathOffsets:
+		return info, nil
+	case *InfoRevocation:
+		return info.InfoPathOffsets, nil
+	}
+	return nil, infoTypeError("*scmp.InfoPathOffsets", p.Info)
+}
+
+// Revocation returns the Info of a revoked interface error.
+func (p *Payload) Revocation() (*InfoRevocation, error) {
+	info, ok := p.Info.(*InfoRevocation)
+	if !ok {
+		return nil, infoTypeError("*scmp.InfoRevocation", p.Info)
+	}
+	return info, nil
+}
+
+// ExtProblem returns the index of the offending extension of an extension
+// class error.
+func (p *Payload) ExtProblem() (*InfoExtIdx, error) {
+	info, ok := p.Info.(*InfoExtIdx)
+	if !ok {
+		return nil, infoTypeError("*scmp.InfoExtIdx", p.Info)
+	}
+	return info, nil
+}
+
+// QuotedUDP parses the quoted L4 header of the offending packet as UDP header.
+func (p *Payload) QuotedUDP() (*l4.UDP, error) {
+	if p.Meta.L4Proto != common.L4UDP {
+		return nil, common.NewBasicError("Quoted L4 header is not UDP", nil,
+			"l4", p.Meta.L4Proto)
+	}
+	return l4.UDPFromRaw(p.L4Hdr)
+}
+
+// QuotedSCMP parses the quoted L4 header of the offending packet as SCMP
+// header. In addition to the SCMP header, the quote contains the meta and
+// info fields of the offending SCMP packet. The parsed info is returned as
+// well.
+func (p *Payload) QuotedSCMP() (*Hdr, Info, error) {
+	if p.Meta.L4Proto != common.L4SCMP {
+		return nil, nil, common.NewBasicError("Quoted L4 header is not SCMP", nil,
+			"l4", p.Meta.L4Proto)
+	}
+	hdr, err := HdrFromRaw(p.L4Hdr)
+	if err != nil {
+		return nil, nil, err
+	}
+	meta, err := MetaFromRaw(p.L4Hdr[hdr.L4Len():])
+	if err != nil {
+		return nil, nil, err
+	}
+	infoStart := hdr.L4Len() + MetaLen
+	infoEnd := infoStart + int(meta.InfoLen)*common.LineLen
+	if len(p.L4Hdr) < infoEnd {
+		return nil, nil, serrors.New("Incomplete quoted SCMP meta and info")
+	}
+	info, err := ParseInfo(p.L4Hdr[infoStart:infoEnd], ClassType{Class: hdr.Class, Type: hdr.Type})
+	if err != nil {
+		return nil, nil, err
+	}
+	return hdr, info, nil
+}
+
+// RevInfo parses the signed revocation contained in the Info.
+func (r *InfoRevocation) RevInfo() (*path_mgmt.RevInfo, error) {
+	sRevInfo, err := path_mgmt.NewSignedRevInfoFromRaw(r.RawSRev)
+	if err != nil {
+		return nil, common.NewBasicError("Unable to parse signed revocation", err)
+	}
+	revInfo, err := sRevInfo.RevInfo()
+	if err != nil {
+		return nil, common.NewBasicError("Unable to parse revocation", err)
+	}
+	return revInfo, nil
+}
+
+// GeneralID returns the ID of the Info of a general class request or reply.
+// It returns false if info does not carry an ID.
+func GeneralID(info Info) (uint64, bool) {
+	switch info := info.(type) {
+	case *InfoEcho:
+		return info.Id, true
+	case *InfoTraceRoute:
+		return info.Id, true
+	case *InfoRecordPath:
+		return info.Id, true
+	}
+	return 0, false
+}
+
+func infoTypeError(expected string, info Info) error {
+	return common.NewBasicError("Unexpected SCMP Info type", nil,
+		"expected", expected, "actual", common.TypeOf(info))
+}
@@ -47,10 +47,7 @@ func InitMac(key []byte) (hash.Hash, error) {
 }
 
 func HFMacFactory(key []byte) (func() hash.Hash, error) {
-	// Generate keys
-	// This uses 16B keys with 1000 hash iterations, which is the same as the
-	// defaults used by pycrypto.
-	hfGenKey := pbkdf2.Key(key, hfMacSalt, 1000, 16, sha256.New)
+	hfGenKey := HFMacKey(key)
 
 	// First check for MAC creation errors.
 	if _, err := InitMac(hfGenKey); err != nil {
@@ -62,3 +59,11 @@ func HFMacFactory(key []byte) (func() hash.Hash, error) {
 	}
 	return f, nil
 }
+
+// HFMacKey derives the key for the hop field MAC computation from the master
+// key.
+func HFMacKey(key []byte) []byte {
+	// This uses 16B keys with 1000 hash iterations, which is the same as the
+	// defaults used by pycrypto.
+	return pbkdf2.Key(key, hfMacSalt, 1000, 16, sha256.New)
+}
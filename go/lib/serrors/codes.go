@@ -0,0 +1,177 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serrors
+
+import (
+	"fmt"
+
+	"golang.org/x/xerrors"
+)
+
+// Code classifies errors, such that callers can programmatically decide how to
+// react to them, e.g., by retrying the operation, by refreshing the path in
+// use, or by giving up.
+type Code uint8
+
+const (
+	// CodeUnknown is the code of errors that are not classified.
+	CodeUnknown Code = iota
+	// CodeTimeout indicates that the operation did not complete in time. The
+	// operation can be retried.
+	CodeTimeout
+	// CodeUnavailable indicates that a service or resource is temporarily
+	// unavailable. The operation can be retried.
+	CodeUnavailable
+	// CodePathDown indicates that the path in use is no longer usable. The
+	// operation can be retried after refreshing the path.
+	CodePathDown
+	// CodeNotFound indicates that the requested object does not exist.
+	CodeNotFound
+	// CodeInvalidArgument indicates that the request was invalid. Retrying
+	// the same request fails again.
+	CodeInvalidArgument
+	// CodeInternal indicates an internal error.
+	CodeInternal
+)
+
+func (c Code) String() string {
+	switch c {
+	case CodeUnknown:
+		return "unknown"
+	case CodeTimeout:
+		return "timeout"
+	case CodeUnavailable:
+		return "unavailable"
+	case CodePathDown:
+		return "path_down"
+	case CodeNotFound:
+		return "not_found"
+	case CodeInvalidArgument:
+		return "invalid_argument"
+	case CodeInternal:
+		return "internal"
+	default:
+		return fmt.Sprintf("code(%d)", uint8(c))
+	}
+}
+
+// Retryable returns whether operations that failed with c can be retried.
+func (c Code) Retryable() bool {
+	switch c {
+	case CodeTimeout, CodeUnavailable, CodePathDown:
+		return true
+	default:
+		return false
+	}
+}
+
+// Coder is implemented by errors that carry a code.
+type Coder interface {
+	error
+	ErrorCode() Code
+}
+
+var _ Coder = (*codedError)(nil)
+var _ Wrapper = (*codedError)(nil)
+
+// codedError attaches a code to an error. It is transparent otherwise: it
+// formats like the error, and Is and As are forwarded to the error.
+type codedError struct {
+	err  error
+	code Code
+}
+
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codedError) ErrorCode() Code {
+	return e.code
+}
+
+func (e *codedError) Is(err error) bool {
+	return xerrors.Is(e.err, err)
+}
+
+func (e *codedError) As(as interface{}) bool {
+	return xerrors.As(e.err, as)
+}
+
+func (e *codedError) Unwrap() error {
+	return xerrors.Unwrap(e.err)
+}
+
+func (e *codedError) TopError() string {
+	if w, ok := e.err.(Wrapper); ok {
+		return w.TopError()
+	}
+	return e.err.Error()
+}
+
+// GetMsg returns the message of the error, such that a code can be attached to
+// errors that are checked with common.GetErrorMsg.
+func (e *codedError) GetMsg() string {
+	if m, ok := e.err.(interface{ GetMsg() string }); ok {
+		return m.GetMsg()
+	}
+	return ""
+}
+
+// Timeout implements the timeout interface checked by IsTimeout.
+func (e *codedError) Timeout() bool {
+	return e.code == CodeTimeout || IsTimeout(e.err)
+}
+
+// Temporary implements the temporary interface checked by IsTemporary.
+func (e *codedError) Temporary() bool {
+	return e.code.Retryable() || IsTemporary(e.err)
+}
+
+// WithCode returns an error that is the same as the given error but carries
+// code. The returned error implements Is and Is(err) returns true. If err is
+// nil, nil is returned.
+func WithCode(err error, code Code) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{err: err, code: code}
+}
+
+// CodeOf returns the code of err. It is the code of the first error in the
+// chain of err that carries a code other than CodeUnknown. If there is no such
+// error, CodeTimeout is returned for timeout errors, and CodeUnknown
+// otherwise.
+func CodeOf(err error) Code {
+	for e := err; e != nil; e = xerrors.Unwrap(e) {
+		var c Coder
+		if xerrors.As(e, &c) && c.ErrorCode() != CodeUnknown {
+			return c.ErrorCode()
+		}
+	}
+	if IsTimeout(err) {
+		return CodeTimeout
+	}
+	return CodeUnknown
+}
+
+// IsRetryable returns whether the operation that failed with err can be
+// retried. This is the case if the code of err is retryable (see CodeOf), or
+// if err has no code and is a timeout or temporary error.
+func IsRetryable(err error) bool {
+	if code := CodeOf(err); code != CodeUnknown {
+		return code.Retryable()
+	}
+	return IsTemporary(err)
+}
@@ -0,0 +1,120 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serrors_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+func TestWithCode(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		assert.NoError(t, serrors.WithCode(nil, serrors.CodeTimeout))
+	})
+	t.Run("Is", func(t *testing.T) {
+		base := serrors.New("base")
+		err := serrors.WithCode(serrors.WrapStr("wrapped", base), serrors.CodeUnavailable)
+		assert.True(t, xerrors.Is(err, base))
+		assert.True(t, xerrors.Is(err, err))
+	})
+	t.Run("As", func(t *testing.T) {
+		base := &testErrType{msg: "test err"}
+		err := serrors.WithCode(base, serrors.CodeInternal)
+		var errAs *testErrType
+		assert.True(t, xerrors.As(err, &errAs))
+		assert.Equal(t, base, errAs)
+	})
+	t.Run("GetErrorMsg", func(t *testing.T) {
+		err := serrors.WithCode(common.NewBasicError("msg", nil), serrors.CodeInternal)
+		assert.Equal(t, "msg", common.GetErrorMsg(err))
+	})
+	t.Run("Fmt", func(t *testing.T) {
+		base := serrors.WrapStr("wrapped", serrors.New("base"), "k", "v")
+		err := serrors.WithCode(base, serrors.CodeInternal)
+		assert.Equal(t, base.Error(), err.Error())
+		assert.Equal(t, "top\n    "+base.Error(), serrors.WrapStr("top", err).Error())
+	})
+}
+
+func TestCodeOf(t *testing.T) {
+	tests := map[string]struct {
+		Err       error
+		Code      serrors.Code
+		Retryable bool
+		Timeout   bool
+	}{
+		"plain": {
+			Err:  serrors.New("plain"),
+			Code: serrors.CodeUnknown,
+		},
+		"coded": {
+			Err:  serrors.WithCode(serrors.New("not found"), serrors.CodeNotFound),
+			Code: serrors.CodeNotFound,
+		},
+		"wrapped coded": {
+			Err: serrors.WrapStr("outer",
+				serrors.WithCode(serrors.New("unavailable"), serrors.CodeUnavailable)),
+			Code:      serrors.CodeUnavailable,
+			Retryable: true,
+		},
+		"outer code wins": {
+			Err: serrors.WithCode(
+				serrors.WithCode(serrors.New("inner"), serrors.CodeInternal),
+				serrors.CodePathDown),
+			Code:      serrors.CodePathDown,
+			Retryable: true,
+		},
+		"coded timeout": {
+			Err:       serrors.WithCode(serrors.New("to"), serrors.CodeTimeout),
+			Code:      serrors.CodeTimeout,
+			Retryable: true,
+			Timeout:   true,
+		},
+		"uncoded timeout": {
+			Err:       serrors.WrapStr("ctx", context.DeadlineExceeded),
+			Code:      serrors.CodeTimeout,
+			Retryable: true,
+			Timeout:   true,
+		},
+		"code keeps timeout": {
+			Err:       serrors.WithCode(context.DeadlineExceeded, serrors.CodeUnavailable),
+			Code:      serrors.CodeUnavailable,
+			Retryable: true,
+			Timeout:   true,
+		},
+		"uncoded temporary": {
+			Err:       &testToTempErr{msg: "temp", temporary: true},
+			Code:      serrors.CodeUnknown,
+			Retryable: true,
+		},
+		"invalid argument": {
+			Err:  serrors.WithCode(serrors.New("bad"), serrors.CodeInvalidArgument),
+			Code: serrors.CodeInvalidArgument,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.Code, serrors.CodeOf(test.Err))
+			assert.Equal(t, test.Retryable, serrors.IsRetryable(test.Err))
+			assert.Equal(t, test.Timeout, serrors.IsTimeout(test.Err))
+		})
+	}
+}
@@ -15,6 +15,7 @@
 package snet
 
 import (
+	"encoding"
 	"flag"
 	"fmt"
 	"net"
@@ -24,14 +25,18 @@ import (
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/spath"
 )
 
 var _ net.Addr = (*Addr)(nil)
 var _ flag.Value = (*Addr)(nil)
+var _ encoding.TextMarshaler = Addr{}
+var _ encoding.TextUnmarshaler = (*Addr)(nil)
 
 var addrRegexp = regexp.MustCompile(
-	`^(?P<ia>\d+-[\d:A-Fa-f]+),\[(?P<host>[^\]]+)\](?P<port>:\d+)?$`)
+	`^(?P<ia>\d+-[\d:A-Fa-f]+),\[(?P<host>[^\]]+)\](?P<port>:\d+)?` +
+		`(?: via \[(?P<nexthop>[^\]]+)\](?P<nexthopport>:\d+)?)?$`)
 
 type Addr struct {
 	IA      addr.IA
@@ -106,10 +111,57 @@ func (a *Addr) Copy() *Addr {
 	return newA
 }
 
-// UnmarshalText implements encoding.TextUnmarshaler
+// MarshalText implements encoding.TextMarshaler. The address is encoded in
+// the format accepted by AddrFromString, with SVC hosts encoded by name (e.g.,
+// 1-ff00:0:300,[CS_M]) and the next hop, if any, appended as
+// " via [ipaddr]:port". The path is not encoded. The zero address is encoded
+// as the empty string.
+func (a Addr) MarshalText() ([]byte, error) {
+	if a.IsZero() {
+		return []byte{}, nil
+	}
+	if a.Host == nil || a.Host.L3 == nil {
+		return nil, serrors.New("Unable to marshal address without host", "addr", a.Desc())
+	}
+	host, err := hostText(a.Host.L3)
+	if err != nil {
+		return nil, err
+	}
+	s := fmt.Sprintf("%s,[%s]", a.IA, host)
+	if a.Host.L4 != nil {
+		s += fmt.Sprintf(":%d", a.Host.L4.Port())
+	}
+	if a.NextHop != nil {
+		s += " via " + a.NextHop.String()
+	}
+	return []byte(s), nil
+}
+
+// hostText returns the textual representation of host that is accepted by
+// AddrFromString.
+func hostText(host addr.HostAddr) (string, error) {
+	switch h := host.(type) {
+	case addr.HostIPv4, addr.HostIPv6:
+		return h.String(), nil
+	case addr.HostSVC:
+		if addr.HostSVCFromString(h.BaseString()) == addr.SvcNone {
+			return "", serrors.New("Unable to marshal unknown SVC address", "svc", h)
+		}
+		if h.IsMulticast() {
+			return h.BaseString() + "_M", nil
+		}
+		return h.BaseString(), nil
+	default:
+		return "", serrors.New("Unable to marshal host address", "type", host.Type())
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts the format
+// produced by MarshalText. The empty string is decoded as the zero address.
 func (a *Addr) UnmarshalText(text []byte) error {
 	if len(text) == 0 {
 		*a = Addr{}
+		return nil
 	}
 	other, err := AddrFromString(string(text))
 	if err != nil {
@@ -124,7 +176,10 @@ func (a *Addr) IsZero() bool {
 }
 
 // AddrFromString converts an address string of format isd-as,[ipaddr]:port
-// (e.g., 1-ff00:0:300,[192.168.1.1]:80) to a SCION address.
+// (e.g., 1-ff00:0:300,[192.168.1.1]:80) to a SCION address. Instead of an IP
+// address, the host can be an SVC address (e.g., 1-ff00:0:300,[CS]). The
+// next hop can optionally be specified by appending " via [ipaddr]:port"
+// (e.g., 1-ff00:0:300,[192.168.1.1]:80 via [10.0.0.1]:30041).
 func AddrFromString(s string) (*Addr, error) {
 	parts, err := parseAddr(s)
 	if err != nil {
@@ -153,7 +208,30 @@ func AddrFromString(s string) (*Addr, error) {
 		// FIXME(sgmonroy) We should not assume UDP as the L4 protocol
 		l4 = addr.NewL4UDPInfo(uint16(p))
 	}
-	return &Addr{IA: ia, Host: &addr.AppAddr{L3: l3, L4: l4}}, nil
+	a := &Addr{IA: ia, Host: &addr.AppAddr{L3: l3, L4: l4}}
+	if parts["nexthop"] != "" {
+		if a.NextHop, err = parseNextHop(parts["nexthop"], parts["nexthopport"]); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+func parseNextHop(host, port string) (*overlay.OverlayAddr, error) {
+	l3 := addr.HostFromIPStr(host)
+	if l3 == nil {
+		return nil, common.NewBasicError("Invalid next hop IP address string", nil, "ip", host)
+	}
+	var l4 addr.L4Info
+	if port != "" {
+		p, err := strconv.ParseUint(port[1:], 10, 16)
+		if err != nil {
+			return nil, common.NewBasicError("Invalid next hop port string", err,
+				"port", port[1:])
+		}
+		l4 = addr.NewL4UDPInfo(uint16(p))
+	}
+	return overlay.NewOverlayAddr(l3, l4)
 }
 
 func parseAddr(s string) (map[string]string, error) {
@@ -177,6 +255,6 @@ func (a *Addr) Set(s string) error {
 	if err != nil {
 		return err
 	}
-	a.IA, a.Host = other.IA, other.Host
+	a.IA, a.Host, a.NextHop = other.IA, other.Host, other.NextHop
 	return nil
 }
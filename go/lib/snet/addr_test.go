@@ -15,11 +15,15 @@
 package snet
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
 	"net"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/scionproto/scion/go/lib/addr"
 )
@@ -118,3 +122,55 @@ func Test_AddrFromString(t *testing.T) {
 		}
 	})
 }
+
+func TestAddrText(t *testing.T) {
+	tests := map[string]struct {
+		Text        string
+		ExpectedErr bool
+	}{
+		"IPv4":             {Text: "1-ff00:0:300,[1.2.3.4]:80"},
+		"IPv6":             {Text: "1-ff00:0:300,[2001::1]:80"},
+		"no port":          {Text: "1-ff00:0:300,[1.2.3.4]"},
+		"SVC anycast":      {Text: "1-ff00:0:300,[CS]"},
+		"SVC multicast":    {Text: "1-ff00:0:300,[PS_M]"},
+		"next hop":         {Text: "1-ff00:0:300,[1.2.3.4]:80 via [10.0.0.1]:30041"},
+		"next hop no port": {Text: "1-ff00:0:300,[1.2.3.4]:80 via [10.0.0.1]"},
+		"empty":            {Text: ""},
+		"bad next hop":     {Text: "1-ff00:0:300,[1.2.3.4]:80 via [CS]", ExpectedErr: true},
+		"bad address":      {Text: "1-ff00:0:300,[foo]:80", ExpectedErr: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var a Addr
+			err := a.UnmarshalText([]byte(test.Text))
+			if test.ExpectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			text, err := a.MarshalText()
+			require.NoError(t, err)
+			assert.Equal(t, test.Text, string(text))
+		})
+	}
+	t.Run("JSON", func(t *testing.T) {
+		type cfg struct {
+			Remote Addr
+		}
+		var c cfg
+		raw := []byte(`{"Remote":"1-ff00:0:300,[CS] via [10.0.0.1]:30041"}`)
+		require.NoError(t, json.Unmarshal(raw, &c))
+		assert.Equal(t, addr.SvcCS, c.Remote.Host.L3)
+		assert.Equal(t, "[10.0.0.1]:30041", c.Remote.NextHop.String())
+		out, err := json.Marshal(c)
+		require.NoError(t, err)
+		assert.Equal(t, string(raw), string(out))
+	})
+	t.Run("flag", func(t *testing.T) {
+		var a Addr
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(&a, "remote", "")
+		require.NoError(t, fs.Parse([]string{"-remote", "1-ff00:0:300,[1.2.3.4]:80"}))
+		assert.Equal(t, uint16(80), a.Host.L4.Port())
+	})
+}
@@ -20,6 +20,11 @@ import (
 	"github.com/scionproto/scion/go/lib/addr"
 )
 
+// scionConnBase contains the immutable state of a connection. It is set up
+// before the connection is returned to the caller and never changed
+// afterwards, so it can be read concurrently without locking. Accessors
+// return copies so callers cannot mutate the state shared with in-flight
+// reads and writes.
 type scionConnBase struct {
 	// Local, remote and bind SCION addresses (IA, L3, L4)
 	laddr *Addr
@@ -37,27 +42,27 @@ type scionConnBase struct {
 }
 
 func (c *scionConnBase) BindAddr() net.Addr {
-	return c.baddr
+	return c.baddr.Copy()
 }
 
 func (c *scionConnBase) BindSnetAddr() *Addr {
-	return c.baddr
+	return c.baddr.Copy()
 }
 
 func (c *scionConnBase) LocalAddr() net.Addr {
-	return c.laddr
+	return c.laddr.Copy()
 }
 
 func (c *scionConnBase) LocalSnetAddr() *Addr {
-	return c.laddr
+	return c.laddr.Copy()
 }
 
 func (c *scionConnBase) RemoteAddr() net.Addr {
-	return c.raddr
+	return c.raddr.Copy()
 }
 
 func (c *scionConnBase) RemoteSnetAddr() *Addr {
-	return c.raddr
+	return c.raddr.Copy()
 }
 
 func (c *scionConnBase) SVC() addr.HostSVC {
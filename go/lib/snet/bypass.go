@@ -0,0 +1,192 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"net"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/l4"
+	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/scmp"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+// defaultBypassPortRange is the range of SCION/UDP ports that are allocated
+// if the application does not request a specific port. It matches the range
+// used by the dispatcher.
+var defaultBypassPortRange = PortRange{Min: 1024, Max: 65535}
+
+var _ PacketDispatcherService = (*BypassPacketDispatcherService)(nil)
+
+// BypassPacketDispatcherService constructs SCION sockets that send and receive
+// SCION packets directly over UDP/IP, without going through a local
+// dispatcher.
+//
+// Border routers deliver packets for end hosts to the overlay port of the
+// destination host, so a socket created by this service takes the place of
+// the dispatcher on its IP address. Only a single application per IP address
+// can use it, and the dispatcher must not run on the same address. This
+// suits containerized applications with their own IP address, or
+// performance-critical applications that want to avoid the extra hop through
+// the dispatcher.
+//
+// Because there is no dispatcher to demultiplex traffic, the sockets drop all
+// non-SCMP packets that are not addressed to the registered address, port and
+// SVC address. All SCMP packets are handed to the SCMP handler.
+type BypassPacketDispatcherService struct {
+	// OverlayPort is the UDP port the sockets listen on for packets from the
+	// border routers. If it is 0, overlay.EndhostPort is used.
+	OverlayPort int
+	// SCMPHandler is invoked for packets that contain an SCMP L4. If the
+	// handler is nil, errors are returned back to applications every time an
+	// SCMP message is received.
+	SCMPHandler SCMPHandler
+	// SocketOptions, if set, are applied to the overlay sockets.
+	SocketOptions *SocketOptions
+	// PortRange, if set, is the range from which a random port is allocated
+	// if the application does not request a specific port. By default, the
+	// range of the dispatcher, 1024-65535, is used.
+	PortRange *PortRange
+}
+
+// RegisterTimeout opens a UDP socket on the overlay port of the IP address in
+// bind, or in public if bind is nil. The timeout is ignored, as no dispatcher
+// is contacted. If the port in public is 0, a random port of the port range is
+// allocated.
+func (s *BypassPacketDispatcherService) RegisterTimeout(ia addr.IA, public *addr.AppAddr,
+	bind *overlay.OverlayAddr, svc addr.HostSVC,
+	timeout time.Duration) (PacketConn, uint16, error) {
+
+	if public == nil || public.L3 == nil || public.L3.IP() == nil {
+		return nil, 0, serrors.New("Public IP address required for dispatcher bypass",
+			"public", public)
+	}
+	ip := public.L3.IP()
+	if bind != nil {
+		ip = bind.L3().IP()
+	}
+	overlayPort := s.OverlayPort
+	if overlayPort == 0 {
+		overlayPort = overlay.EndhostPort
+	}
+	udpConn, err := net.ListenUDP("udp", &net.UDPAddr{IP: ip, Port: overlayPort})
+	if err != nil {
+		return nil, 0, common.NewBasicError("Unable to open overlay socket", err,
+			"ip", ip, "port", overlayPort)
+	}
+	if err := s.SocketOptions.Apply(udpConn); err != nil {
+		udpConn.Close()
+		return nil, 0, common.NewBasicError("Unable to set socket options", err)
+	}
+	var port uint16
+	if public.L4 != nil {
+		port = public.L4.Port()
+	}
+	if port == 0 {
+		portRange := defaultBypassPortRange
+		if s.PortRange != nil {
+			portRange = *s.PortRange
+		}
+		port = portRange.random()
+	}
+	local := &addr.AppAddr{L3: public.L3, L4: addr.NewL4UDPInfo(port)}
+	return newBypassPacketConn(udpConn, ia, local, svc, s.SCMPHandler), port, nil
+}
+
+// bypassPacketConn is a SCIONPacketConn that reads from and writes to a UDP
+// socket directly, and drops the packets that are not addressed to it.
+type bypassPacketConn struct {
+	*SCIONPacketConn
+	ia    addr.IA
+	local *addr.AppAddr
+	svc   addr.HostSVC
+}
+
+func newBypassPacketConn(udpConn *net.UDPConn, ia addr.IA, local *addr.AppAddr,
+	svc addr.HostSVC, scmpHandler SCMPHandler) *bypassPacketConn {
+
+	return &bypassPacketConn{
+		SCIONPacketConn: &SCIONPacketConn{
+			conn:        udpOverlayConn{UDPConn: udpConn},
+			scmpHandler: scmpHandler,
+		},
+		ia:    ia,
+		local: local,
+		svc:   svc,
+	}
+}
+
+func (c *bypassPacketConn) ReadFrom(pkt *SCIONPacket, ov *overlay.OverlayAddr) error {
+	for {
+		if err := c.SCIONPacketConn.ReadFrom(pkt, ov); err != nil {
+			return err
+		}
+		if c.accepts(pkt) {
+			return nil
+		}
+	}
+}
+
+// accepts returns whether pkt is addressed to the connection. SCMP packets are
+// always accepted.
+func (c *bypassPacketConn) accepts(pkt *SCIONPacket) bool {
+	if _, ok := pkt.L4Header.(*scmp.Hdr); ok {
+		return true
+	}
+	dst := pkt.Destination
+	if !c.ia.IsZero() && !dst.IA.Equal(c.ia) {
+		return false
+	}
+	if dst.Host == nil {
+		return false
+	}
+	if svc, ok := dst.Host.(addr.HostSVC); ok {
+		return c.svc != addr.SvcNone && svc.Base() == c.svc.Base()
+	}
+	if !dst.Host.Equal(c.local.L3) {
+		return false
+	}
+	if udp, ok := pkt.L4Header.(*l4.UDP); ok {
+		return udp.DstPort == c.local.L4.Port()
+	}
+	return true
+}
+
+// udpOverlayConn converts between the UDP addresses of a UDP socket and the
+// overlay addresses used by SCIONPacketConn.
+type udpOverlayConn struct {
+	*net.UDPConn
+}
+
+func (c udpOverlayConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	n, src, err := c.UDPConn.ReadFromUDP(b)
+	if err != nil {
+		return n, nil, err
+	}
+	ov, err := overlay.NewOverlayAddr(addr.HostFromIP(src.IP),
+		addr.NewL4UDPInfo(uint16(src.Port)))
+	return n, ov, err
+}
+
+func (c udpOverlayConn) WriteTo(b []byte, address net.Addr) (int, error) {
+	ov, ok := address.(*overlay.OverlayAddr)
+	if !ok || ov.ToUDPAddr() == nil {
+		return 0, common.NewBasicError("Invalid overlay address", nil, "addr", address)
+	}
+	return c.UDPConn.WriteTo(b, ov.ToUDPAddr())
+}
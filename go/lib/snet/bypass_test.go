@@ -0,0 +1,146 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/l4"
+	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/scmp"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestBypassPacketConnAccepts(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	local := &addr.AppAddr{
+		L3: addr.HostFromIP(net.IP{127, 0, 0, 1}),
+		L4: addr.NewL4UDPInfo(40000),
+	}
+	tests := map[string]struct {
+		SVC      addr.HostSVC
+		Dst      SCIONAddress
+		L4       l4.L4Header
+		Expected bool
+	}{
+		"matching address and port": {
+			SVC:      addr.SvcNone,
+			Dst:      SCIONAddress{IA: ia, Host: local.L3},
+			L4:       &l4.UDP{DstPort: 40000},
+			Expected: true,
+		},
+		"wrong port": {
+			SVC: addr.SvcNone,
+			Dst: SCIONAddress{IA: ia, Host: local.L3},
+			L4:  &l4.UDP{DstPort: 40001},
+		},
+		"wrong host": {
+			SVC: addr.SvcNone,
+			Dst: SCIONAddress{IA: ia, Host: addr.HostFromIP(net.IP{127, 0, 0, 2})},
+			L4:  &l4.UDP{DstPort: 40000},
+		},
+		"wrong IA": {
+			SVC: addr.SvcNone,
+			Dst: SCIONAddress{IA: xtest.MustParseIA("1-ff00:0:111"), Host: local.L3},
+			L4:  &l4.UDP{DstPort: 40000},
+		},
+		"SCMP": {
+			SVC:      addr.SvcNone,
+			Dst:      SCIONAddress{IA: ia, Host: local.L3},
+			L4:       &scmp.Hdr{},
+			Expected: true,
+		},
+		"SCMP to other host": {
+			SVC:      addr.SvcNone,
+			Dst:      SCIONAddress{IA: ia, Host: addr.HostFromIP(net.IP{127, 0, 0, 2})},
+			L4:       &scmp.Hdr{},
+			Expected: true,
+		},
+		"registered SVC": {
+			SVC:      addr.SvcPS,
+			Dst:      SCIONAddress{IA: ia, Host: addr.SvcPS.Multicast()},
+			L4:       &l4.UDP{DstPort: 0},
+			Expected: true,
+		},
+		"unregistered SVC": {
+			SVC: addr.SvcNone,
+			Dst: SCIONAddress{IA: ia, Host: addr.SvcPS},
+			L4:  &l4.UDP{DstPort: 40000},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newBypassPacketConn(nil, ia, local, test.SVC, nil)
+			pkt := &SCIONPacket{
+				SCIONPacketInfo: SCIONPacketInfo{
+					Destination: test.Dst,
+					L4Header:    test.L4,
+				},
+			}
+			assert.Equal(t, test.Expected, c.accepts(pkt))
+		})
+	}
+}
+
+func TestBypassPacketConnReadWrite(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	host := addr.HostFromIP(net.IP{127, 0, 0, 1})
+	newConn := func(port uint16) (*bypassPacketConn, *overlay.OverlayAddr) {
+		udpConn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IP{127, 0, 0, 1}})
+		require.NoError(t, err)
+		ov, err := overlay.NewOverlayAddr(host,
+			addr.NewL4UDPInfo(uint16(udpConn.LocalAddr().(*net.UDPAddr).Port)))
+		require.NoError(t, err)
+		local := &addr.AppAddr{L3: host, L4: addr.NewL4UDPInfo(port)}
+		return newBypassPacketConn(udpConn, ia, local, addr.SvcNone, nil), ov
+	}
+	sender, _ := newConn(40000)
+	defer sender.Close()
+	receiver, receiverOv := newConn(40001)
+	defer receiver.Close()
+
+	send := func(dstPort uint16, pld string) {
+		pkt := &SCIONPacket{
+			SCIONPacketInfo: SCIONPacketInfo{
+				Destination: SCIONAddress{IA: ia, Host: host},
+				Source:      SCIONAddress{IA: ia, Host: host},
+				L4Header: &l4.UDP{
+					SrcPort:  40000,
+					DstPort:  dstPort,
+					TotalLen: uint16(l4.UDPLen + len(pld)),
+				},
+				Payload: common.RawBytes(pld),
+			},
+		}
+		require.NoError(t, sender.WriteTo(pkt, receiverOv))
+	}
+	// The first packet is addressed to another port and must be dropped.
+	send(40002, "other")
+	send(40001, "hello")
+
+	require.NoError(t, receiver.SetReadDeadline(time.Now().Add(2*time.Second)))
+	var pkt SCIONPacket
+	var ov overlay.OverlayAddr
+	require.NoError(t, receiver.ReadFrom(&pkt, &ov))
+	assert.Equal(t, common.RawBytes("hello"), pkt.Payload)
+	assert.Equal(t, uint16(40000), pkt.L4Header.(*l4.UDP).SrcPort)
+}
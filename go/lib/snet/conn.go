@@ -15,13 +15,19 @@
 package snet
 
 import (
+	"fmt"
 	"net"
+	"sync"
 	"time"
 
 	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/overlay"
 	"github.com/scionproto/scion/go/lib/pathmgr"
 	"github.com/scionproto/scion/go/lib/scmp"
 	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/spath"
 )
 
 const (
@@ -35,15 +41,86 @@ type Error interface {
 }
 
 var _ Error = (*OpError)(nil)
+var _ serrors.Coder = (*OpError)(nil)
+
+// Action is the reaction an application is advised to take after receiving
+// an OpError.
+type Action int
+
+const (
+	// ActionNone means no specific reaction is suggested.
+	ActionNone Action = iota
+	// ActionRefreshPath means the path in use is no longer usable. The
+	// application should request a fresh path before sending more traffic to
+	// the same destination.
+	ActionRefreshPath
+)
+
+func (a Action) String() string {
+	switch a {
+	case ActionNone:
+		return "none"
+	case ActionRefreshPath:
+		return "refresh_path"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(a))
+	}
+}
 
 type OpError struct {
 	scmp *scmp.Hdr
+	// revInfo is the revocation contained in the SCMP message, if any.
+	revInfo *path_mgmt.RevInfo
+	// path is the path of the packet that triggered the SCMP message, as
+	// quoted in the SCMP payload.
+	path *spath.Path
 }
 
 func (e *OpError) SCMP() *scmp.Hdr {
 	return e.scmp
 }
 
+// RevInfo returns the revocation carried by the SCMP message. The result is
+// nil if the error is not caused by a revocation, or if the revocation could
+// not be parsed.
+func (e *OpError) RevInfo() *path_mgmt.RevInfo {
+	return e.revInfo
+}
+
+// RevokedInterface returns the ISD-AS and interface ID of the revoked
+// interface. The last return value is false if no revocation information is
+// available.
+func (e *OpError) RevokedInterface() (addr.IA, common.IFIDType, bool) {
+	if e.revInfo == nil {
+		return addr.IA{}, 0, false
+	}
+	return e.revInfo.IA(), e.revInfo.IfID, true
+}
+
+// Path returns the forwarding path that was in use by the packet that
+// triggered the error. The offsets of the returned path are not initialized.
+// The result is nil if the SCMP message did not quote the path.
+func (e *OpError) Path() *spath.Path {
+	return e.path
+}
+
+// SuggestedAction returns the reaction an application is advised to take.
+func (e *OpError) SuggestedAction() Action {
+	if e.scmp != nil && e.scmp.Class == scmp.C_Path && e.scmp.Type == scmp.T_P_RevokedIF {
+		return ActionRefreshPath
+	}
+	return ActionNone
+}
+
+// ErrorCode classifies the error. Errors caused by revocations have code
+// serrors.CodePathDown.
+func (e *OpError) ErrorCode() serrors.Code {
+	if e.SuggestedAction() == ActionRefreshPath {
+		return serrors.CodePathDown
+	}
+	return serrors.CodeUnknown
+}
+
 func (e *OpError) Error() string {
 	return e.scmp.String()
 }
@@ -52,11 +129,19 @@ var _ net.Conn = (*SCIONConn)(nil)
 var _ net.PacketConn = (*SCIONConn)(nil)
 var _ Conn = (*SCIONConn)(nil)
 
+// SCIONConn is the default implementation of Conn. It is safe for concurrent
+// use by multiple goroutines.
 type SCIONConn struct {
 	conn PacketConn
 	scionConnBase
 	scionConnWriter
 	scionConnReader
+
+	lingerMtx sync.Mutex
+	// linger is the maximum time Close waits for pending SCMP errors.
+	linger time.Duration
+	// onSCMP is called with the SCMP errors received while lingering.
+	onSCMP func(Error)
 }
 
 func newSCIONConn(base *scionConnBase, pr pathmgr.Resolver, conn PacketConn) *SCIONConn {
@@ -116,6 +201,59 @@ func (c *SCIONConn) SetDeadline(t time.Time) error {
 	return nil
 }
 
+// SetLinger configures the behavior of Close. If linger is 0 (the default),
+// Close releases the connection immediately. Otherwise, Close waits for
+// in-flight writes to complete, and then keeps reading from the connection for
+// up to linger before releasing the dispatcher registration. SCMP errors
+// received during that time are passed to onSCMP (if not nil); data packets
+// are discarded. This allows short-lived applications to learn about errors
+// caused by their last packets.
+func (c *SCIONConn) SetLinger(linger time.Duration, onSCMP func(Error)) {
+	c.lingerMtx.Lock()
+	defer c.lingerMtx.Unlock()
+	c.linger = linger
+	c.onSCMP = onSCMP
+}
+
 func (c *SCIONConn) Close() error {
+	c.lingerMtx.Lock()
+	linger, onSCMP := c.linger, c.onSCMP
+	c.lingerMtx.Unlock()
+	if linger > 0 {
+		c.drain(time.Now().Add(linger), onSCMP)
+	}
 	return c.conn.Close()
 }
+
+// drain blocks new writes, and reads pending packets until the deadline
+// passes or the connection returns an error that is not caused by SCMP.
+func (c *SCIONConn) drain(deadline time.Time, onSCMP func(Error)) {
+	// Writes hold the writer lock for their whole duration, so acquiring it
+	// waits for in-flight writes.
+	c.scionConnWriter.mtx.Lock()
+	defer c.scionConnWriter.mtx.Unlock()
+	// Setting the deadline first unblocks concurrent readers at the latest
+	// when lingering ends.
+	if err := c.conn.SetReadDeadline(deadline); err != nil {
+		return
+	}
+	c.scionConnReader.mtx.Lock()
+	defer c.scionConnReader.mtx.Unlock()
+	for time.Now().Before(deadline) {
+		pkt := SCIONPacket{
+			Bytes: Bytes(c.scionConnReader.buffer),
+		}
+		var lastHop overlay.OverlayAddr
+		err := c.conn.ReadFrom(&pkt, &lastHop)
+		if err == nil {
+			continue
+		}
+		scmpErr, ok := err.(Error)
+		if !ok {
+			return
+		}
+		if onSCMP != nil {
+			onSCMP(scmpErr)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright 2019 ETH Zurich, Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/l4"
+	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/scmp"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+// TestConcurrentReadWrite is meant to be run with the race detector enabled.
+func TestConcurrentReadWrite(t *testing.T) {
+	const goroutines, iterations = 4, 100
+
+	laddr := MustParseAddr("1-ff00:0:110,[127.0.0.1]:80")
+	raddr := MustParseAddr("1-ff00:0:110,[127.0.0.2]:80")
+	packetConn := &loopbackPacketConn{}
+	conn := newSCIONConn(&scionConnBase{
+		laddr:    laddr,
+		raddr:    raddr,
+		scionNet: &SCIONNetwork{localIA: laddr.IA},
+		net:      "udp4",
+	}, nil, packetConn)
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iterations; j++ {
+				_, err := conn.Write([]byte{1, 2, 3})
+				assert.NoError(t, err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			b := make([]byte, 10)
+			for j := 0; j < iterations; j++ {
+				n, remote, err := conn.ReadFromSCION(b)
+				assert.NoError(t, err)
+				assert.Equal(t, 3, n)
+				// Mutating the returned address must not affect the conn.
+				remote.Host.L3 = addr.HostFromIPStr("127.0.0.3")
+				conn.RemoteSnetAddr().Host.L4 = addr.NewL4UDPInfo(42)
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, goroutines*iterations, packetConn.writes)
+	assert.Equal(t, raddr, conn.RemoteSnetAddr())
+}
+
+func TestReadFromSCIONUDP6(t *testing.T) {
+	laddr := MustParseAddr("1-ff00:0:110,[2001:db8::1]:80")
+	conn := newSCIONConn(&scionConnBase{
+		laddr:    laddr,
+		scionNet: &SCIONNetwork{localIA: laddr.IA},
+		net:      "udp6",
+	}, nil, &loopbackPacketConn{src: addr.HostFromIPStr("2001:db8::2")})
+
+	b := make([]byte, 10)
+	n, remote, err := conn.ReadFromSCION(b)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, MustParseAddr("1-ff00:0:110,[2001:db8::2]:80").Host, remote.Host)
+	assert.Equal(t, addr.HostFromIPStr("2001:db8::2"), remote.NextHop.L3())
+}
+
+func TestCloseLinger(t *testing.T) {
+	newConn := func(packetConn PacketConn) *SCIONConn {
+		laddr := MustParseAddr("1-ff00:0:110,[127.0.0.1]:80")
+		return newSCIONConn(&scionConnBase{
+			laddr:    laddr,
+			scionNet: &SCIONNetwork{localIA: laddr.IA},
+			net:      "udp4",
+		}, nil, packetConn)
+	}
+	scmpErr := &OpError{scmp: &scmp.Hdr{Class: scmp.C_Path, Type: scmp.T_P_RevokedIF}}
+
+	t.Run("without linger close is immediate", func(t *testing.T) {
+		packetConn := &drainPacketConn{reads: []error{scmpErr}}
+		conn := newConn(packetConn)
+		require.NoError(t, conn.Close())
+		assert.True(t, packetConn.closed)
+		assert.Len(t, packetConn.reads, 1)
+	})
+	t.Run("linger delivers pending SCMP errors", func(t *testing.T) {
+		packetConn := &drainPacketConn{reads: []error{scmpErr, nil, scmpErr}}
+		conn := newConn(packetConn)
+		var received []Error
+		conn.SetLinger(time.Second, func(err Error) { received = append(received, err) })
+		start := time.Now()
+		require.NoError(t, conn.Close())
+		assert.True(t, packetConn.closed)
+		assert.Equal(t, []Error{scmpErr, scmpErr}, received)
+		assert.Empty(t, packetConn.reads)
+		// Draining stops at the first non-SCMP error, here the read timeout.
+		assert.True(t, time.Since(start) < time.Second)
+	})
+	t.Run("linger ends at deadline", func(t *testing.T) {
+		packetConn := &drainPacketConn{endless: true}
+		conn := newConn(packetConn)
+		conn.SetLinger(50*time.Millisecond, nil)
+		start := time.Now()
+		require.NoError(t, conn.Close())
+		assert.True(t, packetConn.closed)
+		assert.True(t, time.Since(start) >= 50*time.Millisecond)
+	})
+}
+
+func TestReadFilters(t *testing.T) {
+	laddr := MustParseAddr("1-ff00:0:110,[127.0.0.1]:80")
+	ia111, ia112 := xtest.MustParseIA("1-ff00:0:111"), xtest.MustParseIA("1-ff00:0:112")
+	packetConn := &sourcePacketConn{
+		srcs: []addr.IA{ia111, ia112, ia111, ia112},
+		plds: []common.RawBytes{{1}, {1}, {1, 2, 3}, {1, 2, 3}},
+	}
+	conn := newSCIONConn(&scionConnBase{
+		laddr:    laddr,
+		scionNet: &SCIONNetwork{localIA: laddr.IA},
+		net:      "udp4",
+	}, nil, packetConn)
+	conn.SetReadFilters(FilterSourceIAs(ia112), FilterMinPayloadLen(2))
+
+	b := make([]byte, 10)
+	n, remote, err := conn.ReadFromSCION(b)
+	require.NoError(t, err)
+	// Only the last packet passes both filters.
+	assert.Equal(t, 3, n)
+	assert.Equal(t, ia112, remote.IA)
+	assert.Empty(t, packetConn.srcs)
+
+	// Without filters, every packet is delivered.
+	packetConn.srcs = []addr.IA{ia111}
+	packetConn.plds = []common.RawBytes{{1}}
+	conn.SetReadFilters()
+	n, remote, err = conn.ReadFromSCION(b)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, ia111, remote.IA)
+}
+
+// sourcePacketConn returns UDP packets with the queued sources and payloads.
+type sourcePacketConn struct {
+	PacketConn
+	srcs []addr.IA
+	plds []common.RawBytes
+}
+
+func (c *sourcePacketConn) ReadFrom(pkt *SCIONPacket, ov *overlay.OverlayAddr) error {
+	if len(c.srcs) == 0 {
+		return serrors.New("no more packets")
+	}
+	pkt.Source = SCIONAddress{IA: c.srcs[0], Host: addr.HostFromIPStr("127.0.0.2")}
+	pkt.L4Header = &l4.UDP{SrcPort: 80}
+	pkt.Payload = c.plds[0]
+	c.srcs, c.plds = c.srcs[1:], c.plds[1:]
+	lastHop, err := overlay.NewOverlayAddr(addr.HostFromIPStr("127.0.0.2"),
+		addr.NewL4UDPInfo(overlay.EndhostPort))
+	if err != nil {
+		return err
+	}
+	*ov = *lastHop
+	return nil
+}
+
+// drainPacketConn returns the queued read results in order, and a timeout
+// error once the queue is empty. If endless is set, reads return data packets
+// forever.
+type drainPacketConn struct {
+	PacketConn
+	reads   []error
+	endless bool
+	closed  bool
+}
+
+func (c *drainPacketConn) ReadFrom(pkt *SCIONPacket, ov *overlay.OverlayAddr) error {
+	if c.endless {
+		time.Sleep(time.Millisecond)
+		return nil
+	}
+	if len(c.reads) == 0 {
+		return serrors.New("i/o timeout")
+	}
+	err := c.reads[0]
+	c.reads = c.reads[1:]
+	return err
+}
+
+func (c *drainPacketConn) SetReadDeadline(time.Time) error {
+	return nil
+}
+
+func (c *drainPacketConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+// loopbackPacketConn counts written packets and returns a fixed UDP packet on
+// every read.
+type loopbackPacketConn struct {
+	PacketConn
+	// src is the source host of the returned packets. If it is nil,
+	// 127.0.0.2 is used.
+	src    addr.HostAddr
+	mtx    sync.Mutex
+	writes int
+}
+
+func (c *loopbackPacketConn) WriteTo(pkt *SCIONPacket, ov *overlay.OverlayAddr) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.writes++
+	return nil
+}
+
+func (c *loopbackPacketConn) ReadFrom(pkt *SCIONPacket, ov *overlay.OverlayAddr) error {
+	src := c.src
+	if src == nil {
+		src = addr.HostFromIPStr("127.0.0.2")
+	}
+	lastHop, err := overlay.NewOverlayAddr(src, addr.NewL4UDPInfo(overlay.EndhostPort))
+	if err != nil {
+		return err
+	}
+	pkt.Source = SCIONAddress{
+		IA:   xtest.MustParseIA("1-ff00:0:110"),
+		Host: src,
+	}
+	pkt.L4Header = &l4.UDP{SrcPort: 80}
+	pkt.Payload = common.RawBytes{1, 2, 3}
+	*ov = *lastHop
+	return nil
+}
@@ -16,15 +16,20 @@ package snet
 
 import (
 	"context"
+	"net"
 	"time"
 
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/overlay"
 	"github.com/scionproto/scion/go/lib/pathmgr"
 	"github.com/scionproto/scion/go/lib/scmp"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet/internal/pathsource"
 	"github.com/scionproto/scion/go/lib/sock/reliable"
+	"github.com/scionproto/scion/go/lib/spath"
 )
 
 // PacketDispatcherService constructs SCION sockets where applications have
@@ -45,16 +50,38 @@ type DefaultPacketDispatcherService struct {
 	// handler is nil, errors are returned back to applications every time an
 	// SCMP message is received.
 	SCMPHandler SCMPHandler
+	// SocketOptions, if set, are applied to the sockets to the dispatcher. The
+	// connections returned by Dispatcher must give access to their file
+	// descriptor, which is not the case for reconnecting connections.
+	SocketOptions *SocketOptions
+	// PortRange, if set, restricts the ports of sockets that do not request a
+	// specific port to the range. A random port of the range is registered
+	// instead of letting the dispatcher choose a port. If the registration
+	// fails, e.g., because the port is in use, further ports of the range
+	// are tried.
+	PortRange *PortRange
 }
 
 func (s *DefaultPacketDispatcherService) RegisterTimeout(ia addr.IA, public *addr.AppAddr,
 	bind *overlay.OverlayAddr, svc addr.HostSVC,
 	timeout time.Duration) (PacketConn, uint16, error) {
 
-	rconn, port, err := s.Dispatcher.RegisterTimeout(ia, public, bind, svc, timeout)
+	var rconn net.PacketConn
+	port, err := registerInRange(s.PortRange, public, timeout,
+		func(public *addr.AppAddr, timeout time.Duration) (uint16, error) {
+			var port uint16
+			var err error
+			rconn, port, err = s.Dispatcher.RegisterTimeout(ia, public, bind, svc, timeout)
+			return port, err
+		},
+	)
 	if err != nil {
 		return nil, 0, err
 	}
+	if err := s.SocketOptions.Apply(rconn); err != nil {
+		rconn.Close()
+		return nil, 0, common.NewBasicError("Unable to set socket options", err)
+	}
 	return &SCIONPacketConn{conn: rconn, scmpHandler: s.SCMPHandler}, port, err
 }
 
@@ -67,13 +94,19 @@ type SCMPHandler interface {
 	// back to the caller. If the return value is nil, snet will reattempt to
 	// read a data packet from the underlying dispatcher connection.
 	//
-	// Handlers that wish to ignore SCMP can just return nil.
+	// Handlers that wish to ignore SCMP can just return nil. Handlers that
+	// wish to deliver the SCMP packet to the caller like a data packet return
+	// ErrDeliverSCMP.
 	//
 	// If the handler mutates the packet, the changes are seen by snet
 	// connection method callers.
 	Handle(pkt *SCIONPacket) error
 }
 
+// ErrDeliverSCMP is returned by SCMP handlers to have the SCMP packet
+// delivered to the caller as a data packet.
+var ErrDeliverSCMP = serrors.New("deliver scmp packet as data")
+
 // NewSCMPHandler creates a default SCMP handler that forwards revocations to
 // the path resolver. SCMP packets are also forwarded to snet callers via
 // errors returned by Read calls.
@@ -86,13 +119,40 @@ func NewSCMPHandler(pr pathmgr.Resolver) SCMPHandler {
 	}
 }
 
+// NewRevocationDeliveringSCMPHandler creates an SCMP handler that disables the
+// automatic processing of revocations. Revocations are neither forwarded to a
+// path resolver nor returned as errors. Instead, the SCMP packets are
+// delivered to snet callers like data packets, such that applications can
+// keep sending on revoked paths. On Conns, the payload that is read is the
+// SCMP payload, which can be parsed with scmp.PldFromRaw, and the L4 of the
+// remote address is SCMP.
+func NewRevocationDeliveringSCMPHandler() SCMPHandler {
+	return &scmpHandler{
+		deliverRevocations: true,
+	}
+}
+
 // scmpHandler handles SCMP messages received from the network.
-// If a resolver is configured, it is informed of any received revocations. All
-// revocations are passed back to the caller embedded in the error, so
-// applications can handle them manually.
+// If a resolver is configured, it is informed of any received revocations,
+// unless the policy quarantines the revoked interface instead. All
+// revocations that are not ignored by the policy are passed back to the
+// caller embedded in the error, so applications can handle them manually.
+// Revocations that need to be verified are not returned to the caller, as
+// they are verified in the background.
 type scmpHandler struct {
 	// pathResolver manages revocations received via SCMP. If nil, nothing is informed.
 	pathResolver pathmgr.Resolver
+	// deliverRevocations, if set, delivers revocations as data packets
+	// instead of processing them.
+	deliverRevocations bool
+	// policy configures the processing of revocations.
+	policy RevocationPolicy
+	// quarantine keeps track of quarantined interfaces. It must be set if
+	// policy.Quarantine is positive.
+	quarantine *pathsource.Quarantine
+	// pending bounds the number of concurrent revocation verifications. It
+	// must be set if policy.Verifier is set.
+	pending chan struct{}
 }
 
 func (h *scmpHandler) Handle(pkt *SCIONPacket) error {
@@ -103,6 +163,10 @@ func (h *scmpHandler) Handle(pkt *SCIONPacket) error {
 
 	// Only handle revocations for now
 	if hdr.Class == scmp.C_Path && hdr.Type == scmp.T_P_RevokedIF {
+		if h.deliverRevocations {
+			log.Debug("Delivering scmp revocation as data", "hdr", hdr, "src", pkt.Source)
+			return ErrDeliverSCMP
+		}
 		return h.handleSCMPRev(hdr, pkt)
 	}
 	log.Debug("Ignoring scmp packet", "hdr", hdr, "src", pkt.Source)
@@ -115,15 +179,97 @@ func (h *scmpHandler) handleSCMPRev(hdr *scmp.Hdr, pkt *SCIONPacket) error {
 		return common.NewBasicError("Unable to type assert payload to SCMP payload", nil,
 			"type", common.TypeOf(pkt.Payload))
 	}
-	info, ok := scmpPayload.Info.(*scmp.InfoRevocation)
-	if !ok {
-		return common.NewBasicError("Unable to type assert SCMP Info to SCMP Revocation Info", nil,
-			"type", common.TypeOf(scmpPayload.Info))
+	info, err := scmpPayload.Revocation()
+	if err != nil {
+		return err
 	}
 	log.Info("Received SCMP revocation", "header", hdr.String(), "payload", scmpPayload.String(),
 		"src", pkt.Source)
-	if h.pathResolver != nil {
-		h.pathResolver.RevokeRaw(context.TODO(), info.RawSRev)
+	opErr := newRevocationOpError(hdr, scmpPayload, info)
+	event := RevocationEvent{Source: pkt.Source, RevInfo: opErr.revInfo,
+		Action: RevocationApplied}
+	if h.policy.Verifier != nil {
+		h.verifyAndApply(event, info.RawSRev)
+		return nil
+	}
+	h.apply(&event, info.RawSRev)
+	h.notify(event)
+	return opErr
+}
+
+// verifyAndApply verifies the revocation in the background, such that the
+// reading goroutine is not blocked while keys are fetched, and applies it if
+// the verification succeeds. If too many verifications are pending, the
+// revocation is ignored.
+func (h *scmpHandler) verifyAndApply(event RevocationEvent, rawSRev common.RawBytes) {
+	select {
+	case h.pending <- struct{}{}:
+	default:
+		log.Info("Ignoring SCMP revocation, too many pending verifications",
+			"src", event.Source)
+		event.Action, event.Err = RevocationIgnored, errRevVerificationBusy
+		h.notify(event)
+		return
+	}
+	// The packet buffer is reused by subsequent reads.
+	rawSRev = append(common.RawBytes(nil), rawSRev...)
+	go func() {
+		defer log.LogPanicAndExit()
+		defer func() { <-h.pending }()
+		if err := h.verify(rawSRev); err != nil {
+			log.Info("Ignoring unverifiable SCMP revocation", "src", event.Source, "err", err)
+			event.Action, event.Err = RevocationIgnored, err
+			h.notify(event)
+			return
+		}
+		h.apply(&event, rawSRev)
+		h.notify(event)
+	}()
+}
+
+// apply quarantines the revoked interface or forwards the revocation to the
+// path resolver, depending on the policy, and records the action in event.
+func (h *scmpHandler) apply(event *RevocationEvent, rawSRev common.RawBytes) {
+	if h.policy.Quarantine > 0 && event.RevInfo != nil {
+		h.quarantine.Add(event.RevInfo.IA(), event.RevInfo.IfID, h.policy.Quarantine)
+		event.Action = RevocationQuarantined
+	} else if h.pathResolver != nil {
+		h.pathResolver.RevokeRaw(context.TODO(), rawSRev)
+	}
+}
+
+// verify verifies the signature of the revocation.
+func (h *scmpHandler) verify(rawSRev common.RawBytes) error {
+	sRevInfo, err := path_mgmt.NewSignedRevInfoFromRaw(rawSRev)
+	if err != nil {
+		return err
+	}
+	ctx, cancelF := context.WithTimeout(context.Background(), revVerificationTimeout)
+	defer cancelF()
+	_, err = sRevInfo.VerifiedRevInfo(ctx, h.policy.Verifier)
+	return err
+}
+
+func (h *scmpHandler) notify(event RevocationEvent) {
+	if h.policy.OnRevocation != nil {
+		h.policy.OnRevocation(event)
+	}
+}
+
+// newRevocationOpError builds an OpError that carries the revoked interface
+// and the path quoted in the SCMP payload.
+func newRevocationOpError(hdr *scmp.Hdr, pld *scmp.Payload,
+	info *scmp.InfoRevocation) *OpError {
+
+	opErr := &OpError{scmp: hdr}
+	if len(pld.PathHdr) > 0 {
+		opErr.path = spath.New(append(common.RawBytes(nil), pld.PathHdr...))
+	}
+	revInfo, err := info.RevInfo()
+	if err != nil {
+		log.Debug("Unable to parse revocation", "err", err)
+		return opErr
 	}
-	return &OpError{scmp: hdr}
+	opErr.revInfo = revInfo
+	return opErr
 }
@@ -0,0 +1,207 @@
+// Copyright 2019 ETH Zurich, Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/scmp"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet/internal/pathsource"
+	"github.com/scionproto/scion/go/lib/spath/spathmeta"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/proto"
+)
+
+func TestSCMPHandlerRevocationOpError(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	revInfo := &path_mgmt.RevInfo{IfID: 42, RawIsdas: ia.IAInt(), RawTTL: 10}
+	rawRev, err := revInfo.Pack()
+	require.NoError(t, err)
+	rawSRev, err := (&path_mgmt.SignedRevInfo{Blob: rawRev, Sign: &proto.SignS{}}).Pack()
+	require.NoError(t, err)
+
+	hdr := scmp.NewHdr(scmp.ClassType{Class: scmp.C_Path, Type: scmp.T_P_RevokedIF}, 0)
+	pathHdr := make(common.RawBytes, 2*common.LineLen)
+	pkt := &SCIONPacket{
+		SCIONPacketInfo: SCIONPacketInfo{
+			L4Header: hdr,
+			Payload: &scmp.Payload{
+				Info:    scmp.NewInfoRevocation(1, 2, 42, false, rawSRev),
+				PathHdr: pathHdr,
+			},
+		},
+	}
+	err = NewSCMPHandler(nil).Handle(pkt)
+	opErr, ok := err.(*OpError)
+	require.True(t, ok, "expected *OpError, got %T", err)
+
+	revIA, ifID, ok := opErr.RevokedInterface()
+	assert.True(t, ok)
+	assert.Equal(t, ia, revIA)
+	assert.Equal(t, common.IFIDType(42), ifID)
+	assert.Equal(t, pathHdr, opErr.Path().Raw)
+	assert.Equal(t, ActionRefreshPath, opErr.SuggestedAction())
+	assert.Equal(t, serrors.CodePathDown, serrors.CodeOf(err))
+	assert.True(t, serrors.IsRetryable(err))
+}
+
+func TestRevocationDeliveringSCMPHandler(t *testing.T) {
+	pkt := &SCIONPacket{
+		SCIONPacketInfo: SCIONPacketInfo{
+			L4Header: scmp.NewHdr(scmp.ClassType{Class: scmp.C_Path, Type: scmp.T_P_RevokedIF}, 0),
+			Payload: &scmp.Payload{
+				Info: scmp.NewInfoRevocation(1, 2, 42, false, nil),
+			},
+		},
+	}
+	err := NewRevocationDeliveringSCMPHandler().Handle(pkt)
+	assert.True(t, xerrors.Is(err, ErrDeliverSCMP), "unexpected error: %v", err)
+
+	pkt.L4Header = scmp.NewHdr(scmp.ClassType{Class: scmp.C_General, Type: scmp.T_G_EchoReply}, 0)
+	assert.NoError(t, NewRevocationDeliveringSCMPHandler().Handle(pkt))
+}
+
+func TestSCMPHandlerRevocationPolicy(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	revInfo := &path_mgmt.RevInfo{IfID: 42, RawIsdas: ia.IAInt(), RawTTL: 10}
+	rawRev, err := revInfo.Pack()
+	require.NoError(t, err)
+	rawSRev, err := (&path_mgmt.SignedRevInfo{Blob: rawRev, Sign: &proto.SignS{}}).Pack()
+	require.NoError(t, err)
+	newPkt := func() *SCIONPacket {
+		return &SCIONPacket{
+			SCIONPacketInfo: SCIONPacketInfo{
+				L4Header: scmp.NewHdr(
+					scmp.ClassType{Class: scmp.C_Path, Type: scmp.T_P_RevokedIF}, 0),
+				Payload: &scmp.Payload{
+					Info: scmp.NewInfoRevocation(1, 2, 42, false, rawSRev),
+				},
+			},
+		}
+	}
+	errVerify := serrors.New("invalid signature")
+
+	tests := map[string]struct {
+		Policy         RevocationPolicy
+		Busy           bool
+		ExpectedErr    bool
+		ExpectedAction RevocationAction
+		Quarantined    bool
+	}{
+		"default policy applies revocation": {
+			ExpectedErr:    true,
+			ExpectedAction: RevocationApplied,
+		},
+		"unverifiable revocation is ignored": {
+			Policy:         RevocationPolicy{Verifier: revVerifier{err: errVerify}},
+			ExpectedAction: RevocationIgnored,
+		},
+		"verified revocation is applied": {
+			Policy:         RevocationPolicy{Verifier: revVerifier{}},
+			ExpectedAction: RevocationApplied,
+		},
+		"verified revocation is quarantined": {
+			Policy:         RevocationPolicy{Verifier: revVerifier{}, Quarantine: time.Minute},
+			ExpectedAction: RevocationQuarantined,
+			Quarantined:    true,
+		},
+		"revocation is ignored if too many verifications are pending": {
+			Policy:         RevocationPolicy{Verifier: revVerifier{}},
+			Busy:           true,
+			ExpectedAction: RevocationIgnored,
+		},
+		"revoked interface is quarantined": {
+			Policy:         RevocationPolicy{Quarantine: time.Minute},
+			ExpectedErr:    true,
+			ExpectedAction: RevocationQuarantined,
+			Quarantined:    true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			events := make(chan RevocationEvent, 1)
+			test.Policy.OnRevocation = func(e RevocationEvent) { events <- e }
+			quarantine := pathsource.NewQuarantine()
+			h := &scmpHandler{
+				policy:     test.Policy,
+				quarantine: quarantine,
+				pending:    make(chan struct{}, 1),
+			}
+			if test.Busy {
+				h.pending <- struct{}{}
+			}
+
+			err := h.Handle(newPkt())
+			if test.ExpectedErr {
+				_, ok := err.(*OpError)
+				assert.True(t, ok, "expected *OpError, got %v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+			var event RevocationEvent
+			select {
+			case event = <-events:
+			case <-time.After(time.Second):
+				t.Fatal("no revocation event")
+			}
+			assert.Equal(t, test.ExpectedAction, event.Action)
+			assert.Equal(t, revInfo.IfID, event.RevInfo.IfID)
+			if test.Busy {
+				assert.True(t, xerrors.Is(event.Err, errRevVerificationBusy))
+			} else if test.ExpectedAction == RevocationIgnored {
+				assert.True(t, xerrors.Is(event.Err, errVerify))
+			}
+
+			aps := make(spathmeta.AppPathSet)
+			aps.Add(&sciond.PathReplyEntry{Path: &sciond.FwdPathMeta{
+				Interfaces: []sciond.PathInterface{{RawIsdas: ia.IAInt(), IfID: 42}},
+			}})
+			aps.Add(&sciond.PathReplyEntry{Path: &sciond.FwdPathMeta{
+				Interfaces: []sciond.PathInterface{{RawIsdas: ia.IAInt(), IfID: 43}},
+			}})
+			assert.Equal(t, test.Quarantined, len(quarantine.Filter(aps)) == 1)
+		})
+	}
+}
+
+func TestOpErrorWithoutRevocation(t *testing.T) {
+	opErr := &OpError{
+		scmp: scmp.NewHdr(scmp.ClassType{Class: scmp.C_General, Type: scmp.T_G_EchoReply}, 0),
+	}
+	_, _, ok := opErr.RevokedInterface()
+	assert.False(t, ok)
+	assert.Nil(t, opErr.Path())
+	assert.Equal(t, ActionNone, opErr.SuggestedAction())
+	assert.Equal(t, serrors.CodeUnknown, serrors.CodeOf(opErr))
+}
+
+// revVerifier is a path_mgmt.Verifier that returns err for all revocations.
+type revVerifier struct {
+	err error
+}
+
+func (v revVerifier) Verify(context.Context, common.RawBytes, *proto.SignS) error {
+	return v.err
+}
@@ -0,0 +1,219 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"context"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+const (
+	// DefaultHappyEyeballsCandidates is the default number of paths that are
+	// probed by DialSCIONHappyEyeballs.
+	DefaultHappyEyeballsCandidates = 3
+	// DefaultHappyEyeballsDelay is the default delay between starting the
+	// probes on two consecutive paths.
+	DefaultHappyEyeballsDelay = 250 * time.Millisecond
+)
+
+// ErrNoResponsivePath is returned by DialSCIONHappyEyeballs if the probes
+// fail on all candidate paths.
+const ErrNoResponsivePath = "no responsive path"
+
+// ProbeFunc checks that the remote is responsive over conn, e.g., by
+// completing an application-level handshake. It must return as soon as ctx
+// is done.
+type ProbeFunc func(ctx context.Context, conn Conn) error
+
+// HappyEyeballsConfig configures DialSCIONHappyEyeballs.
+type HappyEyeballsConfig struct {
+	// Candidates is the maximum number of paths that are probed. If it is 0,
+	// DefaultHappyEyeballsCandidates is used. It must not be negative.
+	Candidates int
+	// Delay is the time between starting the probes on two consecutive
+	// paths. A probe is started earlier if all running probes failed. If it
+	// is 0, DefaultHappyEyeballsDelay is used. It must not be negative.
+	Delay time.Duration
+	// Probe checks that the remote is responsive over a path. It is required.
+	Probe ProbeFunc
+}
+
+func (cfg *HappyEyeballsConfig) validate() error {
+	if cfg.Probe == nil {
+		return serrors.New("Probe function required")
+	}
+	if cfg.Candidates < 0 {
+		return serrors.New("Number of candidates must not be negative",
+			"candidates", cfg.Candidates)
+	}
+	if cfg.Delay < 0 {
+		return serrors.New("Delay must not be negative", "delay", cfg.Delay)
+	}
+	return nil
+}
+
+func (cfg *HappyEyeballsConfig) initDefaults() {
+	if cfg.Candidates == 0 {
+		cfg.Candidates = DefaultHappyEyeballsCandidates
+	}
+	if cfg.Delay == 0 {
+		cfg.Delay = DefaultHappyEyeballsDelay
+	}
+}
+
+// DialSCIONHappyEyeballs returns a SCION connection to raddr that uses the
+// first responsive path. The top candidate paths to the remote AS are probed
+// in order of preference, where the probes are staggered by the configured
+// delay. The connection of the first successful probe is returned, all other
+// probes are canceled and their connections are closed. This reduces the
+// connection setup latency if the preferred path is dead.
+//
+// Each probe registers its own socket with the dispatcher, so laddr must not
+// specify a port if multiple paths are probed. If raddr already contains a
+// path, or the remote is in the local AS, only that path is probed. The
+// context bounds the whole connection establishment.
+func (n *SCIONNetwork) DialSCIONHappyEyeballs(ctx context.Context, network string,
+	laddr, raddr *Addr, cfg HappyEyeballsConfig) (Conn, error) {
+
+	if raddr == nil {
+		return nil, serrors.New("Unable to dial to nil remote")
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	cfg.initDefaults()
+	candidates, err := n.candidates(ctx, raddr, cfg.Candidates)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) > 1 && laddr != nil && laddr.Host != nil && laddr.Host.L4 != nil &&
+		laddr.Host.L4.Port() != 0 {
+		return nil, serrors.New("Local port must not be set when probing multiple paths",
+			"laddr", laddr)
+	}
+	dial := func(ctx context.Context, a *Addr) (Conn, error) {
+		var timeout time.Duration
+		if deadline, ok := ctx.Deadline(); ok {
+			if timeout = time.Until(deadline); timeout <= 0 {
+				return nil, ctx.Err()
+			}
+		}
+		conn, err := n.DialSCION(network, laddr, a, timeout)
+		if err != nil {
+			return nil, err
+		}
+		if err := cfg.Probe(ctx, conn); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return conn, nil
+	}
+	return raceDial(ctx, candidates, cfg.Delay, dial)
+}
+
+// DialSCIONHappyEyeballs calls DialSCIONHappyEyeballs on the default
+// networking context.
+func DialSCIONHappyEyeballs(ctx context.Context, network string, laddr, raddr *Addr,
+	cfg HappyEyeballsConfig) (Conn, error) {
+
+	if DefNetwork == nil {
+		return nil, serrors.New("SCION network not initialized")
+	}
+	return DefNetwork.DialSCIONHappyEyeballs(ctx, network, laddr, raddr, cfg)
+}
+
+// candidates returns up to max copies of raddr, each fixed to a different
+// path to the remote AS.
+func (n *SCIONNetwork) candidates(ctx context.Context, raddr *Addr, max int) ([]*Addr, error) {
+	if raddr.Path != nil || n.pathResolver == nil || n.localIA.Equal(raddr.IA) {
+		return []*Addr{raddr.Copy()}, nil
+	}
+	router := &BaseRouter{IA: n.localIA, PathResolver: n.pathResolver}
+	paths, err := router.AllRoutes(ctx, raddr.IA)
+	if err != nil {
+		return nil, common.NewBasicError(ErrPath, err)
+	}
+	if len(paths) > max {
+		paths = paths[:max]
+	}
+	candidates := make([]*Addr, 0, len(paths))
+	for _, p := range paths {
+		a := raddr.Copy()
+		a.Path = p.Path()
+		a.NextHop = p.OverlayNextHop()
+		candidates = append(candidates, a)
+	}
+	return candidates, nil
+}
+
+// raceDial dials the candidates, starting a new attempt after each delay or
+// as soon as all running attempts failed. It returns the connection of the
+// first successful attempt. The other attempts are canceled.
+func raceDial(ctx context.Context, candidates []*Addr, delay time.Duration,
+	dial func(context.Context, *Addr) (Conn, error)) (Conn, error) {
+
+	type result struct {
+		conn Conn
+		err  error
+	}
+	ctx, cancelF := context.WithCancel(ctx)
+	defer cancelF()
+	results := make(chan result, len(candidates))
+	next, pending := 0, 0
+	start := func() <-chan time.Time {
+		a := candidates[next]
+		go func() {
+			conn, err := dial(ctx, a)
+			results <- result{conn: conn, err: err}
+		}()
+		next++
+		pending++
+		if next == len(candidates) {
+			return nil
+		}
+		return time.After(delay)
+	}
+	stagger := start()
+	var lastErr error
+	for pending > 0 {
+		select {
+		case <-stagger:
+			stagger = start()
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				// Close the connections of attempts that succeed before
+				// noticing the cancellation.
+				go func(pending int) {
+					for ; pending > 0; pending-- {
+						if r := <-results; r.conn != nil {
+							r.conn.Close()
+						}
+					}
+				}(pending)
+				return r.conn, nil
+			}
+			lastErr = r.err
+			if pending == 0 && next < len(candidates) {
+				stagger = start()
+			}
+		}
+	}
+	return nil, common.NewBasicError(ErrNoResponsivePath, lastErr,
+		"candidates", len(candidates))
+}
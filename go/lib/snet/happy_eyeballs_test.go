@@ -0,0 +1,163 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+)
+
+// fakeConn is a Conn that only records whether it was closed.
+type fakeConn struct {
+	Conn
+	id     int
+	mtx    sync.Mutex
+	closed bool
+}
+
+func (c *fakeConn) Close() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.closed = true
+	return nil
+}
+
+func (c *fakeConn) isClosed() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.closed
+}
+
+func TestRaceDial(t *testing.T) {
+	candidates := make([]*Addr, 3)
+	for i := range candidates {
+		candidates[i] = &Addr{IA: addr.IA{I: addr.ISD(i)}}
+	}
+	// attempt describes the behavior of the dial attempt on a candidate.
+	type attempt struct {
+		Latency time.Duration
+		Fail    bool
+	}
+	tests := map[string]struct {
+		Attempts      []attempt
+		Delay         time.Duration
+		ExpectedWin   int
+		ExpectedError bool
+	}{
+		"first responsive": {
+			Attempts:    []attempt{{}, {}, {}},
+			Delay:       time.Second,
+			ExpectedWin: 0,
+		},
+		"first dead": {
+			Attempts: []attempt{
+				{Latency: time.Hour},
+				{Latency: 10 * time.Millisecond},
+				{Latency: time.Hour},
+			},
+			Delay:       10 * time.Millisecond,
+			ExpectedWin: 1,
+		},
+		"failure starts next attempt": {
+			Attempts:    []attempt{{Fail: true}, {Fail: true}, {}},
+			Delay:       time.Hour,
+			ExpectedWin: 2,
+		},
+		"all fail": {
+			Attempts:      []attempt{{Fail: true}, {Fail: true}, {Fail: true}},
+			Delay:         time.Hour,
+			ExpectedError: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var mtx sync.Mutex
+			var conns []*fakeConn
+			dial := func(ctx context.Context, a *Addr) (Conn, error) {
+				i := int(a.IA.I)
+				select {
+				case <-time.After(test.Attempts[i].Latency):
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+				if test.Attempts[i].Fail {
+					return nil, errors.New("probe failed")
+				}
+				c := &fakeConn{id: i}
+				mtx.Lock()
+				defer mtx.Unlock()
+				conns = append(conns, c)
+				return c, nil
+			}
+			ctx, cancelF := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancelF()
+			conn, err := raceDial(ctx, candidates, test.Delay, dial)
+			if test.ExpectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.ExpectedWin, conn.(*fakeConn).id)
+			assert.False(t, conn.(*fakeConn).isClosed())
+			// Give the remaining attempts time to observe the cancellation.
+			time.Sleep(50 * time.Millisecond)
+			mtx.Lock()
+			defer mtx.Unlock()
+			for _, c := range conns {
+				if c != conn {
+					assert.True(t, c.isClosed(), "loser %d not closed", c.id)
+				}
+			}
+		})
+	}
+}
+
+func TestHappyEyeballsConfigValidate(t *testing.T) {
+	probe := func(context.Context, Conn) error { return nil }
+	tests := map[string]struct {
+		Config    HappyEyeballsConfig
+		Assertion assert.ErrorAssertionFunc
+	}{
+		"defaults": {
+			Config:    HappyEyeballsConfig{Probe: probe},
+			Assertion: assert.NoError,
+		},
+		"no probe": {
+			Config:    HappyEyeballsConfig{},
+			Assertion: assert.Error,
+		},
+		"negative candidates": {
+			Config:    HappyEyeballsConfig{Candidates: -1, Probe: probe},
+			Assertion: assert.Error,
+		},
+		"negative delay": {
+			Config:    HappyEyeballsConfig{Delay: -time.Second, Probe: probe},
+			Assertion: assert.Error,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test.Assertion(t, test.Config.validate())
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+// ErrDropPacket can be returned by interceptors to discard a packet. A
+// dropped write is reported as successful to the caller, a dropped read is
+// skipped and the next packet is read instead.
+var ErrDropPacket = serrors.New("packet dropped by interceptor")
+
+// Interceptor hooks into the packets sent and received on a PacketConn. It
+// allows layering features such as logging, metrics, encryption, or fault
+// injection on top of any connection, see NewInterceptedPacketConn.
+//
+// Interceptors may modify the packet and the overlay address. If a hook
+// returns ErrDropPacket, the packet is discarded. If it returns any other
+// error, the packet is discarded and the error is returned to the caller.
+type Interceptor interface {
+	// OnSend is called before pkt is written to the next hop ov. The next hop
+	// is nil if the packet is sent without one.
+	OnSend(pkt *SCIONPacket, ov *overlay.OverlayAddr) error
+	// OnReceive is called after pkt was read from the last hop ov. SCMP
+	// packets consumed by the SCMP handler of the underlying connection are
+	// not passed to interceptors.
+	OnReceive(pkt *SCIONPacket, ov *overlay.OverlayAddr) error
+}
+
+var _ Interceptor = InterceptorFuncs{}
+
+// InterceptorFuncs is an Interceptor built from functions. Functions that are
+// nil let all packets pass unchanged.
+type InterceptorFuncs struct {
+	Send    func(pkt *SCIONPacket, ov *overlay.OverlayAddr) error
+	Receive func(pkt *SCIONPacket, ov *overlay.OverlayAddr) error
+}
+
+func (f InterceptorFuncs) OnSend(pkt *SCIONPacket, ov *overlay.OverlayAddr) error {
+	if f.Send == nil {
+		return nil
+	}
+	return f.Send(pkt, ov)
+}
+
+func (f InterceptorFuncs) OnReceive(pkt *SCIONPacket, ov *overlay.OverlayAddr) error {
+	if f.Receive == nil {
+		return nil
+	}
+	return f.Receive(pkt, ov)
+}
+
+// NewInterceptedPacketConn wraps conn such that every packet passes through
+// the interceptors. The interceptors form a chain: sent packets are passed to
+// the interceptors in the given order, received packets in the reverse order.
+// The first interceptor is thus the one closest to the application. If no
+// interceptors are given, conn is returned unchanged.
+func NewInterceptedPacketConn(conn PacketConn, interceptors ...Interceptor) PacketConn {
+	if len(interceptors) == 0 {
+		return conn
+	}
+	return &interceptedPacketConn{
+		PacketConn:   conn,
+		interceptors: append([]Interceptor(nil), interceptors...),
+	}
+}
+
+type interceptedPacketConn struct {
+	PacketConn
+	interceptors []Interceptor
+}
+
+func (c *interceptedPacketConn) WriteTo(pkt *SCIONPacket, ov *overlay.OverlayAddr) error {
+	for _, interceptor := range c.interceptors {
+		if err := interceptor.OnSend(pkt, ov); err != nil {
+			if xerrors.Is(err, ErrDropPacket) {
+				return nil
+			}
+			return err
+		}
+	}
+	return c.PacketConn.WriteTo(pkt, ov)
+}
+
+func (c *interceptedPacketConn) ReadFrom(pkt *SCIONPacket, ov *overlay.OverlayAddr) error {
+	for {
+		if err := c.PacketConn.ReadFrom(pkt, ov); err != nil {
+			return err
+		}
+		err := c.receive(pkt, ov)
+		if err == nil || !xerrors.Is(err, ErrDropPacket) {
+			return err
+		}
+	}
+}
+
+func (c *interceptedPacketConn) receive(pkt *SCIONPacket, ov *overlay.OverlayAddr) error {
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		if err := c.interceptors[i].OnReceive(pkt, ov); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ PacketDispatcherService = (*InterceptingPacketDispatcherService)(nil)
+
+// InterceptingPacketDispatcherService wraps the connections constructed by
+// another PacketDispatcherService with interceptors, see
+// NewInterceptedPacketConn.
+type InterceptingPacketDispatcherService struct {
+	// Dispatcher is the service used to register the sockets.
+	Dispatcher PacketDispatcherService
+	// Interceptors are applied to every registered socket.
+	Interceptors []Interceptor
+}
+
+func (s *InterceptingPacketDispatcherService) RegisterTimeout(ia addr.IA,
+	public *addr.AppAddr, bind *overlay.OverlayAddr, svc addr.HostSVC,
+	timeout time.Duration) (PacketConn, uint16, error) {
+
+	conn, port, err := s.Dispatcher.RegisterTimeout(ia, public, bind, svc, timeout)
+	if err != nil {
+		return nil, 0, err
+	}
+	return NewInterceptedPacketConn(conn, s.Interceptors...), port, nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+// queuePacketConn records written packets and returns queued packets on reads.
+type queuePacketConn struct {
+	PacketConn
+	written []common.RawBytes
+	queued  []common.RawBytes
+}
+
+func (c *queuePacketConn) WriteTo(pkt *SCIONPacket, ov *overlay.OverlayAddr) error {
+	c.written = append(c.written, pkt.Payload.(common.RawBytes))
+	return nil
+}
+
+func (c *queuePacketConn) ReadFrom(pkt *SCIONPacket, ov *overlay.OverlayAddr) error {
+	if len(c.queued) == 0 {
+		return serrors.New("queue empty")
+	}
+	pkt.Payload, c.queued = c.queued[0], c.queued[1:]
+	return nil
+}
+
+// appendInterceptor appends its tag to the payload of every packet.
+func appendInterceptor(tag byte) Interceptor {
+	appendTag := func(pkt *SCIONPacket, _ *overlay.OverlayAddr) error {
+		pkt.Payload = append(pkt.Payload.(common.RawBytes), tag)
+		return nil
+	}
+	return InterceptorFuncs{Send: appendTag, Receive: appendTag}
+}
+
+// dropInterceptor drops all packets with the given first payload byte.
+func dropInterceptor(b byte) Interceptor {
+	drop := func(pkt *SCIONPacket, _ *overlay.OverlayAddr) error {
+		if pkt.Payload.(common.RawBytes)[0] == b {
+			return ErrDropPacket
+		}
+		return nil
+	}
+	return InterceptorFuncs{Send: drop, Receive: drop}
+}
+
+func TestNewInterceptedPacketConn(t *testing.T) {
+	t.Run("without interceptors the conn is unchanged", func(t *testing.T) {
+		conn := &queuePacketConn{}
+		assert.Equal(t, PacketConn(conn), NewInterceptedPacketConn(conn))
+	})
+	t.Run("send runs interceptors in order", func(t *testing.T) {
+		conn := &queuePacketConn{}
+		c := NewInterceptedPacketConn(conn, appendInterceptor(1), appendInterceptor(2))
+		pkt := &SCIONPacket{SCIONPacketInfo: SCIONPacketInfo{Payload: common.RawBytes{0}}}
+		require.NoError(t, c.WriteTo(pkt, nil))
+		assert.Equal(t, []common.RawBytes{{0, 1, 2}}, conn.written)
+	})
+	t.Run("receive runs interceptors in reverse order", func(t *testing.T) {
+		conn := &queuePacketConn{queued: []common.RawBytes{{0}}}
+		c := NewInterceptedPacketConn(conn, appendInterceptor(1), appendInterceptor(2))
+		pkt := &SCIONPacket{}
+		require.NoError(t, c.ReadFrom(pkt, &overlay.OverlayAddr{}))
+		assert.Equal(t, common.RawBytes{0, 2, 1}, pkt.Payload)
+	})
+	t.Run("dropped writes succeed without sending", func(t *testing.T) {
+		conn := &queuePacketConn{}
+		c := NewInterceptedPacketConn(conn, dropInterceptor(0), appendInterceptor(1))
+		pkt := &SCIONPacket{SCIONPacketInfo: SCIONPacketInfo{Payload: common.RawBytes{0}}}
+		require.NoError(t, c.WriteTo(pkt, nil))
+		assert.Empty(t, conn.written)
+	})
+	t.Run("dropped reads are skipped", func(t *testing.T) {
+		conn := &queuePacketConn{queued: []common.RawBytes{{0}, {1}}}
+		c := NewInterceptedPacketConn(conn, dropInterceptor(0))
+		pkt := &SCIONPacket{}
+		require.NoError(t, c.ReadFrom(pkt, &overlay.OverlayAddr{}))
+		assert.Equal(t, common.RawBytes{1}, pkt.Payload)
+	})
+	t.Run("errors are returned", func(t *testing.T) {
+		errTest := serrors.New("test")
+		fail := func(*SCIONPacket, *overlay.OverlayAddr) error { return errTest }
+		conn := &queuePacketConn{queued: []common.RawBytes{{0}}}
+		c := NewInterceptedPacketConn(conn, InterceptorFuncs{Send: fail, Receive: fail})
+		pkt := &SCIONPacket{SCIONPacketInfo: SCIONPacketInfo{Payload: common.RawBytes{0}}}
+		assert.Equal(t, errTest, c.WriteTo(pkt, nil))
+		assert.Empty(t, conn.written)
+		assert.Equal(t, errTest, c.ReadFrom(pkt, &overlay.OverlayAddr{}))
+	})
+}
+
+func TestInterceptingPacketDispatcherService(t *testing.T) {
+	conn := &queuePacketConn{}
+	s := &InterceptingPacketDispatcherService{
+		Dispatcher:   &staticDispatcher{conn: conn, port: 40000},
+		Interceptors: []Interceptor{appendInterceptor(1)},
+	}
+	c, port, err := s.RegisterTimeout(xtest.MustParseIA("1-ff00:0:110"), nil, nil,
+		addr.SvcNone, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(40000), port)
+	pkt := &SCIONPacket{SCIONPacketInfo: SCIONPacketInfo{Payload: common.RawBytes{0}}}
+	require.NoError(t, c.WriteTo(pkt, nil))
+	assert.Equal(t, []common.RawBytes{{0, 1}}, conn.written)
+}
+
+type staticDispatcher struct {
+	conn PacketConn
+	port uint16
+}
+
+func (d *staticDispatcher) RegisterTimeout(addr.IA, *addr.AppAddr, *overlay.OverlayAddr,
+	addr.HostSVC, time.Duration) (PacketConn, uint16, error) {
+
+	return d.conn, d.port, nil
+}
@@ -19,6 +19,8 @@ import (
 	"time"
 
 	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/spath"
 )
 
 type Network interface {
@@ -35,12 +37,17 @@ type Conn interface {
 	Write(b []byte) (int, error)
 	WriteTo(b []byte, address net.Addr) (int, error)
 	WriteToSCION(b []byte, address *Addr) (int, error)
+	WriteToVia(b []byte, address *Addr, path *spath.Path,
+		nextHop *overlay.OverlayAddr) (int, error)
+	WriteToFlow(b []byte, address *Addr, flow FlowID) (int, error)
 	Close() error
 	LocalAddr() net.Addr
 	BindAddr() net.Addr
 	SVC() addr.HostSVC
 	RemoteAddr() net.Addr
 	SetDeadline(deadline time.Time) error
+	SetLinger(linger time.Duration, onSCMP func(Error))
 	SetReadDeadline(deadline time.Time) error
+	SetReadFilters(filters ...PacketFilter)
 	SetWriteDeadline(deadline time.Time) error
 }
@@ -0,0 +1,84 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package deadline implements read deadlines for connections whose reads wait
+// on a channel instead of a socket.
+package deadline
+
+import (
+	"sync"
+	"time"
+)
+
+// Deadline is a read deadline. The channel returned by Wait is closed once the
+// deadline has passed. The zero value is not usable, use New instead.
+type Deadline struct {
+	mtx   sync.Mutex
+	ch    chan struct{}
+	timer *time.Timer
+}
+
+// New returns a deadline that is not set.
+func New() *Deadline {
+	return &Deadline{ch: make(chan struct{})}
+}
+
+// Wait returns a channel that is closed once the deadline has passed. Readers
+// must call Wait anew for every read, as the channel is replaced when the
+// deadline is moved after it has passed.
+func (d *Deadline) Wait() <-chan struct{} {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	return d.ch
+}
+
+// Set sets the deadline to t. A zero value for t means reads do not time out.
+func (d *Deadline) Set(t time.Time) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	if d.timer != nil && !d.timer.Stop() {
+		// Wait for the timer to close the channel.
+		<-d.ch
+	}
+	d.timer = nil
+	// Pending reads keep waiting on the current channel, so it is only
+	// replaced once it has been closed.
+	expired := isClosed(d.ch)
+	if t.IsZero() {
+		if expired {
+			d.ch = make(chan struct{})
+		}
+		return
+	}
+	if dur := time.Until(t); dur > 0 {
+		if expired {
+			d.ch = make(chan struct{})
+		}
+		ch := d.ch
+		d.timer = time.AfterFunc(dur, func() { close(ch) })
+		return
+	}
+	if !expired {
+		close(d.ch)
+	}
+}
+
+func isClosed(c <-chan struct{}) bool {
+	select {
+	case <-c:
+		return true
+	default:
+		return false
+	}
+}
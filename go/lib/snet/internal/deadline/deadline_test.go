@@ -0,0 +1,67 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deadline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadline(t *testing.T) {
+	t.Run("unset deadline does not expire", func(t *testing.T) {
+		d := New()
+		assert.False(t, expiresWithin(d.Wait(), 20*time.Millisecond))
+	})
+	t.Run("future deadline expires", func(t *testing.T) {
+		d := New()
+		d.Set(time.Now().Add(10 * time.Millisecond))
+		assert.True(t, expiresWithin(d.Wait(), time.Second))
+	})
+	t.Run("past deadline expires immediately", func(t *testing.T) {
+		d := New()
+		d.Set(time.Now().Add(-time.Second))
+		assert.True(t, isClosed(d.Wait()))
+	})
+	t.Run("pending reads are unblocked by a past deadline", func(t *testing.T) {
+		d := New()
+		d.Set(time.Now().Add(time.Hour))
+		wait := d.Wait()
+		d.Set(time.Now().Add(-time.Second))
+		assert.True(t, isClosed(wait))
+	})
+	t.Run("clearing an expired deadline", func(t *testing.T) {
+		d := New()
+		d.Set(time.Now().Add(-time.Second))
+		d.Set(time.Time{})
+		assert.False(t, isClosed(d.Wait()))
+	})
+	t.Run("moving an expired deadline to the future", func(t *testing.T) {
+		d := New()
+		d.Set(time.Now().Add(-time.Second))
+		d.Set(time.Now().Add(time.Hour))
+		assert.False(t, isClosed(d.Wait()))
+	})
+}
+
+func expiresWithin(c <-chan struct{}, timeout time.Duration) bool {
+	select {
+	case <-c:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
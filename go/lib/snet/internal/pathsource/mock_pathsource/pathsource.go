@@ -37,17 +37,33 @@ func (m *MockPathSource) EXPECT() *MockPathSourceMockRecorder {
 }
 
 // Get mocks base method
-func (m *MockPathSource) Get(arg0 context.Context, arg1, arg2 addr.IA) (*overlay.OverlayAddr, *spath.Path, error) {
+func (m *MockPathSource) Get(arg0 context.Context, arg1, arg2 addr.IA, arg3 uint64) (*overlay.OverlayAddr, *spath.Path, uint16, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "Get", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(*overlay.OverlayAddr)
 	ret1, _ := ret[1].(*spath.Path)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret2, _ := ret[2].(uint16)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
 }
 
 // Get indicates an expected call of Get
-func (mr *MockPathSourceMockRecorder) Get(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockPathSourceMockRecorder) Get(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPathSource)(nil).Get), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPathSource)(nil).Get), arg0, arg1, arg2, arg3)
+}
+
+// SVCHosts mocks base method
+func (m *MockPathSource) SVCHosts(arg0 context.Context, arg1 addr.HostSVC) ([]*overlay.OverlayAddr, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SVCHosts", arg0, arg1)
+	ret0, _ := ret[0].([]*overlay.OverlayAddr)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SVCHosts indicates an expected call of SVCHosts
+func (mr *MockPathSourceMockRecorder) SVCHosts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SVCHosts", reflect.TypeOf((*MockPathSource)(nil).SVCHosts), arg0, arg1)
 }
@@ -0,0 +1,115 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathsource
+
+import (
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/sciond"
+)
+
+// DefaultNextHopTTL is the time a next hop is cached by a NextHopCache.
+const DefaultNextHopTTL = 10 * time.Second
+
+// NextHopCache caches the overlay next hops of paths, keyed by the egress
+// interface of the paths in the local AS. This saves converting the host
+// information of the path on every write. Entries expire after a TTL, and the
+// whole cache is flushed whenever SCIOND reports that the topology changed.
+//
+// A nil cache is valid and caches nothing. Cached next hops are shared by all
+// users of the cache and must not be modified.
+type NextHopCache struct {
+	ttl       time.Duration
+	watchOnce sync.Once
+
+	mu      sync.RWMutex
+	entries map[common.IFIDType]nextHopEntry
+}
+
+type nextHopEntry struct {
+	nextHop *overlay.OverlayAddr
+	expiry  time.Time
+}
+
+// NewNextHopCache creates a cache that keeps next hops for ttl.
+func NewNextHopCache(ttl time.Duration) *NextHopCache {
+	return &NextHopCache{
+		ttl:     ttl,
+		entries: make(map[common.IFIDType]nextHopEntry),
+	}
+}
+
+// Get returns the next hop for egress interface ifid, or nil if the next hop
+// is not cached.
+func (c *NextHopCache) Get(ifid common.IFIDType) *overlay.OverlayAddr {
+	if c == nil {
+		return nil
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.entries[ifid]
+	if !ok || time.Now().After(entry.expiry) {
+		return nil
+	}
+	return entry.nextHop
+}
+
+// Set caches nextHop for egress interface ifid.
+func (c *NextHopCache) Set(ifid common.IFIDType, nextHop *overlay.OverlayAddr) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[ifid] = nextHopEntry{nextHop: nextHop, expiry: time.Now().Add(c.ttl)}
+}
+
+// Flush removes all entries from the cache.
+func (c *NextHopCache) Flush() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[common.IFIDType]nextHopEntry)
+}
+
+// watch flushes the cache whenever conn reports a topology change. Only the
+// first call has an effect.
+func (c *NextHopCache) watch(conn sciond.Connector) {
+	if c == nil || conn == nil {
+		return
+	}
+	c.watchOnce.Do(func() {
+		changes := conn.TopologyChanges()
+		go func() {
+			defer log.LogPanicAndExit()
+			c.flushOn(changes)
+		}()
+	})
+}
+
+// flushOn flushes the cache for every notification received on changes, until
+// changes is closed.
+func (c *NextHopCache) flushOn(changes <-chan *sciond.TopoChangeNotification) {
+	for n := range changes {
+		log.Debug("Flushing next hop cache due to topology change", "notification", n)
+		c.Flush()
+	}
+}
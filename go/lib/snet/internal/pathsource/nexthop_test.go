@@ -0,0 +1,68 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathsource
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/sciond"
+)
+
+func TestNextHopCache(t *testing.T) {
+	nextHop := mustOverlayAddr(t, "127.0.0.1")
+
+	t.Run("cached next hop is returned", func(t *testing.T) {
+		c := NewNextHopCache(time.Minute)
+		assert.Nil(t, c.Get(1))
+		c.Set(1, nextHop)
+		assert.Equal(t, nextHop, c.Get(1))
+		assert.Nil(t, c.Get(2))
+	})
+	t.Run("expired next hop is not returned", func(t *testing.T) {
+		c := NewNextHopCache(-time.Second)
+		c.Set(1, nextHop)
+		assert.Nil(t, c.Get(1))
+	})
+	t.Run("topology change flushes the cache", func(t *testing.T) {
+		c := NewNextHopCache(time.Minute)
+		c.Set(1, nextHop)
+		changes := make(chan *sciond.TopoChangeNotification, 1)
+		changes <- &sciond.TopoChangeNotification{}
+		close(changes)
+		c.flushOn(changes)
+		assert.Nil(t, c.Get(1))
+	})
+	t.Run("nil cache caches nothing", func(t *testing.T) {
+		var c *NextHopCache
+		c.Set(1, nextHop)
+		assert.Nil(t, c.Get(1))
+		c.Flush()
+	})
+}
+
+func mustOverlayAddr(t *testing.T, ip string) *overlay.OverlayAddr {
+	t.Helper()
+	ov, err := overlay.NewOverlayAddr(addr.HostFromIP(net.ParseIP(ip)),
+		addr.NewL4UDPInfo(overlay.EndhostPort))
+	require.NoError(t, err)
+	return ov
+}
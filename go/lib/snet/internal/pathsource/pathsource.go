@@ -23,6 +23,8 @@ import (
 	"github.com/scionproto/scion/go/lib/pathmgr"
 	"github.com/scionproto/scion/go/lib/sciond"
 	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/lib/spath/spathmeta"
+	"github.com/scionproto/scion/go/proto"
 )
 
 const (
@@ -30,42 +32,143 @@ const (
 	ErrNoPath     = "path not found"
 	ErrInitPath   = "raw forwarding path offsets could not be initialized"
 	ErrBadOverlay = "unable to extract next hop from sciond path entry"
+	ErrUnknownSVC = "unsupported SVC address"
+	ErrNoSVCHost  = "no host for SVC address found"
 )
 
 // PathSource is a source of paths and overlay addresses for snet.
 type PathSource interface {
-	Get(ctx context.Context, src, dst addr.IA) (*overlay.OverlayAddr, *spath.Path, error)
+	// Get returns the next hop and path from src to dst, and the MTU of the
+	// path. An MTU of 0 means the MTU is unknown. If flow is not 0, the path
+	// assigned to the flow is returned, such that all packets of a flow use
+	// the same path. Otherwise, an arbitrary path is returned.
+	Get(ctx context.Context, src, dst addr.IA, flow uint64) (*overlay.OverlayAddr, *spath.Path,
+		uint16, error)
+	// SVCHosts returns the overlay addresses of the dispatchers on the hosts
+	// running instances of svc in the local AS. Every host is returned once,
+	// independent of the number of instances it runs.
+	SVCHosts(ctx context.Context, svc addr.HostSVC) ([]*overlay.OverlayAddr, error)
 }
 
 type pathSource struct {
-	resolver pathmgr.Resolver
+	resolver   pathmgr.Resolver
+	nextHops   *NextHopCache
+	quarantine *Quarantine
 }
 
 // NewPathSource initializes a source of paths and overlay addresses for snet,
 // with information obtained from resolver. Passing in a nil resolver is
-// allowed, but the source will always return an error when invoked.
-func NewPathSource(resolver pathmgr.Resolver) PathSource {
-	return &pathSource{resolver: resolver}
+// allowed, but the source will always return an error when invoked. If
+// nextHops is not nil, the next hops of paths are cached in it. If quarantine
+// is not nil, paths traversing quarantined interfaces are avoided.
+func NewPathSource(resolver pathmgr.Resolver, nextHops *NextHopCache,
+	quarantine *Quarantine) PathSource {
+
+	return &pathSource{resolver: resolver, nextHops: nextHops, quarantine: quarantine}
 }
 
-func (ps *pathSource) Get(ctx context.Context,
-	src, dst addr.IA) (*overlay.OverlayAddr, *spath.Path, error) {
+func (ps *pathSource) Get(ctx context.Context, src, dst addr.IA,
+	flow uint64) (*overlay.OverlayAddr, *spath.Path, uint16, error) {
 
 	if ps.resolver == nil {
-		return nil, nil, common.NewBasicError(ErrNoResolver, nil)
+		return nil, nil, 0, common.NewBasicError(ErrNoResolver, nil)
+	}
+	paths := ps.quarantine.Filter(ps.resolver.Query(ctx, src, dst, sciond.PathReqFlags{}))
+	var sciondPath *spathmeta.AppPath
+	if flow != 0 {
+		sciondPath = paths.GetAppPathForFlow(flow)
+	} else {
+		sciondPath = paths.GetAppPath("")
 	}
-	paths := ps.resolver.Query(ctx, src, dst, sciond.PathReqFlags{})
-	sciondPath := paths.GetAppPath("")
 	if sciondPath == nil {
-		return nil, nil, common.NewBasicError(ErrNoPath, nil)
+		return nil, nil, 0, common.NewBasicError(ErrNoPath, nil)
 	}
 	path := &spath.Path{Raw: sciondPath.Entry.Path.FwdPath}
 	if err := path.InitOffsets(); err != nil {
-		return nil, nil, common.NewBasicError(ErrInitPath, nil)
+		return nil, nil, 0, common.NewBasicError(ErrInitPath, nil)
+	}
+	overlayAddr, err := ps.nextHop(sciondPath.Entry)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return overlayAddr, path, sciondPath.Entry.Path.Mtu, nil
+}
+
+// nextHop returns the next hop of the path in entry, from the cache if
+// possible.
+func (ps *pathSource) nextHop(entry *sciond.PathReplyEntry) (*overlay.OverlayAddr, error) {
+	var ifid common.IFIDType
+	if len(entry.Path.Interfaces) > 0 {
+		ifid = entry.Path.Interfaces[0].IfID
+	}
+	if ifid != 0 {
+		if nextHop := ps.nextHops.Get(ifid); nextHop != nil {
+			return nextHop, nil
+		}
+	}
+	overlayAddr, err := entry.HostInfo.Overlay()
+	if err != nil {
+		return nil, common.NewBasicError(ErrBadOverlay, nil)
+	}
+	if ifid != 0 && ps.nextHops != nil {
+		ps.nextHops.watch(ps.resolver.Sciond())
+		ps.nextHops.Set(ifid, overlayAddr)
 	}
-	overlayAddr, err := sciondPath.Entry.HostInfo.Overlay()
+	return overlayAddr, nil
+}
+
+func (ps *pathSource) SVCHosts(ctx context.Context,
+	svc addr.HostSVC) ([]*overlay.OverlayAddr, error) {
+
+	if ps.resolver == nil {
+		return nil, common.NewBasicError(ErrNoResolver, nil)
+	}
+	svcType, ok := serviceType(svc.Base())
+	if !ok {
+		return nil, common.NewBasicError(ErrUnknownSVC, nil, "svc", svc)
+	}
+	reply, err := ps.resolver.Sciond().SVCInfo(ctx, []proto.ServiceType{svcType})
 	if err != nil {
-		return nil, nil, common.NewBasicError(ErrBadOverlay, nil)
+		return nil, err
+	}
+	var hosts []*overlay.OverlayAddr
+	seen := make(map[string]struct{})
+	for _, entry := range reply.Entries {
+		for _, hostInfo := range entry.HostInfos {
+			host := hostInfo.Host()
+			if host == nil {
+				continue
+			}
+			if _, ok := seen[host.String()]; ok {
+				continue
+			}
+			seen[host.String()] = struct{}{}
+			ov, err := overlay.NewOverlayAddr(host, addr.NewL4UDPInfo(overlay.EndhostPort))
+			if err != nil {
+				return nil, common.NewBasicError(ErrBadOverlay, err, "host", host)
+			}
+			hosts = append(hosts, ov)
+		}
+	}
+	if len(hosts) == 0 {
+		return nil, common.NewBasicError(ErrNoSVCHost, nil, "svc", svc)
+	}
+	return hosts, nil
+}
+
+func serviceType(svc addr.HostSVC) (proto.ServiceType, bool) {
+	switch svc {
+	case addr.SvcBS:
+		return proto.ServiceType_bs, true
+	case addr.SvcPS:
+		return proto.ServiceType_ps, true
+	case addr.SvcCS:
+		return proto.ServiceType_cs, true
+	case addr.SvcSB:
+		return proto.ServiceType_sb, true
+	case addr.SvcSIG:
+		return proto.ServiceType_sig, true
+	default:
+		return proto.ServiceType_unset, false
 	}
-	return overlayAddr, path, nil
 }
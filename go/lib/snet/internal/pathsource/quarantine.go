@@ -0,0 +1,96 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathsource
+
+import (
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/spath/spathmeta"
+)
+
+// Quarantine keeps track of interfaces that are temporarily avoided, e.g.,
+// because a revocation was received for them. In contrast to revoking the
+// paths in the resolver, quarantined paths are only skipped while other paths
+// are available, and they are used again once the quarantine period is over.
+//
+// A nil quarantine is valid and quarantines nothing.
+type Quarantine struct {
+	mu      sync.RWMutex
+	entries map[sciond.PathInterface]time.Time
+}
+
+// NewQuarantine creates an empty quarantine.
+func NewQuarantine() *Quarantine {
+	return &Quarantine{entries: make(map[sciond.PathInterface]time.Time)}
+}
+
+// Add quarantines interface ifid of AS ia for duration d. Quarantining an
+// interface that is already quarantined extends the quarantine period if
+// necessary.
+func (q *Quarantine) Add(ia addr.IA, ifid common.IFIDType, d time.Duration) {
+	if q == nil {
+		return
+	}
+	now := time.Now()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for pi, expiry := range q.entries {
+		if now.After(expiry) {
+			delete(q.entries, pi)
+		}
+	}
+	pi := sciond.PathInterface{RawIsdas: ia.IAInt(), IfID: ifid}
+	if expiry := now.Add(d); expiry.After(q.entries[pi]) {
+		q.entries[pi] = expiry
+	}
+}
+
+// Filter returns the paths of aps that do not traverse a quarantined
+// interface. If all paths traverse a quarantined interface, aps is returned
+// unchanged, such that traffic is not blackholed.
+func (q *Quarantine) Filter(aps spathmeta.AppPathSet) spathmeta.AppPathSet {
+	if q == nil {
+		return aps
+	}
+	now := time.Now()
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if len(q.entries) == 0 {
+		return aps
+	}
+	filtered := make(spathmeta.AppPathSet)
+	for key, path := range aps {
+		if !q.quarantined(path, now) {
+			filtered[key] = path
+		}
+	}
+	if len(filtered) == 0 {
+		return aps
+	}
+	return filtered
+}
+
+func (q *Quarantine) quarantined(path *spathmeta.AppPath, now time.Time) bool {
+	for _, pi := range path.Entry.Path.Interfaces {
+		if expiry, ok := q.entries[pi]; ok && now.Before(expiry) {
+			return true
+		}
+	}
+	return false
+}
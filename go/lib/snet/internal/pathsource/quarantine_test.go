@@ -0,0 +1,79 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathsource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/spath/spathmeta"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestQuarantine(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	// pathVia returns a path that traverses interface ifid of ia.
+	pathVia := func(aps spathmeta.AppPathSet, ifid common.IFIDType) *spathmeta.AppPath {
+		return aps.Add(&sciond.PathReplyEntry{
+			Path: &sciond.FwdPathMeta{
+				Interfaces: []sciond.PathInterface{{RawIsdas: ia.IAInt(), IfID: ifid}},
+			},
+		})
+	}
+
+	t.Run("quarantined paths are skipped", func(t *testing.T) {
+		aps := make(spathmeta.AppPathSet)
+		pathVia(aps, 1)
+		other := pathVia(aps, 2)
+		q := NewQuarantine()
+		q.Add(ia, 1, time.Minute)
+		assert.Equal(t, spathmeta.AppPathSet{other.Key(): other}, q.Filter(aps))
+	})
+	t.Run("quarantined paths are used if no other path exists", func(t *testing.T) {
+		aps := make(spathmeta.AppPathSet)
+		pathVia(aps, 1)
+		q := NewQuarantine()
+		q.Add(ia, 1, time.Minute)
+		assert.Equal(t, aps, q.Filter(aps))
+	})
+	t.Run("quarantine expires", func(t *testing.T) {
+		aps := make(spathmeta.AppPathSet)
+		pathVia(aps, 1)
+		pathVia(aps, 2)
+		q := NewQuarantine()
+		q.Add(ia, 1, -time.Second)
+		assert.Equal(t, aps, q.Filter(aps))
+	})
+	t.Run("quarantine is extended", func(t *testing.T) {
+		aps := make(spathmeta.AppPathSet)
+		pathVia(aps, 1)
+		other := pathVia(aps, 2)
+		q := NewQuarantine()
+		q.Add(ia, 1, time.Minute)
+		q.Add(ia, 1, -time.Second)
+		assert.Equal(t, spathmeta.AppPathSet{other.Key(): other}, q.Filter(aps))
+	})
+	t.Run("nil quarantine quarantines nothing", func(t *testing.T) {
+		aps := make(spathmeta.AppPathSet)
+		pathVia(aps, 1)
+		var q *Quarantine
+		q.Add(ia, 1, time.Minute)
+		assert.Equal(t, aps, q.Filter(aps))
+	})
+}
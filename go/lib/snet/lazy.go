@@ -0,0 +1,302 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+const (
+	// DefaultLazyRetryInterval is the time between two registration attempts
+	// of lazily registered sockets, if no other interval is configured.
+	DefaultLazyRetryInterval = time.Second
+)
+
+const (
+	ErrLazyNoPort     = "lazy registration requires a fixed port"
+	ErrLazyClosed     = "socket closed before dispatcher registration"
+	ErrLazyTimeout    = "dispatcher registration timed out"
+	ErrLazyAttempts   = "dispatcher registration failed"
+	ErrLazyPortChange = "dispatcher assigned a different port"
+)
+
+var _ PacketDispatcherService = (*LazyPacketDispatcherService)(nil)
+
+// LazyPacketDispatcherService constructs SCION sockets that defer the
+// registration with the dispatcher until they are first read from or written
+// to. ListenSCION and DialSCION on a network using this service return
+// immediately, even if the dispatcher is not running yet. This allows
+// services to start before the dispatcher, e.g., in containerized
+// deployments.
+//
+// Because the port is only known after registration, lazily registered
+// sockets must be bound to a fixed port. If the registration fails, it is
+// retried until it succeeds, the deadline of the read or write call passes,
+// the maximum number of attempts is reached, or the socket is closed. Failed
+// calls return the registration error, and the next call tries again.
+//
+// The service only covers the initial registration. To recover from
+// dispatcher restarts later on, wrap the reliable.DispatcherService of the
+// underlying service with reconnect.NewDispatcherService.
+type LazyPacketDispatcherService struct {
+	// Dispatcher is the service used to register the sockets.
+	Dispatcher PacketDispatcherService
+	// RetryInterval is the time to wait between two registration attempts.
+	// If it is 0, DefaultLazyRetryInterval is used.
+	RetryInterval time.Duration
+	// MaxAttempts is the maximum number of registration attempts per read or
+	// write call. If it is 0, the number of attempts is not limited.
+	MaxAttempts int
+}
+
+// RegisterTimeout returns a socket that registers with the dispatcher on
+// first use. The timeout applies to every registration attempt. The returned
+// port is the port in public, which must not be 0.
+func (s *LazyPacketDispatcherService) RegisterTimeout(ia addr.IA, public *addr.AppAddr,
+	bind *overlay.OverlayAddr, svc addr.HostSVC,
+	timeout time.Duration) (PacketConn, uint16, error) {
+
+	if public == nil || public.L4 == nil || public.L4.Port() == 0 {
+		return nil, 0, serrors.New(ErrLazyNoPort, "public", public)
+	}
+	port := public.L4.Port()
+	retryInterval := s.RetryInterval
+	if retryInterval == 0 {
+		retryInterval = DefaultLazyRetryInterval
+	}
+	register := func(timeout time.Duration) (PacketConn, uint16, error) {
+		return s.Dispatcher.RegisterTimeout(ia, public, bind, svc, timeout)
+	}
+	return newLazyPacketConn(register, port, timeout, retryInterval, s.MaxAttempts), port, nil
+}
+
+// lazyPacketConn is a PacketConn that registers with the dispatcher on first
+// use.
+type lazyPacketConn struct {
+	register      func(timeout time.Duration) (PacketConn, uint16, error)
+	port          uint16
+	timeout       time.Duration
+	retryInterval time.Duration
+	maxAttempts   int
+	// closedChan is closed when the socket is closed, to abort pending
+	// registrations.
+	closedChan chan struct{}
+
+	// connectSem serializes registration attempts. It is a channel instead
+	// of a mutex, such that waiting callers can give up when their deadline
+	// passes.
+	connectSem chan struct{}
+
+	// mtx protects the fields below.
+	mtx           sync.Mutex
+	conn          PacketConn
+	closed        bool
+	readDeadline  time.Time
+	writeDeadline time.Time
+}
+
+func newLazyPacketConn(register func(timeout time.Duration) (PacketConn, uint16, error),
+	port uint16, timeout, retryInterval time.Duration, maxAttempts int) *lazyPacketConn {
+
+	return &lazyPacketConn{
+		register:      register,
+		port:          port,
+		timeout:       timeout,
+		retryInterval: retryInterval,
+		maxAttempts:   maxAttempts,
+		closedChan:    make(chan struct{}),
+		connectSem:    make(chan struct{}, 1),
+	}
+}
+
+func (c *lazyPacketConn) ReadFrom(pkt *SCIONPacket, ov *overlay.OverlayAddr) error {
+	c.mtx.Lock()
+	deadline := c.readDeadline
+	c.mtx.Unlock()
+	conn, err := c.connect(deadline)
+	if err != nil {
+		return err
+	}
+	return conn.ReadFrom(pkt, ov)
+}
+
+func (c *lazyPacketConn) WriteTo(pkt *SCIONPacket, ov *overlay.OverlayAddr) error {
+	c.mtx.Lock()
+	deadline := c.writeDeadline
+	c.mtx.Unlock()
+	conn, err := c.connect(deadline)
+	if err != nil {
+		return err
+	}
+	return conn.WriteTo(pkt, ov)
+}
+
+func (c *lazyPacketConn) SetReadDeadline(t time.Time) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.readDeadline = t
+	if c.conn != nil {
+		return c.conn.SetReadDeadline(t)
+	}
+	return nil
+}
+
+func (c *lazyPacketConn) SetWriteDeadline(t time.Time) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.writeDeadline = t
+	if c.conn != nil {
+		return c.conn.SetWriteDeadline(t)
+	}
+	return nil
+}
+
+func (c *lazyPacketConn) SetDeadline(t time.Time) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.readDeadline = t
+	c.writeDeadline = t
+	if c.conn != nil {
+		return c.conn.SetDeadline(t)
+	}
+	return nil
+}
+
+func (c *lazyPacketConn) Close() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	close(c.closedChan)
+	if c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
+
+// connect returns the registered socket, registering with the dispatcher if
+// this has not happened yet. Registration is retried until deadline. If
+// another call is registering, connect waits for it until deadline.
+func (c *lazyPacketConn) connect(deadline time.Time) (PacketConn, error) {
+	if conn, err := c.registered(); conn != nil || err != nil {
+		return conn, err
+	}
+	if err := c.acquireConnect(deadline); err != nil {
+		return nil, err
+	}
+	defer func() { <-c.connectSem }()
+	if conn, err := c.registered(); conn != nil || err != nil {
+		return conn, err
+	}
+	for attempt := 1; ; attempt++ {
+		timeout := c.timeout
+		if !deadline.IsZero() {
+			left := time.Until(deadline)
+			if left <= 0 {
+				return nil, serrors.WithCode(serrors.New(ErrLazyTimeout, "attempts", attempt-1),
+					serrors.CodeTimeout)
+			}
+			if timeout == 0 || left < timeout {
+				timeout = left
+			}
+		}
+		conn, port, err := c.register(timeout)
+		if err == nil {
+			return c.setConn(conn, port)
+		}
+		log.Debug("Unable to register with dispatcher, retrying", "port", c.port,
+			"attempt", attempt, "err", err)
+		if c.maxAttempts != 0 && attempt >= c.maxAttempts {
+			return nil, serrors.WithCode(
+				common.NewBasicError(ErrLazyAttempts, err, "attempts", attempt),
+				serrors.CodeUnavailable)
+		}
+		wait := c.retryInterval
+		if !deadline.IsZero() && time.Until(deadline) < wait {
+			return nil, serrors.WithCode(
+				common.NewBasicError(ErrLazyTimeout, err, "attempts", attempt),
+				serrors.CodeTimeout)
+		}
+		select {
+		case <-time.After(wait):
+		case <-c.closedChan:
+			return nil, serrors.New(ErrLazyClosed)
+		}
+	}
+}
+
+// acquireConnect waits until no other call is registering, the deadline
+// passes, or the socket is closed.
+func (c *lazyPacketConn) acquireConnect(deadline time.Time) error {
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case c.connectSem <- struct{}{}:
+		return nil
+	case <-timeout:
+		return serrors.WithCode(serrors.New(ErrLazyTimeout, "attempts", 0),
+			serrors.CodeTimeout)
+	case <-c.closedChan:
+		return serrors.New(ErrLazyClosed)
+	}
+}
+
+func (c *lazyPacketConn) registered() (PacketConn, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.closed {
+		return nil, serrors.New(ErrLazyClosed)
+	}
+	return c.conn, nil
+}
+
+// setConn installs a freshly registered socket and applies the deadlines
+// that were set before the registration.
+func (c *lazyPacketConn) setConn(conn PacketConn, port uint16) (PacketConn, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.closed {
+		conn.Close()
+		return nil, serrors.New(ErrLazyClosed)
+	}
+	if port != c.port {
+		conn.Close()
+		return nil, serrors.New(ErrLazyPortChange, "expected", c.port, "actual", port)
+	}
+	if err := conn.SetReadDeadline(c.readDeadline); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := conn.SetWriteDeadline(c.writeDeadline); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	log.Debug("Registered with dispatcher", "port", port)
+	c.conn = conn
+	return conn, nil
+}
@@ -0,0 +1,201 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+// flakyDispatcher fails the first Failures registrations.
+type flakyDispatcher struct {
+	Failures int
+	Port     uint16
+
+	mtx      sync.Mutex
+	attempts int
+	conn     *deadlinePacketConn
+}
+
+func (d *flakyDispatcher) RegisterTimeout(ia addr.IA, public *addr.AppAddr,
+	bind *overlay.OverlayAddr, svc addr.HostSVC,
+	timeout time.Duration) (PacketConn, uint16, error) {
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	d.attempts++
+	if d.attempts <= d.Failures {
+		return nil, 0, serrors.New("dispatcher not running")
+	}
+	d.conn = &deadlinePacketConn{PacketConn: &loopbackPacketConn{}}
+	return d.conn, d.Port, nil
+}
+
+func (d *flakyDispatcher) Attempts() int {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	return d.attempts
+}
+
+// deadlinePacketConn records deadlines and whether it was closed.
+type deadlinePacketConn struct {
+	PacketConn
+	readDeadline  time.Time
+	writeDeadline time.Time
+	closed        bool
+}
+
+func (c *deadlinePacketConn) SetReadDeadline(t time.Time) error {
+	c.readDeadline = t
+	return nil
+}
+
+func (c *deadlinePacketConn) SetWriteDeadline(t time.Time) error {
+	c.writeDeadline = t
+	return nil
+}
+
+func (c *deadlinePacketConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func lazyTestAddr(port uint16) *addr.AppAddr {
+	return &addr.AppAddr{
+		L3: addr.HostFromIP(net.IP{127, 0, 0, 1}),
+		L4: addr.NewL4UDPInfo(port),
+	}
+}
+
+func TestLazyPacketDispatcherServiceRegisterTimeout(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	t.Run("port 0 is rejected", func(t *testing.T) {
+		s := &LazyPacketDispatcherService{Dispatcher: &flakyDispatcher{}}
+		_, _, err := s.RegisterTimeout(ia, lazyTestAddr(0), nil, addr.SvcNone, 0)
+		assert.Error(t, err)
+	})
+	t.Run("registration is deferred", func(t *testing.T) {
+		d := &flakyDispatcher{Port: 40000}
+		s := &LazyPacketDispatcherService{Dispatcher: d}
+		conn, port, err := s.RegisterTimeout(ia, lazyTestAddr(40000), nil, addr.SvcNone, 0)
+		require.NoError(t, err)
+		assert.Equal(t, uint16(40000), port)
+		assert.Equal(t, 0, d.Attempts())
+		require.NoError(t, conn.WriteTo(&SCIONPacket{}, nil))
+		require.NoError(t, conn.WriteTo(&SCIONPacket{}, nil))
+		assert.Equal(t, 1, d.Attempts())
+	})
+}
+
+func TestLazyPacketConnConnect(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	t.Run("registration is retried", func(t *testing.T) {
+		d := &flakyDispatcher{Failures: 2, Port: 40000}
+		s := &LazyPacketDispatcherService{Dispatcher: d, RetryInterval: time.Millisecond}
+		conn, _, err := s.RegisterTimeout(ia, lazyTestAddr(40000), nil, addr.SvcNone, 0)
+		require.NoError(t, err)
+		require.NoError(t, conn.ReadFrom(&SCIONPacket{}, &overlay.OverlayAddr{}))
+		assert.Equal(t, 3, d.Attempts())
+	})
+	t.Run("attempts are limited", func(t *testing.T) {
+		d := &flakyDispatcher{Failures: 5, Port: 40000}
+		s := &LazyPacketDispatcherService{Dispatcher: d, RetryInterval: time.Millisecond,
+			MaxAttempts: 2}
+		conn, _, err := s.RegisterTimeout(ia, lazyTestAddr(40000), nil, addr.SvcNone, 0)
+		require.NoError(t, err)
+		assert.Error(t, conn.WriteTo(&SCIONPacket{}, nil))
+		assert.Equal(t, 2, d.Attempts())
+		// The next call tries again.
+		assert.Error(t, conn.WriteTo(&SCIONPacket{}, nil))
+		assert.Equal(t, 4, d.Attempts())
+	})
+	t.Run("deadline stops retries", func(t *testing.T) {
+		d := &flakyDispatcher{Failures: 1000, Port: 40000}
+		s := &LazyPacketDispatcherService{Dispatcher: d, RetryInterval: 10 * time.Millisecond}
+		conn, _, err := s.RegisterTimeout(ia, lazyTestAddr(40000), nil, addr.SvcNone, 0)
+		require.NoError(t, err)
+		require.NoError(t, conn.SetWriteDeadline(time.Now().Add(50*time.Millisecond)))
+		assert.Error(t, conn.WriteTo(&SCIONPacket{}, nil))
+		assert.True(t, d.Attempts() < 1000)
+	})
+	t.Run("close aborts retries", func(t *testing.T) {
+		d := &flakyDispatcher{Failures: 1000, Port: 40000}
+		s := &LazyPacketDispatcherService{Dispatcher: d, RetryInterval: time.Hour}
+		conn, _, err := s.RegisterTimeout(ia, lazyTestAddr(40000), nil, addr.SvcNone, 0)
+		require.NoError(t, err)
+		errChan := make(chan error)
+		go func() {
+			errChan <- conn.WriteTo(&SCIONPacket{}, nil)
+		}()
+		time.Sleep(10 * time.Millisecond)
+		require.NoError(t, conn.Close())
+		select {
+		case err := <-errChan:
+			assert.Error(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("write did not return after close")
+		}
+	})
+	t.Run("deadline is honored while another call registers", func(t *testing.T) {
+		d := &flakyDispatcher{Failures: 1000, Port: 40000}
+		s := &LazyPacketDispatcherService{Dispatcher: d, RetryInterval: time.Hour}
+		conn, _, err := s.RegisterTimeout(ia, lazyTestAddr(40000), nil, addr.SvcNone, 0)
+		require.NoError(t, err)
+		defer conn.Close()
+		go conn.WriteTo(&SCIONPacket{}, nil)
+		time.Sleep(10 * time.Millisecond)
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(10*time.Millisecond)))
+		errChan := make(chan error)
+		go func() {
+			errChan <- conn.ReadFrom(&SCIONPacket{}, &overlay.OverlayAddr{})
+		}()
+		select {
+		case err := <-errChan:
+			assert.Equal(t, serrors.CodeTimeout, serrors.CodeOf(err))
+		case <-time.After(time.Second):
+			t.Fatal("read did not return after its deadline")
+		}
+	})
+	t.Run("deadlines are applied after registration", func(t *testing.T) {
+		d := &flakyDispatcher{Port: 40000}
+		s := &LazyPacketDispatcherService{Dispatcher: d}
+		conn, _, err := s.RegisterTimeout(ia, lazyTestAddr(40000), nil, addr.SvcNone, 0)
+		require.NoError(t, err)
+		deadline := time.Now().Add(time.Hour)
+		require.NoError(t, conn.SetReadDeadline(deadline))
+		require.NoError(t, conn.WriteTo(&SCIONPacket{}, nil))
+		assert.Equal(t, deadline, d.conn.readDeadline)
+		require.NoError(t, conn.Close())
+		assert.True(t, d.conn.closed)
+	})
+	t.Run("port change is rejected", func(t *testing.T) {
+		d := &flakyDispatcher{Port: 40001}
+		s := &LazyPacketDispatcherService{Dispatcher: d}
+		conn, _, err := s.RegisterTimeout(ia, lazyTestAddr(40000), nil, addr.SvcNone, 0)
+		require.NoError(t, err)
+		assert.Error(t, conn.WriteTo(&SCIONPacket{}, nil))
+		assert.True(t, d.conn.closed)
+	})
+}
@@ -170,6 +170,18 @@ func (mr *MockConnMockRecorder) SetDeadline(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDeadline", reflect.TypeOf((*MockConn)(nil).SetDeadline), arg0)
 }
 
+// SetLinger mocks base method
+func (m *MockConn) SetLinger(arg0 time.Duration, arg1 func(snet.Error)) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetLinger", arg0, arg1)
+}
+
+// SetLinger indicates an expected call of SetLinger
+func (mr *MockConnMockRecorder) SetLinger(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLinger", reflect.TypeOf((*MockConn)(nil).SetLinger), arg0, arg1)
+}
+
 // SetReadDeadline mocks base method
 func (m *MockConn) SetReadDeadline(arg0 time.Time) error {
 	m.ctrl.T.Helper()
@@ -184,6 +196,22 @@ func (mr *MockConnMockRecorder) SetReadDeadline(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReadDeadline", reflect.TypeOf((*MockConn)(nil).SetReadDeadline), arg0)
 }
 
+// SetReadFilters mocks base method
+func (m *MockConn) SetReadFilters(arg0 ...snet.PacketFilter) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "SetReadFilters", varargs...)
+}
+
+// SetReadFilters indicates an expected call of SetReadFilters
+func (mr *MockConnMockRecorder) SetReadFilters(arg0 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReadFilters", reflect.TypeOf((*MockConn)(nil).SetReadFilters), arg0...)
+}
+
 // SetWriteDeadline mocks base method
 func (m *MockConn) SetWriteDeadline(arg0 time.Time) error {
 	m.ctrl.T.Helper()
@@ -228,6 +256,21 @@ func (mr *MockConnMockRecorder) WriteTo(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteTo", reflect.TypeOf((*MockConn)(nil).WriteTo), arg0, arg1)
 }
 
+// WriteToFlow mocks base method
+func (m *MockConn) WriteToFlow(arg0 []byte, arg1 *snet.Addr, arg2 snet.FlowID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteToFlow", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WriteToFlow indicates an expected call of WriteToFlow
+func (mr *MockConnMockRecorder) WriteToFlow(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteToFlow", reflect.TypeOf((*MockConn)(nil).WriteToFlow), arg0, arg1, arg2)
+}
+
 // WriteToSCION mocks base method
 func (m *MockConn) WriteToSCION(arg0 []byte, arg1 *snet.Addr) (int, error) {
 	m.ctrl.T.Helper()
@@ -243,6 +286,21 @@ func (mr *MockConnMockRecorder) WriteToSCION(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteToSCION", reflect.TypeOf((*MockConn)(nil).WriteToSCION), arg0, arg1)
 }
 
+// WriteToVia mocks base method
+func (m *MockConn) WriteToVia(arg0 []byte, arg1 *snet.Addr, arg2 *spath.Path, arg3 *overlay.OverlayAddr) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteToVia", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WriteToVia indicates an expected call of WriteToVia
+func (mr *MockConnMockRecorder) WriteToVia(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteToVia", reflect.TypeOf((*MockConn)(nil).WriteToVia), arg0, arg1, arg2, arg3)
+}
+
 // MockPacketDispatcherService is a mock of PacketDispatcherService interface
 type MockPacketDispatcherService struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,370 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet/internal/deadline"
+	"github.com/scionproto/scion/go/lib/snet/internal/pathsource"
+	"github.com/scionproto/scion/go/lib/spath"
+)
+
+const (
+	// sourceCacheTTL is the time for which the local source address of a
+	// next hop is cached.
+	sourceCacheTTL = time.Minute
+)
+
+// ErrMultiConnClosed is returned by operations on a closed MultiConn.
+var ErrMultiConnClosed = serrors.New("multi-homed connection closed")
+
+// MultiListen registers the service on each of the local addresses laddrs,
+// e.g., on the IP addresses of the network interfaces of a multi-homed host,
+// see ListenSCIONWithBindSVC. If the port of the first address is 0, the port
+// allocated for it is used for all other addresses that do not specify a
+// port, such that the service is reachable on the same port everywhere.
+//
+// A timeout of 0 means infinite timeout.
+func (n *SCIONNetwork) MultiListen(network string, laddrs []*Addr, svc addr.HostSVC,
+	timeout time.Duration) (*MultiConn, error) {
+
+	if len(laddrs) == 0 {
+		return nil, serrors.New("At least one local address required")
+	}
+	var deadline time.Time
+	if timeout != 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	conns := make([]Conn, 0, len(laddrs))
+	closeAll := func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}
+	var port uint16
+	for _, laddr := range laddrs {
+		if laddr == nil || laddr.Host == nil {
+			closeAll()
+			return nil, serrors.New("Nil laddr not supported")
+		}
+		laddr = laddr.Copy()
+		if port != 0 && (laddr.Host.L4 == nil || laddr.Host.L4.Port() == 0) {
+			laddr.Host.L4 = addr.NewL4UDPInfo(port)
+		}
+		if timeout != 0 {
+			if timeout = time.Until(deadline); timeout <= 0 {
+				closeAll()
+				return nil, serrors.WithCode(serrors.New("Registration timed out"),
+					serrors.CodeTimeout)
+			}
+		}
+		conn, err := n.ListenSCIONWithBindSVC(network, laddr, nil, svc, timeout)
+		if err != nil {
+			closeAll()
+			return nil, common.NewBasicError("Unable to listen on local address", err,
+				"addr", laddr)
+		}
+		if port == 0 {
+			port = conn.LocalAddr().(*Addr).Host.L4.Port()
+		}
+		conns = append(conns, conn)
+	}
+	return newMultiConn(conns, n.localIA, pathsource.NewPathSource(n.pathResolver,
+		n.nextHops, n.quarantine)), nil
+}
+
+var _ net.PacketConn = (*MultiConn)(nil)
+
+// MultiConn is a SCION connection of a service that is registered on several
+// local addresses, see MultiListen. The packets received on all addresses
+// are merged, and each packet is sent from the local address over which the
+// next hop of its path is reached. For remote destinations, the next hop is
+// the border router of the egress interface of the path. The local address is
+// chosen based on the route of the host to the next hop.
+type MultiConn struct {
+	conns      []Conn
+	locals     []net.IP
+	localIA    addr.IA
+	pathSource pathsource.PathSource
+	sources    *sourceSelector
+
+	queue     chan multiPacket
+	closeOnce sync.Once
+	closed    chan struct{}
+	deadline  *deadline.Deadline
+}
+
+type multiPacket struct {
+	pld   []byte
+	raddr *Addr
+	err   error
+}
+
+func newMultiConn(conns []Conn, localIA addr.IA, pathSource pathsource.PathSource) *MultiConn {
+	c := &MultiConn{
+		conns:      conns,
+		locals:     make([]net.IP, 0, len(conns)),
+		localIA:    localIA,
+		pathSource: pathSource,
+		sources:    newSourceSelector(routeSource),
+		queue:      make(chan multiPacket, DefaultMuxQueueSize),
+		closed:     make(chan struct{}),
+		deadline:   deadline.New(),
+	}
+	for _, conn := range conns {
+		c.locals = append(c.locals, conn.LocalAddr().(*Addr).Host.L3.IP())
+		go func(conn Conn) {
+			defer log.LogPanicAndExit()
+			c.read(conn)
+		}(conn)
+	}
+	return c
+}
+
+// ReadFromSCION reads the next packet received on any of the local addresses.
+func (c *MultiConn) ReadFromSCION(b []byte) (int, *Addr, error) {
+	select {
+	case <-c.closed:
+		return 0, nil, ErrMultiConnClosed
+	default:
+	}
+	select {
+	case pkt := <-c.queue:
+		if pkt.err != nil {
+			return 0, nil, pkt.err
+		}
+		return copy(b, pkt.pld), pkt.raddr, nil
+	case <-c.deadline.Wait():
+		return 0, nil, ErrDeadlineExceeded
+	case <-c.closed:
+		return 0, nil, ErrMultiConnClosed
+	}
+}
+
+func (c *MultiConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	n, raddr, err := c.ReadFromSCION(b)
+	if raddr == nil {
+		return n, nil, err
+	}
+	return n, raddr, err
+}
+
+// WriteToSCION sends b to raddr from the local address over which the next
+// hop to raddr is reached. If raddr does not contain a path, the path is
+// resolved first.
+func (c *MultiConn) WriteToSCION(b []byte, raddr *Addr) (int, error) {
+	if raddr == nil {
+		return 0, common.NewBasicError(ErrAddressIsNil, nil)
+	}
+	if raddr.Host == nil {
+		return 0, common.NewBasicError(ErrNoApplicationAddress, nil)
+	}
+	path, nextHop, err := c.route(raddr)
+	if err != nil {
+		return 0, err
+	}
+	if nextHop == nil {
+		// The next hops of multicast SVC addresses are resolved when
+		// writing.
+		return c.conns[0].WriteToSCION(b, raddr)
+	}
+	return c.connFor(nextHop).WriteToVia(b, raddr, path, nextHop)
+}
+
+func (c *MultiConn) WriteTo(b []byte, raddr net.Addr) (int, error) {
+	sraddr, ok := raddr.(*Addr)
+	if !ok {
+		return 0, common.NewBasicError("Unable to write to non-SCION address", nil,
+			"addr", raddr)
+	}
+	return c.WriteToSCION(b, sraddr)
+}
+
+// route returns the path and next hop to raddr.
+func (c *MultiConn) route(raddr *Addr) (*spath.Path, *overlay.OverlayAddr, error) {
+	if raddr.Path != nil || raddr.NextHop != nil {
+		return raddr.Path, raddr.NextHop, nil
+	}
+	if c.localIA.Equal(raddr.IA) {
+		if svc, ok := raddr.Host.L3.(addr.HostSVC); ok && svc.IsMulticast() {
+			return nil, nil, nil
+		}
+		local, err := addOverlayFromScionAddress(raddr)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, local.NextHop, nil
+	}
+	ctx, cancelF := context.WithTimeout(context.Background(), DefaultPathQueryTimeout)
+	defer cancelF()
+	nextHop, path, _, err := c.pathSource.Get(ctx, c.localIA, raddr.IA, 0)
+	if err != nil {
+		return nil, nil, serrors.WithCode(common.NewBasicError(ErrPath, err),
+			serrors.CodeUnavailable)
+	}
+	return path, nextHop, nil
+}
+
+// connFor returns the connection on the local address over which nextHop is
+// reached. If no local address matches, the first connection is returned.
+func (c *MultiConn) connFor(nextHop *overlay.OverlayAddr) Conn {
+	src := c.sources.source(nextHop.L3().IP())
+	for i, local := range c.locals {
+		if local.Equal(src) {
+			return c.conns[i]
+		}
+	}
+	return c.conns[0]
+}
+
+// LocalAddr returns the first local address.
+func (c *MultiConn) LocalAddr() net.Addr {
+	return c.conns[0].LocalAddr()
+}
+
+// LocalAddrs returns all local addresses.
+func (c *MultiConn) LocalAddrs() []*Addr {
+	addrs := make([]*Addr, 0, len(c.conns))
+	for _, conn := range c.conns {
+		addrs = append(addrs, conn.LocalAddr().(*Addr).Copy())
+	}
+	return addrs
+}
+
+// Close closes the connections on all local addresses.
+func (c *MultiConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		for _, conn := range c.conns {
+			if closeErr := conn.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+	})
+	return err
+}
+
+func (c *MultiConn) SetDeadline(t time.Time) error {
+	c.deadline.Set(t)
+	return c.SetWriteDeadline(t)
+}
+
+func (c *MultiConn) SetReadDeadline(t time.Time) error {
+	c.deadline.Set(t)
+	return nil
+}
+
+func (c *MultiConn) SetWriteDeadline(t time.Time) error {
+	for _, conn := range c.conns {
+		if err := conn.SetWriteDeadline(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// read forwards the packets received on conn to the queue, until conn fails
+// or the MultiConn is closed. SCMP errors are forwarded like packets.
+func (c *MultiConn) read(conn Conn) {
+	buf := make([]byte, common.MaxMTU)
+	for {
+		n, raddr, err := conn.ReadFromSCION(buf)
+		pkt := multiPacket{raddr: raddr, err: err}
+		if err == nil {
+			pkt.pld = common.CloneByteSlice(buf[:n])
+		} else {
+			select {
+			case <-c.closed:
+				// The error is caused by closing the connection.
+				return
+			default:
+			}
+		}
+		select {
+		case c.queue <- pkt:
+		case <-c.closed:
+			return
+		}
+		if err != nil {
+			if _, ok := err.(*OpError); !ok {
+				log.Debug("[MultiConn] Stopped reading from local address",
+					"addr", conn.LocalAddr(), "err", err)
+				return
+			}
+		}
+	}
+}
+
+// sourceSelector determines the local IP address from which packets to a
+// destination are sent, and caches the results.
+type sourceSelector struct {
+	lookup func(dst net.IP) (net.IP, error)
+
+	mtx   sync.Mutex
+	cache map[string]sourceEntry
+}
+
+type sourceEntry struct {
+	src     net.IP
+	expires time.Time
+}
+
+func newSourceSelector(lookup func(dst net.IP) (net.IP, error)) *sourceSelector {
+	return &sourceSelector{
+		lookup: lookup,
+		cache:  make(map[string]sourceEntry),
+	}
+}
+
+// source returns the local IP address for dst, or nil if it cannot be
+// determined.
+func (s *sourceSelector) source(dst net.IP) net.IP {
+	key := dst.String()
+	now := time.Now()
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if entry, ok := s.cache[key]; ok && now.Before(entry.expires) {
+		return entry.src
+	}
+	src, err := s.lookup(dst)
+	if err != nil {
+		log.Debug("[MultiConn] Unable to determine source address", "dst", dst, "err", err)
+		return nil
+	}
+	s.cache[key] = sourceEntry{src: src, expires: now.Add(sourceCacheTTL)}
+	return src
+}
+
+// routeSource returns the local IP address that the routing table of the host
+// selects for packets to dst. Connecting a UDP socket does not send any
+// packets.
+func routeSource(dst net.IP) (net.IP, error) {
+	conn, err := net.DialUDP("udp", nil, &net.UDPAddr{IP: dst, Port: overlay.EndhostPort})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
@@ -0,0 +1,178 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/snet/internal/pathsource/mock_pathsource"
+	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestMultiConn(t *testing.T) {
+	localIA := xtest.MustParseIA("1-ff00:0:110")
+	remote := muxTestAddr("1-ff00:0:111", 40000)
+	ipA, ipB := net.IP{10, 0, 0, 1}, net.IP{10, 0, 1, 1}
+	routerA, routerB := net.IP{10, 0, 0, 2}, net.IP{10, 0, 1, 2}
+	lookup := func(dst net.IP) (net.IP, error) {
+		switch {
+		case dst.Equal(routerA):
+			return ipA, nil
+		case dst.Equal(routerB):
+			return ipB, nil
+		}
+		return nil, io.EOF
+	}
+	setup := func(ctrl *gomock.Controller) (*MultiConn, *fakeMultiConn, *fakeMultiConn,
+		*mock_pathsource.MockPathSource) {
+
+		paths := mock_pathsource.NewMockPathSource(ctrl)
+		connA, connB := newFakeMultiConn(localIA, ipA), newFakeMultiConn(localIA, ipB)
+		c := newMultiConn([]Conn{connA, connB}, localIA, paths)
+		c.sources = newSourceSelector(lookup)
+		return c, connA, connB, paths
+	}
+
+	t.Run("reads are merged", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		c, connA, connB, _ := setup(ctrl)
+		defer c.Close()
+		connA.reads <- fakeRead{raddr: remote, pld: []byte("a")}
+		multiTestRead(t, c, "a")
+		connB.reads <- fakeRead{raddr: remote, pld: []byte("b")}
+		multiTestRead(t, c, "b")
+	})
+	t.Run("writes use the local address of the egress router", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		c, connA, connB, paths := setup(ctrl)
+		defer c.Close()
+		path := &spath.Path{Raw: []byte{1}}
+		paths.EXPECT().Get(gomock.Any(), localIA, remote.IA, uint64(0)).Return(
+			multiTestNextHop(routerB), path, uint16(0), nil)
+		_, err := c.WriteTo([]byte("hello"), remote)
+		require.NoError(t, err)
+		w := <-connB.writes
+		assert.Equal(t, []byte("hello"), w.pld)
+		assert.Equal(t, path, w.raddr.Path)
+		assert.Len(t, connA.writes, 0)
+	})
+	t.Run("writes with next hop skip path resolution", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		c, connA, _, _ := setup(ctrl)
+		defer c.Close()
+		raddr := remote.Copy()
+		raddr.NextHop = multiTestNextHop(routerA)
+		_, err := c.WriteToSCION([]byte("hello"), raddr)
+		require.NoError(t, err)
+		w := <-connA.writes
+		assert.Equal(t, raddr.NextHop, w.raddr.NextHop)
+	})
+	t.Run("unknown source falls back to the first address", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		c, connA, _, paths := setup(ctrl)
+		defer c.Close()
+		paths.EXPECT().Get(gomock.Any(), localIA, remote.IA, uint64(0)).Return(
+			multiTestNextHop(net.IP{192, 168, 0, 1}), &spath.Path{}, uint16(0), nil)
+		_, err := c.WriteToSCION([]byte("hello"), remote)
+		require.NoError(t, err)
+		<-connA.writes
+	})
+	t.Run("read deadline", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		c, _, _, _ := setup(ctrl)
+		defer c.Close()
+		require.NoError(t, c.SetReadDeadline(time.Now().Add(10*time.Millisecond)))
+		_, _, err := c.ReadFromSCION(make([]byte, 10))
+		assert.Equal(t, ErrDeadlineExceeded, err)
+	})
+	t.Run("close", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		c, connA, connB, _ := setup(ctrl)
+		require.NoError(t, c.Close())
+		_, _, err := c.ReadFromSCION(make([]byte, 10))
+		assert.Equal(t, ErrMultiConnClosed, err)
+		assert.True(t, connA.isClosed())
+		assert.True(t, connB.isClosed())
+	})
+}
+
+func multiTestRead(t *testing.T, c *MultiConn, expected string) {
+	t.Helper()
+	require.NoError(t, c.SetReadDeadline(time.Now().Add(time.Second)))
+	b := make([]byte, 10)
+	n, _, err := c.ReadFromSCION(b)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(b[:n]))
+}
+
+func multiTestNextHop(ip net.IP) *overlay.OverlayAddr {
+	nextHop, _ := overlay.NewOverlayAddr(addr.HostFromIP(ip),
+		addr.NewL4UDPInfo(overlay.EndhostPort))
+	return nextHop
+}
+
+// fakeMultiConn is a fakeMuxConn bound to a local IP address, which records
+// the writes with explicit path and next hop.
+type fakeMultiConn struct {
+	*fakeMuxConn
+	laddr *Addr
+}
+
+func newFakeMultiConn(ia addr.IA, ip net.IP) *fakeMultiConn {
+	return &fakeMultiConn{
+		fakeMuxConn: newFakeMuxConn(),
+		laddr: &Addr{
+			IA:   ia,
+			Host: &addr.AppAddr{L3: addr.HostFromIP(ip), L4: addr.NewL4UDPInfo(40000)},
+		},
+	}
+}
+
+func (c *fakeMultiConn) LocalAddr() net.Addr {
+	return c.laddr
+}
+
+func (c *fakeMultiConn) WriteToVia(b []byte, raddr *Addr, path *spath.Path,
+	nextHop *overlay.OverlayAddr) (int, error) {
+
+	raddr = raddr.Copy()
+	raddr.Path, raddr.NextHop = path, nextHop
+	return c.WriteToSCION(b, raddr)
+}
+
+func (c *fakeMultiConn) isClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,422 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet/internal/deadline"
+)
+
+const (
+	// DefaultMuxQueueSize is the default number of packets that are buffered
+	// per remote before packets are dropped.
+	DefaultMuxQueueSize = 32
+	// DefaultMuxAcceptBacklog is the default number of new remotes that are
+	// buffered until they are accepted.
+	DefaultMuxAcceptBacklog = 128
+	// DefaultMuxIdleTimeout is the default duration after which connections
+	// without traffic are closed.
+	DefaultMuxIdleTimeout = 5 * time.Minute
+
+	// minMuxIdleCheckInterval bounds how often idle connections are checked
+	// for, such that tiny idle timeouts do not result in a busy loop.
+	minMuxIdleCheckInterval = time.Millisecond
+)
+
+var (
+	// ErrMuxClosed is returned by operations on a closed ConnMux, or on a
+	// connection whose ConnMux has been closed.
+	ErrMuxClosed = serrors.New("connection multiplexer closed")
+	// ErrDeadlineExceeded is returned by reads on a multiplexed connection
+	// after the read deadline has passed. It is a net.Error that reports a
+	// timeout.
+	ErrDeadlineExceeded net.Error = deadlineError{}
+)
+
+// ConnMuxConfig configures a ConnMux.
+type ConnMuxConfig struct {
+	// QueueSize is the number of packets that are buffered per remote. If the
+	// queue of a remote is full, further packets from the remote are dropped.
+	// If it is 0, DefaultMuxQueueSize is used. It must not be negative.
+	QueueSize int
+	// AcceptBacklog is the number of new remotes that are buffered until they
+	// are accepted. If the backlog is full, packets from further new remotes
+	// are dropped. If it is 0, DefaultMuxAcceptBacklog is used. It must not
+	// be negative.
+	AcceptBacklog int
+	// IdleTimeout is the duration after which a connection that has neither
+	// received nor sent a packet is closed and forgotten. If the remote sends
+	// further packets, a new connection is returned by Accept. If it is 0,
+	// DefaultMuxIdleTimeout is used. It must not be negative.
+	IdleTimeout time.Duration
+}
+
+func (cfg *ConnMuxConfig) validate() error {
+	if cfg.QueueSize < 0 {
+		return serrors.New("Queue size must not be negative", "queue_size", cfg.QueueSize)
+	}
+	if cfg.AcceptBacklog < 0 {
+		return serrors.New("Accept backlog must not be negative",
+			"accept_backlog", cfg.AcceptBacklog)
+	}
+	if cfg.IdleTimeout < 0 {
+		return serrors.New("Idle timeout must not be negative",
+			"idle_timeout", cfg.IdleTimeout)
+	}
+	return nil
+}
+
+// ConnMux multiplexes a single SCION connection, and thus a single dispatcher
+// registration, over many remotes. It hands out a lightweight net.Conn per
+// remote address, and delivers each received packet to the connection of
+// its sender. Servers with many clients can use this to avoid registering a
+// socket per client.
+//
+// Connections to remotes are created either explicitly with Dial, or
+// implicitly when a packet from an unknown remote is received; the latter are
+// returned by Accept. Remotes are identified by ISD-AS, host address and port.
+//
+// Connections are removed from the ConnMux when they are closed, or when they
+// have been idle for the configured idle timeout.
+//
+// SCMP errors read from the underlying connection are logged and dropped, as
+// they cannot reliably be attributed to a remote.
+type ConnMux struct {
+	conn        Conn
+	queueSize   int
+	idleTimeout time.Duration
+	accept      chan *MuxConn
+
+	mtx    sync.Mutex
+	conns  map[string]*MuxConn
+	closed chan struct{}
+	err    error
+}
+
+// NewConnMux creates a ConnMux on top of conn, which must not have a fixed
+// remote address (e.g., a connection returned by ListenSCION). The ConnMux
+// takes ownership of conn and starts reading from it immediately. If cfg is
+// invalid, an error is returned and conn is left untouched.
+func NewConnMux(conn Conn, cfg ConnMuxConfig) (*ConnMux, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if cfg.QueueSize == 0 {
+		cfg.QueueSize = DefaultMuxQueueSize
+	}
+	if cfg.AcceptBacklog == 0 {
+		cfg.AcceptBacklog = DefaultMuxAcceptBacklog
+	}
+	if cfg.IdleTimeout == 0 {
+		cfg.IdleTimeout = DefaultMuxIdleTimeout
+	}
+	m := &ConnMux{
+		conn:        conn,
+		queueSize:   cfg.QueueSize,
+		idleTimeout: cfg.IdleTimeout,
+		accept:      make(chan *MuxConn, cfg.AcceptBacklog),
+		conns:       make(map[string]*MuxConn),
+		closed:      make(chan struct{}),
+	}
+	go func() {
+		defer log.LogPanicAndExit()
+		m.run()
+	}()
+	go func() {
+		defer log.LogPanicAndExit()
+		m.expireIdle()
+	}()
+	return m, nil
+}
+
+// Dial returns the connection to raddr. If a connection to raddr already
+// exists, it is returned instead of creating a new one. If raddr contains a
+// path, it is used for writes until a packet from raddr is received.
+func (m *ConnMux) Dial(raddr *Addr) (net.Conn, error) {
+	if raddr == nil || raddr.Host == nil {
+		return nil, serrors.New("remote address required")
+	}
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.isClosed() {
+		return nil, ErrMuxClosed
+	}
+	if c, ok := m.conns[raddr.String()]; ok {
+		return c, nil
+	}
+	return m.newConn(raddr), nil
+}
+
+// Accept waits for and returns the connection of the next remote that sent
+// a packet without having been dialed.
+func (m *ConnMux) Accept() (net.Conn, error) {
+	select {
+	case c := <-m.accept:
+		return c, nil
+	case <-m.closed:
+		return nil, m.closeErr()
+	}
+}
+
+// LocalAddr returns the local address of the underlying connection.
+func (m *ConnMux) LocalAddr() net.Addr {
+	return m.conn.LocalAddr()
+}
+
+// Close closes the underlying connection and all multiplexed connections.
+func (m *ConnMux) Close() error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.isClosed() {
+		return nil
+	}
+	m.closeLocked(ErrMuxClosed)
+	return m.conn.Close()
+}
+
+func (m *ConnMux) run() {
+	buf := make([]byte, common.MaxMTU)
+	for {
+		n, raddr, err := m.conn.ReadFromSCION(buf)
+		if err != nil {
+			if _, ok := err.(*OpError); ok {
+				log.Debug("[ConnMux] Dropping SCMP error", "err", err)
+				continue
+			}
+			m.mtx.Lock()
+			defer m.mtx.Unlock()
+			if !m.isClosed() {
+				m.closeLocked(common.NewBasicError("Unable to read from connection", err))
+				m.conn.Close()
+			}
+			return
+		}
+		m.deliver(raddr, common.CloneByteSlice(buf[:n]))
+	}
+}
+
+// expireIdle periodically closes and removes the connections that have been
+// idle for longer than the idle timeout, until the multiplexer is closed.
+func (m *ConnMux) expireIdle() {
+	interval := m.idleTimeout / 2
+	if interval < minMuxIdleCheckInterval {
+		interval = minMuxIdleCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			m.removeIdle(now)
+		case <-m.closed:
+			return
+		}
+	}
+}
+
+func (m *ConnMux) removeIdle(now time.Time) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	for key, c := range m.conns {
+		if now.Sub(c.lastActive()) > m.idleTimeout {
+			c.closeOnce.Do(func() { close(c.closed) })
+			delete(m.conns, key)
+		}
+	}
+}
+
+func (m *ConnMux) deliver(raddr *Addr, pld []byte) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.isClosed() {
+		return
+	}
+	c, ok := m.conns[raddr.String()]
+	if !ok {
+		if len(m.accept) == cap(m.accept) {
+			log.Debug("[ConnMux] Accept backlog full, dropping packet", "remote", raddr)
+			return
+		}
+		c = m.newConn(raddr)
+		m.accept <- c
+	}
+	c.setRemote(raddr)
+	select {
+	case c.queue <- pld:
+	default:
+		log.Debug("[ConnMux] Queue full, dropping packet", "remote", raddr)
+	}
+}
+
+// newConn creates and registers a connection for raddr. The caller must hold
+// the lock.
+func (m *ConnMux) newConn(raddr *Addr) *MuxConn {
+	c := &MuxConn{
+		mux:      m,
+		key:      raddr.String(),
+		raddr:    raddr.Copy(),
+		queue:    make(chan []byte, m.queueSize),
+		closed:   make(chan struct{}),
+		deadline: deadline.New(),
+		active:   time.Now(),
+	}
+	m.conns[c.key] = c
+	return c
+}
+
+func (m *ConnMux) remove(c *MuxConn) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.conns[c.key] == c {
+		delete(m.conns, c.key)
+	}
+}
+
+// closeLocked closes the multiplexer and all connections. The caller must
+// hold the lock.
+func (m *ConnMux) closeLocked(err error) {
+	m.err = err
+	close(m.closed)
+	for _, c := range m.conns {
+		c.closeOnce.Do(func() { close(c.closed) })
+	}
+	m.conns = make(map[string]*MuxConn)
+}
+
+func (m *ConnMux) isClosed() bool {
+	return isClosedChan(m.closed)
+}
+
+func (m *ConnMux) closeErr() error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.err
+}
+
+type deadlineError struct{}
+
+func (deadlineError) Error() string   { return "deadline exceeded" }
+func (deadlineError) Timeout() bool   { return true }
+func (deadlineError) Temporary() bool { return true }
+
+var _ net.Conn = (*MuxConn)(nil)
+
+// MuxConn is a connection to a single remote that is multiplexed by a
+// ConnMux.
+type MuxConn struct {
+	mux   *ConnMux
+	key   string
+	queue chan []byte
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	deadline  *deadline.Deadline
+
+	mtx    sync.Mutex
+	raddr  *Addr
+	active time.Time
+}
+
+// Read reads the payload of the next packet from the remote into b. If b is
+// too small for the payload, the remainder is discarded.
+func (c *MuxConn) Read(b []byte) (int, error) {
+	select {
+	case pld := <-c.queue:
+		return copy(b, pld), nil
+	case <-c.deadline.Wait():
+		return 0, ErrDeadlineExceeded
+	case <-c.closed:
+		return 0, ErrMuxClosed
+	}
+}
+
+// Write sends b to the remote.
+func (c *MuxConn) Write(b []byte) (int, error) {
+	if isClosedChan(c.closed) {
+		return 0, ErrMuxClosed
+	}
+	c.mtx.Lock()
+	raddr := c.raddr.Copy()
+	c.active = time.Now()
+	c.mtx.Unlock()
+	return c.mux.conn.WriteToSCION(b, raddr)
+}
+
+// Close closes the connection. The underlying connection and the other
+// connections of the ConnMux are not affected. If the remote sends further
+// packets, a new connection is returned by Accept.
+func (c *MuxConn) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	c.mux.remove(c)
+	return nil
+}
+
+func (c *MuxConn) LocalAddr() net.Addr {
+	return c.mux.conn.LocalAddr()
+}
+
+func (c *MuxConn) RemoteAddr() net.Addr {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.raddr.Copy()
+}
+
+// SetDeadline sets the read deadline. See SetWriteDeadline.
+func (c *MuxConn) SetDeadline(t time.Time) error {
+	return c.SetReadDeadline(t)
+}
+
+func (c *MuxConn) SetReadDeadline(t time.Time) error {
+	c.deadline.Set(t)
+	return nil
+}
+
+// SetWriteDeadline is a no-op, because writes share the underlying
+// connection with all other remotes.
+func (c *MuxConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+// setRemote updates the remote address, including the path, to the one of the
+// last received packet, and marks the connection as active.
+func (c *MuxConn) setRemote(raddr *Addr) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.raddr = raddr
+	c.active = time.Now()
+}
+
+// lastActive returns the time a packet was last received from or sent to the
+// remote.
+func (c *MuxConn) lastActive() time.Time {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.active
+}
+
+func isClosedChan(c <-chan struct{}) bool {
+	select {
+	case <-c:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestConnMux(t *testing.T) {
+	remoteA := muxTestAddr("1-ff00:0:111", 40000)
+	remoteB := muxTestAddr("1-ff00:0:112", 40000)
+	remoteC := muxTestAddr("1-ff00:0:112", 40001)
+
+	t.Run("packets from new remotes are accepted", func(t *testing.T) {
+		conn := newFakeMuxConn()
+		mux, err := NewConnMux(conn, ConnMuxConfig{})
+		require.NoError(t, err)
+		defer mux.Close()
+
+		conn.reads <- fakeRead{raddr: remoteA, pld: []byte("a1")}
+		conn.reads <- fakeRead{raddr: remoteB, pld: []byte("b1")}
+		conn.reads <- fakeRead{raddr: remoteA, pld: []byte("a2")}
+
+		connA, err := mux.Accept()
+		require.NoError(t, err)
+		assert.Equal(t, remoteA.String(), connA.RemoteAddr().String())
+		connB, err := mux.Accept()
+		require.NoError(t, err)
+		assert.Equal(t, remoteB.String(), connB.RemoteAddr().String())
+
+		muxTestRead(t, connA, "a1")
+		muxTestRead(t, connA, "a2")
+		muxTestRead(t, connB, "b1")
+	})
+	t.Run("packets from dialed remotes are not accepted", func(t *testing.T) {
+		conn := newFakeMuxConn()
+		mux, err := NewConnMux(conn, ConnMuxConfig{})
+		require.NoError(t, err)
+		defer mux.Close()
+
+		connC, err := mux.Dial(remoteC)
+		require.NoError(t, err)
+		conn.reads <- fakeRead{raddr: remoteC, pld: []byte("c1")}
+		muxTestRead(t, connC, "c1")
+		assert.Len(t, mux.accept, 0)
+
+		again, err := mux.Dial(remoteC)
+		require.NoError(t, err)
+		assert.Equal(t, connC, again)
+	})
+	t.Run("writes go to the remote", func(t *testing.T) {
+		conn := newFakeMuxConn()
+		mux, err := NewConnMux(conn, ConnMuxConfig{})
+		require.NoError(t, err)
+		defer mux.Close()
+
+		connA, err := mux.Dial(remoteA)
+		require.NoError(t, err)
+		n, err := connA.Write([]byte("hello"))
+		require.NoError(t, err)
+		assert.Equal(t, 5, n)
+		w := <-conn.writes
+		assert.Equal(t, []byte("hello"), w.pld)
+		assert.Equal(t, remoteA.String(), w.raddr.String())
+	})
+	t.Run("read deadline", func(t *testing.T) {
+		conn := newFakeMuxConn()
+		mux, err := NewConnMux(conn, ConnMuxConfig{})
+		require.NoError(t, err)
+		defer mux.Close()
+
+		connA, err := mux.Dial(remoteA)
+		require.NoError(t, err)
+		require.NoError(t, connA.SetReadDeadline(time.Now().Add(10*time.Millisecond)))
+		_, err = connA.Read(make([]byte, 10))
+		assert.Equal(t, ErrDeadlineExceeded, err)
+		assert.True(t, err.(net.Error).Timeout())
+
+		// Clearing the deadline makes reads succeed again.
+		require.NoError(t, connA.SetReadDeadline(time.Time{}))
+		conn.reads <- fakeRead{raddr: remoteA, pld: []byte("a1")}
+		muxTestRead(t, connA, "a1")
+	})
+	t.Run("close", func(t *testing.T) {
+		conn := newFakeMuxConn()
+		mux, err := NewConnMux(conn, ConnMuxConfig{})
+		require.NoError(t, err)
+
+		connA, err := mux.Dial(remoteA)
+		require.NoError(t, err)
+		require.NoError(t, mux.Close())
+		_, err = connA.Read(make([]byte, 10))
+		assert.Equal(t, ErrMuxClosed, err)
+		_, err = mux.Accept()
+		assert.Error(t, err)
+		_, err = mux.Dial(remoteB)
+		assert.Error(t, err)
+	})
+	t.Run("idle connections are removed", func(t *testing.T) {
+		conn := newFakeMuxConn()
+		mux, err := NewConnMux(conn, ConnMuxConfig{IdleTimeout: 20 * time.Millisecond})
+		require.NoError(t, err)
+		defer mux.Close()
+
+		connA, err := mux.Dial(remoteA)
+		require.NoError(t, err)
+		_, err = connA.Read(make([]byte, 10))
+		assert.Equal(t, ErrMuxClosed, err)
+		mux.mtx.Lock()
+		assert.Len(t, mux.conns, 0)
+		mux.mtx.Unlock()
+	})
+	t.Run("closed connections are removed", func(t *testing.T) {
+		conn := newFakeMuxConn()
+		mux, err := NewConnMux(conn, ConnMuxConfig{})
+		require.NoError(t, err)
+		defer mux.Close()
+
+		connA, err := mux.Dial(remoteA)
+		require.NoError(t, err)
+		require.NoError(t, connA.Close())
+		mux.mtx.Lock()
+		assert.Len(t, mux.conns, 0)
+		mux.mtx.Unlock()
+	})
+	t.Run("read error closes the multiplexer", func(t *testing.T) {
+		conn := newFakeMuxConn()
+		mux, err := NewConnMux(conn, ConnMuxConfig{})
+		require.NoError(t, err)
+		defer mux.Close()
+
+		conn.reads <- fakeRead{err: io.EOF}
+		_, err = mux.Accept()
+		assert.Error(t, err)
+	})
+	t.Run("negative config values are rejected", func(t *testing.T) {
+		for _, cfg := range []ConnMuxConfig{
+			{QueueSize: -1},
+			{AcceptBacklog: -1},
+			{IdleTimeout: -time.Second},
+		} {
+			_, err := NewConnMux(newFakeMuxConn(), cfg)
+			assert.Error(t, err, "config %+v", cfg)
+		}
+	})
+}
+
+func muxTestAddr(ia string, port uint16) *Addr {
+	return &Addr{
+		IA: xtest.MustParseIA(ia),
+		Host: &addr.AppAddr{
+			L3: addr.HostFromIP(net.IP{127, 0, 0, 1}),
+			L4: addr.NewL4UDPInfo(port),
+		},
+	}
+}
+
+func muxTestRead(t *testing.T, c net.Conn, expected string) {
+	t.Helper()
+	require.NoError(t, c.SetReadDeadline(time.Now().Add(time.Second)))
+	b := make([]byte, 10)
+	n, err := c.Read(b)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(b[:n]))
+}
+
+type fakeRead struct {
+	raddr *Addr
+	pld   []byte
+	err   error
+}
+
+type fakeWrite struct {
+	raddr *Addr
+	pld   []byte
+}
+
+// fakeMuxConn is a Conn that returns the reads sent on the reads channel and
+// records the writes on the writes channel.
+type fakeMuxConn struct {
+	Conn
+	reads  chan fakeRead
+	writes chan fakeWrite
+	closed chan struct{}
+}
+
+func newFakeMuxConn() *fakeMuxConn {
+	return &fakeMuxConn{
+		reads:  make(chan fakeRead, 10),
+		writes: make(chan fakeWrite, 10),
+		closed: make(chan struct{}),
+	}
+}
+
+func (c *fakeMuxConn) ReadFromSCION(b []byte) (int, *Addr, error) {
+	select {
+	case r := <-c.reads:
+		return copy(b, r.pld), r.raddr, r.err
+	case <-c.closed:
+		return 0, nil, io.EOF
+	}
+}
+
+func (c *fakeMuxConn) WriteToSCION(b []byte, raddr *Addr) (int, error) {
+	c.writes <- fakeWrite{raddr: raddr, pld: append([]byte(nil), b...)}
+	return len(b), nil
+}
+
+func (c *fakeMuxConn) Close() error {
+	close(c.closed)
+	return nil
+}
@@ -0,0 +1,249 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/pathmgr"
+	"github.com/scionproto/scion/go/lib/prom"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/snet/internal/pathsource"
+	"github.com/scionproto/scion/go/lib/sock/reliable"
+)
+
+// NetworkConfig configures a SCION networking context, see
+// NewNetworkFromConfig.
+type NetworkConfig struct {
+	// IA is the ISD-AS of the local AS.
+	IA addr.IA
+	// SciondPath is the path to the SCIOND socket. If it is empty, the network
+	// runs without SCIOND, and the application is responsible for supplying
+	// paths for sent traffic.
+	SciondPath string
+	// SciondConnectTimeout is the time allowed to establish the initial
+	// connection to SCIOND. A timeout of 0 means infinite timeout.
+	SciondConnectTimeout time.Duration
+	// SciondNoReconnect disables reconnecting to SCIOND. By default, a new
+	// connection to SCIOND is established for every request, such that SCIOND
+	// restarts are tolerated. If set, a single connection is used instead.
+	SciondNoReconnect bool
+	// Timers are the timers of the path resolver. Timers that are left
+	// uninitialized are set to DefaultPathRefire and DefaultPathErrorRefire.
+	Timers pathmgr.Timers
+	// PrewarmDsts are the destination ISD-ASes whose paths are fetched in the
+	// background when the network is created, such that the first packets to
+	// them do not wait for a path lookup, see pathmgr.Resolver.Prewarm. It is
+	// ignored if the network runs without SCIOND.
+	PrewarmDsts []addr.IA
+	// Dispatcher is used to register sockets with the dispatcher. It is
+	// ignored if PacketDispatcher is set.
+	Dispatcher reliable.DispatcherService
+	// PacketDispatcher gives control over the packet processing socket, see
+	// NewCustomNetwork. If it is nil, a DefaultPacketDispatcherService on top
+	// of Dispatcher is used.
+	PacketDispatcher PacketDispatcherService
+	// SocketOptions, if set, are applied to the sockets registered with
+	// Dispatcher. It is ignored if PacketDispatcher is set, in which case the
+	// options have to be configured on the PacketDispatcherService.
+	SocketOptions *SocketOptions
+	// PortRange, if set, restricts the ports of sockets that do not request a
+	// specific port to the range, see DefaultPacketDispatcherService. It is
+	// ignored if PacketDispatcher is set.
+	PortRange *PortRange
+	// Metrics is the registry with which the metrics of the path requests to
+	// SCIOND are registered. If it is nil, no metrics are recorded.
+	Metrics prometheus.Registerer
+	// Interceptors are applied to all sockets of the network, irrespective of
+	// whether PacketDispatcher is set, see NewInterceptedPacketConn.
+	Interceptors []Interceptor
+	// DeliverRevocations disables the automatic processing of SCMP
+	// revocations, see NewRevocationDeliveringSCMPHandler. It is ignored if
+	// PacketDispatcher is set.
+	DeliverRevocations bool
+	// RevocationPolicy configures the processing of SCMP revocations. It is
+	// ignored if PacketDispatcher is set or DeliverRevocations is true.
+	RevocationPolicy RevocationPolicy
+	// WriteRateLimit is the rate limit of the writes on the connections
+	// created by the network. It can be changed per connection with
+	// SetWriteRateLimit. The zero value does not limit writes.
+	WriteRateLimit RateLimit
+}
+
+// NewNetworkFromConfig creates a new networking context as configured by cfg.
+// It returns an error if SCIOND cannot be reached, if the metrics cannot be
+// registered, or if the port range is invalid.
+func NewNetworkFromConfig(cfg NetworkConfig) (*SCIONNetwork, error) {
+	if cfg.PortRange != nil {
+		if err := cfg.PortRange.Validate(); err != nil {
+			return nil, common.NewBasicError("Invalid port range", err)
+		}
+	}
+	pathResolver, err := cfg.pathResolver()
+	if err != nil {
+		return nil, err
+	}
+	var quarantine *pathsource.Quarantine
+	pktDispatcher := cfg.PacketDispatcher
+	if pktDispatcher == nil {
+		if cfg.RevocationPolicy.Quarantine > 0 {
+			quarantine = pathsource.NewQuarantine()
+		}
+		pktDispatcher = &DefaultPacketDispatcherService{
+			Dispatcher: cfg.Dispatcher,
+			SCMPHandler: &scmpHandler{
+				pathResolver:       pathResolver,
+				deliverRevocations: cfg.DeliverRevocations,
+				policy:             cfg.RevocationPolicy,
+				quarantine:         quarantine,
+				pending:            make(chan struct{}, maxPendingRevVerifications),
+			},
+			SocketOptions: cfg.SocketOptions,
+			PortRange:     cfg.PortRange,
+		}
+	}
+	if len(cfg.Interceptors) > 0 {
+		pktDispatcher = &InterceptingPacketDispatcherService{
+			Dispatcher:   pktDispatcher,
+			Interceptors: cfg.Interceptors,
+		}
+	}
+	n := NewCustomNetworkWithPR(cfg.IA, pktDispatcher, pathResolver)
+	n.quarantine = quarantine
+	n.writeRateLimit = cfg.WriteRateLimit
+	return n, nil
+}
+
+// pathResolver builds the path resolver of the network. It returns nil if the
+// network runs without SCIOND.
+func (cfg NetworkConfig) pathResolver() (pathmgr.Resolver, error) {
+	if cfg.SciondPath == "" {
+		return nil, nil
+	}
+	sciondConn, err := sciond.NewService(cfg.SciondPath, !cfg.SciondNoReconnect).
+		ConnectTimeout(cfg.SciondConnectTimeout)
+	if err != nil {
+		return nil, common.NewBasicError("Unable to initialize SCIOND service", err)
+	}
+	if cfg.Metrics != nil {
+		m, err := newSciondMetrics(cfg.Metrics)
+		if err != nil {
+			return nil, common.NewBasicError("Unable to register metrics", err)
+		}
+		sciondConn = &instrumentedConnector{Connector: sciondConn, metrics: m}
+	}
+	timers := cfg.Timers
+	if timers.NormalRefire == 0 {
+		timers.NormalRefire = DefaultPathRefire
+	}
+	if timers.ErrorRefire == 0 {
+		timers.ErrorRefire = DefaultPathErrorRefire
+	}
+	resolver := pathmgr.New(sciondConn, timers)
+	if len(cfg.PrewarmDsts) > 0 {
+		go func() {
+			defer log.LogPanicAndExit()
+			ctx, cancelF := context.WithTimeout(context.Background(),
+				pathmgr.DefaultQueryTimeout)
+			defer cancelF()
+			resolver.Prewarm(ctx, cfg.IA, cfg.PrewarmDsts)
+		}()
+	}
+	return resolver, nil
+}
+
+// Result values of the path request metrics.
+const (
+	pathReqSuccess    = prom.Success
+	pathReqErrTimeout = prom.ErrTimeout
+	pathReqErrConn    = "err_conn"
+	pathReqErrSciond  = "err_sciond"
+)
+
+type sciondMetrics struct {
+	requests *prometheus.CounterVec
+	latency  prometheus.Histogram
+}
+
+// newSciondMetrics creates the path request metrics and registers them with
+// reg. If the metrics are already registered, e.g., by another network using
+// the same registry, the existing metrics are shared.
+func newSciondMetrics(reg prometheus.Registerer) (*sciondMetrics, error) {
+	requests := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "snet",
+			Subsystem: "sciond",
+			Name:      "path_requests_total",
+			Help:      "Number of path requests to SCIOND, by result.",
+		},
+		[]string{prom.LabelResult},
+	)
+	latency := prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "snet",
+			Subsystem: "sciond",
+			Name:      "path_request_duration_seconds",
+			Help:      "Duration of path requests to SCIOND.",
+			Buckets:   prom.DefaultLatencyBuckets,
+		},
+	)
+	if err := reg.Register(requests); err != nil {
+		existing, ok := err.(prometheus.AlreadyRegisteredError)
+		if !ok {
+			return nil, err
+		}
+		requests = existing.ExistingCollector.(*prometheus.CounterVec)
+	}
+	if err := reg.Register(latency); err != nil {
+		existing, ok := err.(prometheus.AlreadyRegisteredError)
+		if !ok {
+			return nil, err
+		}
+		latency = existing.ExistingCollector.(prometheus.Histogram)
+	}
+	return &sciondMetrics{requests: requests, latency: latency}, nil
+}
+
+// instrumentedConnector records metrics about the path requests to SCIOND.
+// All other requests are passed through.
+type instrumentedConnector struct {
+	sciond.Connector
+	metrics *sciondMetrics
+}
+
+func (c *instrumentedConnector) Paths(ctx context.Context, dst, src addr.IA, max uint16,
+	f sciond.PathReqFlags) (*sciond.PathReply, error) {
+
+	start := time.Now()
+	reply, err := c.Connector.Paths(ctx, dst, src, max, f)
+	c.metrics.latency.Observe(time.Since(start).Seconds())
+	result := pathReqSuccess
+	switch {
+	case err != nil && common.IsTimeoutErr(err):
+		result = pathReqErrTimeout
+	case err != nil:
+		result = pathReqErrConn
+	case reply.ErrorCode != sciond.ErrorOk:
+		result = pathReqErrSciond
+	}
+	c.metrics.requests.WithLabelValues(result).Inc()
+	return reply, err
+}
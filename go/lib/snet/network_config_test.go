@@ -0,0 +1,87 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/sciond/mock_sciond"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestNewNetworkFromConfigWithoutSciond(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	network, err := NewNetworkFromConfig(NetworkConfig{IA: ia, Metrics: prometheus.NewRegistry()})
+	require.NoError(t, err)
+	assert.Nil(t, network.PathResolver())
+	assert.Equal(t, ia, network.IA())
+}
+
+func TestInstrumentedConnector(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	reg := prometheus.NewRegistry()
+	m, err := newSciondMetrics(reg)
+	require.NoError(t, err)
+	// Registering again, e.g., for a second network, shares the metrics.
+	_, err = newSciondMetrics(reg)
+	require.NoError(t, err)
+
+	sd := mock_sciond.NewMockConnector(ctrl)
+	gomock.InOrder(
+		sd.EXPECT().Paths(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any()).Return(&sciond.PathReply{ErrorCode: sciond.ErrorOk}, nil),
+		sd.EXPECT().Paths(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any()).Return(&sciond.PathReply{ErrorCode: sciond.ErrorNoPaths}, nil),
+		sd.EXPECT().Paths(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any()).Return(nil, errors.New("conn closed")),
+	)
+	conn := &instrumentedConnector{Connector: sd, metrics: m}
+	for i := 0; i < 3; i++ {
+		conn.Paths(context.Background(), xtest.MustParseIA("1-ff00:0:110"),
+			xtest.MustParseIA("1-ff00:0:111"), 0, sciond.PathReqFlags{})
+	}
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	results := make(map[string]float64)
+	var observed uint64
+	for _, family := range families {
+		switch family.GetName() {
+		case "snet_sciond_path_requests_total":
+			for _, metric := range family.GetMetric() {
+				results[metric.GetLabel()[0].GetValue()] = metric.GetCounter().GetValue()
+			}
+		case "snet_sciond_path_request_duration_seconds":
+			observed = family.GetMetric()[0].GetHistogram().GetSampleCount()
+		}
+	}
+	expected := map[string]float64{
+		pathReqSuccess:   1,
+		pathReqErrSciond: 1,
+		pathReqErrConn:   1,
+	}
+	assert.Equal(t, expected, results)
+	assert.Equal(t, uint64(3), observed)
+}
@@ -0,0 +1,188 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+// ErrPacingTimeout is returned by writes that cannot be sent within the write
+// deadline without exceeding the rate limit of the connection.
+const ErrPacingTimeout = "write deadline exceeded while pacing"
+
+// DefaultRateLimitBurst is the burst of a RateLimit that does not specify
+// one.
+const DefaultRateLimitBurst = 10 * time.Millisecond
+
+// RateLimit limits the rate at which a connection writes packets. Writes that
+// would exceed the limit are delayed until they conform to it, such that bulk
+// transfers do not overrun constrained links close to the sender. The zero
+// value does not limit writes.
+type RateLimit struct {
+	// BytesPerSecond is the maximum number of bytes written per second,
+	// counting the full size of the SCION packets. A value of 0 or less means
+	// unlimited.
+	BytesPerSecond int
+	// PacketsPerSecond is the maximum number of packets written per second. A
+	// value of 0 or less means unlimited.
+	PacketsPerSecond int
+	// Burst is the amount of traffic, expressed as time at the configured
+	// rates, that can be written back-to-back by a connection that was idle.
+	// If it is 0, DefaultRateLimitBurst is used.
+	Burst time.Duration
+}
+
+// Unlimited returns whether the rate limit does not limit writes.
+func (l RateLimit) Unlimited() bool {
+	return l.BytesPerSecond <= 0 && l.PacketsPerSecond <= 0
+}
+
+// pacer delays writes such that they conform to a rate limit. Each write
+// reserves tokens from a bucket for bytes and a bucket for packets. Buckets
+// may go into debt, such that packets larger than the burst size are
+// eventually sent. It is safe for concurrent use. A nil pacer does not delay
+// writes.
+type pacer struct {
+	mtx      sync.Mutex
+	limit    RateLimit
+	bytes    tokenBucket
+	packets  tokenBucket
+	deadline time.Time
+}
+
+func newPacer(limit RateLimit) *pacer {
+	p := &pacer{}
+	p.setLimit(limit, time.Now())
+	return p
+}
+
+// setLimit replaces the rate limit. The buckets start out full.
+func (p *pacer) setLimit(limit RateLimit, now time.Time) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	burst := limit.Burst
+	if burst == 0 {
+		burst = DefaultRateLimitBurst
+	}
+	p.limit = limit
+	p.bytes = newTokenBucket(float64(limit.BytesPerSecond), burst, now)
+	p.packets = newTokenBucket(float64(limit.PacketsPerSecond), burst, now)
+}
+
+func (p *pacer) getLimit() RateLimit {
+	if p == nil {
+		return RateLimit{}
+	}
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return p.limit
+}
+
+func (p *pacer) setDeadline(t time.Time) {
+	if p == nil {
+		return
+	}
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.deadline = t
+}
+
+// wait blocks until a packet of size bytes can be written without exceeding
+// the rate limit. It returns an error if that is not possible before the write
+// deadline.
+func (p *pacer) wait(size int) error {
+	if p == nil {
+		return nil
+	}
+	delay, err := p.reserve(time.Now(), size)
+	if err != nil || delay == 0 {
+		return err
+	}
+	time.Sleep(delay)
+	return nil
+}
+
+// reserve takes the tokens for a packet of size bytes from the buckets and
+// returns how long the packet has to be delayed. If the delay exceeds the
+// write deadline, no tokens are taken and an error is returned.
+func (p *pacer) reserve(now time.Time, size int) (time.Duration, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.limit.Unlimited() {
+		return 0, nil
+	}
+	delay := p.bytes.take(now, float64(size))
+	if d := p.packets.take(now, 1); d > delay {
+		delay = d
+	}
+	if !p.deadline.IsZero() && now.Add(delay).After(p.deadline) {
+		p.bytes.give(float64(size))
+		p.packets.give(1)
+		return 0, serrors.WithCode(serrors.New(ErrPacingTimeout, "delay", delay),
+			serrors.CodeTimeout)
+	}
+	return delay, nil
+}
+
+// tokenBucket is a token bucket that is refilled continuously at rate tokens
+// per second. A bucket with a rate of 0 or less never runs out of tokens.
+type tokenBucket struct {
+	rate     float64
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+func newTokenBucket(rate float64, burst time.Duration, now time.Time) tokenBucket {
+	if rate <= 0 {
+		return tokenBucket{}
+	}
+	capacity := rate * burst.Seconds()
+	if capacity < 1 {
+		capacity = 1
+	}
+	return tokenBucket{rate: rate, capacity: capacity, tokens: capacity, last: now}
+}
+
+// take removes n tokens from the bucket and returns the time until the bucket
+// is out of debt.
+func (b *tokenBucket) take(now time.Time, n float64) time.Duration {
+	if b.rate <= 0 {
+		return 0
+	}
+	if now.After(b.last) {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+		b.last = now
+	}
+	b.tokens -= n
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// give returns n tokens to the bucket.
+func (b *tokenBucket) give(n float64) {
+	if b.rate <= 0 {
+		return
+	}
+	b.tokens += n
+}
@@ -0,0 +1,112 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+func TestPacerReserve(t *testing.T) {
+	now := time.Now()
+	tests := map[string]struct {
+		Limit  RateLimit
+		Sizes  []int
+		Delays []time.Duration
+	}{
+		"unlimited": {
+			Sizes:  []int{1000, 1000, 1000},
+			Delays: []time.Duration{0, 0, 0},
+		},
+		"bytes within burst": {
+			Limit:  RateLimit{BytesPerSecond: 100000, Burst: 20 * time.Millisecond},
+			Sizes:  []int{1000, 1000},
+			Delays: []time.Duration{0, 0},
+		},
+		"bytes exceeding burst": {
+			Limit:  RateLimit{BytesPerSecond: 100000, Burst: 10 * time.Millisecond},
+			Sizes:  []int{1000, 1000, 500},
+			Delays: []time.Duration{0, 10 * time.Millisecond, 15 * time.Millisecond},
+		},
+		"packet larger than burst": {
+			Limit:  RateLimit{BytesPerSecond: 1000, Burst: time.Millisecond},
+			Sizes:  []int{101},
+			Delays: []time.Duration{100 * time.Millisecond},
+		},
+		"packets": {
+			Limit:  RateLimit{PacketsPerSecond: 100},
+			Sizes:  []int{1000, 1000, 1000},
+			Delays: []time.Duration{0, 10 * time.Millisecond, 20 * time.Millisecond},
+		},
+		"stricter limit applies": {
+			Limit:  RateLimit{BytesPerSecond: 100000, PacketsPerSecond: 100},
+			Sizes:  []int{500, 500, 3000},
+			Delays: []time.Duration{0, 10 * time.Millisecond, 30 * time.Millisecond},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := &pacer{}
+			p.setLimit(test.Limit, now)
+			for i, size := range test.Sizes {
+				delay, err := p.reserve(now, size)
+				require.NoError(t, err)
+				assert.InDelta(t, test.Delays[i], delay, float64(time.Microsecond), "write %d", i)
+			}
+		})
+	}
+	t.Run("tokens are refilled over time", func(t *testing.T) {
+		p := &pacer{}
+		p.setLimit(RateLimit{PacketsPerSecond: 100, Burst: 20 * time.Millisecond}, now)
+		for i := 0; i < 2; i++ {
+			delay, err := p.reserve(now, 1)
+			require.NoError(t, err)
+			assert.Zero(t, delay)
+		}
+		delay, err := p.reserve(now.Add(10*time.Millisecond), 1)
+		require.NoError(t, err)
+		assert.Zero(t, delay)
+		delay, err = p.reserve(now.Add(10*time.Millisecond), 1)
+		require.NoError(t, err)
+		assert.Equal(t, 10*time.Millisecond, delay)
+	})
+	t.Run("deadline exceeded", func(t *testing.T) {
+		p := &pacer{}
+		p.setLimit(RateLimit{PacketsPerSecond: 100}, now)
+		p.setDeadline(now.Add(5 * time.Millisecond))
+		_, err := p.reserve(now, 1)
+		require.NoError(t, err)
+		_, err = p.reserve(now, 1)
+		assert.Contains(t, err.Error(), ErrPacingTimeout)
+		assert.True(t, serrors.IsTimeout(err))
+		// The failed write does not take any tokens.
+		p.setDeadline(time.Time{})
+		delay, err := p.reserve(now, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 10*time.Millisecond, delay)
+	})
+}
+
+func TestPacerNil(t *testing.T) {
+	var p *pacer
+	p.setDeadline(time.Now())
+	assert.NoError(t, p.wait(1000))
+	assert.Equal(t, RateLimit{}, p.getLimit())
+}
@@ -19,12 +19,15 @@ import (
 	"sort"
 	"time"
 
+	"golang.org/x/xerrors"
+
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/hpkt"
 	"github.com/scionproto/scion/go/lib/l4"
 	"github.com/scionproto/scion/go/lib/overlay"
 	"github.com/scionproto/scion/go/lib/scmp"
+	"github.com/scionproto/scion/go/lib/sock/reliable"
 	"github.com/scionproto/scion/go/lib/spath"
 	"github.com/scionproto/scion/go/lib/spkt"
 )
@@ -107,6 +110,11 @@ type SCIONPacketInfo struct {
 	// L4Header contains L4 header information.
 	L4Header l4.L4Header
 	Payload  common.Payload
+	// Timestamp is the time at which the packet was received by the host, as
+	// reported by the dispatcher. It is set when reading, and is zero if the
+	// dispatcher does not support receive timestamps. It is ignored when
+	// writing.
+	Timestamp time.Time
 }
 
 // SCIONAddress is the fully-specified address of a host.
@@ -189,6 +197,9 @@ func (c *SCIONPacketConn) ReadFrom(pkt *SCIONPacket, ov *overlay.OverlayAddr) er
 					"scmp.Hdr", scmpHdr, "src", pkt.Source)
 			}
 			if err := c.scmpHandler.Handle(pkt); err != nil {
+				if xerrors.Is(err, ErrDeliverSCMP) {
+					return nil
+				}
 				// Return error intact s.t. applications can handle custom
 				// error types returned by SCMP handlers.
 				return err
@@ -203,7 +214,7 @@ func (c *SCIONPacketConn) ReadFrom(pkt *SCIONPacket, ov *overlay.OverlayAddr) er
 
 func (c *SCIONPacketConn) readFrom(pkt *SCIONPacket, ov *overlay.OverlayAddr) error {
 	pkt.Prepare()
-	n, lastHopNetAddr, err := c.conn.ReadFrom(pkt.Bytes)
+	n, lastHopNetAddr, timestamp, err := readFromWithTimestamp(c.conn, pkt.Bytes)
 	if err != nil {
 		return common.NewBasicError("Reliable socket read error", err)
 	}
@@ -231,14 +242,29 @@ func (c *SCIONPacketConn) readFrom(pkt *SCIONPacket, ov *overlay.OverlayAddr) er
 	pkt.Destination = SCIONAddress{IA: scnPkt.DstIA, Host: scnPkt.DstHost}
 	pkt.Source = SCIONAddress{IA: scnPkt.SrcIA, Host: scnPkt.SrcHost}
 	pkt.Path = scnPkt.Path
+	// Reset the extensions so that packets reused across reads (e.g., after an
+	// SCMP message was consumed by the handler) do not accumulate stale
+	// extensions.
+	pkt.Extensions = pkt.Extensions[:0]
 	pkt.Extensions = append(pkt.Extensions, scnPkt.HBHExt...)
 	pkt.Extensions = append(pkt.Extensions, scnPkt.E2EExt...)
 	pkt.L4Header = scnPkt.L4
 	pkt.Payload = scnPkt.Pld
+	pkt.Timestamp = timestamp
 	*ov = *lastHop
 	return nil
 }
 
+// readFromWithTimestamp reads a message from conn. The returned timestamp is
+// zero if conn does not report receive timestamps.
+func readFromWithTimestamp(conn net.PacketConn, b []byte) (int, net.Addr, time.Time, error) {
+	if tr, ok := conn.(reliable.TimestampReader); ok {
+		return tr.ReadFromWithTimestamp(b)
+	}
+	n, address, err := conn.ReadFrom(b)
+	return n, address, time.Time{}, err
+}
+
 func (c *SCIONPacketConn) SetReadDeadline(d time.Time) error {
 	return c.conn.SetReadDeadline(d)
 }
@@ -0,0 +1,99 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/l4"
+	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+// rawConn records the last written message, and returns it on every read.
+type rawConn struct {
+	net.PacketConn
+	raw     []byte
+	nextHop net.Addr
+}
+
+func (c *rawConn) WriteTo(b []byte, _ net.Addr) (int, error) {
+	c.raw = append([]byte(nil), b...)
+	return len(b), nil
+}
+
+func (c *rawConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	return copy(b, c.raw), c.nextHop, nil
+}
+
+// timestampConn is a rawConn that reports ts as the receive timestamp.
+type timestampConn struct {
+	*rawConn
+	ts time.Time
+}
+
+func (c timestampConn) ReadFromWithTimestamp(b []byte) (int, net.Addr, time.Time, error) {
+	n, address, err := c.ReadFrom(b)
+	return n, address, c.ts, err
+}
+
+func TestSCIONPacketConnReadFromTimestamp(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	host := addr.HostFromIPStr("127.0.0.1")
+	nextHop, err := overlay.NewOverlayAddr(host, addr.NewL4UDPInfo(30041))
+	require.NoError(t, err)
+	raw := &rawConn{nextHop: nextHop}
+	err = NewSCIONPacketConn(raw).WriteTo(&SCIONPacket{
+		SCIONPacketInfo: SCIONPacketInfo{
+			Destination: SCIONAddress{IA: ia, Host: host},
+			Source:      SCIONAddress{IA: ia, Host: host},
+			L4Header:    &l4.UDP{SrcPort: 40000, DstPort: 40001},
+			Payload:     common.RawBytes{1, 2, 3},
+		},
+	}, nextHop)
+	require.NoError(t, err)
+
+	ts := time.Unix(1500000000, 42)
+	tests := map[string]struct {
+		Conn      net.PacketConn
+		Timestamp time.Time
+	}{
+		"with timestamps": {
+			Conn:      timestampConn{rawConn: raw, ts: ts},
+			Timestamp: ts,
+		},
+		"without timestamps": {
+			Conn: raw,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var pkt SCIONPacket
+			var ov overlay.OverlayAddr
+			err := NewSCIONPacketConn(test.Conn).ReadFrom(&pkt, &ov)
+			require.NoError(t, err)
+			assert.Equal(t, test.Timestamp, pkt.Timestamp)
+			assert.Equal(t, common.RawBytes{1, 2, 3}, pkt.Payload)
+			assert.Equal(t, nextHop.String(), ov.String())
+		})
+	}
+}
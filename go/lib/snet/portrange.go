@@ -0,0 +1,133 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+// maxPortAttempts is the maximum number of ports in a port range on which
+// registration is attempted.
+const maxPortAttempts = 8
+
+// PortRange is an inclusive range of SCION/UDP ports. Applications can
+// register their sockets on a random port of a range, such that firewall
+// rules and monitoring can rely on predictable ports, e.g., 32768-60999.
+type PortRange struct {
+	Min uint16
+	Max uint16
+}
+
+// ParsePortRange parses a port range of the form "min-max".
+func ParsePortRange(s string) (PortRange, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return PortRange{}, serrors.New("invalid port range", "range", s)
+	}
+	min, err := strconv.ParseUint(strings.TrimSpace(parts[0]), 10, 16)
+	if err != nil {
+		return PortRange{}, serrors.WrapStr("invalid min port", err, "range", s)
+	}
+	max, err := strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 16)
+	if err != nil {
+		return PortRange{}, serrors.WrapStr("invalid max port", err, "range", s)
+	}
+	r := PortRange{Min: uint16(min), Max: uint16(max)}
+	if err := r.Validate(); err != nil {
+		return PortRange{}, err
+	}
+	return r, nil
+}
+
+// Validate checks that the range is not empty and does not contain port 0.
+func (r PortRange) Validate() error {
+	if r.Min == 0 {
+		return serrors.New("port range must not contain port 0")
+	}
+	if r.Min > r.Max {
+		return serrors.New("port range is empty", "min", r.Min, "max", r.Max)
+	}
+	return nil
+}
+
+// UnmarshalText parses a port range of the form "min-max", which allows port
+// ranges to be used in configuration files.
+func (r *PortRange) UnmarshalText(text []byte) error {
+	var err error
+	*r, err = ParsePortRange(string(text))
+	return err
+}
+
+// MarshalText returns the port range in the form "min-max".
+func (r PortRange) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r PortRange) String() string {
+	return fmt.Sprintf("%d-%d", r.Min, r.Max)
+}
+
+func (r PortRange) size() int {
+	return int(r.Max) - int(r.Min) + 1
+}
+
+// random returns a random port in the range.
+func (r PortRange) random() uint16 {
+	return uint16(int(r.Min) + rand.Intn(r.size()))
+}
+
+// registerInRange calls register with a random port of r in public, if
+// public does not request a specific port. If the registration fails, e.g.,
+// because the port is in use, the following ports of the range are tried, at
+// most maxPortAttempts in total and within timeout. If r is nil, or public
+// requests a specific port, register is called with public as is.
+func registerInRange(r *PortRange, public *addr.AppAddr, timeout time.Duration,
+	register func(public *addr.AppAddr, timeout time.Duration) (uint16, error)) (uint16, error) {
+
+	if r == nil || public == nil || (public.L4 != nil && public.L4.Port() != 0) {
+		return register(public, timeout)
+	}
+	attempts := maxPortAttempts
+	if r.size() < attempts {
+		attempts = r.size()
+	}
+	deadline := time.Now().Add(timeout)
+	offset := int(r.random() - r.Min)
+	var err error
+	for i := 0; i < attempts; i++ {
+		attemptTimeout := timeout
+		if timeout != 0 {
+			if attemptTimeout = time.Until(deadline); attemptTimeout <= 0 {
+				break
+			}
+		}
+		port := uint16(int(r.Min) + (offset+i)%r.size())
+		candidate := &addr.AppAddr{L3: public.L3, L4: addr.NewL4UDPInfo(port)}
+		var regPort uint16
+		if regPort, err = register(candidate, attemptTimeout); err == nil {
+			return regPort, nil
+		}
+	}
+	return 0, common.NewBasicError("Unable to register on a port in range", err,
+		"range", r, "attempts", attempts)
+}
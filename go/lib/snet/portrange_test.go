@@ -0,0 +1,140 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+func TestParsePortRange(t *testing.T) {
+	tests := map[string]struct {
+		Input    string
+		Expected PortRange
+		Valid    bool
+	}{
+		"valid":          {Input: "32768-60999", Expected: PortRange{32768, 60999}, Valid: true},
+		"single port":    {Input: "40000-40000", Expected: PortRange{40000, 40000}, Valid: true},
+		"spaces":         {Input: "1024 - 2048", Expected: PortRange{1024, 2048}, Valid: true},
+		"port 0":         {Input: "0-1024"},
+		"empty range":    {Input: "2048-1024"},
+		"port too large": {Input: "1024-65536"},
+		"no separator":   {Input: "1024"},
+		"garbage":        {Input: "a-b"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, err := ParsePortRange(test.Input)
+			if !test.Valid {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.Expected, r)
+			text, err := r.MarshalText()
+			require.NoError(t, err)
+			var parsed PortRange
+			require.NoError(t, parsed.UnmarshalText(text))
+			assert.Equal(t, r, parsed)
+		})
+	}
+}
+
+func TestRegisterInRange(t *testing.T) {
+	public := &addr.AppAddr{L3: addr.HostFromIP(net.IP{127, 0, 0, 1})}
+	errInUse := serrors.New("port in use")
+	// register records the requested ports and fails for the first failures
+	// calls.
+	register := func(ports *[]uint16, failures int) func(*addr.AppAddr,
+		time.Duration) (uint16, error) {
+
+		return func(public *addr.AppAddr, _ time.Duration) (uint16, error) {
+			var port uint16
+			if public.L4 != nil {
+				port = public.L4.Port()
+			}
+			*ports = append(*ports, port)
+			if len(*ports) <= failures {
+				return 0, errInUse
+			}
+			if port == 0 {
+				port = 50000
+			}
+			return port, nil
+		}
+	}
+
+	t.Run("no range", func(t *testing.T) {
+		var ports []uint16
+		port, err := registerInRange(nil, public, 0, register(&ports, 0))
+		require.NoError(t, err)
+		assert.Equal(t, uint16(50000), port)
+		assert.Equal(t, []uint16{0}, ports)
+	})
+	t.Run("specific port", func(t *testing.T) {
+		var ports []uint16
+		specific := &addr.AppAddr{L3: public.L3, L4: addr.NewL4UDPInfo(40000)}
+		port, err := registerInRange(&PortRange{32768, 60999}, specific, 0,
+			register(&ports, 0))
+		require.NoError(t, err)
+		assert.Equal(t, uint16(40000), port)
+		assert.Equal(t, []uint16{40000}, ports)
+	})
+	t.Run("random port in range", func(t *testing.T) {
+		r := PortRange{32768, 60999}
+		for i := 0; i < 100; i++ {
+			var ports []uint16
+			port, err := registerInRange(&r, public, time.Second, register(&ports, 0))
+			require.NoError(t, err)
+			assert.True(t, port >= r.Min && port <= r.Max, "port %d", port)
+			assert.Equal(t, []uint16{port}, ports)
+		}
+	})
+	t.Run("ports in use", func(t *testing.T) {
+		r := PortRange{40000, 40004}
+		var ports []uint16
+		port, err := registerInRange(&r, public, time.Second, register(&ports, 3))
+		require.NoError(t, err)
+		require.Len(t, ports, 4)
+		assert.Equal(t, ports[3], port)
+		seen := make(map[uint16]bool)
+		for _, p := range ports {
+			assert.True(t, p >= r.Min && p <= r.Max, "port %d", p)
+			assert.False(t, seen[p], "port %d tried twice", p)
+			seen[p] = true
+		}
+	})
+	t.Run("all ports in use", func(t *testing.T) {
+		r := PortRange{40000, 40002}
+		var ports []uint16
+		_, err := registerInRange(&r, public, time.Second, register(&ports, 10))
+		assert.Error(t, err)
+		assert.Len(t, ports, 3)
+	})
+	t.Run("attempts are limited", func(t *testing.T) {
+		r := PortRange{32768, 60999}
+		var ports []uint16
+		_, err := registerInRange(&r, public, 0, register(&ports, 100))
+		assert.Error(t, err)
+		assert.Len(t, ports, maxPortAttempts)
+	})
+}
@@ -27,12 +27,51 @@ import (
 	"github.com/scionproto/scion/go/lib/serrors"
 )
 
+// PacketFilter decides whether a received packet is delivered to the
+// application. It returns true if the packet is accepted. Filters must not
+// modify or retain the packet.
+type PacketFilter func(pkt *SCIONPacket) bool
+
+// FilterSourceIAs returns a filter that only accepts packets sent from one of
+// the given ISD-ASes.
+func FilterSourceIAs(ias ...addr.IA) PacketFilter {
+	set := make(map[addr.IA]struct{}, len(ias))
+	for _, ia := range ias {
+		set[ia] = struct{}{}
+	}
+	return func(pkt *SCIONPacket) bool {
+		_, ok := set[pkt.Source.IA]
+		return ok
+	}
+}
+
+// FilterMinPayloadLen returns a filter that only accepts packets with a payload
+// of at least n bytes.
+func FilterMinPayloadLen(n int) PacketFilter {
+	return func(pkt *SCIONPacket) bool {
+		return pkt.Payload != nil && pkt.Payload.Len() >= n
+	}
+}
+
+// ReadMeta contains metadata about a packet read from a connection, see
+// ReadFromSCIONWithMeta.
+type ReadMeta struct {
+	// Timestamp is the time at which the packet was received by the host. It
+	// is taken by the kernel where available. It is zero if the dispatcher
+	// does not support receive timestamps.
+	Timestamp time.Time
+}
+
 type scionConnReader struct {
 	base *scionConnBase
 	conn PacketConn
 
 	mtx    sync.Mutex
 	buffer common.RawBytes
+
+	filterMtx sync.RWMutex
+	// filters are applied to every received data packet.
+	filters []PacketFilter
 }
 
 func newScionConnReader(base *scionConnBase, conn PacketConn) *scionConnReader {
@@ -47,23 +86,34 @@ func newScionConnReader(base *scionConnBase, conn PacketConn) *scionConnReader {
 // address of the sender. If the remote address for the connection is already
 // known, ReadFromSCION returns an error.
 func (c *scionConnReader) ReadFromSCION(b []byte) (int, *Addr, error) {
-	return c.read(b)
+	return c.read(b, nil)
+}
+
+// ReadFromSCIONWithMeta is like ReadFromSCION, but also returns metadata about
+// the received packet, e.g., the time at which it was received by the host.
+// Measurement tools can use the timestamp to compute one-way delays and
+// jitter without being affected by scheduling delays in the application.
+func (c *scionConnReader) ReadFromSCIONWithMeta(b []byte) (int, *Addr, ReadMeta, error) {
+	var meta ReadMeta
+	n, raddr, err := c.read(b, &meta)
+	return n, raddr, meta, err
 }
 
 func (c *scionConnReader) ReadFrom(b []byte) (int, net.Addr, error) {
-	return c.read(b)
+	return c.read(b, nil)
 }
 
 // Read reads data into b from a connection with a fixed remote address. If the
 // remote address for the connection is unknown, Read returns an error.
 func (c *scionConnReader) Read(b []byte) (int, error) {
-	n, _, err := c.read(b)
+	n, _, err := c.read(b, nil)
 	return n, err
 }
 
 // read returns the number of bytes read, the address that sent the bytes and
-// an error (if one occurred).
-func (c *scionConnReader) read(b []byte) (int, *Addr, error) {
+// an error (if one occurred). If meta is not nil, it is filled with the
+// metadata of the packet.
+func (c *scionConnReader) read(b []byte, meta *ReadMeta) (int, *Addr, error) {
 	if c.base.scionNet == nil {
 		return 0, nil, serrors.New("SCION network not initialized")
 	}
@@ -71,13 +121,22 @@ func (c *scionConnReader) read(b []byte) (int, *Addr, error) {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
 
-	pkt := SCIONPacket{
-		Bytes: Bytes(c.buffer),
-	}
+	filters := c.readFilters()
+	var pkt SCIONPacket
 	var lastHop overlay.OverlayAddr
-	err := c.conn.ReadFrom(&pkt, &lastHop)
-	if err != nil {
-		return 0, nil, err
+	for {
+		pkt = SCIONPacket{
+			Bytes: Bytes(c.buffer),
+		}
+		if err := c.conn.ReadFrom(&pkt, &lastHop); err != nil {
+			return 0, nil, err
+		}
+		if accept(filters, &pkt) {
+			break
+		}
+	}
+	if meta != nil {
+		meta.Timestamp = pkt.Timestamp
 	}
 
 	// Copy data, extract address
@@ -87,8 +146,8 @@ func (c *scionConnReader) read(b []byte) (int, *Addr, error) {
 	}
 
 	var remote *Addr
-	// On UDP4 network we can get either UDP traffic or SCMP messages
-	if c.base.net == "udp4" {
+	// On UDP networks we can get either UDP traffic or SCMP messages
+	if c.base.net == "udp4" || c.base.net == "udp6" {
 		// Extract remote address
 		remote = &Addr{
 			IA: pkt.Source.IA,
@@ -126,6 +185,32 @@ func (c *scionConnReader) read(b []byte) (int, *Addr, error) {
 	return 0, nil, common.NewBasicError("Unknown network", nil, "net", c.base.net)
 }
 
+// SetReadFilters replaces the filters applied to received packets. Packets
+// that are rejected by any filter are dropped, and reading continues with the
+// next packet. Calling SetReadFilters without arguments removes all filters.
+// Filters run in the goroutine that reads from the connection, and should
+// therefore be cheap.
+func (c *scionConnReader) SetReadFilters(filters ...PacketFilter) {
+	c.filterMtx.Lock()
+	defer c.filterMtx.Unlock()
+	c.filters = append([]PacketFilter(nil), filters...)
+}
+
+func (c *scionConnReader) readFilters() []PacketFilter {
+	c.filterMtx.RLock()
+	defer c.filterMtx.RUnlock()
+	return c.filters
+}
+
+func accept(filters []PacketFilter, pkt *SCIONPacket) bool {
+	for _, filter := range filters {
+		if !filter(pkt) {
+			return false
+		}
+	}
+	return true
+}
+
 func (c *scionConnReader) SetReadDeadline(t time.Time) error {
 	return c.conn.SetReadDeadline(t)
 }
@@ -0,0 +1,99 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"time"
+
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+const (
+	// revVerificationTimeout is the time allowed to verify a revocation
+	// received via SCMP.
+	revVerificationTimeout = 2 * time.Second
+	// maxPendingRevVerifications is the maximum number of revocations that
+	// are verified concurrently.
+	maxPendingRevVerifications = 16
+)
+
+// errRevVerificationBusy indicates that a revocation was ignored, because too
+// many revocations were being verified.
+var errRevVerificationBusy = serrors.New("too many pending revocation verifications")
+
+// RevocationAction describes how a revocation received via SCMP was handled.
+type RevocationAction int
+
+const (
+	// RevocationApplied indicates that the revocation was forwarded to the
+	// path resolver, if any, which removes the revoked paths.
+	RevocationApplied RevocationAction = iota
+	// RevocationQuarantined indicates that the revoked interface was
+	// quarantined, see RevocationPolicy.Quarantine.
+	RevocationQuarantined
+	// RevocationIgnored indicates that the revocation could not be verified
+	// and was ignored.
+	RevocationIgnored
+)
+
+func (a RevocationAction) String() string {
+	switch a {
+	case RevocationApplied:
+		return "applied"
+	case RevocationQuarantined:
+		return "quarantined"
+	case RevocationIgnored:
+		return "ignored"
+	default:
+		return "unknown"
+	}
+}
+
+// RevocationEvent describes a revocation received via SCMP.
+type RevocationEvent struct {
+	// Source is the address of the sender of the SCMP message.
+	Source SCIONAddress
+	// RevInfo is the revocation. It is nil if the revocation could not be
+	// parsed.
+	RevInfo *path_mgmt.RevInfo
+	// Action is the action taken for the revocation.
+	Action RevocationAction
+	// Err is the reason why the revocation was ignored.
+	Err error
+}
+
+// RevocationPolicy configures how the default SCMP handler processes
+// revocations. The zero value revokes all received revocations in the path
+// resolver.
+type RevocationPolicy struct {
+	// Verifier, if set, verifies the signatures of received revocations.
+	// Revocations that cannot be verified are ignored, i.e., they do not
+	// affect path selection. The verification is done in the background,
+	// such that reads are not blocked, e.g., while keys are fetched. Thus,
+	// revocations are not returned to the application as errors; use
+	// OnRevocation to observe them. Revocations received while many others
+	// are being verified are ignored.
+	Verifier path_mgmt.Verifier
+	// Quarantine, if positive, quarantines the revoked interface for the
+	// given duration instead of revoking the paths in the resolver. Paths
+	// traversing a quarantined interface are only used if no other path to
+	// the destination is available.
+	Quarantine time.Duration
+	// OnRevocation, if set, is called for every received revocation with the
+	// action that was taken. It is called on the reading goroutine, or on the
+	// verifying goroutine if a Verifier is set, and must not block.
+	OnRevocation func(RevocationEvent)
+}
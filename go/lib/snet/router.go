@@ -123,7 +123,10 @@ func (r *BaseRouter) LocalIA() addr.IA {
 type Path interface {
 	// Fingerprint uniquely identifies the path based on the sequence of
 	// ASes and BRs. Other metadata, such as MTU or NextHop have no effect
-	// on the fingerprint. Empty string means unknown fingerprint.
+	// on the fingerprint. It is the binary path key (see
+	// spathmeta.AppPath.Key) that is also used to report path feedback to
+	// SCIOND; the tools show the short hex form of it, see
+	// spathmeta.PathKey.Fingerprint. Empty string means unknown fingerprint.
 	Fingerprint() string
 	// OverlayNextHop returns the address:port pair of a local-AS overlay
 	// speaker. Usually, this is a border router that will forward the traffic.
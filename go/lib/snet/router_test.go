@@ -22,6 +22,9 @@ import (
 
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/spath/spathmeta"
+	"github.com/scionproto/scion/go/lib/xtest"
 )
 
 func TestLocalMachineBuildAppAddress(t *testing.T) {
@@ -94,3 +97,18 @@ func mustNewOverlayAddr(l3 addr.HostAddr, l4 addr.L4Info) *overlay.OverlayAddr {
 	}
 	return ov
 }
+
+func TestPathFingerprint(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	entry := &sciond.PathReplyEntry{
+		Path: &sciond.FwdPathMeta{
+			Interfaces: []sciond.PathInterface{
+				{RawIsdas: ia.IAInt(), IfID: 1},
+				{RawIsdas: ia.IAInt(), IfID: 2},
+			},
+		},
+	}
+	ap := &spathmeta.AppPath{Entry: entry}
+	assert.Equal(t, string(ap.Key()), (&path{sciondPath: entry}).Fingerprint())
+	assert.Empty(t, (&path{}).Fingerprint())
+}
@@ -29,17 +29,79 @@
 // sender's address; WriteTo and WriteToSCION can be used to send a message to
 // a chosen destination.
 //
+// If multiple paths to a destination are available, packets without a flow ID
+// may be sent on any of them. Applications that need packets of a flow to
+// stay on one path, e.g., to avoid reordering, tag them with a FlowID, either
+// per write with WriteToFlow or per connection with SetFlowID. Different flows
+// are spread across the available paths.
+//
+// Measurement tools can call ReadFromSCIONWithMeta to learn when a packet was
+// received by the host. The receive timestamp is taken by the dispatcher, by
+// the kernel where available, such that it is not affected by queuing in the
+// dispatcher or by scheduling delays in the application.
+//
+// Servers can install cheap read filters with SetReadFilters, e.g., to only
+// accept packets from certain ASes (FilterSourceIAs). Packets that are rejected
+// are dropped before they are delivered to the application.
+//
+// By default, Close releases a connection immediately, and SCMP errors caused
+// by the last packets sent on it are lost. Short-lived applications can call
+// SetLinger to have Close wait for such errors and pass them to a callback.
+//
+// Bulk transfer tools can limit the rate of their writes in bytes or packets
+// per second, such that they do not overrun constrained links close to the
+// sender. The limit is set for all connections of a network with
+// NetworkConfig.WriteRateLimit, and can be changed per connection at any time
+// with SetWriteRateLimit. Writes that exceed the limit are delayed.
+//
+// DialSCIONHappyEyeballs probes several paths to the remote in parallel and
+// returns a connection over the first responsive one. This avoids long
+// connection setup times if the preferred path is dead.
+//
 // For applications that need to run in multiple ASes, new networking contexts
 // can be created using NewNetwork. Calling the DialSCION or ListenSCION
 // methods on the networking context yields connections that run in that context.
 //
 // Multiple networking contexts can share the same SCIOND and/or dispatcher.
 //
+// Applications that cannot rely on a local dispatcher can create a networking
+// context with NewCustomNetworkWithPR and a BypassPacketDispatcherService,
+// which exchanges packets with the border routers directly over UDP/IP.
+//
+// Applications that must start before the dispatcher is available can wrap
+// their packet dispatcher service in a LazyPacketDispatcherService. Dial and
+// Listen then return immediately, and the registration with the dispatcher
+// happens (and is retried) on the first Read or Write.
+//
+// To inspect or modify the packets of all sockets, e.g., for logging, metrics
+// or fault injection, applications can wrap their packet dispatcher service in
+// an InterceptingPacketDispatcherService, or set NetworkConfig.Interceptors.
+//
+// Errors returned by snet carry a code where possible, such that applications
+// can use serrors.CodeOf and serrors.IsRetryable to decide whether to retry an
+// operation, to refresh the path (serrors.CodePathDown), or to give up.
+//
 // Write calls never return SCMP errors directly. If a write call caused an
 // SCMP message to be received by the Conn, it can be inspected by calling
 // Read. In this case, the error value is non-nil and can be type asserted to
 // *OpError. Method SCMP() can be called on the error to extract the SCMP
-// header.
+// header. For revocations, methods RevokedInterface() and Path() expose the
+// revoked interface and the path that was in use, and SuggestedAction()
+// indicates whether the application should refresh its path.
+//
+// By default, received revocations are also forwarded to the path resolver,
+// such that revoked paths are no longer used. Applications that need to keep
+// sending on revoked paths, e.g., for measurements, can set
+// NetworkConfig.DeliverRevocations or use NewRevocationDeliveringSCMPHandler,
+// in which case revocations are delivered by Read like data packets.
+//
+// Conns are safe for concurrent use. Multiple goroutines may call Read and
+// Write (and their variants) on the same Conn simultaneously; reads are
+// serialized with respect to other reads, and writes with respect to other
+// writes, but a slow path lookup in one writer does not block readers. The
+// addresses passed to Conn methods are never mutated by snet, and the
+// addresses returned by Conn methods are copies that callers can freely
+// modify. SCMP errors are delivered to exactly one of the concurrent readers.
 //
 // Important: not draining SCMP errors via Read calls can cause the dispatcher
 // to shutdown the socket (see https://github.com/scionproto/scion/pull/1356).
@@ -57,9 +119,20 @@ import (
 	"github.com/scionproto/scion/go/lib/pathmgr"
 	"github.com/scionproto/scion/go/lib/sciond"
 	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet/internal/pathsource"
 	"github.com/scionproto/scion/go/lib/sock/reliable"
 )
 
+const (
+	// DefaultPathRefire is the interval in which watched paths are refreshed,
+	// unless configured otherwise in NetworkConfig.Timers or via
+	// SCIONNetwork.SetPathTimers.
+	DefaultPathRefire = time.Minute
+	// DefaultPathErrorRefire is the interval in which watched paths are
+	// refreshed if no paths were found, unless configured otherwise.
+	DefaultPathErrorRefire = 3 * time.Second
+)
+
 var (
 	// Default SCION networking context for package-level Dial and Listen
 	DefNetwork *SCIONNetwork
@@ -101,6 +174,19 @@ type SCIONNetwork struct {
 	// is set to nil when operating on a SCIOND-less Network.
 	pathResolver pathmgr.Resolver
 	localIA      addr.IA
+	// svcResolver resolves SVC destinations when dialing. If nil, SVC
+	// destinations are not resolved.
+	svcResolver SVCResolver
+	// nextHops caches the next hops of the paths used by the connections of
+	// the network. If nil, next hops are not cached.
+	nextHops *pathsource.NextHopCache
+	// quarantine keeps track of the interfaces that are avoided by the
+	// connections of the network, see RevocationPolicy. If nil, no interfaces
+	// are avoided.
+	quarantine *pathsource.Quarantine
+	// writeRateLimit is the initial rate limit of the writes on the
+	// connections of the network.
+	writeRateLimit RateLimit
 }
 
 // NewNetworkWithPR creates a new networking context with path resolver pr. A
@@ -128,6 +214,7 @@ func NewCustomNetworkWithPR(ia addr.IA, pktDispatcher PacketDispatcherService,
 		dispatcher:   pktDispatcher,
 		pathResolver: pr,
 		localIA:      ia,
+		nextHops:     pathsource.NewNextHopCache(pathsource.DefaultNextHopTTL),
 	}
 }
 
@@ -138,14 +225,16 @@ func NewCustomNetworkWithPR(ia addr.IA, pktDispatcher PacketDispatcherService,
 // If sciondPath is the empty string, the network will run without SCIOND. In
 // this mode of operation, the app is fully responsible with supplying paths
 // for sent traffic.
+//
+// Use NewNetworkFromConfig to customize the path resolver.
 func NewNetwork(ia addr.IA, sciondPath string,
 	dispatcher reliable.DispatcherService) (*SCIONNetwork, error) {
 
-	pathResolver, err := getResolver(sciondPath)
-	if err != nil {
-		return nil, err
-	}
-	return NewNetworkWithPR(ia, dispatcher, pathResolver), nil
+	return NewNetworkFromConfig(NetworkConfig{
+		IA:         ia,
+		SciondPath: sciondPath,
+		Dispatcher: dispatcher,
+	})
 }
 
 // NewCustomNetwork is similar to NewNetwork, except it gives control over the
@@ -155,34 +244,15 @@ func NewNetwork(ia addr.IA, sciondPath string,
 func NewCustomNetwork(ia addr.IA, sciondPath string,
 	pktDispatcher PacketDispatcherService) (*SCIONNetwork, error) {
 
-	pathResolver, err := getResolver(sciondPath)
-	if err != nil {
-		return nil, err
-	}
-	return NewCustomNetworkWithPR(ia, pktDispatcher, pathResolver), nil
-}
-
-// getResolver builds a default resolver for snet internals.
-func getResolver(sciondPath string) (pathmgr.Resolver, error) {
-	var pathResolver pathmgr.Resolver
-	if sciondPath != "" {
-		sciondConn, err := sciond.NewService(sciondPath, true).Connect()
-		if err != nil {
-			return nil, common.NewBasicError("Unable to initialize SCIOND service", err)
-		}
-		pathResolver = pathmgr.New(
-			sciondConn,
-			pathmgr.Timers{
-				NormalRefire: time.Minute,
-				ErrorRefire:  3 * time.Second,
-			},
-		)
-	}
-	return pathResolver, nil
+	return NewNetworkFromConfig(NetworkConfig{
+		IA:               ia,
+		SciondPath:       sciondPath,
+		PacketDispatcher: pktDispatcher,
+	})
 }
 
 // DialSCION returns a SCION connection to raddr. Nil values for laddr are not
-// supported yet.  Parameter network must be "udp4". The returned connection's
+// supported yet.  Parameter network must be "udp4" or "udp6". The returned connection's
 // Read and Write methods can be used to receive and send SCION packets.
 //
 // A timeout of 0 means infinite timeout.
@@ -193,9 +263,15 @@ func (n *SCIONNetwork) DialSCION(network string, laddr, raddr *Addr,
 }
 
 // DialSCIONWithBindSVC returns a SCION connection to raddr. Nil values for laddr are not
-// supported yet.  Parameter network must be "udp4". The returned connection's
+// supported yet.  Parameter network must be "udp4" or "udp6". The returned connection's
 // Read and Write methods can be used to receive and send SCION packets.
 //
+// If raddr is an SVC address and an SVC resolver is set (see SetSVCResolver),
+// the remote AS is queried for a concrete instance of the service, and the
+// connection is fixed to the address of that instance. The resolution counts
+// against the timeout. Dialing again performs a new resolution, such that
+// applications can retry against different service instances.
+//
 // A timeout of 0 means infinite timeout.
 func (n *SCIONNetwork) DialSCIONWithBindSVC(network string, laddr, raddr, baddr *Addr,
 	svc addr.HostSVC, timeout time.Duration) (Conn, error) {
@@ -203,6 +279,19 @@ func (n *SCIONNetwork) DialSCIONWithBindSVC(network string, laddr, raddr, baddr
 	if raddr == nil {
 		return nil, serrors.New("Unable to dial to nil remote")
 	}
+	var deadline time.Time
+	if timeout != 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	raddr, err := n.resolveSVC(raddr, deadline)
+	if err != nil {
+		return nil, err
+	}
+	if timeout != 0 {
+		if timeout = time.Until(deadline); timeout <= 0 {
+			return nil, serrors.WithCode(serrors.New(ErrDialTimeout), serrors.CodeTimeout)
+		}
+	}
 	conn, err := n.ListenSCIONWithBindSVC(network, laddr, baddr, svc, timeout)
 	if err != nil {
 		return nil, err
@@ -215,7 +304,7 @@ func (n *SCIONNetwork) DialSCIONWithBindSVC(network string, laddr, raddr, baddr
 // ListenSCION registers laddr with the dispatcher. Nil values for laddr are
 // not supported yet. The returned connection's ReadFrom and WriteTo methods
 // can be used to receive and send SCION packets with per-packet addressing.
-// Parameter network must be "udp4".
+// Parameter network must be "udp4" or "udp6".
 //
 // A timeout of 0 means infinite timeout.
 func (n *SCIONNetwork) ListenSCION(network string, laddr *Addr,
@@ -227,7 +316,7 @@ func (n *SCIONNetwork) ListenSCION(network string, laddr *Addr,
 // ListenSCIONWithBindSVC registers laddr with the dispatcher. Nil values for laddr are
 // not supported yet. The returned connection's ReadFrom and WriteTo methods
 // can be used to receive and send SCION packets with per-packet addressing.
-// Parameter network must be "udp4".
+// Parameter network must be "udp4" or "udp6".
 //
 // A timeout of 0 means infinite timeout.
 func (n *SCIONNetwork) ListenSCIONWithBindSVC(network string, laddr, baddr *Addr,
@@ -248,6 +337,10 @@ func (n *SCIONNetwork) ListenSCIONWithBindSVC(network string, laddr, baddr *Addr
 		l3Type = addr.HostTypeIPv4
 		l4Type = common.L4UDP
 		defL4 = addr.NewL4UDPInfo(0)
+	case "udp6":
+		l3Type = addr.HostTypeIPv6
+		l4Type = common.L4UDP
+		defL4 = addr.NewL4UDPInfo(0)
 	default:
 		return nil, common.NewBasicError("Network not implemented", nil, "net", network)
 	}
@@ -267,6 +360,9 @@ func (n *SCIONNetwork) ListenSCIONWithBindSVC(network string, laddr, baddr *Addr
 	if laddr.Host.L3.IP().IsUnspecified() {
 		return nil, serrors.New("Binding to unspecified address not supported")
 	}
+	// Work on a copy so that the caller's address is never mutated. Callers
+	// are free to reuse laddr concurrently with the returned connection.
+	laddr = laddr.Copy()
 	if laddr.Host.L4 == nil {
 		// If no port has been specified, default to 0 to get a random port from the dispatcher
 		laddr.Host.L4 = defL4
@@ -279,7 +375,7 @@ func (n *SCIONNetwork) ListenSCIONWithBindSVC(network string, laddr, baddr *Addr
 		net:      network,
 		scionNet: n,
 		svc:      svc,
-		laddr:    laddr.Copy(),
+		laddr:    laddr,
 	}
 	// Make sure the IA is set.
 	if conn.laddr.IA.IsZero() {
@@ -320,6 +416,18 @@ func (n *SCIONNetwork) PathResolver() pathmgr.Resolver {
 	return n.pathResolver
 }
 
+// SetPathTimers overrides the intervals in which the paths to dst are
+// refreshed, e.g., to refresh the paths to latency-critical destinations
+// every few seconds and the paths to background destinations only every few
+// minutes. Timers that are left uninitialized use the defaults of the
+// network. The override applies to new and existing path watches to dst. If
+// the network runs without SCIOND, this is a no-op.
+func (n *SCIONNetwork) SetPathTimers(dst addr.IA, timers pathmgr.Timers) {
+	if n.pathResolver != nil {
+		n.pathResolver.SetTimers(dst, timers)
+	}
+}
+
 // Sciond returns the sciond API endpoint that the network is using.
 func (n *SCIONNetwork) Sciond() sciond.Connector {
 	if n.pathResolver != nil {
@@ -0,0 +1,39 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package snettest contains utilities for testing applications built on top
+// of snet without a full SCION topology.
+//
+// FaultPacketConn wraps an snet.PacketConn and impairs the traffic flowing
+// through it. Tests can drop or delay packets, and inject SCMP errors and
+// revocations that are surfaced to the application exactly like the ones
+// received from the network. This makes it possible to exercise failover
+// logic (e.g., switching paths after a revocation) in unit tests.
+//
+// The connections created by a FaultPacketDispatcherService are tracked, so
+// tests that only have access to an snet.Conn can still reach the wrapped
+// packet connections:
+//
+//	scmpHandler := snet.NewSCMPHandler(resolver)
+//	pktDisp := &snettest.FaultPacketDispatcherService{
+//	    Dispatcher: &snet.DefaultPacketDispatcherService{
+//	        Dispatcher:  reliable.NewDispatcherService(""),
+//	        SCMPHandler: scmpHandler,
+//	    },
+//	    SCMPHandler: scmpHandler,
+//	}
+//	network := snet.NewCustomNetworkWithPR(ia, pktDisp, resolver)
+//	conn, _ := network.DialSCION("udp4", local, remote, 0)
+//	pktDisp.Conns()[0].SetFaults(snettest.Faults{WriteLoss: 1})
+package snettest
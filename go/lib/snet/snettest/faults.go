@@ -0,0 +1,349 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snettest
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/scmp"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/snet/internal/deadline"
+	"github.com/scionproto/scion/go/lib/spath"
+)
+
+// ErrClosed is returned by operations on a closed FaultPacketConn.
+var ErrClosed = serrors.New("fault packet conn closed")
+
+// Faults describes the impairments applied by a FaultPacketConn.
+type Faults struct {
+	// WriteLoss is the probability in [0, 1] that a written packet is
+	// silently dropped instead of being passed to the underlying connection.
+	WriteLoss float64
+	// ReadLoss is the probability in [0, 1] that a packet read from the
+	// underlying connection is silently dropped. Injected packets are never
+	// dropped.
+	ReadLoss float64
+	// Latency delays every write by the given duration.
+	Latency time.Duration
+}
+
+var _ snet.PacketConn = (*FaultPacketConn)(nil)
+
+// FaultPacketConn is an snet.PacketConn that impairs the traffic of the
+// wrapped connection according to its Faults, and delivers packets injected
+// by tests to readers.
+//
+// Reads from the underlying connection are done in a background goroutine, so
+// that injected packets are delivered even while a reader is blocked. Read
+// deadlines are therefore handled by the FaultPacketConn itself.
+type FaultPacketConn struct {
+	conn        snet.PacketConn
+	scmpHandler snet.SCMPHandler
+
+	reads    chan readResult
+	injected chan readResult
+	closed   chan struct{}
+	done     chan struct{}
+	deadline *deadline.Deadline
+
+	mtx       sync.Mutex
+	faults    Faults
+	rand      *rand.Rand
+	closeOnce sync.Once
+}
+
+type readResult struct {
+	pkt *snet.SCIONPacket
+	ov  *overlay.OverlayAddr
+	err error
+}
+
+// NewFaultPacketConn wraps conn. Injected SCMP packets are passed to
+// scmpHandler in the same way snet.SCIONPacketConn passes SCMP packets
+// received from the network; it should therefore be the same handler the
+// underlying connection uses. Packets for which the handler returns
+// snet.ErrDeliverSCMP are returned to the reader. If scmpHandler is nil,
+// reading an injected SCMP packet returns an error.
+func NewFaultPacketConn(conn snet.PacketConn, scmpHandler snet.SCMPHandler) *FaultPacketConn {
+	c := &FaultPacketConn{
+		conn:        conn,
+		scmpHandler: scmpHandler,
+		reads:       make(chan readResult),
+		injected:    make(chan readResult, 64),
+		closed:      make(chan struct{}),
+		done:        make(chan struct{}),
+		rand:        rand.New(rand.NewSource(time.Now().UnixNano())),
+		deadline:    deadline.New(),
+	}
+	go func() {
+		defer close(c.done)
+		c.readLoop()
+	}()
+	return c
+}
+
+// SetFaults replaces the impairments applied to subsequent reads and writes.
+func (c *FaultPacketConn) SetFaults(faults Faults) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.faults = faults
+}
+
+// Faults returns the impairments currently applied by the connection.
+func (c *FaultPacketConn) Faults() Faults {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.faults
+}
+
+// Seed seeds the random source used to decide which packets are lost, to
+// make lossy tests reproducible.
+func (c *FaultPacketConn) Seed(seed int64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.rand = rand.New(rand.NewSource(seed))
+}
+
+// Inject queues pkt for delivery to the next reader, as if it was received
+// from next hop ov. Packets are delivered in the order they were injected.
+func (c *FaultPacketConn) Inject(pkt *snet.SCIONPacket, ov *overlay.OverlayAddr) error {
+	res := readResult{pkt: pkt, ov: ov}
+	select {
+	case <-c.closed:
+		return ErrClosed
+	default:
+	}
+	select {
+	case c.injected <- res:
+		return nil
+	default:
+		return serrors.New("injection queue full")
+	}
+}
+
+// InjectSCMP queues an SCMP packet of type ct sent by src. The quoted path
+// header is taken from path, which may be nil.
+func (c *FaultPacketConn) InjectSCMP(src snet.SCIONAddress, ct scmp.ClassType,
+	info scmp.Info, path *spath.Path) error {
+
+	pld := &scmp.Payload{
+		Meta: &scmp.Meta{},
+		Info: info,
+	}
+	if info != nil {
+		pld.Meta.InfoLen = uint8(info.Len() / common.LineLen)
+	}
+	if path != nil {
+		pld.PathHdr = append(common.RawBytes(nil), path.Raw...)
+		pld.Meta.PathHdrLen = uint8(len(pld.PathHdr) / common.LineLen)
+	}
+	pkt := &snet.SCIONPacket{
+		SCIONPacketInfo: snet.SCIONPacketInfo{
+			Source:   src,
+			L4Header: scmp.NewHdr(ct, pld.Len()),
+			Payload:  pld,
+		},
+	}
+	return c.Inject(pkt, nil)
+}
+
+// InjectRevocation queues an SCMP revocation for interface ifID, sent by src.
+// The quoted path header is taken from path, which may be nil.
+func (c *FaultPacketConn) InjectRevocation(src snet.SCIONAddress, ifID common.IFIDType,
+	sRevInfo *path_mgmt.SignedRevInfo, path *spath.Path) error {
+
+	rawSRev, err := sRevInfo.Pack()
+	if err != nil {
+		return common.NewBasicError("Unable to pack revocation", err)
+	}
+	ct := scmp.ClassType{Class: scmp.C_Path, Type: scmp.T_P_RevokedIF}
+	info := scmp.NewInfoRevocation(0, 0, ifID, false, rawSRev)
+	return c.InjectSCMP(src, ct, info, path)
+}
+
+func (c *FaultPacketConn) ReadFrom(pkt *snet.SCIONPacket, ov *overlay.OverlayAddr) error {
+	for {
+		select {
+		case res := <-c.injected:
+			hdr, ok := res.pkt.L4Header.(*scmp.Hdr)
+			if !ok {
+				copyPacket(pkt, ov, res)
+				return nil
+			}
+			// Mimic the SCMP handling of snet.SCIONPacketConn, such that
+			// injected SCMP packets are indistinguishable from the ones
+			// received from the network.
+			if c.scmpHandler == nil {
+				return common.NewBasicError("scmp packet received, but no handler found", nil,
+					"scmp.Hdr", hdr, "src", res.pkt.Source)
+			}
+			if err := c.scmpHandler.Handle(res.pkt); err != nil {
+				if xerrors.Is(err, snet.ErrDeliverSCMP) {
+					copyPacket(pkt, ov, res)
+					return nil
+				}
+				return err
+			}
+		case res := <-c.reads:
+			if res.err != nil {
+				return res.err
+			}
+			if c.lose(func(f Faults) float64 { return f.ReadLoss }) {
+				continue
+			}
+			copyPacket(pkt, ov, res)
+			return nil
+		case <-c.deadline.Wait():
+			return snet.ErrDeadlineExceeded
+		case <-c.closed:
+			return ErrClosed
+		}
+	}
+}
+
+func (c *FaultPacketConn) WriteTo(pkt *snet.SCIONPacket, ov *overlay.OverlayAddr) error {
+	select {
+	case <-c.closed:
+		return ErrClosed
+	default:
+	}
+	if latency := c.Faults().Latency; latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-c.closed:
+			return ErrClosed
+		}
+	}
+	if c.lose(func(f Faults) float64 { return f.WriteLoss }) {
+		return nil
+	}
+	return c.conn.WriteTo(pkt, ov)
+}
+
+func (c *FaultPacketConn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.SetWriteDeadline(t)
+}
+
+func (c *FaultPacketConn) SetReadDeadline(t time.Time) error {
+	c.deadline.Set(t)
+	return nil
+}
+
+func (c *FaultPacketConn) SetWriteDeadline(t time.Time) error {
+	return c.conn.SetWriteDeadline(t)
+}
+
+// Close closes the underlying connection and waits for the background reader
+// to exit.
+func (c *FaultPacketConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		err = c.conn.Close()
+		<-c.done
+	})
+	return err
+}
+
+func (c *FaultPacketConn) readLoop() {
+	for {
+		pkt := &snet.SCIONPacket{Bytes: make(snet.Bytes, common.MaxMTU)}
+		var ov overlay.OverlayAddr
+		err := c.conn.ReadFrom(pkt, &ov)
+		select {
+		case c.reads <- readResult{pkt: pkt, ov: &ov, err: err}:
+		case <-c.closed:
+			return
+		}
+		if _, ok := err.(*snet.OpError); err != nil && !ok {
+			// The underlying connection is broken, report the error to all
+			// subsequent readers.
+			for {
+				select {
+				case c.reads <- readResult{err: err}:
+				case <-c.closed:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (c *FaultPacketConn) lose(probability func(Faults) float64) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	p := probability(c.faults)
+	return p > 0 && c.rand.Float64() < p
+}
+
+func copyPacket(pkt *snet.SCIONPacket, ov *overlay.OverlayAddr, res readResult) {
+	pkt.Bytes = append(pkt.Bytes[:0], res.pkt.Bytes...)
+	pkt.SCIONPacketInfo = res.pkt.SCIONPacketInfo
+	if res.ov != nil && ov != nil {
+		*ov = *res.ov
+	}
+}
+
+var _ snet.PacketDispatcherService = (*FaultPacketDispatcherService)(nil)
+
+// FaultPacketDispatcherService wraps every connection registered through
+// Dispatcher in a FaultPacketConn.
+type FaultPacketDispatcherService struct {
+	// Dispatcher creates the underlying packet connections.
+	Dispatcher snet.PacketDispatcherService
+	// SCMPHandler is passed to every FaultPacketConn, see NewFaultPacketConn.
+	SCMPHandler snet.SCMPHandler
+	// Faults are the initial impairments of every new connection.
+	Faults Faults
+
+	mtx   sync.Mutex
+	conns []*FaultPacketConn
+}
+
+func (s *FaultPacketDispatcherService) RegisterTimeout(ia addr.IA, public *addr.AppAddr,
+	bind *overlay.OverlayAddr, svc addr.HostSVC,
+	timeout time.Duration) (snet.PacketConn, uint16, error) {
+
+	conn, port, err := s.Dispatcher.RegisterTimeout(ia, public, bind, svc, timeout)
+	if err != nil {
+		return nil, 0, err
+	}
+	faultConn := NewFaultPacketConn(conn, s.SCMPHandler)
+	faultConn.SetFaults(s.Faults)
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.conns = append(s.conns, faultConn)
+	return faultConn, port, nil
+}
+
+// Conns returns the connections created by the service, in registration
+// order.
+func (s *FaultPacketDispatcherService) Conns() []*FaultPacketConn {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return append([]*FaultPacketConn(nil), s.conns...)
+}
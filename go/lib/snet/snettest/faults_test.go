@@ -0,0 +1,197 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snettest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/l4"
+	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/scmp"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/snet/mock_snet"
+	"github.com/scionproto/scion/go/lib/snet/snettest"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/proto"
+)
+
+func TestFaultPacketConnInjectRevocation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	conn := newFaultPacketConn(ctrl, snet.NewSCMPHandler(nil))
+	defer conn.Close()
+
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	revInfo := &path_mgmt.RevInfo{IfID: 42, RawIsdas: ia.IAInt(), RawTTL: 10}
+	rawRev, err := revInfo.Pack()
+	require.NoError(t, err)
+	sRevInfo := &path_mgmt.SignedRevInfo{Blob: rawRev, Sign: &proto.SignS{}}
+	src := snet.SCIONAddress{IA: ia, Host: addr.HostFromIPStr("127.0.0.1")}
+	require.NoError(t, conn.InjectRevocation(src, 42, sRevInfo, nil))
+
+	err = conn.ReadFrom(&snet.SCIONPacket{}, &overlay.OverlayAddr{})
+	opErr, ok := err.(*snet.OpError)
+	require.True(t, ok, "expected *snet.OpError, got %T", err)
+	revIA, ifID, ok := opErr.RevokedInterface()
+	assert.True(t, ok)
+	assert.Equal(t, ia, revIA)
+	assert.Equal(t, common.IFIDType(42), ifID)
+}
+
+func TestFaultPacketConnInjectPacket(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	conn := newFaultPacketConn(ctrl, nil)
+	defer conn.Close()
+
+	ov, err := overlay.NewOverlayAddr(addr.HostFromIPStr("127.0.0.2"),
+		addr.NewL4UDPInfo(overlay.EndhostPort))
+	require.NoError(t, err)
+	injected := &snet.SCIONPacket{
+		SCIONPacketInfo: snet.SCIONPacketInfo{
+			L4Header: &l4.UDP{SrcPort: 80},
+			Payload:  common.RawBytes{1, 2, 3},
+		},
+	}
+	require.NoError(t, conn.Inject(injected, ov))
+
+	var pkt snet.SCIONPacket
+	var lastHop overlay.OverlayAddr
+	require.NoError(t, conn.ReadFrom(&pkt, &lastHop))
+	assert.Equal(t, injected.SCIONPacketInfo, pkt.SCIONPacketInfo)
+	assert.Equal(t, *ov, lastHop)
+}
+
+func TestFaultPacketConnInjectSCMPWithoutHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	conn := newFaultPacketConn(ctrl, nil)
+	defer conn.Close()
+
+	require.NoError(t, conn.InjectRevocation(snet.SCIONAddress{}, 1,
+		&path_mgmt.SignedRevInfo{Sign: &proto.SignS{}}, nil))
+	err := conn.ReadFrom(&snet.SCIONPacket{}, &overlay.OverlayAddr{})
+	assert.Error(t, err)
+}
+
+func TestFaultPacketConnInjectSCMPDelivered(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	conn := newFaultPacketConn(ctrl, deliverSCMPHandler{})
+	defer conn.Close()
+
+	ct := scmp.ClassType{Class: scmp.C_General, Type: scmp.T_G_EchoReply}
+	require.NoError(t, conn.InjectSCMP(snet.SCIONAddress{}, ct, &scmp.InfoEcho{Id: 1}, nil))
+	var pkt snet.SCIONPacket
+	require.NoError(t, conn.ReadFrom(&pkt, &overlay.OverlayAddr{}))
+	hdr, ok := pkt.L4Header.(*scmp.Hdr)
+	require.True(t, ok, "expected *scmp.Hdr, got %T", pkt.L4Header)
+	assert.Equal(t, ct, scmp.ClassType{Class: hdr.Class, Type: hdr.Type})
+}
+
+func TestFaultPacketConnWriteLoss(t *testing.T) {
+	testCases := map[string]struct {
+		Faults         snettest.Faults
+		ExpectedWrites int
+	}{
+		"no loss": {
+			ExpectedWrites: 3,
+		},
+		"full loss": {
+			Faults: snettest.Faults{WriteLoss: 1},
+		},
+		"latency": {
+			Faults:         snettest.Faults{Latency: time.Millisecond},
+			ExpectedWrites: 3,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			pconn := newBlockingPacketConn(ctrl)
+			pconn.EXPECT().WriteTo(gomock.Any(), gomock.Any()).Times(tc.ExpectedWrites)
+			conn := snettest.NewFaultPacketConn(pconn, nil)
+			defer conn.Close()
+			conn.SetFaults(tc.Faults)
+
+			for i := 0; i < 3; i++ {
+				assert.NoError(t, conn.WriteTo(&snet.SCIONPacket{}, nil))
+			}
+		})
+	}
+}
+
+func TestFaultPacketConnReadDeadline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	conn := newFaultPacketConn(ctrl, nil)
+	defer conn.Close()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(10*time.Millisecond)))
+	err := conn.ReadFrom(&snet.SCIONPacket{}, &overlay.OverlayAddr{})
+	assert.Equal(t, snet.ErrDeadlineExceeded, err)
+}
+
+func TestFaultPacketConnClose(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	conn := newFaultPacketConn(ctrl, nil)
+
+	require.NoError(t, conn.Close())
+	err := conn.ReadFrom(&snet.SCIONPacket{}, &overlay.OverlayAddr{})
+	assert.Equal(t, snettest.ErrClosed, err)
+	assert.Equal(t, snettest.ErrClosed, conn.WriteTo(&snet.SCIONPacket{}, nil))
+	assert.Equal(t, snettest.ErrClosed, conn.Inject(&snet.SCIONPacket{}, nil))
+}
+
+func newFaultPacketConn(ctrl *gomock.Controller,
+	handler snet.SCMPHandler) *snettest.FaultPacketConn {
+
+	return snettest.NewFaultPacketConn(newBlockingPacketConn(ctrl), handler)
+}
+
+// newBlockingPacketConn returns a mock packet conn whose reads block until it
+// is closed.
+func newBlockingPacketConn(ctrl *gomock.Controller) *mock_snet.MockPacketConn {
+	closed := make(chan struct{})
+	conn := mock_snet.NewMockPacketConn(ctrl)
+	conn.EXPECT().ReadFrom(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(*snet.SCIONPacket, *overlay.OverlayAddr) error {
+			<-closed
+			return snettest.ErrClosed
+		},
+	).AnyTimes()
+	conn.EXPECT().Close().DoAndReturn(func() error {
+		close(closed)
+		return nil
+	})
+	return conn
+}
+
+// deliverSCMPHandler has all SCMP packets delivered to the reader.
+type deliverSCMPHandler struct{}
+
+func (deliverSCMPHandler) Handle(*snet.SCIONPacket) error {
+	return snet.ErrDeliverSCMP
+}
@@ -0,0 +1,109 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"net"
+	"syscall"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+// SocketOptions are options of the socket underlying a SCION connection. For
+// connections through the dispatcher, this is the UNIX domain socket to the
+// dispatcher. For connections that bypass the dispatcher, it is the UDP overlay
+// socket. Options that are left at their zero value are not changed.
+type SocketOptions struct {
+	// ReceiveBufferSize is the size of the socket receive buffer (SO_RCVBUF).
+	ReceiveBufferSize int
+	// SendBufferSize is the size of the socket send buffer (SO_SNDBUF).
+	SendBufferSize int
+	// TOS is the IPv4 type of service, or the IPv6 traffic class, of the sent
+	// packets. The DSCP value goes into the upper 6 bits. It is only supported
+	// on overlay sockets, as the dispatcher sends the packets otherwise.
+	TOS int
+	// BindToDevice is the name of the network interface the socket is bound to
+	// (SO_BINDTODEVICE). It is only supported on overlay sockets, and requires
+	// the CAP_NET_RAW capability.
+	BindToDevice string
+}
+
+// Apply sets the options on conn. It returns an error if conn does not give
+// access to its file descriptor, if an overlay only option is set on a socket
+// that is not an overlay socket, or if an option cannot be set.
+func (o *SocketOptions) Apply(conn net.PacketConn) error {
+	if o == nil {
+		return nil
+	}
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return serrors.New("Connection does not support socket options",
+			"type", common.TypeOf(conn))
+	}
+	udpAddr, overlay := conn.LocalAddr().(*net.UDPAddr)
+	if !overlay && (o.TOS != 0 || o.BindToDevice != "") {
+		return serrors.New("TOS and BindToDevice are only supported on overlay sockets")
+	}
+	rawConn, err := sc.SyscallConn()
+	if err != nil {
+		return common.NewBasicError("Unable to access raw connection", err)
+	}
+	var optErr error
+	err = rawConn.Control(func(fd uintptr) {
+		optErr = o.apply(int(fd), overlay && udpAddr.IP.To4() == nil)
+	})
+	if err != nil {
+		return common.NewBasicError("Unable to control raw connection", err)
+	}
+	return optErr
+}
+
+func (o *SocketOptions) apply(fd int, ipv6 bool) error {
+	if o.ReceiveBufferSize != 0 {
+		err := syscall.SetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_RCVBUF,
+			o.ReceiveBufferSize)
+		if err != nil {
+			return common.NewBasicError("Unable to set SO_RCVBUF", err,
+				"size", o.ReceiveBufferSize)
+		}
+	}
+	if o.SendBufferSize != 0 {
+		err := syscall.SetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_SNDBUF,
+			o.SendBufferSize)
+		if err != nil {
+			return common.NewBasicError("Unable to set SO_SNDBUF", err,
+				"size", o.SendBufferSize)
+		}
+	}
+	if o.TOS != 0 {
+		level, opt := syscall.IPPROTO_IP, syscall.IP_TOS
+		if ipv6 {
+			level, opt = syscall.IPPROTO_IPV6, syscall.IPV6_TCLASS
+		}
+		if err := syscall.SetsockoptInt(fd, level, opt, o.TOS); err != nil {
+			return common.NewBasicError("Unable to set TOS", err, "tos", o.TOS)
+		}
+	}
+	if o.BindToDevice != "" {
+		err := syscall.SetsockoptString(fd, syscall.SOL_SOCKET, syscall.SO_BINDTODEVICE,
+			o.BindToDevice)
+		if err != nil {
+			return common.NewBasicError("Unable to set SO_BINDTODEVICE", err,
+				"device", o.BindToDevice)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/sockctrl"
+)
+
+func TestSocketOptionsApply(t *testing.T) {
+	t.Run("nil options are ignored", func(t *testing.T) {
+		var opts *SocketOptions
+		assert.NoError(t, opts.Apply(nil))
+	})
+	t.Run("overlay socket", func(t *testing.T) {
+		conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IP{127, 0, 0, 1}})
+		require.NoError(t, err)
+		defer conn.Close()
+		opts := &SocketOptions{
+			ReceiveBufferSize: 1 << 16,
+			SendBufferSize:    1 << 16,
+			TOS:               0xb8,
+		}
+		require.NoError(t, opts.Apply(conn))
+		rcvBuf, err := sockctrl.GetsockoptInt(conn, syscall.SOL_SOCKET, syscall.SO_RCVBUF)
+		require.NoError(t, err)
+		// The kernel doubles the requested size to account for bookkeeping.
+		assert.True(t, rcvBuf >= 1<<16)
+		sndBuf, err := sockctrl.GetsockoptInt(conn, syscall.SOL_SOCKET, syscall.SO_SNDBUF)
+		require.NoError(t, err)
+		assert.True(t, sndBuf >= 1<<16)
+		tos, err := sockctrl.GetsockoptInt(conn, syscall.IPPROTO_IP, syscall.IP_TOS)
+		require.NoError(t, err)
+		assert.Equal(t, 0xb8, tos)
+	})
+	t.Run("dispatcher socket", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "snet_sockopt")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		conn, err := net.ListenUnixgram("unixgram",
+			&net.UnixAddr{Name: filepath.Join(dir, "sock"), Net: "unixgram"})
+		require.NoError(t, err)
+		defer conn.Close()
+		assert.NoError(t, (&SocketOptions{ReceiveBufferSize: 1 << 16}).Apply(conn))
+		assert.Error(t, (&SocketOptions{TOS: 0xb8}).Apply(conn))
+		assert.Error(t, (&SocketOptions{BindToDevice: "lo"}).Apply(conn))
+	})
+}
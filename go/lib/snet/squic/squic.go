@@ -13,10 +13,20 @@
 // limitations under the License.
 
 // QUIC/SCION implementation.
+//
+// Sessions that are dialed with DialSCIONWithConfig can be resumed and pinned
+// to a path. Resumption requires a client session cache (see Config), and
+// allows latency-sensitive applications to reconnect quickly, e.g., after a
+// network change. Sending 0-RTT data is not supported by the underlying QUIC
+// implementation; resumption only shortens the handshake. The path of a
+// pinned session can be switched at any time with Session.SetPath, without
+// interrupting the session.
 package squic
 
 import (
 	"crypto/tls"
+	"net"
+	"sync"
 
 	"github.com/lucas-clemente/quic-go"
 
@@ -37,6 +47,51 @@ var (
 	srvTlsCfg = &tls.Config{}
 )
 
+// Config configures QUIC sessions over SCION. The zero value is a valid
+// configuration.
+type Config struct {
+	// QUIC is the configuration of the QUIC sessions. If it is nil, the
+	// quic-go defaults are used.
+	QUIC *quic.Config
+	// SessionCache, if set, stores the TLS session state of client sessions.
+	// Reconnecting to the same server then resumes the previous session with
+	// an abbreviated handshake, if the negotiated QUIC version supports it.
+	SessionCache tls.ClientSessionCache
+	// DisableSessionTickets prevents servers from issuing session tickets,
+	// i.e., clients cannot resume their sessions.
+	DisableSessionTickets bool
+	// Path, if set, pins client sessions to the path. Otherwise, snet
+	// resolves the path of every packet.
+	Path snet.Path
+}
+
+// Session is a client QUIC session over SCION, whose path can be controlled
+// by the application.
+type Session struct {
+	quic.Session
+	conn *pinnedConn
+}
+
+// SetPath pins the session to path. Packets that are sent after the call use
+// the new path, the session itself continues uninterrupted. If path is nil,
+// snet resolves the path of every packet.
+func (s *Session) SetPath(path snet.Path) {
+	s.conn.SetPath(path)
+}
+
+// Path returns the path the session is pinned to, or nil if the session is
+// not pinned.
+func (s *Session) Path() snet.Path {
+	return s.conn.Path()
+}
+
+// Close closes the session and the underlying SCION connection.
+func (s *Session) Close() error {
+	err := s.Session.Close()
+	s.conn.Close()
+	return err
+}
+
 func Init(keyPath, pemPath string) error {
 	if keyPath == "" {
 		keyPath = defKeyPath
@@ -69,6 +124,27 @@ func DialSCIONWithBindSVC(network *snet.SCIONNetwork, laddr, raddr, baddr *snet.
 	return quic.Dial(sconn, raddr, "host:0", cliTlsCfg, quicConfig)
 }
 
+// DialSCIONWithConfig dials a QUIC session to raddr, using the session
+// resumption and path pinning settings in cfg.
+func DialSCIONWithConfig(network *snet.SCIONNetwork, laddr, raddr *snet.Addr,
+	cfg Config) (*Session, error) {
+
+	sconn, err := sListen(network, laddr, nil, addr.SvcNone)
+	if err != nil {
+		return nil, err
+	}
+	conn := &pinnedConn{Conn: sconn, path: cfg.Path}
+	tlsCfg := cliTlsCfg.Clone()
+	tlsCfg.ClientSessionCache = cfg.SessionCache
+	// Use dummy hostname, as it's used for SNI, and we're not doing cert verification.
+	session, err := quic.Dial(conn, raddr, "host:0", tlsCfg, cfg.QUIC)
+	if err != nil {
+		sconn.Close()
+		return nil, err
+	}
+	return &Session{Session: session, conn: conn}, nil
+}
+
 func ListenSCION(network *snet.SCIONNetwork, laddr *snet.Addr,
 	quicConfig *quic.Config) (quic.Listener, error) {
 
@@ -78,14 +154,30 @@ func ListenSCION(network *snet.SCIONNetwork, laddr *snet.Addr,
 func ListenSCIONWithBindSVC(network *snet.SCIONNetwork, laddr, baddr *snet.Addr,
 	svc addr.HostSVC, quicConfig *quic.Config) (quic.Listener, error) {
 
-	if len(srvTlsCfg.Certificates) == 0 {
+	return listen(network, laddr, baddr, svc, srvTlsCfg, quicConfig)
+}
+
+// ListenSCIONWithConfig listens for QUIC sessions on laddr, using the session
+// resumption settings in cfg.
+func ListenSCIONWithConfig(network *snet.SCIONNetwork, laddr *snet.Addr,
+	cfg Config) (quic.Listener, error) {
+
+	tlsCfg := srvTlsCfg.Clone()
+	tlsCfg.SessionTicketsDisabled = cfg.DisableSessionTickets
+	return listen(network, laddr, nil, addr.SvcNone, tlsCfg, cfg.QUIC)
+}
+
+func listen(network *snet.SCIONNetwork, laddr, baddr *snet.Addr, svc addr.HostSVC,
+	tlsCfg *tls.Config, quicConfig *quic.Config) (quic.Listener, error) {
+
+	if len(tlsCfg.Certificates) == 0 {
 		return nil, serrors.New("squic: No server TLS certificate configured")
 	}
 	sconn, err := sListen(network, laddr, baddr, svc)
 	if err != nil {
 		return nil, err
 	}
-	return quic.Listen(sconn, srvTlsCfg, quicConfig)
+	return quic.Listen(sconn, tlsCfg, quicConfig)
 }
 
 func sListen(network *snet.SCIONNetwork, laddr, baddr *snet.Addr,
@@ -96,3 +188,32 @@ func sListen(network *snet.SCIONNetwork, laddr, baddr *snet.Addr,
 	}
 	return network.ListenSCIONWithBindSVC("udp4", laddr, baddr, svc, 0)
 }
+
+// pinnedConn sends all packets over the pinned path, if one is set. The path
+// can be changed concurrently to reads and writes.
+type pinnedConn struct {
+	snet.Conn
+	mtx  sync.RWMutex
+	path snet.Path
+}
+
+func (c *pinnedConn) WriteTo(b []byte, raddr net.Addr) (int, error) {
+	path := c.Path()
+	sraddr, ok := raddr.(*snet.Addr)
+	if path == nil || !ok {
+		return c.Conn.WriteTo(b, raddr)
+	}
+	return c.Conn.WriteToVia(b, sraddr, path.Path(), path.OverlayNextHop())
+}
+
+func (c *pinnedConn) SetPath(path snet.Path) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.path = path
+}
+
+func (c *pinnedConn) Path() snet.Path {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return c.path
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package squic
+
+import (
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/snet/mock_snet"
+	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestPinnedConnWriteTo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	raddr := &snet.Addr{
+		IA: xtest.MustParseIA("1-ff00:0:110"),
+		Host: &addr.AppAddr{
+			L3: addr.HostFromIPStr("127.0.0.1"),
+			L4: addr.NewL4UDPInfo(40000),
+		},
+	}
+	newPath := func(raw byte, brIP string) (snet.Path, *spath.Path, *overlay.OverlayAddr) {
+		rawPath := &spath.Path{Raw: []byte{raw}}
+		nextHop, err := overlay.NewOverlayAddr(addr.HostFromIPStr(brIP),
+			addr.NewL4UDPInfo(30041))
+		xtest.FailOnErr(t, err)
+		path := mock_snet.NewMockPath(ctrl)
+		path.EXPECT().Path().Return(rawPath).AnyTimes()
+		path.EXPECT().OverlayNextHop().Return(nextHop).AnyTimes()
+		return path, rawPath, nextHop
+	}
+	path1, rawPath1, nextHop1 := newPath(1, "127.0.0.2")
+	path2, rawPath2, nextHop2 := newPath(2, "127.0.0.3")
+	payload := []byte("hello")
+
+	t.Run("not pinned", func(t *testing.T) {
+		sconn := mock_snet.NewMockConn(ctrl)
+		sconn.EXPECT().WriteTo(payload, raddr).Return(len(payload), nil)
+		conn := &pinnedConn{Conn: sconn}
+		n, err := conn.WriteTo(payload, raddr)
+		assert.NoError(t, err)
+		assert.Equal(t, len(payload), n)
+		assert.Nil(t, conn.Path())
+	})
+	t.Run("non-SCION address", func(t *testing.T) {
+		udpAddr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 40000}
+		sconn := mock_snet.NewMockConn(ctrl)
+		sconn.EXPECT().WriteTo(payload, udpAddr).Return(len(payload), nil)
+		conn := &pinnedConn{Conn: sconn, path: path1}
+		_, err := conn.WriteTo(payload, udpAddr)
+		assert.NoError(t, err)
+	})
+	t.Run("path switch during session", func(t *testing.T) {
+		sconn := mock_snet.NewMockConn(ctrl)
+		gomock.InOrder(
+			sconn.EXPECT().WriteToVia(payload, raddr, rawPath1, nextHop1).
+				Return(len(payload), nil).Times(2),
+			sconn.EXPECT().WriteToVia(payload, raddr, rawPath2, nextHop2).
+				Return(len(payload), nil),
+			sconn.EXPECT().WriteTo(payload, raddr).Return(len(payload), nil),
+		)
+		conn := &pinnedConn{Conn: sconn, path: path1}
+		session := &Session{conn: conn}
+		for i := 0; i < 2; i++ {
+			_, err := conn.WriteTo(payload, raddr)
+			assert.NoError(t, err)
+		}
+		session.SetPath(path2)
+		assert.Equal(t, path2, session.Path())
+		_, err := conn.WriteTo(payload, raddr)
+		assert.NoError(t, err)
+		session.SetPath(nil)
+		assert.Nil(t, session.Path())
+		_, err = conn.WriteTo(payload, raddr)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"context"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/snet/internal/pathsource"
+)
+
+// Possible SVC resolution errors
+const (
+	ErrSVCResolution = "SVC resolution failed"
+	ErrSVCResolved   = "SVC resolution returned an invalid address"
+	ErrDialTimeout   = "dial timed out during SVC resolution"
+)
+
+// SVCResolver resolves SVC addresses to the addresses of concrete service
+// instances.
+type SVCResolver interface {
+	// ResolveSVC asks the AS at the end of path for an instance of svc, and
+	// returns its address. The returned address must not be an SVC address.
+	ResolveSVC(ctx context.Context, path Path, svc addr.HostSVC) (*addr.AppAddr, error)
+}
+
+// SetSVCResolver sets the resolver used by DialSCION and DialSCIONWithBindSVC
+// to resolve SVC destinations. If no resolver is set, SVC destinations are
+// left as is, and delivery is handled by the dispatcher and the border
+// routers.
+func (n *SCIONNetwork) SetSVCResolver(resolver SVCResolver) {
+	n.svcResolver = resolver
+}
+
+// resolveSVC replaces the SVC host in raddr with the address of a concrete
+// service instance. Destinations that are not SVC addresses, multicast SVC
+// destinations, and SVC destinations for which no next hop can be determined
+// (e.g., in the local AS) are returned unchanged. A zero deadline means no deadline.
+func (n *SCIONNetwork) resolveSVC(raddr *Addr, deadline time.Time) (*Addr, error) {
+	if n.svcResolver == nil || raddr.Host == nil {
+		return raddr, nil
+	}
+	svc, ok := raddr.Host.L3.(addr.HostSVC)
+	if !ok || svc.IsMulticast() {
+		return raddr, nil
+	}
+	ctx := context.Background()
+	if !deadline.IsZero() {
+		var cancelF context.CancelFunc
+		ctx, cancelF = context.WithDeadline(ctx, deadline)
+		defer cancelF()
+	}
+
+	lookup := raddr.Copy()
+	if lookup.Path == nil && !n.localIA.Equal(lookup.IA) {
+		var err error
+		lookup.NextHop, lookup.Path, _, err = pathsource.NewPathSource(n.pathResolver,
+			n.nextHops, n.quarantine).Get(ctx, n.localIA, lookup.IA, 0)
+		if err != nil {
+			return nil, common.NewBasicError(ErrPath, err)
+		}
+	}
+	if lookup.NextHop == nil {
+		return raddr, nil
+	}
+	path, err := lookup.GetPath()
+	if err != nil {
+		return nil, common.NewBasicError(ErrSVCResolution, err, "svc", svc, "ia", raddr.IA)
+	}
+	host, err := n.svcResolver.ResolveSVC(ctx, path, svc)
+	if err != nil {
+		return nil, common.NewBasicError(ErrSVCResolution, err, "svc", svc, "ia", raddr.IA)
+	}
+	if host == nil || host.L3 == nil || host.L3.Type() == addr.HostTypeSVC {
+		return nil, common.NewBasicError(ErrSVCResolved, nil, "svc", svc, "addr", host)
+	}
+	resolved := raddr.Copy()
+	resolved.Host = host.Copy()
+	if resolved.Host.L4 == nil {
+		resolved.Host.L4 = raddr.Host.L4
+	}
+	return resolved, nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+type svcResolverFunc func(ctx context.Context, path Path,
+	svc addr.HostSVC) (*addr.AppAddr, error)
+
+func (f svcResolverFunc) ResolveSVC(ctx context.Context, path Path,
+	svc addr.HostSVC) (*addr.AppAddr, error) {
+
+	return f(ctx, path, svc)
+}
+
+func TestResolveSVC(t *testing.T) {
+	localIA := xtest.MustParseIA("1-ff00:0:110")
+	nextHop, err := overlay.NewOverlayAddr(addr.HostFromIPStr("127.0.0.1"),
+		addr.NewL4UDPInfo(overlay.EndhostPort))
+	require.NoError(t, err)
+	svcAddr := &Addr{
+		IA:      localIA,
+		Host:    &addr.AppAddr{L3: addr.SvcPS, L4: addr.NewL4UDPInfo(0)},
+		NextHop: nextHop,
+	}
+	instance := &addr.AppAddr{
+		L3: addr.HostFromIPStr("127.0.0.2"),
+		L4: addr.NewL4UDPInfo(30041),
+	}
+	resolveTo := func(address *addr.AppAddr, err error) SVCResolver {
+		return svcResolverFunc(func(_ context.Context, path Path,
+			svc addr.HostSVC) (*addr.AppAddr, error) {
+
+			assert.Equal(t, addr.SvcPS, svc)
+			assert.Equal(t, localIA, path.Destination())
+			assert.Equal(t, nextHop, path.OverlayNextHop())
+			return address, err
+		})
+	}
+	tests := map[string]struct {
+		Resolver      SVCResolver
+		Remote        *Addr
+		Expected      *Addr
+		ExpectedError bool
+	}{
+		"no resolver": {
+			Remote:   svcAddr,
+			Expected: svcAddr,
+		},
+		"no SVC address": {
+			Resolver: resolveTo(nil, errors.New("must not be called")),
+			Remote:   MustParseAddr("1-ff00:0:110,[127.0.0.3]:80"),
+			Expected: MustParseAddr("1-ff00:0:110,[127.0.0.3]:80"),
+		},
+		"no next hop in local AS": {
+			Resolver: resolveTo(nil, errors.New("must not be called")),
+			Remote: &Addr{
+				IA:   localIA,
+				Host: &addr.AppAddr{L3: addr.SvcPS, L4: addr.NewL4UDPInfo(0)},
+			},
+			Expected: &Addr{
+				IA:   localIA,
+				Host: &addr.AppAddr{L3: addr.SvcPS, L4: addr.NewL4UDPInfo(0)},
+			},
+		},
+		"multicast SVC address": {
+			Resolver: resolveTo(nil, errors.New("must not be called")),
+			Remote: &Addr{
+				IA:      localIA,
+				Host:    &addr.AppAddr{L3: addr.SvcPS.Multicast(), L4: addr.NewL4UDPInfo(0)},
+				NextHop: nextHop,
+			},
+			Expected: &Addr{
+				IA:      localIA,
+				Host:    &addr.AppAddr{L3: addr.SvcPS.Multicast(), L4: addr.NewL4UDPInfo(0)},
+				NextHop: nextHop,
+			},
+		},
+		"resolved": {
+			Resolver: resolveTo(instance, nil),
+			Remote:   svcAddr,
+			Expected: &Addr{
+				IA:      localIA,
+				Host:    instance,
+				NextHop: nextHop,
+			},
+		},
+		"resolver error": {
+			Resolver:      resolveTo(nil, errors.New("test error")),
+			Remote:        svcAddr,
+			ExpectedError: true,
+		},
+		"resolved to SVC address": {
+			Resolver: resolveTo(&addr.AppAddr{L3: addr.SvcCS,
+				L4: addr.NewL4UDPInfo(0)}, nil),
+			Remote:        svcAddr,
+			ExpectedError: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			n := &SCIONNetwork{localIA: localIA}
+			n.SetSVCResolver(test.Resolver)
+			resolved, err := n.resolveSVC(test.Remote, time.Now().Add(time.Second))
+			if test.ExpectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.Expected, resolved)
+		})
+	}
+}
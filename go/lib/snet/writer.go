@@ -25,8 +25,11 @@ import (
 	"github.com/scionproto/scion/go/lib/l4"
 	"github.com/scionproto/scion/go/lib/overlay"
 	"github.com/scionproto/scion/go/lib/pathmgr"
+	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/snet/internal/ctxmonitor"
 	"github.com/scionproto/scion/go/lib/snet/internal/pathsource"
+	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/lib/spkt"
 )
 
 // Possible write errors
@@ -39,12 +42,25 @@ const (
 	ErrBadOverlay           = "overlay address not set, and construction from SCION address failed"
 	ErrMustHavePath         = "overlay address set, but no path set"
 	ErrPath                 = "no path set, and error during path resolution"
+	ErrPacketTooBig         = "packet exceeds path MTU"
+	ErrSVCHosts             = "unable to resolve hosts of multicast SVC address"
+	ErrMulticast            = "unable to send multicast packet to all hosts"
 )
 
 const (
 	DefaultPathQueryTimeout = 5 * time.Second
 )
 
+// FlowID identifies an application-level flow. All packets of a flow to a
+// destination are sent on the same path, as long as that path is available,
+// while different flows are spread across the available paths. The zero value
+// NoFlow means that the packet does not belong to a flow, and an arbitrary
+// path is used.
+type FlowID uint64
+
+// NoFlow is the flow ID of packets that do not belong to a flow.
+const NoFlow FlowID = 0
+
 type scionConnWriter struct {
 	base     *scionConnBase
 	conn     PacketConn
@@ -52,24 +68,48 @@ type scionConnWriter struct {
 
 	mtx    sync.Mutex
 	buffer common.RawBytes
+	// mtu is the MTU of the path used by the most recent write, 0 if unknown.
+	mtu uint16
+	// enforceMTU indicates whether writes exceeding the path MTU are rejected.
+	enforceMTU bool
+	// flow is the flow ID of packets written without an explicit flow ID.
+	flow FlowID
+	// pacer delays writes that exceed the rate limit of the connection.
+	pacer *pacer
 }
 
 func newScionConnWriter(base *scionConnBase, pr pathmgr.Resolver,
 	conn PacketConn) *scionConnWriter {
 
+	var nextHops *pathsource.NextHopCache
+	var quarantine *pathsource.Quarantine
+	var limit RateLimit
+	if base.scionNet != nil {
+		nextHops = base.scionNet.nextHops
+		quarantine = base.scionNet.quarantine
+		limit = base.scionNet.writeRateLimit
+	}
 	return &scionConnWriter{
 		base: base,
 		conn: conn,
 		resolver: &remoteAddressResolver{
 			localIA:      base.laddr.IA,
-			pathResolver: pathsource.NewPathSource(pr),
+			pathResolver: pathsource.NewPathSource(pr, nextHops, quarantine),
 			monitor:      ctxmonitor.NewMonitor(),
 		},
 		buffer: make(common.RawBytes, common.MaxMTU),
+		pacer:  newPacer(limit),
 	}
 }
 
 // WriteToSCION sends b to raddr.
+//
+// If the host of raddr is a multicast SVC address, b is delivered to all
+// instances of the service in the destination AS. In remote ASes, the border
+// router fans out the packet; in the local AS, a copy is sent to every host
+// running an instance of the service, as reported by SCIOND. The replies of
+// the instances are received on this conn, with their unicast addresses as
+// source.
 func (c *scionConnWriter) WriteToSCION(b []byte, raddr *Addr) (int, error) {
 	return c.write(b, raddr)
 }
@@ -82,23 +122,93 @@ func (c *scionConnWriter) WriteTo(b []byte, raddr net.Addr) (int, error) {
 	return c.WriteToSCION(b, sraddr)
 }
 
+// WriteToFlow is like WriteToSCION, but the path is chosen based on flow
+// instead of the flow ID of the connection (see SetFlowID). Packets with the
+// same flow ID are sent on the same path.
+func (c *scionConnWriter) WriteToFlow(b []byte, raddr *Addr, flow FlowID) (int, error) {
+	return c.writeFlow(b, raddr, flow)
+}
+
 // Write sends b through a connection with fixed remote address. If the remote
 // address for the connection is unknown, Write returns an error.
 func (c *scionConnWriter) Write(b []byte) (int, error) {
 	return c.write(b, nil)
 }
 
+// WriteToVia writes b to raddr using path and nextHop, ignoring any path and
+// next-hop information contained in raddr. raddr is never mutated, so the same
+// address can be shared by concurrent writers that each pick their own path.
+//
+// If both path and nextHop are nil, the path is resolved as in WriteToSCION.
+// If the destination is in a remote AS and only one of them is nil, an error
+// is returned, as the packet can be forwarded only with both. If the
+// destination is in the local AS, path must be nil; a nil nextHop is then
+// derived from the host address in raddr.
+func (c *scionConnWriter) WriteToVia(b []byte, raddr *Addr, path *spath.Path,
+	nextHop *overlay.OverlayAddr) (int, error) {
+
+	if raddr == nil {
+		return 0, common.NewBasicError(ErrAddressIsNil, nil)
+	}
+	viaAddr := &Addr{
+		IA:      raddr.IA,
+		Host:    raddr.Host,
+		Path:    path,
+		NextHop: nextHop,
+	}
+	return c.write(b, viaAddr)
+}
+
 func (c *scionConnWriter) write(b []byte, raddr *Addr) (int, error) {
-	raddr, err := c.resolver.resolveAddrPair(c.base.raddr, raddr)
+	return c.writeFlow(b, raddr, c.flowID())
+}
+
+func (c *scionConnWriter) writeFlow(b []byte, raddr *Addr, flow FlowID) (int, error) {
+	raddr, mtu, err := c.resolver.resolveAddrPair(c.base.raddr, raddr, flow)
+	if err != nil {
+		return 0, err
+	}
+	if raddr.NextHop == nil {
+		return c.writeLocalMulticast(b, raddr)
+	}
+	return c.writeWithLock(b, raddr, mtu)
+}
+
+// writeLocalMulticast sends a copy of b to every host in the local AS that
+// runs an instance of the multicast SVC address in raddr. An error is returned
+// if any of the copies could not be sent.
+func (c *scionConnWriter) writeLocalMulticast(b []byte, raddr *Addr) (int, error) {
+	hosts, err := c.resolver.resolveSVCHosts(raddr.Host.L3.(addr.HostSVC))
 	if err != nil {
 		return 0, err
 	}
-	return c.writeWithLock(b, raddr)
+	var errs []error
+	for _, host := range hosts {
+		hostAddr := raddr.Copy()
+		hostAddr.NextHop = host
+		if _, err := c.writeWithLock(b, hostAddr, 0); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return 0, common.NewBasicError(ErrMulticast, errs[0], "failed", len(errs),
+			"hosts", len(hosts))
+	}
+	return len(b), nil
 }
 
-func (c *scionConnWriter) writeWithLock(b []byte, raddr *Addr) (int, error) {
+func (c *scionConnWriter) writeWithLock(b []byte, raddr *Addr, mtu uint16) (int, error) {
+	size := packetLen(c.base.laddr, raddr, len(b))
+	if err := c.pacer.wait(size); err != nil {
+		return 0, err
+	}
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
+	c.mtu = mtu
+	if c.enforceMTU && mtu != 0 && size > int(mtu) {
+		return 0, common.NewBasicError(ErrPacketTooBig, nil, "size", size, "mtu", mtu,
+			"max_payload", len(b)-(size-int(mtu)))
+	}
 	pkt := &SCIONPacket{
 		Bytes: Bytes(c.buffer),
 		SCIONPacketInfo: SCIONPacketInfo{
@@ -119,11 +229,70 @@ func (c *scionConnWriter) writeWithLock(b []byte, raddr *Addr) (int, error) {
 	return len(b), nil
 }
 
+// MTU returns the MTU of the path currently selected to send packets to the
+// remote address of the connection. If the connection has no remote address,
+// the MTU of the path used by the most recent write is returned. The result is
+// 0 if the MTU is unknown, e.g., if the destination is in the local AS or the
+// path was set explicitly by the caller.
+func (c *scionConnWriter) MTU() uint16 {
+	if c.base.raddr != nil {
+		_, mtu, err := c.resolver.resolveFlowAddr(c.base.raddr, c.flowID())
+		if err != nil {
+			return 0
+		}
+		return mtu
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.mtu
+}
+
+// SetEnforceMTU sets whether writes that result in packets larger than the
+// MTU of the path are rejected with an error, instead of being sent and
+// dropped by a router on the way. Packets on paths with an unknown MTU are
+// always sent.
+func (c *scionConnWriter) SetEnforceMTU(enforce bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.enforceMTU = enforce
+}
+
+// SetFlowID sets the flow ID of the packets that are written without an
+// explicit flow ID, i.e., all writes except WriteToFlow and WriteToVia. All
+// such packets to a destination are sent on the same path. By default, the
+// flow ID is NoFlow.
+func (c *scionConnWriter) SetFlowID(flow FlowID) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.flow = flow
+}
+
+// SetWriteRateLimit sets the rate limit of the writes on the connection,
+// replacing the limit configured for the network (see
+// NetworkConfig.WriteRateLimit). Writes that exceed the limit are delayed; if
+// a write cannot be sent before the write deadline, it fails with a timeout
+// error. The zero value removes the limit.
+func (c *scionConnWriter) SetWriteRateLimit(limit RateLimit) {
+	c.pacer.setLimit(limit, time.Now())
+}
+
+// WriteRateLimit returns the rate limit of the writes on the connection.
+func (c *scionConnWriter) WriteRateLimit() RateLimit {
+	return c.pacer.getLimit()
+}
+
+func (c *scionConnWriter) flowID() FlowID {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.flow
+}
+
 func (c *scionConnWriter) SetWriteDeadline(t time.Time) error {
 	if err := c.conn.SetWriteDeadline(t); err != nil {
 		return err
 	}
 	c.resolver.monitor.SetDeadline(t)
+	c.pacer.setDeadline(t)
 	return nil
 }
 
@@ -142,31 +311,42 @@ type remoteAddressResolver struct {
 	monitor ctxmonitor.Monitor
 }
 
-func (r *remoteAddressResolver) resolveAddrPair(connAddr, argAddr *Addr) (*Addr, error) {
+// resolveAddrPair returns the resolved address and the MTU of its path, 0 if
+// unknown. If a path is resolved, the path assigned to flow is used.
+func (r *remoteAddressResolver) resolveAddrPair(connAddr, argAddr *Addr,
+	flow FlowID) (*Addr, uint16, error) {
+
 	switch {
 	case connAddr == nil && argAddr == nil:
-		return nil, common.NewBasicError(ErrNoAddr, nil)
+		return nil, 0, common.NewBasicError(ErrNoAddr, nil)
 	case connAddr != nil && argAddr != nil:
-		return nil, common.NewBasicError(ErrDuplicateAddr, nil)
+		return nil, 0, common.NewBasicError(ErrDuplicateAddr, nil)
 	case connAddr != nil:
-		return r.resolveAddr(connAddr)
+		return r.resolveFlowAddr(connAddr, flow)
 	default:
 		// argAddr != nil
-		return r.resolveAddr(argAddr)
+		return r.resolveFlowAddr(argAddr, flow)
 	}
 }
 
-func (r *remoteAddressResolver) resolveAddr(address *Addr) (*Addr, error) {
+func (r *remoteAddressResolver) resolveAddr(address *Addr) (*Addr, uint16, error) {
+	return r.resolveFlowAddr(address, NoFlow)
+}
+
+func (r *remoteAddressResolver) resolveFlowAddr(address *Addr, flow FlowID) (*Addr, uint16,
+	error) {
+
 	if address == nil {
-		return nil, common.NewBasicError(ErrAddressIsNil, nil)
+		return nil, 0, common.NewBasicError(ErrAddressIsNil, nil)
 	}
 	if address.Host == nil {
-		return nil, common.NewBasicError(ErrNoApplicationAddress, nil)
+		return nil, 0, common.NewBasicError(ErrNoApplicationAddress, nil)
 	}
 	if r.localIA.Equal(address.IA) {
-		return r.resolveLocalDestination(address)
+		address, err := r.resolveLocalDestination(address)
+		return address, 0, err
 	}
-	return r.resolveRemoteDestination(address)
+	return r.resolveRemoteDestination(address, flow)
 }
 
 func (r *remoteAddressResolver) resolveLocalDestination(address *Addr) (*Addr, error) {
@@ -174,34 +354,58 @@ func (r *remoteAddressResolver) resolveLocalDestination(address *Addr) (*Addr, e
 		return nil, common.NewBasicError(ErrExtraPath, nil)
 	}
 	if address.NextHop == nil {
+		if svc, ok := address.Host.L3.(addr.HostSVC); ok && svc.IsMulticast() {
+			// The next hops are resolved per host when writing, see
+			// writeLocalMulticast.
+			return address, nil
+		}
 		return addOverlayFromScionAddress(address)
 	}
 	return address, nil
 }
 
-func (r *remoteAddressResolver) resolveRemoteDestination(address *Addr) (*Addr, error) {
+// resolveSVCHosts returns the overlay addresses of the hosts running instances
+// of svc in the local AS.
+func (r *remoteAddressResolver) resolveSVCHosts(svc addr.HostSVC) ([]*overlay.OverlayAddr,
+	error) {
+
+	ctx, cancelF := r.monitor.WithTimeout(context.Background(), DefaultPathQueryTimeout)
+	defer cancelF()
+	hosts, err := r.pathResolver.SVCHosts(ctx, svc)
+	if err != nil {
+		return nil, common.NewBasicError(ErrSVCHosts, err, "svc", svc)
+	}
+	return hosts, nil
+}
+
+func (r *remoteAddressResolver) resolveRemoteDestination(address *Addr,
+	flow FlowID) (*Addr, uint16, error) {
+
 	switch {
 	case address.Path != nil && address.NextHop == nil:
-		return nil, common.NewBasicError(ErrBadOverlay, nil)
+		return nil, 0, common.NewBasicError(ErrBadOverlay, nil)
 	case address.Path == nil && address.NextHop != nil:
-		return nil, common.NewBasicError(ErrMustHavePath, nil)
+		return nil, 0, common.NewBasicError(ErrMustHavePath, nil)
 	case address.Path != nil:
-		return address, nil
+		return address, 0, nil
 	default:
-		return r.addPath(address)
+		return r.addPath(address, flow)
 	}
 }
 
-func (r *remoteAddressResolver) addPath(address *Addr) (*Addr, error) {
+func (r *remoteAddressResolver) addPath(address *Addr, flow FlowID) (*Addr, uint16, error) {
 	var err error
+	var mtu uint16
 	address = address.Copy()
 	ctx, cancelF := r.monitor.WithTimeout(context.Background(), DefaultPathQueryTimeout)
 	defer cancelF()
-	address.NextHop, address.Path, err = r.pathResolver.Get(ctx, r.localIA, address.IA)
+	address.NextHop, address.Path, mtu, err = r.pathResolver.Get(ctx, r.localIA, address.IA,
+		uint64(flow))
 	if err != nil {
-		return nil, common.NewBasicError(ErrPath, nil)
+		return nil, 0, serrors.WithCode(common.NewBasicError(ErrPath, err),
+			serrors.CodeUnavailable)
 	}
-	return address, nil
+	return address, mtu, nil
 }
 
 func addOverlayFromScionAddress(address *Addr) (*Addr, error) {
@@ -214,3 +418,13 @@ func addOverlayFromScionAddress(address *Addr) (*Addr, error) {
 	}
 	return address, nil
 }
+
+// packetLen returns the length of the SCION/UDP packet carrying a payload of
+// pldLen bytes from laddr to raddr.
+func packetLen(laddr, raddr *Addr, pldLen int) int {
+	l := spkt.CmnHdrLen + spkt.AddrHdrLen(raddr.Host.L3, laddr.Host.L3) + l4.UDPLen + pldLen
+	if raddr.Path != nil {
+		l += len(raddr.Path.Raw)
+	}
+	return l
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/golang/mock/gomock"
 	. "github.com/smartystreets/goconvey/convey"
 
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/mocks/net/mock_net"
 	"github.com/scionproto/scion/go/lib/overlay"
@@ -48,14 +49,15 @@ func TestConnRemoteAddressResolver(t *testing.T) {
 		defer ctrl.Finish()
 		resolver := &remoteAddressResolver{monitor: buildNullMonitorMock(ctrl)}
 		Convey("If both addresses are unknown, error out", func() {
-			address, err := resolver.resolveAddrPair(nil, nil)
+			address, _, err := resolver.resolveAddrPair(nil, nil, NoFlow)
 			SoMsg("err", err, ShouldNotBeNil)
 			SoMsg("address", address, ShouldBeNil)
 		})
 		Convey("If both address are known, error out", func() {
 			connRemoteAddress := MustParseAddr("1-ff00:0:113,[127.0.0.1]:80")
 			argRemoteAddress := MustParseAddr("1-ff00:0:110,[127.0.0.1]:80")
-			address, err := resolver.resolveAddrPair(connRemoteAddress, argRemoteAddress)
+			address, _, err := resolver.resolveAddrPair(connRemoteAddress, argRemoteAddress,
+				NoFlow)
 			SoMsg("err", err, ShouldNotBeNil)
 			SoMsg("address", address, ShouldBeNil)
 		})
@@ -73,13 +75,13 @@ func TestRemoteAddressResolver(t *testing.T) {
 			monitor:      buildNullMonitorMock(ctrl),
 		}
 		Convey("error if address is nil", func() {
-			address, err := resolver.resolveAddr(nil)
+			address, _, err := resolver.resolveAddr(nil)
 			SoMsg("err", common.GetErrorMsg(err), ShouldEqual, ErrAddressIsNil)
 			SoMsg("address", address, ShouldBeNil)
 		})
 		Convey("error if app address is unset", func() {
 			address := &Addr{}
-			address, err := resolver.resolveAddr(address)
+			address, _, err := resolver.resolveAddr(address)
 			SoMsg("err", common.GetErrorMsg(err), ShouldEqual, ErrNoApplicationAddress)
 			SoMsg("address", address, ShouldBeNil)
 		})
@@ -87,18 +89,18 @@ func TestRemoteAddressResolver(t *testing.T) {
 			inAddress := MustParseAddr("1-ff00:0:110,[127.0.0.1]:80")
 			Convey("error if path set.", func() {
 				inAddress.Path = &spath.Path{}
-				outAddress, err := resolver.resolveAddr(inAddress)
+				outAddress, _, err := resolver.resolveAddr(inAddress)
 				SoMsg("err", common.GetErrorMsg(err), ShouldEqual, ErrExtraPath)
 				SoMsg("address", outAddress, ShouldBeNil)
 			})
 			Convey("return same address if path unset, and overlay address set.", func() {
 				inAddress.NextHop = &overlay.OverlayAddr{}
-				outAddress, err := resolver.resolveAddr(inAddress)
+				outAddress, _, err := resolver.resolveAddr(inAddress)
 				SoMsg("err", err, ShouldBeNil)
 				SoMsg("address", outAddress, ShouldEqual, inAddress)
 			})
 			Convey("inherit overlay data if overlay address unset.", func() {
-				outAddress, err := resolver.resolveAddr(inAddress)
+				outAddress, _, err := resolver.resolveAddr(inAddress)
 				SoMsg("err", err, ShouldBeNil)
 				SoMsg("address", outAddress, ShouldNotBeNil)
 				SoMsg("overlay addr", outAddress.NextHop.L3(), ShouldResemble, outAddress.Host.L3)
@@ -110,47 +112,99 @@ func TestRemoteAddressResolver(t *testing.T) {
 			inAddress := MustParseAddr("1-ff00:0:113,[127.0.0.1]:80")
 			Convey("error if path set but overlay address unset.", func() {
 				inAddress.Path = &spath.Path{}
-				outAddress, err := resolver.resolveAddr(inAddress)
+				outAddress, _, err := resolver.resolveAddr(inAddress)
 				SoMsg("err", common.GetErrorMsg(err), ShouldEqual, ErrBadOverlay)
 				SoMsg("address", outAddress, ShouldBeNil)
 			})
 			Convey("error if overlay set but path unset.", func() {
 				inAddress.NextHop = &overlay.OverlayAddr{}
-				outAddress, err := resolver.resolveAddr(inAddress)
+				outAddress, _, err := resolver.resolveAddr(inAddress)
 				SoMsg("err", common.GetErrorMsg(err), ShouldEqual, ErrMustHavePath)
 				SoMsg("address", outAddress, ShouldBeNil)
 			})
 			Convey("return same address if path and overlay set.", func() {
 				inAddress.Path = &spath.Path{}
 				inAddress.NextHop = &overlay.OverlayAddr{}
-				outAddress, err := resolver.resolveAddr(inAddress)
+				outAddress, _, err := resolver.resolveAddr(inAddress)
 				SoMsg("err", err, ShouldBeNil)
 				SoMsg("address", outAddress, ShouldResemble, inAddress)
 			})
 			Convey("request path if path and overlay unset", func() {
 				Convey("if request not successful, error.", func() {
-					pathSource.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).
-						Return(nil, nil, fmt.Errorf("some error"))
-					outAddress, err := resolver.resolveAddr(inAddress)
+					pathSource.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+						Return(nil, nil, uint16(0), fmt.Errorf("some error"))
+					outAddress, _, err := resolver.resolveAddr(inAddress)
 					SoMsg("err", common.GetErrorMsg(err), ShouldEqual, ErrPath)
 					SoMsg("address", outAddress, ShouldBeNil)
 				})
 				Convey("if request successful, return address.", func() {
 					path := &spath.Path{}
 					overlayAddr := &overlay.OverlayAddr{}
-					pathSource.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).
-						Return(overlayAddr, path, nil)
-					outAddress, err := resolver.resolveAddr(inAddress)
+					pathSource.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+						Return(overlayAddr, path, uint16(1280), nil)
+					outAddress, mtu, err := resolver.resolveAddr(inAddress)
 					SoMsg("err", err, ShouldBeNil)
+					SoMsg("mtu", mtu, ShouldEqual, 1280)
 					SoMsg("address", outAddress, ShouldNotBeNil)
 					SoMsg("path", outAddress.Path, ShouldEqual, path)
 					SoMsg("overlay", outAddress.NextHop, ShouldEqual, overlayAddr)
 				})
+				Convey("the flow ID is used to pick the path.", func() {
+					path := &spath.Path{}
+					pathSource.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any(), uint64(42)).
+						Return(&overlay.OverlayAddr{}, path, uint16(0), nil)
+					outAddress, _, err := resolver.resolveAddrPair(nil, inAddress, 42)
+					SoMsg("err", err, ShouldBeNil)
+					SoMsg("path", outAddress.Path, ShouldEqual, path)
+				})
 			})
 		})
 	})
 }
 
+func TestWriteLocalMulticast(t *testing.T) {
+	Convey("Given an snet write connection", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		pathSource := mock_pathsource.NewMockPathSource(ctrl)
+		packetConn := &recordingPacketConn{}
+		conn := &scionConnWriter{
+			base: &scionConnBase{
+				laddr: MustParseAddr("1-ff00:0:110,[127.0.0.1]:80"),
+			},
+			conn: packetConn,
+			resolver: &remoteAddressResolver{
+				localIA:      xtest.MustParseIA("1-ff00:0:110"),
+				pathResolver: pathSource,
+				monitor:      buildNullMonitorMock(ctrl),
+			},
+			buffer: make(common.RawBytes, common.MaxMTU),
+		}
+		raddr := &Addr{
+			IA:   xtest.MustParseIA("1-ff00:0:110"),
+			Host: &addr.AppAddr{L3: addr.SvcBS.Multicast(), L4: addr.NewL4UDPInfo(0)},
+		}
+		Convey("a copy is sent to every host of the service", func() {
+			hosts := []*overlay.OverlayAddr{{}, {}}
+			pathSource.EXPECT().SVCHosts(gomock.Any(), addr.SvcBS.Multicast()).
+				Return(hosts, nil)
+			n, err := conn.WriteToSCION([]byte{1, 2, 3}, raddr)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("n", n, ShouldEqual, 3)
+			SoMsg("next hops", packetConn.nextHops, ShouldResemble, hosts)
+			SoMsg("raddr next hop", raddr.NextHop, ShouldBeNil)
+		})
+		Convey("an error is returned if the hosts cannot be resolved", func() {
+			pathSource.EXPECT().SVCHosts(gomock.Any(), gomock.Any()).
+				Return(nil, fmt.Errorf("some error"))
+			_, err := conn.WriteToSCION([]byte{1, 2, 3}, raddr)
+			SoMsg("err", common.GetErrorMsg(err), ShouldEqual, ErrSVCHosts)
+			SoMsg("next hops", packetConn.nextHops, ShouldBeEmpty)
+		})
+	})
+}
+
 func TestSetDeadline(t *testing.T) {
 	Convey("Given an snet write connection", t, func() {
 		ctrl := gomock.NewController(t)
@@ -196,6 +250,161 @@ func TestSetDeadline(t *testing.T) {
 	})
 }
 
+func TestWriteToVia(t *testing.T) {
+	Convey("Given an snet write connection", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		packetConn := &recordingPacketConn{}
+		conn := newScionConnWriter(&scionConnBase{
+			laddr: MustParseAddr("1-ff00:0:110,[127.0.0.1]:80"),
+		}, mock_pathmgr.NewMockResolver(ctrl), packetConn)
+		raddr := MustParseAddr("1-ff00:0:113,[127.0.0.2]:80")
+		raddr.Path = &spath.Path{Raw: []byte{1}}
+		raddr.NextHop = &overlay.OverlayAddr{}
+		Convey("WriteToVia uses the supplied path and does not mutate raddr", func() {
+			path := &spath.Path{Raw: []byte{2}}
+			nextHop := &overlay.OverlayAddr{}
+			n, err := conn.WriteToVia([]byte{1, 2, 3}, raddr, path, nextHop)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("n", n, ShouldEqual, 3)
+			SoMsg("path", packetConn.path, ShouldEqual, path)
+			SoMsg("next hop", packetConn.nextHop, ShouldEqual, nextHop)
+			SoMsg("raddr path", raddr.Path.Raw, ShouldResemble, common.RawBytes{1})
+		})
+		Convey("WriteToVia with a path but no next hop errors out", func() {
+			_, err := conn.WriteToVia([]byte{1, 2, 3}, raddr, &spath.Path{Raw: []byte{2}}, nil)
+			SoMsg("err", common.GetErrorMsg(err), ShouldEqual, ErrBadOverlay)
+		})
+		Convey("WriteToVia with a next hop but no path errors out", func() {
+			_, err := conn.WriteToVia([]byte{1, 2, 3}, raddr, nil, &overlay.OverlayAddr{})
+			SoMsg("err", common.GetErrorMsg(err), ShouldEqual, ErrMustHavePath)
+		})
+		Convey("WriteToVia with nil raddr errors out", func() {
+			_, err := conn.WriteToVia([]byte{1, 2, 3}, nil, nil, nil)
+			SoMsg("err", common.GetErrorMsg(err), ShouldEqual, ErrAddressIsNil)
+		})
+	})
+}
+
+func TestWriteToFlow(t *testing.T) {
+	Convey("Given an snet write connection", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		pathSource := mock_pathsource.NewMockPathSource(ctrl)
+		packetConn := &recordingPacketConn{}
+		conn := &scionConnWriter{
+			base: &scionConnBase{
+				laddr: MustParseAddr("1-ff00:0:110,[127.0.0.1]:80"),
+			},
+			conn: packetConn,
+			resolver: &remoteAddressResolver{
+				localIA:      xtest.MustParseIA("1-ff00:0:110"),
+				pathResolver: pathSource,
+				monitor:      buildNullMonitorMock(ctrl),
+			},
+			buffer: make(common.RawBytes, common.MaxMTU),
+		}
+		raddr := MustParseAddr("1-ff00:0:113,[127.0.0.2]:80")
+		path := &spath.Path{Raw: make([]byte, 16)}
+		Convey("WriteToFlow resolves the path of the flow", func() {
+			pathSource.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any(), uint64(42)).
+				Return(&overlay.OverlayAddr{}, path, uint16(0), nil)
+			_, err := conn.WriteToFlow([]byte{1, 2, 3}, raddr, 42)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("path", packetConn.path, ShouldEqual, path)
+		})
+		Convey("Writes use the flow ID of the connection", func() {
+			conn.SetFlowID(7)
+			pathSource.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any(), uint64(7)).
+				Return(&overlay.OverlayAddr{}, path, uint16(0), nil)
+			_, err := conn.WriteToSCION([]byte{1, 2, 3}, raddr)
+			SoMsg("err", err, ShouldBeNil)
+		})
+		Convey("Writes without flow ID use NoFlow", func() {
+			pathSource.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any(), uint64(NoFlow)).
+				Return(&overlay.OverlayAddr{}, path, uint16(0), nil)
+			_, err := conn.WriteToSCION([]byte{1, 2, 3}, raddr)
+			SoMsg("err", err, ShouldBeNil)
+		})
+	})
+}
+
+func TestWriterMTU(t *testing.T) {
+	Convey("Given an snet write connection with a path of MTU 100", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		pathSource := mock_pathsource.NewMockPathSource(ctrl)
+		// The SCION/UDP header with IPv4 hosts and a 16 byte path has 56 bytes.
+		pathSource.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+			Return(&overlay.OverlayAddr{}, &spath.Path{Raw: make([]byte, 16)}, uint16(100), nil)
+		raddr := MustParseAddr("1-ff00:0:113,[127.0.0.2]:80")
+		newWriter := func(raddr *Addr) *scionConnWriter {
+			return &scionConnWriter{
+				base: &scionConnBase{
+					laddr: MustParseAddr("1-ff00:0:110,[127.0.0.1]:80"),
+					raddr: raddr,
+				},
+				conn: &recordingPacketConn{},
+				resolver: &remoteAddressResolver{
+					localIA:      xtest.MustParseIA("1-ff00:0:110"),
+					pathResolver: pathSource,
+					monitor:      buildNullMonitorMock(ctrl),
+				},
+				buffer: make(common.RawBytes, common.MaxMTU),
+			}
+		}
+		Convey("A connected conn reports the MTU of the selected path", func() {
+			conn := newWriter(raddr)
+			SoMsg("mtu", conn.MTU(), ShouldEqual, 100)
+		})
+		Convey("An unconnected conn reports the MTU of the last write", func() {
+			conn := newWriter(nil)
+			SoMsg("mtu before write", conn.MTU(), ShouldEqual, 0)
+			_, err := conn.WriteTo([]byte{1, 2, 3}, raddr)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("mtu after write", conn.MTU(), ShouldEqual, 100)
+		})
+		Convey("Oversized writes are sent if enforcement is disabled", func() {
+			conn := newWriter(raddr)
+			n, err := conn.Write(make([]byte, 45))
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("n", n, ShouldEqual, 45)
+		})
+		Convey("If enforcement is enabled", func() {
+			conn := newWriter(raddr)
+			conn.SetEnforceMTU(true)
+			Convey("writes that fit the MTU are sent", func() {
+				n, err := conn.Write(make([]byte, 44))
+				SoMsg("err", err, ShouldBeNil)
+				SoMsg("n", n, ShouldEqual, 44)
+			})
+			Convey("oversized writes are rejected", func() {
+				n, err := conn.Write(make([]byte, 45))
+				SoMsg("err", common.GetErrorMsg(err), ShouldEqual, ErrPacketTooBig)
+				SoMsg("n", n, ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+// recordingPacketConn records the path and next hop of the last written
+// packet.
+type recordingPacketConn struct {
+	PacketConn
+	path     *spath.Path
+	nextHop  *overlay.OverlayAddr
+	nextHops []*overlay.OverlayAddr
+}
+
+func (c *recordingPacketConn) WriteTo(pkt *SCIONPacket, ov *overlay.OverlayAddr) error {
+	c.path, c.nextHop = pkt.Path, ov
+	c.nextHops = append(c.nextHops, ov)
+	return nil
+}
+
 func MustParseAddr(str string) *Addr {
 	address, err := AddrFromString(str)
 	if err != nil {
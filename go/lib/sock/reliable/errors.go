@@ -33,6 +33,7 @@ const (
 	ErrBadAddressType        = "bad address type"
 	ErrIncompleteAddress     = "incomplete IP address"
 	ErrIncompletePort        = "incomplete UDP port"
+	ErrIncompleteTimestamp   = "incomplete timestamp"
 	ErrIncompleteMessage     = "incomplete message"
 	ErrBadLength             = "bad length"
 	ErrBufferTooSmall        = "buffer too small"
@@ -16,16 +16,28 @@ package reliable
 
 import (
 	"net"
+	"time"
 
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 )
 
+// flagTimestamp is set in the address type field of frames that carry a
+// receive timestamp.
+const flagTimestamp = 0x80
+
+// timestampLength is the length of the receive timestamp field.
+const timestampLength = 8
+
 // OverlayPacket contains metadata about a SCION packet going through the
 // reliable socket framing protocol.
 type OverlayPacket struct {
 	Address *net.UDPAddr
-	Payload []byte
+	// Timestamp is the time at which the packet was received by the host. It
+	// is only sent on connections that negotiated CapReceiveTimestamps. The
+	// zero value means that no timestamp is present.
+	Timestamp time.Time
+	Payload   []byte
 }
 
 func (p *OverlayPacket) SerializeTo(b []byte) (int, error) {
@@ -38,6 +50,11 @@ func (p *OverlayPacket) SerializeTo(b []byte) (int, error) {
 			return 0, err
 		}
 	}
+	if !p.Timestamp.IsZero() {
+		f.AddressType |= flagTimestamp
+		f.Timestamp = make([]byte, timestampLength)
+		common.Order.PutUint64(f.Timestamp, uint64(p.Timestamp.UnixNano()))
+	}
 	f.Payload = p.Payload
 	return f.SerializeTo(b)
 }
@@ -51,6 +68,10 @@ func (p *OverlayPacket) DecodeFromBytes(b []byte) error {
 		return common.NewBasicError(ErrBadCookie, nil)
 	}
 	p.Address = f.extractAddress()
+	p.Timestamp = time.Time{}
+	if f.Timestamp != nil {
+		p.Timestamp = time.Unix(0, int64(common.Order.Uint64(f.Timestamp)))
+	}
 	p.Payload = f.Payload
 	return nil
 }
@@ -62,6 +83,7 @@ type frame struct {
 	Length      uint32
 	Address     []byte
 	Port        []byte
+	Timestamp   []byte
 	Payload     []byte
 }
 
@@ -73,9 +95,11 @@ func (f *frame) SerializeTo(b []byte) (int, error) {
 	common.Order.PutUint64(b, f.Cookie)
 	b[8] = f.AddressType
 	common.Order.PutUint32(b[9:], uint32(f.Length))
-	copy(b[13:], f.Address)
-	copy(b[13+len(f.Address):], f.Port)
-	copy(b[13+len(f.Address)+len(f.Port):], f.Payload)
+	offset := 13
+	offset += copy(b[offset:], f.Address)
+	offset += copy(b[offset:], f.Port)
+	offset += copy(b[offset:], f.Timestamp)
+	copy(b[offset:], f.Payload)
 	return totalLength, nil
 }
 
@@ -87,7 +111,7 @@ func (f *frame) DecodeFromBytes(data []byte) error {
 	f.AddressType = data[8]
 	f.Length = common.Order.Uint32(data[9:])
 	offset := 13
-	addressType := addr.HostAddrType(f.AddressType)
+	addressType := f.addressType()
 	if !isValidReliableSockDestination(addressType) {
 		return common.NewBasicError(ErrBadAddressType, nil, "type", addressType)
 	}
@@ -103,6 +127,14 @@ func (f *frame) DecodeFromBytes(data []byte) error {
 	}
 	f.Port = data[offset : offset+portLen]
 	offset += portLen
+	f.Timestamp = nil
+	if f.AddressType&flagTimestamp != 0 {
+		if len(data[offset:]) < timestampLength {
+			return common.NewBasicError(ErrIncompleteTimestamp, nil)
+		}
+		f.Timestamp = data[offset : offset+timestampLength]
+		offset += timestampLength
+	}
 	f.Payload = data[offset:]
 	if len(f.Payload) != int(f.Length) {
 		return common.NewBasicError(ErrBadLength, nil)
@@ -112,7 +144,7 @@ func (f *frame) DecodeFromBytes(data []byte) error {
 
 // length returns the total length of the frame (including payload).
 func (f *frame) length() int {
-	return f.headerLength() + len(f.Address) + len(f.Port) + len(f.Payload)
+	return f.headerLength() + len(f.Address) + len(f.Port) + len(f.Timestamp) + len(f.Payload)
 }
 
 // header length returns the length of the fixed size start of the frame
@@ -121,6 +153,12 @@ func (f *frame) headerLength() int {
 	return 8 + 1 + 4
 }
 
+// addressType returns the type of the address in the frame, without the
+// flags.
+func (f *frame) addressType() addr.HostAddrType {
+	return addr.HostAddrType(f.AddressType &^ flagTimestamp)
+}
+
 func (f *frame) insertAddress(address *net.UDPAddr) error {
 	if address.IP == nil || address.IP.IsUnspecified() {
 		return common.NewBasicError(ErrNoAddress, nil)
@@ -135,7 +173,7 @@ func (f *frame) insertAddress(address *net.UDPAddr) error {
 }
 
 func (f *frame) extractAddress() *net.UDPAddr {
-	t := addr.HostAddrType(f.AddressType)
+	t := f.addressType()
 	if t == addr.HostTypeIPv4 || t == addr.HostTypeIPv6 {
 		return &net.UDPAddr{
 			IP:   net.IP(f.Address),
@@ -17,6 +17,7 @@ package reliable
 import (
 	"net"
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
 
@@ -95,6 +96,16 @@ func TestOverlayPacketSerializeTo(t *testing.T) {
 			ExpectedData: []byte{0xde, 0, 0xad, 1, 0xbe, 2, 0xef, 3, 1, 0, 0, 0, 4,
 				10, 2, 3, 4, 0, 80, 10, 5, 6, 7},
 		},
+		{
+			Name: "good payload with timestamp",
+			Packet: &OverlayPacket{
+				Address:   &net.UDPAddr{IP: net.ParseIP("10.2.3.4"), Port: 80},
+				Timestamp: time.Unix(0, 0x0102030405060708),
+				Payload:   []byte{10, 5, 6, 7},
+			},
+			ExpectedData: []byte{0xde, 0, 0xad, 1, 0xbe, 2, 0xef, 3, 0x81, 0, 0, 0, 4,
+				10, 2, 3, 4, 0, 80, 1, 2, 3, 4, 5, 6, 7, 8, 10, 5, 6, 7},
+		},
 	}
 	Convey("Different packets serialize correctly", t, func() {
 		for _, tc := range testCases {
@@ -143,6 +154,17 @@ func TestOverlayPacketDecodeFromBytes(t *testing.T) {
 				10, 2, 3, 4, 0},
 			ExpectedError: ErrIncompletePort,
 		},
+		{
+			Name: "incomplete timestamp",
+			Buffer: []byte{0xde, 0, 0xad, 1, 0xbe, 2, 0xef, 3, 0x81, 0, 0, 0, 0,
+				10, 2, 3, 4, 0, 80, 1, 2, 3},
+			ExpectedError: ErrIncompleteTimestamp,
+		},
+		{
+			Name:          "bad address type with timestamp",
+			Buffer:        []byte{0xde, 0, 0xad, 1, 0xbe, 2, 0xef, 3, 0x83, 0, 0, 0, 0},
+			ExpectedError: ErrBadAddressType,
+		},
 		{
 			Name: "bad length (underflow)",
 			Buffer: []byte{0xde, 0, 0xad, 1, 0xbe, 2, 0xef, 3, 1, 0, 0, 0, 0,
@@ -181,6 +203,16 @@ func TestOverlayPacketDecodeFromBytes(t *testing.T) {
 				Payload: []byte{42},
 			},
 		},
+		{
+			Name: "good packet (IPv4 with timestamp)",
+			Buffer: []byte{0xde, 0, 0xad, 1, 0xbe, 2, 0xef, 3, 0x81, 0, 0, 0, 1,
+				10, 2, 3, 4, 0, 80, 1, 2, 3, 4, 5, 6, 7, 8, 42},
+			ExpectedPacket: OverlayPacket{
+				Address:   &net.UDPAddr{IP: net.IP{10, 2, 3, 4}, Port: 80},
+				Timestamp: time.Unix(0, 0x0102030405060708),
+				Payload:   []byte{42},
+			},
+		},
 	}
 	Convey("Different packets decode correctly", t, func() {
 		for _, tc := range testCases {
@@ -57,6 +57,12 @@ func (r *ReadPacketizer) Read(b []byte) (int, error) {
 	}
 }
 
+// prefill adds the data in b to the buffer, as if it was read from the
+// connection. Data that does not fit into the buffer is discarded.
+func (r *ReadPacketizer) prefill(b []byte) {
+	r.addData(copy(r.freeSpace, b))
+}
+
 func (r *ReadPacketizer) deleteData(count int) {
 	copy(r.buffer[:], r.buffer[count:r.availableData()])
 	r.updateSlices(r.availableData() - count)
@@ -81,11 +87,14 @@ func (reader *ReadPacketizer) haveNextPacket(b []byte) []byte {
 	if len(b) < 13 {
 		return nil
 	}
-	rcvdAddrType := b[8]
+	rcvdAddrType := addr.HostAddrType(b[8] &^ flagTimestamp)
 	payloadLength := common.Order.Uint32(b[9:13])
-	addressLength := getAddressLength(addr.HostAddrType(rcvdAddrType))
-	portLength := getPortLength(addr.HostAddrType(rcvdAddrType))
+	addressLength := getAddressLength(rcvdAddrType)
+	portLength := getPortLength(rcvdAddrType)
 	totalLength := 13 + addressLength + portLength + int(payloadLength)
+	if b[8]&flagTimestamp != 0 {
+		totalLength += timestampLength
+	}
 	if len(b) < totalLength {
 		return nil
 	}
@@ -36,6 +36,8 @@ func TestReadPacketizer(t *testing.T) {
 			0xde, 0, 0xad, 1, 0xbe, 2, 0xef, 3, 2, 0, 0, 0, 1,
 			0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1,
 			0, 80, 42,
+			0xde, 0, 0xad, 1, 0xbe, 2, 0xef, 3, 0x81, 0, 0, 0, 1,
+			10, 2, 3, 4, 0, 80, 1, 2, 3, 4, 5, 6, 7, 8, 42,
 		}
 		conn := mock_net.NewMockConn(ctrl)
 		conn.EXPECT().Read(gomock.Any()).DoAndReturn(
@@ -58,6 +60,11 @@ func TestReadPacketizer(t *testing.T) {
 				n, err := packetizer.Read(b)
 				SoMsg("err", err, ShouldBeNil)
 				SoMsg("n", n, ShouldEqual, 32)
+				Convey("third read (timestamped)", func() {
+					n, err := packetizer.Read(b)
+					SoMsg("err", err, ShouldBeNil)
+					SoMsg("n", n, ShouldEqual, 28)
+				})
 			})
 		})
 
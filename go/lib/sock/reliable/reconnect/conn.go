@@ -25,6 +25,7 @@ import (
 )
 
 var _ net.PacketConn = (*PacketConn)(nil)
+var _ reliable.TimestampReader = (*PacketConn)(nil)
 
 type PacketConn struct {
 	// connMtx protects read/write access to connection information. connMtx must
@@ -76,6 +77,17 @@ func (conn *PacketConn) ReadFrom(b []byte) (int, net.Addr, error) {
 	return op.numBytes, op.address, err
 }
 
+// ReadFromWithTimestamp works similarly to ReadFrom. In addition to ReadFrom,
+// it also returns the time at which the message was received by the host. The
+// timestamp is zero if the underlying connection does not report it, see
+// reliable.Conn.ReadFromWithTimestamp.
+func (conn *PacketConn) ReadFromWithTimestamp(b []byte) (int, net.Addr, time.Time, error) {
+	op := &ReadFromWithTimestampOperation{}
+	op.buffer = b
+	err := conn.DoIO(op)
+	return op.numBytes, op.address, op.timestamp, err
+}
+
 func (conn *PacketConn) WriteTo(b []byte, address net.Addr) (int, error) {
 	op := &WriteToOperation{}
 	op.buffer = b
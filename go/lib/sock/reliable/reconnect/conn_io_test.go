@@ -201,6 +201,15 @@ func TestPacketConnReadWrite(t *testing.T) {
 				SoMsg("buffer", buffer[:n], ShouldResemble, readData)
 				SoMsg("err", err, ShouldBeNil)
 			})
+			Convey("ReadFromWithTimestamp without timestamp support", func() {
+				mockConn.EXPECT().ReadFrom(buffer).DoAndReturn(mockReadFunc)
+				n, remoteAddress, ts, err := packetConn.ReadFromWithTimestamp(buffer)
+				SoMsg("n", n, ShouldEqual, len(readData))
+				SoMsg("address", remoteAddress, ShouldEqual, remoteAddr)
+				SoMsg("buffer", buffer[:n], ShouldResemble, readData)
+				SoMsg("timestamp", ts.IsZero(), ShouldBeTrue)
+				SoMsg("err", err, ShouldBeNil)
+			})
 		})
 	})
 }
@@ -14,7 +14,12 @@
 
 package reconnect
 
-import "net"
+import (
+	"net"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/sock/reliable"
+)
 
 // IOOperation provides an abstraction around any Conn reads and writes.  Types
 // that implement this interface contain the Read/Write arguments and return
@@ -70,3 +75,20 @@ func (op *ReadFromOperation) Do(conn net.PacketConn) error {
 	op.address = address
 	return err
 }
+
+type ReadFromWithTimestampOperation struct {
+	ReadFromOperation
+	timestamp time.Time
+}
+
+func (op *ReadFromWithTimestampOperation) Do(conn net.PacketConn) error {
+	tr, ok := conn.(reliable.TimestampReader)
+	if !ok {
+		return op.ReadFromOperation.Do(conn)
+	}
+	n, address, timestamp, err := tr.ReadFromWithTimestamp(op.buffer)
+	op.numBytes = n
+	op.address = address
+	op.timestamp = timestamp
+	return err
+}
@@ -24,17 +24,88 @@ import (
 type CommandBitField uint8
 
 const (
+	// CmdNegotiate is set if the registration message contains a
+	// negotiation field. Dispatchers that do not support negotiation reject
+	// such messages.
+	CmdNegotiate   CommandBitField = 0x08
 	CmdBindAddress CommandBitField = 0x04
 	CmdEnableSCMP  CommandBitField = 0x02
 	CmdAlwaysOn    CommandBitField = 0x01
 )
 
+const (
+	// ProtocolVersion is the highest version of the registration protocol
+	// implemented by this package. Version 0 denotes the legacy protocol
+	// without negotiation.
+	ProtocolVersion uint8 = 1
+	// SupportedCapabilities contains the optional protocol features
+	// implemented by this package.
+	SupportedCapabilities Capabilities = CapReceiveTimestamps
+)
+
+const (
+	// CapReceiveTimestamps indicates that the dispatcher includes the time at
+	// which a packet was received by the host in the frames it sends to the
+	// application, see OverlayPacket.Timestamp.
+	CapReceiveTimestamps Capabilities = 1 << iota
+)
+
+// Capabilities is a bit field of optional protocol features (e.g., batched
+// frames or per-packet metadata). A feature can only be used on a
+// connection if both ends announced it during registration.
+type Capabilities uint32
+
+// Negotiation contains the protocol version and capabilities announced by an
+// application in a registration message, or agreed on by the dispatcher in a
+// confirmation message.
+type Negotiation struct {
+	Version      uint8
+	Capabilities Capabilities
+}
+
+// Negotiate returns the protocol version and capabilities agreed on for a
+// peer announcing n, given the local ProtocolVersion and
+// SupportedCapabilities.
+func (n Negotiation) Negotiate() Negotiation {
+	version := n.Version
+	if version > ProtocolVersion {
+		version = ProtocolVersion
+	}
+	return Negotiation{
+		Version:      version,
+		Capabilities: n.Capabilities & SupportedCapabilities,
+	}
+}
+
+func (n *Negotiation) SerializeTo(b []byte) (int, error) {
+	if len(b) < negotiationLength {
+		return 0, common.NewBasicError(ErrBufferTooSmall, nil)
+	}
+	b[0] = n.Version
+	common.Order.PutUint32(b[1:], uint32(n.Capabilities))
+	return negotiationLength, nil
+}
+
+func (n *Negotiation) DecodeFromBytes(b []byte) error {
+	if len(b) < negotiationLength {
+		return common.NewBasicError(ErrIncompleteMessage, nil)
+	}
+	n.Version = b[0]
+	n.Capabilities = Capabilities(common.Order.Uint32(b[1:]))
+	return nil
+}
+
+const negotiationLength = 5
+
 // Registration contains metadata for a SCION Dispatcher registration message.
 type Registration struct {
 	IA            addr.IA
 	PublicAddress *net.UDPAddr
 	BindAddress   *net.UDPAddr
 	SVCAddress    addr.HostSVC
+	// Negotiation contains the protocol version and capabilities of the
+	// application. If it is nil, the legacy protocol is used.
+	Negotiation *Negotiation
 }
 
 func (r *Registration) SerializeTo(b []byte) (int, error) {
@@ -53,6 +124,11 @@ func (r *Registration) SerializeTo(b []byte) (int, error) {
 		msg.BindData = &bindAddress
 		bindAddress.SetFromUDPAddr(r.BindAddress)
 	}
+	if r.Negotiation != nil {
+		msg.Command |= CmdNegotiate
+		n := *r.Negotiation
+		msg.Negotiation = &n
+	}
 	if r.SVCAddress != addr.SvcNone {
 		buffer := make([]byte, 2)
 		common.Order.PutUint16(buffer, uint16(r.SVCAddress))
@@ -85,18 +161,20 @@ func (r *Registration) DecodeFromBytes(b []byte) error {
 			Port: int(msg.BindData.Port),
 		}
 	}
+	r.Negotiation = msg.Negotiation
 	return nil
 }
 
 // registrationMessage is the wire format for a SCION Dispatcher registration
 // message.
 type registrationMessage struct {
-	Command    CommandBitField
-	L4Proto    uint8
-	IA         uint64
-	PublicData registrationAddressField
-	BindData   *registrationAddressField
-	SVC        []byte
+	Command     CommandBitField
+	L4Proto     uint8
+	IA          uint64
+	PublicData  registrationAddressField
+	BindData    *registrationAddressField
+	Negotiation *Negotiation
+	SVC         []byte
 }
 
 func (m *registrationMessage) SerializeTo(b []byte) (int, error) {
@@ -117,6 +195,12 @@ func (m *registrationMessage) SerializeTo(b []byte) (int, error) {
 		}
 		offset += m.BindData.length()
 	}
+	if m.Negotiation != nil {
+		if _, err := m.Negotiation.SerializeTo(b[offset:]); err != nil {
+			return 0, err
+		}
+		offset += negotiationLength
+	}
 	copy(b[offset:], m.SVC)
 	offset += len(m.SVC)
 	return offset, nil
@@ -141,6 +225,13 @@ func (l *registrationMessage) DecodeFromBytes(b []byte) error {
 		}
 		offset += l.BindData.length()
 	}
+	if (l.Command & CmdNegotiate) != 0 {
+		l.Negotiation = &Negotiation{}
+		if err := l.Negotiation.DecodeFromBytes(b[offset:]); err != nil {
+			return err
+		}
+		offset += negotiationLength
+	}
 	switch len(b[offset:]) {
 	case 0:
 		return nil
@@ -188,7 +279,7 @@ func (l *registrationAddressField) DecodeFromBytes(b []byte) error {
 func (l *registrationAddressField) SetFromUDPAddr(u *net.UDPAddr) {
 	l.Port = uint16(u.Port)
 	l.AddressType = byte(getIPAddressType(u.IP))
-	l.Address = []byte(u.IP)
+	l.Address = []byte(normalizeIP(u.IP))
 }
 
 func (l *registrationAddressField) length() int {
@@ -200,6 +291,10 @@ func (l *registrationAddressField) length() int {
 
 type Confirmation struct {
 	Port uint16
+	// Negotiation contains the protocol version and capabilities agreed on
+	// by the dispatcher. It is only present if the registration message
+	// contained a negotiation field.
+	Negotiation *Negotiation
 }
 
 func (c *Confirmation) SerializeTo(b []byte) (int, error) {
@@ -207,7 +302,14 @@ func (c *Confirmation) SerializeTo(b []byte) (int, error) {
 		return 0, common.NewBasicError(ErrBufferTooSmall, nil)
 	}
 	common.Order.PutUint16(b, c.Port)
-	return 2, nil
+	if c.Negotiation == nil {
+		return 2, nil
+	}
+	n, err := c.Negotiation.SerializeTo(b[2:])
+	if err != nil {
+		return 0, err
+	}
+	return 2 + n, nil
 }
 
 func (c *Confirmation) DecodeFromBytes(b []byte) error {
@@ -215,5 +317,12 @@ func (c *Confirmation) DecodeFromBytes(b []byte) error {
 		return common.NewBasicError(ErrIncompletePort, nil)
 	}
 	c.Port = common.Order.Uint16(b)
+	c.Negotiation = nil
+	if len(b) > 2 {
+		c.Negotiation = &Negotiation{}
+		if err := c.Negotiation.DecodeFromBytes(b[2:]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -82,6 +82,29 @@ func TestRegistrationMessageSerializeTo(t *testing.T) {
 			ExpectedData: []byte{0x07, 17, 0, 1, 0xff, 0, 0, 0, 0, 0x01,
 				0, 80, 1, 10, 2, 3, 4, 0, 81, 1, 10, 5, 6, 7},
 		},
+		{
+			Name: "public 16-byte IPv4 address with bind",
+			Registration: &Registration{
+				IA:            xtest.MustParseIA("1-ff00:0:1"),
+				PublicAddress: &net.UDPAddr{IP: net.ParseIP("10.2.3.4"), Port: 80},
+				BindAddress:   &net.UDPAddr{IP: net.ParseIP("10.5.6.7"), Port: 81},
+				SVCAddress:    addr.SvcNone,
+			},
+			ExpectedData: []byte{0x07, 17, 0, 1, 0xff, 0, 0, 0, 0, 0x01,
+				0, 80, 1, 10, 2, 3, 4, 0, 81, 1, 10, 5, 6, 7},
+		},
+		{
+			Name: "public IPv6 address with bind",
+			Registration: &Registration{
+				IA:            xtest.MustParseIA("1-ff00:0:1"),
+				PublicAddress: &net.UDPAddr{IP: net.ParseIP("2001:db8::1"), Port: 80},
+				BindAddress:   &net.UDPAddr{IP: net.ParseIP("2001:db8::2"), Port: 81},
+				SVCAddress:    addr.SvcNone,
+			},
+			ExpectedData: []byte{0x07, 17, 0, 1, 0xff, 0, 0, 0, 0, 0x01,
+				0, 80, 2, 0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1,
+				0, 81, 2, 0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2},
+		},
 		{
 			Name: "public IPv4 address with SVC",
 			Registration: &Registration{
@@ -104,6 +127,17 @@ func TestRegistrationMessageSerializeTo(t *testing.T) {
 				0, 80, 1, 10, 2, 3, 4,
 				0, 81, 1, 10, 5, 6, 7, 0, 2},
 		},
+		{
+			Name: "public address with negotiation and SVC",
+			Registration: &Registration{
+				IA:            xtest.MustParseIA("1-ff00:0:1"),
+				PublicAddress: &net.UDPAddr{IP: net.IP{10, 2, 3, 4}, Port: 80},
+				SVCAddress:    addr.SvcPS,
+				Negotiation:   &Negotiation{Version: 1, Capabilities: 0x0102},
+			},
+			ExpectedData: []byte{0x0b, 17, 0, 1, 0xff, 0, 0, 0, 0, 0x01, 0,
+				80, 1, 10, 2, 3, 4, 1, 0, 0, 1, 2, 0x00, 0x01},
+		},
 	}
 	Convey("", t, func() {
 		for _, tc := range testCases {
@@ -237,6 +271,26 @@ func TestRegistrationMessageDecodeFromBytes(t *testing.T) {
 				SVCAddress:    addr.SvcPS,
 			},
 		},
+		{
+			Name: "negotiation with SVC address",
+			Data: []byte{0x0b, 17, 0, 1, 0xff, 0, 0, 0, 0, 0x01,
+				0, 80, 1, 10, 2, 3, 4,
+				1, 0, 0, 1, 2,
+				0x00, 0x01},
+			ExpectedRegistration: Registration{
+				IA:            xtest.MustParseIA("1-ff00:0:1"),
+				PublicAddress: &net.UDPAddr{IP: net.IP{10, 2, 3, 4}, Port: 80},
+				SVCAddress:    addr.SvcPS,
+				Negotiation:   &Negotiation{Version: 1, Capabilities: 0x0102},
+			},
+		},
+		{
+			Name: "incomplete negotiation",
+			Data: []byte{0x0b, 17, 0, 1, 0xff, 0, 0, 0, 0, 0x01,
+				0, 80, 1, 10, 2, 3, 4,
+				1, 0},
+			ExpectedError: ErrIncompleteMessage,
+		},
 	}
 	Convey("", t, func() {
 		for _, tc := range testCases {
@@ -265,6 +319,13 @@ func TestConfirmationMessageSerializeTo(t *testing.T) {
 			SoMsg("err", err, ShouldBeNil)
 			SoMsg("data", b[:n], ShouldResemble, []byte{0xaa, 0xbb})
 		})
+		Convey("success with negotiation", func() {
+			confirmation.Negotiation = &Negotiation{Version: 1, Capabilities: 0x0102}
+			b := make([]byte, 1500)
+			n, err := confirmation.SerializeTo(b)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("data", b[:n], ShouldResemble, []byte{0xaa, 0xbb, 1, 0, 0, 1, 2})
+		})
 	})
 }
 
@@ -283,5 +344,30 @@ func TestConfirmationDecodeFromBytes(t *testing.T) {
 			SoMsg("err", err, ShouldBeNil)
 			SoMsg("data", confirmation, ShouldResemble, Confirmation{Port: 0xaabb})
 		})
+		Convey("success with negotiation", func() {
+			b := []byte{0xaa, 0xbb, 1, 0, 0, 1, 2}
+			err := confirmation.DecodeFromBytes(b)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("data", confirmation, ShouldResemble, Confirmation{
+				Port:        0xaabb,
+				Negotiation: &Negotiation{Version: 1, Capabilities: 0x0102},
+			})
+		})
+	})
+}
+
+func TestNegotiationNegotiate(t *testing.T) {
+	Convey("Negotiate", t, func() {
+		Convey("newer peer is downgraded to the local version", func() {
+			n := Negotiation{Version: ProtocolVersion + 1, Capabilities: 0xffffffff}
+			SoMsg("negotiated", n.Negotiate(), ShouldResemble, Negotiation{
+				Version:      ProtocolVersion,
+				Capabilities: SupportedCapabilities,
+			})
+		})
+		Convey("older peer keeps its version", func() {
+			n := Negotiation{Version: 0}
+			SoMsg("negotiated", n.Negotiate(), ShouldResemble, Negotiation{})
+		})
 	})
 }
@@ -25,10 +25,12 @@
 //
 // ReliableSocket common header message format:
 //   8-bytes: COOKIE (0xde00ad01be02ef03)
-//   1-byte: ADDR TYPE (NONE=0, IPv4=1, IPv6=2, SVC=3)
+//   1-byte: ADDR TYPE (NONE=0, IPv4=1, IPv6=2, SVC=3; 0x80 set if timestamped)
 //   4-byte: data length
 //   var-byte: Destination address (0 bytes for SCIOND API)
 //     +2-byte: If destination address not NONE, destination port
+//   +8-byte: Receive timestamp in nanoseconds since the Unix epoch (present if
+//     0x80 is set in ADDR TYPE)
 //   var-byte: Payload
 //
 // ReliableSocket registration message format:
@@ -42,8 +44,27 @@
 //  +2-bytes: L4 bind port  \
 //  +1-byte: Address type    ) (optional bind address)
 //  +var-byte: Bind Address /
+//  +1-byte: Protocol version  \ (optional negotiation field,
+//  +4-bytes: Capabilities     /  present if command has 0x08 set)
 //  +2-bytes: SVC (optional SVC type)
 //
+// ReliableSocket confirmation message format:
+//  13-bytes: [Common header with address type NONE]
+//   2-bytes: L4 port
+//  +1-byte: Protocol version  \ (negotiation field, present if the
+//  +4-bytes: Capabilities     /  registration contained one)
+//
+// Applications announce the highest protocol version and the capabilities
+// they support in the negotiation field. The dispatcher replies with the
+// version and capabilities both ends support. Dispatchers that do not support
+// negotiation close the connection upon receiving a negotiation field; Register
+// then retries without it.
+//
+// If CapReceiveTimestamps was negotiated, the dispatcher adds the time at which
+// the host received a packet to the frames it sends to the application. The
+// timestamp is taken by the kernel where available. Applications read it with
+// Conn.ReadFromWithTimestamp.
+//
 // To communicate with SCIOND, clients must first connect to SCIOND's UNIX socket. Messages
 // for SCIOND must set the ADDR TYPE field in the common header to NONE. The payload contains
 // the query for SCIOND (e.g., a request for paths to a SCION destination). The reply header
@@ -122,6 +143,13 @@ func (d *dispatcherService) RegisterTimeout(ia addr.IA, public *addr.AppAddr,
 
 var _ net.Conn = (*Conn)(nil)
 var _ net.PacketConn = (*Conn)(nil)
+var _ TimestampReader = (*Conn)(nil)
+
+// TimestampReader is implemented by connections that report the time at which
+// a message was received by the host, see Conn.ReadFromWithTimestamp.
+type TimestampReader interface {
+	ReadFromWithTimestamp(b []byte) (int, net.Addr, time.Time, error)
+}
 
 // Conn implements the ReliableSocket framing protocol over UNIX sockets.
 type Conn struct {
@@ -134,6 +162,8 @@ type Conn struct {
 	writeMutex    sync.Mutex
 	writeBuffer   []byte
 	writeStreamer *WriteStreamer
+
+	negotiation Negotiation
 }
 
 func newConn(c net.Conn) *Conn {
@@ -147,6 +177,15 @@ func newConn(c net.Conn) *Conn {
 	}
 }
 
+// NewConn returns a Conn for the established UNIX socket connection c. The
+// buffered data is returned by reads before any data read from c. This allows
+// resuming a connection that was handed over by another process, see Buffered.
+func NewConn(c *net.UnixConn, buffered []byte) *Conn {
+	conn := newConn(c)
+	conn.readPacketizer.prefill(buffered)
+	return conn
+}
+
 // Dial connects to the UNIX socket specified by address.
 func Dial(address string) (*Conn, error) {
 	return DialTimeout(address, 0)
@@ -200,14 +239,53 @@ func RegisterTimeout(dispatcher string, ia addr.IA, public *addr.AppAddr,
 		PublicAddress: publicUDP,
 		BindAddress:   bindUDP,
 		SVCAddress:    svc,
+		Negotiation: &Negotiation{
+			Version:      ProtocolVersion,
+			Capabilities: SupportedCapabilities,
+		},
 	}
 
 	// Compute deadline prior to Dial, because timeout is relative to current time.
 	deadline := time.Now().Add(timeout)
-	conn, err := DialTimeout(dispatcher, timeout)
+	conn, c, err := register(dispatcher, reg, timeout, deadline)
+	if err != nil && IsDispatcherError(err) {
+		// Dispatchers that do not support negotiation close the connection
+		// when receiving a negotiation field, so retry with the legacy
+		// protocol.
+		reg.Negotiation = nil
+		if timeout != 0 {
+			timeout = time.Until(deadline)
+			if timeout <= 0 {
+				return nil, 0, err
+			}
+		}
+		conn, c, err = register(dispatcher, reg, timeout, deadline)
+	}
 	if err != nil {
 		return nil, 0, err
 	}
+	if publicUDP.Port != 0 && publicUDP.Port != int(c.Port) {
+		conn.Close()
+		return nil, 0, common.NewBasicError("port mismatch", nil, "requested", publicUDP.Port,
+			"received", c.Port)
+	}
+	if c.Negotiation != nil {
+		conn.negotiation = c.Negotiation.Negotiate()
+	}
+	// Disable deadline to not affect calling code
+	conn.SetDeadline(time.Time{})
+	return conn, c.Port, nil
+}
+
+// register sends the registration message reg to the dispatcher and returns
+// the connection and the confirmation of the dispatcher.
+func register(dispatcher string, reg *Registration, timeout time.Duration,
+	deadline time.Time) (*Conn, *Confirmation, error) {
+
+	conn, err := DialTimeout(dispatcher, timeout)
+	if err != nil {
+		return nil, nil, err
+	}
 	// If a timeout was specified, make reads and writes return if deadline exceeded.
 	if timeout != 0 {
 		conn.SetDeadline(deadline)
@@ -217,45 +295,64 @@ func RegisterTimeout(dispatcher string, ia addr.IA, public *addr.AppAddr,
 	n, err := reg.SerializeTo(b)
 	if err != nil {
 		conn.Close()
-		return nil, 0, err
+		return nil, nil, err
 	}
 	_, err = conn.WriteTo(b[:n], nil)
 	if err != nil {
 		conn.Close()
-		return nil, 0, err
+		return nil, nil, err
 	}
 
 	n, _, err = conn.ReadFrom(b)
 	if err != nil {
 		conn.Close()
-		return nil, 0, err
+		return nil, nil, err
 	}
 
 	var c Confirmation
 	err = c.DecodeFromBytes(b[:n])
 	if err != nil {
 		conn.Close()
-		return nil, 0, err
+		return nil, nil, err
 	}
-	if publicUDP.Port != 0 && publicUDP.Port != int(c.Port) {
-		conn.Close()
-		return nil, 0, common.NewBasicError("port mismatch", nil, "requested", publicUDP.Port,
-			"received", c.Port)
-	}
-	// Disable deadline to not affect calling code
-	conn.SetDeadline(time.Time{})
-	return conn, c.Port, nil
+	return conn, &c, nil
+}
+
+// Negotiation returns the protocol version and capabilities agreed on with
+// the dispatcher during registration. For connections to dispatchers that do
+// not support negotiation, and for connections that were not registered, the
+// version is 0 and no capabilities are set.
+func (conn *Conn) Negotiation() Negotiation {
+	return conn.negotiation
+}
+
+// Buffered returns a copy of the data that was read from the underlying socket,
+// but that has not been returned by Read or ReadFrom yet, e.g., because a read
+// deadline expired in the middle of a frame.
+func (conn *Conn) Buffered() []byte {
+	conn.readMutex.Lock()
+	defer conn.readMutex.Unlock()
+	return append([]byte(nil), conn.readPacketizer.data...)
 }
 
 // ReadFrom works similarly to Read. In addition to Read, it also returns the last hop
 // (usually, the border router) which sent the message.
 func (conn *Conn) ReadFrom(buf []byte) (int, net.Addr, error) {
+	n, address, _, err := conn.ReadFromWithTimestamp(buf)
+	return n, address, err
+}
+
+// ReadFromWithTimestamp works similarly to ReadFrom. In addition to ReadFrom,
+// it also returns the time at which the message was received by the host. The
+// timestamp is zero if the message did not carry one, e.g., because
+// CapReceiveTimestamps was not negotiated with the dispatcher.
+func (conn *Conn) ReadFromWithTimestamp(buf []byte) (int, net.Addr, time.Time, error) {
 	conn.readMutex.Lock()
 	defer conn.readMutex.Unlock()
 
 	n, err := conn.readPacketizer.Read(conn.readBuffer)
 	if err != nil {
-		return 0, nil, err
+		return 0, nil, time.Time{}, err
 	}
 	var p OverlayPacket
 	p.DecodeFromBytes(conn.readBuffer[:n])
@@ -267,14 +364,14 @@ func (conn *Conn) ReadFrom(buf []byte) (int, net.Addr, error) {
 			addr.NewL4UDPInfo(uint16(p.Address.Port)),
 		)
 		if err != nil {
-			return 0, nil, common.NewBasicError("overlay error", err)
+			return 0, nil, time.Time{}, common.NewBasicError("overlay error", err)
 		}
 	}
 	if len(buf) < len(p.Payload) {
-		return 0, nil, serrors.New("buffer too small")
+		return 0, nil, time.Time{}, serrors.New("buffer too small")
 	}
 	copy(buf, p.Payload)
-	return len(p.Payload), overlayAddr, nil
+	return len(p.Payload), overlayAddr, p.Timestamp, nil
 }
 
 // WriteTo blocks until it sends buf as a single framed message through conn.
@@ -282,6 +379,14 @@ func (conn *Conn) ReadFrom(buf []byte) (int, net.Addr, error) {
 // On error, the number of bytes returned is meaningless. On success, the number of bytes
 // is always len(buf).
 func (conn *Conn) WriteTo(buf []byte, dst net.Addr) (int, error) {
+	return conn.WriteToWithTimestamp(buf, dst, time.Time{})
+}
+
+// WriteToWithTimestamp works similarly to WriteTo, but the message also
+// carries the time at which it was received by the host. If ts is zero, the
+// message carries no timestamp. Timestamps must only be sent to peers that
+// negotiated CapReceiveTimestamps.
+func (conn *Conn) WriteToWithTimestamp(buf []byte, dst net.Addr, ts time.Time) (int, error) {
 	conn.writeMutex.Lock()
 	defer conn.writeMutex.Unlock()
 
@@ -293,8 +398,9 @@ func (conn *Conn) WriteTo(buf []byte, dst net.Addr) (int, error) {
 		}
 	}
 	p := &OverlayPacket{
-		Address: publicAddress,
-		Payload: buf,
+		Address:   publicAddress,
+		Timestamp: ts,
+		Payload:   buf,
 	}
 	n, err := p.SerializeTo(conn.writeBuffer)
 	if err != nil {
@@ -0,0 +1,72 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reliable
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnBufferedResume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reliable")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	listener, err := Listen(filepath.Join(dir, "test.sock"))
+	require.NoError(t, err)
+	defer listener.Close()
+	client, err := net.Dial("unix", listener.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+	c, err := listener.Accept()
+	require.NoError(t, err)
+	server := c.(*Conn)
+	defer server.Close()
+
+	frame := func(payload []byte) []byte {
+		b := make([]byte, 128)
+		n, err := (&OverlayPacket{Payload: payload}).SerializeTo(b)
+		require.NoError(t, err)
+		return b[:n]
+	}
+	first, second := frame([]byte{1, 2, 3}), frame([]byte{4, 5, 6, 7})
+	_, err = client.Write(append(first, second[:5]...))
+	require.NoError(t, err)
+
+	buf := make([]byte, 128)
+	n, err := server.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, buf[:n])
+	// Interrupt the read in the middle of the second frame.
+	require.NoError(t, server.SetReadDeadline(time.Now().Add(50*time.Millisecond)))
+	_, err = server.Read(buf)
+	require.Error(t, err)
+	buffered := server.Buffered()
+	assert.Equal(t, second[:5], buffered)
+
+	require.NoError(t, server.SetReadDeadline(time.Time{}))
+	resumed := NewConn(server.UnixConn, buffered)
+	_, err = client.Write(second[5:])
+	require.NoError(t, err)
+	n, err = resumed.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{4, 5, 6, 7}, buf[:n])
+}
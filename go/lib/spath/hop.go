@@ -41,7 +41,8 @@ const (
 	MaxTTL            = 24 * 60 * 60 // One day in seconds
 	ExpTimeUnit       = MaxTTL / 256 // ~5m38s
 	MaxTTLField       = ExpTimeType(math.MaxUint8)
-	macInputLen       = 16
+	// MacInputLen is the length of the MAC input block, see CalcMac.
+	MacInputLen = 16
 )
 
 // Hop Field format:
@@ -143,15 +144,8 @@ func (h *HopField) Verify(macH hash.Hash, tsInt uint32, prev common.RawBytes) er
 //  +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
 //
 func (h *HopField) CalcMac(mac hash.Hash, tsInt uint32, prev common.RawBytes) common.RawBytes {
-	// If the previous hopfield is set, it must be of length k*8+7 (k >= 0),
-	if len(prev) != 0 && (len(prev)&0x7) != 7 {
-		panic(fmt.Sprintf("Bad previous hop field length len=%d", len(prev)))
-	}
-	all := make(common.RawBytes, macInputLen)
-	common.Order.PutUint32(all, tsInt)
-	all[4] = 0 // Ignore flags
-	common.Order.PutUint32(all[5:], h.expTimeIfIdsPack())
-	copy(all[9:], prev)
+	all := make(common.RawBytes, MacInputLen)
+	h.WriteMacInput(all, tsInt, prev)
 
 	mac.Reset()
 	// Write must not return an error: https://godoc.org/hash#Hash
@@ -162,6 +156,24 @@ func (h *HopField) CalcMac(mac hash.Hash, tsInt uint32, prev common.RawBytes) co
 	return mac.Sum(tmp)[:MacLen]
 }
 
+// WriteMacInput writes the MAC input block, as described in CalcMac, to b,
+// which must be at least MacInputLen bytes long. It allows callers to compute
+// the MAC without allocations.
+// WARN: If prev is of a length not accepted by CalcMac, this function panics.
+func (h *HopField) WriteMacInput(b common.RawBytes, tsInt uint32, prev common.RawBytes) {
+	// If the previous hopfield is set, it must be of length k*8+7 (k >= 0),
+	if len(prev) != 0 && (len(prev)&0x7) != 7 {
+		panic(fmt.Sprintf("Bad previous hop field length len=%d", len(prev)))
+	}
+	common.Order.PutUint32(b, tsInt)
+	b[4] = 0 // Ignore flags
+	common.Order.PutUint32(b[5:], h.expTimeIfIdsPack())
+	n := copy(b[9:MacInputLen], prev)
+	for i := 9 + n; i < MacInputLen; i++ {
+		b[i] = 0
+	}
+}
+
 // Pack packs the hop field.
 func (h *HopField) Pack() common.RawBytes {
 	b := make(common.RawBytes, HopFieldLength)
@@ -19,6 +19,7 @@ package spathmeta
 import (
 	"crypto/sha256"
 	"encoding/binary"
+	"hash/fnv"
 	"strings"
 
 	"github.com/scionproto/scion/go/lib/common"
@@ -74,6 +75,28 @@ func (aps AppPathSet) GetAppPath(pref PathKey) *AppPath {
 	return nil
 }
 
+// GetAppPathForFlow returns the AppPath assigned to flow. Paths are assigned
+// with rendezvous hashing: a flow keeps its path as long as the path is in the
+// set, and different flows are spread evenly across the paths. If the set is
+// empty, nil is returned.
+func (aps AppPathSet) GetAppPathForFlow(flow uint64) *AppPath {
+	var flowBytes [8]byte
+	binary.BigEndian.PutUint64(flowBytes[:], flow)
+	var best *AppPath
+	var bestKey PathKey
+	var bestScore uint64
+	for k, ap := range aps {
+		h := fnv.New64a()
+		h.Write(flowBytes[:])
+		h.Write([]byte(k))
+		score := h.Sum64()
+		if best == nil || score > bestScore || (score == bestScore && k < bestKey) {
+			best, bestKey, bestScore = ap, k, score
+		}
+	}
+	return best
+}
+
 func (aps AppPathSet) String() string {
 	var desc []string
 	for _, path := range aps {
@@ -0,0 +1,72 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spathmeta
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestGetAppPathForFlow(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	aps := AppPathSet{}
+	for ifid := common.IFIDType(1); ifid <= 4; ifid++ {
+		aps.Add(&sciond.PathReplyEntry{
+			Path: &sciond.FwdPathMeta{
+				Interfaces: []sciond.PathInterface{{RawIsdas: ia.IAInt(), IfID: ifid}},
+			},
+		})
+	}
+	t.Run("empty set", func(t *testing.T) {
+		assert.Nil(t, AppPathSet{}.GetAppPathForFlow(1))
+	})
+	t.Run("flow sticks to path", func(t *testing.T) {
+		for flow := uint64(0); flow < 100; flow++ {
+			ap := aps.GetAppPathForFlow(flow)
+			assert.Equal(t, ap, aps.GetAppPathForFlow(flow))
+			assert.Equal(t, ap, aps.Copy().GetAppPathForFlow(flow).Copy())
+		}
+	})
+	t.Run("flows are spread across paths", func(t *testing.T) {
+		used := make(map[PathKey]int)
+		for flow := uint64(0); flow < 100; flow++ {
+			used[aps.GetAppPathForFlow(flow).Key()]++
+		}
+		assert.Len(t, used, len(aps))
+	})
+	t.Run("removing a path only moves its flows", func(t *testing.T) {
+		before := make(map[uint64]PathKey)
+		for flow := uint64(0); flow < 100; flow++ {
+			before[flow] = aps.GetAppPathForFlow(flow).Key()
+		}
+		reduced := aps.Copy()
+		var removed PathKey
+		for k := range reduced {
+			removed = k
+			delete(reduced, k)
+			break
+		}
+		for flow, key := range before {
+			if key != removed {
+				assert.Equal(t, key, reduced.GetAppPathForFlow(flow).Key())
+			}
+		}
+	})
+}
@@ -0,0 +1,82 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spathmeta
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/sciond"
+)
+
+// FingerprintLen is the length of a fingerprint in bytes.
+const FingerprintLen = 8
+
+// Fingerprint returns a short, human readable identifier of the path. It is
+// the hex encoding of the first FingerprintLen bytes of the path key, and is
+// therefore stable across SCIOND queries and tools.
+func (pk PathKey) Fingerprint() string {
+	raw := []byte(pk)
+	if len(raw) > FingerprintLen {
+		raw = raw[:FingerprintLen]
+	}
+	return hex.EncodeToString(raw)
+}
+
+// MatchesFingerprint returns whether fp identifies the path. Similar to
+// abbreviated commit hashes, any non-empty prefix of the fingerprint matches.
+// The comparison is case-insensitive.
+func (pk PathKey) MatchesFingerprint(fp string) bool {
+	return fp != "" && strings.HasPrefix(pk.Fingerprint(), strings.ToLower(fp))
+}
+
+// Fingerprint returns the fingerprint of the path, see PathKey.Fingerprint.
+func (ap *AppPath) Fingerprint() string {
+	return ap.Key().Fingerprint()
+}
+
+// ValidateFingerprint checks that fp is a non-empty hex string that is not
+// longer than a full fingerprint.
+func ValidateFingerprint(fp string) error {
+	if fp == "" {
+		return common.NewBasicError("Empty fingerprint", nil)
+	}
+	if len(fp) > 2*FingerprintLen {
+		return common.NewBasicError("Fingerprint too long", nil, "fingerprint", fp,
+			"max", 2*FingerprintLen)
+	}
+	for _, c := range strings.ToLower(fp) {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return common.NewBasicError("Fingerprint is not hex encoded", nil,
+				"fingerprint", fp)
+		}
+	}
+	return nil
+}
+
+// FilterFingerprint returns the entries whose fingerprint matches fp.
+func FilterFingerprint(entries []sciond.PathReplyEntry,
+	fp string) []sciond.PathReplyEntry {
+
+	var matches []sciond.PathReplyEntry
+	for i := range entries {
+		ap := AppPath{Entry: &entries[i]}
+		if ap.Key().MatchesFingerprint(fp) {
+			matches = append(matches, entries[i])
+		}
+	}
+	return matches
+}
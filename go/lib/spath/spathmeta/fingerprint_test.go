@@ -0,0 +1,77 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spathmeta
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestFingerprint(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	entry := func(ifids ...common.IFIDType) sciond.PathReplyEntry {
+		var ifaces []sciond.PathInterface
+		for _, ifid := range ifids {
+			ifaces = append(ifaces, sciond.PathInterface{RawIsdas: ia.IAInt(), IfID: ifid})
+		}
+		return sciond.PathReplyEntry{Path: &sciond.FwdPathMeta{Interfaces: ifaces, Mtu: 1472}}
+	}
+	entries := []sciond.PathReplyEntry{entry(1, 2), entry(3, 4), entry(1, 2)}
+	// Only the interface sequence is part of the fingerprint.
+	entries[2].Path.Mtu = 1280
+
+	fp := (&AppPath{Entry: &entries[0]}).Fingerprint()
+	t.Run("short and stable", func(t *testing.T) {
+		assert.Len(t, fp, 2*FingerprintLen)
+		assert.Equal(t, fp, (&AppPath{Entry: &entries[2]}).Fingerprint())
+		assert.NotEqual(t, fp, (&AppPath{Entry: &entries[1]}).Fingerprint())
+	})
+	t.Run("filter by prefix", func(t *testing.T) {
+		matches := FilterFingerprint(entries, fp[:6])
+		assert.Equal(t, []sciond.PathReplyEntry{entries[0], entries[2]}, matches)
+	})
+	t.Run("filter is case-insensitive", func(t *testing.T) {
+		assert.Len(t, FilterFingerprint(entries, strings.ToUpper(fp)), 2)
+	})
+	t.Run("empty fingerprint matches nothing", func(t *testing.T) {
+		assert.Empty(t, FilterFingerprint(entries, ""))
+	})
+}
+
+func TestValidateFingerprint(t *testing.T) {
+	testCases := map[string]bool{
+		"":                  false,
+		"0a1B":              true,
+		"0123456789abcdef":  true,
+		"0123456789abcdef0": false,
+		"xyz":               false,
+	}
+	for fp, valid := range testCases {
+		t.Run(fp, func(t *testing.T) {
+			err := ValidateFingerprint(fp)
+			if valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics provides the metrics of the SVC requester.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/scionproto/scion/go/lib/prom"
+)
+
+// Namespace is the prometheus namespace.
+const Namespace = "svc"
+
+// Result type strings.
+const (
+	Success    = prom.Success
+	ErrTimeout = prom.ErrTimeout
+	ErrRequest = "err_request"
+)
+
+// Instances exposes the per-instance metrics.
+var Instances = newInstances()
+
+// InstanceLabels defines the labels of requests to a service instance.
+type InstanceLabels struct {
+	Instance string
+	Result   string
+}
+
+// Labels returns the list of labels.
+func (l InstanceLabels) Labels() []string {
+	return []string{"instance", prom.LabelResult}
+}
+
+// Values returns the label values in the order defined by Labels.
+func (l InstanceLabels) Values() []string {
+	return []string{l.Instance, l.Result}
+}
+
+type instances struct {
+	latency *prometheus.HistogramVec
+}
+
+func newInstances() instances {
+	return instances{
+		latency: prom.NewHistogramVec(Namespace, "", "instance_request_duration_seconds",
+			"Duration of requests to a single service instance.", InstanceLabels{}.Labels(),
+			prom.DefaultLatencyBuckets),
+	}
+}
+
+// Latency returns the histogram for the request durations with the given
+// labels.
+func (i instances) Latency(l InstanceLabels) prometheus.Observer {
+	return i.latency.WithLabelValues(l.Values()...)
+}
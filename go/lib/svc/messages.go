@@ -16,8 +16,12 @@ package svc
 
 import (
 	"io"
+	"net"
 	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/snet"
 	"github.com/scionproto/scion/go/lib/svc/internal/proto"
@@ -36,6 +40,27 @@ type Reply struct {
 	ReturnPath snet.Path
 }
 
+// UDPAddr returns the address of the UDP transport contained in the reply.
+func (r *Reply) UDPAddr() (*addr.AppAddr, error) {
+	address, ok := r.Transports[UDP]
+	if !ok {
+		return nil, common.NewBasicError(errNoTransport, nil, "transports", r.Transports)
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, common.NewBasicError(errBadTransport, err, "addr", address)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, common.NewBasicError(errBadTransport, nil, "addr", address)
+	}
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return nil, common.NewBasicError(errBadTransport, err, "addr", address)
+	}
+	return &addr.AppAddr{L3: addr.HostFromIP(ip), L4: addr.NewL4UDPInfo(uint16(p))}, nil
+}
+
 // DecodeFrom decodes a reply message from its capnp representation. No
 // validation of transport keys is performed.
 //
@@ -54,6 +79,16 @@ func (r *Reply) SerializeTo(wr io.Writer) error {
 	return r.toProtoFormat().SerializeTo(wr)
 }
 
+// key returns a string that identifies the instance that sent the reply. It
+// consists of all transport addresses in ascending key order.
+func (r *Reply) key() string {
+	var parts []string
+	for _, t := range r.toProtoFormat().Transports {
+		parts = append(parts, t.Key+"="+t.Value)
+	}
+	return strings.Join(parts, ",")
+}
+
 // toProtoFormat converts a reply message to a low-level format suitable for
 // network exchanges. The serializer uses this under the hood to convert the
 // reply message to a byte stream.
@@ -16,10 +16,12 @@ package svc
 
 import (
 	"bytes"
+	"net"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
 
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/svc/internal/proto"
 	"github.com/scionproto/scion/go/lib/xtest"
 )
@@ -191,3 +193,57 @@ func TestReplyFromProtoFormat(t *testing.T) {
 		SoMsg("data", reply, ShouldResemble, &Reply{Transports: make(map[Transport]string)})
 	})
 }
+
+func TestReplyUDPAddr(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Transports    map[Transport]string
+		ExpectedAddr  *addr.AppAddr
+		ExpectedError bool
+	}{
+		{
+			Name:          "no UDP transport",
+			Transports:    map[Transport]string{QUIC: "192.168.1.1:80"},
+			ExpectedError: true,
+		},
+		{
+			Name:          "missing port",
+			Transports:    map[Transport]string{UDP: "192.168.1.1"},
+			ExpectedError: true,
+		},
+		{
+			Name:          "hostname",
+			Transports:    map[Transport]string{UDP: "localhost:80"},
+			ExpectedError: true,
+		},
+		{
+			Name:       "IPv4 address",
+			Transports: map[Transport]string{UDP: "192.168.1.1:80"},
+			ExpectedAddr: &addr.AppAddr{
+				L3: addr.HostIPv4(net.IP{192, 168, 1, 1}),
+				L4: addr.NewL4UDPInfo(80),
+			},
+		},
+		{
+			Name:       "IPv6 address",
+			Transports: map[Transport]string{UDP: "[2001:db8::1]:30041"},
+			ExpectedAddr: &addr.AppAddr{
+				L3: addr.HostIPv6(net.ParseIP("2001:db8::1")),
+				L4: addr.NewL4UDPInfo(30041),
+			},
+		},
+	}
+
+	Convey("The UDP address should be extracted from the reply", t, func() {
+		for _, tc := range testCases {
+			Convey(tc.Name, func() {
+				reply := &Reply{Transports: tc.Transports}
+				appAddr, err := reply.UDPAddr()
+				xtest.SoMsgError("err", err, tc.ExpectedError)
+				if !tc.ExpectedError {
+					SoMsg("addr", appAddr.Equal(tc.ExpectedAddr), ShouldBeTrue)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svc
+
+import (
+	"context"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/svc/internal/metrics"
+)
+
+const errAllInstancesFailed = "all instances timed out"
+
+// InstanceResolver resolves an SVC address to all instances of the service.
+type InstanceResolver interface {
+	// LookupSVCInstances resolves all instances of the SVC service in the AS
+	// terminating the path.
+	LookupSVCInstances(ctx context.Context, p snet.Path, svc addr.HostSVC) ([]*Reply, error)
+}
+
+var _ InstanceResolver = (*Resolver)(nil)
+
+// RequestFunc performs a request/response exchange with a single service
+// instance. It must return once ctx is done.
+type RequestFunc func(ctx context.Context, instance *Reply) error
+
+// Requester performs request/response exchanges with a service in a remote
+// AS. It resolves all instances of the service and tries them in turn: if the
+// exchange with an instance times out, the next instance is tried, until one
+// succeeds or the context expires. Errors other than timeouts are returned
+// immediately. The latency of every exchange is recorded per instance.
+type Requester struct {
+	// Resolver resolves the instances of the service.
+	Resolver InstanceResolver
+	// AttemptTimeout is the maximum time spent on a single instance. If it is
+	// 0, the time left in the context is split evenly among the instances
+	// that were not tried yet.
+	AttemptTimeout time.Duration
+}
+
+// Request resolves svc in the AS terminating p, and calls f for the
+// instances until the exchange succeeds. It returns the instance that served
+// the request.
+func (r *Requester) Request(ctx context.Context, p snet.Path, svc addr.HostSVC,
+	f RequestFunc) (*Reply, error) {
+
+	instances, err := r.Resolver.LookupSVCInstances(ctx, p, svc)
+	if err != nil {
+		return nil, err
+	}
+	if len(instances) == 0 {
+		return nil, common.NewBasicError(errNoInstances, nil, "svc", svc)
+	}
+	logger := log.FromCtx(ctx)
+	var lastErr error
+	for i, instance := range instances {
+		if ctx.Err() != nil {
+			break
+		}
+		attemptCtx, cancelF := r.attemptCtx(ctx, len(instances)-i)
+		start := time.Now()
+		err := f(attemptCtx, instance)
+		timeout := err != nil && (common.IsTimeoutErr(err) || attemptCtx.Err() != nil)
+		cancelF()
+		labels := metrics.InstanceLabels{Instance: instance.key(), Result: metrics.Success}
+		switch {
+		case timeout:
+			labels.Result = metrics.ErrTimeout
+		case err != nil:
+			labels.Result = metrics.ErrRequest
+		}
+		metrics.Instances.Latency(labels).Observe(time.Since(start).Seconds())
+		if err != nil && !timeout {
+			return nil, err
+		}
+		if err == nil {
+			return instance, nil
+		}
+		logger.Debug("[svc.Requester] Instance timed out, trying next one", "svc", svc,
+			"instance", labels.Instance, "remaining", len(instances)-i-1)
+		lastErr = err
+	}
+	return nil, common.NewBasicError(errAllInstancesFailed, lastErr, "svc", svc,
+		"instances", len(instances))
+}
+
+func (r *Requester) attemptCtx(ctx context.Context,
+	remaining int) (context.Context, context.CancelFunc) {
+
+	if r.AttemptTimeout != 0 {
+		return context.WithTimeout(ctx, r.AttemptTimeout)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Until(deadline)/time.Duration(remaining))
+}
@@ -0,0 +1,159 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/svc"
+)
+
+type staticResolver struct {
+	instances []*svc.Reply
+	err       error
+}
+
+func (r staticResolver) LookupSVCInstances(_ context.Context, _ snet.Path,
+	_ addr.HostSVC) ([]*svc.Reply, error) {
+
+	return r.instances, r.err
+}
+
+func TestRequesterRequest(t *testing.T) {
+	instances := []*svc.Reply{
+		{Transports: map[svc.Transport]string{svc.UDP: "192.0.2.1:30041"}},
+		{Transports: map[svc.Transport]string{svc.UDP: "192.0.2.2:30041"}},
+		{Transports: map[svc.Transport]string{svc.UDP: "192.0.2.3:30041"}},
+	}
+	// waitTimeout blocks until the attempt times out.
+	waitTimeout := func(ctx context.Context, _ *svc.Reply) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	testCases := map[string]struct {
+		Resolver         svc.InstanceResolver
+		Handler          func(attempt int) svc.RequestFunc
+		ExpectedInstance *svc.Reply
+		ExpectedAttempts int
+		ExpectedErr      bool
+	}{
+		"resolver error": {
+			Resolver:    staticResolver{err: errors.New("resolve failed")},
+			ExpectedErr: true,
+		},
+		"no instances": {
+			Resolver:    staticResolver{},
+			ExpectedErr: true,
+		},
+		"first instance succeeds": {
+			Resolver: staticResolver{instances: instances},
+			Handler: func(int) svc.RequestFunc {
+				return func(context.Context, *svc.Reply) error { return nil }
+			},
+			ExpectedInstance: instances[0],
+			ExpectedAttempts: 1,
+		},
+		"timeout retries next instance": {
+			Resolver: staticResolver{instances: instances},
+			Handler: func(attempt int) svc.RequestFunc {
+				if attempt == 0 {
+					return waitTimeout
+				}
+				return func(context.Context, *svc.Reply) error { return nil }
+			},
+			ExpectedInstance: instances[1],
+			ExpectedAttempts: 2,
+		},
+		"non-timeout error is not retried": {
+			Resolver: staticResolver{instances: instances},
+			Handler: func(int) svc.RequestFunc {
+				return func(context.Context, *svc.Reply) error {
+					return errors.New("request failed")
+				}
+			},
+			ExpectedAttempts: 1,
+			ExpectedErr:      true,
+		},
+		"all instances time out": {
+			Resolver: staticResolver{instances: instances},
+			Handler: func(int) svc.RequestFunc {
+				return waitTimeout
+			},
+			ExpectedAttempts: 3,
+			ExpectedErr:      true,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancelF := context.WithTimeout(context.Background(), time.Second)
+			defer cancelF()
+			requester := &svc.Requester{
+				Resolver:       tc.Resolver,
+				AttemptTimeout: 20 * time.Millisecond,
+			}
+			var attempts int
+			var tried []*svc.Reply
+			instance, err := requester.Request(ctx, nil, addr.SvcPS,
+				func(ctx context.Context, instance *svc.Reply) error {
+					tried = append(tried, instance)
+					f := tc.Handler(attempts)
+					attempts++
+					return f(ctx, instance)
+				},
+			)
+			if tc.ExpectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.ExpectedInstance, instance)
+			assert.Equal(t, tc.ExpectedAttempts, attempts)
+			for i, instance := range tried {
+				assert.Equal(t, instances[i], instance)
+			}
+		})
+	}
+}
+
+func TestRequesterSplitsDeadline(t *testing.T) {
+	instances := []*svc.Reply{
+		{Transports: map[svc.Transport]string{svc.UDP: "192.0.2.1:30041"}},
+		{Transports: map[svc.Transport]string{svc.UDP: "192.0.2.2:30041"}},
+	}
+	ctx, cancelF := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancelF()
+	requester := &svc.Requester{Resolver: staticResolver{instances: instances}}
+	var budgets []time.Duration
+	_, err := requester.Request(ctx, nil, addr.SvcPS,
+		func(ctx context.Context, _ *svc.Reply) error {
+			deadline, ok := ctx.Deadline()
+			assert.True(t, ok)
+			budgets = append(budgets, time.Until(deadline))
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	)
+	assert.Error(t, err)
+	assert.Len(t, budgets, 2)
+	// The first attempt gets roughly half of the total time.
+	assert.True(t, budgets[0] <= 100*time.Millisecond, "budget %v", budgets[0])
+}
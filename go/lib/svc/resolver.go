@@ -41,8 +41,15 @@ const (
 	errRead           = "unable to read"
 	errDecode         = "decode failed"
 	errBadPath        = "unable to parse return path"
+	errNoTransport    = "no UDP transport in reply"
+	errBadTransport   = "unable to parse transport address"
+	errNoInstances    = "no instances found"
 )
 
+// DefaultCollectWindow is the time to wait for further replies after the
+// first reply to a multicast resolution request.
+const DefaultCollectWindow = 100 * time.Millisecond
+
 // Resolver performs SVC address resolution.
 type Resolver struct {
 	// LocalIA is the local AS.
@@ -56,6 +63,9 @@ type Resolver struct {
 	RoundTripper RoundTripper
 	// Payload is used for the data part of SVC requests.
 	Payload []byte
+	// CollectWindow is the time LookupSVCInstances waits for further replies
+	// after the first one. If it is 0, DefaultCollectWindow is used.
+	CollectWindow time.Duration
 }
 
 // LookupSVC resolves the SVC address for the AS terminating the path.
@@ -64,12 +74,80 @@ func (r *Resolver) LookupSVC(ctx context.Context, p snet.Path, svc addr.HostSVC)
 	span, ctx = opentracing.StartSpanFromContext(ctx, "svc.resolution")
 	defer span.Finish()
 
+	conn, requestPacket, err := r.prepare(p, svc)
+	if err != nil {
+		return nil, err
+	}
+	return r.getRoundTripper().RoundTrip(ctx, conn, requestPacket, p.OverlayNextHop())
+}
+
+// LookupSVCInstances resolves all instances of the SVC service in the AS
+// terminating the path. The request is sent to the multicast address of svc,
+// and replies are collected until ctx is done or CollectWindow passed after
+// the first reply. Only servers that handle multicast resolution requests
+// reply (see ResolverPacketDispatcher). Replies are returned in the order
+// they arrived, duplicates are dropped.
+func (r *Resolver) LookupSVCInstances(ctx context.Context, p snet.Path,
+	svc addr.HostSVC) ([]*Reply, error) {
+
+	var span opentracing.Span
+	span, ctx = opentracing.StartSpanFromContext(ctx, "svc.resolution.instances")
+	defer span.Finish()
+
+	conn, requestPacket, err := r.prepare(p, svc.Multicast())
+	if err != nil {
+		return nil, err
+	}
+	cancelF := ctxconn.CloseConnOnDone(ctx, conn)
+	defer cancelF()
+	if err := conn.WriteTo(requestPacket, p.OverlayNextHop()); err != nil {
+		return nil, common.NewBasicError(errWrite, err)
+	}
+	window := r.CollectWindow
+	if window == 0 {
+		window = DefaultCollectWindow
+	}
+	var replies []*Reply
+	seen := make(map[string]struct{})
+	for {
+		var replyPacket snet.SCIONPacket
+		var replyOv overlay.OverlayAddr
+		if err := conn.ReadFrom(&replyPacket, &replyOv); err != nil {
+			if len(replies) > 0 {
+				return replies, nil
+			}
+			return nil, common.NewBasicError(errRead, err)
+		}
+		reply, err := parseReply(&replyPacket, &replyOv)
+		if err != nil {
+			// Ignore garbage, other instances might still reply.
+			continue
+		}
+		key := reply.key()
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		if len(replies) == 0 {
+			if err := conn.SetReadDeadline(time.Now().Add(window)); err != nil {
+				return nil, common.NewBasicError(errRead, err)
+			}
+		}
+		replies = append(replies, reply)
+	}
+}
+
+// prepare registers the connection for a resolution request to svc and
+// builds the request packet.
+func (r *Resolver) prepare(p snet.Path,
+	svc addr.HostSVC) (snet.PacketConn, *snet.SCIONPacket, error) {
+
 	// FIXME(scrye): Assume registration is always instant for now. This,
 	// however, should respect ctx.
 	conn, port, err := r.ConnFactory.RegisterTimeout(r.LocalIA, r.Machine.AppAddress(),
 		nil, addr.SvcNone, 0)
 	if err != nil {
-		return nil, common.NewBasicError(errRegistration, err)
+		return nil, nil, common.NewBasicError(errRegistration, err)
 	}
 
 	requestPacket := &snet.SCIONPacket{
@@ -89,7 +167,23 @@ func (r *Resolver) LookupSVC(ctx context.Context, p snet.Path, svc addr.HostSVC)
 			Payload: common.RawBytes(r.Payload),
 		},
 	}
-	return r.getRoundTripper().RoundTrip(ctx, conn, requestPacket, p.OverlayNextHop())
+	return conn, requestPacket, nil
+}
+
+var _ snet.SVCResolver = (*Resolver)(nil)
+
+// ResolveSVC resolves the SVC address for the AS terminating the path, and
+// returns the UDP address of the service instance contained in the reply. It
+// can be used to resolve SVC destinations when dialing (see
+// snet.SCIONNetwork.SetSVCResolver).
+func (r *Resolver) ResolveSVC(ctx context.Context, p snet.Path,
+	svc addr.HostSVC) (*addr.AppAddr, error) {
+
+	reply, err := r.LookupSVC(ctx, p, svc)
+	if err != nil {
+		return nil, err
+	}
+	return reply.UDPAddr()
 }
 
 func (r *Resolver) getRoundTripper() RoundTripper {
@@ -139,6 +233,12 @@ func (roundTripper) RoundTrip(ctx context.Context, c snet.PacketConn, pkt *snet.
 	if err := c.ReadFrom(&replyPacket, &replyOv); err != nil {
 		return nil, common.NewBasicError(errRead, err)
 	}
+	return parseReply(&replyPacket, &replyOv)
+}
+
+// parseReply decodes the reply contained in replyPacket, and sets its return
+// path.
+func parseReply(replyPacket *snet.SCIONPacket, replyOv *overlay.OverlayAddr) (*Reply, error) {
 	b, ok := replyPacket.Payload.(common.RawBytes)
 	if !ok {
 		return nil, common.NewBasicError(errUnsupportedPld, nil, "payload", replyPacket.Payload)
@@ -158,7 +258,7 @@ func (roundTripper) RoundTrip(ctx context.Context, c snet.PacketConn, pkt *snet.
 	}
 	reply.ReturnPath = &path{
 		spath:       replyPacket.Path,
-		overlay:     &replyOv,
+		overlay:     replyOv,
 		destination: replyPacket.Source.IA,
 	}
 	return &reply, nil
@@ -70,6 +70,11 @@ var _ snet.PacketDispatcherService = (*ResolverPacketDispatcher)(nil)
 type ResolverPacketDispatcher struct {
 	dispService snet.PacketDispatcherService
 	handler     RequestHandler
+	// HandleMulticast enables SVC resolution for packets with a multicast SVC
+	// destination. Clients use this to discover all instances of a service
+	// (see Resolver.LookupSVCInstances). It must only be set if the handler
+	// forwards packets that are not resolution requests.
+	HandleMulticast bool
 }
 
 func (d *ResolverPacketDispatcher) RegisterTimeout(ia addr.IA, public *addr.AppAddr,
@@ -86,7 +91,8 @@ func (d *ResolverPacketDispatcher) RegisterTimeout(ia addr.IA, public *addr.AppA
 			IA:   ia,
 			Host: public.L3,
 		},
-		handler: d.handler,
+		handler:         d.handler,
+		handleMulticast: d.HandleMulticast,
 	}
 	return packetConn, port, err
 }
@@ -100,6 +106,8 @@ type resolverPacketConn struct {
 	source snet.SCIONAddress
 	// handler handles packets for SVC destinations.
 	handler RequestHandler
+	// handleMulticast enables the handler for multicast SVC destinations.
+	handleMulticast bool
 }
 
 func (c *resolverPacketConn) ReadFrom(pkt *snet.SCIONPacket, ov *overlay.OverlayAddr) error {
@@ -115,8 +123,8 @@ func (c *resolverPacketConn) ReadFrom(pkt *snet.SCIONPacket, ov *overlay.Overlay
 			return nil
 		}
 
-		// Multicasts do not trigger SVC resolution logic
-		if svc.IsMulticast() {
+		// Multicasts do not trigger SVC resolution logic, unless enabled
+		if svc.IsMulticast() && !c.handleMulticast {
 			return nil
 		}
 
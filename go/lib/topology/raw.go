@@ -167,6 +167,26 @@ type RawBRIntf struct {
 	ISD_AS        string
 	LinkTo        string
 	MTU           int
+	// Bond lists additional underlay links to the same neighbor. Together with
+	// the link described above, they form a bonded interface.
+	Bond []*RawBRBondLink `json:",omitempty"`
+}
+
+// RawBRBondLink is an additional underlay link of a bonded border router
+// interface. It uses the overlay type of the interface it belongs to.
+type RawBRBondLink struct {
+	PublicOverlay *RawAddrOverlay
+	BindOverlay   *RawAddr `json:",omitempty"`
+	RemoteOverlay *RawAddrOverlay
+}
+
+// intf returns a RawBRIntf that only holds the addresses of the bond link.
+func (l RawBRBondLink) intf() RawBRIntf {
+	return RawBRIntf{
+		PublicOverlay: l.PublicOverlay,
+		BindOverlay:   l.BindOverlay,
+		RemoteOverlay: l.RemoteOverlay,
+	}
 }
 
 // Convert a RawBRIntf struct (filled from JSON) to a TopoBRAddr (used by Go code)
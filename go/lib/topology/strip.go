@@ -48,6 +48,7 @@ func removeBRBind(brs map[string]*RawBRInfo) {
 			bri.Interfaces[i].BindOverlay = nil
 			bri.Interfaces[i].PublicOverlay = nil
 			bri.Interfaces[i].RemoteOverlay = nil
+			bri.Interfaces[i].Bond = nil
 		}
 	}
 }
@@ -234,6 +234,9 @@ func (t *Topo) populateBR(raw *RawTopo) error {
 			if ifinfo.Remote, err = rawIntf.remoteBRAddr(ifinfo.Overlay); err != nil {
 				return err
 			}
+			if ifinfo.Bond, err = bondLinks(rawIntf.Bond, ifinfo.Overlay); err != nil {
+				return common.NewBasicError("Unable to parse bond", err, "ifid", ifid)
+			}
 			brInfo.IFs[ifid] = &ifinfo
 			t.IFInfoMap[ifid] = ifinfo
 		}
@@ -366,6 +369,17 @@ func (svc *SVCInfo) GetAnyTopoAddr() *TopoAddr {
 	return svc.idTopoAddrMap.GetById(id)
 }
 
+// Names returns the names of all instances of the service.
+func (svc *SVCInfo) Names() ServiceNames {
+	return append(ServiceNames(nil), svc.names...)
+}
+
+// GetTopoAddr returns the address of the instance called name, or nil if
+// there is no such instance.
+func (svc *SVCInfo) GetTopoAddr(name string) *TopoAddr {
+	return svc.idTopoAddrMap.GetById(name)
+}
+
 func (svc *SVCInfo) GetAllTopoAddrs() []TopoAddr {
 	var topoAddrs []TopoAddr
 	for _, topoAddr := range svc.idTopoAddrMap {
@@ -441,6 +455,15 @@ type IFInfo struct {
 	ISD_AS        addr.IA
 	LinkType      proto.LinkType
 	MTU           int
+	// Bond contains the additional underlay links of a bonded interface. All
+	// links connect to the same neighbor and share the interface ID.
+	Bond []IFLink
+}
+
+// IFLink is an additional underlay link of a bonded interface.
+type IFLink struct {
+	Local  *TopoBRAddr
+	Remote *overlay.OverlayAddr
 }
 
 func (i IFInfo) Verify(isCore bool, brName string) error {
@@ -464,6 +487,38 @@ func (i IFInfo) Verify(isCore bool, brName string) error {
 
 func (i IFInfo) String() string {
 	return fmt.Sprintf("IFinfo: Name[%s] IntAddr[%+v] CtrlAddr[%+v] Overlay:%s Local:%+v "+
-		"Remote:%+v Bw:%d IA:%s Type:%s MTU:%d", i.BRName, i.InternalAddrs, i.CtrlAddrs, i.Overlay,
-		i.Local, i.Remote, i.Bandwidth, i.ISD_AS, i.LinkType, i.MTU)
+		"Remote:%+v Bond:%d Bw:%d IA:%s Type:%s MTU:%d", i.BRName, i.InternalAddrs, i.CtrlAddrs,
+		i.Overlay, i.Local, i.Remote, len(i.Bond), i.Bandwidth, i.ISD_AS, i.LinkType, i.MTU)
+}
+
+// Links returns all underlay links of the interface, starting with the
+// primary link. Interfaces without a bond have exactly one link.
+func (i IFInfo) Links() []IFLink {
+	links := make([]IFLink, 0, 1+len(i.Bond))
+	links = append(links, IFLink{Local: i.Local, Remote: i.Remote})
+	return append(links, i.Bond...)
+}
+
+// bondLinks converts the raw bond links of an interface.
+func bondLinks(raw []*RawBRBondLink, o overlay.Type) ([]IFLink, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	links := make([]IFLink, 0, len(raw))
+	for i, rawLink := range raw {
+		if rawLink == nil || rawLink.PublicOverlay == nil || rawLink.RemoteOverlay == nil {
+			return nil, common.NewBasicError("Incomplete bond link", nil, "idx", i)
+		}
+		rawIntf := rawLink.intf()
+		var link IFLink
+		var err error
+		if link.Local, err = rawIntf.localTopoBRAddr(o); err != nil {
+			return nil, err
+		}
+		if link.Remote, err = rawIntf.remoteBRAddr(o); err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	return links, nil
 }
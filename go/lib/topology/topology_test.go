@@ -250,6 +250,14 @@ func Test_IFInfoMap(t *testing.T) {
 		ISD_AS:    isdas,
 		LinkType:  proto.LinkType_parent,
 		MTU:       1472,
+		Bond: []IFLink{
+			{
+				Local: &TopoBRAddr{
+					IPv4:    mkOB("192.0.2.11", 44997, "10.0.0.11"),
+					Overlay: overlay.UDPIPv4},
+				Remote: mkO(addr.HostFromIPStr("192.0.2.12"), 44998),
+			},
+		},
 	}
 	isdas, _ = addr.IAFromString("1-ff00:0:314")
 	ifm[3] = IFInfo{
@@ -466,3 +474,20 @@ func Test_TopoFromStripped(t *testing.T) {
 	})
 
 }
+
+func TestBondLinks(t *testing.T) {
+	Convey("Incomplete bond links are rejected", t, func() {
+		raw := []*RawBRBondLink{{PublicOverlay: &RawAddrOverlay{Addr: "192.0.2.11"}}}
+		_, err := bondLinks(raw, overlay.IPv4)
+		So(err, ShouldNotBeNil)
+	})
+	Convey("Links starts with the primary link", t, func() {
+		loadTopo("testdata/basic.json", t)
+		c := testTopo
+		links := c.IFInfoMap[1].Links()
+		So(len(links), ShouldEqual, 2)
+		So(links[0].Remote, ShouldResemble, c.IFInfoMap[1].Remote)
+		So(links[1], ShouldResemble, c.IFInfoMap[1].Bond[0])
+		So(len(c.IFInfoMap[3].Links()), ShouldEqual, 1)
+	})
+}
@@ -29,22 +29,26 @@ import (
 )
 
 var (
-	DefaultQueryInterval      = 5 * time.Minute
-	DefaultCryptoSyncInterval = 30 * time.Second
+	DefaultQueryInterval       = 5 * time.Minute
+	DefaultCryptoSyncInterval  = 30 * time.Second
+	DefaultReplicationInterval = 5 * time.Second
+	DefaultPrefetchMinRequests = 10
+	DefaultPrefetchLeadTime    = 5 * time.Minute
 )
 
 var _ config.Config = (*Config)(nil)
 
 type Config struct {
-	General   env.General
-	Features  env.Features
-	Logging   env.Logging
-	Metrics   env.Metrics
-	Tracing   env.Tracing
-	QUIC      env.QUIC `toml:"quic"`
-	TrustDB   truststorage.TrustDBConf
-	Discovery idiscovery.Config
-	PS        PSConfig
+	General        env.General
+	Features       env.Features
+	Logging        env.Logging
+	Metrics        env.Metrics
+	Tracing        env.Tracing
+	QUIC           env.QUIC           `toml:"quic"`
+	CircuitBreaker env.CircuitBreaker `toml:"circuit_breaker"`
+	TrustDB        truststorage.TrustDBConf
+	Discovery      idiscovery.Config
+	PS             PSConfig
 }
 
 func (cfg *Config) InitDefaults() {
@@ -66,6 +70,8 @@ func (cfg *Config) Validate() error {
 		&cfg.Features,
 		&cfg.Logging,
 		&cfg.Metrics,
+		&cfg.QUIC,
+		&cfg.CircuitBreaker,
 		&cfg.TrustDB,
 		&cfg.Discovery,
 		&cfg.PS,
@@ -80,6 +86,7 @@ func (cfg *Config) Sample(dst io.Writer, path config.Path, _ config.CtxMap) {
 		&cfg.Metrics,
 		&cfg.Tracing,
 		&cfg.QUIC,
+		&cfg.CircuitBreaker,
 		&cfg.TrustDB,
 		&cfg.Discovery,
 		&cfg.PS,
@@ -90,20 +97,44 @@ func (cfg *Config) ConfigName() string {
 	return "ps_config"
 }
 
+// CheckFiles checks that the files referenced by the config can be loaded.
+func (cfg *Config) CheckFiles() error {
+	return env.CheckFilesAll(&cfg.General)
+}
+
 var _ config.Config = (*PSConfig)(nil)
 
 type PSConfig struct {
 	// SegSync enables the "old" replication of down segments between cores,
 	// using SegSync messages.
-	SegSync  bool
-	PathDB   pathstorage.PathDBConf
-	RevCache pathstorage.RevCacheConf
+	SegSync bool
+	// Replication enables the replication of registered segments to the
+	// other path server instances of the local core AS.
+	Replication bool
+	// ReplicationInterval specifies the interval of the replication towards
+	// the other path server instances of the local AS.
+	ReplicationInterval util.DurWrap
+	PathDB              pathstorage.PathDBConf
+	RevCache            pathstorage.RevCacheConf
 	// QueryInterval specifies after how much time segments
 	// for a destination should be refetched.
 	QueryInterval util.DurWrap
 	// CryptoSyncInterval specifies the interval of crypto pushes towards
 	// the local CS.
 	CryptoSyncInterval util.DurWrap
+	// Prefetch enables re-fetching the segments of frequently requested
+	// destinations before they expire.
+	Prefetch bool
+	// PrefetchMinRequests specifies how many times a destination must have
+	// been requested since the last re-fetch to be re-fetched again.
+	PrefetchMinRequests int
+	// PrefetchLeadTime specifies how long before the expiry of the served
+	// segments a destination is re-fetched.
+	PrefetchLeadTime util.DurWrap
+	// KeepRevoked makes the path server reply with segments that are affected
+	// by active revocations, together with the revocations, instead of
+	// filtering them out. Requests can override this setting.
+	KeepRevoked bool
 }
 
 func (cfg *PSConfig) InitDefaults() {
@@ -113,6 +144,15 @@ func (cfg *PSConfig) InitDefaults() {
 	if cfg.CryptoSyncInterval.Duration == 0 {
 		cfg.CryptoSyncInterval.Duration = DefaultCryptoSyncInterval
 	}
+	if cfg.ReplicationInterval.Duration == 0 {
+		cfg.ReplicationInterval.Duration = DefaultReplicationInterval
+	}
+	if cfg.PrefetchMinRequests == 0 {
+		cfg.PrefetchMinRequests = DefaultPrefetchMinRequests
+	}
+	if cfg.PrefetchLeadTime.Duration == 0 {
+		cfg.PrefetchLeadTime.Duration = DefaultPrefetchLeadTime
+	}
 	config.InitAll(&cfg.PathDB, &cfg.RevCache)
 }
 
@@ -120,6 +160,9 @@ func (cfg *PSConfig) Validate() error {
 	if cfg.QueryInterval.Duration == 0 {
 		return serrors.New("QueryInterval must not be zero")
 	}
+	if cfg.PrefetchMinRequests < 0 {
+		return serrors.New("PrefetchMinRequests must not be negative")
+	}
 	return config.ValidateAll(&cfg.PathDB, &cfg.RevCache)
 }
 
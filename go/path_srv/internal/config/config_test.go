@@ -48,12 +48,16 @@ func InitTestConfig(cfg *Config) {
 
 func InitTestPSConfig(cfg *PSConfig) {
 	cfg.SegSync = true
+	cfg.Replication = true
+	cfg.Prefetch = true
+	cfg.KeepRevoked = true
 	pathstoragetest.InitTestPathDBConf(&cfg.PathDB)
 	pathstoragetest.InitTestRevCacheConf(&cfg.RevCache)
 }
 
 func CheckTestConfig(t *testing.T, cfg *Config, id string) {
 	envtest.CheckTest(t, &cfg.General, &cfg.Logging, &cfg.Metrics, &cfg.Tracing, nil, id)
+	envtest.CheckTestCircuitBreaker(t, &cfg.CircuitBreaker)
 	truststoragetest.CheckTestConfig(t, &cfg.TrustDB, id)
 	idiscoverytest.CheckTestConfig(t, &cfg.Discovery)
 	CheckTestPSConfig(t, &cfg.PS, id)
@@ -63,6 +67,12 @@ func CheckTestPSConfig(t *testing.T, cfg *PSConfig, id string) {
 	pathstoragetest.CheckTestPathDBConf(t, &cfg.PathDB, id)
 	pathstoragetest.CheckTestRevCacheConf(t, &cfg.RevCache)
 	assert.False(t, cfg.SegSync)
+	assert.False(t, cfg.Replication)
+	assert.Equal(t, DefaultReplicationInterval, cfg.ReplicationInterval.Duration)
+	assert.False(t, cfg.Prefetch)
+	assert.Equal(t, DefaultPrefetchMinRequests, cfg.PrefetchMinRequests)
+	assert.Equal(t, DefaultPrefetchLeadTime, cfg.PrefetchLeadTime.Duration)
+	assert.False(t, cfg.KeepRevoked)
 	assert.Equal(t, DefaultQueryInterval, cfg.QueryInterval.Duration)
 	assert.Equal(t, DefaultCryptoSyncInterval, cfg.CryptoSyncInterval.Duration)
 }
@@ -21,9 +21,34 @@ const psSample = `
 # messages. (default false)
 SegSync = false
 
+# Enable the replication of registered segments to the other path server
+# instances of the local core AS. (default false)
+Replication = false
+
+# The interval of the replication towards the other path server instances of
+# the local AS. (default 5s)
+ReplicationInterval = "5s"
+
 # The time after which segments for a destination are refetched. (default 5m)
 QueryInterval = "5m"
 
 # The interval of crypto pushes towards the local CS. (default 30s)
 CryptoSyncInterval = "30s"
+
+# Enable re-fetching the segments of frequently requested destinations before
+# they expire. (default false)
+Prefetch = false
+
+# The number of requests for a destination since the last re-fetch required to
+# re-fetch its segments again. (default 10)
+PrefetchMinRequests = 10
+
+# The time before the expiry of the served segments at which a destination is
+# re-fetched. (default 5m)
+PrefetchLeadTime = "5m"
+
+# Reply with segments that are affected by active revocations, together with
+# the revocations, instead of filtering them out. Requests can override this
+# setting. (default false)
+KeepRevoked = false
 `
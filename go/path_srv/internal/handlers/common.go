@@ -25,6 +25,7 @@ import (
 	"github.com/scionproto/scion/go/lib/ctrl/seg"
 	"github.com/scionproto/scion/go/lib/infra"
 	"github.com/scionproto/scion/go/lib/infra/modules/segfetcher"
+	"github.com/scionproto/scion/go/lib/infra/modules/segverifier"
 	"github.com/scionproto/scion/go/lib/pathdb"
 	"github.com/scionproto/scion/go/lib/pathdb/query"
 	"github.com/scionproto/scion/go/lib/revcache"
@@ -45,6 +46,13 @@ type HandlerArgs struct {
 	IA              addr.IA
 	TopoProvider    topology.Provider
 	SegRequestAPI   segfetcher.RequestAPI
+	// KeepRevoked indicates whether segment replies contain segments affected
+	// by active revocations, unless the request specifies otherwise.
+	KeepRevoked bool
+	// VerificationCache caches the results of segment and revocation
+	// verifications, such that segments received from many peers are only
+	// verified once.
+	VerificationCache *segverifier.Cache
 }
 
 type baseHandler struct {
@@ -0,0 +1,34 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"github.com/scionproto/scion/go/lib/infra"
+	"github.com/scionproto/scion/go/lib/infra/messenger"
+	"github.com/scionproto/scion/go/lib/log"
+)
+
+// WithRequestID wraps handler such that every request is assigned a new
+// request ID. The ID is added to the logger of the request context as req_id,
+// and is carried in the upper 32 bits of the IDs of the requests that are sent
+// to other services on behalf of the request, see messenger.NextIdFromCtx.
+func WithRequestID(handler infra.Handler) infra.Handler {
+	return infra.HandlerFunc(func(r *infra.Request) *infra.HandlerResult {
+		id := messenger.NewRequestID()
+		ctx := messenger.NewContextWithRequestID(r.Context(), id)
+		ctx = log.CtxWith(ctx, log.FromCtx(ctx).New("req_id", id))
+		return handler.Handle(infra.NewRequest(ctx, r.Message, r.FullMessage, r.Peer, r.ID))
+	})
+}
@@ -0,0 +1,46 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/infra"
+	"github.com/scionproto/scion/go/lib/infra/messenger"
+)
+
+func TestWithRequestID(t *testing.T) {
+	msg := &path_mgmt.SegReq{}
+	var ids []messenger.RequestID
+	handler := WithRequestID(infra.HandlerFunc(func(r *infra.Request) *infra.HandlerResult {
+		assert.Equal(t, msg, r.Message)
+		assert.Equal(t, uint64(42), r.ID)
+		id, ok := messenger.RequestIDFromContext(r.Context())
+		require.True(t, ok)
+		ids = append(ids, id)
+		return infra.MetricsResultOk
+	}))
+	for i := 0; i < 2; i++ {
+		result := handler.Handle(infra.NewRequest(context.Background(), msg, nil, nil, 42))
+		assert.Equal(t, infra.MetricsResultOk, result)
+	}
+	require.Len(t, ids, 2)
+	assert.NotEqual(t, ids[0], ids[1])
+}
@@ -41,6 +41,7 @@ func NewSegRegHandler(args HandlerArgs) infra.Handler {
 			handler: seghandler.Handler{
 				Verifier: &seghandler.DefaultVerifier{
 					Verifier: args.VerifierFactory.NewVerifier(),
+					Cache:    args.VerificationCache,
 				},
 				Storage: &seghandler.DefaultStorage{
 					PathDB:   args.PathDB,
@@ -106,22 +107,40 @@ func (h *segRegHandler) Handle() *infra.HandlerResult {
 	// wait until processing is done.
 	<-res.FullReplyProcessed()
 	if err := res.Err(); err != nil {
-		// TODO(lukedirtwalker): classify crypto/db error
-		labels.Result = metrics.ErrCrypto
+		// Verification errors are reported per segment, the error is caused
+		// by the storage.
+		labels.Result = metrics.ErrDB
 		metrics.Registrations.ResultsTotal(labels).Inc()
 		sendAck(proto.Ack_ErrCode_reject, err.Error())
 		return infra.MetricsErrInvalid
 	}
-	h.incMetrics(labels, res.Stats())
+	h.incMetrics(labels, res)
+	if failed := res.SegResultCount(seghandler.SegVerifyFailed); failed > 0 &&
+		failed == len(res.SegResults()) {
+
+		logger.Warn("[segRegHandler] No registered segment could be verified",
+			"errs", res.VerificationErrors())
+		sendAck(proto.Ack_ErrCode_reject, messenger.AckRejectFailedToVerify)
+		return infra.MetricsErrInvalid
+	}
 	sendAck(proto.Ack_ErrCode_ok, "")
 	return infra.MetricsResultOk
 }
 
-func (h *segRegHandler) incMetrics(labels metrics.RegistrationLabels, stats seghandler.Stats) {
+func (h *segRegHandler) incMetrics(labels metrics.RegistrationLabels,
+	res *seghandler.ProcessedResult) {
+
+	stats := res.Stats()
 	labels.Result = metrics.RegistrationNew
 	metrics.Registrations.ResultsTotal(labels).Add(float64(len(stats.SegDB.InsertedSegs)))
 	labels.Result = metrics.RegiststrationUpdated
 	metrics.Registrations.ResultsTotal(labels).Add(float64(len(stats.SegDB.UpdatedSegs)))
+	labels.Result = metrics.RegistrationDuplicate
+	metrics.Registrations.ResultsTotal(labels).Add(
+		float64(res.SegResultCount(seghandler.SegFiltered)))
+	labels.Result = metrics.ErrCrypto
+	metrics.Registrations.ResultsTotal(labels).Add(
+		float64(res.SegResultCount(seghandler.SegVerifyFailed)))
 }
 
 // classifySegs determines the type of segments that are registered. In the
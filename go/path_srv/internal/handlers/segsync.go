@@ -40,6 +40,7 @@ func NewSyncHandler(args HandlerArgs) infra.Handler {
 			handler: seghandler.Handler{
 				Verifier: &seghandler.DefaultVerifier{
 					Verifier: args.VerifierFactory.NewVerifier(),
+					Cache:    args.VerificationCache,
 				},
 				Storage: &seghandler.DefaultStorage{
 					PathDB:   args.PathDB,
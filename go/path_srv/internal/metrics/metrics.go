@@ -34,6 +34,7 @@ const (
 	Success               = prom.Success
 	RegistrationNew       = "ok_new"
 	RegiststrationUpdated = "ok_updated"
+	RegistrationDuplicate = "ok_duplicate"
 	RequestCached         = "ok_cached"
 	RequestFetched        = "ok_fetched"
 	ErrParse              = prom.ErrParse
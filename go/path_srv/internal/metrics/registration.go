@@ -26,8 +26,8 @@ import (
 )
 
 // regResults lists all possible results for registrations.
-var regResults = []string{RegistrationNew, RegiststrationUpdated, ErrParse, ErrInternal,
-	ErrCrypto, ErrDB, ErrInternal, ErrTimeout}
+var regResults = []string{RegistrationNew, RegiststrationUpdated, RegistrationDuplicate,
+	ErrParse, ErrInternal, ErrCrypto, ErrDB, ErrInternal, ErrTimeout}
 
 // RegistrationLabels contains the label values for registration metrics.
 type RegistrationLabels struct {
@@ -58,6 +58,10 @@ func (db *PathDB) Get(ctx context.Context, params *query.Params) (query.Results,
 	return res, err
 }
 
+// GetNextQuery implements the path db's get next query function. Local
+// segments are never queried. For non-local segments the zero time is
+// returned if ctx requests a refresh (see WithRefresh), such that the segments
+// are queried regardless of the stored next query time.
 func (db *PathDB) GetNextQuery(ctx context.Context, src, dst addr.IA,
 	policy pathdb.PolicyHash) (time.Time, error) {
 	if local, err := db.LocalInfo.IsSegLocal(ctx, src, dst); err != nil {
@@ -65,9 +69,26 @@ func (db *PathDB) GetNextQuery(ctx context.Context, src, dst addr.IA,
 	} else if local {
 		return time.Now().Add(24 * time.Hour), nil
 	}
+	if refreshFromCtx(ctx) {
+		return time.Time{}, nil
+	}
 	return db.PathDB.GetNextQuery(ctx, src, dst, policy)
 }
 
+type refreshKey struct{}
+
+// WithRefresh returns a context that makes a fetcher backed by PathDB query
+// the non-local segments of a request, even if the cached segments are still
+// up to date.
+func WithRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, refreshKey{}, true)
+}
+
+func refreshFromCtx(ctx context.Context) bool {
+	refresh, _ := ctx.Value(refreshKey{}).(bool)
+	return refresh
+}
+
 // CoreLocalInfo implements local info for core PSes.
 type CoreLocalInfo struct {
 	CoreChecker CoreChecker
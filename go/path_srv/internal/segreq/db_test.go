@@ -122,6 +122,7 @@ func TestPSPathDBGetNextQuery(t *testing.T) {
 		PreparePathDB           func(db *mock_pathdb.MockPathDB, src, dst addr.IA)
 		PrepareLocalInfo        func(i *mock_segreq.MockLocalInfo, src, dst addr.IA)
 		ErrorAssertion          require.ErrorAssertionFunc
+		Refresh                 bool
 		AssertNextQueryAfterNow assert.BoolAssertionFunc
 	}{
 		"LocalInfo error": {
@@ -160,6 +161,30 @@ func TestPSPathDBGetNextQuery(t *testing.T) {
 			ErrorAssertion:          require.NoError,
 			AssertNextQueryAfterNow: assert.True,
 		},
+		"Is Local with refresh": {
+			Src:           xtest.MustParseIA("1-ff00:0:111"),
+			Dst:           xtest.MustParseIA("1-ff00:0:120"),
+			PreparePathDB: func(db *mock_pathdb.MockPathDB, src, dst addr.IA) {},
+			PrepareLocalInfo: func(i *mock_segreq.MockLocalInfo, src, dst addr.IA) {
+				i.EXPECT().IsSegLocal(gomock.Any(), src, dst).
+					Return(true, nil)
+			},
+			Refresh:                 true,
+			ErrorAssertion:          require.NoError,
+			AssertNextQueryAfterNow: assert.True,
+		},
+		"Non local with refresh": {
+			Src:           xtest.MustParseIA("1-ff00:0:111"),
+			Dst:           xtest.MustParseIA("1-ff00:0:120"),
+			PreparePathDB: func(db *mock_pathdb.MockPathDB, src, dst addr.IA) {},
+			PrepareLocalInfo: func(i *mock_segreq.MockLocalInfo, src, dst addr.IA) {
+				i.EXPECT().IsSegLocal(gomock.Any(), src, dst).
+					Return(false, nil)
+			},
+			Refresh:                 true,
+			ErrorAssertion:          require.NoError,
+			AssertNextQueryAfterNow: assert.False,
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -173,7 +198,11 @@ func TestPSPathDBGetNextQuery(t *testing.T) {
 				PathDB:    pdb,
 				LocalInfo: li,
 			}
-			nq, err := db.GetNextQuery(context.Background(), test.Src, test.Dst, nil)
+			ctx := context.Background()
+			if test.Refresh {
+				ctx = segreq.WithRefresh(ctx)
+			}
+			nq, err := db.GetNextQuery(ctx, test.Src, test.Dst, nil)
 			test.ErrorAssertion(t, err)
 			test.AssertNextQueryAfterNow(t, nq.After(time.Now()))
 		})
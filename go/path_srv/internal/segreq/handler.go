@@ -31,29 +31,52 @@ import (
 )
 
 type handler struct {
-	fetcher  *segfetcher.Fetcher
-	revCache revcache.RevCache
+	fetcher     *segfetcher.Fetcher
+	keepFetcher *segfetcher.Fetcher
+	keepRevoked bool
+	revCache    revcache.RevCache
+	tracker     *UsageTracker
 }
 
-func NewHandler(args handlers.HandlerArgs) infra.Handler {
-	core := args.TopoProvider.Get().Core
-	args.PathDB = createPathDB(args, core)
+// NewHandler creates a new segment request handler that resolves requests
+// with fetcher (see NewFetcher). If tracker is not nil, every successfully
+// served request is recorded in the tracker.
+func NewHandler(args handlers.HandlerArgs, fetcher *segfetcher.Fetcher,
+	tracker *UsageTracker) infra.Handler {
+
 	return &handler{
-		fetcher: segfetcher.FetcherConfig{
-			QueryInterval:       args.QueryInterval,
-			LocalIA:             args.IA,
-			ASInspector:         args.ASInspector,
-			VerificationFactory: args.VerifierFactory,
-			PathDB:              args.PathDB,
-			RevCache:            args.RevCache,
-			RequestAPI:          args.SegRequestAPI,
-			DstProvider:         createDstProvider(args, core),
-			Splitter:            &Splitter{ASInspector: args.ASInspector},
-		}.New(),
-		revCache: args.RevCache,
+		fetcher:     fetcher,
+		keepFetcher: newFetcher(args, true),
+		keepRevoked: args.KeepRevoked,
+		revCache:    args.RevCache,
+		tracker:     tracker,
 	}
 }
 
+// NewFetcher creates the segment fetcher used by the path server to resolve
+// segment requests. The fetcher filters out revoked segments.
+func NewFetcher(args handlers.HandlerArgs) *segfetcher.Fetcher {
+	return newFetcher(args, false)
+}
+
+func newFetcher(args handlers.HandlerArgs, keepRevoked bool) *segfetcher.Fetcher {
+	core := args.TopoProvider.Get().Core
+	args.PathDB = createPathDB(args, core)
+	return segfetcher.FetcherConfig{
+		QueryInterval:       args.QueryInterval,
+		LocalIA:             args.IA,
+		ASInspector:         args.ASInspector,
+		VerificationFactory: args.VerifierFactory,
+		PathDB:              args.PathDB,
+		RevCache:            args.RevCache,
+		RequestAPI:          args.SegRequestAPI,
+		DstProvider:         createDstProvider(args, core),
+		Splitter:            &Splitter{ASInspector: args.ASInspector},
+		KeepRevoked:         keepRevoked,
+		VerificationCache:   args.VerificationCache,
+	}.New()
+}
+
 func (h *handler) Handle(request *infra.Request) *infra.HandlerResult {
 	ctx := request.Context()
 	logger := log.FromCtx(ctx)
@@ -78,8 +101,8 @@ func (h *handler) Handle(request *infra.Request) *infra.HandlerResult {
 	}
 	sendAck := messenger.SendAckHelper(ctx, rw)
 
-	segs, err := h.fetcher.FetchSegs(ctx,
-		segfetcher.Request{Src: segReq.SrcIA(), Dst: segReq.DstIA()})
+	req := segfetcher.Request{Src: segReq.SrcIA(), Dst: segReq.DstIA()}
+	segs, err := h.fetcherFor(segReq.Flags.RevFilter).FetchSegs(ctx, req)
 	if err != nil {
 		// TODO(lukedirtwalker): Define clearer the different errors that can
 		// occur and depending on them reply / return different error codes.
@@ -90,6 +113,9 @@ func (h *handler) Handle(request *infra.Request) *infra.HandlerResult {
 		metrics.Requests.Count(labels).Inc()
 		return infra.MetricsErrInternal
 	}
+	if h.tracker != nil {
+		h.tracker.Record(req, segs)
+	}
 	labels.SegType = metrics.DetermineReplyType(segs)
 	revs, err := revcache.RelevantRevInfos(ctx, h.revCache, segs.Up, segs.Core, segs.Down)
 	if err != nil {
@@ -116,6 +142,22 @@ func (h *handler) Handle(request *infra.Request) *infra.HandlerResult {
 	return infra.MetricsResultOk
 }
 
+// fetcherFor returns the fetcher that handles revoked segments as requested by
+// the filter. Unknown filters are handled like the default.
+func (h *handler) fetcherFor(filter path_mgmt.RevFilter) *segfetcher.Fetcher {
+	keep := h.keepRevoked
+	switch filter {
+	case path_mgmt.RevFilterDrop:
+		keep = false
+	case path_mgmt.RevFilterAnnotate:
+		keep = true
+	}
+	if keep {
+		return h.keepFetcher
+	}
+	return h.fetcher
+}
+
 func createValidator(args handlers.HandlerArgs, core bool) segfetcher.Validator {
 	base := BaseValidator{
 		CoreChecker: CoreChecker{Inspector: args.ASInspector},
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/scionproto/scion/go/path_srv/internal/segreq (interfaces: LocalInfo)
+// Source: github.com/scionproto/scion/go/path_srv/internal/segreq (interfaces: LocalInfo,SegFetcher)
 
 // Package mock_segreq is a generated GoMock package.
 package mock_segreq
@@ -8,6 +8,7 @@ import (
 	context "context"
 	gomock "github.com/golang/mock/gomock"
 	addr "github.com/scionproto/scion/go/lib/addr"
+	segfetcher "github.com/scionproto/scion/go/lib/infra/modules/segfetcher"
 	query "github.com/scionproto/scion/go/lib/pathdb/query"
 	reflect "reflect"
 )
@@ -63,3 +64,41 @@ func (mr *MockLocalInfoMockRecorder) IsSegLocal(arg0, arg1, arg2 interface{}) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSegLocal", reflect.TypeOf((*MockLocalInfo)(nil).IsSegLocal), arg0, arg1, arg2)
 }
+
+// MockSegFetcher is a mock of SegFetcher interface
+type MockSegFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockSegFetcherMockRecorder
+}
+
+// MockSegFetcherMockRecorder is the mock recorder for MockSegFetcher
+type MockSegFetcherMockRecorder struct {
+	mock *MockSegFetcher
+}
+
+// NewMockSegFetcher creates a new mock instance
+func NewMockSegFetcher(ctrl *gomock.Controller) *MockSegFetcher {
+	mock := &MockSegFetcher{ctrl: ctrl}
+	mock.recorder = &MockSegFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSegFetcher) EXPECT() *MockSegFetcherMockRecorder {
+	return m.recorder
+}
+
+// FetchSegs mocks base method
+func (m *MockSegFetcher) FetchSegs(arg0 context.Context, arg1 segfetcher.Request) (segfetcher.Segments, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchSegs", arg0, arg1)
+	ret0, _ := ret[0].(segfetcher.Segments)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchSegs indicates an expected call of FetchSegs
+func (mr *MockSegFetcherMockRecorder) FetchSegs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchSegs", reflect.TypeOf((*MockSegFetcher)(nil).FetchSegs), arg0, arg1)
+}
@@ -0,0 +1,184 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segreq
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/infra/modules/segfetcher"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/periodic"
+)
+
+const (
+	// DefaultPrefetchMaxIdle is the default time after which a request that
+	// was not seen anymore is no longer considered for prefetching.
+	DefaultPrefetchMaxIdle = 10 * time.Minute
+	// minPrefetchInterval is the minimum time between two prefetches of the
+	// same request.
+	minPrefetchInterval = 30 * time.Second
+)
+
+// SegFetcher fetches the segments for a request. The prefetcher calls it with
+// a refresh context (see WithRefresh), so it should be backed by PathDB.
+type SegFetcher interface {
+	FetchSegs(ctx context.Context, req segfetcher.Request) (segfetcher.Segments, error)
+}
+
+// UsageTracker tracks how often segment requests are served and when the
+// earliest served segment expires. It is safe for concurrent use.
+type UsageTracker struct {
+	mtx     sync.Mutex
+	entries map[requestKey]*usage
+}
+
+// NewUsageTracker creates a new empty usage tracker.
+func NewUsageTracker() *UsageTracker {
+	return &UsageTracker{entries: make(map[requestKey]*usage)}
+}
+
+// Record records that the request was served with the given segments.
+// Requests that were served without any segments are ignored.
+func (t *UsageTracker) Record(req segfetcher.Request, segs segfetcher.Segments) {
+	expiry := minExpiry(segs)
+	if expiry.IsZero() {
+		return
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	key := requestKey{src: req.Src, dst: req.Dst}
+	u, ok := t.entries[key]
+	if !ok {
+		u = &usage{}
+		t.entries[key] = u
+	}
+	u.uses++
+	u.lastUsed = time.Now()
+	u.expiry = expiry
+}
+
+// expiring returns the requests that have been used at least minUses times
+// since the last prefetch and whose segments expire within leadTime. The
+// returned requests are marked as prefetched at now, so that failing
+// prefetches are not retried immediately. Requests that have not been used
+// for longer than maxIdle are removed.
+func (t *UsageTracker) expiring(now time.Time, minUses int,
+	leadTime, maxIdle time.Duration) []segfetcher.Request {
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	var reqs []segfetcher.Request
+	for key, u := range t.entries {
+		if now.Sub(u.lastUsed) > maxIdle {
+			delete(t.entries, key)
+			continue
+		}
+		if u.uses < minUses || u.expiry.After(now.Add(leadTime)) ||
+			now.Sub(u.lastPrefetch) < minPrefetchInterval {
+			continue
+		}
+		u.lastPrefetch = now
+		reqs = append(reqs, segfetcher.Request{Src: key.src, Dst: key.dst})
+	}
+	return reqs
+}
+
+// prefetched updates the entry of the request after a successful prefetch.
+func (t *UsageTracker) prefetched(req segfetcher.Request, segs segfetcher.Segments) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	u, ok := t.entries[requestKey{src: req.Src, dst: req.Dst}]
+	if !ok {
+		return
+	}
+	u.uses = 0
+	if expiry := minExpiry(segs); !expiry.IsZero() {
+		u.expiry = expiry
+	}
+}
+
+type requestKey struct {
+	src addr.IA
+	dst addr.IA
+}
+
+type usage struct {
+	uses         int
+	lastUsed     time.Time
+	lastPrefetch time.Time
+	expiry       time.Time
+}
+
+var _ periodic.Task = (*Prefetcher)(nil)
+
+// Prefetcher re-fetches the segments of frequently used requests before they
+// expire. The segments are fetched even if the next query time in the path
+// database has not yet passed. This keeps popular destinations warm and avoids lookup latency
+// spikes when the cached segments expire.
+type Prefetcher struct {
+	Fetcher SegFetcher
+	Tracker *UsageTracker
+	// MinUses is the minimum number of times a request must have been served
+	// since the last prefetch to be prefetched.
+	MinUses int
+	// LeadTime specifies how long before the expiry of the earliest segment
+	// the request is prefetched.
+	LeadTime time.Duration
+	// MaxIdle is the time after which a request that was not served anymore
+	// is no longer tracked. If zero, DefaultPrefetchMaxIdle is used.
+	MaxIdle time.Duration
+}
+
+func (p *Prefetcher) Name() string {
+	return "segreq.Prefetcher"
+}
+
+func (p *Prefetcher) Run(ctx context.Context) {
+	logger := log.FromCtx(ctx)
+	maxIdle := p.MaxIdle
+	if maxIdle == 0 {
+		maxIdle = DefaultPrefetchMaxIdle
+	}
+	now := time.Now()
+	for _, req := range p.Tracker.expiring(now, p.MinUses, p.LeadTime, maxIdle) {
+		segs, err := p.Fetcher.FetchSegs(WithRefresh(ctx), req)
+		if err != nil {
+			logger.Warn("[segreq.Prefetcher] Failed to prefetch segments",
+				"src", req.Src, "dst", req.Dst, "err", err)
+			continue
+		}
+		p.Tracker.prefetched(req, segs)
+		logger.Debug("[segreq.Prefetcher] Prefetched segments",
+			"src", req.Src, "dst", req.Dst)
+	}
+}
+
+// minExpiry returns the earliest expiry of all the segments. It returns the
+// zero time if there are no segments.
+func minExpiry(segs segfetcher.Segments) time.Time {
+	var min time.Time
+	for _, list := range []seg.Segments{segs.Up, segs.Core, segs.Down} {
+		for _, s := range list {
+			if exp := s.MinExpiry(); min.IsZero() || exp.Before(min) {
+				min = exp
+			}
+		}
+	}
+	return min
+}
@@ -0,0 +1,123 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segreq_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/infra/modules/segfetcher"
+	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/lib/util"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/path_srv/internal/segreq"
+	"github.com/scionproto/scion/go/path_srv/internal/segreq/mock_segreq"
+)
+
+func TestPrefetcherRun(t *testing.T) {
+	req := segfetcher.Request{
+		Src: xtest.MustParseIA("1-ff00:0:111"),
+		Dst: xtest.MustParseIA("2-ff00:0:222"),
+	}
+	tests := map[string]struct {
+		Uses     int
+		Expiry   time.Duration
+		FetchErr error
+		Fetches  int
+	}{
+		"not used often enough": {
+			Uses:   1,
+			Expiry: time.Minute,
+		},
+		"not expiring soon": {
+			Uses:   3,
+			Expiry: time.Hour,
+		},
+		"popular and expiring": {
+			Uses:    2,
+			Expiry:  time.Minute,
+			Fetches: 1,
+		},
+		"fetch error": {
+			Uses:     2,
+			Expiry:   time.Minute,
+			FetchErr: errors.New("test err"),
+			Fetches:  1,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			fetcher := mock_segreq.NewMockSegFetcher(ctrl)
+			tracker := segreq.NewUsageTracker()
+			p := &segreq.Prefetcher{
+				Fetcher:  fetcher,
+				Tracker:  tracker,
+				MinUses:  2,
+				LeadTime: 5 * time.Minute,
+			}
+			served := segfetcher.Segments{
+				Up: seg.Segments{newExpiringSeg(t, time.Now().Add(test.Expiry))},
+			}
+			for i := 0; i < test.Uses; i++ {
+				tracker.Record(req, served)
+			}
+			fresh := segfetcher.Segments{
+				Up: seg.Segments{newExpiringSeg(t, time.Now().Add(time.Hour))},
+			}
+			fetcher.EXPECT().FetchSegs(gomock.Any(), req).
+				Return(fresh, test.FetchErr).Times(test.Fetches)
+			p.Run(context.Background())
+			// A second run must not prefetch again: either the request was
+			// refreshed, or it is rate limited after the failed attempt.
+			p.Run(context.Background())
+		})
+	}
+}
+
+func TestUsageTrackerIgnoresEmptyReplies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	tracker := segreq.NewUsageTracker()
+	p := &segreq.Prefetcher{
+		Fetcher:  mock_segreq.NewMockSegFetcher(ctrl),
+		Tracker:  tracker,
+		MinUses:  1,
+		LeadTime: time.Hour,
+	}
+	req := segfetcher.Request{
+		Src: xtest.MustParseIA("1-ff00:0:111"),
+		Dst: xtest.MustParseIA("2-ff00:0:222"),
+	}
+	tracker.Record(req, segfetcher.Segments{})
+	p.Run(context.Background())
+}
+
+// newExpiringSeg creates a segment without AS entries that expires at the
+// given time.
+func newExpiringSeg(t *testing.T, expiry time.Time) *seg.PathSegment {
+	t.Helper()
+	ts := expiry.Add(-spath.MaxTTL * time.Second)
+	s, err := seg.NewSeg(&spath.InfoField{ISD: 1, TsInt: util.TimeToSecs(ts)})
+	require.NoError(t, err)
+	return s
+}
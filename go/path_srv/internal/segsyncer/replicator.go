@@ -0,0 +1,142 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segsyncer
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/infra"
+	"github.com/scionproto/scion/go/lib/infra/messenger"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/pathdb"
+	"github.com/scionproto/scion/go/lib/pathdb/query"
+	"github.com/scionproto/scion/go/lib/periodic"
+	"github.com/scionproto/scion/go/lib/revcache"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/topology"
+	"github.com/scionproto/scion/go/proto"
+)
+
+var _ periodic.Task = (*Replicator)(nil)
+
+// Replicator replicates the segments registered at the local path server to
+// the other path server instances of the local AS. This ensures that all
+// instances of a core AS return consistent results to lookups, regardless of
+// which instance received the registration.
+//
+// The replicator keeps track of the latest update sent to each sibling, so
+// that only segments which changed since the last successful replication are
+// sent.
+type Replicator struct {
+	PathDB   pathdb.PathDB
+	RevCache revcache.RevCache
+	Msger    infra.Messenger
+	IA       addr.IA
+	// LocalID is the ID of the local path server instance in the topology.
+	LocalID      string
+	TopoProvider topology.Provider
+
+	latestUpdates map[string]time.Time
+}
+
+func (r *Replicator) Name() string {
+	return "segsyncer.Replicator"
+}
+
+func (r *Replicator) Run(ctx context.Context) {
+	logger := log.FromCtx(ctx)
+	if r.latestUpdates == nil {
+		r.latestUpdates = make(map[string]time.Time)
+	}
+	siblings := r.siblings()
+	// Forget siblings that are no longer in the topology.
+	for id := range r.latestUpdates {
+		if _, ok := siblings[id]; !ok {
+			delete(r.latestUpdates, id)
+		}
+	}
+	for id, dst := range siblings {
+		cnt, err := r.replicate(ctx, id, dst)
+		if err != nil {
+			logger.Error("[segsyncer.Replicator] Failed to replicate segments",
+				"sibling", id, "err", err)
+			continue
+		}
+		if cnt > 0 {
+			logger.Debug("[segsyncer.Replicator] Replicated segments",
+				"sibling", id, "cnt", cnt)
+		}
+	}
+}
+
+// siblings returns the addresses of all path server instances of the local
+// AS, except for the local instance.
+func (r *Replicator) siblings() map[string]*snet.Addr {
+	topo := r.TopoProvider.Get()
+	siblings := make(map[string]*snet.Addr)
+	for id, topoAddr := range topo.PS {
+		if id == r.LocalID {
+			continue
+		}
+		pub := topoAddr.PublicAddr(topoAddr.Overlay)
+		if pub == nil {
+			continue
+		}
+		siblings[id] = &snet.Addr{IA: r.IA, Host: pub}
+	}
+	return siblings
+}
+
+func (r *Replicator) replicate(ctx context.Context, id string, dst *snet.Addr) (int, error) {
+	q := &query.Params{
+		SegTypes: []proto.PathSegType{proto.PathSegType_down, proto.PathSegType_core},
+	}
+	if latest, ok := r.latestUpdates[id]; ok {
+		q.MinLastUpdate = &latest
+	}
+	res, err := r.PathDB.Get(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+	// Send the oldest segments first, so that on failure the next run
+	// continues with the first segment that was not sent.
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].LastUpdate.Before(res[j].LastUpdate)
+	})
+	sent := 0
+	for _, qr := range res {
+		revs, err := revcache.RelevantRevInfos(ctx, r.RevCache, []*seg.PathSegment{qr.Seg})
+		if err != nil {
+			return sent, err
+		}
+		msg := &path_mgmt.SegSync{
+			SegRecs: &path_mgmt.SegRecs{
+				Recs:      []*seg.Meta{seg.NewMeta(qr.Seg, qr.Type)},
+				SRevInfos: revs,
+			},
+		}
+		if err := r.Msger.SendSegSync(ctx, msg, dst, messenger.NextId()); err != nil {
+			return sent, err
+		}
+		r.latestUpdates[id] = qr.LastUpdate
+		sent++
+	}
+	return sent, nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segsyncer
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/infra/mock_infra"
+	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/pathdb/mock_pathdb"
+	"github.com/scionproto/scion/go/lib/pathdb/query"
+	"github.com/scionproto/scion/go/lib/revcache/mock_revcache"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/lib/topology"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/proto"
+)
+
+func TestReplicatorRun(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	topo := topology.NewTopo()
+	topo.PS["ps-1"] = psTopoAddr(net.IPv4(127, 0, 0, 1))
+	topo.PS["ps-2"] = psTopoAddr(net.IPv4(127, 0, 0, 2))
+	segs := query.Results{
+		{
+			Seg:        newTestSeg(t, 2),
+			LastUpdate: time.Unix(20, 0),
+			Type:       proto.PathSegType_core,
+		},
+		{
+			Seg:        newTestSeg(t, 1),
+			LastUpdate: time.Unix(10, 0),
+			Type:       proto.PathSegType_down,
+		},
+	}
+
+	t.Run("sends changed segments to siblings only", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		pathDB := mock_pathdb.NewMockPathDB(ctrl)
+		revCache := mock_revcache.NewMockRevCache(ctrl)
+		msger := mock_infra.NewMockMessenger(ctrl)
+		r := &Replicator{
+			PathDB:       pathDB,
+			RevCache:     revCache,
+			Msger:        msger,
+			IA:           ia,
+			LocalID:      "ps-1",
+			TopoProvider: &xtest.TestTopoProvider{Topo: topo},
+		}
+		revCache.EXPECT().Get(gomock.Any(), gomock.Any()).AnyTimes()
+		pathDB.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, q *query.Params) (query.Results, error) {
+				assert.Nil(t, q.MinLastUpdate)
+				assert.ElementsMatch(t, q.SegTypes,
+					[]proto.PathSegType{proto.PathSegType_down, proto.PathSegType_core})
+				return append(query.Results{}, segs...), nil
+			},
+		)
+		var sent []proto.PathSegType
+		msger.EXPECT().SendSegSync(gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any()).Times(2).DoAndReturn(
+			func(_ context.Context, msg *path_mgmt.SegSync, a net.Addr, _ uint64) error {
+				dst, ok := a.(*snet.Addr)
+				require.True(t, ok)
+				assert.Equal(t, ia, dst.IA)
+				assert.True(t, net.IPv4(127, 0, 0, 2).Equal(dst.Host.L3.IP()))
+				require.Len(t, msg.Recs, 1)
+				sent = append(sent, msg.Recs[0].Type)
+				return nil
+			},
+		)
+		r.Run(context.Background())
+		assert.Equal(t, []proto.PathSegType{proto.PathSegType_down, proto.PathSegType_core},
+			sent)
+
+		pathDB.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, q *query.Params) (query.Results, error) {
+				require.NotNil(t, q.MinLastUpdate)
+				assert.Equal(t, time.Unix(20, 0), *q.MinLastUpdate)
+				return nil, nil
+			},
+		)
+		r.Run(context.Background())
+	})
+	t.Run("resumes after failed send", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		pathDB := mock_pathdb.NewMockPathDB(ctrl)
+		revCache := mock_revcache.NewMockRevCache(ctrl)
+		msger := mock_infra.NewMockMessenger(ctrl)
+		r := &Replicator{
+			PathDB:       pathDB,
+			RevCache:     revCache,
+			Msger:        msger,
+			IA:           ia,
+			LocalID:      "ps-1",
+			TopoProvider: &xtest.TestTopoProvider{Topo: topo},
+		}
+		revCache.EXPECT().Get(gomock.Any(), gomock.Any()).AnyTimes()
+		pathDB.EXPECT().Get(gomock.Any(), gomock.Any()).Return(
+			append(query.Results{}, segs...), nil)
+		gomock.InOrder(
+			msger.EXPECT().SendSegSync(gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any()),
+			msger.EXPECT().SendSegSync(gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any()).Return(serrors.New("test error")),
+		)
+		r.Run(context.Background())
+
+		pathDB.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, q *query.Params) (query.Results, error) {
+				require.NotNil(t, q.MinLastUpdate)
+				assert.Equal(t, time.Unix(10, 0), *q.MinLastUpdate)
+				return nil, nil
+			},
+		)
+		r.Run(context.Background())
+	})
+	t.Run("no siblings", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		single := topology.NewTopo()
+		single.PS["ps-1"] = psTopoAddr(net.IPv4(127, 0, 0, 1))
+		r := &Replicator{
+			PathDB:       mock_pathdb.NewMockPathDB(ctrl),
+			RevCache:     mock_revcache.NewMockRevCache(ctrl),
+			Msger:        mock_infra.NewMockMessenger(ctrl),
+			IA:           ia,
+			LocalID:      "ps-1",
+			TopoProvider: &xtest.TestTopoProvider{Topo: single},
+		}
+		r.Run(context.Background())
+	})
+}
+
+func psTopoAddr(ip net.IP) topology.TopoAddr {
+	a := topology.TestTopoAddr(
+		&addr.AppAddr{L3: addr.HostFromIP(ip), L4: addr.NewL4UDPInfo(30252)}, nil, nil, nil)
+	a.Overlay = overlay.UDPIPv4
+	return a
+}
+
+func newTestSeg(t *testing.T, ts uint32) *seg.PathSegment {
+	t.Helper()
+	s, err := seg.NewSeg(&spath.InfoField{ISD: 1, TsInt: ts})
+	require.NoError(t, err)
+	return s
+}
@@ -15,6 +15,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	_ "net/http/pprof"
@@ -36,6 +37,8 @@ import (
 	"github.com/scionproto/scion/go/lib/infra/messenger"
 	"github.com/scionproto/scion/go/lib/infra/modules/idiscovery"
 	"github.com/scionproto/scion/go/lib/infra/modules/itopo"
+	"github.com/scionproto/scion/go/lib/infra/modules/segfetcher"
+	"github.com/scionproto/scion/go/lib/infra/modules/segverifier"
 	"github.com/scionproto/scion/go/lib/infra/modules/trust"
 	"github.com/scionproto/scion/go/lib/infra/modules/trust/trustdb"
 	"github.com/scionproto/scion/go/lib/log"
@@ -44,7 +47,7 @@ import (
 	"github.com/scionproto/scion/go/lib/periodic"
 	"github.com/scionproto/scion/go/lib/prom"
 	"github.com/scionproto/scion/go/lib/revcache"
-	"github.com/scionproto/scion/go/lib/topology"
+	"github.com/scionproto/scion/go/lib/sock/reliable"
 	"github.com/scionproto/scion/go/path_srv/internal/config"
 	"github.com/scionproto/scion/go/path_srv/internal/cryptosyncer"
 	"github.com/scionproto/scion/go/path_srv/internal/handlers"
@@ -131,13 +134,16 @@ func realMain() int {
 		SVC:                   addr.SvcPS,
 		ReconnectToDispatcher: cfg.General.ReconnectToDispatcher,
 		QUIC: infraenv.QUIC{
-			Address:  cfg.QUIC.Address,
-			CertFile: cfg.QUIC.CertFile,
-			KeyFile:  cfg.QUIC.KeyFile,
+			Address:      cfg.QUIC.Address,
+			CertFile:     cfg.QUIC.CertFile,
+			KeyFile:      cfg.QUIC.KeyFile,
+			ClientCAFile: cfg.QUIC.ClientCAFile,
+			AuthTypes:    cfg.QUIC.AuthTypes,
 		},
 		SVCResolutionFraction: cfg.QUIC.ResolutionFraction,
 		TrustStore:            trustStore,
 		SVCRouter:             messenger.NewSVCRouter(itopo.Provider()),
+		CircuitBreaker:        infraenv.BreakerConfig(cfg.CircuitBreaker),
 	}
 	msger, err := nc.Messenger()
 	if err != nil {
@@ -145,10 +151,11 @@ func realMain() int {
 		return 1
 	}
 	defer msger.CloseServer()
-	msger.AddHandler(infra.ChainRequest, trustStore.NewChainReqHandler(false))
+	msger.AddHandler(infra.ChainRequest,
+		handlers.WithRequestID(trustStore.NewChainReqHandler(false)))
 	// TODO(lukedirtwalker): with the new CP-PKI design the PS should no longer need to handle TRC
 	// and cert requests.
-	msger.AddHandler(infra.TRCRequest, trustStore.NewTRCReqHandler(false))
+	msger.AddHandler(infra.TRCRequest, handlers.WithRequestID(trustStore.NewTRCReqHandler(false)))
 	args := handlers.HandlerArgs{
 		PathDB:          pathDB,
 		RevCache:        revCache,
@@ -158,16 +165,38 @@ func realMain() int {
 		IA:              topo.ISD_AS,
 		TopoProvider:    itopo.Provider(),
 		SegRequestAPI:   msger,
+		KeepRevoked:     cfg.PS.KeepRevoked,
+		VerificationCache: segverifier.NewCache(segverifier.DefaultCacheSize,
+			segverifier.DefaultCacheTTL),
 	}
 	core := topo.Core
-	msger.AddHandler(infra.SegRequest, segreq.NewHandler(args))
-	msger.AddHandler(infra.SegReg, handlers.NewSegRegHandler(args))
-	msger.AddHandler(infra.IfStateInfos, handlers.NewIfStateInfoHandler(args))
-	if cfg.PS.SegSync && core {
-		// Old down segment sync mechanism
-		msger.AddHandler(infra.SegSync, handlers.NewSyncHandler(args))
+	var usageTracker *segreq.UsageTracker
+	if cfg.PS.Prefetch {
+		usageTracker = segreq.NewUsageTracker()
 	}
-	msger.AddHandler(infra.SignedRev, handlers.NewRevocHandler(args))
+	segFetcher := segreq.NewFetcher(args)
+	msger.AddHandler(infra.SegRequest,
+		handlers.WithRequestID(segreq.NewHandler(args, segFetcher, usageTracker)))
+	msger.AddHandler(infra.SegReg, handlers.WithRequestID(handlers.NewSegRegHandler(args)))
+	msger.AddHandler(infra.IfStateInfos,
+		handlers.WithRequestID(handlers.NewIfStateInfoHandler(args)))
+	if (cfg.PS.SegSync || cfg.PS.Replication) && core {
+		// Old down segment sync mechanism, also used for the replication
+		// between the path servers of the local AS.
+		msger.AddHandler(infra.SegSync, handlers.WithRequestID(handlers.NewSyncHandler(args)))
+	}
+	msger.AddHandler(infra.SignedRev, handlers.WithRequestID(handlers.NewRevocHandler(args)))
+	env.AddHealthCheck("pathdb", func(ctx context.Context) error {
+		return pathdb.Ping(ctx, pathDB)
+	})
+	env.AddHealthCheck("trustdb", func(ctx context.Context) error {
+		return trustdb.Ping(ctx, trustDB)
+	})
+	env.AddReadinessCheck("topology", env.TopologyCheck)
+	env.AddReadinessCheck("dispatcher", env.UnixSocketCheck(reliable.DefaultDispPath))
+	env.AddReadinessCheck("messenger", func(ctx context.Context) error {
+		return messenger.CheckServing(ctx, msger)
+	})
 	cfg.Metrics.StartPrometheus()
 	// Start handling requests/messages
 	go func() {
@@ -182,9 +211,11 @@ func realMain() int {
 	}
 	defer discoRunners.Kill()
 	tasks = &periodicTasks{
-		args:    args,
-		msger:   msger,
-		trustDB: trustDB,
+		args:         args,
+		msger:        msger,
+		trustDB:      trustDB,
+		usageTracker: usageTracker,
+		segFetcher:   segFetcher,
 	}
 	if err := tasks.Start(); err != nil {
 		log.Crit("Failed to start periodic tasks", "err", err)
@@ -204,12 +235,16 @@ type periodicTasks struct {
 	args          handlers.HandlerArgs
 	msger         infra.Messenger
 	trustDB       trustdb.TrustDB
+	usageTracker  *segreq.UsageTracker
+	segFetcher    *segfetcher.Fetcher
 	mtx           sync.Mutex
 	running       bool
 	segSyncers    []*periodic.Runner
+	replicator    *periodic.Runner
 	pathDBCleaner *periodic.Runner
 	cryptosyncer  *periodic.Runner
 	rcCleaner     *periodic.Runner
+	prefetcher    *periodic.Runner
 }
 
 func (t *periodicTasks) Start() error {
@@ -227,15 +262,38 @@ func (t *periodicTasks) Start() error {
 			return common.NewBasicError("Unable to start seg syncer", err)
 		}
 	}
-	t.pathDBCleaner = periodic.StartPeriodicTask(pathdb.NewCleaner(t.args.PathDB),
-		periodic.NewTicker(300*time.Second), 295*time.Second)
-	t.cryptosyncer = periodic.StartPeriodicTask(&cryptosyncer.Syncer{
+	if cfg.PS.Replication && itopo.Get().Core {
+		t.replicator = periodic.StartPeriodicTask(&segsyncer.Replicator{
+			PathDB:       t.args.PathDB,
+			RevCache:     t.args.RevCache,
+			Msger:        t.msger,
+			IA:           t.args.IA,
+			LocalID:      cfg.General.ID,
+			TopoProvider: t.args.TopoProvider,
+		}, periodic.NewTicker(cfg.PS.ReplicationInterval.Duration),
+			cfg.PS.ReplicationInterval.Duration)
+	}
+	t.pathDBCleaner = periodic.StartPeriodicTaskWithOptions(pathdb.NewCleaner(t.args.PathDB),
+		periodic.NewTicker(300*time.Second), 295*time.Second,
+		periodic.Options{Jitter: 30 * time.Second})
+	t.cryptosyncer = periodic.StartPeriodicTaskWithOptions(&cryptosyncer.Syncer{
 		DB:    t.trustDB,
 		Msger: t.msger,
 		IA:    t.args.IA,
-	}, periodic.NewTicker(cfg.PS.CryptoSyncInterval.Duration), cfg.PS.CryptoSyncInterval.Duration)
-	t.rcCleaner = periodic.StartPeriodicTask(revcache.NewCleaner(t.args.RevCache),
-		periodic.NewTicker(10*time.Second), 10*time.Second)
+	}, periodic.NewTicker(cfg.PS.CryptoSyncInterval.Duration), cfg.PS.CryptoSyncInterval.Duration,
+		periodic.Options{Jitter: cfg.PS.CryptoSyncInterval.Duration / 10})
+	t.rcCleaner = periodic.StartPeriodicTaskWithOptions(revcache.NewCleaner(t.args.RevCache),
+		periodic.NewTicker(10*time.Second), 10*time.Second,
+		periodic.Options{Jitter: time.Second, MinBackoff: 10 * time.Second,
+			MaxBackoff: 5 * time.Minute})
+	if t.usageTracker != nil {
+		t.prefetcher = periodic.StartPeriodicTask(&segreq.Prefetcher{
+			Fetcher:  t.segFetcher,
+			Tracker:  t.usageTracker,
+			MinUses:  cfg.PS.PrefetchMinRequests,
+			LeadTime: cfg.PS.PrefetchLeadTime.Duration,
+		}, periodic.NewTicker(10*time.Second), time.Minute)
+	}
 	return nil
 }
 
@@ -250,9 +308,17 @@ func (t *periodicTasks) Kill() {
 		syncer := t.segSyncers[i]
 		syncer.Kill()
 	}
+	if t.replicator != nil {
+		t.replicator.Kill()
+		t.replicator = nil
+	}
 	t.pathDBCleaner.Kill()
 	t.cryptosyncer.Kill()
 	t.rcCleaner.Kill()
+	if t.prefetcher != nil {
+		t.prefetcher.Kill()
+		t.prefetcher = nil
+	}
 	t.running = false
 }
 
@@ -273,7 +339,8 @@ func setup() error {
 		return common.NewBasicError("Unable to validate config", err)
 	}
 	itopo.Init(cfg.General.ID, proto.ServiceType_ps, itopo.Callbacks{})
-	topo, err := topology.LoadFromFile(cfg.General.Topology)
+	topo, err := idiscovery.LoadTopology(cfg.General.Topology, cfg.Discovery,
+		discovery.Full, nil)
 	if err != nil {
 		return common.NewBasicError("Unable to load topology", err)
 	}
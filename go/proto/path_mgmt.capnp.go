@@ -69,6 +69,14 @@ func (s SegReq_flags) SetCacheOnly(v bool) {
 	s.Struct.SetBit(129, v)
 }
 
+func (s SegReq_flags) RevFilter() uint8 {
+	return s.Struct.Uint8(17)
+}
+
+func (s SegReq_flags) SetRevFilter(v uint8) {
+	s.Struct.SetUint8(17, v)
+}
+
 // SegReq_List is a list of SegReq.
 type SegReq_List struct{ capnp.List }
 
@@ -212,12 +220,12 @@ type SegReply struct{ capnp.Struct }
 const SegReply_TypeID = 0x9359e1b2db37dbbb
 
 func NewSegReply(s *capnp.Segment) (SegReply, error) {
-	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2})
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2})
 	return SegReply{st}, err
 }
 
 func NewRootSegReply(s *capnp.Segment) (SegReply, error) {
-	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2})
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2})
 	return SegReply{st}, err
 }
 
@@ -281,12 +289,36 @@ func (s SegReply) NewRecs() (SegRecs, error) {
 	return ss, err
 }
 
+func (s SegReply) Chunk() uint16 {
+	return s.Struct.Uint16(0)
+}
+
+func (s SegReply) SetChunk(v uint16) {
+	s.Struct.SetUint16(0, v)
+}
+
+func (s SegReply) Chunks() uint16 {
+	return s.Struct.Uint16(2)
+}
+
+func (s SegReply) SetChunks(v uint16) {
+	s.Struct.SetUint16(2, v)
+}
+
+func (s SegReply) Truncated() bool {
+	return s.Struct.Bit(32)
+}
+
+func (s SegReply) SetTruncated(v bool) {
+	s.Struct.SetBit(32, v)
+}
+
 // SegReply_List is a list of SegReply.
 type SegReply_List struct{ capnp.List }
 
 // NewSegReply creates a new list of SegReply.
 func NewSegReply_List(s *capnp.Segment, sz int32) (SegReply_List, error) {
-	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2}, sz)
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2}, sz)
 	return SegReply_List{l}, err
 }
 
@@ -1982,107 +2014,111 @@ func (p PathMgmt_Promise) HpCfgReply() HPCfgReply_Promise {
 	return HPCfgReply_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
 }
 
-const schema_8fcd13516850d142 = "x\xda|V\x7flTY\x15>\xe7\xde\x99\xce\xefv" +
-	"\xc6\xfb\x8c\xdd\xb8\xb1\xbb\x1bL\xb6H7l\x81\xecn" +
-	"\x83\xf6\x07%\xcb,\x8b\xf6\xf5%\xeb\xee\xc6U\x87\x99" +
-	"7\xd3\xa1\xd3\xe9t\xde@S\x89\xa9\x9a\xa81\x8a\x04" +
-	"D\x03F\x02\x18\x08)\x88\x86&$\xa4\xa4(\x1a\x10" +
-	"\x1b!\x14\xc1\x10Pc\x11#!\x10\x91\xdf\x14\xea\xdb" +
-	"\x9c;\xd3\xf7\xa6o\xa6\xfdo\xe6~\xdf;\xe7\xbb\xe7" +
-	"\x9e\xfb\x9d\xbb4\xe0jc\xaf\xbb\xbf\xef\x06P\xdb\xdc" +
-	"5\xe6\x8b'[7\x0f\xdcT\xb7\x82\x1aF4;&" +
-	"\xbbzTqn+\xb8\xd1\x03 F\xd8\x848\xc6\xe8" +
-	"\xd7Q6\x08h\x8e\x7fQ\x99\x09\x1c\\\xfb\x13\x88\x84" +
-	"\xcb\xb9\x92\xf12\xff\xbdh\xe4\xf4\xeb\xb3\x9c\xb8'\xae" +
-	"\xbdqmt\xea\x83\xaa\xdc\x03|B\x1c\x95\xdc#\x92" +
-	"\xfb\x99\xef\xb9\xbe\xc9V\xe0n\x12\xc1m\xb2\x8b\x18>" +
-	"\xd7\x98\x88\xc8_!\xd7o\x00\xcd\x89\x0b?\x0fvL" +
-	"/\xd9\xe7\x10\xbc\x1a=\x11\x00\xf1\x03\xd7\x84\xf8)\xb1" +
-	"\x97ms\xfd\x96\x03\x9aS\xd7\xbf2z\xf6g\xbd\x07" +
-	"\x1c2d\xf2\x11\xefEq\xcc+\xb7\xe7\xa5\xd0?<" +
-	"\xb3\xfc\x97\xeb\x83G\x0e:\xb8\xb2\x14\x1f\xf8\xee\x09\xdd" +
-	"G\xbfb\xbeV@\xf3\xaf?N\xb6o\x9fy|\xa8" +
-	"\x1aw\x9b\xef\xaa\xd8-\xb9\xbb$w\xed\xaf\xb7<\xfa" +
-	"\xc3\xd0\xd3\xc3\xd5J\xf1'\xdf\x98\x98\x94\xdcs>*" +
-	"\xc5\x8f\x0e]\x0fM=\xda<Z-n\xa3\xff\xaaX" +
-	"\xe1\xa7_\xaf\xfb)n\xfd\xea\x1b_\x10\x7f|y\x8c" +
-	"J\xc1\x1ce\x8b\xf9/\x8a>\xc9M\xfb)n\x9bz" +
-	"\xe6\xc9\xad\xf3\xef\x8dW\x89\xbb\xec\xb4\xff\x13(.K" +
-	"\xf2\xa4\x0c\xbc\xed\xc2\xbe\xff\xf4No9\xeb\xa8\xb1\xac" +
-	"+\x06\x18\x8aP@\x1eM\x80\xc8_=\xfc\xb9\x13\x9f" +
-	"<\xb2\xf6b\x15\xb2h\x0aL\x88\xb7$w\x85\xe4n" +
-	"\xfat\xe7\xb3u\x8d;\xfe\x01\xea\xa7\x90\xdb\xc7\xaex" +
-	"\x90J\x1c\xb8\x05(>\x0a\x90\xdc\xf8\x0b\xdf8\xee\x0f" +
-	"{\xee;\xdbRF\x1d\x0f\x8c\x8a\xd3\x14u\xd9\xa9\xc0" +
-	"\x97\x11\xd0\xcc\xc5\x0a=_\xebK\xf5\xb1\xc2k\xf1X" +
-	".\x9bkY\xd3\xa5\xe9\xa9n}\x00\xa0\x0bQ\xf5r" +
-	"\x17\x80\x0b\x01\"\x8d\xcd\x00\xea\"\x8e\xeaR\x86\x88\x0a" +
-	"\xd2Z\xd3;\x00\xea\x12\x8e\xea\x1a\x86\x0d\x09\xa3\x10m" +
-	"G\x1f0\xf4\x01\x9a\xa9|\xff\xc6\\4a\x00\x00\xd6" +
-	"\x02vq\xc4\xb0]u@Z\xacL.S\xc7\x0dg" +
-	"\xea\xc5\xa5\xd4m\x0c#\xb3\xb9?\xdf\x0d\xa0\xae\xe4\xa8" +
-	"\xbe\xcf\xb0.\xaf\xc7\x0d;M\xdd\x98\x7f\xe5\xe6\xf7v" +
-	"\xee\x99Mct\xeb\x9b\xa2\xd9d?`\x19\xa9\xfb_" +
-	"\xd3o|\xf7\xed\xe6=\x0bk\xc9e\x86*\x0a\xf1\x8a" +
-	"]\x08KM\x13I|\x95\xa3\xba\x9c\xa1'\xaf\x0f`" +
-	"\xd8>\"@\x0cCIc\xd86\x82\xe2\xb2\x95\x17g" +
-	"\xf3\xb6\x16\xebOI\x83V\xd2\xd5T\xfd6\x8e\xea\xbb" +
-	"eI\xa3\xb4\xd8\xc9Q\xedb\x88\x0c\xcbz$\xb2\xae" +
-	"\x19X\x83\x91\x8f\xdb'2\xf7|\x1a\x92\x99X\xca\xa8" +
-	"\xdctW\xac\xd0\xb3.\xd5W(n\xba\x93\xbb\x82\xa6" +
-	"I\x02\xc49l\x06\xd0\xce\"G\xed\x122\x0c\xe1\xff" +
-	"M)BLb\x0b\x80\xf6g\x02\xae\x10\xc0fL\x05" +
-	"\x19\x80\xb8\x8c\xef\x00h\x97\x08\xf8'\x01\xfc\xb9\xa9 " +
-	"\x07\x10\x7f\x97_\\!\xe0\x06\x01\xaeg\xa6\x82.\x00" +
-	"1\x85\x1d\x00\xda\xdf\x08\xb8I\x80{\xdaT\xd0\x0d " +
-	"\xfe-C\xdd \xe0.\x015OM\x05k\x00\xc4\x1d" +
-	"\xfc\x10@\xbbM\xc0\x13\x02<OLE\xde\xf7\x87\xb8" +
-	"\x01@{\x80\x1c\xbb\x19\xc3\x90\xf7\xb1\xa9\xa0\x17@\xcc" +
-	"\xe0w\x00\xb4\xe7\xf4\x81\x97\x00\xdf#SA\x1f\x80p" +
-	"\xb3\xed\x00\x9a\x97q\xd4\x14\x02\xfc\x0fM\x05\xfd\x00\"" +
-	"\xc2\xf2\x00Z\x98\x80\x17\x09\x08<0\x15\x0c\x00\x88\x17" +
-	"\x18\x85\xaa'`\x11\x01\xc1\xfb\xa6\x82A\xb2sFj" +
-	"_\"`\x09\x01\xa1{\xa6\x82!2!Fj_%" +
-	"`9\x01\xb5\xff3\x15\xac%O\x92_,%`%" +
-	"\x01uwM\x05\xeb\x00\xc4[\x12x\x93\x80N\x02\xc2" +
-	"\xff5\x15\x0c\x03\x88v\x19\xaa\x8d\x80w\x19\xc3\x86\x8d" +
-	"YC/@M\xab!{\xa7\xb2\xfbL\xc3\xeaf\xc0" +
-	"\xb0=a\x8ah\xf1\xb3Tew\x0e\x1bzJ\x1b\xca" +
-	"\xc6\xab\xf4\xadu\xa9d\xc0\xf2\xdbDh:\xa9\x15b" +
-	"\x05\xbd\x1b\xb8T3\x92\xfc\xc5\xa9\xde\xc1;7\x1dp" +
-	"\x14\xea\xb2\xc9~\xba\x15'\x03\xcf\x1f6\x9f\x7f\xe9/" +
-	"erW\xf5\xc4\xb2)\xd4\x8dh\x82\xdc\x08\xc3\xb6\xb5" +
-	"V\xe7\xe428\x84a\xdb\xad\x1d,h\xd0\x8dbi" +
-	"\xacYU\x19\xa7X\xa2*\xbb\xed\xc9\xcd\xba\"\xa1\xd6" +
-	"\xe4\x9f\x8b\xe6\x80gH\x825\xe0\xe6\xc2\xa9\xe2\xc7\xd6" +
-	"X\xb5\xd0UI;\xb45\x12\xe6\xa2\xb3\xa1\xad\x19\xe7" +
-	"p\x0f\xa7}\xc7\xd1p\x18HG5\x03Y\\2\x90" +
-	"\xaf3\x8c0&\xefm\xe4#\xf2\xb7\xf79\xaa\x09\x86" +
-	"\xc3%\x1bwx\xb7eg\xf3Y\xaeG\xcf\xe71\x08" +
-	"\x0c\x83\xf38\xab,x\xf1DH\xa7\xcb\xd2\x19j\x01" +
-	"P\xbd\x1c\xd5EL\xb6e4ae\x09\x01\xab\xee\xd4" +
-	"\xa5=\xe7xf\xc8\x11l\xb1\x1d\xcc!\xb8\xfc\x14j" +
-	"\xe7\xb3\xe1h\xc2\x98\x7f\x08\xda\xde\xdfb{\x7f\x83\x94" +
-	",\xa5\x86\x00[\x93\x1b3\x19\xfbo5\xe5\xc5\xd3]" +
-	"Xy<\x99*Sn\xcd\xf7\xf9\x06\xd7\x9a\xae\xb7\xe5" +
-	"\xa9a\xc2!\xbe\xa3\\\xbc\xab$\xbe\xc3\x16?\xdc?" +
-	"\x98\xd5\xf3\xed\xda\xec\x90\xb0N\xdf\x03\x0c=e\xa9\xb8" +
-	"\xf3$\xed\x1b\xe8\xd8\xc6+\xf66<\xe9D\xd9.\xac" +
-	"\x87\x9dc\x17\xf3\x84\x1e\x00G`\x9a\xfeA\x8ej=" +
-	"C3\x133\x0a\xabz\xf48`/z\x81\xa1w\x81" +
-	"R\x97\xde5e\x916\x94E\x8a\xcb\x8c\x09\x0d\xea\xd2" +
-	"\xd9\xb8>\x7f\xb0\xa2\x19\xbc\x96\xac\xa3)J%\x0e\x7f" +
-	"\xabxy\xe66\xc8\xb7\xe5\xc0\x8b4u\x97\x9eIo" +
-	"R\x83\xa4\xd7\xe7c\x88\xc0\x90\x9e_\xf1X\xbcG\xff" +
-	"R6\x038d\xadU\xb4b\x83\xd4N\xb2\xeb-\xd9" +
-	"\xbb\xe8\xdcvpT\xf7\xda\xcf\xb1\xdd\xb4\xb6\x93\xa3\xba" +
-	"\xbf\xec:\xef\xa37\xda^\x8e\xea\xaf\x18F8\x16\x15" +
-	"\x8d\x10s?G\xf5w\x0c#.&\xc7od\x9c\x16" +
-	"\x8fsT/1\x8c\xb8\xb9\x1c\xbd\x91\xc9\x0f\x01\xd4\x0b" +
-	"\x1c\xd5\xdb\x0b\xb9\xc1\xf0&=o\xa4\xfb\xb3V\xc5d" +
-	"'E\xb5N2\xb5R\xf7\x0c\x0f\xe6\xd3\x05=o\xf5" +
-	"\x80\xafx\x9d\x87\xf3z,Q\xb9l\xe6\xf5T\xda(" +
-	"\xe4\xd3\xc0u'\xf6q\x00\x00\x00\xff\xff4\x06O\xa2"
+const schema_8fcd13516850d142 = "x\xda}V}lSU\x14\xbf\xf7\xbev\xdd\xdan" +
+	"k}#\x8e\x88\x0e\x08$\x0c\x81\xc0\xc0\xa0\x04\xdd\x07" +
+	"CVa\xba\xae\x8ah\xf0\xa3\xb4\xaf]Y\xd7u\xef" +
+	"u\x9b\x13\xc9\xc0\x80Jd\xa2@\x0c\x1a\x09` \x04" +
+	"\x10\x0dKHpd(\x1a\x10\x17!\x80h\x88\xa8q" +
+	"\x88\x11\x09D\xbe?\x06\xb3\x9es_\xfb\xfa\xf6\xd6\xee" +
+	"\x8f\x97\xbc\xf7\xfb\x9dw\xce\xef\x9e{\xee9w\xb2\xcb" +
+	"T\xc6\xa6\x98\xdf6\x13\xe2.3g\xc5G\x1c(]" +
+	"\xd2t\xde\xbd\x86\xb8\x1d\x94\xc6+N\xd4\xd4\xb9\xc5\xa3" +
+	"k\x88\x99Z\x08\x11w\xb0\x1eq/\xc3\xb7=\xac\x95" +
+	"\xd0x\xf7\xd3\x05\xfd\xb6\xeds\xd7\x11\xa7Co\xcb-" +
+	"F\x09\xdf\x88\xc5\x02\xbe\x8d\x15\xd0v\xff\x99\xe9g:" +
+	"{_Xgt\xcc\x8d\xb7\x09=\xe2\x1e4\x9e\xba[" +
+	"(\xa2`\xfd\xd0[\xa6\xa5\xec\x11\xba\x11\xad\x85\x94\xb5" +
+	"\x09\x8d\x0f\x9a\xba\xc4\xef\xf9\xdb!\xd3\x17`\xdbs\xfc" +
+	"c{E\xdf\x84-\x06\xcf\xb3\xa9\xc5\x096\xe5\xe6\x1e" +
+	"\xb1\xda\x8c\xae]\xe6\xaf\x040\xef=\xbb\xb0\xf3\xc8\x87" +
+	"\xf5\xdb\x0c\xa2\xb9To\xceI\xb1!\x07\xdfB9\xe8" +
+	"\xfa\xdd\xc3\xd3>]d\xdf\xbd\xdd`\xcb\x93\xe1\xb4^" +
+	"\x15\x1f\xb4\xe2\xdbpk)\xd8\xfe\xfc^\xa0|m\xff" +
+	"\xad\x9d\xe9l]\xd6_\xc4\xe7\xb8\xad\x9b\xdb\xce\xfd\xbc" +
+	"\xe3\xe6\xb7mwv\xa5K\xdcrk\x97\xb8\x8a\xdb\xae" +
+	"\xb4b\xe2V\xef<\x9b\xdb{sIg:\xbf\xbd\xe0" +
+	"\xf7\x12\xb7\xfd\x87\xfb-\x9c}\xee\x09\xf1\xbbQ]\x98" +
+	"\x0afH\xdbp\xdbIq\xac\x8do\x8d\x0d\xfd\x96\xb9" +
+	"\x0f\xdf\xbepl~w\x1a\xbfS\x97\xda\xee\xa3b\x07" +
+	"7^eC\xc7\x1f\x1c\xdf\xf2w}_\xc7\x11C\x8e" +
+	"\xd1\xf1\xd4/m\x8c\x8a\x87\xb8\xf1An\xfc\xf2\xae\x87" +
+	"\xf7\x0f\xdb=\xf7d\x1ac\xf1/[\x8fx\x85\xdb^" +
+	"\xe2\xb6-\x0fT\xde\xad.^\xff;q\xdf\x0f\x1b\xad" +
+	"m{\x81\x85b\x8a\xed\x17\x08\x15\x87\xd9q+|\xc3" +
+	"_\xdfguX\xae\x19\xeb\x87{\xddk\xef\x14\xbb\xed" +
+	"\\\x8c\xfdy\xac\x9f\xa87V\xf7JC\xb0\x81\xc5&" +
+	"\xf9\xbc\xd1HtFU\x8dG\x0a\xd6JM\x84\xd4P" +
+	"\xea\xce\x16L\x84\x98 \x84\xb3\xb8\x04\x0a\x7f\x8c@\xdd" +
+	"\x93\x19\xa5\xb4\x80\"6\xf1)\xc0&\x00V\xc5h\x91" +
+	"_\x89\xb9\xcai\x0ea\xf0\xd0xPnl\x8e\xba\xfc" +
+	"\x0a!\x84\xe6\x11Z#P\xeaHe\x9dP\x04\x07\x07" +
+	"\xe7\xa1}\x8a1\xf4\xf8D\xe82F\x9d\xc9\xd8\x8f\xd7" +
+	"\x028\x13\xc0\x05\x8c\xe6\xcb\xf0S*L~\x97u\xe6" +
+	"\x92\xf9\x1b6%\xc3(\xb5R\x8b+\x12h$Tg" +
+	"T\xfbg\xdf\xf4\x95sJ6\x0d\xad%\x1anS\x13" +
+	"Q\xa0\xa9Y:\x1a\x02\xbf\x06\x81W\xe8\xd4,G\x89" +
+	"o\x00\xf8\x0ed\x87\x15P\x06\xd8J\xcc\xd82\xc0V" +
+	"\x83\xa1\x00\x86\x02\x80\xabf\x00\xb8\x02\xc0\xf7\x014\x8d" +
+	",\xa0\xe0\xd6\xd9\x81kY\x0d\xe0\x06F-\xb2\xd4\x04" +
+	"\xf2\xb4\x0d\x06y\x0e\x92X\xa1#\xd5HT\xb8\xc8W" +
+	"\xd7\x1c\xa9\x87J\x84\xff\x08-\xe5_J\xf23\x1e\x93" +
+	"\x9b#>oL\"\xd4\x0f\"A\x98n\x994\xb9\xcc" +
+	"Ru\xbbq\x8dvm\x8d\xb3Qz\x19\x08\x9a\xa7[" +
+	"\xa3\x0b\xc1J\x00kp\x8dTW\x92\xce\xea\x12\xc2\x8a" +
+	"\x14\xd9\x97*\x80\x81\xe5P\x14\x08{\x83\xca\xe0\x1c\xd7" +
+	"\x00P\x1dl\x88\xa99\xae\x14L\xf6x\x1c\x05\x88G" +
+	")\x04\xf3\x1c\xa1\x02\xf5\x9c\x82X\xb9\xf4\xbf8\x17!" +
+	"\x9e\xa0\x90@\xcf\x0fH\x9cF\x82\xf5\xc7y\xb6\xc5\x9f" +
+	"(\x14#\x18\x03\xf1\x07\x12\xc2\xbd8\xcf\xb8\xf8\x1b\xff" +
+	"\xe34\x12\xe7\x900\xdd\x8d\xf3\xac\x8b\xbd\xb4\x02\x88_" +
+	"\x918\x8f\x84\xb9\x0f\x083\x1e=\xee\xea\x1c\x12\x97\x91" +
+	"\xc8\xba\x03D\x16\x9eD\xfa\"\x10\x17\x91\xb8\x8d\x84\xe5" +
+	"6\x10x\xacn\xd0\xc5@\\\x07\xa2\x96\x01\x9e}\x0b" +
+	"\xf0l\xc0\xfb\xe9\x9b\x80\xdf\xc3\x1f\xb2\x91\xc8\xb9\x09D" +
+	"\x0e\x10f\xb6\x16\x88l\x06D\x01\x12\xd6\x1b@X\xf1" +
+	"$3\x19\x08\x07\x12#\x90\xb0]\x07\xc2\x86]\x89\xa1" +
+	"\xabB$\xc6 a\xbf\x06\x84\x1d\x9b\x14C\xb5#\x91" +
+	"\x98\x80D\xeeU r\x81(f\xa8v\x1c\x12\xd3\x90" +
+	"\xc8\xbb\x02D\x1e\x10S\xf8\x1f\x93\x91\x98\x89D\xfee" +
+	" \xf2\x81x\x8c\x13\x8f\"Q\x89\x84\xe3_ \x1c8" +
+	"\x1b\xb8\xab2$\xe6\x01Q\xd4\x1cQ\xa4\x18\xc9*U" +
+	"x\xed\x0c.\xd7\xb8\xa2\x1d\x1e\x02\xac6\xd2TV\xfd" +
+	"-8\xb8\x9c\xdb\x81\xf0\xb4E|\x83\x99\xd4\x19\xe6\x0e" +
+	"\xf5\x87\x17\xd9P\xc0\x13\x83B\xaf%\x02W\xb3#\xf0" +
+	"\xc9\xc1\xfa\xd6K\xe7\x0d\xb4\x8b\xe4\x83\x07<F\x07l" +
+	"\xf7n\x94\x1c\x1b\xf9\xa3N\xee\xac:o$H%\xc5" +
+	"\xe5\xc7\xe6\x076Z'Oo\x13\x0d\xd36\xb0\xd2\x86" +
+	"\x83\xc1\x8a\x14I\x8a\x9a\x1am4\x0e\xf6\xa3\xa6(\xcd" +
+	"j\xeb\xa2\xc9&\x8c\xacv\xd5\x18\xc8F\x89\x10F\x09" +
+	"\xda<\x1dH\x07\xd5\x9f\xb5)\xae\xb1\xb3\x02)\xd7\xda" +
+	"\x04\x1a\xc8&]k#5Ag\x9a\x16>\xaa\x18\x1a" +
+	"HE\xba\x062>\xd1@^\x05\x90%\xba\xe4K\xd8" +
+	"N\x17\x00\xe8g\xb4=15\x0c\xa3B\xeb\x7f\x99:" +
+	"\xbcE\x92e8\x0b\x0c\x9e\xf4\x8d\x9c'\\\xdd\x11\xd4" +
+	"i\xd2t\xe6b;\xce\x86\xe8c\x18/K\x98X\xc9" +
+	"(\xb9\xe0/o\x885G!E\x06g\xe3S\xce\x0c" +
+	"\x82\xf5\xbb\x90\x97\xa9\x0dC\xf0\xcc3W\xcb\xe2D\x94" +
+	"<\x0e\xc0ip\x10\xb9d.5\x17\xceU\xa09\x1c" +
+	"N}\xa6S\xae\xee\xee\xd0\xca}\x81\xa0N\xb9v\x9d" +
+	"\xc84'\xabj\xe6\xf0]\xa3~\x83\xf8\x0a\xbdxS" +
+	"B|EJ|{ckD\x92\xcb=\xc9!\xa1\xed" +
+	"~r~%C\x09\xc6\x9dL\x9d@\xc32F\xa7\x96" +
+	"a\x09\xf9u\xab\xd0\xee\x91\x86Udp\xddD\x0c\x8e" +
+	"q@\xdb\xc1q!\xa3\xf1\xb0W\x89\xcd\xaa\x93|\x84" +
+	"\xd6C\x93g\xf0dNu\xe2\x1a\xa5\xf3\xb4X\xe7\xc9" +
+	"\xc7#\xfa=$?\x14\xf1I\x99\x9d\xa9\xcd`R " +
+	"\x1f\xa7(\x9e2\xc72\xf5\xf0\x0c\x9c\xd3\xcb\xd5+\x86" +
+	"\x0b\xc5V\x01\xf8,\x1e3\xa7z\xc5p#\x08\xb9p" +
+	"/\xc4\xaa\x09-\x92\xbd\xda\x95\xc0\xe7\xf5\xd5I\xcfD" +
+	"\xc2\x04\x1aZ\x12\x93\xa5\x96'Ca\xbc:\xc80\xf9" +
+	"\x18<ij\xb6\x88/\x12\xd7W\xa8\xad\xef#\xdc\xe0" +
+	"\xf5\x10gs\xea\x9a\xb8\x11\xb1\x0d\x80m\xd5\x9d\xfb-" +
+	"xw\xdc\x0c\xe0g\xba\xdb\xd1\x0e\xb4\xdc\x0a\xe0\xd7x" +
+	";b\xaa\xf4n\x04\xf7\x01x\x0a@\xb3\xc0g\xb4\xf3" +
+	"\x04\x0c$\xf7q\x00/\x0e\xd56\xda[$Y\x095" +
+	"F\xb4\xd4\xf2\x92sy*\xb1\xfb%\xca\xac\xbdU\x0e" +
+	"\xc5\xc0,Y,9\xea\xb9o\x97%\xaf\x7f0\x0c\x99" +
+	"\x09\x86\x94\x98\x1c\x82Qc\xe4\xfe\x07\x92\x1dn`"
 
 func init() {
 	schemas.Register(schema_8fcd13516850d142,
@@ -13,23 +13,28 @@ type SCIONDMsg struct{ capnp.Struct }
 type SCIONDMsg_Which uint16
 
 const (
-	SCIONDMsg_Which_unset              SCIONDMsg_Which = 0
-	SCIONDMsg_Which_pathReq            SCIONDMsg_Which = 1
-	SCIONDMsg_Which_pathReply          SCIONDMsg_Which = 2
-	SCIONDMsg_Which_asInfoReq          SCIONDMsg_Which = 3
-	SCIONDMsg_Which_asInfoReply        SCIONDMsg_Which = 4
-	SCIONDMsg_Which_revNotification    SCIONDMsg_Which = 5
-	SCIONDMsg_Which_ifInfoRequest      SCIONDMsg_Which = 6
-	SCIONDMsg_Which_ifInfoReply        SCIONDMsg_Which = 7
-	SCIONDMsg_Which_serviceInfoRequest SCIONDMsg_Which = 8
-	SCIONDMsg_Which_serviceInfoReply   SCIONDMsg_Which = 9
-	SCIONDMsg_Which_revReply           SCIONDMsg_Which = 10
-	SCIONDMsg_Which_segTypeHopReq      SCIONDMsg_Which = 11
-	SCIONDMsg_Which_segTypeHopReply    SCIONDMsg_Which = 12
+	SCIONDMsg_Which_unset                  SCIONDMsg_Which = 0
+	SCIONDMsg_Which_pathReq                SCIONDMsg_Which = 1
+	SCIONDMsg_Which_pathReply              SCIONDMsg_Which = 2
+	SCIONDMsg_Which_asInfoReq              SCIONDMsg_Which = 3
+	SCIONDMsg_Which_asInfoReply            SCIONDMsg_Which = 4
+	SCIONDMsg_Which_revNotification        SCIONDMsg_Which = 5
+	SCIONDMsg_Which_ifInfoRequest          SCIONDMsg_Which = 6
+	SCIONDMsg_Which_ifInfoReply            SCIONDMsg_Which = 7
+	SCIONDMsg_Which_serviceInfoRequest     SCIONDMsg_Which = 8
+	SCIONDMsg_Which_serviceInfoReply       SCIONDMsg_Which = 9
+	SCIONDMsg_Which_revReply               SCIONDMsg_Which = 10
+	SCIONDMsg_Which_segTypeHopReq          SCIONDMsg_Which = 11
+	SCIONDMsg_Which_segTypeHopReply        SCIONDMsg_Which = 12
+	SCIONDMsg_Which_nextQueryReq           SCIONDMsg_Which = 13
+	SCIONDMsg_Which_nextQueryReply         SCIONDMsg_Which = 14
+	SCIONDMsg_Which_pathFeedbackReq        SCIONDMsg_Which = 15
+	SCIONDMsg_Which_pathFeedbackReply      SCIONDMsg_Which = 16
+	SCIONDMsg_Which_topoChangeNotification SCIONDMsg_Which = 17
 )
 
 func (w SCIONDMsg_Which) String() string {
-	const s = "unsetpathReqpathReplyasInfoReqasInfoReplyrevNotificationifInfoRequestifInfoReplyserviceInfoRequestserviceInfoReplyrevReplysegTypeHopReqsegTypeHopReply"
+	const s = "unsetpathReqpathReplyasInfoReqasInfoReplyrevNotificationifInfoRequestifInfoReplyserviceInfoRequestserviceInfoReplyrevReplysegTypeHopReqsegTypeHopReplynextQueryReqnextQueryReplypathFeedbackReqpathFeedbackReplytopoChangeNotification"
 	switch w {
 	case SCIONDMsg_Which_unset:
 		return s[0:5]
@@ -57,6 +62,16 @@ func (w SCIONDMsg_Which) String() string {
 		return s[122:135]
 	case SCIONDMsg_Which_segTypeHopReply:
 		return s[135:150]
+	case SCIONDMsg_Which_nextQueryReq:
+		return s[150:162]
+	case SCIONDMsg_Which_nextQueryReply:
+		return s[162:176]
+	case SCIONDMsg_Which_pathFeedbackReq:
+		return s[176:191]
+	case SCIONDMsg_Which_pathFeedbackReply:
+		return s[191:208]
+	case SCIONDMsg_Which_topoChangeNotification:
+		return s[208:230]
 
 	}
 	return "SCIONDMsg_Which(" + strconv.FormatUint(uint64(w), 10) + ")"
@@ -497,6 +512,171 @@ func (s SCIONDMsg) NewSegTypeHopReply() (SegTypeHopReply, error) {
 	return ss, err
 }
 
+func (s SCIONDMsg) NextQueryReq() (NextQueryReq, error) {
+	if s.Struct.Uint16(8) != 13 {
+		panic("Which() != nextQueryReq")
+	}
+	p, err := s.Struct.Ptr(0)
+	return NextQueryReq{Struct: p.Struct()}, err
+}
+
+func (s SCIONDMsg) HasNextQueryReq() bool {
+	if s.Struct.Uint16(8) != 13 {
+		return false
+	}
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s SCIONDMsg) SetNextQueryReq(v NextQueryReq) error {
+	s.Struct.SetUint16(8, 13)
+	return s.Struct.SetPtr(0, v.Struct.ToPtr())
+}
+
+// NewNextQueryReq sets the nextQueryReq field to a newly
+// allocated NextQueryReq struct, preferring placement in s's segment.
+func (s SCIONDMsg) NewNextQueryReq() (NextQueryReq, error) {
+	s.Struct.SetUint16(8, 13)
+	ss, err := NewNextQueryReq(s.Struct.Segment())
+	if err != nil {
+		return NextQueryReq{}, err
+	}
+	err = s.Struct.SetPtr(0, ss.Struct.ToPtr())
+	return ss, err
+}
+
+func (s SCIONDMsg) NextQueryReply() (NextQueryReply, error) {
+	if s.Struct.Uint16(8) != 14 {
+		panic("Which() != nextQueryReply")
+	}
+	p, err := s.Struct.Ptr(0)
+	return NextQueryReply{Struct: p.Struct()}, err
+}
+
+func (s SCIONDMsg) HasNextQueryReply() bool {
+	if s.Struct.Uint16(8) != 14 {
+		return false
+	}
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s SCIONDMsg) SetNextQueryReply(v NextQueryReply) error {
+	s.Struct.SetUint16(8, 14)
+	return s.Struct.SetPtr(0, v.Struct.ToPtr())
+}
+
+// NewNextQueryReply sets the nextQueryReply field to a newly
+// allocated NextQueryReply struct, preferring placement in s's segment.
+func (s SCIONDMsg) NewNextQueryReply() (NextQueryReply, error) {
+	s.Struct.SetUint16(8, 14)
+	ss, err := NewNextQueryReply(s.Struct.Segment())
+	if err != nil {
+		return NextQueryReply{}, err
+	}
+	err = s.Struct.SetPtr(0, ss.Struct.ToPtr())
+	return ss, err
+}
+
+func (s SCIONDMsg) PathFeedbackReq() (PathFeedbackReq, error) {
+	if s.Struct.Uint16(8) != 15 {
+		panic("Which() != pathFeedbackReq")
+	}
+	p, err := s.Struct.Ptr(0)
+	return PathFeedbackReq{Struct: p.Struct()}, err
+}
+
+func (s SCIONDMsg) HasPathFeedbackReq() bool {
+	if s.Struct.Uint16(8) != 15 {
+		return false
+	}
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s SCIONDMsg) SetPathFeedbackReq(v PathFeedbackReq) error {
+	s.Struct.SetUint16(8, 15)
+	return s.Struct.SetPtr(0, v.Struct.ToPtr())
+}
+
+// NewPathFeedbackReq sets the pathFeedbackReq field to a newly
+// allocated PathFeedbackReq struct, preferring placement in s's segment.
+func (s SCIONDMsg) NewPathFeedbackReq() (PathFeedbackReq, error) {
+	s.Struct.SetUint16(8, 15)
+	ss, err := NewPathFeedbackReq(s.Struct.Segment())
+	if err != nil {
+		return PathFeedbackReq{}, err
+	}
+	err = s.Struct.SetPtr(0, ss.Struct.ToPtr())
+	return ss, err
+}
+
+func (s SCIONDMsg) PathFeedbackReply() (PathFeedbackReply, error) {
+	if s.Struct.Uint16(8) != 16 {
+		panic("Which() != pathFeedbackReply")
+	}
+	p, err := s.Struct.Ptr(0)
+	return PathFeedbackReply{Struct: p.Struct()}, err
+}
+
+func (s SCIONDMsg) HasPathFeedbackReply() bool {
+	if s.Struct.Uint16(8) != 16 {
+		return false
+	}
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s SCIONDMsg) SetPathFeedbackReply(v PathFeedbackReply) error {
+	s.Struct.SetUint16(8, 16)
+	return s.Struct.SetPtr(0, v.Struct.ToPtr())
+}
+
+// NewPathFeedbackReply sets the pathFeedbackReply field to a newly
+// allocated PathFeedbackReply struct, preferring placement in s's segment.
+func (s SCIONDMsg) NewPathFeedbackReply() (PathFeedbackReply, error) {
+	s.Struct.SetUint16(8, 16)
+	ss, err := NewPathFeedbackReply(s.Struct.Segment())
+	if err != nil {
+		return PathFeedbackReply{}, err
+	}
+	err = s.Struct.SetPtr(0, ss.Struct.ToPtr())
+	return ss, err
+}
+
+func (s SCIONDMsg) TopoChangeNotification() (TopoChangeNotification, error) {
+	if s.Struct.Uint16(8) != 17 {
+		panic("Which() != topoChangeNotification")
+	}
+	p, err := s.Struct.Ptr(0)
+	return TopoChangeNotification{Struct: p.Struct()}, err
+}
+
+func (s SCIONDMsg) HasTopoChangeNotification() bool {
+	if s.Struct.Uint16(8) != 17 {
+		return false
+	}
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s SCIONDMsg) SetTopoChangeNotification(v TopoChangeNotification) error {
+	s.Struct.SetUint16(8, 17)
+	return s.Struct.SetPtr(0, v.Struct.ToPtr())
+}
+
+// NewTopoChangeNotification sets the topoChangeNotification field to a newly
+// allocated TopoChangeNotification struct, preferring placement in s's segment.
+func (s SCIONDMsg) NewTopoChangeNotification() (TopoChangeNotification, error) {
+	s.Struct.SetUint16(8, 17)
+	ss, err := NewTopoChangeNotification(s.Struct.Segment())
+	if err != nil {
+		return TopoChangeNotification{}, err
+	}
+	err = s.Struct.SetPtr(0, ss.Struct.ToPtr())
+	return ss, err
+}
+
 // SCIONDMsg_List is a list of SCIONDMsg.
 type SCIONDMsg_List struct{ capnp.List }
 
@@ -571,6 +751,26 @@ func (p SCIONDMsg_Promise) SegTypeHopReply() SegTypeHopReply_Promise {
 	return SegTypeHopReply_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
 }
 
+func (p SCIONDMsg_Promise) NextQueryReq() NextQueryReq_Promise {
+	return NextQueryReq_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
+}
+
+func (p SCIONDMsg_Promise) NextQueryReply() NextQueryReply_Promise {
+	return NextQueryReply_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
+}
+
+func (p SCIONDMsg_Promise) PathFeedbackReq() PathFeedbackReq_Promise {
+	return PathFeedbackReq_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
+}
+
+func (p SCIONDMsg_Promise) PathFeedbackReply() PathFeedbackReply_Promise {
+	return PathFeedbackReply_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
+}
+
+func (p SCIONDMsg_Promise) TopoChangeNotification() TopoChangeNotification_Promise {
+	return TopoChangeNotification_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
+}
+
 type PathReq struct{ capnp.Struct }
 type PathReq_flags PathReq
 
@@ -639,6 +839,38 @@ func (s PathReq_flags) SetHidden(v bool) {
 	s.Struct.SetBit(145, v)
 }
 
+func (s PathReq_flags) Ordering() uint8 {
+	return s.Struct.Uint8(19)
+}
+
+func (s PathReq_flags) SetOrdering(v uint8) {
+	s.Struct.SetUint8(19, v)
+}
+
+func (s PathReq_flags) MaxCandidates() uint16 {
+	return s.Struct.Uint16(20)
+}
+
+func (s PathReq_flags) SetMaxCandidates(v uint16) {
+	s.Struct.SetUint16(20, v)
+}
+
+func (s PathReq_flags) Disjointness() uint8 {
+	return s.Struct.Uint8(22)
+}
+
+func (s PathReq_flags) SetDisjointness(v uint8) {
+	s.Struct.SetUint8(22, v)
+}
+
+func (s PathReq_flags) AllowStale() bool {
+	return s.Struct.Bit(146)
+}
+
+func (s PathReq_flags) SetAllowStale(v bool) {
+	s.Struct.SetBit(146, v)
+}
+
 func (s PathReq) HpCfgs() (HPGroupId_List, error) {
 	p, err := s.Struct.Ptr(0)
 	return HPGroupId_List{List: p.List()}, err
@@ -758,6 +990,22 @@ func (s PathReply) NewEntries(n int32) (PathReplyEntry_List, error) {
 	return l, err
 }
 
+func (s PathReply) Stale() bool {
+	return s.Struct.Bit(16)
+}
+
+func (s PathReply) SetStale(v bool) {
+	s.Struct.SetBit(16, v)
+}
+
+func (s PathReply) Staleness() uint32 {
+	return s.Struct.Uint32(4)
+}
+
+func (s PathReply) SetStaleness(v uint32) {
+	s.Struct.SetUint32(4, v)
+}
+
 // PathReply_List is a list of PathReply.
 type PathReply_List struct{ capnp.List }
 
@@ -1925,12 +2173,12 @@ type ServiceInfoReplyEntry struct{ capnp.Struct }
 const ServiceInfoReplyEntry_TypeID = 0xe7279389a6bbe1dc
 
 func NewServiceInfoReplyEntry(s *capnp.Segment) (ServiceInfoReplyEntry, error) {
-	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 1})
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2})
 	return ServiceInfoReplyEntry{st}, err
 }
 
 func NewRootServiceInfoReplyEntry(s *capnp.Segment) (ServiceInfoReplyEntry, error) {
-	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 1})
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2})
 	return ServiceInfoReplyEntry{st}, err
 }
 
@@ -1985,12 +2233,37 @@ func (s ServiceInfoReplyEntry) NewHostInfos(n int32) (HostInfo_List, error) {
 	return l, err
 }
 
+func (s ServiceInfoReplyEntry) Instances() (ServiceInstance_List, error) {
+	p, err := s.Struct.Ptr(1)
+	return ServiceInstance_List{List: p.List()}, err
+}
+
+func (s ServiceInfoReplyEntry) HasInstances() bool {
+	p, err := s.Struct.Ptr(1)
+	return p.IsValid() || err != nil
+}
+
+func (s ServiceInfoReplyEntry) SetInstances(v ServiceInstance_List) error {
+	return s.Struct.SetPtr(1, v.List.ToPtr())
+}
+
+// NewInstances sets the instances field to a newly
+// allocated ServiceInstance_List, preferring placement in s's segment.
+func (s ServiceInfoReplyEntry) NewInstances(n int32) (ServiceInstance_List, error) {
+	l, err := NewServiceInstance_List(s.Struct.Segment(), n)
+	if err != nil {
+		return ServiceInstance_List{}, err
+	}
+	err = s.Struct.SetPtr(1, l.List.ToPtr())
+	return l, err
+}
+
 // ServiceInfoReplyEntry_List is a list of ServiceInfoReplyEntry.
 type ServiceInfoReplyEntry_List struct{ capnp.List }
 
 // NewServiceInfoReplyEntry creates a new list of ServiceInfoReplyEntry.
 func NewServiceInfoReplyEntry_List(s *capnp.Segment, sz int32) (ServiceInfoReplyEntry_List, error) {
-	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 8, PointerCount: 1}, sz)
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2}, sz)
 	return ServiceInfoReplyEntry_List{l}, err
 }
 
@@ -2248,152 +2521,773 @@ func (p SegTypeHopReplyEntry_Promise) Struct() (SegTypeHopReplyEntry, error) {
 	return SegTypeHopReplyEntry{s}, err
 }
 
-const schema_8f4bd412642c9517 = "x\xda\x94W}l[\xd5\x15?\xe7^\xbb\xb6\x13;" +
-	"\xb6\xfb\x1c\xa82m\x19UQ\x9b\x8aT$\xd0\xad\xaa" +
-	"4\xd2\xa6M\x88\xd9\x02yv6\x09\x04\x1a&~N" +
-	"<9\xb6\xfb\xdeK\x1a#\xba\xd0)\xd9X\x07\xa2\x08" +
-	"\xaa}\xb4h|\xac]\xb3\x81F\xbb\x16A\xa7!!" +
-	"\x9aM\x8b`\x03\x89\x89-*\x03:J\x9b\xb2iK" +
-	"iW\xd2\xad{\xd3y\xdf}}),\x7f]\xe7w" +
-	"|\xee\xf9\xfa\xfd\xce\xf5\xf5<\xb8\x81\xb5\x05\xff\x11\x02" +
-	"\x10\xef\x0c.\xd1>z\xee\xd9}\x1f\x9e\xbd\xf7;\x90" +
-	"\x8c\xa1v\xf5\xae\xeb\xf2K\xdf\xfc\xf2\xc3\x10\xc4\x10\x80" +
-	"\xf06\x9f\x15\xe68\x9dN\xf0\x0e@\xed\xec\xec\xc2\xd7" +
-	"_\x9ayg\x07\x881t\x1b32i\x0c\xcc\x08\xd7" +
-	"\x04\xe8\xf4\xd9\xc0I@\xad)\xb9\xa7\xfb}y\xfb\xc3" +
-	"\x1ec\xdd\"\x18<(\xc4\x82t\x8a\x04\xc9q\xf7+" +
-	"\xdd\xe3\x87v\x9f~\x84l\x99c\xdb\x85\xa1\x18\x06\x84" +
-	"\x96\xe0\x11\xa1\x8d\xacoh\x0d^\xcd\x01\xb5\xc7O\xa5" +
-	"\x8e\xafZ\xf6\xcd\xc7\xfcb~(<#\xfc0L\xa7" +
-	"]ar\xfd\xe4\xb6\xfa\xfdk7\xd4vy\\\xeba" +
-	"L\x87g\x857t\xdb\xd7\xc2[\x01\xb5\xb9\xcew&" +
-	"\x7f:\xb9d\xb7\x9f\xdf\x96\xc8iam\x84Nm\x11" +
-	"\xf2;\xfb\xe7\x1d\xa7\xde\x0b\xfe~7\x88\x8d\xc8\xb5\x0f" +
-	"\x9f>z\xac\xad\xf17G\xa1\x11C\x08 |52" +
-	"\x0b(\xdc\x1e!\xafK\xdb\x9eh\xbb3|\xdb\x94\x8f" +
-	"\xd7\x1b\x0eG\x18\x0a/\xebn\x7f\xad\xbb=4?%" +
-	"\xde\xb1\xec\xe3g\xbc%\xd6\xad\xe7#KQ\xc0:\xb2" +
-	"\xbe\x18\xf9\x05\xa0\xf6\xf9k\x1f\xdd\x1a\\\xd9t\xd0\xb7" +
-	"!\xcf\xd6\x1d\x14\x0e\xeb\xc6\x07\xea(\x8eSg\xae\x1a" +
-	"=\xf1\xf7\x0d\xaf\xf8e7_wZ\xb8\xa8\xdb.\xd4" +
-	"Q\x18v>b\x0c\xf9e\xa5\xa8\xff\x99\xd0V\xaf\xf7" +
-	"\xa3\xbe\x19\x01\xb5\xbf\x8d~\xbf\xda\xbfF\x9b\xf6x\xd6" +
-	"\xa3X\x1b=.l\x8c\xd2\xe9KQ\x8a\".\xfda" +
-	"c\xe7\xc4\xe7f\xfc\xc6\xe2\xf1\xe8\xac0\xa5\xdb\xee\x8d" +
-	"R\x14{?X\xb1g\xffS\xd2\xab~\xb6\xd3\xd1#" +
-	"\xc2k\xba\xed\xeft\xdbc\xef\xfdj\xdfw\x1f]y" +
-	"\xd2\xb7ps\xd1&\x14\x16t\xebsQ*\\\xe9\xdd" +
-	"\xcc\xd7\x9a\xde8\x7f\xd2\xaf\x16{c3\xc2\x81\x98^" +
-	"\xc1\x18y^\xb7\xf2\xado\x0f6N\xff\xd3\xcf\xb3\xf0" +
-	"v\xec\x8c0\xa7\x1b\x9f\x88Qz\x1d\x1f\xdc\xd4\xf2\xfc" +
-	"\\|\xde\xd7\xb8\xab\xe1\x88\xd0\xdb@\xa7t\x03\x19\xbf" +
-	"\xf8\xd2\xd8\xd4\xf7\xde\xdaw\xde/\x8a\x17\x1b\xce\x08\xd3" +
-	"\xba\xed\xcb\x0d\x14E\xb4\xe9/?\x1f\xbc\xf6\xc4\x02\x88" +
-	"W\xa1\xab\xf1\x8dL\x9f\xb7\xf9\x86\xe3\x80\xc29\xdd\xeb" +
-	"/\x9f\xbf\xf7\xe6CO\x1f\xb8\xe07\xf1b\xfc\x8cp" +
-	"W\x9cN\xb7\xc7\xa9\x0e\xca@\xb1R\xce\xaf\x19`\xb9" +
-	"j\xb9\xba>\xdd\x9d.\x17*\x19i\xcb\x88\xc4\x15\xb5" +
-	"\x0fQ\x0c\xf0\x00@\x00\x01\x92\xb1v\x001\xccQ\\" +
-	"\xc1\xb0\xb9XHoV\xb0\x01\xb0\x8f#F\x80\xd1\xd1" +
-	"\xe3\xab{k\xbe/\xa7\x0e\xf5Jj\x0e\x80\\%l" +
-	"W\xb9N\xd2\x1e\x8e\xe2\x10C\xc4\x14\xd2\xff\xa4\xe5\x00" +
-	"\xe2\xdd\x1c\xc5\x12\xc3$\xc3\x142\x80d\xf1\x0e\x00q" +
-	"\x88\xa38\xc10\xc91\x85\x1c \xb9\x9d\xbe}\x1fG" +
-	"\xf1\x01\x86\xe3\x05\xe3\x16\x8c\x01\xc3\x18`hX\x1d\xc1" +
-	"\x100\x0c\x01j\xc5\xb2*\xc9\x85\xdc\x00p\xc9\x8e5" +
-	"\xe1\xe8\x01 \xfds\\\x1a\xab\xf6\x17\x87%\x0c\x03\xc3" +
-	"\xb0+\x0b\xd4\xb3\xc8H\xa3\xcd\x19\xa9Z\xaay\x8a\xb1" +
-	"\xde,F\x8aa\x87,)#%\xd5\xbe\xf6R\x07\xd9" +
-	"M\xe9\x8e\xdbn\xdd\xdc\xab\x0c\x92\x87u\x96\x07\xa1\x86" +
-	"M\x00Y\x159f\xefG\x861\xd44\xbd\x10\xc26" +
-	"l\x07\xc8\x8e\x110A\x00\xfb\xaf\xa6\x17C\xd8\x8e\x9d" +
-	"\x00\xd9\xfb\x08x\x80\x00~Q\xd3\x0b\"Lb\x06 " +
-	";A\xc0N\x02\x02\xff\xd1R\x18 )\xd4\x81\x07\x09" +
-	"\xf8\x01\x01\xc1\x7fk)\x0c\x922\xe2=\x00\xd9\xc7\x08" +
-	"x\x82\x80%\x17\xb4\x14.!\x02\xe2\xb7\x00\xb2{\x08" +
-	"\xd8O@hAK\x19\x9c@\x19 \xfb\x13\x02\x9e#" +
-	" \xfc\xb1\x96\xc20QDw\xf5\x0c\x01/\x10\x109" +
-	"\xaf\xa50\x02 \x1c\xc6\x1f\x01d_ \xe0(\x01u" +
-	"\xff\xd2RXG\xe3\x8c;\x00\xb2G\x09x\x9d\x80\xfa" +
-	"sZ\x0a\xebI\x83\xf1\x16\x80\xec\xab\x04\xfc\x89\x80\xe8" +
-	"Y-\x85Q\x00\xe1\x8f\xfa\xe5o\x12\xf0.\x01\xb1\x8f" +
-	"\xb4\x14\xc6\x88|z\xb8\xc7\x088\x85\x0cy1\xaf\x0f" +
-	"c\x04\xb0y\xa4\xacH*,\x19\xaf\xe6\xd4\xa1\x8c\xb4" +
-	"\x05\x13\x8e\xa4\x01b\x02P3\x90j\x09\xb0\x86\x09\x87" +
-	"\xb7&\x9aS\x0c*\x00\xd2wm!\xf2\xa2\xa1j\x89" +
-	"\xbem/$\x13\x97\xa5\xd1[+j\xb1\x80\xc5\x81\x9c" +
-	"Z\xac\x94\x01\x13\xcer1m\x8a\x05\xd3G\xf3\x96\x11" +
-	"IQ1\xe1\xacb\xaf\x85y\x8b-Z&\xaeH\xf2" +
-	"hq@J\xa3\x8b\xb4\x98p\xf6\x8d\xafY\xb5T\x03" +
-	"\x0a\xc7\xd6\x1e'd\x13$\xd4^\xde\xb6\x8f\xc1\xfeZ" +
-	"U\xea\x81\xe6J\xd5(\xa7\xad\xe3\x1e\x0b$\x03\xf2\x83" +
-	"\x09g\xe3X6\x97(\xc4\xc6l\xda\x89\xc8\xc3\xafN" +
-	"Gl\xc6\xa5\xb2*\x17\xdd\x14\xb6\x05\xce\xa0\xb0\xc7-" +
-	"\xe9A\xda\xa0>\x1f\x90\xc8o\xd8\xf6\xdbB\"\xb6\x82" +
-	"\xa3x=\xc3\xa4%=\xad\xab\x01\xc4U\x1c\xc5\x1bI" +
-	"\xd9\x94|N\xb1\xa6(N:g}\xf0\\\x931[" +
-	"\\\x1c\xc8\xc5\xa9\xc5\x9e\x04n\x01\x10\xa3\x1c\xc5e\x0c" +
-	"5%#\x8dR\xaaFi3\x7f\xbd\xf0\xc5\xc9\x9b\xdb" +
-	"\x7f\xec_\x94>c^\xd7\x14J9>\xa8P\xe8\x89" +
-	"\x9d\x86\xee\xb5t\xbac\x7fD\xa7x\xb2u\xbd\x13\xfb" +
-	"\xb8,\x15dI\x19B\x04\x86\x08\xd81T\xcc\xe7\xa5" +
-	"\xb2\xf5\xd1\xbe\x88\x1b\xc2dN\x855;\x8a\xea\xed\xc1" +
-	"7\xcc\x14V1{\x86\xfa!^\xab:\xad\x88k\xea" +
-	"\xe0\xeb\x9fii\xcd\x1c\xf7\xb6\xc2\xba\xc3\x98\x09s$" +
-	"\xba\xca\xaa\x8c\xba\x92F\xed[\xbaH\xe27s\x14\xef" +
-	"vv\xc1]\x19g?\xd8\xbb@\xeat\x16\xc4\xa7\x93" +
-	"vM-\x0eK\x8a\x9a\x1b\x06\xacZ\xf2\xfe\x09r\xdf" +
-	"SQ\x9aU*\x89glV;\xa5\xa7?g\x19'" +
-	"[\xdb\x81\xc5\xab\x15\xd9\xd6\xff\xe6\\>/+\x9e\xae" +
-	"\xba\x0a\x11\xf7\xd9&W\x9cv\xfbE\xe8)1Z\xf3" +
-	"\x12\xa7\x81!\x8f)\xdb\xe36\xda\xa6c\xe6\xe2\xb4\xca" +
-	"\xba}\xb9\xb38\x93,l\x94u\x92\x06u\x82\xa3\xb8" +
-	"\x93!rt\xbdk\x93\x0f\xb5\x03\xc3\x80\xbe1\x92#" +
-	"4gU\x8e\xe2\x83\x0cCyE\xb5H\x11R\xe4\x01" +
-	"\x9b \xc3\xb91\x1a_\x85\xe6\xdc\xaaF\xa1\x94\x1bT" +
-	":\x86\xaa\x9b\x0a\x83\xae\x9c\x96u\xbd\x7f\x93\xf0\xdbk" +
-	"\x8e,\xce`s`B\xaa\\[\xbc\x17\x0e\x85)\x8b" +
-	"\xeb8\x8a\xeb\x18\xc6I\xd81\xe1\xfcX1y6T" +
-	"QT\x87\x85\xf6\x03\xca\x97\x85\xae~q\xa3\xb6\xaen" +
-	"\xadvv\x7f\\\xadU%\x8ck\xf7\xaf{\xaaN\x9a" +
-	":\xff$y\x8b_\xd6\xa3\x8d\xd9t\x87A\xb3E\x9e" +
-	"T)\xaf\xf0\\\x89\xab\x06\x91\xb8\xec%\xd2=&\x91" +
-	"\xfa\\u\xe9\xa5\x96\xf7p\x14\xfb\x19\"3:.\x12" +
-	"\xbb\xfaL\"\xd9\xbc\x0e\x19\x89\xb8\xf9\x1c\x07\x0c\xa9j" +
-	"\xc9\xa6\x8a]@tu\xd2]\xc7\x86E\x1f\x94\xff\xb7" +
-	"\xc4\xdbo\xeeOr\xdbL\xa2R\xbb\x12]}&\xe4" +
-	"\x12Y\xfftsa\xb3\xadc\xc8~\x0f\xban\xcc8" +
-	"2l\xdd\xd8\xd6i\xde\xd8\xc3P\x93d\xb9\"o\xaa" +
-	"\xe4\x01%\x8b\x1a\x97'm\xff\x8c\xf2M\xda5\x04\xbe" +
-	"O\xd2+\xd6\xd3\xfeu\xe4\xeb\xba\xc7,\xc1\x9a\\>" +
-	"\x94\x97\x15#1#\x8b\xcb\xe8\xc6<\x1b3^\xac\x8e" +
-	"\xdeh\xbd\xbf\xe9\xc3\x17\xac\x0f\x8b\xaf{\xa7i\xae\xf9" +
-	"%2l\xe0(~\x85.\x0a\x18\xb7\xa7\x97\xbb\x86\x9a" +
-	"\xf5\x19\xb7\xf7\xaew\x86\xfaR\xda\xb8\x9f\xff\x1dEe" +
-	"SE\x96\xac\xed\xf7\xbf\x00\x00\x00\xff\xffvu8\x1b"
+type NextQueryReq struct{ capnp.Struct }
+
+// NextQueryReq_TypeID is the unique identifier for the type NextQueryReq.
+const NextQueryReq_TypeID = 0x8c745e24d32419fd
+
+func NewNextQueryReq(s *capnp.Segment) (NextQueryReq, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 16, PointerCount: 0})
+	return NextQueryReq{st}, err
+}
+
+func NewRootNextQueryReq(s *capnp.Segment) (NextQueryReq, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 16, PointerCount: 0})
+	return NextQueryReq{st}, err
+}
+
+func ReadRootNextQueryReq(msg *capnp.Message) (NextQueryReq, error) {
+	root, err := msg.RootPtr()
+	return NextQueryReq{root.Struct()}, err
+}
+
+func (s NextQueryReq) String() string {
+	str, _ := text.Marshal(0x8c745e24d32419fd, s.Struct)
+	return str
+}
+
+func (s NextQueryReq) Dst() uint64 {
+	return s.Struct.Uint64(0)
+}
+
+func (s NextQueryReq) SetDst(v uint64) {
+	s.Struct.SetUint64(0, v)
+}
+
+func (s NextQueryReq) Delete() bool {
+	return s.Struct.Bit(64)
+}
+
+func (s NextQueryReq) SetDelete(v bool) {
+	s.Struct.SetBit(64, v)
+}
+
+// NextQueryReq_List is a list of NextQueryReq.
+type NextQueryReq_List struct{ capnp.List }
+
+// NewNextQueryReq creates a new list of NextQueryReq.
+func NewNextQueryReq_List(s *capnp.Segment, sz int32) (NextQueryReq_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 16, PointerCount: 0}, sz)
+	return NextQueryReq_List{l}, err
+}
+
+func (s NextQueryReq_List) At(i int) NextQueryReq { return NextQueryReq{s.List.Struct(i)} }
+
+func (s NextQueryReq_List) Set(i int, v NextQueryReq) error { return s.List.SetStruct(i, v.Struct) }
+
+func (s NextQueryReq_List) String() string {
+	str, _ := text.MarshalList(0x8c745e24d32419fd, s.List)
+	return str
+}
+
+// NextQueryReq_Promise is a wrapper for a NextQueryReq promised by a client call.
+type NextQueryReq_Promise struct{ *capnp.Pipeline }
+
+func (p NextQueryReq_Promise) Struct() (NextQueryReq, error) {
+	s, err := p.Pipeline.Struct()
+	return NextQueryReq{s}, err
+}
+
+type NextQueryReply struct{ capnp.Struct }
+
+// NextQueryReply_TypeID is the unique identifier for the type NextQueryReply.
+const NextQueryReply_TypeID = 0xa7f75dfd14b1cda2
+
+func NewNextQueryReply(s *capnp.Segment) (NextQueryReply, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 1})
+	return NextQueryReply{st}, err
+}
+
+func NewRootNextQueryReply(s *capnp.Segment) (NextQueryReply, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 1})
+	return NextQueryReply{st}, err
+}
+
+func ReadRootNextQueryReply(msg *capnp.Message) (NextQueryReply, error) {
+	root, err := msg.RootPtr()
+	return NextQueryReply{root.Struct()}, err
+}
+
+func (s NextQueryReply) String() string {
+	str, _ := text.Marshal(0xa7f75dfd14b1cda2, s.Struct)
+	return str
+}
+
+func (s NextQueryReply) Entries() (NextQueryReplyEntry_List, error) {
+	p, err := s.Struct.Ptr(0)
+	return NextQueryReplyEntry_List{List: p.List()}, err
+}
+
+func (s NextQueryReply) HasEntries() bool {
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s NextQueryReply) SetEntries(v NextQueryReplyEntry_List) error {
+	return s.Struct.SetPtr(0, v.List.ToPtr())
+}
+
+// NewEntries sets the entries field to a newly
+// allocated NextQueryReplyEntry_List, preferring placement in s's segment.
+func (s NextQueryReply) NewEntries(n int32) (NextQueryReplyEntry_List, error) {
+	l, err := NewNextQueryReplyEntry_List(s.Struct.Segment(), n)
+	if err != nil {
+		return NextQueryReplyEntry_List{}, err
+	}
+	err = s.Struct.SetPtr(0, l.List.ToPtr())
+	return l, err
+}
+
+func (s NextQueryReply) ErrorCode() uint16 {
+	return s.Struct.Uint16(0)
+}
+
+func (s NextQueryReply) SetErrorCode(v uint16) {
+	s.Struct.SetUint16(0, v)
+}
+
+// NextQueryReply_List is a list of NextQueryReply.
+type NextQueryReply_List struct{ capnp.List }
+
+// NewNextQueryReply creates a new list of NextQueryReply.
+func NewNextQueryReply_List(s *capnp.Segment, sz int32) (NextQueryReply_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 8, PointerCount: 1}, sz)
+	return NextQueryReply_List{l}, err
+}
+
+func (s NextQueryReply_List) At(i int) NextQueryReply { return NextQueryReply{s.List.Struct(i)} }
+
+func (s NextQueryReply_List) Set(i int, v NextQueryReply) error { return s.List.SetStruct(i, v.Struct) }
+
+func (s NextQueryReply_List) String() string {
+	str, _ := text.MarshalList(0xa7f75dfd14b1cda2, s.List)
+	return str
+}
+
+// NextQueryReply_Promise is a wrapper for a NextQueryReply promised by a client call.
+type NextQueryReply_Promise struct{ *capnp.Pipeline }
+
+func (p NextQueryReply_Promise) Struct() (NextQueryReply, error) {
+	s, err := p.Pipeline.Struct()
+	return NextQueryReply{s}, err
+}
+
+type NextQueryReplyEntry struct{ capnp.Struct }
+
+// NextQueryReplyEntry_TypeID is the unique identifier for the type NextQueryReplyEntry.
+const NextQueryReplyEntry_TypeID = 0xb156f55bfea7787c
+
+func NewNextQueryReplyEntry(s *capnp.Segment) (NextQueryReplyEntry, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 24, PointerCount: 0})
+	return NextQueryReplyEntry{st}, err
+}
+
+func NewRootNextQueryReplyEntry(s *capnp.Segment) (NextQueryReplyEntry, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 24, PointerCount: 0})
+	return NextQueryReplyEntry{st}, err
+}
+
+func ReadRootNextQueryReplyEntry(msg *capnp.Message) (NextQueryReplyEntry, error) {
+	root, err := msg.RootPtr()
+	return NextQueryReplyEntry{root.Struct()}, err
+}
+
+func (s NextQueryReplyEntry) String() string {
+	str, _ := text.Marshal(0xb156f55bfea7787c, s.Struct)
+	return str
+}
+
+func (s NextQueryReplyEntry) Src() uint64 {
+	return s.Struct.Uint64(0)
+}
+
+func (s NextQueryReplyEntry) SetSrc(v uint64) {
+	s.Struct.SetUint64(0, v)
+}
+
+func (s NextQueryReplyEntry) Dst() uint64 {
+	return s.Struct.Uint64(8)
+}
+
+func (s NextQueryReplyEntry) SetDst(v uint64) {
+	s.Struct.SetUint64(8, v)
+}
+
+func (s NextQueryReplyEntry) NextQuery() uint32 {
+	return s.Struct.Uint32(16)
+}
+
+func (s NextQueryReplyEntry) SetNextQuery(v uint32) {
+	s.Struct.SetUint32(16, v)
+}
+
+// NextQueryReplyEntry_List is a list of NextQueryReplyEntry.
+type NextQueryReplyEntry_List struct{ capnp.List }
+
+// NewNextQueryReplyEntry creates a new list of NextQueryReplyEntry.
+func NewNextQueryReplyEntry_List(s *capnp.Segment, sz int32) (NextQueryReplyEntry_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 24, PointerCount: 0}, sz)
+	return NextQueryReplyEntry_List{l}, err
+}
+
+func (s NextQueryReplyEntry_List) At(i int) NextQueryReplyEntry {
+	return NextQueryReplyEntry{s.List.Struct(i)}
+}
+
+func (s NextQueryReplyEntry_List) Set(i int, v NextQueryReplyEntry) error {
+	return s.List.SetStruct(i, v.Struct)
+}
+
+func (s NextQueryReplyEntry_List) String() string {
+	str, _ := text.MarshalList(0xb156f55bfea7787c, s.List)
+	return str
+}
+
+// NextQueryReplyEntry_Promise is a wrapper for a NextQueryReplyEntry promised by a client call.
+type NextQueryReplyEntry_Promise struct{ *capnp.Pipeline }
+
+func (p NextQueryReplyEntry_Promise) Struct() (NextQueryReplyEntry, error) {
+	s, err := p.Pipeline.Struct()
+	return NextQueryReplyEntry{s}, err
+}
+
+type PathFeedbackReq struct{ capnp.Struct }
+
+// PathFeedbackReq_TypeID is the unique identifier for the type PathFeedbackReq.
+const PathFeedbackReq_TypeID = 0xc4b2a48f218903cb
+
+func NewPathFeedbackReq(s *capnp.Segment) (PathFeedbackReq, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 1})
+	return PathFeedbackReq{st}, err
+}
+
+func NewRootPathFeedbackReq(s *capnp.Segment) (PathFeedbackReq, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 1})
+	return PathFeedbackReq{st}, err
+}
+
+func ReadRootPathFeedbackReq(msg *capnp.Message) (PathFeedbackReq, error) {
+	root, err := msg.RootPtr()
+	return PathFeedbackReq{root.Struct()}, err
+}
+
+func (s PathFeedbackReq) String() string {
+	str, _ := text.Marshal(0xc4b2a48f218903cb, s.Struct)
+	return str
+}
+
+func (s PathFeedbackReq) PathKey() ([]byte, error) {
+	p, err := s.Struct.Ptr(0)
+	return []byte(p.Data()), err
+}
+
+func (s PathFeedbackReq) HasPathKey() bool {
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s PathFeedbackReq) SetPathKey(v []byte) error {
+	return s.Struct.SetData(0, v)
+}
+
+func (s PathFeedbackReq) Rtt() uint32 {
+	return s.Struct.Uint32(0)
+}
+
+func (s PathFeedbackReq) SetRtt(v uint32) {
+	s.Struct.SetUint32(0, v)
+}
+
+func (s PathFeedbackReq) Loss() uint32 {
+	return s.Struct.Uint32(4)
+}
+
+func (s PathFeedbackReq) SetLoss(v uint32) {
+	s.Struct.SetUint32(4, v)
+}
+
+// PathFeedbackReq_List is a list of PathFeedbackReq.
+type PathFeedbackReq_List struct{ capnp.List }
+
+// NewPathFeedbackReq creates a new list of PathFeedbackReq.
+func NewPathFeedbackReq_List(s *capnp.Segment, sz int32) (PathFeedbackReq_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 8, PointerCount: 1}, sz)
+	return PathFeedbackReq_List{l}, err
+}
+
+func (s PathFeedbackReq_List) At(i int) PathFeedbackReq { return PathFeedbackReq{s.List.Struct(i)} }
+
+func (s PathFeedbackReq_List) Set(i int, v PathFeedbackReq) error {
+	return s.List.SetStruct(i, v.Struct)
+}
+
+func (s PathFeedbackReq_List) String() string {
+	str, _ := text.MarshalList(0xc4b2a48f218903cb, s.List)
+	return str
+}
+
+// PathFeedbackReq_Promise is a wrapper for a PathFeedbackReq promised by a client call.
+type PathFeedbackReq_Promise struct{ *capnp.Pipeline }
+
+func (p PathFeedbackReq_Promise) Struct() (PathFeedbackReq, error) {
+	s, err := p.Pipeline.Struct()
+	return PathFeedbackReq{s}, err
+}
+
+type PathFeedbackReply struct{ capnp.Struct }
+
+// PathFeedbackReply_TypeID is the unique identifier for the type PathFeedbackReply.
+const PathFeedbackReply_TypeID = 0x9a6be708302bcadd
+
+func NewPathFeedbackReply(s *capnp.Segment) (PathFeedbackReply, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 0})
+	return PathFeedbackReply{st}, err
+}
+
+func NewRootPathFeedbackReply(s *capnp.Segment) (PathFeedbackReply, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 0})
+	return PathFeedbackReply{st}, err
+}
+
+func ReadRootPathFeedbackReply(msg *capnp.Message) (PathFeedbackReply, error) {
+	root, err := msg.RootPtr()
+	return PathFeedbackReply{root.Struct()}, err
+}
+
+func (s PathFeedbackReply) String() string {
+	str, _ := text.Marshal(0x9a6be708302bcadd, s.Struct)
+	return str
+}
+
+func (s PathFeedbackReply) Result() uint16 {
+	return s.Struct.Uint16(0)
+}
+
+func (s PathFeedbackReply) SetResult(v uint16) {
+	s.Struct.SetUint16(0, v)
+}
+
+// PathFeedbackReply_List is a list of PathFeedbackReply.
+type PathFeedbackReply_List struct{ capnp.List }
+
+// NewPathFeedbackReply creates a new list of PathFeedbackReply.
+func NewPathFeedbackReply_List(s *capnp.Segment, sz int32) (PathFeedbackReply_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 8, PointerCount: 0}, sz)
+	return PathFeedbackReply_List{l}, err
+}
+
+func (s PathFeedbackReply_List) At(i int) PathFeedbackReply {
+	return PathFeedbackReply{s.List.Struct(i)}
+}
+
+func (s PathFeedbackReply_List) Set(i int, v PathFeedbackReply) error {
+	return s.List.SetStruct(i, v.Struct)
+}
+
+func (s PathFeedbackReply_List) String() string {
+	str, _ := text.MarshalList(0x9a6be708302bcadd, s.List)
+	return str
+}
+
+// PathFeedbackReply_Promise is a wrapper for a PathFeedbackReply promised by a client call.
+type PathFeedbackReply_Promise struct{ *capnp.Pipeline }
+
+func (p PathFeedbackReply_Promise) Struct() (PathFeedbackReply, error) {
+	s, err := p.Pipeline.Struct()
+	return PathFeedbackReply{s}, err
+}
+
+type ServiceInstance struct{ capnp.Struct }
+
+// ServiceInstance_TypeID is the unique identifier for the type ServiceInstance.
+const ServiceInstance_TypeID = 0xf30abc511658c2b2
+
+func NewServiceInstance(s *capnp.Segment) (ServiceInstance, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 3})
+	return ServiceInstance{st}, err
+}
+
+func NewRootServiceInstance(s *capnp.Segment) (ServiceInstance, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 3})
+	return ServiceInstance{st}, err
+}
+
+func ReadRootServiceInstance(msg *capnp.Message) (ServiceInstance, error) {
+	root, err := msg.RootPtr()
+	return ServiceInstance{root.Struct()}, err
+}
+
+func (s ServiceInstance) String() string {
+	str, _ := text.Marshal(0xf30abc511658c2b2, s.Struct)
+	return str
+}
+
+func (s ServiceInstance) Id() (string, error) {
+	p, err := s.Struct.Ptr(0)
+	return p.Text(), err
+}
+
+func (s ServiceInstance) HasId() bool {
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s ServiceInstance) IdBytes() ([]byte, error) {
+	p, err := s.Struct.Ptr(0)
+	return p.TextBytes(), err
+}
+
+func (s ServiceInstance) SetId(v string) error {
+	return s.Struct.SetText(0, v)
+}
+
+func (s ServiceInstance) Ipv4() (HostInfo, error) {
+	p, err := s.Struct.Ptr(1)
+	return HostInfo{Struct: p.Struct()}, err
+}
+
+func (s ServiceInstance) HasIpv4() bool {
+	p, err := s.Struct.Ptr(1)
+	return p.IsValid() || err != nil
+}
+
+func (s ServiceInstance) SetIpv4(v HostInfo) error {
+	return s.Struct.SetPtr(1, v.Struct.ToPtr())
+}
+
+// NewIpv4 sets the ipv4 field to a newly
+// allocated HostInfo struct, preferring placement in s's segment.
+func (s ServiceInstance) NewIpv4() (HostInfo, error) {
+	ss, err := NewHostInfo(s.Struct.Segment())
+	if err != nil {
+		return HostInfo{}, err
+	}
+	err = s.Struct.SetPtr(1, ss.Struct.ToPtr())
+	return ss, err
+}
+
+func (s ServiceInstance) Ipv6() (HostInfo, error) {
+	p, err := s.Struct.Ptr(2)
+	return HostInfo{Struct: p.Struct()}, err
+}
+
+func (s ServiceInstance) HasIpv6() bool {
+	p, err := s.Struct.Ptr(2)
+	return p.IsValid() || err != nil
+}
+
+func (s ServiceInstance) SetIpv6(v HostInfo) error {
+	return s.Struct.SetPtr(2, v.Struct.ToPtr())
+}
+
+// NewIpv6 sets the ipv6 field to a newly
+// allocated HostInfo struct, preferring placement in s's segment.
+func (s ServiceInstance) NewIpv6() (HostInfo, error) {
+	ss, err := NewHostInfo(s.Struct.Segment())
+	if err != nil {
+		return HostInfo{}, err
+	}
+	err = s.Struct.SetPtr(2, ss.Struct.ToPtr())
+	return ss, err
+}
+
+// ServiceInstance_List is a list of ServiceInstance.
+type ServiceInstance_List struct{ capnp.List }
+
+// NewServiceInstance creates a new list of ServiceInstance.
+func NewServiceInstance_List(s *capnp.Segment, sz int32) (ServiceInstance_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 3}, sz)
+	return ServiceInstance_List{l}, err
+}
+
+func (s ServiceInstance_List) At(i int) ServiceInstance { return ServiceInstance{s.List.Struct(i)} }
+
+func (s ServiceInstance_List) Set(i int, v ServiceInstance) error {
+	return s.List.SetStruct(i, v.Struct)
+}
+
+func (s ServiceInstance_List) String() string {
+	str, _ := text.MarshalList(0xf30abc511658c2b2, s.List)
+	return str
+}
+
+// ServiceInstance_Promise is a wrapper for a ServiceInstance promised by a client call.
+type ServiceInstance_Promise struct{ *capnp.Pipeline }
+
+func (p ServiceInstance_Promise) Struct() (ServiceInstance, error) {
+	s, err := p.Pipeline.Struct()
+	return ServiceInstance{s}, err
+}
+
+func (p ServiceInstance_Promise) Ipv4() HostInfo_Promise {
+	return HostInfo_Promise{Pipeline: p.Pipeline.GetPipeline(1)}
+}
+
+func (p ServiceInstance_Promise) Ipv6() HostInfo_Promise {
+	return HostInfo_Promise{Pipeline: p.Pipeline.GetPipeline(2)}
+}
+
+type TopoChangeNotification struct{ capnp.Struct }
+
+// TopoChangeNotification_TypeID is the unique identifier for the type TopoChangeNotification.
+const TopoChangeNotification_TypeID = 0xe0f58596da339444
+
+func NewTopoChangeNotification(s *capnp.Segment) (TopoChangeNotification, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 0})
+	return TopoChangeNotification{st}, err
+}
+
+func NewRootTopoChangeNotification(s *capnp.Segment) (TopoChangeNotification, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 0})
+	return TopoChangeNotification{st}, err
+}
+
+func ReadRootTopoChangeNotification(msg *capnp.Message) (TopoChangeNotification, error) {
+	root, err := msg.RootPtr()
+	return TopoChangeNotification{root.Struct()}, err
+}
+
+func (s TopoChangeNotification) String() string {
+	str, _ := text.Marshal(0xe0f58596da339444, s.Struct)
+	return str
+}
+
+func (s TopoChangeNotification) Timestamp() uint32 {
+	return s.Struct.Uint32(0)
+}
+
+func (s TopoChangeNotification) SetTimestamp(v uint32) {
+	s.Struct.SetUint32(0, v)
+}
+
+// TopoChangeNotification_List is a list of TopoChangeNotification.
+type TopoChangeNotification_List struct{ capnp.List }
+
+// NewTopoChangeNotification creates a new list of TopoChangeNotification.
+func NewTopoChangeNotification_List(s *capnp.Segment, sz int32) (TopoChangeNotification_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 8, PointerCount: 0}, sz)
+	return TopoChangeNotification_List{l}, err
+}
+
+func (s TopoChangeNotification_List) At(i int) TopoChangeNotification {
+	return TopoChangeNotification{s.List.Struct(i)}
+}
+
+func (s TopoChangeNotification_List) Set(i int, v TopoChangeNotification) error {
+	return s.List.SetStruct(i, v.Struct)
+}
+
+func (s TopoChangeNotification_List) String() string {
+	str, _ := text.MarshalList(0xe0f58596da339444, s.List)
+	return str
+}
+
+// TopoChangeNotification_Promise is a wrapper for a TopoChangeNotification promised by a client call.
+type TopoChangeNotification_Promise struct{ *capnp.Pipeline }
+
+func (p TopoChangeNotification_Promise) Struct() (TopoChangeNotification, error) {
+	s, err := p.Pipeline.Struct()
+	return TopoChangeNotification{s}, err
+}
+
+const schema_8f4bd412642c9517 = "x\xda\x9cX{\x90\x14\xd5\xd5?\xe7\xf6<\xf61\xbb" +
+	"=3\xdd\xbb\x1f\xee\xf7\xf9\xadRP\x02\x11\x02\xbb\x9a" +
+	" %\x0e,\xb0\xb2*\xba=\x83\xcfHb\xb3sw" +
+	"wpvfv\xbaw\xd9\xb1$@\x8aM\x82\xc1R" +
+	"T\x12\xe3\xa3\x02\xbeI\xb4\"\x84X\x91\xa8UFH" +
+	"J\xf2~\x95\xe5\x96F%*\x8a1\x11\x94 $\xd8" +
+	"\xa9\xd3\xdd\xd3\xdd\xdb\xdbK,\xe7\xaf\x9e\xf9\x9d9\xf7" +
+	"\xdc\xdf9\xf7w\xce\xed\xb93\xa3\x8b\xd8\xbc\xf0\xd5u" +
+	"\x00\xcaH8b|\xf8\xe4\x13\x8f\xbc\xf7\xd1M\xdf\x80" +
+	"D\x03\x1a\xff\xb3\xed\xdcl\xf2O\x97\xde\x06a\x8c\x02" +
+	"H\x89\xf0\x98tf\x98\x9e\xce\x08\xa7\x00\x8d\x8f\xc6N" +
+	"|\xe5\xb9\x03\xaf\xdd\x02J\x03z\x8d\x19\x99t\x85\x0f" +
+	"HW\x9a\xc6J\xf8\x10\xa0q\xea\x8ci\x7f\x9c\xf6e" +
+	"}\x0b\x193\xd78D\x16\x17D\xfe -\x8b\xd0\xd3" +
+	"\xe2\xc8Z@\xa3%q_\xe7\x9b\xe5\x8d\xb7\xf9\x1c\x9b" +
+	"\xb6\xf7GvK\x0f\x9b\xb6;\"\x14D\xe7\x0b\x9d\xeb" +
+	"\xf7\xdc{x\xab\xcf\xef2\x8c&1$=\x1f\xd9+" +
+	"\xbdH\xd6\xed\xfb#\x9f\x0f\x01\x1a\xf7\xbf#\x1f\x9c1" +
+	"\xe5\xabw\x05\xedo\xa0\xfe\x80T\xa9\xa7\xa7\xa1zr" +
+	"\xbdc]\xfdc\xe7/\xaal\x0b\x0a\xf9\xe1\xfa1i" +
+	"\x97i\xfbD=\x85\xfc\xea\x81\xcf\xcd\xad9t\xe3=" +
+	"A!\xd7\xc6NJM1\x93\xc1\x18\xf9}\xb7\xe3\xb5" +
+	"\xd1GG#\xf7\x06\xc50/vXZh\xda^`" +
+	"\xda\x8e\xbd|\xcb;o\x84\x7fs/(M(\x18\xef" +
+	"=\xb8\xef\x95yM?\xdf\x07M\x18E\x00iUl" +
+	"\x0c\xb0]\x8d]\x8d\x80\xc6\x03\xbf\xde%\x9fZu\xfc" +
+	"Q\x7f:L\xbf\xcf6\x1c\x94^l\xa0\xa7\xfd\x0d\x14" +
+	"or\xde\xf6y\xd7\xd7\\\xb13 \x86\xf6\xf3\x1b\x19" +
+	"J\x8b\x1b\xc9xa#\x05\xb1\xe7\xc8N\xe5\xba)\x1f" +
+	"?\x1e\xe4\xb9}\xb01\x89\xd2F\xd3z]\xe3\x0f\x01" +
+	"\x8d\x9bG\x1e\xfd\xe4K\xc7\xae\xdaE\xd6\xc2x*\xda" +
+	"g\x8bu(-\x14\xcd\xfd\x89d|\xd6\xf4;\xd7\x86" +
+	"\xcfi\xd9\x1dXC\x7f\x16wK\xaf\x9a\xc6/\x8b\x14" +
+	"\xf4;G\x9b\x87\xdfz\x7f\xd1\x0bA\xc4\x9d\x11?," +
+	"M\x8f\xd3\xd3\xd9q\x8a\xf9\x97\xc2\xe6\xb3o{h\xf7" +
+	"\xbe@6\x94\xf8ai\x95i|m\x9c\xa2px\x1d" +
+	"\x1f\xb2e|$\xfe}\xe9\x04\x19\xb7\x1f\x8b\xb7\"\xa0" +
+	"\xf1\xb7\xe1\xef\x94V\xce1\xf6\xfb\xc20C>\x958" +
+	"(\xd5&\xe9)\x9c\xa4\x90E\xfe\xdb\xc5\x1d\x9b\xfe\xff" +
+	"@P]\xf0\xe4\x984h\xda\x0e$)\xe4\x87\xdf\x9e" +
+	"v\xdfc\x0f\xf0_\x05\xd9\xde\x9a\xdc+m3m\xb7" +
+	"\x9a\xb6K\xefj\x1f\xfb\xf6\xe8\xb1\xd7\x03l\xdbw%" +
+	"\xa7\xa2\xf4\xbci\xfc\xaci\xfc\xca\x1b?}d\xf3\x9d" +
+	"\xe7\x1c\x0a\"\xb9\xfd\x8dd\x0bJGL\xeb\xf7\x93t" +
+	"R\xf3\xaf\xa7\xafj\xf9\xfd\xf1CA,\xbf/\x1d\x90" +
+	"NH\xf4tL\"\xcf\xf3\xcfy\xe9\xeb}M\xfb?" +
+	"\x08dy\xba|T\x9a'\xd3\xd3l\x99\xb8H\xbd}" +
+	"\xd1\xcc\xa7\xde\x15\x8f\x04\x1a\xdf*\xef\x95\xb6\x99\xc6[" +
+	"e\x8ab\xf7\xcf\xaeiV\x9e\xa9\xfb\xd0\x17\x85`\xd6" +
+	"Y\xd3ais\x13=\x8d6Q\xfa\x9e~nd\xe7" +
+	"\xb7^z\xe4xP\xc4g6\x1f\x95f6\x9b\xe14" +
+	"S\xc4\xb1\x96\xbf\xfc\xa0o\xfa['@iFO\xf9" +
+	"51\xf3@]\xd9|\x10P\xba\xb6\x99\xc2\xfd\xd1S" +
+	"7]\xbc\xe7\xc1]'\x83\x8e\xff\x8f\x9b\x8fJ\xcf\x9b" +
+	"^\x9fm\xa6\x08\xb4\x9e\\\xb1\x90\x9d\xd3\xc3\xd4R\xa1" +
+	"\xb4\xa0\xab\xb3\xab\xd0[L\xf3\xc1!.hz7\xa2" +
+	"\x12\x12B\x00!\x04H4\xb4\x01(5\x02*\xd3\x18" +
+	"\xb6\xe6z\xbb\x96j\xd8\x08\xd8- \xd6\x02\xc3\xc6\x09" +
+	"\xbe:\xd7f\xbbU\xbd\x7f\x05\xd7U\x00r\x15w\\" +
+	"\xa9\x1d\x00\xca\xf5\x02*\xfd\x0c\x11e\xa4\xdf\xf8T\x00" +
+	"\xe5\x06\x01\x95<\xc3\x04C\x19\x19@\"w\x1d\x80\xd2" +
+	"/\xa0\xb2\x89aB@\x19\x05\x80\xc4F\xfa\xf7\xcd\x02" +
+	"*\xdfd\xb8\xbe\xd7Z\x05\x1b\x80a\x03`t@\x1f" +
+	"\xc2(0\x8c\x02\x1a\xb9\x82\xce\xcb\xbdj\x0f\x08\xdc\x89" +
+	"5\xee\x8a# \xfd\xb8\x9e\x8f\x94V\xe6\x068\xd6\x00" +
+	"\xc3\x9a\x09\xbb\xb8\x9c\x8f\xe8\xca\x10/W\xd2\x1c\x07i" +
+	"\x175\xce.fR\xc4\xd3\x04T\xe62L\xe0\"k" +
+	"\x1b\xb3\x17\x00(3\x04T\xcec\x18\xcdj\xbaIN" +
+	"-`*\xcb\xf3\\\xe7\x88\xc0\x10=\xab\xa0\xb9J\x9a" +
+	"\x0f\xb7\xa6y)_\xf1Q\xbe\xc0\xa6\\f\x98*s" +
+	"m(\xaf;\x9b\x1b\xef \xb3\xa4+u\xc5\xe5KW" +
+	"h}\xe4\xe1\xb2\xaa\x07)\xccZ\x00\xd2L\xc0L\x8c" +
+	"1l@\xc30\xc3\x94jY\x1b@&D@\x9c\x00" +
+	"\xf6\x89a2.5\xb0\x0e\x80L\x0d\x012\x01\xc2)" +
+	"\xc3d]J\xb04@&N\xc0\xff\x11\x10\xfa\xb7!" +
+	"c\x88\xf4\xcb\x04\xa6\x100\x8d\x80\xf0\xbf\x0c\x19\xc3$" +
+	"gl5@\xe6,\x02\xce% r\xd2\x901\x02 " +
+	"\xcdd_\x03\xc8\xcc \xe0<\x02\xa2'\x0c\xd9\xea!" +
+	"\xac\x0c\x90\x99K\xc0\x85\x04\xd4|l\xc8XC\x92k" +
+	"\xba\x9aO\xc0R\x02j\x8f\x1b2\xd6R\xdbe\xf7\x00" +
+	"d\x96\x12\xd0M@\xdd?\x0d\x19\xeb\x00\xa4\x15\xec\x16" +
+	"\x80L7\x01\xd7\x13P\x7f\xcc\x90\xb1\x9et\x93]\x02" +
+	"\x90\xb9\x86\x80,\x01\xb1\x8f\x0c\x19c\x00\x92j.~" +
+	"\x03\x01y\x02\x1a>4dl\x00\x90rf\xb8\xfd\x04" +
+	"\xe8\x044\x1e5dl\x04\x90\x06\xd9\x1a\x80L\x89\x80" +
+	"\x9b\x09\x10\x8f\x182\x8a\x00R\x85\xdd\x04\x90\x19!`" +
+	"\x13\x01\xf1\x0f\x0c\x19\xe3\x00\xd2F\xd3\xd5\x06\x02\xb6\x10" +
+	"\x90\xf8\x87!c\x02@\xda\xcc\xee\x00\xc8l!\xe0n" +
+	"\x02\x92\x7f7dL\x02H\xdb\xd8^\x80\xcc\xdd\x04<" +
+	"\xc4\x18\x0a\xb9l\xb5\xa2Z\x87\x0a\x1a\xd7!\xb2\xbe\xa4" +
+	"\xea\xfdi>\x88q\xb7\x1b\x00b\x1c\xd0\xb0\x90R\x1e" +
+	"\xb0\x82qW\xc5lT\xd5\xac\xc3\x0eH\xffu4\xdc" +
+	"\x8fFKy\xfa\xb73\x7f\xd8x\x99\x0f_^\xd4s" +
+	"\xbd\x98\xebQ\xf5\\\xb1\x00\x18w\xe7\x03\xdb&\xd7k" +
+	"\xfbh\x1d\x1c\xe2\x9a\x8eqwJ\xf3[\xd8\xab8\x12" +
+	"n\xe3\x1a/\x0f\xe7zx\x17zd\x09\xe3\xee\x10\x10" +
+	"hV\xcaW\x80\xc2q\xd4\xd5\x0d\xd9\x06\x09uf5" +
+	"\xc7G\xdf\xcaJ\x89/\x87\xd6b\xc9\xa2\xd3i\x81>" +
+	"\x0b,\x96,?\x18w;\xbbmS\xb0\xf5\x02\xc4\x8a" +
+	"\xe5\xc4\x19\x1f\xfd\x06\xa9\x8a\xe9\x04\xe3\xee\xfc\xe3I[" +
+	"'\xe7Y\\\xad\xf6\xdc\x98\xe6\x83\xb4\x8e3\x15\x04\xdb" +
+	"\x94\xf2f\x8a\x9da\xce\xb6\xd2\x8b\xa5\xe2\x92~\xb5\x80" +
+	"}\xdcLV.e%\x0b\xe3n\x1f\xaenn\x9c\xec" +
+	"-\xcet\xb9T\xfaD\xa9\xc3\xed\x03\xebyA/\xe7" +
+	"\xbc\xea\xea\xf4\x1eK]}nI\xaa\xbb,U\x16z" +
+	"\xb8ON\xdb\xbcrjw\x85\xd9\xb3\\9m\xcdi" +
+	"YU\xab\x96\xbfH-\xa8\xfa%`\x19\"g\xb5\xda" +
+	"\x93\xba\xf13\xea*\xab\x0a\xb3E\\\x8f*\x12q>" +
+	"?\x97\x00(1\x01\x95)\x0c\x0d-\xcd\x87\x894\xab" +
+	"\xba\xd2\x7f=\xf9\xc5\xd1\x8b\xdb\xbe\x17Lo\xb7ud" +
+	"\xe7\xf4\xe6U\xa1OS\xa6\x08\xa1\xf8\xedVs\xfb." +
+	"\xb1{\x97\x80\xcavba\xab)\xb1\x89\xfb)\xde\xbb" +
+	"\x05T\x1e\xa2\xde(\x99*\x9a\xd8A\x8bo\x17Py" +
+	"\x9czc\x9d\xa9\xa0\x89\x9de\x00\xe51\x01\x95=\x0c" +
+	"\x13\xa1fS=\x13\xbb\xd6\x00(O\x0a\xa8<\xc30" +
+	"\x11\xbe\xc3T\xce\xc4\xd3\xd4Z\x7f\"\xa0\xb2\x8f\xe1\xfa" +
+	"2\xef-s\xad\xbf\xda\x9dR\xfd\xb9l\x96\x17\xaa_" +
+	"\x8db9\xcb\xcb\xb9B\x1f\x00`\xc4\xfem@\x1dY" +
+	"\xa2\x16\xb29h\xcd\xaa:\xd7\x1c\xfe\xb29mM1" +
+	"W\xd0A,pMs\xcc\xd5|\xbe\xb86\xa3\xab " +
+	"\xe4'6\xc1\x09\xad6j\xa7\xccS\x1d\x1dvu," +
+	"rG\x86\x85i\x00\xe5B\x01\x95\xe5A\x95\xe8L\xf3" +
+	"v%\xf2r\xb9X^R\xcc\x02\xf2\x09\xc9\x16\xac&" +
+	"j\xabHUk4\xdd_\xfak\xec|\xcf`\x8e\xe6" +
+	"\xac\x04\xb1Rr\xd7\x15\x0d\xbd\xefw\xff;sv\xfa" +
+	"\xa0\xff\x04T\xd7\xb04\xc4\x96\x90e\x05\xbd\x8c\xe6V" +
+	"c\xce*\xcb(3K\x05Tnp\xb7\xba*\xedN" +
+	"L\xcet\xc4;\xdc\x91\xe9\xd3\x0d;\x86\x9e\x1b\xe0\x9a" +
+	"\xae\x0e\x00\x96\xaa\x03\xcf\xa4\x03\x90\xe0\xcf\x8a\x1d\xaf\xad" +
+	"\x08\x9e\x80i\x10Z$\xa0r\x99\xe7\xe4vM\xb5w" +
+	"\xd1M\x11\x87\xac\x88W\xd06.\x13P\xb9\x86aT" +
+	"+\xf7T\xcf\xafwRr\x15\x12+\x13b\xb2\xa6\x9d" +
+	"\xe5E\xadU\xa74\xf9jd\x96\xab \xf4qG\xe6" +
+	"\xc4\xec6`b\xa9Xv\xcey\xab\x9a\xcd\x965_" +
+	"\x05z\x92#\x06\xa8\xc6i\x85\xcf\xb9jN*|\xb6" +
+	"\"\x89$\xe9>\x02;\\\x02\x83\xf9\xb33\xbe\x82v" +
+	"\xb8\\@e%C\xb3\xef_\xca+\xce\xe8[\xd6\xf5" +
+	"*_b\xbe\xa8i\x93\x90\xd7\xad\xeab\xbf\x1d\x82\xec" +
+	"\x84\xb0\x8e\x96\x1b\xb1'\xedj\x0c\x1b\xa7\xba\x93v\x82" +
+	"\xd5X1\x8c\x92\xeel\x12P\xb9\x9d!\x0a\xe8\xb9\xe9" +
+	"'nm\x03\x86!s\xfaK\x0c\x91f\x95\x04T\xb6" +
+	"\x8c\x1f\x84\xbdi'\x11!f4\x00p\x12\xd3\x9bW" +
+	"\xfb\xb4T\x7fiIo\x9f\x87\xde)\xcb\xde\xbcH\xfa" +
+	"\xc5\xd9{'\xa7\xd7\xae\xcf\xa8^\xaeL^\x16nc" +
+	"\xa1]\x9c+\xa02\x9f\xa1HLb\xdc}-dk" +
+	"v\x7fQ\xd3]Ewn\\\x81\x8a\xee)\x1d\x81\x0f" +
+	"\xfa\x0ag\x96\xdbnD\xbdR\xe2(\x1a\x1b\xe6?P" +
+	"\xc7w\x1e\xdf\x01\x80(N\xc8\xd1\xe2LW\xcaR\xa1" +
+	"I\xee`\xb2\xbf\x1d\xfaN\xedJ\xbb\xf1W\xfb~\x8f" +
+	"\xaaG'\xb6\xaf\xb4\xa7}\x05(\xc3\xe9\xe4\xd1\xd2\x02" +
+	"\xa1\\\xf1\xdd\xecV{nqU\xaesTFY\x01" +
+	"\x95\x12CdV\x15\x0d\xd0\xday\xbb\xb4\xaa7\xbb\xd1" +
+	"\xb4]Z\xdb\xbd\xfa\x1a\xb5\x18\xf3\xea\xaa\x08\x18\xd5\xf5" +
+	"\xbc\x13\xa8\x93)\xf4\x94\x8c7a\x8d\xe6uP\xd3\xd5" +
+	"B\x0f\x1fg\xe4\xdc\xd6\x03\xeb\xaa\xab\xf33\x8eA\xce" +
+	"\xeb\x85\xff\xe6\xb6\xd5T\xd4\xd3\xe9X@\xbd\x8e\x1b}" +
+	">]\x95:g?\xd5\xef\x0cD\x9e\xb4\xa5\xdd\xb49" +
+	"Y\xeb\xb0\xb3\xb6\x81\x0e\x7f\xdcJ\xdb\xba6\x8fL8" +
+	"\x17r\xfa\xf7\x06\xeb\xa8\x07\xb5\xd9\x89\xf48/\xa2," +
+	"zZ5]\xf5\x8e\x05\xf4\xad\xc05JT\xf0\xad\xdc" +
+	")EM\x17)\xa5>9m\x09\xeaG\xb3\xbcz\xca" +
+	"&\xea)]\xadb\xc0\x8851W\x1a>o\"\x99" +
+	"\xf4\xf3\x17>\x85\x128\xc7$\xf0N\x7f\xda\xbaq^" +
+	"x\x05\xd6\xcdr;\xd5s\xd4l4[\xd6\xac\x92\x91" +
+	"\xd1_3\xe6\x9e\x99oz\xb6\xf6d\xf7\x0ak'\xf6" +
+	"\x97\xc9G\x7f\xb78=\xe4\xb6y\xc9\x0d\x055\xabn" +
+	"\x9b\xdc\x05.\xb9\xe3\xc5\xca\xfb\x96&\x95\xd3\x96\x14\xcb" +
+	"N\xf2\xff3\x00|\x15\xc3\xbd"
 
 func init() {
 	schemas.Register(schema_8f4bd412642c9517,
 		0x877af4eba6adb0f3,
 		0x8adfcabe5ff9daf4,
+		0x8c745e24d32419fd,
 		0x8f8172e4469c111a,
 		0x91ea9bb47f46c346,
 		0x947e1828e214e89d,
 		0x95794035a80b7da1,
+		0x9a6be708302bcadd,
 		0x9b0685a785df42e9,
 		0x9bce05e1e88ad9da,
+		0xa7f75dfd14b1cda2,
 		0xa94f085c31a03112,
 		0xacf8185a51a9f1b4,
+		0xb156f55bfea7787c,
 		0xb21a270577932520,
 		0xc340ede57616f2e8,
+		0xc4b2a48f218903cb,
 		0xc4c61531dcc4a3eb,
 		0xc5ff2e54709776ec,
 		0xca1e844241cf650f,
 		0xcc65a2a89c24e6a5,
+		0xe0f58596da339444,
 		0xe7279389a6bbe1dc,
 		0xe7f7d11a5652e06c,
 		0xf0c5156786d72738,
 		0xf10fe9b6293ee63f,
+		0xf30abc511658c2b2,
 		0xf7a6d78ba978beb9,
 		0xf9e52567abde1a0c,
 		0xfab1a3b4477ab6b3)
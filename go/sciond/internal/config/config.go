@@ -19,9 +19,11 @@ import (
 	"io"
 	"time"
 
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/config"
 	"github.com/scionproto/scion/go/lib/env"
+	"github.com/scionproto/scion/go/lib/hostinfo"
 	"github.com/scionproto/scion/go/lib/infra/modules/idiscovery"
 	"github.com/scionproto/scion/go/lib/pathstorage"
 	"github.com/scionproto/scion/go/lib/sciond"
@@ -32,7 +34,9 @@ import (
 )
 
 var (
-	DefaultQueryInterval = 5 * time.Minute
+	DefaultQueryInterval   = 5 * time.Minute
+	DefaultPathFeedbackTTL = 5 * time.Minute
+	DefaultWatchInterval   = time.Minute
 )
 
 var _ config.Config = (*Config)(nil)
@@ -68,6 +72,7 @@ func (cfg *Config) Validate() error {
 		&cfg.Features,
 		&cfg.Logging,
 		&cfg.Metrics,
+		&cfg.QUIC,
 		&cfg.TrustDB,
 		&cfg.Discovery,
 		&cfg.SD,
@@ -92,6 +97,11 @@ func (cfg *Config) ConfigName() string {
 	return "sd_config"
 }
 
+// CheckFiles checks that the files referenced by the config can be loaded.
+func (cfg *Config) CheckFiles() error {
+	return env.CheckFilesAll(&cfg.General)
+}
+
 var _ config.Config = (*SDConfig)(nil)
 
 type SDConfig struct {
@@ -118,6 +128,48 @@ type SDConfig struct {
 	// QueryInterval specifies after how much time segments
 	// for a destination should be refetched.
 	QueryInterval util.DurWrap
+	// PathOrdering is the criterion by which paths are ordered if the path
+	// request does not specify one.
+	PathOrdering sciond.PathOrdering
+	// MaxPathCandidates is the maximum number of non-revoked paths, in the
+	// configured ordering, from which a reply is selected if the path request
+	// does not specify a limit. Revocations are only checked until enough
+	// candidates are found. If it is 0, all paths are candidates.
+	MaxPathCandidates int
+	// DisablePathFeedback disables the ranking of paths based on the
+	// performance feedback reported by applications.
+	DisablePathFeedback bool
+	// PathFeedbackTTL is the time after which the feedback for a path is
+	// discarded if it is not refreshed.
+	PathFeedbackTTL util.DurWrap
+	// AccessLog is the path of the file to which a JSON line is appended for
+	// every API request. If empty, no access log is written.
+	AccessLog string
+	// JSONRPC is the address of a UNIX stream socket on which the API is
+	// additionally served as JSON-RPC. If empty, the JSON-RPC endpoint is
+	// disabled.
+	JSONRPC string
+	// AddressFamily is the address family of the border router and service
+	// addresses in replies, if they are reachable over both IPv4 and IPv6. By
+	// default, both addresses are conveyed, and clients use the IPv4 address.
+	AddressFamily hostinfo.Preference
+	// VerificationWorkers is the number of workers that verify fetched
+	// segments. If it is 0, one worker per CPU is used.
+	VerificationWorkers int
+	// VerificationQueueSize is the number of segments that can wait for
+	// verification before fetching further segments is blocked. If it is 0,
+	// segverifier.DefaultPoolQueueSize is used.
+	VerificationQueueSize int
+	// WatchList contains the destinations for which the quality of the paths
+	// is periodically exported as metrics.
+	WatchList []addr.IA
+	// WatchInterval is the interval at which the paths to the destinations
+	// in the watch list are checked.
+	WatchInterval util.DurWrap
+	// HiddenPathServers are the hidden path servers to which hidden path
+	// lookups are forwarded. The servers are tried in order until one
+	// answers. If empty, hidden path lookups fail.
+	HiddenPathServers []*snet.Addr
 }
 
 func (cfg *SDConfig) InitDefaults() {
@@ -133,6 +185,15 @@ func (cfg *SDConfig) InitDefaults() {
 	if cfg.QueryInterval.Duration == 0 {
 		cfg.QueryInterval.Duration = DefaultQueryInterval
 	}
+	if cfg.PathOrdering == sciond.PathOrderingDefault {
+		cfg.PathOrdering = sciond.PathOrderingHops
+	}
+	if cfg.PathFeedbackTTL.Duration == 0 {
+		cfg.PathFeedbackTTL.Duration = DefaultPathFeedbackTTL
+	}
+	if cfg.WatchInterval.Duration == 0 {
+		cfg.WatchInterval.Duration = DefaultWatchInterval
+	}
 	config.InitAll(&cfg.PathDB, &cfg.RevCache)
 }
 
@@ -149,6 +210,31 @@ func (cfg *SDConfig) Validate() error {
 	if cfg.QueryInterval.Duration == 0 {
 		return serrors.New("QueryInterval must not be zero")
 	}
+	if cfg.MaxPathCandidates < 0 {
+		return serrors.New("MaxPathCandidates must not be negative")
+	}
+	if cfg.PathFeedbackTTL.Duration <= 0 {
+		return serrors.New("PathFeedbackTTL must be positive")
+	}
+	if cfg.VerificationWorkers < 0 {
+		return serrors.New("VerificationWorkers must not be negative")
+	}
+	if cfg.VerificationQueueSize < 0 {
+		return serrors.New("VerificationQueueSize must not be negative")
+	}
+	if cfg.WatchInterval.Duration <= 0 {
+		return serrors.New("WatchInterval must be positive")
+	}
+	for _, ia := range cfg.WatchList {
+		if ia.I == 0 || ia.A == 0 {
+			return serrors.New("WatchList must not contain wildcard ISD-AS", "ia", ia)
+		}
+	}
+	for _, a := range cfg.HiddenPathServers {
+		if a == nil || a.Host == nil || a.IA.IsZero() {
+			return serrors.New("HiddenPathServers must contain full addresses", "addr", a)
+		}
+	}
 	return config.ValidateAll(&cfg.PathDB, &cfg.RevCache)
 }
 
@@ -168,5 +254,10 @@ func (cfg *SDConfig) CreateSocketDirs() error {
 	if err := util.CreateParentDirs(cfg.Unix); err != nil {
 		return common.NewBasicError("Cannot create unix socket dir", err)
 	}
+	if cfg.JSONRPC != "" {
+		if err := util.CreateParentDirs(cfg.JSONRPC); err != nil {
+			return common.NewBasicError("Cannot create JSON-RPC socket dir", err)
+		}
+	}
 	return nil
 }
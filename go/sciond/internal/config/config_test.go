@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/scionproto/scion/go/lib/env/envtest"
+	"github.com/scionproto/scion/go/lib/hostinfo"
 	"github.com/scionproto/scion/go/lib/infra/modules/idiscovery/idiscoverytest"
 	"github.com/scionproto/scion/go/lib/pathstorage/pathstoragetest"
 	"github.com/scionproto/scion/go/lib/sciond"
@@ -49,6 +50,8 @@ func InitTestConfig(cfg *Config) {
 
 func InitTestSDConfig(cfg *SDConfig) {
 	cfg.DeleteSocket = true
+	cfg.AccessLog = "test"
+	cfg.JSONRPC = "test"
 	pathstoragetest.InitTestPathDBConf(&cfg.PathDB)
 	pathstoragetest.InitTestRevCacheConf(&cfg.RevCache)
 }
@@ -68,5 +71,17 @@ func CheckTestSDConfig(t *testing.T, cfg *SDConfig, id string) {
 	assert.Equal(t, sciond.DefaultSocketFileMode, int(cfg.SocketFileMode))
 	assert.Equal(t, "1-ff00:0:110,[127.0.0.1]:0 (UDP)", cfg.Public.String())
 	assert.Equal(t, DefaultQueryInterval, cfg.QueryInterval.Duration)
+	assert.Equal(t, sciond.PathOrderingHops, cfg.PathOrdering)
+	assert.Equal(t, 0, cfg.MaxPathCandidates)
+	assert.False(t, cfg.DisablePathFeedback)
+	assert.Equal(t, DefaultPathFeedbackTTL, cfg.PathFeedbackTTL.Duration)
 	assert.False(t, cfg.DeleteSocket)
+	assert.Empty(t, cfg.AccessLog)
+	assert.Empty(t, cfg.JSONRPC)
+	assert.Equal(t, hostinfo.PreferAny, cfg.AddressFamily)
+	assert.Equal(t, 0, cfg.VerificationWorkers)
+	assert.Equal(t, 0, cfg.VerificationQueueSize)
+	assert.Empty(t, cfg.WatchList)
+	assert.Equal(t, DefaultWatchInterval, cfg.WatchInterval.Duration)
+	assert.Empty(t, cfg.HiddenPathServers)
 }
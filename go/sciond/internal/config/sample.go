@@ -41,4 +41,68 @@ Public = "1-ff00:0:110,[127.0.0.1]:0"
 
 # The time after which segments for a destination are refetched. (default 5m)
 QueryInterval = "5m"
+
+# The criterion by which paths are ordered if the path request does not
+# specify one: one of hops, expiry or mtu. Ties are broken deterministically.
+# (default hops)
+PathOrdering = "hops"
+
+# The maximum number of non-revoked paths, in the configured ordering, from
+# which a reply is selected if the path request does not specify a limit.
+# Revocations are only checked until enough candidates are found. 0 means no
+# limit. (default 0)
+MaxPathCandidates = 0
+
+# Disables the ranking of paths based on the round trip time and loss rate
+# reported by applications. If enabled, paths for which applications reported
+# a good performance are returned first, unless the path request specifies an
+# ordering. (default false)
+DisablePathFeedback = false
+
+# The time after which the feedback reported for a path is discarded if it is
+# not refreshed. (default 5m)
+PathFeedbackTTL = "5m"
+
+# The file to which a JSON line is appended for every API request, recording
+# the client, request type, parameters, result and latency. If empty, no access
+# log is written. (default "")
+AccessLog = ""
+
+# The address of a UNIX stream socket on which the API is additionally served
+# as JSON-RPC 2.0, with one JSON object per line. This allows tools written in
+# other languages to query SCIOND without a Cap'n Proto toolchain. If empty,
+# the JSON-RPC endpoint is disabled. (default "")
+JSONRPC = ""
+
+# The address family of the border router and service addresses in replies, if
+# they are reachable over both IPv4 and IPv6: one of any, ipv4 or ipv6. With
+# any, both addresses are conveyed and clients use the IPv4 address. Set ipv6
+# to make clients use the IPv6 overlay. (default any)
+AddressFamily = "any"
+
+# The number of workers that verify fetched segments. Bounding the number of
+# workers prevents a burst of path requests from using all CPUs. 0 means one
+# worker per CPU. (default 0)
+VerificationWorkers = 0
+
+# The number of segments that can wait for verification. If the queue is full,
+# fetching further segments is blocked until segments have been verified. 0
+# means the default size of 256. (default 0)
+VerificationQueueSize = 0
+
+# The destinations for which the number of usable paths, the number of AS hops
+# of the shortest path and the earliest path expiration are exported as
+# metrics, e.g., to alert if there are too few paths to an important AS.
+# (default [])
+# WatchList = ["1-ff00:0:111", "2-ff00:0:210"]
+
+# The interval at which the paths to the destinations in the watch list are
+# checked. (default 1m)
+WatchInterval = "1m"
+
+# The hidden path servers to which hidden path lookups are forwarded, i.e.,
+# path requests with the hidden flag set. The hidden segments of the requested
+# groups are fetched from the first server that answers. If empty, hidden path
+# lookups fail. (default [])
+# HiddenPathServers = ["1-ff00:0:110,[127.0.0.1]:30100"]
 `
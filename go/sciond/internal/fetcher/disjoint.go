@@ -0,0 +1,91 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetcher
+
+import (
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/infra/modules/combinator"
+	"github.com/scionproto/scion/go/lib/sciond"
+)
+
+// SelectDisjoint reorders paths such that each path overlaps as little as
+// possible with the paths before it, according to disjointness. Thus, the first
+// n paths form a maximally disjoint subset of size n, as far as a greedy
+// selection finds it. Paths with equal overlap keep their relative order, so
+// the first path is never moved. DisjointNone leaves paths unchanged.
+func SelectDisjoint(paths []*combinator.Path, disjointness sciond.PathDisjointness) {
+	if disjointness == sciond.DisjointNone || len(paths) < 3 {
+		return
+	}
+	elems := make([][]interface{}, len(paths))
+	for i, path := range paths {
+		elems[i] = disjointElems(path, disjointness)
+	}
+	used := make(map[interface{}]struct{})
+	for i := range paths {
+		best, bestOverlap := i, -1
+		for j := i; j < len(paths); j++ {
+			overlap := 0
+			for _, e := range elems[j] {
+				if _, ok := used[e]; ok {
+					overlap++
+				}
+			}
+			if bestOverlap == -1 || overlap < bestOverlap {
+				best, bestOverlap = j, overlap
+			}
+			if overlap == 0 {
+				break
+			}
+		}
+		// Move the best path to position i, keeping the order of the others.
+		path, pathElems := paths[best], elems[best]
+		copy(paths[i+1:best+1], paths[i:best])
+		copy(elems[i+1:best+1], elems[i:best])
+		paths[i], elems[i] = path, pathElems
+		for _, e := range pathElems {
+			used[e] = struct{}{}
+		}
+	}
+}
+
+// disjointElems returns the elements of path that should not be shared with
+// other paths.
+func disjointElems(path *combinator.Path,
+	disjointness sciond.PathDisjointness) []interface{} {
+
+	ifaces := path.Interfaces
+	var elems []interface{}
+	switch disjointness {
+	case sciond.DisjointLink:
+		// Each interface belongs to exactly one link, so counting shared
+		// interfaces counts shared links.
+		for _, iface := range ifaces {
+			elems = append(elems, iface)
+		}
+	case sciond.DisjointAS:
+		// The interfaces in between the first and the last one belong to
+		// transit ASes, each transit AS appears with its ingress and egress
+		// interface.
+		var last addr.IAInt
+		for i := 1; i < len(ifaces)-1; i++ {
+			if ifaces[i].RawIsdas != last {
+				elems = append(elems, ifaces[i].RawIsdas)
+				last = ifaces[i].RawIsdas
+			}
+		}
+	}
+	return elems
+}
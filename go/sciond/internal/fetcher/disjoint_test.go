@@ -0,0 +1,74 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetcher_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/infra/modules/combinator"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/sciond/internal/fetcher"
+)
+
+func TestSelectDisjoint(t *testing.T) {
+	// All paths go from 1-ff00:0:111 to 1-ff00:0:110.
+	viaA1 := ifacePath(t, "1-ff00:0:111#1", "1-ff00:0:120#1", "1-ff00:0:120#2",
+		"1-ff00:0:110#1")
+	viaA2 := ifacePath(t, "1-ff00:0:111#2", "1-ff00:0:120#3", "1-ff00:0:120#4",
+		"1-ff00:0:110#2")
+	viaA1Again := ifacePath(t, "1-ff00:0:111#1", "1-ff00:0:120#1", "1-ff00:0:120#4",
+		"1-ff00:0:110#2")
+	viaB := ifacePath(t, "1-ff00:0:111#3", "1-ff00:0:130#1", "1-ff00:0:130#2",
+		"1-ff00:0:110#3")
+
+	tests := map[string]struct {
+		Disjointness sciond.PathDisjointness
+		Expected     []*combinator.Path
+	}{
+		"none": {
+			Disjointness: sciond.DisjointNone,
+			Expected:     []*combinator.Path{viaA1, viaA1Again, viaA2, viaB},
+		},
+		"link": {
+			Disjointness: sciond.DisjointLink,
+			Expected:     []*combinator.Path{viaA1, viaA2, viaB, viaA1Again},
+		},
+		"as": {
+			Disjointness: sciond.DisjointAS,
+			Expected:     []*combinator.Path{viaA1, viaB, viaA1Again, viaA2},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			paths := []*combinator.Path{viaA1, viaA1Again, viaA2, viaB}
+			fetcher.SelectDisjoint(paths, test.Disjointness)
+			assert.Equal(t, test.Expected, paths)
+		})
+	}
+}
+
+func ifacePath(t *testing.T, ifaces ...string) *combinator.Path {
+	t.Helper()
+	path := &combinator.Path{}
+	for _, str := range ifaces {
+		iface, err := sciond.NewPathInterface(str)
+		require.NoError(t, err)
+		path.Interfaces = append(path.Interfaces, iface)
+	}
+	return path
+}
@@ -0,0 +1,165 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetcher
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/infra/modules/combinator"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/spath/spathmeta"
+)
+
+const (
+	// FeedbackLossThreshold is the smoothed loss rate above which a path is
+	// ranked behind all paths without feedback.
+	FeedbackLossThreshold = 0.05
+	// feedbackWeight is the weight of a new sample in the moving averages.
+	feedbackWeight = 0.25
+)
+
+// PathKey returns the key of the path as used by the applications, see
+// snet.Path.Fingerprint.
+func PathKey(path *combinator.Path) spathmeta.PathKey {
+	ap := &spathmeta.AppPath{
+		Entry: &sciond.PathReplyEntry{
+			Path: &sciond.FwdPathMeta{Interfaces: path.Interfaces},
+		},
+	}
+	return ap.Key()
+}
+
+// PathFeedback stores the path performance reported by the local
+// applications. The round trip time and loss rate of each path are smoothed
+// with an exponentially weighted moving average. Feedback for a path expires
+// if it is not refreshed within the TTL, such that paths that were ranked down
+// are eventually tried again.
+type PathFeedback struct {
+	ttl       time.Duration
+	mtx       sync.Mutex
+	entries   map[spathmeta.PathKey]*feedbackEntry
+	lastPrune time.Time
+}
+
+// NewPathFeedback creates an empty feedback store. Feedback for a path that is
+// not refreshed within ttl is discarded.
+func NewPathFeedback(ttl time.Duration) *PathFeedback {
+	return &PathFeedback{
+		ttl:     ttl,
+		entries: make(map[spathmeta.PathKey]*feedbackEntry),
+	}
+}
+
+// Record adds a sample for the path with the given key. A zero rtt means the
+// round trip time is unknown, in which case only the loss rate is updated.
+func (f *PathFeedback) Record(key spathmeta.PathKey, rtt time.Duration, loss float64,
+	now time.Time) {
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.prune(now)
+	e, ok := f.entries[key]
+	if !ok || e.expired(now, f.ttl) {
+		e = &feedbackEntry{loss: loss}
+		f.entries[key] = e
+	} else {
+		e.loss += feedbackWeight * (loss - e.loss)
+	}
+	if rtt > 0 {
+		if e.rtt == 0 {
+			e.rtt = rtt
+		} else {
+			e.rtt += time.Duration(feedbackWeight * float64(rtt-e.rtt))
+		}
+	}
+	e.updated = now
+}
+
+// Rank reorders paths based on the recorded feedback. Paths with a known
+// round trip time and a loss rate below FeedbackLossThreshold are moved to the
+// front, ordered by round trip time. Paths with a loss rate above the
+// threshold are moved to the back. All other paths, and paths that compare
+// equal, keep their relative order.
+func (f *PathFeedback) Rank(paths []*combinator.Path, now time.Time) {
+	f.mtx.Lock()
+	entries := make([]rankEntry, 0, len(paths))
+	for _, path := range paths {
+		re := rankEntry{path: path, class: rankUnknown}
+		if e, ok := f.entries[PathKey(path)]; ok && !e.expired(now, f.ttl) {
+			re.class, re.rtt = e.class(), e.rtt
+		}
+		entries = append(entries, re)
+	}
+	f.mtx.Unlock()
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].class != entries[j].class {
+			return entries[i].class < entries[j].class
+		}
+		return entries[i].class == rankGood && entries[i].rtt < entries[j].rtt
+	})
+	for i := range entries {
+		paths[i] = entries[i].path
+	}
+}
+
+// prune removes expired entries, at most once per TTL.
+func (f *PathFeedback) prune(now time.Time) {
+	if now.Sub(f.lastPrune) < f.ttl {
+		return
+	}
+	for key, e := range f.entries {
+		if e.expired(now, f.ttl) {
+			delete(f.entries, key)
+		}
+	}
+	f.lastPrune = now
+}
+
+type rankClass int
+
+const (
+	rankGood rankClass = iota
+	rankUnknown
+	rankLossy
+)
+
+type rankEntry struct {
+	path  *combinator.Path
+	class rankClass
+	rtt   time.Duration
+}
+
+type feedbackEntry struct {
+	rtt     time.Duration
+	loss    float64
+	updated time.Time
+}
+
+func (e *feedbackEntry) expired(now time.Time, ttl time.Duration) bool {
+	return now.Sub(e.updated) > ttl
+}
+
+func (e *feedbackEntry) class() rankClass {
+	switch {
+	case e.loss > FeedbackLossThreshold:
+		return rankLossy
+	case e.rtt > 0:
+		return rankGood
+	default:
+		return rankUnknown
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetcher_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/infra/modules/combinator"
+	"github.com/scionproto/scion/go/sciond/internal/fetcher"
+)
+
+func TestPathFeedbackRank(t *testing.T) {
+	now := time.Now()
+	a := testPath(2, 1000, 1280, 1)
+	b := testPath(2, 1000, 1280, 2)
+	c := testPath(2, 1000, 1280, 3)
+	d := testPath(2, 1000, 1280, 4)
+
+	type sample struct {
+		Path *combinator.Path
+		RTT  time.Duration
+		Loss float64
+		Age  time.Duration
+	}
+	tests := map[string]struct {
+		Samples  []sample
+		Expected []*combinator.Path
+	}{
+		"no feedback keeps order": {
+			Expected: []*combinator.Path{a, b, c, d},
+		},
+		"measured paths first by rtt": {
+			Samples: []sample{
+				{Path: c, RTT: 20 * time.Millisecond},
+				{Path: d, RTT: 10 * time.Millisecond},
+			},
+			Expected: []*combinator.Path{d, c, a, b},
+		},
+		"lossy paths last": {
+			Samples: []sample{
+				{Path: a, RTT: 10 * time.Millisecond, Loss: 0.5},
+				{Path: c, Loss: 0.01},
+			},
+			Expected: []*combinator.Path{b, c, d, a},
+		},
+		"rtt is smoothed": {
+			Samples: []sample{
+				{Path: a, RTT: 10 * time.Millisecond},
+				{Path: a, RTT: 50 * time.Millisecond},
+				{Path: b, RTT: 25 * time.Millisecond},
+			},
+			Expected: []*combinator.Path{a, b, c, d},
+		},
+		"loss is smoothed": {
+			Samples: []sample{
+				{Path: a, RTT: 10 * time.Millisecond},
+				{Path: a, RTT: 10 * time.Millisecond, Loss: 0.1},
+			},
+			Expected: []*combinator.Path{a, b, c, d},
+		},
+		"expired feedback is ignored": {
+			Samples: []sample{
+				{Path: a, RTT: 10 * time.Millisecond, Loss: 0.5},
+				{Path: d, RTT: 10 * time.Millisecond, Age: 2 * time.Minute},
+			},
+			Expected: []*combinator.Path{b, c, d, a},
+		},
+		"expired feedback is replaced": {
+			Samples: []sample{
+				{Path: a, Loss: 1, Age: 2 * time.Minute},
+				{Path: a, RTT: 10 * time.Millisecond},
+			},
+			Expected: []*combinator.Path{a, b, c, d},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			feedback := fetcher.NewPathFeedback(time.Minute)
+			for _, s := range test.Samples {
+				feedback.Record(fetcher.PathKey(s.Path), s.RTT, s.Loss, now.Add(-s.Age))
+			}
+			paths := []*combinator.Path{a, b, c, d}
+			feedback.Rank(paths, now)
+			assert.Equal(t, test.Expected, paths)
+		})
+	}
+}
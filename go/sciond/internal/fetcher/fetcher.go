@@ -29,6 +29,8 @@ import (
 	"github.com/scionproto/scion/go/lib/infra"
 	"github.com/scionproto/scion/go/lib/infra/modules/combinator"
 	"github.com/scionproto/scion/go/lib/infra/modules/segfetcher"
+	"github.com/scionproto/scion/go/lib/infra/modules/seghandler"
+	"github.com/scionproto/scion/go/lib/infra/modules/segverifier"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/pathdb"
 	"github.com/scionproto/scion/go/lib/pathdb/query"
@@ -44,6 +46,9 @@ import (
 
 const (
 	DefaultMinWorkerLifetime = 10 * time.Second
+	// staleLookupTimeout is the time allocated to loading cached segments if
+	// fetching them failed.
+	staleLookupTimeout = time.Second
 )
 
 type TrustStore interface {
@@ -57,18 +62,30 @@ type Fetcher struct {
 	topoProvider    topology.Provider
 	config          config.SDConfig
 	segfetcher      *segfetcher.Fetcher
+	hidden          *hiddenSegFetcher
+	feedback        *PathFeedback
 }
 
 func NewFetcher(messenger infra.Messenger, pathDB pathdb.PathDB, trustStore TrustStore,
 	revCache revcache.RevCache, cfg config.SDConfig, topoProvider topology.Provider,
-	logger log.Logger) *Fetcher {
+	feedback *PathFeedback, logger log.Logger) *Fetcher {
 
 	localIA := topoProvider.Get().ISD_AS
+	verificationPool := segverifier.NewPool(cfg.VerificationWorkers, cfg.VerificationQueueSize)
 	return &Fetcher{
 		pathDB:          pathDB,
 		revocationCache: revCache,
 		topoProvider:    topoProvider,
 		config:          cfg,
+		feedback:        feedback,
+		hidden: &hiddenSegFetcher{
+			requester: messenger,
+			servers:   cfg.HiddenPathServers,
+			verifier: &seghandler.DefaultVerifier{
+				Verifier: trustStore.NewVerifier(),
+				Pool:     verificationPool,
+			},
+		},
 		segfetcher: segfetcher.FetcherConfig{
 			QueryInterval:       cfg.QueryInterval.Duration,
 			LocalIA:             localIA,
@@ -80,6 +97,7 @@ func NewFetcher(messenger infra.Messenger, pathDB pathdb.PathDB, trustStore Trus
 			DstProvider:         &dstProvider{IA: localIA},
 			Splitter:            NewRequestSplitter(localIA, trustStore),
 			SciondMode:          true,
+			VerificationPool:    verificationPool,
 		}.New(),
 	}
 }
@@ -107,6 +125,10 @@ type fetcherHandler struct {
 // to build paths at start, after earlyReplyInterval and at context expiration
 // (or whenever all background workers return). An earlyReplyInterval of 0
 // means no early reply attempt is made.
+//
+// If fetching the segments fails and the request allows stale answers, the
+// paths are built from the cached segments instead, and the reply is marked as
+// stale.
 func (f *fetcherHandler) GetPaths(ctx context.Context, req *sciond.PathReq,
 	earlyReplyInterval time.Duration) (*sciond.PathReply, error) {
 
@@ -149,17 +171,80 @@ func (f *fetcherHandler) GetPaths(ctx context.Context, req *sciond.PathReq,
 	// which will forward the query to a ISD-local core PS, so there won't be
 	// any loop.
 
-	segs, err := f.segfetcher.FetchSegs(ctx,
-		segfetcher.Request{Src: req.Src.IA(), Dst: req.Dst.IA()})
-	if err != nil {
-		return f.buildSCIONDReply(nil, 0, sciond.ErrorInternal), err
+	var hiddenSegs seg.Segments
+	if req.Flags.Hidden {
+		var err error
+		if hiddenSegs, err = f.hidden.FetchSegs(ctx, req.Dst.IA(), req.HPCfgs); err != nil {
+			return f.buildSCIONDReply(nil, 0, sciond.ErrorInternal),
+				serrors.WrapStr("fetching hidden segments failed", err)
+		}
 	}
-	paths := f.buildPathsToAllDsts(req, segs.Up, segs.Core, segs.Down)
-	paths, err = f.filterRevokedPaths(ctx, paths)
+	segReq := segfetcher.Request{Src: req.Src.IA(), Dst: req.Dst.IA()}
+	segs, err := f.segfetcher.FetchSegs(ctx, segReq)
+	var stale bool
+	var staleness time.Duration
 	if err != nil {
+		if !req.Flags.AllowStale {
+			return f.buildSCIONDReply(nil, 0, sciond.ErrorInternal), err
+		}
+		// Fetching usually fails because ctx expired, so the cache lookup
+		// gets its own deadline.
+		cacheCtx, cancelF := context.WithTimeout(
+			log.CtxWith(context.Background(), f.logger), staleLookupTimeout)
+		defer cancelF()
+		var cacheErr error
+		segs, staleness, cacheErr = f.segfetcher.CachedSegs(cacheCtx, segReq)
+		if cacheErr != nil {
+			f.logger.Error("Failed to load cached segments", "err", cacheErr)
+			return f.buildSCIONDReply(nil, 0, sciond.ErrorInternal), err
+		}
+		f.logger.Info("Failed to fetch segments, answering from cache",
+			"err", err, "staleness", staleness)
+		ctx, stale = cacheCtx, true
+	}
+	// Hidden segments are down segments, they are combined with the public
+	// up and core segments.
+	downs := append(append(seg.Segments(nil), segs.Down...), hiddenSegs...)
+	paths := f.buildPathsToAllDsts(req, segs.Up, segs.Core, downs)
+	SortPaths(paths, f.pathOrdering(req))
+	paths, filterErr := f.filterRevokedPaths(ctx, paths, f.maxPathCandidates(req))
+	if filterErr != nil {
+		return f.buildSCIONDReply(nil, 0, sciond.ErrorInternal), filterErr
+	}
+	if stale && len(paths) == 0 {
+		// An empty stale reply is not better than the failure.
 		return f.buildSCIONDReply(nil, 0, sciond.ErrorInternal), err
 	}
-	return f.buildSCIONDReply(paths, req.MaxPaths, sciond.ErrorOk), nil
+	// Feedback only overrides the configured ordering, an explicitly
+	// requested ordering is kept.
+	if f.feedback != nil && req.Flags.Ordering == sciond.PathOrderingDefault {
+		f.feedback.Rank(paths, time.Now())
+	}
+	SelectDisjoint(paths, req.Flags.Disjointness)
+	reply := f.buildSCIONDReply(paths, req.MaxPaths, sciond.ErrorOk)
+	if stale {
+		reply.Stale = true
+		reply.RawStaleness = uint32(staleness / time.Second)
+	}
+	return reply, nil
+}
+
+// pathOrdering returns the ordering requested in req, or the configured
+// ordering if the request does not specify one.
+func (f *fetcherHandler) pathOrdering(req *sciond.PathReq) sciond.PathOrdering {
+	if req.Flags.Ordering != sciond.PathOrderingDefault {
+		return req.Flags.Ordering
+	}
+	return f.config.PathOrdering
+}
+
+// maxPathCandidates returns the limit of path candidates requested in req, or
+// the configured limit if the request does not specify one.
+func (f *fetcherHandler) maxPathCandidates(req *sciond.PathReq) int {
+	if req.Flags.MaxCandidates != 0 {
+		return int(req.Flags.MaxCandidates)
+	}
+	return f.config.MaxPathCandidates
 }
 
 // buildSCIONDReply constructs a fresh SCIOND PathReply from the information
@@ -233,7 +318,8 @@ func (f *fetcherHandler) buildSCIONDReplyEntries(paths []*combinator.Path,
 				Interfaces: path.Interfaces,
 				ExpTime:    uint32(path.ComputeExpTime().Unix()),
 			},
-			HostInfo: hostinfo.FromTopoBRAddr(*ifInfo.InternalAddrs),
+			HostInfo: hostinfo.FromTopoBRAddrWithPreference(*ifInfo.InternalAddrs,
+				f.config.AddressFamily),
 		})
 		if maxPaths != 0 && len(entries) == int(maxPaths) {
 			break
@@ -244,9 +330,10 @@ func (f *fetcherHandler) buildSCIONDReplyEntries(paths []*combinator.Path,
 
 // filterRevokedPaths returns a new slice containing only those paths that do
 // not have revoked interfaces in their forwarding path. Only the interfaces
-// that have traffic going through them are checked.
+// that have traffic going through them are checked. If max is not 0, at most
+// max paths are returned, and the remaining paths are not checked.
 func (f *fetcherHandler) filterRevokedPaths(ctx context.Context,
-	paths []*combinator.Path) ([]*combinator.Path, error) {
+	paths []*combinator.Path, max int) ([]*combinator.Path, error) {
 
 	prevPaths := len(paths)
 	var newPaths []*combinator.Path
@@ -265,6 +352,9 @@ func (f *fetcherHandler) filterRevokedPaths(ctx context.Context,
 		if !revoked {
 			newPaths = append(newPaths, path)
 		}
+		if max != 0 && len(newPaths) == max {
+			break
+		}
 	}
 	f.logger.Trace("Filtered paths with revocations",
 		"paths", prevPaths, "nonrevoked", len(newPaths))
@@ -0,0 +1,120 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetcher
+
+import (
+	"context"
+	"net"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/infra/messenger"
+	"github.com/scionproto/scion/go/lib/infra/modules/seghandler"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/proto"
+)
+
+var (
+	// ErrNoHiddenPathServers indicates that a hidden path lookup was requested,
+	// but no hidden path servers are configured.
+	ErrNoHiddenPathServers = serrors.New("no hidden path servers configured")
+	// ErrNoHiddenPathGroups indicates that a hidden path lookup was requested
+	// without any hidden path group.
+	ErrNoHiddenPathGroups = serrors.New("no hidden path groups requested")
+)
+
+// hiddenSegRequester requests hidden segments from a hidden path server.
+type hiddenSegRequester interface {
+	GetHPSegs(ctx context.Context, msg *path_mgmt.HPSegReq, a net.Addr,
+		id uint64) (*path_mgmt.HPSegReply, error)
+}
+
+// hiddenSegFetcher fetches the hidden down segments of hidden path groups from
+// the hidden path servers. The hidden segments are verified, but they are not
+// stored in the path database, such that they are only used for the requests
+// that list the hidden path groups.
+type hiddenSegFetcher struct {
+	requester hiddenSegRequester
+	servers   []*snet.Addr
+	verifier  seghandler.Verifier
+}
+
+// FetchSegs fetches the hidden down segments to dst of the groups. The
+// servers are tried in order until one answers.
+func (f *hiddenSegFetcher) FetchSegs(ctx context.Context, dst addr.IA,
+	groups []*path_mgmt.HPGroupId) (seg.Segments, error) {
+
+	if len(f.servers) == 0 {
+		return nil, ErrNoHiddenPathServers
+	}
+	if len(groups) == 0 {
+		return nil, ErrNoHiddenPathGroups
+	}
+	req := &path_mgmt.HPSegReq{RawDstIA: dst.IAInt(), GroupIds: groups}
+	var errs serrors.List
+	for _, server := range f.servers {
+		reply, err := f.requester.GetHPSegs(ctx, req, server, messenger.NextIdFromCtx(ctx))
+		if err != nil {
+			errs = append(errs, serrors.WrapStr("requesting hidden segments failed", err,
+				"server", server))
+			continue
+		}
+		return f.verify(ctx, reply.Sanitize(log.FromCtx(ctx)), server)
+	}
+	return nil, errs.ToError()
+}
+
+// verify verifies the down segments of reply and returns the segments that
+// could be verified.
+func (f *hiddenSegFetcher) verify(ctx context.Context, reply *path_mgmt.HPSegReply,
+	server net.Addr) (seg.Segments, error) {
+
+	logger := log.FromCtx(ctx)
+	var metas []*seg.Meta
+	for _, recs := range reply.Recs {
+		if recs.Err != "" {
+			logger.Info("Hidden path server failed to provide segments",
+				"group", recs.GroupId, "err", recs.Err)
+			continue
+		}
+		for _, meta := range recs.Recs {
+			if meta.Type == proto.PathSegType_down {
+				metas = append(metas, meta)
+			}
+		}
+	}
+	if len(metas) == 0 {
+		return nil, nil
+	}
+	results, units := f.verifier.Verify(ctx, seghandler.Segments{Segs: metas}, server)
+	var segs seg.Segments
+	for i := 0; i < units; i++ {
+		select {
+		case result := <-results:
+			if err := result.SegError(); err != nil {
+				logger.Info("Discarding unverifiable hidden segment",
+					"seg", result.Unit.SegMeta.Segment, "err", err)
+				continue
+			}
+			segs = append(segs, result.Unit.SegMeta.Segment)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return segs, nil
+}
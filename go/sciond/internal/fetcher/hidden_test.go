@@ -0,0 +1,140 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetcher
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/infra/modules/seghandler"
+	"github.com/scionproto/scion/go/lib/infra/modules/segverifier"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/proto"
+)
+
+func TestHiddenSegFetcher(t *testing.T) {
+	dst := xtest.MustParseIA("1-ff00:0:112")
+	groups := []*path_mgmt.HPGroupId{{OwnerAS: dst.A, GroupId: 1}}
+	down, invalid, up := &seg.PathSegment{}, &seg.PathSegment{}, &seg.PathSegment{}
+	reply := &path_mgmt.HPSegReply{
+		Recs: []*path_mgmt.HPSegRecs{
+			{
+				GroupId: groups[0],
+				Recs: []*seg.Meta{
+					{Type: proto.PathSegType_down, Segment: down},
+					{Type: proto.PathSegType_down, Segment: invalid},
+					{Type: proto.PathSegType_up, Segment: up},
+				},
+			},
+			{GroupId: &path_mgmt.HPGroupId{OwnerAS: dst.A, GroupId: 2}, Err: "unknown"},
+		},
+	}
+	verifier := fakeSegVerifier{invalid: invalid}
+	failing := mustSnetAddr(t, "1-ff00:0:110,[127.0.0.1]:30100")
+	working := mustSnetAddr(t, "1-ff00:0:110,[127.0.0.2]:30100")
+	requester := fakeHPSegRequester{
+		failing.String(): {err: serrors.New("unreachable")},
+		working.String(): {reply: reply},
+	}
+
+	t.Run("segments are fetched from the first working server", func(t *testing.T) {
+		f := &hiddenSegFetcher{
+			requester: requester,
+			servers:   []*snet.Addr{failing, working},
+			verifier:  verifier,
+		}
+		segs, err := f.FetchSegs(context.Background(), dst, groups)
+		require.NoError(t, err)
+		require.Len(t, segs, 1)
+		assert.True(t, segs[0] == down, "expected the verified down segment")
+	})
+	t.Run("failing servers return an error", func(t *testing.T) {
+		f := &hiddenSegFetcher{
+			requester: requester,
+			servers:   []*snet.Addr{failing},
+			verifier:  verifier,
+		}
+		_, err := f.FetchSegs(context.Background(), dst, groups)
+		assert.Error(t, err)
+	})
+	t.Run("no servers", func(t *testing.T) {
+		f := &hiddenSegFetcher{requester: requester, verifier: verifier}
+		_, err := f.FetchSegs(context.Background(), dst, groups)
+		assert.True(t, xerrors.Is(err, ErrNoHiddenPathServers), "unexpected error: %v", err)
+	})
+	t.Run("no groups", func(t *testing.T) {
+		f := &hiddenSegFetcher{
+			requester: requester,
+			servers:   []*snet.Addr{working},
+			verifier:  verifier,
+		}
+		_, err := f.FetchSegs(context.Background(), dst, nil)
+		assert.True(t, xerrors.Is(err, ErrNoHiddenPathGroups), "unexpected error: %v", err)
+	})
+}
+
+type hpSegResult struct {
+	reply *path_mgmt.HPSegReply
+	err   error
+}
+
+// fakeHPSegRequester answers hidden segment requests with the result
+// registered for the server address.
+type fakeHPSegRequester map[string]hpSegResult
+
+func (r fakeHPSegRequester) GetHPSegs(_ context.Context, _ *path_mgmt.HPSegReq, a net.Addr,
+	_ uint64) (*path_mgmt.HPSegReply, error) {
+
+	result := r[a.String()]
+	return result.reply, result.err
+}
+
+// fakeSegVerifier accepts all segments except invalid.
+type fakeSegVerifier struct {
+	invalid *seg.PathSegment
+}
+
+func (v fakeSegVerifier) Verify(_ context.Context, recs seghandler.Segments,
+	_ net.Addr) (chan segverifier.UnitResult, int) {
+
+	results := make(chan segverifier.UnitResult, len(recs.Segs))
+	for _, meta := range recs.Segs {
+		result := segverifier.UnitResult{
+			Unit:   &segverifier.Unit{SegMeta: meta},
+			Errors: make(map[int]error),
+		}
+		if meta.Segment == v.invalid {
+			result.Errors[-1] = serrors.New("invalid signature")
+		}
+		results <- result
+	}
+	return results, len(recs.Segs)
+}
+
+func mustSnetAddr(t *testing.T, s string) *snet.Addr {
+	t.Helper()
+	a, err := snet.AddrFromString(s)
+	require.NoError(t, err)
+	return a
+}
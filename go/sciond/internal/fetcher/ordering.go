@@ -0,0 +1,107 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetcher
+
+import (
+	"sort"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/infra/modules/combinator"
+	"github.com/scionproto/scion/go/lib/sciond"
+)
+
+// SortPaths sorts paths according to ordering. Paths that are equal with
+// regard to the ordering are ordered by the remaining criteria and finally by
+// their interfaces, so the result does not depend on the input order.
+// PathOrderingDefault orders by hops.
+func SortPaths(paths []*combinator.Path, ordering sciond.PathOrdering) {
+	entries := make([]sortEntry, 0, len(paths))
+	for _, path := range paths {
+		entries = append(entries, sortEntry{path: path, expiry: path.ComputeExpTime()})
+	}
+	criteria := orderingCriteria(ordering)
+	sort.Slice(entries, func(i, j int) bool {
+		for _, cmp := range criteria {
+			if c := cmp(&entries[i], &entries[j]); c != 0 {
+				return c < 0
+			}
+		}
+		return cmpInterfaces(&entries[i], &entries[j]) < 0
+	})
+	for i := range entries {
+		paths[i] = entries[i].path
+	}
+}
+
+type sortEntry struct {
+	path   *combinator.Path
+	expiry time.Time
+}
+
+// cmpFunc returns a negative value if a should be ordered before b, a positive
+// value if b should be ordered before a and 0 if they are equal.
+type cmpFunc func(a, b *sortEntry) int
+
+func orderingCriteria(ordering sciond.PathOrdering) []cmpFunc {
+	switch ordering {
+	case sciond.PathOrderingExpiry:
+		return []cmpFunc{cmpExpiry, cmpHops, cmpMTU}
+	case sciond.PathOrderingMTU:
+		return []cmpFunc{cmpMTU, cmpHops, cmpExpiry}
+	default:
+		return []cmpFunc{cmpHops, cmpExpiry, cmpMTU}
+	}
+}
+
+// cmpHops orders paths with fewer hops first.
+func cmpHops(a, b *sortEntry) int {
+	return a.path.Weight - b.path.Weight
+}
+
+// cmpExpiry orders paths that expire later first.
+func cmpExpiry(a, b *sortEntry) int {
+	switch {
+	case a.expiry.After(b.expiry):
+		return -1
+	case a.expiry.Before(b.expiry):
+		return 1
+	}
+	return 0
+}
+
+// cmpMTU orders paths with a larger MTU first.
+func cmpMTU(a, b *sortEntry) int {
+	return int(b.path.Mtu) - int(a.path.Mtu)
+}
+
+// cmpInterfaces orders paths lexicographically by their interfaces.
+func cmpInterfaces(a, b *sortEntry) int {
+	ai, bi := a.path.Interfaces, b.path.Interfaces
+	for i := 0; i < len(ai) && i < len(bi); i++ {
+		if ai[i].RawIsdas != bi[i].RawIsdas {
+			if ai[i].RawIsdas < bi[i].RawIsdas {
+				return -1
+			}
+			return 1
+		}
+		if ai[i].IfID != bi[i].IfID {
+			if ai[i].IfID < bi[i].IfID {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(ai) - len(bi)
+}
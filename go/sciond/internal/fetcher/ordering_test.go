@@ -0,0 +1,91 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetcher_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/infra/modules/combinator"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/sciond/internal/fetcher"
+)
+
+func TestSortPaths(t *testing.T) {
+	// short path, early expiry, small MTU
+	short := testPath(2, 1000, 1280, 1)
+	// long path, late expiry, large MTU
+	long := testPath(4, 2000, 1472, 2)
+	// medium path, late expiry, medium MTU
+	medium := testPath(3, 2000, 1400, 3)
+	// same properties as medium, but different interfaces
+	mediumTwin := testPath(3, 2000, 1400, 4)
+
+	tests := map[string]struct {
+		Ordering sciond.PathOrdering
+		Expected []*combinator.Path
+	}{
+		"default": {
+			Ordering: sciond.PathOrderingDefault,
+			Expected: []*combinator.Path{short, medium, mediumTwin, long},
+		},
+		"hops": {
+			Ordering: sciond.PathOrderingHops,
+			Expected: []*combinator.Path{short, medium, mediumTwin, long},
+		},
+		"expiry": {
+			Ordering: sciond.PathOrderingExpiry,
+			Expected: []*combinator.Path{medium, mediumTwin, long, short},
+		},
+		"mtu": {
+			Ordering: sciond.PathOrderingMTU,
+			Expected: []*combinator.Path{long, medium, mediumTwin, short},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// The result must not depend on the input order.
+			for _, paths := range [][]*combinator.Path{
+				{short, long, medium, mediumTwin},
+				{mediumTwin, medium, long, short},
+			} {
+				fetcher.SortPaths(paths, test.Ordering)
+				assert.Equal(t, test.Expected, paths)
+			}
+		})
+	}
+}
+
+func testPath(hops int, ts uint32, mtu uint16, firstIfID common.IFIDType) *combinator.Path {
+	return &combinator.Path{
+		Segments: []*combinator.Segment{
+			{
+				InfoField: &combinator.InfoField{
+					InfoField: &spath.InfoField{TsInt: ts},
+				},
+			},
+		},
+		Weight: hops,
+		Mtu:    mtu,
+		Interfaces: []sciond.PathInterface{
+			{RawIsdas: xtest.MustParseIA("1-ff00:0:111").IAInt(), IfID: firstIfID},
+			{RawIsdas: xtest.MustParseIA("1-ff00:0:110").IAInt(), IfID: 1},
+		},
+	}
+}
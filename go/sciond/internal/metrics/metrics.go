@@ -14,5 +14,29 @@
 
 package metrics
 
+import (
+	"github.com/scionproto/scion/go/lib/prom"
+)
+
 // Namespace is the metrics namespace for sciond.
 const Namespace = "sd"
+
+// HandlerPanics counts the panics recovered in the API request handlers,
+// labeled by the request type.
+var HandlerPanics = prom.NewCounterVec(Namespace, "", "handler_panics_total",
+	"Total number of panics recovered in API request handlers.", []string{"type"})
+
+// The following gauges report the quality of the paths to the destinations in
+// the watch list, labeled by the destination.
+var (
+	// WatchPaths is the number of usable paths.
+	WatchPaths = prom.NewGaugeVec(Namespace, "watch", "paths",
+		"Number of usable paths to the watched destination.", []string{"dst"})
+	// WatchShortestPathHops is the number of AS hops of the shortest path.
+	WatchShortestPathHops = prom.NewGaugeVec(Namespace, "watch", "shortest_path_hops",
+		"Number of AS hops of the shortest path to the watched destination.", []string{"dst"})
+	// WatchMinPathExpiry is the earliest expiration time of the usable paths.
+	WatchMinPathExpiry = prom.NewGaugeVec(Namespace, "watch", "min_path_expiry_seconds",
+		"Earliest expiration time of the usable paths to the watched destination, "+
+			"as a Unix timestamp.", []string{"dst"})
+)
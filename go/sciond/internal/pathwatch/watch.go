@@ -0,0 +1,120 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pathwatch periodically exports the quality of the paths to a list
+// of watched destinations as metrics.
+package pathwatch
+
+import (
+	"context"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/periodic"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/sciond/internal/metrics"
+)
+
+// PathGetter returns the paths to a destination.
+type PathGetter interface {
+	GetPaths(ctx context.Context, req *sciond.PathReq, earlyReplyInterval time.Duration,
+		logger log.Logger) (*sciond.PathReply, error)
+}
+
+// Quality summarizes the usable paths to a destination.
+type Quality struct {
+	// Paths is the number of usable paths.
+	Paths int
+	// ShortestHops is the number of AS hops of the shortest path. It is 0 if
+	// there is no usable path.
+	ShortestHops int
+	// MinExpiry is the earliest expiration time of the usable paths. It is
+	// the zero value if there is no usable path.
+	MinExpiry time.Time
+}
+
+// NewQuality computes the quality of the paths in reply. Paths that are
+// expired at now, and the empty path that is returned if no path exists, are
+// not usable.
+func NewQuality(reply *sciond.PathReply, now time.Time) Quality {
+	var q Quality
+	if reply == nil || reply.ErrorCode != sciond.ErrorOk {
+		return q
+	}
+	for _, entry := range reply.Entries {
+		if entry.Path == nil || len(entry.Path.Interfaces) == 0 {
+			continue
+		}
+		expiry := entry.Path.Expiry()
+		if !expiry.After(now) {
+			continue
+		}
+		hops := len(entry.Path.Interfaces) / 2
+		if q.Paths == 0 || hops < q.ShortestHops {
+			q.ShortestHops = hops
+		}
+		if q.Paths == 0 || expiry.Before(q.MinExpiry) {
+			q.MinExpiry = expiry
+		}
+		q.Paths++
+	}
+	return q
+}
+
+var _ periodic.Task = (*Watcher)(nil)
+
+// Watcher checks the paths to the watched destinations and exports their
+// quality as metrics. A destination for which the paths cannot be
+// determined is reported as having no usable path.
+type Watcher struct {
+	// Paths is used to look up the paths to the destinations.
+	Paths PathGetter
+	// Dsts are the watched destinations.
+	Dsts []addr.IA
+	// EarlyReplyInterval is passed to the path lookups.
+	EarlyReplyInterval time.Duration
+}
+
+// Name returns the task name.
+func (w *Watcher) Name() string {
+	return "sd_pathwatch.Watcher"
+}
+
+// Run checks the paths to all watched destinations.
+func (w *Watcher) Run(ctx context.Context) {
+	logger := log.FromCtx(ctx)
+	for _, dst := range w.Dsts {
+		req := &sciond.PathReq{
+			Dst:   dst.IAInt(),
+			Flags: sciond.PathReqFlags{AllowStale: true},
+		}
+		reply, err := w.Paths.GetPaths(ctx, req, w.EarlyReplyInterval, logger)
+		if err != nil {
+			logger.Info("Unable to get paths to watched destination", "dst", dst, "err", err)
+		}
+		w.export(dst, NewQuality(reply, time.Now()))
+	}
+}
+
+func (w *Watcher) export(dst addr.IA, q Quality) {
+	label := dst.String()
+	metrics.WatchPaths.WithLabelValues(label).Set(float64(q.Paths))
+	metrics.WatchShortestPathHops.WithLabelValues(label).Set(float64(q.ShortestHops))
+	var expiry float64
+	if !q.MinExpiry.IsZero() {
+		expiry = float64(q.MinExpiry.Unix())
+	}
+	metrics.WatchMinPathExpiry.WithLabelValues(label).Set(expiry)
+}
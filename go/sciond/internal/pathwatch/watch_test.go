@@ -0,0 +1,122 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathwatch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/util"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestNewQuality(t *testing.T) {
+	now := util.SecsToTime(util.TimeToSecs(time.Now()))
+	path := func(hops int, expiry time.Time) sciond.PathReplyEntry {
+		return sciond.PathReplyEntry{
+			Path: &sciond.FwdPathMeta{
+				Interfaces: make([]sciond.PathInterface, 2*hops),
+				ExpTime:    util.TimeToSecs(expiry),
+			},
+		}
+	}
+	tests := map[string]struct {
+		Reply    *sciond.PathReply
+		Expected Quality
+	}{
+		"nil reply": {},
+		"error reply": {
+			Reply: &sciond.PathReply{
+				ErrorCode: sciond.ErrorNoPaths,
+				Entries:   []sciond.PathReplyEntry{path(2, now.Add(time.Hour))},
+			},
+		},
+		"empty path": {
+			Reply: &sciond.PathReply{
+				Entries: []sciond.PathReplyEntry{path(0, now.Add(time.Hour))},
+			},
+		},
+		"expired path": {
+			Reply: &sciond.PathReply{
+				Entries: []sciond.PathReplyEntry{path(2, now)},
+			},
+		},
+		"usable paths": {
+			Reply: &sciond.PathReply{
+				Entries: []sciond.PathReplyEntry{
+					path(3, now.Add(time.Hour)),
+					path(2, now.Add(2*time.Hour)),
+					path(4, now.Add(30*time.Minute)),
+					path(1, now.Add(-time.Minute)),
+				},
+			},
+			Expected: Quality{
+				Paths:        3,
+				ShortestHops: 2,
+				MinExpiry:    now.Add(30 * time.Minute),
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			q := NewQuality(test.Reply, now)
+			assert.Equal(t, test.Expected.Paths, q.Paths)
+			assert.Equal(t, test.Expected.ShortestHops, q.ShortestHops)
+			assert.True(t, test.Expected.MinExpiry.Equal(q.MinExpiry),
+				"expected %v, actual %v", test.Expected.MinExpiry, q.MinExpiry)
+		})
+	}
+}
+
+type recordingGetter struct {
+	reqs []*sciond.PathReq
+}
+
+func (g *recordingGetter) GetPaths(_ context.Context, req *sciond.PathReq, _ time.Duration,
+	_ log.Logger) (*sciond.PathReply, error) {
+
+	g.reqs = append(g.reqs, req)
+	if req.Dst.IA().A == 0xff0000000111 {
+		return nil, common.NewBasicError("no paths", nil)
+	}
+	return &sciond.PathReply{}, nil
+}
+
+func TestWatcherRun(t *testing.T) {
+	getter := &recordingGetter{}
+	w := &Watcher{
+		Paths: getter,
+		Dsts: []addr.IA{
+			xtest.MustParseIA("1-ff00:0:111"),
+			xtest.MustParseIA("2-ff00:0:210"),
+		},
+	}
+	ctx, cancelF := context.WithTimeout(context.Background(), time.Second)
+	defer cancelF()
+	w.Run(ctx)
+	if assert.Len(t, getter.reqs, 2) {
+		for i, req := range getter.reqs {
+			assert.Equal(t, w.Dsts[i], req.Dst.IA())
+			assert.True(t, req.Flags.AllowStale)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/proto"
+)
+
+// Result values recorded in the access log.
+const (
+	// ResultOK indicates that a reply was sent and it did not carry an error.
+	ResultOK = "ok"
+	// ResultError indicates that a reply was sent, but it carried an error.
+	ResultError = "error"
+	// ResultNoReply indicates that the handler did not reply to the client.
+	ResultNoReply = "no_reply"
+	// ResultWriteError indicates that the reply could not be written to the
+	// client.
+	ResultWriteError = "write_error"
+	// ResultBadRequest indicates that the request could not be parsed or that
+	// no handler exists for it.
+	ResultBadRequest = "bad_request"
+	// ResultPanic indicates that the handler panicked while handling the
+	// request.
+	ResultPanic = "panic"
+)
+
+// AccessLogEntry is a single record of the access log. It is written as one
+// JSON object per line.
+type AccessLogEntry struct {
+	// Time is the time at which the request was received.
+	Time time.Time `json:"time"`
+	// Network is the network of the server that received the request.
+	Network string `json:"network,omitempty"`
+	// ConnID identifies the client connection the request was received on.
+	// It is unique per server for the lifetime of the process.
+	ConnID uint64 `json:"conn_id"`
+	// Client is the address of the client, if known.
+	Client string `json:"client,omitempty"`
+	// ID is the request ID chosen by the client.
+	ID uint64 `json:"id"`
+	// Request is the type of the request.
+	Request string `json:"request"`
+	// Params are the request parameters.
+	Params map[string]interface{} `json:"params,omitempty"`
+	// Result is one of the Result constants.
+	Result string `json:"result"`
+	// Detail describes the result in more detail, e.g., the error carried
+	// in the reply.
+	Detail string `json:"detail,omitempty"`
+	// LatencyMs is the time it took to handle the request, in milliseconds.
+	LatencyMs float64 `json:"latency_ms"`
+}
+
+// AccessLog writes a structured record of every SCIOND API request as JSON
+// lines. It is safe for concurrent use.
+type AccessLog struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	closer io.Closer
+}
+
+// NewAccessLog creates an access log that writes to w.
+func NewAccessLog(w io.Writer) *AccessLog {
+	al := &AccessLog{enc: json.NewEncoder(w)}
+	if c, ok := w.(io.Closer); ok {
+		al.closer = c
+	}
+	return al
+}
+
+// OpenAccessLog opens the file at path for appending and returns an access
+// log writing to it. The file is created if it does not exist.
+func OpenAccessLog(path string) (*AccessLog, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, common.NewBasicError("Unable to open access log", err, "path", path)
+	}
+	return NewAccessLog(f), nil
+}
+
+// Log writes the entry to the access log.
+func (al *AccessLog) Log(entry AccessLogEntry) error {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	return al.enc.Encode(entry)
+}
+
+// Close closes the underlying writer, if it is closable.
+func (al *AccessLog) Close() error {
+	if al.closer == nil {
+		return nil
+	}
+	return al.closer.Close()
+}
+
+// replyRecorder wraps the connection passed to a handler and keeps track of
+// the reply written to the client.
+type replyRecorder struct {
+	net.PacketConn
+	mu    sync.Mutex
+	reply common.RawBytes
+	err   error
+}
+
+func (r *replyRecorder) WriteTo(b []byte, address net.Addr) (int, error) {
+	n, err := r.PacketConn.WriteTo(b, address)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reply = append(common.RawBytes(nil), b...)
+	r.err = err
+	return n, err
+}
+
+// result returns the result and detail for the recorded reply.
+func (r *replyRecorder) result() (string, string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return ResultWriteError, r.err.Error()
+	}
+	if r.reply == nil {
+		return ResultNoReply, ""
+	}
+	p := &sciond.Pld{}
+	if err := proto.ParseFromReader(p, bytes.NewReader(r.reply)); err != nil {
+		return ResultOK, ""
+	}
+	return replyResult(p)
+}
+
+func replyResult(p *sciond.Pld) (string, string) {
+	switch {
+	case p.Which == proto.SCIONDMsg_Which_pathReply && p.PathReply != nil:
+		if p.PathReply.ErrorCode != sciond.ErrorOk {
+			return ResultError, p.PathReply.ErrorCode.String()
+		}
+	case p.Which == proto.SCIONDMsg_Which_revReply && p.RevReply != nil:
+		if p.RevReply.Result != sciond.RevValid {
+			return ResultError, p.RevReply.Result.String()
+		}
+	case p.Which == proto.SCIONDMsg_Which_pathFeedbackReply && p.PathFeedbackReply != nil:
+		if p.PathFeedbackReply.Result != sciond.PathFeedbackOk {
+			return ResultError, p.PathFeedbackReply.Result.String()
+		}
+	}
+	return ResultOK, ""
+}
+
+// requestParams extracts the parameters of the request in p.
+func requestParams(p *sciond.Pld) map[string]interface{} {
+	switch {
+	case p.Which == proto.SCIONDMsg_Which_pathReq && p.PathReq != nil:
+		return map[string]interface{}{
+			"src":       p.PathReq.Src.IA().String(),
+			"dst":       p.PathReq.Dst.IA().String(),
+			"max_paths": p.PathReq.MaxPaths,
+			"refresh":   p.PathReq.Flags.Refresh,
+			"hidden":    p.PathReq.Flags.Hidden,
+			"ordering":  p.PathReq.Flags.Ordering.String(),
+			"disjoint":  p.PathReq.Flags.Disjointness.String(),
+		}
+	case p.Which == proto.SCIONDMsg_Which_asInfoReq && p.AsInfoReq != nil:
+		return map[string]interface{}{"isd_as": p.AsInfoReq.Isdas.IA().String()}
+	case p.Which == proto.SCIONDMsg_Which_ifInfoRequest && p.IfInfoRequest != nil:
+		return map[string]interface{}{"ifids": p.IfInfoRequest.IfIDs}
+	case p.Which == proto.SCIONDMsg_Which_serviceInfoRequest && p.ServiceInfoRequest != nil:
+		svcs := make([]string, 0, len(p.ServiceInfoRequest.ServiceTypes))
+		for _, svc := range p.ServiceInfoRequest.ServiceTypes {
+			svcs = append(svcs, svc.String())
+		}
+		return map[string]interface{}{"service_types": svcs}
+	case p.Which == proto.SCIONDMsg_Which_revNotification && p.RevNotification != nil:
+		return map[string]interface{}{"rev_info": p.RevNotification.SRevInfo.String()}
+	case p.Which == proto.SCIONDMsg_Which_nextQueryReq && p.NextQueryReq != nil:
+		return map[string]interface{}{
+			"dst":    p.NextQueryReq.Dst.IA().String(),
+			"delete": p.NextQueryReq.Delete,
+		}
+	case p.Which == proto.SCIONDMsg_Which_pathFeedbackReq && p.PathFeedbackReq != nil:
+		return map[string]interface{}{
+			"path": p.PathFeedbackReq.PathKey.String(),
+			"rtt":  p.PathFeedbackReq.RTT().String(),
+			"loss": p.PathFeedbackReq.LossRate(),
+		}
+	}
+	return nil
+}
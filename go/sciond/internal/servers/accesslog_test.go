@@ -0,0 +1,146 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/mocks/net/mock_net"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/proto"
+)
+
+type handlerFunc func(ctx context.Context, conn net.PacketConn, src net.Addr, pld *sciond.Pld)
+
+func (f handlerFunc) Handle(ctx context.Context, conn net.PacketConn, src net.Addr,
+	pld *sciond.Pld) {
+
+	f(ctx, conn, src, pld)
+}
+
+func TestConnHandlerAccessLog(t *testing.T) {
+	pathReq := &sciond.Pld{
+		Id:    42,
+		Which: proto.SCIONDMsg_Which_pathReq,
+		PathReq: &sciond.PathReq{
+			Src:      xtest.MustParseIA("1-ff00:0:110").IAInt(),
+			Dst:      xtest.MustParseIA("1-ff00:0:111").IAInt(),
+			MaxPaths: 5,
+		},
+	}
+	replyWith := func(code sciond.PathErrorCode) Handler {
+		return handlerFunc(func(_ context.Context, conn net.PacketConn, src net.Addr,
+			pld *sciond.Pld) {
+
+			reply := &sciond.Pld{
+				Id:        pld.Id,
+				Which:     proto.SCIONDMsg_Which_pathReply,
+				PathReply: &sciond.PathReply{ErrorCode: code},
+			}
+			_ = sendReply(reply, conn, src)
+		})
+	}
+	tests := map[string]struct {
+		Handlers       HandlerMap
+		WriteErr       error
+		ExpectedResult string
+		ExpectedDetail string
+	}{
+		"reply ok": {
+			Handlers: HandlerMap{
+				proto.SCIONDMsg_Which_pathReq: replyWith(sciond.ErrorOk),
+			},
+			ExpectedResult: ResultOK,
+		},
+		"reply with error": {
+			Handlers: HandlerMap{
+				proto.SCIONDMsg_Which_pathReq: replyWith(sciond.ErrorNoPaths),
+			},
+			ExpectedResult: ResultError,
+			ExpectedDetail: sciond.ErrorNoPaths.String(),
+		},
+		"write error": {
+			Handlers: HandlerMap{
+				proto.SCIONDMsg_Which_pathReq: replyWith(sciond.ErrorOk),
+			},
+			WriteErr:       errors.New("test error"),
+			ExpectedResult: ResultWriteError,
+			ExpectedDetail: "test error",
+		},
+		"no reply": {
+			Handlers: HandlerMap{
+				proto.SCIONDMsg_Which_pathReq: handlerFunc(func(context.Context,
+					net.PacketConn, net.Addr, *sciond.Pld) {
+				}),
+			},
+			ExpectedResult: ResultNoReply,
+		},
+		"no handler": {
+			Handlers:       HandlerMap{},
+			ExpectedResult: ResultBadRequest,
+			ExpectedDetail: "no handler for request",
+		},
+		"handler panics": {
+			Handlers: HandlerMap{
+				proto.SCIONDMsg_Which_pathReq: handlerFunc(func(context.Context,
+					net.PacketConn, net.Addr, *sciond.Pld) {
+					panic("test panic")
+				}),
+			},
+			ExpectedResult: ResultPanic,
+			ExpectedDetail: "test panic",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			conn := mock_net.NewMockPacketConn(ctrl)
+			conn.EXPECT().WriteTo(gomock.Any(), gomock.Any()).Return(0,
+				test.WriteErr).AnyTimes()
+			var buf bytes.Buffer
+			hdl := NewConnHandler(conn, test.Handlers, log.Root())
+			hdl.AccessLog = NewAccessLog(&buf)
+			hdl.Network = "unixpacket"
+			hdl.ConnID = 3
+			b, err := proto.PackRoot(pathReq)
+			require.NoError(t, err)
+			hdl.Handle(b, &net.UnixAddr{Name: "client", Net: "unixpacket"})
+
+			var entry AccessLogEntry
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+			assert.Equal(t, "unixpacket", entry.Network)
+			assert.Equal(t, uint64(3), entry.ConnID)
+			assert.Equal(t, "client", entry.Client)
+			assert.Equal(t, uint64(42), entry.ID)
+			assert.Equal(t, proto.SCIONDMsg_Which_pathReq.String(), entry.Request)
+			assert.Equal(t, "1-ff00:0:111", entry.Params["dst"])
+			assert.Equal(t, test.ExpectedResult, entry.Result)
+			assert.Equal(t, test.ExpectedDetail, entry.Detail)
+			assert.True(t, entry.LatencyMs >= 0)
+		})
+	}
+}
@@ -23,12 +23,16 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"runtime/debug"
+	"sync"
+	"time"
 
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/sciond"
 	"github.com/scionproto/scion/go/lib/tracing"
 	"github.com/scionproto/scion/go/proto"
+	"github.com/scionproto/scion/go/sciond/internal/metrics"
 )
 
 // ConnHandler is a SCIOND API server running on top of a PacketConn. It
@@ -39,6 +43,16 @@ type ConnHandler struct {
 	// State for request Handlers
 	Handlers map[proto.SCIONDMsg_Which]Handler
 	Logger   log.Logger
+	// AccessLog, if set, receives a record of every request handled on the
+	// connection.
+	AccessLog *AccessLog
+	// Network and ConnID identify the connection in the access log.
+	Network string
+	ConnID  uint64
+
+	mu sync.Mutex
+	// peer is the address of the last request, notifications are sent to it.
+	peer net.Addr
 }
 
 func NewConnHandler(conn net.PacketConn,
@@ -58,6 +72,9 @@ func (srv *ConnHandler) Serve() error {
 		if err != nil {
 			return err
 		}
+		srv.mu.Lock()
+		srv.peer = address
+		srv.mu.Unlock()
 		go func() {
 			defer log.LogPanicAndExit()
 			srv.Handle(b[:n], address)
@@ -66,20 +83,100 @@ func (srv *ConnHandler) Serve() error {
 }
 
 func (srv *ConnHandler) Handle(b common.RawBytes, address net.Addr) {
+	start := time.Now()
 	p := &sciond.Pld{}
 	if err := proto.ParseFromReader(p, bytes.NewReader(b)); err != nil {
 		log.Error("capnp error", "err", err)
+		srv.logAccess(start, address, p, ResultBadRequest, err.Error())
 		return
 	}
 	handler, ok := srv.Handlers[p.Which]
 	if !ok {
 		log.Error("handler not found for capnp message", "which", p.Which)
+		srv.logAccess(start, address, p, ResultBadRequest, "no handler for request")
 		return
 	}
 	ctx, span := tracing.CtxWith(context.Background(), srv.Logger,
 		fmt.Sprintf("%s.handler", p.Which))
 	defer span.Finish()
-	handler.Handle(ctx, srv.Conn, address, p)
+	if srv.AccessLog == nil {
+		srv.handleRecovering(ctx, handler, srv.Conn, address, p)
+		return
+	}
+	recorder := &replyRecorder{PacketConn: srv.Conn}
+	if msg, panicked := srv.handleRecovering(ctx, handler, recorder, address, p); panicked {
+		srv.logAccess(start, address, p, ResultPanic, fmt.Sprint(msg))
+		return
+	}
+	result, detail := recorder.result()
+	srv.logAccess(start, address, p, result, detail)
+}
+
+// handleRecovering calls the handler and recovers from a panic in it. The
+// panic is logged with its stack trace and counted. Path requests are answered
+// with an internal error, so that the client does not have to wait for a
+// timeout. It returns the recovered value and whether the handler panicked.
+func (srv *ConnHandler) handleRecovering(ctx context.Context, handler Handler,
+	conn net.PacketConn, address net.Addr, p *sciond.Pld) (msg interface{}, panicked bool) {
+
+	defer func() {
+		if msg = recover(); msg == nil {
+			return
+		}
+		panicked = true
+		metrics.HandlerPanics.WithLabelValues(p.Which.String()).Inc()
+		logger := log.FromCtx(ctx)
+		logger.Error("Handler panicked", "which", p.Which, "msg", msg,
+			"stack", string(debug.Stack()))
+		if p.Which != proto.SCIONDMsg_Which_pathReq {
+			return
+		}
+		reply := &sciond.Pld{
+			Id:        p.Id,
+			Which:     proto.SCIONDMsg_Which_pathReply,
+			PathReply: &sciond.PathReply{ErrorCode: sciond.ErrorInternal},
+		}
+		if err := sendReply(reply, conn, address); err != nil {
+			logger.Warn("Unable to reply to client", "client", address, "err", err)
+		}
+	}()
+	handler.Handle(ctx, conn, address, p)
+	return nil, false
+}
+
+func (srv *ConnHandler) logAccess(start time.Time, address net.Addr, p *sciond.Pld,
+	result, detail string) {
+
+	if srv.AccessLog == nil {
+		return
+	}
+	entry := AccessLogEntry{
+		Time:      start,
+		Network:   srv.Network,
+		ConnID:    srv.ConnID,
+		ID:        p.Id,
+		Request:   p.Which.String(),
+		Params:    requestParams(p),
+		Result:    result,
+		Detail:    detail,
+		LatencyMs: float64(time.Since(start)) / float64(time.Millisecond),
+	}
+	if address != nil {
+		entry.Client = address.String()
+	}
+	if err := srv.AccessLog.Log(entry); err != nil {
+		srv.Logger.Warn("Unable to write access log", "err", err)
+	}
+}
+
+// Notify pushes the raw SCIOND message b to the client, without the client
+// having requested it.
+func (srv *ConnHandler) Notify(b common.RawBytes) error {
+	srv.mu.Lock()
+	peer := srv.peer
+	srv.mu.Unlock()
+	_, err := srv.Conn.WriteTo(b, peer)
+	return err
 }
 
 func (srv *ConnHandler) Close() error {
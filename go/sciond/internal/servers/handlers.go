@@ -16,10 +16,12 @@ package servers
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"net"
 	"time"
 
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
 	"github.com/scionproto/scion/go/lib/hostinfo"
 	"github.com/scionproto/scion/go/lib/infra"
@@ -27,10 +29,13 @@ import (
 	"github.com/scionproto/scion/go/lib/infra/modules/segfetcher"
 	"github.com/scionproto/scion/go/lib/infra/modules/segverifier"
 	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/pathdb"
 	"github.com/scionproto/scion/go/lib/revcache"
 	"github.com/scionproto/scion/go/lib/sciond"
 	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/spath/spathmeta"
 	"github.com/scionproto/scion/go/lib/topology"
+	"github.com/scionproto/scion/go/lib/util"
 	"github.com/scionproto/scion/go/proto"
 	"github.com/scionproto/scion/go/sciond/internal/fetcher"
 )
@@ -141,7 +146,11 @@ func (h *ASInfoRequestHandler) Handle(ctx context.Context, conn net.PacketConn,
 // IFInfoRequestHandler represents the shared global state for the handling of all
 // IFInfoRequest queries. The SCIOND API spawns a goroutine with method Handle
 // for each IFInfoRequest it receives.
-type IFInfoRequestHandler struct{}
+type IFInfoRequestHandler struct {
+	// AddressFamily is the address family that is conveyed for border routers
+	// that are reachable over both IPv4 and IPv6.
+	AddressFamily hostinfo.Preference
+}
 
 func (h *IFInfoRequestHandler) Handle(ctx context.Context, conn net.PacketConn, src net.Addr,
 	pld *sciond.Pld) {
@@ -156,7 +165,7 @@ func (h *IFInfoRequestHandler) Handle(ctx context.Context, conn net.PacketConn,
 		for ifid, ifInfo := range topo.IFInfoMap {
 			ifInfoReply.RawEntries = append(ifInfoReply.RawEntries, sciond.IFInfoReplyEntry{
 				IfID:     ifid,
-				HostInfo: hostinfo.FromTopoBRAddr(*ifInfo.InternalAddrs),
+				HostInfo: h.hostInfo(ifInfo),
 			})
 		}
 	} else {
@@ -169,7 +178,7 @@ func (h *IFInfoRequestHandler) Handle(ctx context.Context, conn net.PacketConn,
 			}
 			ifInfoReply.RawEntries = append(ifInfoReply.RawEntries, sciond.IFInfoReplyEntry{
 				IfID:     ifid,
-				HostInfo: hostinfo.FromTopoBRAddr(*ifInfo.InternalAddrs),
+				HostInfo: h.hostInfo(ifInfo),
 			})
 		}
 	}
@@ -185,10 +194,18 @@ func (h *IFInfoRequestHandler) Handle(ctx context.Context, conn net.PacketConn,
 	}
 }
 
+func (h *IFInfoRequestHandler) hostInfo(ifInfo topology.IFInfo) hostinfo.Host {
+	return hostinfo.FromTopoBRAddrWithPreference(*ifInfo.InternalAddrs, h.AddressFamily)
+}
+
 // SVCInfoRequestHandler represents the shared global state for the handling of all
 // SVCInfoRequest queries. The SCIOND API spawns a goroutine with method Handle
 // for each SVCInfoRequest it receives.
-type SVCInfoRequestHandler struct{}
+type SVCInfoRequestHandler struct {
+	// AddressFamily is the address family that is conveyed in the host infos
+	// of services that are reachable over both IPv4 and IPv6.
+	AddressFamily hostinfo.Preference
+}
 
 func (h *SVCInfoRequestHandler) Handle(ctx context.Context, conn net.PacketConn,
 	src net.Addr, pld *sciond.Pld) {
@@ -200,11 +217,12 @@ func (h *SVCInfoRequestHandler) Handle(ctx context.Context, conn net.PacketConn,
 	topo := itopo.Get()
 	for _, t := range svcInfoRequest.ServiceTypes {
 		var hostInfos []hostinfo.Host
-		hostInfos = makeHostInfos(topo, t)
+		hostInfos = makeHostInfos(topo, t, h.AddressFamily)
 		replyEntry := sciond.ServiceInfoReplyEntry{
 			ServiceType: t,
 			Ttl:         DefaultServiceTTL,
 			HostInfos:   hostInfos,
+			Instances:   makeInstances(topo, t),
 		}
 		svcInfoReply.Entries = append(svcInfoReply.Entries, replyEntry)
 	}
@@ -220,7 +238,26 @@ func (h *SVCInfoRequestHandler) Handle(ctx context.Context, conn net.PacketConn,
 	}
 }
 
-func makeHostInfos(topo *topology.Topo, t proto.ServiceType) []hostinfo.Host {
+func makeInstances(topo *topology.Topo, t proto.ServiceType) []sciond.ServiceInstance {
+	svcInfo, err := topo.GetSvcInfo(t)
+	if err != nil {
+		return nil
+	}
+	var instances []sciond.ServiceInstance
+	for _, name := range svcInfo.Names() {
+		topoAddr := svcInfo.GetTopoAddr(name)
+		if topoAddr == nil {
+			continue
+		}
+		ipv4, ipv6 := hostinfo.FromTopoAddrPerOverlay(*topoAddr)
+		instances = append(instances, sciond.ServiceInstance{ID: name, IPv4: ipv4, IPv6: ipv6})
+	}
+	return instances
+}
+
+func makeHostInfos(topo *topology.Topo, t proto.ServiceType,
+	pref hostinfo.Preference) []hostinfo.Host {
+
 	var hostInfos []hostinfo.Host
 	addresses, err := topo.GetAllTopoAddrs(t)
 	if err != nil {
@@ -229,7 +266,7 @@ func makeHostInfos(topo *topology.Topo, t proto.ServiceType) []hostinfo.Host {
 		return hostInfos
 	}
 	for _, a := range addresses {
-		hostInfos = append(hostInfos, hostinfo.FromTopoAddr(a))
+		hostInfos = append(hostInfos, hostinfo.FromTopoAddrWithPreference(a, pref))
 	}
 	return hostInfos
 }
@@ -334,3 +371,112 @@ func sendReply(pld *sciond.Pld, conn net.PacketConn, src net.Addr) error {
 	_, err = conn.WriteTo(b, src)
 	return err
 }
+
+// NextQueryHandler represents the shared global state for the handling of all
+// NextQueryReq queries. It lists, and optionally deletes, the NextQuery
+// entries of the path database for a destination.
+type NextQueryHandler struct {
+	PathDB pathdb.PathDB
+}
+
+func (h *NextQueryHandler) Handle(ctx context.Context, conn net.PacketConn,
+	src net.Addr, pld *sciond.Pld) {
+
+	logger := log.FromCtx(ctx)
+	req := pld.NextQueryReq
+	logger.Debug("[NextQueryHandler] Received request", "req", req)
+	workCtx, workCancelF := context.WithTimeout(ctx, DefaultWorkTimeout)
+	defer workCancelF()
+	var dst addr.IA
+	if req.Dst != 0 {
+		dst = req.Dst.IA()
+	}
+	nqReply := &sciond.NextQueryReply{}
+	entries, err := h.nextQueries(workCtx, dst, req.Delete)
+	if err != nil {
+		logger.Error("Failed to handle NextQuery entries", "dst", dst,
+			"delete", req.Delete, "err", err)
+		nqReply.ErrorCode = sciond.NextQueryInternal
+	}
+	for _, e := range entries {
+		nqReply.Entries = append(nqReply.Entries, sciond.NextQueryReplyEntry{
+			RawSrc:    e.Src.IAInt(),
+			RawDst:    e.Dst.IAInt(),
+			NextQuery: util.TimeToSecs(e.NextQuery),
+		})
+	}
+	reply := &sciond.Pld{
+		Id:             pld.Id,
+		Which:          proto.SCIONDMsg_Which_nextQueryReply,
+		NextQueryReply: nqReply,
+	}
+	if err := sendReply(reply, conn, src); err != nil {
+		logger.Warn("Unable to reply to client", "client", src, "err", err)
+		return
+	}
+	logger.Trace("Sent reply", "nextQueryReply", nqReply)
+}
+
+// nextQueries loads the NextQuery entries for dst. If del is set, the loaded
+// entries are deleted in the same transaction, such that no entry that is
+// concurrently inserted is deleted without being reported.
+func (h *NextQueryHandler) nextQueries(ctx context.Context, dst addr.IA,
+	del bool) ([]pathdb.NextQueryEntry, error) {
+
+	if !del {
+		return h.PathDB.GetNextQueries(ctx, addr.IA{}, dst, nil)
+	}
+	tx, err := h.PathDB.BeginTransaction(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	entries, err := tx.GetNextQueries(ctx, addr.IA{}, dst, nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.DeleteNQ(ctx, addr.IA{}, dst, nil); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// PathFeedbackHandler represents the shared global state for the handling of
+// all PathFeedbackReq queries. It records the reported path performance, which
+// is used to rank the paths of subsequent path replies.
+type PathFeedbackHandler struct {
+	// Feedback stores the feedback. If it is nil, ranking paths based on
+	// feedback is disabled.
+	Feedback *fetcher.PathFeedback
+}
+
+func (h *PathFeedbackHandler) Handle(ctx context.Context, conn net.PacketConn,
+	src net.Addr, pld *sciond.Pld) {
+
+	logger := log.FromCtx(ctx)
+	req := pld.PathFeedbackReq
+	logger.Debug("[PathFeedbackHandler] Received request", "req", req)
+	result := sciond.PathFeedbackOk
+	switch {
+	case h.Feedback == nil:
+		result = sciond.PathFeedbackDisabled
+	case len(req.PathKey) != sha256.Size || req.Loss > sciond.LossPPM:
+		result = sciond.PathFeedbackInvalid
+	default:
+		h.Feedback.Record(spathmeta.PathKey(req.PathKey), req.RTT(), req.LossRate(),
+			time.Now())
+	}
+	reply := &sciond.Pld{
+		Id:                pld.Id,
+		Which:             proto.SCIONDMsg_Which_pathFeedbackReply,
+		PathFeedbackReply: &sciond.PathFeedbackReply{Result: result},
+	}
+	if err := sendReply(reply, conn, src); err != nil {
+		logger.Warn("Unable to reply to client", "client", src, "err", err)
+		return
+	}
+	logger.Trace("Sent reply", "pathFeedbackReply", reply.PathFeedbackReply)
+}
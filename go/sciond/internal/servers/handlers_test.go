@@ -0,0 +1,127 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servers
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/mocks/net/mock_net"
+	"github.com/scionproto/scion/go/lib/pathdb"
+	"github.com/scionproto/scion/go/lib/pathdb/mock_pathdb"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/proto"
+)
+
+func TestNextQueryHandler(t *testing.T) {
+	dst := xtest.MustParseIA("1-ff00:0:110")
+	nq := time.Unix(1570000000, 0)
+	entries := []pathdb.NextQueryEntry{
+		{Src: xtest.MustParseIA("1-ff00:0:111"), Dst: dst, NextQuery: nq},
+	}
+	replyEntries := []sciond.NextQueryReplyEntry{
+		{
+			RawSrc:    xtest.MustParseIA("1-ff00:0:111").IAInt(),
+			RawDst:    dst.IAInt(),
+			NextQuery: 1570000000,
+		},
+	}
+	errDB := serrors.New("test error")
+
+	tests := map[string]struct {
+		Delete        bool
+		PrepareDB     func(db *mock_pathdb.MockPathDB, tx *mock_pathdb.MockTransaction)
+		ExpectedReply *sciond.NextQueryReply
+	}{
+		"list": {
+			PrepareDB: func(db *mock_pathdb.MockPathDB, _ *mock_pathdb.MockTransaction) {
+				db.EXPECT().GetNextQueries(gomock.Any(), addr.IA{}, dst, nil).
+					Return(entries, nil)
+			},
+			ExpectedReply: &sciond.NextQueryReply{Entries: replyEntries},
+		},
+		"list fails": {
+			PrepareDB: func(db *mock_pathdb.MockPathDB, _ *mock_pathdb.MockTransaction) {
+				db.EXPECT().GetNextQueries(gomock.Any(), addr.IA{}, dst, nil).
+					Return(nil, errDB)
+			},
+			ExpectedReply: &sciond.NextQueryReply{ErrorCode: sciond.NextQueryInternal},
+		},
+		"delete": {
+			Delete: true,
+			PrepareDB: func(db *mock_pathdb.MockPathDB, tx *mock_pathdb.MockTransaction) {
+				db.EXPECT().BeginTransaction(gomock.Any(), nil).Return(tx, nil)
+				gomock.InOrder(
+					tx.EXPECT().GetNextQueries(gomock.Any(), addr.IA{}, dst, nil).
+						Return(entries, nil),
+					tx.EXPECT().DeleteNQ(gomock.Any(), addr.IA{}, dst, nil).Return(1, nil),
+					tx.EXPECT().Commit(),
+				)
+				tx.EXPECT().Rollback().AnyTimes()
+			},
+			ExpectedReply: &sciond.NextQueryReply{Entries: replyEntries},
+		},
+		"delete fails": {
+			Delete: true,
+			PrepareDB: func(db *mock_pathdb.MockPathDB, tx *mock_pathdb.MockTransaction) {
+				db.EXPECT().BeginTransaction(gomock.Any(), nil).Return(tx, nil)
+				tx.EXPECT().GetNextQueries(gomock.Any(), addr.IA{}, dst, nil).
+					Return(entries, nil)
+				tx.EXPECT().DeleteNQ(gomock.Any(), addr.IA{}, dst, nil).Return(0, errDB)
+				tx.EXPECT().Rollback()
+			},
+			ExpectedReply: &sciond.NextQueryReply{ErrorCode: sciond.NextQueryInternal},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			db := mock_pathdb.NewMockPathDB(ctrl)
+			test.PrepareDB(db, mock_pathdb.NewMockTransaction(ctrl))
+			peer := &net.UnixAddr{Name: "client", Net: "unixpacket"}
+			conn := mock_net.NewMockPacketConn(ctrl)
+			conn.EXPECT().SetWriteDeadline(gomock.Any())
+			conn.EXPECT().WriteTo(gomock.Any(), peer).DoAndReturn(
+				func(b []byte, _ net.Addr) (int, error) {
+					pld, err := sciond.NewPldFromRaw(common.RawBytes(b))
+					require.NoError(t, err)
+					assert.Equal(t, uint64(1), pld.Id)
+					assert.Equal(t, test.ExpectedReply, pld.NextQueryReply)
+					return len(b), nil
+				},
+			)
+			h := &NextQueryHandler{PathDB: db}
+			h.Handle(context.Background(), conn, peer, &sciond.Pld{
+				Id:    1,
+				Which: proto.SCIONDMsg_Which_nextQueryReq,
+				NextQueryReq: &sciond.NextQueryReq{
+					Dst:    dst.IAInt(),
+					Delete: test.Delete,
+				},
+			})
+		})
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/proto"
+)
+
+// JSONRPCVersion is the JSON-RPC version spoken by the JSON server.
+const JSONRPCVersion = "2.0"
+
+// JSON-RPC error codes.
+const (
+	JSONErrParse          = -32700
+	JSONErrInvalidRequest = -32600
+	JSONErrMethodNotFound = -32601
+	JSONErrInvalidParams  = -32602
+	JSONErrInternal       = -32603
+)
+
+// JSONRequest is a JSON-RPC request. The method is the name of a SCIOND API
+// request, e.g., "pathReq", and the params are the fields of the request, as
+// defined in package sciond. Requests without an ID are notifications and are
+// not answered.
+type JSONRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// JSONResponse is a JSON-RPC response. The result contains the fields of the
+// SCIOND API reply.
+type JSONResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *JSONError      `json:"error,omitempty"`
+}
+
+// JSONError is the error object of a failed JSON-RPC request.
+type JSONError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// JSONServer serves the SCIOND API as JSON-RPC 2.0 over a UNIX stream socket,
+// such that scripts and tools written in other languages can use SCIOND
+// without a Cap'n Proto toolchain. Requests and responses are JSON objects,
+// separated by newlines. The requests are passed to the same handlers as the
+// Cap'n Proto requests, thus both APIs always offer the same functionality.
+// For example, {"jsonrpc":"2.0","id":1,"method":"asInfoReq","params":{}}
+// requests the AS information of the local AS.
+type JSONServer struct {
+	// connCount is accessed atomically and must stay 64-bit aligned.
+	connCount uint64
+
+	address   string
+	filemode  os.FileMode
+	handlers  HandlerMap
+	log       log.Logger
+	accessLog *AccessLog
+
+	mu          sync.Mutex
+	listener    net.Listener
+	closeCalled bool
+	conns       map[net.Conn]struct{}
+}
+
+// NewJSONServer initializes a new JSON server at address. To start listening
+// on the address, call ListenAndServe.
+func NewJSONServer(address string, filemode os.FileMode, handlers HandlerMap,
+	logger log.Logger) *JSONServer {
+
+	return &JSONServer{
+		address:  address,
+		filemode: filemode,
+		handlers: handlers,
+		log:      logger,
+		conns:    make(map[net.Conn]struct{}),
+	}
+}
+
+// SetAccessLog sets the access log that receives a record of every request
+// handled by the server. It must be called before ListenAndServe.
+func (srv *JSONServer) SetAccessLog(al *AccessLog) {
+	srv.accessLog = al
+}
+
+// ListenAndServe starts listening on srv's address, and serves the accepted
+// connections until the server is closed.
+func (srv *JSONServer) ListenAndServe() error {
+	srv.mu.Lock()
+	if srv.closeCalled {
+		srv.mu.Unlock()
+		return serrors.New("attempted to listen on server that was shut down")
+	}
+	listener, err := net.Listen("unix", srv.address)
+	if err != nil {
+		srv.mu.Unlock()
+		return common.NewBasicError("unable to listen on socket", err, "address", srv.address)
+	}
+	if err := os.Chmod(srv.address, srv.filemode); err != nil {
+		listener.Close()
+		srv.mu.Unlock()
+		return common.NewBasicError("chmod failed", err, "address", srv.address)
+	}
+	srv.listener = listener
+	srv.mu.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if strings.Contains(err.Error(), "use of closed network connection") {
+				return err
+			}
+			srv.log.Warn("unable to accept conn", "err", err)
+			continue
+		}
+		go func() {
+			defer log.LogPanicAndExit()
+			srv.serveConn(conn)
+		}()
+	}
+}
+
+// Close makes the server stop listening for new connections, and closes all
+// open connections.
+func (srv *JSONServer) Close() error {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.listener == nil {
+		return serrors.New("uninitialized server")
+	}
+	srv.closeCalled = true
+	for conn := range srv.conns {
+		conn.Close()
+	}
+	return srv.listener.Close()
+}
+
+// Shutdown closes the server. Requests in progress are aborted.
+func (srv *JSONServer) Shutdown(ctx context.Context) error {
+	return srv.Close()
+}
+
+func (srv *JSONServer) serveConn(conn net.Conn) {
+	srv.mu.Lock()
+	if srv.closeCalled {
+		srv.mu.Unlock()
+		conn.Close()
+		return
+	}
+	srv.conns[conn] = struct{}{}
+	srv.mu.Unlock()
+	defer func() {
+		srv.mu.Lock()
+		delete(srv.conns, conn)
+		srv.mu.Unlock()
+		conn.Close()
+	}()
+
+	hdl := &ConnHandler{
+		Handlers:  srv.handlers,
+		Logger:    srv.log,
+		AccessLog: srv.accessLog,
+		Network:   "json",
+		ConnID:    atomic.AddUint64(&srv.connCount, 1),
+	}
+	dec := json.NewDecoder(bufio.NewReader(conn))
+	enc := json.NewEncoder(conn)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if _, ok := err.(*json.SyntaxError); ok {
+				enc.Encode(newJSONError(nil, JSONErrParse, err.Error()))
+			}
+			return
+		}
+		resp := handleJSON(hdl, raw, conn.RemoteAddr())
+		if resp == nil {
+			continue
+		}
+		if err := enc.Encode(resp); err != nil {
+			srv.log.Warn("Unable to write JSON response", "err", err)
+			return
+		}
+	}
+}
+
+// handleJSON handles a single JSON-RPC request. The request is translated to
+// a SCIOND API message, which is handled by hdl. The result is nil if the
+// request is a notification.
+func handleJSON(hdl *ConnHandler, raw json.RawMessage, address net.Addr) *JSONResponse {
+	var req JSONRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return newJSONError(nil, JSONErrInvalidRequest, err.Error())
+	}
+	resp := handleJSONRequest(hdl, &req, address)
+	if len(req.ID) == 0 {
+		return nil
+	}
+	return resp
+}
+
+func handleJSONRequest(hdl *ConnHandler, req *JSONRequest, address net.Addr) *JSONResponse {
+	if req.JSONRPC != JSONRPCVersion || req.Method == "" {
+		return newJSONError(req.ID, JSONErrInvalidRequest, "invalid request")
+	}
+	which, ok := jsonMethod(hdl.Handlers, req.Method)
+	if !ok {
+		return newJSONError(req.ID, JSONErrMethodNotFound, "unknown method: "+req.Method)
+	}
+	p := &sciond.Pld{Which: which}
+	field := pldField(p, which)
+	if !field.IsValid() {
+		return newJSONError(req.ID, JSONErrMethodNotFound, "unknown method: "+req.Method)
+	}
+	field.Set(reflect.New(field.Type().Elem()))
+	if len(req.Params) != 0 {
+		if err := json.Unmarshal(req.Params, field.Interface()); err != nil {
+			return newJSONError(req.ID, JSONErrInvalidParams, err.Error())
+		}
+	}
+	b, err := proto.PackRoot(p)
+	if err != nil {
+		return newJSONError(req.ID, JSONErrInvalidParams, err.Error())
+	}
+	conn := &jsonReplyConn{}
+	(&ConnHandler{
+		Conn:      conn,
+		Handlers:  hdl.Handlers,
+		Logger:    hdl.Logger,
+		AccessLog: hdl.AccessLog,
+		Network:   hdl.Network,
+		ConnID:    hdl.ConnID,
+	}).Handle(b, address)
+	if conn.reply == nil {
+		return newJSONError(req.ID, JSONErrInternal, "no reply")
+	}
+	reply := &sciond.Pld{}
+	if err := proto.ParseFromReader(reply, bytes.NewReader(conn.reply)); err != nil {
+		return newJSONError(req.ID, JSONErrInternal, err.Error())
+	}
+	result := pldField(reply, reply.Which)
+	if !result.IsValid() {
+		return newJSONError(req.ID, JSONErrInternal, "unexpected reply: "+reply.Which.String())
+	}
+	return &JSONResponse{JSONRPC: JSONRPCVersion, ID: req.ID, Result: result.Interface()}
+}
+
+// jsonMethod returns the request type of a JSON-RPC method. Only requests for
+// which a handler is registered can be called.
+func jsonMethod(handlers HandlerMap, method string) (proto.SCIONDMsg_Which, bool) {
+	for which := range handlers {
+		if which.String() == method {
+			return which, true
+		}
+	}
+	return 0, false
+}
+
+// pldField returns the field of p that holds the message of type which. The
+// field name is the capitalized name of the union member, e.g., PathReq for
+// pathReq.
+func pldField(p *sciond.Pld, which proto.SCIONDMsg_Which) reflect.Value {
+	name := []rune(which.String())
+	if len(name) == 0 {
+		return reflect.Value{}
+	}
+	name[0] = unicode.ToUpper(name[0])
+	field := reflect.ValueOf(p).Elem().FieldByName(string(name))
+	if !field.IsValid() || field.Kind() != reflect.Ptr {
+		return reflect.Value{}
+	}
+	return field
+}
+
+func newJSONError(id json.RawMessage, code int, msg string) *JSONResponse {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &JSONResponse{
+		JSONRPC: JSONRPCVersion,
+		ID:      id,
+		Error:   &JSONError{Code: code, Message: msg},
+	}
+}
+
+var _ net.PacketConn = (*jsonReplyConn)(nil)
+
+// jsonReplyConn is the connection passed to the API handlers for JSON
+// requests. It records the reply instead of sending it.
+type jsonReplyConn struct {
+	mu    sync.Mutex
+	reply common.RawBytes
+}
+
+func (c *jsonReplyConn) WriteTo(b []byte, _ net.Addr) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reply = append(common.RawBytes(nil), b...)
+	return len(b), nil
+}
+
+func (c *jsonReplyConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	return 0, nil, serrors.New("read not supported")
+}
+
+func (c *jsonReplyConn) Close() error                       { return nil }
+func (c *jsonReplyConn) LocalAddr() net.Addr                { return nil }
+func (c *jsonReplyConn) SetDeadline(t time.Time) error      { return nil }
+func (c *jsonReplyConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *jsonReplyConn) SetWriteDeadline(t time.Time) error { return nil }
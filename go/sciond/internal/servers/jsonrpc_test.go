@@ -0,0 +1,176 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/proto"
+)
+
+func TestHandleJSON(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	hdl := &ConnHandler{
+		Handlers: HandlerMap{proto.SCIONDMsg_Which_asInfoReq: asInfoEchoHandler{}},
+		Logger:   log.Root(),
+	}
+	tests := map[string]struct {
+		Request string
+		// Response is nil if no response is expected.
+		Response *JSONResponse
+	}{
+		"valid request": {
+			Request: `{"jsonrpc":"2.0","id":1,"method":"asInfoReq",` +
+				`"params":{"Isdas":` + jsonIA(ia) + `}}`,
+			Response: &JSONResponse{
+				JSONRPC: JSONRPCVersion,
+				ID:      json.RawMessage("1"),
+				Result: &sciond.ASInfoReply{
+					Entries: []sciond.ASInfoReplyEntry{{RawIsdas: ia.IAInt(), Mtu: 1472}},
+				},
+			},
+		},
+		"request without params": {
+			Request: `{"jsonrpc":"2.0","id":"a","method":"asInfoReq"}`,
+			Response: &JSONResponse{
+				JSONRPC: JSONRPCVersion,
+				ID:      json.RawMessage(`"a"`),
+				Result: &sciond.ASInfoReply{
+					Entries: []sciond.ASInfoReplyEntry{{Mtu: 1472}},
+				},
+			},
+		},
+		"notification": {
+			Request: `{"jsonrpc":"2.0","method":"asInfoReq"}`,
+		},
+		"unknown method": {
+			Request:  `{"jsonrpc":"2.0","id":2,"method":"pathReq"}`,
+			Response: newJSONError(json.RawMessage("2"), JSONErrMethodNotFound, ""),
+		},
+		"invalid params": {
+			Request:  `{"jsonrpc":"2.0","id":3,"method":"asInfoReq","params":{"Isdas":"x"}}`,
+			Response: newJSONError(json.RawMessage("3"), JSONErrInvalidParams, ""),
+		},
+		"wrong version": {
+			Request:  `{"jsonrpc":"1.0","id":4,"method":"asInfoReq"}`,
+			Response: newJSONError(json.RawMessage("4"), JSONErrInvalidRequest, ""),
+		},
+		"not an object": {
+			Request:  `[1, 2]`,
+			Response: newJSONError(nil, JSONErrInvalidRequest, ""),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := handleJSON(hdl, json.RawMessage(test.Request), nil)
+			if test.Response == nil {
+				assert.Nil(t, resp)
+				return
+			}
+			require.NotNil(t, resp)
+			if test.Response.Error != nil {
+				require.NotNil(t, resp.Error)
+				assert.Equal(t, test.Response.Error.Code, resp.Error.Code)
+				resp.Error.Message = ""
+			}
+			assert.Equal(t, test.Response, resp)
+		})
+	}
+}
+
+func TestJSONServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sciond-json")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	address := filepath.Join(dir, "sd.json.sock")
+
+	srv := NewJSONServer(address, 0700, HandlerMap{
+		proto.SCIONDMsg_Which_asInfoReq: asInfoEchoHandler{},
+	}, log.Root())
+	done := make(chan error, 1)
+	go func() { done <- srv.ListenAndServe() }()
+	var conn net.Conn
+	for i := 0; i < 100 && conn == nil; i++ {
+		if conn, err = net.Dial("unix", address); err != nil {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	require.NoError(t, err)
+	defer conn.Close()
+
+	requests := `{"jsonrpc":"2.0","id":1,"method":"asInfoReq"}` + "\n" +
+		`{"jsonrpc":"2.0","method":"asInfoReq"}` + "\n" +
+		`{"jsonrpc":"2.0","id":2,"method":"nope"}` + "\n"
+	_, err = conn.Write([]byte(requests))
+	require.NoError(t, err)
+	reader := bufio.NewReader(conn)
+	var resp struct {
+		ID     int                `json:"id"`
+		Result sciond.ASInfoReply `json:"result"`
+		Error  *JSONError         `json:"error"`
+	}
+	line, err := reader.ReadBytes('\n')
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(line, &resp))
+	assert.Equal(t, 1, resp.ID)
+	assert.Nil(t, resp.Error)
+	assert.Len(t, resp.Result.Entries, 1)
+	// The notification is not answered, the next response is for request 2.
+	line, err = reader.ReadBytes('\n')
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(line, &resp))
+	assert.Equal(t, 2, resp.ID)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, JSONErrMethodNotFound, resp.Error.Code)
+
+	require.NoError(t, srv.Shutdown(context.Background()))
+	assert.Error(t, <-done)
+}
+
+// asInfoEchoHandler replies to AS info requests with the requested ISD-AS and
+// a fixed MTU.
+type asInfoEchoHandler struct{}
+
+func (asInfoEchoHandler) Handle(_ context.Context, conn net.PacketConn, src net.Addr,
+	pld *sciond.Pld) {
+
+	sendReply(&sciond.Pld{
+		Id:    pld.Id,
+		Which: proto.SCIONDMsg_Which_asInfoReply,
+		AsInfoReply: &sciond.ASInfoReply{
+			Entries: []sciond.ASInfoReplyEntry{{RawIsdas: pld.AsInfoReq.Isdas, Mtu: 1472}},
+		},
+	}, conn, src)
+}
+
+func jsonIA(ia addr.IA) string {
+	b, _ := json.Marshal(ia.IAInt())
+	return string(b)
+}
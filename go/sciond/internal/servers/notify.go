@@ -0,0 +1,47 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servers
+
+import (
+	"sync"
+	"time"
+)
+
+// TopoNotifier pushes topology change notifications to the clients of all
+// servers added to it. It is intended to be hooked into the topology update
+// callbacks, which are registered before the servers are started. The zero
+// value is ready to use.
+type TopoNotifier struct {
+	mu      sync.Mutex
+	servers []*Server
+}
+
+// Add registers srv, such that its clients receive subsequent notifications.
+func (n *TopoNotifier) Add(srv *Server) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.servers = append(n.servers, srv)
+}
+
+// Notify pushes a notification for a topology with timestamp ts to the
+// clients of all registered servers.
+func (n *TopoNotifier) Notify(ts time.Time) {
+	n.mu.Lock()
+	servers := append([]*Server(nil), n.servers...)
+	n.mu.Unlock()
+	for _, srv := range servers {
+		srv.NotifyTopoChange(ts)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servers
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/mocks/net/mock_net"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/proto"
+)
+
+func TestTopoNotifier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ts := time.Unix(1568000000, 0)
+	peer := &net.UnixAddr{Name: "client", Net: "unixpacket"}
+
+	srv := NewServer("unixpacket", "", 0, HandlerMap{}, log.Root())
+	var notifier TopoNotifier
+	notifier.Add(srv)
+	// Connections are notified at the address of the last request.
+	conn := mock_net.NewMockPacketConn(ctrl)
+	hdl := NewConnHandler(conn, srv.handlers, log.Root())
+	hdl.peer = peer
+	srv.addConn(hdl)
+	// Closed connections are not notified anymore.
+	closed := NewConnHandler(mock_net.NewMockPacketConn(ctrl), srv.handlers, log.Root())
+	srv.addConn(closed)
+	srv.removeConn(closed)
+
+	conn.EXPECT().WriteTo(gomock.Any(), peer).DoAndReturn(
+		func(b []byte, _ net.Addr) (int, error) {
+			pld, err := sciond.NewPldFromRaw(common.RawBytes(b))
+			require.NoError(t, err)
+			assert.Equal(t, proto.SCIONDMsg_Which_topoChangeNotification, pld.Which)
+			require.NotNil(t, pld.TopoChangeNotification)
+			assert.Equal(t, ts, pld.TopoChangeNotification.Timestamp())
+			return len(b), nil
+		},
+	)
+	notifier.Notify(ts)
+}
@@ -21,9 +21,12 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/sciond"
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/sock/reliable"
 	"github.com/scionproto/scion/go/proto"
@@ -35,15 +38,21 @@ type HandlerMap map[proto.SCIONDMsg_Which]Handler
 // Whenever a new connection is accepted, a SCIOND API server is created to
 // handle the connection.
 type Server struct {
-	network  string
-	address  string
-	filemode os.FileMode
-	handlers map[proto.SCIONDMsg_Which]Handler
-	log      log.Logger
+	// connCount is accessed atomically and must stay 64-bit aligned.
+	connCount uint64
+
+	network   string
+	address   string
+	filemode  os.FileMode
+	handlers  map[proto.SCIONDMsg_Which]Handler
+	log       log.Logger
+	accessLog *AccessLog
 
 	mu          sync.Mutex
 	listener    net.Listener
 	closeCalled bool
+	// conns contains the handlers of all open connections.
+	conns map[*ConnHandler]struct{}
 }
 
 // NewServer initializes a new server at address on the specified network. The
@@ -60,9 +69,16 @@ func NewServer(network string, address string, filemode os.FileMode, handlers Ha
 		filemode: filemode,
 		handlers: handlers,
 		log:      logger,
+		conns:    make(map[*ConnHandler]struct{}),
 	}
 }
 
+// SetAccessLog sets the access log that receives a record of every request
+// handled by the server. It must be called before ListenAndServe.
+func (srv *Server) SetAccessLog(al *AccessLog) {
+	srv.accessLog = al
+}
+
 // ListenAndServe starts listening on srv's address, and repeatedly accepts
 // connections from clients. For each accepted connection, a SCIONDMsg server
 // is started as a separate goroutine; the server will manage the connection
@@ -97,6 +113,11 @@ func (srv *Server) ListenAndServe() error {
 			defer log.LogPanicAndExit()
 			pconn := conn.(net.PacketConn)
 			hdl := NewConnHandler(pconn, srv.handlers, srv.log)
+			hdl.AccessLog = srv.accessLog
+			hdl.Network = srv.network
+			hdl.ConnID = atomic.AddUint64(&srv.connCount, 1)
+			srv.addConn(hdl)
+			defer srv.removeConn(hdl)
 			if err := hdl.Serve(); err != nil && err != io.EOF {
 				srv.log.Error("Transport handler error", "err", err)
 			}
@@ -104,6 +125,43 @@ func (srv *Server) ListenAndServe() error {
 	}
 }
 
+func (srv *Server) addConn(hdl *ConnHandler) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.conns[hdl] = struct{}{}
+}
+
+func (srv *Server) removeConn(hdl *ConnHandler) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	delete(srv.conns, hdl)
+}
+
+// NotifyTopoChange pushes a topology change notification for a topology with
+// timestamp ts to all clients that are currently connected to the server.
+func (srv *Server) NotifyTopoChange(ts time.Time) {
+	b, err := proto.PackRoot(&sciond.Pld{
+		Which:                  proto.SCIONDMsg_Which_topoChangeNotification,
+		TopoChangeNotification: sciond.NewTopoChangeNotification(ts),
+	})
+	if err != nil {
+		srv.log.Error("Unable to pack topology change notification", "err", err)
+		return
+	}
+	srv.mu.Lock()
+	conns := make([]*ConnHandler, 0, len(srv.conns))
+	for hdl := range srv.conns {
+		conns = append(conns, hdl)
+	}
+	srv.mu.Unlock()
+	for _, hdl := range conns {
+		if err := hdl.Notify(b); err != nil {
+			srv.log.Warn("Unable to send topology change notification",
+				"conn", hdl.ConnID, "err", err)
+		}
+	}
+}
+
 func (srv *Server) listen() (net.Listener, error) {
 	var listener net.Listener
 	var err error
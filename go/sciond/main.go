@@ -37,16 +37,19 @@ import (
 	"github.com/scionproto/scion/go/lib/infra/modules/itopo"
 	"github.com/scionproto/scion/go/lib/infra/modules/segfetcher"
 	"github.com/scionproto/scion/go/lib/infra/modules/trust"
+	"github.com/scionproto/scion/go/lib/infra/modules/trust/trustdb"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/pathdb"
 	"github.com/scionproto/scion/go/lib/pathstorage"
 	"github.com/scionproto/scion/go/lib/periodic"
 	"github.com/scionproto/scion/go/lib/prom"
 	"github.com/scionproto/scion/go/lib/revcache"
+	"github.com/scionproto/scion/go/lib/sock/reliable"
 	"github.com/scionproto/scion/go/lib/topology"
 	"github.com/scionproto/scion/go/proto"
 	"github.com/scionproto/scion/go/sciond/internal/config"
 	"github.com/scionproto/scion/go/sciond/internal/fetcher"
+	"github.com/scionproto/scion/go/sciond/internal/pathwatch"
 	"github.com/scionproto/scion/go/sciond/internal/servers"
 )
 
@@ -57,6 +60,8 @@ const (
 var (
 	cfg         config.Config
 	discRunners idiscovery.Runners
+	// topoNotifier pushes topology changes to the clients of the API servers.
+	topoNotifier servers.TopoNotifier
 )
 
 func init() {
@@ -123,9 +128,11 @@ func realMain() int {
 		SVC:                   addr.SvcNone,
 		ReconnectToDispatcher: cfg.General.ReconnectToDispatcher,
 		QUIC: infraenv.QUIC{
-			Address:  cfg.QUIC.Address,
-			CertFile: cfg.QUIC.CertFile,
-			KeyFile:  cfg.QUIC.KeyFile,
+			Address:      cfg.QUIC.Address,
+			CertFile:     cfg.QUIC.CertFile,
+			KeyFile:      cfg.QUIC.KeyFile,
+			ClientCAFile: cfg.QUIC.ClientCAFile,
+			AuthTypes:    cfg.QUIC.AuthTypes,
 		},
 		SVCResolutionFraction: cfg.QUIC.ResolutionFraction,
 		TrustStore:            trustStore,
@@ -136,43 +143,101 @@ func realMain() int {
 		log.Crit(infraenv.ErrAppUnableToInitMessenger, "err", err)
 		return 1
 	}
+	var feedback *fetcher.PathFeedback
+	if !cfg.SD.DisablePathFeedback {
+		feedback = fetcher.NewPathFeedback(cfg.SD.PathFeedbackTTL.Duration)
+	}
+	pathFetcher := fetcher.NewFetcher(
+		msger,
+		pathDB,
+		trustStore,
+		revCache,
+		cfg.SD,
+		itopo.Provider(),
+		feedback,
+		log.Root(),
+	)
 	// Route messages to their correct handlers
 	handlers := servers.HandlerMap{
 		proto.SCIONDMsg_Which_pathReq: &servers.PathRequestHandler{
-			Fetcher: fetcher.NewFetcher(
-				msger,
-				pathDB,
-				trustStore,
-				revCache,
-				cfg.SD,
-				itopo.Provider(),
-				log.Root(),
-			),
+			Fetcher: pathFetcher,
 		},
 		proto.SCIONDMsg_Which_asInfoReq: &servers.ASInfoRequestHandler{
 			ASInspector: trustStore,
 		},
-		proto.SCIONDMsg_Which_ifInfoRequest:      &servers.IFInfoRequestHandler{},
-		proto.SCIONDMsg_Which_serviceInfoRequest: &servers.SVCInfoRequestHandler{},
+		proto.SCIONDMsg_Which_ifInfoRequest: &servers.IFInfoRequestHandler{
+			AddressFamily: cfg.SD.AddressFamily,
+		},
+		proto.SCIONDMsg_Which_serviceInfoRequest: &servers.SVCInfoRequestHandler{
+			AddressFamily: cfg.SD.AddressFamily,
+		},
 		proto.SCIONDMsg_Which_revNotification: &servers.RevNotificationHandler{
 			RevCache:         revCache,
 			VerifierFactory:  trustStore,
 			NextQueryCleaner: segfetcher.NextQueryCleaner{PathDB: pathDB},
 		},
+		proto.SCIONDMsg_Which_nextQueryReq: &servers.NextQueryHandler{
+			PathDB: pathDB,
+		},
+		proto.SCIONDMsg_Which_pathFeedbackReq: &servers.PathFeedbackHandler{
+			Feedback: feedback,
+		},
 	}
-	cleaner := periodic.StartPeriodicTask(pathdb.NewCleaner(pathDB),
-		periodic.NewTicker(300*time.Second), 295*time.Second)
+	cleaner := periodic.StartPeriodicTaskWithOptions(pathdb.NewCleaner(pathDB),
+		periodic.NewTicker(300*time.Second), 295*time.Second,
+		periodic.Options{Jitter: 30 * time.Second})
 	defer cleaner.Stop()
-	rcCleaner := periodic.StartPeriodicTask(revcache.NewCleaner(revCache),
-		periodic.NewTicker(10*time.Second), 10*time.Second)
+	rcCleaner := periodic.StartPeriodicTaskWithOptions(revcache.NewCleaner(revCache),
+		periodic.NewTicker(10*time.Second), 10*time.Second,
+		periodic.Options{Jitter: time.Second, MinBackoff: 10 * time.Second,
+			MaxBackoff: 5 * time.Minute})
 	defer rcCleaner.Stop()
+	if len(cfg.SD.WatchList) > 0 {
+		watcher := periodic.StartPeriodicTask(
+			&pathwatch.Watcher{
+				Paths:              pathFetcher,
+				Dsts:               cfg.SD.WatchList,
+				EarlyReplyInterval: servers.DefaultEarlyReply,
+			},
+			periodic.NewTicker(cfg.SD.WatchInterval.Duration),
+			cfg.SD.WatchInterval.Duration,
+		)
+		defer watcher.Stop()
+	}
 	// Start servers
+	var accessLog *servers.AccessLog
+	if cfg.SD.AccessLog != "" {
+		if accessLog, err = servers.OpenAccessLog(cfg.SD.AccessLog); err != nil {
+			log.Crit("Unable to open access log", "err", err)
+			return 1
+		}
+		defer accessLog.Close()
+	}
 	rsockServer, shutdownF := NewServer("rsock", cfg.SD.Reliable, handlers, log.Root())
 	defer shutdownF()
+	rsockServer.SetAccessLog(accessLog)
+	topoNotifier.Add(rsockServer)
 	StartServer("ReliableSockServer", cfg.SD.Reliable, rsockServer)
 	unixpacketServer, shutdownF := NewServer("unixpacket", cfg.SD.Unix, handlers, log.Root())
 	defer shutdownF()
+	unixpacketServer.SetAccessLog(accessLog)
+	topoNotifier.Add(unixpacketServer)
 	StartServer("UnixServer", cfg.SD.Unix, unixpacketServer)
+	if cfg.SD.JSONRPC != "" {
+		jsonServer := servers.NewJSONServer(cfg.SD.JSONRPC,
+			os.FileMode(cfg.SD.SocketFileMode), handlers, log.Root())
+		defer jsonServer.Close()
+		jsonServer.SetAccessLog(accessLog)
+		StartServer("JSONRPCServer", cfg.SD.JSONRPC, jsonServer)
+	}
+	env.AddHealthCheck("pathdb", func(ctx context.Context) error {
+		return pathdb.Ping(ctx, pathDB)
+	})
+	env.AddHealthCheck("trustdb", func(ctx context.Context) error {
+		return trustdb.Ping(ctx, trustDB)
+	})
+	env.AddReadinessCheck("topology", env.TopologyCheck)
+	env.AddReadinessCheck("dispatcher", env.UnixSocketCheck(reliable.DefaultDispPath))
 	cfg.Metrics.StartPrometheus()
 	select {
 	case <-fatal.ShutdownChan():
@@ -200,8 +265,13 @@ func setup() error {
 	if err := cfg.Validate(); err != nil {
 		return common.NewBasicError("Unable to validate config", err)
 	}
-	itopo.Init("", proto.ServiceType_unset, itopo.Callbacks{})
-	topo, err := topology.LoadFromFile(cfg.General.Topology)
+	itopo.Init("", proto.ServiceType_unset, itopo.Callbacks{
+		CleanDynamic: notifyTopoChange,
+		DropDynamic:  notifyTopoChange,
+		UpdateStatic: notifyTopoChange,
+	})
+	topo, err := idiscovery.LoadTopology(cfg.General.Topology, cfg.Discovery,
+		discovery.Default, nil)
 	if err != nil {
 		return common.NewBasicError("Unable to load topology", err)
 	}
@@ -215,10 +285,28 @@ func setup() error {
 func startDiscovery() error {
 	var err error
 	discRunners, err = idiscovery.StartRunners(cfg.Discovery, discovery.Default,
-		idiscovery.TopoHandlers{}, nil)
+		idiscovery.TopoHandlers{Dynamic: setDynamic}, nil)
 	return err
 }
 
+// setDynamic sets the dynamic topology in itopo. Contrary to the static
+// topology, itopo has no callback for dynamic updates, thus the clients are
+// notified here.
+func setDynamic(topo *topology.Topo) (bool, error) {
+	_, updated, err := itopo.SetDynamic(topo)
+	if updated {
+		notifyTopoChange()
+	}
+	return updated, err
+}
+
+// notifyTopoChange pushes a topology change notification to all connected API
+// clients.
+func notifyTopoChange() {
+	log.Info("Topology changed, notifying API clients")
+	topoNotifier.Notify(itopo.Get().Timestamp)
+}
+
 func NewServer(network string, rsockPath string, handlers servers.HandlerMap,
 	logger log.Logger) (*servers.Server, func()) {
 
@@ -232,7 +320,7 @@ func NewServer(network string, rsockPath string, handlers servers.HandlerMap,
 	return server, shutdownF
 }
 
-func StartServer(name, sockPath string, server *servers.Server) {
+func StartServer(name, sockPath string, server interface{ ListenAndServe() error }) {
 	go func() {
 		defer log.LogPanicAndExit()
 		if cfg.SD.DeleteSocket {
@@ -19,14 +19,27 @@ package config
 import (
 	"encoding/json"
 	"io/ioutil"
+	"sort"
 
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/pathpol"
+	"github.com/scionproto/scion/go/lib/pktcls"
+	"github.com/scionproto/scion/go/sig/mgmt"
 )
 
+// DefaultSessId is the ID of the session that carries all traffic to a remote
+// AS that is not matched by any of the AS's packet policies. The default
+// session always exists, even if it is not listed in the AS's sessions.
+const DefaultSessId mgmt.SessionType = 0
+
 // Cfg is a direct Go representation of the JSON file format.
 type Cfg struct {
-	ASes          map[addr.IA]*ASEntry
+	ASes map[addr.IA]*ASEntry
+	// Classes are the traffic classes packet policies refer to, keyed by name.
+	Classes pktcls.ClassMap `json:",omitempty"`
+	// PathPolicies are the path policies sessions refer to, keyed by name.
+	PathPolicies  pathpol.PolicyMap `json:",omitempty"`
 	ConfigVersion uint64
 }
 
@@ -40,9 +53,121 @@ func LoadFromFile(path string) (*Cfg, error) {
 	if err := json.Unmarshal(b, cfg); err != nil {
 		return nil, common.NewBasicError("Unable to parse SIG config", err)
 	}
+	if err := cfg.init(); err != nil {
+		return nil, common.NewBasicError("Invalid SIG config", err)
+	}
 	return cfg, nil
 }
 
+// init names the path policies after their keys and validates the config.
+func (cfg *Cfg) init() error {
+	for name, policy := range cfg.PathPolicies {
+		if policy == nil {
+			policy = &pathpol.ExtPolicy{}
+			cfg.PathPolicies[name] = policy
+		}
+		if policy.Policy == nil {
+			policy.Policy = &pathpol.Policy{}
+		}
+		policy.Name = name
+	}
+	return cfg.Validate()
+}
+
+// Validate checks that all references between ASes, classes and path
+// policies can be resolved.
+func (cfg *Cfg) Validate() error {
+	for name := range cfg.PathPolicies {
+		if _, err := cfg.PathPolicy(name); err != nil {
+			return err
+		}
+	}
+	for ia, entry := range cfg.ASes {
+		if entry == nil {
+			return common.NewBasicError("Empty AS entry", nil, "ia", ia)
+		}
+		if err := entry.validate(cfg); err != nil {
+			return common.NewBasicError("Invalid AS entry", err, "ia", ia)
+		}
+	}
+	return nil
+}
+
+// PathPolicy returns the compiled path policy with the specified name. The
+// empty name refers to no policy, in which case nil is returned.
+func (cfg *Cfg) PathPolicy(name string) (*pathpol.Policy, error) {
+	if name == "" {
+		return nil, nil
+	}
+	extPolicy, ok := cfg.PathPolicies[name]
+	if !ok {
+		return nil, common.NewBasicError("Unknown path policy", nil, "name", name)
+	}
+	extended := make([]*pathpol.ExtPolicy, 0, len(cfg.PathPolicies))
+	for _, policy := range cfg.PathPolicies {
+		extended = append(extended, policy)
+	}
+	return pathpol.PolicyFromExtPolicy(extPolicy, extended)
+}
+
 type ASEntry struct {
 	Nets []*IPNet
+	// Sessions maps the IDs of the sessions to the remote AS to the name of
+	// the path policy that restricts the paths the session uses. An empty
+	// name means that the session can use all paths.
+	Sessions map[mgmt.SessionType]string `json:",omitempty"`
+	// PktPolicies map traffic classes to sessions. Packets are matched
+	// against the policies in order, the first matching policy determines the
+	// sessions a packet can be sent on. Packets that match no policy are sent
+	// on the default session.
+	PktPolicies []*PktPolicy `json:",omitempty"`
+}
+
+// SessionIds returns the IDs of all sessions to the remote AS, including the
+// default session.
+func (ae *ASEntry) SessionIds() []mgmt.SessionType {
+	ids := []mgmt.SessionType{DefaultSessId}
+	for id := range ae.Sessions {
+		if id != DefaultSessId {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func (ae *ASEntry) validate(cfg *Cfg) error {
+	for id, name := range ae.Sessions {
+		if _, err := cfg.PathPolicy(name); err != nil {
+			return common.NewBasicError("Invalid session", err, "sessId", id)
+		}
+	}
+	for _, pktPol := range ae.PktPolicies {
+		if pktPol == nil {
+			return common.NewBasicError("Empty packet policy", nil)
+		}
+		if _, ok := cfg.Classes[pktPol.ClassName]; !ok {
+			return common.NewBasicError("Unknown traffic class", nil,
+				"class", pktPol.ClassName)
+		}
+		if len(pktPol.SessIds) == 0 {
+			return common.NewBasicError("Packet policy without sessions", nil,
+				"class", pktPol.ClassName)
+		}
+		for _, id := range pktPol.SessIds {
+			if _, ok := ae.Sessions[id]; !ok && id != DefaultSessId {
+				return common.NewBasicError("Unknown session in packet policy", nil,
+					"class", pktPol.ClassName, "sessId", id)
+			}
+		}
+	}
+	return nil
+}
+
+// PktPolicy maps a traffic class to the sessions that carry it. Packets of
+// the class are striped across the healthy sessions in the list. If none of
+// the sessions is healthy, the packets fail over to the default session.
+type PktPolicy struct {
+	ClassName string
+	SessIds   []mgmt.SessionType
 }
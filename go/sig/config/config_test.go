@@ -15,6 +15,7 @@
 package config
 
 import (
+	"encoding/json"
 	"flag"
 	"net"
 	"path/filepath"
@@ -23,7 +24,10 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 
 	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/pathpol"
+	"github.com/scionproto/scion/go/lib/pktcls"
 	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/sig/mgmt"
 )
 
 var (
@@ -71,6 +75,54 @@ func TestLoadFromFile(t *testing.T) {
 				ConfigVersion: 9001,
 			},
 		},
+		{
+			Name:     "policies",
+			FileName: "02-policies",
+			Config: Cfg{
+				ASes: map[addr.IA]*ASEntry{
+					xtest.MustParseIA("1-ff00:0:1"): {
+						Nets: []*IPNet{
+							{
+								IP:   net.IP{192, 0, 2, 0},
+								Mask: net.CIDRMask(24, 8*net.IPv4len),
+							},
+						},
+						Sessions: map[mgmt.SessionType]string{
+							0: "",
+							1: "no_110",
+							2: "no_110",
+						},
+						PktPolicies: []*PktPolicy{
+							{
+								ClassName: "voice",
+								SessIds:   []mgmt.SessionType{1, 2},
+							},
+						},
+					},
+				},
+				Classes: pktcls.ClassMap{
+					"voice": pktcls.NewClass("voice",
+						pktcls.NewCondIPv4(&pktcls.IPv4MatchDSCP{DSCP: 0x2e})),
+				},
+				PathPolicies: pathpol.PolicyMap{
+					"no_110": &pathpol.ExtPolicy{
+						Policy: &pathpol.Policy{
+							Name: "no_110",
+							ACL: &pathpol.ACL{
+								Entries: []*pathpol.ACLEntry{
+									{
+										Action: pathpol.Deny,
+										Rule:   mustHopPredicate(t, "1-ff00:0:110#0"),
+									},
+									{Action: pathpol.Allow},
+								},
+							},
+						},
+					},
+				},
+				ConfigVersion: 1,
+			},
+		},
 	}
 
 	Convey("Test SIG config marshal/unmarshal", t, func() {
@@ -88,6 +140,90 @@ func TestLoadFromFile(t *testing.T) {
 	})
 }
 
+func TestCfgInit(t *testing.T) {
+	testCases := []struct {
+		Name  string
+		Error bool
+		JSON  string
+	}{
+		{
+			Name:  "No policies",
+			Error: false,
+			JSON:  `{"ASes": {"1-ff00:0:1": {"Nets": []}}}`,
+		},
+		{
+			Name:  "Empty AS entry",
+			Error: true,
+			JSON:  `{"ASes": {"1-ff00:0:1": null}}`,
+		},
+		{
+			Name:  "Session without path policy",
+			Error: false,
+			JSON:  `{"ASes": {"1-ff00:0:1": {"Sessions": {"1": ""}}}}`,
+		},
+		{
+			Name:  "Unknown path policy",
+			Error: true,
+			JSON:  `{"ASes": {"1-ff00:0:1": {"Sessions": {"1": "foo"}}}}`,
+		},
+		{
+			Name:  "Unknown extended path policy",
+			Error: true,
+			JSON:  `{"PathPolicies": {"foo": {"extends": ["bar"]}}}`,
+		},
+		{
+			Name:  "Packet policy on default session",
+			Error: false,
+			JSON: `{"ASes": {"1-ff00:0:1": {"PktPolicies": [
+				{"ClassName": "any", "SessIds": [0]}]}},
+				"Classes": {"any": {"CondBool": true}}}`,
+		},
+		{
+			Name:  "Unknown class",
+			Error: true,
+			JSON: `{"ASes": {"1-ff00:0:1": {"PktPolicies": [
+				{"ClassName": "any", "SessIds": [0]}]}}}`,
+		},
+		{
+			Name:  "Unknown session",
+			Error: true,
+			JSON: `{"ASes": {"1-ff00:0:1": {"PktPolicies": [
+				{"ClassName": "any", "SessIds": [1]}]}},
+				"Classes": {"any": {"CondBool": true}}}`,
+		},
+		{
+			Name:  "No sessions",
+			Error: true,
+			JSON: `{"ASes": {"1-ff00:0:1": {"PktPolicies": [
+				{"ClassName": "any"}]}},
+				"Classes": {"any": {"CondBool": true}}}`,
+		},
+	}
+
+	Convey("Test SIG config references", t, func() {
+		for _, tc := range testCases {
+			Convey(tc.Name, func() {
+				cfg := &Cfg{}
+				SoMsg("json", json.Unmarshal([]byte(tc.JSON), cfg), ShouldBeNil)
+				xtest.SoMsgError("err", cfg.init(), tc.Error)
+			})
+		}
+	})
+}
+
+func TestASEntrySessionIds(t *testing.T) {
+	Convey("Session IDs include the default session", t, func() {
+		ae := &ASEntry{Sessions: map[mgmt.SessionType]string{3: "", 1: ""}}
+		SoMsg("ids", ae.SessionIds(), ShouldResemble, []mgmt.SessionType{0, 1, 3})
+	})
+}
+
+func mustHopPredicate(t *testing.T, str string) *pathpol.HopPredicate {
+	hp, err := pathpol.HopPredicateFromString(str)
+	xtest.FailOnErr(t, err)
+	return hp
+}
+
 func TestIPNetUnmarshalJSON(t *testing.T) {
 	testCases := []struct {
 		Name  string
@@ -24,6 +24,8 @@ import (
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/pathmgr"
+	"github.com/scionproto/scion/go/lib/pathpol"
 	"github.com/scionproto/scion/go/lib/ringbuf"
 	"github.com/scionproto/scion/go/sig/base"
 	"github.com/scionproto/scion/go/sig/config"
@@ -32,6 +34,7 @@ import (
 	"github.com/scionproto/scion/go/sig/egress/router"
 	"github.com/scionproto/scion/go/sig/egress/selector"
 	"github.com/scionproto/scion/go/sig/egress/session"
+	"github.com/scionproto/scion/go/sig/mgmt"
 )
 
 const (
@@ -49,7 +52,16 @@ type ASEntry struct {
 	version           uint64 // used to track certain changes made to ASEntry
 	log.Logger
 
+	// Session is the default session, which carries all traffic that is not
+	// matched by a packet policy.
 	Session *session.Session
+	// sessions contains all sessions to the remote AS, including the
+	// default session, keyed by their ID.
+	sessions map[mgmt.SessionType]*session.Session
+	// sessPolicies contains the names of the path policies the sessions
+	// were created with.
+	sessPolicies map[mgmt.SessionType]string
+	selector     *selector.PolicySelector
 }
 
 func newASEntry(ia addr.IA) (*ASEntry, error) {
@@ -59,16 +71,17 @@ func newASEntry(ia addr.IA) (*ASEntry, error) {
 		IAString:          ia.String(),
 		Nets:              make(map[string]*net.IPNet),
 		healthMonitorStop: make(chan struct{}),
+		sessions:          make(map[mgmt.SessionType]*session.Session),
+		sessPolicies:      make(map[mgmt.SessionType]string),
 	}
 	var err error
-	pool, err := session.NewPathPool(ia)
-	if err != nil {
-		return nil, err
-	}
-	ae.Session, err = session.NewSession(ia, 0, ae.Logger, pool)
+	ae.Session, err = ae.newSession(config.DefaultSessId, nil)
 	if err != nil {
 		return nil, err
 	}
+	ae.sessions[config.DefaultSessId] = ae.Session
+	ae.sessPolicies[config.DefaultSessId] = ""
+	ae.selector = selector.NewPolicySelector(ae.Session)
 	return ae, nil
 }
 
@@ -76,10 +89,119 @@ func (ae *ASEntry) ReloadConfig(cfg *config.Cfg, cfgEntry *config.ASEntry) bool
 	ae.Lock()
 	defer ae.Unlock()
 	// Method calls first to prevent skips due to logical short-circuit
-	s := ae.addNewNets(cfgEntry.Nets)
+	s := ae.reloadSessions(cfg, cfgEntry)
+	s = ae.addNewNets(cfgEntry.Nets) && s
 	return ae.delOldNets(cfgEntry.Nets) && s
 }
 
+// reloadSessions creates the sessions in cfgEntry that do not exist yet,
+// recreates the sessions whose path policy changed, updates the packet
+// policies, and finally removes the sessions that are no longer configured.
+func (ae *ASEntry) reloadSessions(cfg *config.Cfg, cfgEntry *config.ASEntry) bool {
+	s := true
+	sessions := make(map[mgmt.SessionType]*session.Session)
+	var stale []*session.Session
+	for _, id := range cfgEntry.SessionIds() {
+		polName := cfgEntry.Sessions[id]
+		if sess, ok := ae.sessions[id]; ok && ae.sessPolicies[id] == polName {
+			sessions[id] = sess
+			continue
+		}
+		policy, err := cfg.PathPolicy(polName)
+		if err != nil {
+			ae.Error("Unable to load path policy", "sessId", id, "policy", polName, "err", err)
+			s = false
+			if sess, ok := ae.sessions[id]; ok {
+				// Keep the session with the previous policy.
+				sessions[id] = sess
+			}
+			continue
+		}
+		sess, err := ae.newSession(id, policy)
+		if err != nil {
+			ae.Error("Unable to create session", "sessId", id, "err", err)
+			s = false
+			if sess, ok := ae.sessions[id]; ok {
+				sessions[id] = sess
+			}
+			continue
+		}
+		if old, ok := ae.sessions[id]; ok {
+			stale = append(stale, old)
+		}
+		if ae.egressRing != nil {
+			sess.Start()
+		}
+		sessions[id] = sess
+		ae.sessPolicies[id] = polName
+		ae.Info("Added session", "sessId", id, "policy", polName)
+	}
+	for id, sess := range ae.sessions {
+		if _, ok := sessions[id]; !ok {
+			stale = append(stale, sess)
+			delete(ae.sessPolicies, id)
+		}
+	}
+	ae.sessions = sessions
+	ae.Session = sessions[config.DefaultSessId]
+	ae.selector.Update(ae.classPolicies(cfg, cfgEntry), ae.Session)
+	// The stale sessions are only cleaned up once the selector no longer
+	// hands them out.
+	for _, sess := range stale {
+		if err := sess.Cleanup(); err != nil {
+			sess.Error("Error cleaning up session", "err", err)
+		}
+		ae.Info("Removed session", "sessId", sess.ID())
+	}
+	return s
+}
+
+// classPolicies resolves the packet policies of cfgEntry to the current
+// sessions.
+func (ae *ASEntry) classPolicies(cfg *config.Cfg,
+	cfgEntry *config.ASEntry) []*selector.ClassPolicy {
+
+	var policies []*selector.ClassPolicy
+	for _, pktPol := range cfgEntry.PktPolicies {
+		class, ok := cfg.Classes[pktPol.ClassName]
+		if !ok {
+			ae.Error("Unknown traffic class", "class", pktPol.ClassName)
+			continue
+		}
+		cp := &selector.ClassPolicy{Class: class}
+		for _, id := range pktPol.SessIds {
+			if sess, ok := ae.sessions[id]; ok {
+				cp.Sessions = append(cp.Sessions, sess)
+			}
+		}
+		if len(cp.Sessions) > 0 {
+			policies = append(policies, cp)
+		}
+	}
+	return policies
+}
+
+func (ae *ASEntry) newSession(id mgmt.SessionType,
+	policy *pathpol.Policy) (*session.Session, error) {
+
+	// Only pass non-nil policies, such that unfiltered pools are polled like
+	// regular watches.
+	var filter pathmgr.Policy
+	if policy != nil {
+		filter = policy
+	}
+	pool, err := session.NewPathPool(ae.IA, filter)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := session.NewSession(ae.IA, id, ae.Logger, pool)
+	if err != nil {
+		pool.Destroy()
+		return nil, err
+	}
+	return sess, nil
+}
+
 // addNewNets adds the networks in ipnets that are not currently configured.
 func (ae *ASEntry) addNewNets(ipnets []*config.IPNet) bool {
 	s := true
@@ -225,8 +347,10 @@ func (ae *ASEntry) Cleanup() error {
 }
 
 func (ae *ASEntry) cleanSessions() {
-	if err := ae.Session.Cleanup(); err != nil {
-		ae.Session.Error("Error cleaning up session", "err", err)
+	for _, sess := range ae.sessions {
+		if err := sess.Cleanup(); err != nil {
+			sess.Error("Error cleaning up session", "err", err)
+		}
 	}
 }
 
@@ -234,13 +358,14 @@ func (ae *ASEntry) setupNet() {
 	ae.egressRing = ringbuf.New(iface.EgressRemotePkts, nil, fmt.Sprintf("egress_%s", ae.IAString))
 	go func() {
 		defer log.LogPanicAndExit()
-		dispatcher.NewDispatcher(ae.IA, ae.egressRing,
-			&selector.SingleSession{Session: ae.Session}).Run()
+		dispatcher.NewDispatcher(ae.IA, ae.egressRing, ae.selector).Run()
 	}()
 	go func() {
 		defer log.LogPanicAndExit()
 		ae.monitorHealth()
 	}()
-	ae.Session.Start()
+	for _, sess := range ae.sessions {
+		sess.Start()
+	}
 	ae.Info("Network setup done")
 }
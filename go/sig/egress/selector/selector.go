@@ -16,7 +16,10 @@
 package selector
 
 import (
+	"sync/atomic"
+
 	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/pktcls"
 	"github.com/scionproto/scion/go/sig/egress/iface"
 )
 
@@ -31,3 +34,73 @@ type SingleSession struct {
 func (ss *SingleSession) ChooseSess(b common.RawBytes) iface.Session {
 	return ss.Session
 }
+
+var _ iface.SessionSelector = (*PolicySelector)(nil)
+
+// ClassPolicy maps a traffic class to the sessions that carry it.
+type ClassPolicy struct {
+	Class    *pktcls.Class
+	Sessions []iface.Session
+	// next is the index of the session the next packet of the class is
+	// striped onto.
+	next uint32
+}
+
+// choose returns the next healthy session of the policy in round-robin
+// order, or nil if none of the sessions is healthy.
+func (cp *ClassPolicy) choose() iface.Session {
+	n := uint32(len(cp.Sessions))
+	start := atomic.AddUint32(&cp.next, 1) - 1
+	for i := uint32(0); i < n; i++ {
+		if sess := cp.Sessions[(start+i)%n]; sess.Healthy() {
+			return sess
+		}
+	}
+	return nil
+}
+
+type policySet struct {
+	classes []*ClassPolicy
+	def     iface.Session
+}
+
+// PolicySelector implements iface.SessionSelector. It classifies packets
+// according to its class policies, which are evaluated in order. Packets of a
+// class are striped across the healthy sessions of the first matching
+// policy. Packets that match no policy, or whose policy has no healthy
+// session, are sent on the default session. The policies can be replaced at
+// any time with Update.
+type PolicySelector struct {
+	set atomic.Value
+}
+
+// NewPolicySelector returns a selector that sends all packets on def.
+func NewPolicySelector(def iface.Session) *PolicySelector {
+	ps := &PolicySelector{}
+	ps.Update(nil, def)
+	return ps
+}
+
+// Update replaces the class policies and the default session of the
+// selector. It is safe to call Update concurrently with ChooseSess.
+func (ps *PolicySelector) Update(classes []*ClassPolicy, def iface.Session) {
+	ps.set.Store(&policySet{classes: classes, def: def})
+}
+
+func (ps *PolicySelector) ChooseSess(b common.RawBytes) iface.Session {
+	set := ps.set.Load().(*policySet)
+	if len(set.classes) == 0 {
+		return set.def
+	}
+	pkt := pktcls.NewPacket(b)
+	for _, cp := range set.classes {
+		if !cp.Class.Eval(pkt) {
+			continue
+		}
+		if sess := cp.choose(); sess != nil {
+			return sess
+		}
+		break
+	}
+	return set.def
+}
@@ -0,0 +1,89 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/pktcls"
+	"github.com/scionproto/scion/go/sig/egress/iface"
+	"github.com/scionproto/scion/go/sig/egress/iface/mock_iface"
+)
+
+func TestPolicySelector(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	def := mock_iface.NewMockSession(ctrl)
+	s1, s2 := mock_iface.NewMockSession(ctrl), mock_iface.NewMockSession(ctrl)
+	healthy := map[iface.Session]bool{s1: true, s2: true}
+	for _, s := range []*mock_iface.MockSession{s1, s2} {
+		s := s
+		s.EXPECT().Healthy().DoAndReturn(func() bool { return healthy[s] }).AnyTimes()
+	}
+	voice := pktcls.NewClass("voice", pktcls.NewCondIPv4(&pktcls.IPv4MatchDSCP{DSCP: 0x2e}))
+	voicePkt := newTestPacket(t, 0x2e<<2)
+	otherPkt := newTestPacket(t, 0)
+
+	ps := NewPolicySelector(def)
+	assert.Equal(t, def, ps.ChooseSess(voicePkt), "no policies")
+
+	ps.Update([]*ClassPolicy{{Class: voice, Sessions: []iface.Session{s1, s2}}}, def)
+	t.Run("striping", func(t *testing.T) {
+		assert.Equal(t, s1, ps.ChooseSess(voicePkt))
+		assert.Equal(t, s2, ps.ChooseSess(voicePkt))
+		assert.Equal(t, s1, ps.ChooseSess(voicePkt))
+	})
+	t.Run("unmatched", func(t *testing.T) {
+		assert.Equal(t, def, ps.ChooseSess(otherPkt))
+	})
+	t.Run("failover to healthy session", func(t *testing.T) {
+		healthy[s1] = false
+		assert.Equal(t, s2, ps.ChooseSess(voicePkt))
+		assert.Equal(t, s2, ps.ChooseSess(voicePkt))
+	})
+	t.Run("failover to default session", func(t *testing.T) {
+		healthy[s2] = false
+		assert.Equal(t, def, ps.ChooseSess(voicePkt))
+	})
+}
+
+func newTestPacket(t *testing.T, tos uint8) common.RawBytes {
+	buf := gopacket.NewSerializeBuffer()
+	err := gopacket.SerializeLayers(
+		buf,
+		gopacket.SerializeOptions{FixLengths: true},
+		&layers.IPv4{
+			Version:  4,
+			IHL:      5,
+			TOS:      tos,
+			TTL:      64,
+			Protocol: layers.IPProtocolUDP,
+			SrcIP:    []byte{192, 0, 2, 1},
+			DstIP:    []byte{198, 51, 100, 1},
+		},
+		gopacket.Payload([]byte{1, 2, 3, 4}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
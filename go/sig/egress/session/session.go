@@ -33,6 +33,7 @@ import (
 	"github.com/scionproto/scion/go/lib/spath/spathmeta"
 	"github.com/scionproto/scion/go/sig/egress/iface"
 	"github.com/scionproto/scion/go/sig/egress/worker"
+	"github.com/scionproto/scion/go/sig/metrics"
 	"github.com/scionproto/scion/go/sig/mgmt"
 	"github.com/scionproto/scion/go/sig/sigcmn"
 )
@@ -59,6 +60,8 @@ type Session struct {
 	pktDispStop    chan struct{}
 	pktDispStopped chan struct{}
 	workerStopped  chan struct{}
+	// started is set once the session monitor and worker are running.
+	started bool
 }
 
 func NewSession(dstIA addr.IA, sessId mgmt.SessionType, logger log.Logger,
@@ -72,7 +75,7 @@ func NewSession(dstIA addr.IA, sessId mgmt.SessionType, logger log.Logger,
 		pool:   pool,
 	}
 	s.currRemote.Store((*iface.RemoteInfo)(nil))
-	s.healthy.Store(false)
+	s.setHealthy(false)
 	s.ring = ringbuf.New(64, nil, fmt.Sprintf("egress_%s_%s", dstIA, sessId))
 	// Not using a fixed local port, as this is for outgoing data only.
 	s.conn, err = snet.ListenSCION("udp4",
@@ -92,6 +95,7 @@ func NewSession(dstIA addr.IA, sessId mgmt.SessionType, logger log.Logger,
 }
 
 func (s *Session) Start() {
+	s.started = true
 	go func() {
 		defer log.LogPanicAndExit()
 		newSessMonitor(s).run()
@@ -105,10 +109,13 @@ func (s *Session) Start() {
 func (s *Session) Cleanup() error {
 	s.ring.Close()
 	close(s.sessMonStop)
-	s.Debug("iface.Session Cleanup: wait for worker")
-	<-s.workerStopped
-	s.Debug("iface.Session Cleanup: wait for session monitor")
-	<-s.sessMonStopped
+	// Sessions that were never started have no worker and monitor to wait for.
+	if s.started {
+		s.Debug("iface.Session Cleanup: wait for worker")
+		<-s.workerStopped
+		s.Debug("iface.Session Cleanup: wait for session monitor")
+		<-s.sessMonStopped
+	}
 	close(s.pktDispStop)
 	s.Debug("iface.Session Cleanup: wait for pktDisp")
 	s.conn.SetReadDeadline(time.Now())
@@ -120,6 +127,8 @@ func (s *Session) Cleanup() error {
 	if err := s.pool.Destroy(); err != nil {
 		return common.NewBasicError("Error destroying path pool", err)
 	}
+	s.setRemote(nil)
+	metrics.SessionHealthy.DeleteLabelValues(s.ia.String(), s.SessId.String())
 	return nil
 }
 
@@ -144,10 +153,42 @@ func (s *Session) ID() mgmt.SessionType {
 }
 
 func (s *Session) Healthy() bool {
-	// FIxME(kormat): export as metric.
 	return s.healthy.Load().(bool)
 }
 
+func (s *Session) setHealthy(healthy bool) {
+	s.healthy.Store(healthy)
+	v := 0.0
+	if healthy {
+		v = 1
+	}
+	metrics.SessionHealthy.WithLabelValues(s.ia.String(), s.SessId.String()).Set(v)
+}
+
+// setRemote updates the remote of the session and the metric of the path in
+// use.
+func (s *Session) setRemote(remote *iface.RemoteInfo) {
+	old := s.Remote()
+	s.currRemote.Store(remote)
+	oldPath, newPath := pathLabel(old), pathLabel(remote)
+	if oldPath == newPath {
+		return
+	}
+	if oldPath != "" {
+		metrics.SessionPath.DeleteLabelValues(s.ia.String(), s.SessId.String(), oldPath)
+	}
+	if newPath != "" {
+		metrics.SessionPath.WithLabelValues(s.ia.String(), s.SessId.String(), newPath).Set(1)
+	}
+}
+
+func pathLabel(remote *iface.RemoteInfo) string {
+	if remote == nil || remote.SessPath == nil {
+		return ""
+	}
+	return remote.SessPath.Key().String()
+}
+
 func (s *Session) PathPool() iface.PathPool {
 	return s.pool
 }
@@ -163,8 +204,10 @@ type PathPool struct {
 
 var _ iface.PathPool = (*PathPool)(nil)
 
-func NewPathPool(dst addr.IA) (*PathPool, error) {
-	pool, err := sigcmn.PathMgr.Watch(context.TODO(), sigcmn.IA, dst)
+// NewPathPool creates a pool of paths to dst. If policy is not nil, the pool
+// only contains the paths the policy accepts.
+func NewPathPool(dst addr.IA, policy pathmgr.Policy) (*PathPool, error) {
+	pool, err := sigcmn.PathMgr.WatchFilter(context.TODO(), sigcmn.IA, dst, policy)
 	if err != nil {
 		return nil, common.NewBasicError("Unable to register watch", err)
 	}
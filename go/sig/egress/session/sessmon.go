@@ -143,7 +143,12 @@ func (sm *sessMonitor) updateRemote() {
 		metrics.SessionTimedOut.WithLabelValues(
 			sm.sess.IA().String(),
 			sm.sess.SessId.String()).Inc()
-		sm.sess.healthy.Store(false)
+		if sm.sess.Healthy() {
+			metrics.SessionFailovers.WithLabelValues(
+				sm.sess.IA().String(),
+				sm.sess.SessId.String()).Inc()
+		}
+		sm.sess.setHealthy(false)
 		if sm.smRemote.SessPath != nil {
 			// Update path statistics. This is a bit of a stretch. The path
 			// may be OK, but the remote SIG may be down. However, we accept
@@ -165,7 +170,7 @@ func (sm *sessMonitor) updateRemote() {
 	// but also when the pool is empty. Try to get a new path.
 	if sm.smRemote.SessPath == nil {
 		sm.Info("sessMonitor: Path not available", "remote", sm.smRemote)
-		sm.sess.healthy.Store(false)
+		sm.sess.setHealthy(false)
 		// Start monitoring the new path.
 		sm.smRemote.SessPath = sm.getNewPath(sm.smRemote.SessPath)
 		sm.updateSessSnap()
@@ -217,7 +222,7 @@ func (sm *sessMonitor) updateSessSnap() {
 		}
 		remote.Sig = old.Sig
 	}
-	sm.sess.currRemote.Store(remote)
+	sm.sess.setRemote(remote)
 }
 
 func (sm *sessMonitor) getNewPath(old *iface.SessPath) *iface.SessPath {
@@ -321,7 +326,7 @@ func (sm *sessMonitor) handleRep(rpld *disp.RegPld) {
 			sm.updateSessSnap()
 			sm.Info("sessMonitor: updating remote Info", "msgId", rpld.Id, "remote", sm.smRemote)
 		}
-		sm.sess.healthy.Store(true)
+		sm.sess.setHealthy(true)
 	} else {
 		// This is going to happen if latency of the path is greater than the poll ticker period.
 		sm.Info("Reply to an old request received", "request", sm.updateMsgId, "reply", rpld.Id)
@@ -19,6 +19,8 @@ package worker
 import (
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/l4"
 	"github.com/scionproto/scion/go/lib/log"
@@ -69,6 +71,12 @@ type worker struct {
 	currSig       *siginfo.Sig
 	currPathEntry *sciond.PathReplyEntry
 	frameSentCtrs metrics.CtrPair
+	encapCtrs     metrics.CtrPair
+	// noPathDrops, noRemoteDrops and writeErrDrops count the frames that
+	// could not be sent, by reason.
+	noPathDrops   prometheus.Counter
+	noRemoteDrops prometheus.Counter
+	writeErrDrops prometheus.Counter
 
 	epoch uint16
 	seq   uint32
@@ -84,16 +92,25 @@ type worker struct {
 func NewWorker(sess iface.Session, writer SCIONWriter, ignoreAddress bool,
 	logger log.Logger) *worker {
 
+	ia, sessId := sess.IA().String(), sess.ID().String()
 	return &worker{
 		Logger:        logger,
-		iaString:      sess.IA().String(),
+		iaString:      ia,
 		sess:          sess,
 		writer:        writer,
 		ignoreAddress: ignoreAddress,
 		frameSentCtrs: metrics.CtrPair{
-			Pkts:  metrics.FramesSent.WithLabelValues(sess.IA().String(), sess.ID().String()),
-			Bytes: metrics.FrameBytesSent.WithLabelValues(sess.IA().String(), sess.ID().String()),
+			Pkts:  metrics.FramesSent.WithLabelValues(ia, sessId),
+			Bytes: metrics.FrameBytesSent.WithLabelValues(ia, sessId),
+		},
+		encapCtrs: metrics.CtrPair{
+			Pkts:  metrics.PktsEncap.WithLabelValues(ia, sessId),
+			Bytes: metrics.PktBytesEncap.WithLabelValues(ia, sessId),
 		},
+		noPathDrops:   metrics.FramesDropped.WithLabelValues(ia, sessId, metrics.DropNoPath),
+		noRemoteDrops: metrics.FramesDropped.WithLabelValues(ia, sessId, metrics.DropNoRemote),
+		writeErrDrops: metrics.FramesDropped.WithLabelValues(ia, sessId,
+			metrics.DropWriteError),
 		pkts: make(ringbuf.EntryList, 0, iface.EgressBufPkts),
 	}
 }
@@ -153,6 +170,8 @@ func (w *worker) processPkt(f *frame, pkt common.RawBytes) error {
 		}
 		if pktOff == len(pkt) {
 			// This packet is now finished, time to get a new one.
+			w.encapCtrs.Pkts.Inc()
+			w.encapCtrs.Bytes.Add(float64(len(pkt)))
 			return nil
 		}
 		// Otherwise continue copying packet into next frame.
@@ -192,11 +211,11 @@ func (w *worker) write(f *frame) error {
 	var snetAddr *snet.Addr
 	if !w.ignoreAddress {
 		if w.currPathEntry == nil {
-			// FIXME(kormat): add some metrics to track this.
+			w.noPathDrops.Inc()
 			return nil
 		}
 		if w.currSig == nil {
-			// FIXME(kormat): add some metrics to track this.
+			w.noRemoteDrops.Inc()
 			return nil
 		}
 		snetAddr = w.currSig.EncapSnetAddr()
@@ -214,6 +233,7 @@ func (w *worker) write(f *frame) error {
 	f.writeHdr(w.sess.ID(), w.epoch, seq)
 	bytesWritten, err := w.writer.WriteToSCION(f.raw(), snetAddr)
 	if err != nil {
+		w.writeErrDrops.Inc()
 		return common.NewBasicError("Egress write error", err)
 	}
 	w.frameSentCtrs.Pkts.Inc()
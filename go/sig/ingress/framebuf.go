@@ -22,6 +22,7 @@ import (
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/ringbuf"
 	"github.com/scionproto/scion/go/lib/util"
+	"github.com/scionproto/scion/go/sig/metrics"
 	"github.com/scionproto/scion/go/sig/mgmt"
 )
 
@@ -128,6 +129,7 @@ func (fb *FrameBuf) ProcessCompletePkts() {
 		//	"offset", offset, "len", pktLen)
 		if err := fb.snd.send(rawPkt[:pktLen]); err != nil {
 			log.Error("Unable to send packet", "err", err)
+			fb.snd.reassemblyError(metrics.ReassemblySendError)
 		}
 		offset += pktLen
 		// Packet always starts at 8-byte boundary.
@@ -125,6 +125,7 @@ func (l *ReassemblyList) tryReassemble() {
 		// Should never happen.
 		log.Error("First frame in reassembly list does not contain a packet start.",
 			"frame", startFrame.String())
+		l.snd.reassemblyError(metrics.ReassemblyNoPktStart)
 		// Safest to remove all frames in the list.
 		l.removeAll()
 		return
@@ -147,6 +148,7 @@ func (l *ReassemblyList) tryReassemble() {
 			log.Error("Framing error occurred. Not enough bytes to reassemble packet",
 				"startFrame", startFrame.String(), "currFrame", currFrame.String(),
 				"pktLen", startFrame.pktLen)
+			l.snd.reassemblyError(metrics.ReassemblyFraming)
 			framingError = true
 			break
 		}
@@ -184,10 +186,12 @@ func (l *ReassemblyList) collectAndWrite() {
 	if l.buf.Len() != pktLen {
 		log.Error("Packet len for reassembled packet does not match header",
 			"expected", pktLen, "have", l.buf.Len())
+		l.snd.reassemblyError(metrics.ReassemblyLenMismatch)
 	} else {
 		// Write the packet to the wire.
 		if err := l.snd.send(l.buf.Bytes()); err != nil {
 			log.Error("Unable to send reassembled packet", "err", err)
+			l.snd.reassemblyError(metrics.ReassemblySendError)
 		}
 	}
 	// Process the complete packets in the last frame
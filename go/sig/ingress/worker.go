@@ -37,6 +37,9 @@ const (
 
 type sender interface {
 	send(common.RawBytes) error
+	// reassemblyError records a failure to decapsulate or reassemble a
+	// packet. typ is one of the metrics.Reassembly* label values.
+	reassemblyError(typ string)
 }
 
 // Worker handles decapsulation of SIG frames.
@@ -161,3 +164,8 @@ func (w *Worker) send(packet common.RawBytes) error {
 	w.sentCtrs.Bytes.Add(float64(bytesWritten))
 	return nil
 }
+
+func (w *Worker) reassemblyError(typ string) {
+	metrics.ReassemblyErrors.WithLabelValues(w.Remote.IA.String(), w.SessId.String(),
+		typ).Inc()
+}
@@ -0,0 +1,251 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package adminapi implements the admin API of the SIG. The API allows
+// inspecting the SIG traffic config and adding and removing remote ASes and
+// their networks at runtime. All changes are applied the same way a reloaded
+// config file is applied. Note that reloading the config file replaces all
+// changes done through the API.
+//
+// The API consists of the following endpoints:
+//
+//	GET    /config                        returns the current config.
+//	PUT    /as?ia=<ia>                    adds or replaces the AS entry in the body.
+//	DELETE /as?ia=<ia>                    removes the AS.
+//	POST   /net?ia=<ia>&net=<prefix>      adds the network to the AS.
+//	DELETE /net?ia=<ia>&net=<prefix>      removes the network from the AS.
+//
+// All successful requests return the resulting config as JSON.
+//
+// Example: curl --unix-socket sig.admin -X POST 'http://sig/net?ia=1-ff00:0:1&net=192.0.2.0/24'
+package adminapi
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/sig/config"
+)
+
+// ApplyFunc applies a config to the SIG. It returns false if the config could
+// not be applied completely.
+type ApplyFunc func(cfg *config.Cfg) bool
+
+// API serves the admin API. It keeps track of the current config, which is
+// the base for all changes.
+type API struct {
+	mu    sync.Mutex
+	cfg   *config.Cfg
+	apply ApplyFunc
+}
+
+// New creates a new admin API that applies changes with apply.
+func New(apply ApplyFunc) *API {
+	return &API{
+		cfg:   &config.Cfg{},
+		apply: apply,
+	}
+}
+
+// SetConfig sets the current config. It must be called whenever a config is
+// applied outside of the API, e.g., when the config file is reloaded.
+func (a *API) SetConfig(cfg *config.Cfg) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.cfg = cfg
+}
+
+// Handler returns the HTTP handler serving the API.
+func (a *API) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/config", a.handleConfig)
+	mux.HandleFunc("/as", a.handleAS)
+	mux.HandleFunc("/net", a.handleNet)
+	return mux
+}
+
+// ListenAndServe serves the API on the unix socket at path. An existing
+// socket file at path is removed.
+func (a *API) ListenAndServe(path string) error {
+	if err := removeSocket(path); err != nil {
+		return err
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return common.NewBasicError("Unable to listen on admin socket", err, "path", path)
+	}
+	log.Info("Serving admin API", "path", path)
+	return http.Serve(l, a.Handler())
+}
+
+func (a *API) handleConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	writeConfig(w, a.cfg)
+}
+
+func (a *API) handleAS(w http.ResponseWriter, r *http.Request) {
+	ia, err := addr.IAFromString(r.URL.Query().Get("ia"))
+	if err != nil || ia.IsWildcard() {
+		http.Error(w, "invalid ia parameter", http.StatusBadRequest)
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		entry := &config.ASEntry{}
+		if err := json.NewDecoder(r.Body).Decode(entry); err != nil {
+			http.Error(w, "invalid AS entry: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		a.update(w, func(cfg *config.Cfg) int {
+			cfg.ASes[ia] = entry
+			return http.StatusOK
+		})
+	case http.MethodDelete:
+		a.update(w, func(cfg *config.Cfg) int {
+			if _, ok := cfg.ASes[ia]; !ok {
+				return http.StatusNotFound
+			}
+			delete(cfg.ASes, ia)
+			return http.StatusOK
+		})
+	default:
+		http.Error(w, "only PUT and DELETE are supported", http.StatusMethodNotAllowed)
+	}
+}
+
+func (a *API) handleNet(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	ia, err := addr.IAFromString(q.Get("ia"))
+	if err != nil {
+		http.Error(w, "invalid ia parameter", http.StatusBadRequest)
+		return
+	}
+	rawNet, _ := json.Marshal(q.Get("net"))
+	ipnet := &config.IPNet{}
+	if err := ipnet.UnmarshalJSON(rawNet); err != nil {
+		http.Error(w, "invalid net parameter: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	switch r.Method {
+	case http.MethodPost:
+		a.update(w, func(cfg *config.Cfg) int {
+			entry, ok := cfg.ASes[ia]
+			if !ok {
+				return http.StatusNotFound
+			}
+			if indexNet(entry.Nets, ipnet) >= 0 {
+				return http.StatusOK
+			}
+			entry = copyEntry(entry)
+			entry.Nets = append(entry.Nets, ipnet)
+			cfg.ASes[ia] = entry
+			return http.StatusOK
+		})
+	case http.MethodDelete:
+		a.update(w, func(cfg *config.Cfg) int {
+			entry, ok := cfg.ASes[ia]
+			if !ok {
+				return http.StatusNotFound
+			}
+			i := indexNet(entry.Nets, ipnet)
+			if i < 0 {
+				return http.StatusNotFound
+			}
+			entry = copyEntry(entry)
+			entry.Nets = append(entry.Nets[:i], entry.Nets[i+1:]...)
+			cfg.ASes[ia] = entry
+			return http.StatusOK
+		})
+	default:
+		http.Error(w, "only POST and DELETE are supported", http.StatusMethodNotAllowed)
+	}
+}
+
+// update applies the change done by modify to a copy of the current config.
+// If modify returns a status other than http.StatusOK, the copy is discarded
+// and the status is returned to the client.
+func (a *API) update(w http.ResponseWriter, modify func(cfg *config.Cfg) int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	cfg := copyConfig(a.cfg)
+	if status := modify(cfg); status != http.StatusOK {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	if err := cfg.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ok := a.apply(cfg)
+	// Even if the config was not applied completely, the SIG state is closer
+	// to the new config than to the old one.
+	a.cfg = cfg
+	if !ok {
+		http.Error(w, "config not applied completely, see SIG log",
+			http.StatusInternalServerError)
+		return
+	}
+	writeConfig(w, cfg)
+}
+
+func writeConfig(w http.ResponseWriter, cfg *config.Cfg) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	enc.Encode(cfg)
+}
+
+// copyConfig returns a copy of cfg that can be modified without affecting
+// cfg. AS entries are shared and must be copied before modifying them.
+func copyConfig(cfg *config.Cfg) *config.Cfg {
+	c := *cfg
+	c.ASes = make(map[addr.IA]*config.ASEntry, len(cfg.ASes))
+	for ia, entry := range cfg.ASes {
+		c.ASes[ia] = entry
+	}
+	return &c
+}
+
+func copyEntry(entry *config.ASEntry) *config.ASEntry {
+	e := *entry
+	e.Nets = append([]*config.IPNet(nil), entry.Nets...)
+	return &e
+}
+
+func removeSocket(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return common.NewBasicError("Unable to remove existing admin socket", err, "path", path)
+	}
+	return nil
+}
+
+func indexNet(nets []*config.IPNet, ipnet *config.IPNet) int {
+	for i, n := range nets {
+		if n.String() == ipnet.String() {
+			return i
+		}
+	}
+	return -1
+}
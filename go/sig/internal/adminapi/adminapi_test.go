@@ -0,0 +1,154 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adminapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/sig/config"
+)
+
+func TestAPI(t *testing.T) {
+	ia1 := xtest.MustParseIA("1-ff00:0:1")
+	ia2 := xtest.MustParseIA("1-ff00:0:2")
+
+	var applied []*config.Cfg
+	api := New(func(cfg *config.Cfg) bool {
+		applied = append(applied, cfg)
+		return true
+	})
+	api.SetConfig(&config.Cfg{
+		ASes: map[addr.IA]*config.ASEntry{ia1: {}},
+	})
+	h := api.Handler()
+
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return rec
+	}
+	nets := func(ia addr.IA) []string {
+		var s []string
+		for _, n := range applied[len(applied)-1].ASes[ia].Nets {
+			s = append(s, n.String())
+		}
+		return s
+	}
+
+	t.Run("get config", func(t *testing.T) {
+		rec := do(http.MethodGet, "/config", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var cfg config.Cfg
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &cfg))
+		assert.Contains(t, cfg.ASes, ia1)
+		assert.Empty(t, applied)
+	})
+	t.Run("add AS", func(t *testing.T) {
+		rec := do(http.MethodPut, "/as?ia=1-ff00:0:2", `{"Nets": ["192.0.2.0/24"]}`)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		require.Len(t, applied, 1)
+		assert.Contains(t, applied[0].ASes, ia1)
+		assert.Equal(t, []string{"192.0.2.0/24"}, nets(ia2))
+	})
+	t.Run("add net", func(t *testing.T) {
+		rec := do(http.MethodPost, "/net?ia=1-ff00:0:2&net=198.51.100.0/24", "")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, []string{"192.0.2.0/24", "198.51.100.0/24"}, nets(ia2))
+		// The previously applied config is not modified.
+		assert.Len(t, applied[0].ASes[ia2].Nets, 1)
+	})
+	t.Run("remove net", func(t *testing.T) {
+		rec := do(http.MethodDelete, "/net?ia=1-ff00:0:2&net=192.0.2.0/24", "")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, []string{"198.51.100.0/24"}, nets(ia2))
+	})
+	t.Run("remove AS", func(t *testing.T) {
+		rec := do(http.MethodDelete, "/as?ia=1-ff00:0:2", "")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.NotContains(t, applied[len(applied)-1].ASes, ia2)
+		assert.Contains(t, applied[len(applied)-1].ASes, ia1)
+	})
+
+	errCases := []struct {
+		Name   string
+		Method string
+		Target string
+		Body   string
+		Status int
+	}{
+		{"wildcard AS", http.MethodPut, "/as?ia=1-0", "{}", http.StatusBadRequest},
+		{"invalid AS entry", http.MethodPut, "/as?ia=1-ff00:0:2", "{", http.StatusBadRequest},
+		{
+			"unknown class",
+			http.MethodPut,
+			"/as?ia=1-ff00:0:2",
+			`{"PktPolicies": [{"ClassName": "voice", "SessIds": [0]}]}`,
+			http.StatusBadRequest,
+		},
+		{"remove unknown AS", http.MethodDelete, "/as?ia=1-ff00:0:3", "", http.StatusNotFound},
+		{
+			"add net to unknown AS",
+			http.MethodPost,
+			"/net?ia=1-ff00:0:3&net=192.0.2.0/24",
+			"",
+			http.StatusNotFound,
+		},
+		{
+			"non-canonical net",
+			http.MethodPost,
+			"/net?ia=1-ff00:0:1&net=192.0.2.1/24",
+			"",
+			http.StatusBadRequest,
+		},
+		{
+			"remove unknown net",
+			http.MethodDelete,
+			"/net?ia=1-ff00:0:1&net=192.0.2.0/24",
+			"",
+			http.StatusNotFound,
+		},
+		{"modify config", http.MethodPut, "/config", "{}", http.StatusMethodNotAllowed},
+	}
+	for _, tc := range errCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			n := len(applied)
+			rec := do(tc.Method, tc.Target, tc.Body)
+			assert.Equal(t, tc.Status, rec.Code, rec.Body.String())
+			assert.Len(t, applied, n)
+		})
+	}
+}
+
+func TestAPIApplyFailure(t *testing.T) {
+	api := New(func(cfg *config.Cfg) bool { return false })
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/as?ia=1-ff00:0:1", strings.NewReader("{}"))
+	api.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	// The config is kept even though it was not applied completely.
+	rec = httptest.NewRecorder()
+	api.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/config", nil))
+	assert.Contains(t, rec.Body.String(), "1-ff00:0:1")
+}
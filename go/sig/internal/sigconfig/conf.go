@@ -20,9 +20,11 @@ import (
 	"net"
 
 	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/config"
 	"github.com/scionproto/scion/go/lib/env"
 	"github.com/scionproto/scion/go/lib/serrors"
+	sigcfg "github.com/scionproto/scion/go/sig/config"
 )
 
 const (
@@ -76,6 +78,17 @@ func (cfg *Config) ConfigName() string {
 	return "sig_config"
 }
 
+// CheckFiles checks that the SIG traffic config referenced by the config can
+// be loaded. Unlike the other services, the SIG has no general section and
+// does not load a topology file; it learns about the local AS from SCIOND.
+func (cfg *Config) CheckFiles() error {
+	if _, err := sigcfg.LoadFromFile(cfg.Sig.SIGConfig); err != nil {
+		return common.NewBasicError("Unable to load SIG config", err,
+			"path", cfg.Sig.SIGConfig)
+	}
+	return nil
+}
+
 var _ config.Config = (*SigConf)(nil)
 
 // SigConf contains the configuration specific to the SIG.
@@ -102,6 +115,9 @@ type SigConf struct {
 	SrcIP4 net.IP
 	// IPv6 source address hint to put into routing table.
 	SrcIP6 net.IP
+	// AdminSocket is the path of the unix socket on which the SIG serves its
+	// admin API. The API is disabled if no path is set. (default "")
+	AdminSocket string
 }
 
 // InitDefaults sets the default values to unset values.
@@ -43,4 +43,9 @@ Tun = "sig"
 
 # Id of the routing table. (default 11)
 TunRTableId = 11
+
+# Unix socket on which the admin API is served. The admin API allows adding
+# and removing remote ASes and their networks at runtime. The API is disabled
+# if the path is empty. (default "")
+AdminSocket = ""
 `
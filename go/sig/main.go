@@ -38,6 +38,7 @@ import (
 	"github.com/scionproto/scion/go/sig/disp"
 	"github.com/scionproto/scion/go/sig/egress"
 	"github.com/scionproto/scion/go/sig/ingress"
+	"github.com/scionproto/scion/go/sig/internal/adminapi"
 	"github.com/scionproto/scion/go/sig/internal/sigconfig"
 	"github.com/scionproto/scion/go/sig/metrics"
 	"github.com/scionproto/scion/go/sig/sigcmn"
@@ -46,6 +47,9 @@ import (
 
 var (
 	cfg sigconfig.Config
+	// adminAPI tracks the applied SIG traffic config, such that it can be
+	// changed through the admin API.
+	adminAPI = adminapi.New(egress.ReloadConfig)
 )
 
 func init() {
@@ -104,6 +108,14 @@ func realMain() int {
 	}()
 	egress.Init(tunIO)
 	ingress.Init(tunIO)
+	if cfg.Sig.AdminSocket != "" {
+		go func() {
+			defer log.LogPanicAndExit()
+			if err := adminAPI.ListenAndServe(cfg.Sig.AdminSocket); err != nil {
+				fatal.Fatal(common.NewBasicError("Admin API failed", err))
+			}
+		}()
+	}
 	cfg.Metrics.StartPrometheus()
 	select {
 	case <-fatal.ShutdownChan():
@@ -197,6 +209,9 @@ func loadConfig(path string) bool {
 		return false
 	}
 	ok := egress.ReloadConfig(cfg)
+	// Track the config even if it was not applied completely, as the SIG
+	// state is closer to it than to the previous one.
+	adminAPI.SetConfig(cfg)
 	if !ok {
 		return false
 	}
@@ -49,10 +49,32 @@ var (
 	SessionTimedOut       *prometheus.CounterVec
 	SessionPathSwitched   *prometheus.CounterVec
 	SessionOldPollReplies *prometheus.CounterVec
+	SessionHealthy        *prometheus.GaugeVec
+	SessionPath           *prometheus.GaugeVec
+	SessionFailovers      *prometheus.CounterVec
+	PktsEncap             *prometheus.CounterVec
+	PktBytesEncap         *prometheus.CounterVec
+	FramesDropped         *prometheus.CounterVec
+	ReassemblyErrors      *prometheus.CounterVec
 
 	EgressRxQueueFull *prometheus.CounterVec
 )
 
+// Label values of FramesDropped.
+const (
+	DropNoPath     = "no_path"
+	DropNoRemote   = "no_remote"
+	DropWriteError = "write_error"
+)
+
+// Label values of ReassemblyErrors.
+const (
+	ReassemblyNoPktStart  = "no_pkt_start"
+	ReassemblyFraming     = "framing"
+	ReassemblyLenMismatch = "len_mismatch"
+	ReassemblySendError   = "send_error"
+)
+
 // Version number of loaded config, atomic
 var ConfigVersion uint64
 
@@ -66,6 +88,9 @@ func init() {
 	newCVec := func(name, help string, lNames []string) *prometheus.CounterVec {
 		return prom.NewCounterVec(Namespace, "", name, help, lNames)
 	}
+	newGVec := func(name, help string, lNames []string) *prometheus.GaugeVec {
+		return prom.NewGaugeVec(Namespace, "", name, help, lNames)
+	}
 	// FIXME(kormat): these metrics should probably have more informative labels
 	PktsRecv = newCVec("pkts_recv_total", "Number of packets received.", iaLabels)
 	PktsSent = newCVec("pkts_sent_total", "Number of packets sent.", iaLabels)
@@ -83,6 +108,22 @@ func init() {
 	SessionPathSwitched = newCVec("session_switch_path", "Number of path switches", iaLabels)
 	SessionOldPollReplies = newCVec("session_old_poll_replies",
 		"Number of poll replies received after next poll request was sent", iaLabels)
+	SessionHealthy = newGVec("session_healthy",
+		"Whether the session receives poll replies from the remote SIG (1) or not (0).",
+		iaLabels)
+	SessionPath = newGVec("session_path",
+		"Path currently used by the session, identified by the path label. "+
+			"The value is always 1.", append(iaLabels, "path"))
+	SessionFailovers = newCVec("session_failovers_total",
+		"Number of times a healthy session timed out and switched to a new path.", iaLabels)
+	PktsEncap = newCVec("pkts_encap_total", "Number of packets encapsulated.", iaLabels)
+	PktBytesEncap = newCVec("pkt_bytes_encap_total",
+		"Number of packet bytes encapsulated.", iaLabels)
+	FramesDropped = newCVec("frames_dropped_total",
+		"Number of frames that could not be sent.", append(iaLabels, "reason"))
+	ReassemblyErrors = newCVec("reassembly_errors_total",
+		"Number of errors while decapsulating and reassembling packets.",
+		append(iaLabels, "type"))
 
 	EgressRxQueueFull = newCVec("egress_recv_queue_full_total",
 		"Egress packets dropped due to full queues.", []string{"IA"})
@@ -0,0 +1,79 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+)
+
+func calcAndPrint(w io.Writer, input string) error {
+	a, err := parseAddr(input)
+	if err != nil {
+		return err
+	}
+	printAddr(w, a)
+	return nil
+}
+
+// parseAddr parses a SCION address. The input is either a full address of
+// the form isd-as,[host]:port, an ISD-AS, or the integer representation of an
+// ISD-AS in decimal or in hex prefixed with 0x.
+func parseAddr(input string) (*snet.Addr, error) {
+	input = strings.TrimSpace(input)
+	if strings.Contains(input, ",") {
+		return snet.AddrFromString(input)
+	}
+	if ia, err := addr.IAFromString(input); err == nil {
+		return &snet.Addr{IA: ia}, nil
+	}
+	if v, err := strconv.ParseUint(input, 0, 64); err == nil {
+		return &snet.Addr{IA: addr.IAInt(v).IA()}, nil
+	}
+	return nil, serrors.New("input is neither an address, an ISD-AS nor an ISD-AS integer")
+}
+
+func printAddr(w io.Writer, a *snet.Addr) {
+	ia := a.IA
+	rawIA := make(common.RawBytes, addr.IABytes)
+	ia.Write(rawIA)
+	if a.Host == nil {
+		fmt.Fprintf(w, "Address: %s\n", ia)
+	} else {
+		fmt.Fprintf(w, "Address: %s\n", a)
+	}
+	fmt.Fprintf(w, "  ISD: %d\n", ia.I)
+	fmt.Fprintf(w, "  AS: %s (decimal %d)\n", ia.A, uint64(ia.A))
+	fmt.Fprintf(w, "  ISD-AS: %s (integer %d, 0x%x)\n", ia, uint64(ia.IAInt()),
+		uint64(ia.IAInt()))
+	fmt.Fprintf(w, "  File format: %s\n", ia.FileFmt(true))
+	fmt.Fprintf(w, "  Raw ISD-AS: %s\n", hex.EncodeToString(rawIA))
+	if a.Host == nil {
+		return
+	}
+	fmt.Fprintf(w, "  Host: %s (%s, raw %s)\n", a.Host.L3, a.Host.L3.Type(),
+		hex.EncodeToString(a.Host.L3.Pack()))
+	if a.Host.L4 != nil {
+		fmt.Fprintf(w, "  Port: %d\n", a.Host.L4.Port())
+	}
+}
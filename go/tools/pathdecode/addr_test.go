@@ -0,0 +1,89 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestParseAddr(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	tests := map[string]struct {
+		Input     string
+		Host      string
+		Assertion require.ErrorAssertionFunc
+	}{
+		"ISD-AS": {
+			Input:     "1-ff00:0:110",
+			Assertion: require.NoError,
+		},
+		"decimal ISD-AS integer": {
+			Input:     "561850441793808",
+			Assertion: require.NoError,
+		},
+		"hex ISD-AS integer": {
+			Input:     " 0x1ff0000000110\n",
+			Assertion: require.NoError,
+		},
+		"address": {
+			Input:     "1-ff00:0:110,[127.0.0.1]:40000",
+			Host:      "[127.0.0.1]:40000",
+			Assertion: require.NoError,
+		},
+		"SVC address": {
+			Input:     "1-ff00:0:110,[CS]",
+			Host:      "[CS A (0x0002)]:<nil>",
+			Assertion: require.NoError,
+		},
+		"garbage": {
+			Input:     "not an address",
+			Assertion: require.Error,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, err := parseAddr(test.Input)
+			test.Assertion(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, ia, a.IA)
+			if test.Host == "" {
+				assert.Nil(t, a.Host)
+				return
+			}
+			require.NotNil(t, a.Host)
+			assert.Equal(t, test.Host, a.Host.String())
+		})
+	}
+}
+
+func TestCalcAndPrint(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, calcAndPrint(&buf, "1-ff00:0:110,[127.0.0.1]:40000"))
+	out := buf.String()
+	assert.Contains(t, out, "AS: ff00:0:110 (decimal 280375465083152)")
+	assert.Contains(t, out, "(integer 561850441793808, 0x1ff0000000110)")
+	assert.Contains(t, out, "File format: ISD1-ASff00_0_110")
+	assert.Contains(t, out, "Raw ISD-AS: 0001ff0000000110")
+	assert.Contains(t, out, "Host: 127.0.0.1 (IPv4, raw 7f000001)")
+	assert.Contains(t, out, "Port: 40000")
+}
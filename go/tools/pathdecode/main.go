@@ -0,0 +1,242 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Pathdecode decodes raw SCION forwarding paths, e.g., taken from a packet
+// capture or a log line, and prints the segments and hop fields they consist
+// of. With -addr, it instead decodes SCION addresses and prints the different
+// representations of their ISD-AS and host.
+package main
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/env"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/lib/util"
+)
+
+// Input encodings.
+const (
+	encAuto   = "auto"
+	encHex    = "hex"
+	encBase64 = "base64"
+)
+
+var (
+	encoding = flag.String("enc", encAuto, "Encoding of the raw path: auto, hex or base64")
+	addrMode = flag.Bool("addr", false, "Decode SCION addresses instead of paths")
+	version  = flag.Bool("version", false, "Output version information and exit.")
+)
+
+func main() {
+	flag.Usage = flagUsage
+	flag.Parse()
+	if *version {
+		fmt.Print(env.VersionInfo())
+		os.Exit(0)
+	}
+	inputs := flag.Args()
+	if len(inputs) == 0 {
+		var err error
+		if inputs, err = readLines(os.Stdin); err != nil {
+			fatal(err)
+		}
+	}
+	failed := false
+	for _, input := range inputs {
+		var err error
+		if *addrMode {
+			err = calcAndPrint(os.Stdout, input)
+		} else {
+			err = decodeAndPrint(os.Stdout, input, *encoding)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %s: %s\n", input, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func decodeAndPrint(w io.Writer, input, enc string) error {
+	raw, err := parseInput(input, enc)
+	if err != nil {
+		return err
+	}
+	segs, err := decode(raw)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Path (%d bytes): %s\n", len(raw), hex.EncodeToString(raw))
+	for i, seg := range segs {
+		seg.print(w, i)
+	}
+	return nil
+}
+
+// parseInput returns the raw bytes of a path encoded as hex or base64. Hex
+// input may be prefixed with 0x and contain whitespace, colons and dashes
+// between the bytes.
+func parseInput(input, enc string) (common.RawBytes, error) {
+	input = strings.TrimSpace(input)
+	switch enc {
+	case encHex:
+		return parseHex(input)
+	case encBase64:
+		return parseBase64(input)
+	case encAuto:
+		if raw, err := parseHex(input); err == nil {
+			return raw, nil
+		}
+		if raw, err := parseBase64(input); err == nil {
+			return raw, nil
+		}
+		return nil, serrors.New("input is neither hex nor base64")
+	default:
+		return nil, serrors.New("unknown encoding", "enc", enc)
+	}
+}
+
+func parseHex(input string) (common.RawBytes, error) {
+	input = strings.TrimPrefix(strings.TrimPrefix(input, "0x"), "0X")
+	input = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', ':', '-':
+			return -1
+		}
+		return r
+	}, input)
+	return hex.DecodeString(input)
+}
+
+func parseBase64(input string) (common.RawBytes, error) {
+	if raw, err := base64.StdEncoding.DecodeString(input); err == nil {
+		return raw, nil
+	}
+	return base64.RawStdEncoding.DecodeString(input)
+}
+
+// segment is a decoded path segment.
+type segment struct {
+	Info *spath.InfoField
+	Hops []*spath.HopField
+}
+
+// decode splits the raw path into its segments.
+func decode(raw common.RawBytes) ([]segment, error) {
+	if len(raw)%common.LineLen != 0 {
+		return nil, serrors.New("path length is not a multiple of the line length",
+			"len", len(raw), "line_len", common.LineLen)
+	}
+	var segs []segment
+	for off := 0; off < len(raw); {
+		info, err := spath.InfoFFromRaw(raw[off:])
+		if err != nil {
+			return nil, serrors.WrapStr("unable to parse info field", err, "offset", off)
+		}
+		off += spath.InfoFieldLength
+		if info.Hops == 0 {
+			return nil, serrors.New("segment without hop fields", "offset",
+				off-spath.InfoFieldLength)
+		}
+		seg := segment{Info: info}
+		for i := 0; i < int(info.Hops); i++ {
+			if off >= len(raw) {
+				return nil, serrors.New("path ends within segment", "segment", len(segs),
+					"hops", info.Hops, "parsed", i)
+			}
+			hop, err := spath.HopFFromRaw(raw[off:])
+			if err != nil {
+				return nil, serrors.WrapStr("unable to parse hop field", err, "offset", off)
+			}
+			seg.Hops = append(seg.Hops, hop)
+			off += spath.HopFieldLength
+		}
+		segs = append(segs, seg)
+	}
+	return segs, nil
+}
+
+// Expiry returns the expiration time of the segment, i.e., the expiration
+// time of the hop field that expires first.
+func (s segment) Expiry() time.Time {
+	var expiry time.Time
+	for _, hop := range s.Hops {
+		if e := s.hopExpiry(hop); expiry.IsZero() || e.Before(expiry) {
+			expiry = e
+		}
+	}
+	return expiry
+}
+
+func (s segment) hopExpiry(hop *spath.HopField) time.Time {
+	return s.Info.Timestamp().Add(hop.ExpTime.ToDuration())
+}
+
+func (s segment) print(w io.Writer, idx int) {
+	fmt.Fprintf(w, "Segment %d: %s\n", idx, s.Info)
+	fmt.Fprintf(w, "  Expiry: %s\n", util.TimeToCompact(s.Expiry()))
+	for i, hop := range s.Hops {
+		fmt.Fprintf(w, "  Hop %d: %s Expiry: %s\n", i, hop,
+			util.TimeToCompact(s.hopExpiry(hop)))
+	}
+}
+
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+func flagUsage() {
+	fmt.Fprintf(os.Stderr, `
+Usage: pathdecode [flags] [path...]
+       pathdecode -addr [flags] [address...]
+
+Decodes raw SCION forwarding paths and prints their segments, hop fields, interface IDs and
+expiration times. The paths are read from the arguments, or line by line from stdin if no
+arguments are given.
+
+With -addr, the inputs are SCION addresses (isd-as,[host]:port), ISD-ASes, or ISD-AS integers
+in decimal or hex. The ISD, AS, integer, file and raw representations are printed.
+
+Example: pathdecode 0x01...
+         pathdecode -addr 1-ff00:0:110,[127.0.0.1]:40000
+
+flags:
+`)
+	flag.PrintDefaults()
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "ERROR: %s\n", err)
+	os.Exit(1)
+}
@@ -0,0 +1,164 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/spath"
+)
+
+func TestParseInput(t *testing.T) {
+	raw := testPath(t)
+	tests := map[string]struct {
+		Input     string
+		Enc       string
+		Assertion require.ErrorAssertionFunc
+	}{
+		"hex": {
+			Input:     hex.EncodeToString(raw),
+			Enc:       encHex,
+			Assertion: require.NoError,
+		},
+		"hex with prefix and separators": {
+			Input:     " 0x" + hexWithColons(raw) + "\n",
+			Enc:       encAuto,
+			Assertion: require.NoError,
+		},
+		"base64": {
+			Input:     base64.StdEncoding.EncodeToString(raw),
+			Enc:       encBase64,
+			Assertion: require.NoError,
+		},
+		"base64 auto": {
+			Input:     base64.StdEncoding.EncodeToString(raw),
+			Enc:       encAuto,
+			Assertion: require.NoError,
+		},
+		"garbage": {
+			Input:     "not a path!",
+			Enc:       encAuto,
+			Assertion: require.Error,
+		},
+		"unknown encoding": {
+			Input:     hex.EncodeToString(raw),
+			Enc:       "binary",
+			Assertion: require.Error,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			parsed, err := parseInput(test.Input, test.Enc)
+			test.Assertion(t, err)
+			if err == nil {
+				assert.Equal(t, raw, parsed)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("valid path", func(t *testing.T) {
+		segs, err := decode(testPath(t))
+		require.NoError(t, err)
+		require.Len(t, segs, 2)
+		assert.Len(t, segs[0].Hops, 2)
+		assert.Len(t, segs[1].Hops, 1)
+		assert.Equal(t, common.IFIDType(2), segs[0].Hops[1].ConsIngress)
+		assert.Equal(t, common.IFIDType(3), segs[1].Hops[0].ConsEgress)
+		// The second hop of the first segment expires first.
+		assert.Equal(t, segs[0].hopExpiry(segs[0].Hops[1]), segs[0].Expiry())
+	})
+	t.Run("truncated path", func(t *testing.T) {
+		raw := testPath(t)
+		_, err := decode(raw[:len(raw)-spath.HopFieldLength])
+		assert.Error(t, err)
+	})
+	t.Run("partial line", func(t *testing.T) {
+		raw := testPath(t)
+		_, err := decode(raw[:len(raw)-1])
+		assert.Error(t, err)
+	})
+	t.Run("empty segment", func(t *testing.T) {
+		raw := make(common.RawBytes, spath.InfoFieldLength)
+		(&spath.InfoField{ISD: 1}).Write(raw)
+		_, err := decode(raw)
+		assert.Error(t, err)
+	})
+}
+
+func TestDecodeAndPrint(t *testing.T) {
+	var buf bytes.Buffer
+	err := decodeAndPrint(&buf, hex.EncodeToString(testPath(t)), encAuto)
+	require.NoError(t, err)
+	out := buf.String()
+	assert.Contains(t, out, "Segment 0:")
+	assert.Contains(t, out, "Segment 1:")
+	assert.Contains(t, out, "ConsIngress: 2 ConsEgress: 0")
+}
+
+// testPath returns a raw path with two segments. The first one consists of
+// two hop fields, the second one of a single hop field.
+func testPath(t *testing.T) common.RawBytes {
+	segs := []struct {
+		Info spath.InfoField
+		Hops []spath.HopField
+	}{
+		{
+			Info: spath.InfoField{ConsDir: true, TsInt: 1500000000, ISD: 1, Hops: 2},
+			Hops: []spath.HopField{
+				{ConsEgress: 1, ExpTime: 63, Mac: common.RawBytes{1, 2, 3}},
+				{ConsIngress: 2, ExpTime: 10, Mac: common.RawBytes{4, 5, 6}},
+			},
+		},
+		{
+			Info: spath.InfoField{TsInt: 1500000100, ISD: 1, Hops: 1},
+			Hops: []spath.HopField{
+				{ConsEgress: 3, ExpTime: 63, Mac: common.RawBytes{7, 8, 9}},
+			},
+		},
+	}
+	var raw common.RawBytes
+	for _, seg := range segs {
+		b := make(common.RawBytes, spath.InfoFieldLength)
+		seg.Info.Write(b)
+		raw = append(raw, b...)
+		for _, hop := range seg.Hops {
+			b := make(common.RawBytes, spath.HopFieldLength)
+			hop.Write(b)
+			raw = append(raw, b...)
+		}
+	}
+	require.Len(t, raw, 5*common.LineLen)
+	return raw
+}
+
+func hexWithColons(raw common.RawBytes) string {
+	var buf bytes.Buffer
+	for i, b := range raw {
+		if i != 0 {
+			buf.WriteByte(':')
+		}
+		buf.WriteString(hex.EncodeToString([]byte{b}))
+	}
+	return buf.String()
+}
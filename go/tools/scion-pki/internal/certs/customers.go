@@ -37,21 +37,38 @@ func runCustomers(args []string) {
 	if err != nil {
 		pkicmn.ErrorAndExit("Error loading configs: %s\n", err)
 	}
+	if err := customers(asMap, cfgs); err != nil {
+		pkicmn.ErrorAndExit("Error: %s\n", err)
+	}
+	os.Exit(0)
+}
+
+// Customers collects the customer keys for all core ASes in the mapping from
+// ISD to ASes. Only the ASes in the mapping are considered as customers.
+func Customers(asMap map[addr.ISD][]addr.IA) error {
+	cfgs, err := loadConfigs(asMap)
+	if err != nil {
+		return common.NewBasicError("Error loading configs", err)
+	}
+	return customers(asMap, cfgs)
+}
+
+func customers(asMap map[addr.ISD][]addr.IA, cfgs map[addr.ISD]map[addr.IA]*conf.As) error {
 	for isd, ases := range asMap {
 		iconf, err := conf.LoadIsdConf(pkicmn.GetIsdPath(pkicmn.RootDir, isd))
 		if err != nil {
-			pkicmn.ErrorAndExit("Error reading isd.ini: %s\n", err)
+			return common.NewBasicError("Error reading isd.ini", err, "isd", isd)
 		}
 		for _, ia := range ases {
 			if !pkicmn.Contains(iconf.Trc.CoreIAs, ia) {
 				continue
 			}
 			if err := copyCustomers(ia, cfgs[isd]); err != nil {
-				pkicmn.ErrorAndExit("Error copying customer keys for %s: %s\n", ia, err)
+				return common.NewBasicError("Error copying customer keys", err, "ia", ia)
 			}
 		}
 	}
-	os.Exit(0)
+	return nil
 }
 
 func copyCustomers(ia addr.IA, cfgs map[addr.IA]*conf.As) error {
@@ -95,13 +112,17 @@ func loadASConfigs(selector string) (map[addr.ISD]map[addr.IA]*conf.As, error) {
 	if err != nil {
 		return nil, err
 	}
+	return loadConfigs(asMap)
+}
+
+func loadConfigs(asMap map[addr.ISD][]addr.IA) (map[addr.ISD]map[addr.IA]*conf.As, error) {
 	cfgs := make(map[addr.ISD]map[addr.IA]*conf.As)
 	for isd, ases := range asMap {
 		cfgs[isd] = make(map[addr.IA]*conf.As)
 		for _, ia := range ases {
 			confdir := pkicmn.GetAsPath(pkicmn.RootDir, ia)
 			path := filepath.Join(confdir, conf.AsConfFileName)
-			if _, err = os.Stat(path); os.IsNotExist(err) {
+			if _, err := os.Stat(path); os.IsNotExist(err) {
 				pkicmn.QuietPrint("Skipping %s. Missing %s\n", ia, path)
 				continue
 			}
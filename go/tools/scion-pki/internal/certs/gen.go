@@ -40,10 +40,20 @@ func runGenCert(args []string) {
 	if err != nil {
 		pkicmn.ErrorAndExit("Error: %s\n", err)
 	}
+	if err := Gen(asMap); err != nil {
+		pkicmn.ErrorAndExit("Error: %s\n", err)
+	}
+	os.Exit(0)
+}
+
+// Gen generates the certificate chains for all ASes in the mapping from ISD to
+// ASes. The chains of the core ASes are generated first, such that they can
+// be used to issue the chains of the non-core ASes.
+func Gen(asMap map[addr.ISD][]addr.IA) error {
 	for isd, ases := range asMap {
 		iconf, err := conf.LoadIsdConf(pkicmn.GetIsdPath(pkicmn.RootDir, isd))
 		if err != nil {
-			pkicmn.ErrorAndExit("Error reading isd.ini: %s\n", err)
+			return common.NewBasicError("Error reading isd.ini", err, "isd", isd)
 		}
 		// Process cores.
 		for _, ia := range ases {
@@ -51,7 +61,7 @@ func runGenCert(args []string) {
 				continue
 			}
 			if err = genCert(ia, true); err != nil {
-				pkicmn.ErrorAndExit("Error generating cert for %s: %s\n", ia, err)
+				return common.NewBasicError("Error generating cert", err, "ia", ia)
 			}
 		}
 		// Process non-cores.
@@ -60,11 +70,11 @@ func runGenCert(args []string) {
 				continue
 			}
 			if err = genCert(ia, false); err != nil {
-				pkicmn.ErrorAndExit("Error generating cert for %s: %s\n", ia, err)
+				return common.NewBasicError("Error generating cert", err, "ia", ia)
 			}
 		}
 	}
-	os.Exit(0)
+	return nil
 }
 
 func genCert(ia addr.IA, isIssuer bool) error {
@@ -23,6 +23,7 @@ import (
 
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/tools/scion-pki/internal/certs"
+	"github.com/scionproto/scion/go/tools/scion-pki/internal/deploy"
 	"github.com/scionproto/scion/go/tools/scion-pki/internal/keys"
 	"github.com/scionproto/scion/go/tools/scion-pki/internal/pkicmn"
 	"github.com/scionproto/scion/go/tools/scion-pki/internal/tmpl"
@@ -117,6 +118,7 @@ func init() {
 	RootCmd.AddCommand(version.Cmd)
 	RootCmd.AddCommand(trc.Cmd)
 	RootCmd.AddCommand(tmpl.Cmd)
+	RootCmd.AddCommand(deploy.Cmd)
 	RootCmd.AddCommand(autoCompleteCmd)
 	RootCmd.AddCommand(v2.Cmd)
 }
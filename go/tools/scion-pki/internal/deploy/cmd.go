@@ -0,0 +1,82 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package deploy generates and verifies the crypto material of a whole
+// topology.
+package deploy
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	notBefore   uint32
+	rawValidity string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "deploy",
+	Short: "Generate and verify the crypto material for a whole topology.",
+	Long: `
+'deploy' can be used to generate all keys, TRCs and certificate chains required by a topology,
+and to verify the consistency of the crypto material of an existing deployment.
+
+The topology is described by a yaml file with the following structure:
+	ASes:
+		"1-ff00:0:110":
+			core: true
+		"1-ff00:0:111":
+			cert_issuer: "1-ff00:0:110"
+`,
+}
+
+var gen = &cobra.Command{
+	Use:   "gen",
+	Short: "Generate configs, keys, TRCs and certificate chains for the topology",
+	Long: `
+'gen' generates the isd.ini and as.ini files for all ISDs and ASes in the topology.
+Based on these configurations, it generates all keys, TRCs and certificate chains, and
+collects the customer keys of the core ASes.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runGen(args[0])
+	},
+}
+
+var verify = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify the crypto material of the topology",
+	Long: `
+'verify' checks that the crypto material on disk is consistent with the topology:
+	- The TRC of each ISD lists exactly the core ASes of the topology, and its keys and
+	  signatures match the keys of the core ASes.
+	- The certificate chain of each AS is issued for the AS by the issuer specified in the
+	  topology, verifies against the TRC, and its keys match the keys of the AS.
+For each ISD and AS, the first inconsistency is reported.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runVerify(args[0])
+	},
+}
+
+func init() {
+	gen.Flags().Uint32VarP(&notBefore, "notbefore", "b", 0,
+		"set not_before time in all configs")
+	gen.Flags().StringVar(&rawValidity, "validity", "365d",
+		"set the validity of all crypto material")
+	Cmd.AddCommand(gen)
+	Cmd.AddCommand(verify)
+}
@@ -0,0 +1,47 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/tools/scion-pki/internal/certs"
+	"github.com/scionproto/scion/go/tools/scion-pki/internal/keys"
+	"github.com/scionproto/scion/go/tools/scion-pki/internal/tmpl"
+	"github.com/scionproto/scion/go/tools/scion-pki/internal/trc"
+)
+
+func runGen(file string) error {
+	if err := tmpl.GenTopoTmpl(file, notBefore, rawValidity); err != nil {
+		return common.NewBasicError("Error generating configs", err)
+	}
+	topo, err := tmpl.LoadTopo(file)
+	if err != nil {
+		return err
+	}
+	asMap := topo.ASMap()
+	if err := keys.Gen(asMap); err != nil {
+		return common.NewBasicError("Error generating keys", err)
+	}
+	if err := trc.Gen(asMap); err != nil {
+		return common.NewBasicError("Error generating TRCs", err)
+	}
+	if err := certs.Gen(asMap); err != nil {
+		return common.NewBasicError("Error generating certificates", err)
+	}
+	if err := certs.Customers(asMap); err != nil {
+		return common.NewBasicError("Error collecting customer keys", err)
+	}
+	return nil
+}
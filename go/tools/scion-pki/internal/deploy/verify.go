@@ -0,0 +1,249 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/keyconf"
+	"github.com/scionproto/scion/go/lib/scrypto"
+	"github.com/scionproto/scion/go/lib/scrypto/cert"
+	"github.com/scionproto/scion/go/lib/scrypto/trc"
+	"github.com/scionproto/scion/go/tools/scion-pki/internal/conf"
+	"github.com/scionproto/scion/go/tools/scion-pki/internal/pkicmn"
+	"github.com/scionproto/scion/go/tools/scion-pki/internal/tmpl"
+)
+
+func runVerify(file string) error {
+	topo, err := tmpl.LoadTopo(file)
+	if err != nil {
+		return err
+	}
+	errs := verifyTopo(topo)
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+	}
+	if len(errs) > 0 {
+		return common.NewBasicError("Verification FAILED", nil, "errors", len(errs))
+	}
+	pkicmn.QuietPrint("Verification SUCCEEDED.\n")
+	return nil
+}
+
+// verifyTopo verifies the crypto material of all ISDs and ASes in the
+// topology. For each ISD and AS, the first inconsistency is returned.
+func verifyTopo(topo tmpl.Topo) []error {
+	v := verifier{
+		topo: topo,
+		trcs: make(map[trcKey]*trc.TRC),
+	}
+	asMap := topo.ASMap()
+	isds := make([]addr.ISD, 0, len(asMap))
+	for isd := range asMap {
+		isds = append(isds, isd)
+	}
+	sort.Slice(isds, func(i, j int) bool { return isds[i] < isds[j] })
+	var errs []error
+	for _, isd := range isds {
+		if err := v.verifyISD(isd); err != nil {
+			errs = append(errs, common.NewBasicError("ISD inconsistent", err, "isd", isd))
+		}
+		for _, ia := range asMap[isd] {
+			if err := v.verifyAS(ia); err != nil {
+				errs = append(errs, common.NewBasicError("AS inconsistent", err, "ia", ia))
+			}
+		}
+	}
+	return errs
+}
+
+type trcKey struct {
+	isd addr.ISD
+	ver scrypto.Version
+}
+
+type verifier struct {
+	topo tmpl.Topo
+	trcs map[trcKey]*trc.TRC
+}
+
+// verifyISD checks that the TRC referenced in isd.ini contains exactly the
+// core ASes of the topology, and that the keys and signatures in the TRC match
+// the keys of the core ASes.
+func (v *verifier) verifyISD(isd addr.ISD) error {
+	iconf, err := conf.LoadIsdConf(pkicmn.GetIsdPath(pkicmn.RootDir, isd))
+	if err != nil {
+		return common.NewBasicError("Error reading isd.ini", err)
+	}
+	t, err := v.loadTRC(isd, scrypto.Version(iconf.Trc.Version))
+	if err != nil {
+		return err
+	}
+	cores := v.topo.Cores(isd)
+	sort.Slice(cores, func(i, j int) bool { return cores[i].IAInt() < cores[j].IAInt() })
+	match := len(cores) == len(t.CoreASes)
+	for _, ia := range cores {
+		match = match && t.CoreASes.Contains(ia)
+	}
+	if !match {
+		return common.NewBasicError("Core ASes mismatch", nil,
+			"expected", cores, "actual", t.CoreASes.ASList())
+	}
+	for _, ia := range cores {
+		if err := verifyTRCKeys(t, ia); err != nil {
+			return common.NewBasicError("Invalid core AS entry", err, "ia", ia)
+		}
+	}
+	return nil
+}
+
+// verifyTRCKeys checks that the keys of the core AS in the TRC match the keys
+// on disk, and that the TRC is signed with the online key.
+func verifyTRCKeys(t *trc.TRC, ia addr.IA) error {
+	coreAS := t.CoreASes[ia]
+	keysDir := filepath.Join(pkicmn.GetAsPath(pkicmn.OutDir, ia), pkicmn.KeysDir)
+	onKey, err := checkKey(filepath.Join(keysDir, keyconf.OnKeyFile), coreAS.OnlineKeyAlg,
+		coreAS.OnlineKey)
+	if err != nil {
+		return err
+	}
+	_, err = checkKey(filepath.Join(keysDir, keyconf.OffKeyFile), coreAS.OfflineKeyAlg,
+		coreAS.OfflineKey)
+	if err != nil {
+		return err
+	}
+	// Signatures are deterministic. Re-signing the TRC with the online key
+	// must result in the same signature.
+	signed := *t
+	signed.Signatures = make(map[string]common.RawBytes)
+	if err := signed.Sign(ia.String(), onKey, coreAS.OnlineKeyAlg); err != nil {
+		return common.NewBasicError("Error signing TRC", err)
+	}
+	if !bytes.Equal(signed.Signatures[ia.String()], t.Signatures[ia.String()]) {
+		return common.NewBasicError("Invalid TRC signature", nil)
+	}
+	return nil
+}
+
+// verifyAS checks that the certificate chain referenced in as.ini is issued
+// for the AS by the issuer in the topology, that it verifies against the TRC,
+// and that the keys in the chain match the keys on disk.
+func (v *verifier) verifyAS(ia addr.IA) error {
+	a, err := conf.LoadAsConf(pkicmn.GetAsPath(pkicmn.RootDir, ia))
+	if err != nil {
+		return common.NewBasicError("Error reading as.ini", err)
+	}
+	fname := fmt.Sprintf(pkicmn.CertNameFmt, ia.I, ia.A.FileFmt(), a.AsCert.Version)
+	file := filepath.Join(pkicmn.GetAsPath(pkicmn.OutDir, ia), pkicmn.CertsDir, fname)
+	chain, err := cert.ChainFromFile(file, false)
+	if err != nil {
+		return common.NewBasicError("Error loading certificate chain", err, "file", file)
+	}
+	if !chain.Leaf.Subject.Equal(ia) {
+		return common.NewBasicError("Subject mismatch", nil,
+			"expected", ia, "actual", chain.Leaf.Subject)
+	}
+	entry := v.topo.ASes[ia]
+	issuer := entry.Issuer
+	if issuer.IsZero() && entry.Core {
+		issuer = ia
+	}
+	if !issuer.IsZero() && !chain.Leaf.Issuer.Equal(issuer) {
+		return common.NewBasicError("Issuer mismatch", nil,
+			"expected", issuer, "actual", chain.Leaf.Issuer)
+	}
+	t, err := v.loadTRC(ia.I, chain.Leaf.TRCVersion)
+	if err != nil {
+		return err
+	}
+	if err := chain.Verify(ia, t); err != nil {
+		return common.NewBasicError("Certificate chain verification failed", err)
+	}
+	keysDir := filepath.Join(pkicmn.GetAsPath(pkicmn.OutDir, ia), pkicmn.KeysDir)
+	_, err = checkKey(filepath.Join(keysDir, keyconf.SigKeyFile), chain.Leaf.SignAlgorithm,
+		chain.Leaf.SubjectSignKey)
+	if err != nil {
+		return err
+	}
+	_, err = checkKey(filepath.Join(keysDir, keyconf.DecKeyFile), chain.Leaf.EncAlgorithm,
+		chain.Leaf.SubjectEncKey)
+	if err != nil {
+		return err
+	}
+	if !chain.Issuer.Subject.Equal(ia) {
+		return nil
+	}
+	_, err = checkKey(filepath.Join(keysDir, keyconf.IssSigKeyFile),
+		chain.Issuer.SignAlgorithm, chain.Issuer.SubjectSignKey)
+	return err
+}
+
+func (v *verifier) loadTRC(isd addr.ISD, ver scrypto.Version) (*trc.TRC, error) {
+	key := trcKey{isd: isd, ver: ver}
+	if t, ok := v.trcs[key]; ok {
+		return t, nil
+	}
+	fname := fmt.Sprintf(pkicmn.TrcNameFmt, isd, ver)
+	file := filepath.Join(pkicmn.GetIsdPath(pkicmn.OutDir, isd), pkicmn.TRCsDir, fname)
+	t, err := trc.TRCFromFile(file, false)
+	if err != nil {
+		return nil, common.NewBasicError("Error loading TRC", err, "file", file)
+	}
+	if t.ISD != isd || t.Version != ver {
+		return nil, common.NewBasicError("TRC mismatch", nil, "file", file,
+			"isd", t.ISD, "version", t.Version)
+	}
+	v.trcs[key] = t
+	return t, nil
+}
+
+// checkKey loads the private key from the file and checks that it matches the
+// expected public key. The private key is returned.
+func checkKey(file, algo string, expected common.RawBytes) (common.RawBytes, error) {
+	priv, err := keyconf.LoadKey(file, algo)
+	if err != nil {
+		return nil, common.NewBasicError("Error loading key", err, "file", file)
+	}
+	pub, err := pubKey(priv, algo)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(pub, expected) {
+		return nil, common.NewBasicError("Key mismatch", nil, "file", file)
+	}
+	return priv, nil
+}
+
+func pubKey(priv common.RawBytes, algo string) (common.RawBytes, error) {
+	switch algo {
+	case scrypto.Ed25519:
+		return common.RawBytes(ed25519.PrivateKey(priv).Public().(ed25519.PublicKey)), nil
+	case scrypto.Curve25519xSalsa20Poly1305:
+		var privFixed, pub [32]byte
+		copy(privFixed[:], priv)
+		curve25519.ScalarBaseMult(&pub, &privFixed)
+		return pub[:], nil
+	}
+	return nil, common.NewBasicError("Unsupported key type", nil, "type", algo)
+}
@@ -0,0 +1,125 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/keyconf"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/tools/scion-pki/internal/pkicmn"
+	"github.com/scionproto/scion/go/tools/scion-pki/internal/tmpl"
+)
+
+const topoRaw = `
+ASes:
+  "1-ff00:0:110":
+    core: true
+  "1-ff00:0:111":
+    cert_issuer: "1-ff00:0:110"
+  "2-ff00:0:210":
+    core: true
+`
+
+func TestGenVerify(t *testing.T) {
+	ia110 := xtest.MustParseIA("1-ff00:0:110")
+	ia111 := xtest.MustParseIA("1-ff00:0:111")
+	ia210 := xtest.MustParseIA("2-ff00:0:210")
+
+	tests := map[string]struct {
+		Modify func(t *testing.T, topo *tmpl.Topo)
+		ErrCnt int
+	}{
+		"consistent": {
+			Modify: func(_ *testing.T, _ *tmpl.Topo) {},
+		},
+		"core AS missing in TRC": {
+			Modify: func(_ *testing.T, topo *tmpl.Topo) {
+				topo.ASes[ia111] = tmpl.TopoAS{Core: true, Issuer: ia110}
+			},
+			ErrCnt: 1,
+		},
+		"issuer mismatch": {
+			Modify: func(_ *testing.T, topo *tmpl.Topo) {
+				topo.ASes[ia111] = tmpl.TopoAS{Issuer: ia111}
+			},
+			ErrCnt: 1,
+		},
+		"AS signing key replaced": {
+			Modify: func(t *testing.T, _ *tmpl.Topo) {
+				writeKey(t, ia111, keyconf.SigKeyFile)
+			},
+			ErrCnt: 1,
+		},
+		"online key replaced": {
+			Modify: func(t *testing.T, _ *tmpl.Topo) {
+				writeKey(t, ia210, keyconf.OnKeyFile)
+			},
+			ErrCnt: 1,
+		},
+		"TRC missing": {
+			Modify: func(t *testing.T, _ *tmpl.Topo) {
+				dir := filepath.Join(pkicmn.GetIsdPath(pkicmn.OutDir, ia210.I), pkicmn.TRCsDir)
+				require.NoError(t, os.RemoveAll(dir))
+			},
+			ErrCnt: 2,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanF := setupDirs(t)
+			defer cleanF()
+			topoFile := filepath.Join(dir, "topo.yml")
+			require.NoError(t, ioutil.WriteFile(topoFile, []byte(topoRaw), 0644))
+			require.NoError(t, runGen(topoFile))
+
+			topo, err := tmpl.LoadTopo(topoFile)
+			require.NoError(t, err)
+			test.Modify(t, &topo)
+			errs := verifyTopo(topo)
+			assert.Len(t, errs, test.ErrCnt, "%v", errs)
+		})
+	}
+}
+
+func setupDirs(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "scion-pki-deploy")
+	require.NoError(t, err)
+	pkicmn.RootDir, pkicmn.OutDir, pkicmn.Quiet = dir, dir, true
+	notBefore = uint32(time.Now().Unix())
+	return dir, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+// writeKey overwrites the key file of the AS with a fresh key.
+func writeKey(t *testing.T, ia addr.IA, fname string) {
+	seed := make([]byte, 32)
+	_, err := rand.Read(seed)
+	require.NoError(t, err)
+	file := filepath.Join(pkicmn.GetAsPath(pkicmn.OutDir, ia), pkicmn.KeysDir, fname)
+	raw := []byte(base64.StdEncoding.EncodeToString(seed))
+	require.NoError(t, ioutil.WriteFile(file, raw, 0600))
+}
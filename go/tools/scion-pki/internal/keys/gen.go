@@ -24,6 +24,7 @@ import (
 
 	"golang.org/x/crypto/ed25519"
 
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/keyconf"
 	"github.com/scionproto/scion/go/lib/scrypto"
@@ -37,21 +38,29 @@ func runGenKey(args []string) {
 	if err != nil {
 		pkicmn.ErrorAndExit("Error: %s\n", err)
 	}
+	if err := Gen(asMap); err != nil {
+		pkicmn.ErrorAndExit("Error: %s\n", err)
+	}
+	os.Exit(0)
+}
+
+// Gen generates the keys for all ASes in the mapping from ISD to ASes.
+func Gen(asMap map[addr.ISD][]addr.IA) error {
 	for isd, ases := range asMap {
 		iconf, err := conf.LoadIsdConf(pkicmn.GetIsdPath(pkicmn.RootDir, isd))
 		if err != nil {
-			pkicmn.ErrorAndExit("Error reading isd.ini: %s\n", err)
+			return common.NewBasicError("Error reading isd.ini", err, "isd", isd)
 		}
 		for _, ia := range ases {
 			dir := pkicmn.GetAsPath(pkicmn.OutDir, ia)
 			core := pkicmn.Contains(iconf.Trc.CoreIAs, ia)
 			pkicmn.QuietPrint("Generating keys for %s\n", ia)
 			if err = genAll(filepath.Join(dir, pkicmn.KeysDir), core); err != nil {
-				pkicmn.ErrorAndExit("Error generating keys: %s\n", err)
+				return common.NewBasicError("Error generating keys", err, "ia", ia)
 			}
 		}
 	}
-	os.Exit(0)
+	return nil
 }
 
 func genAll(outDir string, core bool) error {
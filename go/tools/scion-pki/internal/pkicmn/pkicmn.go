@@ -33,6 +33,7 @@ const (
 	TrcNameFmt         = "ISD%d-V%d.trc"
 	TRCPartsDirFmt     = "ISD%d-V%d.parts"
 	TRCSigPartFmt      = "ISD%d-V%d.sig.%s"
+	TRCSigReqFmt       = "ISD%d-V%d.req.%s"
 	TRCProtoNameFmt    = "ISD%d-V%d.proto"
 	ErrInvalidSelector = "Invalid selector."
 	ErrNoISDDirFound   = "No ISD directories found"
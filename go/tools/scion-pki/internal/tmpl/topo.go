@@ -19,6 +19,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"time"
 
 	"gopkg.in/yaml.v2"
@@ -36,18 +37,21 @@ var (
 )
 
 func runGenTopoTmpl(args []string) error {
-	raw, err := ioutil.ReadFile(args[0])
+	return GenTopoTmpl(args[0], notBefore, rawValidity)
+}
+
+// GenTopoTmpl generates the isd.ini and as.ini files for all ISDs and ASes in
+// the topology file. The not_before time and the validity are set in all
+// configs.
+func GenTopoTmpl(file string, notBefore uint32, rawValidity string) error {
+	val, err := parseValidity(notBefore, rawValidity)
 	if err != nil {
-		return common.NewBasicError("unable to read file", err, "file", args[0])
+		return err
 	}
-	val, err := validityFromFlags()
+	topo, err := LoadTopo(file)
 	if err != nil {
 		return err
 	}
-	var topo topoFile
-	if err := yaml.Unmarshal(raw, &topo); err != nil {
-		return common.NewBasicError("unable to parse topo", err, "file", args[0])
-	}
 	isdCfgs := make(map[addr.ISD]*conf.Isd)
 	for isd := range topo.ISDs() {
 		isdCfg := genISDCfg(isd, topo, val)
@@ -74,7 +78,7 @@ func runGenTopoTmpl(args []string) error {
 	return nil
 }
 
-func genISDCfg(isd addr.ISD, topo topoFile, val validity) *conf.Isd {
+func genISDCfg(isd addr.ISD, topo Topo, val validity) *conf.Isd {
 	cores := topo.Cores(isd)
 	isdCfg := &conf.Isd{
 		Desc: fmt.Sprintf("ISD %d", isd),
@@ -92,7 +96,7 @@ func genISDCfg(isd addr.ISD, topo topoFile, val validity) *conf.Isd {
 	return isdCfg
 }
 
-func genASCfg(ia addr.IA, entry asEntry, val validity, isdCfg *conf.Isd) *conf.As {
+func genASCfg(ia addr.IA, entry TopoAS, val validity, isdCfg *conf.Isd) *conf.As {
 	asCfg := conf.NewTemplateAsConf(ia, isdCfg.Trc.Version, pkicmn.Contains(isdCfg.Trc.CoreIAs, ia))
 	asCfg.AsCert.Comment = "AS certificate"
 	asCfg.AsCert.IssuingTime = val.NotBefore
@@ -118,7 +122,7 @@ type validity struct {
 	Validity  time.Duration
 }
 
-func validityFromFlags() (validity, error) {
+func parseValidity(notBefore uint32, rawValidity string) (validity, error) {
 	p, err := util.ParseDuration(rawValidity)
 	if err != nil {
 		return validity{}, common.NewBasicError("invalid validity", err, "input", rawValidity)
@@ -126,12 +130,26 @@ func validityFromFlags() (validity, error) {
 	return validity{NotBefore: notBefore, Validity: p}, nil
 }
 
-// topoFile is used to parse the topology description.
-type topoFile struct {
-	ASes map[addr.IA]asEntry `yaml:"ASes"`
+// Topo is used to parse the topology description.
+type Topo struct {
+	ASes map[addr.IA]TopoAS `yaml:"ASes"`
 }
 
-func (t topoFile) ISDs() map[addr.ISD]struct{} {
+// LoadTopo loads the topology description from the file.
+func LoadTopo(file string) (Topo, error) {
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		return Topo{}, common.NewBasicError("unable to read file", err, "file", file)
+	}
+	var topo Topo
+	if err := yaml.Unmarshal(raw, &topo); err != nil {
+		return Topo{}, common.NewBasicError("unable to parse topo", err, "file", file)
+	}
+	return topo, nil
+}
+
+// ISDs returns the set of ISDs in the topology.
+func (t Topo) ISDs() map[addr.ISD]struct{} {
 	m := make(map[addr.ISD]struct{})
 	for ia := range t.ASes {
 		m[ia.I] = struct{}{}
@@ -139,7 +157,8 @@ func (t topoFile) ISDs() map[addr.ISD]struct{} {
 	return m
 }
 
-func (t topoFile) Cores(isd addr.ISD) []addr.IA {
+// Cores returns the core ASes of the ISD.
+func (t Topo) Cores(isd addr.ISD) []addr.IA {
 	var cores []addr.IA
 	for ia, entry := range t.ASes {
 		if ia.I == isd && entry.Core {
@@ -149,7 +168,21 @@ func (t topoFile) Cores(isd addr.ISD) []addr.IA {
 	return cores
 }
 
-type asEntry struct {
+// ASMap returns a mapping from ISD to the ASes of that ISD in the topology.
+// The ASes are sorted.
+func (t Topo) ASMap() map[addr.ISD][]addr.IA {
+	m := make(map[addr.ISD][]addr.IA)
+	for ia := range t.ASes {
+		m[ia.I] = append(m[ia.I], ia)
+	}
+	for _, ases := range m {
+		sort.Slice(ases, func(i, j int) bool { return ases[i].IAInt() < ases[j].IAInt() })
+	}
+	return m
+}
+
+// TopoAS is the AS entry in the topology description.
+type TopoAS struct {
 	Core   bool    `yaml:"core"`
 	Issuer addr.IA `yaml:"cert_issuer"`
 }
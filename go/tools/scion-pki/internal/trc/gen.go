@@ -37,12 +37,20 @@ func runGenTrc(args []string) {
 	if err != nil {
 		pkicmn.ErrorAndExit("Error: %s\n", err)
 	}
+	if err := Gen(asMap); err != nil {
+		pkicmn.ErrorAndExit("Error generating TRC: %s\n", err)
+	}
+	os.Exit(0)
+}
+
+// Gen generates the TRCs for all ISDs in the mapping from ISD to ASes.
+func Gen(asMap map[addr.ISD][]addr.IA) error {
 	for isd := range asMap {
-		if err = genTrc(isd); err != nil {
-			pkicmn.ErrorAndExit("Error generating TRC: %s\n", err)
+		if err := genTrc(isd); err != nil {
+			return err
 		}
 	}
-	os.Exit(0)
+	return nil
 }
 
 func genTrc(isd addr.ISD) error {
@@ -0,0 +1,399 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trcs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/keyconf"
+	"github.com/scionproto/scion/go/lib/scrypto"
+	"github.com/scionproto/scion/go/lib/scrypto/trc/v2"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/tools/scion-pki/internal/pkicmn"
+	"github.com/scionproto/scion/go/tools/scion-pki/internal/v2/conf"
+)
+
+// signRequest is a request for a single detached signature on a TRC. It
+// contains everything that is required to create the signature, such that it
+// can be signed on an offline machine.
+type signRequest struct {
+	EncodedTRC       trc.Encoded          `json:"payload"`
+	EncodedProtected trc.EncodedProtected `json:"protected"`
+}
+
+// sign creates the detached signature with the provided private key. The
+// result is a partially signed TRC that only carries the requested signature.
+func (r *signRequest) sign(key []byte) (*trc.Signed, error) {
+	protected, err := r.EncodedProtected.Decode()
+	if err != nil {
+		return nil, common.NewBasicError("unable to parse protected meta", err)
+	}
+	sig, err := scrypto.Sign(trc.SigInput(r.EncodedProtected, r.EncodedTRC), key,
+		protected.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	signed := &trc.Signed{
+		EncodedTRC: r.EncodedTRC,
+		Signatures: []trc.Signature{{EncodedProtected: r.EncodedProtected, Signature: sig}},
+	}
+	return signed, nil
+}
+
+// expectedSignatures returns the metadata of all signatures that are
+// required for the TRC, and the key that is used to verify them. For TRC
+// updates, the voting keys are taken from the previous TRC.
+func expectedSignatures(t, prev *trc.TRC) (map[trc.Protected]scrypto.KeyMeta, error) {
+	expected := make(map[trc.Protected]scrypto.KeyMeta)
+	for as, keyTypes := range t.ProofOfPossession {
+		for _, keyType := range keyTypes {
+			key, ok := t.PrimaryASes[as].Keys[keyType]
+			if !ok {
+				return nil, common.NewBasicError("missing key for proof of possession", nil,
+					"as", as, "key_type", keyType)
+			}
+			expected[protectedFor(as, keyType, key, trc.POPSignature)] = key
+		}
+	}
+	if len(t.Votes) == 0 {
+		return expected, nil
+	}
+	if prev == nil {
+		return nil, serrors.New("previous TRC required to verify votes")
+	}
+	for as, vote := range t.Votes {
+		key, ok := prev.PrimaryASes[as].Keys[vote.KeyType]
+		if !ok {
+			return nil, common.NewBasicError("missing voting key in previous TRC", nil,
+				"as", as, "key_type", vote.KeyType)
+		}
+		if key.KeyVersion != vote.KeyVersion {
+			return nil, common.NewBasicError("voting key version mismatch", nil,
+				"as", as, "key_type", vote.KeyType, "expected", key.KeyVersion,
+				"actual", vote.KeyVersion)
+		}
+		expected[protectedFor(as, vote.KeyType, key, trc.VoteSignature)] = key
+	}
+	return expected, nil
+}
+
+func protectedFor(as addr.AS, keyType trc.KeyType, key scrypto.KeyMeta,
+	sigType trc.SignatureType) trc.Protected {
+
+	return trc.Protected{
+		AS:         as,
+		Algorithm:  key.Algorithm,
+		KeyType:    keyType,
+		KeyVersion: key.KeyVersion,
+		Type:       sigType,
+	}
+}
+
+// verifySignatures checks the signatures against the expected signatures. It
+// returns the set of expected signatures that are present and verifiable. An
+// error is returned if a signature is not expected or fails to verify.
+func verifySignatures(expected map[trc.Protected]scrypto.KeyMeta, encoded trc.Encoded,
+	signatures []trc.Signature) (map[trc.Protected]struct{}, error) {
+
+	valid := make(map[trc.Protected]struct{})
+	for _, sig := range signatures {
+		protected, err := sig.EncodedProtected.Decode()
+		if err != nil {
+			return nil, common.NewBasicError("unable to parse protected meta", err)
+		}
+		key, ok := expected[protected]
+		if !ok {
+			return nil, common.NewBasicError("unexpected signature", nil,
+				"protected", protected)
+		}
+		err = scrypto.Verify(trc.SigInput(sig.EncodedProtected, encoded), sig.Signature,
+			key.Key, key.Algorithm)
+		if err != nil {
+			return nil, common.NewBasicError("signature verification failed", err,
+				"protected", protected)
+		}
+		valid[protected] = struct{}{}
+	}
+	return valid, nil
+}
+
+// checkQuorum checks that all proof of possession signatures are present, and
+// that enough votes are cast to reach the voting quorum.
+func checkQuorum(t *trc.TRC, expected map[trc.Protected]scrypto.KeyMeta,
+	valid map[trc.Protected]struct{}) error {
+
+	var missing []trc.Protected
+	votes := 0
+	for protected := range expected {
+		_, ok := valid[protected]
+		switch {
+		case ok && protected.Type == trc.VoteSignature:
+			votes++
+		case !ok && protected.Type == trc.POPSignature:
+			missing = append(missing, protected)
+		}
+	}
+	if len(missing) > 0 {
+		return common.NewBasicError(trc.MissingPOPSignature, nil, "missing", missing)
+	}
+	if !t.Base() && votes < t.VotingQuorum() {
+		return common.NewBasicError("voting quorum not reached", nil,
+			"votes", votes, "quorum", t.VotingQuorum())
+	}
+	return nil
+}
+
+// requestID returns the identifier that is used in the file name of signing
+// requests and imported signatures.
+func requestID(protected trc.Protected) (string, error) {
+	keyType, err := protected.KeyType.MarshalText()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s-%s-%s", protected.AS.FileFmt(), protected.Type, keyType), nil
+}
+
+// loadPrevTRC loads the previous signed TRC for TRC updates. For base TRCs,
+// nil is returned.
+func loadPrevTRC(t *trc.TRC) (*trc.TRC, error) {
+	if t.Base() {
+		return nil, nil
+	}
+	file := SignedFile(t.ISD, uint64(t.Version)-1)
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, common.NewBasicError("unable to read previous TRC", err, "file", file)
+	}
+	var signed trc.Signed
+	if err := json.Unmarshal(raw, &signed); err != nil {
+		return nil, common.NewBasicError("unable to parse previous TRC", err, "file", file)
+	}
+	return signed.EncodedTRC.Decode()
+}
+
+// loadCeremony loads the prototype TRC of the ISD and the signatures it requires.
+func loadCeremony(isd addr.ISD) (*trc.TRC, trc.Encoded,
+	map[trc.Protected]scrypto.KeyMeta, error) {
+
+	isdCfg, err := conf.LoadISDCfg(pkicmn.GetIsdPath(pkicmn.RootDir, isd))
+	if err != nil {
+		return nil, nil, nil, common.NewBasicError("error loading ISD config", err)
+	}
+	t, encoded, err := loadProtoTRC(isd, isdCfg.Version)
+	if err != nil {
+		return nil, nil, nil, common.NewBasicError("unable to load prototype TRC", err)
+	}
+	if err := sanityChecks(isd, isdCfg, t); err != nil {
+		return nil, nil, nil, common.NewBasicError("invalid prototype TRC", err)
+	}
+	prev, err := loadPrevTRC(t)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	expected, err := expectedSignatures(t, prev)
+	if err != nil {
+		return nil, nil, nil, common.NewBasicError("unable to determine signatures", err)
+	}
+	return t, encoded, expected, nil
+}
+
+func runExport(selector string) error {
+	asMap, err := pkicmn.ProcessSelector(selector)
+	if err != nil {
+		return err
+	}
+	for isd := range asMap {
+		if err = exportRequests(isd); err != nil {
+			return common.NewBasicError("unable to export signing requests", err, "isd", isd)
+		}
+	}
+	return nil
+}
+
+func exportRequests(isd addr.ISD) error {
+	t, encoded, expected, err := loadCeremony(isd)
+	if err != nil {
+		return err
+	}
+	for protected := range expected {
+		encProtected, err := trc.EncodeProtected(protected)
+		if err != nil {
+			return err
+		}
+		req := signRequest{EncodedTRC: encoded, EncodedProtected: encProtected}
+		raw, err := json.Marshal(req)
+		if err != nil {
+			return common.NewBasicError("unable to marshal signing request", err)
+		}
+		id, err := requestID(protected)
+		if err != nil {
+			return err
+		}
+		file := RequestFile(isd, uint64(t.Version), id)
+		if err := pkicmn.WriteToFile(raw, file, 0644); err != nil {
+			return err
+		}
+		pkicmn.QuietPrint("Exported signing request %s\n", file)
+	}
+	return nil
+}
+
+func runSignRequest(reqFile, keyFile, out string) error {
+	raw, err := ioutil.ReadFile(reqFile)
+	if err != nil {
+		return err
+	}
+	var req signRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return common.NewBasicError("unable to parse signing request", err, "file", reqFile)
+	}
+	t, err := req.EncodedTRC.Decode()
+	if err != nil {
+		return common.NewBasicError("unable to parse TRC payload", err, "file", reqFile)
+	}
+	protected, err := req.EncodedProtected.Decode()
+	if err != nil {
+		return common.NewBasicError("unable to parse protected meta", err, "file", reqFile)
+	}
+	key, err := keyconf.LoadKey(keyFile, protected.Algorithm)
+	if err != nil {
+		return common.NewBasicError("unable to load key", err, "file", keyFile)
+	}
+	id, err := requestID(protected)
+	if err != nil {
+		return err
+	}
+	pkicmn.QuietPrint("Signing TRC for ISD %d version %d: %s\n", t.ISD, t.Version, id)
+	signed, err := req.sign(key)
+	if err != nil {
+		return common.NewBasicError("unable to sign request", err)
+	}
+	if raw, err = json.Marshal(signed); err != nil {
+		return common.NewBasicError("unable to marshal signature", err)
+	}
+	return pkicmn.WriteToFile(raw, out, 0644)
+}
+
+func runImport(files []string) error {
+	for _, file := range files {
+		if err := importSignatures(file); err != nil {
+			return common.NewBasicError("unable to import signature", err, "file", file)
+		}
+	}
+	return nil
+}
+
+func importSignatures(file string) error {
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var signed trc.Signed
+	if err := json.Unmarshal(raw, &signed); err != nil {
+		return common.NewBasicError("unable to parse signature", err)
+	}
+	payload, err := signed.EncodedTRC.Decode()
+	if err != nil {
+		return common.NewBasicError("unable to parse TRC payload", err)
+	}
+	t, encoded, expected, err := loadCeremony(payload.ISD)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(encoded, signed.EncodedTRC) {
+		return serrors.New("signed payload does not match prototype TRC")
+	}
+	if _, err := verifySignatures(expected, encoded, signed.Signatures); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(PartsDir(t.ISD, uint64(t.Version)), 0755); err != nil {
+		return err
+	}
+	for _, sig := range signed.Signatures {
+		protected, err := sig.EncodedProtected.Decode()
+		if err != nil {
+			return err
+		}
+		id, err := requestID(protected)
+		if err != nil {
+			return err
+		}
+		part := trc.Signed{EncodedTRC: encoded, Signatures: []trc.Signature{sig}}
+		if raw, err = json.Marshal(part); err != nil {
+			return common.NewBasicError("unable to marshal signature", err)
+		}
+		out := PartsFile(t.ISD, uint64(t.Version), id)
+		if err := pkicmn.WriteToFile(raw, out, 0644); err != nil {
+			return err
+		}
+		pkicmn.QuietPrint("Imported signature %s\n", out)
+	}
+	return nil
+}
+
+func runQuorum(selector string) error {
+	asMap, err := pkicmn.ProcessSelector(selector)
+	if err != nil {
+		return err
+	}
+	for isd := range asMap {
+		if err = verifyQuorum(isd); err != nil {
+			return common.NewBasicError("unable to verify quorum", err, "isd", isd)
+		}
+	}
+	return nil
+}
+
+func verifyQuorum(isd addr.ISD) error {
+	t, encoded, expected, err := loadCeremony(isd)
+	if err != nil {
+		return err
+	}
+	signatures, err := loadUniqueSignatures(isd, t.Version, encoded)
+	if err != nil {
+		return common.NewBasicError("unable to load signatures", err)
+	}
+	valid, err := verifySignatures(expected, encoded, signatures)
+	if err != nil {
+		return err
+	}
+	ids := make([]string, 0, len(expected))
+	status := make(map[string]string, len(expected))
+	for protected := range expected {
+		id, err := requestID(protected)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+		status[id] = "missing"
+		if _, ok := valid[protected]; ok {
+			status[id] = "ok"
+		}
+	}
+	sort.Strings(ids)
+	fmt.Printf("ISD %d version %d: %d/%d signatures\n", isd, t.Version, len(valid),
+		len(expected))
+	for _, id := range ids {
+		fmt.Printf("  %s: %s\n", id, status[id])
+	}
+	return checkQuorum(t, expected, valid)
+}
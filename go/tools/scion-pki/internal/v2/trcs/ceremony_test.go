@@ -0,0 +1,108 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trcs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/scrypto"
+	"github.com/scionproto/scion/go/lib/scrypto/trc/v2"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestCeremony(t *testing.T) {
+	as110 := xtest.MustParseAS("ff00:0:110")
+	as120 := xtest.MustParseAS("ff00:0:120")
+	keys := make(map[addr.AS][]byte)
+	base := &trc.TRC{
+		Version:           1,
+		BaseVersion:       1,
+		PrimaryASes:       make(trc.PrimaryASes),
+		ProofOfPossession: make(map[addr.AS][]trc.KeyType),
+	}
+	for _, as := range []addr.AS{as110, as120} {
+		pub, priv, err := scrypto.GenKeyPair(scrypto.Ed25519)
+		require.NoError(t, err)
+		keys[as] = priv
+		base.PrimaryASes[as] = trc.PrimaryAS{
+			Keys: map[trc.KeyType]scrypto.KeyMeta{
+				trc.OnlineKey: {Algorithm: scrypto.Ed25519, Key: pub, KeyVersion: 1},
+			},
+		}
+		base.ProofOfPossession[as] = []trc.KeyType{trc.OnlineKey}
+	}
+	encoded := trc.Encoded("payload")
+
+	sign := func(t *testing.T, as addr.AS, payload trc.Encoded) trc.Signature {
+		protected := protectedFor(as, trc.OnlineKey, base.PrimaryASes[as].Keys[trc.OnlineKey],
+			trc.POPSignature)
+		encProtected, err := trc.EncodeProtected(protected)
+		require.NoError(t, err)
+		req := signRequest{EncodedTRC: payload, EncodedProtected: encProtected}
+		signed, err := req.sign(keys[as])
+		require.NoError(t, err)
+		require.Len(t, signed.Signatures, 1)
+		return signed.Signatures[0]
+	}
+
+	expected, err := expectedSignatures(base, nil)
+	require.NoError(t, err)
+	assert.Len(t, expected, 2)
+
+	t.Run("all signatures present", func(t *testing.T) {
+		sigs := []trc.Signature{sign(t, as110, encoded), sign(t, as120, encoded)}
+		valid, err := verifySignatures(expected, encoded, sigs)
+		require.NoError(t, err)
+		assert.Len(t, valid, 2)
+		assert.NoError(t, checkQuorum(base, expected, valid))
+	})
+	t.Run("missing proof of possession", func(t *testing.T) {
+		sigs := []trc.Signature{sign(t, as110, encoded)}
+		valid, err := verifySignatures(expected, encoded, sigs)
+		require.NoError(t, err)
+		assert.Error(t, checkQuorum(base, expected, valid))
+	})
+	t.Run("signature on different payload", func(t *testing.T) {
+		sigs := []trc.Signature{sign(t, as110, trc.Encoded("other"))}
+		_, err := verifySignatures(expected, encoded, sigs)
+		assert.Error(t, err)
+	})
+	t.Run("unexpected signature", func(t *testing.T) {
+		sig := sign(t, as110, encoded)
+		protected := protectedFor(as110, trc.OfflineKey,
+			base.PrimaryASes[as110].Keys[trc.OnlineKey], trc.POPSignature)
+		encProtected, err := trc.EncodeProtected(protected)
+		require.NoError(t, err)
+		sig.EncodedProtected = encProtected
+		_, err = verifySignatures(expected, encoded, []trc.Signature{sig})
+		assert.Error(t, err)
+	})
+	t.Run("update without previous TRC", func(t *testing.T) {
+		update := *base
+		update.Version = 2
+		update.Votes = map[addr.AS]trc.Vote{
+			as110: {KeyType: trc.OnlineKey, KeyVersion: 1},
+		}
+		_, err := expectedSignatures(&update, nil)
+		assert.Error(t, err)
+		expected, err := expectedSignatures(&update, base)
+		require.NoError(t, err)
+		assert.Len(t, expected, 3)
+	})
+}
@@ -36,6 +36,15 @@ Generating a TRC can be split into three phases:
 In case the caller has access to all private keys, the caller can use a short-cut command
 that generates the signed TRC in one call: 'gen'.
 
+In case the private keys are distributed among multiple parties, e.g., voting ASes that
+keep their keys on offline machines, the signing ceremony looks as follows:
+1. 'proto': Generate the prototype TRC.
+2. 'export': Export one signing request per required signature.
+3. 'signreq': Each party signs its requests on its own (offline) machine.
+4. 'import': Import the detached signatures returned by the parties.
+5. 'quorum': Verify that all required signatures are present and valid.
+6. 'combine': Combine the signatures and the payload to a fully signed TRC.
+
 Selector:
 	*
 		All ISDs under the root directory.
@@ -165,10 +174,80 @@ var human = &cobra.Command{
 	},
 }
 
+var export = &cobra.Command{
+	Use:   "export",
+	Short: "Export signing requests for the proto TRCs",
+	Long: `
+	'export' writes one signing request per signature that is required for the proto
+	TRCs. A signing request contains the TRC payload and the signature metadata, and
+	can be signed without access to the root directory using the signreq command.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := runExport(args[0]); err != nil {
+			return common.NewBasicError("unable to export signing requests", err)
+		}
+		return nil
+	},
+}
+
+var signReq = &cobra.Command{
+	Use:   "signreq <request> <key> <output>",
+	Short: "Sign a signing request",
+	Long: `
+	'signreq' signs the signing request with the private key file and writes the
+	detached signature to the output file. It does not require access to the root
+	directory and can be run on an offline machine.
+`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := runSignRequest(args[0], args[1], args[2]); err != nil {
+			return common.NewBasicError("unable to sign request", err)
+		}
+		return nil
+	},
+}
+
+var importSigs = &cobra.Command{
+	Use:   "import <signature>...",
+	Short: "Import detached signatures",
+	Long: `
+	'import' verifies the detached signatures against the proto TRC and adds them to
+	the signatures that are combined with the combine command.
+`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := runImport(args); err != nil {
+			return common.NewBasicError("unable to import signatures", err)
+		}
+		return nil
+	},
+}
+
+var quorum = &cobra.Command{
+	Use:   "quorum",
+	Short: "Verify the signatures of the proto TRCs",
+	Long: `
+	'quorum' displays the status of all signatures required for the proto TRCs. It
+	fails if a proof of possession is missing, or if the voting quorum is not reached.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := runQuorum(args[0]); err != nil {
+			return common.NewBasicError("quorum not reached", err)
+		}
+		return nil
+	},
+}
+
 func init() {
 	Cmd.AddCommand(gen)
 	Cmd.AddCommand(proto)
 	Cmd.AddCommand(sign)
 	Cmd.AddCommand(combine)
+	Cmd.AddCommand(export)
+	Cmd.AddCommand(signReq)
+	Cmd.AddCommand(importSigs)
+	Cmd.AddCommand(quorum)
 	Cmd.AddCommand(human)
 }
@@ -82,7 +82,8 @@ func loadUniqueSignatures(isd addr.ISD, ver scrypto.Version,
 			return nil, common.NewBasicError("unable to parse file", err, "file", fname)
 		}
 		if !bytes.Equal(encoded, signed.EncodedTRC) {
-			pkicmn.QuietPrint("Ignoring signed in %s. Payload is different", fname)
+			pkicmn.QuietPrint("Ignoring signed in %s. Payload is different\n", fname)
+			continue
 		}
 		for _, sign := range signed.Signatures {
 			protected, err := sign.EncodedProtected.Decode()
@@ -48,6 +48,12 @@ func PartsFile(isd addr.ISD, ver uint64, selector string) string {
 		fmt.Sprintf(pkicmn.TRCSigPartFmt, isd, ver, selector))
 }
 
+// RequestFile returns the file path for the signing request with the id.
+func RequestFile(isd addr.ISD, ver uint64, id string) string {
+	return filepath.Join(PartsDir(isd, ver),
+		fmt.Sprintf(pkicmn.TRCSigReqFmt, isd, ver, id))
+}
+
 // SignedFile returns the file path for the signed TRC.
 func SignedFile(isd addr.ISD, ver uint64) string {
 	return filepath.Join(Dir(isd), fmt.Sprintf(pkicmn.TrcNameFmt, isd, ver))
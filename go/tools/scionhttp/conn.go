@@ -0,0 +1,211 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/lucas-clemente/quic-go"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/snet/squic"
+)
+
+// streamConn exposes a single QUIC stream as a net.Conn, such that the
+// net/http machinery can run HTTP/1.1 on top of it.
+type streamConn struct {
+	quic.Stream
+	session quic.Session
+}
+
+func (c *streamConn) LocalAddr() net.Addr {
+	return c.session.LocalAddr()
+}
+
+func (c *streamConn) RemoteAddr() net.Addr {
+	return c.session.RemoteAddr()
+}
+
+var _ net.Listener = (*streamListener)(nil)
+
+// streamListener accepts QUIC sessions and hands out every stream opened by
+// a client as a separate connection.
+type streamListener struct {
+	listener  quic.Listener
+	conns     chan net.Conn
+	errs      chan error
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newStreamListener(listener quic.Listener) *streamListener {
+	l := &streamListener{
+		listener: listener,
+		conns:    make(chan net.Conn),
+		errs:     make(chan error, 1),
+		closed:   make(chan struct{}),
+	}
+	go func() {
+		defer log.LogPanicAndExit()
+		l.acceptSessions()
+	}()
+	return l
+}
+
+func (l *streamListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case err := <-l.errs:
+		return nil, err
+	case <-l.closed:
+		return nil, common.NewBasicError("Listener closed", nil)
+	}
+}
+
+func (l *streamListener) Close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		close(l.closed)
+		err = l.listener.Close()
+	})
+	return err
+}
+
+func (l *streamListener) Addr() net.Addr {
+	return l.listener.Addr()
+}
+
+func (l *streamListener) acceptSessions() {
+	for {
+		session, err := l.listener.Accept()
+		if err != nil {
+			l.errs <- err
+			return
+		}
+		log.Debug("Accepted QUIC session", "remote", session.RemoteAddr())
+		go func() {
+			defer log.LogPanicAndExit()
+			l.acceptStreams(session)
+		}()
+	}
+}
+
+func (l *streamListener) acceptStreams(session quic.Session) {
+	for {
+		stream, err := session.AcceptStream()
+		if err != nil {
+			log.Debug("QUIC session terminated", "remote", session.RemoteAddr(), "err", err)
+			return
+		}
+		select {
+		case l.conns <- &streamConn{Stream: stream, session: session}:
+		case <-l.closed:
+			stream.Close()
+			return
+		}
+	}
+}
+
+// sessionDialer opens a new QUIC stream for every connection requested by the
+// HTTP transport. Sessions are shared between all connections to the same
+// host and are re-established once they fail.
+type sessionDialer struct {
+	local *snet.Addr
+	hosts hostMap
+
+	mu       sync.Mutex
+	sessions map[string]quic.Session
+}
+
+func newSessionDialer(local *snet.Addr, hosts hostMap) *sessionDialer {
+	return &sessionDialer{
+		local:    local,
+		hosts:    hosts,
+		sessions: make(map[string]quic.Session),
+	}
+}
+
+// DialContext implements the DialContext function of http.Transport. The
+// address is resolved through the host map, the context is not used since
+// opening QUIC sessions and streams cannot be cancelled.
+func (d *sessionDialer) DialContext(_ context.Context, _, address string) (net.Conn, error) {
+	remote, ok := d.hosts.Lookup(address)
+	if !ok {
+		return nil, common.NewBasicError("No SCION address known for host", nil,
+			"host", address)
+	}
+	key := remote.String()
+	session, err := d.session(key, remote)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := session.OpenStreamSync()
+	if err != nil {
+		// The session might have been closed by the remote, retry once on a
+		// fresh session.
+		d.drop(key, session)
+		if session, err = d.session(key, remote); err != nil {
+			return nil, err
+		}
+		if stream, err = session.OpenStreamSync(); err != nil {
+			d.drop(key, session)
+			return nil, common.NewBasicError("Unable to open QUIC stream", err,
+				"remote", remote)
+		}
+	}
+	return &streamConn{Stream: stream, session: session}, nil
+}
+
+func (d *sessionDialer) session(key string, remote *snet.Addr) (quic.Session, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if session, ok := d.sessions[key]; ok {
+		return session, nil
+	}
+	// Every session uses its own socket, thus let the dispatcher pick the port.
+	local := d.local.Copy()
+	local.Host.L4 = nil
+	session, err := squic.DialSCION(nil, local, remote, nil)
+	if err != nil {
+		return nil, common.NewBasicError("Unable to dial QUIC session", err, "remote", remote)
+	}
+	log.Debug("Established QUIC session", "remote", remote)
+	d.sessions[key] = session
+	return session, nil
+}
+
+func (d *sessionDialer) drop(key string, session quic.Session) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.sessions[key] == session {
+		delete(d.sessions, key)
+	}
+	session.Close()
+}
+
+// Close closes all open sessions.
+func (d *sessionDialer) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for key, session := range d.sessions {
+		session.Close()
+		delete(d.sessions, key)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/snet"
+)
+
+// hostMap maps HTTP host names to the SCION addresses serving them. It
+// implements flag.Value, every occurrence of the flag adds one mapping of the
+// form name=ISD-AS,[IP]:port.
+type hostMap map[string]*snet.Addr
+
+func (m hostMap) String() string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	entries := make([]string, 0, len(names))
+	for _, name := range names {
+		entries = append(entries, name+"="+m[name].String())
+	}
+	return strings.Join(entries, " ")
+}
+
+func (m hostMap) Set(s string) error {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return common.NewBasicError("Invalid host mapping, expected name=address", nil,
+			"mapping", s)
+	}
+	name := strings.ToLower(parts[0])
+	if _, ok := m[name]; ok {
+		return common.NewBasicError("Duplicate host mapping", nil, "name", name)
+	}
+	a, err := snet.AddrFromString(parts[1])
+	if err != nil {
+		return common.NewBasicError("Invalid SCION address in host mapping", err,
+			"mapping", s)
+	}
+	if a.Host == nil || a.Host.L4 == nil || a.Host.L4.Port() == 0 {
+		return common.NewBasicError("Missing port in host mapping", nil, "mapping", s)
+	}
+	m[name] = a
+	return nil
+}
+
+// Lookup returns the SCION address of the given host. The host may contain a
+// port, which is ignored; the port of the mapping is used instead.
+func (m hostMap) Lookup(host string) (*snet.Addr, bool) {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	a, ok := m[strings.ToLower(host)]
+	if !ok {
+		return nil, false
+	}
+	return a.Copy(), true
+}
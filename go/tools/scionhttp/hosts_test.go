@@ -0,0 +1,94 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostMapSet(t *testing.T) {
+	tests := map[string]struct {
+		Mappings  []string
+		Assertion assert.ErrorAssertionFunc
+	}{
+		"valid": {
+			Mappings:  []string{"www.example=1-ff00:0:110,[127.0.0.1]:8000"},
+			Assertion: assert.NoError,
+		},
+		"missing name": {
+			Mappings:  []string{"=1-ff00:0:110,[127.0.0.1]:8000"},
+			Assertion: assert.Error,
+		},
+		"missing separator": {
+			Mappings:  []string{"1-ff00:0:110,[127.0.0.1]:8000"},
+			Assertion: assert.Error,
+		},
+		"invalid address": {
+			Mappings:  []string{"www.example=1-ff00:0:110"},
+			Assertion: assert.Error,
+		},
+		"missing port": {
+			Mappings:  []string{"www.example=1-ff00:0:110,[127.0.0.1]"},
+			Assertion: assert.Error,
+		},
+		"duplicate": {
+			Mappings: []string{
+				"www.example=1-ff00:0:110,[127.0.0.1]:8000",
+				"WWW.example=1-ff00:0:111,[127.0.0.1]:8000",
+			},
+			Assertion: assert.Error,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := make(hostMap)
+			var err error
+			for _, mapping := range test.Mappings {
+				if err = m.Set(mapping); err != nil {
+					break
+				}
+			}
+			test.Assertion(t, err)
+		})
+	}
+}
+
+func TestHostMapLookup(t *testing.T) {
+	m := make(hostMap)
+	require.NoError(t, m.Set("www.example=1-ff00:0:110,[127.0.0.1]:8000"))
+	t.Run("with port", func(t *testing.T) {
+		a, ok := m.Lookup("www.example:80")
+		require.True(t, ok)
+		assert.Equal(t, "1-ff00:0:110", a.IA.String())
+		assert.Equal(t, uint16(8000), a.Host.L4.Port())
+	})
+	t.Run("case insensitive", func(t *testing.T) {
+		_, ok := m.Lookup("WWW.Example")
+		assert.True(t, ok)
+	})
+	t.Run("returns copy", func(t *testing.T) {
+		a, _ := m.Lookup("www.example")
+		a.Host = nil
+		b, _ := m.Lookup("www.example")
+		assert.NotNil(t, b.Host)
+	})
+	t.Run("unknown", func(t *testing.T) {
+		_, ok := m.Lookup("other.example")
+		assert.False(t, ok)
+	})
+}
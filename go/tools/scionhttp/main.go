@@ -0,0 +1,157 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// scionhttp demonstrates HTTP over QUIC/SCION. In server mode, it serves the
+// files of a directory to SCION clients. In proxy mode, it runs a local HTTP
+// forward proxy that sends requests for the configured host names to the
+// respective SCION servers.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"os"
+
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/snet/squic"
+	"github.com/scionproto/scion/go/lib/sock/reliable"
+)
+
+const (
+	ModeServer = "server"
+	ModeProxy  = "proxy"
+)
+
+var (
+	local      snet.Addr
+	hosts      = make(hostMap)
+	mode       = flag.String("mode", ModeProxy, "Run in "+ModeServer+" or "+ModeProxy+" mode")
+	sciond     = flag.String("sciond", "", "Path to sciond socket")
+	dispatcher = flag.String("dispatcher", "", "Path to dispatcher socket")
+	root       = flag.String("root", ".", "Directory to serve (only server)")
+	listen     = flag.String("listen", "127.0.0.1:8080",
+		"TCP address the HTTP proxy listens on (only proxy)")
+)
+
+func init() {
+	flag.Var(&local, "local", "(Mandatory) SCION address to use")
+	flag.Var(hosts, "map",
+		"Map a host name to a SCION address, name=ISD-AS,[IP]:port (repeatable, only proxy)")
+}
+
+func main() {
+	os.Setenv("TZ", "UTC")
+	log.AddLogConsFlags()
+	validateFlags()
+	if err := log.SetupFromFlags(""); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %s", err)
+		flag.Usage()
+		os.Exit(1)
+	}
+	defer log.LogPanicAndExit()
+	initNetwork()
+	switch *mode {
+	case ModeServer:
+		runServer()
+	case ModeProxy:
+		runProxy()
+	}
+}
+
+func validateFlags() {
+	flag.Parse()
+	if *mode != ModeServer && *mode != ModeProxy {
+		LogFatal("Unknown mode, must be either '" + ModeServer + "' or '" + ModeProxy + "'")
+	}
+	if local.Host == nil {
+		LogFatal("Missing local address")
+	}
+	if *mode == ModeServer && (local.Host.L4 == nil || local.Host.L4.Port() == 0) {
+		LogFatal("Missing local port")
+	}
+	if *mode == ModeProxy && len(hosts) == 0 {
+		LogFatal("No host mappings configured")
+	}
+}
+
+func LogFatal(msg string, a ...interface{}) {
+	log.Crit(msg, a...)
+	os.Exit(1)
+}
+
+func initNetwork() {
+	if err := snet.Init(local.IA, *sciond, reliable.NewDispatcherService(*dispatcher)); err != nil {
+		LogFatal("Unable to initialize SCION network", "err", err)
+	}
+	log.Debug("SCION network successfully initialized")
+	if err := squic.Init("", ""); err != nil {
+		LogFatal("Unable to initialize QUIC/SCION", "err", err)
+	}
+	log.Debug("QUIC/SCION successfully initialized")
+}
+
+func runServer() {
+	qsock, err := squic.ListenSCION(nil, &local, nil)
+	if err != nil {
+		LogFatal("Unable to listen", "err", err)
+	}
+	log.Info("Serving HTTP over QUIC/SCION", "local", qsock.Addr(), "root", *root)
+	server := &http.Server{
+		Handler: logRequests(http.FileServer(http.Dir(*root))),
+	}
+	if err := server.Serve(newStreamListener(qsock)); err != nil {
+		LogFatal("HTTP server failed", "err", err)
+	}
+}
+
+func runProxy() {
+	dialer := newSessionDialer(&local, hosts)
+	defer dialer.Close()
+	proxy := &httputil.ReverseProxy{
+		Director: func(r *http.Request) {
+			r.URL.Scheme = "http"
+			if r.URL.Host == "" {
+				r.URL.Host = r.Host
+			}
+		},
+		Transport: &http.Transport{
+			DialContext: dialer.DialContext,
+		},
+	}
+	log.Info("Proxying HTTP over QUIC/SCION", "listen", *listen, "hosts", hosts)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodConnect {
+			http.Error(w, "CONNECT is not supported", http.StatusMethodNotAllowed)
+			return
+		}
+		if _, ok := hosts.Lookup(r.Host); !ok {
+			http.Error(w, "Unknown host "+r.Host, http.StatusBadGateway)
+			return
+		}
+		proxy.ServeHTTP(w, r)
+	})
+	if err := http.ListenAndServe(*listen, logRequests(handler)); err != nil {
+		LogFatal("HTTP proxy failed", "err", err)
+	}
+}
+
+func logRequests(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.Debug("HTTP request", "method", r.Method, "url", r.URL, "remote", r.RemoteAddr)
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -27,14 +27,12 @@ import (
 
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
-	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
 	"github.com/scionproto/scion/go/lib/env"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/overlay"
 	"github.com/scionproto/scion/go/lib/sciond"
 	"github.com/scionproto/scion/go/lib/scmp"
 	_ "github.com/scionproto/scion/go/lib/scrypto" // Make sure math/rand is seeded
-	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/snet"
 	"github.com/scionproto/scion/go/lib/sock/reliable"
 	"github.com/scionproto/scion/go/lib/spkt"
@@ -198,20 +196,15 @@ func Validate(pkt *spkt.ScnPkt) (*scmp.Hdr, *scmp.Payload, error) {
 		return scmpHdr, scmpPld, nil
 	}
 	// Handle revocation
-	infoRev, ok := scmpPld.Info.(*scmp.InfoRevocation)
-	if !ok {
-		return scmpHdr, scmpPld,
-			serrors.New("Failed to parse SCMP revocation Info")
-	}
-	signedRevInfo, err := path_mgmt.NewSignedRevInfoFromRaw(infoRev.RawSRev)
+	infoRev, err := scmpPld.Revocation()
 	if err != nil {
 		return scmpHdr, scmpPld,
-			serrors.New("Failed to decode SCMP signed revocation Info")
+			common.NewBasicError("Failed to parse SCMP revocation Info", err)
 	}
-	ri, err := signedRevInfo.RevInfo()
+	ri, err := infoRev.RevInfo()
 	if err != nil {
 		return scmpHdr, scmpPld,
-			serrors.New("Failed to decode SCMP revocation Info")
+			common.NewBasicError("Failed to decode SCMP revocation Info", err)
 	}
 	return scmpHdr, scmpPld, common.NewBasicError("", nil, "Revocation", ri)
 }
@@ -170,10 +170,9 @@ func validate(pkt *spkt.ScnPkt) (*scmp.Hdr, *scmp.InfoEcho, error) {
 		}
 		return nil, nil, err
 	}
-	info, ok := scmpPld.Info.(*scmp.InfoEcho)
-	if !ok {
-		return nil, nil,
-			common.NewBasicError("Not an Info Echo", nil, "type", common.TypeOf(scmpPld.Info))
+	info, err := scmpPld.Echo()
+	if err != nil {
+		return nil, nil, err
 	}
 	if info.Id != id {
 		return nil, nil,
@@ -29,6 +29,7 @@ import (
 	"github.com/scionproto/scion/go/lib/sciond"
 	"github.com/scionproto/scion/go/lib/sock/reliable"
 	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/lib/spath/spathmeta"
 	"github.com/scionproto/scion/go/tools/scmp/cmn"
 	"github.com/scionproto/scion/go/tools/scmp/echo"
 	"github.com/scionproto/scion/go/tools/scmp/recordpath"
@@ -42,12 +43,19 @@ var (
 	refresh      = flag.Bool("refresh", false, "Set refresh flag for SCIOND path request")
 	sdConn       sciond.Connector
 	version      = flag.Bool("version", false, "Output version information and exit.")
+	fingerprint  = flag.String("fingerprint", "",
+		"Use the path with the given fingerprint (or fingerprint prefix), see showpaths")
 )
 
 func main() {
 	var err error
 	cmd := cmn.ParseFlags(version)
 	cmn.ValidateFlags()
+	if *fingerprint != "" {
+		if err := spathmeta.ValidateFingerprint(*fingerprint); err != nil {
+			cmn.Fatal("Invalid fingerprint: %v\n", err)
+		}
+	}
 	if *sciondFromIA {
 		if *sciondPath != "" {
 			cmn.Fatal("Only one of -sciond or -sciondFromIA can be specified")
@@ -128,10 +136,17 @@ func choosePath() sciond.PathReplyEntry {
 	if len(paths) == 0 {
 		cmn.Fatal("No paths available to remote destination")
 	}
+	if *fingerprint != "" {
+		paths = spathmeta.FilterFingerprint(paths, *fingerprint)
+		if len(paths) == 0 {
+			cmn.Fatal("No path to remote destination matches fingerprint %s\n", *fingerprint)
+		}
+	}
 	if cmn.Interactive {
 		fmt.Printf("Available paths to %v\n", cmn.Remote.IA)
 		for i := range paths {
-			fmt.Printf("[%2d] %s\n", i, paths[i].Path.String())
+			ap := spathmeta.AppPath{Entry: &paths[i]}
+			fmt.Printf("[%2d] %s %s\n", i, ap.Fingerprint(), paths[i].Path.String())
 		}
 		reader := bufio.NewReader(os.Stdin)
 		for {
@@ -109,10 +109,9 @@ func validate(pkt *spkt.ScnPkt, pathEntry *sciond.PathReplyEntry) (*scmp.Hdr,
 	if err != nil {
 		return nil, nil, err
 	}
-	info, ok := scmpPld.Info.(*scmp.InfoRecordPath)
-	if !ok {
-		return nil, nil,
-			common.NewBasicError("Not an Info RecordPath", nil, "type", common.TypeOf(scmpPld.Info))
+	info, err := scmpPld.RecordPath()
+	if err != nil {
+		return nil, nil, err
 	}
 	if info.Id != id {
 		return nil, nil,
@@ -183,10 +183,9 @@ func validate(pkt *spkt.ScnPkt, pathEntry *sciond.PathReplyEntry) (*scmp.Hdr,
 	if err != nil {
 		return nil, nil, err
 	}
-	info, ok := scmpPld.Info.(*scmp.InfoTraceRoute)
-	if !ok {
-		return nil, nil,
-			common.NewBasicError("Not an Info TraceRoute", nil, "type", common.TypeOf(scmpPld.Info))
+	info, err := scmpPld.TraceRoute()
+	if err != nil {
+		return nil, nil, err
 	}
 	if info.Id != id {
 		return nil, nil,
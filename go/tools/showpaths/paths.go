@@ -28,6 +28,7 @@ import (
 	"github.com/scionproto/scion/go/lib/sciond"
 	"github.com/scionproto/scion/go/lib/sciond/pathprobe"
 	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/spath/spathmeta"
 )
 
 var (
@@ -40,13 +41,26 @@ var (
 	expiration   = flag.Bool("expiration", false, "Show path expiration timestamps")
 	refresh      = flag.Bool("refresh", false, "Set refresh flag for SCIOND path request")
 	status       = flag.Bool("p", false, "Probe the paths and print out the statuses")
-	version      = flag.Bool("version", false, "Output version information and exit.")
+	orderingStr  = flag.String("ordering", "", "Path ordering: hops, expiry or mtu")
+	disjointStr  = flag.String("disjoint", "",
+		"Prefer disjoint paths: link or as (link- or AS-disjoint)")
+	version     = flag.Bool("version", false, "Output version information and exit.")
+	nextQueries = flag.Bool("nextQueries", false,
+		"Show the NextQuery entries of SCIOND for the destination and exit")
+	invalidate = flag.Bool("invalidate", false,
+		"Delete the NextQuery entries of SCIOND before requesting paths")
+	fingerprint = flag.String("fingerprint", "",
+		"Only show paths whose fingerprint starts with the given hex string")
+	allowStale = flag.Bool("stale", false,
+		"Allow SCIOND to answer from its cache if segments cannot be fetched")
 )
 
 var (
-	dstIA addr.IA
-	srcIA addr.IA
-	local snet.Addr
+	dstIA        addr.IA
+	srcIA        addr.IA
+	local        snet.Addr
+	ordering     sciond.PathOrdering
+	disjointness sciond.PathDisjointness
 )
 
 func init() {
@@ -70,8 +84,24 @@ func main() {
 	if err != nil {
 		LogFatal("Failed to connect to SCIOND", "err", err)
 	}
+	if *nextQueries {
+		showNextQueries(sdConn)
+		return
+	}
+	if *invalidate {
+		nqReply, err := sdConn.DeleteNextQueries(context.Background(), dstIA)
+		if err != nil {
+			LogFatal("Failed to delete NextQuery entries", "err", err)
+		}
+		if nqReply.ErrorCode != sciond.NextQueryOk {
+			LogFatal("SCIOND unable to delete NextQuery entries",
+				"ErrorCode", nqReply.ErrorCode)
+		}
+		log.Debug("Deleted NextQuery entries", "count", len(nqReply.Entries))
+	}
 	reply, err := sdConn.Paths(context.Background(), dstIA, srcIA, uint16(*maxPaths),
-		sciond.PathReqFlags{Refresh: *refresh})
+		sciond.PathReqFlags{Refresh: *refresh, Ordering: ordering, Disjointness: disjointness,
+			AllowStale: *allowStale})
 	if err != nil {
 		LogFatal("Failed to retrieve paths from SCIOND", "err", err)
 	}
@@ -79,21 +109,34 @@ func main() {
 		LogFatal("SCIOND unable to retrieve paths", "ErrorCode", reply.ErrorCode)
 	}
 
+	entries := reply.Entries
+	if *fingerprint != "" {
+		entries = spathmeta.FilterFingerprint(entries, *fingerprint)
+		if len(entries) == 0 {
+			LogFatal("No path matches the fingerprint", "fingerprint", *fingerprint)
+		}
+	}
+
 	fmt.Println("Available paths to", dstIA)
+	if reply.Stale {
+		fmt.Printf("Paths are stale, the refresh of the cached segments is overdue by %s\n",
+			reply.Staleness())
+	}
 	var pathStatuses map[string]pathprobe.Status
 	if *status {
 		ctx, cancelF := context.WithTimeout(context.Background(), *timeout)
 		pathStatuses, err = pathprobe.Prober{
 			Local: local,
 			DstIA: dstIA,
-		}.GetStatuses(ctx, reply.Entries)
+		}.GetStatuses(ctx, entries)
 		cancelF()
 		if err != nil {
 			LogFatal("Failed to get status", "err", err)
 		}
 	}
-	for i, path := range reply.Entries {
-		fmt.Printf("[%2d] %s", i, path.Path.String())
+	for i, path := range entries {
+		ap := spathmeta.AppPath{Entry: &entries[i]}
+		fmt.Printf("[%2d] %s %s", i, ap.Fingerprint(), path.Path.String())
 		if *expiration {
 			fmt.Printf(" Expires: %s (%s)", path.Path.Expiry(),
 				time.Until(path.Path.Expiry()).Truncate(time.Second))
@@ -105,6 +148,22 @@ func main() {
 	}
 }
 
+func showNextQueries(sdConn sciond.Connector) {
+	reply, err := sdConn.NextQueries(context.Background(), dstIA)
+	if err != nil {
+		LogFatal("Failed to retrieve NextQuery entries from SCIOND", "err", err)
+	}
+	if reply.ErrorCode != sciond.NextQueryOk {
+		LogFatal("SCIOND unable to retrieve NextQuery entries", "ErrorCode", reply.ErrorCode)
+	}
+	fmt.Println("NextQuery entries for", dstIA)
+	for i, e := range reply.Entries {
+		nq := e.NextQueryTime()
+		fmt.Printf("[%2d] %s -> %s NextQuery: %s (%s)\n", i, e.Src(), e.Dst(), nq,
+			time.Until(nq).Truncate(time.Second))
+	}
+}
+
 func validateFlags() {
 	flag.Parse()
 	var err error
@@ -127,6 +186,14 @@ func validateFlags() {
 		}
 	}
 
+	if ordering, err = sciond.ParsePathOrdering(*orderingStr); err != nil {
+		LogFatal("Unable to parse path ordering", "err", err)
+	}
+
+	if disjointness, err = sciond.ParsePathDisjointness(*disjointStr); err != nil {
+		LogFatal("Unable to parse path disjointness", "err", err)
+	}
+
 	if *sciondFromIA {
 		if *sciondPath != "" {
 			LogFatal("Only one of -sciond or -sciondFromIA can be specified")
@@ -139,6 +206,12 @@ func validateFlags() {
 		*sciondPath = sciond.GetDefaultSCIONDPath(nil)
 	}
 
+	if *fingerprint != "" {
+		if err := spathmeta.ValidateFingerprint(*fingerprint); err != nil {
+			LogFatal("Invalid fingerprint", "err", err)
+		}
+	}
+
 	if *status && (local.IA.IsZero() || local.Host == nil) {
 		LogFatal("Local address is required for health checks")
 	}
@@ -150,7 +223,13 @@ Usage: showpaths [flags]
 
 Lists available paths between SCION ASes. Paths might be retrieved from a local cache, and they
 might not forward traffic successfully (for example, if a network link went down). To probe if the
-paths are healthy, use -p.
+paths are healthy, use -p. To force SCIOND to fetch fresh segments for the destination, use
+-invalidate; -nextQueries lists when SCIOND will query for new segments next.
+
+Every path is listed with its fingerprint, a short hash of the sequence of interfaces on the path.
+The fingerprint is stable across invocations and can be used to select the same path in other
+tools (e.g., scmp -fingerprint). Use -fingerprint to only show the paths matching a fingerprint
+prefix.
 
 flags:
 `)
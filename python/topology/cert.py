@@ -38,11 +38,7 @@ class CertGenerator(object):
         self.core_count = defaultdict(int)
 
     def generate(self, topo_dicts):
-        self.pki('tmpl', 'topo', self.args.topo_config, '-d', self.args.output_dir)
-        self.pki('keys', 'gen', '*', '-d', self.args.output_dir)
-        self.pki('trc', 'gen', '*', '-d', self.args.output_dir)
-        self.pki('certs', 'gen', '*', '-d', self.args.output_dir)
-        self.pki('certs', 'customers', '*', '-d', self.args.output_dir)
+        self.pki('deploy', 'gen', self.args.topo_config, '-d', self.args.output_dir)
         self._copy_files(topo_dicts)
 
     def _copy_files(self, topo_dicts):